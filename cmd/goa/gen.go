@@ -6,6 +6,7 @@ import (
 	"go/build"
 	"go/parser"
 	"go/token"
+	"io"
 	"os"
 	"os/exec"
 	"path/filepath"
@@ -33,6 +34,17 @@ type Generator struct {
 	// DesignVersion is either 2 or 3.
 	DesignVersion int
 
+	// GenPkg is the Go import path of the generated "gen" package. If empty
+	// the import path is computed from Output.
+	GenPkg string
+
+	// CmdLine overrides the command line recorded in the generated file
+	// headers if not empty.
+	CmdLine string
+
+	// Options contains the optional code generation settings.
+	Options options
+
 	// bin is the filename of the generated generator.
 	bin string
 
@@ -115,10 +127,22 @@ func (g *Generator) Write(_ bool) error {
 
 	var sections []*codegen.SectionTemplate
 	{
+		// The generator removes the directories of the selected services
+		// itself when generating a subset of the services.
+		var cleanup []string
+		if len(g.Options.Services) == 0 {
+			cleanup = cleanupDirs(g.Command, g.Output)
+		}
 		data := map[string]any{
 			"Command":       g.Command,
-			"CleanupDirs":   cleanupDirs(g.Command, g.Output),
+			"CleanupDirs":   cleanup,
 			"DesignVersion": g.DesignVersion,
+			"GenPkg":        g.GenPkg,
+			"Options":       g.Options,
+			"TraceFile":     "",
+		}
+		if g.Options.DebugEval {
+			data["TraceFile"] = g.TraceFile()
 		}
 		ver := ""
 		if g.DesignVersion > 2 {
@@ -182,6 +206,23 @@ func (g *Generator) Compile() error {
 	return err
 }
 
+// TraceFile returns the path to the file the generator writes the trace of
+// the design evaluation to when the DebugEval option is set.
+func (g *Generator) TraceFile() string {
+	return filepath.Join(g.tmpDir, "eval.trace")
+}
+
+// PrintTrace copies the trace of the design evaluation written by the
+// generator to w. It does nothing if there is no trace.
+func (g *Generator) PrintTrace(w io.Writer) {
+	f, err := os.Open(g.TraceFile())
+	if err != nil {
+		return
+	}
+	defer f.Close()
+	io.Copy(w, f) // nolint: errcheck
+}
+
 // Run runs the compiled binary and return the output lines.
 func (g *Generator) Run() ([]string, error) {
 	var cmdl string
@@ -208,6 +249,9 @@ func (g *Generator) Run() ([]string, error) {
 		rawcmd = strings.TrimSuffix(rawcmd, ".exe")
 
 		cmdl = fmt.Sprintf("$ %s%s", rawcmd, cmdl)
+		if g.CmdLine != "" {
+			cmdl = g.CmdLine
+		}
 	}
 
 	args := []string{"--version=" + strconv.Itoa(g.DesignVersion), "--output=" + g.Output, "--cmd=" + cmdl}
@@ -255,10 +299,14 @@ func (g *Generator) runGoCmd(args ...string) error {
 }
 
 // cleanupDirs returns the paths of the subdirectories under gendir to delete
-// before generating code.
+// before generating code. There is nothing to delete if gendir contains a
+// manifest, the generator uses it to delete the stale files instead.
 func cleanupDirs(cmd, output string) []string {
 	if cmd == "gen" {
 		gendirPath := filepath.Join(output, codegen.Gendir)
+		if _, err := os.Stat(filepath.Join(gendirPath, codegen.Manifest)); err == nil {
+			return nil
+		}
 		gendir, err := os.Open(gendirPath)
 		if err != nil {
 			return nil
@@ -317,6 +365,14 @@ const mainT = `func main() {
 	if err := eval.Context.Errors; err != nil {
 		fail(err.Error())
 	}
+{{- if .TraceFile }}
+	trace, err := os.Create({{ printf "%q" .TraceFile }})
+	if err != nil {
+		fail(err.Error())
+	}
+	defer trace.Close()
+	eval.Trace = trace
+{{- end }}
 	if err := eval.RunDSL(); err != nil {
 		fail(err.Error())
 	}
@@ -328,7 +384,35 @@ const mainT = `func main() {
 {{- if gt .DesignVersion 2 }}
 	codegen.DesignVersion = ver
 {{- end }}
+{{- if .Options.Postman }}
+	generator.GeneratePostman = true
+{{- end }}
+{{- if .Options.Fuzz }}
+	generator.GenerateFuzz = true
+{{- end }}
+{{- if .Options.GraphQL }}
+	generator.GenerateGraphQL = true
+{{- end }}
+{{- if .Options.Docs }}
+	generator.DocsFormat = {{ printf "%q" .Options.Docs }}
+{{- end }}
+{{- if .Options.LoadTest }}
+	generator.LoadTestFormat = {{ printf "%q" .Options.LoadTest }}
+{{- end }}
+{{- if .Options.Templates }}
+	generator.TemplatesDir = {{ printf "%q" .Options.Templates }}
+{{- end }}
+{{- if .Options.Against }}
+	generator.DiffAgainst = {{ printf "%q" .Options.Against }}
+{{- end }}
+{{- if .Options.Services }}
+	generator.Services = []string{ {{- range .Options.Services }}{{ printf "%q" . }}, {{ end }}}
+{{- end }}
+{{- if .GenPkg }}
+	outputs, err := generator.GenerateAs(*out, {{ printf "%q" .GenPkg }}, {{ printf "%q" .Command }})
+{{- else }}
 	outputs, err := generator.Generate(*out, {{ printf "%q" .Command }})
+{{- end }}
 	if err != nil {
 		fail(err.Error())
 	}
@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"go/build"
 	"os"
+	"path/filepath"
 	"strings"
 
 	"flag"
@@ -26,7 +27,7 @@ func main() {
 		case "version":
 			fmt.Println("Goa version " + goa.Version())
 			os.Exit(0)
-		case "gen", "example":
+		case "gen", "example", "verify", "lint", "diff":
 			if len(os.Args) == 2 {
 				usage()
 			}
@@ -41,6 +42,7 @@ func main() {
 	var (
 		output = "."
 		debug  bool
+		opts   options
 	)
 	if len(os.Args) > offset+1 {
 		var (
@@ -49,6 +51,17 @@ func main() {
 			out  = fset.String("output", output, "output `directory`")
 		)
 		fset.BoolVar(&debug, "debug", false, "Print debug information")
+		fset.BoolVar(&opts.DebugEval, "debug-eval", false, "Print a trace of the design evaluation")
+		fset.BoolVar(&opts.Postman, "postman", false, "Generate a Postman collection and environment")
+		fset.BoolVar(&opts.Fuzz, "fuzz", false, "Generate the fuzz tests of the HTTP server request decoders")
+		fset.BoolVar(&opts.GraphQL, "graphql", false, "Generate a GraphQL gateway")
+		fset.StringVar(&opts.Docs, "docs", "", "Generate a reference of the HTTP endpoints in the given `format`")
+		fset.StringVar(&opts.LoadTest, "loadtest", "", "Generate a load test scenario covering the HTTP endpoints in the given `format`")
+		fset.StringVar(&opts.Templates, "templates", "", "Path to a `directory` of template overrides")
+		fset.StringVar(&opts.Against, "against", "", "Path to the OpenAPI 3 JSON specification of the previous API version (diff command)")
+		fset.Var(&opts.Services, "service", "Generate only the code of the `service` (repeatable)")
+		fset.BoolVar(&opts.Watch, "watch", false, "Regenerate the code each time the design changes")
+		fset.StringVar(&opts.Run, "run", "", "Build and restart the server `package` after each generation (watch mode)")
 
 		fset.Usage = usage
 		if err := fset.Parse(os.Args[offset+1:]); err != nil {
@@ -62,19 +75,84 @@ func main() {
 		}
 	}
 
-	if err := gen(cmd, path, output, debug); err != nil {
+	if opts.Run != "" && !opts.Watch {
+		fmt.Fprintln(os.Stderr, "the -run flag requires -watch")
+		os.Exit(1)
+	}
+	if opts.Watch {
+		if err := watch(cmd, path, output, debug, opts); err != nil {
+			fmt.Fprintln(os.Stderr, err.Error())
+			os.Exit(1)
+		}
+		return
+	}
+	if err := gen(cmd, path, output, debug, opts); err != nil {
 		fmt.Fprintln(os.Stderr, err.Error())
 		os.Exit(1)
 	}
 }
 
+// options lists the optional code generation settings given on the command
+// line.
+type options struct {
+	// Postman enables the generation of a Postman collection and
+	// environment covering the HTTP endpoints.
+	Postman bool
+	// Fuzz enables the generation of the fuzz tests of the HTTP server
+	// request decoders and request body validation functions.
+	Fuzz bool
+	// GraphQL enables the generation of the GraphQL schema and resolvers
+	// exposing the service methods.
+	GraphQL bool
+	// Docs is the format of the generated HTTP reference documentation,
+	// "markdown" or "asciidoc". No documentation is generated if empty.
+	Docs string
+	// LoadTest is the format of the generated load test scenario, "k6" or
+	// "vegeta". No scenario is generated if empty.
+	LoadTest string
+	// Services lists the names of the services to generate the code for,
+	// the code of all the services is generated if empty.
+	Services stringsFlag
+	// Templates is the path to a directory of template overrides matched by
+	// section name, see generator.TemplatesDir.
+	Templates string
+	// Against is the path to the OpenAPI 3 JSON specification of the previous
+	// version of the API compared with the design by the diff command.
+	Against string
+	// DebugEval enables the trace of the design evaluation, the trace is
+	// printed to stderr.
+	DebugEval bool
+	// Watch enables the watch mode: the code is regenerated each time the
+	// design package changes.
+	Watch bool
+	// Run is the path to the server main package relative to the output
+	// directory followed by the server arguments. The server is built and
+	// restarted after each generation in watch mode.
+	Run string
+}
+
+// stringsFlag is a flag that may be given multiple times.
+type stringsFlag []string
+
+// String returns the comma separated flag values.
+func (s *stringsFlag) String() string { return strings.Join(*s, ",") }
+
+// Set appends the given value to the flag values.
+func (s *stringsFlag) Set(v string) error {
+	if v == "" {
+		return fmt.Errorf("service name cannot be empty")
+	}
+	*s = append(*s, v)
+	return nil
+}
+
 // help with tests
 var (
 	usage = help
 	gen   = generate
 )
 
-func generate(cmd, path, output string, debug bool) error {
+func generate(cmd, path, output string, debug bool, opts options) error {
 	var (
 		files []string
 		err   error
@@ -85,7 +163,41 @@ func generate(cmd, path, output string, debug bool) error {
 		goto fail
 	}
 
+	if opts.Docs != "" && opts.Docs != "markdown" && opts.Docs != "asciidoc" {
+		return fmt.Errorf("invalid -docs format %q, must be markdown or asciidoc", opts.Docs)
+	}
+
+	if opts.LoadTest != "" && opts.LoadTest != "k6" && opts.LoadTest != "vegeta" {
+		return fmt.Errorf("invalid -loadtest format %q, must be k6 or vegeta", opts.LoadTest)
+	}
+
+	if opts.Templates != "" {
+		if opts.Templates, err = filepath.Abs(opts.Templates); err != nil {
+			return err
+		}
+		if fi, err := os.Stat(opts.Templates); err != nil || !fi.IsDir() {
+			return fmt.Errorf("invalid -templates directory %q", opts.Templates)
+		}
+	}
+
+	if cmd == "diff" {
+		if opts.Against == "" {
+			return fmt.Errorf("the diff command requires the -against flag")
+		}
+		if opts.Against, err = filepath.Abs(opts.Against); err != nil {
+			return err
+		}
+	}
+
+	if cmd == "verify" {
+		if len(opts.Services) > 0 {
+			return fmt.Errorf("the -service flag cannot be used with the verify command")
+		}
+		return verify(path, output, debug, opts)
+	}
+
 	tmp = NewGenerator(cmd, path, output)
+	tmp.Options = opts
 
 	if err = tmp.Write(debug); err != nil {
 		goto fail
@@ -95,11 +207,22 @@ func generate(cmd, path, output string, debug bool) error {
 		goto fail
 	}
 
-	if files, err = tmp.Run(); err != nil {
+	files, err = tmp.Run()
+	if opts.DebugEval {
+		tmp.PrintTrace(os.Stderr)
+	}
+	if err != nil {
 		goto fail
 	}
 
-	fmt.Println(strings.Join(files, "\n"))
+	switch cmd {
+	case "lint":
+		fmt.Println("design passes all lint rules")
+	case "diff":
+		fmt.Println("no breaking change found")
+	default:
+		fmt.Println(strings.Join(files, "\n"))
+	}
 	if !debug {
 		tmp.Remove()
 	}
@@ -116,15 +239,32 @@ func help() {
 Learn more at https://goa.design.
 
 Usage:
-  goa gen PACKAGE [--output DIRECTORY] [--debug]
-  goa example PACKAGE [--output DIRECTORY] [--debug]
+  goa gen PACKAGE [--output DIRECTORY] [--debug] [--postman] [--fuzz] [--graphql] [--docs FORMAT] [--loadtest FORMAT] [--service NAME]... [--templates DIRECTORY] [--debug-eval] [--watch [--run PACKAGE]]
+  goa example PACKAGE [--output DIRECTORY] [--debug] [--templates DIRECTORY] [--debug-eval] [--watch [--run PACKAGE]]
+  goa verify PACKAGE [--output DIRECTORY] [--debug] [--postman] [--fuzz] [--graphql] [--docs FORMAT] [--loadtest FORMAT] [--templates DIRECTORY]
+  goa lint PACKAGE [--debug] [--debug-eval]
+  goa diff PACKAGE --against FILE [--debug]
   goa version
 
 Commands:
   gen
         Generate service interfaces, endpoints, transport code and OpenAPI spec.
   example
-        Generate example server and client tool.
+        Generate example server and client tool. The changes made to the
+        design are merged into the existing example files using the copies
+        of the files as last generated kept in the .goa/scaffold directory,
+        conflicting changes are delimited with conflict markers.
+  verify
+        Regenerate the code in a temporary directory and compare it with the
+        content of the gen directory, exit with a non-zero status on drift.
+  lint
+        Check the design against the lint rules, exit with a non-zero status
+        if any rule is violated. Rules can be disabled with the "lint:disable"
+        meta on the API, a service or a method.
+  diff
+        Compare the OpenAPI 3 specification of the design with the one of a
+        previous version of the API (usually a copy of gen/http/openapi3.json)
+        and report the breaking changes, exit with a non-zero status if any.
   version
         Print version information.
 
@@ -139,9 +279,63 @@ Flags:
   -debug
         Print debug information (mainly intended for Goa developers)
 
+  -debug-eval
+        Print a trace of the design evaluation to stderr: each DSL function
+        execution with the expression type, the DSL source location and the
+        resulting changes to the attributes (mainly intended for DSL plugin
+        authors)
+
+  -postman
+        Generate a Postman collection and environment in the gen/http
+        directory
+
+  -fuzz
+        Generate the fuzz tests of the HTTP server request decoders and
+        request body validation functions (fuzz_test.go in the
+        gen/http/SERVICE/server directories), run them with "go test -fuzz"
+
+  -graphql
+        Generate a GraphQL schema and the resolvers that map the queries and
+        mutations onto the service methods in the gen/graphql directory
+
+  -docs FORMAT
+        Generate a reference of the HTTP endpoints in the gen/http/docs
+        directory, FORMAT is one of markdown or asciidoc
+
+  -loadtest FORMAT
+        Generate a load test scenario covering the HTTP endpoints with the
+        design examples in the gen/http/loadtest directory, FORMAT is one of
+        k6 or vegeta. The requests are weighted with the "loadtest:weight"
+        method meta
+
+  -templates DIRECTORY
+        Directory of template overrides, each file NAME.tmpl replaces the
+        template of the generated code sections named NAME. The overrides
+        are given the same data and functions as the templates they replace.
+
+  -against FILE
+        Path to the OpenAPI 3 JSON specification of the previous version of
+        the API, the diff command only
+
+  -service NAME
+        Generate only the code of the service with the given name, may be
+        given multiple times. The code of the other services is left
+        untouched. The gen command only.
+
+  -watch
+        Keep running and regenerate the code each time a Go file of the
+        design package (or of its sub-packages) or a template override
+        changes. The gen and example commands only.
+
+  -run "PACKAGE [ARGS]"
+        Build the server main package (path relative to the output
+        directory, e.g. ./cmd/calc) and restart it with the given arguments
+        after each successful generation, requires -watch.
+
 Example:
 
   goa gen goa.design/examples/cellar/design -o gendir
+  goa gen goa.design/examples/cellar/design --watch --run ./cmd/cellar
 
 `)
 }
@@ -16,10 +16,14 @@ func TestCmdLine(t *testing.T) {
 		cmd          string
 		path, output string
 		debug        bool
+		postman      bool
 	)
 
 	usage = func() { usageCalled = true }
-	gen = func(c string, p, o string, d bool) error { cmd, path, output, debug = c, p, o, d; return nil }
+	gen = func(c string, p, o string, d bool, opts options) error {
+		cmd, path, output, debug, postman = c, p, o, d, opts.Postman
+		return nil
+	}
 	defer func() {
 		usage = help
 		gen = generate
@@ -32,17 +36,21 @@ func TestCmdLine(t *testing.T) {
 		ExpectedPath    string
 		ExpectedOutput  string
 		ExpectedDebug   bool
+		ExpectedPostman bool
 	}{
-		"gen": {"gen " + testPkg, false, "gen", testPkg, ".", false},
+		"gen":    {"gen " + testPkg, false, "gen", testPkg, ".", false, false},
+		"verify": {"verify " + testPkg, false, "verify", testPkg, ".", false, false},
+
+		"invalid":     {"invalid " + testPkg, true, "", "", ".", false, false},
+		"empty":       {"", true, "", "", ".", false, false},
+		"invalid gen": {"invalid gen" + testPkg, true, "", "", ".", false, false},
 
-		"invalid":     {"invalid " + testPkg, true, "", "", ".", false},
-		"empty":       {"", true, "", "", ".", false},
-		"invalid gen": {"invalid gen" + testPkg, true, "", "", ".", false},
+		"output":       {"gen " + testPkg + " -output " + testOutput, false, "gen", testPkg, testOutput, false, false},
+		"output short": {"gen " + testPkg + " -o " + testOutput, false, "gen", testPkg, testOutput, false, false},
 
-		"output":       {"gen " + testPkg + " -output " + testOutput, false, "gen", testPkg, testOutput, false},
-		"output short": {"gen " + testPkg + " -o " + testOutput, false, "gen", testPkg, testOutput, false},
+		"debug": {"gen " + testPkg + " -debug", false, "gen", testPkg, ".", true, false},
 
-		"debug": {"gen " + testPkg + " -debug", false, "gen", testPkg, ".", true},
+		"postman": {"gen " + testPkg + " -postman", false, "gen", testPkg, ".", false, true},
 	}
 
 	for k, c := range cases {
@@ -54,6 +62,7 @@ func TestCmdLine(t *testing.T) {
 			path = ""
 			output = ""
 			debug = false
+			postman = false
 		}
 
 		main()
@@ -73,5 +82,110 @@ func TestCmdLine(t *testing.T) {
 		if debug != c.ExpectedDebug {
 			t.Errorf("%s: Expected debug to be %v but got %v", k, c.ExpectedDebug, debug)
 		}
+		if postman != c.ExpectedPostman {
+			t.Errorf("%s: Expected postman to be %v but got %v", k, c.ExpectedPostman, postman)
+		}
+	}
+}
+
+func TestGraphQLFlag(t *testing.T) {
+	var graphql bool
+	gen = func(_ string, _, _ string, _ bool, opts options) error {
+		graphql = opts.GraphQL
+		return nil
+	}
+	defer func() { gen = generate }()
+
+	cases := map[string]struct {
+		CmdLine  string
+		Expected bool
+	}{
+		"default": {"gen /test", false},
+		"graphql": {"gen /test -graphql", true},
+	}
+	for k, c := range cases {
+		graphql = false
+		os.Args = append([]string{"goa"}, strings.Split(c.CmdLine, " ")...)
+		main()
+		if graphql != c.Expected {
+			t.Errorf("%s: Expected graphql to be %v but got %v", k, c.Expected, graphql)
+		}
+	}
+}
+
+func TestFuzzFlag(t *testing.T) {
+	var fuzz bool
+	gen = func(_ string, _, _ string, _ bool, opts options) error {
+		fuzz = opts.Fuzz
+		return nil
+	}
+	defer func() { gen = generate }()
+
+	cases := map[string]struct {
+		CmdLine  string
+		Expected bool
+	}{
+		"default": {"gen /test", false},
+		"fuzz":    {"gen /test -fuzz", true},
+	}
+	for k, c := range cases {
+		fuzz = false
+		os.Args = append([]string{"goa"}, strings.Split(c.CmdLine, " ")...)
+		main()
+		if fuzz != c.Expected {
+			t.Errorf("%s: Expected fuzz to be %v but got %v", k, c.Expected, fuzz)
+		}
+	}
+}
+
+func TestLoadTestFlag(t *testing.T) {
+	var format string
+	gen = func(_ string, _, _ string, _ bool, opts options) error {
+		format = opts.LoadTest
+		return nil
+	}
+	defer func() { gen = generate }()
+
+	cases := map[string]struct {
+		CmdLine  string
+		Expected string
+	}{
+		"default": {"gen /test", ""},
+		"k6":      {"gen /test -loadtest k6", "k6"},
+		"vegeta":  {"gen /test --loadtest vegeta", "vegeta"},
+	}
+	for k, c := range cases {
+		format = ""
+		os.Args = append([]string{"goa"}, strings.Split(c.CmdLine, " ")...)
+		main()
+		if format != c.Expected {
+			t.Errorf("%s: Expected loadtest format to be %q but got %q", k, c.Expected, format)
+		}
+	}
+}
+
+func TestServiceFlag(t *testing.T) {
+	var services []string
+	gen = func(_ string, _, _ string, _ bool, opts options) error {
+		services = opts.Services
+		return nil
+	}
+	defer func() { gen = generate }()
+
+	cases := map[string]struct {
+		CmdLine  string
+		Expected []string
+	}{
+		"none":     {"gen /test", nil},
+		"single":   {"gen /test -service foo", []string{"foo"}},
+		"multiple": {"gen /test -service foo --service bar", []string{"foo", "bar"}},
+	}
+	for k, c := range cases {
+		services = nil
+		os.Args = append([]string{"goa"}, strings.Split(c.CmdLine, " ")...)
+		main()
+		if strings.Join(services, ",") != strings.Join(c.Expected, ",") {
+			t.Errorf("%s: Expected services to be %v but got %v", k, c.Expected, services)
+		}
 	}
 }
@@ -0,0 +1,264 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+
+	"goa.design/goa/v3/codegen"
+	goa "goa.design/goa/v3/pkg"
+	"golang.org/x/tools/go/packages"
+)
+
+type (
+	// manifest maps the slash separated path of each file in a generated
+	// tree relative to the tree root to the SHA-256 hash of its content.
+	manifest map[string]string
+
+	// drift describes a difference between the committed and the
+	// regenerated code.
+	drift struct {
+		// Path is the slash separated path of the file relative to the
+		// gen directory.
+		Path string
+		// Kind is one of "modified", "missing" or "stale".
+		Kind string
+	}
+)
+
+// versionHeader matches the version recorded in generated file headers.
+var versionHeader = regexp.MustCompile(`^// Code generated by goa (v\S+), DO NOT EDIT\.`)
+
+// verify regenerates the code for the design package at path in a temporary
+// directory using the given options and compares the result with the gen
+// directory under output. It returns an error if the trees differ or if the
+// committed code was generated with a different version of goa.
+func verify(path, output string, debug bool, opts options) error {
+	gendir := filepath.Join(output, codegen.Gendir)
+	committed, err := newManifest(gendir)
+	if err != nil {
+		return fmt.Errorf("failed to read generated code: %w", err)
+	}
+	if len(committed) == 0 {
+		return fmt.Errorf("no generated code found in %s", gendir)
+	}
+	genpkg, err := genPackagePath(gendir)
+	if err != nil {
+		return err
+	}
+	tmpOut, err := os.MkdirTemp("", "goa-verify")
+	if err != nil {
+		return err
+	}
+	defer os.RemoveAll(tmpOut)
+
+	tmp := NewGenerator("gen", path, tmpOut)
+	tmp.GenPkg = genpkg
+	tmp.Options = opts
+	tmp.CmdLine = recordedCommand(gendir)
+	if err := tmp.Write(debug); err != nil {
+		tmp.Remove()
+		return err
+	}
+	if !debug {
+		defer tmp.Remove()
+	}
+	if err := tmp.Compile(); err != nil {
+		return err
+	}
+	if _, err := tmp.Run(); err != nil {
+		return err
+	}
+	generated, err := newManifest(filepath.Join(tmpOut, codegen.Gendir))
+	if err != nil {
+		return err
+	}
+
+	drifts := committed.diff(generated)
+	versions := recordedVersions(gendir)
+	var mismatch []string
+	for _, v := range versions {
+		if v != goa.Version() {
+			mismatch = append(mismatch, v)
+		}
+	}
+	if len(drifts) == 0 && len(mismatch) == 0 {
+		fmt.Printf("generated code in %s is up to date (%d files)\n", gendir, len(committed))
+		return nil
+	}
+	var msg bytes.Buffer
+	if len(mismatch) > 0 {
+		fmt.Fprintf(&msg, "generated code in %s was produced by goa %s, running goa %s\n",
+			gendir, strings.Join(mismatch, ", "), goa.Version())
+	}
+	if len(drifts) > 0 {
+		fmt.Fprintf(&msg, "generated code in %s does not match the design:\n", gendir)
+		for _, d := range drifts {
+			fmt.Fprintf(&msg, "  %-8s %s\n", d.Kind, d.Path)
+		}
+	}
+	return fmt.Errorf("%s", strings.TrimSuffix(msg.String(), "\n"))
+}
+
+// newManifest computes the manifest of the files under root. It returns an
+// empty manifest if root does not exist. Line endings are normalized prior to
+// hashing so that checkouts using CRLF line endings verify identically.
+func newManifest(root string) (manifest, error) {
+	m := make(manifest)
+	err := filepath.WalkDir(root, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			if os.IsNotExist(err) && path == root {
+				return filepath.SkipDir
+			}
+			return err
+		}
+		if d.IsDir() {
+			return nil
+		}
+		b, err := os.ReadFile(path)
+		if err != nil {
+			return err
+		}
+		rel, err := filepath.Rel(root, path)
+		if err != nil {
+			return err
+		}
+		if rel == codegen.Manifest {
+			// The manifest is only present in trees generated by recent
+			// versions of goa.
+			return nil
+		}
+		sum := sha256.Sum256(bytes.ReplaceAll(b, []byte("\r\n"), []byte("\n")))
+		m[filepath.ToSlash(rel)] = hex.EncodeToString(sum[:])
+		return nil
+	})
+	return m, err
+}
+
+// diff returns the differences between m (the committed tree) and other (the
+// regenerated tree) sorted by path. Files in m but not in other are "stale",
+// files in other but not in m are "missing".
+func (m manifest) diff(other manifest) []drift {
+	var drifts []drift
+	for p, h := range m {
+		oh, ok := other[p]
+		switch {
+		case !ok:
+			drifts = append(drifts, drift{Path: p, Kind: "stale"})
+		case oh != h:
+			drifts = append(drifts, drift{Path: p, Kind: "modified"})
+		}
+	}
+	for p := range other {
+		if _, ok := m[p]; !ok {
+			drifts = append(drifts, drift{Path: p, Kind: "missing"})
+		}
+	}
+	sort.Slice(drifts, func(i, j int) bool { return drifts[i].Path < drifts[j].Path })
+	return drifts
+}
+
+// genPackagePath returns the Go import path of the package rooted at gendir.
+// The path is computed by loading a temporary package created next to gendir
+// so that the committed generated code is left untouched.
+func genPackagePath(gendir string) (string, error) {
+	abs, err := filepath.Abs(gendir)
+	if err != nil {
+		return "", err
+	}
+	tmp, err := os.MkdirTemp(filepath.Dir(abs), "goa-verify")
+	if err != nil {
+		return "", err
+	}
+	defer os.RemoveAll(tmp)
+	if err := os.WriteFile(filepath.Join(tmp, "temp.go"), []byte("package gen"), 0600); err != nil {
+		return "", err
+	}
+	pkgs, err := packages.Load(&packages.Config{Mode: packages.NeedName, Dir: tmp}, ".")
+	if err != nil {
+		return "", err
+	}
+	if len(pkgs) != 1 || pkgs[0].PkgPath == "" {
+		return "", fmt.Errorf("expected to find one package in %s", filepath.Dir(gendir))
+	}
+	return path.Join(path.Dir(pkgs[0].PkgPath), filepath.Base(abs)), nil
+}
+
+// recordedCommand returns the command line recorded in the headers of the
+// Go files generated under gendir, the empty string if there is none.
+func recordedCommand(gendir string) string {
+	var cmdl string
+	walkGoHeaders(gendir, func(lines []string) bool {
+		for i, l := range lines {
+			if l != "// Command:" || i+1 == len(lines) {
+				continue
+			}
+			// Long command lines are wrapped on spaces.
+			parts := make([]string, len(lines)-i-1)
+			for j, cl := range lines[i+1:] {
+				parts[j] = strings.TrimPrefix(cl, "// ")
+			}
+			cmdl = strings.Join(parts, " ")
+			return false
+		}
+		return true
+	})
+	return cmdl
+}
+
+// recordedVersions returns the sorted list of goa versions recorded in the
+// headers of the Go files generated under gendir.
+func recordedVersions(gendir string) []string {
+	seen := make(map[string]struct{})
+	walkGoHeaders(gendir, func(lines []string) bool {
+		if len(lines) > 0 {
+			if m := versionHeader.FindStringSubmatch(lines[0]); m != nil {
+				seen[m[1]] = struct{}{}
+			}
+		}
+		return true
+	})
+	versions := make([]string, 0, len(seen))
+	for v := range seen {
+		versions = append(versions, v)
+	}
+	sort.Strings(versions)
+	return versions
+}
+
+// walkGoHeaders calls fn with the leading comment lines of each Go file under
+// root until fn returns false.
+func walkGoHeaders(root string, fn func([]string) bool) {
+	filepath.WalkDir(root, func(path string, d fs.DirEntry, err error) error { // nolint: errcheck
+		if err != nil || d.IsDir() || filepath.Ext(path) != ".go" {
+			return nil
+		}
+		f, err := os.Open(path)
+		if err != nil {
+			return nil
+		}
+		defer f.Close()
+		var lines []string
+		s := bufio.NewScanner(f)
+		for s.Scan() {
+			l := s.Text()
+			if !strings.HasPrefix(l, "//") {
+				break
+			}
+			lines = append(lines, l)
+		}
+		if !fn(lines) {
+			return filepath.SkipAll
+		}
+		return nil
+	})
+}
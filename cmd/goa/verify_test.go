@@ -0,0 +1,118 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestManifestDiff(t *testing.T) {
+	committed := manifest{"a.go": "1", "b.go": "2", "c.go": "3"}
+	generated := manifest{"a.go": "1", "b.go": "4", "d.go": "5"}
+
+	drifts := committed.diff(generated)
+
+	expected := []drift{{"b.go", "modified"}, {"c.go", "stale"}, {"d.go", "missing"}}
+	if len(drifts) != len(expected) {
+		t.Fatalf("got %d drifts, expected %d: %v", len(drifts), len(expected), drifts)
+	}
+	for i, d := range drifts {
+		if d != expected[i] {
+			t.Errorf("drift %d: got %v, expected %v", i, d, expected[i])
+		}
+	}
+	if d := committed.diff(committed); len(d) != 0 {
+		t.Errorf("got drifts %v comparing manifest with itself", d)
+	}
+}
+
+func TestNewManifest(t *testing.T) {
+	lf, crlf := t.TempDir(), t.TempDir()
+	write := func(dir, name, content string) {
+		path := filepath.Join(dir, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0750); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0600); err != nil {
+			t.Fatal(err)
+		}
+	}
+	write(lf, filepath.Join("svc", "service.go"), "package svc\n\nconst A = 1\n")
+	write(crlf, filepath.Join("svc", "service.go"), "package svc\r\n\r\nconst A = 1\r\n")
+
+	m1, err := newManifest(lf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	m2, err := newManifest(crlf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := m1["svc/service.go"]; !ok {
+		t.Errorf("expected slash separated key svc/service.go, got %v", m1)
+	}
+	if d := m1.diff(m2); len(d) != 0 {
+		t.Errorf("expected line endings to be normalized, got drifts %v", d)
+	}
+	missing, err := newManifest(filepath.Join(lf, "missing"))
+	if err != nil {
+		t.Errorf("expected no error for missing directory, got %s", err)
+	}
+	if len(missing) != 0 {
+		t.Errorf("expected empty manifest for missing directory, got %v", missing)
+	}
+}
+
+func TestRecordedCommand(t *testing.T) {
+	dir := t.TempDir()
+	header := `// Code generated by goa v3.0.0, DO NOT EDIT.
+//
+// svc service
+//
+// Command:
+// $ goa gen goa.design/goa/v3/examples/basic/design -o
+// $(GOPATH)/src/goa.design/goa/v3/examples/basic
+
+package svc
+`
+	if err := os.WriteFile(filepath.Join(dir, "service.go"), []byte(header), 0600); err != nil {
+		t.Fatal(err)
+	}
+	expected := "$ goa gen goa.design/goa/v3/examples/basic/design -o $(GOPATH)/src/goa.design/goa/v3/examples/basic"
+	if cmdl := recordedCommand(dir); cmdl != expected {
+		t.Errorf("got command %q, expected %q", cmdl, expected)
+	}
+	if vs := recordedVersions(dir); len(vs) != 1 || vs[0] != "v3.0.0" {
+		t.Errorf("got versions %v, expected [v3.0.0]", vs)
+	}
+}
+
+func TestGenPackagePath(t *testing.T) {
+	dir := t.TempDir()
+	gendir := filepath.Join(dir, "gen")
+	if err := os.WriteFile(filepath.Join(dir, "go.mod"), []byte("module example.com/app\n\ngo 1.20\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Mkdir(gendir, 0700); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(gendir, "doc.go"), []byte("package gen\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	pkg, err := genPackagePath(gendir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if pkg != "example.com/app/gen" {
+		t.Errorf("got package path %q, expected %q", pkg, "example.com/app/gen")
+	}
+	for d, n := range map[string]int{dir: 2, gendir: 1} {
+		entries, err := os.ReadDir(d)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(entries) != n {
+			t.Errorf("got %d entries in %s, expected %d", len(entries), d, n)
+		}
+	}
+}
@@ -0,0 +1,226 @@
+package main
+
+import (
+	"fmt"
+	"go/build"
+	"io/fs"
+	"os"
+	"os/exec"
+	"os/signal"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"syscall"
+	"time"
+
+	"goa.design/goa/v3/codegen"
+)
+
+type (
+	// snapshot records the modification time and size of the watched files
+	// indexed by path.
+	snapshot map[string]string
+
+	// runner builds and runs the server restarted after each successful
+	// generation.
+	runner struct {
+		// pkg is the path to the Go package of the server main relative
+		// to the output directory.
+		pkg string
+		// args are the server command line arguments.
+		args []string
+		// dir is the output directory.
+		dir string
+		// bin is the path to the server binary.
+		bin string
+		// cmd is the running server if any.
+		cmd *exec.Cmd
+	}
+)
+
+// pollInterval is the interval at which the watched files are checked for
+// changes.
+var pollInterval = 500 * time.Millisecond
+
+// watch runs the generation once and then each time a Go file of the design
+// package (or of its sub-packages) or a template override changes. If
+// opts.Run is set watch also builds and (re)starts the corresponding server
+// after each successful generation. watch returns when the process receives
+// an interrupt or termination signal.
+func watch(cmd, path, output string, debug bool, opts options) error {
+	if cmd != "gen" && cmd != "example" {
+		return fmt.Errorf("the -watch flag can only be used with the gen and example commands")
+	}
+	pkg, err := build.Import(path, ".", build.FindOnly)
+	if err != nil {
+		return err
+	}
+	dirs := []string{pkg.Dir}
+	if opts.Templates != "" {
+		dirs = append(dirs, opts.Templates)
+	}
+	outdir, err := filepath.Abs(output)
+	if err != nil {
+		return err
+	}
+	ignored := []string{filepath.Join(outdir, codegen.Gendir), filepath.Join(outdir, "cmd")}
+
+	var run *runner
+	if opts.Run != "" {
+		run, err = newRunner(opts.Run, outdir)
+		if err != nil {
+			return err
+		}
+		defer run.stop()
+	}
+
+	stop := make(chan struct{})
+	sig := make(chan os.Signal, 1)
+	signal.Notify(sig, os.Interrupt, syscall.SIGTERM)
+	defer signal.Stop(sig)
+	go func() {
+		<-sig
+		close(stop)
+	}()
+
+	regen := func() {
+		if err := gen(cmd, path, output, debug, opts); err != nil {
+			fmt.Fprintln(os.Stderr, err.Error())
+			return
+		}
+		if run != nil {
+			if err := run.restart(); err != nil {
+				fmt.Fprintln(os.Stderr, err.Error())
+			}
+		}
+	}
+	regen()
+	fmt.Fprintf(os.Stderr, "watching %s for changes...\n", strings.Join(dirs, ", "))
+	watchLoop(dirs, ignored, stop, func() {
+		fmt.Fprintln(os.Stderr, "design changed, regenerating...")
+		regen()
+	})
+	return nil
+}
+
+// watchLoop calls fn each time the Go and template files under dirs change
+// until stop is closed. The files under the ignored directories are not
+// watched.
+func watchLoop(dirs, ignored []string, stop <-chan struct{}, fn func()) {
+	last := takeSnapshot(dirs, ignored)
+	ticker := time.NewTicker(pollInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-stop:
+			return
+		case <-ticker.C:
+			cur := takeSnapshot(dirs, ignored)
+			if cur.equal(last) {
+				continue
+			}
+			last = cur
+			fn()
+		}
+	}
+}
+
+// takeSnapshot returns the state of the Go and template files under dirs.
+func takeSnapshot(dirs, ignored []string) snapshot {
+	s := make(snapshot)
+	for _, dir := range dirs {
+		filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error { // nolint: errcheck
+			if err != nil {
+				return nil
+			}
+			if d.IsDir() {
+				for _, ig := range ignored {
+					if path == ig {
+						return filepath.SkipDir
+					}
+				}
+				if path != dir && strings.HasPrefix(d.Name(), ".") {
+					return filepath.SkipDir
+				}
+				return nil
+			}
+			if ext := filepath.Ext(path); ext != ".go" && ext != ".tmpl" {
+				return nil
+			}
+			fi, err := d.Info()
+			if err != nil {
+				return nil
+			}
+			s[path] = fmt.Sprintf("%d-%d", fi.ModTime().UnixNano(), fi.Size())
+			return nil
+		})
+	}
+	return s
+}
+
+// equal returns true if s and other record the same files in the same state.
+func (s snapshot) equal(other snapshot) bool {
+	if len(s) != len(other) {
+		return false
+	}
+	for p, st := range s {
+		if other[p] != st {
+			return false
+		}
+	}
+	return true
+}
+
+// newRunner returns a runner for the given command line made of the path to
+// the server main package relative to dir followed by the server arguments.
+func newRunner(cmdline, dir string) (*runner, error) {
+	fields := strings.Fields(cmdline)
+	if len(fields) == 0 {
+		return nil, fmt.Errorf("missing server package in -run flag")
+	}
+	tmp, err := os.MkdirTemp("", "goa-run")
+	if err != nil {
+		return nil, err
+	}
+	bin := filepath.Join(tmp, "server")
+	if runtime.GOOS == "windows" {
+		bin += ".exe"
+	}
+	return &runner{pkg: fields[0], args: fields[1:], dir: dir, bin: bin}, nil
+}
+
+// restart builds the server and restarts it.
+func (r *runner) restart() error {
+	build := exec.Command("go", "build", "-o", r.bin, r.pkg)
+	build.Dir = r.dir
+	if out, err := build.CombinedOutput(); err != nil {
+		return fmt.Errorf("failed to build %s: %s\n%s", r.pkg, err, out)
+	}
+	r.kill()
+	r.cmd = exec.Command(r.bin, r.args...)
+	r.cmd.Dir = r.dir
+	r.cmd.Stdout = os.Stdout
+	r.cmd.Stderr = os.Stderr
+	if err := r.cmd.Start(); err != nil {
+		r.cmd = nil
+		return fmt.Errorf("failed to start %s: %w", r.pkg, err)
+	}
+	fmt.Fprintf(os.Stderr, "started %s (pid %d)\n", r.pkg, r.cmd.Process.Pid)
+	return nil
+}
+
+// kill stops the running server if any.
+func (r *runner) kill() {
+	if r.cmd == nil {
+		return
+	}
+	r.cmd.Process.Kill() // nolint: errcheck
+	r.cmd.Wait()         // nolint: errcheck
+	r.cmd = nil
+}
+
+// stop stops the running server and deletes its binary.
+func (r *runner) stop() {
+	r.kill()
+	os.RemoveAll(filepath.Dir(r.bin)) // nolint: errcheck
+}
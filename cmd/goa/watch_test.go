@@ -0,0 +1,67 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestWatchLoop(t *testing.T) {
+	dir := t.TempDir()
+	ignored := filepath.Join(dir, "gen")
+	write := func(path, content string) {
+		t.Helper()
+		path = filepath.Join(dir, path)
+		if err := os.MkdirAll(filepath.Dir(path), 0750); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0600); err != nil {
+			t.Fatal(err)
+		}
+	}
+	write("design.go", "package design")
+
+	defer func(d time.Duration) { pollInterval = d }(pollInterval)
+	pollInterval = 10 * time.Millisecond
+	changed := make(chan struct{}, 10)
+	stop := make(chan struct{})
+	done := make(chan struct{})
+	go func() {
+		watchLoop([]string{dir}, []string{ignored}, stop, func() { changed <- struct{}{} })
+		close(done)
+	}()
+	wait := func(expected bool) {
+		t.Helper()
+		select {
+		case <-changed:
+			if !expected {
+				t.Error("got unexpected change")
+			}
+		case <-time.After(200 * time.Millisecond):
+			if expected {
+				t.Error("change not detected")
+			}
+		}
+	}
+
+	time.Sleep(3 * pollInterval)
+	write("gen/service.go", "package gen")
+	write("README.md", "readme")
+	write(".git/HEAD", "ref")
+	wait(false)
+
+	write("sub/types.go", "package sub")
+	wait(true)
+
+	write("design.go", "package design // changed")
+	wait(true)
+
+	if err := os.Remove(filepath.Join(dir, "sub", "types.go")); err != nil {
+		t.Fatal(err)
+	}
+	wait(true)
+
+	close(stop)
+	<-done
+}
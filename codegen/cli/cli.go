@@ -76,6 +76,9 @@ type (
 		Example string
 		// Default returns the default value if any.
 		Default any
+		// Decode is the function that decodes the flag value if any, see
+		// the "struct:field:decode" metadata.
+		Decode string
 	}
 
 	// BuildFunctionData contains the data needed to generate a constructor
@@ -347,7 +350,11 @@ func FieldLoadCode(f *FlagData, argName, argTypeName, validate string, defaultVa
 			declErr = validate != ""
 		} else {
 			var checkErr bool
-			code, declErr, checkErr = conversionCode(f.FullName, argName, argTypeName, !f.Required && defaultValue == nil)
+			if f.Decode != "" {
+				code, declErr, checkErr = decodeCode(f.FullName, argName, f.Decode, !f.Required && defaultValue == nil)
+			} else {
+				code, declErr, checkErr = conversionCode(f.FullName, argName, argTypeName, !f.Required && defaultValue == nil)
+			}
 			if checkErr {
 				code += "\nif err != nil {\n"
 				nilVal := "nil"
@@ -355,7 +362,9 @@ func FieldLoadCode(f *FlagData, argName, argTypeName, validate string, defaultVa
 					code += fmt.Sprintf("var zero %s\n", payloadRef)
 					nilVal = "zero"
 				}
-				if flagType(argTypeName) == "JSON" {
+				if f.Decode != "" {
+					code += fmt.Sprintf(`return %s, fmt.Errorf("invalid value for %s, %%s", err)`, nilVal, argName)
+				} else if flagType(argTypeName) == "JSON" {
 					code += fmt.Sprintf(`return %s, fmt.Errorf("invalid JSON for %s, \nerror: %%s, \nexample of valid JSON:\n%%s", err, %q)`,
 						nilVal, argName, f.Example)
 				} else {
@@ -543,6 +552,16 @@ func conversionCode(from, to, typeName string, pointer bool) (string, bool, bool
 	return parse, declErr, checkErr
 }
 
+// decodeCode produces the code that decodes the string contained in the
+// variable named from with the function decode and stores the result in the
+// variable "to". The return values are the same as conversionCode.
+func decodeCode(from, to, decode string, pointer bool) (string, bool, bool) {
+	if pointer {
+		return fmt.Sprintf("val, err := %s(%s)\n%s = &val", decode, from, to), false, true
+	}
+	return fmt.Sprintf("%s, err = %s(%s)", to, decode, from), true, true
+}
+
 // goifyTerms makes valid go identifiers out of the supplied terms
 func goifyTerms(terms ...string) string {
 	res := codegen.Goify(terms[0], false)
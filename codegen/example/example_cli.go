@@ -1,7 +1,6 @@
 package example
 
 import (
-	"os"
 	"path/filepath"
 	"strings"
 
@@ -27,9 +26,6 @@ func exampleCLIMain(_ string, root *expr.RootExpr, svr *expr.ServerExpr) *codege
 	svrdata := Servers.Get(svr)
 
 	path := filepath.Join("cmd", svrdata.Dir+"-cli", "main.go")
-	if _, err := os.Stat(path); !os.IsNotExist(err) {
-		return nil // file already exists, skip it.
-	}
 	specs := []*codegen.ImportSpec{
 		{Path: "context"},
 		{Path: "encoding/json"},
@@ -1,7 +1,6 @@
 package example
 
 import (
-	"os"
 	"path"
 	"path/filepath"
 	"strings"
@@ -28,9 +27,6 @@ func ServerFiles(genpkg string, root *expr.RootExpr) []*codegen.File {
 func exampleSvrMain(genpkg string, root *expr.RootExpr, svr *expr.ServerExpr) *codegen.File {
 	svrdata := Servers.Get(svr)
 	mainPath := filepath.Join("cmd", svrdata.Dir, "main.go")
-	if _, err := os.Stat(mainPath); !os.IsNotExist(err) {
-		return nil // file already exists, skip it.
-	}
 	specs := []*codegen.ImportSpec{
 		{Path: "context"},
 		{Path: "flag"},
@@ -34,6 +34,11 @@ type (
 		Variables []*VariableData
 		// Transports is the list of transports defined in the server.
 		Transports []*TransportData
+		// WebServices is the list of services whose gRPC endpoints are
+		// also served to gRPC-Web and Connect clients by the HTTP server.
+		// These are the services that define both transports and the
+		// "grpc:web" meta.
+		WebServices []string
 		// Dir is the directory name for the generated client and server examples.
 		Dir string
 	}
@@ -202,6 +207,7 @@ func buildServerData(svr *expr.ServerExpr) *Data {
 		transports   []*TransportData
 		httpServices []string
 		grpcServices []string
+		webServices  []string
 
 		foundTrans = make(map[Transport]struct{})
 	)
@@ -222,6 +228,9 @@ func buildServerData(svr *expr.ServerExpr) *Data {
 					transports = append(transports, newGRPCTransport())
 					foundTrans[TransportGRPC] = struct{}{}
 				}
+				if _, ok := expr.Root.Service(svc).Meta["grpc:web"]; ok && expr.Root.API.HTTP.Service(svc) != nil {
+					webServices = append(webServices, svc)
+				}
 			}
 		}
 	}
@@ -241,6 +250,7 @@ func buildServerData(svr *expr.ServerExpr) *Data {
 		Hosts:       hosts,
 		Variables:   variables,
 		Transports:  transports,
+		WebServices: webServices,
 		Dir:         codegen.SnakeCase(codegen.Goify(svr.Name, true)),
 	}
 }
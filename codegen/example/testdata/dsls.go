@@ -158,6 +158,35 @@ var ServerHostingMultipleServicesDSL = func() {
 	})
 }
 
+var ServerHostingGRPCWebDSL = func() {
+	API("ServerHostingGRPCWeb", func() {
+		Server("SingleHost", func() {
+			Services("Service", "AnotherService")
+			Host("dev", func() {
+				URI("http://example:8090")
+				URI("grpc://example:8080")
+			})
+		})
+	})
+	Service("Service", func() {
+		Meta("grpc:web")
+		Method("Method", func() {
+			HTTP(func() {
+				GET("/")
+			})
+			GRPC(func() {})
+		})
+	})
+	Service("AnotherService", func() {
+		Method("Method", func() {
+			HTTP(func() {
+				GET("/")
+			})
+			GRPC(func() {})
+		})
+	})
+}
+
 var SingleServerMultipleHostsDSL = func() {
 	API("SingleServerMultipleHosts", func() {
 		Server("MultipleHosts", func() {
@@ -19,10 +19,14 @@ import (
 )
 
 // Gendir is the name of the subdirectory of the output directory that contains
-// the generated files. This directory is wiped and re-written each time goa is
-// run.
+// the generated files. This directory is re-written each time goa is run.
 const Gendir = "gen"
 
+// Manifest is the name of the file in Gendir that lists the generated files
+// with the checksums of their content. goa uses it to delete the files that
+// are not generated anymore instead of wiping Gendir.
+const Manifest = ".goa-manifest.json"
+
 type (
 	// A File contains the logic to generate a complete file.
 	File struct {
@@ -71,6 +75,22 @@ func (f *File) Section(name string) []*SectionTemplate {
 // happens the smallest integer value greater than 1 to make it unique. Renders
 // returns the computed path.
 func (f *File) Render(dir string) (string, error) {
+	path, err := f.RenderSections(dir)
+	if err != nil || path == "" {
+		return path, err
+	}
+	if err := f.Finalize(path); err != nil {
+		return "", err
+	}
+	return path, nil
+}
+
+// RenderSections executes the file section templates and appends the
+// resulting bytes to the output file like Render but does not format the
+// file nor run the file finalizer. RenderSections and Finalize make it
+// possible to execute the templates sequentially and to finalize the files
+// concurrently.
+func (f *File) RenderSections(dir string) (string, error) {
 	base, err := filepath.Abs(dir)
 	if err != nil {
 		return "", err
@@ -102,22 +122,27 @@ func (f *File) Render(dir string) (string, error) {
 	if err := file.Close(); err != nil {
 		return "", err
 	}
+	return path, nil
+}
 
+// Finalize formats the Go source file rendered at path and runs the file
+// finalizer if any. Finalize may be called concurrently for files with
+// different paths.
+func (f *File) Finalize(path string) error {
 	// Format Go source files
 	if filepath.Ext(path) == ".go" {
 		if err := finalizeGoSource(path); err != nil {
-			return "", err
+			return err
 		}
 	}
 
 	// Run finalizer if any
 	if f.FinalizeFunc != nil {
 		if err := f.FinalizeFunc(path); err != nil {
-			return "", err
+			return err
 		}
 	}
-
-	return path, nil
+	return nil
 }
 
 // Write writes the section to the given writer.
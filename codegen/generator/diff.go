@@ -0,0 +1,54 @@
+package generator
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+
+	"goa.design/goa/v3/codegen"
+	"goa.design/goa/v3/diff"
+	"goa.design/goa/v3/eval"
+	"goa.design/goa/v3/expr"
+	openapiv3 "goa.design/goa/v3/http/codegen/openapi/v3"
+)
+
+// Diff compares the OpenAPI 3 specification of the design with the one stored
+// in the DiffAgainst file and returns an error listing the breaking changes if
+// any. It does not produce any file.
+func Diff(_ string, roots []eval.Root) ([]*codegen.File, error) {
+	if DiffAgainst == "" {
+		return nil, fmt.Errorf("missing path to the previous OpenAPI specification")
+	}
+	previous, err := os.ReadFile(DiffAgainst)
+	if err != nil {
+		return nil, err
+	}
+	for _, root := range roots {
+		r, ok := root.(*expr.RootExpr)
+		if !ok {
+			continue
+		}
+		var spec any = map[string]any{}
+		if s := openapiv3.New(r); s != nil {
+			spec = s
+		}
+		current, err := json.Marshal(spec)
+		if err != nil {
+			return nil, err
+		}
+		changes, err := diff.Compare(previous, current)
+		if err != nil {
+			return nil, err
+		}
+		if len(changes) == 0 {
+			return nil, nil
+		}
+		msgs := make([]string, len(changes))
+		for i, c := range changes {
+			msgs[i] = "  " + c.String()
+		}
+		return nil, fmt.Errorf("design has %d breaking change(s) compared to %s:\n%s", len(changes), DiffAgainst, strings.Join(msgs, "\n"))
+	}
+	return nil, nil
+}
@@ -0,0 +1,21 @@
+package generator
+
+import (
+	"goa.design/goa/v3/codegen"
+	"goa.design/goa/v3/eval"
+	"goa.design/goa/v3/expr"
+	httpcodegen "goa.design/goa/v3/http/codegen"
+)
+
+// Fuzz iterates through the roots and returns the files needed to render the
+// fuzz tests of the HTTP server request decoders and request body validation
+// functions.
+func Fuzz(_ string, roots []eval.Root) ([]*codegen.File, error) {
+	var files []*codegen.File
+	for _, root := range roots {
+		if r, ok := root.(*expr.RootExpr); ok {
+			files = append(files, httpcodegen.ServerFuzzFiles(r)...)
+		}
+	}
+	return files, nil
+}
@@ -12,17 +12,7 @@ import (
 
 // Generate runs the code generation algorithms.
 func Generate(dir, cmd string) (outputs []string, err1 error) {
-	// 1. Compute design roots.
-	var roots []eval.Root
-	{
-		rs, err := eval.Context.Roots()
-		if err != nil {
-			return nil, err
-		}
-		roots = rs
-	}
-
-	// 2. Compute "gen" package import path.
+	// Compute "gen" package import path.
 	var genpkg string
 	{
 		base, err := filepath.Abs(dir)
@@ -59,7 +49,25 @@ func Generate(dir, cmd string) (outputs []string, err1 error) {
 		genpkg = pkgs[0].PkgPath
 	}
 
-	// 3. Retrieve goa generators for given command.
+	return GenerateAs(dir, genpkg, cmd)
+}
+
+// GenerateAs runs the code generation algorithms using genpkg as the Go import
+// path of the "gen" package instead of computing it from dir. This makes it
+// possible to render the generated code in a directory that does not belong to
+// the design module, for example to compare it with a committed copy.
+func GenerateAs(dir, genpkg, cmd string) ([]string, error) {
+	// 1. Compute design roots.
+	var roots []eval.Root
+	{
+		rs, err := eval.Context.Roots()
+		if err != nil {
+			return nil, err
+		}
+		roots = rs
+	}
+
+	// 2. Retrieve goa generators for given command.
 	var genfuncs []Genfunc
 	{
 		gs, err := Generators(cmd)
@@ -69,13 +77,13 @@ func Generate(dir, cmd string) (outputs []string, err1 error) {
 		genfuncs = gs
 	}
 
-	// 4. Run the code pre generation plugins.
+	// 3. Run the code pre generation plugins.
 	err := codegen.RunPluginsPrepare(cmd, genpkg, roots)
 	if err != nil {
 		return nil, err
 	}
 
-	// 5. Generate initial set of files produced by goa code generators.
+	// 4. Generate initial set of files produced by goa code generators.
 	var genfiles []*codegen.File
 	for _, gen := range genfuncs {
 		fs, err := gen(genpkg, roots)
@@ -85,27 +93,42 @@ func Generate(dir, cmd string) (outputs []string, err1 error) {
 		genfiles = append(genfiles, fs...)
 	}
 
-	// 6. Run the code generation plugins.
+	// 5. Run the code generation plugins.
 	genfiles, err = codegen.RunPlugins(cmd, genpkg, roots, genfiles)
 	if err != nil {
 		return nil, err
 	}
 
-	// 7. Write the files.
-	written := make(map[string]struct{})
-	for _, f := range genfiles {
-		filename, err := f.Render(dir)
+	// 6. Apply the template overrides and keep only the files of the
+	// selected services.
+	if TemplatesDir != "" {
+		if err := overrideTemplates(genfiles); err != nil {
+			return nil, err
+		}
+	}
+	var unselected func(string) bool
+	if cmd == "gen" && len(Services) > 0 {
+		genfiles, unselected, err = selectServices(dir, roots, genfiles)
 		if err != nil {
 			return nil, err
 		}
-		if filename != "" {
-			written[filename] = struct{}{}
+	}
+
+	// 7. Write the files, leaving the unchanged generated files untouched,
+	// merging the scaffold files and deleting the stale files.
+	written, conflicts, err := renderFiles(dir, genfiles)
+	if err != nil {
+		return nil, err
+	}
+	if cmd == "gen" {
+		if err := updateManifest(dir, written, unselected); err != nil {
+			return nil, err
 		}
 	}
 
 	// 8. Compute all output filenames.
+	outputs := make([]string, len(written))
 	{
-		outputs = make([]string, len(written))
 		cwd, err := os.Getwd()
 		if err != nil {
 			cwd = "."
@@ -116,6 +139,9 @@ func Generate(dir, cmd string) (outputs []string, err1 error) {
 			if err != nil {
 				rel = o
 			}
+			if conflicts[o] {
+				rel += " (merge conflicts)"
+			}
 			outputs[i] = rel
 			i++
 		}
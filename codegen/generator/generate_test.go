@@ -0,0 +1,92 @@
+package generator
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"goa.design/goa/v3/codegen"
+	"goa.design/goa/v3/codegen/example"
+	"goa.design/goa/v3/codegen/generator/testdata"
+	"goa.design/goa/v3/codegen/service"
+	grpccodegen "goa.design/goa/v3/grpc/codegen"
+	httpcodegen "goa.design/goa/v3/http/codegen"
+	"goa.design/goa/v3/http/codegen/openapi"
+)
+
+// resetCodegen resets the data structures cached by the code generators.
+func resetCodegen() {
+	service.Services = make(service.ServicesData)
+	httpcodegen.HTTPServices = make(httpcodegen.ServicesData)
+	grpccodegen.GRPCServices = make(grpccodegen.ServicesData)
+	example.Servers = make(example.ServersData)
+	openapi.Definitions = make(map[string]*openapi.Schema)
+}
+
+// generate runs the DSL and generates the code in a new temporary directory
+// using the given number of workers. It returns the generated directory.
+func generate(tb testing.TB, dsl func(), workers int) string {
+	tb.Helper()
+	defer func(w int) { Workers = w }(Workers)
+	Workers = workers
+	resetCodegen()
+	codegen.RunDSL(tb, dsl)
+	dir := tb.TempDir()
+	if _, err := GenerateAs(dir, "example.com/large/gen", "gen"); err != nil {
+		tb.Fatal(err)
+	}
+	return dir
+}
+
+func TestGenerateDeterministic(t *testing.T) {
+	sequential := generate(t, testdata.LargeDSL(5), 1)
+	parallel := generate(t, testdata.LargeDSL(5), 8)
+	seq, par := readTree(t, sequential), readTree(t, parallel)
+	if len(seq) == 0 {
+		t.Fatal("no file generated")
+	}
+	if len(seq) != len(par) {
+		t.Fatalf("got %d files with parallel generation, expected %d", len(par), len(seq))
+	}
+	for path, content := range seq {
+		if par[path] != content {
+			t.Errorf("content of %s differs between sequential and parallel generation", path)
+		}
+	}
+}
+
+// readTree returns the content of the files under dir indexed by path
+// relative to dir.
+func readTree(t *testing.T, dir string) map[string]string {
+	t.Helper()
+	res := make(map[string]string)
+	err := filepath.Walk(dir, func(path string, fi os.FileInfo, err error) error {
+		if err != nil || fi.IsDir() {
+			return err
+		}
+		b, err := os.ReadFile(path)
+		if err != nil {
+			return err
+		}
+		rel, _ := filepath.Rel(dir, path)
+		res[rel] = string(b)
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return res
+}
+
+func BenchmarkGenerate(b *testing.B) {
+	for _, n := range []int{10, 50} {
+		for _, workers := range []int{1, 4} {
+			b.Run(fmt.Sprintf("services=%d/workers=%d", n, workers), func(b *testing.B) {
+				for i := 0; i < b.N; i++ {
+					generate(b, testdata.LargeDSL(n), workers)
+				}
+			})
+		}
+	}
+}
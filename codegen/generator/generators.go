@@ -16,14 +16,66 @@ type Genfunc func(genpkg string, roots []eval.Root) ([]*codegen.File, error)
 // plugins) may override the default generators.
 var Generators = generators
 
+// GeneratePostman causes the "gen" command to also generate a Postman collection and
+// environment for the HTTP endpoints when set to true.
+var GeneratePostman bool
+
+// GenerateFuzz causes the "gen" command to also generate the fuzz tests of the
+// HTTP server request decoders and request body validation functions when set
+// to true.
+var GenerateFuzz bool
+
+// GenerateGraphQL causes the "gen" command to also generate a GraphQL gateway
+// exposing the service methods as GraphQL queries and mutations when set to
+// true.
+var GenerateGraphQL bool
+
+// DocsFormat causes the "gen" command to also generate a reference of the HTTP
+// endpoints in the given format when not empty. The supported formats are
+// "markdown" and "asciidoc".
+var DocsFormat string
+
+// LoadTestFormat causes the "gen" command to also generate a load test
+// scenario covering the HTTP endpoints in the given format when not empty. The
+// supported formats are "k6" and "vegeta".
+var LoadTestFormat string
+
+// Services lists the names of the services the "gen" command generates code
+// for. The code of all the services is generated when Services is empty.
+var Services []string
+
+// DiffAgainst is the path to the OpenAPI 3 JSON specification of the previous
+// version of the API the "diff" command compares the design with.
+var DiffAgainst string
+
 // generators returns the generator functions exposed by the generator package
 // for the given command.
 func generators(cmd string) ([]Genfunc, error) {
 	switch cmd {
 	case "gen":
-		return []Genfunc{Service, Transport, OpenAPI}, nil
+		gens := []Genfunc{Service, Transport, OpenAPI}
+		if GeneratePostman {
+			gens = append(gens, Postman)
+		}
+		if GenerateFuzz {
+			gens = append(gens, Fuzz)
+		}
+		if GenerateGraphQL {
+			gens = append(gens, GraphQL)
+		}
+		if DocsFormat != "" {
+			gens = append(gens, Docs)
+		}
+		if LoadTestFormat != "" {
+			gens = append(gens, LoadTest)
+		}
+		return gens, nil
 	case "example":
 		return []Genfunc{Example}, nil
+	case "lint":
+		return []Genfunc{Lint}, nil
+	case "diff":
+		return []Genfunc{Diff}, nil
 	default:
 		return nil, fmt.Errorf("unknown command %q", cmd)
 	}
@@ -0,0 +1,20 @@
+package generator
+
+import (
+	"goa.design/goa/v3/codegen"
+	"goa.design/goa/v3/eval"
+	"goa.design/goa/v3/expr"
+	graphqlcodegen "goa.design/goa/v3/graphql/codegen"
+)
+
+// GraphQL iterates through the roots and returns the files needed to render
+// the GraphQL gateway: the GraphQL schema and the resolvers that map the
+// queries and mutations onto the service methods.
+func GraphQL(genpkg string, roots []eval.Root) ([]*codegen.File, error) {
+	for _, root := range roots {
+		if r, ok := root.(*expr.RootExpr); ok {
+			return graphqlcodegen.ServerFiles(genpkg, r)
+		}
+	}
+	return nil, nil
+}
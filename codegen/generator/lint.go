@@ -0,0 +1,32 @@
+package generator
+
+import (
+	"fmt"
+	"strings"
+
+	"goa.design/goa/v3/codegen"
+	"goa.design/goa/v3/eval"
+	"goa.design/goa/v3/expr"
+	"goa.design/goa/v3/lint"
+)
+
+// Lint runs the registered lint rules against the design and returns an error
+// listing the violations if any. It does not produce any file.
+func Lint(_ string, roots []eval.Root) ([]*codegen.File, error) {
+	for _, root := range roots {
+		r, ok := root.(*expr.RootExpr)
+		if !ok {
+			continue
+		}
+		vs := lint.Run(r)
+		if len(vs) == 0 {
+			return nil, nil
+		}
+		msgs := make([]string, len(vs))
+		for i, v := range vs {
+			msgs[i] = "  " + v.String()
+		}
+		return nil, fmt.Errorf("design has %d lint violation(s):\n%s", len(vs), strings.Join(msgs, "\n"))
+	}
+	return nil, nil
+}
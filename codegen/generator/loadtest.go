@@ -0,0 +1,20 @@
+package generator
+
+import (
+	"goa.design/goa/v3/codegen"
+	"goa.design/goa/v3/eval"
+	"goa.design/goa/v3/expr"
+	httpcodegen "goa.design/goa/v3/http/codegen"
+)
+
+// LoadTest iterates through the roots and returns the files needed to render
+// the load test scenario covering the HTTP endpoints in the format given by
+// LoadTestFormat. It produces files only if the roots define a HTTP service.
+func LoadTest(_ string, roots []eval.Root) ([]*codegen.File, error) {
+	for _, root := range roots {
+		if r, ok := root.(*expr.RootExpr); ok {
+			return httpcodegen.LoadTestFiles(r, LoadTestFormat)
+		}
+	}
+	return nil, nil
+}
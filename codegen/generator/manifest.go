@@ -0,0 +1,144 @@
+package generator
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"goa.design/goa/v3/codegen"
+)
+
+// manifest lists the files generated by the "gen" command. It is stored in
+// the codegen.Manifest file of the gen directory.
+type manifest struct {
+	// Files maps the slash separated path of each generated file relative
+	// to the output directory to the SHA-256 checksum of its content.
+	Files map[string]string `json:"files"`
+}
+
+// updateManifest writes the manifest listing the written files and deletes
+// the files listed in the previous manifest that were not written. The entries
+// of the previous manifest for which keep returns true are kept as is, keep
+// may be nil.
+func updateManifest(dir string, written map[string]struct{}, keep func(string) bool) error {
+	base, err := filepath.Abs(dir)
+	if err != nil {
+		return err
+	}
+	prev, err := readManifest(base)
+	if err != nil {
+		return err
+	}
+	m := &manifest{Files: make(map[string]string, len(written))}
+	for path := range written {
+		sum, err := checksum(path)
+		if err != nil {
+			return err
+		}
+		rel, err := filepath.Rel(base, path)
+		if err != nil {
+			return err
+		}
+		m.Files[filepath.ToSlash(rel)] = sum
+	}
+	if prev != nil {
+		var stale []string
+		for rel, sum := range prev.Files {
+			if _, ok := m.Files[rel]; ok {
+				continue
+			}
+			if keep != nil && keep(rel) {
+				m.Files[rel] = sum
+				continue
+			}
+			stale = append(stale, filepath.Join(base, filepath.FromSlash(rel)))
+		}
+		if err := removeStale(filepath.Join(base, codegen.Gendir), stale, m, base); err != nil {
+			return err
+		}
+	}
+	return m.write(base)
+}
+
+// removeStale deletes the stale files and the directories under gendir that
+// do not contain any file listed in m after the deletion. This takes care of
+// the files created by file finalizers such as the protoc compiler.
+func removeStale(gendir string, stale []string, m *manifest, base string) error {
+	used := make(map[string]bool)
+	for rel := range m.Files {
+		for d := filepath.Dir(filepath.Join(base, filepath.FromSlash(rel))); isUnder(d, gendir); d = filepath.Dir(d) {
+			used[d] = true
+		}
+	}
+	for _, path := range stale {
+		if err := os.Remove(path); err != nil && !errors.Is(err, os.ErrNotExist) {
+			return err
+		}
+		var unused string
+		for d := filepath.Dir(path); isUnder(d, gendir) && !used[d]; d = filepath.Dir(d) {
+			unused = d
+		}
+		if unused != "" {
+			if err := os.RemoveAll(unused); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// isUnder returns true if path is a descendant of dir.
+func isUnder(path, dir string) bool {
+	return strings.HasPrefix(path, dir+string(filepath.Separator))
+}
+
+// readManifest reads the manifest in the gen directory under base, it returns
+// nil if there is none.
+func readManifest(base string) (*manifest, error) {
+	b, err := os.ReadFile(filepath.Join(base, codegen.Gendir, codegen.Manifest))
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	var m manifest
+	if err := json.Unmarshal(b, &m); err != nil {
+		return nil, err
+	}
+	return &m, nil
+}
+
+// write writes the manifest in the gen directory under base unless its
+// content is unchanged. The entries are sorted by path so that the manifest
+// content is deterministic.
+func (m *manifest) write(base string) error {
+	b, err := json.MarshalIndent(m, "", "  ")
+	if err != nil {
+		return err
+	}
+	b = append(b, '\n')
+	path := filepath.Join(base, codegen.Gendir, codegen.Manifest)
+	if cur, err := os.ReadFile(path); err == nil && bytes.Equal(cur, b) {
+		return nil
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0750); err != nil {
+		return err
+	}
+	return os.WriteFile(path, b, 0600)
+}
+
+// checksum returns the hex encoded SHA-256 checksum of the file content.
+func checksum(path string) (string, error) {
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return "", err
+	}
+	sum := sha256.Sum256(b)
+	return hex.EncodeToString(sum[:]), nil
+}
@@ -0,0 +1,73 @@
+package generator
+
+import (
+	"os"
+	"path/filepath"
+	"sort"
+	"testing"
+	"time"
+
+	"goa.design/goa/v3/codegen"
+)
+
+func TestRenderIncremental(t *testing.T) {
+	dir := t.TempDir()
+	file := func(path, content string) *codegen.File {
+		return &codegen.File{
+			Path:             filepath.Join(codegen.Gendir, path),
+			SectionTemplates: []*codegen.SectionTemplate{{Name: "test", Source: content}},
+		}
+	}
+	generate := func(files ...*codegen.File) {
+		t.Helper()
+		written, _, err := renderFiles(dir, files)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := updateManifest(dir, written, nil); err != nil {
+			t.Fatal(err)
+		}
+	}
+	path := func(p string) string { return filepath.Join(dir, codegen.Gendir, p) }
+
+	generate(file("a/a.txt", "a"), file("b/b.txt", "b"), file("b/c.txt", "c"))
+	old := time.Now().Add(-time.Hour).Truncate(time.Second)
+	for _, p := range []string{"a/a.txt", "b/b.txt"} {
+		if err := os.Chtimes(path(p), old, old); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	generate(file("a/a.txt", "a"), file("b/b.txt", "modified"))
+
+	if fi, err := os.Stat(path("a/a.txt")); err != nil || !fi.ModTime().Equal(old) {
+		t.Errorf("unchanged file a/a.txt was rewritten")
+	}
+	if b, err := os.ReadFile(path("b/b.txt")); err != nil || string(b) != "modified" {
+		t.Errorf("got b/b.txt content %q, expected %q", b, "modified")
+	}
+	if _, err := os.Stat(path("b/c.txt")); !os.IsNotExist(err) {
+		t.Errorf("stale file b/c.txt was not deleted")
+	}
+	m, err := readManifest(dir)
+	if err != nil || m == nil {
+		t.Fatalf("failed to read manifest: %v", err)
+	}
+	var files []string
+	for f := range m.Files {
+		files = append(files, f)
+	}
+	sort.Strings(files)
+	if len(files) != 2 || files[0] != "gen/a/a.txt" || files[1] != "gen/b/b.txt" {
+		t.Errorf("got manifest files %v, expected [gen/a/a.txt gen/b/b.txt]", files)
+	}
+
+	generate(file("b/b.txt", "modified"))
+
+	if _, err := os.Stat(path("a")); !os.IsNotExist(err) {
+		t.Errorf("directory a without generated files was not deleted")
+	}
+	if matches, _ := filepath.Glob(filepath.Join(dir, codegen.Gendir, "*", "*"+prevSuffix)); len(matches) > 0 {
+		t.Errorf("leftover files %v", matches)
+	}
+}
@@ -0,0 +1,184 @@
+package generator
+
+import "strings"
+
+// hunk describes the replacement of the lines [start, end) of the base version
+// of a file with lines.
+type hunk struct {
+	start, end int
+	lines      []string
+}
+
+// Conflict markers written by merge3.
+const (
+	conflictStart = "<<<<<<< edited"
+	conflictSep   = "======="
+	conflictEnd   = ">>>>>>> generated"
+)
+
+// merge3 merges the changes made to base in edited and in generated. It
+// returns the merged content and true if the two versions change the same
+// lines differently in which case the merged content contains both versions
+// delimited with conflict markers.
+func merge3(base, edited, generated string) (string, bool) {
+	if edited == base || edited == generated {
+		return generated, false
+	}
+	if generated == base {
+		return edited, false
+	}
+	bl := splitLines(base)
+	ours := diffLines(bl, splitLines(edited))
+	theirs := diffLines(bl, splitLines(generated))
+
+	var (
+		res       []string
+		conflicts bool
+		pos       int
+	)
+	for len(ours) > 0 || len(theirs) > 0 {
+		// Compute the group of overlapping or adjacent hunks starting
+		// with the hunk with the lowest start line.
+		start := len(bl)
+		if len(ours) > 0 {
+			start = ours[0].start
+		}
+		if len(theirs) > 0 && theirs[0].start < start {
+			start = theirs[0].start
+		}
+		end := start
+		var og, tg []hunk
+		for {
+			grown := false
+			for len(ours) > 0 && ours[0].start <= end {
+				if ours[0].end > end {
+					end = ours[0].end
+				}
+				og, ours = append(og, ours[0]), ours[1:]
+				grown = true
+			}
+			for len(theirs) > 0 && theirs[0].start <= end {
+				if theirs[0].end > end {
+					end = theirs[0].end
+				}
+				tg, theirs = append(tg, theirs[0]), theirs[1:]
+				grown = true
+			}
+			if !grown {
+				break
+			}
+		}
+
+		res = append(res, bl[pos:start]...)
+		switch {
+		case len(tg) == 0:
+			res = append(res, apply(bl, start, end, og)...)
+		case len(og) == 0:
+			res = append(res, apply(bl, start, end, tg)...)
+		default:
+			o, t := apply(bl, start, end, og), apply(bl, start, end, tg)
+			if strings.Join(o, "") == strings.Join(t, "") {
+				res = append(res, o...)
+				break
+			}
+			conflicts = true
+			res = append(res, conflictStart+"\n")
+			res = append(res, terminated(o)...)
+			res = append(res, conflictSep+"\n")
+			res = append(res, terminated(t)...)
+			res = append(res, conflictEnd+"\n")
+		}
+		pos = end
+	}
+	res = append(res, bl[pos:]...)
+	return strings.Join(res, ""), conflicts
+}
+
+// apply returns the lines [start, end) of base modified with the given hunks.
+func apply(base []string, start, end int, hunks []hunk) []string {
+	var res []string
+	pos := start
+	for _, h := range hunks {
+		res = append(res, base[pos:h.start]...)
+		res = append(res, h.lines...)
+		pos = h.end
+	}
+	return append(res, base[pos:end]...)
+}
+
+// terminated makes sure the last line ends with a newline so that conflict
+// markers start on their own line.
+func terminated(lines []string) []string {
+	if n := len(lines); n > 0 && !strings.HasSuffix(lines[n-1], "\n") {
+		lines = append(lines[:n-1:n-1], lines[n-1]+"\n")
+	}
+	return lines
+}
+
+// diffLines returns the hunks that transform a into b computed from their
+// longest common subsequence.
+func diffLines(a, b []string) []hunk {
+	// lcs[i][j] is the length of the longest common subsequence of a[i:]
+	// and b[j:].
+	lcs := make([][]int, len(a)+1)
+	for i := range lcs {
+		lcs[i] = make([]int, len(b)+1)
+	}
+	for i := len(a) - 1; i >= 0; i-- {
+		for j := len(b) - 1; j >= 0; j-- {
+			if a[i] == b[j] {
+				lcs[i][j] = lcs[i+1][j+1] + 1
+			} else if lcs[i+1][j] >= lcs[i][j+1] {
+				lcs[i][j] = lcs[i+1][j]
+			} else {
+				lcs[i][j] = lcs[i][j+1]
+			}
+		}
+	}
+
+	var (
+		hunks []hunk
+		cur   *hunk
+		i, j  int
+	)
+	flush := func() {
+		if cur != nil {
+			hunks = append(hunks, *cur)
+			cur = nil
+		}
+	}
+	for i < len(a) || j < len(b) {
+		switch {
+		case i < len(a) && j < len(b) && a[i] == b[j]:
+			flush()
+			i++
+			j++
+		case j < len(b) && (i == len(a) || lcs[i][j+1] >= lcs[i+1][j]):
+			if cur == nil {
+				cur = &hunk{start: i, end: i}
+			}
+			cur.lines = append(cur.lines, b[j])
+			j++
+		default:
+			if cur == nil {
+				cur = &hunk{start: i, end: i}
+			}
+			i++
+			cur.end = i
+		}
+	}
+	flush()
+	return hunks
+}
+
+// splitLines splits s into lines keeping the line terminators.
+func splitLines(s string) []string {
+	if s == "" {
+		return nil
+	}
+	lines := strings.SplitAfter(s, "\n")
+	if lines[len(lines)-1] == "" {
+		lines = lines[:len(lines)-1]
+	}
+	return lines
+}
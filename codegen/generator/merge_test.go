@@ -0,0 +1,37 @@
+package generator
+
+import "testing"
+
+func TestMerge3(t *testing.T) {
+	const base = "package main\n\nfunc main() {\n\tstart()\n}\n"
+	cases := []struct {
+		Name      string
+		Edited    string
+		Generated string
+		Expected  string
+		Conflicts bool
+	}{
+		{"unchanged", base, base, base, false},
+		{"edited only", "package main\n\nfunc main() {\n\tstart()\n\twait()\n}\n", base,
+			"package main\n\nfunc main() {\n\tstart()\n\twait()\n}\n", false},
+		{"generated only", base, "// Package main.\npackage main\n\nfunc main() {\n\tstart()\n}\n",
+			"// Package main.\npackage main\n\nfunc main() {\n\tstart()\n}\n", false},
+		{"both", "package main\n\nfunc main() {\n\tstart()\n\twait()\n}\n", "// Package main.\npackage main\n\nfunc main() {\n\tstart()\n}\n",
+			"// Package main.\npackage main\n\nfunc main() {\n\tstart()\n\twait()\n}\n", false},
+		{"same change", "package main\n\nfunc main() {\n\trun()\n}\n", "package main\n\nfunc main() {\n\trun()\n}\n",
+			"package main\n\nfunc main() {\n\trun()\n}\n", false},
+		{"conflict", "package main\n\nfunc main() {\n\trun()\n}\n", "package main\n\nfunc main() {\n\tserve()\n}\n",
+			"package main\n\nfunc main() {\n<<<<<<< edited\n\trun()\n=======\n\tserve()\n>>>>>>> generated\n}\n", true},
+	}
+	for _, c := range cases {
+		t.Run(c.Name, func(t *testing.T) {
+			merged, conflicts := merge3(base, c.Edited, c.Generated)
+			if merged != c.Expected {
+				t.Errorf("got:\n%s\nexpected:\n%s", merged, c.Expected)
+			}
+			if conflicts != c.Conflicts {
+				t.Errorf("got conflicts %v, expected %v", conflicts, c.Conflicts)
+			}
+		})
+	}
+}
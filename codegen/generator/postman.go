@@ -0,0 +1,20 @@
+package generator
+
+import (
+	"goa.design/goa/v3/codegen"
+	"goa.design/goa/v3/eval"
+	"goa.design/goa/v3/expr"
+	httpcodegen "goa.design/goa/v3/http/codegen"
+)
+
+// Postman iterates through the roots and returns the files needed to render a
+// Postman collection and environment covering the HTTP endpoints. It produces
+// files only if the roots define a HTTP service.
+func Postman(_ string, roots []eval.Root) ([]*codegen.File, error) {
+	for _, root := range roots {
+		if r, ok := root.(*expr.RootExpr); ok {
+			return httpcodegen.PostmanFiles(r)
+		}
+	}
+	return nil, nil
+}
@@ -0,0 +1,20 @@
+package generator
+
+import (
+	"goa.design/goa/v3/codegen"
+	"goa.design/goa/v3/eval"
+	"goa.design/goa/v3/expr"
+	httpcodegen "goa.design/goa/v3/http/codegen"
+)
+
+// Docs iterates through the roots and returns the files needed to render a
+// human readable reference of the HTTP endpoints in the format given by
+// DocsFormat. It produces files only if the roots define a HTTP service.
+func Docs(_ string, roots []eval.Root) ([]*codegen.File, error) {
+	for _, root := range roots {
+		if r, ok := root.(*expr.RootExpr); ok {
+			return httpcodegen.DocsFiles(r, DocsFormat)
+		}
+	}
+	return nil, nil
+}
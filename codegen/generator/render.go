@@ -0,0 +1,196 @@
+package generator
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"runtime"
+	"sync"
+
+	"goa.design/goa/v3/codegen"
+)
+
+// Workers is the maximum number of goroutines used to generate the files of
+// the services and to finalize (format) the generated files. It defaults to the
+// number of CPUs. The templates are executed sequentially as they share the
+// data computed from the design.
+var Workers = runtime.GOMAXPROCS(0)
+
+// prevSuffix is appended to the path of the existing files while they are
+// being regenerated.
+const prevSuffix = ".goa-prev"
+
+// renderFiles renders the files in dir and returns the absolute paths of the
+// rendered files as well as the paths of the scaffold files merged with
+// conflicts. The files whose content does not change are left untouched so
+// that their modification time is preserved. See renderScaffold for the
+// rendering of scaffold files.
+func renderFiles(dir string, files []*codegen.File) (map[string]struct{}, map[string]bool, error) {
+	base, err := filepath.Abs(dir)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	// Move the existing files aside: rendering appends to existing files.
+	prev := make(map[string]string)
+	restore := func() {
+		for path, p := range prev {
+			os.Rename(p, path) // nolint: errcheck
+		}
+	}
+	for _, f := range files {
+		if f.SkipExist {
+			continue
+		}
+		path := filepath.Join(base, f.Path)
+		if _, ok := prev[path]; ok {
+			continue
+		}
+		if _, err := os.Stat(path); err != nil {
+			continue
+		}
+		if err := os.Rename(path, path+prevSuffix); err != nil {
+			restore()
+			return nil, nil, err
+		}
+		prev[path] = path + prevSuffix
+	}
+
+	// Execute the templates sequentially as they share the data cached by
+	// the generators (e.g. service.Services), then format the files
+	// concurrently.
+	written := make(map[string]struct{})
+	conflicts := make(map[string]bool)
+	var (
+		paths []string
+		fins  = make(map[string][]*codegen.File)
+	)
+	for _, f := range files {
+		var (
+			filename string
+			conflict bool
+			err      error
+		)
+		if f.SkipExist {
+			filename, conflict, err = renderScaffold(dir, f)
+		} else {
+			filename, err = f.RenderSections(dir)
+			if filename != "" {
+				if _, ok := fins[filename]; !ok {
+					paths = append(paths, filename)
+				}
+				fins[filename] = append(fins[filename], f)
+			}
+		}
+		if err != nil {
+			restore()
+			return nil, nil, err
+		}
+		if filename != "" {
+			written[filename] = struct{}{}
+		}
+		if conflict {
+			conflicts[filename] = true
+		}
+	}
+	if err := finalizeFiles(paths, fins); err != nil {
+		restore()
+		return nil, nil, err
+	}
+
+	// Put back the files that did not change.
+	for path, p := range prev {
+		if sameContent(path, p) {
+			if err := os.Rename(p, path); err != nil {
+				return nil, nil, err
+			}
+			continue
+		}
+		if err := os.Remove(p); err != nil {
+			return nil, nil, err
+		}
+	}
+	return written, conflicts, nil
+}
+
+// finalizeFiles finalizes the files rendered at the given paths concurrently
+// using up to Workers goroutines. The files rendered at the same path are
+// finalized in order by the same goroutine. finalizeFiles returns the error
+// of the first path in paths that failed so that the error is deterministic.
+func finalizeFiles(paths []string, files map[string][]*codegen.File) error {
+	errs := make([]error, len(paths))
+	parallel(len(paths), func(i int) {
+		path := paths[i]
+		for j, f := range files[path] {
+			if j > 0 && filepath.Ext(path) == ".go" {
+				// The file was already formatted, only run the
+				// finalizer.
+				if f.FinalizeFunc != nil {
+					if err := f.FinalizeFunc(path); err != nil {
+						errs[i] = err
+						return
+					}
+				}
+				continue
+			}
+			if err := f.Finalize(path); err != nil {
+				errs[i] = err
+				return
+			}
+		}
+	})
+	return firstError(errs)
+}
+
+// parallel calls fn with the integers from 0 to n-1 using up to Workers
+// goroutines. The calls are started in order: a call may wait for the
+// completion of a step of the calls made with smaller integers.
+func parallel(n int, fn func(int)) {
+	workers := Workers
+	if workers < 1 {
+		workers = 1
+	}
+	var (
+		wg   sync.WaitGroup
+		jobs = make(chan int)
+	)
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range jobs {
+				fn(i)
+			}
+		}()
+	}
+	for i := 0; i < n; i++ {
+		jobs <- i
+	}
+	close(jobs)
+	wg.Wait()
+}
+
+// firstError returns the first non-nil error in errs so that the errors
+// returned by the concurrent steps are deterministic.
+func firstError(errs []error) error {
+	for _, err := range errs {
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// sameContent returns true if the files at paths a and b have the same
+// content.
+func sameContent(a, b string) bool {
+	ca, err := os.ReadFile(a)
+	if err != nil {
+		return false
+	}
+	cb, err := os.ReadFile(b)
+	if err != nil {
+		return false
+	}
+	return bytes.Equal(ca, cb)
+}
@@ -0,0 +1,88 @@
+package generator
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+
+	"goa.design/goa/v3/codegen"
+)
+
+// ScaffoldDir is the directory relative to the output directory where the
+// generator keeps a copy of the scaffold files as last generated. The copies
+// are the common ancestors used to merge the changes made by the user to the
+// scaffold files with the changes resulting from design updates. The
+// directory should be committed together with the scaffold files.
+var ScaffoldDir = filepath.Join(".goa", "scaffold")
+
+// renderScaffold renders a scaffold file, that is a file generated once and
+// then maintained by the user (codegen.File.SkipExist is true). The file is
+// rendered if it does not exist. Otherwise, if a copy of the file as last
+// generated exists, the user changes are merged with the changes made to the
+// generated content. The file is left untouched if there is no copy of the
+// last generated content. renderScaffold returns the path to the file if it
+// was written and whether the merge resulted in conflicts.
+func renderScaffold(dir string, f *codegen.File) (string, bool, error) {
+	base, err := filepath.Abs(dir)
+	if err != nil {
+		return "", false, err
+	}
+	path := filepath.Join(base, f.Path)
+	ancestor := filepath.Join(base, ScaffoldDir, f.Path)
+
+	edited, err := os.ReadFile(path)
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return "", false, err
+	}
+	exists := err == nil
+	prev, err := os.ReadFile(ancestor)
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return "", false, err
+	}
+	if exists && err != nil {
+		// The file was generated by a version of goa that did not keep
+		// the copy, there is no way to tell the user changes apart.
+		return "", false, nil
+	}
+
+	// Render the file in a temporary directory to compute the new
+	// generated content.
+	tmp, err := os.MkdirTemp("", "goa-scaffold")
+	if err != nil {
+		return "", false, err
+	}
+	defer os.RemoveAll(tmp)
+	rendered, err := f.Render(tmp)
+	if err != nil {
+		return "", false, err
+	}
+	generated, err := os.ReadFile(rendered)
+	if err != nil {
+		return "", false, err
+	}
+
+	content, conflicts := string(generated), false
+	if exists {
+		if string(generated) == string(prev) {
+			// Nothing changed in the design.
+			return "", false, nil
+		}
+		content, conflicts = merge3(string(prev), string(edited), string(generated))
+	}
+	if err := writeFile(path, []byte(content)); err != nil {
+		return "", false, err
+	}
+	if err := writeFile(ancestor, generated); err != nil {
+		return "", false, err
+	}
+	return path, conflicts, nil
+}
+
+// writeFile writes content to the file at path creating the parent
+// directories as needed.
+func writeFile(path string, content []byte) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0750); err != nil {
+		return err
+	}
+	return os.WriteFile(path, content, 0600)
+}
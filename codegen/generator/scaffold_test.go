@@ -0,0 +1,67 @@
+package generator
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"goa.design/goa/v3/codegen"
+)
+
+func TestRenderScaffold(t *testing.T) {
+	dir := t.TempDir()
+	scaffold := func(content string) *codegen.File {
+		return &codegen.File{
+			Path:             "main.txt",
+			SectionTemplates: []*codegen.SectionTemplate{{Name: "test", Source: content}},
+			SkipExist:        true,
+		}
+	}
+	path := filepath.Join(dir, "main.txt")
+	read := func() string {
+		t.Helper()
+		b, err := os.ReadFile(path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return string(b)
+	}
+
+	if p, _, err := renderScaffold(dir, scaffold("a\nb\nc\n")); err != nil || p != path {
+		t.Fatalf("got path %q and error %v, expected %q", p, err, path)
+	}
+	if err := os.WriteFile(path, []byte("a\nb\nc\nuser\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	// Unchanged design
+	if p, _, err := renderScaffold(dir, scaffold("a\nb\nc\n")); err != nil || p != "" {
+		t.Fatalf("got path %q and error %v, expected scaffold to be left untouched", p, err)
+	}
+
+	// Changed design
+	p, conflicts, err := renderScaffold(dir, scaffold("A\nb\nc\n"))
+	if err != nil || p != path || conflicts {
+		t.Fatalf("got path %q, conflicts %v and error %v", p, conflicts, err)
+	}
+	if got := read(); got != "A\nb\nc\nuser\n" {
+		t.Errorf("got merged content %q", got)
+	}
+
+	// Conflicting changes
+	if err := os.WriteFile(path, []byte("A\nmine\nc\nuser\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	if _, conflicts, err := renderScaffold(dir, scaffold("A\ntheirs\nc\n")); err != nil || !conflicts {
+		t.Fatalf("got conflicts %v and error %v, expected conflicts", conflicts, err)
+	}
+
+	// Scaffold generated without copy
+	legacy := &codegen.File{Path: "legacy.txt", SectionTemplates: scaffold("new\n").SectionTemplates, SkipExist: true}
+	if err := os.WriteFile(filepath.Join(dir, "legacy.txt"), []byte("old\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	if p, _, err := renderScaffold(dir, legacy); err != nil || p != "" {
+		t.Fatalf("got path %q and error %v, expected legacy scaffold to be left untouched", p, err)
+	}
+}
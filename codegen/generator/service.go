@@ -9,7 +9,9 @@ import (
 
 // Service iterates through the roots and returns the files needed to render
 // the service code. It returns an error if the roots slice does not include
-// a goa design.
+// a goa design. The files of the services are generated concurrently using up
+// to Workers goroutines and returned in the order of the services in the
+// design.
 func Service(genpkg string, roots []eval.Root) ([]*codegen.File, error) {
 	var files []*codegen.File
 	var userTypePkgs = make(map[string][]string)
@@ -18,26 +20,69 @@ func Service(genpkg string, roots []eval.Root) ([]*codegen.File, error) {
 		if !ok {
 			continue
 		}
-		for _, s := range r.Services {
+		var (
+			svcFiles  = make([][]*codegen.File, len(r.Services))
+			convFiles = make([]*codegen.File, len(r.Services))
+			errs      = make([]error, len(r.Services))
+			// turns[i] is closed once the services up to i-1
+			// generated their examples and user types.
+			turns = make([]chan struct{}, len(r.Services)+1)
+		)
+		for i := range turns {
+			turns[i] = make(chan struct{})
+		}
+		close(turns[0])
+		parallel(len(r.Services), func(i int) {
+			s := r.Services[i]
+			svc := service.Services.Analyze(s)
+
+			// The examples are generated with the random generator
+			// of the design and the user types defined in a custom
+			// package by the first service that uses them: process
+			// the services in order so that the output does not
+			// depend on the scheduling.
+			<-turns[i]
+			svc.InitExamples()
+			fs := service.Files(genpkg, s, userTypePkgs)
+			close(turns[i+1])
+
 			// Make sure service is first so name scope is
 			// properly initialized.
-			files = append(files, service.Files(genpkg, s, userTypePkgs)...)
-			files = append(files, service.EndpointFile(genpkg, s))
-			files = append(files, service.ClientFile(genpkg, s))
+			fs = append(fs, service.EndpointFile(genpkg, s))
+			fs = append(fs, service.ClientFile(genpkg, s))
 			if f := service.ViewsFile(genpkg, s); f != nil {
-				files = append(files, f)
+				fs = append(fs, f)
+			}
+			if f := service.EventsFile(genpkg, s); f != nil {
+				fs = append(fs, f)
+			}
+			if f := service.InterceptorsFile(genpkg, s); f != nil {
+				fs = append(fs, f)
+			}
+			if f := service.CodecFile(genpkg, s); f != nil {
+				fs = append(fs, f)
+			}
+			if f := service.RandomFile(genpkg, s); f != nil {
+				fs = append(fs, f)
+			}
+			if f := service.PatchFile(genpkg, s); f != nil {
+				fs = append(fs, f)
 			}
+			svcFiles[i] = fs
+			convFiles[i], errs[i] = service.ConvertFile(r, s)
+		})
+		for i, s := range r.Services {
+			files = append(files, svcFiles[i]...)
 			for _, f := range files {
 				if len(f.SectionTemplates) > 0 {
 					service.AddServiceDataMetaTypeImports(f.SectionTemplates[0], s)
 				}
 			}
-			f, err := service.ConvertFile(r, s)
-			if err != nil {
-				return nil, err
+			if errs[i] != nil {
+				return nil, errs[i]
 			}
-			if f != nil {
-				files = append(files, f)
+			if convFiles[i] != nil {
+				files = append(files, convFiles[i])
 			}
 		}
 	}
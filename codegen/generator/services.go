@@ -0,0 +1,96 @@
+package generator
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"goa.design/goa/v3/codegen"
+	"goa.design/goa/v3/codegen/service"
+	"goa.design/goa/v3/eval"
+	"goa.design/goa/v3/expr"
+)
+
+// selectServices removes the files that belong to services not listed in
+// Services from genfiles. Files that are not specific to a service (e.g. the
+// OpenAPI specifications or the CLI) are kept. selectServices also returns a
+// function that reports whether a generated file path belongs to a service
+// that is not selected so that these files are not considered stale. In the
+// absence of a manifest selectServices deletes the directories of the
+// selected services under dir so that stale files do not linger.
+func selectServices(dir string, roots []eval.Root, genfiles []*codegen.File) ([]*codegen.File, func(string) bool, error) {
+	var root *expr.RootExpr
+	for _, r := range roots {
+		if rt, ok := r.(*expr.RootExpr); ok {
+			root = rt
+			break
+		}
+	}
+	if root == nil {
+		return genfiles, nil, nil
+	}
+	selected := make(map[string]bool)
+	for _, name := range Services {
+		if root.Service(name) == nil {
+			return nil, nil, fmt.Errorf("unknown service %q", name)
+		}
+		selected[name] = true
+	}
+	owners := make(map[string]bool) // service path name -> selected
+	for _, svc := range root.Services {
+		owners[service.Services.Get(svc.Name).PathName] = selected[svc.Name]
+	}
+	unselected := func(path string) bool {
+		sel, ok := owners[serviceDir(path)]
+		return ok && !sel
+	}
+
+	var kept []*codegen.File
+	for _, f := range genfiles {
+		if unselected(f.Path) {
+			continue
+		}
+		kept = append(kept, f)
+	}
+	base, err := filepath.Abs(dir)
+	if err != nil {
+		return nil, nil, err
+	}
+	if m, err := readManifest(base); err != nil || m != nil {
+		return kept, unselected, err
+	}
+	for p, sel := range owners {
+		if !sel {
+			continue
+		}
+		for _, d := range []string{
+			filepath.Join(dir, codegen.Gendir, p),
+			filepath.Join(dir, codegen.Gendir, "http", p),
+			filepath.Join(dir, codegen.Gendir, "grpc", p),
+		} {
+			if err := os.RemoveAll(d); err != nil {
+				return nil, nil, err
+			}
+		}
+	}
+	return kept, unselected, nil
+}
+
+// serviceDir returns the name of the service directory containing the
+// generated file with the given path, that is "x" for paths of the form
+// "gen/x/...", "gen/http/x/..." and "gen/grpc/x/...". It returns the empty
+// string if the path is not in a service directory.
+func serviceDir(path string) string {
+	parts := strings.Split(filepath.ToSlash(path), "/")
+	if len(parts) < 3 || parts[0] != codegen.Gendir {
+		return ""
+	}
+	if parts[1] == "http" || parts[1] == "grpc" {
+		if len(parts) < 4 {
+			return ""
+		}
+		return parts[2]
+	}
+	return parts[1]
+}
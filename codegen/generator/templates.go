@@ -0,0 +1,69 @@
+package generator
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"text/template"
+
+	"goa.design/goa/v3/codegen"
+)
+
+// TemplatesDir is the path to a directory containing template overrides when
+// not empty. Each file with the ".tmpl" extension in the directory replaces
+// the source of the section templates whose name is the file name without the
+// extension, for example "server-handler-init.tmpl" overrides the template of
+// the "server-handler-init" sections. The override is given the same data and
+// functions as the template it replaces.
+var TemplatesDir string
+
+// templateExt is the extension of template override files.
+const templateExt = ".tmpl"
+
+// overrideTemplates replaces the source of the sections of files with the
+// templates in TemplatesDir.
+func overrideTemplates(files []*codegen.File) error {
+	overrides, err := readTemplates(TemplatesDir)
+	if err != nil {
+		return err
+	}
+	for _, f := range files {
+		for _, s := range f.SectionTemplates {
+			src, ok := overrides[s.Name]
+			if !ok {
+				continue
+			}
+			funcs := codegen.TemplateFuncs()
+			for k, v := range s.FuncMap {
+				funcs[k] = v
+			}
+			if _, err := template.New(s.Name).Funcs(funcs).Parse(src); err != nil {
+				return fmt.Errorf("invalid template override for section %q: %w", s.Name, err)
+			}
+			s.Source = src
+		}
+	}
+	return nil
+}
+
+// readTemplates returns the content of the template override files in dir
+// indexed by section name.
+func readTemplates(dir string) (map[string]string, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read template overrides: %w", err)
+	}
+	overrides := make(map[string]string)
+	for _, e := range entries {
+		if e.IsDir() || filepath.Ext(e.Name()) != templateExt {
+			continue
+		}
+		b, err := os.ReadFile(filepath.Join(dir, e.Name()))
+		if err != nil {
+			return nil, fmt.Errorf("failed to read template overrides: %w", err)
+		}
+		overrides[strings.TrimSuffix(e.Name(), templateExt)] = string(b)
+	}
+	return overrides, nil
+}
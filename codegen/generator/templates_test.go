@@ -0,0 +1,52 @@
+package generator
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"goa.design/goa/v3/codegen"
+)
+
+func TestOverrideTemplates(t *testing.T) {
+	dir := t.TempDir()
+	write := func(name, content string) {
+		t.Helper()
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0600); err != nil {
+			t.Fatal(err)
+		}
+	}
+	defer func() { TemplatesDir = "" }()
+	TemplatesDir = dir
+
+	write("greeting.tmpl", `{{ greet .Name }} from override`)
+	write("notes.txt", `ignored`)
+	greeting := &codegen.SectionTemplate{
+		Name:    "greeting",
+		Source:  `hello {{ .Name }}`,
+		Data:    map[string]string{"Name": "goa"},
+		FuncMap: map[string]any{"greet": func(n string) string { return "hi " + n }},
+	}
+	other := &codegen.SectionTemplate{Name: "other", Source: `other`}
+	files := []*codegen.File{{Path: "f.go", SectionTemplates: []*codegen.SectionTemplate{greeting, other}}}
+
+	if err := overrideTemplates(files); err != nil {
+		t.Fatal(err)
+	}
+	var buf bytes.Buffer
+	if err := greeting.Write(&buf); err != nil {
+		t.Fatal(err)
+	}
+	if got := buf.String(); got != "hi goa from override" {
+		t.Errorf("got %q, expected %q", got, "hi goa from override")
+	}
+	if other.Source != "other" {
+		t.Errorf("section without override was modified: %q", other.Source)
+	}
+
+	write("other.tmpl", `{{ .Unclosed `)
+	if err := overrideTemplates(files); err == nil {
+		t.Error("expected an error for an invalid template override")
+	}
+}
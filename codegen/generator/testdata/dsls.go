@@ -0,0 +1,62 @@
+package testdata
+
+import (
+	"fmt"
+
+	. "goa.design/goa/v3/dsl"
+)
+
+// LargeDSL returns a design with the given number of services, each service
+// defines methods exposed via HTTP. The services share user types defined in
+// a custom package.
+func LargeDSL(services int) func() {
+	return func() {
+		var Tag = Type("Tag", func() {
+			Meta("struct:pkg:path", "types")
+			Attribute("name", String)
+			Required("name")
+		})
+		var Item = Type("Item", func() {
+			Attribute("id", Int)
+			Attribute("name", String)
+			Attribute("tags", ArrayOf(Tag))
+			Required("id", "name")
+		})
+		var Summary = ResultType("application/vnd.summary", func() {
+			Attribute("id", Int)
+			Attribute("tag", Tag)
+			View("default", func() {
+				Attribute("id")
+				Attribute("tag")
+			})
+			View("tiny", func() {
+				Attribute("id")
+			})
+		})
+		for i := 0; i < services; i++ {
+			Service(fmt.Sprintf("service%d", i), func() {
+				for _, m := range []string{"list", "show", "create", "update", "delete"} {
+					Method(m, func() {
+						Payload(func() {
+							Attribute("id", Int)
+							Attribute("item", Item)
+							Required("id")
+						})
+						Result(Item)
+						HTTP(func() {
+							POST("/" + m + "/{id}")
+						})
+					})
+				}
+				Method("summary", func() {
+					Payload(Tag)
+					Result(Summary)
+					HTTP(func() {
+						GET("/summary")
+						Param("name")
+					})
+				})
+			})
+		}
+	}
+}
@@ -26,6 +26,7 @@ func Transport(genpkg string, roots []eval.Root) ([]*codegen.File, error) {
 		files = append(files, httpcodegen.ServerTypeFiles(genpkg, r)...)
 		files = append(files, httpcodegen.ClientTypeFiles(genpkg, r)...)
 		files = append(files, httpcodegen.PathFiles(r)...)
+		files = append(files, httpcodegen.ServerJSONTestFiles(r)...)
 		files = append(files, httpcodegen.ClientCLIFiles(genpkg, r)...)
 
 		// GRPC
@@ -35,6 +36,7 @@ func Transport(genpkg string, roots []eval.Root) ([]*codegen.File, error) {
 		files = append(files, grpccodegen.ServerTypeFiles(genpkg, r)...)
 		files = append(files, grpccodegen.ClientTypeFiles(genpkg, r)...)
 		files = append(files, grpccodegen.ClientCLIFiles(genpkg, r)...)
+		files = append(files, grpccodegen.TranscoderFiles(genpkg, r)...)
 
 		for _, f := range files {
 			if len(f.SectionTemplates) > 0 {
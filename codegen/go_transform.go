@@ -13,7 +13,7 @@ var transformGoArrayT, transformGoMapT, transformGoUnionT, transformGoUnionToObj
 
 // NOTE: can't initialize inline because https://github.com/golang/go/issues/1817
 func init() {
-	fm := template.FuncMap{"transformAttribute": transformAttribute, "transformHelperName": transformHelperName}
+	fm := template.FuncMap{"transformAttribute": transformAttribute, "transformHelperCall": transformHelperCall}
 	transformGoArrayT = template.Must(template.New("transformGoArray").Funcs(fm).Parse(transformGoArrayTmpl))
 	transformGoMapT = template.Must(template.New("transformGoMap").Funcs(fm).Parse(transformGoMapTmpl))
 	transformGoUnionT = template.Must(template.New("transformGoUnion").Funcs(fm).Parse(transformGoUnionTmpl))
@@ -228,7 +228,7 @@ func transformObject(source, target *expr.AttributeExpr, sourceVar, targetVar st
 					tgtVar = targetVar + ".(" + ref + ")." + GoifyAtt(tgtc, tgtMatt.ElemName(n), true)
 				}
 				if !expr.IsPrimitive(srcc.Type) {
-					code = fmt.Sprintf("%s = %s(%s)\n", tgtVar, transformHelperName(srcc, tgtc, ta), srcVar)
+					code = fmt.Sprintf("%s = %s\n", tgtVar, transformHelperCall(srcc, tgtc, srcVar, ta))
 				}
 			case expr.IsObject(srcc.Type):
 				code, err = transformAttribute(srcc, tgtc, srcVar, tgtVar, false, ta)
@@ -318,7 +318,7 @@ func transformArray(source, target *expr.Array, sourceVar, targetVar string, new
 		return "", err
 	}
 	data := map[string]any{
-		"ElemTypeRef":    ta.TargetCtx.Scope.Ref(target.ElemType, ta.TargetCtx.Pkg(target.ElemType)),
+		"ElemTypeRef":    elemTypeRef(target.ElemType, ta),
 		"SourceElem":     source.ElemType,
 		"TargetElem":     target.ElemType,
 		"SourceVar":      sourceVar,
@@ -327,6 +327,7 @@ func transformArray(source, target *expr.Array, sourceVar, targetVar string, new
 		"TransformAttrs": ta,
 		"LoopVar":        string(rune(105 + strings.Count(targetVar, "["))),
 		"IsStruct":       expr.IsObject(target.ElemType.Type),
+		"CheckNil":       isStructValue(target.ElemType) && !isStructValue(source.ElemType),
 	}
 	var buf bytes.Buffer
 	if err := transformGoArrayT.Execute(&buf, data); err != nil {
@@ -345,7 +346,7 @@ func transformMap(source, target *expr.Map, sourceVar, targetVar string, newVar
 	}
 	data := map[string]any{
 		"KeyTypeRef":     ta.TargetCtx.Scope.Ref(target.KeyType, ta.TargetCtx.Pkg(target.KeyType)),
-		"ElemTypeRef":    ta.TargetCtx.Scope.Ref(target.ElemType, ta.TargetCtx.Pkg(target.ElemType)),
+		"ElemTypeRef":    elemTypeRef(target.ElemType, ta),
 		"SourceKey":      source.KeyType,
 		"TargetKey":      target.KeyType,
 		"SourceElem":     source.ElemType,
@@ -357,6 +358,7 @@ func transformMap(source, target *expr.Map, sourceVar, targetVar string, newVar
 		"LoopVar":        "",
 		"IsKeyStruct":    expr.IsObject(target.KeyType.Type),
 		"IsElemStruct":   expr.IsObject(target.ElemType.Type),
+		"CheckNil":       isStructValue(target.ElemType) && !isStructValue(source.ElemType),
 	}
 	if depth := MapDepth(target); depth > 0 {
 		data["LoopVar"] = string(rune(97 + depth))
@@ -644,6 +646,40 @@ func walkMatches(source, target *expr.AttributeExpr, walker func(src, tgt *expr.
 	}
 }
 
+// transformHelperCall returns the code that calls the transformation function
+// that initializes a target user type from an instance of a source user type.
+// The code takes the address of the source value and dereferences the result
+// if the corresponding Go struct is held by value, see isStructValue.
+func transformHelperCall(source, target *expr.AttributeExpr, sourceVar string, ta *TransformAttrs) string {
+	if isStructValue(source) {
+		sourceVar = "&" + sourceVar
+	}
+	call := fmt.Sprintf("%s(%s)", transformHelperName(source, target, ta), sourceVar)
+	if isStructValue(target) {
+		return "*" + call
+	}
+	return call
+}
+
+// elemTypeRef returns the reference to the Go type of the target array or map
+// element att.
+func elemTypeRef(att *expr.AttributeExpr, ta *TransformAttrs) string {
+	ref := ta.TargetCtx.Scope.Ref(att, ta.TargetCtx.Pkg(att))
+	if isStructValue(att) {
+		return strings.TrimPrefix(ref, "*")
+	}
+	return ref
+}
+
+// isStructValue returns true if the Go type of att is a struct held by value
+// instead of by pointer. This is the case for the struct fields, slice and map
+// elements of the external types used with ConvertTo and CreateFrom that are
+// not pointers. Such attributes have the "struct:field:value" meta.
+func isStructValue(att *expr.AttributeExpr) bool {
+	_, ok := att.Meta["struct:field:value"]
+	return ok
+}
+
 // transformHelperName returns the transformation function name to initialize a
 // target user type from an instance of a source user type.
 func transformHelperName(source, target *expr.AttributeExpr, ta *TransformAttrs) string {
@@ -667,7 +703,11 @@ const (
 	transformGoArrayTmpl = `{{ .TargetVar }} {{ if .NewVar }}:={{ else }}={{ end }} make([]{{ .ElemTypeRef }}, len({{ .SourceVar }}))
 for {{ .LoopVar }}, val := range {{ .SourceVar }} {
 {{ if .IsStruct -}}
-	{{ .TargetVar }}[{{ .LoopVar }}] = {{ transformHelperName .SourceElem .TargetElem .TransformAttrs }}(val)
+{{ if .CheckNil }}	if val != nil {
+{{ end -}}
+	{{ .TargetVar }}[{{ .LoopVar }}] = {{ transformHelperCall .SourceElem .TargetElem "val" .TransformAttrs }}
+{{ if .CheckNil }}	}
+{{ end -}}
 {{ else -}}
 	{{ transformAttribute .SourceElem .TargetElem "val" (printf "%s[%s]" .TargetVar .LoopVar) false .TransformAttrs -}}
 {{ end -}}
@@ -677,12 +717,16 @@ for {{ .LoopVar }}, val := range {{ .SourceVar }} {
 	transformGoMapTmpl = `{{ .TargetVar }} {{ if .NewVar }}:={{ else }}={{ end }} make(map[{{ .KeyTypeRef }}]{{ .ElemTypeRef }}, len({{ .SourceVar }}))
 for key, val := range {{ .SourceVar }} {
 {{ if .IsKeyStruct -}}
-	tk := {{ transformHelperName .SourceKey .TargetKey .TransformAttrs -}}(val)
+	tk := {{ transformHelperCall .SourceKey .TargetKey "val" .TransformAttrs }}
 {{ else -}}
   {{ transformAttribute .SourceKey .TargetKey "key" "tk" true .TransformAttrs -}}
 {{ end -}}
 {{ if .IsElemStruct -}}
-	{{ .TargetVar }}[tk] = {{ transformHelperName .SourceElem .TargetElem .TransformAttrs -}}(val)
+{{ if .CheckNil }}	if val != nil {
+{{ end -}}
+	{{ .TargetVar }}[tk] = {{ transformHelperCall .SourceElem .TargetElem "val" .TransformAttrs }}
+{{ if .CheckNil }}	}
+{{ end -}}
 {{ else -}}
 	{{ transformAttribute .SourceElem .TargetElem "val" (printf "tv%s" .LoopVar) true .TransformAttrs -}}
 	{{ .TargetVar }}[tk] = {{ printf "tv%s" .LoopVar -}}
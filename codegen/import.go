@@ -102,12 +102,26 @@ func (loc *Location) PackageName() string {
 // GetMetaType retrieves the type and package defined by the struct:field:type
 // metadata if any.
 func GetMetaType(att *expr.AttributeExpr) (typeName string, importS *ImportSpec) {
+	return metaRef(att, "struct:field:type")
+}
+
+// GetMetaFunc retrieves the function and package defined by the
+// struct:field:decode, struct:field:encode or struct:field:validate metadata
+// identified by key if any. The functions convert and validate the values of
+// attributes whose type is overridden with struct:field:type.
+func GetMetaFunc(att *expr.AttributeExpr, key string) (fn string, importS *ImportSpec) {
+	return metaRef(att, key)
+}
+
+// metaRef retrieves the Go reference and package defined by the metadata key
+// if any.
+func metaRef(att *expr.AttributeExpr, key string) (ref string, importS *ImportSpec) {
 	if att == nil {
 		return
 	}
-	if args, ok := att.Meta["struct:field:type"]; ok {
+	if args, ok := att.Meta[key]; ok {
 		if len(args) > 0 {
-			typeName = args[0]
+			ref = args[0]
 		}
 		if len(args) > 1 {
 			importS = &ImportSpec{Path: args[1]}
@@ -176,6 +190,11 @@ func safelyGetMetaTypeImports(att *expr.AttributeExpr, seen map[string]struct{})
 	if im != nil {
 		uniqueImports[*im] = struct{}{}
 	}
+	for _, key := range []string{"struct:field:decode", "struct:field:encode", "struct:field:validate"} {
+		if _, im := GetMetaFunc(att, key); im != nil {
+			uniqueImports[*im] = struct{}{}
+		}
+	}
 	for imp := range uniqueImports {
 		// Copy loop variable into body so next iteration doesn't overwrite its address https://stackoverflow.com/questions/27610039/golang-appending-leaves-only-last-element
 		cp := imp
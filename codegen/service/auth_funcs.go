@@ -22,3 +22,29 @@ func (s *{{ $.VarName }}srvc) {{ .Type }}Auth(ctx context.Context, {{ if eq .Typ
 }
 {{- end }}
 `
+
+// data: Data
+const dummyAuthorizerT = `
+{{ printf "Authorize implements the authorization logic for service %q, it is invoked with the policy of the endpoint after the request has been authenticated." .Name | comment }}
+func (s *{{ .VarName }}srvc) Authorize(ctx context.Context, policy *security.Policy, payload any) error {
+	//
+	// TBD: add authorization logic.
+	//
+	// The authentication functions should store the authenticated principal
+	// in the context so that it can be checked against the policy roles and
+	// permissions, e.g.:
+	//
+	//    ctx = security.WithPrincipal(ctx, &security.Principal{Subject: sub, Roles: roles})
+	//
+	// Rules defined in the design must be evaluated here.
+	//
+	principal, _ := security.PrincipalFromContext(ctx)
+	if err := policy.Check(principal); err != nil {
+		return err
+	}
+	if policy.Rule != "" {
+		return fmt.Errorf("policy rule not implemented: %s", policy.Rule)
+	}
+	return nil
+}
+`
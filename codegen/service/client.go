@@ -45,6 +45,13 @@ func ClientFile(_ string, service *expr.ServiceExpr) *codegen.File {
 				Source: serviceClientMethodT,
 				Data:   m,
 			})
+			if m.Pagination != nil {
+				sections = append(sections, &codegen.SectionTemplate{
+					Name:   "client-iterator",
+					Source: serviceClientIteratorT,
+					Data:   m,
+				})
+			}
 		}
 	}
 
@@ -105,3 +112,44 @@ func (c *{{ .ClientVarName }}) {{ .VarName }}(ctx context.Context, {{ if .Payloa
 	{{- end }}
 }
 `
+
+// input: EndpointMethodData
+const serviceClientIteratorT = `
+{{ printf "%s iterates over the items returned by the %q endpoint of the %q service." .Pagination.IteratorName .Name .ServiceName | comment }}
+type {{ .Pagination.IteratorName }} = goa.Iterator[{{ .Pagination.ItemRef }}, {{ .Pagination.CursorRef }}]
+
+{{ printf "%sIter returns an iterator over the items returned by the %q endpoint of the %q service. The iterator retrieves the pages as needed starting with the page identified by p and stops after maxItems items if maxItems is greater than zero." .VarName .Name .ServiceName | comment }}
+func (c *{{ .ClientVarName }}) {{ .VarName }}Iter(ctx context.Context, p {{ .PayloadRef }}, maxItems int) *{{ .Pagination.IteratorName }} {
+	var start {{ .Pagination.CursorRef }}
+	{{- if .Pagination.CursorPointer }}
+	if p.{{ .Pagination.CursorField }} != nil {
+		start = *p.{{ .Pagination.CursorField }}
+	}
+	{{- else }}
+	start = p.{{ .Pagination.CursorField }}
+	{{- end }}
+	return goa.NewIterator(ctx, start, func(ctx context.Context, cursor {{ .Pagination.CursorRef }}) (items []{{ .Pagination.ItemRef }}, next {{ .Pagination.CursorRef }}, err error) {
+		pp := *p
+		if cursor != start {
+			pp.{{ .Pagination.CursorField }} = {{ if .Pagination.CursorPointer }}&{{ end }}cursor
+		}
+		res, err := c.{{ .VarName }}(ctx, &pp)
+		if err != nil {
+			return
+		}
+		{{- if .Pagination.NextPointer }}
+		if res.{{ .Pagination.NextField }} != nil {
+			next = *res.{{ .Pagination.NextField }}
+		}
+		{{- else }}
+		next = res.{{ .Pagination.NextField }}
+		{{- end }}
+		return res.{{ .Pagination.ItemsField }}, next, nil
+	}, maxItems)
+}
+
+{{ printf "%sCollect returns all the items returned by the %q endpoint of the %q service retrieving the pages starting with the page identified by p. It returns the items retrieved so far and goa.ErrMaxItems if there are more than maxItems items and maxItems is greater than zero." .VarName .Name .ServiceName | comment }}
+func (c *{{ .ClientVarName }}) {{ .VarName }}Collect(ctx context.Context, p {{ .PayloadRef }}, maxItems int) ([]{{ .Pagination.ItemRef }}, error) {
+	return goa.Collect(c.{{ .VarName }}Iter(ctx, p, maxItems))
+}
+`
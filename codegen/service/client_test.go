@@ -22,6 +22,7 @@ func TestClient(t *testing.T) {
 		{"client-multiple", testdata.MultipleEndpointsDSL, testdata.MultipleMethodsClient},
 		{"client-no-payload", testdata.NoPayloadEndpointDSL, testdata.NoPayloadMethodsClient},
 		{"client-with-result", testdata.WithResultEndpointDSL, testdata.WithResultMethodClient},
+		{"client-paginated", testdata.PaginatedEndpointDSL, testdata.PaginatedMethodClient},
 		{"client-streaming-result", testdata.StreamingResultMethodDSL, testdata.StreamingResultMethodClient},
 		{"client-streaming-result-no-payload", testdata.StreamingResultNoPayloadMethodDSL, testdata.StreamingResultNoPayloadMethodClient},
 		{"client-streaming-payload", testdata.StreamingPayloadMethodDSL, testdata.StreamingPayloadMethodClient},
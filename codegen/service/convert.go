@@ -417,7 +417,7 @@ func buildDesignType(dt *expr.DataType, t reflect.Type, ref expr.DataType, recs
 		if err := buildDesignType(&elem, e, eref, appendPath(rec, "[0]")); err != nil {
 			return fmt.Errorf("%s", err)
 		}
-		*dt = &expr.Array{ElemType: &expr.AttributeExpr{Type: elem}}
+		*dt = &expr.Array{ElemType: elemAttribute(elem, e)}
 
 	case reflect.Map:
 		var kref, vref expr.DataType
@@ -434,7 +434,7 @@ func buildDesignType(dt *expr.DataType, t reflect.Type, ref expr.DataType, recs
 		if err := buildDesignType(&vt, t.Elem(), vref, appendPath(rec, ".value")); err != nil {
 			return fmt.Errorf("%s", err)
 		}
-		*dt = &expr.Map{KeyType: &expr.AttributeExpr{Type: kt}, ElemType: &expr.AttributeExpr{Type: vt}}
+		*dt = &expr.Map{KeyType: elemAttribute(kt, t.Key()), ElemType: elemAttribute(vt, t.Elem())}
 
 	case reflect.Struct:
 		var oref *expr.Object
@@ -483,7 +483,10 @@ func buildDesignType(dt *expr.DataType, t reflect.Type, ref expr.DataType, recs
 					aref = at.Type
 				}
 			}
-			var fdt expr.DataType
+			var (
+				fdt  expr.DataType
+				meta expr.MetaExpr
+			)
 			if f.Type.Kind() == reflect.Ptr {
 				if err := buildDesignType(&fdt, f.Type.Elem(), aref, recf); err != nil {
 					return fmt.Errorf("%q.%s: %s", t.Name(), f.Name, err)
@@ -495,7 +498,12 @@ func buildDesignType(dt *expr.DataType, t reflect.Type, ref expr.DataType, recs
 					return fmt.Errorf("%s: field of type pointer to map are not supported, use map instead", rec.path)
 				}
 			} else if f.Type.Kind() == reflect.Struct {
-				return fmt.Errorf("%s: fields of type struct must use pointers", recf.path)
+				// struct held by value, it can't be nil
+				if err := buildDesignType(&fdt, f.Type, aref, recf); err != nil {
+					return fmt.Errorf("%q.%s: %s", t.Name(), f.Name, err)
+				}
+				meta = structValueMeta()
+				required = append(required, atn)
 			} else {
 				if isPrimitive(f.Type) {
 					required = append(required, atn)
@@ -510,7 +518,7 @@ func buildDesignType(dt *expr.DataType, t reflect.Type, ref expr.DataType, recs
 			}
 			obj[i] = &expr.NamedAttributeExpr{
 				Name:      name,
-				Attribute: &expr.AttributeExpr{Type: fdt},
+				Attribute: &expr.AttributeExpr{Type: fdt, Meta: meta},
 			}
 		}
 		if len(required) > 0 {
@@ -532,6 +540,23 @@ func buildDesignType(dt *expr.DataType, t reflect.Type, ref expr.DataType, recs
 	return nil
 }
 
+// elemAttribute returns the attribute of the slice or map element or map key of
+// Go type t and data type dt.
+func elemAttribute(dt expr.DataType, t reflect.Type) *expr.AttributeExpr {
+	att := &expr.AttributeExpr{Type: dt}
+	if t.Kind() == reflect.Struct {
+		att.Meta = structValueMeta()
+	}
+	return att
+}
+
+// structValueMeta returns the meta that flags the attributes whose Go type is
+// a struct held by value so that the generated transform code takes the
+// address of or dereferences the values as needed.
+func structValueMeta() expr.MetaExpr {
+	return expr.MetaExpr{"struct:field:value": nil}
+}
+
 // attributeName computes the name of the attribute for the given field name and
 // object that must contain the matching attribute.
 func attributeName(obj *expr.Object, name string) (string, string) {
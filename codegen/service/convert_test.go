@@ -111,12 +111,12 @@ func TestDesignType(t *testing.T) {
 		{"map", map[string]string{}, dsl.MapOf(expr.String, expr.String), ""},
 		{"object", objT{}, obj, ""},
 		{"array-object", []objT{{}}, dsl.ArrayOf(obj), ""},
+		{"object-value-field", hasNonPtrFields{inner: inner{foo: "foo"}}, objValueField, ""},
 
 		{"invalid-bool", &f, nil, "*(<value>): only pointer to struct can be converted"},
 		{"invalid-array", []*bool{&f}, nil, "*(<value>[0]): only pointer to struct can be converted"},
 		{"invalid-map-key", map[*bool]string{&f: ""}, nil, "*(<value>.key): only pointer to struct can be converted"},
 		{"invalid-map-val", map[string]*bool{"": &f}, nil, "*(<value>.value): only pointer to struct can be converted"},
-		{"invalid-struct", hasNonPtrFields{inner: inner{foo: "foo"}}, nil, "<value>.inner: fields of type struct must use pointers"},
 	}
 	for _, c := range cases {
 		t.Run(c.Name, func(t *testing.T) {
@@ -243,6 +243,10 @@ func TestConvertFile(t *testing.T) {
 		{"create-external-convert", testdata.CreateExternalDSL, 0, testdata.CreateExternalConvert},
 		{"create-alias-convert", testdata.CreateAliasDSL, 0, testdata.CreateAliasConvert},
 		{"mixed-case-convert", testdata.MixedCaseDSL, 0, testdata.MixedCaseConvert},
+		{"convert-value", testdata.ConvertValueDSL, 1, testdata.ConvertValueCode},
+		{"convert-value-helper", testdata.ConvertValueDSL, 2, testdata.ConvertValueHelperCode},
+		{"create-value", testdata.CreateValueDSL, 1, testdata.CreateValueCode},
+		{"create-value-helper", testdata.CreateValueDSL, 2, testdata.CreateValueHelperCode},
 	}
 	for _, c := range cases {
 		t.Run(c.Name, func(t *testing.T) {
@@ -318,6 +322,22 @@ var obj = &expr.UserTypeExpr{
 	TypeName: "objT",
 }
 
+var objValueField = &expr.UserTypeExpr{
+	AttributeExpr: &expr.AttributeExpr{
+		Type: &expr.Object{
+			{Name: "inner", Attribute: &expr.AttributeExpr{Type: &expr.UserTypeExpr{
+				AttributeExpr: &expr.AttributeExpr{
+					Type: &expr.Object{
+						{Name: "foo", Attribute: &expr.AttributeExpr{Type: expr.String}},
+					},
+				},
+				TypeName: "inner",
+			}}},
+		},
+	},
+	TypeName: "hasNonPtrFields",
+}
+
 var objMapped = &expr.UserTypeExpr{
 	AttributeExpr: &expr.AttributeExpr{
 		Type: &expr.Object{
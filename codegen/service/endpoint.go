@@ -30,6 +30,9 @@ type (
 		// Schemes contains the security schemes types used by the
 		// all the endpoints.
 		Schemes SchemesData
+		// HasPolicies is true if any of the endpoints defines an
+		// authorization policy.
+		HasPolicies bool
 	}
 
 	// EndpointMethodData describes a single endpoint method.
@@ -152,6 +155,7 @@ func endpointData(service *expr.ServiceExpr) *EndpointsData {
 		ClientInitArgs: strings.Join(names, ", "),
 		Methods:        methods,
 		Schemes:        svc.Schemes,
+		HasPolicies:    svc.HasPolicies,
 	}
 }
 
@@ -177,10 +181,14 @@ func New{{ .VarName }}(s {{ .ServiceVarName }}) *{{ .VarName }} {
 {{- if .Schemes }}
 	// Casting service to Auther interface
 	a := s.(Auther)
+{{- end }}
+{{- if .HasPolicies }}
+	// Casting service to Authorizer interface
+	authz := s.(Authorizer)
 {{- end }}
 	return &{{ .VarName }}{
 {{- range .Methods }}
-		{{ .VarName }}: New{{ .VarName }}Endpoint(s{{ range .Schemes }}, a.{{ .Type }}Auth{{ end }}),
+		{{ .VarName }}: New{{ .VarName }}Endpoint(s{{ range .Schemes }}, a.{{ .Type }}Auth{{ end }}{{ if .Policy }}, authz.Authorize{{ end }}),
 {{- end }}
 	}
 }
@@ -224,7 +232,7 @@ type {{ .ResponseStruct }} struct {
 
 // input: endpointMethodData
 const serviceEndpointMethodT = `{{ printf "New%sEndpoint returns an endpoint function that calls the method %q of service %q." .VarName .Name .ServiceName | comment }}
-func New{{ .VarName }}Endpoint(s {{ .ServiceVarName }}{{ range .Schemes }}, auth{{ .Type }}Fn security.Auth{{ .Type }}Func{{ end }}) goa.Endpoint {
+func New{{ .VarName }}Endpoint(s {{ .ServiceVarName }}{{ range .Schemes }}, auth{{ .Type }}Fn security.Auth{{ .Type }}Func{{ end }}{{ if .Policy }}, authorizeFn security.AuthorizeFunc{{ end }}) goa.Endpoint {
 	return func(ctx context.Context, req any) (any, error) {
 {{- if or .ServerStream }}
 		ep := req.(*{{ .ServerStream.EndpointStruct }})
@@ -337,6 +345,29 @@ func New{{ .VarName }}Endpoint(s {{ .ServiceVarName }}{{ range .Schemes }}, auth
 		if err != nil {
 			return nil, err
 		}
+	{{- if and .PayloadRef (not .ServerStream) (not .SkipRequestBodyEncodeDecode) }}
+		if err := goa.DecodeBody(ctx); err != nil {
+			return nil, err
+		}
+	{{- end }}
+{{- end }}
+{{- if .Policy }}
+		policy := security.Policy{
+			Service: {{ printf "%q" .ServiceName }},
+			Method:  {{ printf "%q" .Name }},
+		{{- if .Policy.Roles }}
+			Roles: []string{ {{- range .Policy.Roles }}{{ printf "%q" . }}, {{ end }} },
+		{{- end }}
+		{{- if .Policy.Permissions }}
+			Permissions: []string{ {{- range .Policy.Permissions }}{{ printf "%q" . }}, {{ end }} },
+		{{- end }}
+		{{- if .Policy.Rule }}
+			Rule: {{ printf "%q" .Policy.Rule }},
+		{{- end }}
+		}
+		if err := authorizeFn(ctx, &policy, {{ if .PayloadRef }}{{ $payload }}{{ else }}nil{{ end }}); err != nil {
+			return nil, err
+		}
 {{- end }}
 {{- if .ServerStream }}
 	return nil, s.{{ .VarName }}(ctx, {{ if .PayloadRef }}{{ $payload }}, {{ end }}ep.Stream)
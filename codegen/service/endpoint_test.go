@@ -31,6 +31,7 @@ func TestEndpoint(t *testing.T) {
 		{"endpoint-streaming-payload-no-result", testdata.StreamingPayloadNoResultMethodDSL, testdata.StreamingPayloadNoResultMethodEndpoint},
 		{"endpoint-bidirectional-streaming", testdata.BidirectionalStreamingEndpointDSL, testdata.BidirectionalStreamingMethodEndpoint},
 		{"endpoint-bidirectional-streaming-no-payload", testdata.BidirectionalStreamingNoPayloadMethodDSL, testdata.BidirectionalStreamingNoPayloadMethodEndpoint},
+		{"endpoint-with-policy", testdata.PolicyEndpointDSL, testdata.PolicyEndpoint},
 	}
 	for _, c := range cases {
 		t.Run(c.Name, func(t *testing.T) {
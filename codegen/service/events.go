@@ -0,0 +1,93 @@
+package service
+
+import (
+	"path/filepath"
+
+	"goa.design/goa/v3/codegen"
+	"goa.design/goa/v3/expr"
+)
+
+// EventsFile returns the file defining the publisher of the events of the
+// given service, nil if the service does not define any event.
+func EventsFile(_ string, service *expr.ServiceExpr) *codegen.File {
+	svc := Services.Get(service.Name)
+	if len(svc.Events) == 0 {
+		return nil
+	}
+	path := filepath.Join(codegen.Gendir, svc.PathName, "events.go")
+	imports := []*codegen.ImportSpec{
+		{Path: "context"},
+		codegen.GoaImport("events"),
+	}
+	imports = append(imports, svc.UserTypeImports...)
+	sections := []*codegen.SectionTemplate{
+		codegen.Header(service.Name+" events", svc.PkgName, imports),
+		{
+			Name:   "events-publisher",
+			Source: eventsPublisherT,
+			Data:   svc,
+		},
+	}
+	for _, e := range svc.Events {
+		sections = append(sections, &codegen.SectionTemplate{
+			Name:   "events-publish",
+			Source: eventsPublishT,
+			Data:   e,
+		})
+	}
+	for _, t := range svc.eventTypes {
+		sections = append(sections, &codegen.SectionTemplate{
+			Name:   "events-body-type",
+			Source: userTypeT,
+			Data:   t,
+		})
+	}
+	var helpers []*codegen.TransformFunctionData
+	for _, i := range svc.eventInits {
+		helpers = codegen.AppendHelpers(helpers, i.Helpers)
+		sections = append(sections, &codegen.SectionTemplate{
+			Name:   "events-body-init",
+			Source: typeInitT,
+			Data:   i,
+		})
+	}
+	for _, h := range helpers {
+		sections = append(sections, &codegen.SectionTemplate{
+			Name:   "events-transform-helper",
+			Source: transformHelperT,
+			Data:   h,
+		})
+	}
+	return &codegen.File{Path: path, SectionTemplates: sections}
+}
+
+// input: Data
+const eventsPublisherT = `// Publisher publishes the events of the {{ .Name }} service.
+type Publisher interface {
+{{- range .Events }}
+	// {{ .FuncName }} publishes the {{ printf "%q" .Name }} event to the {{ printf "%q" .Topic }} topic.
+	{{- if .Description }}
+	{{ comment .Description }}
+	{{- end }}
+	{{ .FuncName }}(context.Context, {{ .PayloadRef }}) error
+{{- end }}
+}
+
+// publisher implements Publisher.
+type publisher struct {
+	p *events.Publisher
+}
+
+// NewPublisher returns a Publisher that encodes and sends the {{ .Name }}
+// service events with p.
+func NewPublisher(p *events.Publisher) Publisher {
+	return &publisher{p}
+}
+`
+
+// input: EventData
+const eventsPublishT = `// {{ .FuncName }} publishes the {{ printf "%q" .Name }} event.
+func (p *publisher) {{ .FuncName }}(ctx context.Context, ev {{ .PayloadRef }}) error {
+	return p.p.Publish(ctx, {{ printf "%q" .Topic }}, {{ printf "%q" .Name }}, {{ if .BodyInit }}{{ .BodyInit }}(ev){{ else }}ev{{ end }})
+}
+`
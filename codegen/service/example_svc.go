@@ -1,7 +1,8 @@
 package service
 
 import (
-	"os"
+	"encoding/json"
+	"fmt"
 	"path"
 	"strings"
 
@@ -27,6 +28,9 @@ type (
 		// ResultView is the view to render the result. It is set only if the
 		// result type uses views.
 		ResultView string
+		// ResultExample is the JSON representation of the first result
+		// example defined in the design if any.
+		ResultExample string
 		// StreamInterface is the stream interface in the service package used
 		// by the endpoint implementation.
 		StreamInterface string
@@ -62,9 +66,6 @@ func exampleServiceFile(genpkg string, _ *expr.RootExpr, svc *expr.ServiceExpr,
 	data := Services.Get(svc.Name)
 	svcName := data.PathName
 	fpath := svcName + ".go"
-	if _, err := os.Stat(fpath); !os.IsNotExist(err) {
-		return nil // file already exists, skip it.
-	}
 	specs := []*codegen.ImportSpec{
 		{Path: "io"},
 		{Path: "context"},
@@ -86,6 +87,13 @@ func exampleServiceFile(genpkg string, _ *expr.RootExpr, svc *expr.ServiceExpr,
 			Data:   data,
 		})
 	}
+	if data.HasPolicies {
+		sections = append(sections, &codegen.SectionTemplate{
+			Name:   "security-authorizer",
+			Source: dummyAuthorizerT,
+			Data:   data,
+		})
+	}
 	for _, m := range svc.Methods {
 		sections = append(sections, basicEndpointSection(m, data))
 	}
@@ -112,6 +120,9 @@ func basicEndpointSection(m *expr.MethodExpr, svcData *Data) *codegen.SectionTem
 		ed.ResultFullName = svcData.Scope.GoFullTypeName(m.Result, svcData.PkgName)
 		ed.ResultFullRef = svcData.Scope.GoFullTypeRef(m.Result, svcData.PkgName)
 		ed.ResultIsStruct = expr.IsObject(m.Result.Type)
+		if exs := m.Result.ExtractUserExamples(); len(exs) > 0 {
+			ed.ResultExample = exampleJSON(exs[0].Value)
+		}
 		if md.ViewedResult != nil {
 			view := "default"
 			if v, ok := m.Result.Meta["view"]; ok {
@@ -130,6 +141,41 @@ func basicEndpointSection(m *expr.MethodExpr, svcData *Data) *codegen.SectionTem
 	}
 }
 
+// exampleJSON returns the JSON representation of the given example
+// value. Map keys that are not strings are formatted with fmt.
+func exampleJSON(v any) string {
+	var stringKeys func(any) any
+	stringKeys = func(v any) any {
+		switch actual := v.(type) {
+		case map[any]any:
+			m := make(map[string]any, len(actual))
+			for k, e := range actual {
+				m[fmt.Sprint(k)] = stringKeys(e)
+			}
+			return m
+		case map[string]any:
+			m := make(map[string]any, len(actual))
+			for k, e := range actual {
+				m[k] = stringKeys(e)
+			}
+			return m
+		case []any:
+			elems := make([]any, len(actual))
+			for i, e := range actual {
+				elems[i] = stringKeys(e)
+			}
+			return elems
+		default:
+			return actual
+		}
+	}
+	b, err := json.Marshal(stringKeys(v))
+	if err != nil {
+		return fmt.Sprintf("%v", v)
+	}
+	return string(b)
+}
+
 const (
 	// input: service.Data
 	svcStructT = `{{ printf "%s service example implementation.\nThe example methods log the requests and return zero values." .Name | comment }}
@@ -156,6 +202,9 @@ func (s *{{ .ServiceVarName }}srvc) {{ .VarName }}(ctx context.Context{{ if .Pay
 	// req is the HTTP request body stream.
 	defer req.Close()
 {{- end }}
+{{- if .ResultExample }}
+	{{ comment (printf "Example result defined in the design:\n%s" .ResultExample) }}
+{{- end }}
 {{- if and (and .ResultFullRef .ResultIsStruct) (not .ServerStream) }}
 	res = &{{ .ResultFullName }}{}
 {{- end }}
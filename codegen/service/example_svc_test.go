@@ -3,6 +3,7 @@ package service
 import (
 	"bytes"
 	"reflect"
+	"strings"
 	"testing"
 
 	"goa.design/goa/v3/codegen"
@@ -56,4 +57,38 @@ func TestExampleServiceFiles(t *testing.T) {
 			})
 		}
 	})
+	t.Run("result examples", func(t *testing.T) {
+		codegen.RunDSL(t, testdata.ResultExampleDSL)
+		fs := ExampleServiceFiles("", expr.Root)
+		if len(fs) != 1 {
+			t.Fatalf("got %d example file services, expected 1", len(fs))
+		}
+		sections := fs[0].Section("basic-endpoint")
+		if len(sections) != 3 {
+			t.Fatalf("got %d endpoint sections, expected 3", len(sections))
+		}
+		expected := []string{
+			`// Example result defined in the design:
+// {"name":"Sterling","vintage":2014}`,
+			`// Example result defined in the design:
+// 42`,
+			"",
+		}
+		for i, s := range sections {
+			var b bytes.Buffer
+			if err := s.Write(&b); err != nil {
+				t.Fatal(err)
+			}
+			code := strings.ReplaceAll(b.String(), "\t", "") // sections are not formatted
+			if expected[i] == "" {
+				if strings.Contains(code, "Example result") {
+					t.Errorf("section %d: unexpected example comment in\n%s", i, code)
+				}
+				continue
+			}
+			if !strings.Contains(code, expected[i]) {
+				t.Errorf("section %d: got\n%s\nexpected to contain\n%s", i, code, expected[i])
+			}
+		}
+	})
 }
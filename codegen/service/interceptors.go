@@ -0,0 +1,296 @@
+package service
+
+import (
+	"path/filepath"
+
+	"goa.design/goa/v3/codegen"
+	"goa.design/goa/v3/expr"
+)
+
+// InterceptorsFile returns the file defining the interceptors of the given
+// service, nil if no interceptor applies to the service methods.
+func InterceptorsFile(_ string, service *expr.ServiceExpr) *codegen.File {
+	svc := Services.Get(service.Name)
+	if len(svc.Interceptors) == 0 {
+		return nil
+	}
+	path := filepath.Join(codegen.Gendir, svc.PathName, "interceptors.go")
+	imports := []*codegen.ImportSpec{
+		{Path: "context"},
+		codegen.GoaImport(""),
+	}
+	imports = append(imports, svc.UserTypeImports...)
+	sections := []*codegen.SectionTemplate{
+		codegen.Header(service.Name+" interceptors", svc.PkgName, imports),
+		{
+			Name:   "interceptors-interfaces",
+			Source: interceptorsInterfacesT,
+			Data:   svc,
+		},
+	}
+	for _, i := range svc.Interceptors {
+		sections = append(sections, &codegen.SectionTemplate{
+			Name:   "interceptor-types",
+			Source: interceptorTypesT,
+			Data:   i,
+		})
+	}
+	for _, i := range svc.Interceptors {
+		for _, m := range i.Methods {
+			sections = append(sections, &codegen.SectionTemplate{
+				Name:   "interceptor-accessors",
+				Source: interceptorAccessorsT,
+				Data:   map[string]any{"Interceptor": i, "Method": m},
+			})
+		}
+	}
+	if len(svc.ServerInterceptors) > 0 {
+		sections = append(sections, &codegen.SectionTemplate{
+			Name:   "interceptors-endpoints-intercept",
+			Source: interceptorsEndpointsInterceptT,
+			Data:   svc,
+		})
+	}
+	if len(svc.ClientInterceptors) > 0 {
+		sections = append(sections, &codegen.SectionTemplate{
+			Name:   "interceptors-client-intercept",
+			Source: interceptorsClientInterceptT,
+			Data:   svc,
+		})
+	}
+	for _, m := range svc.Methods {
+		if len(m.ServerInterceptors) > 0 {
+			sections = append(sections, &codegen.SectionTemplate{
+				Name:    "interceptors-wrap-endpoint",
+				Source:  interceptorsWrapEndpointT,
+				Data:    m,
+				FuncMap: map[string]any{"reverse": reverse},
+			})
+		}
+		if len(m.ClientInterceptors) > 0 {
+			sections = append(sections, &codegen.SectionTemplate{
+				Name:    "interceptors-wrap-client-endpoint",
+				Source:  interceptorsWrapClientEndpointT,
+				Data:    m,
+				FuncMap: map[string]any{"reverse": reverse},
+			})
+		}
+	}
+	for _, i := range svc.ServerInterceptors {
+		sections = append(sections, &codegen.SectionTemplate{
+			Name:   "interceptor-wrap",
+			Source: interceptorWrapT,
+			Data:   map[string]any{"Service": svc.Name, "Interceptor": i, "Side": "Server", "Kind": "server"},
+		})
+	}
+	for _, i := range svc.ClientInterceptors {
+		sections = append(sections, &codegen.SectionTemplate{
+			Name:   "interceptor-wrap",
+			Source: interceptorWrapT,
+			Data:   map[string]any{"Service": svc.Name, "Interceptor": i, "Side": "Client", "Kind": "client"},
+		})
+	}
+	return &codegen.File{Path: path, SectionTemplates: sections}
+}
+
+// reverse returns the given interceptors in reverse order so that wrapping
+// the endpoint with each of them makes the first one the outermost.
+func reverse(names []string) []string {
+	res := make([]string, len(names))
+	for i, n := range names {
+		res[len(names)-1-i] = n
+	}
+	return res
+}
+
+// input: Data
+const interceptorsInterfacesT = `{{ if .ServerInterceptors -}}
+{{ printf "ServerInterceptors lists the interceptors invoked around the server side endpoints of the %s service. The interceptors must call next to invoke the intercepted endpoint." .Name | comment }}
+type ServerInterceptors interface {
+{{- range .ServerInterceptors }}
+	// {{ .VarName }} implements the {{ printf "%q" .Name }} interceptor.
+	{{- if .Description }}
+	{{ comment .Description }}
+	{{- end }}
+	{{ .VarName }}(ctx context.Context, info *{{ .InfoStruct }}, next goa.Endpoint) (any, error)
+{{- end }}
+}
+{{ end -}}
+{{ if .ClientInterceptors -}}
+{{ if .ServerInterceptors }}
+{{ end -}}
+{{ printf "ClientInterceptors lists the interceptors invoked around the client side endpoints of the %s service. The interceptors must call next to invoke the intercepted endpoint." .Name | comment }}
+type ClientInterceptors interface {
+{{- range .ClientInterceptors }}
+	// {{ .VarName }} implements the {{ printf "%q" .Name }} interceptor.
+	{{- if .Description }}
+	{{ comment .Description }}
+	{{- end }}
+	{{ .VarName }}(ctx context.Context, info *{{ .InfoStruct }}, next goa.Endpoint) (any, error)
+{{- end }}
+}
+{{ end -}}
+`
+
+// input: InterceptorData
+const interceptorTypesT = `{{ printf "%s describes the call intercepted by the %q interceptor." .InfoStruct .Name | comment }}
+type {{ .InfoStruct }} struct {
+	// Service is the name of the service.
+	Service string
+	// Method is the name of the intercepted method.
+	Method string
+	// RawPayload is the request given to the intercepted endpoint.
+	RawPayload any
+}
+{{- if .PayloadAccess }}
+
+{{ printf "%s gives the %q interceptor access to the payload attributes." .PayloadAccess .Name | comment }}
+type {{ .PayloadAccess }} interface {
+	{{- range .ReadPayload }}
+	{{ .VarName }}() {{ .TypeRef }}
+	{{- end }}
+	{{- range .WritePayload }}
+	Set{{ .VarName }}({{ .TypeRef }})
+	{{- end }}
+}
+
+// Payload returns the accessor of the intercepted method payload.
+func (info *{{ .InfoStruct }}) Payload() {{ .PayloadAccess }} {
+	switch info.Method {
+	{{- range .Methods }}
+	case {{ printf "%q" .MethodName }}:
+		return &{{ .PayloadAccess }}{ {{ .PayloadCast }} }
+	{{- end }}
+	default:
+		return nil
+	}
+}
+{{- end }}
+{{- if .ResultAccess }}
+
+{{ printf "%s gives the %q interceptor access to the result attributes." .ResultAccess .Name | comment }}
+type {{ .ResultAccess }} interface {
+	{{- range .ReadResult }}
+	{{ .VarName }}() {{ .TypeRef }}
+	{{- end }}
+	{{- range .WriteResult }}
+	Set{{ .VarName }}({{ .TypeRef }})
+	{{- end }}
+}
+
+// Result returns the accessor of the result returned by the intercepted
+// endpoint.
+func (info *{{ .InfoStruct }}) Result(res any) {{ .ResultAccess }} {
+	switch info.Method {
+	{{- range .Methods }}
+	case {{ printf "%q" .MethodName }}:
+		return &{{ .ResultAccess }}{ {{ .ResultCast }} }
+	{{- end }}
+	default:
+		return nil
+	}
+}
+{{- end }}
+`
+
+// input: map[string]any{"Interceptor": *InterceptorData, "Method": *InterceptorMethodData}
+const interceptorAccessorsT = `{{- $m := .Method }}
+{{- if $m.PayloadAccess }}
+{{ printf "%s implements %s for the %q method." $m.PayloadAccess .Interceptor.PayloadAccess $m.MethodName | comment }}
+type {{ $m.PayloadAccess }} struct {
+	p {{ $m.PayloadRef }}
+}
+	{{- range .Interceptor.ReadPayload }}
+
+{{ printf "%s returns the value of the %q payload attribute." .VarName .Name | comment }}
+func (a *{{ $m.PayloadAccess }}) {{ .VarName }}() {{ .TypeRef }} {
+	return a.p.{{ .FieldName }}
+}
+	{{- end }}
+	{{- range .Interceptor.WritePayload }}
+
+{{ printf "Set%s sets the value of the %q payload attribute." .VarName .Name | comment }}
+func (a *{{ $m.PayloadAccess }}) Set{{ .VarName }}(v {{ .TypeRef }}) {
+	a.p.{{ .FieldName }} = v
+}
+	{{- end }}
+{{- end }}
+{{- if $m.ResultAccess }}
+{{ if $m.PayloadAccess }}
+{{ end }}
+{{- printf "%s implements %s for the %q method." $m.ResultAccess .Interceptor.ResultAccess $m.MethodName | comment }}
+type {{ $m.ResultAccess }} struct {
+	r {{ $m.ResultRef }}
+}
+	{{- range .Interceptor.ReadResult }}
+
+{{ printf "%s returns the value of the %q result attribute." .VarName .Name | comment }}
+func (a *{{ $m.ResultAccess }}) {{ .VarName }}() {{ .TypeRef }} {
+	return a.r.{{ .FieldName }}
+}
+	{{- end }}
+	{{- range .Interceptor.WriteResult }}
+
+{{ printf "Set%s sets the value of the %q result attribute." .VarName .Name | comment }}
+func (a *{{ $m.ResultAccess }}) Set{{ .VarName }}(v {{ .TypeRef }}) {
+	a.r.{{ .FieldName }} = v
+}
+	{{- end }}
+{{- end }}
+`
+
+// input: Data
+const interceptorsEndpointsInterceptT = `{{ printf "Intercept wraps the endpoints of the %s service methods with the server interceptors defined in the design. Intercept must be called before the endpoints are mounted on the transport servers." .Name | comment }}
+func (e *Endpoints) Intercept(si ServerInterceptors) {
+{{- range .Methods }}
+	{{- if .ServerInterceptors }}
+	e.{{ .VarName }} = Wrap{{ .VarName }}Endpoint(e.{{ .VarName }}, si)
+	{{- end }}
+{{- end }}
+}
+`
+
+// input: Data
+const interceptorsClientInterceptT = `{{ printf "Intercept wraps the client endpoints of the %s service methods with the client interceptors defined in the design." .Name | comment }}
+func (c *Client) Intercept(ci ClientInterceptors) {
+{{- range .Methods }}
+	{{- if .ClientInterceptors }}
+	c.{{ .VarName }}Endpoint = Wrap{{ .VarName }}ClientEndpoint(c.{{ .VarName }}Endpoint, ci)
+	{{- end }}
+{{- end }}
+}
+`
+
+// input: MethodData
+const interceptorsWrapEndpointT = `{{ printf "Wrap%sEndpoint wraps the %q endpoint with the server interceptors defined in the design." .VarName .Name | comment }}
+func Wrap{{ .VarName }}Endpoint(endpoint goa.Endpoint, i ServerInterceptors) goa.Endpoint {
+{{- range (reverse .ServerInterceptors) }}
+	endpoint = wrapServer{{ . }}(endpoint, i, {{ printf "%q" $.Name }})
+{{- end }}
+	return endpoint
+}
+`
+
+// input: MethodData
+const interceptorsWrapClientEndpointT = `{{ printf "Wrap%sClientEndpoint wraps the %q client endpoint with the client interceptors defined in the design." .VarName .Name | comment }}
+func Wrap{{ .VarName }}ClientEndpoint(endpoint goa.Endpoint, i ClientInterceptors) goa.Endpoint {
+{{- range (reverse .ClientInterceptors) }}
+	endpoint = wrapClient{{ . }}(endpoint, i, {{ printf "%q" $.Name }})
+{{- end }}
+	return endpoint
+}
+`
+
+// input: map[string]any{"Service": string, "Interceptor": *InterceptorData, "Side": string, "Kind": string}
+const interceptorWrapT = `{{ printf "wrap%s%s applies the %q %s interceptor to the endpoint of the given method." .Side .Interceptor.VarName .Interceptor.Name .Kind | comment }}
+func wrap{{ .Side }}{{ .Interceptor.VarName }}(endpoint goa.Endpoint, i {{ .Side }}Interceptors, method string) goa.Endpoint {
+	return func(ctx context.Context, req any) (any, error) {
+		info := &{{ .Interceptor.InfoStruct }}{
+			Service:    {{ printf "%q" .Service }},
+			Method:     method,
+			RawPayload: req,
+		}
+		return i.{{ .Interceptor.VarName }}(ctx, info, endpoint)
+	}
+}
+`
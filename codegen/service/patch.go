@@ -0,0 +1,276 @@
+package service
+
+import (
+	"path/filepath"
+
+	"goa.design/goa/v3/codegen"
+	"goa.design/goa/v3/expr"
+)
+
+type (
+	// patchData contains the data needed to render the function that
+	// applies the patch held by a payload attribute defined with the Patch
+	// DSL.
+	patchData struct {
+		// Name is the name of the function.
+		Name string
+		// TargetName is the Go name of the target type.
+		TargetName string
+		// TargetRef is the reference to the target type.
+		TargetRef string
+		// Validate is the name of the function that validates the
+		// target type, empty if the type does not define validations.
+		Validate string
+	}
+
+	// fieldMaskData contains the data needed to render the functions that
+	// parse and apply the field mask held by a payload attribute defined
+	// with the FieldMask DSL.
+	fieldMaskData struct {
+		// Name is the name of the field mask attribute.
+		Name string
+		// MethodName is the name of the method.
+		MethodName string
+		// VarName is the Go name of the method and attribute used to
+		// build the names of the generated functions.
+		VarName string
+		// TargetName is the Go name of the target type.
+		TargetName string
+		// TargetRef is the reference to the target type.
+		TargetRef string
+		// Pointer is true if the payload field holding the field mask
+		// is a pointer.
+		Pointer bool
+		// Paths lists the paths of the target type attributes.
+		Paths []string
+		// Validate is the name of the function that validates the
+		// target type, empty if the type does not define validations.
+		Validate string
+	}
+
+	// patchValidateData contains the data needed to render the function
+	// that validates a user type used by a patch target type.
+	patchValidateData struct {
+		// Name is the name of the user type.
+		Name string
+		// VarName is the Go name of the user type.
+		VarName string
+		// Ref is the reference to the Go type.
+		Ref string
+		// Code is the code that validates v.
+		Code string
+	}
+)
+
+// PatchFile returns the file defining the functions that apply the JSON Patch
+// and JSON Merge Patch documents and the field masks held by the method payload
+// attributes defined with the Patch and FieldMask DSLs, nil if the service does
+// not define any.
+func PatchFile(_ string, service *expr.ServiceExpr) *codegen.File {
+	svc := Services.Get(service.Name)
+	var (
+		patches   []*patchData
+		masks     []*fieldMaskData
+		validates []*patchValidateData
+		seen      = make(map[string]struct{})
+	)
+	for i, m := range service.Methods {
+		obj := expr.AsObject(m.Payload.Type)
+		if obj == nil {
+			continue
+		}
+		for _, nat := range *obj {
+			if ut := expr.FieldMaskTarget(nat.Attribute); ut != nil {
+				att := &expr.AttributeExpr{Type: ut}
+				data := &fieldMaskData{
+					Name:       nat.Name,
+					MethodName: m.Name,
+					VarName:    svc.Methods[i].VarName + codegen.Goify(nat.Name, true),
+					TargetName: svc.Scope.GoTypeName(att),
+					TargetRef:  svc.Scope.GoTypeRef(att),
+					Pointer:    m.Payload.IsPrimitivePointer(nat.Name, true),
+					Paths:      fieldMaskPaths(ut.Attribute(), "", nil),
+				}
+				validates = append(validates, collectPatchValidations(att, svc.Scope, seen)...)
+				for _, v := range validates {
+					if v.VarName == data.TargetName {
+						data.Validate = "Validate" + v.VarName
+					}
+				}
+				masks = append(masks, data)
+				continue
+			}
+			ut := expr.PatchTarget(nat.Attribute)
+			if ut == nil {
+				continue
+			}
+			att := &expr.AttributeExpr{Type: ut}
+			data := &patchData{
+				Name:       "Apply" + svc.Methods[i].VarName + codegen.Goify(nat.Name, true),
+				TargetName: svc.Scope.GoTypeName(att),
+				TargetRef:  svc.Scope.GoTypeRef(att),
+			}
+			validates = append(validates, collectPatchValidations(att, svc.Scope, seen)...)
+			for _, v := range validates {
+				if v.VarName == data.TargetName {
+					data.Validate = "Validate" + v.VarName
+				}
+			}
+			patches = append(patches, data)
+		}
+	}
+	if len(patches) == 0 && len(masks) == 0 {
+		return nil
+	}
+	path := filepath.Join(codegen.Gendir, svc.PathName, "patch.go")
+	imports := []*codegen.ImportSpec{
+		{Path: "unicode/utf8"},
+		codegen.GoaImport(""),
+	}
+	imports = append(imports, svc.UserTypeImports...)
+	sections := []*codegen.SectionTemplate{
+		codegen.Header(service.Name+" patches", svc.PkgName, imports),
+	}
+	for _, p := range patches {
+		sections = append(sections, &codegen.SectionTemplate{
+			Name:   "patch-apply",
+			Source: patchApplyT,
+			Data:   p,
+		})
+	}
+	for _, m := range masks {
+		sections = append(sections, &codegen.SectionTemplate{
+			Name:   "field-mask-apply",
+			Source: fieldMaskApplyT,
+			Data:   m,
+		})
+	}
+	for _, v := range validates {
+		sections = append(sections, &codegen.SectionTemplate{
+			Name:   "patch-validate",
+			Source: patchValidateT,
+			Data:   v,
+		})
+	}
+	return &codegen.File{Path: path, SectionTemplates: sections}
+}
+
+// fieldMaskPaths returns the paths of the attributes of the given object
+// attribute that a field mask may designate. The paths of the attributes of the
+// nested objects are built by joining the names with dots. seen lists the user
+// types of the parent attributes to stop recursion.
+func fieldMaskPaths(att *expr.AttributeExpr, prefix string, seen []string) []string {
+	if ut, ok := att.Type.(expr.UserType); ok {
+		for _, id := range seen {
+			if id == ut.ID() {
+				return nil
+			}
+		}
+		seen = append(seen, ut.ID())
+	}
+	obj := expr.AsObject(att.Type)
+	if obj == nil {
+		return nil
+	}
+	var paths []string
+	for _, nat := range *obj {
+		path := prefix + nat.Name
+		paths = append(paths, path)
+		paths = append(paths, fieldMaskPaths(nat.Attribute, path+".", seen)...)
+	}
+	return paths
+}
+
+// collectPatchValidations traverses the attribute to gather the data needed to
+// generate the validation functions of the object user types it uses that
+// define validations.
+func collectPatchValidations(att *expr.AttributeExpr, scope *codegen.NameScope, seen map[string]struct{}) (data []*patchValidateData) {
+	if att == nil || att.Type == expr.Empty {
+		return
+	}
+	collect := func(at *expr.AttributeExpr) []*patchValidateData { return collectPatchValidations(at, scope, seen) }
+	switch dt := att.Type.(type) {
+	case expr.UserType:
+		if _, ok := seen[dt.ID()]; ok {
+			return nil
+		}
+		seen[dt.ID()] = struct{}{}
+		if _, ok := dt.Attribute().Type.(*expr.Object); ok {
+			code := codegen.ValidationCode(dt.Attribute(), dt, typeContext("", scope), true, false, "v")
+			if code != "" {
+				data = append(data, &patchValidateData{
+					Name:    dt.Name(),
+					VarName: scope.GoTypeName(att),
+					Ref:     scope.GoTypeRef(att),
+					Code:    code,
+				})
+			}
+		}
+		data = append(data, collect(dt.Attribute())...)
+	case *expr.Object:
+		for _, nat := range *dt {
+			data = append(data, collect(nat.Attribute)...)
+		}
+	case *expr.Array:
+		data = append(data, collect(dt.ElemType)...)
+	case *expr.Map:
+		data = append(data, collect(dt.KeyType)...)
+		data = append(data, collect(dt.ElemType)...)
+	}
+	return
+}
+
+// input: patchData
+const patchApplyT = `{{ printf "%s applies patch to a copy of target and returns the result once validated. target is left unchanged." .Name | comment }}
+func {{ .Name }}(target {{ .TargetRef }}, patch *goa.Patch) ({{ .TargetRef }}, error) {
+	res, err := goa.ApplyPatch(target, patch)
+	if err != nil {
+		return nil, err
+	}
+{{- if .Validate }}
+	if err := {{ .Validate }}(res); err != nil {
+		return nil, err
+	}
+{{- end }}
+	return res, nil
+}
+`
+
+// input: fieldMaskData
+const fieldMaskApplyT = `{{ printf "%sPaths lists the paths of the %s attributes that the %q field mask of the %q method may designate." .VarName .TargetName .Name .MethodName | comment }}
+var {{ .VarName }}Paths = []string{ {{- range $i, $p := .Paths }}{{ if $i }}, {{ end }}{{ printf "%q" $p }}{{ end -}} }
+
+{{ printf "Parse%s parses the %q field mask of the %q method payload and checks that it only designates %s attributes." .VarName .Name .MethodName .TargetName | comment }}
+func Parse{{ .VarName }}(mask {{ if .Pointer }}*{{ end }}string) (*goa.FieldMask, error) {
+{{- if .Pointer }}
+	if mask == nil {
+		return &goa.FieldMask{}, nil
+	}
+	return goa.ParseFieldMask(*mask, {{ .VarName }}Paths...)
+{{- else }}
+	return goa.ParseFieldMask(mask, {{ .VarName }}Paths...)
+{{- end }}
+}
+
+{{ printf "Apply%s returns a copy of target where the attributes designated by mask are set to their value in update once validated. target is left unchanged." .VarName | comment }}
+func Apply{{ .VarName }}(target, update {{ .TargetRef }}, mask *goa.FieldMask) ({{ .TargetRef }}, error) {
+	res, err := goa.ApplyFieldMask(target, update, mask)
+	if err != nil {
+		return nil, err
+	}
+{{- if .Validate }}
+	if err := {{ .Validate }}(res); err != nil {
+		return nil, err
+	}
+{{- end }}
+	return res, nil
+}
+`
+
+// input: patchValidateData
+const patchValidateT = `{{ printf "Validate%s runs the validations defined on %s." .VarName .Name | comment }}
+func Validate{{ .VarName }}(v {{ .Ref }}) (err error) {
+	{{ .Code }}
+	return
+}
+`
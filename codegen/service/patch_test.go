@@ -0,0 +1,54 @@
+package service
+
+import (
+	"bytes"
+	"fmt"
+	"go/format"
+	"testing"
+
+	"goa.design/goa/v3/codegen"
+	"goa.design/goa/v3/codegen/service/testdata"
+	"goa.design/goa/v3/expr"
+)
+
+func TestPatchFile(t *testing.T) {
+	cases := []struct {
+		Name string
+		DSL  func()
+		Code string
+	}{
+		{"patch", testdata.PatchDSL, testdata.PatchCode},
+		{"field-mask", testdata.FieldMaskDSL, testdata.FieldMaskCode},
+	}
+	for _, c := range cases {
+		t.Run(c.Name, func(t *testing.T) {
+			Services = make(ServicesData)
+			codegen.RunDSL(t, c.DSL)
+			f := PatchFile("goa.design/goa/example", expr.Root.Services[0])
+			if f == nil {
+				t.Fatalf("got nil file, expected not nil")
+			}
+			buf := new(bytes.Buffer)
+			for _, s := range f.SectionTemplates[1:] {
+				if err := s.Write(buf); err != nil {
+					t.Fatal(err)
+				}
+			}
+			bs, err := format.Source(buf.Bytes())
+			if err != nil {
+				fmt.Println(buf.String())
+				t.Fatal(err)
+			}
+			code := string(bs)
+			if code != c.Code {
+				t.Errorf("invalid code, got:\n%s\ngot vs. expected:\n%s", code, codegen.Diff(t, code, c.Code))
+			}
+		})
+	}
+
+	Services = make(ServicesData)
+	codegen.RunDSL(t, testdata.RandomDSL)
+	if f := PatchFile("goa.design/goa/example", expr.Root.Services[0]); f != nil {
+		t.Errorf("got file %q, expected nil", f.Path)
+	}
+}
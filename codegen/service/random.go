@@ -0,0 +1,313 @@
+package service
+
+import (
+	"fmt"
+	"math"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"goa.design/goa/v3/codegen"
+	"goa.design/goa/v3/expr"
+)
+
+type (
+	// randomData contains the data needed to render the functions that
+	// return random instances of a user type.
+	randomData struct {
+		// Name is the name of the user type.
+		Name string
+		// VarName is the Go name of the user type used to name the
+		// functions.
+		VarName string
+		// Ref is the reference to the Go type.
+		Ref string
+		// Code is the code that initializes res with random values.
+		Code string
+		// Object is true if the user type is an object in which case
+		// the function returns nil once the maximum depth is reached.
+		Object bool
+	}
+)
+
+// RandomFile returns the file defining the functions that return random
+// instances of the user types used by the method payloads and results of the
+// given service, nil if the service does not use any user type. The values
+// satisfy the validations defined in the design and are generated with a
+// caller provided *rand.Rand so that they can be reproduced.
+func RandomFile(_ string, service *expr.ServiceExpr) *codegen.File {
+	svc := Services.Get(service.Name)
+	var (
+		data []*randomData
+		seen = make(map[string]struct{})
+	)
+	for _, m := range service.Methods {
+		data = append(data, collectRandomTypes(m.Payload, svc.Scope, seen)...)
+		data = append(data, collectRandomTypes(m.Result, svc.Scope, seen)...)
+	}
+	if len(data) == 0 {
+		return nil
+	}
+	path := filepath.Join(codegen.Gendir, svc.PathName, "random.go")
+	imports := []*codegen.ImportSpec{
+		{Path: "math/rand"},
+		codegen.GoaImport("random"),
+	}
+	imports = append(imports, svc.UserTypeImports...)
+	sections := []*codegen.SectionTemplate{
+		codegen.Header(service.Name+" random values", svc.PkgName, imports),
+	}
+	for _, d := range data {
+		sections = append(sections, &codegen.SectionTemplate{
+			Name:   "random-type",
+			Source: randomTypeT,
+			Data:   d,
+		})
+	}
+	return &codegen.File{Path: path, SectionTemplates: sections}
+}
+
+// collectRandomTypes traverses the attribute to gather the data needed to
+// generate the random functions of the user types it uses.
+func collectRandomTypes(att *expr.AttributeExpr, scope *codegen.NameScope, seen map[string]struct{}) (data []*randomData) {
+	if att == nil || att.Type == expr.Empty {
+		return
+	}
+	collect := func(at *expr.AttributeExpr) []*randomData { return collectRandomTypes(at, scope, seen) }
+	switch dt := att.Type.(type) {
+	case *expr.Union:
+		return nil
+	case expr.UserType:
+		if _, ok := seen[dt.ID()]; ok || dt == expr.ErrorResult {
+			return nil
+		}
+		seen[dt.ID()] = struct{}{}
+		ref := scope.GoFullTypeRef(att, codegen.UserTypeLocation(dt).PackageName())
+		rd := &randomData{Name: dt.Name(), VarName: scope.GoTypeName(att), Ref: ref}
+		if _, ok := dt.Attribute().Type.(*expr.Object); ok {
+			rd.Object = true
+			rd.Code = randomObjectCode(dt.Attribute(), "res", scope, 0)
+		} else {
+			rd.Code = randomCode(dt.Attribute(), "res", strings.TrimPrefix(ref, "*"), scope, 0)
+		}
+		data = append(data, rd)
+		data = append(data, collect(dt.Attribute())...)
+	case *expr.Object:
+		for _, nat := range *dt {
+			data = append(data, collect(nat.Attribute)...)
+		}
+	case *expr.Array:
+		data = append(data, collect(dt.ElemType)...)
+	case *expr.Map:
+		data = append(data, collect(dt.KeyType)...)
+		data = append(data, collect(dt.ElemType)...)
+	}
+	return
+}
+
+// randomObjectCode returns the code that initializes the fields of the struct
+// target built from the given object attribute with random values. Optional
+// fields are initialized half of the time.
+func randomObjectCode(parent *expr.AttributeExpr, target string, scope *codegen.NameScope, depth int) string {
+	var lines []string
+	for _, nat := range *expr.AsObject(parent.Type) {
+		att := nat.Attribute
+		if t, _ := codegen.GetMetaType(att); t != "" || expr.IsUnion(att.Type) {
+			continue
+		}
+		field := target + "." + codegen.GoifyAtt(att, nat.Name, true)
+		ref := randomTypeRef(att, scope)
+		ptr := parent.IsPrimitivePointer(nat.Name, true)
+		var code string
+		if ptr {
+			code = fmt.Sprintf("v := %s\n%s = &v", randomPrimitive(att, ref), field)
+		} else {
+			code = randomCode(att, field, ref, scope, depth)
+		}
+		if code == "" {
+			continue
+		}
+		if !parent.IsRequired(nat.Name) && (ptr || !expr.IsPrimitive(att.Type)) {
+			code = fmt.Sprintf("if r.Intn(2) == 0 {\n%s\n}", code)
+		}
+		lines = append(lines, code)
+	}
+	return strings.Join(lines, "\n")
+}
+
+// randomCode returns the code that assigns a random value of the given
+// attribute type to target. ref is the Go type of target.
+func randomCode(att *expr.AttributeExpr, target, ref string, scope *codegen.NameScope, depth int) string {
+	switch dt := att.Type.(type) {
+	case *expr.Union:
+		return ""
+	case expr.UserType:
+		if dt == expr.ErrorResult {
+			return ""
+		}
+		return fmt.Sprintf("%s = random%s(r, depth+1)", target, scope.GoTypeName(att))
+	case *expr.Object:
+		return fmt.Sprintf("%s = &%s{}\n%s", target, ref, randomObjectCode(att, target, scope, depth))
+	case *expr.Array:
+		i := fmt.Sprintf("i%d", depth)
+		elem := randomCode(dt.ElemType, target+"["+i+"]", randomTypeRef(dt.ElemType, scope), scope, depth+1)
+		min, max := randomLength(att, 0, 3)
+		return fmt.Sprintf("%s = make(%s, random.Length(r, %d, %d))\nfor %s := range %s {\n%s\n}", target, ref, min, max, i, target, elem)
+	case *expr.Map:
+		k, v := fmt.Sprintf("k%d", depth), fmt.Sprintf("v%d", depth)
+		kref, vref := randomTypeRef(dt.KeyType, scope), randomTypeRef(dt.ElemType, scope)
+		min, max := randomLength(att, 0, 3)
+		return fmt.Sprintf("%s = make(%s)\nfor n := random.Length(r, %d, %d); n > 0; n-- {\nvar %s %s\n%s\nvar %s %s\n%s\n%s[%s] = %s\n}",
+			target, ref, min, max,
+			k, kref, randomCode(dt.KeyType, k, kref, scope, depth+1),
+			v, vref, randomCode(dt.ElemType, v, vref, scope, depth+1),
+			target, k, v)
+	case expr.Primitive:
+		return fmt.Sprintf("%s = %s", target, randomPrimitive(att, ref))
+	}
+	return ""
+}
+
+// randomPrimitive returns the expression that produces a random value of the
+// given primitive attribute type converted to ref.
+func randomPrimitive(att *expr.AttributeExpr, ref string) string {
+	v := att.Validation
+	if v != nil && len(v.Values) > 0 {
+		vals := make([]string, len(v.Values))
+		for i, val := range v.Values {
+			vals[i] = fmt.Sprintf("%#v", val)
+		}
+		return fmt.Sprintf("[]%s{%s}[r.Intn(%d)]", ref, strings.Join(vals, ", "), len(vals))
+	}
+	conv := func(native, code string) string {
+		if ref == native {
+			return code
+		}
+		return ref + "(" + code + ")"
+	}
+	switch k := att.Type.Kind(); k {
+	case expr.BooleanKind:
+		return conv("bool", "r.Intn(2) == 0")
+	case expr.IntKind, expr.Int32Kind, expr.Int64Kind, expr.UIntKind, expr.UInt32Kind, expr.UInt64Kind:
+		min, max := randomRange(v, true, k == expr.UIntKind || k == expr.UInt32Kind || k == expr.UInt64Kind)
+		return conv("int64", fmt.Sprintf("random.Int64(r, %d, %d)", int64(min), int64(max)))
+	case expr.Float32Kind, expr.Float64Kind:
+		min, max := randomRange(v, false, false)
+		return conv("float64", fmt.Sprintf("random.Float64(r, %s, %s)", formatFloat(min), formatFloat(max)))
+	case expr.StringKind:
+		switch {
+		case v != nil && v.Pattern != "":
+			return conv("string", fmt.Sprintf("random.Pattern(r, %q)", v.Pattern))
+		case v != nil && v.Format != "":
+			return conv("string", fmt.Sprintf("random.Format(r, %q)", v.Format))
+		}
+		min, max := randomLength(att, 1, 9)
+		return conv("string", fmt.Sprintf("random.String(r, %d, %d)", min, max))
+	case expr.BytesKind:
+		min, max := randomLength(att, 1, 9)
+		return conv("string", fmt.Sprintf("random.String(r, %d, %d)", min, max))
+	}
+	return "random.String(r, 1, 10)"
+}
+
+// randomRange returns the interval of the random numeric values that satisfy
+// the given validation. The interval spans 100 if not bounded. The bounds of
+// integer intervals are included while the upper bound of floating point
+// intervals is excluded.
+func randomRange(v *expr.ValidationExpr, integer, unsigned bool) (min, max float64) {
+	var hasMin, hasMax bool
+	if v != nil {
+		switch {
+		case v.Minimum != nil:
+			min, hasMin = *v.Minimum, true
+			if integer {
+				min = math.Ceil(min)
+			}
+		case v.ExclusiveMinimum != nil:
+			min, hasMin = *v.ExclusiveMinimum, true
+			if integer {
+				min = math.Floor(min) + 1
+			}
+		}
+		switch {
+		case v.Maximum != nil:
+			max, hasMax = *v.Maximum, true
+			if integer {
+				max = math.Floor(max)
+			}
+		case v.ExclusiveMaximum != nil:
+			max, hasMax = *v.ExclusiveMaximum, true
+			if integer {
+				max = math.Ceil(max) - 1
+			}
+		}
+	}
+	switch {
+	case !hasMin && !hasMax:
+		max = 100
+	case !hasMax:
+		max = min + 100
+	case !hasMin:
+		min = max - 100
+	}
+	if unsigned && min < 0 {
+		min = 0
+	}
+	return
+}
+
+// randomLength returns the interval of the random lengths of the given string,
+// bytes, array or map attribute that satisfy its validations.
+func randomLength(att *expr.AttributeExpr, min, span int) (int, int) {
+	if v := att.Validation; v != nil && v.MinLength != nil {
+		min = *v.MinLength
+	}
+	max := min + span
+	if v := att.Validation; v != nil && v.MaxLength != nil {
+		max = *v.MaxLength
+	}
+	if min > max {
+		min = max
+	}
+	return min, max
+}
+
+// randomTypeRef returns the reference to the Go type of the given attribute
+// used in the service package.
+func randomTypeRef(att *expr.AttributeExpr, scope *codegen.NameScope) string {
+	var pkg string
+	if ut, ok := att.Type.(expr.UserType); ok {
+		pkg = codegen.UserTypeLocation(ut).PackageName()
+	}
+	return scope.GoFullTypeRef(att, pkg)
+}
+
+// formatFloat returns the Go literal of the given floating point number.
+func formatFloat(f float64) string {
+	s := strconv.FormatFloat(f, 'g', -1, 64)
+	if !strings.ContainsAny(s, ".e") {
+		s += ".0"
+	}
+	return s
+}
+
+// input: randomData
+const randomTypeT = `{{ printf "Random%s returns a random %s value that satisfies the validations defined in the design using r as source of randomness." .VarName .Name | comment }}
+func Random{{ .VarName }}(r *rand.Rand) {{ .Ref }} {
+	return random{{ .VarName }}(r, 0)
+}
+
+{{ printf "random%s returns a random %s value, depth is the number of enclosing user types." .VarName .Name | comment }}
+func random{{ .VarName }}(r *rand.Rand, depth int) {{ .Ref }} {
+{{- if .Object }}
+	if depth > random.MaxDepth {
+		return nil
+	}
+	res := &{{ slice .Ref 1 }}{}
+{{- else }}
+	var res {{ .Ref }}
+{{- end }}
+	{{ .Code }}
+	return res
+}
+`
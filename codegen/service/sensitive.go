@@ -0,0 +1,365 @@
+package service
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"goa.design/goa/v3/codegen"
+	"goa.design/goa/v3/expr"
+)
+
+type (
+	// codecData contains the data needed to render the functions that
+	// encrypt and decrypt the encrypted attributes of a service.
+	codecData struct {
+		// Name is the service name.
+		Name string
+		// ServerMethods lists the methods whose server endpoint must be
+		// wrapped.
+		ServerMethods []*codecMethodData
+		// ClientMethods lists the methods whose client endpoint must be
+		// wrapped.
+		ClientMethods []*codecMethodData
+	}
+
+	// codecMethodData contains the data needed to render the function that
+	// encrypts and decrypts the encrypted attributes of a method payload and
+	// result.
+	codecMethodData struct {
+		// Name is the method name.
+		Name string
+		// VarName is the Go method name.
+		VarName string
+		// PayloadRef is a reference to the payload type, empty if the
+		// payload does not define encrypted attributes.
+		PayloadRef string
+		// PayloadCode is the code that encrypts or decrypts the payload
+		// attributes of p.
+		PayloadCode string
+		// ResultRef is a reference to the result type, empty if the
+		// result does not define encrypted attributes.
+		ResultRef string
+		// ResultCode is the code that encrypts or decrypts the result
+		// attributes of r.
+		ResultCode string
+		// Viewed is true if the result is a viewed result.
+		Viewed bool
+		// DeclareErr is true if the code requires err to be declared.
+		DeclareErr bool
+	}
+)
+
+// CodecFile returns the file defining the functions that apply a
+// goa.FieldCodec to the encrypted attributes of the service method payloads
+// and results, nil if the service does not define encrypted attributes.
+func CodecFile(genpkg string, service *expr.ServiceExpr) *codegen.File {
+	svc := Services.Get(service.Name)
+	data := &codecData{Name: service.Name}
+	for i, m := range service.Methods {
+		md := svc.Methods[i]
+		payload, result := expr.HasEncrypted(m.Payload), expr.HasEncrypted(m.Result)
+		if !payload && !result {
+			continue
+		}
+		server := &codecMethodData{Name: m.Name, VarName: md.VarName}
+		client := &codecMethodData{Name: m.Name, VarName: md.VarName}
+		if payload {
+			server.PayloadRef, client.PayloadRef = md.PayloadRef, md.PayloadRef
+			server.PayloadCode, server.DeclareErr = codecCode(m.Payload, "p", "Decrypt", false)
+			client.PayloadCode, client.DeclareErr = codecCode(m.Payload, "p", "Encrypt", false)
+		}
+		if result {
+			server.ResultRef, client.ResultRef = md.ResultRef, md.ResultRef
+			var declare bool
+			if md.ViewedResult != nil && !md.ViewedResult.IsCollection {
+				server.Viewed = true
+				server.ResultRef = md.ViewedResult.FullRef
+				server.ResultCode, declare = codecCode(m.Result, "r", "Encrypt", true)
+			} else {
+				server.ResultCode, declare = codecCode(m.Result, "r", "Encrypt", false)
+			}
+			server.DeclareErr = server.DeclareErr || declare
+			client.ResultCode, declare = codecCode(m.Result, "r", "Decrypt", false)
+			client.DeclareErr = client.DeclareErr || declare
+		}
+		data.ServerMethods = append(data.ServerMethods, server)
+		data.ClientMethods = append(data.ClientMethods, client)
+	}
+	if len(data.ServerMethods) == 0 {
+		return nil
+	}
+	path := filepath.Join(codegen.Gendir, svc.PathName, "codec.go")
+	imports := []*codegen.ImportSpec{
+		{Path: "context"},
+		codegen.GoaImport(""),
+		{Path: genpkg + "/" + svc.PathName + "/" + "views", Name: svc.ViewsPkg},
+	}
+	imports = append(imports, svc.UserTypeImports...)
+	sections := []*codegen.SectionTemplate{
+		codegen.Header(service.Name+" codec", svc.PkgName, imports),
+		{
+			Name:   "codec-use",
+			Source: codecUseT,
+			Data:   data,
+		},
+	}
+	for _, m := range data.ServerMethods {
+		sections = append(sections, &codegen.SectionTemplate{
+			Name:   "codec-server-wrap",
+			Source: codecServerWrapT,
+			Data:   m,
+		})
+	}
+	for _, m := range data.ClientMethods {
+		sections = append(sections, &codegen.SectionTemplate{
+			Name:   "codec-client-wrap",
+			Source: codecClientWrapT,
+			Data:   m,
+		})
+	}
+	return &codegen.File{Path: path, SectionTemplates: sections}
+}
+
+// collectRedactMethods traverses the attribute to gather the data needed to
+// generate the Redact methods of the object user types that define sensitive
+// attributes.
+func collectRedactMethods(att *expr.AttributeExpr, scope *codegen.NameScope, seen map[string]struct{}) (data []*RedactData) {
+	if att == nil || att.Type == expr.Empty {
+		return
+	}
+	collect := func(at *expr.AttributeExpr) []*RedactData { return collectRedactMethods(at, scope, seen) }
+	switch dt := att.Type.(type) {
+	case expr.UserType:
+		if _, ok := seen[dt.ID()]; ok {
+			return nil
+		}
+		seen[dt.ID()] = struct{}{}
+		if _, ok := dt.Attribute().Type.(*expr.Object); ok && expr.HasSensitive(dt.Attribute()) {
+			data = append(data, &RedactData{
+				VarName: scope.GoTypeName(att),
+				Ref:     scope.GoTypeRef(att),
+				Loc:     codegen.UserTypeLocation(dt),
+				Code:    redactCode(dt.Attribute(), scope, false),
+			})
+		}
+		data = append(data, collect(dt.Attribute())...)
+	case *expr.Object:
+		for _, nat := range *dt {
+			data = append(data, collect(nat.Attribute)...)
+		}
+	case *expr.Array:
+		data = append(data, collect(dt.ElemType)...)
+	case *expr.Map:
+		data = append(data, collect(dt.KeyType)...)
+		data = append(data, collect(dt.ElemType)...)
+	case *expr.Union:
+		for _, nat := range dt.Values {
+			data = append(data, collect(nat.Attribute)...)
+		}
+	}
+	return
+}
+
+// viewedRedactData returns the data needed to generate the Redact method of
+// the given viewed result type, nil if the projected type does not define
+// sensitive attributes.
+func viewedRedactData(vrt *ViewedResultTypeData, viewScope *codegen.NameScope) *RedactData {
+	projected := expr.AsObject(vrt.Type.Attribute().Type).Attribute("projected")
+	var code string
+	if arr := expr.AsArray(projected.Type); arr != nil {
+		if !isObjectUserType(arr.ElemType.Type) || !expr.HasSensitive(arr.ElemType) {
+			return nil
+		}
+		code = fmt.Sprintf("if res.Projected != nil {\n\tl := make(%s, len(res.Projected))\n\tfor i, e := range res.Projected {\n\t\tl[i] = e.Redact().(%s)\n\t}\n\tres.Projected = l\n}",
+			viewScope.GoTypeRef(projected), viewScope.GoTypeRef(arr.ElemType))
+	} else {
+		if !expr.HasSensitive(projected) {
+			return nil
+		}
+		code = fmt.Sprintf("if res.Projected != nil {\n\tres.Projected = res.Projected.Redact().(%s)\n}", viewScope.GoTypeRef(projected))
+	}
+	return &RedactData{
+		VarName: vrt.VarName,
+		Ref:     vrt.Ref,
+		Code:    code,
+		Value:   vrt.IsCollection,
+	}
+}
+
+// redactCode returns the code that redacts the sensitive fields of the struct
+// res built from the given object attribute. Child object user types that
+// define sensitive attributes are redacted using their own Redact method. If
+// projected is true res is a projected type whose fields are all pointers.
+func redactCode(parent *expr.AttributeExpr, scope *codegen.NameScope, projected bool) string {
+	var lines []string
+	for _, nat := range *expr.AsObject(parent.Type) {
+		att := nat.Attribute
+		field := "res." + codegen.GoifyAtt(att, nat.Name, true)
+		ptr := parent.IsPrimitivePointer(nat.Name, true) || projected && expr.IsPrimitive(att.Type)
+		switch {
+		case att.IsSensitive() && att.Type.Kind() == expr.StringKind && ptr:
+			redacted := "goa.Redacted"
+			if name := scope.GoTypeName(att); name != "string" {
+				redacted = name + "(goa.Redacted)"
+			}
+			lines = append(lines, fmt.Sprintf("if %s != nil {\n\tr := %s\n\t%s = &r\n}", field, redacted, field))
+		case att.IsSensitive() && att.Type.Kind() == expr.StringKind:
+			lines = append(lines, fmt.Sprintf("%s = goa.Redacted", field))
+		case att.IsSensitive():
+			lines = append(lines, fmt.Sprintf("%s = %s", field, zeroValue(att, ptr)))
+		case !expr.HasSensitive(att):
+		case isObjectUserType(att.Type):
+			lines = append(lines, fmt.Sprintf("if %s != nil {\n\t%s = %s.Redact().(%s)\n}", field, field, field, scope.GoTypeRef(att)))
+		case expr.IsArray(att.Type) && isObjectUserType(expr.AsArray(att.Type).ElemType.Type):
+			elem := expr.AsArray(att.Type).ElemType
+			lines = append(lines, fmt.Sprintf("if %s != nil {\n\tl := make(%s, len(%s))\n\tfor i, e := range %s {\n\t\tl[i] = e.Redact().(%s)\n\t}\n\t%s = l\n}",
+				field, scope.GoTypeRef(att), field, field, scope.GoTypeRef(elem), field))
+		}
+	}
+	return strings.Join(lines, "\n")
+}
+
+// codecCode returns the code that applies the given codec operation to the
+// encrypted attributes of the struct v built from the given object attribute.
+// If projected is true v is a projected type whose fields are all pointers.
+// codecCode also returns whether the code requires err to be declared.
+func codecCode(parent *expr.AttributeExpr, v, op string, projected bool) (string, bool) {
+	var (
+		lines   []string
+		declare bool
+	)
+	for _, nat := range *expr.AsObject(parent.Type) {
+		if !nat.Attribute.IsEncrypted() {
+			continue
+		}
+		field := v + "." + codegen.GoifyAtt(nat.Attribute, nat.Name, true)
+		if projected || parent.IsPrimitivePointer(nat.Name, true) {
+			lines = append(lines, fmt.Sprintf("if %s != nil {\n\tv, err := c.%s(ctx, %q, *%s)\n\tif err != nil {\n\t\treturn nil, err\n\t}\n\t%s = &v\n}",
+				field, op, nat.Name, field, field))
+			continue
+		}
+		declare = true
+		lines = append(lines, fmt.Sprintf("if %s, err = c.%s(ctx, %q, %s); err != nil {\n\treturn nil, err\n}", field, op, nat.Name, field))
+	}
+	return strings.Join(lines, "\n"), declare
+}
+
+// isObjectUserType returns true if dt is a user type whose underlying type is
+// an object.
+func isObjectUserType(dt expr.DataType) bool {
+	ut, ok := dt.(expr.UserType)
+	if !ok {
+		return false
+	}
+	_, ok = ut.Attribute().Type.(*expr.Object)
+	return ok
+}
+
+// zeroValue returns the Go zero value of the field holding the given attribute.
+func zeroValue(att *expr.AttributeExpr, ptr bool) string {
+	if ptr {
+		return "nil"
+	}
+	switch att.Type.Kind() {
+	case expr.BooleanKind:
+		return "false"
+	case expr.IntKind, expr.Int32Kind, expr.Int64Kind, expr.UIntKind, expr.UInt32Kind, expr.UInt64Kind, expr.Float32Kind, expr.Float64Kind:
+		return "0"
+	case expr.StringKind:
+		return `""`
+	default:
+		return "nil"
+	}
+}
+
+// input: RedactData
+const redactT = `{{ printf "Redact returns a copy of v where the sensitive attributes are redacted so that it can be logged safely." | comment }}
+func (v {{ .Ref }}) Redact() any {
+{{- if .Value }}
+	res := v
+{{- else }}
+	if v == nil {
+		return v
+	}
+	res := *v
+{{- end }}
+	{{ .Code }}
+	return {{ if not .Value }}&{{ end }}res
+}
+`
+
+// input: codecData
+const codecUseT = `{{ printf "UseCodec wraps the %q service endpoints so that the encrypted payload attributes are decrypted with c before the service methods are called and the encrypted result attributes are encrypted with c before the results are encoded." .Name | comment }}
+func (e *Endpoints) UseCodec(c goa.FieldCodec) {
+{{- range .ServerMethods }}
+	e.{{ .VarName }} = wrap{{ .VarName }}ServerCodec(e.{{ .VarName }}, c)
+{{- end }}
+}
+
+{{ printf "UseCodec wraps the %q service client endpoints so that the encrypted payload attributes are encrypted with c before the requests are encoded and the encrypted result attributes are decrypted with c once the responses are decoded." .Name | comment }}
+func (c *Client) UseCodec(fc goa.FieldCodec) {
+{{- range .ClientMethods }}
+	c.{{ .VarName }}Endpoint = wrap{{ .VarName }}ClientCodec(c.{{ .VarName }}Endpoint, fc)
+{{- end }}
+}
+`
+
+// input: codecMethodData
+const codecServerWrapT = `{{ printf "wrap%sServerCodec applies the codec to the encrypted attributes of the %q method payload and result." .VarName .Name | comment }}
+func wrap{{ .VarName }}ServerCodec(endpoint goa.Endpoint, c goa.FieldCodec) goa.Endpoint {
+	return func(ctx context.Context, req any) (any, error) {
+{{- if .DeclareErr }}
+		var err error
+{{- end }}
+{{- if .PayloadRef }}
+		p := req.({{ .PayloadRef }})
+		{{ .PayloadCode }}
+{{- end }}
+		res, err := endpoint(ctx, req)
+		if err != nil {
+			return nil, err
+		}
+{{- if not .ResultRef }}
+		return res, nil
+{{- else if .Viewed }}
+		vres := *res.({{ .ResultRef }})
+		r := *vres.Projected
+		{{ .ResultCode }}
+		vres.Projected = &r
+		return &vres, nil
+{{- else }}
+		r := *res.({{ .ResultRef }})
+		{{ .ResultCode }}
+		return &r, nil
+{{- end }}
+	}
+}
+`
+
+// input: codecMethodData
+const codecClientWrapT = `{{ printf "wrap%sClientCodec applies the codec to the encrypted attributes of the %q method payload and result." .VarName .Name | comment }}
+func wrap{{ .VarName }}ClientCodec(endpoint goa.Endpoint, c goa.FieldCodec) goa.Endpoint {
+	return func(ctx context.Context, req any) (any, error) {
+{{- if .DeclareErr }}
+		var err error
+{{- end }}
+{{- if .PayloadRef }}
+		p := *req.({{ .PayloadRef }})
+		{{ .PayloadCode }}
+		req = &p
+{{- end }}
+		res, err := endpoint(ctx, req)
+		if err != nil {
+			return nil, err
+		}
+{{- if .ResultRef }}
+		r := res.({{ .ResultRef }})
+		{{ .ResultCode }}
+		return r, nil
+{{- else }}
+		return res, nil
+{{- end }}
+	}
+}
+`
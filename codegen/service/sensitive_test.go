@@ -0,0 +1,90 @@
+package service
+
+import (
+	"bytes"
+	"fmt"
+	"go/format"
+	"testing"
+
+	"goa.design/goa/v3/codegen"
+	"goa.design/goa/v3/codegen/service/testdata"
+	"goa.design/goa/v3/expr"
+)
+
+func TestRedact(t *testing.T) {
+	Services = make(ServicesData)
+	codegen.RunDSL(t, testdata.SensitiveDSL)
+	files := Files("goa.design/goa/example", expr.Root.Services[0], make(map[string][]string))
+	buf := new(bytes.Buffer)
+	for _, s := range files[0].SectionTemplates {
+		if s.Name != "service-redact" {
+			continue
+		}
+		if err := s.Write(buf); err != nil {
+			t.Fatal(err)
+		}
+	}
+	bs, err := format.Source(buf.Bytes())
+	if err != nil {
+		fmt.Println(buf.String())
+		t.Fatal(err)
+	}
+	code := string(bs)
+	if code != testdata.RedactCode {
+		t.Errorf("invalid code, got:\n%s\ngot vs. expected:\n%s", code, codegen.Diff(t, code, testdata.RedactCode))
+	}
+}
+
+func TestCodecFile(t *testing.T) {
+	Services = make(ServicesData)
+	codegen.RunDSL(t, testdata.SensitiveDSL)
+	f := CodecFile("goa.design/goa/example", expr.Root.Services[0])
+	if f == nil {
+		t.Fatalf("got nil file, expected not nil")
+	}
+	buf := new(bytes.Buffer)
+	for _, s := range f.SectionTemplates[1:] {
+		if err := s.Write(buf); err != nil {
+			t.Fatal(err)
+		}
+	}
+	bs, err := format.Source(buf.Bytes())
+	if err != nil {
+		fmt.Println(buf.String())
+		t.Fatal(err)
+	}
+	code := string(bs)
+	if code != testdata.CodecCode {
+		t.Errorf("invalid code, got:\n%s\ngot vs. expected:\n%s", code, codegen.Diff(t, code, testdata.CodecCode))
+	}
+
+	Services = make(ServicesData)
+	codegen.RunDSL(t, testdata.EventsDSL)
+	if f := CodecFile("goa.design/goa/example", expr.Root.Services[0]); f != nil {
+		t.Errorf("got file %q, expected nil", f.Path)
+	}
+}
+
+func TestViewedRedact(t *testing.T) {
+	Services = make(ServicesData)
+	codegen.RunDSL(t, testdata.SensitiveViewsDSL)
+	f := ViewsFile("goa.design/goa/example", expr.Root.Services[0])
+	buf := new(bytes.Buffer)
+	for _, s := range f.SectionTemplates {
+		if s.Name != "viewed-redact" {
+			continue
+		}
+		if err := s.Write(buf); err != nil {
+			t.Fatal(err)
+		}
+	}
+	bs, err := format.Source(buf.Bytes())
+	if err != nil {
+		fmt.Println(buf.String())
+		t.Fatal(err)
+	}
+	code := string(bs)
+	if code != testdata.ViewedRedactCode {
+		t.Errorf("invalid code, got:\n%s\ngot vs. expected:\n%s", code, codegen.Diff(t, code, testdata.ViewedRedactCode))
+	}
+}
@@ -100,6 +100,14 @@ func Files(genpkg string, service *expr.ServiceExpr, userTypePkgs map[string][]s
 		})
 	}
 
+	for _, r := range svc.redactMethods {
+		addTypeDefSection(pathWithDefault(r.Loc, svcPath), "~"+r.VarName+".Redact", &codegen.SectionTemplate{
+			Name:   "service-redact",
+			Source: redactT,
+			Data:   r,
+		})
+	}
+
 	for _, et := range errorTypes {
 		// Don't override the section created for the error type
 		// declaration, make sure the key does not clash with existing
@@ -328,6 +336,17 @@ type Auther interface {
 	{{- end }}
 }
 {{- end }}
+{{- if .HasPolicies }}
+
+// Authorizer defines the authorization function invoked with the policy of the
+// endpoints after the requests have been authenticated.
+type Authorizer interface {
+	// Authorize returns a non-nil error, typically a *security.AuthorizationError,
+	// if the request is denied by the endpoint policy. payload is nil for
+	// methods that do not define one.
+	Authorize(ctx context.Context, policy *security.Policy, payload any) error
+}
+{{- end }}
 
 // ServiceName is the name of the service as defined in the design. This is the
 // same value that is set in the endpoint request contexts under the ServiceKey
@@ -338,6 +357,13 @@ const ServiceName = {{ printf "%q" .Name }}
 // are the same values that are set in the endpoint request contexts under the
 // MethodKey key.
 var MethodNames = [{{ len .Methods }}]string{ {{ range .Methods }}{{ printf "%q" .Name }}, {{ end }} }
+{{- if .DiscoveryTags }}
+
+// DiscoveryTags lists the service discovery tags defined in the design with the
+// "discovery:tag" meta. They are meant to be set on the registrations made with
+// the goa.design/goa/v3/discovery package.
+var DiscoveryTags = []string{ {{ range .DiscoveryTags }}{{ printf "%q" . }}, {{ end }} }
+{{- end }}
 {{- range .Methods }}
 	{{- if .ServerStream }}
 		{{ template "stream_interface" (streamInterfaceFor "server" . .ServerStream) }}
@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"sort"
 	"strings"
+	"sync"
 	"text/template"
 
 	"goa.design/goa/v3/codegen"
@@ -15,6 +16,10 @@ import (
 // of the services.
 var Services = make(ServicesData)
 
+// servicesMu synchronizes the accesses to the services data so that the data
+// of different services may be computed concurrently.
+var servicesMu sync.Mutex
+
 var (
 	// initTypeTmpl is the template used to render the code that initializes a
 	// projected type or viewed result type or a result type.
@@ -55,8 +60,25 @@ type (
 		ViewsPkg string
 		// Methods lists the service interface methods.
 		Methods []*MethodData
+		// Events lists the events published by the service.
+		Events []*EventData
+		// Interceptors lists the interceptors applied to the service
+		// methods server side or client side.
+		Interceptors []*InterceptorData
+		// ServerInterceptors lists the interceptors applied to the server
+		// side endpoints.
+		ServerInterceptors []*InterceptorData
+		// ClientInterceptors lists the interceptors applied to the client
+		// side endpoints.
+		ClientInterceptors []*InterceptorData
 		// Schemes is the list of security schemes required by the service methods.
 		Schemes SchemesData
+		// HasPolicies is true if any of the service methods defines an
+		// authorization policy.
+		HasPolicies bool
+		// DiscoveryTags lists the service discovery tags defined with the
+		// "discovery:tag" meta of the API and of the service.
+		DiscoveryTags []string
 		// Scope initialized with all the service types.
 		Scope *codegen.NameScope
 		// ViewScope initialized with all the viewed types.
@@ -84,6 +106,19 @@ type (
 		viewedResultTypes []*ViewedResultTypeData
 		// unionValueMethods lists the methods used to define union types.
 		unionValueMethods []*UnionValueMethodData
+		// redactMethods lists the methods that redact the sensitive
+		// attributes of the service types.
+		redactMethods []*RedactData
+		// viewedRedactMethods lists the methods that redact the sensitive
+		// attributes of the projected and viewed result types.
+		viewedRedactMethods []*RedactData
+		// eventTypes lists the event body type definitions.
+		eventTypes []*UserTypeData
+		// eventInits lists the functions that build the event bodies.
+		eventInits []*InitData
+		// examples is true once the examples of the method payloads and
+		// results are generated.
+		examples bool
 	}
 
 	// UnionValueMethodData describes a method used on a union value type.
@@ -97,6 +132,23 @@ type (
 		Loc *codegen.Location
 	}
 
+	// RedactData describes the method that redacts the sensitive attributes of
+	// a type.
+	RedactData struct {
+		// VarName is the name of the type.
+		VarName string
+		// Ref is a reference to the type.
+		Ref string
+		// Loc defines the file and Go package of the type if overridden
+		// via Meta.
+		Loc *codegen.Location
+		// Code is the code that redacts the fields of res.
+		Code string
+		// Value is true if the method receiver is a value rather than a
+		// pointer.
+		Value bool
+	}
+
 	// ErrorInitData describes an error returned by a service method of type
 	// ErrorResult.
 	ErrorInitData struct {
@@ -118,6 +170,93 @@ type (
 		Fault bool
 	}
 
+	// EventData describes an event published by the service.
+	EventData struct {
+		// Name is the event name.
+		Name string
+		// Description is the event description.
+		Description string
+		// FuncName is the name of the Publisher interface method that
+		// publishes the event.
+		FuncName string
+		// Topic is the name of the topic the event is published to.
+		Topic string
+		// PayloadRef is a reference to the event payload type.
+		PayloadRef string
+		// BodyInit is the name of the function that builds the event
+		// body from the payload, empty if the payload is encoded as is.
+		BodyInit string
+	}
+
+	// InterceptorData describes an interceptor applied to the service
+	// methods.
+	InterceptorData struct {
+		// Name is the interceptor name.
+		Name string
+		// Description is the interceptor description.
+		Description string
+		// VarName is the name of the interceptor interfaces method.
+		VarName string
+		// InfoStruct is the name of the struct describing the intercepted
+		// calls.
+		InfoStruct string
+		// PayloadAccess is the name of the interface giving access to the
+		// payload attributes, empty if the interceptor does not access the
+		// payload.
+		PayloadAccess string
+		// ResultAccess is the name of the interface giving access to the
+		// result attributes, empty if the interceptor does not access the
+		// result.
+		ResultAccess string
+		// ReadPayload lists the payload attributes read by the interceptor.
+		ReadPayload []*AttributeAccessorData
+		// WritePayload lists the payload attributes written by the
+		// interceptor.
+		WritePayload []*AttributeAccessorData
+		// ReadResult lists the result attributes read by the interceptor.
+		ReadResult []*AttributeAccessorData
+		// WriteResult lists the result attributes written by the
+		// interceptor.
+		WriteResult []*AttributeAccessorData
+		// Methods lists the methods the interceptor applies to.
+		Methods []*InterceptorMethodData
+	}
+
+	// InterceptorMethodData describes a method an interceptor applies to.
+	InterceptorMethodData struct {
+		// MethodName is the name of the method.
+		MethodName string
+		// PayloadAccess is the name of the struct implementing the
+		// interceptor payload access for the method.
+		PayloadAccess string
+		// PayloadRef is a reference to the method payload type.
+		PayloadRef string
+		// PayloadCast is the expression that retrieves the payload from the
+		// endpoint request.
+		PayloadCast string
+		// ResultAccess is the name of the struct implementing the
+		// interceptor result access for the method.
+		ResultAccess string
+		// ResultRef is a reference to the method result type.
+		ResultRef string
+		// ResultCast is the expression that retrieves the result from the
+		// endpoint response.
+		ResultCast string
+	}
+
+	// AttributeAccessorData describes an attribute accessed by an
+	// interceptor.
+	AttributeAccessorData struct {
+		// Name is the name of the attribute.
+		Name string
+		// VarName is the name of the accessor methods.
+		VarName string
+		// FieldName is the name of the struct field holding the attribute.
+		FieldName string
+		// TypeRef is a reference to the attribute type.
+		TypeRef string
+	}
+
 	// MethodData describes a single service method.
 	MethodData struct {
 		// Name is the method name.
@@ -175,6 +314,11 @@ type (
 		// Schemes contains the security schemes types used by the
 		// method.
 		Schemes SchemesData
+		// Policy contains the authorization policy of the method if any.
+		Policy *PolicyData
+		// Pagination contains the data needed to generate the client
+		// iterator of paginated methods if any.
+		Pagination *PaginationData
 		// ViewedResult contains the data required to generate the code handling
 		// views if any.
 		ViewedResult *ViewedResultTypeData
@@ -201,6 +345,12 @@ type (
 		// result and response body reader when SkipResponseBodyEncodeDecode is
 		// used.
 		ResponseStruct string
+		// ServerInterceptors lists the names of the interceptor interfaces
+		// methods invoked around the server side endpoint in order.
+		ServerInterceptors []string
+		// ClientInterceptors lists the names of the interceptor interfaces
+		// methods invoked around the client side endpoint in order.
+		ClientInterceptors []string
 	}
 
 	// StreamData is the data used to generate client and server interfaces that
@@ -248,6 +398,39 @@ type (
 		Scopes []string
 	}
 
+	// PolicyData contains the data describing the authorization policy of
+	// a method.
+	PolicyData struct {
+		// Roles lists the roles authorized to call the method.
+		Roles []string
+		// Permissions lists the permissions required to call the method.
+		Permissions []string
+		// Rule is the authorization expression if any.
+		Rule string
+	}
+
+	// PaginationData contains the data needed to generate the client
+	// iterator of a paginated method.
+	PaginationData struct {
+		// IteratorName is the name of the iterator type.
+		IteratorName string
+		// ItemRef is a reference to the type of the items.
+		ItemRef string
+		// CursorRef is a reference to the type of the cursors.
+		CursorRef string
+		// CursorField is the name of the payload cursor field.
+		CursorField string
+		// CursorPointer is true if the payload cursor field is a pointer.
+		CursorPointer bool
+		// NextField is the name of the result next cursor field.
+		NextField string
+		// NextPointer is true if the result next cursor field is a
+		// pointer.
+		NextPointer bool
+		// ItemsField is the name of the result items field.
+		ItemsField string
+	}
+
 	// UserTypeData contains the data describing a user-defined type.
 	UserTypeData struct {
 		// Name is the type name.
@@ -433,15 +616,37 @@ type (
 // Get retrieves the data for the service with the given name computing it if
 // needed. It returns nil if there is no service with the given name.
 func (d ServicesData) Get(name string) *Data {
-	if data, ok := d[name]; ok {
+	servicesMu.Lock()
+	data, ok := d[name]
+	servicesMu.Unlock()
+	if ok {
 		return data
 	}
 	service := expr.Root.Service(name)
 	if service == nil {
 		return nil
 	}
-	d[name] = d.analyze(service)
-	return d[name]
+	data = d.Analyze(service)
+	data.InitExamples()
+	return data
+}
+
+// Analyze retrieves the data for the given service computing it if needed like
+// Get except that it does not generate the examples of the method payloads and
+// results, see InitExamples. Analyze may be called concurrently for different
+// services.
+func (d ServicesData) Analyze(service *expr.ServiceExpr) *Data {
+	servicesMu.Lock()
+	data, ok := d[service.Name]
+	servicesMu.Unlock()
+	if ok {
+		return data
+	}
+	data = d.analyze(service)
+	servicesMu.Lock()
+	defer servicesMu.Unlock()
+	d[service.Name] = data
+	return data
 }
 
 // Method returns the service method data for the method with the given name,
@@ -455,6 +660,30 @@ func (d *Data) Method(name string) *MethodData {
 	return nil
 }
 
+// InitExamples generates the examples of the payloads and results of the
+// service methods. The examples are generated with the random generator shared
+// by the whole design so that their values depend on the order of the calls.
+// InitExamples does nothing if the examples were already generated.
+func (d *Data) InitExamples() {
+	if d.examples {
+		return
+	}
+	d.examples = true
+	gen := expr.Root.API.ExampleGenerator
+	for _, m := range expr.Root.Service(d.Name).Methods {
+		md := d.Method(m.Name)
+		if m.Payload.Type != expr.Empty {
+			md.PayloadEx = m.Payload.Example(gen)
+		}
+		if m.Result.Type != expr.Empty {
+			md.ResultEx = m.Result.Example(gen)
+		}
+		if m.IsStreaming() && m.StreamingPayload.Type != expr.Empty {
+			md.StreamingPayloadEx = m.StreamingPayload.Example(gen)
+		}
+	}
+}
+
 // initUserTypeImports sets the import paths for the user types defined in the
 // service.  User types may be declared in multiple packages when defined with
 // the Meta key "struct:pkg:path".
@@ -610,6 +839,9 @@ func (d ServicesData) analyze(service *expr.ServiceExpr) *Data {
 				recordError(er)
 			}
 		}
+		for _, e := range service.Events {
+			types = append(types, collectTypes(e.Payload, scope, seen)...)
+		}
 
 		// A function to convert raw object type to user type.
 		makeUserType := func(att *expr.AttributeExpr, name, id string) {
@@ -658,8 +890,9 @@ func (d ServicesData) analyze(service *expr.ServiceExpr) *Data {
 	}
 
 	var (
-		methods []*MethodData
-		schemes SchemesData
+		methods     []*MethodData
+		schemes     SchemesData
+		hasPolicies bool
 	)
 	{
 		methods = make([]*MethodData, len(service.Methods))
@@ -669,6 +902,9 @@ func (d ServicesData) analyze(service *expr.ServiceExpr) *Data {
 			for _, s := range m.Schemes {
 				schemes = schemes.Append(s)
 			}
+			if m.Policy != nil {
+				hasPolicies = true
+			}
 			rt, ok := e.Result.Type.(*expr.ResultTypeExpr)
 			if !ok {
 				continue
@@ -733,6 +969,45 @@ func (d ServicesData) analyze(service *expr.ServiceExpr) *Data {
 		}
 	}
 
+	var (
+		redactMethods []*RedactData
+	)
+	{
+		seen := make(map[string]struct{})
+		for _, t := range types {
+			redactMethods = append(redactMethods, collectRedactMethods(&expr.AttributeExpr{Type: t.Type}, scope, seen)...)
+		}
+		for _, t := range errTypes {
+			redactMethods = append(redactMethods, collectRedactMethods(&expr.AttributeExpr{Type: t.Type}, scope, seen)...)
+		}
+		for _, m := range service.Methods {
+			redactMethods = append(redactMethods, collectRedactMethods(m.Payload, scope, seen)...)
+			redactMethods = append(redactMethods, collectRedactMethods(m.StreamingPayload, scope, seen)...)
+			redactMethods = append(redactMethods, collectRedactMethods(m.Result, scope, seen)...)
+		}
+	}
+
+	var (
+		viewedRedactMethods []*RedactData
+	)
+	{
+		for _, t := range projTypes {
+			if !isObjectUserType(t.Type) || !expr.HasSensitive(t.Type.Attribute()) {
+				continue
+			}
+			viewedRedactMethods = append(viewedRedactMethods, &RedactData{
+				VarName: t.VarName,
+				Ref:     t.Ref,
+				Code:    redactCode(t.Type.Attribute(), viewScope, true),
+			})
+		}
+		for _, t := range viewedRTs {
+			if d := viewedRedactData(t, viewScope); d != nil {
+				viewedRedactMethods = append(viewedRedactMethods, d)
+			}
+		}
+	}
+
 	var (
 		desc string
 	)
@@ -744,31 +1019,77 @@ func (d ServicesData) analyze(service *expr.ServiceExpr) *Data {
 	}
 
 	varName := codegen.Goify(service.Name, false)
+	var (
+		events     = make([]*EventData, len(service.Events))
+		eventTypes []*UserTypeData
+		eventInits []*InitData
+	)
+	for i, e := range service.Events {
+		ed, types, init := buildEventData(e, scope, seen)
+		events[i] = ed
+		eventTypes = append(eventTypes, types...)
+		if init != nil {
+			eventInits = append(eventInits, init)
+		}
+	}
+
+	interceptors, serverInterceptors, clientInterceptors := buildInterceptorsData(service, methods, scope)
+
 	data := &Data{
-		Name:               service.Name,
-		Description:        desc,
-		VarName:            varName,
-		PathName:           codegen.SnakeCase(varName),
-		StructName:         codegen.Goify(service.Name, true),
-		PkgName:            pkgName,
-		ViewsPkg:           viewspkg,
-		Methods:            methods,
-		Schemes:            schemes,
-		Scope:              scope,
-		ViewScope:          viewScope,
-		errorTypes:         errTypes,
-		errorInits:         errorInits,
-		userTypes:          types,
-		projectedTypes:     projTypes,
-		viewedUnionMethods: viewedUnionMeths,
-		viewedResultTypes:  viewedRTs,
-		unionValueMethods:  unionMethods,
+		Name:                service.Name,
+		Description:         desc,
+		VarName:             varName,
+		PathName:            codegen.SnakeCase(varName),
+		StructName:          codegen.Goify(service.Name, true),
+		PkgName:             pkgName,
+		ViewsPkg:            viewspkg,
+		Methods:             methods,
+		Events:              events,
+		Interceptors:        interceptors,
+		ServerInterceptors:  serverInterceptors,
+		ClientInterceptors:  clientInterceptors,
+		Schemes:             schemes,
+		HasPolicies:         hasPolicies,
+		DiscoveryTags:       discoveryTags(service),
+		Scope:               scope,
+		ViewScope:           viewScope,
+		errorTypes:          errTypes,
+		errorInits:          errorInits,
+		userTypes:           types,
+		projectedTypes:      projTypes,
+		viewedUnionMethods:  viewedUnionMeths,
+		viewedResultTypes:   viewedRTs,
+		unionValueMethods:   unionMethods,
+		redactMethods:       redactMethods,
+		viewedRedactMethods: viewedRedactMethods,
+		eventTypes:          eventTypes,
+		eventInits:          eventInits,
 	}
-	d[service.Name] = data
 
 	return data
 }
 
+// discoveryTags returns the tags defined with the "discovery:tag" meta of the
+// API and of the given service.
+func discoveryTags(svc *expr.ServiceExpr) []string {
+	var metas []expr.MetaExpr
+	if expr.Root != nil && expr.Root.API != nil {
+		metas = append(metas, expr.Root.API.Meta)
+	}
+	metas = append(metas, svc.Meta)
+	var tags []string
+	seen := make(map[string]struct{})
+	for _, m := range metas {
+		for _, v := range m["discovery:tag"] {
+			if _, ok := seen[v]; !ok {
+				seen[v] = struct{}{}
+				tags = append(tags, v)
+			}
+		}
+	}
+	return tags
+}
+
 // typeContext returns a contextual attribute for service types. Service types
 // are Go types and uses non-pointers to hold attributes having default values.
 func typeContext(pkg string, scope *codegen.NameScope) *codegen.AttributeContext {
@@ -882,6 +1203,212 @@ func buildErrorInitData(er *expr.ErrorExpr, scope *codegen.NameScope) *ErrorInit
 	}
 }
 
+// buildEventData builds the data needed to render the publisher method of the
+// given event. It also returns the event body types and the function that
+// builds the body from the payload if the payload contains objects: the body
+// types define JSON tags so that the events are encoded using the attribute
+// names defined in the design.
+func buildEventData(e *expr.EventExpr, scope *codegen.NameScope, seen map[string]struct{}) (*EventData, []*UserTypeData, *InitData) {
+	var loc *codegen.Location
+	if dt, ok := e.Payload.Type.(expr.UserType); ok {
+		loc = codegen.UserTypeLocation(dt)
+	}
+	data := &EventData{
+		Name:        e.Name,
+		Description: e.Description,
+		FuncName:    "Publish" + codegen.Goify(e.Name, true),
+		Topic:       e.Topic,
+		PayloadRef:  scope.GoFullTypeRef(e.Payload, loc.PackageName()),
+	}
+	if !hasObject(e.Body.Type) {
+		return data, nil, nil
+	}
+	addEventBodyTags(e.Body, make(map[string]struct{}))
+	types := collectTypes(e.Body, scope, seen)
+	for _, t := range types {
+		if t.Type == e.Body.Type {
+			t.Description = fmt.Sprintf("%s is the body of the %q event.", t.VarName, e.Name)
+		} else if t.Description == "" {
+			t.Description = fmt.Sprintf("%s is used to define fields on event body types.", t.VarName)
+		}
+	}
+	ctx := typeContext("", scope)
+	code, helpers, err := codegen.GoTransform(e.Payload, e.Body, "v", "body", ctx, ctx, "marshal", true)
+	if err != nil {
+		panic(err) // bug
+	}
+	data.BodyInit = "new" + codegen.Goify(e.Name, true) + "EventBody"
+	init := &InitData{
+		Name:          data.BodyInit,
+		Description:   fmt.Sprintf("%s builds the body of the %q event from its payload.", data.BodyInit, e.Name),
+		Args:          []*InitArgData{{Name: "v", Ref: data.PayloadRef}},
+		ReturnTypeRef: scope.GoFullTypeRef(e.Body, ""),
+		Code:          code + "\n\treturn body",
+		Helpers:       helpers,
+	}
+	return data, types, init
+}
+
+// buildInterceptorsData builds the data of the interceptors applied to the
+// service methods. It returns all the interceptors as well as the interceptors
+// applied server side and client side.
+func buildInterceptorsData(service *expr.ServiceExpr, methods []*MethodData, scope *codegen.NameScope) (all, server, client []*InterceptorData) {
+	byName := make(map[string]*InterceptorData)
+	get := func(i *expr.InterceptorExpr) *InterceptorData {
+		if d, ok := byName[i.Name]; ok {
+			return d
+		}
+		name := codegen.Goify(i.Name, true)
+		d := &InterceptorData{
+			Name:        i.Name,
+			Description: i.Description,
+			VarName:     name,
+			InfoStruct:  name + "Info",
+		}
+		if i.HasPayloadAccess() {
+			d.PayloadAccess = name + "Payload"
+		}
+		if i.HasResultAccess() {
+			d.ResultAccess = name + "Result"
+		}
+		byName[i.Name] = d
+		all = append(all, d)
+		return d
+	}
+	appendUnique := func(l []*InterceptorData, d *InterceptorData) []*InterceptorData {
+		for _, e := range l {
+			if e == d {
+				return l
+			}
+		}
+		return append(l, d)
+	}
+	for idx, m := range service.Methods {
+		md := methods[idx]
+		applied := make(map[string]struct{})
+		apply := func(i *expr.InterceptorExpr) *InterceptorData {
+			d := get(i)
+			if _, ok := applied[i.Name]; ok {
+				return d
+			}
+			applied[i.Name] = struct{}{}
+			d.Methods = append(d.Methods, buildInterceptorMethodData(i, d, m, md, scope))
+			return d
+		}
+		for _, i := range m.ServerInterceptorExprs() {
+			d := apply(i)
+			server = appendUnique(server, d)
+			md.ServerInterceptors = append(md.ServerInterceptors, d.VarName)
+		}
+		for _, i := range m.ClientInterceptorExprs() {
+			d := apply(i)
+			client = appendUnique(client, d)
+			md.ClientInterceptors = append(md.ClientInterceptors, d.VarName)
+		}
+	}
+	return
+}
+
+// buildInterceptorMethodData builds the data needed to give the interceptor
+// typed access to the payload and result of the given method. It also
+// initializes the interceptor accessors the first time it is called for the
+// interceptor.
+func buildInterceptorMethodData(i *expr.InterceptorExpr, d *InterceptorData, m *expr.MethodExpr, md *MethodData, scope *codegen.NameScope) *InterceptorMethodData {
+	prefix := codegen.Goify(i.Name, false) + md.VarName
+	imd := &InterceptorMethodData{MethodName: m.Name}
+	first := len(d.Methods) == 0
+	accessors := func(parent, atts *expr.AttributeExpr) []*AttributeAccessorData {
+		if atts == nil {
+			return nil
+		}
+		var res []*AttributeAccessorData
+		for _, nat := range *expr.AsObject(atts.Type) {
+			att := expr.AsObject(parent.Type).Attribute(nat.Name)
+			var loc *codegen.Location
+			if dt, ok := att.Type.(expr.UserType); ok {
+				loc = codegen.UserTypeLocation(dt)
+			}
+			ref := scope.GoFullTypeRef(att, loc.PackageName())
+			if parent.IsPrimitivePointer(nat.Name, true) {
+				ref = "*" + ref
+			}
+			res = append(res, &AttributeAccessorData{
+				Name:      nat.Name,
+				VarName:   codegen.Goify(nat.Name, true),
+				FieldName: codegen.GoifyAtt(att, nat.Name, true),
+				TypeRef:   ref,
+			})
+		}
+		return res
+	}
+	if d.PayloadAccess != "" {
+		imd.PayloadAccess = prefix + "Payload"
+		imd.PayloadRef = md.PayloadRef
+		imd.PayloadCast = fmt.Sprintf("info.RawPayload.(%s)", md.PayloadRef)
+		if md.SkipRequestBodyEncodeDecode {
+			imd.PayloadCast = fmt.Sprintf("info.RawPayload.(*%s).Payload", md.RequestStruct)
+		}
+		if first {
+			d.ReadPayload = accessors(m.Payload, i.ReadPayload)
+			d.WritePayload = accessors(m.Payload, i.WritePayload)
+		}
+	}
+	if d.ResultAccess != "" {
+		imd.ResultAccess = prefix + "Result"
+		imd.ResultRef = md.ResultRef
+		imd.ResultCast = fmt.Sprintf("res.(%s)", md.ResultRef)
+		if md.SkipResponseBodyEncodeDecode {
+			imd.ResultCast = fmt.Sprintf("res.(*%s).Result", md.ResponseStruct)
+		}
+		if first {
+			d.ReadResult = accessors(m.Result, i.ReadResult)
+			d.WriteResult = accessors(m.Result, i.WriteResult)
+		}
+	}
+	return imd
+}
+
+// addEventBodyTags adds JSON tags to the attributes of the objects contained in
+// the given event body recursively unless they already define one.
+func addEventBodyTags(att *expr.AttributeExpr, seen map[string]struct{}) {
+	switch dt := att.Type.(type) {
+	case expr.UserType:
+		if _, ok := seen[dt.ID()]; ok {
+			return
+		}
+		seen[dt.ID()] = struct{}{}
+		addEventBodyTags(dt.Attribute(), seen)
+	case *expr.Object:
+		for _, nat := range *dt {
+			if _, ok := nat.Attribute.Meta["struct:tag:json"]; !ok {
+				tag := nat.Name
+				if !att.IsRequired(nat.Name) && !att.HasDefaultValue(nat.Name) {
+					tag += ",omitempty"
+				}
+				nat.Attribute.AddMeta("struct:tag:json", tag)
+			}
+			addEventBodyTags(nat.Attribute, seen)
+		}
+	case *expr.Array:
+		addEventBodyTags(dt.ElemType, seen)
+	case *expr.Map:
+		addEventBodyTags(dt.KeyType, seen)
+		addEventBodyTags(dt.ElemType, seen)
+	}
+}
+
+// hasObject returns true if the given data type is or contains an object.
+func hasObject(dt expr.DataType) bool {
+	switch actual := dt.(type) {
+	case *expr.Array:
+		return hasObject(actual.ElemType.Type)
+	case *expr.Map:
+		return hasObject(actual.KeyType.Type) || hasObject(actual.ElemType.Type)
+	default:
+		return expr.IsObject(dt)
+	}
+}
+
 // buildMethodData creates the data needed to render the given endpoint. It
 // records the user types needed by the service definition in userTypes.
 func buildMethodData(m *expr.MethodExpr, scope *codegen.NameScope) *MethodData {
@@ -893,13 +1420,11 @@ func buildMethodData(m *expr.MethodExpr, scope *codegen.NameScope) *MethodData {
 		payloadDef  string
 		payloadRef  string
 		payloadDesc string
-		payloadEx   any
 		rname       string
 		resultLoc   *codegen.Location
 		resultDef   string
 		resultRef   string
 		resultDesc  string
-		resultEx    any
 		errors      []*ErrorInitData
 		errorLocs   map[string]*codegen.Location
 		reqs        RequirementsData
@@ -922,7 +1447,6 @@ func buildMethodData(m *expr.MethodExpr, scope *codegen.NameScope) *MethodData {
 			payloadDesc = fmt.Sprintf("%s is the payload type of the %s service %s method.",
 				payloadName, m.Service.Name, m.Name)
 		}
-		payloadEx = m.Payload.Example(expr.Root.API.ExampleGenerator)
 	}
 	if m.Result.Type != expr.Empty {
 		rname = scope.GoTypeName(m.Result)
@@ -936,7 +1460,6 @@ func buildMethodData(m *expr.MethodExpr, scope *codegen.NameScope) *MethodData {
 			resultDesc = fmt.Sprintf("%s is the result type of the %s service %s method.",
 				rname, m.Service.Name, m.Name)
 		}
-		resultEx = m.Result.Example(expr.Root.API.ExampleGenerator)
 	}
 	if len(m.Errors) > 0 {
 		errors = make([]*ErrorInitData, len(m.Errors))
@@ -955,6 +1478,14 @@ func buildMethodData(m *expr.MethodExpr, scope *codegen.NameScope) *MethodData {
 		}
 		reqs = append(reqs, &RequirementData{Schemes: rs, Scopes: req.Scopes})
 	}
+	var policy *PolicyData
+	if p := m.EffectivePolicy(); p != nil {
+		policy = &PolicyData{Roles: p.Roles, Permissions: p.Permissions, Rule: p.Rule}
+	}
+	var pagination *PaginationData
+	if pg := m.Pagination; pg != nil {
+		pagination = buildPaginationData(pg, vname, scope)
+	}
 	var httpMet *expr.HTTPEndpointExpr
 	if httpSvc := expr.Root.HTTPService(m.Service.Name); httpSvc != nil {
 		httpMet = httpSvc.Endpoint(m.Name)
@@ -968,18 +1499,18 @@ func buildMethodData(m *expr.MethodExpr, scope *codegen.NameScope) *MethodData {
 		PayloadDef:                   payloadDef,
 		PayloadRef:                   payloadRef,
 		PayloadDesc:                  payloadDesc,
-		PayloadEx:                    payloadEx,
 		PayloadDefault:               m.Payload.DefaultValue,
 		Result:                       rname,
 		ResultLoc:                    resultLoc,
 		ResultDef:                    resultDef,
 		ResultRef:                    resultRef,
 		ResultDesc:                   resultDesc,
-		ResultEx:                     resultEx,
 		Errors:                       errors,
 		ErrorLocs:                    errorLocs,
 		Requirements:                 reqs,
 		Schemes:                      schemes,
+		Policy:                       policy,
+		Pagination:                   pagination,
 		StreamKind:                   m.Stream,
 		SkipRequestBodyEncodeDecode:  httpMet != nil && httpMet.SkipRequestBodyEncodeDecode,
 		SkipResponseBodyEncodeDecode: httpMet != nil && httpMet.SkipResponseBodyEncodeDecode,
@@ -992,6 +1523,34 @@ func buildMethodData(m *expr.MethodExpr, scope *codegen.NameScope) *MethodData {
 	return data
 }
 
+// buildPaginationData builds the data needed to generate the client iterator
+// of the paginated method with the given Go name.
+func buildPaginationData(pg *expr.PaginationExpr, vname string, scope *codegen.NameScope) *PaginationData {
+	typeRef := func(att *expr.AttributeExpr) string {
+		var loc *codegen.Location
+		if dt, ok := att.Type.(expr.UserType); ok {
+			loc = codegen.UserTypeLocation(dt)
+		}
+		return scope.GoFullTypeRef(att, loc.PackageName())
+	}
+	var (
+		m      = pg.Method
+		cursor = m.Payload.Find(pg.Cursor)
+		next   = m.Result.Find(pg.NextCursor)
+		items  = m.Result.Find(pg.Items)
+	)
+	return &PaginationData{
+		IteratorName:  vname + "Iterator",
+		ItemRef:       typeRef(expr.AsArray(items.Type).ElemType),
+		CursorRef:     typeRef(cursor),
+		CursorField:   codegen.GoifyAtt(cursor, pg.Cursor, true),
+		CursorPointer: m.Payload.IsPrimitivePointer(pg.Cursor, true),
+		NextField:     codegen.GoifyAtt(next, pg.NextCursor, true),
+		NextPointer:   m.Result.IsPrimitivePointer(pg.NextCursor, true),
+		ItemsField:    codegen.GoifyAtt(items, pg.Items, true),
+	}
+}
+
 // initStreamData initializes the streaming payload data structures and methods.
 func initStreamData(data *MethodData, m *expr.MethodExpr, vname, rname, resultRef string, scope *codegen.NameScope) {
 	var (
@@ -999,7 +1558,6 @@ func initStreamData(data *MethodData, m *expr.MethodExpr, vname, rname, resultRe
 		spayloadRef  string
 		spayloadDef  string
 		spayloadDesc string
-		spayloadEx   any
 	)
 	if m.StreamingPayload.Type != expr.Empty {
 		spayloadName = scope.GoTypeName(m.StreamingPayload)
@@ -1012,7 +1570,6 @@ func initStreamData(data *MethodData, m *expr.MethodExpr, vname, rname, resultRe
 			spayloadDesc = fmt.Sprintf("%s is the streaming payload type of the %s service %s method.",
 				spayloadName, m.Service.Name, m.Name)
 		}
-		spayloadEx = m.StreamingPayload.Example(expr.Root.API.ExampleGenerator)
 	}
 	svrStream := &StreamData{
 		Interface:      vname + "ServerStream",
@@ -1064,7 +1621,6 @@ func initStreamData(data *MethodData, m *expr.MethodExpr, vname, rname, resultRe
 	data.StreamingPayloadDef = spayloadDef
 	data.StreamingPayloadRef = spayloadDef
 	data.StreamingPayloadDesc = spayloadDesc
-	data.StreamingPayloadEx = spayloadEx
 }
 
 // BuildSchemeData builds the scheme data for the given scheme and method expr.
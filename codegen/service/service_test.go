@@ -24,6 +24,8 @@ func TestService(t *testing.T) {
 		{"service-union", testdata.UnionMethodDSL, testdata.UnionMethod},
 		{"service-multi-union", testdata.MultiUnionMethodDSL, testdata.MultiUnionMethod},
 		{"service-no-payload-no-result", testdata.EmptyMethodDSL, testdata.EmptyMethod},
+		{"service-discovery-tags", testdata.DiscoveryTagsDSL, testdata.DiscoveryTags},
+		{"service-policy", testdata.PolicyMethodDSL, testdata.PolicyMethod},
 		{"service-payload-no-result", testdata.EmptyResultMethodDSL, testdata.EmptyResultMethod},
 		{"service-no-payload-result", testdata.EmptyPayloadMethodDSL, testdata.EmptyPayloadMethod},
 		{"service-payload-result-with-default", testdata.WithDefaultDSL, testdata.WithDefault},
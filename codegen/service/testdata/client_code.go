@@ -283,3 +283,63 @@ func (c *Client) BidirectionalStreamingNoPayloadMethod(ctx context.Context) (res
 	return ires.(BidirectionalStreamingNoPayloadMethodClientStream), nil
 }
 `
+
+const PaginatedMethodClient = `// Client is the "Paginated" service client.
+type Client struct {
+	ListEndpoint goa.Endpoint
+}
+
+// NewClient initializes a "Paginated" service client given the endpoints.
+func NewClient(list goa.Endpoint) *Client {
+	return &Client{
+		ListEndpoint: list,
+	}
+}
+
+// List calls the "List" endpoint of the "Paginated" service.
+func (c *Client) List(ctx context.Context, p *ListPayload) (res *ListResult, err error) {
+	var ires any
+	ires, err = c.ListEndpoint(ctx, p)
+	if err != nil {
+		return
+	}
+	return ires.(*ListResult), nil
+}
+
+// ListIterator iterates over the items returned by the "List" endpoint of the
+// "Paginated" service.
+type ListIterator = goa.Iterator[*Item, string]
+
+// ListIter returns an iterator over the items returned by the "List" endpoint
+// of the "Paginated" service. The iterator retrieves the pages as needed
+// starting with the page identified by p and stops after maxItems items if
+// maxItems is greater than zero.
+func (c *Client) ListIter(ctx context.Context, p *ListPayload, maxItems int) *ListIterator {
+	var start string
+	if p.PageToken != nil {
+		start = *p.PageToken
+	}
+	return goa.NewIterator(ctx, start, func(ctx context.Context, cursor string) (items []*Item, next string, err error) {
+		pp := *p
+		if cursor != start {
+			pp.PageToken = &cursor
+		}
+		res, err := c.List(ctx, &pp)
+		if err != nil {
+			return
+		}
+		if res.NextPageToken != nil {
+			next = *res.NextPageToken
+		}
+		return res.Items, next, nil
+	}, maxItems)
+}
+
+// ListCollect returns all the items returned by the "List" endpoint of the
+// "Paginated" service retrieving the pages starting with the page identified
+// by p. It returns the items retrieved so far and goa.ErrMaxItems if there are
+// more than maxItems items and maxItems is greater than zero.
+func (c *Client) ListCollect(ctx context.Context, p *ListPayload, maxItems int) ([]*Item, error) {
+	return goa.Collect(c.ListIter(ctx, p, maxItems))
+}
+`
@@ -218,3 +218,24 @@ var ConvertAliasDSL = func() {
 		})
 	})
 }
+
+var ConvertValueDSL = func() {
+	var ValueField = Type("ValueField", func() {
+		Attribute("Name", String)
+		Required("Name")
+	})
+
+	var ValueType = Type("ValueType", func() {
+		ConvertTo(ValueT{})
+		Attribute("Value", ValueField)
+		Attribute("Values", ArrayOf(ValueField))
+		Attribute("ValueMap", MapOf(String, ValueField))
+		Attribute("Pointer", ValueField)
+	})
+
+	Service("Service", func() {
+		Method("Method", func() {
+			Payload(ValueType)
+		})
+	})
+}
@@ -225,3 +225,47 @@ func (t *StringType) CreateFromConvertModel(v *external.ConvertModel) {
 	*t = *temp
 }
 `
+
+var ConvertValueCode = `// ConvertToValueT creates an instance of ValueT initialized from t.
+func (t *ValueType) ConvertToValueT() *testdata.ValueT {
+	v := &testdata.ValueT{}
+	if t.Value != nil {
+		v.Value = *transformValueFieldToTestdataValueFieldT(t.Value)
+	}
+	if t.Values != nil {
+		v.Values = make([]testdata.ValueFieldT, len(t.Values))
+		for i, val := range t.Values {
+			if val != nil {
+				v.Values[i] = *transformValueFieldToTestdataValueFieldT(val)
+			}
+		}
+	}
+	if t.ValueMap != nil {
+		v.ValueMap = make(map[string]testdata.ValueFieldT, len(t.ValueMap))
+		for key, val := range t.ValueMap {
+			tk := key
+			if val != nil {
+				v.ValueMap[tk] = *transformValueFieldToTestdataValueFieldT(val)
+			}
+		}
+	}
+	if t.Pointer != nil {
+		v.Pointer = transformValueFieldToTestdataValueFieldT(t.Pointer)
+	}
+	return v
+}
+`
+
+var ConvertValueHelperCode = `// transformValueFieldToTestdataValueFieldT builds a value of type
+// *testdata.ValueFieldT from a value of type *ValueField.
+func transformValueFieldToTestdataValueFieldT(v *ValueField) *testdata.ValueFieldT {
+	if v == nil {
+		return nil
+	}
+	res := &testdata.ValueFieldT{
+		Name: v.Name,
+	}
+
+	return res
+}
+`
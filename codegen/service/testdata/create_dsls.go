@@ -263,3 +263,24 @@ var MixedCaseDSL = func() {
 		})
 	})
 }
+
+var CreateValueDSL = func() {
+	var ValueField = Type("ValueField", func() {
+		Attribute("Name", String)
+		Required("Name")
+	})
+
+	var ValueType = Type("ValueType", func() {
+		CreateFrom(ValueT{})
+		Attribute("Value", ValueField)
+		Attribute("Values", ArrayOf(ValueField))
+		Attribute("ValueMap", MapOf(String, ValueField))
+		Attribute("Pointer", ValueField)
+	})
+
+	Service("Service", func() {
+		Method("Method", func() {
+			Payload(ValueType)
+		})
+	})
+}
@@ -171,3 +171,38 @@ func (t *StringType) CreateFromMixedCaseModel(v *external.MixedCaseModel) {
 	*t = *temp
 }
 `
+
+var CreateValueCode = `// CreateFromValueT initializes t from the fields of v
+func (t *ValueType) CreateFromValueT(v *testdata.ValueT) {
+	temp := &ValueType{}
+	temp.Value = transformTestdataValueFieldTToValueField(&v.Value)
+	if v.Values != nil {
+		temp.Values = make([]*ValueField, len(v.Values))
+		for i, val := range v.Values {
+			temp.Values[i] = transformTestdataValueFieldTToValueField(&val)
+		}
+	}
+	if v.ValueMap != nil {
+		temp.ValueMap = make(map[string]*ValueField, len(v.ValueMap))
+		for key, val := range v.ValueMap {
+			tk := key
+			temp.ValueMap[tk] = transformTestdataValueFieldTToValueField(&val)
+		}
+	}
+	if v.Pointer != nil {
+		temp.Pointer = transformTestdataValueFieldTToValueField(v.Pointer)
+	}
+	*t = *temp
+}
+`
+
+var CreateValueHelperCode = `// transformTestdataValueFieldTToValueField builds a value of type *ValueField
+// from a value of type *testdata.ValueFieldT.
+func transformTestdataValueFieldTToValueField(v *testdata.ValueFieldT) *ValueField {
+	res := &ValueField{
+		Name: v.Name,
+	}
+
+	return res
+}
+`
@@ -513,3 +513,62 @@ func NewBidirectionalStreamingNoPayloadMethodEndpoint(s Service) goa.Endpoint {
 	}
 }
 `
+
+const PolicyEndpoint = `// Endpoints wraps the "PolicyEndpoint" service endpoints.
+type Endpoints struct {
+	A goa.Endpoint
+	B goa.Endpoint
+}
+
+// NewEndpoints wraps the methods of the "PolicyEndpoint" service with
+// endpoints.
+func NewEndpoints(s Service) *Endpoints {
+	// Casting service to Authorizer interface
+	authz := s.(Authorizer)
+	return &Endpoints{
+		A: NewAEndpoint(s, authz.Authorize),
+		B: NewBEndpoint(s, authz.Authorize),
+	}
+}
+
+// Use applies the given middleware to all the "PolicyEndpoint" service
+// endpoints.
+func (e *Endpoints) Use(m func(goa.Endpoint) goa.Endpoint) {
+	e.A = m(e.A)
+	e.B = m(e.B)
+}
+
+// NewAEndpoint returns an endpoint function that calls the method "A" of
+// service "PolicyEndpoint".
+func NewAEndpoint(s Service, authorizeFn security.AuthorizeFunc) goa.Endpoint {
+	return func(ctx context.Context, req any) (any, error) {
+		p := req.(*AType)
+		policy := security.Policy{
+			Service:     "PolicyEndpoint",
+			Method:      "A",
+			Permissions: []string{"a:read", "a:write"},
+			Rule:        "input.principal.subject == input.payload.owner",
+		}
+		if err := authorizeFn(ctx, &policy, p); err != nil {
+			return nil, err
+		}
+		return nil, s.A(ctx, p)
+	}
+}
+
+// NewBEndpoint returns an endpoint function that calls the method "B" of
+// service "PolicyEndpoint".
+func NewBEndpoint(s Service, authorizeFn security.AuthorizeFunc) goa.Endpoint {
+	return func(ctx context.Context, req any) (any, error) {
+		policy := security.Policy{
+			Service: "PolicyEndpoint",
+			Method:  "B",
+			Roles:   []string{"admin", "operator"},
+		}
+		if err := authorizeFn(ctx, &policy, nil); err != nil {
+			return nil, err
+		}
+		return nil, s.B(ctx)
+	}
+}
+`
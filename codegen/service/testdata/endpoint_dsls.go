@@ -65,6 +65,25 @@ var WithResultEndpointDSL = func() {
 	})
 }
 
+var PaginatedEndpointDSL = func() {
+	var Item = Type("Item", func() {
+		Attribute("name", String)
+	})
+	Service("Paginated", func() {
+		Method("List", func() {
+			Payload(func() {
+				Attribute("page_token", String)
+				Attribute("page_size", Int)
+			})
+			Result(func() {
+				Attribute("items", ArrayOf(Item))
+				Attribute("next_page_token", String)
+			})
+			Paginate("page_token", "next_page_token", "items")
+		})
+	})
+}
+
 var WithResultMultipleViewsEndpointDSL = func() {
 	var ViewType = ResultType("application/vnd.withresult.multiple.views", func() {
 		TypeName("Viewtype")
@@ -181,3 +200,23 @@ var BidirectionalStreamingEndpointDSL = func() {
 		})
 	})
 }
+
+var PolicyEndpointDSL = func() {
+	var AType = Type("AType", func() {
+		Attribute("owner", String)
+	})
+	Service("PolicyEndpoint", func() {
+		Policy(func() {
+			Roles("admin", "operator")
+		})
+		Method("A", func() {
+			Payload(AType)
+			Policy(func() {
+				Permissions("a:read", "a:write")
+				Rule("input.principal.subject == input.payload.owner")
+			})
+		})
+		Method("B", func() {
+		})
+	})
+}
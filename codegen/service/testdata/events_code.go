@@ -0,0 +1,155 @@
+package testdata
+
+const EventsCode = `// Publisher publishes the events of the inventory service.
+type Publisher interface {
+	// PublishBottleCreated publishes the "BottleCreated" event to the "inventory.bottles.created" topic.
+	// Published when a bottle is added to the inventory.
+	PublishBottleCreated(context.Context, *Bottle) error
+	// PublishBottleRemoved publishes the "bottle_removed" event to the "inventory.bottle_removed" topic.
+	PublishBottleRemoved(context.Context, string) error
+}
+
+// publisher implements Publisher.
+type publisher struct {
+	p *events.Publisher
+}
+
+// NewPublisher returns a Publisher that encodes and sends the inventory
+// service events with p.
+func NewPublisher(p *events.Publisher) Publisher {
+	return &publisher{p}
+}
+
+// PublishBottleCreated publishes the "BottleCreated" event.
+func (p *publisher) PublishBottleCreated(ctx context.Context, ev *Bottle) error {
+	return p.p.Publish(ctx, "inventory.bottles.created", "BottleCreated", newBottleCreatedEventBody(ev))
+}
+
+// PublishBottleRemoved publishes the "bottle_removed" event.
+func (p *publisher) PublishBottleRemoved(ctx context.Context, ev string) error {
+	return p.p.Publish(ctx, "inventory.bottle_removed", "bottle_removed", ev)
+}
+
+// BottleCreatedEventBody is the body of the "BottleCreated" event.
+type BottleCreatedEventBody struct {
+	ID   string  ` + "`" + `json:"id"` + "`" + `
+	Name *string ` + "`" + `json:"name,omitempty"` + "`" + `
+}
+
+// newBottleCreatedEventBody builds the body of the "BottleCreated" event from
+// its payload.
+func newBottleCreatedEventBody(v *Bottle) *BottleCreatedEventBody {
+	body := &BottleCreatedEventBody{
+		ID:   v.ID,
+		Name: v.Name,
+	}
+	return body
+}
+`
+
+const EventsPkgPathCode = `// Publisher publishes the events of the EventsPkgPath service.
+type Publisher interface {
+	// PublishBottleCreated publishes the "BottleCreated" event to the "EventsPkgPath.BottleCreated" topic.
+	PublishBottleCreated(context.Context, []*types.Bottle) error
+}
+
+// publisher implements Publisher.
+type publisher struct {
+	p *events.Publisher
+}
+
+// NewPublisher returns a Publisher that encodes and sends the EventsPkgPath
+// service events with p.
+func NewPublisher(p *events.Publisher) Publisher {
+	return &publisher{p}
+}
+
+// PublishBottleCreated publishes the "BottleCreated" event.
+func (p *publisher) PublishBottleCreated(ctx context.Context, ev []*types.Bottle) error {
+	return p.p.Publish(ctx, "EventsPkgPath.BottleCreated", "BottleCreated", newBottleCreatedEventBody(ev))
+}
+
+// BottleEventBody is used to define fields on event body types.
+type BottleEventBody struct {
+	ID *string ` + "`" + `json:"id,omitempty"` + "`" + `
+}
+
+// newBottleCreatedEventBody builds the body of the "BottleCreated" event from
+// its payload.
+func newBottleCreatedEventBody(v []*types.Bottle) []*BottleEventBody {
+	body := make([]*BottleEventBody, len(v))
+	for i, val := range v {
+		body[i] = marshalTypesBottleToBottleEventBody(val)
+	}
+	return body
+}
+
+// marshalTypesBottleToBottleEventBody builds a value of type *BottleEventBody
+// from a value of type *types.Bottle.
+func marshalTypesBottleToBottleEventBody(v *types.Bottle) *BottleEventBody {
+	res := &BottleEventBody{
+		ID: v.ID,
+	}
+
+	return res
+}
+`
+
+var EventsResultTypeCode = `// Publisher publishes the events of the EventsResultType service.
+type Publisher interface {
+	// PublishBottleCreated publishes the "BottleCreated" event to the "EventsResultType.BottleCreated" topic.
+	PublishBottleCreated(context.Context, *Bottle) error
+}
+
+// publisher implements Publisher.
+type publisher struct {
+	p *events.Publisher
+}
+
+// NewPublisher returns a Publisher that encodes and sends the EventsResultType
+// service events with p.
+func NewPublisher(p *events.Publisher) Publisher {
+	return &publisher{p}
+}
+
+// PublishBottleCreated publishes the "BottleCreated" event.
+func (p *publisher) PublishBottleCreated(ctx context.Context, ev *Bottle) error {
+	return p.p.Publish(ctx, "EventsResultType.BottleCreated", "BottleCreated", newBottleCreatedEventBody(ev))
+}
+
+// BottleCreatedEventBody is the body of the "BottleCreated" event.
+type BottleCreatedEventBody struct {
+	ID     string           ` + "`" + `json:"id"` + "`" + `
+	Winery *WineryEventBody ` + "`" + `json:"winery,omitempty"` + "`" + `
+}
+
+// WineryEventBody is used to define fields on event body types.
+type WineryEventBody struct {
+	Name string ` + "`" + `json:"name"` + "`" + `
+}
+
+// newBottleCreatedEventBody builds the body of the "BottleCreated" event from
+// its payload.
+func newBottleCreatedEventBody(v *Bottle) *BottleCreatedEventBody {
+	body := &BottleCreatedEventBody{
+		ID: v.ID,
+	}
+	if v.Winery != nil {
+		body.Winery = marshalWineryToWineryEventBody(v.Winery)
+	}
+	return body
+}
+
+// marshalWineryToWineryEventBody builds a value of type *WineryEventBody from
+// a value of type *Winery.
+func marshalWineryToWineryEventBody(v *Winery) *WineryEventBody {
+	if v == nil {
+		return nil
+	}
+	res := &WineryEventBody{
+		Name: v.Name,
+	}
+
+	return res
+}
+`
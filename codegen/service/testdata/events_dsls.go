@@ -0,0 +1,49 @@
+package testdata
+
+import (
+	. "goa.design/goa/v3/dsl"
+)
+
+var EventsDSL = func() {
+	var Bottle = Type("Bottle", func() {
+		Attribute("id", String)
+		Attribute("name", String)
+		Required("id")
+	})
+	Service("inventory", func() {
+		Event("BottleCreated", Bottle, func() {
+			Description("Published when a bottle is added to the inventory.")
+			Topic("inventory.bottles.created")
+		})
+		Event("bottle_removed", String)
+	})
+}
+
+var EventsPkgPathDSL = func() {
+	var Bottle = Type("Bottle", func() {
+		Attribute("id", String)
+		Meta("struct:pkg:path", "types")
+	})
+	Service("EventsPkgPath", func() {
+		Event("BottleCreated", ArrayOf(Bottle))
+	})
+}
+
+var EventsResultTypeDSL = func() {
+	var Winery = ResultType("application/vnd.winery", func() {
+		Attribute("name", String)
+		Required("name")
+	})
+	var Bottle = ResultType("application/vnd.bottle", func() {
+		TypeName("Bottle")
+		Attribute("id", String)
+		Attribute("winery", Winery)
+		Required("id")
+	})
+	Service("EventsResultType", func() {
+		Method("show", func() {
+			Result(Bottle)
+		})
+		Event("BottleCreated", Bottle)
+	})
+}
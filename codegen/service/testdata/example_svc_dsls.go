@@ -21,3 +21,24 @@ var ConflictWithGoifiedAPINameAndServiceNamesDSL = func() {
 	var _ = Service("good-by-api", func() {})   // API name + 'api' suffix
 	var _ = Service("good-by-api-1", func() {}) // API name + 'api' suffix + sequential no.
 }
+
+var ResultExampleDSL = func() {
+	var Bottle = Type("Bottle", func() {
+		Attribute("name", String)
+		Attribute("vintage", Int)
+		Example(map[string]any{"name": "Sterling", "vintage": 2014})
+	})
+	var _ = Service("cellar", func() {
+		Method("show", func() {
+			Result(Bottle)
+		})
+		Method("count", func() {
+			Result(Int, func() {
+				Example(42)
+			})
+		})
+		Method("list", func() {
+			Result(ArrayOf(String))
+		})
+	})
+}
@@ -0,0 +1,296 @@
+package testdata
+
+const InterceptorsCode = `// ServerInterceptors lists the interceptors invoked around the server side
+// endpoints of the inventory service. The interceptors must call next to
+// invoke the intercepted endpoint.
+type ServerInterceptors interface {
+	// Cache implements the "Cache" interceptor.
+	// Serves cached results
+	Cache(ctx context.Context, info *CacheInfo, next goa.Endpoint) (any, error)
+	// Tenant implements the "tenant" interceptor.
+	Tenant(ctx context.Context, info *TenantInfo, next goa.Endpoint) (any, error)
+}
+
+// ClientInterceptors lists the interceptors invoked around the client side
+// endpoints of the inventory service. The interceptors must call next to
+// invoke the intercepted endpoint.
+type ClientInterceptors interface {
+	// Tenant implements the "tenant" interceptor.
+	Tenant(ctx context.Context, info *TenantInfo, next goa.Endpoint) (any, error)
+}
+
+// CacheInfo describes the call intercepted by the "Cache" interceptor.
+type CacheInfo struct {
+	// Service is the name of the service.
+	Service string
+	// Method is the name of the intercepted method.
+	Method string
+	// RawPayload is the request given to the intercepted endpoint.
+	RawPayload any
+}
+
+// CachePayload gives the "Cache" interceptor access to the payload attributes.
+type CachePayload interface {
+	ID() string
+}
+
+// Payload returns the accessor of the intercepted method payload.
+func (info *CacheInfo) Payload() CachePayload {
+	switch info.Method {
+	case "get":
+		return &cacheGetPayload{info.RawPayload.(*GetPayload)}
+	case "download":
+		return &cacheDownloadPayload{info.RawPayload.(*DownloadRequestData).Payload}
+	default:
+		return nil
+	}
+}
+
+// CacheResult gives the "Cache" interceptor access to the result attributes.
+type CacheResult interface {
+	SetCachedAt(*string)
+}
+
+// Result returns the accessor of the result returned by the intercepted
+// endpoint.
+func (info *CacheInfo) Result(res any) CacheResult {
+	switch info.Method {
+	case "get":
+		return &cacheGetResult{res.(*Bottle)}
+	case "download":
+		return &cacheDownloadResult{res.(*Bottle)}
+	default:
+		return nil
+	}
+}
+
+// TenantInfo describes the call intercepted by the "tenant" interceptor.
+type TenantInfo struct {
+	// Service is the name of the service.
+	Service string
+	// Method is the name of the intercepted method.
+	Method string
+	// RawPayload is the request given to the intercepted endpoint.
+	RawPayload any
+}
+
+// TenantPayload gives the "tenant" interceptor access to the payload
+// attributes.
+type TenantPayload interface {
+	SetTenant(*string)
+}
+
+// Payload returns the accessor of the intercepted method payload.
+func (info *TenantInfo) Payload() TenantPayload {
+	switch info.Method {
+	case "get":
+		return &tenantGetPayload{info.RawPayload.(*GetPayload)}
+	default:
+		return nil
+	}
+}
+
+// TenantResult gives the "tenant" interceptor access to the result attributes.
+type TenantResult interface {
+	ID() string
+}
+
+// Result returns the accessor of the result returned by the intercepted
+// endpoint.
+func (info *TenantInfo) Result(res any) TenantResult {
+	switch info.Method {
+	case "get":
+		return &tenantGetResult{res.(*Bottle)}
+	default:
+		return nil
+	}
+}
+
+// cacheGetPayload implements CachePayload for the "get" method.
+type cacheGetPayload struct {
+	p *GetPayload
+}
+
+// ID returns the value of the "id" payload attribute.
+func (a *cacheGetPayload) ID() string {
+	return a.p.ID
+}
+
+// cacheGetResult implements CacheResult for the "get" method.
+type cacheGetResult struct {
+	r *Bottle
+}
+
+// SetCachedAt sets the value of the "cached_at" result attribute.
+func (a *cacheGetResult) SetCachedAt(v *string) {
+	a.r.CachedAt = v
+}
+
+// cacheDownloadPayload implements CachePayload for the "download" method.
+type cacheDownloadPayload struct {
+	p *DownloadPayload
+}
+
+// ID returns the value of the "id" payload attribute.
+func (a *cacheDownloadPayload) ID() string {
+	return a.p.ID
+}
+
+// cacheDownloadResult implements CacheResult for the "download" method.
+type cacheDownloadResult struct {
+	r *Bottle
+}
+
+// SetCachedAt sets the value of the "cached_at" result attribute.
+func (a *cacheDownloadResult) SetCachedAt(v *string) {
+	a.r.CachedAt = v
+}
+
+// tenantGetPayload implements TenantPayload for the "get" method.
+type tenantGetPayload struct {
+	p *GetPayload
+}
+
+// SetTenant sets the value of the "tenant" payload attribute.
+func (a *tenantGetPayload) SetTenant(v *string) {
+	a.p.Tenant = v
+}
+
+// tenantGetResult implements TenantResult for the "get" method.
+type tenantGetResult struct {
+	r *Bottle
+}
+
+// ID returns the value of the "id" result attribute.
+func (a *tenantGetResult) ID() string {
+	return a.r.ID
+}
+
+// Intercept wraps the endpoints of the inventory service methods with the
+// server interceptors defined in the design. Intercept must be called before
+// the endpoints are mounted on the transport servers.
+func (e *Endpoints) Intercept(si ServerInterceptors) {
+	e.Get = WrapGetEndpoint(e.Get, si)
+	e.Download = WrapDownloadEndpoint(e.Download, si)
+}
+
+// Intercept wraps the client endpoints of the inventory service methods with
+// the client interceptors defined in the design.
+func (c *Client) Intercept(ci ClientInterceptors) {
+	c.GetEndpoint = WrapGetClientEndpoint(c.GetEndpoint, ci)
+}
+
+// WrapGetEndpoint wraps the "get" endpoint with the server interceptors
+// defined in the design.
+func WrapGetEndpoint(endpoint goa.Endpoint, i ServerInterceptors) goa.Endpoint {
+	endpoint = wrapServerTenant(endpoint, i, "get")
+	endpoint = wrapServerCache(endpoint, i, "get")
+	return endpoint
+}
+
+// WrapGetClientEndpoint wraps the "get" client endpoint with the client
+// interceptors defined in the design.
+func WrapGetClientEndpoint(endpoint goa.Endpoint, i ClientInterceptors) goa.Endpoint {
+	endpoint = wrapClientTenant(endpoint, i, "get")
+	return endpoint
+}
+
+// WrapDownloadEndpoint wraps the "download" endpoint with the server
+// interceptors defined in the design.
+func WrapDownloadEndpoint(endpoint goa.Endpoint, i ServerInterceptors) goa.Endpoint {
+	endpoint = wrapServerCache(endpoint, i, "download")
+	return endpoint
+}
+
+// wrapServerCache applies the "Cache" server interceptor to the endpoint of
+// the given method.
+func wrapServerCache(endpoint goa.Endpoint, i ServerInterceptors, method string) goa.Endpoint {
+	return func(ctx context.Context, req any) (any, error) {
+		info := &CacheInfo{
+			Service:    "inventory",
+			Method:     method,
+			RawPayload: req,
+		}
+		return i.Cache(ctx, info, endpoint)
+	}
+}
+
+// wrapServerTenant applies the "tenant" server interceptor to the endpoint of
+// the given method.
+func wrapServerTenant(endpoint goa.Endpoint, i ServerInterceptors, method string) goa.Endpoint {
+	return func(ctx context.Context, req any) (any, error) {
+		info := &TenantInfo{
+			Service:    "inventory",
+			Method:     method,
+			RawPayload: req,
+		}
+		return i.Tenant(ctx, info, endpoint)
+	}
+}
+
+// wrapClientTenant applies the "tenant" client interceptor to the endpoint of
+// the given method.
+func wrapClientTenant(endpoint goa.Endpoint, i ClientInterceptors, method string) goa.Endpoint {
+	return func(ctx context.Context, req any) (any, error) {
+		info := &TenantInfo{
+			Service:    "inventory",
+			Method:     method,
+			RawPayload: req,
+		}
+		return i.Tenant(ctx, info, endpoint)
+	}
+}
+`
+
+const InterceptorsNoAccessCode = `// ClientInterceptors lists the interceptors invoked around the client side
+// endpoints of the NoAccess service. The interceptors must call next to invoke
+// the intercepted endpoint.
+type ClientInterceptors interface {
+	// Log implements the "Log" interceptor.
+	Log(ctx context.Context, info *LogInfo, next goa.Endpoint) (any, error)
+}
+
+// LogInfo describes the call intercepted by the "Log" interceptor.
+type LogInfo struct {
+	// Service is the name of the service.
+	Service string
+	// Method is the name of the intercepted method.
+	Method string
+	// RawPayload is the request given to the intercepted endpoint.
+	RawPayload any
+}
+
+// Intercept wraps the client endpoints of the NoAccess service methods with
+// the client interceptors defined in the design.
+func (c *Client) Intercept(ci ClientInterceptors) {
+	c.PingEndpoint = WrapPingClientEndpoint(c.PingEndpoint, ci)
+	c.StreamEndpoint = WrapStreamClientEndpoint(c.StreamEndpoint, ci)
+}
+
+// WrapPingClientEndpoint wraps the "ping" client endpoint with the client
+// interceptors defined in the design.
+func WrapPingClientEndpoint(endpoint goa.Endpoint, i ClientInterceptors) goa.Endpoint {
+	endpoint = wrapClientLog(endpoint, i, "ping")
+	return endpoint
+}
+
+// WrapStreamClientEndpoint wraps the "stream" client endpoint with the client
+// interceptors defined in the design.
+func WrapStreamClientEndpoint(endpoint goa.Endpoint, i ClientInterceptors) goa.Endpoint {
+	endpoint = wrapClientLog(endpoint, i, "stream")
+	return endpoint
+}
+
+// wrapClientLog applies the "Log" client interceptor to the endpoint of the
+// given method.
+func wrapClientLog(endpoint goa.Endpoint, i ClientInterceptors, method string) goa.Endpoint {
+	return func(ctx context.Context, req any) (any, error) {
+		info := &LogInfo{
+			Service:    "NoAccess",
+			Method:     method,
+			RawPayload: req,
+		}
+		return i.Log(ctx, info, endpoint)
+	}
+}
+`
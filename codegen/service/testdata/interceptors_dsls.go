@@ -0,0 +1,65 @@
+package testdata
+
+import (
+	. "goa.design/goa/v3/dsl"
+)
+
+var InterceptorsDSL = func() {
+	var Cache = Interceptor("Cache", func() {
+		Description("Serves cached results")
+		ReadPayload(func() {
+			Attribute("id")
+		})
+		WriteResult(func() {
+			Attribute("cached_at")
+		})
+	})
+	var Tenant = Interceptor("tenant", func() {
+		WritePayload(func() {
+			Attribute("tenant")
+		})
+		ReadResult(func() {
+			Attribute("id")
+		})
+	})
+	var Bottle = Type("Bottle", func() {
+		Attribute("id", String)
+		Attribute("cached_at", String)
+		Required("id")
+	})
+	Service("inventory", func() {
+		ServerInterceptor(Cache)
+		Method("get", func() {
+			ServerInterceptor(Tenant)
+			ClientInterceptor(Tenant)
+			Payload(func() {
+				Attribute("id", String)
+				Attribute("tenant", String)
+				Required("id")
+			})
+			Result(Bottle)
+		})
+		Method("download", func() {
+			Payload(func() {
+				Attribute("id", String)
+				Required("id")
+			})
+			Result(Bottle)
+			HTTP(func() {
+				GET("/{id}")
+				SkipRequestBodyEncodeDecode()
+			})
+		})
+	})
+}
+
+var InterceptorsNoAccessDSL = func() {
+	var Log = Interceptor("Log")
+	Service("NoAccess", func() {
+		ClientInterceptor(Log)
+		Method("ping", func() {})
+		Method("stream", func() {
+			StreamingPayload(String)
+		})
+	})
+}
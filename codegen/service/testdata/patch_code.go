@@ -0,0 +1,119 @@
+package testdata
+
+var PatchCode = `// ApplyUpdatePatch applies patch to a copy of target and returns the result
+// once validated. target is left unchanged.
+func ApplyUpdatePatch(target *Account, patch *goa.Patch) (*Account, error) {
+	res, err := goa.ApplyPatch(target, patch)
+	if err != nil {
+		return nil, err
+	}
+	if err := ValidateAccount(res); err != nil {
+		return nil, err
+	}
+	return res, nil
+}
+
+// ApplyRenameChanges applies patch to a copy of target and returns the result
+// once validated. target is left unchanged.
+func ApplyRenameChanges(target *Account, patch *goa.Patch) (*Account, error) {
+	res, err := goa.ApplyPatch(target, patch)
+	if err != nil {
+		return nil, err
+	}
+	if err := ValidateAccount(res); err != nil {
+		return nil, err
+	}
+	return res, nil
+}
+
+// ApplyConfigureSettings applies patch to a copy of target and returns the
+// result once validated. target is left unchanged.
+func ApplyConfigureSettings(target *Settings, patch *goa.Patch) (*Settings, error) {
+	res, err := goa.ApplyPatch(target, patch)
+	if err != nil {
+		return nil, err
+	}
+	return res, nil
+}
+
+// ValidateAccount runs the validations defined on Account.
+func ValidateAccount(v *Account) (err error) {
+	if utf8.RuneCountInString(v.Name) < 2 {
+		err = goa.MergeErrors(err, goa.InvalidLengthError("v.name", v.Name, utf8.RuneCountInString(v.Name), 2, true))
+	}
+	if v.Address != nil {
+		if err2 := ValidateAddress(v.Address); err2 != nil {
+			err = goa.MergeErrors(err, err2)
+		}
+	}
+	return
+}
+
+// ValidateAddress runs the validations defined on Address.
+func ValidateAddress(v *Address) (err error) {
+	if utf8.RuneCountInString(v.Street) < 1 {
+		err = goa.MergeErrors(err, goa.InvalidLengthError("v.street", v.Street, utf8.RuneCountInString(v.Street), 1, true))
+	}
+	return
+}
+`
+
+var FieldMaskCode = `// UpdateUpdateMaskPaths lists the paths of the Account attributes that the
+// "update_mask" field mask of the "Update" method may designate.
+var UpdateUpdateMaskPaths = []string{"name", "address", "address.street", "address.city", "tags"}
+
+// ParseUpdateUpdateMask parses the "update_mask" field mask of the "Update"
+// method payload and checks that it only designates Account attributes.
+func ParseUpdateUpdateMask(mask *string) (*goa.FieldMask, error) {
+	if mask == nil {
+		return &goa.FieldMask{}, nil
+	}
+	return goa.ParseFieldMask(*mask, UpdateUpdateMaskPaths...)
+}
+
+// ApplyUpdateUpdateMask returns a copy of target where the attributes
+// designated by mask are set to their value in update once validated. target
+// is left unchanged.
+func ApplyUpdateUpdateMask(target, update *Account, mask *goa.FieldMask) (*Account, error) {
+	res, err := goa.ApplyFieldMask(target, update, mask)
+	if err != nil {
+		return nil, err
+	}
+	if err := ValidateAccount(res); err != nil {
+		return nil, err
+	}
+	return res, nil
+}
+
+// ReplaceMaskPaths lists the paths of the Account attributes that the "mask"
+// field mask of the "Replace" method may designate.
+var ReplaceMaskPaths = []string{"name", "address", "address.street", "address.city", "tags"}
+
+// ParseReplaceMask parses the "mask" field mask of the "Replace" method
+// payload and checks that it only designates Account attributes.
+func ParseReplaceMask(mask string) (*goa.FieldMask, error) {
+	return goa.ParseFieldMask(mask, ReplaceMaskPaths...)
+}
+
+// ApplyReplaceMask returns a copy of target where the attributes designated by
+// mask are set to their value in update once validated. target is left
+// unchanged.
+func ApplyReplaceMask(target, update *Account, mask *goa.FieldMask) (*Account, error) {
+	res, err := goa.ApplyFieldMask(target, update, mask)
+	if err != nil {
+		return nil, err
+	}
+	if err := ValidateAccount(res); err != nil {
+		return nil, err
+	}
+	return res, nil
+}
+
+// ValidateAccount runs the validations defined on Account.
+func ValidateAccount(v *Account) (err error) {
+	if utf8.RuneCountInString(v.Name) < 2 {
+		err = goa.MergeErrors(err, goa.InvalidLengthError("v.name", v.Name, utf8.RuneCountInString(v.Name), 2, true))
+	}
+	return
+}
+`
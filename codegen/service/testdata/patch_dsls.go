@@ -0,0 +1,77 @@
+package testdata
+
+import . "goa.design/goa/v3/dsl"
+
+var PatchDSL = func() {
+	var Address = Type("Address", func() {
+		Attribute("street", String, func() {
+			MinLength(1)
+		})
+		Required("street")
+	})
+	var Account = Type("Account", func() {
+		Attribute("name", String, func() {
+			MinLength(2)
+		})
+		Attribute("address", Address)
+		Attribute("tags", ArrayOf(String))
+		Required("name")
+	})
+	var Settings = Type("Settings", func() {
+		Attribute("theme", String)
+	})
+	Service("Patch", func() {
+		Method("Update", func() {
+			Payload(func() {
+				Attribute("id", String)
+				Patch("patch", Account)
+				Required("id", "patch")
+			})
+			Result(Account)
+		})
+		Method("Rename", func() {
+			Payload(func() {
+				Patch("changes", Account)
+			})
+			Result(Account)
+		})
+		Method("Configure", func() {
+			Payload(func() {
+				Patch("settings", Settings)
+			})
+		})
+	})
+}
+
+var FieldMaskDSL = func() {
+	var Address = Type("Address", func() {
+		Attribute("street", String)
+		Attribute("city", String)
+	})
+	var Account = Type("Account", func() {
+		Attribute("name", String, func() {
+			MinLength(2)
+		})
+		Attribute("address", Address)
+		Attribute("tags", ArrayOf(String))
+		Required("name")
+	})
+	Service("FieldMask", func() {
+		Method("Update", func() {
+			Payload(func() {
+				Attribute("account", Account)
+				FieldMask("update_mask", Account)
+				Required("account")
+			})
+			Result(Account)
+		})
+		Method("Replace", func() {
+			Payload(func() {
+				Attribute("account", Account)
+				FieldMask("mask", Account)
+				Required("account", "mask")
+			})
+			Result(Account)
+		})
+	})
+}
@@ -0,0 +1,61 @@
+package testdata
+
+var RandomCode = `// RandomItem returns a random Item value that satisfies the validations
+// defined in the design using r as source of randomness.
+func RandomItem(r *rand.Rand) *Item {
+	return randomItem(r, 0)
+}
+
+// randomItem returns a random Item value, depth is the number of enclosing
+// user types.
+func randomItem(r *rand.Rand, depth int) *Item {
+	if depth > random.MaxDepth {
+		return nil
+	}
+	res := &Item{}
+	res.ID = random.Format(r, "uuid")
+	res.Kind = []string{"a", "b"}[r.Intn(2)]
+	res.Count = int32(random.Int64(r, 5, 7))
+	if r.Intn(2) == 0 {
+		v := random.Float64(r, 0.0, 1.0)
+		res.Ratio = &v
+	}
+	res.Codes = make([]Code, random.Length(r, 1, 2))
+	for i0 := range res.Codes {
+		res.Codes[i0] = randomCode(r, depth+1)
+	}
+	if r.Intn(2) == 0 {
+		res.Attrs = make(map[string]uint)
+		for n := random.Length(r, 0, 3); n > 0; n-- {
+			var k0 string
+			k0 = random.String(r, 1, 10)
+			var v0 uint
+			v0 = uint(random.Int64(r, 0, 100))
+			res.Attrs[k0] = v0
+		}
+	}
+	if r.Intn(2) == 0 {
+		res.Child = randomItem(r, depth+1)
+	}
+	if r.Intn(2) == 0 {
+		v := random.String(r, 3, 4)
+		res.Name = &v
+	}
+	res.Limit = int(random.Int64(r, 0, 100))
+	return res
+}
+
+// RandomCode returns a random Code value that satisfies the validations
+// defined in the design using r as source of randomness.
+func RandomCode(r *rand.Rand) Code {
+	return randomCode(r, 0)
+}
+
+// randomCode returns a random Code value, depth is the number of enclosing
+// user types.
+func randomCode(r *rand.Rand, depth int) Code {
+	var res Code
+	res = Code(random.Pattern(r, "^[A-Z]{3}$"))
+	return res
+}
+`
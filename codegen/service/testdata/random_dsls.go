@@ -0,0 +1,54 @@
+package testdata
+
+import . "goa.design/goa/v3/dsl"
+
+var RandomDSL = func() {
+	var Code = Type("Code", String, func() {
+		Pattern("^[A-Z]{3}$")
+	})
+	var Item = Type("Item", func() {
+		Attribute("id", String, func() {
+			Format(FormatUUID)
+		})
+		Attribute("kind", String, func() {
+			Enum("a", "b")
+		})
+		Attribute("count", Int32, func() {
+			Minimum(5)
+			Maximum(7)
+		})
+		Attribute("ratio", Float64, func() {
+			ExclusiveMinimum(0)
+			Maximum(1)
+		})
+		Attribute("codes", ArrayOf(Code), func() {
+			MinLength(1)
+			MaxLength(2)
+		})
+		Attribute("attrs", MapOf(String, UInt))
+		Attribute("child", "Item")
+		Attribute("name", String, func() {
+			MinLength(3)
+			MaxLength(4)
+		})
+		Attribute("limit", Int, func() {
+			Default(3)
+		})
+		Required("id", "kind", "count", "codes")
+	})
+	Service("RandomService", func() {
+		Method("Method", func() {
+			Payload(Item)
+			Result(ArrayOf(Item))
+		})
+	})
+}
+
+var NoRandomDSL = func() {
+	Service("NoRandomService", func() {
+		Method("Method", func() {
+			Payload(String)
+			Result(Int)
+		})
+	})
+}
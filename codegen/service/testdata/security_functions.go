@@ -62,6 +62,9 @@ func NewSecureWithRequiredScopesEndpoint(s Service, authJWTFn security.AuthJWTFu
 		if err != nil {
 			return nil, err
 		}
+		if err := goa.DecodeBody(ctx); err != nil {
+			return nil, err
+		}
 		return nil, s.SecureWithRequiredScopes(ctx, p)
 	}
 }
@@ -91,6 +94,9 @@ func NewSecureWithOptionalRequiredScopesEndpoint(s Service, authBasicFn security
 		if err != nil {
 			return nil, err
 		}
+		if err := goa.DecodeBody(ctx); err != nil {
+			return nil, err
+		}
 		return nil, s.SecureWithOptionalRequiredScopes(ctx, p)
 	}
 }
@@ -116,6 +122,9 @@ func NewSecureWithAPIKeyOverrideEndpoint(s Service, authAPIKeyFn security.AuthAP
 		if err != nil {
 			return nil, err
 		}
+		if err := goa.DecodeBody(ctx); err != nil {
+			return nil, err
+		}
 		return nil, s.SecureWithAPIKeyOverride(ctx, p)
 	}
 }
@@ -148,6 +157,9 @@ func NewSecureWithOAuth2Endpoint(s Service, authOAuth2Fn security.AuthOAuth2Func
 		if err != nil {
 			return nil, err
 		}
+		if err := goa.DecodeBody(ctx); err != nil {
+			return nil, err
+		}
 		return nil, s.SecureWithOAuth2(ctx, p)
 	}
 }
@@ -0,0 +1,233 @@
+package testdata
+
+var RedactCode = `// Redact returns a copy of v where the sensitive attributes are redacted so
+// that it can be logged safely.
+func (v *Account) Redact() any {
+	if v == nil {
+		return v
+	}
+	res := *v
+	if res.Token != nil {
+		r := goa.Redacted
+		res.Token = &r
+	}
+	if res.Owner != nil {
+		res.Owner = res.Owner.Redact().(*User)
+	}
+	if res.Members != nil {
+		l := make([]*User, len(res.Members))
+		for i, e := range res.Members {
+			l[i] = e.Redact().(*User)
+		}
+		res.Members = l
+	}
+	return &res
+}
+
+// Redact returns a copy of v where the sensitive attributes are redacted so
+// that it can be logged safely.
+func (v *GetResult) Redact() any {
+	if v == nil {
+		return v
+	}
+	res := *v
+	res.Secret = goa.Redacted
+	return &res
+}
+
+// Redact returns a copy of v where the sensitive attributes are redacted so
+// that it can be logged safely.
+func (v *SignupPayload) Redact() any {
+	if v == nil {
+		return v
+	}
+	res := *v
+	res.Password = goa.Redacted
+	if res.Ssn != nil {
+		r := goa.Redacted
+		res.Ssn = &r
+	}
+	res.Pin = goa.Redacted
+	return &res
+}
+
+// Redact returns a copy of v where the sensitive attributes are redacted so
+// that it can be logged safely.
+func (v *User) Redact() any {
+	if v == nil {
+		return v
+	}
+	res := *v
+	if res.Email != nil {
+		r := goa.Redacted
+		res.Email = &r
+	}
+	res.ID = nil
+	res.Age = 0
+	res.Tags = nil
+	return &res
+}
+`
+
+var CodecCode = `// UseCodec wraps the "Sensitive" service endpoints so that the encrypted
+// payload attributes are decrypted with c before the service methods are
+// called and the encrypted result attributes are encrypted with c before the
+// results are encoded.
+func (e *Endpoints) UseCodec(c goa.FieldCodec) {
+	e.Signup = wrapSignupServerCodec(e.Signup, c)
+	e.Get = wrapGetServerCodec(e.Get, c)
+}
+
+// UseCodec wraps the "Sensitive" service client endpoints so that the
+// encrypted payload attributes are encrypted with c before the requests are
+// encoded and the encrypted result attributes are decrypted with c once the
+// responses are decoded.
+func (c *Client) UseCodec(fc goa.FieldCodec) {
+	c.SignupEndpoint = wrapSignupClientCodec(c.SignupEndpoint, fc)
+	c.GetEndpoint = wrapGetClientCodec(c.GetEndpoint, fc)
+}
+
+// wrapSignupServerCodec applies the codec to the encrypted attributes of the
+// "signup" method payload and result.
+func wrapSignupServerCodec(endpoint goa.Endpoint, c goa.FieldCodec) goa.Endpoint {
+	return func(ctx context.Context, req any) (any, error) {
+		var err error
+		p := req.(*SignupPayload)
+		if p.Ssn != nil {
+			v, err := c.Decrypt(ctx, "ssn", *p.Ssn)
+			if err != nil {
+				return nil, err
+			}
+			p.Ssn = &v
+		}
+		if p.Pin, err = c.Decrypt(ctx, "pin", p.Pin); err != nil {
+			return nil, err
+		}
+		res, err := endpoint(ctx, req)
+		if err != nil {
+			return nil, err
+		}
+		vres := *res.(*sensitiveviews.Account)
+		r := *vres.Projected
+		if r.Token != nil {
+			v, err := c.Encrypt(ctx, "token", *r.Token)
+			if err != nil {
+				return nil, err
+			}
+			r.Token = &v
+		}
+		vres.Projected = &r
+		return &vres, nil
+	}
+}
+
+// wrapGetServerCodec applies the codec to the encrypted attributes of the
+// "get" method payload and result.
+func wrapGetServerCodec(endpoint goa.Endpoint, c goa.FieldCodec) goa.Endpoint {
+	return func(ctx context.Context, req any) (any, error) {
+		var err error
+		res, err := endpoint(ctx, req)
+		if err != nil {
+			return nil, err
+		}
+		r := *res.(*GetResult)
+		if r.Secret, err = c.Encrypt(ctx, "secret", r.Secret); err != nil {
+			return nil, err
+		}
+		return &r, nil
+	}
+}
+
+// wrapSignupClientCodec applies the codec to the encrypted attributes of the
+// "signup" method payload and result.
+func wrapSignupClientCodec(endpoint goa.Endpoint, c goa.FieldCodec) goa.Endpoint {
+	return func(ctx context.Context, req any) (any, error) {
+		var err error
+		p := *req.(*SignupPayload)
+		if p.Ssn != nil {
+			v, err := c.Encrypt(ctx, "ssn", *p.Ssn)
+			if err != nil {
+				return nil, err
+			}
+			p.Ssn = &v
+		}
+		if p.Pin, err = c.Encrypt(ctx, "pin", p.Pin); err != nil {
+			return nil, err
+		}
+		req = &p
+		res, err := endpoint(ctx, req)
+		if err != nil {
+			return nil, err
+		}
+		r := res.(*Account)
+		if r.Token != nil {
+			v, err := c.Decrypt(ctx, "token", *r.Token)
+			if err != nil {
+				return nil, err
+			}
+			r.Token = &v
+		}
+		return r, nil
+	}
+}
+
+// wrapGetClientCodec applies the codec to the encrypted attributes of the
+// "get" method payload and result.
+func wrapGetClientCodec(endpoint goa.Endpoint, c goa.FieldCodec) goa.Endpoint {
+	return func(ctx context.Context, req any) (any, error) {
+		var err error
+		res, err := endpoint(ctx, req)
+		if err != nil {
+			return nil, err
+		}
+		r := res.(*GetResult)
+		if r.Secret, err = c.Decrypt(ctx, "secret", r.Secret); err != nil {
+			return nil, err
+		}
+		return r, nil
+	}
+}
+`
+
+var ViewedRedactCode = `// Redact returns a copy of v where the sensitive attributes are redacted so
+// that it can be logged safely.
+func (v *ProfileView) Redact() any {
+	if v == nil {
+		return v
+	}
+	res := *v
+	if res.Email != nil {
+		r := goa.Redacted
+		res.Email = &r
+	}
+	res.Score = nil
+	return &res
+}
+
+// Redact returns a copy of v where the sensitive attributes are redacted so
+// that it can be logged safely.
+func (v *Profile) Redact() any {
+	if v == nil {
+		return v
+	}
+	res := *v
+	if res.Projected != nil {
+		res.Projected = res.Projected.Redact().(*ProfileView)
+	}
+	return &res
+}
+
+// Redact returns a copy of v where the sensitive attributes are redacted so
+// that it can be logged safely.
+func (v ProfileCollection) Redact() any {
+	res := v
+	if res.Projected != nil {
+		l := make(ProfileCollectionView, len(res.Projected))
+		for i, e := range res.Projected {
+			l[i] = e.Redact().(*ProfileView)
+		}
+		res.Projected = l
+	}
+	return res
+}
+`
@@ -0,0 +1,93 @@
+package testdata
+
+import (
+	. "goa.design/goa/v3/dsl"
+)
+
+var SensitiveDSL = func() {
+	var ID = Type("ID", String)
+	var User = Type("User", func() {
+		Attribute("name", String)
+		Attribute("email", String, func() {
+			Sensitive()
+		})
+		Attribute("id", ID, func() {
+			Sensitive()
+		})
+		Attribute("age", Int, func() {
+			Sensitive()
+		})
+		Attribute("tags", ArrayOf(String), func() {
+			Sensitive()
+		})
+		Required("age")
+	})
+	var Account = ResultType("application/vnd.account", func() {
+		Attributes(func() {
+			Attribute("id", String)
+			Attribute("token", String, func() {
+				Encrypted()
+			})
+			Attribute("owner", User)
+			Attribute("members", ArrayOf(User))
+		})
+	})
+	Service("Sensitive", func() {
+		Method("signup", func() {
+			Payload(func() {
+				Attribute("name", String)
+				Attribute("password", String, func() {
+					Sensitive()
+				})
+				Attribute("ssn", String, func() {
+					Encrypted()
+				})
+				Attribute("pin", String, func() {
+					Encrypted()
+				})
+				Required("name", "password", "pin")
+			})
+			Result(Account)
+		})
+		Method("get", func() {
+			Payload(String)
+			Result(func() {
+				Attribute("secret", String, func() {
+					Encrypted()
+				})
+				Required("secret")
+			})
+		})
+	})
+}
+
+var SensitiveViewsDSL = func() {
+	var Profile = ResultType("application/vnd.profile", func() {
+		Attributes(func() {
+			Attribute("name", String)
+			Attribute("email", String, func() {
+				Sensitive()
+			})
+			Attribute("score", Int, func() {
+				Sensitive()
+			})
+			Required("email", "score")
+		})
+		View("default", func() {
+			Attribute("name")
+			Attribute("email")
+			Attribute("score")
+		})
+		View("tiny", func() {
+			Attribute("name")
+		})
+	})
+	Service("SensitiveViews", func() {
+		Method("show", func() {
+			Result(Profile)
+		})
+		Method("list", func() {
+			Result(CollectionOf(Profile))
+		})
+	})
+}
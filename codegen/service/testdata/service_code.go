@@ -291,6 +291,56 @@ const ServiceName = "Empty"
 var MethodNames = [1]string{"Empty"}
 `
 
+const DiscoveryTags = `
+// Service is the Discovery service interface.
+type Service interface {
+	// Discover implements Discover.
+	Discover(context.Context) (err error)
+}
+
+// ServiceName is the name of the service as defined in the design. This is the
+// same value that is set in the endpoint request contexts under the ServiceKey
+// key.
+const ServiceName = "Discovery"
+
+// MethodNames lists the service method names as defined in the design. These
+// are the same values that are set in the endpoint request contexts under the
+// MethodKey key.
+var MethodNames = [1]string{"Discover"}
+
+// DiscoveryTags lists the service discovery tags defined in the design with the
+// "discovery:tag" meta. They are meant to be set on the registrations made with
+// the goa.design/goa/v3/discovery package.
+var DiscoveryTags = []string{"v1", "public"}
+`
+
+const PolicyMethod = `
+// Service is the Policy service interface.
+type Service interface {
+	// Protected implements Protected.
+	Protected(context.Context) (err error)
+}
+
+// Authorizer defines the authorization function invoked with the policy of the
+// endpoints after the requests have been authenticated.
+type Authorizer interface {
+	// Authorize returns a non-nil error, typically a *security.AuthorizationError,
+	// if the request is denied by the endpoint policy. payload is nil for
+	// methods that do not define one.
+	Authorize(ctx context.Context, policy *security.Policy, payload any) error
+}
+
+// ServiceName is the name of the service as defined in the design. This is the
+// same value that is set in the endpoint request contexts under the ServiceKey
+// key.
+const ServiceName = "Policy"
+
+// MethodNames lists the service method names as defined in the design. These
+// are the same values that are set in the endpoint request contexts under the
+// MethodKey key.
+var MethodNames = [1]string{"Protected"}
+`
+
 const EmptyResultMethod = `
 // Service is the EmptyResult service interface.
 type Service interface {
@@ -156,6 +156,27 @@ var EmptyMethodDSL = func() {
 	})
 }
 
+var DiscoveryTagsDSL = func() {
+	API("Discovery", func() {
+		Meta("discovery:tag", "v1")
+	})
+	Service("Discovery", func() {
+		Meta("discovery:tag", "public", "v1")
+		Method("Discover", func() {
+		})
+	})
+}
+
+var PolicyMethodDSL = func() {
+	Service("Policy", func() {
+		Policy(func() {
+			Roles("admin")
+		})
+		Method("Protected", func() {
+		})
+	})
+}
+
 var EmptyPayloadMethodDSL = func() {
 	var AResult = Type("AResult", func() {
 		Attribute("IntField", Int)
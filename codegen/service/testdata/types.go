@@ -46,3 +46,14 @@ type ObjectFieldT struct {
 	Array   []bool
 	Map     map[string]bool
 }
+
+type ValueT struct {
+	Value    ValueFieldT
+	Values   []ValueFieldT
+	ValueMap map[string]ValueFieldT
+	Pointer  *ValueFieldT
+}
+
+type ValueFieldT struct {
+	Name string
+}
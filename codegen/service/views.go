@@ -58,6 +58,15 @@ func ViewsFile(_ string, service *expr.ServiceExpr) *codegen.File {
 			})
 		}
 
+		// Redact methods
+		for _, r := range svc.viewedRedactMethods {
+			sections = append(sections, &codegen.SectionTemplate{
+				Name:   "viewed-redact",
+				Source: redactT,
+				Data:   r,
+			})
+		}
+
 		// generate a map for result types with view name as key and the fields
 		// rendered in the view as value.
 		var (
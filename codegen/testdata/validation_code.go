@@ -532,5 +532,34 @@ const (
 		}
 	}
 }
+`
+
+	CustomValidateRequiredValidationCode = `func Validate() (err error) {
+	if err2 := ids.Validate(target.RequiredID); err2 != nil {
+		err = goa.MergeErrors(err, goa.InvalidValueError("target.required_id", target.RequiredID, err2))
+	}
+	if target.Amount != nil {
+		if err2 := amounts.Validate(*target.Amount); err2 != nil {
+			err = goa.MergeErrors(err, goa.InvalidValueError("target.amount", *target.Amount, err2))
+		}
+	}
+}
+`
+
+	CustomValidatePointerValidationCode = `func Validate() (err error) {
+	if target.RequiredID == nil {
+		err = goa.MergeErrors(err, goa.MissingFieldError("required_id", "target"))
+	}
+	if target.RequiredID != nil {
+		if err2 := ids.Validate(*target.RequiredID); err2 != nil {
+			err = goa.MergeErrors(err, goa.InvalidValueError("target.required_id", *target.RequiredID, err2))
+		}
+	}
+	if target.Amount != nil {
+		if err2 := amounts.Validate(*target.Amount); err2 != nil {
+			err = goa.MergeErrors(err, goa.InvalidValueError("target.amount", *target.Amount, err2))
+		}
+	}
+}
 `
 )
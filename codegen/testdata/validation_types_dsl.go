@@ -153,5 +153,18 @@ var ValidationTypesDSL = func() {
 				Attribute("integer", IntegerT)
 			})
 		})
+
+		_ = Type("CustomValidate", func() {
+			Attribute("required_id", String, func() {
+				Meta("struct:field:type", "uuid.UUID", "github.com/google/uuid")
+				Meta("struct:field:validate", "ids.Validate", "example.com/ids")
+				Format(FormatUUID)
+			})
+			Attribute("amount", String, func() {
+				Meta("struct:field:type", "decimal.Decimal", "github.com/shopspring/decimal")
+				Meta("struct:field:validate", "amounts.Validate", "example.com/amounts")
+			})
+			Required("required_id")
+		})
 	)
 }
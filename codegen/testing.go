@@ -15,7 +15,7 @@ import (
 )
 
 // RunDSL returns the DSL root resulting from running the given DSL.
-func RunDSL(t *testing.T, dsl func()) *expr.RootExpr {
+func RunDSL(t testing.TB, dsl func()) *expr.RootExpr {
 	t.Helper()
 	eval.Reset()
 	expr.Root = new(expr.RootExpr)
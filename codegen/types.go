@@ -43,24 +43,34 @@ func GoNativeTypeName(t expr.DataType) string {
 
 // AttributeTags computes the struct field tags from its metadata if any.
 func AttributeTags(_, att *expr.AttributeExpr) string {
-	var elems []string
-	keys := make([]string, len(att.Meta))
-	i := 0
-	for k := range att.Meta {
-		keys[i] = k
-		i++
+	return FieldTags(att, nil)
+}
+
+// FieldTags computes the struct field tags from the given default tags and the
+// "struct:tag:xxx" metadata of the attribute. The metadata add tags to the
+// default tags and override the default tags with the same name. The tags are
+// sorted by name.
+func FieldTags(att *expr.AttributeExpr, defaults map[string]string) string {
+	tags := make(map[string]string, len(defaults))
+	for name, value := range defaults {
+		tags[name] = value
 	}
-	sort.Strings(keys)
-	for _, key := range keys {
-		val := att.Meta[key]
+	for key, val := range att.Meta {
 		if strings.HasPrefix(key, "struct:tag:") {
-			name := key[11:]
-			value := strings.Join(val, ",")
-			elems = append(elems, fmt.Sprintf("%s:\"%s\"", name, value))
+			tags[key[11:]] = strings.Join(val, ",")
 		}
 	}
-	if len(elems) > 0 {
-		return " `" + strings.Join(elems, " ") + "`"
+	if len(tags) == 0 {
+		return ""
+	}
+	names := make([]string, 0, len(tags))
+	for name := range tags {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	elems := make([]string, len(names))
+	for i, name := range names {
+		elems[i] = fmt.Sprintf("%s:\"%s\"", name, tags[name])
 	}
-	return ""
+	return " `" + strings.Join(elems, " ") + "`"
 }
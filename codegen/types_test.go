@@ -92,6 +92,34 @@ func TestGoTypeDef(t *testing.T) {
 	}
 }
 
+func TestFieldTags(t *testing.T) {
+	var (
+		defaults = map[string]string{"json": "name,omitempty", "xml": "name,omitempty"}
+		bson     = expr.MetaExpr{"struct:tag:bson": []string{"name"}}
+		validate = expr.MetaExpr{"struct:tag:validate": []string{"required", "min=1"}}
+		json     = expr.MetaExpr{"struct:tag:json": []string{"n"}, "struct:tag:db": []string{"name"}}
+	)
+	cases := map[string]struct {
+		meta     expr.MetaExpr
+		defaults map[string]string
+		expected string
+	}{
+		"NoTag":         {nil, nil, ""},
+		"Defaults":      {nil, defaults, " `json:\"name,omitempty\" xml:\"name,omitempty\"`"},
+		"Meta":          {bson, nil, " `bson:\"name\"`"},
+		"MetaValues":    {validate, nil, " `validate:\"required,min=1\"`"},
+		"MetaDefaults":  {bson, defaults, " `bson:\"name\" json:\"name,omitempty\" xml:\"name,omitempty\"`"},
+		"MetaOverrides": {json, defaults, " `db:\"name\" json:\"n\" xml:\"name,omitempty\"`"},
+	}
+
+	for k, tc := range cases {
+		actual := FieldTags(&expr.AttributeExpr{Type: expr.String, Meta: tc.meta}, tc.defaults)
+		if actual != tc.expected {
+			t.Errorf("%s: got %#v, expected %#v", k, actual, tc.expected)
+		}
+	}
+}
+
 func TestGoNativeTypeName(t *testing.T) {
 	cases := map[string]struct {
 		dataType expr.DataType
@@ -22,6 +22,7 @@ var (
 	mapValT        *template.Template
 	unionValT      *template.Template
 	userValT       *template.Template
+	funcValT       *template.Template
 )
 
 func init() {
@@ -42,6 +43,7 @@ func init() {
 	mapValT = template.Must(template.New("map").Funcs(fm).Parse(mapValTmpl))
 	unionValT = template.Must(template.New("union").Funcs(fm).Parse(unionValTmpl))
 	userValT = template.Must(template.New("user").Funcs(fm).Parse(userValTmpl))
+	funcValT = template.Must(template.New("func").Funcs(fm).Parse(funcValTmpl))
 }
 
 // AttributeValidationCode produces Go code that runs the validations defined
@@ -251,7 +253,8 @@ func validationCode(att *expr.AttributeExpr, attCtx *AttributeContext, req, alia
 			att.Validation = validation
 		}
 	}
-	if validation == nil {
+	fn, _ := GetMetaFunc(att, "struct:field:validate")
+	if validation == nil && fn == "" {
 		return ""
 	}
 	var (
@@ -284,6 +287,12 @@ func validationCode(att *expr.AttributeExpr, attCtx *AttributeContext, req, alia
 		}
 		return buf.String()
 	}
+	if fn != "" {
+		// The validation function replaces the validations defined in
+		// the design which may not apply to the custom type.
+		data["validate"] = fn
+		return runTemplate(funcValT, data)
+	}
 	var res []string
 	if values := validation.Values; values != nil {
 		data["values"] = values
@@ -366,6 +375,10 @@ func hasValidations(attCtx *AttributeContext, ut expr.UserType) bool {
 	res := false
 	done := errors.New("done")
 	Walk(ut.Attribute(), func(a *expr.AttributeExpr) error { // nolint: errcheck
+		if fn, _ := GetMetaFunc(a, "struct:field:validate"); fn != "" {
+			res = true
+			return done
+		}
 		if a.Validation == nil {
 			return nil
 		}
@@ -520,6 +533,15 @@ const (
         err = goa.MergeErrors(err, err2)
 }`
 
+	funcValTmpl = `{{ if .isPointer }}if {{ .target }} != nil {
+{{ end -}}
+if err2 := {{ .validate }}({{ .targetVal }}); err2 != nil {
+        err = goa.MergeErrors(err, goa.InvalidValueError({{ printf "%q" .context }}, {{ .targetVal }}, err2))
+{{ if .isPointer -}}
+}
+{{ end -}}
+}`
+
 	enumValTmpl = `{{ if .isPointer }}if {{ .target }} != nil {
 {{ end -}}
 if !({{ oneof .targetVal .values }}) {
@@ -25,6 +25,7 @@ func TestRecursiveValidationCode(t *testing.T) {
 		rtcolT   = root.UserType("Collection")
 		colT     = root.UserType("TypeWithCollection")
 		deepT    = root.UserType("Deep")
+		customT  = root.UserType("CustomValidate")
 	)
 	cases := []struct {
 		Name       string
@@ -60,6 +61,8 @@ func TestRecursiveValidationCode(t *testing.T) {
 		{"collection-pointer", rtcolT, false, true, false, testdata.ResultCollectionPointerValidationCode},
 		{"type-with-collection-pointer", colT, false, true, false, testdata.TypeWithCollectionPointerValidationCode},
 		{"type-with-embedded-type", deepT, false, true, false, testdata.TypeWithEmbeddedTypeValidationCode},
+		{"custom-validate-required", customT, true, false, false, testdata.CustomValidateRequiredValidationCode},
+		{"custom-validate-pointer", customT, false, true, false, testdata.CustomValidatePointerValidationCode},
 	}
 	for _, c := range cases {
 		t.Run(c.Name, func(t *testing.T) {
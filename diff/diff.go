@@ -0,0 +1,389 @@
+/*
+Package diff detects the breaking changes between two versions of an API
+described by OpenAPI 3 specifications. It is used by the "goa diff" command to
+compare the current design with a snapshot of the OpenAPI specification
+generated for a previous version, typically gen/http/openapi3.json.
+
+A change is breaking if clients written against the previous version may fail
+with the new version:
+
+  - an endpoint is removed,
+  - a parameter or request body property becomes required,
+  - the type of a parameter, request or response property changes,
+  - the values accepted in requests are narrowed (enum values removed,
+    tighter minimum, maximum, minLength or maxLength),
+  - a response property is removed or is no longer required,
+  - a response status code is removed.
+*/
+package diff
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+type (
+	// Change describes a breaking change.
+	Change struct {
+		// Location identifies the changed element, for example
+		// "POST /users request body.name".
+		Location string
+		// Message describes the change.
+		Message string
+	}
+
+	// doc is an OpenAPI document decoded from JSON.
+	doc map[string]any
+
+	// comparer keeps the state needed to compare two documents.
+	comparer struct {
+		old, new doc
+		changes  []*Change
+		// visited records the pairs of schema references being compared
+		// to handle recursive types.
+		visited map[string]bool
+	}
+)
+
+// methods lists the HTTP methods of an OpenAPI path item in the order used in
+// reports.
+var methods = []string{"get", "put", "post", "delete", "options", "head", "patch", "trace"}
+
+// paramRegex matches the path parameters of an OpenAPI path.
+var paramRegex = regexp.MustCompile(`{[^}]*}`)
+
+// Compare returns the breaking changes made to the API described by the
+// OpenAPI 3 JSON specification previous in the API described by current.
+func Compare(previous, current []byte) ([]*Change, error) {
+	var old, cur doc
+	if err := json.Unmarshal(previous, &old); err != nil {
+		return nil, fmt.Errorf("invalid previous OpenAPI specification: %w", err)
+	}
+	if err := json.Unmarshal(current, &cur); err != nil {
+		return nil, fmt.Errorf("invalid current OpenAPI specification: %w", err)
+	}
+	c := &comparer{old: old, new: cur, visited: make(map[string]bool)}
+	c.comparePaths()
+	return c.changes, nil
+}
+
+// String returns the change formatted for display.
+func (c *Change) String() string {
+	return fmt.Sprintf("%s: %s", c.Location, c.Message)
+}
+
+// comparePaths compares the operations of the two documents.
+func (c *comparer) comparePaths() {
+	newPaths := make(map[string]map[string]any)
+	for p, item := range object(c.new["paths"]) {
+		newPaths[normalizePath(p)] = object(item)
+	}
+	oldPaths := object(c.old["paths"])
+	for _, p := range sortedKeys(oldPaths) {
+		oldItem := object(oldPaths[p])
+		newItem := newPaths[normalizePath(p)]
+		for _, m := range methods {
+			oldOp := object(oldItem[m])
+			if oldOp == nil {
+				continue
+			}
+			loc := strings.ToUpper(m) + " " + p
+			newOp := object(newItem[m])
+			if newOp == nil {
+				c.add(loc, "endpoint removed")
+				continue
+			}
+			c.compareOperation(loc, oldOp, newOp)
+		}
+	}
+}
+
+// compareOperation compares the parameters, request body and responses of an
+// operation.
+func (c *comparer) compareOperation(loc string, old, new map[string]any) {
+	// Parameters
+	{
+		oldParams := c.params(c.old, old)
+		newParams := c.params(c.new, new)
+		for _, k := range sortedKeys(newParams) {
+			np := newParams[k]
+			op, ok := oldParams[k]
+			ploc := fmt.Sprintf("%s %s parameter %q", loc, np["in"], np["name"])
+			if isTrue(np["required"]) && (!ok || !isTrue(op["required"])) {
+				c.add(ploc, "parameter is now required")
+			}
+			if ok {
+				c.compareSchema(ploc, object(op["schema"]), object(np["schema"]), true)
+			}
+		}
+	}
+
+	// Request body
+	{
+		oldBody := c.resolve(c.old, object(old["requestBody"]))
+		newBody := c.resolve(c.new, object(new["requestBody"]))
+		bloc := loc + " request body"
+		if newBody != nil && isTrue(newBody["required"]) && (oldBody == nil || !isTrue(oldBody["required"])) {
+			c.add(bloc, "request body is now required")
+		}
+		if oldBody != nil && newBody != nil {
+			c.compareSchema(bloc, contentSchema(oldBody), contentSchema(newBody), true)
+		}
+	}
+
+	// Responses
+	{
+		oldResps := object(old["responses"])
+		newResps := object(new["responses"])
+		for _, code := range sortedKeys(oldResps) {
+			rloc := fmt.Sprintf("%s response %s", loc, code)
+			nr, ok := newResps[code]
+			if !ok {
+				c.add(rloc, "response removed")
+				continue
+			}
+			oldResp := c.resolve(c.old, object(oldResps[code]))
+			newResp := c.resolve(c.new, object(nr))
+			c.compareSchema(rloc, contentSchema(oldResp), contentSchema(newResp), false)
+		}
+	}
+}
+
+// compareSchema compares two JSON schemas. request indicates whether the
+// schemas describe data sent by clients (request) or received by clients
+// (response).
+func (c *comparer) compareSchema(loc string, old, new map[string]any, request bool) {
+	if old == nil || new == nil {
+		return
+	}
+	if oref, nref := str(old["$ref"]), str(new["$ref"]); oref != "" || nref != "" {
+		key := fmt.Sprintf("%s|%s|%v", oref, nref, request)
+		if c.visited[key] {
+			return
+		}
+		c.visited[key] = true
+		defer delete(c.visited, key)
+	}
+	old = c.resolve(c.old, old)
+	new = c.resolve(c.new, new)
+	if old == nil || new == nil {
+		return
+	}
+
+	if ot, nt := str(old["type"]), str(new["type"]); ot != "" && nt != "" && ot != nt {
+		c.add(loc, fmt.Sprintf("type changed from %s to %s", ot, nt))
+		return
+	}
+	if of, nf := str(old["format"]), str(new["format"]); of != nf && nf != "" {
+		c.add(loc, fmt.Sprintf("format changed from %q to %q", of, nf))
+	}
+
+	if request {
+		c.compareRequestValidations(loc, old, new)
+	} else {
+		c.compareResponseValidations(loc, old, new)
+	}
+
+	oldProps := object(old["properties"])
+	newProps := object(new["properties"])
+	oldReq := stringSet(old["required"])
+	newReq := stringSet(new["required"])
+	for _, n := range sortedKeys(oldProps) {
+		ploc := loc + "." + n
+		np, ok := newProps[n]
+		if !ok {
+			if !request {
+				c.add(ploc, "property removed")
+			}
+			continue
+		}
+		if !request && oldReq[n] && !newReq[n] {
+			c.add(ploc, "property is no longer required")
+		}
+		c.compareSchema(ploc, object(oldProps[n]), object(np), request)
+	}
+	if request {
+		for _, n := range sortedKeys(newProps) {
+			if newReq[n] && !oldReq[n] {
+				c.add(loc+"."+n, "property is now required")
+			}
+		}
+	}
+	c.compareSchema(loc+"[]", object(old["items"]), object(new["items"]), request)
+	c.compareSchema(loc+"{}", object(old["additionalProperties"]), object(new["additionalProperties"]), request)
+}
+
+// compareRequestValidations reports the validations that reject values
+// previously accepted.
+func (c *comparer) compareRequestValidations(loc string, old, new map[string]any) {
+	if newEnum := new["enum"]; newEnum != nil {
+		oldEnum := old["enum"]
+		if oldEnum == nil {
+			c.add(loc, "values restricted to an enum")
+		} else if removed := missing(oldEnum, newEnum); len(removed) > 0 {
+			c.add(loc, fmt.Sprintf("enum values removed: %s", strings.Join(removed, ", ")))
+		}
+	}
+	for _, k := range []string{"minimum", "minLength", "minItems"} {
+		if n, ok := number(new[k]); ok {
+			if o, ok := number(old[k]); !ok || n > o {
+				c.add(loc, fmt.Sprintf("%s increased to %v", k, n))
+			}
+		}
+	}
+	for _, k := range []string{"maximum", "maxLength", "maxItems"} {
+		if n, ok := number(new[k]); ok {
+			if o, ok := number(old[k]); !ok || n < o {
+				c.add(loc, fmt.Sprintf("%s decreased to %v", k, n))
+			}
+		}
+	}
+	if op, np := str(old["pattern"]), str(new["pattern"]); np != "" && op != np {
+		c.add(loc, fmt.Sprintf("pattern changed to %q", np))
+	}
+}
+
+// compareResponseValidations reports the response values that clients may
+// not expect.
+func (c *comparer) compareResponseValidations(loc string, old, new map[string]any) {
+	oldEnum := old["enum"]
+	if oldEnum == nil {
+		return
+	}
+	newEnum := new["enum"]
+	if newEnum == nil {
+		c.add(loc, "values no longer restricted to an enum")
+	} else if added := missing(newEnum, oldEnum); len(added) > 0 {
+		c.add(loc, fmt.Sprintf("enum values added: %s", strings.Join(added, ", ")))
+	}
+}
+
+// params returns the parameters of op indexed by location and name.
+func (c *comparer) params(d doc, op map[string]any) map[string]map[string]any {
+	res := make(map[string]map[string]any)
+	ps, _ := op["parameters"].([]any)
+	for _, p := range ps {
+		param := c.resolve(d, object(p))
+		if param == nil {
+			continue
+		}
+		res[fmt.Sprintf("%s:%s", param["in"], param["name"])] = param
+	}
+	return res
+}
+
+// resolve returns the object referred to by the "$ref" property of o if any,
+// o otherwise.
+func (c *comparer) resolve(d doc, o map[string]any) map[string]any {
+	for i := 0; o != nil && i < 10; i++ {
+		ref := str(o["$ref"])
+		if ref == "" {
+			return o
+		}
+		if !strings.HasPrefix(ref, "#/") {
+			return nil
+		}
+		var cur any = map[string]any(d)
+		for _, seg := range strings.Split(strings.TrimPrefix(ref, "#/"), "/") {
+			seg = strings.ReplaceAll(strings.ReplaceAll(seg, "~1", "/"), "~0", "~")
+			cur = object(cur)[seg]
+		}
+		o = object(cur)
+	}
+	return o
+}
+
+// add records a breaking change.
+func (c *comparer) add(loc, msg string) {
+	c.changes = append(c.changes, &Change{Location: loc, Message: msg})
+}
+
+// contentSchema returns the schema of the JSON content of a request body or
+// response, the schema of the first content type if there is no JSON
+// content.
+func contentSchema(o map[string]any) map[string]any {
+	content := object(o["content"])
+	if s, ok := content["application/json"]; ok {
+		return object(object(s)["schema"])
+	}
+	for _, k := range sortedKeys(content) {
+		return object(object(content[k])["schema"])
+	}
+	return nil
+}
+
+// normalizePath removes the names of the path parameters so that renaming a
+// parameter is not reported as removing the endpoint.
+func normalizePath(p string) string {
+	return paramRegex.ReplaceAllString(p, "{}")
+}
+
+// missing returns the values of vals that are not in others formatted as
+// JSON.
+func missing(vals, others any) []string {
+	vs, _ := vals.([]any)
+	os, _ := others.([]any)
+	var res []string
+	for _, v := range vs {
+		found := false
+		for _, o := range os {
+			if reflect.DeepEqual(v, o) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			b, _ := json.Marshal(v)
+			res = append(res, string(b))
+		}
+	}
+	return res
+}
+
+// object returns v if it is a JSON object, nil otherwise.
+func object(v any) map[string]any {
+	m, _ := v.(map[string]any)
+	return m
+}
+
+// str returns v if it is a string, the empty string otherwise.
+func str(v any) string {
+	s, _ := v.(string)
+	return s
+}
+
+// number returns v if it is a number.
+func number(v any) (float64, bool) {
+	f, ok := v.(float64)
+	return f, ok
+}
+
+// isTrue returns true if v is the boolean true.
+func isTrue(v any) bool {
+	b, _ := v.(bool)
+	return b
+}
+
+// stringSet returns the strings of the JSON array v as a set.
+func stringSet(v any) map[string]bool {
+	vs, _ := v.([]any)
+	res := make(map[string]bool, len(vs))
+	for _, s := range vs {
+		res[str(s)] = true
+	}
+	return res
+}
+
+// sortedKeys returns the keys of m in lexical order.
+func sortedKeys[T any](m map[string]T) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,130 @@
+package diff
+
+import (
+	"strings"
+	"testing"
+)
+
+const baseSpec = `{
+	"openapi": "3.0.3",
+	"paths": {
+		"/users/{id}": {
+			"get": {
+				"parameters": [
+					{"name": "id", "in": "path", "required": true, "schema": {"type": "integer"}},
+					{"name": "fields", "in": "query", "schema": {"type": "string"}}
+				],
+				"responses": {
+					"200": {"content": {"application/json": {"schema": {"$ref": "#/components/schemas/User"}}}},
+					"404": {"description": "Not found"}
+				}
+			},
+			"put": {
+				"requestBody": {"content": {"application/json": {"schema": {"$ref": "#/components/schemas/UserBody"}}}},
+				"responses": {"204": {"description": "No content"}}
+			}
+		},
+		"/health": {"get": {"responses": {"200": {"description": "OK"}}}}
+	},
+	"components": {
+		"schemas": {
+			"User": {
+				"type": "object",
+				"properties": {
+					"name": {"type": "string"},
+					"role": {"type": "string", "enum": ["admin", "user"]},
+					"friends": {"type": "array", "items": {"$ref": "#/components/schemas/User"}}
+				},
+				"required": ["name"]
+			},
+			"UserBody": {
+				"type": "object",
+				"properties": {
+					"name": {"type": "string", "maxLength": 100},
+					"role": {"type": "string", "enum": ["admin", "user"]},
+					"age": {"type": "integer"}
+				}
+			}
+		}
+	}
+}`
+
+func TestCompare(t *testing.T) {
+	cases := []struct {
+		Name     string
+		Current  string
+		Expected []string
+	}{
+		{"identical", baseSpec, nil},
+		{"renamed path parameter", replace(baseSpec, `"/users/{id}"`, `"/users/{userID}"`), nil},
+		{"removed endpoint", replace(baseSpec, `"/health": {"get": {"responses": {"200": {"description": "OK"}}}}`, `"/health": {}`), []string{
+			"GET /health: endpoint removed",
+		}},
+		{"new required parameter", replace(baseSpec, `{"name": "fields", "in": "query", "schema"`, `{"name": "fields", "in": "query", "required": true, "schema"`), []string{
+			`GET /users/{id} query parameter "fields": parameter is now required`,
+		}},
+		{"parameter type changed", replace(baseSpec, `"in": "path", "required": true, "schema": {"type": "integer"}`, `"in": "path", "required": true, "schema": {"type": "string"}`), []string{
+			`GET /users/{id} path parameter "id": type changed from integer to string`,
+		}},
+		{"removed response", replace(baseSpec, `,
+					"404": {"description": "Not found"}`, ``), []string{
+			"GET /users/{id} response 404: response removed",
+		}},
+		{"response property removed", replace(baseSpec, `"name": {"type": "string"},
+					"role"`, `"role"`), []string{
+			"GET /users/{id} response 200.name: property removed",
+		}},
+		{"response enum widened", replace(baseSpec, `"role": {"type": "string", "enum": ["admin", "user"]},
+					"friends"`, `"role": {"type": "string", "enum": ["admin", "user", "guest"]},
+					"friends"`), []string{
+			`GET /users/{id} response 200.role: enum values added: "guest"`,
+		}},
+		{"request property required", replace(baseSpec, `"age": {"type": "integer"}
+				}`, `"age": {"type": "integer"}
+				},
+				"required": ["age"]`), []string{
+			"PUT /users/{id} request body.age: property is now required",
+		}},
+		{"request narrowed", replace(replace(baseSpec, `"maxLength": 100`, `"maxLength": 50`), `"role": {"type": "string", "enum": ["admin", "user"]},
+					"age"`, `"role": {"type": "string", "enum": ["admin"]},
+					"age"`), []string{
+			"PUT /users/{id} request body.name: maxLength decreased to 50",
+			`PUT /users/{id} request body.role: enum values removed: "user"`,
+		}},
+		{"request widened", replace(baseSpec, `"maxLength": 100`, `"maxLength": 200`), nil},
+	}
+	for _, c := range cases {
+		t.Run(c.Name, func(t *testing.T) {
+			changes, err := Compare([]byte(baseSpec), []byte(c.Current))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(changes) != len(c.Expected) {
+				t.Fatalf("got %d changes, expected %d: %v", len(changes), len(c.Expected), changes)
+			}
+			for i, ch := range changes {
+				if ch.String() != c.Expected[i] {
+					t.Errorf("change %d: got %q, expected %q", i, ch.String(), c.Expected[i])
+				}
+			}
+		})
+	}
+}
+
+func TestCompareInvalid(t *testing.T) {
+	if _, err := Compare([]byte("{"), []byte(baseSpec)); err == nil {
+		t.Error("expected an error for an invalid previous specification")
+	}
+	if _, err := Compare([]byte(baseSpec), []byte("{")); err == nil {
+		t.Error("expected an error for an invalid current specification")
+	}
+}
+
+// replace replaces old with new in s and panics if old is not found so that
+// test cases do not silently compare identical documents.
+func replace(s, old, new string) string {
+	if !strings.Contains(s, old) {
+		panic("test: " + old + " not found")
+	}
+	return strings.Replace(s, old, new, 1)
+}
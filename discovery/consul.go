@@ -0,0 +1,102 @@
+package discovery
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+
+	goahttp "goa.design/goa/v3/http"
+)
+
+type (
+	// ConsulRegistrar registers the service instances with the local
+	// Consul agent.
+	ConsulRegistrar struct {
+		addr string
+		doer goahttp.Doer
+	}
+
+	// consulRegistration is the payload of the Consul agent service
+	// registration endpoint.
+	consulRegistration struct {
+		ID      string
+		Name    string
+		Address string
+		Port    int
+		Tags    []string          `json:",omitempty"`
+		Meta    map[string]string `json:",omitempty"`
+		Check   *consulCheck      `json:",omitempty"`
+	}
+
+	// consulCheck is the health check of a Consul service registration.
+	consulCheck struct {
+		HTTP                           string
+		Interval                       string
+		DeregisterCriticalServiceAfter string
+	}
+)
+
+// NewConsulRegistrar returns a registrar that uses the HTTP API of the Consul
+// agent at addr (e.g. "http://localhost:8500"). d is used to make the requests
+// to the agent, http.DefaultClient if nil. The registered instances are health
+// checked by the agent and deregistered automatically if they remain unhealthy
+// for a minute.
+func NewConsulRegistrar(addr string, d goahttp.Doer) *ConsulRegistrar {
+	if d == nil {
+		d = http.DefaultClient
+	}
+	return &ConsulRegistrar{addr: strings.TrimSuffix(addr, "/"), doer: d}
+}
+
+// Register implements Registrar.
+func (r *ConsulRegistrar) Register(ctx context.Context, reg *Registration) error {
+	payload := &consulRegistration{
+		ID:      reg.ID,
+		Name:    reg.Name,
+		Address: reg.Host,
+		Port:    reg.Port,
+		Tags:    reg.Tags,
+		Meta:    reg.Meta,
+	}
+	if u := reg.HealthURL(); u != "" {
+		payload.Check = &consulCheck{
+			HTTP:                           u,
+			Interval:                       reg.healthInterval().String(),
+			DeregisterCriticalServiceAfter: "1m",
+		}
+	}
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return err
+	}
+	return r.put(ctx, "/v1/agent/service/register", body)
+}
+
+// Deregister implements Registrar.
+func (r *ConsulRegistrar) Deregister(ctx context.Context, reg *Registration) error {
+	return r.put(ctx, "/v1/agent/service/deregister/"+url.PathEscape(reg.ID), nil)
+}
+
+// put makes a PUT request to the given agent endpoint.
+func (r *ConsulRegistrar) put(ctx context.Context, path string, body []byte) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPut, r.addr+path, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := r.doer.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		msg, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return fmt.Errorf("consul: unexpected status %d: %s", resp.StatusCode, strings.TrimSpace(string(msg)))
+	}
+	return nil
+}
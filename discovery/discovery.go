@@ -0,0 +1,133 @@
+/*
+Package discovery registers the running servers with a service registry so
+that the clients can discover them. Register registers the server on startup
+and returns the function that deregisters it on graceful shutdown. The package
+provides registrars for Consul and etcd, both implemented on top of their HTTP
+APIs.
+
+The generated service packages define a DiscoveryTags variable listing the
+tags set in the design with the "discovery:tag" meta which may be used to
+initialize the registration:
+
+	reg, err := discovery.NewRegistration(calc.ServiceName, *httpAddr, calc.DiscoveryTags...)
+	if err != nil {
+	    log.Fatal(err)
+	}
+	reg.HealthPath = "/healthz"
+	deregister, err := discovery.Register(ctx, discovery.NewConsulRegistrar("http://localhost:8500", nil), reg)
+	if err != nil {
+	    log.Fatal(err)
+	}
+	defer deregister(context.Background())
+*/
+package discovery
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/url"
+	"os"
+	"strconv"
+	"time"
+)
+
+type (
+	// Registrar registers service instances with a service registry.
+	// Implementations must be safe for concurrent use.
+	Registrar interface {
+		// Register registers the instance described by reg.
+		Register(ctx context.Context, reg *Registration) error
+		// Deregister deregisters the instance described by reg.
+		Deregister(ctx context.Context, reg *Registration) error
+	}
+
+	// Registration describes a service instance.
+	Registration struct {
+		// ID identifies the instance, it defaults to the name, host and
+		// port of the instance.
+		ID string
+		// Name is the name of the service.
+		Name string
+		// Host is the host name or IP address of the instance.
+		Host string
+		// Port is the port the instance listens on.
+		Port int
+		// HealthPath is the path of the health check endpoint of the
+		// instance (e.g. "/healthz") or its absolute URL. The instance is
+		// not health checked by the registry if empty.
+		HealthPath string
+		// HealthInterval is the interval at which the registry checks
+		// the health of the instance, 10 seconds by default.
+		HealthInterval time.Duration
+		// Tags lists the instance tags.
+		Tags []string
+		// Meta contains arbitrary key value pairs describing the
+		// instance.
+		Meta map[string]string
+	}
+)
+
+// NewRegistration returns the registration of the instance of the given
+// service listening on addr (e.g. ":8080"). The host defaults to the host name
+// of the machine if addr does not specify one or if it specifies an
+// unspecified IP address (e.g. "0.0.0.0").
+func NewRegistration(name, addr string, tags ...string) (*Registration, error) {
+	host, p, err := net.SplitHostPort(addr)
+	if err != nil {
+		return nil, fmt.Errorf("invalid address %q: %w", addr, err)
+	}
+	port, err := strconv.Atoi(p)
+	if err != nil {
+		return nil, fmt.Errorf("invalid port in address %q: %w", addr, err)
+	}
+	if ip := net.ParseIP(host); host == "" || ip != nil && ip.IsUnspecified() {
+		if host, err = os.Hostname(); err != nil {
+			return nil, err
+		}
+	}
+	return &Registration{Name: name, Host: host, Port: port, Tags: tags}, nil
+}
+
+// Register registers reg with r and returns the function that deregisters it,
+// meant to be called on graceful shutdown. The ID of the registration is
+// initialized if empty.
+func Register(ctx context.Context, r Registrar, reg *Registration) (func(context.Context) error, error) {
+	if reg.ID == "" {
+		reg.ID = fmt.Sprintf("%s-%s-%d", reg.Name, reg.Host, reg.Port)
+	}
+	if err := r.Register(ctx, reg); err != nil {
+		return nil, fmt.Errorf("failed to register %q: %w", reg.ID, err)
+	}
+	return func(ctx context.Context) error {
+		if err := r.Deregister(ctx, reg); err != nil {
+			return fmt.Errorf("failed to deregister %q: %w", reg.ID, err)
+		}
+		return nil
+	}, nil
+}
+
+// Address returns the address ("host:port") of the instance.
+func (reg *Registration) Address() string {
+	return net.JoinHostPort(reg.Host, strconv.Itoa(reg.Port))
+}
+
+// HealthURL returns the URL of the health check endpoint of the instance, an
+// empty string if there is none.
+func (reg *Registration) HealthURL() string {
+	if reg.HealthPath == "" {
+		return ""
+	}
+	if u, err := url.Parse(reg.HealthPath); err == nil && u.IsAbs() {
+		return reg.HealthPath
+	}
+	return "http://" + reg.Address() + reg.HealthPath
+}
+
+// healthInterval returns the health check interval.
+func (reg *Registration) healthInterval() time.Duration {
+	if reg.HealthInterval > 0 {
+		return reg.HealthInterval
+	}
+	return 10 * time.Second
+}
@@ -0,0 +1,137 @@
+package discovery
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestNewRegistration(t *testing.T) {
+	reg, err := NewRegistration("calc", "10.0.0.1:8080", "v1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	reg.HealthPath = "/healthz"
+	if reg.Address() != "10.0.0.1:8080" || reg.HealthURL() != "http://10.0.0.1:8080/healthz" || !reflect.DeepEqual(reg.Tags, []string{"v1"}) {
+		t.Errorf("got address %q, health URL %q and tags %v", reg.Address(), reg.HealthURL(), reg.Tags)
+	}
+	reg, err = NewRegistration("calc", ":8080")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if reg.Host == "" {
+		t.Error("expected the host to default to the host name")
+	}
+	if _, err := NewRegistration("calc", "localhost"); err == nil {
+		t.Error("expected an error for an address without port")
+	}
+}
+
+func TestConsulRegistrar(t *testing.T) {
+	var registered *consulRegistration
+	var deregistered string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.Method == "PUT" && r.URL.Path == "/v1/agent/service/register":
+			json.NewDecoder(r.Body).Decode(&registered) // nolint: errcheck
+		case r.Method == "PUT" && r.URL.Path == "/v1/agent/service/deregister/calc-1":
+			deregistered = "calc-1"
+		default:
+			http.Error(w, "not found", http.StatusNotFound)
+		}
+	}))
+	defer srv.Close()
+	reg := &Registration{ID: "calc-1", Name: "calc", Host: "10.0.0.1", Port: 8080, HealthPath: "/healthz", Tags: []string{"v1"}}
+	deregister, err := Register(context.Background(), NewConsulRegistrar(srv.URL, nil), reg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := &consulRegistration{
+		ID:      "calc-1",
+		Name:    "calc",
+		Address: "10.0.0.1",
+		Port:    8080,
+		Tags:    []string{"v1"},
+		Check:   &consulCheck{HTTP: "http://10.0.0.1:8080/healthz", Interval: "10s", DeregisterCriticalServiceAfter: "1m"},
+	}
+	if !reflect.DeepEqual(registered, expected) {
+		t.Errorf("got registration %+v, expected %+v", registered, expected)
+	}
+	if err := deregister(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if deregistered != "calc-1" {
+		t.Error("expected the instance to be deregistered")
+	}
+	if _, err := Register(context.Background(), NewConsulRegistrar(srv.URL+"/invalid", nil), reg); err == nil {
+		t.Error("expected an error for an unexpected status")
+	}
+}
+
+func TestEtcdRegistrar(t *testing.T) {
+	var mu sync.Mutex
+	kv := make(map[string]string)
+	var keepalives, revoked int
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+		var body map[string]any
+		json.NewDecoder(r.Body).Decode(&body) // nolint: errcheck
+		switch r.URL.Path {
+		case "/v3/lease/grant":
+			w.Write([]byte(`{"ID":"42","TTL":"1"}`)) // nolint: errcheck
+		case "/v3/kv/put":
+			key, _ := base64.StdEncoding.DecodeString(body["key"].(string))
+			val, _ := base64.StdEncoding.DecodeString(body["value"].(string))
+			if body["lease"] != "42" {
+				http.Error(w, "invalid lease", http.StatusBadRequest)
+				return
+			}
+			kv[string(key)] = string(val)
+		case "/v3/lease/keepalive":
+			keepalives++
+		case "/v3/lease/revoke":
+			revoked++
+			kv = make(map[string]string)
+		case "/healthz":
+		default:
+			http.Error(w, "not found", http.StatusNotFound)
+		}
+	}))
+	defer srv.Close()
+	r := NewEtcdRegistrar(srv.URL, "/services/", 150*time.Millisecond, nil)
+	reg := &Registration{Name: "calc", Host: "10.0.0.1", Port: 8080, HealthPath: srv.URL + "/healthz"}
+	deregister, err := Register(context.Background(), r, reg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	mu.Lock()
+	val := kv["/services/calc/calc-10.0.0.1-8080"]
+	mu.Unlock()
+	if val != `{"id":"calc-10.0.0.1-8080","name":"calc","address":"10.0.0.1:8080"}` {
+		t.Errorf("got value %q", val)
+	}
+	for {
+		mu.Lock()
+		n := keepalives
+		mu.Unlock()
+		if n > 0 {
+			break
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	if err := deregister(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	mu.Lock()
+	defer mu.Unlock()
+	if revoked != 1 || len(kv) != 0 {
+		t.Errorf("got %d revocations and %d keys, expected the lease to be revoked", revoked, len(kv))
+	}
+}
@@ -0,0 +1,189 @@
+package discovery
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	goahttp "goa.design/goa/v3/http"
+)
+
+type (
+	// EtcdRegistrar registers the service instances in etcd. Each instance
+	// is stored under the key "<prefix>/<name>/<id>" with a lease that is
+	// kept alive while the instance is registered and healthy so that the
+	// instances that stop without deregistering are removed when the lease
+	// expires.
+	EtcdRegistrar struct {
+		addr   string
+		prefix string
+		ttl    time.Duration
+		doer   goahttp.Doer
+
+		mu     sync.Mutex
+		leases map[string]*etcdLease
+	}
+
+	// etcdLease is the lease of a registered instance.
+	etcdLease struct {
+		id     string
+		cancel context.CancelFunc
+		done   chan struct{}
+	}
+
+	// etcdInstance is the value stored in etcd for each instance.
+	etcdInstance struct {
+		ID      string            `json:"id"`
+		Name    string            `json:"name"`
+		Address string            `json:"address"`
+		Tags    []string          `json:"tags,omitempty"`
+		Meta    map[string]string `json:"meta,omitempty"`
+	}
+)
+
+// NewEtcdRegistrar returns a registrar that uses the JSON gateway of the etcd
+// server at addr (e.g. "http://localhost:2379") to store the instances under
+// prefix (e.g. "/services") with leases of the given TTL, 30 seconds if 0. d
+// is used to make the requests to etcd and to the health check endpoints of
+// the instances, http.DefaultClient if nil. The leases of the instances whose
+// health check endpoint does not return a 2xx response are not renewed.
+func NewEtcdRegistrar(addr, prefix string, ttl time.Duration, d goahttp.Doer) *EtcdRegistrar {
+	if ttl <= 0 {
+		ttl = 30 * time.Second
+	}
+	if d == nil {
+		d = http.DefaultClient
+	}
+	return &EtcdRegistrar{
+		addr:   strings.TrimSuffix(addr, "/"),
+		prefix: strings.TrimSuffix(prefix, "/"),
+		ttl:    ttl,
+		doer:   d,
+		leases: make(map[string]*etcdLease),
+	}
+}
+
+// Register implements Registrar.
+func (r *EtcdRegistrar) Register(ctx context.Context, reg *Registration) error {
+	ttl := int64(r.ttl / time.Second)
+	if ttl < 1 {
+		ttl = 1
+	}
+	var grant struct{ ID string }
+	if err := r.post(ctx, "/v3/lease/grant", map[string]any{"TTL": ttl}, &grant); err != nil {
+		return err
+	}
+	val, err := json.Marshal(&etcdInstance{ID: reg.ID, Name: reg.Name, Address: reg.Address(), Tags: reg.Tags, Meta: reg.Meta})
+	if err != nil {
+		return err
+	}
+	put := map[string]any{
+		"key":   base64.StdEncoding.EncodeToString([]byte(r.prefix + "/" + reg.Name + "/" + reg.ID)),
+		"value": base64.StdEncoding.EncodeToString(val),
+		"lease": grant.ID,
+	}
+	if err := r.post(ctx, "/v3/kv/put", put, nil); err != nil {
+		r.post(ctx, "/v3/lease/revoke", map[string]any{"ID": grant.ID}, nil) // nolint: errcheck
+		return err
+	}
+	kctx, cancel := context.WithCancel(context.Background())
+	lease := &etcdLease{id: grant.ID, cancel: cancel, done: make(chan struct{})}
+	r.mu.Lock()
+	if prev, ok := r.leases[reg.ID]; ok {
+		prev.cancel()
+	}
+	r.leases[reg.ID] = lease
+	r.mu.Unlock()
+	go r.keepAlive(kctx, reg, lease)
+	return nil
+}
+
+// Deregister implements Registrar.
+func (r *EtcdRegistrar) Deregister(ctx context.Context, reg *Registration) error {
+	r.mu.Lock()
+	lease, ok := r.leases[reg.ID]
+	delete(r.leases, reg.ID)
+	r.mu.Unlock()
+	if !ok {
+		return nil
+	}
+	lease.cancel()
+	<-lease.done
+	return r.post(ctx, "/v3/lease/revoke", map[string]any{"ID": lease.id}, nil)
+}
+
+// keepAlive renews the lease of the instance until ctx is canceled.
+func (r *EtcdRegistrar) keepAlive(ctx context.Context, reg *Registration, lease *etcdLease) {
+	defer close(lease.done)
+	ticker := time.NewTicker(r.ttl / 3)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if !r.healthy(ctx, reg) {
+				continue
+			}
+			r.post(ctx, "/v3/lease/keepalive", map[string]any{"ID": lease.id}, nil) // nolint: errcheck
+		}
+	}
+}
+
+// healthy returns true if the health check endpoint of the instance returns a
+// 2xx response or if the instance does not define one.
+func (r *EtcdRegistrar) healthy(ctx context.Context, reg *Registration) bool {
+	u := reg.HealthURL()
+	if u == "" {
+		return true
+	}
+	ctx, cancel := context.WithTimeout(ctx, reg.healthInterval())
+	defer cancel()
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, u, nil)
+	if err != nil {
+		return false
+	}
+	resp, err := r.doer.Do(req)
+	if err != nil {
+		return false
+	}
+	resp.Body.Close()
+	return resp.StatusCode >= 200 && resp.StatusCode < 300
+}
+
+// post makes a POST request to the given etcd endpoint and decodes the
+// response body into res unless nil.
+func (r *EtcdRegistrar) post(ctx context.Context, path string, payload, res any) error {
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, r.addr+path, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := r.doer.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		msg, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return fmt.Errorf("etcd: unexpected status %d: %s", resp.StatusCode, strings.TrimSpace(string(msg)))
+	}
+	if res == nil {
+		return nil
+	}
+	if err := json.NewDecoder(resp.Body).Decode(res); err != nil {
+		return fmt.Errorf("etcd: %w", err)
+	}
+	return nil
+}
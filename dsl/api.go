@@ -287,7 +287,9 @@ func Email(email string) {
 
 // URL sets the contact, license or external documentation URL.
 //
-// URL must appear in Contact, License or Docs.
+// URL must appear in Contact, License, Docs or Callback. In Callback URL sets
+// the OpenAPI runtime expression that describes the callback URL, e.g.
+// "{$request.body#/callback_url}".
 //
 // URL accepts a single argument which is the URL.
 //
@@ -305,6 +307,8 @@ func URL(url string) {
 		def.URL = url
 	case *expr.DocsExpr:
 		def.URL = url
+	case *expr.HTTPCallbackExpr:
+		def.URL = url
 	default:
 		eval.IncompatibleDSL()
 	}
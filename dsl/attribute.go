@@ -2,6 +2,7 @@ package dsl
 
 import (
 	"fmt"
+	"time"
 
 	"goa.design/goa/v3/eval"
 	"goa.design/goa/v3/expr"
@@ -260,6 +261,45 @@ func Default(def any) {
 	a.SetDefault(def)
 }
 
+// MigrationDefault makes it possible to introduce a new required attribute
+// without breaking existing clients. HTTP servers use the given default value
+// when the attribute is missing from the request body until the end of the
+// grace period and add a Warning header to the response. Once the grace
+// period has elapsed requests that omit the attribute fail validation as
+// usual. The attribute must be listed in Required and be of a primitive type.
+//
+// MigrationDefault must appear in an Attribute DSL.
+//
+// MigrationDefault takes two arguments: the default value and the last day of
+// the grace period formatted as YYYY-MM-DD (UTC).
+//
+// Example:
+//
+//	var CreateBottle = Type("CreateBottle", func() {
+//	    Attribute("name", String)
+//	    Attribute("region", String, func() {
+//	        MigrationDefault("us-east-1", "2024-12-31")
+//	    })
+//	    Required("name", "region")
+//	})
+func MigrationDefault(def any, until string) {
+	a, ok := eval.Current().(*expr.AttributeExpr)
+	if !ok {
+		eval.IncompatibleDSL()
+		return
+	}
+	if _, err := time.Parse("2006-01-02", until); err != nil {
+		eval.ReportError("invalid migration grace period end %q, must be formatted as YYYY-MM-DD", until)
+		return
+	}
+	if _, ok := a.Type.(expr.Primitive); a.Type != nil && !ok {
+		eval.ReportError("migration default requires an attribute of primitive type, got %s", expr.QualifiedTypeName(a.Type))
+		return
+	}
+	Default(def)
+	a.AddMeta("migration:until", until)
+}
+
 // Example provides an example value for a type, a parameter, a header or any
 // attribute. Example supports two syntaxes: one syntax accepts two arguments
 // where the first argument is a summary describing the example and the second a
@@ -24,11 +24,9 @@ import (
 // match is found or if the matching field type does not correspond to the
 // attribute type.
 //
-// The following limitations apply on the external Go struct field types
-// recursively:
-//
-//   - struct fields must use pointers
-//   - pointers on slices or on maps are not supported
+// Nested structs are converted recursively using the same algorithm. The struct
+// fields, slice elements and map values of the external type may be structs or
+// pointers to structs. Pointers on slices or on maps are not supported.
 //
 // ConvertTo must appear in Type or ResutType.
 //
@@ -105,11 +103,9 @@ func ConvertTo(obj any) {
 // match is found or if the matching field type does not correspond to the
 // attribute type.
 //
-// The following limitations apply on the external Go struct field types
-// recursively:
-//
-//   - struct fields must use pointers
-//   - pointers on slices or on maps are not supported
+// Nested structs are converted recursively using the same algorithm. The struct
+// fields, slice elements and map values of the external type may be structs or
+// pointers to structs. Pointers on slices or on maps are not supported.
 //
 // CreateFrom must appear in Type or ResultType.
 //
@@ -8,7 +8,8 @@ import (
 // Description sets the expression description.
 //
 // Description may appear in API, Docs, Type or Attribute.
-// Description may also appear in Response and Files.
+// Description may also appear in Response, Files, Callback, Event and
+// Interceptor.
 //
 // Description accepts one arguments: the description string.
 //
@@ -44,6 +45,12 @@ func Description(d string) {
 		e.Description = d
 	case *expr.HTTPFileServerExpr:
 		e.Description = d
+	case *expr.HTTPCallbackExpr:
+		e.Description = d
+	case *expr.EventExpr:
+		e.Description = d
+	case *expr.InterceptorExpr:
+		e.Description = d
 	case *expr.GRPCResponseExpr:
 		e.Description = d
 	default:
@@ -0,0 +1,86 @@
+package dsl
+
+import (
+	"goa.design/goa/v3/eval"
+	"goa.design/goa/v3/expr"
+)
+
+// Event defines a domain event published by the service, e.g. to a message
+// broker such as NATS or Kafka. Events make it possible to keep the contracts
+// of the asynchronous messages produced by a service in the same design as its
+// API. The generated service package defines a Publisher interface with one
+// method per event (e.g. PublishBottleCreated) and a NewPublisher function that
+// implements it on top of an events.Publisher. The events package provides
+// transports for NATS and Kafka and optionally encodes the events using the
+// CloudEvents JSON format.
+//
+// Event must appear in a Service expression.
+//
+// Event takes the name of the event, the type of the event payload and an
+// optional DSL function. The DSL may use Description and Topic to set the name
+// of the topic (or subject) the event is published to. The topic defaults to
+// the service name followed by a dot and the event name.
+//
+// Example:
+//
+//    var Bottle = Type("Bottle", func() {
+//        Attribute("id", String)
+//        Attribute("name", String)
+//    })
+//
+//    var _ = Service("inventory", func() {
+//        Event("BottleCreated", Bottle, func() {
+//            Description("Published when a bottle is added to the inventory.")
+//            Topic("inventory.bottles.created")
+//        })
+//    })
+//
+func Event(name string, payload any, fn ...func()) {
+	s, ok := eval.Current().(*expr.ServiceExpr)
+	if !ok {
+		eval.IncompatibleDSL()
+		return
+	}
+	if len(fn) > 1 {
+		eval.ReportError("too many arguments given to Event")
+		return
+	}
+	dt, ok := payload.(expr.DataType)
+	if !ok {
+		eval.InvalidArgError("type", payload)
+		return
+	}
+	e := &expr.EventExpr{
+		Name:    name,
+		Topic:   s.Name + "." + name,
+		Payload: &expr.AttributeExpr{Type: dt},
+		Service: s,
+	}
+	if len(fn) == 1 {
+		if !eval.Execute(fn[0], e) {
+			return
+		}
+	}
+	s.Events = append(s.Events, e)
+}
+
+// Topic sets the name of the topic (or subject) an event is published to.
+//
+// Topic must appear in an Event expression.
+//
+// Topic takes one argument: the name of the topic.
+//
+// Example:
+//
+//    Event("BottleCreated", Bottle, func() {
+//        Topic("inventory.bottles.created")
+//    })
+//
+func Topic(name string) {
+	e, ok := eval.Current().(*expr.EventExpr)
+	if !ok {
+		eval.IncompatibleDSL()
+		return
+	}
+	e.Topic = name
+}
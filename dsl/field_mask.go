@@ -0,0 +1,61 @@
+package dsl
+
+import (
+	"goa.design/goa/v3/eval"
+	"goa.design/goa/v3/expr"
+)
+
+// FieldMask defines a payload string attribute holding a field mask, the comma
+// separated list of the paths of the target type attributes that an update
+// request modifies, e.g. "name,address.street_name". The string uses the JSON
+// encoding of google.protobuf.FieldMask so that the HTTP and gRPC endpoints
+// implement the partial updates described in https://google.aip.dev/134. The
+// field mask can be mapped to a query string parameter, a header or a body
+// field like any other attribute.
+//
+// The generated service package defines a Parse and an Apply function for each
+// field mask attribute, e.g. ParseUpdateUpdateMask and ApplyUpdateUpdateMask for
+// the "update_mask" attribute of the "update" method. The Parse function
+// returns an error if the field mask contains a path that does not designate an
+// attribute of the target type. The Apply function sets the attributes of a
+// value of the target type designated by the mask to their value in another and
+// runs the validations defined on the target type against the result. Both
+// functions return errors named "invalid_field_mask" that the generated servers
+// report as bad requests.
+//
+// FieldMask must appear in a Payload expression. The target type must be an
+// object user type. The other parameters and usage of FieldMask are the same as
+// the Attribute function after the type.
+//
+// Example:
+//
+//    var _ = Service("account", func() {
+//        Method("update", func() {
+//            Payload(func() {
+//                Attribute("account", Account)
+//                FieldMask("update_mask", Account, "Account fields to update")
+//                Required("account")
+//            })
+//            Result(Account)
+//            HTTP(func() {
+//                PATCH("/{account.id}")
+//                Body("account")
+//                Param("update_mask")
+//            })
+//        })
+//    })
+//
+func FieldMask(name string, target expr.UserType, args ...any) {
+	if _, ok := eval.Current().(*expr.AttributeExpr); !ok {
+		eval.IncompatibleDSL()
+		return
+	}
+	if target == nil {
+		eval.ReportError("field mask target type cannot be nil")
+		return
+	}
+	args = useDSL(args, func() {
+		Meta("fieldmask:target", target.Name())
+	})
+	Attribute(name, append([]any{expr.String}, args...)...)
+}
@@ -254,7 +254,11 @@ func Message(fn func()) {
 // that must be set in the request metadata instead of the message.
 // If Metadata is set in the gRPC endpoint expression, it inherits the
 // attribute properties (description, type, meta, validations etc.) from the
-// method payload.
+// method payload. As with HTTP headers the metadata key defaults to the
+// attribute name and may be set explicitly using the "attribute:key" syntax,
+// e.g. Attribute("token:authorization"). The generated server and client code
+// convert the metadata values to and from the attribute types including
+// arrays.
 //
 // Example:
 //
@@ -306,6 +310,8 @@ func Metadata(fn func()) {
 // If Trailers is set in the gRPC response expression, it inherits the
 // attribute properties (description, type, meta, validations etc.) from the
 // method result.
+// The trailer keys may be set explicitly using the "attribute:key" syntax in
+// the same way as the request metadata keys, see Metadata.
 //
 // Example:
 //
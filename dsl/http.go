@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"strconv"
 	"strings"
+	"time"
 
 	"goa.design/goa/v3/eval"
 	"goa.design/goa/v3/expr"
@@ -354,6 +355,14 @@ func route(method, path string) *expr.RouteExpr {
 // define headers sent in result metadata). Finally Header may also appear in a
 // Headers expression.
 //
+// Headers defined in the API or a service HTTP expression that the method
+// payload does not define are added to the payload of each endpoint (and made
+// required if listed in Required) so that headers common to all the endpoints
+// such as a tenant ID or an idempotency key are decoded, validated and
+// documented without repeating the declaration in each method. The payloads
+// defined with a user type are extended with a copy of the type named after
+// the type and the method, the payloads must be objects.
+//
 // Header accepts the same arguments as the Attribute function. The header name
 // may define a mapping between the attribute name and the HTTP header name when
 // they differ. The mapping syntax is "name of attribute:name of header".
@@ -376,6 +385,17 @@ func route(method, path string) *expr.RouteExpr {
 //        })
 //    })
 //
+//    var _ = API("saas", func() {
+//        HTTP(func() {
+//            Headers(func() {
+//                Header("tenant:X-Tenant-Id", String, func() {
+//                    Pattern("^[a-z0-9-]+$")
+//                })
+//                Required("tenant")
+//            })
+//        })
+//    })
+//
 func Header(name string, args ...any) {
 	h := headers(eval.Current())
 	if h == nil {
@@ -807,6 +827,11 @@ func SkipRequestBodyEncodeDecode() {
 // incompatible with gRPC and calling it on a method that defines a gRPC
 // transport is an error.
 //
+// The generated server honors the Range and If-Range request headers when the
+// reader returned by the service method implements io.ReadSeeker (e.g. an
+// *os.File) and responds with 206 Partial Content. Clients resume interrupted
+// downloads by using a Doer created with goahttp.NewResumeDoer.
+//
 // SkipResponseBodyEncodeDecode must appear in a HTTP endpoint expression.
 //
 // Example:
@@ -835,6 +860,495 @@ func SkipResponseBodyEncodeDecode() {
 	e.SkipResponseBodyEncodeDecode = true
 }
 
+// DisallowUnknownFields makes the decoding of JSON bodies fail when the body
+// contains fields that are not defined in the design. When used in a HTTP
+// endpoint expression the server rejects such request bodies with a 400 Bad
+// Request response. When used in a Response expression the client rejects such
+// response bodies with a decoding error.
+//
+// DisallowUnknownFields must appear in a HTTP endpoint expression or in a
+// Response expression.
+//
+// Example:
+//
+//    var _ = Service("account", func() {
+//        Method("create", func() {
+//            Payload(Account)
+//            Result(Account)
+//            HTTP(func() {
+//                POST("/")
+//                DisallowUnknownFields()
+//                Response(StatusCreated, func() {
+//                    DisallowUnknownFields()
+//                })
+//            })
+//        })
+//    })
+//
+func DisallowUnknownFields() {
+	switch actual := eval.Current().(type) {
+	case *expr.HTTPEndpointExpr:
+		actual.DisallowUnknownFields = true
+	case *expr.HTTPResponseExpr:
+		actual.DisallowUnknownFields = true
+	default:
+		eval.IncompatibleDSL()
+	}
+}
+
+// CollectUnknownFields stores the fields of JSON bodies that are not defined in
+// the design in the given payload (HTTP endpoint expression) or result
+// (Response expression) attribute instead of ignoring them. The attribute must
+// be a map with string keys, typically MapOf(String, Any). It is not part of
+// the body: the generated servers or clients initialize it with the unknown
+// fields when decoding the body.
+//
+// CollectUnknownFields must appear in a HTTP endpoint expression or in a
+// Response expression.
+//
+// CollectUnknownFields accepts one argument: the name of the attribute.
+//
+// Example:
+//
+//    var _ = Service("events", func() {
+//        Method("receive", func() {
+//            Payload(func() {
+//                Attribute("id", String)
+//                Attribute("extensions", MapOf(String, Any))
+//            })
+//            HTTP(func() {
+//                POST("/")
+//                CollectUnknownFields("extensions")
+//            })
+//        })
+//    })
+//
+func CollectUnknownFields(name string) {
+	switch actual := eval.Current().(type) {
+	case *expr.HTTPEndpointExpr:
+		actual.UnknownFields = name
+	case *expr.HTTPResponseExpr:
+		actual.UnknownFields = name
+	default:
+		eval.IncompatibleDSL()
+	}
+}
+
+// FieldPresence records the names of the request body fields set by the client
+// in the given payload attribute so that the service can tell a field
+// explicitly set to null from an absent field, e.g. to implement partial
+// updates. The attribute must be an array of strings, it is not part of the
+// body: the generated servers initialize it with the names of the top level
+// fields of the JSON request body and the generated clients encode the fields
+// it lists as null when their value is nil. The generated OpenAPI
+// specifications document the request body without the attribute.
+//
+// FieldPresence must appear in a HTTP endpoint expression.
+//
+// FieldPresence accepts one argument: the name of the attribute.
+//
+// Example:
+//
+//    var _ = Service("account", func() {
+//        Method("update", func() {
+//            Payload(func() {
+//                Attribute("id", String)
+//                Attribute("nickname", String)
+//                Attribute("fields", ArrayOf(String))
+//                Required("id")
+//            })
+//            HTTP(func() {
+//                PATCH("/{id}")
+//                FieldPresence("fields")
+//            })
+//        })
+//    })
+//
+// A request body of {"nickname": null} yields a payload whose Nickname field
+// is nil and whose Fields field is []string{"nickname"}, an empty request body
+// yields a nil Fields field.
+//
+func FieldPresence(name string) {
+	e, ok := eval.Current().(*expr.HTTPEndpointExpr)
+	if !ok {
+		eval.IncompatibleDSL()
+		return
+	}
+	e.PresentFields = name
+}
+
+// FieldSelection lets clients select the result fields rendered in the
+// response body with a query string parameter, e.g. "?fields=id,account.name".
+// The response body only contains the selected fields, all the fields are
+// rendered if the parameter is absent. Nested fields are selected using a dot
+// separated path and selecting a field selects all its sub-fields. Requests
+// that select fields not defined in the response body type are rejected with
+// a bad request error. The parameter is documented in the generated OpenAPI
+// specifications.
+//
+// FieldSelection must appear in a HTTP endpoint expression.
+//
+// FieldSelection accepts an optional argument which is the name of the query
+// string parameter, "fields" by default.
+//
+// Example:
+//
+//    var _ = Service("cellar", func() {
+//        Method("show", func() {
+//            Payload(func() {
+//                Attribute("id", Int)
+//            })
+//            Result(Bottle)
+//            HTTP(func() {
+//                GET("/{id}")
+//                FieldSelection()
+//            })
+//        })
+//    })
+//
+func FieldSelection(param ...string) {
+	e, ok := eval.Current().(*expr.HTTPEndpointExpr)
+	if !ok {
+		eval.IncompatibleDSL()
+		return
+	}
+	if len(param) > 1 {
+		eval.ReportError("too many arguments given to FieldSelection")
+		return
+	}
+	e.FieldSelection = "fields"
+	if len(param) == 1 {
+		if param[0] == "" {
+			eval.ReportError("field selection parameter name cannot be empty")
+			return
+		}
+		e.FieldSelection = param[0]
+	}
+}
+
+// Batch lets clients make batch requests to the endpoint. A batch request is
+// a POST request whose body is a JSON array of the endpoint request bodies, it
+// is made to the endpoint route path followed by the batch path. The other
+// request elements (path and query string parameters, headers and cookies)
+// are shared by all the elements of the batch. The server calls the service
+// method once per element and responds with a multi-status (207) response
+// whose body lists the status code, headers and body of the response to each
+// element so that elements may fail independently of one another. The
+// generated client defines a batch endpoint (e.g. CreateBatch) that accepts a
+// slice of method payloads and returns the results and errors of each element.
+//
+// Batch must appear in a HTTP endpoint expression.
+//
+// Batch accepts an optional argument which is the batch path, "/batch" by
+// default.
+//
+// Example:
+//
+//    var _ = Service("cellar", func() {
+//        Method("create", func() {
+//            Payload(Bottle)
+//            Result(Int)
+//            HTTP(func() {
+//                POST("/bottles")
+//                Batch() // POST /bottles/batch
+//            })
+//        })
+//    })
+//
+func Batch(path ...string) {
+	e, ok := eval.Current().(*expr.HTTPEndpointExpr)
+	if !ok {
+		eval.IncompatibleDSL()
+		return
+	}
+	if len(path) > 1 {
+		eval.ReportError("too many arguments given to Batch")
+		return
+	}
+	e.BatchPath = "/batch"
+	if len(path) == 1 {
+		e.BatchPath = path[0]
+	}
+}
+
+// Callback defines an outbound HTTP request (a webhook) made by the server to
+// a URL provided by the clients of the endpoint, e.g. to notify them of events
+// related to the request. The generated server package defines a function
+// named after the endpoint and the callback (e.g. SendSubscribeOnEventCallback)
+// that sends the callback with a goahttp.WebhookSender: the function encodes
+// the payload in the JSON request body, signs the request with HMAC-SHA256
+// and retries failed deliveries. Callbacks are documented in the callbacks
+// section of the OpenAPI 3 specification.
+//
+// Callback must appear in a HTTP endpoint expression.
+//
+// Callback takes the name of the callback, the type of the callback payload and
+// an optional DSL function. The DSL may use Description, URL to set the OpenAPI
+// runtime expression that describes the callback URL and Retry to set the
+// delivery retry policy.
+//
+// Example:
+//
+//    var Notification = Type("Notification", func() {
+//        Attribute("id", String)
+//        Attribute("kind", String)
+//    })
+//
+//    var _ = Service("events", func() {
+//        Method("subscribe", func() {
+//            Payload(func() {
+//                Attribute("callback_url", String)
+//            })
+//            HTTP(func() {
+//                POST("/subscriptions")
+//                Callback("onEvent", Notification, func() {
+//                    Description("Sent when an event occurs.")
+//                    URL("{$request.body#/callback_url}")
+//                    Retry(5, "1s")
+//                })
+//            })
+//        })
+//    })
+//
+func Callback(name string, payload any, fn ...func()) {
+	e, ok := eval.Current().(*expr.HTTPEndpointExpr)
+	if !ok {
+		eval.IncompatibleDSL()
+		return
+	}
+	if len(fn) > 1 {
+		eval.ReportError("too many arguments given to Callback")
+		return
+	}
+	dt, ok := payload.(expr.DataType)
+	if !ok {
+		eval.InvalidArgError("type", payload)
+		return
+	}
+	c := &expr.HTTPCallbackExpr{
+		Name:     name,
+		Payload:  &expr.AttributeExpr{Type: dt},
+		Endpoint: e,
+	}
+	if len(fn) == 1 {
+		if !eval.Execute(fn[0], c) {
+			return
+		}
+	}
+	e.Callbacks = append(e.Callbacks, c)
+}
+
+// Retry sets the delivery retry policy of a callback. The callback is sent at
+// most attempts times, failed deliveries (transport errors, 429 and 5xx
+// responses) are retried after waiting for the given backoff duration which
+// doubles after each retry. Callbacks are sent only once by default.
+//
+// Retry must appear in a Callback expression.
+//
+// Retry takes the maximum number of attempts and the initial backoff duration
+// formatted as accepted by time.ParseDuration, e.g. "500ms".
+//
+// Example:
+//
+//    Callback("onEvent", Notification, func() {
+//        URL("{$request.body#/callback_url}")
+//        Retry(5, "1s")
+//    })
+//
+func Retry(attempts int, backoff string) {
+	c, ok := eval.Current().(*expr.HTTPCallbackExpr)
+	if !ok {
+		eval.IncompatibleDSL()
+		return
+	}
+	d, err := time.ParseDuration(backoff)
+	if err != nil {
+		eval.ReportError("invalid backoff duration %q: %s", backoff, err)
+		return
+	}
+	c.MaxAttempts = attempts
+	c.Backoff = d
+}
+
+// CloudEvents indicates that the endpoint receives CloudEvents using the
+// CloudEvents HTTP binding, e.g. from an eventing mesh such as Knative. The
+// generated handler accepts both binary and structured content modes: events
+// sent in structured mode (with the application/cloudevents+json content type)
+// are converted to binary mode before being decoded so that the event
+// attributes are always available in "ce-" prefixed headers and the event data
+// in the request body. Requests that are not CloudEvents or that are missing
+// one of the required event attributes are rejected. The payload attributes
+// may be mapped to the event attributes using the Header DSL and to the event
+// data using the Body DSL. The generated client sends events in binary mode.
+//
+// CloudEvents must appear in a HTTP endpoint expression.
+//
+// Example:
+//
+//    var _ = Service("inventory", func() {
+//        Method("receive", func() {
+//            Payload(func() {
+//                Attribute("id", String)
+//                Attribute("source", String)
+//                Attribute("type", String)
+//                Attribute("bottle", Bottle)
+//            })
+//            HTTP(func() {
+//                POST("/events")
+//                CloudEvents()
+//                Header("id:ce-id")
+//                Header("source:ce-source")
+//                Header("type:ce-type")
+//                Body("bottle")
+//            })
+//        })
+//    })
+//
+func CloudEvents() {
+	e, ok := eval.Current().(*expr.HTTPEndpointExpr)
+	if !ok {
+		eval.IncompatibleDSL()
+		return
+	}
+	e.CloudEvents = true
+}
+
+// ChunkedResult streams the endpoint result array in the response body. The
+// generated server writes the JSON array element by element and flushes the
+// response every chunk size elements using the chunked transfer encoding so
+// that clients receive the first elements before the whole array is encoded.
+// Large list results are thus never buffered in full by the server. Responses
+// that are not rendered as plain JSON (e.g. because the client negotiated
+// XML) are encoded as a whole.
+//
+// ChunkedResult must appear in a HTTP endpoint expression. The method result
+// must be an array.
+//
+// ChunkedResult accepts an optional argument which is the number of elements
+// written before each flush, 100 by default.
+//
+// Example:
+//
+//    var _ = Service("cellar", func() {
+//        Method("list", func() {
+//            Result(ArrayOf(Bottle))
+//            HTTP(func() {
+//                GET("/bottles")
+//                ChunkedResult(500) // flush every 500 bottles
+//            })
+//        })
+//    })
+//
+func ChunkedResult(size ...int) {
+	e, ok := eval.Current().(*expr.HTTPEndpointExpr)
+	if !ok {
+		eval.IncompatibleDSL()
+		return
+	}
+	if len(size) > 1 {
+		eval.ReportError("too many arguments given to ChunkedResult")
+		return
+	}
+	e.ChunkSize = 100
+	if len(size) == 1 {
+		if size[0] <= 0 {
+			eval.ReportError("ChunkedResult chunk size must be strictly positive, got %d", size[0])
+			return
+		}
+		e.ChunkSize = size[0]
+	}
+}
+
+// Idempotent makes the endpoint safe to retry: the response to the first
+// request made with a given idempotency key is recorded and replayed to the
+// requests retried with the same key without calling the method again.
+// Requests made while a request with the same key is in flight receive a 409
+// Conflict response. The generated server defines a UseIdempotency method that
+// applies the http/middleware Idempotency middleware to the idempotent
+// endpoints given a store for the recorded responses. The idempotency key
+// header is documented in the generated OpenAPI specifications.
+//
+// Idempotent must appear in a HTTP endpoint expression. The endpoint routes
+// must use unsafe HTTP methods (e.g. POST or PATCH).
+//
+// Idempotent accepts an optional argument which is the name of the request
+// header holding the idempotency key, "Idempotency-Key" by default.
+//
+// Example:
+//
+//    var _ = Service("orders", func() {
+//        Method("create", func() {
+//            Payload(Order)
+//            Result(OrderID)
+//            HTTP(func() {
+//                POST("/orders")
+//                Idempotent()
+//            })
+//        })
+//    })
+//
+func Idempotent(header ...string) {
+	e, ok := eval.Current().(*expr.HTTPEndpointExpr)
+	if !ok {
+		eval.IncompatibleDSL()
+		return
+	}
+	if len(header) > 1 {
+		eval.ReportError("too many arguments given to Idempotent")
+		return
+	}
+	e.IdempotencyHeader = "Idempotency-Key"
+	if len(header) == 1 {
+		if header[0] == "" {
+			eval.ReportError("Idempotent header name cannot be empty")
+			return
+		}
+		e.IdempotencyHeader = header[0]
+	}
+}
+
+// CacheControl declares the endpoint responses cacheable. The generated
+// server sets the Cache-Control header of the successful responses to the
+// given value and the Vary header to the given request headers if any. The
+// generated server also defines a UseCache method that applies the
+// http/middleware ResponseCache middleware to the cacheable endpoints so that
+// the responses are served from a cache for the duration given by the max-age
+// directive, and while being refreshed for the duration given by the
+// stale-while-revalidate directive.
+//
+// CacheControl must appear in a HTTP endpoint expression. The endpoint routes
+// must use the GET or HEAD HTTP methods.
+//
+// CacheControl accepts the value of the Cache-Control header (e.g.
+// "public, max-age=60") as first argument followed by the names of the request
+// headers that identify different representations of the responses.
+//
+// Example:
+//
+//    var _ = Service("catalog", func() {
+//        Method("list", func() {
+//            Result(ArrayOf(Product))
+//            HTTP(func() {
+//                GET("/products")
+//                CacheControl("public, max-age=300, stale-while-revalidate=60", "Accept-Language")
+//            })
+//        })
+//    })
+//
+func CacheControl(value string, vary ...string) {
+	e, ok := eval.Current().(*expr.HTTPEndpointExpr)
+	if !ok {
+		eval.IncompatibleDSL()
+		return
+	}
+	if value == "" {
+		eval.ReportError("CacheControl value cannot be empty")
+		return
+	}
+	e.CacheControl = value
+	e.Vary = append(e.Vary, vary...)
+}
+
 // Body describes a HTTP request or response body.
 //
 // Body must appear in a Method HTTP expression to define the request body or in
@@ -1082,6 +1596,55 @@ func ContentType(typ string) {
 	}
 }
 
+// ContentDisposition maps a result attribute to the Content-Disposition
+// response header. The generated server writes the header with the given
+// disposition type and the attribute value as filename, the generated client
+// parses the header and initializes the attribute with the filename. The
+// content type of the download may be mapped to a result attribute as well
+// using the Content-Type header.
+//
+// ContentDisposition must appear in a Response expression.
+//
+// ContentDisposition accepts two arguments: the disposition type, one of
+// "attachment" or "inline", and the name of the String result attribute that
+// holds the filename.
+//
+// Example:
+//
+//    var _ = Method("download", func() {
+//        Payload(String)
+//        Result(func() {
+//            Attribute("filename", String)
+//            Attribute("content_type", String)
+//        })
+//        HTTP(func() {
+//            GET("/files/{*path}")
+//            SkipResponseBodyEncodeDecode()
+//            Response(StatusOK, func() {
+//                ContentDisposition("attachment", "filename")
+//                Header("content_type:Content-Type")
+//            })
+//        })
+//    })
+//
+func ContentDisposition(disposition, filename string) {
+	res, ok := eval.Current().(*expr.HTTPResponseExpr)
+	if !ok {
+		eval.IncompatibleDSL()
+		return
+	}
+	if disposition != "attachment" && disposition != "inline" {
+		eval.ReportError("invalid content disposition %q, must be one of \"attachment\" or \"inline\"", disposition)
+		return
+	}
+	if filename == "" {
+		eval.ReportError("content disposition filename attribute cannot be empty")
+		return
+	}
+	Header(filename + ":Content-Disposition")
+	res.Headers.AddMeta("http:content-disposition", disposition)
+}
+
 // headers returns the mapped attribute containing the headers for the given
 // expression if it's either the root, a service or an endpoint - nil otherwise.
 func headers(exp eval.Expression) *expr.MappedAttributeExpr {
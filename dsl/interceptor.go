@@ -0,0 +1,229 @@
+package dsl
+
+import (
+	"goa.design/goa/v3/eval"
+	"goa.design/goa/v3/expr"
+)
+
+// Interceptor defines an interceptor invoked around the endpoints of the
+// methods it applies to. Interceptors implement concerns such as caching,
+// enrichment or field-level encryption that need typed access to the method
+// payloads and results. The generated service package defines the
+// ServerInterceptors and ClientInterceptors interfaces with one method per
+// interceptor as well as accessors that read and write the attributes listed
+// in the interceptor DSL so that accessing an attribute that is not listed
+// fails at compile time.
+//
+// Interceptor is a top level DSL. The interceptor is applied to the server or
+// client endpoints of the service methods using ServerInterceptor and
+// ClientInterceptor respectively.
+//
+// Interceptor takes the name of the interceptor and an optional DSL function.
+// The DSL may use Description, ReadPayload, WritePayload, ReadResult and
+// WriteResult. The attributes listed in ReadPayload, WritePayload, ReadResult
+// and WriteResult must be defined by the payloads and results of all the
+// methods the interceptor applies to with the same types.
+//
+// Example:
+//
+//    var Cache = Interceptor("Cache", func() {
+//        Description("Serves cached results")
+//        ReadPayload(func() {
+//            Attribute("id")
+//        })
+//        WriteResult(func() {
+//            Attribute("cachedAt")
+//        })
+//    })
+//
+//    var _ = Service("inventory", func() {
+//        ServerInterceptor(Cache)
+//        Method("get", func() {
+//            Payload(func() {
+//                Attribute("id", String)
+//                Required("id")
+//            })
+//            Result(func() {
+//                Attribute("name", String)
+//                Attribute("cachedAt", String)
+//            })
+//        })
+//    })
+//
+func Interceptor(name string, fn ...func()) *expr.InterceptorExpr {
+	if _, ok := eval.Current().(eval.TopExpr); !ok {
+		eval.IncompatibleDSL()
+		return nil
+	}
+	if len(fn) > 1 {
+		eval.ReportError("too many arguments given to Interceptor")
+		return nil
+	}
+	for _, i := range expr.Root.Interceptors {
+		if i.Name == name {
+			eval.ReportError("interceptor %#v already defined", name)
+			return nil
+		}
+	}
+	i := &expr.InterceptorExpr{Name: name}
+	if len(fn) == 1 {
+		i.DSLFunc = fn[0]
+	}
+	expr.Root.Interceptors = append(expr.Root.Interceptors, i)
+	return i
+}
+
+// ReadPayload lists the payload attributes read by an interceptor.
+//
+// ReadPayload must appear in an Interceptor expression.
+//
+// ReadPayload takes one argument: a DSL function that lists the attributes
+// using Attribute. The attribute types are defined by the method payloads and
+// need not be repeated.
+//
+// Example:
+//
+//    var Cache = Interceptor("Cache", func() {
+//        ReadPayload(func() {
+//            Attribute("id")
+//        })
+//    })
+//
+func ReadPayload(fn func()) {
+	if i, ok := eval.Current().(*expr.InterceptorExpr); ok {
+		i.ReadPayload = interceptorAttributes(fn)
+		return
+	}
+	eval.IncompatibleDSL()
+}
+
+// WritePayload lists the payload attributes written by an interceptor.
+//
+// WritePayload must appear in an Interceptor expression.
+//
+// WritePayload takes one argument: a DSL function that lists the attributes
+// using Attribute.
+//
+// Example:
+//
+//    var Tenant = Interceptor("Tenant", func() {
+//        WritePayload(func() {
+//            Attribute("tenantID")
+//        })
+//    })
+//
+func WritePayload(fn func()) {
+	if i, ok := eval.Current().(*expr.InterceptorExpr); ok {
+		i.WritePayload = interceptorAttributes(fn)
+		return
+	}
+	eval.IncompatibleDSL()
+}
+
+// ReadResult lists the result attributes read by an interceptor.
+//
+// ReadResult must appear in an Interceptor expression.
+//
+// ReadResult takes one argument: a DSL function that lists the attributes
+// using Attribute.
+//
+// Example:
+//
+//    var Audit = Interceptor("Audit", func() {
+//        ReadResult(func() {
+//            Attribute("status")
+//        })
+//    })
+//
+func ReadResult(fn func()) {
+	if i, ok := eval.Current().(*expr.InterceptorExpr); ok {
+		i.ReadResult = interceptorAttributes(fn)
+		return
+	}
+	eval.IncompatibleDSL()
+}
+
+// WriteResult lists the result attributes written by an interceptor.
+//
+// WriteResult must appear in an Interceptor expression.
+//
+// WriteResult takes one argument: a DSL function that lists the attributes
+// using Attribute.
+//
+// Example:
+//
+//    var Cache = Interceptor("Cache", func() {
+//        WriteResult(func() {
+//            Attribute("cachedAt")
+//        })
+//    })
+//
+func WriteResult(fn func()) {
+	if i, ok := eval.Current().(*expr.InterceptorExpr); ok {
+		i.WriteResult = interceptorAttributes(fn)
+		return
+	}
+	eval.IncompatibleDSL()
+}
+
+// ServerInterceptor applies an interceptor to the server side endpoints of
+// the service methods. Interceptors are invoked in the order in which they
+// are applied, the service interceptors first.
+//
+// ServerInterceptor must appear in a Service or Method expression.
+//
+// ServerInterceptor takes one argument: the interceptor returned by
+// Interceptor.
+//
+// Example:
+//
+//    var _ = Service("inventory", func() {
+//        ServerInterceptor(Cache)
+//    })
+//
+func ServerInterceptor(i *expr.InterceptorExpr) {
+	switch e := eval.Current().(type) {
+	case *expr.ServiceExpr:
+		e.ServerInterceptors = append(e.ServerInterceptors, i)
+	case *expr.MethodExpr:
+		e.ServerInterceptors = append(e.ServerInterceptors, i)
+	default:
+		eval.IncompatibleDSL()
+	}
+}
+
+// ClientInterceptor applies an interceptor to the client side endpoints of
+// the service methods. Interceptors are invoked in the order in which they
+// are applied, the service interceptors first.
+//
+// ClientInterceptor must appear in a Service or Method expression.
+//
+// ClientInterceptor takes one argument: the interceptor returned by
+// Interceptor.
+//
+// Example:
+//
+//    var _ = Service("inventory", func() {
+//        Method("get", func() {
+//            ClientInterceptor(Cache)
+//        })
+//    })
+//
+func ClientInterceptor(i *expr.InterceptorExpr) {
+	switch e := eval.Current().(type) {
+	case *expr.ServiceExpr:
+		e.ClientInterceptors = append(e.ClientInterceptors, i)
+	case *expr.MethodExpr:
+		e.ClientInterceptors = append(e.ClientInterceptors, i)
+	default:
+		eval.IncompatibleDSL()
+	}
+}
+
+// interceptorAttributes executes fn to build the list of attributes accessed
+// by an interceptor.
+func interceptorAttributes(fn func()) *expr.AttributeExpr {
+	att := &expr.AttributeExpr{Type: &expr.Object{}}
+	eval.Execute(fn, att)
+	return att
+}
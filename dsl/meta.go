@@ -83,6 +83,32 @@ import (
 //	     })
 //	})
 //
+// - "struct:field:decode", "struct:field:encode" and "struct:field:validate"
+// provide the functions used by the generated code to handle the values of
+// attributes whose type is overridden with "struct:field:type". The decode
+// function has the signature func(string) (T, error) and parses the path,
+// query string, header and cookie values as well as the CLI flags. The encode
+// function has the signature func(T) string and formats the values sent in
+// the path, query string, headers and cookies. The validate function has the
+// signature func(T) error and replaces the validations defined in the design.
+// The import path and package name of the functions may be given as the second
+// and third parameters like for "struct:field:type". Applicable to attributes
+// only.
+//
+//	var Order = Type("Order", func() {
+//	    Attribute("id", String, func() {
+//	        Meta("struct:field:type", "uuid.UUID", "github.com/google/uuid")
+//	        Meta("struct:field:decode", "uuid.Parse")
+//	        Meta("struct:field:encode", "uuid.UUID.String")
+//	    })
+//	    Attribute("total", String, func() {
+//	        Meta("struct:field:type", "decimal.Decimal", "github.com/shopspring/decimal")
+//	        Meta("struct:field:decode", "decimal.NewFromString")
+//	        Meta("struct:field:encode", "decimal.Decimal.String")
+//	        Meta("struct:field:validate", "money.ValidateTotal", "example.com/money")
+//	    })
+//	})
+//
 // - "struct:field:proto" overrides the generated protobuf field type. If the
 // type is defined in a separate proto file, the last three elements define the
 // proto file import path, Go type name and Go import path respectively.
@@ -103,15 +129,36 @@ import (
 //	    })
 //	})
 //
+// "struct:field:proto" may also be set on a user type to reuse a message
+// defined in an existing proto file: the generated proto file imports the file
+// instead of defining the message and the gRPC request and response messages
+// of the methods whose payload or result is the user type (and that do not
+// define metadata) are the external message. The user type attributes must
+// match the message fields, the generated code converts between the two. The
+// nested user types must also reference their external messages.
+//
+//	var Timestamp = Type("Timestamp", func() {
+//	    Meta("struct:field:proto", "google.protobuf.Timestamp", "google/protobuf/timestamp.proto", "Timestamp", "google.golang.org/protobuf/types/known/timestamppb")
+//	    Field(1, "seconds", Int64, func() {
+//	        Meta("struct:field:proto", "int64")
+//	    })
+//	    Field(2, "nanos", Int32, func() {
+//	        Meta("struct:field:proto", "int32")
+//	    })
+//	})
+//
 // - "struct:tag:xxx" sets a generated Go struct field tag and overrides tags
-// that Goa would otherwise set. If the metadata value is a slice then the
-// strings are joined with the space character as separator. Applicable to
-// attributes only.
+// that Goa would otherwise set. Tags other than the form, json and xml tags
+// (e.g. bson, db, validate or yaml) are added alongside the tags set by the
+// transport. If the metadata value is a slice then the strings are joined
+// with the comma character as separator. Applicable to attributes only.
 //
 //	var MyType = Type("MyType", func() {
 //	    Attribute("ssn", String, "User SSN", func() {
 //	        Meta("struct:tag:json", "SSN,omitempty")
 //	        Meta("struct:tag:xml", "SSN,omitempty")
+//	        Meta("struct:tag:bson", "ssn")
+//	        Meta("struct:tag:validate", "required", "len=11")
 //	    })
 //	})
 //
@@ -222,6 +269,174 @@ import (
 //	    Attribute("name", String)
 //	    Meta("openapi:typename", "Bar")
 //	})
+//
+// - "jsonapi:type" renders the HTTP responses of the methods returning the
+// result type (or collections of the result type) following the JSON:API
+// convention (https://jsonapi.org). The value is the JSON:API resource type.
+// The "id" attribute of the result type provides the resource id, the links
+// defined with Links are rendered as the resource links and the other
+// attributes as the resource attributes. Clients may request sparse fieldsets
+// with the "fields[TYPE]" query string parameter. Errors are rendered as
+// JSON:API error objects. The generated OpenAPI specifications document the
+// responses with the "application/vnd.api+json" media type and the schema of
+// the JSON:API documents. Applicable to result types only.
+//
+// - "jsonapi:relationship" renders the attribute of a JSON:API result type as
+// a relationship. The value is the JSON:API type of the related resources.
+// Applicable to attributes only.
+//
+//	var Bottle = ResultType("application/vnd.bottle", func() {
+//	    Meta("jsonapi:type", "bottles")
+//	    Attributes(func() {
+//	        Attribute("id", Int)
+//	        Attribute("name", String)
+//	        Attribute("account", Account, func() {
+//	            Meta("jsonapi:relationship", "accounts")
+//	        })
+//	    })
+//	})
+//
+// - "graphql:operation" sets the GraphQL root type of the field generated for
+// the method by "goa gen --graphql", the value is "query" or "mutation". The
+// methods bound to a HTTP GET route are queries by default, the others are
+// mutations. Applicable to methods only.
+//
+// - "graphql:skip" excludes the method from the GraphQL gateway. Applicable
+// to methods only.
+//
+//	Method("list", func() {
+//	    Meta("graphql:operation", "query")
+//	    Payload(ListCriteria)
+//	    Result(CollectionOf(Bottle))
+//	})
+//
+// - "loadtest:weight" sets the relative frequency of the requests sent to the
+// method HTTP endpoint by the load test scenario generated with "goa gen
+// --loadtest", the value is a positive integer and defaults to 1.
+// "loadtest:skip" excludes the method from the scenario. Applicable to
+// methods only.
+//
+//	Method("list", func() {
+//	    Meta("loadtest:weight", "10")
+//	})
+//
+// - "http:json:fast" generates AppendJSON and MarshalJSON methods for the HTTP
+// server response body types so that encoding them does not rely on
+// reflection. The encoding is identical to the one produced by encoding/json.
+// Types with fields that use custom struct tags or Go types are not affected.
+// The generated json_test.go file benchmarks the methods against
+// encoding/json. Applicable to API and services.
+//
+//	var _ = Service("catalog", func() {
+//	    Meta("http:json:fast")
+//	})
+//
+// - "grpc:web" serves the gRPC-Web and Connect requests made to the gRPC
+// endpoints of the service on the port of the HTTP server generated by
+// "goa example" so that browser clients may call them directly. Applicable
+// to services that define both the HTTP and gRPC transports.
+//
+//	var _ = Service("calc", func() {
+//	    Meta("grpc:web")
+//	})
+//
+// - "grpc:transcode" generates the gen/grpc/<service>/transcoder package whose
+// NewEndpoints function builds the service endpoints from a gRPC client
+// connection. Giving these endpoints to the service HTTP server makes it
+// translate the HTTP requests into calls to the gRPC server so that a single
+// service implementation backs both transports while the HTTP routes remain
+// defined in the design. Streaming methods and methods that skip the body
+// encoding cannot be transcoded. Applicable to services that define both the
+// HTTP and gRPC transports.
+//
+//	var _ = Service("calc", func() {
+//	    Meta("grpc:transcode")
+//	})
+//
+// - "grpc:health" and "grpc:reflection" set to "false" prevent the gRPC server
+// generated by "goa example" from registering the standard grpc.health.v1
+// health checking service and the server reflection service respectively.
+// Both services are registered by default so that tools such as grpcurl and
+// the Kubernetes gRPC probes work out of the box. Applicable to API only.
+//
+//	var _ = API("calc", func() {
+//	    Meta("grpc:reflection", "false")
+//	})
+//
+// - "feature:flag" gates the method HTTP endpoint with the feature flag of the
+// given name so that it can be shipped dark. The generated server defines a
+// UseFeatureFlags method that wraps the handlers of the gated methods with a
+// middleware consulting a middleware.FlagProvider on each request. Requests
+// made while the flag is disabled receive a 404 Not Found response or the
+// status code set with "feature:flag:status" (e.g. "403"). Applicable to
+// services and methods, the method meta takes precedence.
+//
+//	Method("checkout", func() {
+//	    Meta("feature:flag", "new-checkout")
+//	    Meta("feature:flag:status", "403")
+//	})
+//
+// - "slo:target" declares the service level objective of the method as the
+// percentage of requests that must succeed (e.g. "99.9") and "slo:latency" the
+// maximum duration of a successful request (e.g. "300ms"). The generated HTTP
+// server defines a UseSLOs method that records the requests handled by the
+// methods with a middleware.SLOTracker which tracks the compliance of the
+// objectives and calls the burn rate alert callbacks. Requests that result in
+// a 5xx response count as failed. Applicable to services and methods, the
+// method meta takes precedence.
+//
+//	Method("list", func() {
+//	    Meta("slo:target", "99.9")
+//	    Meta("slo:latency", "300ms")
+//	})
+//
+// - "budget:response-size" sets the maximum size in bytes of the response
+// bodies of the method and "budget:latency" the maximum duration of its
+// requests. The generated HTTP server defines a UseBudgets method that applies
+// the http/middleware Budget middleware to the methods: over-budget responses
+// are rejected, truncated or only reported depending on the middleware options
+// and the requests that exceed their budget are counted in the
+// http_budget_exceeded_total metric. The generated OpenAPI specifications
+// document the budgets with the x-goa-budget operation extension. Applicable
+// to services and methods, the method meta takes precedence.
+//
+//	Method("export", func() {
+//	    Meta("budget:response-size", "1048576")
+//	    Meta("budget:latency", "2s")
+//	})
+//
+// - "http:locale" advertises that the HTTP endpoints negotiate the locale of
+// the requests: the generated OpenAPI specifications document the
+// Accept-Language request header and the time zone request header named by
+// the meta value, "Time-Zone" by default. The headers are parsed by the
+// http/middleware Locale middleware. Applicable to APIs, services and methods,
+// the method meta takes precedence over the service meta which takes
+// precedence over the API meta.
+//
+//	var _ = API("calc", func() {
+//	    Meta("http:locale", "X-Time-Zone")
+//	})
+//
+// - "discovery:tag" lists the tags of the service instances registered with a
+// service registry. The generated service packages define a DiscoveryTags
+// variable listing the tags of the API and of the service which may be given
+// to the goa.design/goa/v3/discovery package registrars. Applicable to APIs and
+// services.
+//
+//	var _ = Service("calc", func() {
+//	    Meta("discovery:tag", "v1", "public")
+//	})
+//
+// - "sensitive" marks the attribute as holding sensitive data, see Sensitive.
+// "sensitive:encrypt" also encrypts the attribute at the transport boundary,
+// see Encrypted. Applicable to attributes only.
+//
+//	var MyType = Type("MyType", func() {
+//	    Attribute("ssn", String, func() {
+//	        Meta("sensitive:encrypt")
+//	        Meta("sensitive")
+//	    })
+//	})
 func Meta(name string, value ...string) {
 	appendMeta := func(meta expr.MetaExpr, name string, value ...string) expr.MetaExpr {
 		if meta == nil {
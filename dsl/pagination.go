@@ -0,0 +1,46 @@
+package dsl
+
+import (
+	"goa.design/goa/v3/eval"
+	"goa.design/goa/v3/expr"
+)
+
+// Paginate declares that the method returns its results one page at a time.
+// The generated service client defines an iterator type for the method that
+// walks the pages transparently as well as a Collect method that returns all
+// the items.
+//
+// Paginate must appear in a Method expression.
+//
+// Paginate takes three arguments: the name of the payload attribute that
+// identifies the requested page, the name of the result attribute that
+// identifies the next page and the name of the result array attribute that
+// holds the items of the page. Both cursor attributes must have the same
+// primitive type, the next cursor must be empty or zero on the last page.
+//
+// Example:
+//
+//    var _ = Method("list", func() {
+//        Payload(func() {
+//            Attribute("page_token", String)
+//            Attribute("page_size", Int)
+//        })
+//        Result(func() {
+//            Attribute("bottles", ArrayOf(Bottle))
+//            Attribute("next_page_token", String)
+//        })
+//        Paginate("page_token", "next_page_token", "bottles")
+//    })
+//
+func Paginate(cursor, nextCursor, items string) {
+	m, ok := eval.Current().(*expr.MethodExpr)
+	if !ok {
+		eval.IncompatibleDSL()
+		return
+	}
+	if cursor == "" || nextCursor == "" || items == "" {
+		eval.ReportError("pagination cursor, next cursor and items attribute names cannot be empty")
+		return
+	}
+	m.Pagination = &expr.PaginationExpr{Cursor: cursor, NextCursor: nextCursor, Items: items, Method: m}
+}
@@ -0,0 +1,58 @@
+package dsl
+
+import (
+	"goa.design/goa/v3/eval"
+	"goa.design/goa/v3/expr"
+)
+
+// Patch defines a payload attribute holding a JSON Patch (RFC 6902) or JSON
+// Merge Patch (RFC 7396) document that describes changes to apply to a value
+// of the target type. The attribute Go type is *goa.Patch, decoding a JSON
+// array produces a JSON Patch and decoding a JSON object a JSON Merge Patch so
+// that the endpoints accept both the application/json-patch+json and the
+// application/merge-patch+json content types. The generated clients set the
+// request Content-Type header accordingly when the patch is the request body.
+//
+// The generated service package defines an Apply function for each patch
+// attribute, e.g. ApplyUpdatePatch for the "patch" attribute of the "update"
+// method. The function applies the patch to a value of the target type and
+// runs the validations defined on the target type against the result. The
+// patch locations designate the target type attributes using their design
+// names, e.g. "/address/street_name".
+//
+// Patch must appear in a Payload expression. The target type must be an
+// object user type. The other parameters and usage of Patch are the same as
+// the Attribute function after the type.
+//
+// Example:
+//
+//    var _ = Service("account", func() {
+//        Method("update", func() {
+//            Payload(func() {
+//                Attribute("id", String)
+//                Patch("patch", Account, "Changes to apply to the account")
+//                Required("id", "patch")
+//            })
+//            Result(Account)
+//            HTTP(func() {
+//                PATCH("/{id}")
+//                Body("patch")
+//            })
+//        })
+//    })
+//
+func Patch(name string, target expr.UserType, args ...any) {
+	if _, ok := eval.Current().(*expr.AttributeExpr); !ok {
+		eval.IncompatibleDSL()
+		return
+	}
+	if target == nil {
+		eval.ReportError("patch target type cannot be nil")
+		return
+	}
+	args = useDSL(args, func() {
+		Meta("struct:field:type", "*goa.Patch", "goa.design/goa/v3/pkg", "goa")
+		Meta("patch:target", target.Name())
+	})
+	Attribute(name, append([]any{expr.Any}, args...)...)
+}
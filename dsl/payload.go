@@ -198,3 +198,131 @@ func methodDSL(m *expr.MethodExpr, suffix string, p any, args ...any) *expr.Attr
 	}
 	return att
 }
+
+// Filter defines the attributes of a method payload that clients use to filter
+// the method results. Filter attributes are never required. The HTTP transport
+// maps each filter attribute to a query string parameter named after the
+// attribute and prefixed with "filter", e.g. "?filter[name]=foo", unless the
+// attribute is explicitly mapped to a different HTTP request element. The
+// generated code decodes the parameters into the typed payload fields and
+// rejects requests that use undeclared filters.
+//
+// Filter must appear in a Payload or Type expression.
+//
+// Filter takes a single argument which is the DSL function defining the filter
+// attributes.
+//
+// Example:
+//
+//	Method("list", func() {
+//	    Payload(func() {
+//	        Filter(func() {
+//	            Attribute("name", String, "Filter bottles by name")
+//	            Attribute("vintage", Int, "Filter bottles by vintage")
+//	        })
+//	        Sort("name", "vintage")
+//	    })
+//	    Result(CollectionOf(Bottle))
+//	    HTTP(func() {
+//	        GET("/bottles") // ?filter[name]=merlot&sort=-vintage
+//	    })
+//	})
+func Filter(fn func()) {
+	parent, obj := criteriaParent()
+	if obj == nil {
+		return
+	}
+	existing := make(map[string]struct{}, len(*obj))
+	for _, nat := range *obj {
+		existing[nat.Name] = struct{}{}
+	}
+	if !eval.Execute(fn, parent) {
+		return
+	}
+	for _, nat := range *obj {
+		if _, ok := existing[nat.Name]; ok {
+			continue
+		}
+		if parent.IsRequired(nat.Name) {
+			eval.ReportError("filter attribute %q cannot be required", nat.Name)
+			continue
+		}
+		if nat.Attribute.Meta == nil {
+			nat.Attribute.Meta = expr.MetaExpr{}
+		}
+		nat.Attribute.Meta[expr.FilterMeta] = nil
+	}
+}
+
+// Sort defines the "sort" attribute of a method payload that clients use to
+// sort the method results. The attribute is an array of strings, each element
+// is the name of one of the given fields optionally prefixed with "-" to sort
+// in descending order. The elements are listed by order of precedence. The HTTP
+// transport maps the attribute to the "sort" query string parameter, e.g.
+// "?sort=name&sort=-vintage", unless the attribute is explicitly mapped to a
+// different HTTP request element. The generated code validates that only the
+// given fields are used.
+//
+// Sort must appear in a Payload or Type expression.
+//
+// Sort takes the names of the fields that may be used to sort the results.
+//
+// Example:
+//
+//	Method("list", func() {
+//	    Payload(func() {
+//	        Sort("name", "vintage")
+//	    })
+//	    Result(CollectionOf(Bottle))
+//	    HTTP(func() {
+//	        GET("/bottles")
+//	    })
+//	})
+func Sort(fields ...string) {
+	if len(fields) == 0 {
+		eval.ReportError("Sort requires at least one field")
+		return
+	}
+	_, obj := criteriaParent()
+	if obj == nil {
+		return
+	}
+	values := make([]any, 0, 2*len(fields))
+	for _, f := range fields {
+		values = append(values, f, "-"+f)
+	}
+	obj.Set("sort", &expr.AttributeExpr{
+		Type: &expr.Array{ElemType: &expr.AttributeExpr{
+			Type:       expr.String,
+			Validation: &expr.ValidationExpr{Values: values},
+		}},
+		Description: "Sort order, the name of a field optionally prefixed with - to sort in descending order.",
+		Meta:        expr.MetaExpr{expr.SortMeta: nil},
+	})
+}
+
+// criteriaParent returns the payload attribute and object the Filter and Sort
+// DSLs apply to, it reports an error and returns nil if the current DSL
+// context is not an object.
+func criteriaParent() (*expr.AttributeExpr, *expr.Object) {
+	var parent *expr.AttributeExpr
+	switch def := eval.Current().(type) {
+	case *expr.AttributeExpr:
+		parent = def
+	case expr.CompositeExpr:
+		parent = def.Attribute()
+	}
+	if parent == nil {
+		eval.IncompatibleDSL()
+		return nil, nil
+	}
+	if parent.Type == nil {
+		parent.Type = &expr.Object{}
+	}
+	obj, ok := parent.Type.(*expr.Object)
+	if !ok {
+		eval.ReportError("can't define filter or sort attributes on attribute of type %s", parent.Type.Name())
+		return nil, nil
+	}
+	return parent, obj
+}
@@ -0,0 +1,126 @@
+package dsl
+
+import (
+	"goa.design/goa/v3/eval"
+	"goa.design/goa/v3/expr"
+)
+
+// Policy defines the authorization policy of the service methods. Policies
+// are enforced after the requests have been authenticated: the generated
+// service package defines an Authorizer interface that the service must
+// implement and the generated endpoints call its Authorize method with the
+// policy of the method and the request payload. Requests denied by the
+// authorizer receive a 403 Forbidden HTTP response or a PermissionDenied gRPC
+// status listing the missing roles and permissions.
+//
+// Policy must appear in a Service or Method expression. The policy defined in
+// a Method overrides the policy defined in the Service.
+//
+// Policy takes one argument: a DSL function that uses Roles, Permissions and
+// Rule to describe the policy.
+//
+// Example:
+//
+//    var _ = Service("calc", func() {
+//        Security(OAuth2)
+//        Policy(func() {
+//            Roles("admin", "operator")
+//        })
+//        Method("divide", func() {
+//            Policy(func() {
+//                Permissions("calc:read", "calc:divide")
+//                Rule("input.payload.denominator != 0")
+//            })
+//        })
+//    })
+//
+func Policy(fn func()) {
+	var parent eval.Expression
+	switch actual := eval.Current().(type) {
+	case *expr.ServiceExpr, *expr.MethodExpr:
+		parent = actual
+	default:
+		eval.IncompatibleDSL()
+		return
+	}
+	p := &expr.PolicyExpr{Parent: parent}
+	if !eval.Execute(fn, p) {
+		return
+	}
+	switch actual := parent.(type) {
+	case *expr.ServiceExpr:
+		actual.Policy = p
+	case *expr.MethodExpr:
+		actual.Policy = p
+	}
+}
+
+// Roles lists the roles authorized by a policy, callers must have at least one
+// of the roles to be authorized.
+//
+// Roles must appear in a Policy expression.
+//
+// Roles accepts one or more role names.
+//
+// Example:
+//
+//    Policy(func() {
+//        Roles("admin", "operator")
+//    })
+//
+func Roles(roles ...string) {
+	p, ok := eval.Current().(*expr.PolicyExpr)
+	if !ok {
+		eval.IncompatibleDSL()
+		return
+	}
+	p.Roles = append(p.Roles, roles...)
+}
+
+// Permissions lists the permissions required by a policy, callers must have
+// all the permissions to be authorized. Permissions are typically the OAuth2
+// or JWT scopes granted to the caller.
+//
+// Permissions must appear in a Policy expression.
+//
+// Permissions accepts one or more permission names.
+//
+// Example:
+//
+//    Policy(func() {
+//        Permissions("account:read", "account:write")
+//    })
+//
+func Permissions(perms ...string) {
+	p, ok := eval.Current().(*expr.PolicyExpr)
+	if !ok {
+		eval.IncompatibleDSL()
+		return
+	}
+	p.Permissions = append(p.Permissions, perms...)
+}
+
+// Rule sets an authorization expression evaluated by the service authorizer
+// in addition to the roles and permissions of a policy. The syntax of the
+// expression depends on the authorizer, e.g. a Rego query when using Open
+// Policy Agent (see package goa.design/goa/v3/security/opa) or a matcher when
+// using Casbin (see package goa.design/goa/v3/security/casbin).
+//
+// Rule must appear in a Policy expression.
+//
+// Rule accepts one argument: the expression.
+//
+// Example:
+//
+//    Policy(func() {
+//        Rule("input.principal.subject == input.payload.owner")
+//    })
+//
+func Rule(expression string) {
+	p, ok := eval.Current().(*expr.PolicyExpr)
+	if !ok {
+		eval.IncompatibleDSL()
+		return
+	}
+	p.Rule = expression
+}
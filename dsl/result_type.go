@@ -196,6 +196,25 @@ func TypeName(name string) {
 //	        GRPC(func() {})
 //	    })
 //	})
+//
+//	// MyOtherMethod lets clients select the view with the "view" query
+//	// string parameter. The method code returns the view together with the
+//	// result and the generated code validates the parameter value.
+//	var _ = Service("MyService", func() {
+//	    Method("MyOtherMethod", func() {
+//	        Payload(func() {
+//	            Attribute("view", String, func() {
+//	                Enum("default", "extended")
+//	                Default("default")
+//	            })
+//	        })
+//	        Result(MyResultType)
+//	        HTTP(func() {
+//	            GET("/")
+//	            Param("view")
+//	        })
+//	    })
+//	})
 func View(name string, adsl ...func()) {
 	switch e := eval.Current().(type) {
 	case *expr.ResultTypeExpr:
@@ -473,6 +492,83 @@ func Attributes(fn func()) {
 	eval.Execute(fn, mt.AttributeExpr)
 }
 
+// Links defines the links to related resources rendered in the HTTP responses
+// that use the result type. The links are rendered in a "_links" attribute
+// following the HAL convention or in a "links" attribute following the
+// JSON:API convention if the "links:format" meta of the result type or of the
+// API is set to "jsonapi". The HTTP server sets the links of top level results
+// and of the elements of collections from the result attributes, links whose
+// path parameters have no value are omitted.
+//
+// Links must appear in a ResultType expression.
+//
+// Links accepts a single argument: the DSL listing the links using Link.
+//
+// Example:
+//
+//	var Bottle = ResultType("application/vnd.cellar.bottle", func() {
+//	    Attributes(func() {
+//	        Attribute("id", Int)
+//	        Attribute("account_id", Int)
+//	    })
+//	    Links(func() {
+//	        Link("self", "sommelier", "show")
+//	        Link("account", "account", "show", "id:account_id")
+//	    })
+//	})
+func Links(fn func()) {
+	mt, ok := eval.Current().(*expr.ResultTypeExpr)
+	if !ok {
+		eval.IncompatibleDSL()
+		return
+	}
+	eval.Execute(fn, &expr.LinksExpr{Parent: mt})
+}
+
+// Link defines a link to the HTTP endpoint of a service method. The link URL
+// is built from the path of the first route of the endpoint. The path
+// parameters are set from the result type attributes with the same name
+// unless mapped explicitly using the "parameter:attribute" syntax.
+//
+// Link must appear in a Links expression.
+//
+// Link accepts the name of the link relation, the names of the service and
+// method as defined in the design and optional path parameter mappings.
+//
+// Example:
+//
+//	Links(func() {
+//	    Link("self", "sommelier", "show") // path parameter "id" uses attribute "id"
+//	    Link("account", "account", "show", "id:account_id")
+//	})
+func Link(name, service, method string, params ...string) {
+	links, ok := eval.Current().(*expr.LinksExpr)
+	if !ok {
+		eval.IncompatibleDSL()
+		return
+	}
+	rt := links.Parent
+	for _, l := range rt.Links {
+		if l.Name == name {
+			eval.ReportError("multiple links named %q in result type %q", name, rt.TypeName)
+			return
+		}
+	}
+	link := &expr.LinkExpr{Name: name, Service: service, Method: method, Parent: rt}
+	for _, p := range params {
+		elems := strings.SplitN(p, ":", 2)
+		if len(elems) != 2 || elems[0] == "" || elems[1] == "" {
+			eval.ReportError("invalid link parameter mapping %q, must be of the form \"parameter:attribute\"", p)
+			return
+		}
+		if link.Params == nil {
+			link.Params = make(map[string]string)
+		}
+		link.Params[elems[0]] = elems[1]
+	}
+	rt.Links = append(rt.Links, link)
+}
+
 // mediaTypeToResultType returns the formatted identifier and the result type
 // name from the given identifier string. If the given identifier is invalid it
 // returns text/plain as the identifier and an error.
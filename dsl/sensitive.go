@@ -0,0 +1,68 @@
+package dsl
+
+import (
+	"goa.design/goa/v3/eval"
+	"goa.design/goa/v3/expr"
+)
+
+// Sensitive marks an attribute as holding sensitive data such as personal
+// information or secrets. The generated service types that define sensitive
+// attributes implement the goa.Redacter interface: their Redact method returns
+// a copy of the value where the sensitive string attributes are replaced with
+// goa.Redacted and the other sensitive attributes are zeroed so that the value
+// can be logged safely. Sensitive attributes are also excluded from the
+// generated examples, including the examples used in the OpenAPI
+// specifications and in the generated CLI usage.
+//
+// Sensitive must appear in an Attribute or Field expression.
+//
+// Sensitive takes no argument.
+//
+// Example:
+//
+//    var Signup = Type("Signup", func() {
+//        Attribute("email", String, func() {
+//            Sensitive()
+//        })
+//    })
+//
+func Sensitive() {
+	if a, ok := eval.Current().(*expr.AttributeExpr); ok {
+		a.AddMeta(expr.SensitiveMetaKey)
+		return
+	}
+	eval.IncompatibleDSL()
+}
+
+// Encrypted marks a sensitive attribute as being encrypted at the transport
+// boundary. Encrypted implies Sensitive. The generated service package defines
+// UseCodec methods on the server endpoints and on the client that apply a
+// user supplied goa.FieldCodec: the server decrypts the encrypted payload
+// attributes before calling the service and encrypts the encrypted result
+// attributes before the result is encoded, the client does the reverse so that
+// the values travel encrypted over the wire.
+//
+// Encrypted must appear in an Attribute or Field expression. Only the top level
+// string attributes of the payloads and results of non-streaming methods can be
+// encrypted.
+//
+// Encrypted takes no argument.
+//
+// Example:
+//
+//    Method("signup", func() {
+//        Payload(func() {
+//            Attribute("ssn", String, func() {
+//                Encrypted()
+//            })
+//        })
+//    })
+//
+func Encrypted() {
+	if a, ok := eval.Current().(*expr.AttributeExpr); ok {
+		a.AddMeta(expr.SensitiveMetaKey)
+		a.AddMeta(expr.EncryptedMetaKey)
+		return
+	}
+	eval.IncompatibleDSL()
+}
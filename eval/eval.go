@@ -22,6 +22,9 @@ func RunDSL() error {
 	if len(roots) == 0 {
 		return nil
 	}
+	if Trace != nil {
+		fmt.Fprintln(Trace, "execute")
+	}
 	executed := 0
 	recursed := 0
 	for executed < len(roots) {
@@ -50,6 +53,9 @@ func RunDSL() error {
 	if Context.Errors != nil {
 		return Context.Errors
 	}
+	if Trace != nil {
+		fmt.Fprintln(Trace, "finalize")
+	}
 	for _, root := range roots {
 		finalizeSet(ExpressionSet{root})
 		root.WalkSets(finalizeSet)
@@ -73,9 +79,16 @@ func Execute(fn func(), def Expression) bool {
 	if Context.Errors != nil {
 		startCount = len(Context.Errors.(MultiError))
 	}
+	var before []string
+	if Trace != nil {
+		before = traceStart(fn, def)
+	}
 	Context.Stack = append(Context.Stack, def)
 	fn()
 	Context.Stack = Context.Stack[:len(Context.Stack)-1]
+	if Trace != nil {
+		traceEnd(def, before)
+	}
 	var endCount int
 	if Context.Errors != nil {
 		endCount = len(Context.Errors.(MultiError))
@@ -228,7 +241,17 @@ func finalizeSet(set ExpressionSet) {
 			continue
 		}
 		if f, ok := def.(Finalizer); ok {
+			if Trace == nil {
+				f.Finalize()
+				continue
+			}
+			before := traceState(def)
 			f.Finalize()
+			after := traceState(def)
+			if strings.Join(before, "\n") != strings.Join(after, "\n") {
+				fmt.Fprintf(Trace, "  %s (%T)\n", exprName(def), def)
+				traceDiff("    ", before, after)
+			}
 		}
 	}
 }
@@ -0,0 +1,93 @@
+package eval
+
+import (
+	"fmt"
+	"io"
+	"reflect"
+	"runtime"
+	"strings"
+)
+
+var (
+	// Trace receives a trace of the design evaluation when not nil. The
+	// trace lists each DSL function execution with the expression being
+	// initialized, its Go type, the location of the DSL function source
+	// code and the changes made to the expression as described by
+	// TraceState. It also lists the changes made when finalizing the
+	// expressions.
+	Trace io.Writer
+
+	// TraceState returns a description of the state of an expression as a
+	// list of lines. The trace reports the lines that differ before and
+	// after the expression DSL executes. The expr package sets TraceState
+	// to a function that describes attributes and the expressions that
+	// contain them.
+	TraceState func(Expression) []string
+)
+
+// traceStart writes the trace line reporting the execution of fn to
+// initialize def and returns the state of def prior to the execution.
+func traceStart(fn func(), def Expression) []string {
+	indent := strings.Repeat("  ", len(Context.Stack))
+	fmt.Fprintf(Trace, "%s%s (%T) %s\n", indent, exprName(def), def, funcLocation(fn))
+	return traceState(def)
+}
+
+// traceEnd writes the changes made to def since before was computed.
+func traceEnd(def Expression, before []string) {
+	indent := strings.Repeat("  ", len(Context.Stack)+1)
+	traceDiff(indent, before, traceState(def))
+}
+
+// traceDiff writes the lines that are in after but not in before prefixed
+// with "+" and the lines that are in before but not in after prefixed with
+// "-".
+func traceDiff(indent string, before, after []string) {
+	in := func(l string, ls []string) bool {
+		for _, o := range ls {
+			if o == l {
+				return true
+			}
+		}
+		return false
+	}
+	for _, l := range before {
+		if !in(l, after) {
+			fmt.Fprintf(Trace, "%s- %s\n", indent, l)
+		}
+	}
+	for _, l := range after {
+		if !in(l, before) {
+			fmt.Fprintf(Trace, "%s+ %s\n", indent, l)
+		}
+	}
+}
+
+// traceState returns the state of def or nil if TraceState is not set.
+func traceState(def Expression) []string {
+	if TraceState == nil || def == nil {
+		return nil
+	}
+	return TraceState(def)
+}
+
+// exprName returns the name of def used in traces.
+func exprName(def Expression) string {
+	if def == nil {
+		return "<nil>"
+	}
+	if n := def.EvalName(); n != "" {
+		return n
+	}
+	return "<unnamed>"
+}
+
+// funcLocation returns the file and line where fn is defined.
+func funcLocation(fn func()) string {
+	f := runtime.FuncForPC(reflect.ValueOf(fn).Pointer())
+	if f == nil {
+		return "<unknown>"
+	}
+	file, line := f.FileLine(f.Entry())
+	return fmt.Sprintf("%s:%d", file, line)
+}
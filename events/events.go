@@ -0,0 +1,144 @@
+/*
+Package events contains the runtime used by the code generated for the events
+defined in the design with the Event DSL. The generated service packages define
+a Publisher interface with one method per event and a NewPublisher function
+that implements the interface with a Publisher. A Publisher encodes the event
+payloads and sends the resulting messages with a Transport. The package
+provides transports for NATS and Kafka and optionally encodes the events using
+the structured mode of the CloudEvents JSON format.
+*/
+package events
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type (
+	// Message is a message sent by a Transport.
+	Message struct {
+		// Topic is the name of the topic (or subject) the message is
+		// sent to.
+		Topic string
+		// Key is the unique ID of the event, it may be used by the
+		// transport to partition the messages.
+		Key string
+		// Headers lists the message headers.
+		Headers map[string]string
+		// Data is the encoded event.
+		Data []byte
+	}
+
+	// Transport sends the messages built by a Publisher to a message broker.
+	Transport interface {
+		// Send sends the message.
+		Send(ctx context.Context, msg *Message) error
+	}
+
+	// TransportFunc is an adapter that allows the use of ordinary functions
+	// as transports.
+	TransportFunc func(ctx context.Context, msg *Message) error
+
+	// Publisher encodes events and sends them with a transport.
+	Publisher struct {
+		transport Transport
+		// source is the CloudEvents source, empty if the events are not
+		// encoded using the CloudEvents format.
+		source string
+		newID  func() string
+		now    func() time.Time
+	}
+
+	// Option is a publisher option.
+	Option func(*Publisher)
+
+	// cloudEvent is the CloudEvents structured mode JSON envelope.
+	cloudEvent struct {
+		SpecVersion     string          `json:"specversion"`
+		ID              string          `json:"id"`
+		Source          string          `json:"source"`
+		Type            string          `json:"type"`
+		Subject         string          `json:"subject,omitempty"`
+		Time            string          `json:"time"`
+		DataContentType string          `json:"datacontenttype"`
+		Data            json.RawMessage `json:"data"`
+	}
+)
+
+const (
+	// TypeHeader is the name of the message header that holds the event
+	// type.
+	TypeHeader = "Event-Type"
+	// IDHeader is the name of the message header that holds the event ID.
+	IDHeader = "Event-Id"
+	// ContentTypeHeader is the name of the message header that holds the
+	// content type of the message data.
+	ContentTypeHeader = "Content-Type"
+)
+
+// NewPublisher returns a publisher that sends the events with t. The events
+// are encoded in JSON unless the WithCloudEvents option is used.
+func NewPublisher(t Transport, opts ...Option) *Publisher {
+	p := &Publisher{transport: t, newID: uuid.NewString, now: time.Now}
+	for _, o := range opts {
+		o(p)
+	}
+	return p
+}
+
+// WithCloudEvents causes the publisher to encode the events using the
+// structured mode of the CloudEvents JSON format. source is the value of the
+// CloudEvents "source" attribute, e.g. "/inventory".
+func WithCloudEvents(source string) Option {
+	return func(p *Publisher) {
+		p.source = source
+	}
+}
+
+// Publish encodes payload and sends the resulting message to the given topic.
+// eventType is the name of the event as defined in the design. The message
+// headers hold the event type, a unique event ID and the content type of the
+// message data which is either the JSON representation of the payload or the
+// CloudEvents envelope containing it.
+func (p *Publisher) Publish(ctx context.Context, topic, eventType string, payload any) error {
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return err
+	}
+	id := p.newID()
+	ct := "application/json"
+	if p.source != "" {
+		data, err = json.Marshal(&cloudEvent{
+			SpecVersion:     "1.0",
+			ID:              id,
+			Source:          p.source,
+			Type:            eventType,
+			Subject:         topic,
+			Time:            p.now().UTC().Format(time.RFC3339Nano),
+			DataContentType: ct,
+			Data:            data,
+		})
+		if err != nil {
+			return err
+		}
+		ct = "application/cloudevents+json"
+	}
+	return p.transport.Send(ctx, &Message{
+		Topic: topic,
+		Key:   id,
+		Headers: map[string]string{
+			TypeHeader:        eventType,
+			IDHeader:          id,
+			ContentTypeHeader: ct,
+		},
+		Data: data,
+	})
+}
+
+// Send calls f(ctx, msg).
+func (f TransportFunc) Send(ctx context.Context, msg *Message) error {
+	return f(ctx, msg)
+}
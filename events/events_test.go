@@ -0,0 +1,97 @@
+package events
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type natsConn struct {
+	subject string
+	data    []byte
+}
+
+func (c *natsConn) Publish(subject string, data []byte) error {
+	c.subject = subject
+	c.data = data
+	return nil
+}
+
+func TestPublish(t *testing.T) {
+	payload := map[string]string{"id": "1"}
+	cases := map[string]struct {
+		Options     []Option
+		ContentType string
+		Data        string
+	}{
+		"json": {
+			ContentType: "application/json",
+			Data:        `{"id":"1"}`,
+		},
+		"cloudevents": {
+			Options:     []Option{WithCloudEvents("/inventory")},
+			ContentType: "application/cloudevents+json",
+			Data:        `{"specversion":"1.0","id":"42","source":"/inventory","type":"BottleCreated","subject":"inventory.BottleCreated","time":"2023-11-14T22:13:20Z","datacontenttype":"application/json","data":{"id":"1"}}`,
+		},
+	}
+	for name, c := range cases {
+		t.Run(name, func(t *testing.T) {
+			var msg *Message
+			p := NewPublisher(TransportFunc(func(_ context.Context, m *Message) error {
+				msg = m
+				return nil
+			}), c.Options...)
+			p.newID = func() string { return "42" }
+			p.now = func() time.Time { return time.Unix(1700000000, 0) }
+
+			require.NoError(t, p.Publish(context.Background(), "inventory.BottleCreated", "BottleCreated", payload))
+
+			require.NotNil(t, msg)
+			assert.Equal(t, "inventory.BottleCreated", msg.Topic)
+			assert.Equal(t, "42", msg.Key)
+			assert.Equal(t, map[string]string{
+				TypeHeader:        "BottleCreated",
+				IDHeader:          "42",
+				ContentTypeHeader: c.ContentType,
+			}, msg.Headers)
+			assert.Equal(t, c.Data, string(msg.Data))
+		})
+	}
+}
+
+func TestPublishError(t *testing.T) {
+	p := NewPublisher(TransportFunc(func(context.Context, *Message) error {
+		return errors.New("unavailable")
+	}))
+	assert.EqualError(t, p.Publish(context.Background(), "t", "E", "v"), "unavailable")
+	assert.Error(t, p.Publish(context.Background(), "t", "E", make(chan int)))
+}
+
+func TestTransports(t *testing.T) {
+	msg := &Message{Topic: "topic", Key: "42", Headers: map[string]string{TypeHeader: "E"}, Data: []byte(`"v"`)}
+
+	t.Run("nats", func(t *testing.T) {
+		conn := &natsConn{}
+		require.NoError(t, NewNATSTransport(conn).Send(context.Background(), msg))
+		assert.Equal(t, "topic", conn.subject)
+		assert.Equal(t, `"v"`, string(conn.data))
+	})
+
+	t.Run("kafka", func(t *testing.T) {
+		var topic, key, value string
+		var headers map[string]string
+		tr := NewKafkaTransport(func(_ context.Context, t string, k, v []byte, h map[string]string) error {
+			topic, key, value, headers = t, string(k), string(v), h
+			return nil
+		})
+		require.NoError(t, tr.Send(context.Background(), msg))
+		assert.Equal(t, "topic", topic)
+		assert.Equal(t, "42", key)
+		assert.Equal(t, `"v"`, value)
+		assert.Equal(t, msg.Headers, headers)
+	})
+}
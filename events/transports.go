@@ -0,0 +1,48 @@
+package events
+
+import (
+	"context"
+)
+
+type (
+	// NATSConn is the interface implemented by the NATS client connection
+	// (*nats.Conn of github.com/nats-io/nats.go) used to publish messages.
+	NATSConn interface {
+		// Publish publishes data to the given subject.
+		Publish(subject string, data []byte) error
+	}
+
+	// KafkaProduceFunc writes a record to a Kafka topic. It makes it
+	// possible to use any Kafka client library with NewKafkaTransport,
+	// for example with github.com/segmentio/kafka-go:
+	//
+	//    w := &kafka.Writer{Addr: kafka.TCP("localhost:9092")}
+	//    t := events.NewKafkaTransport(func(ctx context.Context, topic string, key, value []byte, headers map[string]string) error {
+	//        msg := kafka.Message{Topic: topic, Key: key, Value: value}
+	//        for k, v := range headers {
+	//            msg.Headers = append(msg.Headers, kafka.Header{Key: k, Value: []byte(v)})
+	//        }
+	//        return w.WriteMessages(ctx, msg)
+	//    })
+	//
+	KafkaProduceFunc func(ctx context.Context, topic string, key, value []byte, headers map[string]string) error
+)
+
+// NewNATSTransport returns a transport that publishes the messages to the NATS
+// subject named after the message topic. Core NATS messages do not have
+// headers: use the WithCloudEvents publisher option so that the message data
+// carries the event metadata.
+func NewNATSTransport(conn NATSConn) Transport {
+	return TransportFunc(func(_ context.Context, msg *Message) error {
+		return conn.Publish(msg.Topic, msg.Data)
+	})
+}
+
+// NewKafkaTransport returns a transport that writes the messages to the Kafka
+// topic named after the message topic using produce. The record key is the
+// event ID and the record headers are the message headers.
+func NewKafkaTransport(produce KafkaProduceFunc) Transport {
+	return TransportFunc(func(ctx context.Context, msg *Message) error {
+		return produce(ctx, msg.Topic, []byte(msg.Key), msg.Data, msg.Headers)
+	})
+}
@@ -0,0 +1,87 @@
+package expr
+
+import (
+	"sort"
+	"strings"
+)
+
+const (
+	// FilterMeta is the meta set on the payload attributes defined with
+	// the Filter DSL.
+	FilterMeta = "criteria:filter"
+
+	// SortMeta is the meta set on the payload attribute defined with the
+	// Sort DSL.
+	SortMeta = "criteria:sort"
+)
+
+// FilterParamName returns the name of the HTTP query string parameter that
+// holds the value of the filter with the given name, e.g. "filter[name]".
+func FilterParamName(name string) string {
+	return "filter[" + name + "]"
+}
+
+// Filters returns the names of the method payload attributes defined with the
+// Filter DSL sorted alphabetically.
+func (m *MethodExpr) Filters() []string {
+	return m.criteria(FilterMeta)
+}
+
+// SortAttribute returns the name of the method payload attribute defined with
+// the Sort DSL, the empty string if there is none.
+func (m *MethodExpr) SortAttribute() string {
+	if names := m.criteria(SortMeta); len(names) > 0 {
+		return names[0]
+	}
+	return ""
+}
+
+// criteria returns the names of the method payload attributes that define
+// the given meta.
+func (m *MethodExpr) criteria(meta string) []string {
+	if m.Payload == nil {
+		return nil
+	}
+	obj := AsObject(m.Payload.Type)
+	if obj == nil {
+		return nil
+	}
+	var names []string
+	for _, nat := range *obj {
+		if _, ok := nat.Attribute.Meta[meta]; ok {
+			names = append(names, nat.Name)
+		}
+	}
+	sort.Strings(names)
+	return names
+}
+
+// mapCriteria maps the filter and sort attributes of the endpoint method
+// payload that are not explicitly mapped to HTTP request elements to query
+// string parameters. The filter attribute "name" maps to the "filter[name]"
+// parameter and the sort attribute maps to the parameter with the same name.
+func (e *HTTPEndpointExpr) mapCriteria() {
+	sortAtt := e.MethodExpr.SortAttribute()
+	names := e.MethodExpr.Filters()
+	if sortAtt != "" {
+		names = append(names, sortAtt)
+	}
+	for _, name := range names {
+		if e.Params.Find(name) != nil || e.Headers.Find(name) != nil || e.Cookies.Find(name) != nil {
+			continue
+		}
+		if e.Body != nil && e.Body.Find(name) != nil {
+			continue
+		}
+		elem := name
+		if name != sortAtt {
+			elem = FilterParamName(name)
+		}
+		e.Params.Merge(NewMappedAttributeExpr(&AttributeExpr{
+			Type: &Object{&NamedAttributeExpr{
+				Name:      strings.Join([]string{name, elem}, ":"),
+				Attribute: &AttributeExpr{Type: String},
+			}},
+		}))
+	}
+}
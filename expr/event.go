@@ -0,0 +1,101 @@
+package expr
+
+import (
+	"fmt"
+
+	"goa.design/goa/v3/eval"
+)
+
+type (
+	// EventExpr describes a domain event published by a service, e.g. to a
+	// message broker such as NATS or Kafka.
+	EventExpr struct {
+		eval.DSLFunc
+		// Name is the name of the event.
+		Name string
+		// Description is the event description.
+		Description string
+		// Topic is the name of the topic (or subject) the event is
+		// published to.
+		Topic string
+		// Payload is the event payload.
+		Payload *AttributeExpr
+		// Body is the encoded event computed from the payload during
+		// finalization.
+		Body *AttributeExpr
+		// Service is the service that publishes the event.
+		Service *ServiceExpr
+	}
+)
+
+// EvalName returns the generic expression name used in error messages.
+func (e *EventExpr) EvalName() string {
+	return fmt.Sprintf("event %q of %s", e.Name, e.Service.EvalName())
+}
+
+// Validate makes sure the event is valid.
+func (e *EventExpr) Validate() *eval.ValidationErrors {
+	verr := new(eval.ValidationErrors)
+	if e.Topic == "" {
+		verr.Add(e, "event topic cannot be empty")
+	}
+	if e.Payload == nil || e.Payload.Type == Empty {
+		verr.Add(e, "event must define a payload")
+		return verr
+	}
+	if IsObject(e.Payload.Type) {
+		if _, ok := e.Payload.Type.(UserType); !ok {
+			verr.Add(e, "event payload must be a primitive, an array, a map or a user type defined with Type")
+		}
+	}
+	verr.Merge(e.Payload.Validate("payload", e))
+	return verr
+}
+
+// Finalize computes the event body.
+func (e *EventExpr) Finalize() {
+	e.Payload.Finalize()
+	body := DupAtt(e.Payload)
+	if _, ok := body.Type.(UserType); ok || !IsPrimitive(body.Type) {
+		RemovePkgPath(body)
+		renameType(body, concat(e.Name, "Event", "Body"), "EventBody")
+		asUserTypes(body, make(map[DataType]DataType))
+	}
+	e.Body = body
+	e.Body.Finalize()
+}
+
+// asUserTypes replaces the result types used by att with user types. Result
+// types are identified by their media type identifier, the renamed copies used
+// in the event bodies must be identified by name so that they are not
+// confused with the original types.
+func asUserTypes(att *AttributeExpr, seen map[DataType]DataType) {
+	switch dt := att.Type.(type) {
+	case UserType:
+		if t, ok := seen[dt]; ok {
+			att.Type = t
+			return
+		}
+		seen[dt] = dt
+		if rt, ok := dt.(*ResultTypeExpr); ok {
+			ut := rt.UserTypeExpr
+			ut.UID = ""
+			seen[dt] = ut
+			att.Type = ut
+		}
+		asUserTypes(dt.Attribute(), seen)
+	case *Object:
+		for _, nat := range *dt {
+			asUserTypes(nat.Attribute, seen)
+		}
+	case *Array:
+		asUserTypes(dt.ElemType, seen)
+	case *Map:
+		asUserTypes(dt.KeyType, seen)
+		asUserTypes(dt.ElemType, seen)
+	case *Union:
+		for _, nat := range dt.Values {
+			asUserTypes(nat.Attribute, seen)
+		}
+	}
+}
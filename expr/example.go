@@ -17,8 +17,13 @@ const (
 
 // Example returns the example set on the attribute at design time. If there
 // isn't such a value then Example computes a random value for the attribute
-// using the given random value producer.
+// using the given random value producer. Example returns nil for sensitive
+// attributes.
 func (a *AttributeExpr) Example(r *ExampleGenerator) any {
+	if a.IsSensitive() {
+		// Do not leak sensitive data in the generated examples.
+		return nil
+	}
 	if ex := a.ExtractUserExamples(); len(ex) > 0 {
 		// Return the last item in the slice so that examples can be overridden
 		// in the DSL. Overridden examples are always appended to the UserExamples
@@ -0,0 +1,37 @@
+package expr
+
+import "goa.design/goa/v3/eval"
+
+// FieldMaskTarget returns the type whose attributes are designated by the field
+// mask held by the given attribute, nil if the attribute was not defined with
+// the FieldMask DSL.
+func FieldMaskTarget(att *AttributeExpr) UserType {
+	name, ok := att.Meta.Last("fieldmask:target")
+	if !ok {
+		return nil
+	}
+	return Root.UserType(name)
+}
+
+// validateFieldMasks checks that the attributes defined with the FieldMask DSL
+// are payload attributes that target object user types.
+func (m *MethodExpr) validateFieldMasks() *eval.ValidationErrors {
+	verr := new(eval.ValidationErrors)
+	check := func(att *AttributeExpr, kind string) {
+		walkAttribute(att, func(name string, a *AttributeExpr) error { // nolint: errcheck
+			if _, ok := a.Meta["fieldmask:target"]; !ok {
+				return nil
+			}
+			if kind != "payload" {
+				verr.Add(m, "attribute %q of the %s cannot hold a field mask, only payload attributes can", name, kind)
+			} else if ut := FieldMaskTarget(a); ut == nil || !IsObject(ut) {
+				verr.Add(m, "field mask attribute %q must target an object user type", name)
+			}
+			return nil
+		})
+	}
+	check(m.Payload, "payload")
+	check(m.StreamingPayload, "streaming payload")
+	check(m.Result, "result")
+	return verr
+}
@@ -0,0 +1,28 @@
+package expr_test
+
+import (
+	"testing"
+
+	"goa.design/goa/v3/expr"
+	"goa.design/goa/v3/expr/testdata"
+)
+
+func TestFieldMaskTarget(t *testing.T) {
+	root := expr.RunDSL(t, testdata.ValidFieldMaskDSL)
+	payload := root.Service("Valid").Method("update").Payload
+	if ut := expr.FieldMaskTarget(payload.Find("update_mask")); ut == nil || ut.Name() != "Account" {
+		t.Errorf("got field mask target %v, expected Account", ut)
+	}
+	if ut := expr.FieldMaskTarget(payload.Find("account")); ut != nil {
+		t.Errorf("got field mask target %q for account, expected nil", ut.Name())
+	}
+}
+
+func TestFieldMaskValidateErrors(t *testing.T) {
+	err := expr.RunInvalidDSL(t, testdata.InvalidFieldMaskDSL)
+	expected := `service "Invalid" method "not-object": field mask attribute "update_mask" must target an object user type
+service "Invalid" method "result": attribute "update_mask" of the result cannot hold a field mask, only payload attributes can`
+	if err.Error() != expected {
+		t.Errorf("invalid error:\ngot:\n%s\n\ngot vs expected:\n%s", err.Error(), expr.Diff(t, err.Error(), expected))
+	}
+}
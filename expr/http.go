@@ -2,6 +2,8 @@ package expr
 
 import (
 	"regexp"
+
+	"goa.design/goa/v3/eval"
 )
 
 type (
@@ -75,6 +77,21 @@ func (*HTTPExpr) EvalName() string {
 	return "API HTTP"
 }
 
+// Validate makes sure the result type links refer to existing HTTP endpoints
+// and that the JSON:API result types are valid.
+func (h *HTTPExpr) Validate() error {
+	verr := new(eval.ValidationErrors)
+	for _, ut := range Root.ResultTypes {
+		if rt, ok := ut.(*ResultTypeExpr); ok {
+			for _, l := range rt.Links {
+				verr.Merge(l.Validate())
+			}
+			verr.Merge(rt.validateJSONAPI())
+		}
+	}
+	return verr
+}
+
 // Finalize initializes Consumes and Produces with defaults if not set.
 func (h *HTTPExpr) Finalize() {
 	if len(h.Consumes) == 0 {
@@ -0,0 +1,101 @@
+package expr
+
+import (
+	"fmt"
+	"time"
+
+	"goa.design/goa/v3/eval"
+)
+
+type (
+	// HTTPCallbackExpr describes an outbound HTTP request (a webhook) made
+	// by the server to a URL provided by the client of an endpoint, e.g.
+	// to notify the client of events related to the request.
+	HTTPCallbackExpr struct {
+		eval.DSLFunc
+		// Name is the name of the callback.
+		Name string
+		// Description is the callback description.
+		Description string
+		// URL is the OpenAPI runtime expression that describes the
+		// callback URL, e.g. "{$request.body#/callback_url}".
+		URL string
+		// Payload is the callback request payload.
+		Payload *AttributeExpr
+		// Body is the callback request body computed from the payload
+		// during finalization.
+		Body *AttributeExpr
+		// MaxAttempts is the maximum number of delivery attempts.
+		MaxAttempts int
+		// Backoff is the delay before the first retry, the delay
+		// doubles with each subsequent retry.
+		Backoff time.Duration
+		// Endpoint is the endpoint that defines the callback.
+		Endpoint *HTTPEndpointExpr
+	}
+)
+
+// EvalName returns the generic expression name used in error messages.
+func (c *HTTPCallbackExpr) EvalName() string {
+	return fmt.Sprintf("callback %q of %s", c.Name, c.Endpoint.EvalName())
+}
+
+// Prepare makes sure the service generates the callback payload type.
+func (c *HTTPCallbackExpr) Prepare() {
+	if c.MaxAttempts == 0 {
+		c.MaxAttempts = 1
+	}
+	if c.Payload == nil {
+		return
+	}
+	ut, ok := c.Payload.Type.(*UserTypeExpr)
+	if !ok {
+		return
+	}
+	svc := c.Endpoint.Service.Name()
+	svcs, ok := ut.Attribute().Meta["type:generate:force"]
+	if ok && len(svcs) == 0 {
+		// already generated for all services
+		return
+	}
+	for _, s := range svcs {
+		if s == svc {
+			return
+		}
+	}
+	ut.Attribute().AddMeta("type:generate:force", svc)
+}
+
+// Validate makes sure the callback is valid.
+func (c *HTTPCallbackExpr) Validate() *eval.ValidationErrors {
+	verr := new(eval.ValidationErrors)
+	if c.URL == "" {
+		verr.Add(c, "callback must define a URL expression")
+	}
+	if c.Payload == nil || c.Payload.Type == Empty {
+		verr.Add(c, "callback must define a payload")
+	} else if IsObject(c.Payload.Type) {
+		if _, ok := c.Payload.Type.(*UserTypeExpr); !ok {
+			verr.Add(c, "callback payload must be a primitive, an array, a map or a user type defined with Type")
+		}
+	}
+	if c.MaxAttempts < 1 {
+		verr.Add(c, "callback maximum number of attempts must be at least 1, got %d", c.MaxAttempts)
+	}
+	if c.Backoff < 0 {
+		verr.Add(c, "callback backoff cannot be negative, got %s", c.Backoff)
+	}
+	return verr
+}
+
+// Finalize computes the callback request body.
+func (c *HTTPCallbackExpr) Finalize() {
+	const suffix = "CallbackBody"
+	body := DupAtt(c.Payload)
+	if _, ok := body.Type.(UserType); ok || !IsPrimitive(body.Type) {
+		RemovePkgPath(body)
+		renameType(body, concat(c.Endpoint.Name(), c.Name, "Callback", "Body"), suffix)
+	}
+	c.Body = body
+	c.Body.Finalize()
+}
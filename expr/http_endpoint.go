@@ -3,7 +3,10 @@ package expr
 import (
 	"fmt"
 	"path"
+	"sort"
+	"strconv"
 	"strings"
+	"time"
 
 	"github.com/dimfeld/httppath"
 	"goa.design/goa/v3/eval"
@@ -53,6 +56,48 @@ type (
 		// returns a reader and that the client accepts a reader to stream the
 		// response body.
 		SkipResponseBodyEncodeDecode bool
+		// DisallowUnknownFields is true if the server rejects the request
+		// bodies that contain fields not defined in the design.
+		DisallowUnknownFields bool
+		// UnknownFields is the name of the payload map attribute that
+		// collects the request body fields not defined in the design,
+		// empty if unknown fields are ignored.
+		UnknownFields string
+		// PresentFields is the name of the payload array attribute that
+		// lists the request body fields set by the client, empty if
+		// the presence of the fields is not recorded.
+		PresentFields string
+		// FieldSelection is the name of the query string parameter used by
+		// clients to select the result fields rendered in the response
+		// body, empty if field selection is disabled.
+		FieldSelection string
+		// BatchPath is the path appended to the endpoint routes paths
+		// to serve batch requests, empty if batch requests are not
+		// supported.
+		BatchPath string
+		// ChunkSize is the number of result array elements written
+		// before flushing the response when the result is streamed
+		// with the chunked transfer encoding, 0 if the result is
+		// encoded as a whole.
+		ChunkSize int
+		// IdempotencyHeader is the name of the request header holding
+		// the idempotency key used to replay the responses to retried
+		// requests, empty if the endpoint does not use the Idempotent
+		// DSL.
+		IdempotencyHeader string
+		// CacheControl is the value of the Cache-Control header set on
+		// the successful responses, empty if the endpoint does not use
+		// the CacheControl DSL.
+		CacheControl string
+		// Vary lists the request headers that identify the cached
+		// representations of the endpoint responses.
+		Vary []string
+		// Callbacks lists the outbound requests made by the server to
+		// URLs provided by the endpoint clients.
+		Callbacks []*HTTPCallbackExpr
+		// CloudEvents is true if the endpoint receives CloudEvents, see
+		// the CloudEvents DSL.
+		CloudEvents bool
 		// Responses is the list of all the possible success HTTP
 		// responses.
 		Responses []*HTTPResponseExpr
@@ -197,6 +242,7 @@ func (e *HTTPEndpointExpr) Prepare() {
 	headers := NewEmptyMappedAttributeExpr()
 	headers.Merge(Root.API.HTTP.Headers)
 	headers.Merge(e.Service.Headers)
+	e.inheritHeaders(headers)
 
 	cookies := NewEmptyMappedAttributeExpr()
 	cookies.Merge(Root.API.HTTP.Cookies)
@@ -260,6 +306,13 @@ func (e *HTTPEndpointExpr) Prepare() {
 		}
 	}
 
+	// Map the filter and sort criteria to query string parameters.
+	e.mapCriteria()
+
+	for _, c := range e.Callbacks {
+		c.Prepare()
+	}
+
 	// Make sure there's a default success response if none define explicitly.
 	if len(e.Responses) == 0 {
 		status := StatusOK
@@ -352,6 +405,12 @@ func (e *HTTPEndpointExpr) Validate() error {
 		if e.MethodExpr.IsResultStreaming() {
 			verr.Add(e, "Endpoint cannot use SkipRequestBodyEncodeDecode when method defines a StreamingResult. Use SkipResponseBodyEncodeDecode instead.")
 		}
+		if HasEncrypted(e.MethodExpr.Payload) {
+			verr.Add(e, "Endpoint cannot use SkipRequestBodyEncodeDecode when method payload defines encrypted attributes.")
+		}
+		if e.MethodExpr.Pagination != nil {
+			verr.Add(e, "Endpoint cannot use SkipRequestBodyEncodeDecode when method is paginated.")
+		}
 	}
 
 	// SkipResponseBodyEncodeDecode is not compatible with gRPC or WebSocket.
@@ -367,6 +426,12 @@ func (e *HTTPEndpointExpr) Validate() error {
 		if e.MethodExpr.IsResultStreaming() {
 			verr.Add(e, "Endpoint cannot use SkipResponseBodyEncodeDecode when method defines a StreamingResult.")
 		}
+		if HasEncrypted(e.MethodExpr.Result) {
+			verr.Add(e, "Endpoint cannot use SkipResponseBodyEncodeDecode when method result defines encrypted attributes.")
+		}
+		if e.MethodExpr.Pagination != nil {
+			verr.Add(e, "Endpoint cannot use SkipResponseBodyEncodeDecode when method is paginated.")
+		}
 		if rt, ok := e.MethodExpr.Result.Type.(*ResultTypeExpr); ok {
 			if len(rt.Views) > 1 {
 				verr.Add(e, "Endpoint cannot use SkipResponseBodyEncodeDecode when method result type defines multiple views.")
@@ -374,6 +439,142 @@ func (e *HTTPEndpointExpr) Validate() error {
 		}
 	}
 
+	verr.Merge(validateUnknownFields(e, e.MethodExpr.Payload, e.DisallowUnknownFields, e.UnknownFields, "payload"))
+	if e.SkipRequestBodyEncodeDecode && (e.DisallowUnknownFields || e.UnknownFields != "") {
+		verr.Add(e, "Endpoint cannot handle unknown fields when using SkipRequestBodyEncodeDecode.")
+	}
+	if e.PresentFields != "" {
+		if e.SkipRequestBodyEncodeDecode {
+			verr.Add(e, "Endpoint cannot use FieldPresence and SkipRequestBodyEncodeDecode.")
+		}
+		if att := e.MethodExpr.Payload.Find(e.PresentFields); att == nil {
+			verr.Add(e, "attribute %q recording the present fields is not defined in the payload", e.PresentFields)
+		} else if arr := AsArray(att.Type); arr == nil || arr.ElemType.Type != String {
+			verr.Add(e, "attribute %q recording the present fields must be an array of strings", e.PresentFields)
+		}
+	}
+
+	// FieldSelection requires a response body that is an object.
+	if e.FieldSelection != "" {
+		if e.SkipResponseBodyEncodeDecode {
+			verr.Add(e, "Endpoint cannot use FieldSelection and SkipResponseBodyEncodeDecode.")
+		}
+		if e.MethodExpr.IsStreaming() {
+			verr.Add(e, "Endpoint cannot use FieldSelection when method defines a streaming payload or result.")
+		}
+		if len(e.SelectableFields()) == 0 {
+			verr.Add(e, "Endpoint cannot use FieldSelection, the response body must be an object or an array of objects.")
+		}
+		if e.Params != nil {
+			if obj := AsObject(e.Params.Type); obj != nil {
+				for _, nat := range *obj {
+					if e.Params.ElemName(nat.Name) == e.FieldSelection {
+						verr.Add(e, "Endpoint cannot use FieldSelection, parameter %q is already defined.", e.FieldSelection)
+					}
+				}
+			}
+		}
+	}
+
+	// Batch requires a payload and a request body that can be decoded.
+	if e.BatchPath != "" {
+		if e.MethodExpr.Payload == nil || e.MethodExpr.Payload.Type == Empty {
+			verr.Add(e, "Endpoint cannot use Batch, the method must define a payload.")
+		}
+		if e.MethodExpr.IsStreaming() {
+			verr.Add(e, "Endpoint cannot use Batch when method defines a streaming payload or result.")
+		}
+		if e.SkipRequestBodyEncodeDecode || e.SkipResponseBodyEncodeDecode {
+			verr.Add(e, "Endpoint cannot use Batch and SkipRequestBodyEncodeDecode or SkipResponseBodyEncodeDecode.")
+		}
+		if e.MultipartRequest {
+			verr.Add(e, "Endpoint cannot use Batch and MultipartRequest.")
+		}
+		if e.Redirect != nil {
+			verr.Add(e, "Endpoint cannot use Batch and Redirect.")
+		}
+		if !strings.HasPrefix(e.BatchPath, "/") {
+			verr.Add(e, "Endpoint batch path %q must start with /.", e.BatchPath)
+		}
+	}
+
+	// CloudEvents requires a payload and a request body that can be
+	// decoded.
+	if e.CloudEvents {
+		if e.MethodExpr.Payload == nil || e.MethodExpr.Payload.Type == Empty {
+			verr.Add(e, "Endpoint cannot use CloudEvents, the method must define a payload.")
+		}
+		if e.MethodExpr.IsPayloadStreaming() {
+			verr.Add(e, "Endpoint cannot use CloudEvents when method defines a streaming payload.")
+		}
+		if e.SkipRequestBodyEncodeDecode {
+			verr.Add(e, "Endpoint cannot use CloudEvents and SkipRequestBodyEncodeDecode.")
+		}
+		if e.MultipartRequest {
+			verr.Add(e, "Endpoint cannot use CloudEvents and MultipartRequest.")
+		}
+	}
+
+	// Callback names must be unique.
+	for i, c := range e.Callbacks {
+		verr.Merge(c.Validate())
+		for _, c2 := range e.Callbacks[i+1:] {
+			if c.Name == c2.Name {
+				verr.Add(e, "Multiple callbacks named %q.", c.Name)
+			}
+		}
+	}
+
+	// ChunkedResult requires a result array that is encoded in the body.
+	if e.ChunkSize != 0 {
+		if e.MethodExpr.Result == nil || !IsArray(e.MethodExpr.Result.Type) {
+			verr.Add(e, "Endpoint cannot use ChunkedResult, the method result must be an array.")
+		}
+		if e.MethodExpr.IsStreaming() {
+			verr.Add(e, "Endpoint cannot use ChunkedResult when method defines a streaming payload or result.")
+		}
+		if e.SkipResponseBodyEncodeDecode {
+			verr.Add(e, "Endpoint cannot use ChunkedResult and SkipResponseBodyEncodeDecode.")
+		}
+		if e.Redirect != nil {
+			verr.Add(e, "Endpoint cannot use ChunkedResult and Redirect.")
+		}
+	}
+
+	// Idempotent requires unsafe HTTP methods and responses that can be
+	// recorded.
+	if e.IdempotencyHeader != "" {
+		for _, r := range e.Routes {
+			switch r.Method {
+			case "GET", "HEAD", "OPTIONS", "TRACE":
+				verr.Add(e, "Endpoint cannot use Idempotent with the safe HTTP method %s.", r.Method)
+			}
+		}
+		if e.MethodExpr.IsStreaming() {
+			verr.Add(e, "Endpoint cannot use Idempotent when method defines a streaming payload or result.")
+		}
+		if e.SkipRequestBodyEncodeDecode || e.SkipResponseBodyEncodeDecode {
+			verr.Add(e, "Endpoint cannot use Idempotent and SkipRequestBodyEncodeDecode or SkipResponseBodyEncodeDecode.")
+		}
+	}
+
+	// CacheControl requires safe HTTP methods and valid directives.
+	if e.CacheControl != "" {
+		for _, r := range e.Routes {
+			if r.Method != "GET" && r.Method != "HEAD" {
+				verr.Add(e, "Endpoint cannot use CacheControl with the HTTP method %s, only GET and HEAD responses are cacheable.", r.Method)
+			}
+		}
+		if e.MethodExpr.IsStreaming() {
+			verr.Add(e, "Endpoint cannot use CacheControl when method defines a streaming payload or result.")
+		}
+		validateCacheControl(e.CacheControl, e, verr)
+	}
+
+	validateFeatureFlagStatus(e.MethodExpr.Meta, e, verr)
+	validateSLO(e.MethodExpr.Meta, e.MethodExpr.Service.Meta, e, verr)
+	validateBudget(e.MethodExpr.Meta, e, verr)
+
 	// Redirect is not compatible with Response.
 	if e.Redirect != nil {
 		found := false
@@ -702,6 +903,19 @@ func (e *HTTPEndpointExpr) Finalize() {
 	initAttr(e.Headers, e.MethodExpr.Payload)
 	initAttr(e.Cookies, e.MethodExpr.Payload)
 
+	if e.UnknownFields != "" {
+		// The unknown fields are collected by the decoder, they are
+		// not part of the body.
+		if att := e.MethodExpr.Payload.Find(e.UnknownFields); att != nil {
+			att.AddMeta("struct:tag:json", "-")
+		}
+	}
+	if e.PresentFields != "" {
+		if att := e.MethodExpr.Payload.Find(e.PresentFields); att != nil {
+			att.AddMeta("struct:tag:json", "-")
+		}
+	}
+
 	e.Body = httpRequestBody(e)
 	e.Body.Finalize()
 
@@ -713,6 +927,11 @@ func (e *HTTPEndpointExpr) Finalize() {
 	// Initialize responses parent, headers and body
 	for _, r := range e.Responses {
 		r.Finalize(e, e.MethodExpr.Result)
+		if r.UnknownFields != "" {
+			if att := e.MethodExpr.Result.Find(r.UnknownFields); att != nil {
+				att.AddMeta("struct:tag:json", "-")
+			}
+		}
 		r.Body = httpResponseBody(e, r)
 		r.Body.Finalize()
 	}
@@ -721,6 +940,35 @@ func (e *HTTPEndpointExpr) Finalize() {
 	for _, herr := range e.HTTPErrors {
 		herr.Finalize(e)
 	}
+	for _, c := range e.Callbacks {
+		c.Finalize()
+	}
+}
+
+// validateUnknownFields checks that the attribute collecting the unknown fields
+// of the request or response body is a map with string keys defined by the
+// payload or result parent.
+func validateUnknownFields(e eval.Expression, parent *AttributeExpr, disallow bool, name, kind string) *eval.ValidationErrors {
+	verr := new(eval.ValidationErrors)
+	if name == "" {
+		return verr
+	}
+	if disallow {
+		verr.Add(e, "unknown fields cannot be both disallowed and collected")
+	}
+	if !IsObject(parent.Type) {
+		verr.Add(e, "%s must be an object to collect unknown fields", kind)
+		return verr
+	}
+	att := parent.Find(name)
+	if att == nil {
+		verr.Add(e, "attribute %q collecting the unknown fields is not defined in the %s", name, kind)
+		return verr
+	}
+	if m := AsMap(att.Type); m == nil || m.KeyType.Type != String {
+		verr.Add(e, "attribute %q collecting the unknown fields must be a map with string keys", name)
+	}
+	return verr
 }
 
 // validateParams checks the endpoint parameters are of an allowed type and the
@@ -840,6 +1088,15 @@ func (e *HTTPEndpointExpr) validateHeadersAndCookies() *eval.ValidationErrors {
 		}
 		return nil
 	})
+	if !IsObject(e.MethodExpr.Payload.Type) {
+		inherited := NewEmptyMappedAttributeExpr()
+		inherited.Merge(Root.API.HTTP.Headers)
+		inherited.Merge(e.Service.Headers)
+		WalkMappedAttr(inherited, func(name, _ string, _ *AttributeExpr) error { // nolint: errcheck
+			verr.Add(e, "header %q is defined at the API or service level but the method payload is not an object.", name)
+			return nil
+		})
+	}
 	switch e.MethodExpr.Payload.Type.(type) {
 	case *Object, UserType:
 		hasBasicAuth := TaggedAttribute(e.MethodExpr.Payload, "security:username") != ""
@@ -1070,3 +1327,265 @@ func isEmpty(a *AttributeExpr) bool {
 	}
 	return true
 }
+
+// SelectableFields returns the paths to the fields of the endpoint success
+// response body that clients may select when field selection is enabled,
+// e.g. "name" or "account.id". Arrays are traversed so that the paths to the
+// fields of the elements of a collection are the paths of the element fields.
+// The paths are computed from the method result prior to finalizing the
+// endpoint response bodies. The paths are sorted alphabetically.
+func (e *HTTPEndpointExpr) SelectableFields() []string {
+	body := e.MethodExpr.Result
+	for _, r := range e.Responses {
+		if r.Body != nil && r.Body.Type != Empty {
+			body = r.Body
+			break
+		}
+	}
+	var paths []string
+	if body != nil {
+		fieldPaths(body, "", make(map[string]struct{}), &paths)
+	}
+	sort.Strings(paths)
+	return paths
+}
+
+// FieldSelectionAttribute returns the attribute describing the field
+// selection query string parameter, nil if field selection is disabled.
+func (e *HTTPEndpointExpr) FieldSelectionAttribute() *AttributeExpr {
+	if e.FieldSelection == "" {
+		return nil
+	}
+	fields := e.SelectableFields()
+	var ex []string
+	for _, f := range fields {
+		if len(ex) < 2 && !strings.Contains(f, ".") {
+			ex = append(ex, f)
+		}
+	}
+	return &AttributeExpr{
+		Type:         String,
+		Description:  fmt.Sprintf("Comma separated list of the result fields rendered in the response body, all fields are rendered if absent. Valid fields are %s.", strings.Join(fields, ", ")),
+		UserExamples: []*ExampleExpr{{Summary: "default", Value: strings.Join(ex, ",")}},
+	}
+}
+
+// inheritHeaders adds the API and service level request headers that the
+// method payload does not define to the payload so that the headers common to
+// all the endpoints (e.g. a tenant ID or an idempotency key) are decoded,
+// validated and documented without being repeated in each method design. The
+// headers required at the API or service level are required in the payload.
+// User type payloads are duplicated and renamed before being modified so that
+// the other usages of the type are left untouched. Payloads that are not
+// objects are left untouched and fail validation.
+func (e *HTTPEndpointExpr) inheritHeaders(headers *MappedAttributeExpr) {
+	if headers.IsEmpty() {
+		return
+	}
+	payload := e.MethodExpr.Payload
+	if payload == nil {
+		return
+	}
+	if payload.Type == Empty {
+		payload.Type = &Object{}
+	}
+	if AsObject(payload.Type) == nil {
+		return
+	}
+	var missing []*NamedAttributeExpr
+	for _, nat := range *AsObject(headers.Type) {
+		if payload.Find(nat.Name) == nil {
+			missing = append(missing, nat)
+		}
+	}
+	if len(missing) == 0 {
+		return
+	}
+	ut, isUT := payload.Type.(UserType)
+	if isUT {
+		dupped := Dup(ut).(UserType)
+		if renamer, ok := dupped.(interface{ Rename(string) }); ok {
+			renamer.Rename(ut.Name() + "_" + e.MethodExpr.Name + "_Payload")
+		}
+		payload.Type = dupped
+		ut = dupped
+	}
+	obj := AsObject(payload.Type)
+	for _, nat := range missing {
+		obj.Set(nat.Name, DupAtt(nat.Attribute))
+		if !headers.IsRequired(nat.Name) {
+			continue
+		}
+		att := payload
+		if isUT {
+			att = ut.Attribute()
+		}
+		if att.Validation == nil {
+			att.Validation = &ValidationExpr{}
+		}
+		att.Validation.AddRequired(nat.Name)
+	}
+}
+
+// IdempotencyHeaders returns the attribute describing the idempotency key
+// request header if the endpoint uses the Idempotent DSL and does not define
+// the header explicitly, nil otherwise.
+func (e *HTTPEndpointExpr) IdempotencyHeaders() []*NamedAttributeExpr {
+	if e.IdempotencyHeader == "" {
+		return nil
+	}
+	if obj := AsObject(e.Headers.Type); obj != nil {
+		for _, nat := range *obj {
+			if strings.EqualFold(e.Headers.ElemName(nat.Name), e.IdempotencyHeader) {
+				return nil
+			}
+		}
+	}
+	return []*NamedAttributeExpr{{Name: e.IdempotencyHeader, Attribute: &AttributeExpr{
+		Type:         String,
+		Description:  "Unique key of the request used to safely retry it: retried requests with the same key receive the response to the first request.",
+		UserExamples: []*ExampleExpr{{Summary: "default", Value: "8e03978e-40d5-43e8-bc93-6894a57f9324"}},
+	}}}
+}
+
+// LocaleHeaders returns the attributes describing the Accept-Language and time
+// zone request headers parsed by the locale middleware when the method, its
+// service or the API define the "http:locale" meta, nil otherwise. The value
+// of the meta if any is the name of the time zone header, "Time-Zone" by
+// default. Headers explicitly defined by the endpoint are omitted.
+func (e *HTTPEndpointExpr) LocaleHeaders() []*NamedAttributeExpr {
+	metas := []MetaExpr{e.MethodExpr.Meta}
+	if e.MethodExpr.Service != nil {
+		metas = append(metas, e.MethodExpr.Service.Meta)
+	}
+	if Root.API != nil {
+		metas = append(metas, Root.API.Meta)
+	}
+	var tz string
+	found := false
+	for _, meta := range metas {
+		if vals, ok := meta["http:locale"]; ok {
+			found = true
+			if len(vals) > 0 {
+				tz = vals[len(vals)-1]
+			}
+			break
+		}
+	}
+	if !found {
+		return nil
+	}
+	if tz == "" {
+		tz = "Time-Zone"
+	}
+	defined := make(map[string]struct{})
+	if obj := AsObject(e.Headers.Type); obj != nil {
+		for _, nat := range *obj {
+			defined[strings.ToLower(e.Headers.ElemName(nat.Name))] = struct{}{}
+		}
+	}
+	var headers []*NamedAttributeExpr
+	add := func(name, desc string, ex string) {
+		if _, ok := defined[strings.ToLower(name)]; ok {
+			return
+		}
+		headers = append(headers, &NamedAttributeExpr{Name: name, Attribute: &AttributeExpr{
+			Type:         String,
+			Description:  desc,
+			UserExamples: []*ExampleExpr{{Summary: "default", Value: ex}},
+		}})
+	}
+	add("Accept-Language", "Languages preferred by the client for the response messages by order of preference as defined by RFC 9110.", "fr-CA, fr;q=0.9, en;q=0.5")
+	add(tz, "IANA time zone used to render and interpret the request and response times, UTC if absent.", "America/Los_Angeles")
+	return headers
+}
+
+// fieldPaths appends the paths to the fields of att prefixed with prefix to
+// paths. seen records the user types being traversed to stop recursions.
+func fieldPaths(att *AttributeExpr, prefix string, seen map[string]struct{}, paths *[]string) {
+	if arr := AsArray(att.Type); arr != nil {
+		att = arr.ElemType
+	}
+	obj := AsObject(att.Type)
+	if obj == nil {
+		return
+	}
+	if ut, ok := att.Type.(UserType); ok {
+		if _, ok := seen[ut.ID()]; ok {
+			return
+		}
+		seen[ut.ID()] = struct{}{}
+		defer delete(seen, ut.ID())
+	}
+	for _, nat := range *obj {
+		p := prefix + nat.Name
+		*paths = append(*paths, p)
+		fieldPaths(nat.Attribute, p+".", seen, paths)
+	}
+}
+
+// validateCacheControl makes sure the given Cache-Control header value only
+// contains known response directives with valid values.
+func validateCacheControl(cc string, e eval.Expression, verr *eval.ValidationErrors) {
+	for _, d := range strings.Split(cc, ",") {
+		name, val, hasVal := strings.Cut(strings.TrimSpace(d), "=")
+		name = strings.ToLower(strings.TrimSpace(name))
+		switch name {
+		case "max-age", "s-maxage", "stale-while-revalidate", "stale-if-error":
+			if n, err := strconv.Atoi(strings.TrimSpace(val)); err != nil || n < 0 {
+				verr.Add(e, "CacheControl directive %q must have a non-negative number of seconds as value, got %q.", name, val)
+			}
+		case "public", "private", "no-cache", "no-store", "no-transform", "must-revalidate", "proxy-revalidate", "must-understand", "immutable":
+			if hasVal && name != "private" && name != "no-cache" {
+				verr.Add(e, "CacheControl directive %q does not accept a value.", name)
+			}
+		default:
+			verr.Add(e, "Unknown CacheControl directive %q.", name)
+		}
+	}
+}
+
+// validateFeatureFlagStatus checks that the "feature:flag:status" meta if any is
+// an error HTTP status code.
+func validateFeatureFlagStatus(meta MetaExpr, e eval.Expression, verr *eval.ValidationErrors) {
+	if v, ok := meta.Last("feature:flag:status"); ok {
+		if code, err := strconv.Atoi(v); err != nil || code < 400 || code > 599 {
+			verr.Add(e, "Invalid feature:flag:status meta %q, the value must be a 4xx or 5xx HTTP status code.", v)
+		}
+	}
+}
+
+// validateBudget checks that the "budget:response-size" and "budget:latency"
+// meta if any are a positive number of bytes and a positive duration.
+func validateBudget(meta MetaExpr, e eval.Expression, verr *eval.ValidationErrors) {
+	if size, ok := meta.Last("budget:response-size"); ok {
+		if n, err := strconv.ParseInt(size, 10, 64); err != nil || n <= 0 {
+			verr.Add(e, "Invalid budget:response-size meta %q, the value must be a positive number of bytes.", size)
+		}
+	}
+	if latency, ok := meta.Last("budget:latency"); ok {
+		if d, err := time.ParseDuration(latency); err != nil || d <= 0 {
+			verr.Add(e, "Invalid budget:latency meta %q, the value must be a positive duration, e.g. \"500ms\".", latency)
+		}
+	}
+}
+
+// validateSLO checks that the "slo:target" and "slo:latency" meta if any are
+// a percentage and a positive duration. parent is the meta of the service
+// when validating a method, nil otherwise.
+func validateSLO(meta, parent MetaExpr, e eval.Expression, verr *eval.ValidationErrors) {
+	target, hasTarget := meta.Last("slo:target")
+	if hasTarget {
+		if p, err := strconv.ParseFloat(target, 64); err != nil || p <= 0 || p >= 100 {
+			verr.Add(e, "Invalid slo:target meta %q, the value must be a percentage strictly between 0 and 100.", target)
+		}
+	}
+	if latency, ok := meta.Last("slo:latency"); ok {
+		if d, err := time.ParseDuration(latency); err != nil || d <= 0 {
+			verr.Add(e, "Invalid slo:latency meta %q, the value must be a positive duration, e.g. \"300ms\".", latency)
+		}
+		if _, ok := parent.Last("slo:target"); !hasTarget && !ok {
+			verr.Add(e, "The slo:latency meta requires the slo:target meta.")
+		}
+	}
+}
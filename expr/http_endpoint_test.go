@@ -58,6 +58,11 @@ func TestHTTPEndpointPrepare(t *testing.T) {
 			Params:  []string{"pparam", "param"},
 			Cookies: []string{"pcookie", "cookie"},
 		},
+		"criteria": {
+			DSL:     testdata.EndpointCriteriaDSL,
+			Headers: []string{"vintage"},
+			Params:  []string{"name", "sort"},
+		},
 		"error": {
 			DSL:   testdata.EndpointRecursiveParentDSL,
 			Error: "service \"Parent\": Parent service Child is also child\nservice \"Child\": Parent service Parent is also child",
@@ -167,10 +172,93 @@ service "Service" HTTP endpoint "Method": HTTP endpoint response body must be em
 			DSL:   testdata.EndpointHasSkipEncodeAndGRPC,
 			Error: `service "Service" HTTP endpoint "Method": Endpoint cannot use SkipRequestBodyEncodeDecode and define a gRPC transport.`,
 		},
+		"endpoint-field-selection-primitive-result": {
+			DSL:   testdata.EndpointFieldSelectionPrimitiveResult,
+			Error: `service "Service" HTTP endpoint "Method": Endpoint cannot use FieldSelection, the response body must be an object or an array of objects.`,
+		},
+		"endpoint-field-selection-param-conflict": {
+			DSL:   testdata.EndpointFieldSelectionParamConflict,
+			Error: `service "Service" HTTP endpoint "Method": Endpoint cannot use FieldSelection, parameter "fields" is already defined.`,
+		},
+		"endpoint-batch-no-payload": {
+			DSL: testdata.EndpointBatchNoPayload,
+			Error: `service "Service" HTTP endpoint "Method": Endpoint cannot use Batch, the method must define a payload.
+service "Service" HTTP endpoint "Method": Endpoint batch path "batch" must start with /.`,
+		},
+		"endpoint-cloudevents-invalid": {
+			DSL: testdata.EndpointCloudEventsInvalid,
+			Error: `service "Service" HTTP endpoint "Method": Endpoint cannot use CloudEvents, the method must define a payload.
+service "Service" HTTP endpoint "Method": Endpoint cannot use CloudEvents and SkipRequestBodyEncodeDecode.`,
+		},
+		"endpoint-callback-invalid": {
+			DSL: testdata.EndpointCallbackInvalid,
+			Error: `callback "onEvent" of service "Service" HTTP endpoint "Method": callback must define a URL expression
+service "Service" HTTP endpoint "Method": Multiple callbacks named "onEvent".
+callback "onEvent" of service "Service" HTTP endpoint "Method": callback maximum number of attempts must be at least 1, got -1`,
+		},
+		"endpoint-inherited-headers-primitive": {
+			DSL:   testdata.EndpointInheritedHeadersPrimitive,
+			Error: `service "Service" HTTP endpoint "Method": header "tenant" is defined at the API or service level but the method payload is not an object.`,
+		},
+		"endpoint-idempotent-safe-method": {
+			DSL:   testdata.EndpointIdempotentSafeMethod,
+			Error: `service "Service" HTTP endpoint "Method": Endpoint cannot use Idempotent with the safe HTTP method GET.`,
+		},
+		"endpoint-cache-control-invalid": {
+			DSL: testdata.EndpointCacheControlInvalid,
+			Error: `service "Service" HTTP endpoint "Method": Endpoint cannot use CacheControl with the HTTP method POST, only GET and HEAD responses are cacheable.
+service "Service" HTTP endpoint "Method": CacheControl directive "max-age" must have a non-negative number of seconds as value, got "ten".
+service "Service" HTTP endpoint "Method": Unknown CacheControl directive "forever".`,
+		},
+		"endpoint-chunked-result-not-array": {
+			DSL:   testdata.EndpointChunkedResultNotArray,
+			Error: `service "Service" HTTP endpoint "Method": Endpoint cannot use ChunkedResult, the method result must be an array.`,
+		},
+		"endpoint-feature-flag-invalid-status": {
+			DSL:   testdata.EndpointFeatureFlagInvalidStatus,
+			Error: `service "Service" HTTP endpoint "Method": Invalid feature:flag:status meta "200", the value must be a 4xx or 5xx HTTP status code.`,
+		},
+		"endpoint-slo-invalid": {
+			DSL: testdata.EndpointSLOInvalid,
+			Error: `service "Service" HTTP endpoint "Method": Invalid slo:target meta "100", the value must be a percentage strictly between 0 and 100.
+service "Service" HTTP endpoint "Method": Invalid slo:latency meta "fast", the value must be a positive duration, e.g. "300ms".
+service "Service" HTTP endpoint "Other": The slo:latency meta requires the slo:target meta.`,
+		},
+		"endpoint-budget-invalid": {
+			DSL: testdata.EndpointBudgetInvalid,
+			Error: `service "Service" HTTP endpoint "Method": Invalid budget:response-size meta "1MB", the value must be a positive number of bytes.
+service "Service" HTTP endpoint "Method": Invalid budget:latency meta "-1s", the value must be a positive duration, e.g. "500ms".`,
+		},
 		"endpoint-payload-missing-required": {
 			DSL:   testdata.EndpointPayloadMissingRequired,
 			Error: `service "Service" HTTP endpoint "Method": The following HTTP request body attribute is required but the corresponding method payload attribute is not: nonreq. Use 'Required' to make the attribute required in the method payload as well.`,
 		},
+		"endpoint-collect-unknown-fields": {
+			DSL: testdata.EndpointCollectUnknownFields,
+		},
+		"endpoint-collect-unknown-fields-not-map": {
+			DSL:   testdata.EndpointCollectUnknownFieldsNotMap,
+			Error: `service "Service" HTTP endpoint "Method": attribute "extra" collecting the unknown fields must be a map with string keys`,
+		},
+		"endpoint-collect-unknown-fields-missing": {
+			DSL:   testdata.EndpointCollectUnknownFieldsMissing,
+			Error: `service "Service" HTTP endpoint "Method": attribute "extra" collecting the unknown fields is not defined in the payload`,
+		},
+		"endpoint-unknown-fields-conflict": {
+			DSL:   testdata.EndpointUnknownFieldsConflict,
+			Error: `service "Service" HTTP endpoint "Method": unknown fields cannot be both disallowed and collected`,
+		},
+		"endpoint-field-presence": {
+			DSL: testdata.EndpointFieldPresence,
+		},
+		"endpoint-field-presence-not-array": {
+			DSL:   testdata.EndpointFieldPresenceNotArray,
+			Error: `service "Service" HTTP endpoint "Method": attribute "fields" recording the present fields must be an array of strings`,
+		},
+		"endpoint-field-presence-missing": {
+			DSL:   testdata.EndpointFieldPresenceMissing,
+			Error: `service "Service" HTTP endpoint "Method": attribute "fields" recording the present fields is not defined in the payload`,
+		},
 		"streaming-endpoint-has-request-body": {
 			DSL: testdata.StreamingEndpointRequestBody,
 			Error: `service "Service" HTTP endpoint "MethodA": HTTP endpoint request body must be empty when the endpoint uses streaming. Payload attributes must be mapped to headers and/or params.
@@ -222,6 +310,47 @@ func TestHTTPEndpointParentRequired(t *testing.T) {
 	}
 }
 
+func TestHTTPEndpointInheritedHeaders(t *testing.T) {
+	root := expr.RunDSL(t, testdata.EndpointInheritedHeaders)
+	svc := root.Service("Service")
+	if svc == nil {
+		t.Fatal(`unexpected error, service "Service" not found`)
+	}
+	for _, name := range []string{"Method", "Empty", "Shared"} {
+		m := svc.Method(name)
+		if m == nil {
+			t.Fatalf(`unexpected error, method %q not found`, name)
+		}
+		tenant := m.Payload.Find("tenant")
+		if tenant == nil {
+			t.Fatalf(`method %q: expected "tenant" to be added to the payload`, name)
+		}
+		if tenant.Validation == nil || tenant.Validation.Pattern != "^[a-z]+$" {
+			t.Errorf(`method %q: expected "tenant" to inherit the header validations`, name)
+		}
+		if !m.Payload.IsRequired("tenant") {
+			t.Errorf(`method %q: expected "tenant" is required, but not so`, name)
+		}
+		key := m.Payload.Find("key")
+		if key == nil {
+			t.Fatalf(`method %q: expected "key" to be added to the payload`, name)
+		}
+		if m.Payload.IsRequired("key") {
+			t.Errorf(`method %q: expected "key" is not required, but it is`, name)
+		}
+	}
+	shared := svc.Method("Shared")
+	if shared.Payload.Type.Name() == "Account" {
+		t.Errorf(`expected the "Account" payload type to be renamed once extended, got %q`, shared.Payload.Type.Name())
+	}
+	if shared.Result.Type.Name() != "Account" || shared.Result.Find("tenant") != nil || shared.Result.IsRequired("tenant") {
+		t.Errorf(`expected the "Account" result type to be left untouched`)
+	}
+	if key := svc.Method("Method").Payload.Find("key"); key.Type != expr.Int {
+		t.Errorf(`expected "key" payload attribute to keep its type, got %s`, key.Type.Name())
+	}
+}
+
 func TestHTTPEndpointFinalization(t *testing.T) {
 	cases := map[string]struct {
 		DSL          func()
@@ -90,6 +90,14 @@ type (
 		Body *AttributeExpr
 		// Response Content-Type header value
 		ContentType string
+		// DisallowUnknownFields is true if the client rejects the
+		// response bodies that contain fields not defined in the
+		// design.
+		DisallowUnknownFields bool
+		// UnknownFields is the name of the result map attribute that
+		// collects the response body fields not defined in the design,
+		// empty if unknown fields are ignored.
+		UnknownFields string
 		// Tag the value a field of the result must have for this
 		// response to be used.
 		Tag [2]string
@@ -204,6 +212,20 @@ func (r *HTTPResponseExpr) Validate(e *HTTPEndpointExpr) *eval.ValidationErrors
 			}
 		}
 	}
+	verr.Merge(validateUnknownFields(r, e.MethodExpr.Result, r.DisallowUnknownFields, r.UnknownFields, "result"))
+	if e.SkipResponseBodyEncodeDecode && (r.DisallowUnknownFields || r.UnknownFields != "") {
+		verr.Add(r, "response cannot handle unknown fields when using SkipResponseBodyEncodeDecode.")
+	}
+	if _, ok := r.Headers.Meta["http:content-disposition"]; ok {
+		name := r.Headers.KeyName("Content-Disposition")
+		t := e.MethodExpr.Result.Type
+		if IsObject(t) {
+			t = resultAttributeType(name)
+		}
+		if t != nil && t != String {
+			verr.Add(r, "attribute %q mapped to the Content-Disposition header must be a String.", name)
+		}
+	}
 	if !r.Cookies.IsEmpty() {
 		verr.Merge(r.Cookies.Validate("HTTP response cookies", r))
 		if isEmpty(e.MethodExpr.Result) {
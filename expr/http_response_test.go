@@ -32,6 +32,8 @@ func TestHTTPResponseValidation(t *testing.T) {
 		{"missing header result attribute", missingHeaderResultAttributeDSL, `HTTP response of service "MissingHeaderResultAttribute" HTTP endpoint "Method": header "bar" has no equivalent attribute in result type, use notation 'attribute_name:header_name' to identify corresponding result type attribute.`},
 		{"missing cookie result attribute", missingCookieResultAttributeDSL, `HTTP response of service "MissingCookieResultAttribute" HTTP endpoint "Method": cookie "bar" has no equivalent attribute in result type, use notation 'attribute_name:cookie_name' to identify corresponding result type attribute.
 service "MissingCookieResultAttribute" HTTP endpoint "Method": attribute "bar" used in HTTP cookies must be a primitive type.`},
+		{"content disposition", contentDispositionDSL, ""},
+		{"content disposition not string", contentDispositionNotStringDSL, `HTTP response of service "ContentDispositionNotString" HTTP endpoint "Method": attribute "name" mapped to the Content-Disposition header must be a String.`},
 		{"skip encode and gRPC", skipEncodeAndGRPCDSL, `service "SkipEncodeAndGRPC" HTTP endpoint "Method": Endpoint response cannot use SkipResponseBodyEncodeDecode and define a gRPC transport.`},
 	}
 	for _, c := range cases {
@@ -313,6 +315,40 @@ var missingCookieResultAttributeDSL = func() {
 	})
 }
 
+var contentDispositionDSL = func() {
+	Service("ContentDisposition", func() {
+		Method("Method", func() {
+			Result(func() {
+				Attribute("name", String)
+				Attribute("type", String)
+			})
+			HTTP(func() {
+				GET("/")
+				Response(func() {
+					ContentDisposition("inline", "name")
+					Header("type:Content-Type")
+				})
+			})
+		})
+	})
+}
+
+var contentDispositionNotStringDSL = func() {
+	Service("ContentDispositionNotString", func() {
+		Method("Method", func() {
+			Result(func() {
+				Attribute("name", Int)
+			})
+			HTTP(func() {
+				GET("/")
+				Response(func() {
+					ContentDisposition("attachment", "name")
+				})
+			})
+		})
+	})
+}
+
 var skipEncodeAndGRPCDSL = func() {
 	Service("SkipEncodeAndGRPC", func() {
 		Method("Method", func() {
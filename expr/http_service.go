@@ -202,6 +202,9 @@ func (svc *HTTPServiceExpr) Validate() error {
 	if svc.Headers != nil {
 		verr.Merge(svc.Headers.Validate("headers", svc))
 	}
+	validateFeatureFlagStatus(svc.ServiceExpr.Meta, svc, verr)
+	validateSLO(svc.ServiceExpr.Meta, nil, svc, verr)
+	validateBudget(svc.ServiceExpr.Meta, svc, verr)
 	if n := svc.ParentName; n != "" {
 		if p := Root.API.HTTP.Service(n); p == nil {
 			verr.Add(svc, "Parent service %s not found", n)
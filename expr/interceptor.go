@@ -0,0 +1,166 @@
+package expr
+
+import (
+	"fmt"
+
+	"goa.design/goa/v3/eval"
+)
+
+type (
+	// InterceptorExpr describes an interceptor invoked around the endpoints
+	// of the methods it applies to. The interceptor lists the payload and
+	// result attributes it reads or writes, the generated code provides
+	// typed access to these attributes.
+	InterceptorExpr struct {
+		// DSLFunc contains the DSL used to initialize the expression.
+		eval.DSLFunc
+		// Name is the name of the interceptor.
+		Name string
+		// Description is the interceptor description.
+		Description string
+		// ReadPayload lists the payload attributes read by the
+		// interceptor.
+		ReadPayload *AttributeExpr
+		// WritePayload lists the payload attributes written by the
+		// interceptor.
+		WritePayload *AttributeExpr
+		// ReadResult lists the result attributes read by the interceptor.
+		ReadResult *AttributeExpr
+		// WriteResult lists the result attributes written by the
+		// interceptor.
+		WriteResult *AttributeExpr
+	}
+)
+
+// EvalName returns the generic expression name used in error messages.
+func (i *InterceptorExpr) EvalName() string {
+	return fmt.Sprintf("interceptor %q", i.Name)
+}
+
+// HasPayloadAccess returns true if the interceptor reads or writes payload
+// attributes.
+func (i *InterceptorExpr) HasPayloadAccess() bool {
+	return hasAttributes(i.ReadPayload) || hasAttributes(i.WritePayload)
+}
+
+// HasResultAccess returns true if the interceptor reads or writes result
+// attributes.
+func (i *InterceptorExpr) HasResultAccess() bool {
+	return hasAttributes(i.ReadResult) || hasAttributes(i.WriteResult)
+}
+
+// Validate makes sure the attributes accessed by the interceptor are defined
+// by the payloads and results of all the methods it applies to using the same
+// types.
+func (i *InterceptorExpr) Validate() error {
+	verr := new(eval.ValidationErrors)
+	type access struct {
+		method   *MethodExpr
+		hash     string
+		required bool
+	}
+	seen := make(map[string]*access)
+	check := func(m *MethodExpr, parent *AttributeExpr, atts *AttributeExpr, kind string) {
+		if !hasAttributes(atts) {
+			return
+		}
+		obj := AsObject(parent.Type)
+		if obj == nil {
+			verr.Add(i, "%s of %s must be an object to be accessed by the interceptor", kind, m.EvalName())
+			return
+		}
+		for _, nat := range *AsObject(atts.Type) {
+			att := obj.Attribute(nat.Name)
+			if att == nil {
+				verr.Add(i, "%s of %s does not define attribute %q", kind, m.EvalName(), nat.Name)
+				continue
+			}
+			a := &access{m, Hash(att.Type, false, false, true), !parent.IsPrimitivePointer(nat.Name, true)}
+			key := kind + ":" + nat.Name
+			if prev, ok := seen[key]; !ok {
+				seen[key] = a
+			} else if prev.hash != a.hash || prev.required != a.required {
+				verr.Add(i, "attribute %q of the %s of %s and %s must have the same type and be required in both", nat.Name, kind, prev.method.EvalName(), m.EvalName())
+			}
+		}
+	}
+	for _, s := range Root.Services {
+		for _, m := range s.Methods {
+			server, client := m.applies(i)
+			if !server && !client {
+				continue
+			}
+			if m.IsStreaming() && (i.HasPayloadAccess() || i.HasResultAccess()) {
+				verr.Add(i, "interceptor cannot access the payload or result of the streaming %s", m.EvalName())
+				continue
+			}
+			check(m, m.Payload, i.ReadPayload, "payload")
+			check(m, m.Payload, i.WritePayload, "payload")
+			if server && i.HasResultAccess() {
+				if _, ok := m.Result.Type.(*ResultTypeExpr); ok {
+					verr.Add(i, "server interceptor cannot access the result of %s, result types with views are not supported", m.EvalName())
+					continue
+				}
+			}
+			check(m, m.Result, i.ReadResult, "result")
+			check(m, m.Result, i.WriteResult, "result")
+		}
+	}
+	return verr
+}
+
+// ServerInterceptorExprs returns the server interceptors that apply to the
+// method: the service interceptors followed by the method interceptors.
+func (m *MethodExpr) ServerInterceptorExprs() []*InterceptorExpr {
+	return mergeInterceptors(m.Service.ServerInterceptors, m.ServerInterceptors)
+}
+
+// ClientInterceptorExprs returns the client interceptors that apply to the
+// method: the service interceptors followed by the method interceptors.
+func (m *MethodExpr) ClientInterceptorExprs() []*InterceptorExpr {
+	return mergeInterceptors(m.Service.ClientInterceptors, m.ClientInterceptors)
+}
+
+// applies returns whether the given interceptor applies to the method server
+// side and client side.
+func (m *MethodExpr) applies(i *InterceptorExpr) (server, client bool) {
+	for _, si := range m.ServerInterceptorExprs() {
+		if si == i {
+			server = true
+		}
+	}
+	for _, ci := range m.ClientInterceptorExprs() {
+		if ci == i {
+			client = true
+		}
+	}
+	return
+}
+
+// mergeInterceptors appends the method interceptors to the service
+// interceptors omitting duplicates.
+func mergeInterceptors(svc, m []*InterceptorExpr) []*InterceptorExpr {
+	res := append([]*InterceptorExpr{}, svc...)
+	for _, i := range m {
+		found := false
+		for _, r := range res {
+			if r == i {
+				found = true
+				break
+			}
+		}
+		if !found {
+			res = append(res, i)
+		}
+	}
+	return res
+}
+
+// hasAttributes returns true if att is an object with at least one attribute.
+func hasAttributes(att *AttributeExpr) bool {
+	if att == nil {
+		return false
+	}
+	obj := AsObject(att.Type)
+	return obj != nil && len(*obj) > 0
+}
@@ -0,0 +1,46 @@
+package expr_test
+
+import (
+	"testing"
+
+	"goa.design/goa/v3/expr"
+	"goa.design/goa/v3/expr/testdata"
+)
+
+func TestInterceptorExprValidate(t *testing.T) {
+	root := expr.RunDSL(t, testdata.ValidInterceptorsDSL)
+	svc := root.Service("Valid")
+	if got := svc.Method("get").ServerInterceptorExprs(); len(got) != 2 || got[0].Name != "Log" || got[1].Name != "Cache" {
+		t.Errorf("got %d server interceptors for method get, expected Log and Cache", len(got))
+	}
+	if got := svc.Method("stream").ServerInterceptorExprs(); len(got) != 1 || got[0].Name != "Log" {
+		t.Errorf("got %d server interceptors for method stream, expected Log", len(got))
+	}
+	if got := svc.Method("get").ClientInterceptorExprs(); len(got) != 1 || got[0].Name != "Log" {
+		t.Errorf("got %d client interceptors for method get, expected Log", len(got))
+	}
+
+}
+
+func TestInterceptorExprValidateErrors(t *testing.T) {
+	cases := []struct {
+		Name  string
+		DSL   func()
+		Error string
+	}{
+		{"invalid", testdata.InvalidInterceptorsDSL, `interceptor "Cache": payload of service "Invalid" method "missing" does not define attribute "id"
+interceptor "Cache": result of service "Invalid" method "missing" must be an object to be accessed by the interceptor
+interceptor "Cache": attribute "id" of the payload of service "Invalid" method "mismatch" and service "Invalid" method "viewed" must have the same type and be required in both
+interceptor "Cache": server interceptor cannot access the result of service "Invalid" method "viewed", result types with views are not supported
+interceptor "Cache": interceptor cannot access the payload or result of the streaming service "Invalid" method "stream"`},
+		{"duplicate", testdata.DuplicateInterceptorDSL, `[testdata/interceptor_dsls.go:95] interceptor "Cache" already defined (top level)`},
+	}
+	for _, tc := range cases {
+		t.Run(tc.Name, func(t *testing.T) {
+			err := expr.RunInvalidDSL(t, tc.DSL)
+			if tc.Error != err.Error() {
+				t.Errorf("invalid error:\ngot:\n%s\n\ngot vs expected:\n%s", err.Error(), expr.Diff(t, err.Error(), tc.Error))
+			}
+		})
+	}
+}
@@ -0,0 +1,88 @@
+package expr
+
+import "goa.design/goa/v3/eval"
+
+const (
+	// JSONAPITypeMeta is the meta key used to render a result type
+	// following the JSON:API convention. The value is the JSON:API resource
+	// type.
+	JSONAPITypeMeta = "jsonapi:type"
+
+	// JSONAPIRelationshipMeta is the meta key used to render an attribute
+	// of a JSON:API result type as a relationship. The value is the JSON:API
+	// type of the related resources.
+	JSONAPIRelationshipMeta = "jsonapi:relationship"
+)
+
+// JSONAPIType returns the JSON:API resource type of the result type, the
+// empty string if the result type does not follow the JSON:API convention.
+func (m *ResultTypeExpr) JSONAPIType() string {
+	if t, ok := m.Meta[JSONAPITypeMeta]; ok && len(t) > 0 {
+		return t[0]
+	}
+	return ""
+}
+
+// JSONAPIResult returns the result type rendered following the JSON:API
+// convention given a method result: the result type itself or the element
+// result type if the result is a collection. It returns nil if the result type
+// does not define the "jsonapi:type" meta.
+func JSONAPIResult(result *AttributeExpr) *ResultTypeExpr {
+	rt, ok := result.Type.(*ResultTypeExpr)
+	if !ok {
+		return nil
+	}
+	if arr := AsArray(rt); arr != nil {
+		if rt, ok = arr.ElemType.Type.(*ResultTypeExpr); !ok {
+			return nil
+		}
+	}
+	if rt.JSONAPIType() == "" {
+		return nil
+	}
+	return rt
+}
+
+// JSONAPIRelationships returns the JSON:API types of the related resources
+// indexed by the names of the attributes of the result type that define the
+// "jsonapi:relationship" meta.
+func (m *ResultTypeExpr) JSONAPIRelationships() map[string]string {
+	obj := AsObject(m.Type)
+	if obj == nil {
+		return nil
+	}
+	var rels map[string]string
+	for _, nat := range *obj {
+		t, ok := nat.Attribute.Meta[JSONAPIRelationshipMeta]
+		if !ok || len(t) == 0 {
+			continue
+		}
+		if rels == nil {
+			rels = make(map[string]string)
+		}
+		rels[nat.Name] = t[0]
+	}
+	return rels
+}
+
+// validateJSONAPI makes sure JSON:API result types define an "id" attribute
+// and that relationships are only defined on JSON:API result types.
+func (m *ResultTypeExpr) validateJSONAPI() *eval.ValidationErrors {
+	verr := new(eval.ValidationErrors)
+	obj := AsObject(m.Type)
+	if obj == nil {
+		return verr
+	}
+	if m.JSONAPIType() == "" {
+		for _, nat := range *obj {
+			if _, ok := nat.Attribute.Meta[JSONAPIRelationshipMeta]; ok {
+				verr.Add(m, "attribute %q defines the %q meta but the result type does not define the %q meta", nat.Name, JSONAPIRelationshipMeta, JSONAPITypeMeta)
+			}
+		}
+		return verr
+	}
+	if obj.Attribute("id") == nil {
+		verr.Add(m, "JSON:API result types must define an \"id\" attribute")
+	}
+	return verr
+}
@@ -0,0 +1,244 @@
+package expr
+
+import (
+	"fmt"
+	"net/url"
+	"strings"
+
+	"goa.design/goa/v3/eval"
+)
+
+type (
+	// LinksExpr is the expression used to evaluate the Links DSL of a
+	// result type.
+	LinksExpr struct {
+		// Parent is the result type the links belong to.
+		Parent *ResultTypeExpr
+	}
+
+	// LinkExpr describes a link from a result type to a related resource
+	// served by a HTTP endpoint. The HTTP server renders the links in the
+	// response body using the URL of the endpoint built from the values of
+	// the result type attributes.
+	LinkExpr struct {
+		// Name is the name of the link relation, e.g. "self".
+		Name string
+		// Service is the name of the service of the endpoint.
+		Service string
+		// Method is the name of the method of the endpoint.
+		Method string
+		// Params maps the names of the endpoint path parameters to the
+		// names of the result type attributes that provide their values.
+		// Path parameters that are not listed use the attribute with the
+		// same name.
+		Params map[string]string
+		// Parent is the result type the link belongs to.
+		Parent *ResultTypeExpr
+	}
+)
+
+const (
+	// LinksFormatMeta is the meta key used to select the format of the
+	// links, either "hal" (default) or "jsonapi". The meta may be set on
+	// the result type or on the API.
+	LinksFormatMeta = "links:format"
+
+	// LinksMeta is the meta set on the attribute that holds the links
+	// of a result type.
+	LinksMeta = "links"
+)
+
+// EvalName returns the generic expression name used in error messages.
+func (l *LinksExpr) EvalName() string {
+	return "links of " + l.Parent.EvalName()
+}
+
+// EvalName returns the generic expression name used in error messages.
+func (l *LinkExpr) EvalName() string {
+	return fmt.Sprintf("link %q of %s", l.Name, l.Parent.EvalName())
+}
+
+// Endpoint returns the HTTP endpoint of the link, nil if there is none.
+func (l *LinkExpr) Endpoint() *HTTPEndpointExpr {
+	if Root.API == nil || Root.API.HTTP == nil {
+		return nil
+	}
+	svc := Root.API.HTTP.Service(l.Service)
+	if svc == nil {
+		return nil
+	}
+	return svc.Endpoint(l.Method)
+}
+
+// Pattern returns the path of the first route of the link endpoint including
+// the API and service base paths.
+func (l *LinkExpr) Pattern() string {
+	e := l.Endpoint()
+	if e == nil || len(e.Routes) == 0 {
+		return ""
+	}
+	if paths := e.Routes[0].FullPaths(); len(paths) > 0 {
+		return paths[0]
+	}
+	return ""
+}
+
+// Attribute returns the name of the result type attribute that provides the
+// value of the given path parameter.
+func (l *LinkExpr) Attribute(param string) string {
+	if att, ok := l.Params[param]; ok {
+		return att
+	}
+	return param
+}
+
+// Validate makes sure the link endpoint exists and that all its path
+// parameters map to attributes of the result type.
+func (l *LinkExpr) Validate() *eval.ValidationErrors {
+	verr := new(eval.ValidationErrors)
+	e := l.Endpoint()
+	if e == nil {
+		verr.Add(l, "there is no HTTP endpoint for method %q of service %q", l.Method, l.Service)
+		return verr
+	}
+	if IsArray(l.Parent.Type) {
+		verr.Add(l, "links cannot be defined on collections, define them on the element result type instead")
+		return verr
+	}
+	obj := AsObject(l.Parent.Type)
+	wcs := ExtractHTTPWildcards(l.Pattern())
+	for _, wc := range wcs {
+		if obj == nil || obj.Attribute(l.Attribute(wc)) == nil {
+			verr.Add(l, "path parameter %q of %s has no corresponding result type attribute %q", wc, e.EvalName(), l.Attribute(wc))
+		}
+	}
+	for p := range l.Params {
+		found := false
+		for _, wc := range wcs {
+			if wc == p {
+				found = true
+				break
+			}
+		}
+		if !found {
+			verr.Add(l, "%q is not a path parameter of %s, path parameters are %s", p, e.EvalName(), strings.Join(wcs, ", "))
+		}
+	}
+	return verr
+}
+
+// LinksAttributeName returns the name of the attribute that holds the links
+// of the result type: "_links" for the HAL format (default) or "links" for
+// the JSON:API format. Result types that define the "jsonapi:type" meta
+// always use the JSON:API format.
+func (m *ResultTypeExpr) LinksAttributeName() string {
+	format := "hal"
+	if m.JSONAPIType() != "" {
+		format = "jsonapi"
+	} else if f, ok := m.Meta[LinksFormatMeta]; ok && len(f) > 0 {
+		format = f[0]
+	} else if Root.API != nil {
+		if f, ok := Root.API.Meta[LinksFormatMeta]; ok && len(f) > 0 {
+			format = f[0]
+		}
+	}
+	if format == "jsonapi" {
+		return "links"
+	}
+	return "_links"
+}
+
+// LinksAttribute returns the attribute that holds the links of the result
+// type, nil if the result type has no link.
+func (m *ResultTypeExpr) LinksAttribute() *NamedAttributeExpr {
+	if len(m.Links) == 0 {
+		return nil
+	}
+	obj := AsObject(m.Type)
+	if obj == nil {
+		return nil
+	}
+	for _, nat := range *obj {
+		if _, ok := nat.Attribute.Meta[LinksMeta]; ok {
+			return nat
+		}
+	}
+	return nil
+}
+
+// finalizeLinks adds the attribute that holds the links to the result type
+// and to its views. The links are rendered as an object whose keys are the
+// link names and values are link objects with a "href" key.
+func (m *ResultTypeExpr) finalizeLinks() {
+	obj := AsObject(m.Type)
+	if len(m.Links) == 0 || obj == nil || m.LinksAttribute() != nil {
+		return
+	}
+	nat := &NamedAttributeExpr{
+		Name: m.LinksAttributeName(),
+		Attribute: &AttributeExpr{
+			Type: &Map{
+				KeyType: &AttributeExpr{Type: String},
+				ElemType: &AttributeExpr{Type: &Map{
+					KeyType:  &AttributeExpr{Type: String},
+					ElemType: &AttributeExpr{Type: String},
+				}},
+			},
+			Description: "Links to the related resources indexed by relation name.",
+			// The examples of the links are built from the examples of
+			// the attributes mapped to the link path parameters, see
+			// ResultTypeExpr.Example.
+			Meta: MetaExpr{LinksMeta: nil, "openapi:example": []string{"false"}},
+		},
+	}
+	obj.Set(nat.Name, nat.Attribute)
+	for _, v := range m.Views {
+		if vobj := AsObject(v.Type); vobj != nil && vobj.Attribute(nat.Name) == nil {
+			vobj.Set(nat.Name, nat.Attribute)
+		}
+	}
+}
+
+// Example returns a random example of the result type. The links of the
+// example are built from the values of the attributes that provide the link
+// path parameters.
+func (m *ResultTypeExpr) Example(r *ExampleGenerator) any {
+	ex := m.UserTypeExpr.Example(r)
+	nat := m.LinksAttribute()
+	obj, ok := ex.(map[string]any)
+	if nat == nil || !ok {
+		return ex
+	}
+	links := make(map[string]map[string]string)
+	for _, l := range m.Links {
+		if href, ok := l.exampleHref(obj); ok {
+			links[l.Name] = map[string]string{"href": href}
+		}
+	}
+	if len(links) > 0 {
+		obj[nat.Name] = links
+	}
+	return ex
+}
+
+// exampleHref returns the URL of the link built from the given result type
+// example. It returns false if the example is missing a path parameter value.
+func (l *LinkExpr) exampleHref(ex map[string]any) (string, bool) {
+	href := l.Pattern()
+	if href == "" {
+		return "", false
+	}
+	for _, wc := range ExtractHTTPWildcards(href) {
+		v, ok := ex[l.Attribute(wc)]
+		if !ok || v == nil {
+			return "", false
+		}
+		s := fmt.Sprint(v)
+		segs := strings.Split(s, "/")
+		for i, seg := range segs {
+			segs[i] = url.PathEscape(seg)
+		}
+		href = strings.NewReplacer("{"+wc+"}", url.PathEscape(s), "{*"+wc+"}", strings.Join(segs, "/")).Replace(href)
+	}
+	return href, true
+}
@@ -31,6 +31,12 @@ type (
 		// schemes. Incoming requests must validate at least one
 		// requirement to be authorized.
 		Requirements []*SecurityExpr
+		// Policy is the authorization policy of the method if any. The
+		// policy of the service applies if nil.
+		Policy *PolicyExpr
+		// Pagination describes how the method results are paginated if
+		// any.
+		Pagination *PaginationExpr
 		// Service that owns method.
 		Service *ServiceExpr
 		// Meta is an arbitrary set of key/value pairs, see dsl.Meta
@@ -40,6 +46,14 @@ type (
 		Stream StreamKind
 		// StreamingPayload is the payload sent across the stream.
 		StreamingPayload *AttributeExpr
+		// ServerInterceptors lists the interceptors invoked around the
+		// server side endpoint of the method in addition to the service
+		// interceptors.
+		ServerInterceptors []*InterceptorExpr
+		// ClientInterceptors lists the interceptors invoked around the
+		// client side endpoint of the method in addition to the service
+		// interceptors.
+		ClientInterceptors []*InterceptorExpr
 	}
 )
 
@@ -190,6 +204,15 @@ func (m *MethodExpr) Validate() error {
 	if m.Result.Type != Empty {
 		verr.Merge(m.Result.Validate("result", m))
 	}
+	verr.Merge(m.validateEncrypted())
+	verr.Merge(m.validatePatches())
+	verr.Merge(m.validateFieldMasks())
+	if m.Policy != nil {
+		verr.Merge(m.Policy.Validate())
+	}
+	if m.Pagination != nil {
+		verr.Merge(m.Pagination.Validate())
+	}
 	for i, e := range m.Errors {
 		if err := e.Validate(); err != nil {
 			if verrs, ok := err.(*eval.ValidationErrors); ok {
@@ -0,0 +1,70 @@
+package expr
+
+import (
+	"goa.design/goa/v3/eval"
+)
+
+// PaginationExpr describes a method whose results are returned one page at a
+// time. The payload cursor attribute identifies the page to return and the
+// result defines the items of the page and the cursor of the next page.
+type PaginationExpr struct {
+	// Cursor is the name of the payload attribute that identifies the
+	// requested page.
+	Cursor string
+	// NextCursor is the name of the result attribute that identifies the
+	// next page. The next cursor is empty or zero on the last page.
+	NextCursor string
+	// Items is the name of the result array attribute that holds the items
+	// of the page.
+	Items string
+	// Method is the paginated method.
+	Method *MethodExpr
+}
+
+// EvalName returns the generic expression name used in error messages.
+func (p *PaginationExpr) EvalName() string {
+	suffix := "pagination"
+	if p.Method != nil {
+		return suffix + " of " + p.Method.EvalName()
+	}
+	return suffix
+}
+
+// Validate makes sure the cursor attributes are primitives of the same type
+// defined by the method payload and result and that the items attribute is an
+// array.
+func (p *PaginationExpr) Validate() *eval.ValidationErrors {
+	verr := new(eval.ValidationErrors)
+	m := p.Method
+	if m.IsStreaming() {
+		verr.Add(p, "streaming methods cannot be paginated")
+		return verr
+	}
+	if !IsObject(m.Payload.Type) {
+		verr.Add(p, "payload of paginated method must be an object")
+		return verr
+	}
+	if !IsObject(m.Result.Type) {
+		verr.Add(p, "result of paginated method must be an object")
+		return verr
+	}
+	cursor := m.Payload.Find(p.Cursor)
+	if cursor == nil {
+		verr.Add(p, "cursor attribute %q is not defined in the payload", p.Cursor)
+	} else if !IsPrimitive(cursor.Type) || cursor.Type == Bytes || cursor.Type == Any {
+		verr.Add(p, "cursor attribute %q must be a string or a number", p.Cursor)
+	}
+	next := m.Result.Find(p.NextCursor)
+	if next == nil {
+		verr.Add(p, "next cursor attribute %q is not defined in the result", p.NextCursor)
+	} else if cursor != nil && next.Type.Name() != cursor.Type.Name() {
+		verr.Add(p, "next cursor attribute %q must have the same type as the cursor attribute %q", p.NextCursor, p.Cursor)
+	}
+	items := m.Result.Find(p.Items)
+	if items == nil {
+		verr.Add(p, "items attribute %q is not defined in the result", p.Items)
+	} else if !IsArray(items.Type) {
+		verr.Add(p, "items attribute %q must be an array", p.Items)
+	}
+	return verr
+}
@@ -0,0 +1,29 @@
+package expr_test
+
+import (
+	"testing"
+
+	"goa.design/goa/v3/expr"
+	"goa.design/goa/v3/expr/testdata"
+)
+
+func TestPaginationExprValidate(t *testing.T) {
+	root := expr.RunDSL(t, testdata.ValidPaginationDSL)
+	pg := root.Service("Valid").Method("list").Pagination
+	if pg == nil || pg.Cursor != "page" || pg.NextCursor != "next_page" || pg.Items != "items" {
+		t.Errorf("got pagination %+v, expected page, next_page and items", pg)
+	}
+}
+
+func TestPaginationExprValidateErrors(t *testing.T) {
+	err := expr.RunInvalidDSL(t, testdata.InvalidPaginationDSL)
+	expected := `pagination of service "Invalid" method "missing": cursor attribute "token" is not defined in the payload
+pagination of service "Invalid" method "missing": next cursor attribute "next" is not defined in the result
+pagination of service "Invalid" method "missing": items attribute "values" is not defined in the result
+pagination of service "Invalid" method "types": next cursor attribute "next_page" must have the same type as the cursor attribute "page"
+pagination of service "Invalid" method "types": items attribute "items" must be an array
+pagination of service "Invalid" method "not-object": payload of paginated method must be an object`
+	if err.Error() != expected {
+		t.Errorf("invalid error:\ngot:\n%s\n\ngot vs expected:\n%s", err.Error(), expr.Diff(t, err.Error(), expected))
+	}
+}
@@ -0,0 +1,37 @@
+package expr
+
+import "goa.design/goa/v3/eval"
+
+// PatchTarget returns the type targeted by the JSON Patch or JSON Merge Patch
+// document held by the given attribute, nil if the attribute was not defined
+// with the Patch DSL.
+func PatchTarget(att *AttributeExpr) UserType {
+	name, ok := att.Meta.Last("patch:target")
+	if !ok {
+		return nil
+	}
+	return Root.UserType(name)
+}
+
+// validatePatches checks that the attributes defined with the Patch DSL are
+// payload attributes that target object user types.
+func (m *MethodExpr) validatePatches() *eval.ValidationErrors {
+	verr := new(eval.ValidationErrors)
+	check := func(att *AttributeExpr, kind string) {
+		walkAttribute(att, func(name string, a *AttributeExpr) error { // nolint: errcheck
+			if _, ok := a.Meta["patch:target"]; !ok {
+				return nil
+			}
+			if kind != "payload" {
+				verr.Add(m, "attribute %q of the %s cannot hold a patch, only payload attributes can", name, kind)
+			} else if ut := PatchTarget(a); ut == nil || !IsObject(ut) {
+				verr.Add(m, "patch attribute %q must target an object user type", name)
+			}
+			return nil
+		})
+	}
+	check(m.Payload, "payload")
+	check(m.StreamingPayload, "streaming payload")
+	check(m.Result, "result")
+	return verr
+}
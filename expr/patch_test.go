@@ -0,0 +1,28 @@
+package expr_test
+
+import (
+	"testing"
+
+	"goa.design/goa/v3/expr"
+	"goa.design/goa/v3/expr/testdata"
+)
+
+func TestPatchTarget(t *testing.T) {
+	root := expr.RunDSL(t, testdata.ValidPatchDSL)
+	payload := root.Service("Valid").Method("update").Payload
+	if ut := expr.PatchTarget(payload.Find("patch")); ut == nil || ut.Name() != "Account" {
+		t.Errorf("got patch target %v, expected Account", ut)
+	}
+	if ut := expr.PatchTarget(payload.Find("id")); ut != nil {
+		t.Errorf("got patch target %q for id, expected nil", ut.Name())
+	}
+}
+
+func TestPatchValidateErrors(t *testing.T) {
+	err := expr.RunInvalidDSL(t, testdata.InvalidPatchDSL)
+	expected := `service "Invalid" method "not-object": patch attribute "patch" must target an object user type
+service "Invalid" method "result": attribute "patch" of the result cannot hold a patch, only payload attributes can`
+	if err.Error() != expected {
+		t.Errorf("invalid error:\ngot:\n%s\n\ngot vs expected:\n%s", err.Error(), expr.Diff(t, err.Error(), expected))
+	}
+}
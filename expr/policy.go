@@ -0,0 +1,66 @@
+package expr
+
+import (
+	"goa.design/goa/v3/eval"
+)
+
+// PolicyExpr describes the authorization policy of a method, it lists the
+// roles and permissions required to call the method and an optional rule
+// evaluated by the service authorizer.
+type PolicyExpr struct {
+	// Roles lists the roles authorized to call the method, callers must
+	// have at least one of them.
+	Roles []string
+	// Permissions lists the permissions required to call the method,
+	// callers must have all of them.
+	Permissions []string
+	// Rule is an authorization expression evaluated by the service
+	// authorizer, e.g. a Rego query or a Casbin matcher.
+	Rule string
+	// Parent is the method or service expression that defines the policy.
+	Parent eval.Expression
+}
+
+// EvalName returns the generic expression name used in error messages.
+func (p *PolicyExpr) EvalName() string {
+	suffix := "policy"
+	if p.Parent != nil {
+		return suffix + " of " + p.Parent.EvalName()
+	}
+	return suffix
+}
+
+// Validate makes sure the policy requires at least one role, permission or
+// rule.
+func (p *PolicyExpr) Validate() *eval.ValidationErrors {
+	verr := new(eval.ValidationErrors)
+	if len(p.Roles) == 0 && len(p.Permissions) == 0 && p.Rule == "" {
+		verr.Add(p, "policy must define at least one role, permission or rule, use Roles, Permissions or Rule to define one")
+	}
+	seen := make(map[string]struct{})
+	for _, r := range p.Roles {
+		if _, ok := seen["role:"+r]; ok {
+			verr.Add(p, "role %q is listed more than once", r)
+		}
+		seen["role:"+r] = struct{}{}
+	}
+	for _, perm := range p.Permissions {
+		if _, ok := seen["perm:"+perm]; ok {
+			verr.Add(p, "permission %q is listed more than once", perm)
+		}
+		seen["perm:"+perm] = struct{}{}
+	}
+	return verr
+}
+
+// EffectivePolicy returns the policy that applies to the method: the policy
+// defined on the method if any, the policy of the service otherwise.
+func (m *MethodExpr) EffectivePolicy() *PolicyExpr {
+	if m.Policy != nil {
+		return m.Policy
+	}
+	if m.Service != nil {
+		return m.Service.Policy
+	}
+	return nil
+}
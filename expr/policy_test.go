@@ -0,0 +1,29 @@
+package expr_test
+
+import (
+	"testing"
+
+	"goa.design/goa/v3/expr"
+	"goa.design/goa/v3/expr/testdata"
+)
+
+func TestPolicyExprValidate(t *testing.T) {
+	root := expr.RunDSL(t, testdata.ValidPolicyDSL)
+	svc := root.Service("Valid")
+	if p := svc.Method("inherited").EffectivePolicy(); p == nil || len(p.Roles) != 1 || p.Roles[0] != "admin" {
+		t.Errorf("got policy %+v for method inherited, expected the service policy", p)
+	}
+	if p := svc.Method("override").EffectivePolicy(); p == nil || len(p.Roles) != 0 || len(p.Permissions) != 1 || p.Rule == "" {
+		t.Errorf("got policy %+v for method override, expected the method policy", p)
+	}
+}
+
+func TestPolicyExprValidateErrors(t *testing.T) {
+	err := expr.RunInvalidDSL(t, testdata.InvalidPolicyDSL)
+	expected := `policy of service "Invalid": policy must define at least one role, permission or rule, use Roles, Permissions or Rule to define one
+policy of service "Invalid" method "duplicate": role "admin" is listed more than once
+policy of service "Invalid" method "duplicate": permission "read" is listed more than once`
+	if err.Error() != expected {
+		t.Errorf("invalid error:\ngot:\n%s\n\ngot vs expected:\n%s", err.Error(), expr.Diff(t, err.Error(), expected))
+	}
+}
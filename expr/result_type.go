@@ -26,6 +26,9 @@ type (
 		ContentType string
 		// Views list the supported views indexed by name.
 		Views []*ViewExpr
+		// Links lists the links to related resources rendered in HTTP
+		// responses.
+		Links []*LinkExpr
 	}
 
 	// ViewExpr defines which fields to render when building a response. The view
@@ -170,9 +173,11 @@ func (m *ResultTypeExpr) ViewHasAttribute(view, attr string) bool {
 	return v.AttributeExpr.Find(attr) != nil
 }
 
-// Finalize builds the default view if not explicitly defined and finalizes
-// the underlying UserTypeExpr.
+// Finalize adds the links attribute if the result type defines links, builds
+// the default view if not explicitly defined and finalizes the underlying
+// UserTypeExpr.
 func (m *ResultTypeExpr) Finalize() {
+	m.finalizeLinks()
 	if m.View("default") == nil {
 		m.ensureDefaultView()
 	}
@@ -34,6 +34,8 @@ type (
 		Creations []*TypeMap
 		// Schemes list the registered security schemes.
 		Schemes []*SchemeExpr
+		// Interceptors lists the interceptors defined in the DSL.
+		Interceptors []*InterceptorExpr
 	}
 
 	// MetaExpr is a set of key/value pairs
@@ -79,6 +81,9 @@ func (r *RootExpr) WalkSets(walk eval.SetWalker) {
 	}
 	walk(mtypes)
 
+	// Interceptors
+	walk(eval.ToExpressionSet(r.Interceptors))
+
 	// Services
 	walk(eval.ToExpressionSet(r.Services))
 
@@ -0,0 +1,140 @@
+package expr
+
+import (
+	"goa.design/goa/v3/eval"
+)
+
+const (
+	// SensitiveMetaKey is the meta key used to identify attributes that
+	// hold sensitive data. Sensitive attributes are redacted in logs and
+	// are not included in the generated examples.
+	SensitiveMetaKey = "sensitive"
+
+	// EncryptedMetaKey is the meta key used to identify sensitive attributes
+	// that are encrypted at the transport boundary.
+	EncryptedMetaKey = "sensitive:encrypt"
+)
+
+// IsSensitive returns true if the attribute is marked as holding sensitive
+// data.
+func (a *AttributeExpr) IsSensitive() bool {
+	if a == nil {
+		return false
+	}
+	_, ok := a.Meta[SensitiveMetaKey]
+	return ok
+}
+
+// IsEncrypted returns true if the attribute is marked as being encrypted at
+// the transport boundary.
+func (a *AttributeExpr) IsEncrypted() bool {
+	if a == nil {
+		return false
+	}
+	_, ok := a.Meta[EncryptedMetaKey]
+	return ok
+}
+
+// HasSensitive returns true if the given attribute is an object that defines
+// sensitive attributes directly or in any of its child attributes.
+func HasSensitive(att *AttributeExpr) bool {
+	return hasSensitive(att, make(map[string]struct{}))
+}
+
+// HasEncrypted returns true if the given attribute is an object that defines
+// encrypted attributes directly.
+func HasEncrypted(att *AttributeExpr) bool {
+	obj := AsObject(att.Type)
+	if obj == nil {
+		return false
+	}
+	for _, nat := range *obj {
+		if nat.Attribute.IsEncrypted() {
+			return true
+		}
+	}
+	return false
+}
+
+// validateEncrypted makes sure the encrypted attributes of the method payload
+// and result are top level string attributes of non-streaming methods.
+func (m *MethodExpr) validateEncrypted() *eval.ValidationErrors {
+	verr := new(eval.ValidationErrors)
+	check := func(att *AttributeExpr, kind string) {
+		obj := AsObject(att.Type)
+		if obj == nil {
+			for _, name := range nestedEncrypted(att, make(map[string]struct{})) {
+				verr.Add(m, "attribute %q of the %s cannot be encrypted, only the top level attributes of payloads and results can be encrypted", name, kind)
+			}
+			return
+		}
+		for _, nat := range *obj {
+			if nat.Attribute.IsEncrypted() {
+				if m.IsStreaming() {
+					verr.Add(m, "attribute %q of the %s cannot be encrypted, streaming methods do not support encryption", nat.Name, kind)
+				} else if nat.Attribute.Type.Kind() != StringKind {
+					verr.Add(m, "attribute %q of the %s cannot be encrypted, only string attributes can be encrypted", nat.Name, kind)
+				}
+			}
+			for _, name := range nestedEncrypted(nat.Attribute, make(map[string]struct{})) {
+				verr.Add(m, "attribute %q of the %s cannot be encrypted, only the top level attributes of payloads and results can be encrypted", name, kind)
+			}
+		}
+	}
+	check(m.Payload, "payload")
+	check(m.Result, "result")
+	return verr
+}
+
+// hasSensitive implements HasSensitive, seen records the user types already
+// visited to handle recursive types.
+func hasSensitive(att *AttributeExpr, seen map[string]struct{}) bool {
+	if ut, ok := att.Type.(UserType); ok {
+		if _, ok := seen[ut.ID()]; ok {
+			return false
+		}
+		seen[ut.ID()] = struct{}{}
+	}
+	if arr := AsArray(att.Type); arr != nil {
+		return hasSensitive(arr.ElemType, seen)
+	}
+	obj := AsObject(att.Type)
+	if obj == nil {
+		return false
+	}
+	for _, nat := range *obj {
+		if nat.Attribute.IsSensitive() || hasSensitive(nat.Attribute, seen) {
+			return true
+		}
+	}
+	return false
+}
+
+// nestedEncrypted returns the names of the encrypted attributes defined by
+// the child attributes of att recursively.
+func nestedEncrypted(att *AttributeExpr, seen map[string]struct{}) []string {
+	if ut, ok := att.Type.(UserType); ok {
+		if _, ok := seen[ut.ID()]; ok {
+			return nil
+		}
+		seen[ut.ID()] = struct{}{}
+	}
+	switch {
+	case IsArray(att.Type):
+		return nestedEncrypted(AsArray(att.Type).ElemType, seen)
+	case IsMap(att.Type):
+		return append(nestedEncrypted(AsMap(att.Type).KeyType, seen), nestedEncrypted(AsMap(att.Type).ElemType, seen)...)
+	}
+	obj := AsObject(att.Type)
+	if obj == nil {
+		return nil
+	}
+	var names []string
+	for _, nat := range *obj {
+		if nat.Attribute.IsEncrypted() {
+			names = append(names, nat.Name)
+		}
+		names = append(names, nestedEncrypted(nat.Attribute, seen)...)
+	}
+	return names
+}
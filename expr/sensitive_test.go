@@ -0,0 +1,40 @@
+package expr_test
+
+import (
+	"testing"
+
+	"goa.design/goa/v3/expr"
+	"goa.design/goa/v3/expr/testdata"
+)
+
+func TestSensitiveExprValidate(t *testing.T) {
+	root := expr.RunDSL(t, testdata.ValidSensitiveDSL)
+	user := expr.AsObject(root.UserType("User").Attribute().Type)
+	if email := user.Attribute("email"); !email.IsSensitive() || email.IsEncrypted() {
+		t.Errorf("got sensitive %v and encrypted %v for email, expected sensitive only", email.IsSensitive(), email.IsEncrypted())
+	}
+	if ex := user.Attribute("email").Example(root.API.ExampleGenerator); ex != nil {
+		t.Errorf("got example %v for sensitive attribute, expected nil", ex)
+	}
+	m := root.Service("Valid").Method("signup")
+	if !expr.HasSensitive(m.Payload) {
+		t.Error("got no sensitive attribute in signup payload, expected some")
+	}
+	if !expr.HasEncrypted(m.Payload) || !expr.HasEncrypted(m.Result) {
+		t.Error("got no encrypted attribute in signup payload or result, expected some")
+	}
+	if ssn := expr.AsObject(m.Payload.Type).Attribute("ssn"); !ssn.IsSensitive() || !ssn.IsEncrypted() {
+		t.Errorf("got sensitive %v and encrypted %v for ssn, expected both", ssn.IsSensitive(), ssn.IsEncrypted())
+	}
+}
+
+func TestSensitiveExprValidateErrors(t *testing.T) {
+	err := expr.RunInvalidDSL(t, testdata.InvalidSensitiveDSL)
+	expected := `service "Invalid" method "kind": attribute "pin" of the payload cannot be encrypted, only string attributes can be encrypted
+service "Invalid" method "nested": attribute "value" of the payload cannot be encrypted, only the top level attributes of payloads and results can be encrypted
+service "Invalid" method "nested": attribute "value" of the result cannot be encrypted, only the top level attributes of payloads and results can be encrypted
+service "Invalid" method "stream": attribute "ssn" of the payload cannot be encrypted, streaming methods do not support encryption`
+	if err.Error() != expected {
+		t.Errorf("invalid error:\ngot:\n%s\n\ngot vs expected:\n%s", err.Error(), expr.Diff(t, err.Error(), expected))
+	}
+}
@@ -21,11 +21,22 @@ type (
 		Methods []*MethodExpr
 		// Errors list the errors common to all the service methods.
 		Errors []*ErrorExpr
+		// Events lists the domain events published by the service.
+		Events []*EventExpr
+		// ServerInterceptors lists the interceptors invoked around the
+		// server side endpoints of all the service methods.
+		ServerInterceptors []*InterceptorExpr
+		// ClientInterceptors lists the interceptors invoked around the
+		// client side endpoints of all the service methods.
+		ClientInterceptors []*InterceptorExpr
 		// Requirements contains the security requirements that apply to
 		// all the service methods. One requirement is composed of
 		// potentially multiple schemes. Incoming requests must validate
 		// at least one requirement to be authorized.
 		Requirements []*SecurityExpr
+		// Policy is the authorization policy that applies to the service
+		// methods that do not define one.
+		Policy *PolicyExpr
 		// Meta is a set of key/value pairs with semantic that is
 		// specific to each generator.
 		Meta MetaExpr
@@ -52,6 +63,16 @@ func (s *ServiceExpr) Method(n string) *MethodExpr {
 	return nil
 }
 
+// Event returns the event with the given name, nil if there isn't one.
+func (s *ServiceExpr) Event(n string) *EventExpr {
+	for _, e := range s.Events {
+		if e.Name == n {
+			return e
+		}
+	}
+	return nil
+}
+
 // EvalName returns the generic expression name used in error messages.
 func (s *ServiceExpr) EvalName() string {
 	if s.Name == "" {
@@ -75,7 +96,7 @@ func (s *ServiceExpr) Hash() string {
 	return "_service_+" + s.Name
 }
 
-// Validate validates the service methods and errors.
+// Validate validates the service methods, errors and events.
 func (s *ServiceExpr) Validate() error {
 	verr := new(eval.ValidationErrors)
 	for _, e := range s.Errors {
@@ -85,14 +106,28 @@ func (s *ServiceExpr) Validate() error {
 			}
 		}
 	}
+	names := make(map[string]struct{}, len(s.Events))
+	for _, e := range s.Events {
+		if _, ok := names[e.Name]; ok {
+			verr.Add(s, "Multiple events named %q.", e.Name)
+		}
+		names[e.Name] = struct{}{}
+		verr.Merge(e.Validate())
+	}
+	if s.Policy != nil {
+		verr.Merge(s.Policy.Validate())
+	}
 	return verr
 }
 
-// Finalize finalizes all the service methods and errors.
+// Finalize finalizes all the service methods, errors and events.
 func (s *ServiceExpr) Finalize() {
 	for _, e := range s.Errors {
 		e.Finalize()
 	}
+	for _, e := range s.Events {
+		e.Finalize()
+	}
 }
 
 // Validate checks that the error name is found in the result meta for
@@ -94,6 +94,9 @@ func TestServiceExprValidate(t *testing.T) {
 		Error string
 	}{
 		{"service errors", testdata.ServiceErrorDSL, `attribute: error name "a" must be required in type "ServiceError"`},
+		{"service events", testdata.ServiceEventsDSL, `event "created" of service "InvalidEvents": event must define a payload
+service "InvalidEvents": Multiple events named "created".
+event "deleted" of service "InvalidEvents": event topic cannot be empty`},
 	}
 
 	for _, tc := range cases {
@@ -329,6 +329,67 @@ var EndpointHasParent = func() {
 	})
 }
 
+var EndpointInheritedHeaders = func() {
+	var Account = Type("Account", func() {
+		Attribute("id", String)
+		Required("id")
+	})
+	API("API", func() {
+		HTTP(func() {
+			Headers(func() {
+				Header("tenant:X-Tenant-Id", String, func() {
+					Pattern("^[a-z]+$")
+				})
+				Required("tenant")
+			})
+		})
+	})
+	Service("Service", func() {
+		HTTP(func() {
+			Header("key:Idempotency-Key", String)
+		})
+		Method("Method", func() {
+			Payload(func() {
+				Attribute("name", String)
+				Attribute("key", Int)
+			})
+			HTTP(func() {
+				POST("/")
+			})
+		})
+		Method("Empty", func() {
+			HTTP(func() {
+				GET("/")
+			})
+		})
+		Method("Shared", func() {
+			Payload(Account)
+			Result(Account)
+			HTTP(func() {
+				PUT("/")
+			})
+		})
+	})
+}
+
+var EndpointInheritedHeadersPrimitive = func() {
+	API("API", func() {
+		HTTP(func() {
+			Headers(func() {
+				Header("tenant:X-Tenant-Id", String)
+			})
+		})
+	})
+	Service("Service", func() {
+		Method("Method", func() {
+			Payload(String)
+			HTTP(func() {
+				POST("/")
+			})
+		})
+	})
+}
+
 var EndpointHasParentAndOther = func() {
 	Service("Parent", func() {
 		HTTP(func() {
@@ -705,3 +766,277 @@ var GRPCEndpointWithExtendedTypes = func() {
 		})
 	})
 }
+
+var EndpointFieldSelectionPrimitiveResult = func() {
+	Service("Service", func() {
+		Method("Method", func() {
+			Result(String)
+			HTTP(func() {
+				GET("/")
+				FieldSelection()
+			})
+		})
+	})
+}
+
+var EndpointFieldSelectionParamConflict = func() {
+	Service("Service", func() {
+		Method("Method", func() {
+			Payload(func() {
+				Attribute("fields", String)
+			})
+			Result(func() {
+				Attribute("name", String)
+			})
+			HTTP(func() {
+				GET("/")
+				Param("fields")
+				FieldSelection()
+			})
+		})
+	})
+}
+
+var EndpointCriteriaDSL = func() {
+	Service("Service", func() {
+		Method("Method", func() {
+			Payload(func() {
+				Filter(func() {
+					Attribute("name", String)
+					Attribute("vintage", Int)
+				})
+				Sort("name", "vintage")
+			})
+			HTTP(func() {
+				GET("/")
+				Header("vintage")
+			})
+		})
+	})
+}
+
+var EndpointBatchNoPayload = func() {
+	Service("Service", func() {
+		Method("Method", func() {
+			HTTP(func() {
+				POST("/")
+				Batch("batch")
+			})
+		})
+	})
+}
+
+var EndpointCloudEventsInvalid = func() {
+	Service("Service", func() {
+		Method("Method", func() {
+			HTTP(func() {
+				POST("/")
+				CloudEvents()
+				SkipRequestBodyEncodeDecode()
+			})
+		})
+	})
+}
+
+var EndpointCallbackInvalid = func() {
+	var Event = Type("Event", func() {
+		Attribute("id", String)
+	})
+	Service("Service", func() {
+		Method("Method", func() {
+			HTTP(func() {
+				POST("/")
+				Callback("onEvent", Event)
+				Callback("onEvent", Event, func() {
+					URL("{$request.body#/url}")
+					Retry(-1, "1s")
+				})
+			})
+		})
+	})
+}
+
+var EndpointChunkedResultNotArray = func() {
+	Service("Service", func() {
+		Method("Method", func() {
+			Result(String)
+			HTTP(func() {
+				GET("/")
+				ChunkedResult()
+			})
+		})
+	})
+}
+
+var EndpointIdempotentSafeMethod = func() {
+	Service("Service", func() {
+		Method("Method", func() {
+			HTTP(func() {
+				GET("/")
+				Idempotent()
+			})
+		})
+	})
+}
+
+var EndpointCacheControlInvalid = func() {
+	Service("Service", func() {
+		Method("Method", func() {
+			HTTP(func() {
+				POST("/")
+				CacheControl("max-age=ten, forever")
+			})
+		})
+	})
+}
+
+var EndpointFeatureFlagInvalidStatus = func() {
+	Service("Service", func() {
+		Method("Method", func() {
+			Meta("feature:flag", "beta")
+			Meta("feature:flag:status", "200")
+			HTTP(func() {
+				GET("/")
+			})
+		})
+	})
+}
+
+var EndpointCollectUnknownFields = func() {
+	Service("Service", func() {
+		Method("Method", func() {
+			Payload(func() {
+				Attribute("name", String)
+				Attribute("extra", MapOf(String, Any))
+			})
+			Result(func() {
+				Attribute("name", String)
+			})
+			HTTP(func() {
+				POST("/")
+				CollectUnknownFields("extra")
+				Response(StatusOK, func() {
+					DisallowUnknownFields()
+				})
+			})
+		})
+	})
+}
+
+var EndpointCollectUnknownFieldsNotMap = func() {
+	Service("Service", func() {
+		Method("Method", func() {
+			Payload(func() {
+				Attribute("name", String)
+				Attribute("extra", String)
+			})
+			HTTP(func() {
+				POST("/")
+				CollectUnknownFields("extra")
+			})
+		})
+	})
+}
+
+var EndpointCollectUnknownFieldsMissing = func() {
+	Service("Service", func() {
+		Method("Method", func() {
+			Payload(func() {
+				Attribute("name", String)
+			})
+			HTTP(func() {
+				POST("/")
+				CollectUnknownFields("extra")
+			})
+		})
+	})
+}
+
+var EndpointUnknownFieldsConflict = func() {
+	Service("Service", func() {
+		Method("Method", func() {
+			Payload(func() {
+				Attribute("extra", MapOf(String, String))
+			})
+			HTTP(func() {
+				POST("/")
+				DisallowUnknownFields()
+				CollectUnknownFields("extra")
+			})
+		})
+	})
+}
+
+var EndpointFieldPresence = func() {
+	Service("Service", func() {
+		Method("Method", func() {
+			Payload(func() {
+				Attribute("name", String)
+				Attribute("fields", ArrayOf(String))
+			})
+			HTTP(func() {
+				PATCH("/")
+				FieldPresence("fields")
+			})
+		})
+	})
+}
+
+var EndpointFieldPresenceNotArray = func() {
+	Service("Service", func() {
+		Method("Method", func() {
+			Payload(func() {
+				Attribute("name", String)
+				Attribute("fields", String)
+			})
+			HTTP(func() {
+				PATCH("/")
+				FieldPresence("fields")
+			})
+		})
+	})
+}
+
+var EndpointFieldPresenceMissing = func() {
+	Service("Service", func() {
+		Method("Method", func() {
+			Payload(func() {
+				Attribute("name", String)
+			})
+			HTTP(func() {
+				PATCH("/")
+				FieldPresence("fields")
+			})
+		})
+	})
+}
+
+var EndpointSLOInvalid = func() {
+	Service("Service", func() {
+		Method("Method", func() {
+			Meta("slo:target", "100")
+			Meta("slo:latency", "fast")
+			HTTP(func() {
+				GET("/")
+			})
+		})
+		Method("Other", func() {
+			Meta("slo:latency", "1s")
+			HTTP(func() {
+				GET("/other")
+			})
+		})
+	})
+}
+
+var EndpointBudgetInvalid = func() {
+	Service("Service", func() {
+		Method("Method", func() {
+			Meta("budget:response-size", "1MB")
+			Meta("budget:latency", "-1s")
+			HTTP(func() {
+				GET("/")
+			})
+		})
+	})
+}
@@ -0,0 +1,38 @@
+package testdata
+
+import (
+	. "goa.design/goa/v3/dsl"
+)
+
+var ValidFieldMaskDSL = func() {
+	var Account = Type("Account", func() {
+		Attribute("name", String)
+	})
+	Service("Valid", func() {
+		Method("update", func() {
+			Payload(func() {
+				Attribute("account", Account)
+				FieldMask("update_mask", Account)
+			})
+		})
+	})
+}
+
+var InvalidFieldMaskDSL = func() {
+	var Name = Type("Name", String)
+	var Account = Type("Account", func() {
+		Attribute("name", String)
+	})
+	Service("Invalid", func() {
+		Method("not-object", func() {
+			Payload(func() {
+				FieldMask("update_mask", Name)
+			})
+		})
+		Method("result", func() {
+			Result(func() {
+				FieldMask("update_mask", Account)
+			})
+		})
+	})
+}
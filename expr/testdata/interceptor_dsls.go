@@ -0,0 +1,96 @@
+package testdata
+
+import . "goa.design/goa/v3/dsl"
+
+var ValidInterceptorsDSL = func() {
+	var Cache = Interceptor("Cache", func() {
+		ReadPayload(func() {
+			Attribute("id")
+		})
+		WriteResult(func() {
+			Attribute("cached_at")
+		})
+	})
+	var Log = Interceptor("Log")
+	Service("Valid", func() {
+		ServerInterceptor(Log)
+		ClientInterceptor(Log)
+		Method("get", func() {
+			ServerInterceptor(Cache)
+			Payload(func() {
+				Attribute("id", String)
+				Required("id")
+			})
+			Result(func() {
+				Attribute("cached_at", String)
+			})
+		})
+		Method("list", func() {
+			ServerInterceptor(Cache)
+			Payload(func() {
+				Attribute("id", String)
+				Attribute("limit", Int)
+				Required("id")
+			})
+			Result(func() {
+				Attribute("cached_at", String)
+				Attribute("count", Int)
+			})
+		})
+		Method("stream", func() {
+			ServerInterceptor(Log)
+			StreamingResult(String)
+		})
+	})
+}
+
+var InvalidInterceptorsDSL = func() {
+	var Cache = Interceptor("Cache", func() {
+		ReadPayload(func() {
+			Attribute("id")
+		})
+		ReadResult(func() {
+			Attribute("name")
+		})
+	})
+	var Bottle = ResultType("application/vnd.bottle", func() {
+		Attribute("name", String)
+	})
+	Service("Invalid", func() {
+		Method("missing", func() {
+			ServerInterceptor(Cache)
+			Payload(func() {
+				Attribute("other", String)
+			})
+			Result(String)
+		})
+		Method("mismatch", func() {
+			ClientInterceptor(Cache)
+			Payload(func() {
+				Attribute("id", Int)
+			})
+			Result(func() {
+				Attribute("name", String)
+			})
+		})
+		Method("viewed", func() {
+			ServerInterceptor(Cache)
+			Payload(func() {
+				Attribute("id", String)
+			})
+			Result(Bottle)
+		})
+		Method("stream", func() {
+			ServerInterceptor(Cache)
+			Payload(func() {
+				Attribute("id", String)
+			})
+			StreamingResult(Bottle)
+		})
+	})
+}
+
+var DuplicateInterceptorDSL = func() {
+	Interceptor("Cache")
+	Interceptor("Cache")
+}
@@ -0,0 +1,59 @@
+package testdata
+
+import . "goa.design/goa/v3/dsl"
+
+var ValidPaginationDSL = func() {
+	var Token = Type("Token", String)
+	Service("Valid", func() {
+		Method("list", func() {
+			Payload(func() {
+				Attribute("page", Int)
+			})
+			Result(func() {
+				Attribute("items", ArrayOf(String))
+				Attribute("next_page", Int)
+			})
+			Paginate("page", "next_page", "items")
+		})
+		Method("alias", func() {
+			Payload(func() {
+				Attribute("token", Token)
+			})
+			Result(func() {
+				Attribute("values", ArrayOf(Int))
+				Attribute("next", Token)
+			})
+			Paginate("token", "next", "values")
+		})
+	})
+}
+
+var InvalidPaginationDSL = func() {
+	Service("Invalid", func() {
+		Method("missing", func() {
+			Payload(func() {
+				Attribute("page", Int)
+			})
+			Result(func() {
+				Attribute("next_page", String)
+				Attribute("items", String)
+			})
+			Paginate("token", "next", "values")
+		})
+		Method("types", func() {
+			Payload(func() {
+				Attribute("page", Int)
+			})
+			Result(func() {
+				Attribute("next_page", String)
+				Attribute("items", String)
+			})
+			Paginate("page", "next_page", "items")
+		})
+		Method("not-object", func() {
+			Payload(String)
+			Result(String)
+			Paginate("page", "next_page", "items")
+		})
+	})
+}
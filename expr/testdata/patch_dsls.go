@@ -0,0 +1,38 @@
+package testdata
+
+import (
+	. "goa.design/goa/v3/dsl"
+)
+
+var ValidPatchDSL = func() {
+	var Account = Type("Account", func() {
+		Attribute("name", String)
+	})
+	Service("Valid", func() {
+		Method("update", func() {
+			Payload(func() {
+				Attribute("id", String)
+				Patch("patch", Account)
+			})
+		})
+	})
+}
+
+var InvalidPatchDSL = func() {
+	var Name = Type("Name", String)
+	var Account = Type("Account", func() {
+		Attribute("name", String)
+	})
+	Service("Invalid", func() {
+		Method("not-object", func() {
+			Payload(func() {
+				Patch("patch", Name)
+			})
+		})
+		Method("result", func() {
+			Result(func() {
+				Patch("patch", Account)
+			})
+		})
+	})
+}
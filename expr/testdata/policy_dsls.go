@@ -0,0 +1,32 @@
+package testdata
+
+import . "goa.design/goa/v3/dsl"
+
+var ValidPolicyDSL = func() {
+	Service("Valid", func() {
+		Policy(func() {
+			Roles("admin")
+		})
+		Method("inherited", func() {
+		})
+		Method("override", func() {
+			Policy(func() {
+				Permissions("read")
+				Rule("input.payload.id != \"\"")
+			})
+		})
+	})
+}
+
+var InvalidPolicyDSL = func() {
+	Service("Invalid", func() {
+		Policy(func() {
+		})
+		Method("duplicate", func() {
+			Policy(func() {
+				Roles("admin", "admin")
+				Permissions("read", "read")
+			})
+		})
+	})
+}
@@ -0,0 +1,61 @@
+package testdata
+
+import . "goa.design/goa/v3/dsl"
+
+var ValidSensitiveDSL = func() {
+	var User = Type("User", func() {
+		Attribute("name", String)
+		Attribute("email", String, func() {
+			Sensitive()
+			Example("user@example.com")
+		})
+	})
+	Service("Valid", func() {
+		Method("signup", func() {
+			Payload(func() {
+				Attribute("user", User)
+				Attribute("ssn", String, func() {
+					Encrypted()
+				})
+			})
+			Result(func() {
+				Attribute("token", String, func() {
+					Encrypted()
+				})
+			})
+		})
+	})
+}
+
+var InvalidSensitiveDSL = func() {
+	var Secret = Type("Secret", func() {
+		Attribute("value", String, func() {
+			Encrypted()
+		})
+	})
+	Service("Invalid", func() {
+		Method("kind", func() {
+			Payload(func() {
+				Attribute("pin", Int, func() {
+					Encrypted()
+				})
+			})
+		})
+		Method("nested", func() {
+			Payload(func() {
+				Attribute("secret", Secret)
+			})
+			Result(ArrayOf(Secret))
+		})
+		Method("stream", func() {
+			StreamingPayload(func() {
+				Attribute("ssn", String)
+			})
+			Payload(func() {
+				Attribute("ssn", String, func() {
+					Encrypted()
+				})
+			})
+		})
+	})
+}
@@ -65,3 +65,14 @@ var ServiceErrorDSL = func() {
 		Method("Method", func() {})
 	})
 }
+
+var ServiceEventsDSL = func() {
+	Service("InvalidEvents", func() {
+		Event("created", Empty)
+		Event("created", String)
+		Event("deleted", String, func() {
+			Topic("")
+		})
+		Method("Method", func() {})
+	})
+}
@@ -0,0 +1,19 @@
+package testdata
+
+import . "goa.design/goa/v3/dsl"
+
+var TraceDSL = func() {
+	Service("Service", func() {
+		Method("Method", func() {
+			Payload(func() {
+				Attribute("name", String, func() {
+					MinLength(1)
+				})
+				Attribute("count", Int, func() {
+					Default(10)
+				})
+				Required("name")
+			})
+		})
+	})
+}
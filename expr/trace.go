@@ -0,0 +1,127 @@
+package expr
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"goa.design/goa/v3/eval"
+)
+
+func init() {
+	eval.TraceState = traceState
+}
+
+// traceState describes the attributes of the given expression for the
+// evaluation trace, see eval.Trace.
+func traceState(e eval.Expression) []string {
+	var lines []string
+	switch actual := e.(type) {
+	case *AttributeExpr:
+		traceAttribute(&lines, "", actual)
+	case UserType:
+		traceAttribute(&lines, "", actual.Attribute())
+	case *MethodExpr:
+		if actual.Payload != nil {
+			traceAttribute(&lines, "payload", actual.Payload)
+		}
+		if actual.Result != nil {
+			traceAttribute(&lines, "result", actual.Result)
+		}
+		for _, er := range actual.Errors {
+			traceAttribute(&lines, "error "+er.Name, er.AttributeExpr)
+		}
+	case *ErrorExpr:
+		traceAttribute(&lines, "", actual.AttributeExpr)
+	case *ServiceExpr:
+		for _, m := range actual.Methods {
+			lines = append(lines, "method "+m.Name)
+		}
+	}
+	return lines
+}
+
+// traceAttribute appends the description of att and of its child attributes
+// to lines. Each line starts with the path to the attribute.
+func traceAttribute(lines *[]string, path string, att *AttributeExpr) {
+	if att == nil {
+		return
+	}
+	var elems []string
+	if att.Type != nil {
+		elems = append(elems, att.Type.Name())
+	}
+	if obj := AsObject(att.Type); obj != nil {
+		if att.Validation != nil && len(att.Validation.Required) > 0 {
+			req := append([]string(nil), att.Validation.Required...)
+			sort.Strings(req)
+			elems = append(elems, "required="+strings.Join(req, ","))
+		}
+	}
+	if att.DefaultValue != nil {
+		elems = append(elems, fmt.Sprintf("default=%v", att.DefaultValue))
+	}
+	if v := att.Validation; v != nil {
+		if len(v.Values) > 0 {
+			elems = append(elems, fmt.Sprintf("enum=%v", v.Values))
+		}
+		if v.Format != "" {
+			elems = append(elems, "format="+string(v.Format))
+		}
+		if v.Pattern != "" {
+			elems = append(elems, "pattern="+v.Pattern)
+		}
+		if v.Minimum != nil {
+			elems = append(elems, fmt.Sprintf("min=%v", *v.Minimum))
+		}
+		if v.Maximum != nil {
+			elems = append(elems, fmt.Sprintf("max=%v", *v.Maximum))
+		}
+		if v.MinLength != nil {
+			elems = append(elems, fmt.Sprintf("minlength=%d", *v.MinLength))
+		}
+		if v.MaxLength != nil {
+			elems = append(elems, fmt.Sprintf("maxlength=%d", *v.MaxLength))
+		}
+	}
+	if len(att.Meta) > 0 {
+		keys := make([]string, 0, len(att.Meta))
+		for k := range att.Meta {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		elems = append(elems, "meta="+strings.Join(keys, ","))
+	}
+	name := path
+	if name == "" {
+		name = "."
+	}
+	*lines = append(*lines, name+": "+strings.Join(elems, " "))
+
+	switch dt := att.Type.(type) {
+	case UserType:
+		// Do not describe the attributes of user types, their own trace
+		// covers them.
+		return
+	case *Object:
+		for _, nat := range *dt {
+			traceAttribute(lines, joinPath(path, nat.Name), nat.Attribute)
+		}
+	case *Array:
+		traceAttribute(lines, path+"[]", dt.ElemType)
+	case *Map:
+		traceAttribute(lines, path+"{}", dt.ElemType)
+	case *Union:
+		for _, nat := range dt.Values {
+			traceAttribute(lines, joinPath(path, nat.Name), nat.Attribute)
+		}
+	}
+}
+
+// joinPath appends name to the attribute path.
+func joinPath(path, name string) string {
+	if path == "" {
+		return name
+	}
+	return path + "." + name
+}
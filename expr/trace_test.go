@@ -0,0 +1,37 @@
+package expr_test
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"goa.design/goa/v3/eval"
+	"goa.design/goa/v3/expr"
+	"goa.design/goa/v3/expr/testdata"
+)
+
+func TestTrace(t *testing.T) {
+	var buf bytes.Buffer
+	eval.Trace = &buf
+	defer func() { eval.Trace = nil }()
+
+	expr.RunDSL(t, testdata.TraceDSL)
+
+	trace := buf.String()
+	expected := []string{
+		"execute\n",
+		`service "Service" (*expr.ServiceExpr) `,
+		"  + method Method\n",
+		`service "Service" method "Method" (*expr.MethodExpr) `,
+		"  + payload: object required=name\n",
+		"  + payload.name: string minlength=1\n",
+		"  + payload.count: int default=10\n",
+		"trace_dsls.go:",
+		"finalize\n",
+	}
+	for _, e := range expected {
+		if !strings.Contains(trace, e) {
+			t.Errorf("trace does not contain %q:\n%s", e, trace)
+		}
+	}
+}
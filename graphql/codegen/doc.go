@@ -0,0 +1,7 @@
+/*
+Package codegen contains the code generation logic that produces the GraphQL
+gateway: the GraphQL schema of the service methods (gen/graphql/schema.graphql)
+and the resolvers that map the GraphQL queries and mutations onto the
+service endpoints (gen/graphql/server.go).
+*/
+package codegen
@@ -0,0 +1,542 @@
+package codegen
+
+import (
+	"encoding/json"
+	"fmt"
+	"path"
+
+	"goa.design/goa/v3/codegen"
+	"goa.design/goa/v3/codegen/service"
+	"goa.design/goa/v3/expr"
+)
+
+type (
+	// gatewayData contains the data needed to render the GraphQL gateway.
+	gatewayData struct {
+		// Services lists the service endpoints used by the resolvers.
+		Services []*endpointsData
+		// Queries lists the fields of the Query type.
+		Queries []*fieldData
+		// Mutations lists the fields of the Mutation type.
+		Mutations []*fieldData
+		// Types lists the Go types of the field arguments and results.
+		Types []*typeData
+		// Inits lists the functions that build the method payloads from
+		// the field arguments and the field results from the method
+		// results.
+		Inits []*initData
+		// Helpers lists the transform functions used by the inits.
+		Helpers []*codegen.TransformFunctionData
+		// Imports lists the packages imported by the resolvers.
+		Imports []*codegen.ImportSpec
+		// Scalars lists the custom scalars used by the schema.
+		Scalars []string
+		// Inputs lists the GraphQL input object types.
+		Inputs []*sdlType
+		// Objects lists the GraphQL object types.
+		Objects []*sdlType
+	}
+
+	// endpointsData describes the endpoints of a service.
+	endpointsData struct {
+		// Name is the service name.
+		Name string
+		// VarName is the name of the endpoints argument.
+		VarName string
+		// TypeRef is the reference to the endpoints type.
+		TypeRef string
+	}
+
+	// fieldData describes a Query or Mutation field.
+	fieldData struct {
+		// Name is the GraphQL field name.
+		Name string
+		// Description is the field description.
+		Description string
+		// ServiceName is the name of the service.
+		ServiceName string
+		// MethodName is the name of the method.
+		MethodName string
+		// Endpoints describes the service endpoints.
+		Endpoints *endpointsData
+		// MethodVarName is the name of the method endpoint field.
+		MethodVarName string
+		// Resolver is the name of the resolver constructor.
+		Resolver string
+		// ArgsRef is the reference to the arguments type, empty if the
+		// method has no payload.
+		ArgsRef string
+		// ValidateArgs is the name of the arguments validation function,
+		// empty if there is no validation.
+		ValidateArgs string
+		// PayloadInit is the name of the function that builds the method
+		// payload from the arguments.
+		PayloadInit string
+		// HasResult is true if the method has a result.
+		HasResult bool
+		// ResultRef is the reference to the type of the values returned by
+		// the endpoint.
+		ResultRef string
+		// ViewedResultInit is the name of the function that builds the
+		// method result from the viewed result returned by the endpoint,
+		// empty if the method result is not a viewed result.
+		ViewedResultInit string
+		// ResultInit is the name of the function that builds the field
+		// result from the method result, empty if the method result is
+		// returned as is.
+		ResultInit string
+		// Args lists the field arguments in the schema.
+		Args []*sdlField
+		// Type is the schema type of the field.
+		Type string
+	}
+
+	// typeData describes a Go type.
+	typeData struct {
+		// VarName is the type name.
+		VarName string
+		// Description is the type description.
+		Description string
+		// Def is the type definition.
+		Def string
+		// ValidateDef is the validation code, empty if none.
+		ValidateDef string
+	}
+
+	// initData describes a constructor function.
+	initData struct {
+		// Name is the function name.
+		Name string
+		// Description is the function description.
+		Description string
+		// ArgRef is the reference to the function argument type.
+		ArgRef string
+		// ReturnRef is the reference to the returned type.
+		ReturnRef string
+		// Code is the function body.
+		Code string
+	}
+
+	// sdlType describes a GraphQL object or input object type.
+	sdlType struct {
+		// Name is the type name.
+		Name string
+		// Description is the type description.
+		Description string
+		// Fields lists the type fields.
+		Fields []*sdlField
+	}
+
+	// sdlField describes a GraphQL field or argument.
+	sdlField struct {
+		// Name is the field name.
+		Name string
+		// Description is the field description.
+		Description string
+		// Type is the field type.
+		Type string
+		// Default is the GraphQL literal of the default value, empty if
+		// none.
+		Default string
+	}
+
+	// gatewayBuilder accumulates the gateway data.
+	gatewayBuilder struct {
+		data  *gatewayData
+		scope *codegen.NameScope
+		types map[string]struct{}
+		// json is true if the schema uses the JSON scalar.
+		json bool
+	}
+)
+
+// buildGatewayData builds the data needed to render the GraphQL gateway of
+// the given services. It returns nil if no method can be exposed.
+func buildGatewayData(genpkg string, services []*expr.ServiceExpr) (*gatewayData, error) {
+	b := &gatewayBuilder{
+		data:  &gatewayData{},
+		scope: codegen.NewNameScope(),
+		types: make(map[string]struct{}),
+	}
+	counts := make(map[string]int)
+	for _, svc := range services {
+		for _, m := range svc.Methods {
+			if exposed(m) {
+				counts[codegen.Goify(m.Name, false)]++
+			}
+		}
+	}
+	for _, svc := range services {
+		sd := service.Services.Get(svc.Name)
+		var endpoints *endpointsData
+		for _, m := range svc.Methods {
+			if !exposed(m) {
+				continue
+			}
+			if endpoints == nil {
+				endpoints = &endpointsData{
+					Name:    svc.Name,
+					VarName: codegen.Goify(svc.Name, false) + "Endpoints",
+					TypeRef: "*" + sd.PkgName + ".Endpoints",
+				}
+				b.data.Services = append(b.data.Services, endpoints)
+				b.data.Imports = append(b.data.Imports,
+					codegen.NewImport(sd.PkgName, path.Join(genpkg, sd.PathName)),
+					codegen.NewImport(sd.ViewsPkg, path.Join(genpkg, sd.PathName, "views")))
+				b.data.Imports = append(b.data.Imports, sd.UserTypeImports...)
+			}
+			op, err := operation(m)
+			if err != nil {
+				return nil, err
+			}
+			name := codegen.Goify(m.Name, false)
+			if counts[name] > 1 {
+				name = codegen.Goify(svc.Name+"_"+m.Name, false)
+			}
+			f := b.buildField(name, m, sd, endpoints)
+			if op == "query" {
+				b.data.Queries = append(b.data.Queries, f)
+			} else {
+				b.data.Mutations = append(b.data.Mutations, f)
+			}
+		}
+	}
+	if len(b.data.Services) == 0 {
+		return nil, nil
+	}
+	if b.json {
+		b.data.Scalars = append(b.data.Scalars, "JSON")
+	}
+	return b.data, nil
+}
+
+// buildField builds the data of the field with the given name exposing method
+// m.
+func (b *gatewayBuilder) buildField(name string, m *expr.MethodExpr, sd *service.Data, endpoints *endpointsData) *fieldData {
+	md := sd.Method(m.Name)
+	prefix := codegen.Goify(sd.Name, true) + md.VarName
+	f := &fieldData{
+		Name:          name,
+		Description:   m.Description,
+		ServiceName:   sd.Name,
+		MethodName:    m.Name,
+		Endpoints:     endpoints,
+		MethodVarName: md.VarName,
+		Resolver:      "New" + prefix + "Resolver",
+		HasResult:     m.Result.Type != expr.Empty,
+		Type:          "Boolean",
+	}
+	if m.Payload.Type != expr.Empty {
+		args := expr.DupAtt(m.Payload)
+		expr.RemovePkgPath(args)
+		if ut, ok := args.Type.(expr.UserType); ok {
+			ut.Rename(prefix + "Args")
+		} else {
+			args = &expr.AttributeExpr{Type: &expr.UserTypeExpr{AttributeExpr: args, TypeName: prefix + "Args"}}
+		}
+		ut := args.Type.(expr.UserType)
+		walkUserTypes(ut.Attribute(), func(ut expr.UserType) { ut.Rename(ut.Name() + "Input") })
+		addJSONTags(args)
+		f.ArgsRef = b.scope.GoTypeName(args)
+		argsCtx := codegen.NewAttributeContext(true, false, false, "", b.scope)
+		b.addTypes(args, argsCtx, true, fmt.Sprintf("%s holds the arguments of the %q field.", f.ArgsRef, name))
+		if validate := codegen.ValidationCode(ut.Attribute(), ut, argsCtx, true, expr.IsAlias(ut), "args"); validate != "" {
+			f.ValidateArgs = "Validate" + f.ArgsRef
+		}
+		obj := expr.AsObject(ut.Attribute().Type)
+		for _, nat := range *obj {
+			arg := &sdlField{
+				Name:        nat.Name,
+				Description: nat.Attribute.Description,
+				Type:        b.sdlTypeRef(nat.Attribute, true),
+			}
+			if ut.Attribute().IsRequired(nat.Name) && nat.Attribute.DefaultValue == nil {
+				arg.Type += "!"
+			}
+			if nat.Attribute.DefaultValue != nil && expr.IsPrimitive(nat.Attribute.Type) {
+				if def, err := json.Marshal(nat.Attribute.DefaultValue); err == nil {
+					arg.Default = string(def)
+				}
+			}
+			f.Args = append(f.Args, arg)
+		}
+		pkg := sd.PkgName
+		if md.PayloadLoc != nil {
+			pkg = md.PayloadLoc.PackageName()
+		}
+		svcCtx := codegen.NewAttributeContext(false, false, true, pkg, sd.Scope)
+		code, helpers, err := codegen.GoTransform(args, m.Payload, "v", "res", argsCtx, svcCtx, "unmarshal", true)
+		if err != nil {
+			panic(err) // bug
+		}
+		f.PayloadInit = "new" + prefix + "Payload"
+		b.data.Inits = append(b.data.Inits, &initData{
+			Name:        f.PayloadInit,
+			Description: fmt.Sprintf("%s builds the payload of the %q method of the %q service from the %q field arguments.", f.PayloadInit, m.Name, sd.Name, f.Name),
+			ArgRef:      "*" + f.ArgsRef,
+			ReturnRef:   sd.Scope.GoFullTypeRef(m.Payload, pkg),
+			Code:        code + "\n\treturn res",
+		})
+		b.data.Helpers = codegen.AppendHelpers(b.data.Helpers, helpers)
+	}
+	if !f.HasResult {
+		return f
+	}
+	if !hasObject(m.Result.Type) {
+		f.Type = b.sdlTypeRef(m.Result, false)
+		return f
+	}
+	res := expr.DupAtt(m.Result)
+	expr.RemovePkgPath(res)
+	addJSONTags(res)
+	resCtx := codegen.NewAttributeContext(false, false, true, "", b.scope)
+	b.addTypes(res, resCtx, false, "")
+	f.Type = b.sdlTypeRef(res, false)
+	pkg := sd.PkgName
+	if md.ResultLoc != nil {
+		pkg = md.ResultLoc.PackageName()
+	}
+	svcCtx := codegen.NewAttributeContext(false, false, true, pkg, sd.Scope)
+	code, helpers, err := codegen.GoTransform(m.Result, res, "v", "res", svcCtx, resCtx, "marshal", true)
+	if err != nil {
+		panic(err) // bug
+	}
+	f.ResultRef = sd.Scope.GoFullTypeRef(m.Result, pkg)
+	if md.ViewedResult != nil {
+		f.ResultRef = md.ViewedResult.FullRef
+		f.ViewedResultInit = sd.PkgName + "." + md.ViewedResult.ResultInit.Name
+	}
+	f.ResultInit = "new" + prefix + "Result"
+	b.data.Inits = append(b.data.Inits, &initData{
+		Name:        f.ResultInit,
+		Description: fmt.Sprintf("%s builds the %q field result from the result of the %q method of the %q service.", f.ResultInit, f.Name, m.Name, sd.Name),
+		ArgRef:      sd.Scope.GoFullTypeRef(m.Result, pkg),
+		ReturnRef:   b.scope.GoTypeRef(res),
+		Code:        code + "\n\treturn res",
+	})
+	b.data.Helpers = codegen.AppendHelpers(b.data.Helpers, helpers)
+	return f
+}
+
+// addTypes records the Go and GraphQL types of the user types contained in
+// att. input is true if the types describe field arguments, desc overrides
+// the description of the att type if not empty.
+func (b *gatewayBuilder) addTypes(att *expr.AttributeExpr, ctx *codegen.AttributeContext, input bool, desc string) {
+	add := func(ut expr.UserType) {
+		uatt := &expr.AttributeExpr{Type: ut}
+		name := b.scope.GoTypeName(uatt)
+		if _, ok := b.types[name]; ok {
+			return
+		}
+		b.types[name] = struct{}{}
+		td := &typeData{
+			VarName:     name,
+			Description: ut.Attribute().Description,
+			Def:         b.scope.GoTypeDef(ut.Attribute(), input, !input),
+		}
+		if td.Description == "" {
+			td.Description = fmt.Sprintf("%s is a type used by the field results.", name)
+		}
+		if input {
+			if ut.Attribute().Description == "" {
+				td.Description = fmt.Sprintf("%s is a type used by the field arguments.", name)
+			}
+			td.ValidateDef = codegen.ValidationCode(ut.Attribute(), ut, ctx, true, expr.IsAlias(ut), "args")
+		}
+		if desc != "" && att.Type == ut {
+			td.Description = desc
+		}
+		b.data.Types = append(b.data.Types, td)
+		obj, ok := ut.Attribute().Type.(*expr.Object)
+		if !ok || (input && att.Type == ut) {
+			return
+		}
+		st := &sdlType{Name: name, Description: ut.Attribute().Description}
+		for _, nat := range *obj {
+			typ := b.sdlTypeRef(nat.Attribute, input)
+			if ut.Attribute().IsRequired(nat.Name) && (!input || nat.Attribute.DefaultValue == nil) {
+				typ += "!"
+			}
+			st.Fields = append(st.Fields, &sdlField{Name: nat.Name, Description: nat.Attribute.Description, Type: typ})
+		}
+		if input {
+			b.data.Inputs = append(b.data.Inputs, st)
+		} else {
+			b.data.Objects = append(b.data.Objects, st)
+		}
+	}
+	if ut, ok := att.Type.(expr.UserType); ok {
+		add(ut)
+	}
+	walkUserTypes(att, add)
+}
+
+// sdlTypeRef returns the GraphQL type reference of att.
+func (b *gatewayBuilder) sdlTypeRef(att *expr.AttributeExpr, input bool) string {
+	switch dt := att.Type.(type) {
+	case expr.UserType:
+		if _, ok := dt.Attribute().Type.(*expr.Object); ok {
+			return b.scope.GoTypeName(att)
+		}
+		return b.sdlTypeRef(dt.Attribute(), input)
+	case *expr.Array:
+		elem := b.sdlTypeRef(dt.ElemType, input)
+		if expr.IsPrimitive(dt.ElemType.Type) {
+			elem += "!"
+		}
+		return "[" + elem + "]"
+	case expr.Primitive:
+		switch dt.Kind() {
+		case expr.BooleanKind:
+			return "Boolean"
+		case expr.IntKind, expr.Int32Kind, expr.Int64Kind, expr.UIntKind, expr.UInt32Kind, expr.UInt64Kind:
+			return "Int"
+		case expr.Float32Kind, expr.Float64Kind:
+			return "Float"
+		case expr.StringKind, expr.BytesKind:
+			return "String"
+		}
+	}
+	b.json = true
+	return "JSON"
+}
+
+// exposed returns true if the method can be exposed by the GraphQL gateway.
+func exposed(m *expr.MethodExpr) bool {
+	if _, ok := m.Meta["graphql:skip"]; ok {
+		return false
+	}
+	if m.IsStreaming() || hasUnion(m.Payload, nil) || hasUnion(m.Result, nil) {
+		return false
+	}
+	if md := service.Services.Get(m.Service.Name).Method(m.Name); md.SkipRequestBodyEncodeDecode || md.SkipResponseBodyEncodeDecode {
+		return false
+	}
+	return m.Payload.Type == expr.Empty || expr.IsObject(m.Payload.Type)
+}
+
+// operation returns the GraphQL root type of the field exposing m: "query"
+// or "mutation".
+func operation(m *expr.MethodExpr) (string, error) {
+	if op, ok := m.Meta.Last("graphql:operation"); ok {
+		if op != "query" && op != "mutation" {
+			return "", fmt.Errorf("invalid graphql:operation meta %q of method %q of service %q, must be query or mutation", op, m.Name, m.Service.Name)
+		}
+		return op, nil
+	}
+	if expr.Root.API.HTTP != nil {
+		if svc := expr.Root.API.HTTP.Service(m.Service.Name); svc != nil {
+			if e := svc.Endpoint(m.Name); e != nil {
+				for _, r := range e.Routes {
+					if r.Method == "GET" {
+						return "query", nil
+					}
+				}
+			}
+		}
+	}
+	return "mutation", nil
+}
+
+// addJSONTags adds JSON tags to the attributes of the objects contained in
+// att recursively unless they already define one.
+func addJSONTags(att *expr.AttributeExpr) {
+	tag := func(o *expr.AttributeExpr) {
+		obj, ok := o.Type.(*expr.Object)
+		if !ok {
+			return
+		}
+		for _, nat := range *obj {
+			if _, ok := nat.Attribute.Meta["struct:tag:json"]; ok {
+				continue
+			}
+			t := nat.Name
+			if !o.IsRequired(nat.Name) && !o.HasDefaultValue(nat.Name) {
+				t += ",omitempty"
+			}
+			nat.Attribute.AddMeta("struct:tag:json", t)
+		}
+	}
+	_ = codegen.Walk(att, func(a *expr.AttributeExpr) error {
+		if ut, ok := a.Type.(expr.UserType); ok {
+			tag(ut.Attribute())
+			return nil
+		}
+		tag(a)
+		return nil
+	})
+}
+
+// walkUserTypes calls fn once on each user type contained in att.
+func walkUserTypes(att *expr.AttributeExpr, fn func(expr.UserType)) {
+	seen := make(map[expr.UserType]struct{})
+	var walk func(*expr.AttributeExpr)
+	walk = func(a *expr.AttributeExpr) {
+		switch dt := a.Type.(type) {
+		case expr.UserType:
+			if _, ok := seen[dt]; ok {
+				return
+			}
+			seen[dt] = struct{}{}
+			fn(dt)
+			walk(dt.Attribute())
+		case *expr.Object:
+			for _, nat := range *dt {
+				walk(nat.Attribute)
+			}
+		case *expr.Array:
+			walk(dt.ElemType)
+		case *expr.Map:
+			walk(dt.KeyType)
+			walk(dt.ElemType)
+		}
+	}
+	if ut, ok := att.Type.(expr.UserType); ok {
+		seen[ut] = struct{}{}
+		walk(ut.Attribute())
+		return
+	}
+	walk(att)
+}
+
+// hasObject returns true if the given data type is or contains an object.
+func hasObject(dt expr.DataType) bool {
+	switch actual := dt.(type) {
+	case expr.UserType:
+		return hasObject(actual.Attribute().Type)
+	case *expr.Array:
+		return hasObject(actual.ElemType.Type)
+	case *expr.Map:
+		return hasObject(actual.KeyType.Type) || hasObject(actual.ElemType.Type)
+	default:
+		return expr.IsObject(dt)
+	}
+}
+
+// hasUnion returns true if att is or contains a union.
+func hasUnion(att *expr.AttributeExpr, seen map[string]struct{}) bool {
+	if seen == nil {
+		seen = make(map[string]struct{})
+	}
+	switch dt := att.Type.(type) {
+	case *expr.Union:
+		return true
+	case expr.UserType:
+		if _, ok := seen[dt.ID()]; ok {
+			return false
+		}
+		seen[dt.ID()] = struct{}{}
+		return hasUnion(dt.Attribute(), seen)
+	case *expr.Object:
+		for _, nat := range *dt {
+			if hasUnion(nat.Attribute, seen) {
+				return true
+			}
+		}
+	case *expr.Array:
+		return hasUnion(dt.ElemType, seen)
+	case *expr.Map:
+		return hasUnion(dt.KeyType, seen) || hasUnion(dt.ElemType, seen)
+	}
+	return false
+}
@@ -0,0 +1,254 @@
+package codegen
+
+import (
+	"path/filepath"
+
+	"goa.design/goa/v3/codegen"
+	"goa.design/goa/v3/expr"
+)
+
+// ServerFiles returns the files that implement the GraphQL gateway of the
+// services defined in root: the GraphQL schema and the resolvers that map
+// the schema fields onto the service methods. It returns nil if no method
+// can be exposed, the streaming methods, the methods that use unions, the
+// methods whose payload is not an object and the methods that define the
+// "graphql:skip" meta are not exposed.
+func ServerFiles(genpkg string, root *expr.RootExpr) ([]*codegen.File, error) {
+	data, err := buildGatewayData(genpkg, root.Services)
+	if err != nil || data == nil {
+		return nil, err
+	}
+	return []*codegen.File{schemaFile(data), serverFile(data)}, nil
+}
+
+// schemaFile returns the file containing the GraphQL schema definition.
+func schemaFile(data *gatewayData) *codegen.File {
+	return &codegen.File{
+		Path: filepath.Join(codegen.Gendir, "graphql", "schema.graphql"),
+		SectionTemplates: []*codegen.SectionTemplate{{
+			Name:    "graphql-schema",
+			Source:  schemaT,
+			Data:    data,
+			FuncMap: map[string]any{"description": description},
+		}},
+	}
+}
+
+// serverFile returns the file implementing the GraphQL resolvers.
+func serverFile(data *gatewayData) *codegen.File {
+	imports := []*codegen.ImportSpec{
+		{Path: "context"},
+		{Path: "net/http"},
+		{Path: "unicode/utf8"},
+		codegen.GoaImport(""),
+		codegen.GoaNamedImport("graphql", "goagraphql"),
+	}
+	imports = append(imports, data.Imports...)
+	sections := []*codegen.SectionTemplate{
+		codegen.Header("GraphQL gateway", "graphql", imports),
+		{
+			Name:   "graphql-server-init",
+			Source: serverInitT,
+			Data:   data,
+		},
+	}
+	for _, f := range append(append([]*fieldData{}, data.Queries...), data.Mutations...) {
+		sections = append(sections, &codegen.SectionTemplate{
+			Name:   "graphql-resolver",
+			Source: resolverT,
+			Data:   f,
+		})
+	}
+	for _, t := range data.Types {
+		sections = append(sections, &codegen.SectionTemplate{
+			Name:   "graphql-type",
+			Source: typeT,
+			Data:   t,
+		})
+	}
+	for _, i := range data.Inits {
+		sections = append(sections, &codegen.SectionTemplate{
+			Name:   "graphql-type-init",
+			Source: typeInitT,
+			Data:   i,
+		})
+	}
+	for _, t := range data.Types {
+		if t.ValidateDef == "" {
+			continue
+		}
+		sections = append(sections, &codegen.SectionTemplate{
+			Name:   "graphql-validate",
+			Source: validateT,
+			Data:   t,
+		})
+	}
+	for _, h := range data.Helpers {
+		sections = append(sections, &codegen.SectionTemplate{
+			Name:   "graphql-transform-helper",
+			Source: transformHelperT,
+			Data:   h,
+		})
+	}
+	return &codegen.File{
+		Path:             filepath.Join(codegen.Gendir, "graphql", "server.go"),
+		SectionTemplates: sections,
+	}
+}
+
+// description returns the GraphQL description of a type or field indented
+// with the given prefix, an empty string if desc is empty.
+func description(desc, indent string) string {
+	if desc == "" {
+		return ""
+	}
+	var lines []byte
+	start := 0
+	for i := 0; i <= len(desc); i++ {
+		if i < len(desc) && desc[i] != '\n' {
+			continue
+		}
+		lines = append(lines, indent...)
+		lines = append(lines, desc[start:i]...)
+		lines = append(lines, '\n')
+		start = i + 1
+	}
+	return indent + `"""` + "\n" + string(lines) + indent + `"""` + "\n"
+}
+
+// input: gatewayData
+const schemaT = `# Code generated by goa, DO NOT EDIT.
+{{- range .Scalars }}
+
+"""
+{{ . }} is the JSON representation of a value.
+"""
+scalar {{ . }}
+{{- end }}
+{{- if .Queries }}
+
+type Query {
+{{- range .Queries }}
+{{ description .Description "  " }}  {{ .Name }}{{ if .Args }}({{ range $i, $a := .Args }}{{ if $i }}, {{ end }}{{ $a.Name }}: {{ $a.Type }}{{ if $a.Default }} = {{ $a.Default }}{{ end }}{{ end }}){{ end }}: {{ .Type }}
+{{- end }}
+}
+{{- end }}
+{{- if .Mutations }}
+
+type Mutation {
+{{- range .Mutations }}
+{{ description .Description "  " }}  {{ .Name }}{{ if .Args }}({{ range $i, $a := .Args }}{{ if $i }}, {{ end }}{{ $a.Name }}: {{ $a.Type }}{{ if $a.Default }} = {{ $a.Default }}{{ end }}{{ end }}){{ end }}: {{ .Type }}
+{{- end }}
+}
+{{- end }}
+{{- range .Objects }}
+
+{{ description .Description "" }}type {{ .Name }} {
+{{- range .Fields }}
+{{ description .Description "  " }}  {{ .Name }}: {{ .Type }}
+{{- end }}
+}
+{{- end }}
+{{- range .Inputs }}
+
+{{ description .Description "" }}input {{ .Name }} {
+{{- range .Fields }}
+{{ description .Description "  " }}  {{ .Name }}: {{ .Type }}
+{{- end }}
+}
+{{- end }}
+`
+
+// input: gatewayData
+const serverInitT = `// New returns the GraphQL schema of the gateway. The schema resolvers call
+// the given service endpoints.
+func New({{ range .Services }}{{ .VarName }} {{ .TypeRef }}, {{ end }}) *goagraphql.Schema {
+	return &goagraphql.Schema{
+{{- if .Queries }}
+		Query: map[string]goagraphql.Resolver{
+	{{- range .Queries }}
+			{{ printf "%q" .Name }}: {{ .Resolver }}({{ .Endpoints.VarName }}),
+	{{- end }}
+		},
+{{- end }}
+{{- if .Mutations }}
+		Mutation: map[string]goagraphql.Resolver{
+	{{- range .Mutations }}
+			{{ printf "%q" .Name }}: {{ .Resolver }}({{ .Endpoints.VarName }}),
+	{{- end }}
+		},
+{{- end }}
+	}
+}
+
+// NewHandler returns a HTTP handler that serves the GraphQL gateway, e.g.
+//
+//	mux.Handle("POST", "/graphql", graphql.NewHandler({{ range $i, $s := .Services }}{{ if $i }}, {{ end }}{{ $s.VarName }}{{ end }}).ServeHTTP)
+func NewHandler({{ range .Services }}{{ .VarName }} {{ .TypeRef }}, {{ end }}) http.Handler {
+	return goagraphql.NewHandler(New({{ range .Services }}{{ .VarName }}, {{ end }}))
+}
+`
+
+// input: fieldData
+const resolverT = `{{ printf "%s returns the resolver of the %q field which calls the %q method of the %q service." .Resolver .Name .MethodName .ServiceName | comment }}
+func {{ .Resolver }}(e {{ .Endpoints.TypeRef }}) goagraphql.Resolver {
+	return func(ctx context.Context, args map[string]any) (any, error) {
+{{- if .ArgsRef }}
+		var a {{ .ArgsRef }}
+		if err := goagraphql.DecodeArgs(args, &a); err != nil {
+			return nil, err
+		}
+	{{- if .ValidateArgs }}
+		if err := {{ .ValidateArgs }}(&a); err != nil {
+			return nil, err
+		}
+	{{- end }}
+{{- else }}
+		if len(args) > 0 {
+			return nil, goa.DecodePayloadError("field {{ .Name }} does not accept arguments")
+		}
+{{- end }}
+		{{ if .HasResult }}res{{ else }}_{{ end }}, err := e.{{ .MethodVarName }}(ctx, {{ if .ArgsRef }}{{ .PayloadInit }}(&a){{ else }}nil{{ end }})
+		if err != nil {
+			return nil, err
+		}
+{{- if .ViewedResultInit }}
+		return {{ .ResultInit }}({{ .ViewedResultInit }}(res.({{ .ResultRef }}))), nil
+{{- else if .ResultInit }}
+		return {{ .ResultInit }}(res.({{ .ResultRef }})), nil
+{{- else if .HasResult }}
+		return res, nil
+{{- else }}
+		return true, nil
+{{- end }}
+	}
+}
+`
+
+// input: typeData
+const typeT = `{{ comment .Description }}
+type {{ .VarName }} {{ .Def }}
+`
+
+// input: initData
+const typeInitT = `{{ comment .Description }}
+func {{ .Name }}(v {{ .ArgRef }}) {{ .ReturnRef }} {
+	{{ .Code }}
+}
+`
+
+// input: typeData
+const validateT = `{{ printf "Validate%s runs the validations defined on %s." .VarName .VarName | comment }}
+func Validate{{ .VarName }}(args *{{ .VarName }}) (err error) {
+	{{ .ValidateDef }}
+	return
+}
+`
+
+// input: TransformFunctionData
+const transformHelperT = `{{ printf "%s builds a value of type %s from a value of type %s." .Name .ResultTypeRef .ParamTypeRef | comment }}
+func {{ .Name }}(v {{ .ParamTypeRef }}) {{ .ResultTypeRef }} {
+	{{ .Code }}
+	return res
+}
+`
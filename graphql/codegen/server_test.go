@@ -0,0 +1,64 @@
+package codegen
+
+import (
+	"bytes"
+	"path/filepath"
+	"testing"
+
+	"goa.design/goa/v3/codegen"
+	"goa.design/goa/v3/codegen/service"
+	"goa.design/goa/v3/graphql/codegen/testdata"
+)
+
+func TestServerFiles(t *testing.T) {
+	service.Services = make(service.ServicesData)
+	root := codegen.RunDSL(t, testdata.GatewayDSL)
+	fs, err := ServerFiles("goa.design/goa/example", root)
+	if err != nil {
+		t.Fatalf("ServerFiles failed with %s", err)
+	}
+	if len(fs) != 2 {
+		t.Fatalf("got %d files, expected 2", len(fs))
+	}
+	if fs[0].Path != filepath.Join("gen", "graphql", "schema.graphql") {
+		t.Errorf("invalid schema path %q", fs[0].Path)
+	}
+	if fs[1].Path != filepath.Join("gen", "graphql", "server.go") {
+		t.Errorf("invalid server path %q", fs[1].Path)
+	}
+
+	var buf bytes.Buffer
+	if err := fs[0].SectionTemplates[0].Write(&buf); err != nil {
+		t.Fatal(err)
+	}
+	if schema := buf.String(); schema != testdata.GatewaySchema {
+		t.Errorf("invalid schema, got:\n%s\ngot vs. expected:\n%s", schema, codegen.Diff(t, schema, testdata.GatewaySchema))
+	}
+
+	code := codegen.SectionsCode(t, fs[1].SectionTemplates[1:])
+	if code != testdata.GatewayCode {
+		t.Errorf("invalid code, got:\n%s\ngot vs. expected:\n%s", code, codegen.Diff(t, code, testdata.GatewayCode))
+	}
+}
+
+func TestServerFilesNoField(t *testing.T) {
+	service.Services = make(service.ServicesData)
+	root := codegen.RunDSL(t, testdata.NoFieldDSL)
+	fs, err := ServerFiles("goa.design/goa/example", root)
+	if err != nil {
+		t.Fatalf("ServerFiles failed with %s", err)
+	}
+	if fs != nil {
+		t.Errorf("got %d files, expected none", len(fs))
+	}
+}
+
+func TestServerFilesInvalidOperation(t *testing.T) {
+	service.Services = make(service.ServicesData)
+	root := codegen.RunDSL(t, testdata.InvalidOperationDSL)
+	_, err := ServerFiles("goa.design/goa/example", root)
+	expected := `invalid graphql:operation meta "subscription" of method "method" of service "InvalidOperation", must be query or mutation`
+	if err == nil || err.Error() != expected {
+		t.Errorf("got error %v, expected %q", err, expected)
+	}
+}
@@ -0,0 +1,318 @@
+package testdata
+
+var GatewaySchema = `# Code generated by goa, DO NOT EDIT.
+
+"""
+JSON is the JSON representation of a value.
+"""
+scalar JSON
+
+type Query {
+  """
+  Show a bottle.
+  """
+  show(id: String!): Bottle
+  calcAdd(values: JSON): [Int!]
+}
+
+type Mutation {
+  cellarAdd(name: String!, winery: WineryInput!, vintage: Int = 2020): String
+  remove(id: String): Boolean
+}
+
+type Bottle {
+  id: String!
+  name: String!
+  winery: Winery
+  tags: [String!]
+}
+
+"""
+Winery producing wines
+"""
+type Winery {
+  """
+  Name of winery
+  """
+  name: String!
+  region: String
+}
+
+"""
+Winery producing wines
+"""
+input WineryInput {
+  """
+  Name of winery
+  """
+  name: String!
+  region: String
+}
+`
+
+var GatewayCode = `// New returns the GraphQL schema of the gateway. The schema resolvers call
+// the given service endpoints.
+func New(cellarEndpoints *cellar.Endpoints, calcEndpoints *calc.Endpoints) *goagraphql.Schema {
+	return &goagraphql.Schema{
+		Query: map[string]goagraphql.Resolver{
+			"show":    NewCellarShowResolver(cellarEndpoints),
+			"calcAdd": NewCalcAddResolver(calcEndpoints),
+		},
+		Mutation: map[string]goagraphql.Resolver{
+			"cellarAdd": NewCellarAddResolver(cellarEndpoints),
+			"remove":    NewCellarRemoveResolver(cellarEndpoints),
+		},
+	}
+}
+
+// NewHandler returns a HTTP handler that serves the GraphQL gateway, e.g.
+//
+//	mux.Handle("POST", "/graphql", graphql.NewHandler(cellarEndpoints, calcEndpoints).ServeHTTP)
+func NewHandler(cellarEndpoints *cellar.Endpoints, calcEndpoints *calc.Endpoints) http.Handler {
+	return goagraphql.NewHandler(New(cellarEndpoints, calcEndpoints))
+}
+
+// NewCellarShowResolver returns the resolver of the "show" field which calls
+// the "show" method of the "Cellar" service.
+func NewCellarShowResolver(e *cellar.Endpoints) goagraphql.Resolver {
+	return func(ctx context.Context, args map[string]any) (any, error) {
+		var a CellarShowArgs
+		if err := goagraphql.DecodeArgs(args, &a); err != nil {
+			return nil, err
+		}
+		if err := ValidateCellarShowArgs(&a); err != nil {
+			return nil, err
+		}
+		res, err := e.Show(ctx, newCellarShowPayload(&a))
+		if err != nil {
+			return nil, err
+		}
+		return newCellarShowResult(cellar.NewBottle(res.(*cellarviews.Bottle))), nil
+	}
+}
+
+// NewCalcAddResolver returns the resolver of the "calcAdd" field which calls
+// the "add" method of the "Calc" service.
+func NewCalcAddResolver(e *calc.Endpoints) goagraphql.Resolver {
+	return func(ctx context.Context, args map[string]any) (any, error) {
+		var a CalcAddArgs
+		if err := goagraphql.DecodeArgs(args, &a); err != nil {
+			return nil, err
+		}
+		res, err := e.Add(ctx, newCalcAddPayload(&a))
+		if err != nil {
+			return nil, err
+		}
+		return res, nil
+	}
+}
+
+// NewCellarAddResolver returns the resolver of the "cellarAdd" field which
+// calls the "add" method of the "Cellar" service.
+func NewCellarAddResolver(e *cellar.Endpoints) goagraphql.Resolver {
+	return func(ctx context.Context, args map[string]any) (any, error) {
+		var a CellarAddArgs
+		if err := goagraphql.DecodeArgs(args, &a); err != nil {
+			return nil, err
+		}
+		if err := ValidateCellarAddArgs(&a); err != nil {
+			return nil, err
+		}
+		res, err := e.Add(ctx, newCellarAddPayload(&a))
+		if err != nil {
+			return nil, err
+		}
+		return res, nil
+	}
+}
+
+// NewCellarRemoveResolver returns the resolver of the "remove" field which
+// calls the "remove" method of the "Cellar" service.
+func NewCellarRemoveResolver(e *cellar.Endpoints) goagraphql.Resolver {
+	return func(ctx context.Context, args map[string]any) (any, error) {
+		var a CellarRemoveArgs
+		if err := goagraphql.DecodeArgs(args, &a); err != nil {
+			return nil, err
+		}
+		_, err := e.Remove(ctx, newCellarRemovePayload(&a))
+		if err != nil {
+			return nil, err
+		}
+		return true, nil
+	}
+}
+
+// CellarShowArgs holds the arguments of the "show" field.
+type CellarShowArgs struct {
+	ID *string ` + "`" + `json:"id"` + "`" + `
+}
+
+// Bottle is a type used by the field results.
+type Bottle struct {
+	ID     string   ` + "`" + `json:"id"` + "`" + `
+	Name   string   ` + "`" + `json:"name"` + "`" + `
+	Winery *Winery  ` + "`" + `json:"winery,omitempty"` + "`" + `
+	Tags   []string ` + "`" + `json:"tags,omitempty"` + "`" + `
+}
+
+// Winery producing wines
+type Winery struct {
+	// Name of winery
+	Name   string  ` + "`" + `json:"name"` + "`" + `
+	Region *string ` + "`" + `json:"region,omitempty"` + "`" + `
+}
+
+// CellarAddArgs holds the arguments of the "cellarAdd" field.
+type CellarAddArgs struct {
+	Name    *string      ` + "`" + `json:"name"` + "`" + `
+	Winery  *WineryInput ` + "`" + `json:"winery"` + "`" + `
+	Vintage *int         ` + "`" + `json:"vintage"` + "`" + `
+}
+
+// Winery producing wines
+type WineryInput struct {
+	// Name of winery
+	Name   *string ` + "`" + `json:"name"` + "`" + `
+	Region *string ` + "`" + `json:"region,omitempty"` + "`" + `
+}
+
+// CellarRemoveArgs holds the arguments of the "remove" field.
+type CellarRemoveArgs struct {
+	ID *string ` + "`" + `json:"id,omitempty"` + "`" + `
+}
+
+// CalcAddArgs holds the arguments of the "calcAdd" field.
+type CalcAddArgs struct {
+	Values map[string]int ` + "`" + `json:"values,omitempty"` + "`" + `
+}
+
+// newCellarShowPayload builds the payload of the "show" method of the "Cellar"
+// service from the "show" field arguments.
+func newCellarShowPayload(v *CellarShowArgs) *cellar.ShowPayload {
+	res := &cellar.ShowPayload{
+		ID: *v.ID,
+	}
+	return res
+}
+
+// newCellarShowResult builds the "show" field result from the result of the
+// "show" method of the "Cellar" service.
+func newCellarShowResult(v *cellar.Bottle) *Bottle {
+	res := &Bottle{
+		ID:   v.ID,
+		Name: v.Name,
+	}
+	if v.Winery != nil {
+		res.Winery = marshalCellarWineryToWinery(v.Winery)
+	}
+	if v.Tags != nil {
+		res.Tags = make([]string, len(v.Tags))
+		for i, val := range v.Tags {
+			res.Tags[i] = val
+		}
+	}
+	return res
+}
+
+// newCellarAddPayload builds the payload of the "add" method of the "Cellar"
+// service from the "cellarAdd" field arguments.
+func newCellarAddPayload(v *CellarAddArgs) *cellar.NewBottle {
+	res := &cellar.NewBottle{
+		Name: *v.Name,
+	}
+	if v.Vintage != nil {
+		res.Vintage = *v.Vintage
+	}
+	res.Winery = unmarshalWineryInputToCellarWinery(v.Winery)
+	if v.Vintage == nil {
+		res.Vintage = 2020
+	}
+	return res
+}
+
+// newCellarRemovePayload builds the payload of the "remove" method of the
+// "Cellar" service from the "remove" field arguments.
+func newCellarRemovePayload(v *CellarRemoveArgs) *cellar.RemovePayload {
+	res := &cellar.RemovePayload{
+		ID: v.ID,
+	}
+	return res
+}
+
+// newCalcAddPayload builds the payload of the "add" method of the "Calc"
+// service from the "calcAdd" field arguments.
+func newCalcAddPayload(v *CalcAddArgs) *calc.AddPayload {
+	res := &calc.AddPayload{}
+	if v.Values != nil {
+		res.Values = make(map[string]int, len(v.Values))
+		for key, val := range v.Values {
+			tk := key
+			tv := val
+			res.Values[tk] = tv
+		}
+	}
+	return res
+}
+
+// ValidateCellarShowArgs runs the validations defined on CellarShowArgs.
+func ValidateCellarShowArgs(args *CellarShowArgs) (err error) {
+	if args.ID == nil {
+		err = goa.MergeErrors(err, goa.MissingFieldError("id", "args"))
+	}
+	return
+}
+
+// ValidateCellarAddArgs runs the validations defined on CellarAddArgs.
+func ValidateCellarAddArgs(args *CellarAddArgs) (err error) {
+	if args.Name == nil {
+		err = goa.MergeErrors(err, goa.MissingFieldError("name", "args"))
+	}
+	if args.Winery == nil {
+		err = goa.MergeErrors(err, goa.MissingFieldError("winery", "args"))
+	}
+	if args.Name != nil {
+		if utf8.RuneCountInString(*args.Name) < 2 {
+			err = goa.MergeErrors(err, goa.InvalidLengthError("args.name", *args.Name, utf8.RuneCountInString(*args.Name), 2, true))
+		}
+	}
+	if args.Winery != nil {
+		if err2 := ValidateWineryInput(args.Winery); err2 != nil {
+			err = goa.MergeErrors(err, err2)
+		}
+	}
+	return
+}
+
+// ValidateWineryInput runs the validations defined on WineryInput.
+func ValidateWineryInput(args *WineryInput) (err error) {
+	if args.Name == nil {
+		err = goa.MergeErrors(err, goa.MissingFieldError("name", "args"))
+	}
+	return
+}
+
+// marshalCellarWineryToWinery builds a value of type *Winery from a value of
+// type *cellar.Winery.
+func marshalCellarWineryToWinery(v *cellar.Winery) *Winery {
+	if v == nil {
+		return nil
+	}
+	res := &Winery{
+		Name:   v.Name,
+		Region: v.Region,
+	}
+
+	return res
+}
+
+// unmarshalWineryInputToCellarWinery builds a value of type *cellar.Winery
+// from a value of type *WineryInput.
+func unmarshalWineryInputToCellarWinery(v *WineryInput) *cellar.Winery {
+	res := &cellar.Winery{
+		Name:   *v.Name,
+		Region: v.Region,
+	}
+
+	return res
+}
+`
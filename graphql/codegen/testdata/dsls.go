@@ -0,0 +1,89 @@
+package testdata
+
+import (
+	. "goa.design/goa/v3/dsl"
+)
+
+var GatewayDSL = func() {
+	var Winery = Type("Winery", func() {
+		Description("Winery producing wines")
+		Attribute("name", String, "Name of winery")
+		Attribute("region", String)
+		Required("name")
+	})
+	var Bottle = ResultType("application/vnd.bottle", func() {
+		Attributes(func() {
+			Attribute("id", String)
+			Attribute("name", String)
+			Attribute("winery", Winery)
+			Attribute("tags", ArrayOf(String))
+		})
+		Required("id", "name")
+	})
+	var NewBottle = Type("NewBottle", func() {
+		Attribute("name", String, func() { MinLength(2) })
+		Attribute("winery", Winery)
+		Attribute("vintage", Int, func() { Default(2020) })
+		Required("name", "winery")
+	})
+	Service("Cellar", func() {
+		Method("show", func() {
+			Description("Show a bottle.")
+			Payload(func() {
+				Attribute("id", String)
+				Required("id")
+			})
+			Result(Bottle)
+			HTTP(func() {
+				GET("/bottles/{id}")
+			})
+		})
+		Method("add", func() {
+			Payload(NewBottle)
+			Result(String)
+			HTTP(func() {
+				POST("/bottles")
+			})
+		})
+		Method("remove", func() {
+			Meta("graphql:operation", "mutation")
+			Payload(func() {
+				Attribute("id", String)
+			})
+		})
+		Method("watch", func() {
+			StreamingResult(Bottle)
+		})
+		Method("internal", func() {
+			Meta("graphql:skip")
+		})
+	})
+	Service("Calc", func() {
+		Method("add", func() {
+			Meta("graphql:operation", "query")
+			Payload(func() {
+				Attribute("values", MapOf(String, Int))
+			})
+			Result(ArrayOf(Int))
+		})
+	})
+}
+
+var NoFieldDSL = func() {
+	Service("NoField", func() {
+		Method("watch", func() {
+			StreamingPayload(String)
+		})
+		Method("scalar", func() {
+			Payload(String)
+		})
+	})
+}
+
+var InvalidOperationDSL = func() {
+	Service("InvalidOperation", func() {
+		Method("method", func() {
+			Meta("graphql:operation", "subscription")
+		})
+	})
+}
@@ -0,0 +1,12 @@
+/*
+Package graphql contains the runtime used by the GraphQL gateway generated by
+"goa gen --graphql". The gateway exposes the service methods as the fields of
+the GraphQL Query and Mutation root types. The package implements a GraphQL
+executor that supports the query language features needed by clients of such
+a gateway: operations, variables, aliases, fragments and the @include and
+@skip directives. The executor resolves the root fields by calling the
+generated resolvers and completes the results by applying the selection sets
+to their JSON representation. The GraphQL schema is described by the SDL
+document generated alongside the resolvers.
+*/
+package graphql
@@ -0,0 +1,484 @@
+package graphql
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	goa "goa.design/goa/v3/pkg"
+)
+
+type (
+	// Resolver resolves a root field given the field arguments. The keys
+	// of args are the names of the arguments provided in the query. The
+	// values are JSON compatible values: nil, bool, int64, float64,
+	// string, []any or map[string]any. The result is encoded to JSON
+	// before the field selection set is applied.
+	Resolver func(ctx context.Context, args map[string]any) (any, error)
+
+	// Schema lists the resolvers of the fields of the Query and Mutation
+	// root types indexed by field name.
+	Schema struct {
+		// Query lists the resolvers of the Query type fields.
+		Query map[string]Resolver
+		// Mutation lists the resolvers of the Mutation type fields.
+		Mutation map[string]Resolver
+	}
+
+	// Request is a GraphQL request.
+	Request struct {
+		// Query is the GraphQL document.
+		Query string `json:"query"`
+		// OperationName is the name of the operation to execute, it may be
+		// empty if the document contains a single operation.
+		OperationName string `json:"operationName,omitempty"`
+		// Variables contains the operation variable values.
+		Variables map[string]any `json:"variables,omitempty"`
+	}
+
+	// Response is a GraphQL response.
+	Response struct {
+		// Data is the execution result, it is nil if the request could
+		// not be executed.
+		Data any `json:"data,omitempty"`
+		// Errors lists the errors that occurred.
+		Errors []*Error `json:"errors,omitempty"`
+	}
+
+	// Error is a GraphQL error.
+	Error struct {
+		// Message is the error message.
+		Message string `json:"message"`
+		// Locations lists the document locations the error relates to.
+		Locations []Location `json:"locations,omitempty"`
+		// Path is the response path of the field that failed.
+		Path []any `json:"path,omitempty"`
+		// Extensions contains additional error information. The "code"
+		// extension is set to the goa error name of resolver errors that
+		// have one.
+		Extensions map[string]any `json:"extensions,omitempty"`
+	}
+
+	// Location is a document location.
+	Location struct {
+		// Line is the line number starting at 1.
+		Line int `json:"line"`
+		// Column is the column number starting at 1.
+		Column int `json:"column"`
+	}
+
+	// executor executes a single operation.
+	executor struct {
+		doc       *document
+		variables map[string]any
+		errors    []*Error
+	}
+
+	// field is a field collected from a selection set.
+	field struct {
+		key  string
+		sels []*selection
+	}
+
+	// object is a JSON object whose keys are written in order.
+	object []*member
+
+	// member is an object member.
+	member struct {
+		key string
+		val any
+	}
+)
+
+// Error returns the error message.
+func (e *Error) Error() string { return e.Message }
+
+// Execute executes the GraphQL request using the schema resolvers. The
+// response Data field is nil if the request is invalid, e.g. if the
+// document cannot be parsed, if the operation selects unknown root fields or
+// if required variables are missing.
+func (s *Schema) Execute(ctx context.Context, req *Request) *Response {
+	doc, err := parse(req.Query)
+	if err != nil {
+		return errorResponse(locatedError(err))
+	}
+	op, err := selectOperation(doc, req.OperationName)
+	if err != nil {
+		return errorResponse(&Error{Message: err.Error()})
+	}
+	var typ string
+	var resolvers map[string]Resolver
+	switch op.kind {
+	case "query":
+		typ, resolvers = "Query", s.Query
+	case "mutation":
+		typ, resolvers = "Mutation", s.Mutation
+	default:
+		return errorResponse(&Error{Message: fmt.Sprintf("Operation type %q is not supported.", op.kind)})
+	}
+	if resolvers == nil {
+		return errorResponse(&Error{Message: fmt.Sprintf("Schema is not configured for %ss.", op.kind)})
+	}
+	vars, err := coerceVariables(op, req.Variables)
+	if err != nil {
+		return errorResponse(&Error{Message: err.Error()})
+	}
+	e := &executor{doc: doc, variables: vars}
+	fields, err := e.collect(op.selection, nil, map[string]bool{})
+	if err != nil {
+		return errorResponse(locatedError(err))
+	}
+	for _, f := range fields {
+		if name := f.sels[0].name; name != "__typename" && resolvers[name] == nil {
+			return errorResponse(&Error{
+				Message:   fmt.Sprintf("Cannot query field %q on type %q.", name, typ),
+				Locations: []Location{{Line: f.sels[0].line, Column: f.sels[0].column}},
+			})
+		}
+	}
+	data := make(object, 0, len(fields))
+	for _, f := range fields {
+		data = append(data, &member{key: f.key, val: e.resolve(ctx, typ, resolvers, f)})
+	}
+	return &Response{Data: data, Errors: e.errors}
+}
+
+// DecodeArgs decodes the resolver arguments into v using their JSON
+// representation. The generated resolvers use DecodeArgs to initialize the
+// structs that hold the field arguments.
+func DecodeArgs(args map[string]any, v any) error {
+	b, err := json.Marshal(args)
+	if err != nil {
+		return goa.DecodePayloadError(err.Error())
+	}
+	if err := json.Unmarshal(b, v); err != nil {
+		return goa.DecodePayloadError(err.Error())
+	}
+	return nil
+}
+
+// MarshalJSON writes the object members in order.
+func (o object) MarshalJSON() ([]byte, error) {
+	var buf bytes.Buffer
+	buf.WriteByte('{')
+	for i, m := range o {
+		if i > 0 {
+			buf.WriteByte(',')
+		}
+		k, err := json.Marshal(m.key)
+		if err != nil {
+			return nil, err
+		}
+		buf.Write(k)
+		buf.WriteByte(':')
+		v, err := json.Marshal(m.val)
+		if err != nil {
+			return nil, err
+		}
+		buf.Write(v)
+	}
+	buf.WriteByte('}')
+	return buf.Bytes(), nil
+}
+
+// resolve calls the resolver of the root field f and completes its value.
+func (e *executor) resolve(ctx context.Context, typ string, resolvers map[string]Resolver, f *field) any {
+	sel := f.sels[0]
+	if sel.name == "__typename" {
+		return typ
+	}
+	args, err := e.arguments(sel.args)
+	if err != nil {
+		e.fail(f, err)
+		return nil
+	}
+	res, err := resolvers[sel.name](ctx, args)
+	if err != nil {
+		e.fail(f, err)
+		return nil
+	}
+	raw, err := json.Marshal(res)
+	if err != nil {
+		e.fail(f, err)
+		return nil
+	}
+	dec := json.NewDecoder(bytes.NewReader(raw))
+	dec.UseNumber()
+	var val any
+	if err := dec.Decode(&val); err != nil {
+		e.fail(f, err)
+		return nil
+	}
+	v, err := e.complete(val, f.sels)
+	if err != nil {
+		e.fail(f, err)
+		return nil
+	}
+	return v
+}
+
+// complete applies the selection sets of the field selections to the JSON
+// value v.
+func (e *executor) complete(v any, sels []*selection) (any, error) {
+	var sub []*selection
+	for _, s := range sels {
+		sub = append(sub, s.selection...)
+	}
+	if len(sub) == 0 {
+		return v, nil
+	}
+	switch actual := v.(type) {
+	case []any:
+		res := make([]any, len(actual))
+		for i, elem := range actual {
+			c, err := e.complete(elem, sels)
+			if err != nil {
+				return nil, err
+			}
+			res[i] = c
+		}
+		return res, nil
+	case map[string]any:
+		fields, err := e.collect(sub, nil, map[string]bool{})
+		if err != nil {
+			return nil, err
+		}
+		res := make(object, 0, len(fields))
+		for _, f := range fields {
+			c, err := e.complete(actual[f.sels[0].name], f.sels)
+			if err != nil {
+				return nil, err
+			}
+			res = append(res, &member{key: f.key, val: c})
+		}
+		return res, nil
+	default:
+		return v, nil
+	}
+}
+
+// collect returns the fields selected by sels grouped by response key in
+// order, taking into account fragments and the @skip and @include
+// directives.
+func (e *executor) collect(sels []*selection, fields []*field, visited map[string]bool) ([]*field, error) {
+	for _, s := range sels {
+		include, err := e.included(s)
+		if err != nil {
+			return nil, err
+		}
+		if !include {
+			continue
+		}
+		switch {
+		case s.spread:
+			if visited[s.name] {
+				continue
+			}
+			frag, ok := e.doc.fragments[s.name]
+			if !ok {
+				return nil, &Error{
+					Message:   fmt.Sprintf("Unknown fragment %q.", s.name),
+					Locations: []Location{{Line: s.line, Column: s.column}},
+				}
+			}
+			visited[s.name] = true
+			if fields, err = e.collect(frag.selection, fields, visited); err != nil {
+				return nil, err
+			}
+		case s.inline:
+			if fields, err = e.collect(s.selection, fields, visited); err != nil {
+				return nil, err
+			}
+		default:
+			key := s.alias
+			if key == "" {
+				key = s.name
+			}
+			var found bool
+			for _, f := range fields {
+				if f.key == key {
+					f.sels = append(f.sels, s)
+					found = true
+					break
+				}
+			}
+			if !found {
+				fields = append(fields, &field{key: key, sels: []*selection{s}})
+			}
+		}
+	}
+	return fields, nil
+}
+
+// included evaluates the @skip and @include directives of s.
+func (e *executor) included(s *selection) (bool, error) {
+	for _, d := range s.directives {
+		if d.name != "skip" && d.name != "include" {
+			continue
+		}
+		args, err := e.arguments(d.args)
+		if err != nil {
+			return false, err
+		}
+		cond, ok := args["if"].(bool)
+		if !ok {
+			return false, fmt.Errorf("Directive \"@%s\" argument \"if\" of type \"Boolean!\" is required.", d.name)
+		}
+		if cond == (d.name == "skip") {
+			return false, nil
+		}
+	}
+	return true, nil
+}
+
+// arguments evaluates the given arguments.
+func (e *executor) arguments(args []*argument) (map[string]any, error) {
+	res := make(map[string]any, len(args))
+	for _, a := range args {
+		if v, ok := a.value.(variable); ok {
+			if _, ok := e.variables[string(v)]; !ok {
+				continue
+			}
+		}
+		v, err := e.eval(a.value)
+		if err != nil {
+			return nil, err
+		}
+		res[a.name] = v
+	}
+	return res, nil
+}
+
+// eval evaluates the value v.
+func (e *executor) eval(v value) (any, error) {
+	switch actual := v.(type) {
+	case variable:
+		val, ok := e.variables[string(actual)]
+		if !ok {
+			return nil, nil
+		}
+		return val, nil
+	case []value:
+		res := make([]any, len(actual))
+		for i, elem := range actual {
+			ev, err := e.eval(elem)
+			if err != nil {
+				return nil, err
+			}
+			res[i] = ev
+		}
+		return res, nil
+	case *objectValue:
+		res := make(map[string]any, len(actual.fields))
+		for _, f := range actual.fields {
+			ev, err := e.eval(f.value)
+			if err != nil {
+				return nil, err
+			}
+			res[f.name] = ev
+		}
+		return res, nil
+	default:
+		return v, nil
+	}
+}
+
+// fail records the error produced when resolving the root field f.
+func (e *executor) fail(f *field, err error) {
+	gerr := &Error{
+		Message:   err.Error(),
+		Locations: []Location{{Line: f.sels[0].line, Column: f.sels[0].column}},
+		Path:      []any{f.key},
+	}
+	var namer goa.GoaErrorNamer
+	if errors.As(err, &namer) {
+		gerr.Extensions = map[string]any{"code": namer.GoaErrorName()}
+	}
+	e.errors = append(e.errors, gerr)
+}
+
+// selectOperation returns the operation to execute.
+func selectOperation(doc *document, name string) (*operation, error) {
+	if name == "" {
+		if len(doc.operations) > 1 {
+			return nil, errors.New("Must provide operation name if query contains multiple operations.")
+		}
+		return doc.operations[0], nil
+	}
+	for _, op := range doc.operations {
+		if op.name == name {
+			return op, nil
+		}
+	}
+	return nil, fmt.Errorf("Unknown operation named %q.", name)
+}
+
+// coerceVariables returns the operation variable values given the request
+// values and the variable definition defaults.
+func coerceVariables(op *operation, values map[string]any) (map[string]any, error) {
+	vars := make(map[string]any, len(op.variables))
+	for _, def := range op.variables {
+		v, ok := values[def.name]
+		switch {
+		case ok && v == nil && def.nonNull:
+			return nil, fmt.Errorf("Variable \"$%s\" of non-null type must not be null.", def.name)
+		case ok:
+			vars[def.name] = normalize(v)
+		case def.hasDef:
+			vars[def.name] = def.defaults
+		case def.nonNull:
+			return nil, fmt.Errorf("Variable \"$%s\" of required type was not provided.", def.name)
+		}
+	}
+	return vars, nil
+}
+
+// normalize converts the JSON numbers contained in v to int64 or float64.
+func normalize(v any) any {
+	switch actual := v.(type) {
+	case json.Number:
+		if i, err := actual.Int64(); err == nil {
+			return i
+		}
+		f, _ := actual.Float64()
+		return f
+	case float64:
+		if i := int64(actual); float64(i) == actual {
+			return i
+		}
+		return actual
+	case []any:
+		for i, elem := range actual {
+			actual[i] = normalize(elem)
+		}
+		return actual
+	case map[string]any:
+		for k, elem := range actual {
+			actual[k] = normalize(elem)
+		}
+		return actual
+	default:
+		return v
+	}
+}
+
+// locatedError converts err to a GraphQL error, adding its location if err
+// is a syntax error.
+func locatedError(err error) *Error {
+	var gerr *Error
+	if errors.As(err, &gerr) {
+		return gerr
+	}
+	var serr *syntaxError
+	if errors.As(err, &serr) {
+		return &Error{Message: err.Error(), Locations: []Location{{Line: serr.line, Column: serr.column}}}
+	}
+	return &Error{Message: err.Error()}
+}
+
+// errorResponse returns a response that contains the given request error.
+func errorResponse(err *Error) *Response {
+	return &Response{Errors: []*Error{err}}
+}
@@ -0,0 +1,142 @@
+package graphql
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+
+	goa "goa.design/goa/v3/pkg"
+)
+
+type bottle struct {
+	ID      string  `json:"id"`
+	Name    string  `json:"name"`
+	Vintage int     `json:"vintage"`
+	Winery  *winery `json:"winery"`
+}
+
+type winery struct {
+	Name   string `json:"name"`
+	Region string `json:"region"`
+}
+
+func testSchema() *Schema {
+	bottles := []*bottle{
+		{ID: "1", Name: "Blue", Vintage: 2015, Winery: &winery{Name: "Longoria", Region: "Central Coast"}},
+		{ID: "2", Name: "Red", Vintage: 2018, Winery: &winery{Name: "Ridge", Region: "Cupertino"}},
+	}
+	return &Schema{
+		Query: map[string]Resolver{
+			"list": func(_ context.Context, args map[string]any) (any, error) {
+				if limit, ok := args["limit"].(int64); ok && int(limit) < len(bottles) {
+					return bottles[:limit], nil
+				}
+				return bottles, nil
+			},
+			"show": func(_ context.Context, args map[string]any) (any, error) {
+				for _, b := range bottles {
+					if b.ID == args["id"] {
+						return b, nil
+					}
+				}
+				return nil, goa.PermanentError("not_found", "bottle %v not found", args["id"])
+			},
+			"echo": func(_ context.Context, args map[string]any) (any, error) {
+				return args, nil
+			},
+		},
+		Mutation: map[string]Resolver{
+			"add": func(_ context.Context, args map[string]any) (any, error) {
+				if args["name"] == "" {
+					return nil, errors.New("name cannot be empty")
+				}
+				return &bottle{ID: "3", Name: args["name"].(string)}, nil
+			},
+		},
+	}
+}
+
+func TestExecute(t *testing.T) {
+	cases := []struct {
+		name      string
+		query     string
+		operation string
+		variables map[string]any
+		expected  string
+	}{
+		{"fields", `{ list { name vintage } }`, "", nil,
+			`{"data":{"list":[{"name":"Blue","vintage":2015},{"name":"Red","vintage":2018}]}}`},
+		{"nested", `query { show(id: "2") { id winery { name } } }`, "", nil,
+			`{"data":{"show":{"id":"2","winery":{"name":"Ridge"}}}}`},
+		{"aliases", `{ first: show(id: "1") { name } second: show(id: "2") { label: name } }`, "", nil,
+			`{"data":{"first":{"name":"Blue"},"second":{"label":"Red"}}}`},
+		{"variables", `query Show($id: String!, $limit: Int = 1) { show(id: $id) { name } list(limit: $limit) { id } }`, "", map[string]any{"id": "2"},
+			`{"data":{"show":{"name":"Red"},"list":[{"id":"1"}]}}`},
+		{"json-number-variables", `query($limit: Int) { list(limit: $limit) { id } }`, "", map[string]any{"limit": json.Number("1")},
+			`{"data":{"list":[{"id":"1"}]}}`},
+		{"fragments", `query { show(id: "1") { ...names winery { ... on Winery { region } } } } fragment names on Bottle { name winery { name } }`, "", nil,
+			`{"data":{"show":{"name":"Blue","winery":{"name":"Longoria","region":"Central Coast"}}}}`},
+		{"directives", `query($full: Boolean!) { show(id: "1") { name vintage @include(if: $full) id @skip(if: true) } }`, "", map[string]any{"full": false},
+			`{"data":{"show":{"name":"Blue"}}}`},
+		{"values", `{ echo(i: -3, f: 1.5e2, s: "a\"é", b: true, n: null, e: RED, l: [1, 2], o: {k: "v"}, block: """
+		    hello
+		      world
+		""") }`, "", nil,
+			`{"data":{"echo":{"b":true,"block":"hello\n  world","e":"RED","f":150,"i":-3,"l":[1,2],"n":null,"o":{"k":"v"},"s":"a\"é"}}}`},
+		{"typename", `{ __typename }`, "", nil, `{"data":{"__typename":"Query"}}`},
+		{"operation-name", `query A { list { id } } mutation B { add(name: "Rosé") { id name } }`, "B", nil,
+			`{"data":{"add":{"id":"3","name":"Rosé"}}}`},
+		{"resolver-error", `{ show(id: "9") { name } list(limit: 1) { id } }`, "", nil,
+			`{"data":{"show":null,"list":[{"id":"1"}]},"errors":[{"message":"bottle 9 not found","locations":[{"line":1,"column":3}],"path":["show"],"extensions":{"code":"not_found"}}]}`},
+		{"mutation-error", `mutation { add(name: "") { id } }`, "", nil,
+			`{"data":{"add":null},"errors":[{"message":"name cannot be empty","locations":[{"line":1,"column":12}],"path":["add"]}]}`},
+		{"unknown-field", `{ list { id } remove }`, "", nil,
+			`{"errors":[{"message":"Cannot query field \"remove\" on type \"Query\".","locations":[{"line":1,"column":15}]}]}`},
+		{"syntax-error", `{ list { id }`, "", nil,
+			`{"errors":[{"message":"Syntax Error: Unexpected \u003cEOF\u003e (line 1, column 14)","locations":[{"line":1,"column":14}]}]}`},
+		{"missing-variable", `query($id: String!) { show(id: $id) { id } }`, "", nil,
+			`{"errors":[{"message":"Variable \"$id\" of required type was not provided."}]}`},
+		{"unknown-fragment", `{ show(id: "1") { ...missing } }`, "", nil,
+			`{"data":{"show":null},"errors":[{"message":"Unknown fragment \"missing\".","locations":[{"line":1,"column":3}],"path":["show"]}]}`},
+		{"ambiguous-operation", `query A { list { id } } query B { list { id } }`, "", nil,
+			`{"errors":[{"message":"Must provide operation name if query contains multiple operations."}]}`},
+		{"subscription", `subscription { list { id } }`, "", nil,
+			`{"errors":[{"message":"Operation type \"subscription\" is not supported."}]}`},
+	}
+	s := testSchema()
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			resp := s.Execute(context.Background(), &Request{Query: c.query, OperationName: c.operation, Variables: c.variables})
+			b, err := json.Marshal(resp)
+			if err != nil {
+				t.Fatalf("failed to encode response: %s", err)
+			}
+			if string(b) != c.expected {
+				t.Errorf("got\n%s\nexpected\n%s", b, c.expected)
+			}
+		})
+	}
+}
+
+func TestDecodeArgs(t *testing.T) {
+	var args struct {
+		ID    *string  `json:"id"`
+		Limit *int     `json:"limit"`
+		Tags  []string `json:"tags"`
+	}
+	if err := DecodeArgs(map[string]any{"id": "1", "limit": int64(3), "tags": []any{"a"}}, &args); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if *args.ID != "1" || *args.Limit != 3 || len(args.Tags) != 1 {
+		t.Errorf("got %v %v %v", *args.ID, *args.Limit, args.Tags)
+	}
+	err := DecodeArgs(map[string]any{"limit": "three"}, &args)
+	if err == nil {
+		t.Fatal("expected an error")
+	}
+	var serr *goa.ServiceError
+	if !errors.As(err, &serr) || serr.Name != "decode_payload" {
+		t.Errorf("got error %v, expected a decode_payload error", err)
+	}
+}
@@ -0,0 +1,87 @@
+package graphql
+
+import (
+	"encoding/json"
+	"mime"
+	"net/http"
+)
+
+// maxRequestSize is the maximum size of the request bodies read by the
+// handler.
+const maxRequestSize = 1 << 20
+
+// NewHandler returns a HTTP handler that executes GraphQL requests using the
+// given schema. The handler accepts POST requests whose body is the JSON
+// representation of a Request and GET requests whose "query",
+// "operationName" and "variables" query string parameters describe the
+// request. GET requests cannot execute mutations. The response body is the
+// JSON representation of the Response. The response status code is 200 if
+// the request was executed, it is 400 if the request is invalid.
+func NewHandler(s *Schema) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req Request
+		switch r.Method {
+		case http.MethodGet:
+			q := r.URL.Query()
+			req.Query = q.Get("query")
+			req.OperationName = q.Get("operationName")
+			if vars := q.Get("variables"); vars != "" {
+				if err := json.Unmarshal([]byte(vars), &req.Variables); err != nil {
+					writeResponse(w, http.StatusBadRequest, errorResponse(&Error{Message: "Variables are invalid JSON."}))
+					return
+				}
+			}
+			if operationKind(&req) == "mutation" {
+				w.Header().Set("Allow", http.MethodPost)
+				writeResponse(w, http.StatusMethodNotAllowed, errorResponse(&Error{Message: "Mutations can only be executed via POST requests."}))
+				return
+			}
+		case http.MethodPost:
+			if ct, _, _ := mime.ParseMediaType(r.Header.Get("Content-Type")); ct != "" && ct != "application/json" {
+				writeResponse(w, http.StatusUnsupportedMediaType, errorResponse(&Error{Message: "Content type must be application/json."}))
+				return
+			}
+			dec := json.NewDecoder(http.MaxBytesReader(w, r.Body, maxRequestSize))
+			dec.UseNumber()
+			if err := dec.Decode(&req); err != nil {
+				writeResponse(w, http.StatusBadRequest, errorResponse(&Error{Message: "Body is not a valid GraphQL request: " + err.Error()}))
+				return
+			}
+		default:
+			w.Header().Set("Allow", "GET, POST")
+			writeResponse(w, http.StatusMethodNotAllowed, errorResponse(&Error{Message: "GraphQL only supports GET and POST requests."}))
+			return
+		}
+		if req.Query == "" {
+			writeResponse(w, http.StatusBadRequest, errorResponse(&Error{Message: "Must provide query string."}))
+			return
+		}
+		resp := s.Execute(r.Context(), &req)
+		status := http.StatusOK
+		if resp.Data == nil {
+			status = http.StatusBadRequest
+		}
+		writeResponse(w, status, resp)
+	})
+}
+
+// operationKind returns the kind of the operation executed by req, it returns
+// an empty string if the operation cannot be determined.
+func operationKind(req *Request) string {
+	doc, err := parse(req.Query)
+	if err != nil {
+		return ""
+	}
+	op, err := selectOperation(doc, req.OperationName)
+	if err != nil {
+		return ""
+	}
+	return op.kind
+}
+
+// writeResponse writes the JSON representation of resp.
+func writeResponse(w http.ResponseWriter, status int, resp *Response) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(resp)
+}
@@ -0,0 +1,59 @@
+package graphql
+
+import (
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+)
+
+func TestHandler(t *testing.T) {
+	cases := []struct {
+		name        string
+		method      string
+		target      string
+		contentType string
+		body        string
+		status      int
+		expected    string
+	}{
+		{"post", "POST", "/graphql", "application/json", `{"query":"query($id: String!) { show(id: $id) { name } }","variables":{"id":"1"}}`,
+			200, `{"data":{"show":{"name":"Blue"}}}`},
+		{"post-limit", "POST", "/graphql", "", `{"query":"query($n: Int) { list(limit: $n) { id } }","variables":{"n":1}}`,
+			200, `{"data":{"list":[{"id":"1"}]}}`},
+		{"get", "GET", "/graphql?" + url.Values{"query": {"query($n: Int) { list(limit: $n) { id } }"}, "variables": {`{"n":1}`}}.Encode(), "", "",
+			200, `{"data":{"list":[{"id":"1"}]}}`},
+		{"get-mutation", "GET", "/graphql?" + url.Values{"query": {`mutation { add(name: "x") { id } }`}}.Encode(), "", "",
+			405, `{"errors":[{"message":"Mutations can only be executed via POST requests."}]}`},
+		{"invalid-body", "POST", "/graphql", "application/json", `{`,
+			400, `{"errors":[{"message":"Body is not a valid GraphQL request: unexpected EOF"}]}`},
+		{"invalid-content-type", "POST", "/graphql", "text/plain", `{ list { id } }`,
+			415, `{"errors":[{"message":"Content type must be application/json."}]}`},
+		{"missing-query", "POST", "/graphql", "application/json", `{}`,
+			400, `{"errors":[{"message":"Must provide query string."}]}`},
+		{"invalid-query", "POST", "/graphql", "application/json", `{"query":"{ unknown }"}`,
+			400, `{"errors":[{"message":"Cannot query field \"unknown\" on type \"Query\".","locations":[{"line":1,"column":3}]}]}`},
+		{"method", "PUT", "/graphql", "", "",
+			405, `{"errors":[{"message":"GraphQL only supports GET and POST requests."}]}`},
+	}
+	h := NewHandler(testSchema())
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			r := httptest.NewRequest(c.method, c.target, strings.NewReader(c.body))
+			if c.contentType != "" {
+				r.Header.Set("Content-Type", c.contentType)
+			}
+			w := httptest.NewRecorder()
+			h.ServeHTTP(w, r)
+			if w.Code != c.status {
+				t.Errorf("got status %d, expected %d", w.Code, c.status)
+			}
+			if ct := w.Header().Get("Content-Type"); ct != "application/json" {
+				t.Errorf("got content type %q, expected %q", ct, "application/json")
+			}
+			if body := strings.TrimSpace(w.Body.String()); body != c.expected {
+				t.Errorf("got\n%s\nexpected\n%s", body, c.expected)
+			}
+		})
+	}
+}
@@ -0,0 +1,631 @@
+package graphql
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+type (
+	// document is a parsed GraphQL document.
+	document struct {
+		operations []*operation
+		fragments  map[string]*fragment
+	}
+
+	// operation is a GraphQL operation definition.
+	operation struct {
+		kind      string // "query", "mutation" or "subscription"
+		name      string
+		variables []*variableDef
+		selection []*selection
+	}
+
+	// variableDef is a variable definition.
+	variableDef struct {
+		name     string
+		nonNull  bool
+		defaults any
+		hasDef   bool
+	}
+
+	// fragment is a named fragment definition.
+	fragment struct {
+		name      string
+		selection []*selection
+	}
+
+	// selection is a field, a fragment spread or an inline fragment.
+	selection struct {
+		// alias is the field alias, empty if there is none.
+		alias string
+		// name is the field name or the fragment name of a spread.
+		name string
+		// args lists the field arguments.
+		args []*argument
+		// directives lists the selection directives.
+		directives []*directive
+		// selection is the field or inline fragment selection set.
+		selection []*selection
+		// spread is true if the selection is a fragment spread.
+		spread bool
+		// inline is true if the selection is an inline fragment.
+		inline bool
+		// line and column locate the selection in the document.
+		line, column int
+	}
+
+	// argument is a field or directive argument.
+	argument struct {
+		name  string
+		value value
+	}
+
+	// directive is a selection directive.
+	directive struct {
+		name string
+		args []*argument
+	}
+
+	// value is a GraphQL input value. It is one of variable, nil, bool,
+	// int64, float64, string (also used for enum values), []value or
+	// *objectValue.
+	value any
+
+	// variable is a reference to an operation variable.
+	variable string
+
+	// objectValue is an input object value.
+	objectValue struct {
+		fields []*argument
+	}
+
+	// parser parses GraphQL documents.
+	parser struct {
+		lexer *lexer
+		tok   token
+	}
+
+	// lexer tokenizes GraphQL documents.
+	lexer struct {
+		src          string
+		pos          int
+		line, column int
+	}
+
+	// token is a lexical token.
+	token struct {
+		kind         tokenKind
+		val          string
+		line, column int
+	}
+
+	// tokenKind is the type of a token.
+	tokenKind int
+
+	// syntaxError is the error returned when a document cannot be parsed.
+	syntaxError struct {
+		msg          string
+		line, column int
+	}
+)
+
+const (
+	tokEOF tokenKind = iota
+	tokPunct
+	tokName
+	tokInt
+	tokFloat
+	tokString
+)
+
+// parse parses the GraphQL document src.
+func parse(src string) (doc *document, err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			serr, ok := r.(*syntaxError)
+			if !ok {
+				panic(r)
+			}
+			err = serr
+		}
+	}()
+	p := &parser{lexer: &lexer{src: src, line: 1, column: 1}}
+	p.next()
+	doc = &document{fragments: make(map[string]*fragment)}
+	for p.tok.kind != tokEOF {
+		switch {
+		case p.peek("{"):
+			doc.operations = append(doc.operations, &operation{kind: "query", selection: p.selectionSet()})
+		case p.tok.kind == tokName && p.tok.val == "fragment":
+			p.next()
+			f := &fragment{name: p.name()}
+			if _, ok := doc.fragments[f.name]; ok {
+				p.fail("There can be only one fragment named %q.", f.name)
+			}
+			p.typeCondition()
+			p.directives()
+			f.selection = p.selectionSet()
+			doc.fragments[f.name] = f
+		case p.tok.kind == tokName && (p.tok.val == "query" || p.tok.val == "mutation" || p.tok.val == "subscription"):
+			op := &operation{kind: p.tok.val}
+			p.next()
+			if p.tok.kind == tokName {
+				op.name = p.name()
+			}
+			if p.skip("(") {
+				for !p.skip(")") {
+					op.variables = append(op.variables, p.variableDef())
+				}
+			}
+			p.directives()
+			op.selection = p.selectionSet()
+			doc.operations = append(doc.operations, op)
+		default:
+			p.unexpected()
+		}
+	}
+	if len(doc.operations) == 0 {
+		p.fail("document does not contain any operation")
+	}
+	return doc, nil
+}
+
+// Error returns the error message.
+func (e *syntaxError) Error() string {
+	return fmt.Sprintf("Syntax Error: %s (line %d, column %d)", e.msg, e.line, e.column)
+}
+
+// variableDef parses a variable definition.
+func (p *parser) variableDef() *variableDef {
+	p.expect("$")
+	v := &variableDef{name: p.name()}
+	p.expect(":")
+	v.nonNull = p.typeRef()
+	if p.skip("=") {
+		v.defaults = p.value(true)
+		v.hasDef = true
+	}
+	p.directives()
+	return v
+}
+
+// typeRef parses a type reference and returns whether it is non-null.
+func (p *parser) typeRef() bool {
+	if p.skip("[") {
+		p.typeRef()
+		p.expect("]")
+	} else {
+		p.name()
+	}
+	return p.skip("!")
+}
+
+// typeCondition parses a fragment type condition.
+func (p *parser) typeCondition() {
+	if p.tok.kind != tokName || p.tok.val != "on" {
+		p.unexpected()
+	}
+	p.next()
+	p.name()
+}
+
+// selectionSet parses a selection set.
+func (p *parser) selectionSet() []*selection {
+	p.expect("{")
+	var sels []*selection
+	for !p.skip("}") {
+		sels = append(sels, p.selection())
+	}
+	if len(sels) == 0 {
+		p.fail("selection set cannot be empty")
+	}
+	return sels
+}
+
+// selection parses a field, a fragment spread or an inline fragment.
+func (p *parser) selection() *selection {
+	s := &selection{line: p.tok.line, column: p.tok.column}
+	if p.skip("...") {
+		if p.tok.kind == tokName && p.tok.val != "on" {
+			s.spread = true
+			s.name = p.name()
+			s.directives = p.directives()
+			return s
+		}
+		s.inline = true
+		if p.tok.kind == tokName {
+			p.typeCondition()
+		}
+		s.directives = p.directives()
+		s.selection = p.selectionSet()
+		return s
+	}
+	s.name = p.name()
+	if p.skip(":") {
+		s.alias = s.name
+		s.name = p.name()
+	}
+	s.args = p.arguments(false)
+	s.directives = p.directives()
+	if p.peek("{") {
+		s.selection = p.selectionSet()
+	}
+	return s
+}
+
+// arguments parses optional arguments.
+func (p *parser) arguments(constant bool) []*argument {
+	if !p.skip("(") {
+		return nil
+	}
+	var args []*argument
+	for !p.skip(")") {
+		a := &argument{name: p.name()}
+		p.expect(":")
+		a.value = p.value(constant)
+		args = append(args, a)
+	}
+	return args
+}
+
+// directives parses optional directives.
+func (p *parser) directives() []*directive {
+	var ds []*directive
+	for p.skip("@") {
+		ds = append(ds, &directive{name: p.name(), args: p.arguments(false)})
+	}
+	return ds
+}
+
+// value parses a value, variables are not allowed if constant is true.
+func (p *parser) value(constant bool) value {
+	tok := p.tok
+	switch tok.kind {
+	case tokPunct:
+		switch tok.val {
+		case "$":
+			if constant {
+				p.unexpected()
+			}
+			p.next()
+			return variable(p.name())
+		case "[":
+			p.next()
+			list := []value{}
+			for !p.skip("]") {
+				list = append(list, p.value(constant))
+			}
+			return list
+		case "{":
+			p.next()
+			obj := &objectValue{}
+			for !p.skip("}") {
+				f := &argument{name: p.name()}
+				p.expect(":")
+				f.value = p.value(constant)
+				obj.fields = append(obj.fields, f)
+			}
+			return obj
+		}
+	case tokInt:
+		p.next()
+		i, err := strconv.ParseInt(tok.val, 10, 64)
+		if err != nil {
+			p.fail("invalid integer %s", tok.val)
+		}
+		return i
+	case tokFloat:
+		p.next()
+		f, err := strconv.ParseFloat(tok.val, 64)
+		if err != nil {
+			p.fail("invalid float %s", tok.val)
+		}
+		return f
+	case tokString:
+		p.next()
+		return tok.val
+	case tokName:
+		p.next()
+		switch tok.val {
+		case "true":
+			return true
+		case "false":
+			return false
+		case "null":
+			return nil
+		}
+		return tok.val
+	}
+	p.unexpected()
+	return nil
+}
+
+// name parses a name.
+func (p *parser) name() string {
+	if p.tok.kind != tokName {
+		p.unexpected()
+	}
+	n := p.tok.val
+	p.next()
+	return n
+}
+
+// expect consumes the given punctuator or fails.
+func (p *parser) expect(punct string) {
+	if !p.skip(punct) {
+		p.failAt(p.tok, "Expected %q, found %s", punct, p.tok)
+	}
+}
+
+// skip consumes the given punctuator if it is the current token.
+func (p *parser) skip(punct string) bool {
+	if !p.peek(punct) {
+		return false
+	}
+	p.next()
+	return true
+}
+
+// peek returns true if the current token is the given punctuator.
+func (p *parser) peek(punct string) bool {
+	return p.tok.kind == tokPunct && p.tok.val == punct
+}
+
+// next reads the next token.
+func (p *parser) next() {
+	tok, err := p.lexer.next()
+	if err != nil {
+		panic(err)
+	}
+	p.tok = tok
+}
+
+// unexpected fails with an unexpected token error.
+func (p *parser) unexpected() {
+	p.failAt(p.tok, "Unexpected %s", p.tok)
+}
+
+// fail fails at the current token.
+func (p *parser) fail(format string, args ...any) {
+	p.failAt(p.tok, format, args...)
+}
+
+// failAt fails at the given token.
+func (p *parser) failAt(tok token, format string, args ...any) {
+	panic(&syntaxError{msg: fmt.Sprintf(format, args...), line: tok.line, column: tok.column})
+}
+
+// String returns a description of the token used in error messages.
+func (t token) String() string {
+	switch t.kind {
+	case tokEOF:
+		return "<EOF>"
+	case tokString:
+		return "String " + strconv.Quote(t.val)
+	case tokName:
+		return "Name " + strconv.Quote(t.val)
+	default:
+		return strconv.Quote(t.val)
+	}
+}
+
+// next returns the next token.
+func (l *lexer) next() (token, error) {
+	l.skipIgnored()
+	tok := token{line: l.line, column: l.column}
+	if l.pos >= len(l.src) {
+		return tok, nil
+	}
+	c := l.src[l.pos]
+	switch {
+	case strings.IndexByte("!$&()=:@[]{}|", c) >= 0:
+		l.advance(1)
+		tok.kind, tok.val = tokPunct, string(c)
+	case c == '.':
+		if !strings.HasPrefix(l.src[l.pos:], "...") {
+			return tok, l.errorf("Unexpected character %q", c)
+		}
+		l.advance(3)
+		tok.kind, tok.val = tokPunct, "..."
+	case c == '_' || isLetter(c):
+		start := l.pos
+		for l.pos < len(l.src) && (l.src[l.pos] == '_' || isLetter(l.src[l.pos]) || isDigit(l.src[l.pos])) {
+			l.advance(1)
+		}
+		tok.kind, tok.val = tokName, l.src[start:l.pos]
+	case c == '-' || isDigit(c):
+		return l.number(tok)
+	case c == '"':
+		return l.string(tok)
+	default:
+		r, _ := utf8.DecodeRuneInString(l.src[l.pos:])
+		return tok, l.errorf("Unexpected character %q", r)
+	}
+	return tok, nil
+}
+
+// number lexes an integer or a float.
+func (l *lexer) number(tok token) (token, error) {
+	start := l.pos
+	tok.kind = tokInt
+	if l.src[l.pos] == '-' {
+		l.advance(1)
+	}
+	digits := func() error {
+		if l.pos >= len(l.src) || !isDigit(l.src[l.pos]) {
+			return l.errorf("Invalid number, expected digit")
+		}
+		for l.pos < len(l.src) && isDigit(l.src[l.pos]) {
+			l.advance(1)
+		}
+		return nil
+	}
+	if err := digits(); err != nil {
+		return tok, err
+	}
+	if l.pos < len(l.src) && l.src[l.pos] == '.' {
+		tok.kind = tokFloat
+		l.advance(1)
+		if err := digits(); err != nil {
+			return tok, err
+		}
+	}
+	if l.pos < len(l.src) && (l.src[l.pos] == 'e' || l.src[l.pos] == 'E') {
+		tok.kind = tokFloat
+		l.advance(1)
+		if l.pos < len(l.src) && (l.src[l.pos] == '+' || l.src[l.pos] == '-') {
+			l.advance(1)
+		}
+		if err := digits(); err != nil {
+			return tok, err
+		}
+	}
+	tok.val = l.src[start:l.pos]
+	return tok, nil
+}
+
+// string lexes a string or a block string.
+func (l *lexer) string(tok token) (token, error) {
+	tok.kind = tokString
+	if strings.HasPrefix(l.src[l.pos:], `"""`) {
+		l.advance(3)
+		end := strings.Index(l.src[l.pos:], `"""`)
+		if end < 0 {
+			return tok, l.errorf("Unterminated string")
+		}
+		tok.val = blockString(l.src[l.pos : l.pos+end])
+		l.advance(end + 3)
+		return tok, nil
+	}
+	l.advance(1)
+	var sb strings.Builder
+	for {
+		if l.pos >= len(l.src) || l.src[l.pos] == '\n' {
+			return tok, l.errorf("Unterminated string")
+		}
+		c := l.src[l.pos]
+		if c == '"' {
+			l.advance(1)
+			break
+		}
+		if c != '\\' {
+			r, size := utf8.DecodeRuneInString(l.src[l.pos:])
+			sb.WriteRune(r)
+			l.advance(size)
+			continue
+		}
+		if l.pos+1 >= len(l.src) {
+			return tok, l.errorf("Unterminated string")
+		}
+		esc := l.src[l.pos+1]
+		l.advance(2)
+		switch esc {
+		case '"', '\\', '/':
+			sb.WriteByte(esc)
+		case 'b':
+			sb.WriteByte('\b')
+		case 'f':
+			sb.WriteByte('\f')
+		case 'n':
+			sb.WriteByte('\n')
+		case 'r':
+			sb.WriteByte('\r')
+		case 't':
+			sb.WriteByte('\t')
+		case 'u':
+			if l.pos+4 > len(l.src) {
+				return tok, l.errorf("Invalid Unicode escape sequence")
+			}
+			r, err := strconv.ParseUint(l.src[l.pos:l.pos+4], 16, 32)
+			if err != nil {
+				return tok, l.errorf("Invalid Unicode escape sequence")
+			}
+			sb.WriteRune(rune(r))
+			l.advance(4)
+		default:
+			return tok, l.errorf("Invalid character escape sequence \\%c", esc)
+		}
+	}
+	tok.val = sb.String()
+	return tok, nil
+}
+
+// skipIgnored skips white spaces, line terminators, commas and comments.
+func (l *lexer) skipIgnored() {
+	for l.pos < len(l.src) {
+		switch c := l.src[l.pos]; c {
+		case ' ', '\t', ',', '\r':
+			l.advance(1)
+		case '\n':
+			l.pos++
+			l.line++
+			l.column = 1
+		case '#':
+			for l.pos < len(l.src) && l.src[l.pos] != '\n' {
+				l.advance(1)
+			}
+		default:
+			if strings.HasPrefix(l.src[l.pos:], "\uFEFF") {
+				l.advance(len("\uFEFF"))
+				continue
+			}
+			return
+		}
+	}
+}
+
+// advance moves the lexer n bytes forward on the current line.
+func (l *lexer) advance(n int) {
+	for i := 0; i < n; i++ {
+		if l.src[l.pos] == '\n' {
+			l.line++
+			l.column = 1
+		} else {
+			l.column++
+		}
+		l.pos++
+	}
+}
+
+// errorf returns a syntax error located at the current position.
+func (l *lexer) errorf(format string, args ...any) error {
+	return &syntaxError{msg: fmt.Sprintf(format, args...), line: l.line, column: l.column}
+}
+
+// blockString returns the value of a block string: common indentation and
+// leading and trailing blank lines are removed.
+func blockString(raw string) string {
+	lines := strings.Split(strings.ReplaceAll(raw, "\r\n", "\n"), "\n")
+	indent := -1
+	for _, line := range lines[1:] {
+		trimmed := strings.TrimLeft(line, " \t")
+		if trimmed == "" {
+			continue
+		}
+		if n := len(line) - len(trimmed); indent < 0 || n < indent {
+			indent = n
+		}
+	}
+	if indent > 0 {
+		for i, line := range lines[1:] {
+			if len(line) >= indent {
+				lines[i+1] = line[indent:]
+			} else {
+				lines[i+1] = ""
+			}
+		}
+	}
+	for len(lines) > 0 && strings.TrimSpace(lines[0]) == "" {
+		lines = lines[1:]
+	}
+	for len(lines) > 0 && strings.TrimSpace(lines[len(lines)-1]) == "" {
+		lines = lines[:len(lines)-1]
+	}
+	return strings.Join(lines, "\n")
+}
+
+func isLetter(c byte) bool { return c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' }
+
+func isDigit(c byte) bool { return c >= '0' && c <= '9' }
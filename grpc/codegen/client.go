@@ -47,6 +47,7 @@ func client(genpkg string, svc *expr.GRPCServiceExpr) *codegen.File {
 			{Path: path.Join(genpkg, "grpc", svcName, pbPkgName), Name: data.PkgName},
 		}
 		imports = append(imports, data.Service.UserTypeImports...)
+		imports = append(imports, data.Service.ProtoImports...)
 		sections = []*codegen.SectionTemplate{
 			codegen.Header(svc.Name()+" gRPC client", "client", imports),
 		}
@@ -136,6 +137,7 @@ func clientEncodeDecode(genpkg string, svc *expr.GRPCServiceExpr) *codegen.File
 			{Path: path.Join(genpkg, "grpc", svcName, pbPkgName), Name: data.PkgName},
 		}
 		imports = append(imports, data.Service.UserTypeImports...)
+		imports = append(imports, data.Service.ProtoImports...)
 		sections = []*codegen.SectionTemplate{codegen.Header(svc.Name()+" gRPC client encoders and decoders", "client", imports)}
 		fm := transTmplFuncs(svc)
 		fm["metadataEncodeDecodeData"] = metadataEncodeDecodeData
@@ -331,7 +333,7 @@ func Decode{{ .Method.VarName }}Response(ctx context.Context, v any, hdr, trlr m
 			}
 		{{- else }}
 			if vals := {{ .VarName }}.Get({{ printf "%q" .Metadata.Name }}); len(vals) > 0 {
-				{{ .Metadata.VarName }} = vals[0]
+				{{ .Metadata.VarName }} = {{ if .Metadata.Pointer }}&{{ end }}vals[0]
 			}
 		{{- end }}
 	{{- else if .Metadata.StringSlice }}
@@ -361,12 +363,12 @@ func Decode{{ .Method.VarName }}Response(ctx context.Context, v any, hdr, trlr m
 			if vals := {{ .VarName }}.Get({{ printf "%q" .Metadata.Name }}); len(vals) == 0 {
 				err = goa.MergeErrors(err, goa.MissingFieldError({{ printf "%q" .Metadata.Name }}, "metadata"))
 			} else {
-				{{ .Metadata.VarName }}Raw = vals[0]
+				{{ .Metadata.VarName }}Raw := vals[0]
 				{{ template "type_conversion" .Metadata }}
 			}
 		{{- else }}
 			if vals := {{ .VarName }}.Get({{ printf "%q" .Metadata.Name }}); len(vals) > 0 {
-				{{ .Metadata.VarName }}Raw = vals[0]
+				{{ .Metadata.VarName }}Raw := vals[0]
 				{{ template "type_conversion" .Metadata }}
 			}
 		{{- end }}
@@ -80,6 +80,7 @@ func endpointParser(genpkg string, root *expr.RootExpr, svr *expr.ServerExpr, da
 			Name: svcName + pbPkgName,
 		})
 		specs = append(specs, sd.Service.UserTypeImports...)
+		specs = append(specs, sd.Service.ProtoImports...)
 	}
 
 	sections := []*codegen.SectionTemplate{
@@ -121,6 +122,7 @@ func payloadBuilders(genpkg string, svc *expr.GRPCServiceExpr, data *cli.Command
 		{Path: path.Join(genpkg, "grpc", svcName, pbPkgName), Name: sd.PkgName},
 	}
 	specs = append(specs, sd.Service.UserTypeImports...)
+	specs = append(specs, sd.Service.ProtoImports...)
 	sections := []*codegen.SectionTemplate{
 		codegen.Header(title, "client", specs),
 	}
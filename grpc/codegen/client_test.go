@@ -92,6 +92,7 @@ func TestResponseDecoder(t *testing.T) {
 		{"response-decoder-result-array", testdata.MessageArrayDSL, testdata.ResultArrayResponseDecoderCode},
 		{"response-decoder-result-primitive", testdata.UnaryRPCNoPayloadDSL, testdata.ResultPrimitiveResponseDecoderCode},
 		{"response-decoder-result-with-metadata", testdata.MessageWithMetadataDSL, testdata.ResultWithMetadataResponseDecoderCode},
+		{"response-decoder-result-with-string-metadata", testdata.ResultWithStringMetadataDSL, testdata.ResultWithStringMetadataResponseDecoderCode},
 		{"response-decoder-result-with-validate", testdata.MessageWithValidateDSL, testdata.ResultWithValidateResponseDecoderCode},
 		{"response-decoder-result-collection", testdata.MessageResultTypeCollectionDSL, testdata.ResultCollectionResponseDecoderCode},
 		{"response-decoder-server-streaming", testdata.ServerStreamingUserTypeDSL, testdata.ServerStreamingResponseDecoderCode},
@@ -1,7 +1,6 @@
 package codegen
 
 import (
-	"os"
 	"path"
 	"path/filepath"
 	"strings"
@@ -26,16 +25,9 @@ func ExampleCLIFiles(genpkg string, root *expr.RootExpr) []*codegen.File {
 // server expression.
 func exampleCLI(genpkg string, root *expr.RootExpr, svr *expr.ServerExpr) *codegen.File {
 	var (
-		mainPath string
-
-		svrdata = example.Servers.Get(svr)
-	)
-	{
+		svrdata  = example.Servers.Get(svr)
 		mainPath = filepath.Join("cmd", svrdata.Dir+"-cli", "grpc.go")
-		if _, err := os.Stat(mainPath); !os.IsNotExist(err) {
-			return nil // file already exists, skip it.
-		}
-	}
+	)
 
 	var (
 		rootPath string
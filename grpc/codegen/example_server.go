@@ -1,7 +1,6 @@
 package codegen
 
 import (
-	"os"
 	"path"
 	"path/filepath"
 	"strings"
@@ -25,16 +24,9 @@ func ExampleServerFiles(genpkg string, root *expr.RootExpr) []*codegen.File {
 // exampleServer returns an example gRPC server implementation.
 func exampleServer(genpkg string, root *expr.RootExpr, svr *expr.ServerExpr) *codegen.File {
 	var (
-		mainPath string
-
-		svrdata = example.Servers.Get(svr)
-	)
-	{
+		svrdata  = example.Servers.Get(svr)
 		mainPath = filepath.Join("cmd", svrdata.Dir, "grpc.go")
-		if _, err := os.Stat(mainPath); !os.IsNotExist(err) {
-			return nil // file already exists, skip it.
-		}
-	}
+	)
 
 	var (
 		specs []*codegen.ImportSpec
@@ -46,6 +38,7 @@ func exampleServer(genpkg string, root *expr.RootExpr, svr *expr.ServerExpr) *co
 			{Path: "context"},
 			{Path: "log"},
 			{Path: "net"},
+			{Path: "net/http"},
 			{Path: "net/url"},
 			{Path: "os"},
 			{Path: "sync"},
@@ -98,6 +91,7 @@ func exampleServer(genpkg string, root *expr.RootExpr, svr *expr.ServerExpr) *co
 				svcdata = append(svcdata, data)
 			}
 		}
+		health, reflection := apiMetaEnabled(root, "grpc:health"), apiMetaEnabled(root, "grpc:reflection")
 		sections = []*codegen.SectionTemplate{
 			codegen.Header("", "main", specs),
 			{
@@ -119,7 +113,9 @@ func exampleServer(genpkg string, root *expr.RootExpr, svr *expr.ServerExpr) *co
 				Name:   "server-grpc-register",
 				Source: grpcRegisterSvrT,
 				Data: map[string]any{
-					"Services": svcdata,
+					"Services":   svcdata,
+					"Health":     health,
+					"Reflection": reflection,
 				},
 				FuncMap: map[string]any{
 					"goify":      codegen.Goify,
@@ -130,13 +126,38 @@ func exampleServer(genpkg string, root *expr.RootExpr, svr *expr.ServerExpr) *co
 				Source: grpcSvrEndT,
 				Data: map[string]any{
 					"Services": svcdata,
+					"Health":   health,
 				},
 			},
 		}
+		var websvcdata []*ServiceData
+		for _, svc := range svrdata.WebServices {
+			websvcdata = append(websvcdata, GRPCServices.Get(svc))
+		}
+		if len(websvcdata) > 0 {
+			sections = append(sections, &codegen.SectionTemplate{
+				Name:   "server-grpc-web",
+				Source: grpcWebHandlerT,
+				Data: map[string]any{
+					"Services": websvcdata,
+				},
+				FuncMap: map[string]any{
+					"goify":      codegen.Goify,
+					"needStream": needStream,
+				},
+			})
+		}
 	}
 	return &codegen.File{Path: mainPath, SectionTemplates: sections, SkipExist: true}
 }
 
+// apiMetaEnabled returns false if the API sets the meta with the given key to
+// "false", true otherwise.
+func apiMetaEnabled(root *expr.RootExpr, key string) bool {
+	v, ok := root.API.Meta.Last(key)
+	return !ok || v != "false"
+}
+
 // needStream returns true if at least one method in the defined services
 // uses stream for sending payload/result.
 func needStream(data []*ServiceData) bool {
@@ -188,7 +209,7 @@ func handleGRPCServer(ctx context.Context, u *url.URL{{ range $.Services }}{{ if
 	}
 `
 
-	// input: map[string]any{"Services":[]*ServiceData}
+	// input: map[string]any{"Services":[]*ServiceData, "Health": bool, "Reflection": bool}
 	grpcRegisterSvrT = `
 	// Initialize gRPC server with the middleware.
 	srv := grpc.NewServer(
@@ -215,12 +236,22 @@ func handleGRPCServer(ctx context.Context, u *url.URL{{ range $.Services }}{{ if
 		}
 	}
 
+	{{- if .Health }}
+
+	// Register the health checking service on the server. The status of
+	// the services is set to NOT_SERVING on shutdown.
+	// See https://github.com/grpc/grpc/blob/master/doc/health-checking.md.
+	healthSrv := goagrpc.RegisterHealth(srv)
+	{{- end }}
+	{{- if .Reflection }}
+
 	// Register the server reflection service on the server.
 	// See https://grpc.github.io/grpc/core/md_doc_server-reflection.html.
 	reflection.Register(srv)
+	{{- end }}
 `
 
-	// input: map[string]any{"Services":[]*ServiceData}
+	// input: map[string]any{"Services":[]*ServiceData, "Health": bool}
 	grpcSvrEndT = `
 	(*wg).Add(1)
 	go func() {
@@ -238,8 +269,39 @@ func handleGRPCServer(ctx context.Context, u *url.URL{{ range $.Services }}{{ if
 
 		<-ctx.Done()
 		logger.Printf("shutting down gRPC server at %q", u.Host)
+	{{- if .Health }}
+		healthSrv.Shutdown()
+	{{- end }}
 		srv.Stop()
   }()
 }
+`
+
+	// input: map[string]any{"Services":[]*ServiceData}
+	grpcWebHandlerT = `
+{{ comment "handleGRPCWeb returns a HTTP handler that serves the gRPC-Web and Connect requests made to the gRPC endpoints of the services and the other requests with h. It makes it possible for browser clients to call the gRPC endpoints on the HTTP server port." }}
+func handleGRPCWeb(h http.Handler{{ range $.Services }}{{ if .Service.Methods }}, {{ .Service.VarName }}Endpoints *{{ .Service.PkgName }}.Endpoints{{ end }}{{ end }}, logger *log.Logger) http.Handler {
+	adapter := middleware.NewLogger(logger)
+	srv := grpc.NewServer(
+		grpc.ChainUnaryInterceptor(
+			grpcmdlwr.UnaryRequestID(),
+			grpcmdlwr.UnaryServerLog(adapter),
+		),
+	{{- if needStream .Services }}
+		grpc.ChainStreamInterceptor(
+			grpcmdlwr.StreamRequestID(),
+			grpcmdlwr.StreamServerLog(adapter),
+		),
+	{{- end }}
+	)
+	{{- range .Services }}
+		{{- if .Endpoints }}
+	{{ .PkgName }}.Register{{ goify .Service.VarName true }}Server(srv, {{ .Service.PkgName }}svr.New({{ .Service.VarName }}Endpoints{{ if .HasUnaryEndpoint }}, nil{{ end }}{{ if .HasStreamingEndpoint }}, nil{{ end }}))
+		{{- else }}
+	{{ .PkgName }}.Register{{ goify .Service.VarName true }}Server(srv, {{ .Service.PkgName }}svr.New(nil{{ if .HasUnaryEndpoint }}, nil{{ end }}{{ if .HasStreamingEndpoint }}, nil{{ end }}))
+		{{- end }}
+	{{- end }}
+	return goagrpc.NewWebHandler(srv, h)
+}
 `
 )
@@ -21,6 +21,8 @@ func TestExampleServerFiles(t *testing.T) {
 		{"no-server", ctestdata.NoServerDSL, testdata.NoServerServerHandleCode},
 		{"server-hosting-service-subset", ctestdata.ServerHostingServiceSubsetDSL, testdata.ServerHostingServiceSubsetServerHandleCode},
 		{"server-hosting-multiple-services", ctestdata.ServerHostingMultipleServicesDSL, testdata.ServerHostingMultipleServicesServerHandleCode},
+		{"server-hosting-grpc-web", ctestdata.ServerHostingGRPCWebDSL, testdata.ServerHostingGRPCWebServerHandleCode},
+		{"no-health-no-reflection", testdata.NoHealthNoReflectionDSL, testdata.ServerNoHealthNoReflectionServerHandleCode},
 	}
 	for _, c := range cases {
 		t.Run(c.Name, func(t *testing.T) {
@@ -220,10 +220,10 @@ func protoBufGoTypeName(att *expr.AttributeExpr, s *codegen.NameScope) string {
 // the given package name for the given attribute generated after compiling
 // the proto file (in *.pb.go).
 func protoBufGoFullTypeName(att *expr.AttributeExpr, pkg string, s *codegen.NameScope) string {
-	if proto := att.Meta["struct:field:proto"]; len(proto) > 2 {
+	if proto := protoBufMeta(att); len(proto) > 2 {
 		typ := proto[2]
-		if len(att.Meta["struct:field:proto"]) > 3 {
-			elems := strings.Split(att.Meta["struct:field:proto"][3], "/")
+		if len(proto) > 3 {
+			elems := strings.Split(proto[3], "/")
 			typ = elems[len(elems)-1] + "." + typ
 		}
 		return typ
@@ -248,12 +248,38 @@ func protoBufGoFullTypeName(att *expr.AttributeExpr, pkg string, s *codegen.Name
 
 // protoType returns the protocol buffer type name for the given attribute.
 func protoType(att *expr.AttributeExpr, sd *ServiceData) string {
-	if protos := att.Meta["struct:field:proto"]; len(protos) > 0 {
+	if protos := protoBufMeta(att); len(protos) > 0 {
 		return protos[0]
 	}
 	return protoBufMessageDef(att, sd)
 }
 
+// protoBufMeta returns the values of the "struct:field:proto" meta of the
+// given attribute. If the attribute does not define the meta, protoBufMeta
+// returns the values of the meta of the attribute user type if the user type
+// is an external message, see externalMessage.
+func protoBufMeta(att *expr.AttributeExpr) []string {
+	if proto := att.Meta["struct:field:proto"]; len(proto) > 0 {
+		return proto
+	}
+	return externalMessage(att.Type)
+}
+
+// externalMessage returns the values of the "struct:field:proto" meta of the
+// given data type if it is an object user type mapped to a message defined in
+// a separate proto file, nil otherwise. The values are the message name, the
+// proto file import path, the Go type name and the Go import path.
+func externalMessage(dt expr.DataType) []string {
+	ut, ok := dt.(expr.UserType)
+	if !ok || !expr.IsObject(ut) {
+		return nil
+	}
+	if proto := ut.Attribute().Meta["struct:field:proto"]; len(proto) > 2 {
+		return proto
+	}
+	return nil
+}
+
 // protoBufMessageDef returns the protocol buffer code that defines a message
 // which matches the data structure definition (the part that comes after
 // `message foo`). The message is defined using the proto3 syntax.
@@ -48,6 +48,7 @@ func serverFile(genpkg string, svc *expr.GRPCServiceExpr) *codegen.File {
 			{Path: path.Join(genpkg, "grpc", svcName, pbPkgName), Name: data.PkgName},
 		}
 		imports = append(imports, data.Service.UserTypeImports...)
+		imports = append(imports, data.Service.ProtoImports...)
 		sections = []*codegen.SectionTemplate{
 			codegen.Header(svc.Name()+" gRPC server", "server", imports),
 			{Name: "server-struct", Source: serverStructT, Data: data},
@@ -141,6 +142,7 @@ func serverEncodeDecode(genpkg string, svc *expr.GRPCServiceExpr) *codegen.File
 			{Path: path.Join(genpkg, "grpc", svcName, pbPkgName), Name: data.PkgName},
 		}
 		imports = append(imports, data.Service.UserTypeImports...)
+		imports = append(imports, data.Service.ProtoImports...)
 		sections = []*codegen.SectionTemplate{codegen.Header(title, "server", imports)}
 
 		for _, e := range data.Endpoints {
@@ -420,21 +422,21 @@ func Encode{{ .Method.VarName }}Response(ctx context.Context, v any, hdr, trlr *
 
 {{- define "metadata_encoder" }}
 	{{- if .Metadata.StringSlice }}
-	{{ .VarName }}.Append({{ printf "%q" .Metadata.Name }}, res.{{ .Metadata.FieldName }}...)
+	{{ .VarName }}.Append({{ printf "%q" .Metadata.Name }}, result{{ if .Metadata.FieldName }}.{{ .Metadata.FieldName }}{{ end }}...)
 	{{- else if .Metadata.Slice }}
-		for _, value := range res.{{ .Metadata.FieldName }} {
+		for _, value := range result{{ if .Metadata.FieldName }}.{{ .Metadata.FieldName }}{{ end }} {
 			{{ template "string_conversion" (typeConversionData .Metadata.Type.ElemType.Type "valueStr" "value") }}
 			{{ .VarName }}.Append({{ printf "%q" .Metadata.Name }}, valueStr)
 		}
 	{{- else }}
 		{{- if .Metadata.Pointer }}
-			if res.{{ .Metadata.FieldName }} != nil {
+			if result{{ if .Metadata.FieldName }}.{{ .Metadata.FieldName }}{{ end }} != nil {
 		{{- end }}
 		{{ .VarName }}.Append({{ printf "%q" .Metadata.Name }},
 			{{- if eq .Metadata.Type.Name "bytes" }} string(
 			{{- else if not (eq .Metadata.TypeName "string") }} fmt.Sprintf("%v",
 			{{- end }}
-			{{- if .Metadata.Pointer }}*{{ end }}p.{{ .Metadata.FieldName }}
+			{{- if .Metadata.Pointer }}*{{ end }}result{{ if .Metadata.FieldName }}.{{ .Metadata.FieldName }}{{ end }}
 			{{- if or (eq .Metadata.Type.Name "bytes") (not (eq .Metadata.TypeName "string")) }})
 			{{- end }})
 		{{- if .Metadata.Pointer }}
@@ -126,6 +126,7 @@ func TestResponseEncoder(t *testing.T) {
 		{"response-encoder-result-array", testdata.MessageArrayDSL, testdata.ResultArrayResponseEncoderCode},
 		{"response-encoder-result-primitive", testdata.UnaryRPCNoPayloadDSL, testdata.ResultPrimitiveResponseEncoderCode},
 		{"response-encoder-result-with-metadata", testdata.MessageWithMetadataDSL, testdata.ResultWithMetadataResponseEncoderCode},
+		{"response-encoder-result-with-string-metadata", testdata.ResultWithStringMetadataDSL, testdata.ResultWithStringMetadataResponseEncoderCode},
 		{"response-encoder-result-with-validate", testdata.MessageWithValidateDSL, testdata.ResultWithValidateResponseEncoderCode},
 		{"response-encoder-result-collection", testdata.MessageResultTypeCollectionDSL, testdata.ResultCollectionResponseEncoderCode},
 	}
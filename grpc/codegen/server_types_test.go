@@ -25,6 +25,7 @@ func TestServerTypeFiles(t *testing.T) {
 		{"server-alias-validation", testdata.AliasValidationDSL, testdata.AliasValidationServerTypesFile},
 		{"server-struct-meta-type", testdata.StructMetaTypeDSL, testdata.StructMetaTypeServerTypeCode},
 		{"server-default-fields", testdata.DefaultFieldsDSL, testdata.DefaultFieldsServerTypeCode},
+		{"server-external-message", testdata.ExternalMessageDSL, testdata.ExternalMessageServerTypeCode},
 	}
 	for _, c := range cases {
 		t.Run(c.Name, func(t *testing.T) {
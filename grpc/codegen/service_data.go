@@ -461,6 +461,15 @@ func (ServicesData) analyze(gs *expr.GRPCServiceExpr) *ServiceData {
 			e.StreamingRequest = makeProtoBufMessage(e.StreamingRequest, protoBufify(e.Name()+"_streaming_request", true, true), sd)
 		}
 		e.Response.Message = makeProtoBufMessage(e.Response.Message, protoBufify(e.Name()+"_response", true, true), sd)
+		if proto := externalMessage(e.MethodExpr.Payload.Type); proto != nil && e.MethodExpr.StreamingPayload.Type == expr.Empty && e.Metadata.IsEmpty() {
+			setExternalMessage(e.Request, proto)
+		}
+		if proto := externalMessage(e.MethodExpr.StreamingPayload.Type); proto != nil {
+			setExternalMessage(e.StreamingRequest, proto)
+		}
+		if proto := externalMessage(e.MethodExpr.Result.Type); proto != nil && e.Response.Headers.IsEmpty() && e.Response.Trailers.IsEmpty() {
+			setExternalMessage(e.Response.Message, proto)
+		}
 		for _, er := range e.GRPCErrors {
 			if er.ErrorExpr.Type == expr.ErrorResult || !expr.IsObject(er.ErrorExpr.Type) {
 				continue
@@ -481,6 +490,16 @@ func (ServicesData) analyze(gs *expr.GRPCServiceExpr) *ServiceData {
 					sd.ProtoImports = append(sd.ProtoImports, imp)
 				}
 			}
+			if proto := externalMessage(att.Type); proto != nil {
+				// the message is defined in an imported proto file
+				sd.Messages = append(sd.Messages, msgs...)
+				return &service.UserTypeData{
+					Name:    att.Type.Name(),
+					VarName: proto[0],
+					Ref:     protoBufGoFullTypeRef(att, sd.PkgName, sd.Scope),
+					Type:    att.Type.(expr.UserType),
+				}
+			}
 			if len(msgs) > 0 {
 				sd.Messages = append(sd.Messages, msgs...)
 				return msgs[0]
@@ -651,22 +670,19 @@ func collectMessages(at *expr.AttributeExpr, sd *ServiceData, seen map[string]st
 	if at == nil {
 		return
 	}
-	if proto := at.Meta["struct:field:proto"]; len(proto) > 1 {
-		if len(proto) > 1 {
-			imp := proto[1]
+	if proto := protoBufMeta(at); len(proto) > 1 {
+		imports = append(imports, proto[1])
+		if len(proto) > 3 {
 			found := false
 			for _, i := range sd.Service.ProtoImports {
-				if i.Path == imp {
+				if i.Path == proto[3] {
 					found = true
 					break
 				}
 			}
 			if !found {
-				imports = append(imports, imp)
-				if len(proto) > 3 {
-					elems := strings.Split(proto[3], "/")
-					sd.Service.ProtoImports = append(sd.Service.ProtoImports, &codegen.ImportSpec{Path: proto[3], Name: elems[len(elems)-1]})
-				}
+				elems := strings.Split(proto[3], "/")
+				sd.Service.ProtoImports = append(sd.Service.ProtoImports, &codegen.ImportSpec{Path: proto[3], Name: elems[len(elems)-1]})
 			}
 		}
 	}
@@ -682,6 +698,13 @@ func collectMessages(at *expr.AttributeExpr, sd *ServiceData, seen map[string]st
 			return
 		}
 		att := userTypeAttribute(dt)
+		if externalMessage(dt) != nil {
+			// the message is defined in an imported proto file
+			seen[dt.Name()] = struct{}{}
+			d, i := collect(att)
+			data, imports = append(data, d...), append(imports, i...)
+			return
+		}
 		data = append(data, &service.UserTypeData{
 			Name:        dt.Name(),
 			VarName:     protoBufMessageName(at, sd.Scope),
@@ -726,7 +749,7 @@ func addValidation(att *expr.AttributeExpr, attName string, sd *ServiceData, req
 	if !ok {
 		return nil
 	}
-	name := protoBufGoTypeName(att, sd.Scope)
+	name := initTypeName(att, sd)
 	ref := protoBufGoFullTypeRef(att, sd.PkgName, sd.Scope)
 	kind := validateClient
 	if req {
@@ -776,6 +799,9 @@ func collectValidations(att *expr.AttributeExpr, attName string, ctx *codegen.At
 		vtx := protoBufTypeContext(sd.PkgName, sd.Scope, false)
 		def := codegen.AttributeValidationCode(att, dt, vtx, true, false, gattName, attName)
 		name := protoBufMessageName(att, sd.Scope)
+		if externalMessage(dt) != nil {
+			name = initTypeName(att, sd)
+		}
 		kind := validateClient
 		if req {
 			kind = validateServer
@@ -1025,13 +1051,13 @@ func buildInitData(source, target *expr.AttributeExpr, sourceVar, targetVar stri
 		}
 		isStruct = expr.IsObject(target.Type) || expr.IsUnion(target.Type)
 		if _, ok := source.Type.(expr.UserType); ok && usesrc {
-			name += protoBufGoTypeName(source, sd.Scope)
+			name += initTypeName(source, sd)
 		}
-		n := protoBufGoTypeName(target, sd.Scope)
+		n := initTypeName(target, sd)
 		if !isStruct {
 			// If target is array, map, or primitive the name will be suffixed with
 			// the definition (e.g int, []string, map[int]string) which is incorrect.
-			n = protoBufGoTypeName(source, sd.Scope)
+			n = initTypeName(source, sd)
 		}
 		name += n
 		code, helpers, err = protoBufTransform(source, target, sourceVar, targetVar, srcCtx, tgtCtx, proto, true)
@@ -1483,3 +1509,25 @@ func (s *{{ .VarName }}) SetView(view string) {
 	s.view = view
 }
 `
+
+// initTypeName returns the name of the protocol buffer type of att used to
+// build the names of the constructor functions. The names of the types of the
+// external messages are qualified with the Go package name, e.g.
+// TimestamppbTimestamp.
+func initTypeName(att *expr.AttributeExpr, sd *ServiceData) string {
+	n := protoBufGoTypeName(att, sd.Scope)
+	if strings.Contains(n, ".") {
+		return codegen.Goify(n, true)
+	}
+	return n
+}
+
+// setExternalMessage maps the protocol buffer message att to the message
+// defined in a separate proto file described by the "struct:field:proto" meta
+// values proto.
+func setExternalMessage(att *expr.AttributeExpr, proto []string) {
+	ut := att.Type.(expr.UserType)
+	meta := ut.Attribute().Meta.Dup()
+	meta["struct:field:proto"] = proto
+	ut.Attribute().Meta = meta
+}
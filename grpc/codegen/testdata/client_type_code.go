@@ -392,3 +392,70 @@ func NewProtoMethodRequest(payload *defaultfields.MethodPayload) *default_fields
 	return message
 }
 `
+
+const ExternalMessageClientTypeCode = `// NewProtoTimestamppbTimestamp builds the gRPC request type from the payload
+// of the "Method" endpoint of the "ExternalMessage" service.
+func NewProtoTimestamppbTimestamp(payload *externalmessage.Timestamp) *timestamppb.Timestamp {
+	message := &timestamppb.Timestamp{
+		Seconds: payload.Seconds,
+		Nanos:   payload.Nanos,
+	}
+	return message
+}
+
+// NewMethodResult builds the result type of the "Method" endpoint of the
+// "ExternalMessage" service from the gRPC response type.
+func NewMethodResult(message *external_messagepb.MethodResponse) *externalmessage.Event {
+	result := &externalmessage.Event{
+		Name: message.Name,
+	}
+	if message.CreatedAt != nil {
+		result.CreatedAt = protobufTimestamppbTimestampToExternalmessageTimestamp(message.CreatedAt)
+	}
+	return result
+}
+
+// ValidateTimestamppbTimestamp runs the validations defined on
+// TimestamppbTimestamp.
+func ValidateTimestamppbTimestamp(message *timestamppb.Timestamp) (err error) {
+	if message.Nanos < 0 {
+		err = goa.MergeErrors(err, goa.InvalidRangeError("message.nanos", message.Nanos, 0, true))
+	}
+	return
+}
+
+// ValidateMethodResponse runs the validations defined on MethodResponse.
+func ValidateMethodResponse(message *external_messagepb.MethodResponse) (err error) {
+	if message.CreatedAt == nil {
+		err = goa.MergeErrors(err, goa.MissingFieldError("created_at", "message"))
+	}
+	if message.CreatedAt != nil {
+		if err2 := ValidateTimestamppbTimestamp(message.CreatedAt); err2 != nil {
+			err = goa.MergeErrors(err, err2)
+		}
+	}
+	return
+}
+
+// svcExternalmessageTimestampToTimestamppbTimestamp builds a value of type
+// *timestamppb.Timestamp from a value of type *externalmessage.Timestamp.
+func svcExternalmessageTimestampToTimestamppbTimestamp(v *externalmessage.Timestamp) *timestamppb.Timestamp {
+	res := &timestamppb.Timestamp{
+		Seconds: v.Seconds,
+		Nanos:   v.Nanos,
+	}
+
+	return res
+}
+
+// protobufTimestamppbTimestampToExternalmessageTimestamp builds a value of
+// type *externalmessage.Timestamp from a value of type *timestamppb.Timestamp.
+func protobufTimestamppbTimestampToExternalmessageTimestamp(v *timestamppb.Timestamp) *externalmessage.Timestamp {
+	res := &externalmessage.Timestamp{
+		Seconds: v.Seconds,
+		Nanos:   v.Nanos,
+	}
+
+	return res
+}
+`
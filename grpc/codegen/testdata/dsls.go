@@ -723,6 +723,30 @@ var MessageWithMetadataDSL = func() {
 	})
 }
 
+var ResultWithStringMetadataDSL = func() {
+	Service("ServiceResultWithStringMetadata", func() {
+		Method("MethodResultWithStringMetadata", func() {
+			Result(func() {
+				Field(1, "etag", String)
+				Field(2, "tags", ArrayOf(String))
+				Field(3, "ids", ArrayOf(Int))
+				Field(4, "body", String)
+			})
+			GRPC(func() {
+				Response(CodeOK, func() {
+					Headers(func() {
+						Attribute("etag:ETag")
+						Attribute("tags")
+					})
+					Trailers(func() {
+						Attribute("ids")
+					})
+				})
+			})
+		})
+	})
+}
+
 var MessageWithValidateDSL = func() {
 	var UTLevel1 = Type("UTLevel1", func() {
 		Field(1, "Int32Field", Int32)
@@ -924,6 +948,32 @@ var StructMetaTypeDSL = func() {
 	})
 }
 
+var ExternalMessageDSL = func() {
+	var Timestamp = Type("Timestamp", func() {
+		Meta("struct:field:proto", "google.protobuf.Timestamp", "google/protobuf/timestamp.proto", "Timestamp", "google.golang.org/protobuf/types/known/timestamppb")
+		Field(1, "seconds", Int64, func() {
+			Meta("struct:field:proto", "int64")
+		})
+		Field(2, "nanos", Int32, func() {
+			Meta("struct:field:proto", "int32")
+			Minimum(0)
+		})
+		Required("seconds", "nanos")
+	})
+	var Event = Type("Event", func() {
+		Field(1, "name", String)
+		Field(2, "created_at", Timestamp)
+		Required("name", "created_at")
+	})
+	Service("ExternalMessage", func() {
+		Method("Method", func() {
+			Payload(Timestamp)
+			Result(Event)
+			GRPC(func() {})
+		})
+	})
+}
+
 var DefaultFieldsDSL = func() {
 	Service("DefaultFields", func() {
 		Method("Method", func() {
@@ -947,3 +997,77 @@ var DefaultFieldsDSL = func() {
 		})
 	})
 }
+
+var TranscoderDSL = func() {
+	var Bottle = ResultType("application/vnd.bottle", func() {
+		Attributes(func() {
+			Field(1, "id", Int)
+			Field(2, "name", String)
+		})
+		View("default", func() {
+			Attribute("id")
+			Attribute("name")
+		})
+		View("tiny", func() {
+			Attribute("id")
+		})
+	})
+	Service("Cellar", func() {
+		Meta("grpc:transcode")
+		Method("add", func() {
+			Payload(func() {
+				Field(1, "name", String)
+			})
+			Result(Int)
+			HTTP(func() {
+				POST("/")
+			})
+			GRPC(func() {})
+		})
+		Method("show", func() {
+			Payload(func() {
+				Field(1, "id", Int)
+			})
+			Result(Bottle)
+			HTTP(func() {
+				GET("/{id}")
+			})
+			GRPC(func() {})
+		})
+		Method("watch", func() {
+			StreamingResult(Bottle)
+			HTTP(func() {
+				GET("/watch")
+			})
+			GRPC(func() {})
+		})
+		Method("download", func() {
+			HTTP(func() {
+				GET("/download")
+			})
+		})
+	})
+	Service("Untranscoded", func() {
+		Method("method", func() {
+			HTTP(func() {
+				GET("/")
+			})
+			GRPC(func() {})
+		})
+	})
+}
+
+var NoHealthNoReflectionDSL = func() {
+	API("api", func() {
+		Meta("grpc:health", "false")
+		Meta("grpc:reflection", "false")
+	})
+	Service("Service", func() {
+		Method("Method", func() {
+			HTTP(func() {
+				GET("/")
+			})
+			GRPC(func() {})
+		})
+	})
+}
@@ -40,6 +40,11 @@ func handleGRPCServer(ctx context.Context, u *url.URL, serviceEndpoints *service
 		}
 	}
 
+	// Register the health checking service on the server. The status of
+	// the services is set to NOT_SERVING on shutdown.
+	// See https://github.com/grpc/grpc/blob/master/doc/health-checking.md.
+	healthSrv := goagrpc.RegisterHealth(srv)
+
 	// Register the server reflection service on the server.
 	// See https://grpc.github.io/grpc/core/md_doc_server-reflection.html.
 	reflection.Register(srv)
@@ -60,6 +65,7 @@ func handleGRPCServer(ctx context.Context, u *url.URL, serviceEndpoints *service
 
 		<-ctx.Done()
 		logger.Printf("shutting down gRPC server at %q", u.Host)
+		healthSrv.Shutdown()
 		srv.Stop()
 	}()
 }
@@ -105,6 +111,11 @@ func handleGRPCServer(ctx context.Context, u *url.URL, serviceEndpoints *service
 		}
 	}
 
+	// Register the health checking service on the server. The status of
+	// the services is set to NOT_SERVING on shutdown.
+	// See https://github.com/grpc/grpc/blob/master/doc/health-checking.md.
+	healthSrv := goagrpc.RegisterHealth(srv)
+
 	// Register the server reflection service on the server.
 	// See https://grpc.github.io/grpc/core/md_doc_server-reflection.html.
 	reflection.Register(srv)
@@ -125,6 +136,7 @@ func handleGRPCServer(ctx context.Context, u *url.URL, serviceEndpoints *service
 
 		<-ctx.Done()
 		logger.Printf("shutting down gRPC server at %q", u.Host)
+		healthSrv.Shutdown()
 		srv.Stop()
 	}()
 }
@@ -173,6 +185,11 @@ func handleGRPCServer(ctx context.Context, u *url.URL, serviceEndpoints *service
 		}
 	}
 
+	// Register the health checking service on the server. The status of
+	// the services is set to NOT_SERVING on shutdown.
+	// See https://github.com/grpc/grpc/blob/master/doc/health-checking.md.
+	healthSrv := goagrpc.RegisterHealth(srv)
+
 	// Register the server reflection service on the server.
 	// See https://grpc.github.io/grpc/core/md_doc_server-reflection.html.
 	reflection.Register(srv)
@@ -193,6 +210,7 @@ func handleGRPCServer(ctx context.Context, u *url.URL, serviceEndpoints *service
 
 		<-ctx.Done()
 		logger.Printf("shutting down gRPC server at %q", u.Host)
+		healthSrv.Shutdown()
 		srv.Stop()
 	}()
 }
@@ -250,3 +268,154 @@ const ExampleCLICode = `func doGRPC(scheme, host string, timeout int, debug bool
 	return cli.ParseEndpoint(conn)
 }
 `
+
+const ServerHostingGRPCWebServerHandleCode = `// handleGRPCServer starts configures and starts a gRPC server on the given
+// URL. It shuts down the server if any error is received in the error channel.
+func handleGRPCServer(ctx context.Context, u *url.URL, serviceEndpoints *service.Endpoints, anotherServiceEndpoints *anotherservice.Endpoints, wg *sync.WaitGroup, errc chan error, logger *log.Logger, debug bool) {
+
+	// Setup goa log adapter.
+	var (
+		adapter middleware.Logger
+	)
+	{
+		adapter = middleware.NewLogger(logger)
+	}
+
+	// Wrap the endpoints with the transport specific layers. The generated
+	// server packages contains code generated from the design which maps
+	// the service input and output data structures to gRPC requests and
+	// responses.
+	var (
+		serviceServer        *servicesvr.Server
+		anotherServiceServer *anotherservicesvr.Server
+	)
+	{
+		serviceServer = servicesvr.New(serviceEndpoints, nil)
+		anotherServiceServer = anotherservicesvr.New(anotherServiceEndpoints, nil)
+	}
+
+	// Initialize gRPC server with the middleware.
+	srv := grpc.NewServer(
+		grpc.ChainUnaryInterceptor(
+			grpcmdlwr.UnaryRequestID(),
+			grpcmdlwr.UnaryServerLog(adapter),
+		),
+	)
+
+	// Register the servers.
+	servicepb.RegisterServiceServer(srv, serviceServer)
+	another_servicepb.RegisterAnotherServiceServer(srv, anotherServiceServer)
+
+	for svc, info := range srv.GetServiceInfo() {
+		for _, m := range info.Methods {
+			logger.Printf("serving gRPC method %s", svc+"/"+m.Name)
+		}
+	}
+
+	// Register the health checking service on the server. The status of
+	// the services is set to NOT_SERVING on shutdown.
+	// See https://github.com/grpc/grpc/blob/master/doc/health-checking.md.
+	healthSrv := goagrpc.RegisterHealth(srv)
+
+	// Register the server reflection service on the server.
+	// See https://grpc.github.io/grpc/core/md_doc_server-reflection.html.
+	reflection.Register(srv)
+
+	(*wg).Add(1)
+	go func() {
+		defer (*wg).Done()
+
+		// Start gRPC server in a separate goroutine.
+		go func() {
+			lis, err := net.Listen("tcp", u.Host)
+			if err != nil {
+				errc <- err
+			}
+			logger.Printf("gRPC server listening on %q", u.Host)
+			errc <- srv.Serve(lis)
+		}()
+
+		<-ctx.Done()
+		logger.Printf("shutting down gRPC server at %q", u.Host)
+		healthSrv.Shutdown()
+		srv.Stop()
+	}()
+}
+
+// handleGRPCWeb returns a HTTP handler that serves the gRPC-Web and Connect
+// requests made to the gRPC endpoints of the services and the other requests
+// with h. It makes it possible for browser clients to call the gRPC endpoints
+// on the HTTP server port.
+func handleGRPCWeb(h http.Handler, serviceEndpoints *service.Endpoints, logger *log.Logger) http.Handler {
+	adapter := middleware.NewLogger(logger)
+	srv := grpc.NewServer(
+		grpc.ChainUnaryInterceptor(
+			grpcmdlwr.UnaryRequestID(),
+			grpcmdlwr.UnaryServerLog(adapter),
+		),
+	)
+	servicepb.RegisterServiceServer(srv, servicesvr.New(serviceEndpoints, nil))
+	return goagrpc.NewWebHandler(srv, h)
+}
+`
+
+const ServerNoHealthNoReflectionServerHandleCode = `// handleGRPCServer starts configures and starts a gRPC server on the given
+// URL. It shuts down the server if any error is received in the error channel.
+func handleGRPCServer(ctx context.Context, u *url.URL, serviceEndpoints *service.Endpoints, wg *sync.WaitGroup, errc chan error, logger *log.Logger, debug bool) {
+
+	// Setup goa log adapter.
+	var (
+		adapter middleware.Logger
+	)
+	{
+		adapter = middleware.NewLogger(logger)
+	}
+
+	// Wrap the endpoints with the transport specific layers. The generated
+	// server packages contains code generated from the design which maps
+	// the service input and output data structures to gRPC requests and
+	// responses.
+	var (
+		serviceServer *servicesvr.Server
+	)
+	{
+		serviceServer = servicesvr.New(serviceEndpoints, nil)
+	}
+
+	// Initialize gRPC server with the middleware.
+	srv := grpc.NewServer(
+		grpc.ChainUnaryInterceptor(
+			grpcmdlwr.UnaryRequestID(),
+			grpcmdlwr.UnaryServerLog(adapter),
+		),
+	)
+
+	// Register the servers.
+	servicepb.RegisterServiceServer(srv, serviceServer)
+
+	for svc, info := range srv.GetServiceInfo() {
+		for _, m := range info.Methods {
+			logger.Printf("serving gRPC method %s", svc+"/"+m.Name)
+		}
+	}
+
+	(*wg).Add(1)
+	go func() {
+		defer (*wg).Done()
+
+		// Start gRPC server in a separate goroutine.
+		go func() {
+			lis, err := net.Listen("tcp", u.Host)
+			if err != nil {
+				errc <- err
+			}
+			logger.Printf("gRPC server listening on %q", u.Host)
+			errc <- srv.Serve(lis)
+		}()
+
+		<-ctx.Done()
+		logger.Printf("shutting down gRPC server at %q", u.Host)
+		srv.Stop()
+	}()
+}
+`
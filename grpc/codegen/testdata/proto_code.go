@@ -504,3 +504,23 @@ message MethodRequest {
 message MethodResponse {
 }
 `
+
+const ExternalMessageProtoCode = `
+syntax = "proto3";
+
+package external_message;
+
+option go_package = "/external_messagepb";
+import "google/protobuf/timestamp.proto";
+
+// Service is the ExternalMessage service interface.
+service ExternalMessage {
+	// Method implements Method.
+	rpc Method (google.protobuf.Timestamp) returns (MethodResponse);
+}
+
+message MethodResponse {
+	string name = 1;
+	google.protobuf.Timestamp created_at = 2;
+}
+`
@@ -83,7 +83,7 @@ func DecodeMethodMessageWithMetadataResponse(ctx context.Context, v any, hdr, tr
 	{
 
 		if vals := hdr.Get("Location"); len(vals) > 0 {
-			inHeaderRaw = vals[0]
+			inHeaderRaw := vals[0]
 
 			v, err2 := strconv.ParseInt(inHeaderRaw, 10, strconv.IntSize)
 			if err2 != nil {
@@ -94,7 +94,7 @@ func DecodeMethodMessageWithMetadataResponse(ctx context.Context, v any, hdr, tr
 		}
 
 		if vals := trlr.Get("InTrailer"); len(vals) > 0 {
-			inTrailerRaw = vals[0]
+			inTrailerRaw := vals[0]
 
 			v, err2 := strconv.ParseBool(inTrailerRaw)
 			if err2 != nil {
@@ -126,7 +126,7 @@ func DecodeMethodMessageWithValidateResponse(ctx context.Context, v any, hdr, tr
 	{
 
 		if vals := hdr.Get("Location"); len(vals) > 0 {
-			inHeaderRaw = vals[0]
+			inHeaderRaw := vals[0]
 
 			v, err2 := strconv.ParseInt(inHeaderRaw, 10, strconv.IntSize)
 			if err2 != nil {
@@ -142,7 +142,7 @@ func DecodeMethodMessageWithValidateResponse(ctx context.Context, v any, hdr, tr
 		}
 
 		if vals := trlr.Get("InTrailer"); len(vals) > 0 {
-			inTrailerRaw = vals[0]
+			inTrailerRaw := vals[0]
 
 			v, err2 := strconv.ParseBool(inTrailerRaw)
 			if err2 != nil {
@@ -243,3 +243,43 @@ func DecodeMethodBidirectionalStreamingRPCResponse(ctx context.Context, v any, h
 	}, nil
 }
 `
+
+const ResultWithStringMetadataResponseDecoderCode = `// DecodeMethodResultWithStringMetadataResponse decodes responses from the
+// ServiceResultWithStringMetadata MethodResultWithStringMetadata endpoint.
+func DecodeMethodResultWithStringMetadataResponse(ctx context.Context, v any, hdr, trlr metadata.MD) (any, error) {
+	var (
+		etag *string
+		tags []string
+		ids  []int
+		err  error
+	)
+	{
+
+		if vals := hdr.Get("ETag"); len(vals) > 0 {
+			etag = &vals[0]
+		}
+
+		tags = hdr.Get("tags")
+
+		if idsRaw := trlr.Get("ids"); len(idsRaw) > 0 {
+			ids = make([]int, len(idsRaw))
+			for i, rv := range idsRaw {
+				v, err2 := strconv.ParseInt(rv, 10, strconv.IntSize)
+				if err2 != nil {
+					err = goa.MergeErrors(err, goa.InvalidFieldTypeError("ids", idsRaw, "array of integers"))
+				}
+				ids[i] = int(v)
+			}
+		}
+	}
+	if err != nil {
+		return nil, err
+	}
+	message, ok := v.(*service_result_with_string_metadatapb.MethodResultWithStringMetadataResponse)
+	if !ok {
+		return nil, goagrpc.ErrInvalidType("ServiceResultWithStringMetadata", "MethodResultWithStringMetadata", "*service_result_with_string_metadatapb.MethodResultWithStringMetadataResponse", v)
+	}
+	res := NewMethodResultWithStringMetadataResult(message, etag, tags, ids)
+	return res, nil
+}
+`
@@ -71,12 +71,12 @@ func EncodeMethodMessageWithMetadataResponse(ctx context.Context, v any, hdr, tr
 	}
 	resp := NewProtoMethodMessageWithMetadataResponse(result)
 
-	if res.InHeader != nil {
-		(*hdr).Append("Location", fmt.Sprintf("%v", *p.InHeader))
+	if result.InHeader != nil {
+		(*hdr).Append("Location", fmt.Sprintf("%v", *result.InHeader))
 	}
 
-	if res.InTrailer != nil {
-		(*trlr).Append("InTrailer", fmt.Sprintf("%v", *p.InTrailer))
+	if result.InTrailer != nil {
+		(*trlr).Append("InTrailer", fmt.Sprintf("%v", *result.InTrailer))
 	}
 	return resp, nil
 }
@@ -91,12 +91,12 @@ func EncodeMethodMessageWithValidateResponse(ctx context.Context, v any, hdr, tr
 	}
 	resp := NewProtoMethodMessageWithValidateResponse(result)
 
-	if res.InHeader != nil {
-		(*hdr).Append("Location", fmt.Sprintf("%v", *p.InHeader))
+	if result.InHeader != nil {
+		(*hdr).Append("Location", fmt.Sprintf("%v", *result.InHeader))
 	}
 
-	if res.InTrailer != nil {
-		(*trlr).Append("InTrailer", fmt.Sprintf("%v", *p.InTrailer))
+	if result.InTrailer != nil {
+		(*trlr).Append("InTrailer", fmt.Sprintf("%v", *result.InTrailer))
 	}
 	return resp, nil
 }
@@ -116,3 +116,27 @@ func EncodeMethodMessageUserTypeWithNestedUserTypesResponse(ctx context.Context,
 	return resp, nil
 }
 `
+
+const ResultWithStringMetadataResponseEncoderCode = `// EncodeMethodResultWithStringMetadataResponse encodes responses from the
+// "ServiceResultWithStringMetadata" service "MethodResultWithStringMetadata"
+// endpoint.
+func EncodeMethodResultWithStringMetadataResponse(ctx context.Context, v any, hdr, trlr *metadata.MD) (any, error) {
+	result, ok := v.(*serviceresultwithstringmetadata.MethodResultWithStringMetadataResult)
+	if !ok {
+		return nil, goagrpc.ErrInvalidType("ServiceResultWithStringMetadata", "MethodResultWithStringMetadata", "*serviceresultwithstringmetadata.MethodResultWithStringMetadataResult", v)
+	}
+	resp := NewProtoMethodResultWithStringMetadataResponse(result)
+
+	if result.Etag != nil {
+		(*hdr).Append("ETag", *result.Etag)
+	}
+
+	(*hdr).Append("tags", result.Tags...)
+
+	for _, value := range result.Ids {
+		valueStr := strconv.Itoa(value)
+		(*trlr).Append("ids", valueStr)
+	}
+	return resp, nil
+}
+`
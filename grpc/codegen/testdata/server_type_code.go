@@ -554,3 +554,57 @@ func NewProtoMethodResponse() *default_fieldspb.MethodResponse {
 	return message
 }
 `
+
+const ExternalMessageServerTypeCode = `// NewMethodPayload builds the payload of the "Method" endpoint of the
+// "ExternalMessage" service from the gRPC request type.
+func NewMethodPayload(message *timestamppb.Timestamp) *externalmessage.Timestamp {
+	v := &externalmessage.Timestamp{
+		Seconds: message.Seconds,
+		Nanos:   message.Nanos,
+	}
+	return v
+}
+
+// NewProtoMethodResponse builds the gRPC response type from the result of the
+// "Method" endpoint of the "ExternalMessage" service.
+func NewProtoMethodResponse(result *externalmessage.Event) *external_messagepb.MethodResponse {
+	message := &external_messagepb.MethodResponse{
+		Name: result.Name,
+	}
+	if result.CreatedAt != nil {
+		message.CreatedAt = svcExternalmessageTimestampToTimestamppbTimestamp(result.CreatedAt)
+	}
+	return message
+}
+
+// ValidateTimestamppbTimestamp runs the validations defined on
+// TimestamppbTimestamp.
+func ValidateTimestamppbTimestamp(message *timestamppb.Timestamp) (err error) {
+	if message.Nanos < 0 {
+		err = goa.MergeErrors(err, goa.InvalidRangeError("message.nanos", message.Nanos, 0, true))
+	}
+	return
+}
+
+// svcExternalmessageTimestampToTimestamppbTimestamp builds a value of type
+// *timestamppb.Timestamp from a value of type *externalmessage.Timestamp.
+func svcExternalmessageTimestampToTimestamppbTimestamp(v *externalmessage.Timestamp) *timestamppb.Timestamp {
+	res := &timestamppb.Timestamp{
+		Seconds: v.Seconds,
+		Nanos:   v.Nanos,
+	}
+
+	return res
+}
+
+// protobufTimestamppbTimestampToExternalmessageTimestamp builds a value of
+// type *externalmessage.Timestamp from a value of type *timestamppb.Timestamp.
+func protobufTimestamppbTimestampToExternalmessageTimestamp(v *timestamppb.Timestamp) *externalmessage.Timestamp {
+	res := &externalmessage.Timestamp{
+		Seconds: v.Seconds,
+		Nanos:   v.Nanos,
+	}
+
+	return res
+}
+`
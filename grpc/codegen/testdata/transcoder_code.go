@@ -0,0 +1,39 @@
+package testdata
+
+var TranscoderCode = `// NewEndpoints returns the "Cellar" service endpoints that transcode the
+// method calls into requests made to the gRPC server with cc. Giving the
+// endpoints to the constructor of the service HTTP server makes the gRPC
+// server serve the HTTP routes defined in the design.
+func NewEndpoints(cc *grpc.ClientConn, opts ...grpc.CallOption) *cellar.Endpoints {
+	c := client.NewClient(cc, opts...)
+	return &cellar.Endpoints{
+		Add:      c.Add(),
+		Show:     NewShowEndpoint(c),
+		Watch:    unsupported("watch"),
+		Download: unsupported("download"),
+	}
+}
+
+// NewShowEndpoint returns an endpoint that calls the "show" method of the gRPC
+// server and renders the result using the "default" view.
+func NewShowEndpoint(c *client.Client) goa.Endpoint {
+	endpoint := c.Show()
+	return func(ctx context.Context, req any) (any, error) {
+		res, err := endpoint(ctx, req)
+		if err != nil {
+			return nil, err
+		}
+		return cellar.NewViewedBottle(res.(*cellar.Bottle), "default"), nil
+	}
+}
+
+// unsupported returns an endpoint that fails with a "not_implemented" error.
+// The streaming methods, the methods that skip the encoding and decoding of
+// the request or response body and the methods that do not define the gRPC
+// transport cannot be transcoded.
+func unsupported(method string) goa.Endpoint {
+	return func(context.Context, any) (any, error) {
+		return nil, goa.PermanentError("not_implemented", "method %q cannot be transcoded to gRPC", method)
+	}
+}
+`
@@ -0,0 +1,178 @@
+package codegen
+
+import (
+	"path"
+	"path/filepath"
+
+	"goa.design/goa/v3/codegen"
+	"goa.design/goa/v3/expr"
+)
+
+type (
+	// transcoderData contains the data needed to render the transcoder of
+	// a service.
+	transcoderData struct {
+		// ServiceName is the name of the service.
+		ServiceName string
+		// ServicePkgName is the name of the service package.
+		ServicePkgName string
+		// ClientStruct is the name of the gRPC client struct.
+		ClientStruct string
+		// ClientInit is the name of the constructor of the gRPC client.
+		ClientInit string
+		// Methods lists the service methods.
+		Methods []*transcodedMethodData
+		// HasUnsupported is true if at least one method cannot be
+		// transcoded.
+		HasUnsupported bool
+	}
+
+	// transcodedMethodData contains the data needed to render the
+	// transcoding endpoint of a method.
+	transcodedMethodData struct {
+		// Name is the name of the method.
+		Name string
+		// VarName is the name of the method endpoint field.
+		VarName string
+		// Supported is true if the method calls can be transcoded.
+		Supported bool
+		// ResultRef is the fully qualified reference to the method result
+		// type.
+		ResultRef string
+		// ViewedResultInit is the name of the function that builds the
+		// viewed result from the result, empty if the result is not viewed.
+		ViewedResultInit string
+		// ViewName is the name of the view used to render the result.
+		ViewName string
+	}
+)
+
+// TranscoderFiles returns the transcoders of the services that define both
+// the HTTP and gRPC transports and the "grpc:transcode" meta. A transcoder
+// builds the service endpoints that forward the method calls to the gRPC
+// server so that a single service implementation served by the gRPC server
+// also backs the HTTP routes defined in the design.
+func TranscoderFiles(genpkg string, root *expr.RootExpr) []*codegen.File {
+	var fw []*codegen.File
+	for _, svc := range root.API.GRPC.Services {
+		if _, ok := svc.ServiceExpr.Meta["grpc:transcode"]; !ok {
+			continue
+		}
+		if root.API.HTTP.Service(svc.Name()) == nil {
+			continue
+		}
+		fw = append(fw, transcoderFile(genpkg, svc))
+	}
+	return fw
+}
+
+// transcoderFile returns the file defining the transcoder of the given
+// service.
+func transcoderFile(genpkg string, svc *expr.GRPCServiceExpr) *codegen.File {
+	var (
+		data = GRPCServices.Get(svc.Name())
+		sd   = data.Service
+
+		svcName = sd.PathName
+		fpath   = filepath.Join(codegen.Gendir, "grpc", svcName, "transcoder", "transcoder.go")
+	)
+	endpoints := make(map[string]*EndpointData, len(data.Endpoints))
+	for _, e := range data.Endpoints {
+		endpoints[e.Method.Name] = e
+	}
+	td := &transcoderData{
+		ServiceName:    sd.Name,
+		ServicePkgName: sd.PkgName,
+		ClientStruct:   data.ClientStruct,
+		ClientInit:     data.ClientInit,
+	}
+	for _, m := range sd.Methods {
+		md := &transcodedMethodData{Name: m.Name, VarName: m.VarName}
+		e, ok := endpoints[m.Name]
+		md.Supported = ok && e.ServerStream == nil && e.ClientStream == nil &&
+			!m.SkipRequestBodyEncodeDecode && !m.SkipResponseBodyEncodeDecode
+		if md.Supported && m.ViewedResult != nil {
+			md.ResultRef = e.ResultRef
+			md.ViewedResultInit = m.ViewedResult.Init.Name
+			md.ViewName = m.ViewedResult.ViewName
+			if md.ViewName == "" {
+				md.ViewName = expr.DefaultView
+			}
+		}
+		if !md.Supported {
+			td.HasUnsupported = true
+		}
+		td.Methods = append(td.Methods, md)
+	}
+
+	imports := []*codegen.ImportSpec{
+		{Path: "context"},
+		{Path: "google.golang.org/grpc"},
+		codegen.GoaImport(""),
+		{Path: path.Join(genpkg, svcName), Name: sd.PkgName},
+		{Path: path.Join(genpkg, "grpc", svcName, "client")},
+	}
+	imports = append(imports, sd.UserTypeImports...)
+	sections := []*codegen.SectionTemplate{
+		codegen.Header(svc.Name()+" gRPC transcoder", "transcoder", imports),
+		{
+			Name:   "transcoder-endpoints-init",
+			Source: transcoderEndpointsInitT,
+			Data:   td,
+		},
+	}
+	for _, m := range td.Methods {
+		if m.ViewedResultInit == "" {
+			continue
+		}
+		sections = append(sections, &codegen.SectionTemplate{
+			Name:   "transcoder-viewed-endpoint",
+			Source: transcoderViewedEndpointT,
+			Data:   map[string]any{"Transcoder": td, "Method": m},
+		})
+	}
+	if td.HasUnsupported {
+		sections = append(sections, &codegen.SectionTemplate{
+			Name:   "transcoder-unsupported",
+			Source: transcoderUnsupportedT,
+		})
+	}
+	return &codegen.File{Path: fpath, SectionTemplates: sections}
+}
+
+// input: transcoderData
+const transcoderEndpointsInitT = `{{ printf "NewEndpoints returns the %q service endpoints that transcode the method calls into requests made to the gRPC server with cc. Giving the endpoints to the constructor of the service HTTP server makes the gRPC server serve the HTTP routes defined in the design." .ServiceName | comment }}
+func NewEndpoints(cc *grpc.ClientConn, opts ...grpc.CallOption) *{{ .ServicePkgName }}.Endpoints {
+	c := client.{{ .ClientInit }}(cc, opts...)
+	return &{{ .ServicePkgName }}.Endpoints{
+{{- range .Methods }}
+		{{ .VarName }}: {{ if not .Supported }}unsupported({{ printf "%q" .Name }}){{ else if .ViewedResultInit }}New{{ .VarName }}Endpoint(c){{ else }}c.{{ .VarName }}(){{ end }},
+{{- end }}
+	}
+}
+`
+
+// input: map[string]any{"Transcoder": *transcoderData, "Method": *transcodedMethodData}
+const transcoderViewedEndpointT = `{{ printf "New%sEndpoint returns an endpoint that calls the %q method of the gRPC server and renders the result using the %q view." .Method.VarName .Method.Name .Method.ViewName | comment }}
+func New{{ .Method.VarName }}Endpoint(c *client.{{ .Transcoder.ClientStruct }}) goa.Endpoint {
+	endpoint := c.{{ .Method.VarName }}()
+	return func(ctx context.Context, req any) (any, error) {
+		res, err := endpoint(ctx, req)
+		if err != nil {
+			return nil, err
+		}
+		return {{ .Transcoder.ServicePkgName }}.{{ .Method.ViewedResultInit }}(res.({{ .Method.ResultRef }}), {{ printf "%q" .Method.ViewName }}), nil
+	}
+}
+`
+
+const transcoderUnsupportedT = `// unsupported returns an endpoint that fails with a "not_implemented" error.
+// The streaming methods, the methods that skip the encoding and decoding of
+// the request or response body and the methods that do not define the gRPC
+// transport cannot be transcoded.
+func unsupported(method string) goa.Endpoint {
+	return func(context.Context, any) (any, error) {
+		return nil, goa.PermanentError("not_implemented", "method %q cannot be transcoded to gRPC", method)
+	}
+}
+`
@@ -0,0 +1,24 @@
+package codegen
+
+import (
+	"testing"
+
+	"goa.design/goa/v3/codegen"
+	"goa.design/goa/v3/expr"
+	"goa.design/goa/v3/grpc/codegen/testdata"
+)
+
+func TestTranscoderFiles(t *testing.T) {
+	RunGRPCDSL(t, testdata.TranscoderDSL)
+	fs := TranscoderFiles("", expr.Root)
+	if len(fs) != 1 {
+		t.Fatalf("got %d files, expected 1", len(fs))
+	}
+	if fs[0].Path != "gen/grpc/cellar/transcoder/transcoder.go" {
+		t.Errorf("got path %q, expected %q", fs[0].Path, "gen/grpc/cellar/transcoder/transcoder.go")
+	}
+	code := codegen.SectionsCode(t, fs[0].SectionTemplates[1:])
+	if code != testdata.TranscoderCode {
+		t.Errorf("invalid code, got:\n%s\ngot vs. expected:\n%s", code, codegen.Diff(t, code, testdata.TranscoderCode))
+	}
+}
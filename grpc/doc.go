@@ -14,5 +14,6 @@ In addition to the code generation logic, the grpc package contains:
     * Encoder and decoder interfaces to convert a protocol buffer type to a Goa type and vice versa.
     * Error handlers to encode and decode error responses.
     * Interceptors (a.k.a middlewares) to wrap additional functionality around unary and streaming RPCs.
+    * A HTTP handler that serves gRPC-Web and Connect requests with a gRPC server.
 */
 package grpc
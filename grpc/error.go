@@ -1,10 +1,12 @@
 package grpc
 
 import (
+	"errors"
 	"fmt"
 
 	goapb "goa.design/goa/v3/grpc/pb"
 	goa "goa.design/goa/v3/pkg"
+	"goa.design/goa/v3/security"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
 	"google.golang.org/protobuf/proto"
@@ -76,9 +78,11 @@ func NewStatusError(code codes.Code, err error, details ...protoiface.MessageV1)
 // EncodeError returns a gRPC status error from the given error with the error
 // response encoded in the status details. If error is a goa ServiceError type
 // it implements a heuristic to compute the status code from the Timeout,
-// Fault, and Temporary characteristics of the ServiceError. If error is not a
-// ServiceError or a gRPC status error it returns a gRPC status error with
-// Unknown code and Fault characteristic set.
+// Fault, and Temporary characteristics of the ServiceError. Authorization
+// errors returned by the endpoint authorizers are mapped to the
+// PermissionDenied code. If error is not a ServiceError or a gRPC status error
+// it returns a gRPC status error with Unknown code and Fault characteristic
+// set.
 func EncodeError(err error) error {
 	if st, ok := status.FromError(err); ok {
 		if s, err := st.WithDetails(NewErrorResponse(err)); err == nil {
@@ -104,6 +108,10 @@ func EncodeError(err error) error {
 		}
 		return NewStatusError(code, err, NewErrorResponse(err))
 	}
+	var aerr *security.AuthorizationError
+	if errors.As(err, &aerr) {
+		return NewStatusError(codes.PermissionDenied, err, NewErrorResponse(goa.PermanentError("forbidden", aerr.Error())))
+	}
 	// Return an unknown gRPC status error with fault characteristic set.
 	return NewStatusError(codes.Unknown, err, NewErrorResponse(err))
 }
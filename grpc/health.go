@@ -0,0 +1,33 @@
+package grpc
+
+import (
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+)
+
+// RegisterHealth registers the standard grpc.health.v1 health checking service
+// with srv and returns it so that standard tooling such as Kubernetes probes
+// and grpc-health-probe can check the server. The serving status of the server
+// (empty service name) and of the services registered with srv prior to
+// calling RegisterHealth is set to SERVING. Calling Shutdown on the returned
+// server on shutdown sets all the statuses to NOT_SERVING so that the clients
+// stop sending new requests. The examples generated by "goa example" call
+// RegisterHealth unless the API sets the "grpc:health" meta to "false".
+//
+// Example:
+//
+//	srv := grpc.NewServer()
+//	calcpb.RegisterCalcServer(srv, calcsvr.New(endpoints, nil))
+//	hs := goagrpc.RegisterHealth(srv)
+//	...
+//	hs.Shutdown()
+//	srv.GracefulStop()
+func RegisterHealth(srv *grpc.Server) *health.Server {
+	hs := health.NewServer()
+	for name := range srv.GetServiceInfo() {
+		hs.SetServingStatus(name, healthpb.HealthCheckResponse_SERVING)
+	}
+	healthpb.RegisterHealthServer(srv, hs)
+	return hs
+}
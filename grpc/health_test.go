@@ -0,0 +1,37 @@
+package grpc
+
+import (
+	"context"
+	"testing"
+
+	"google.golang.org/grpc"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/reflection"
+)
+
+func TestRegisterHealth(t *testing.T) {
+	srv := grpc.NewServer()
+	reflection.Register(srv)
+	hs := RegisterHealth(srv)
+	if _, ok := srv.GetServiceInfo()[healthpb.Health_ServiceDesc.ServiceName]; !ok {
+		t.Fatal("expected the health service to be registered")
+	}
+	ctx := context.Background()
+	for _, name := range []string{"", "grpc.reflection.v1alpha.ServerReflection"} {
+		resp, err := hs.Check(ctx, &healthpb.HealthCheckRequest{Service: name})
+		if err != nil {
+			t.Fatalf("%q: %v", name, err)
+		}
+		if resp.Status != healthpb.HealthCheckResponse_SERVING {
+			t.Errorf("%q: got status %v, expected SERVING", name, resp.Status)
+		}
+	}
+	hs.Shutdown()
+	resp, err := hs.Check(ctx, &healthpb.HealthCheckRequest{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if resp.Status != healthpb.HealthCheckResponse_NOT_SERVING {
+		t.Errorf("got status %v after shutdown, expected NOT_SERVING", resp.Status)
+	}
+}
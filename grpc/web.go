@@ -0,0 +1,382 @@
+package grpc
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/reflect/protoregistry"
+)
+
+type (
+	// webResponseWriter translates the HTTP/2 response written by the gRPC
+	// server into a gRPC-Web response: the trailers are written at the end
+	// of the body in a frame flagged with 0x80.
+	webResponseWriter struct {
+		w           http.ResponseWriter
+		header      http.Header
+		contentType string
+		text        bool
+		wroteHeader bool
+		// buf contains the bytes of a gRPC-Web text response written
+		// since the last flush.
+		buf bytes.Buffer
+	}
+
+	// connectRecorder records the response written by the gRPC server to
+	// a Connect unary request.
+	connectRecorder struct {
+		header http.Header
+		body   bytes.Buffer
+	}
+
+	// connectError is the JSON representation of a Connect error.
+	connectError struct {
+		Code    string `json:"code"`
+		Message string `json:"message,omitempty"`
+	}
+)
+
+// grpcTrailers lists the trailers written by the gRPC server.
+var grpcTrailers = []string{"Grpc-Status", "Grpc-Message", "Grpc-Status-Details-Bin"}
+
+// connectCodes maps the gRPC status codes to the Connect error codes and
+// HTTP status codes, see https://connectrpc.com/docs/protocol#error-codes.
+var connectCodes = map[codes.Code]struct {
+	name   string
+	status int
+}{
+	codes.Canceled:           {"canceled", 499},
+	codes.Unknown:            {"unknown", http.StatusInternalServerError},
+	codes.InvalidArgument:    {"invalid_argument", http.StatusBadRequest},
+	codes.DeadlineExceeded:   {"deadline_exceeded", http.StatusGatewayTimeout},
+	codes.NotFound:           {"not_found", http.StatusNotFound},
+	codes.AlreadyExists:      {"already_exists", http.StatusConflict},
+	codes.PermissionDenied:   {"permission_denied", http.StatusForbidden},
+	codes.ResourceExhausted:  {"resource_exhausted", http.StatusTooManyRequests},
+	codes.FailedPrecondition: {"failed_precondition", http.StatusBadRequest},
+	codes.Aborted:            {"aborted", http.StatusConflict},
+	codes.OutOfRange:         {"out_of_range", http.StatusBadRequest},
+	codes.Unimplemented:      {"unimplemented", http.StatusNotImplemented},
+	codes.Internal:           {"internal", http.StatusInternalServerError},
+	codes.Unavailable:        {"unavailable", http.StatusServiceUnavailable},
+	codes.DataLoss:           {"data_loss", http.StatusInternalServerError},
+	codes.Unauthenticated:    {"unauthenticated", http.StatusUnauthorized},
+}
+
+// NewWebHandler returns a HTTP handler that serves the gRPC-Web and Connect
+// requests made to the methods registered on srv so that browser clients may
+// call them on the same port as the REST endpoints. The other requests are
+// served by next.
+//
+// gRPC-Web requests are identified by their "application/grpc-web" and
+// "application/grpc-web-text" content types. Connect requests are POST
+// requests made to the path of a unary method (/package.Service/Method) with
+// the "application/proto" or "application/json" content type, the JSON
+// messages are converted using the protocol buffer types registered in the
+// global registry. Connect streaming requests are not supported.
+//
+// The methods must be registered on srv before NewWebHandler is called.
+func NewWebHandler(srv *grpc.Server, next http.Handler) http.Handler {
+	unary := make(map[string]struct{})
+	for svc, info := range srv.GetServiceInfo() {
+		for _, m := range info.Methods {
+			if !m.IsClientStream && !m.IsServerStream {
+				unary["/"+svc+"/"+m.Name] = struct{}{}
+			}
+		}
+	}
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ct, _, _ := mime.ParseMediaType(r.Header.Get("Content-Type"))
+		if strings.HasPrefix(ct, "application/grpc-web") {
+			serveGRPCWeb(srv, ct, w, r)
+			return
+		}
+		if _, ok := unary[r.URL.Path]; ok && r.Method == http.MethodPost {
+			if ct == "application/proto" || ct == "application/json" {
+				serveConnect(srv, ct, w, r)
+				return
+			}
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// serveGRPCWeb serves a gRPC-Web request by forwarding it to the gRPC server
+// as a HTTP/2 gRPC request.
+func serveGRPCWeb(srv *grpc.Server, ct string, w http.ResponseWriter, r *http.Request) {
+	subtype := strings.TrimPrefix(ct, "application/grpc-web")
+	text := strings.HasPrefix(subtype, "-text")
+	subtype = strings.TrimPrefix(subtype, "-text")
+	req := r.Clone(r.Context())
+	if text {
+		body, err := io.ReadAll(r.Body)
+		if err == nil {
+			body, err = decodeWebText(body)
+		}
+		if err != nil {
+			http.Error(w, "invalid gRPC-Web text request body: "+err.Error(), http.StatusBadRequest)
+			return
+		}
+		req.Body = io.NopCloser(bytes.NewReader(body))
+	}
+	toHTTP2(req, "application/grpc"+subtype)
+	ww := &webResponseWriter{w: w, header: make(http.Header), contentType: ct, text: text}
+	srv.ServeHTTP(ww, req)
+	ww.finish()
+}
+
+// serveConnect serves a Connect unary request by forwarding it to the gRPC
+// server as a HTTP/2 gRPC request and by translating the response.
+func serveConnect(srv *grpc.Server, ct string, w http.ResponseWriter, r *http.Request) {
+	if enc := r.Header.Get("Content-Encoding"); enc != "" && enc != "identity" {
+		writeConnectError(w, codes.Unimplemented, fmt.Sprintf("unsupported content encoding %q", enc))
+		return
+	}
+	msg, err := io.ReadAll(r.Body)
+	if err != nil {
+		writeConnectError(w, codes.InvalidArgument, err.Error())
+		return
+	}
+	var md protoreflect.MethodDescriptor
+	if ct == "application/json" {
+		if md, err = methodDescriptor(r.URL.Path); err != nil {
+			writeConnectError(w, codes.Unimplemented, err.Error())
+			return
+		}
+		if msg, err = convertMessage(md.Input(), msg, protojson.Unmarshal, proto.Marshal); err != nil {
+			writeConnectError(w, codes.InvalidArgument, err.Error())
+			return
+		}
+	}
+	req := r.Clone(r.Context())
+	req.Body = io.NopCloser(bytes.NewReader(frame(0, msg)))
+	toHTTP2(req, "application/grpc+proto")
+	if ms := r.Header.Get("Connect-Timeout-Ms"); ms != "" {
+		req.Header.Set("Grpc-Timeout", ms+"m")
+	}
+	req.Header.Del("Connect-Timeout-Ms")
+	req.Header.Del("Connect-Protocol-Version")
+
+	rec := &connectRecorder{header: make(http.Header)}
+	srv.ServeHTTP(rec, req)
+
+	st := rec.header.Get("Grpc-Status")
+	if st == "" {
+		// The gRPC server rejected the request before handling it.
+		writeConnectError(w, codes.Unknown, strings.TrimSpace(rec.body.String()))
+		return
+	}
+	code, err := strconv.Atoi(st)
+	if err != nil {
+		writeConnectError(w, codes.Internal, "invalid gRPC status "+st)
+		return
+	}
+	for k, vs := range rec.header {
+		switch {
+		case strings.HasPrefix(k, http.TrailerPrefix):
+			w.Header()["Trailer-"+strings.TrimPrefix(k, http.TrailerPrefix)] = vs
+		case k == "Content-Type" || k == "Trailer" || strings.HasPrefix(k, "Grpc-"):
+		default:
+			w.Header()[k] = vs
+		}
+	}
+	if codes.Code(code) != codes.OK {
+		m, _ := url.PathUnescape(rec.header.Get("Grpc-Message"))
+		writeConnectError(w, codes.Code(code), m)
+		return
+	}
+	body := rec.body.Bytes()
+	if len(body) < 5 || body[0] != 0 || int(binary.BigEndian.Uint32(body[1:5])) != len(body)-5 {
+		writeConnectError(w, codes.Internal, "invalid gRPC response message")
+		return
+	}
+	msg = body[5:]
+	if md != nil {
+		if msg, err = convertMessage(md.Output(), msg, proto.Unmarshal, protojson.Marshal); err != nil {
+			writeConnectError(w, codes.Internal, err.Error())
+			return
+		}
+	}
+	w.Header().Set("Content-Type", ct)
+	w.Header().Set("Content-Length", strconv.Itoa(len(msg)))
+	w.WriteHeader(http.StatusOK)
+	w.Write(msg) // nolint: errcheck
+}
+
+// toHTTP2 turns req into the HTTP/2 gRPC request served by the gRPC server.
+func toHTTP2(req *http.Request, ct string) {
+	req.ProtoMajor, req.ProtoMinor, req.Proto = 2, 0, "HTTP/2.0"
+	req.ContentLength = -1
+	req.Header.Del("Content-Length")
+	req.Header.Set("Content-Type", ct)
+	req.Header.Set("Te", "trailers")
+}
+
+// frame returns the gRPC length-prefixed message made of the given flags and
+// message.
+func frame(flags byte, msg []byte) []byte {
+	b := make([]byte, 5, 5+len(msg))
+	b[0] = flags
+	binary.BigEndian.PutUint32(b[1:], uint32(len(msg)))
+	return append(b, msg...)
+}
+
+// decodeWebText decodes the base64 encoded body of a gRPC-Web text request.
+// The body may be made of several padded base64 chunks so each 4 characters
+// quantum is decoded separately.
+func decodeWebText(body []byte) ([]byte, error) {
+	body = bytes.Join(bytes.Fields(body), nil)
+	if len(body)%4 != 0 {
+		return nil, fmt.Errorf("invalid base64 length %d", len(body))
+	}
+	res := make([]byte, 0, len(body)/4*3)
+	var buf [3]byte
+	for i := 0; i < len(body); i += 4 {
+		n, err := base64.StdEncoding.Decode(buf[:], body[i:i+4])
+		if err != nil {
+			return nil, err
+		}
+		res = append(res, buf[:n]...)
+	}
+	return res, nil
+}
+
+// methodDescriptor returns the descriptor of the method with the given gRPC
+// path looked up in the global protocol buffer registry.
+func methodDescriptor(path string) (protoreflect.MethodDescriptor, error) {
+	svc, name, _ := strings.Cut(strings.TrimPrefix(path, "/"), "/")
+	d, err := protoregistry.GlobalFiles.FindDescriptorByName(protoreflect.FullName(svc))
+	if err != nil {
+		return nil, fmt.Errorf("unknown service %q", svc)
+	}
+	sd, ok := d.(protoreflect.ServiceDescriptor)
+	if !ok {
+		return nil, fmt.Errorf("unknown service %q", svc)
+	}
+	md := sd.Methods().ByName(protoreflect.Name(name))
+	if md == nil {
+		return nil, fmt.Errorf("unknown method %q", path)
+	}
+	return md, nil
+}
+
+// convertMessage decodes msg into a message described by desc using decode
+// and encodes it back using encode.
+func convertMessage(desc protoreflect.MessageDescriptor, msg []byte, decode func([]byte, proto.Message) error, encode func(proto.Message) ([]byte, error)) ([]byte, error) {
+	mt, err := protoregistry.GlobalTypes.FindMessageByName(desc.FullName())
+	if err != nil {
+		return nil, fmt.Errorf("unknown message %q", desc.FullName())
+	}
+	m := mt.New().Interface()
+	if err := decode(msg, m); err != nil {
+		return nil, err
+	}
+	return encode(m)
+}
+
+// writeConnectError writes a Connect unary error response.
+func writeConnectError(w http.ResponseWriter, code codes.Code, msg string) {
+	c, ok := connectCodes[code]
+	if !ok {
+		c = connectCodes[codes.Unknown]
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(c.status)
+	json.NewEncoder(w).Encode(&connectError{Code: c.name, Message: msg}) // nolint: errcheck
+}
+
+// Header returns the headers written by the gRPC server, the keys listed in
+// grpcTrailers and the keys prefixed with http.TrailerPrefix are trailers.
+func (w *webResponseWriter) Header() http.Header { return w.header }
+
+// WriteHeader writes the response headers.
+func (w *webResponseWriter) WriteHeader(code int) {
+	if w.wroteHeader {
+		return
+	}
+	w.wroteHeader = true
+	h := w.w.Header()
+	for k, vs := range w.header {
+		if k != "Trailer" && !strings.HasPrefix(k, http.TrailerPrefix) {
+			h[k] = vs
+		}
+	}
+	for _, k := range grpcTrailers {
+		h.Del(k)
+	}
+	h.Set("Content-Type", w.contentType)
+	w.w.WriteHeader(code)
+}
+
+// Write writes the response body.
+func (w *webResponseWriter) Write(b []byte) (int, error) {
+	if !w.wroteHeader {
+		w.WriteHeader(http.StatusOK)
+	}
+	if w.text {
+		return w.buf.Write(b)
+	}
+	return w.w.Write(b)
+}
+
+// Flush writes the buffered text response and flushes the underlying writer.
+func (w *webResponseWriter) Flush() {
+	if !w.wroteHeader {
+		w.WriteHeader(http.StatusOK)
+	}
+	if w.text && w.buf.Len() > 0 {
+		w.w.Write([]byte(base64.StdEncoding.EncodeToString(w.buf.Bytes()))) // nolint: errcheck
+		w.buf.Reset()
+	}
+	if f, ok := w.w.(http.Flusher); ok {
+		f.Flush()
+	}
+}
+
+// finish writes the trailers frame.
+func (w *webResponseWriter) finish() {
+	var trailers bytes.Buffer
+	for _, k := range grpcTrailers {
+		if v := w.header.Get(k); v != "" {
+			fmt.Fprintf(&trailers, "%s: %s\r\n", strings.ToLower(k), v)
+		}
+	}
+	for k, vs := range w.header {
+		if !strings.HasPrefix(k, http.TrailerPrefix) {
+			continue
+		}
+		for _, v := range vs {
+			fmt.Fprintf(&trailers, "%s: %s\r\n", strings.ToLower(strings.TrimPrefix(k, http.TrailerPrefix)), v)
+		}
+	}
+	w.Write(frame(0x80, trailers.Bytes())) // nolint: errcheck
+	w.Flush()
+}
+
+// Header returns the response headers and trailers.
+func (r *connectRecorder) Header() http.Header { return r.header }
+
+// WriteHeader is a no-op, the status of the response is read from the
+// gRPC status trailer.
+func (r *connectRecorder) WriteHeader(int) {}
+
+// Write records the response body.
+func (r *connectRecorder) Write(b []byte) (int, error) { return r.body.Write(b) }
+
+// Flush is a no-op, it makes it possible for the gRPC server to serve the
+// request.
+func (r *connectRecorder) Flush() {}
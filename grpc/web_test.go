@@ -0,0 +1,99 @@
+package grpc
+
+import (
+	"bytes"
+	"encoding/base64"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/protobuf/proto"
+)
+
+func TestWebHandler(t *testing.T) {
+	srv := grpc.NewServer()
+	hs := health.NewServer()
+	hs.SetServingStatus("calc", healthpb.HealthCheckResponse_SERVING)
+	healthpb.RegisterHealthServer(srv, hs)
+	rest := http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.Write([]byte("rest")) // nolint: errcheck
+	})
+	h := NewWebHandler(srv, rest)
+
+	check, err := proto.Marshal(&healthpb.HealthCheckRequest{Service: "calc"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	serving, err := proto.Marshal(&healthpb.HealthCheckResponse{Status: healthpb.HealthCheckResponse_SERVING})
+	if err != nil {
+		t.Fatal(err)
+	}
+	const path = "/grpc.health.v1.Health/Check"
+
+	cases := []struct {
+		name        string
+		path        string
+		contentType string
+		body        []byte
+		status      int
+		resContent  string
+		resBody     []byte
+	}{
+		{"grpc-web", path, "application/grpc-web+proto", frame(0, check),
+			200, "application/grpc-web+proto", append(frame(0, serving), frame(0x80, []byte("grpc-status: 0\r\n"))...)},
+		{"grpc-web-text", path, "application/grpc-web-text", []byte(base64.StdEncoding.EncodeToString(frame(0, check))),
+			200, "application/grpc-web-text", []byte(base64.StdEncoding.EncodeToString(frame(0, serving)) + base64.StdEncoding.EncodeToString(frame(0x80, []byte("grpc-status: 0\r\n"))))},
+		{"grpc-web-error", path, "application/grpc-web", frame(0, []byte{0x0a, 0x03, 'f', 'o', 'o'}),
+			200, "application/grpc-web", frame(0x80, []byte("grpc-status: 5\r\ngrpc-message: unknown service\r\n"))},
+		{"connect-proto", path, "application/proto", check,
+			200, "application/proto", serving},
+		{"connect-json", path, "application/json", []byte(`{"service":"calc"}`),
+			200, "application/json", []byte(`{"status":"SERVING"}`)},
+		{"connect-error", path, "application/json", []byte(`{"service":"foo"}`),
+			404, "application/json", []byte(`{"code":"not_found","message":"unknown service"}` + "\n")},
+		{"connect-invalid-json", path, "application/json", []byte(`{"svc":"foo"}`),
+			400, "application/json", nil},
+		{"rest-json", "/calc/add", "application/json", []byte(`{"a":1}`),
+			200, "text/plain; charset=utf-8", []byte("rest")},
+		{"connect-streaming", "/grpc.health.v1.Health/Watch", "application/json", []byte(`{}`),
+			200, "text/plain; charset=utf-8", []byte("rest")},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			r := httptest.NewRequest("POST", c.path, bytes.NewReader(c.body))
+			r.Header.Set("Content-Type", c.contentType)
+			w := httptest.NewRecorder()
+			h.ServeHTTP(w, r)
+			if w.Code != c.status {
+				t.Errorf("got status %d, expected %d (%s)", w.Code, c.status, w.Body.String())
+			}
+			if ct := w.Header().Get("Content-Type"); ct != c.resContent {
+				t.Errorf("got content type %q, expected %q", ct, c.resContent)
+			}
+			if c.resBody != nil && !bytes.Equal(w.Body.Bytes(), c.resBody) {
+				t.Errorf("got body %q, expected %q", w.Body.String(), c.resBody)
+			}
+			if w.Header().Get("Grpc-Status") != "" {
+				t.Errorf("got grpc-status header, expected it to be sent in the body")
+			}
+		})
+	}
+}
+
+func TestDecodeWebText(t *testing.T) {
+	enc := base64.StdEncoding.EncodeToString
+	body := enc([]byte("a")) + enc([]byte("bc")) + "\n" + enc([]byte("def"))
+	got, err := decodeWebText([]byte(body))
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if string(got) != "abcdef" {
+		t.Errorf("got %q, expected %q", got, "abcdef")
+	}
+	if _, err := decodeWebText([]byte(body + "Y")); err == nil {
+		t.Error("expected an error")
+	}
+}
@@ -0,0 +1,190 @@
+package http
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"sync"
+	"time"
+)
+
+type (
+	// AdminServer serves the operational endpoints of a service - health
+	// checks, metrics, profiles and configuration reload - on a port
+	// distinct from the one serving the business routes so that they are
+	// not exposed publicly and do not go through the business
+	// middlewares. AdminServer is safe for concurrent use.
+	AdminServer struct {
+		// Addr is the address the server listens on, e.g. ":9090".
+		Addr string
+
+		mux    Muxer
+		mu     sync.RWMutex
+		checks map[string]func(context.Context) error
+	}
+
+	// AdminOption configures an AdminServer.
+	AdminOption func(*AdminServer)
+
+	// HealthStatus is the body of the responses to the readiness checks
+	// served by AdminServer.
+	HealthStatus struct {
+		// Status is "ok" if all the checks pass and "unavailable"
+		// otherwise.
+		Status string `json:"status"`
+		// Checks maps the names of the checks to "ok" or to the error
+		// they returned.
+		Checks map[string]string `json:"checks,omitempty"`
+	}
+)
+
+// healthCheckTimeout is the maximum duration of the readiness checks.
+const healthCheckTimeout = 5 * time.Second
+
+// NewAdminServer returns an admin server listening on addr that serves:
+//
+//   - GET /livez which always responds with 200 OK while the process runs.
+//   - GET /readyz (and its alias /healthz) which runs the checks added with
+//     WithHealthCheck or AddHealthCheck concurrently and responds with 200 OK
+//     if they all pass and 503 Service Unavailable otherwise. The body is the
+//     JSON representation of a HealthStatus.
+//   - GET /metrics if configured with WithAdminMetrics.
+//   - POST /reload if configured with WithAdminReload.
+//   - the debug handlers under /debug if configured with WithAdminDebug.
+//
+// Example:
+//
+//	metrics := httpmdlwr.NewServerMetrics()
+//	admin := goahttp.NewAdminServer(":9090",
+//	    goahttp.WithHealthCheck("db", db.PingContext),
+//	    goahttp.WithAdminMetrics(metrics),
+//	    goahttp.WithAdminReload(func() error { return cfg.Reload(load) }),
+//	    goahttp.WithAdminDebug(goahttp.WithDebugAuth(goahttp.DebugLoopbackAuth), goahttp.WithDebugRoutes(servers.Routes())))
+//	go admin.Run(ctx)
+func NewAdminServer(addr string, opts ...AdminOption) *AdminServer {
+	s := &AdminServer{
+		Addr:   addr,
+		mux:    NewMuxer(),
+		checks: make(map[string]func(context.Context) error),
+	}
+	s.mux.Handle("GET", "/livez", func(w http.ResponseWriter, _ *http.Request) {
+		w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+		w.Write([]byte("ok")) // nolint: errcheck
+	})
+	s.mux.Handle("GET", "/readyz", s.serveReady)
+	s.mux.Handle("GET", "/healthz", s.serveReady)
+	for _, opt := range opts {
+		opt(s)
+	}
+	return s
+}
+
+// WithHealthCheck adds a readiness check, see AddHealthCheck.
+func WithHealthCheck(name string, check func(context.Context) error) AdminOption {
+	return func(s *AdminServer) { s.AddHealthCheck(name, check) }
+}
+
+// WithAdminMetrics serves h, e.g. a *middleware.ServerMetrics or a Prometheus
+// handler, under /metrics.
+func WithAdminMetrics(h http.Handler) AdminOption {
+	return func(s *AdminServer) { s.mux.Handle("GET", "/metrics", h.ServeHTTP) }
+}
+
+// WithAdminReload calls reload on POST /reload, e.g. to reload the
+// configuration. The errors returned by reload are written in 500 Internal
+// Server Error responses.
+func WithAdminReload(reload func() error) AdminOption {
+	return func(s *AdminServer) {
+		s.mux.Handle("POST", "/reload", func(w http.ResponseWriter, _ *http.Request) {
+			if err := reload(); err != nil {
+				http.Error(w, err.Error(), http.StatusInternalServerError)
+				return
+			}
+			w.WriteHeader(http.StatusNoContent)
+		})
+	}
+}
+
+// WithAdminDebug mounts the debug handlers configured with the given options,
+// see MountDebug.
+func WithAdminDebug(opts ...DebugOption) AdminOption {
+	return func(s *AdminServer) { MountDebug(s.mux, opts...) }
+}
+
+// AddHealthCheck adds a readiness check with the given name replacing any
+// check with the same name. The check must return an error if the service
+// cannot serve requests, e.g. because a dependency is unreachable. The checks
+// are given a context with a deadline of 5 seconds.
+func (s *AdminServer) AddHealthCheck(name string, check func(context.Context) error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.checks[name] = check
+}
+
+// Handle mounts an additional handler on the admin server.
+func (s *AdminServer) Handle(method, pattern string, h http.HandlerFunc) {
+	s.mux.Handle(method, pattern, h)
+}
+
+// Check runs the readiness checks concurrently and returns their status.
+func (s *AdminServer) Check(ctx context.Context) *HealthStatus {
+	s.mu.RLock()
+	names := make([]string, 0, len(s.checks))
+	checks := make([]func(context.Context) error, 0, len(s.checks))
+	for name, check := range s.checks {
+		names = append(names, name)
+		checks = append(checks, check)
+	}
+	s.mu.RUnlock()
+
+	ctx, cancel := context.WithTimeout(ctx, healthCheckTimeout)
+	defer cancel()
+	errs := make([]error, len(checks))
+	var wg sync.WaitGroup
+	for i, check := range checks {
+		wg.Add(1)
+		go func(i int, check func(context.Context) error) {
+			defer wg.Done()
+			errs[i] = check(ctx)
+		}(i, check)
+	}
+	wg.Wait()
+
+	status := &HealthStatus{Status: "ok"}
+	if len(names) > 0 {
+		status.Checks = make(map[string]string, len(names))
+	}
+	for i, name := range names {
+		if errs[i] != nil {
+			status.Status = "unavailable"
+			status.Checks[name] = errs[i].Error()
+			continue
+		}
+		status.Checks[name] = "ok"
+	}
+	return status
+}
+
+// ServeHTTP serves the admin endpoints.
+func (s *AdminServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.mux.ServeHTTP(w, r)
+}
+
+// Run serves the admin endpoints until ctx is done, see Run.
+func (s *AdminServer) Run(ctx context.Context, opts ...RunOption) error {
+	srv := &http.Server{Addr: s.Addr, Handler: s, ReadHeaderTimeout: 10 * time.Second}
+	return Run(ctx, srv, opts...)
+}
+
+// serveReady runs the readiness checks and writes their status.
+func (s *AdminServer) serveReady(w http.ResponseWriter, r *http.Request) {
+	status := s.Check(r.Context())
+	code := http.StatusOK
+	if status.Status != "ok" {
+		code = http.StatusServiceUnavailable
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Cache-Control", "no-store")
+	w.WriteHeader(code)
+	json.NewEncoder(w).Encode(status) // nolint: errcheck
+}
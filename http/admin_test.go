@@ -0,0 +1,65 @@
+package http
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestAdminServer(t *testing.T) {
+	reloaded := false
+	s := NewAdminServer(":0",
+		WithHealthCheck("db", func(context.Context) error { return nil }),
+		WithAdminMetrics(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+			w.Write([]byte("requests_total 1")) // nolint: errcheck
+		})),
+		WithAdminReload(func() error { reloaded = true; return nil }))
+
+	cases := []struct {
+		Name     string
+		Method   string
+		Path     string
+		Setup    func()
+		Status   int
+		Contains string
+	}{
+		{"live", "GET", "/livez", nil, http.StatusOK, "ok"},
+		{"ready", "GET", "/readyz", nil, http.StatusOK, `"db":"ok"`},
+		{"metrics", "GET", "/metrics", nil, http.StatusOK, "requests_total"},
+		{"reload", "POST", "/reload", nil, http.StatusNoContent, ""},
+		{"not ready", "GET", "/healthz", func() {
+			s.AddHealthCheck("cache", func(context.Context) error { return errors.New("cold") })
+		}, http.StatusServiceUnavailable, `"cache":"cold"`},
+	}
+	for _, c := range cases {
+		t.Run(c.Name, func(t *testing.T) {
+			if c.Setup != nil {
+				c.Setup()
+			}
+			w := httptest.NewRecorder()
+			s.ServeHTTP(w, httptest.NewRequest(c.Method, c.Path, nil))
+			if w.Code != c.Status {
+				t.Errorf("got status %d, expected %d", w.Code, c.Status)
+			}
+			if !strings.Contains(w.Body.String(), c.Contains) {
+				t.Errorf("got body %q, expected it to contain %q", w.Body.String(), c.Contains)
+			}
+		})
+	}
+	if !reloaded {
+		t.Error("expected reload to be called")
+	}
+	var status HealthStatus
+	w := httptest.NewRecorder()
+	s.ServeHTTP(w, httptest.NewRequest("GET", "/readyz", nil))
+	if err := json.Unmarshal(w.Body.Bytes(), &status); err != nil {
+		t.Fatal(err)
+	}
+	if status.Status != "unavailable" || len(status.Checks) != 2 {
+		t.Errorf("got status %+v, expected unavailable with 2 checks", status)
+	}
+}
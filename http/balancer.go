@@ -0,0 +1,359 @@
+package http
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+type (
+	// Resolver resolves the addresses ("host:port") of the instances of a
+	// service. Implementations must be safe for concurrent use.
+	Resolver interface {
+		// Resolve returns the addresses of the instances.
+		Resolve(ctx context.Context) ([]string, error)
+	}
+
+	// ResolverFunc is an adapter that makes it possible to use a function
+	// as Resolver.
+	ResolverFunc func(ctx context.Context) ([]string, error)
+
+	// BalanceStrategy is the strategy used by BalancedDoer to pick the host
+	// a request is sent to.
+	BalanceStrategy int
+
+	// BalancedDoer is a Doer that spreads the requests across the instances
+	// of a service returned by a Resolver so that the generated clients can
+	// talk to clustered services without an external load balancer. Hosts
+	// that fail repeatedly are ejected for a while. Use NewBalancedDoer to
+	// wrap the Doer given to the generated client constructors.
+	// BalancedDoer is safe for concurrent use.
+	BalancedDoer struct {
+		doer            Doer
+		resolver        Resolver
+		strategy        BalanceStrategy
+		resolveInterval time.Duration
+		maxFailures     int
+		ejection        time.Duration
+		now             func() time.Time
+
+		resolveMu sync.Mutex
+		mu        sync.Mutex
+		hosts     []*balancedHost
+		resolved  time.Time
+		resolving bool
+		next      int
+	}
+
+	// BalancerOption configures a BalancedDoer.
+	BalancerOption func(*BalancedDoer)
+
+	// balancedHost is a host tracked by BalancedDoer.
+	balancedHost struct {
+		addr string
+		// pending is the number of requests in flight.
+		pending int
+		// failures is the number of consecutive failed requests.
+		failures int
+		// ejectedUntil is the time until which the host is not used.
+		ejectedUntil time.Time
+	}
+
+	// consulServiceEntry is an entry of the response of the Consul health
+	// service endpoint.
+	consulServiceEntry struct {
+		Node struct {
+			Address string
+		}
+		Service struct {
+			Address string
+			Port    int
+		}
+	}
+)
+
+const (
+	// RoundRobin sends the requests to each host in turn.
+	RoundRobin BalanceStrategy = iota
+	// LeastPending sends the requests to the host with the fewest requests
+	// in flight.
+	LeastPending
+)
+
+// ErrNoHost is returned by BalancedDoer when the resolver returns no host.
+var ErrNoHost = errors.New("no host available")
+
+// NewBalancedDoer returns a Doer that makes the requests with d, sending each
+// request to one of the hosts returned by r. The hosts are resolved again
+// every 30 seconds in the background and the requests are spread using the
+// RoundRobin strategy unless configured otherwise. Hosts are ejected for 30
+// seconds after 3 consecutive failures, a failure being a transport error or a
+// 502, 503 or 504 response. Ejected hosts are used anyway when all the hosts
+// are ejected.
+//
+// The host given to the generated client constructor is replaced by the host
+// picked by the balancer.
+//
+// Example:
+//
+//	r := goahttp.StaticResolver("10.0.0.1:8080", "10.0.0.2:8080")
+//	doer := goahttp.NewBalancedDoer(http.DefaultClient, r, goahttp.WithBalanceStrategy(goahttp.LeastPending))
+//	client := catalogc.NewClient("http", "catalog", doer, enc, dec, false)
+func NewBalancedDoer(d Doer, r Resolver, opts ...BalancerOption) *BalancedDoer {
+	b := &BalancedDoer{
+		doer:            d,
+		resolver:        r,
+		strategy:        RoundRobin,
+		resolveInterval: 30 * time.Second,
+		maxFailures:     3,
+		ejection:        30 * time.Second,
+		now:             time.Now,
+	}
+	for _, opt := range opts {
+		opt(b)
+	}
+	return b
+}
+
+// WithBalanceStrategy sets the strategy used to pick the hosts.
+func WithBalanceStrategy(s BalanceStrategy) BalancerOption {
+	return func(b *BalancedDoer) { b.strategy = s }
+}
+
+// WithResolveInterval sets the interval at which the hosts are resolved again.
+// A value of 0 resolves the hosts only once.
+func WithResolveInterval(d time.Duration) BalancerOption {
+	return func(b *BalancedDoer) { b.resolveInterval = d }
+}
+
+// WithHostEjection ejects the hosts for duration d after maxFailures
+// consecutive failures. A value of 0 for maxFailures disables ejection.
+func WithHostEjection(maxFailures int, d time.Duration) BalancerOption {
+	return func(b *BalancedDoer) {
+		b.maxFailures = maxFailures
+		b.ejection = d
+	}
+}
+
+// Do sends the request to the host picked by the balancing strategy.
+func (b *BalancedDoer) Do(req *http.Request) (*http.Response, error) {
+	if err := b.resolve(req.Context()); err != nil {
+		return nil, err
+	}
+	host := b.pick()
+	if host == nil {
+		return nil, ErrNoHost
+	}
+	r := req.Clone(req.Context())
+	r.URL.Host = host.addr
+	r.Host = ""
+	resp, err := b.doer.Do(r)
+	b.done(host, err != nil || isHostFailure(resp.StatusCode))
+	return resp, err
+}
+
+// Hosts returns the addresses of the hosts currently in use, that is that are
+// not ejected.
+func (b *BalancedDoer) Hosts() []string {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	now := b.now()
+	var addrs []string
+	for _, h := range b.hosts {
+		if !now.Before(h.ejectedUntil) {
+			addrs = append(addrs, h.addr)
+		}
+	}
+	return addrs
+}
+
+// resolve resolves the hosts if they have not been resolved yet and refreshes
+// them in the background if they are stale.
+func (b *BalancedDoer) resolve(ctx context.Context) error {
+	b.mu.Lock()
+	empty := len(b.hosts) == 0
+	stale := !empty && b.resolveInterval > 0 && b.now().Sub(b.resolved) >= b.resolveInterval && !b.resolving
+	if stale {
+		b.resolving = true
+	}
+	b.mu.Unlock()
+	if stale {
+		go func() {
+			b.refresh(context.Background()) // nolint: errcheck
+			b.mu.Lock()
+			b.resolving = false
+			b.mu.Unlock()
+		}()
+	}
+	if !empty {
+		return nil
+	}
+	b.resolveMu.Lock()
+	defer b.resolveMu.Unlock()
+	b.mu.Lock()
+	empty = len(b.hosts) == 0
+	b.mu.Unlock()
+	if !empty {
+		return nil
+	}
+	return b.refresh(ctx)
+}
+
+// refresh resolves the hosts, keeping the state of the hosts that are still
+// returned by the resolver. The current hosts are kept if the resolver fails.
+func (b *BalancedDoer) refresh(ctx context.Context) error {
+	addrs, err := b.resolver.Resolve(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to resolve hosts: %w", err)
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	current := make(map[string]*balancedHost, len(b.hosts))
+	for _, h := range b.hosts {
+		current[h.addr] = h
+	}
+	hosts := make([]*balancedHost, 0, len(addrs))
+	for _, addr := range addrs {
+		h, ok := current[addr]
+		if !ok {
+			h = &balancedHost{addr: addr}
+		}
+		hosts = append(hosts, h)
+	}
+	b.hosts = hosts
+	b.resolved = b.now()
+	return nil
+}
+
+// pick returns the host the next request is sent to, nil if there is none.
+func (b *BalancedDoer) pick() *balancedHost {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	n := len(b.hosts)
+	if n == 0 {
+		return nil
+	}
+	now := b.now()
+	var picked *balancedHost
+	for _, ignoreEjection := range []bool{false, true} {
+		for i := 0; i < n; i++ {
+			h := b.hosts[(b.next+i)%n]
+			if !ignoreEjection && now.Before(h.ejectedUntil) {
+				continue
+			}
+			if picked == nil || b.strategy == LeastPending && h.pending < picked.pending {
+				picked = h
+			}
+			if b.strategy == RoundRobin {
+				break
+			}
+		}
+		if picked != nil {
+			break
+		}
+	}
+	b.next = (b.next + 1) % n
+	picked.pending++
+	return picked
+}
+
+// done records the outcome of a request sent to h.
+func (b *BalancedDoer) done(h *balancedHost, failed bool) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	h.pending--
+	if !failed {
+		h.failures = 0
+		return
+	}
+	h.failures++
+	if b.maxFailures > 0 && h.failures >= b.maxFailures {
+		h.failures = 0
+		h.ejectedUntil = b.now().Add(b.ejection)
+	}
+}
+
+// Resolve calls f(ctx).
+func (f ResolverFunc) Resolve(ctx context.Context) ([]string, error) {
+	return f(ctx)
+}
+
+// StaticResolver returns a resolver that always returns the given addresses.
+func StaticResolver(addrs ...string) Resolver {
+	return ResolverFunc(func(context.Context) ([]string, error) {
+		return addrs, nil
+	})
+}
+
+// SRVResolver returns a resolver that looks up the DNS SRV records of the
+// given service, protocol and domain name (e.g. "http", "tcp" and
+// "catalog.service.consul"). Only the targets with the highest priority (the
+// lowest priority value) are returned.
+func SRVResolver(service, proto, name string) Resolver {
+	return ResolverFunc(func(ctx context.Context) ([]string, error) {
+		_, srvs, err := net.DefaultResolver.LookupSRV(ctx, service, proto, name)
+		if err != nil {
+			return nil, err
+		}
+		var addrs []string
+		for _, srv := range srvs {
+			if srv.Priority != srvs[0].Priority {
+				break // LookupSRV sorts the records by priority
+			}
+			addrs = append(addrs, net.JoinHostPort(strings.TrimSuffix(srv.Target, "."), strconv.Itoa(int(srv.Port))))
+		}
+		return addrs, nil
+	})
+}
+
+// ConsulResolver returns a resolver that lists the healthy instances of the
+// given service using the health endpoint of the Consul agent at addr (e.g.
+// "http://localhost:8500"). d is used to make the requests to the agent,
+// http.DefaultClient if nil.
+func ConsulResolver(addr, service string, d Doer) Resolver {
+	if d == nil {
+		d = http.DefaultClient
+	}
+	u := strings.TrimSuffix(addr, "/") + "/v1/health/service/" + url.PathEscape(service) + "?passing=true"
+	return ResolverFunc(func(ctx context.Context) ([]string, error) {
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, u, nil)
+		if err != nil {
+			return nil, err
+		}
+		resp, err := d.Do(req)
+		if err != nil {
+			return nil, err
+		}
+		defer resp.Body.Close()
+		if resp.StatusCode != http.StatusOK {
+			return nil, fmt.Errorf("consul: unexpected status %d", resp.StatusCode)
+		}
+		var entries []*consulServiceEntry
+		if err := json.NewDecoder(resp.Body).Decode(&entries); err != nil {
+			return nil, fmt.Errorf("consul: %w", err)
+		}
+		addrs := make([]string, 0, len(entries))
+		for _, e := range entries {
+			host := e.Service.Address
+			if host == "" {
+				host = e.Node.Address
+			}
+			addrs = append(addrs, net.JoinHostPort(host, strconv.Itoa(e.Service.Port)))
+		}
+		return addrs, nil
+	})
+}
+
+// isHostFailure returns true if a response with the given status code
+// indicates that the host is unhealthy.
+func isHostFailure(status int) bool {
+	return status == http.StatusBadGateway || status == http.StatusServiceUnavailable || status == http.StatusGatewayTimeout
+}
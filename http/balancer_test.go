@@ -0,0 +1,112 @@
+package http
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+	"time"
+)
+
+type hostDoer struct {
+	hosts  []string
+	status map[string]int
+}
+
+func (d *hostDoer) Do(req *http.Request) (*http.Response, error) {
+	d.hosts = append(d.hosts, req.URL.Host)
+	status, ok := d.status[req.URL.Host]
+	if !ok {
+		status = http.StatusOK
+	}
+	if status == 0 {
+		return nil, errors.New("connection refused")
+	}
+	return &http.Response{StatusCode: status, Body: http.NoBody}, nil
+}
+
+func TestBalancedDoerRoundRobin(t *testing.T) {
+	d := &hostDoer{status: map[string]int{"b:80": http.StatusServiceUnavailable}}
+	now := time.Now()
+	b := NewBalancedDoer(d, StaticResolver("a:80", "b:80", "c:80"), WithHostEjection(2, time.Minute))
+	b.now = func() time.Time { return now }
+	for i := 0; i < 7; i++ {
+		req, _ := http.NewRequest("GET", "http://svc/items", nil)
+		if _, err := b.Do(req); err != nil {
+			t.Fatal(err)
+		}
+		if req.URL.Host != "svc" {
+			t.Fatalf("request was modified: got host %q", req.URL.Host)
+		}
+	}
+	expected := []string{"a:80", "b:80", "c:80", "a:80", "b:80", "c:80", "a:80"}
+	if !reflect.DeepEqual(d.hosts, expected) {
+		t.Errorf("got hosts %v, expected %v", d.hosts, expected)
+	}
+	if hosts := b.Hosts(); !reflect.DeepEqual(hosts, []string{"a:80", "c:80"}) {
+		t.Errorf("got active hosts %v, expected b:80 to be ejected", hosts)
+	}
+
+	now = now.Add(time.Minute)
+	if hosts := b.Hosts(); len(hosts) != 3 {
+		t.Errorf("got active hosts %v, expected b:80 to be restored", hosts)
+	}
+}
+
+func TestBalancedDoerLeastPending(t *testing.T) {
+	b := NewBalancedDoer(&hostDoer{}, StaticResolver("a:80", "b:80", "c:80"), WithBalanceStrategy(LeastPending))
+	if err := b.resolve(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	first, second := b.pick(), b.pick()
+	b.done(first, false)
+	if third := b.pick(); third.addr != "c:80" {
+		t.Errorf("got %q, expected the host without pending requests", third.addr)
+	}
+	if fourth := b.pick(); fourth.addr != first.addr {
+		t.Errorf("got %q, expected %q", fourth.addr, first.addr)
+	}
+	b.done(second, false)
+}
+
+func TestBalancedDoerAllEjected(t *testing.T) {
+	d := &hostDoer{status: map[string]int{"a:80": 0}}
+	b := NewBalancedDoer(d, StaticResolver("a:80"), WithHostEjection(1, time.Minute))
+	for i := 0; i < 2; i++ {
+		req, _ := http.NewRequest("GET", "http://svc/", nil)
+		if _, err := b.Do(req); err == nil {
+			t.Fatal("expected an error")
+		}
+	}
+	if len(d.hosts) != 2 {
+		t.Errorf("got %d requests, expected the ejected host to be used", len(d.hosts))
+	}
+	b = NewBalancedDoer(d, StaticResolver())
+	req, _ := http.NewRequest("GET", "http://svc/", nil)
+	if _, err := b.Do(req); !errors.Is(err, ErrNoHost) {
+		t.Errorf("got error %v, expected ErrNoHost", err)
+	}
+}
+
+func TestConsulResolver(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/v1/health/service/catalog" || r.URL.Query().Get("passing") != "true" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		w.Write([]byte(`[{"Node":{"Address":"10.0.0.1"},"Service":{"Address":"","Port":8080}},{"Node":{"Address":"10.0.0.2"},"Service":{"Address":"10.0.1.2","Port":8081}}]`)) // nolint: errcheck
+	}))
+	defer srv.Close()
+	addrs, err := ConsulResolver(srv.URL, "catalog", nil).Resolve(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if expected := []string{"10.0.0.1:8080", "10.0.1.2:8081"}; !reflect.DeepEqual(addrs, expected) {
+		t.Errorf("got %v, expected %v", addrs, expected)
+	}
+	if _, err := ConsulResolver(srv.URL, "unknown", nil).Resolve(context.Background()); err == nil {
+		t.Error("expected an error")
+	}
+}
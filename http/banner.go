@@ -0,0 +1,97 @@
+package http
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"text/tabwriter"
+)
+
+// Banner describes a server for the startup banner printed by Run when
+// configured with WithBanner or WithBannerJSON.
+type Banner struct {
+	// Name is the name of the server, e.g. the name of the API.
+	Name string `json:"name,omitempty"`
+	// Addresses are the URLs the server listens on. Run sets Addresses
+	// once the server listens.
+	Addresses []string `json:"addresses"`
+	// Routes are the routes mounted on the server, e.g. the routes
+	// returned by the Routes method of Servers.
+	Routes RouteTable `json:"routes"`
+	// Middlewares are the names of the middlewares enabled on the server
+	// in the order they are applied.
+	Middlewares []string `json:"middlewares,omitempty"`
+}
+
+// WithBanner makes Run print the banner to w once the server listens. The
+// banner lists the listening addresses, the middlewares and the routes
+// formatted as a table.
+//
+// Example:
+//
+//	banner := &goahttp.Banner{
+//		Name:        "calc",
+//		Routes:      goahttp.Servers{calcServer}.Routes(),
+//		Middlewares: []string{"RequestID", "Log"},
+//	}
+//	err := goahttp.Run(ctx, srv, goahttp.WithBanner(os.Stderr, banner))
+func WithBanner(w io.Writer, b *Banner) RunOption {
+	return func(o *runOptions) {
+		o.bannerW, o.banner, o.bannerJSON = w, b, false
+	}
+}
+
+// WithBannerJSON makes Run print the banner to w as a single line JSON object
+// once the server listens so that it can be consumed by tools.
+func WithBannerJSON(w io.Writer, b *Banner) RunOption {
+	return func(o *runOptions) {
+		o.bannerW, o.banner, o.bannerJSON = w, b, true
+	}
+}
+
+// String returns the banner formatted for humans, the routes are sorted by
+// pattern and verb.
+func (b *Banner) String() string {
+	var s strings.Builder
+	name := b.Name
+	if name == "" {
+		name = "server"
+	}
+	fmt.Fprintf(&s, "%s listening on %s\n", name, strings.Join(b.Addresses, ", "))
+	if len(b.Middlewares) > 0 {
+		fmt.Fprintf(&s, "middlewares: %s\n", strings.Join(b.Middlewares, ", "))
+	}
+	if len(b.Routes) == 0 {
+		return s.String()
+	}
+	routes := make(RouteTable, len(b.Routes))
+	copy(routes, b.Routes)
+	sort.SliceStable(routes, func(i, j int) bool {
+		if routes[i].Pattern != routes[j].Pattern {
+			return routes[i].Pattern < routes[j].Pattern
+		}
+		return routes[i].Verb < routes[j].Verb
+	})
+	tw := tabwriter.NewWriter(&s, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "METHOD\tPATH\tSERVICE\tENDPOINT")
+	for _, r := range routes {
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%s\n", r.Verb, r.Pattern, r.Service, r.Method)
+	}
+	tw.Flush() // nolint: errcheck
+	return s.String()
+}
+
+// print writes the banner to w as text or JSON.
+func (b *Banner) print(w io.Writer, asJSON bool) {
+	if !asJSON {
+		io.WriteString(w, b.String()) // nolint: errcheck
+		return
+	}
+	js, err := json.Marshal(b)
+	if err != nil {
+		return
+	}
+	w.Write(append(js, '\n')) // nolint: errcheck
+}
@@ -0,0 +1,60 @@
+package http
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net"
+	"net/http"
+	"testing"
+	"time"
+)
+
+func TestBannerString(t *testing.T) {
+	b := &Banner{
+		Name:        "calc",
+		Addresses:   []string{"http://127.0.0.1:8080"},
+		Middlewares: []string{"RequestID", "Log"},
+		Routes: RouteTable{
+			{Service: "calc", Method: "div", Verb: "GET", Pattern: "/div/{a}/{b}"},
+			{Service: "calc", Method: "add", Verb: "POST", Pattern: "/add"},
+		},
+	}
+	expected := `calc listening on http://127.0.0.1:8080
+middlewares: RequestID, Log
+METHOD  PATH          SERVICE  ENDPOINT
+POST    /add          calc     add
+GET     /div/{a}/{b}  calc     div
+`
+	if got := b.String(); got != expected {
+		t.Errorf("got\n%s\nexpected\n%s", got, expected)
+	}
+}
+
+func TestRunBannerJSON(t *testing.T) {
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var buf bytes.Buffer
+	b := &Banner{Name: "calc", Routes: RouteTable{{Service: "calc", Method: "add", Verb: "GET", Pattern: "/add"}}}
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	srv := &http.Server{Handler: http.NotFoundHandler(), ReadHeaderTimeout: time.Second}
+	if err := Run(ctx, srv, WithListener(l), WithBannerJSON(&buf, b)); err != nil {
+		t.Fatal(err)
+	}
+	var got Banner
+	if err := json.Unmarshal(buf.Bytes(), &got); err != nil {
+		t.Fatalf("invalid JSON banner %q: %s", buf.String(), err)
+	}
+	if len(got.Addresses) != 1 || got.Addresses[0] != "http://"+l.Addr().String() {
+		t.Errorf("got addresses %v, expected [http://%s]", got.Addresses, l.Addr())
+	}
+	if len(got.Routes) != 1 || got.Routes[0].Pattern != "/add" {
+		t.Errorf("got routes %v, expected the /add route", got.Routes)
+	}
+	if b.Addresses != nil {
+		t.Error("expected the given banner to be left unchanged")
+	}
+}
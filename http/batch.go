@@ -0,0 +1,217 @@
+package http
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+
+	goa "goa.design/goa/v3/pkg"
+)
+
+type (
+	// BatchItem is an element of the body of a multi-status (207) response
+	// to a batch request. It describes the response to the element of the
+	// batch request with the same index.
+	BatchItem struct {
+		// Status is the HTTP status code of the element response.
+		Status int `json:"status"`
+		// Headers lists the headers of the element response.
+		Headers http.Header `json:"headers,omitempty"`
+		// Body is the body of the element response if any.
+		Body json.RawMessage `json:"body,omitempty"`
+	}
+
+	// BatchResult is the result of an element of a batch request as
+	// returned by the generated client batch endpoints.
+	BatchResult struct {
+		// Result is the method result, nil if Err is not nil.
+		Result any
+		// Err is the error returned by the element request if any.
+		Err error
+	}
+
+	// batchRecorder is the response writer used to record the response to an
+	// element of a batch request.
+	batchRecorder struct {
+		header http.Header
+		status int
+		body   bytes.Buffer
+	}
+)
+
+// NewBatchHandler returns a HTTP handler that serves batch requests made to
+// the endpoint served by h. The body of a batch request is a JSON array, the
+// handler calls h concurrently once per element using a copy of the batch
+// request whose body is the element. The handler responds with a multi-status
+// (207) response whose body lists the status, headers and body of each
+// element response as a BatchItem in the order of the request elements so
+// that elements may fail independently. The generated HTTP servers use
+// NewBatchHandler to serve the endpoints that use the Batch DSL.
+func NewBatchHandler(h http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var bodies []json.RawMessage
+		if err := json.NewDecoder(r.Body).Decode(&bodies); err != nil {
+			resp := NewErrorResponse(r.Context(), goa.DecodePayloadError(err.Error()))
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(resp.StatusCode())
+			_ = json.NewEncoder(w).Encode(resp)
+			return
+		}
+		items := make([]*BatchItem, len(bodies))
+		var wg sync.WaitGroup
+		for i, body := range bodies {
+			wg.Add(1)
+			go func(i int, body json.RawMessage) {
+				defer wg.Done()
+				rec := &batchRecorder{header: make(http.Header)}
+				defer func() {
+					if p := recover(); p != nil {
+						items[i] = &BatchItem{Status: http.StatusInternalServerError}
+						return
+					}
+					items[i] = rec.item()
+				}()
+				req := r.Clone(r.Context())
+				req.Body = io.NopCloser(bytes.NewReader(body))
+				req.ContentLength = int64(len(body))
+				req.Header.Set("Content-Type", "application/json")
+				req.Header.Set("Accept", "application/json")
+				h.ServeHTTP(rec, req)
+			}(i, body)
+		}
+		wg.Wait()
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusMultiStatus)
+		_ = json.NewEncoder(w).Encode(items)
+	})
+}
+
+// NewBatchRequest returns a batch request that combines the given requests
+// made to the same endpoint. The URL of the batch request is the URL of the
+// first request with the path suffixed with suffix and its headers are the
+// headers of the first request. The body is the JSON array of the bodies of
+// the requests which must be JSON (or empty). The generated HTTP clients use
+// NewBatchRequest to build the requests made to the batch endpoints.
+func NewBatchRequest(reqs []*http.Request, suffix string) (*http.Request, error) {
+	if len(reqs) == 0 {
+		return nil, errors.New("batch request must contain at least one element")
+	}
+	bodies := make([]json.RawMessage, len(reqs))
+	for i, req := range reqs {
+		bodies[i] = json.RawMessage("null")
+		if req.Body == nil {
+			continue
+		}
+		b, err := io.ReadAll(req.Body)
+		req.Body.Close()
+		if err != nil {
+			return nil, err
+		}
+		if len(b) > 0 {
+			if !json.Valid(b) {
+				return nil, fmt.Errorf("batch request element %d: body is not JSON", i)
+			}
+			bodies[i] = b
+		}
+	}
+	body, err := json.Marshal(bodies)
+	if err != nil {
+		return nil, err
+	}
+	first := reqs[0]
+	u := *first.URL
+	u.Path = strings.TrimSuffix(u.Path, "/") + suffix
+	u.RawPath = ""
+	req, err := http.NewRequestWithContext(first.Context(), http.MethodPost, u.String(), bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	req.Header = first.Header.Clone()
+	req.Header.Set("Content-Type", "application/json")
+	return req, nil
+}
+
+// DecodeBatchResponse decodes the multi-status response to a batch request.
+// It calls decode with a response built from each element of the response
+// body and returns the results in order. DecodeBatchResponse returns the
+// error returned by decode when called with resp if the response status is
+// not 207 (e.g. because the batch request body could not be decoded).
+func DecodeBatchResponse(resp *http.Response, decode func(*http.Response) (any, error)) ([]*BatchResult, error) {
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusMultiStatus {
+		if _, err := decode(resp); err != nil {
+			return nil, err
+		}
+		return nil, fmt.Errorf("unexpected status code %d for batch request", resp.StatusCode)
+	}
+	var items []*BatchItem
+	if err := json.NewDecoder(resp.Body).Decode(&items); err != nil {
+		return nil, err
+	}
+	results := make([]*BatchResult, len(items))
+	for i, item := range items {
+		header := item.Headers.Clone()
+		if header == nil {
+			header = make(http.Header)
+		}
+		if len(item.Body) > 0 && header.Get("Content-Type") == "" {
+			header.Set("Content-Type", "application/json")
+		}
+		res, err := decode(&http.Response{
+			Status:        fmt.Sprintf("%d %s", item.Status, http.StatusText(item.Status)),
+			StatusCode:    item.Status,
+			Proto:         resp.Proto,
+			ProtoMajor:    resp.ProtoMajor,
+			ProtoMinor:    resp.ProtoMinor,
+			Header:        header,
+			Body:          io.NopCloser(bytes.NewReader(item.Body)),
+			ContentLength: int64(len(item.Body)),
+			Request:       resp.Request,
+		})
+		results[i] = &BatchResult{Result: res, Err: err}
+	}
+	return results, nil
+}
+
+// Header returns the response headers.
+func (r *batchRecorder) Header() http.Header {
+	return r.header
+}
+
+// WriteHeader records the response status code.
+func (r *batchRecorder) WriteHeader(status int) {
+	if r.status == 0 {
+		r.status = status
+	}
+}
+
+// Write records the response body.
+func (r *batchRecorder) Write(b []byte) (int, error) {
+	r.WriteHeader(http.StatusOK)
+	return r.body.Write(b)
+}
+
+// item returns the recorded response.
+func (r *batchRecorder) item() *BatchItem {
+	item := &BatchItem{Status: r.status}
+	if item.Status == 0 {
+		item.Status = http.StatusOK
+	}
+	if len(r.header) > 0 {
+		item.Headers = r.header
+		item.Headers.Del("Content-Length")
+	}
+	if body := bytes.TrimSpace(r.body.Bytes()); len(body) > 0 {
+		if json.Valid(body) {
+			item.Body = body
+		} else {
+			item.Body, _ = json.Marshal(string(body))
+		}
+	}
+	return item
+}
@@ -0,0 +1,102 @@
+package http
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestBatchHandler(t *testing.T) {
+	h := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var v struct {
+			Name string `json:"name"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&v); err != nil || v.Name == "" {
+			w.WriteHeader(http.StatusBadRequest)
+			w.Write([]byte("invalid"))
+			return
+		}
+		if v.Name == "panic" {
+			panic("boom")
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.Header().Set("X-Query", r.URL.Query().Get("q"))
+		w.WriteHeader(http.StatusCreated)
+		w.Write([]byte(`{"name":"` + v.Name + `"}`))
+	})
+
+	reqs := make([]*http.Request, 4)
+	for i, body := range []string{`{"name":"a"}`, `{}`, `{"name":"panic"}`, ``} {
+		reqs[i] = httptest.NewRequest("POST", "http://localhost/bottles/?q=x", strings.NewReader(body))
+	}
+	req, err := NewBatchRequest(reqs, "/batch")
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if req.URL.Path != "/bottles/batch" || req.URL.RawQuery != "q=x" {
+		t.Errorf("got URL %q, expected %q", req.URL, "http://localhost/bottles/batch?q=x")
+	}
+	body, _ := io.ReadAll(req.Body)
+	if got, want := string(body), `[{"name":"a"},{},{"name":"panic"},null]`; got != want {
+		t.Errorf("got body %s, expected %s", got, want)
+	}
+	req.Body = io.NopCloser(bytes.NewReader(body))
+
+	w := httptest.NewRecorder()
+	NewBatchHandler(h).ServeHTTP(w, req)
+	if w.Code != http.StatusMultiStatus {
+		t.Fatalf("got status %d, expected %d", w.Code, http.StatusMultiStatus)
+	}
+
+	decode := func(resp *http.Response) (any, error) {
+		b, _ := io.ReadAll(resp.Body)
+		if resp.StatusCode != http.StatusCreated {
+			return nil, errors.New(string(b))
+		}
+		return resp.Header.Get("X-Query") + string(b), nil
+	}
+	results, err := DecodeBatchResponse(w.Result(), decode)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if len(results) != 4 {
+		t.Fatalf("got %d results, expected 4", len(results))
+	}
+	if results[0].Err != nil || results[0].Result != `x{"name":"a"}` {
+		t.Errorf("got result %v and error %v for first element", results[0].Result, results[0].Err)
+	}
+	if results[1].Err == nil || results[1].Err.Error() != `"invalid"` {
+		t.Errorf("got error %v for second element, expected %q", results[1].Err, `"invalid"`)
+	}
+	for _, i := range []int{2, 3} {
+		if results[i].Err == nil {
+			t.Errorf("expected an error for element %d", i)
+		}
+	}
+}
+
+func TestBatchHandlerInvalidBody(t *testing.T) {
+	h := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		t.Error("unexpected call to handler")
+	})
+	w := httptest.NewRecorder()
+	NewBatchHandler(h).ServeHTTP(w, httptest.NewRequest("POST", "/", strings.NewReader(`{"name":"a"}`)))
+	if w.Code != http.StatusBadRequest {
+		t.Errorf("got status %d, expected %d", w.Code, http.StatusBadRequest)
+	}
+}
+
+func TestNewBatchRequestErrors(t *testing.T) {
+	if _, err := NewBatchRequest(nil, "/batch"); err == nil {
+		t.Error("expected an error for an empty batch")
+	}
+	req := httptest.NewRequest("POST", "/", strings.NewReader("<xml/>"))
+	if _, err := NewBatchRequest([]*http.Request{req}, "/batch"); err == nil {
+		t.Error("expected an error for a non JSON body")
+	}
+}
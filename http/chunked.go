@@ -0,0 +1,63 @@
+package http
+
+import (
+	"encoding/json"
+	"net/http"
+	"reflect"
+)
+
+// DefaultChunkSize is the default number of array elements written by
+// EncodeChunked before flushing the response.
+const DefaultChunkSize = 100
+
+// EncodeChunked writes v, a slice or an array, to w. If enc is a JSON encoder
+// EncodeChunked writes the JSON array incrementally: it encodes the elements
+// one by one and flushes the response every chunkSize elements so that the
+// first bytes reach the client before the whole array is encoded. The response
+// is sent using the chunked transfer encoding as it does not have a
+// Content-Length header. Elements that implement JSONAppender are encoded
+// with their AppendJSON method. EncodeChunked uses enc to encode v as a whole
+// if enc is not a JSON encoder (e.g. because the client negotiated XML,
+// requested a JSON:API document or selected fields) or if v is not a slice.
+// The generated response encoders of the endpoints that use the ChunkedResult
+// DSL call EncodeChunked.
+func EncodeChunked(w http.ResponseWriter, enc Encoder, v any, chunkSize int) error {
+	if _, ok := enc.(*json.Encoder); !ok {
+		return enc.Encode(v)
+	}
+	rv := reflect.ValueOf(v)
+	if rv.Kind() != reflect.Slice && rv.Kind() != reflect.Array {
+		return enc.Encode(v)
+	}
+	if rv.Kind() == reflect.Slice && rv.IsNil() {
+		_, err := w.Write([]byte("null\n"))
+		return err
+	}
+	if chunkSize <= 0 {
+		chunkSize = DefaultChunkSize
+	}
+	flusher, _ := w.(http.Flusher)
+	buf := make([]byte, 0, 1024)
+	buf = append(buf, '[')
+	for i := 0; i < rv.Len(); i++ {
+		if i > 0 {
+			buf = append(buf, ',')
+		}
+		var err error
+		if buf, err = AppendJSON(buf, rv.Index(i).Interface()); err != nil {
+			return err
+		}
+		if (i+1)%chunkSize == 0 {
+			if _, err := w.Write(buf); err != nil {
+				return err
+			}
+			buf = buf[:0]
+			if flusher != nil {
+				flusher.Flush()
+			}
+		}
+	}
+	buf = append(buf, ']', '\n')
+	_, err := w.Write(buf)
+	return err
+}
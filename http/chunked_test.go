@@ -0,0 +1,58 @@
+package http
+
+import (
+	"encoding/json"
+	"encoding/xml"
+	"net/http/httptest"
+	"testing"
+)
+
+type chunkedElem struct {
+	Name string `json:"name" xml:"name"`
+}
+
+func TestEncodeChunked(t *testing.T) {
+	elems := []*chunkedElem{{"a"}, {"<b>"}, {"c"}}
+	cases := map[string]struct {
+		Value     any
+		ChunkSize int
+		// output
+		Expected string
+		Flushed  bool
+	}{
+		"nil":            {[]*chunkedElem(nil), 2, "null\n", false},
+		"empty":          {[]*chunkedElem{}, 2, "[]\n", false},
+		"chunks":         {elems, 2, `[{"name":"a"},{"name":"\u003cb\u003e"},{"name":"c"}]` + "\n", true},
+		"single-chunk":   {elems, 0, `[{"name":"a"},{"name":"\u003cb\u003e"},{"name":"c"}]` + "\n", false},
+		"array":          {[2]int{1, 2}, 1, "[1,2]\n", true},
+		"appender":       {[]appender{{}, {}}, 5, `["appended","appended"]` + "\n", false},
+		"not-collection": {&chunkedElem{"a"}, 1, `{"name":"a"}` + "\n", false},
+	}
+	for k, c := range cases {
+		t.Run(k, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			if err := EncodeChunked(w, json.NewEncoder(w), c.Value, c.ChunkSize); err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+			if got := w.Body.String(); got != c.Expected {
+				t.Errorf("got %q, expected %q", got, c.Expected)
+			}
+			if w.Flushed != c.Flushed {
+				t.Errorf("got flushed %v, expected %v", w.Flushed, c.Flushed)
+			}
+		})
+	}
+}
+
+func TestEncodeChunkedFallback(t *testing.T) {
+	w := httptest.NewRecorder()
+	if err := EncodeChunked(w, xml.NewEncoder(w), []*chunkedElem{{"a"}}, 1); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if got := w.Body.String(); got != "<chunkedElem><name>a</name></chunkedElem>" {
+		t.Errorf("got %q, expected XML encoding", got)
+	}
+	if w.Flushed {
+		t.Error("expected XML response not to be flushed")
+	}
+}
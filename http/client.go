@@ -181,10 +181,11 @@ func ErrDecodingError(svc, m string, err error) error {
 }
 
 // ErrValidationError is the error returned when the response body is properly
-// received and decoded but fails validation.
+// received and decoded but fails validation. The returned error is a
+// *ContractError.
 func ErrValidationError(svc, m string, err error) error {
 	msg := fmt.Sprintf("invalid response: %s", err)
-	return &ClientError{Name: "validation_error", Message: msg, Service: svc, Method: m, Err: err}
+	return &ContractError{&ClientError{Name: "validation_error", Message: msg, Service: svc, Method: m, Err: err}}
 }
 
 // ErrInvalidResponse is the error returned when the service responded with an
@@ -0,0 +1,310 @@
+package http
+
+import (
+	"bytes"
+	"container/list"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+type (
+	// CachingDoer is a Doer that caches the responses to GET requests as
+	// a private cache as defined by RFC 9111: responses are served from the
+	// cache while fresh as directed by their Cache-Control max-age directive
+	// or Expires header and stale responses that define an ETag or a
+	// Last-Modified header are revalidated with a conditional request. Use
+	// NewCachingDoer to wrap the Doer given to the generated client
+	// constructors. CachingDoer is safe for concurrent use.
+	CachingDoer struct {
+		doer       Doer
+		maxEntries int
+		now        func() time.Time
+
+		mu      sync.Mutex
+		entries map[string]*list.Element
+		lru     *list.List
+	}
+
+	// clientCacheEntry is a response cached by CachingDoer.
+	clientCacheEntry struct {
+		key    string
+		status int
+		proto  string
+		major  int
+		minor  int
+		header http.Header
+		body   []byte
+		// vary contains the values of the request headers listed in
+		// the Vary response header.
+		vary map[string]string
+		// stored is the time the response was received.
+		stored time.Time
+		// age is the age of the response when it was received.
+		age time.Duration
+		// lifetime is the freshness lifetime of the response.
+		lifetime time.Duration
+	}
+)
+
+// NewCachingDoer returns a Doer that caches the responses of the GET requests
+// made with d, keeping at most maxEntries responses (0 for no limit) and
+// evicting the least recently used responses first.
+//
+// Example:
+//
+//	doer := goahttp.NewCachingDoer(http.DefaultClient, 1000)
+//	client := catalogc.NewClient("http", "localhost:8080", doer, enc, dec, false)
+func NewCachingDoer(d Doer, maxEntries int) *CachingDoer {
+	return &CachingDoer{
+		doer:       d,
+		maxEntries: maxEntries,
+		now:        time.Now,
+		entries:    make(map[string]*list.Element),
+		lru:        list.New(),
+	}
+}
+
+// Do makes the request or serves it from the cache. Responses served from the
+// cache have the Age header set. Successful requests made with unsafe methods
+// (e.g. POST) invalidate the response cached for the same URL.
+func (c *CachingDoer) Do(req *http.Request) (*http.Response, error) {
+	key := req.URL.String()
+	if req.Method != http.MethodGet {
+		resp, err := c.doer.Do(req)
+		if err == nil && req.Method != http.MethodHead && req.Method != http.MethodOptions && resp.StatusCode < 400 {
+			c.delete(key)
+		}
+		return resp, err
+	}
+	reqcc := req.Header.Get("Cache-Control")
+	if hasCacheDirective(reqcc, "no-store") {
+		return c.doer.Do(req)
+	}
+	entry := c.get(key, req)
+	if entry != nil && !hasCacheDirective(reqcc, "no-cache") && c.now().Sub(entry.stored)+entry.age < entry.lifetime {
+		return entry.response(req, c.now()), nil
+	}
+	creq := req
+	if entry != nil {
+		etag, lastModified := entry.header.Get("ETag"), entry.header.Get("Last-Modified")
+		if etag != "" || lastModified != "" {
+			creq = req.Clone(req.Context())
+			if etag != "" {
+				creq.Header.Set("If-None-Match", etag)
+			}
+			if lastModified != "" {
+				creq.Header.Set("If-Modified-Since", lastModified)
+			}
+		}
+	}
+	resp, err := c.doer.Do(creq)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode == http.StatusNotModified && creq != req {
+		resp.Body.Close()
+		updated := *entry
+		updated.header = entry.header.Clone()
+		for k, v := range resp.Header {
+			if k != "Content-Length" {
+				updated.header[k] = v
+			}
+		}
+		updated.stored, updated.age, updated.lifetime = c.now(), responseAge(resp.Header), freshnessLifetime(updated.header)
+		c.put(&updated)
+		return updated.response(req, c.now()), nil
+	}
+	if !isClientCacheable(resp) {
+		return resp, nil
+	}
+	body, err := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+	resp.Body = io.NopCloser(bytes.NewReader(body))
+	vary := make(map[string]string)
+	for _, name := range varyHeaders(resp.Header) {
+		vary[name] = strings.Join(req.Header.Values(name), ",")
+	}
+	c.put(&clientCacheEntry{
+		key:      key,
+		status:   resp.StatusCode,
+		proto:    resp.Proto,
+		major:    resp.ProtoMajor,
+		minor:    resp.ProtoMinor,
+		header:   resp.Header.Clone(),
+		body:     body,
+		vary:     vary,
+		stored:   c.now(),
+		age:      responseAge(resp.Header),
+		lifetime: freshnessLifetime(resp.Header),
+	})
+	return resp, nil
+}
+
+// Purge deletes all the cached responses.
+func (c *CachingDoer) Purge() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.entries = make(map[string]*list.Element)
+	c.lru.Init()
+}
+
+// get returns the response cached for key that matches the request headers
+// listed in its Vary header, nil if there is none.
+func (c *CachingDoer) get(key string, req *http.Request) *clientCacheEntry {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	elem, ok := c.entries[key]
+	if !ok {
+		return nil
+	}
+	entry := elem.Value.(*clientCacheEntry)
+	for name, val := range entry.vary {
+		if strings.Join(req.Header.Values(name), ",") != val {
+			return nil
+		}
+	}
+	c.lru.MoveToFront(elem)
+	return entry
+}
+
+// put caches entry, evicting the least recently used entries if needed.
+func (c *CachingDoer) put(entry *clientCacheEntry) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if elem, ok := c.entries[entry.key]; ok {
+		elem.Value = entry
+		c.lru.MoveToFront(elem)
+		return
+	}
+	c.entries[entry.key] = c.lru.PushFront(entry)
+	for c.maxEntries > 0 && c.lru.Len() > c.maxEntries {
+		oldest := c.lru.Back()
+		c.lru.Remove(oldest)
+		delete(c.entries, oldest.Value.(*clientCacheEntry).key)
+	}
+}
+
+// delete deletes the response cached for key if any.
+func (c *CachingDoer) delete(key string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if elem, ok := c.entries[key]; ok {
+		c.lru.Remove(elem)
+		delete(c.entries, key)
+	}
+}
+
+// response returns the cached response to req.
+func (e *clientCacheEntry) response(req *http.Request, now time.Time) *http.Response {
+	header := e.header.Clone()
+	header.Set("Age", strconv.Itoa(int((now.Sub(e.stored) + e.age).Seconds())))
+	return &http.Response{
+		Status:        strconv.Itoa(e.status) + " " + http.StatusText(e.status),
+		StatusCode:    e.status,
+		Proto:         e.proto,
+		ProtoMajor:    e.major,
+		ProtoMinor:    e.minor,
+		Header:        header,
+		Body:          io.NopCloser(bytes.NewReader(e.body)),
+		ContentLength: int64(len(e.body)),
+		Request:       req,
+	}
+}
+
+// isClientCacheable returns true if resp may be stored by a private cache,
+// that is if its status code is cacheable by default, it is not marked
+// no-store, it does not vary on all the request headers and it is either
+// fresh or can be revalidated.
+func isClientCacheable(resp *http.Response) bool {
+	switch resp.StatusCode {
+	case 200, 203, 204, 300, 301, 308, 404, 405, 410, 414, 501:
+	default:
+		return false
+	}
+	if hasCacheDirective(resp.Header.Get("Cache-Control"), "no-store") {
+		return false
+	}
+	for _, name := range varyHeaders(resp.Header) {
+		if name == "*" {
+			return false
+		}
+	}
+	return freshnessLifetime(resp.Header) > 0 || resp.Header.Get("ETag") != "" || resp.Header.Get("Last-Modified") != ""
+}
+
+// freshnessLifetime returns the freshness lifetime of the response with the
+// given headers as defined by the max-age directive or the Expires header.
+// Responses marked no-cache must always be revalidated.
+func freshnessLifetime(h http.Header) time.Duration {
+	cc := h.Get("Cache-Control")
+	if hasCacheDirective(cc, "no-cache") {
+		return 0
+	}
+	if v, ok := cacheDirective(cc, "max-age"); ok {
+		if n, err := strconv.Atoi(strings.Trim(v, `"`)); err == nil && n > 0 {
+			return time.Duration(n) * time.Second
+		}
+		return 0
+	}
+	if exp := h.Get("Expires"); exp != "" {
+		expires, err := http.ParseTime(exp)
+		if err != nil {
+			return 0
+		}
+		date, err := http.ParseTime(h.Get("Date"))
+		if err != nil {
+			date = time.Now()
+		}
+		if d := expires.Sub(date); d > 0 {
+			return d
+		}
+	}
+	return 0
+}
+
+// responseAge returns the value of the Age header.
+func responseAge(h http.Header) time.Duration {
+	if n, err := strconv.Atoi(h.Get("Age")); err == nil && n > 0 {
+		return time.Duration(n) * time.Second
+	}
+	return 0
+}
+
+// varyHeaders returns the canonical names of the request headers listed in the
+// Vary header.
+func varyHeaders(h http.Header) []string {
+	var names []string
+	for _, v := range h.Values("Vary") {
+		for _, name := range strings.Split(v, ",") {
+			if name = strings.TrimSpace(name); name != "" {
+				names = append(names, http.CanonicalHeaderKey(name))
+			}
+		}
+	}
+	return names
+}
+
+// hasCacheDirective returns true if the given Cache-Control header value
+// contains the given directive.
+func hasCacheDirective(cc, name string) bool {
+	_, ok := cacheDirective(cc, name)
+	return ok
+}
+
+// cacheDirective returns the value of the given Cache-Control directive.
+func cacheDirective(cc, name string) (string, bool) {
+	for _, d := range strings.Split(cc, ",") {
+		k, v, _ := strings.Cut(strings.TrimSpace(d), "=")
+		if strings.EqualFold(strings.TrimSpace(k), name) {
+			return strings.TrimSpace(v), true
+		}
+	}
+	return "", false
+}
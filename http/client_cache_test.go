@@ -0,0 +1,126 @@
+package http
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+)
+
+func TestCachingDoer(t *testing.T) {
+	var calls, revalidations int
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		switch r.URL.Path {
+		case "/fresh":
+			w.Header().Set("Cache-Control", "private, max-age=60")
+		case "/etag":
+			w.Header().Set("Cache-Control", "no-cache")
+			w.Header().Set("ETag", `"v1"`)
+			if r.Header.Get("If-None-Match") == `"v1"` {
+				revalidations++
+				w.WriteHeader(http.StatusNotModified)
+				return
+			}
+		case "/vary":
+			w.Header().Set("Cache-Control", "max-age=60")
+			w.Header().Set("Vary", "Accept-Language")
+		case "/nostore":
+			w.Header().Set("Cache-Control", "no-store")
+		}
+		if r.Method == "POST" {
+			return
+		}
+		w.Write([]byte(r.URL.Path + " " + strconv.Itoa(calls) + " " + r.Header.Get("Accept-Language"))) // nolint: errcheck
+	}))
+	defer srv.Close()
+	doer := NewCachingDoer(srv.Client(), 10)
+	get := func(path, lang string) (string, *http.Response) {
+		req, _ := http.NewRequest("GET", srv.URL+path, nil)
+		if lang != "" {
+			req.Header.Set("Accept-Language", lang)
+		}
+		resp, err := doer.Do(req)
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer resp.Body.Close()
+		b, _ := io.ReadAll(resp.Body)
+		return string(b), resp
+	}
+
+	first, _ := get("/fresh", "")
+	second, resp := get("/fresh", "")
+	if first != "/fresh 1 " || second != first || calls != 1 {
+		t.Errorf("fresh: got %q then %q with %d calls", first, second, calls)
+	}
+	if resp.Header.Get("Age") != "0" || resp.StatusCode != http.StatusOK {
+		t.Errorf("fresh: got status %d and age %q", resp.StatusCode, resp.Header.Get("Age"))
+	}
+
+	first, _ = get("/etag", "")
+	second, resp = get("/etag", "")
+	if first != "/etag 2 " || second != first || calls != 3 || revalidations != 1 || resp.StatusCode != http.StatusOK {
+		t.Errorf("etag: got %q then %q (%d) with %d calls and %d revalidations", first, second, resp.StatusCode, calls, revalidations)
+	}
+
+	en, _ := get("/vary", "en")
+	fr, _ := get("/vary", "fr")
+	fr2, _ := get("/vary", "fr")
+	if en != "/vary 4 en" || fr != "/vary 5 fr" || fr2 != fr {
+		t.Errorf("vary: got %q, %q and %q", en, fr, fr2)
+	}
+
+	get("/nostore", "")
+	if second, _ := get("/nostore", ""); second != "/nostore 7 " {
+		t.Errorf("no-store: got %q", second)
+	}
+
+	req, _ := http.NewRequest("POST", srv.URL+"/fresh", nil)
+	if _, err := doer.Do(req); err != nil {
+		t.Fatal(err)
+	}
+	if body, _ := get("/fresh", ""); body != "/fresh 9 " {
+		t.Errorf("invalidated: got %q", body)
+	}
+}
+
+func TestCachingDoerExpiration(t *testing.T) {
+	var calls int
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		w.Header().Set("Cache-Control", "max-age=10")
+		w.Header().Set("Age", "5")
+	}))
+	defer srv.Close()
+	now := time.Now()
+	doer := NewCachingDoer(srv.Client(), 1)
+	doer.now = func() time.Time { return now }
+	get := func(path string) {
+		req, _ := http.NewRequest("GET", srv.URL+path, nil)
+		resp, err := doer.Do(req)
+		if err != nil {
+			t.Fatal(err)
+		}
+		resp.Body.Close()
+	}
+
+	get("/a")
+	now = now.Add(4 * time.Second)
+	get("/a")
+	if calls != 1 {
+		t.Errorf("got %d calls, expected 1", calls)
+	}
+	now = now.Add(2 * time.Second)
+	get("/a")
+	if calls != 2 {
+		t.Errorf("got %d calls, expected 2 once the response is stale", calls)
+	}
+	get("/b") // evicts /a
+	get("/a")
+	if calls != 4 {
+		t.Errorf("got %d calls, expected 4 after eviction", calls)
+	}
+}
@@ -0,0 +1,172 @@
+package http
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"strings"
+
+	"goa.design/goa/v3/events"
+	goa "goa.design/goa/v3/pkg"
+)
+
+// CloudEventsMode is the CloudEvents HTTP binding content mode.
+type CloudEventsMode int
+
+const (
+	// CloudEventsBinary is the binary content mode: the event attributes
+	// are carried in "ce-" prefixed headers and the event data in the
+	// request body.
+	CloudEventsBinary CloudEventsMode = iota
+	// CloudEventsStructured is the structured content mode: the request
+	// body is the JSON representation of the event including its data.
+	CloudEventsStructured
+)
+
+// CloudEventsContentType is the content type of structured mode CloudEvents.
+const CloudEventsContentType = "application/cloudevents+json"
+
+// cloudEventsHeaderPrefix is the prefix of the CloudEvents binary mode headers.
+const cloudEventsHeaderPrefix = "ce-"
+
+// NormalizeCloudEvent makes sure r carries a CloudEvent and converts
+// structured mode events to binary mode so that the endpoints may map the
+// event attributes to payload attributes using "ce-" prefixed headers (e.g.
+// "ce-id" or "ce-type") and decode the event data from the request body. The
+// Content-Type header of the converted request is the event data content type.
+// NormalizeCloudEvent returns an error if r is not a CloudEvent or if it is
+// missing one of the required "specversion", "id", "source" or "type"
+// attributes. The generated HTTP handlers call NormalizeCloudEvent for the
+// endpoints that use the CloudEvents DSL.
+func NormalizeCloudEvent(r *http.Request) error {
+	if ct, _, _ := mime.ParseMediaType(r.Header.Get("Content-Type")); ct == CloudEventsContentType {
+		if err := structuredToBinary(r); err != nil {
+			return err
+		}
+	}
+	for _, attr := range []string{"specversion", "id", "source", "type"} {
+		if r.Header.Get(cloudEventsHeaderPrefix+attr) == "" {
+			return goa.MissingFieldError(cloudEventsHeaderPrefix+attr, "header")
+		}
+	}
+	return nil
+}
+
+// NewCloudEventsTransport returns an events transport that sends the events
+// to url in HTTP POST requests using the given CloudEvents content mode, e.g.
+// to a Knative broker. The events must be published by a publisher that uses
+// the events.WithCloudEvents option. Requests that do not receive a 2xx
+// response fail.
+func NewCloudEventsTransport(doer Doer, url string, mode CloudEventsMode) events.Transport {
+	return events.TransportFunc(func(ctx context.Context, msg *events.Message) error {
+		if msg.Headers[events.ContentTypeHeader] != CloudEventsContentType {
+			return errors.New("cloudevents: event is not a CloudEvent, use events.WithCloudEvents")
+		}
+		body, ct := msg.Data, CloudEventsContentType
+		var header http.Header
+		if mode == CloudEventsBinary {
+			var err error
+			if body, ct, header, err = binaryEvent(msg.Data); err != nil {
+				return err
+			}
+		}
+		req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(body))
+		if err != nil {
+			return err
+		}
+		for k, v := range header {
+			req.Header[k] = v
+		}
+		req.Header.Set("Content-Type", ct)
+		resp, err := doer.Do(req)
+		if err != nil {
+			return err
+		}
+		defer resp.Body.Close()
+		_, _ = io.Copy(io.Discard, resp.Body)
+		if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+			return fmt.Errorf("cloudevents %s: unexpected response status %s", url, resp.Status)
+		}
+		return nil
+	})
+}
+
+// structuredToBinary converts the structured mode CloudEvent carried by r to
+// binary mode.
+func structuredToBinary(r *http.Request) error {
+	var event map[string]json.RawMessage
+	if err := json.NewDecoder(r.Body).Decode(&event); err != nil {
+		return goa.DecodePayloadError(err.Error())
+	}
+	r.Body.Close()
+	body, ct, header, err := eventParts(event)
+	if err != nil {
+		return err
+	}
+	for k, v := range header {
+		r.Header[k] = v
+	}
+	r.Header.Set("Content-Type", ct)
+	r.Body = io.NopCloser(bytes.NewReader(body))
+	r.ContentLength = int64(len(body))
+	return nil
+}
+
+// binaryEvent returns the body, content type and headers of the binary mode
+// representation of the given structured mode event.
+func binaryEvent(data []byte) ([]byte, string, http.Header, error) {
+	var event map[string]json.RawMessage
+	if err := json.Unmarshal(data, &event); err != nil {
+		return nil, "", nil, err
+	}
+	return eventParts(event)
+}
+
+// eventParts returns the data, data content type and "ce-" prefixed headers of
+// the given structured mode event.
+func eventParts(event map[string]json.RawMessage) ([]byte, string, http.Header, error) {
+	ct := "application/json"
+	if raw, ok := event["datacontenttype"]; ok {
+		if err := json.Unmarshal(raw, &ct); err != nil {
+			return nil, "", nil, goa.InvalidFieldTypeError("datacontenttype", string(raw), "string")
+		}
+	}
+	var data []byte
+	if raw, ok := event["data_base64"]; ok {
+		var s string
+		if err := json.Unmarshal(raw, &s); err != nil {
+			return nil, "", nil, goa.InvalidFieldTypeError("data_base64", string(raw), "string")
+		}
+		b, err := base64.StdEncoding.DecodeString(s)
+		if err != nil {
+			return nil, "", nil, goa.DecodePayloadError(fmt.Sprintf("invalid data_base64: %s", err))
+		}
+		data = b
+	} else if raw, ok := event["data"]; ok {
+		data = raw
+		var s string
+		if !strings.Contains(ct, "json") && json.Unmarshal(raw, &s) == nil {
+			data = []byte(s)
+		}
+	}
+	header := make(http.Header)
+	for name, raw := range event {
+		switch name {
+		case "data", "data_base64", "datacontenttype":
+			continue
+		}
+		v := string(raw)
+		var s string
+		if json.Unmarshal(raw, &s) == nil {
+			v = s
+		}
+		header.Set(cloudEventsHeaderPrefix+name, v)
+	}
+	return data, ct, header, nil
+}
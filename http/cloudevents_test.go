@@ -0,0 +1,126 @@
+package http
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"goa.design/goa/v3/events"
+)
+
+func TestNormalizeCloudEvent(t *testing.T) {
+	binary := http.Header{
+		"Content-Type":   {"application/json"},
+		"Ce-Specversion": {"1.0"},
+		"Ce-Id":          {"42"},
+		"Ce-Source":      {"/inventory"},
+		"Ce-Type":        {"BottleCreated"},
+	}
+	cases := []struct {
+		name        string
+		header      http.Header
+		body        string
+		err         string
+		contentType string
+		id          string
+		ext         string
+		data        string
+	}{
+		{"binary", binary, `{"id":"1"}`, "", "application/json", "42", "", `{"id":"1"}`},
+		{"structured", http.Header{"Content-Type": {"application/cloudevents+json; charset=utf-8"}},
+			`{"specversion":"1.0","id":"42","source":"/inventory","type":"BottleCreated","datacontenttype":"application/json","priority":3,"data":{"id":"1"}}`,
+			"", "application/json", "42", "3", `{"id":"1"}`},
+		{"structured-text", http.Header{"Content-Type": {CloudEventsContentType}},
+			`{"specversion":"1.0","id":"42","source":"/inventory","type":"BottleCreated","datacontenttype":"text/plain","data":"hello"}`,
+			"", "text/plain", "42", "", "hello"},
+		{"structured-base64", http.Header{"Content-Type": {CloudEventsContentType}},
+			`{"specversion":"1.0","id":"42","source":"/inventory","type":"BottleCreated","datacontenttype":"application/octet-stream","data_base64":"aGVsbG8="}`,
+			"", "application/octet-stream", "42", "", "hello"},
+		{"structured-invalid", http.Header{"Content-Type": {CloudEventsContentType}}, `{`, "unexpected EOF", "", "", "", ""},
+		{"not-an-event", http.Header{"Content-Type": {"application/json"}}, `{"id":"1"}`, `"ce-specversion" is missing from header`, "", "", "", ""},
+		{"missing-id", http.Header{"Content-Type": {CloudEventsContentType}},
+			`{"specversion":"1.0","source":"/inventory","type":"BottleCreated"}`, `"ce-id" is missing from header`, "", "", "", ""},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			r := httptest.NewRequest("POST", "/events", strings.NewReader(c.body))
+			r.Header = c.header.Clone()
+			err := NormalizeCloudEvent(r)
+			if c.err != "" {
+				if err == nil || err.Error() != c.err {
+					t.Fatalf("got error %v, expected %q", err, c.err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+			if ct := r.Header.Get("Content-Type"); ct != c.contentType {
+				t.Errorf("got content type %q, expected %q", ct, c.contentType)
+			}
+			if id := r.Header.Get("ce-id"); id != c.id {
+				t.Errorf("got ID %q, expected %q", id, c.id)
+			}
+			if ext := r.Header.Get("ce-priority"); ext != c.ext {
+				t.Errorf("got extension %q, expected %q", ext, c.ext)
+			}
+			body, _ := io.ReadAll(r.Body)
+			if string(body) != c.data {
+				t.Errorf("got data %s, expected %s", body, c.data)
+			}
+		})
+	}
+}
+
+func TestCloudEventsTransport(t *testing.T) {
+	const event = `{"specversion":"1.0","id":"42","source":"/inventory","type":"BottleCreated","datacontenttype":"application/json","data":{"id":"1"}}`
+	cases := []struct {
+		name        string
+		mode        CloudEventsMode
+		contentType string
+		status      int
+		contentMsg  string
+		body        string
+		err         bool
+	}{
+		{"binary", CloudEventsBinary, CloudEventsContentType, 202, "application/json", `{"id":"1"}`, false},
+		{"structured", CloudEventsStructured, CloudEventsContentType, 200, CloudEventsContentType, event, false},
+		{"not-a-cloudevent", CloudEventsBinary, "application/json", 200, "", "", true},
+		{"failure", CloudEventsStructured, CloudEventsContentType, 503, CloudEventsContentType, event, true},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				body, _ := io.ReadAll(r.Body)
+				if string(body) != c.body {
+					t.Errorf("got body %s, expected %s", body, c.body)
+				}
+				if ct := r.Header.Get("Content-Type"); ct != c.contentMsg {
+					t.Errorf("got content type %q, expected %q", ct, c.contentMsg)
+				}
+				if c.mode == CloudEventsBinary {
+					if typ := r.Header.Get("ce-type"); typ != "BottleCreated" {
+						t.Errorf("got type %q, expected %q", typ, "BottleCreated")
+					}
+				}
+				w.WriteHeader(c.status)
+			}))
+			defer srv.Close()
+			tr := NewCloudEventsTransport(srv.Client(), srv.URL, c.mode)
+			err := tr.Send(context.Background(), &events.Message{
+				Topic:   "inventory.BottleCreated",
+				Headers: map[string]string{events.ContentTypeHeader: c.contentType},
+				Data:    []byte(event),
+			})
+			if c.err && err == nil {
+				t.Error("expected an error")
+			}
+			if !c.err && err != nil {
+				t.Errorf("unexpected error: %s", err)
+			}
+		})
+	}
+}
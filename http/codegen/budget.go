@@ -0,0 +1,71 @@
+package codegen
+
+import (
+	"strconv"
+	"time"
+
+	"goa.design/goa/v3/expr"
+)
+
+// BudgetData contains the data needed to render the code that enforces the
+// response size and latency budget of an endpoint.
+type BudgetData struct {
+	// ResponseSize is the maximum size in bytes of the response body, 0
+	// if unlimited.
+	ResponseSize int64
+	// Latency is the Go expression of the maximum duration of a request,
+	// e.g. "1 * time.Second", empty if unlimited.
+	Latency string
+}
+
+// endpointBudget returns the budget of the given endpoint as defined by the
+// "budget:response-size" and "budget:latency" meta of the method or of its
+// service, nil if there is none.
+func endpointBudget(e *expr.HTTPEndpointExpr) *BudgetData {
+	var data BudgetData
+	if v, ok := methodMeta(e, "budget:response-size"); ok {
+		if n, err := strconv.ParseInt(v, 10, 64); err == nil {
+			data.ResponseSize = n
+		}
+	}
+	if v, ok := methodMeta(e, "budget:latency"); ok {
+		if d, err := time.ParseDuration(v); err == nil {
+			data.Latency = durationLiteral(d)
+		}
+	}
+	if data.ResponseSize == 0 && data.Latency == "" {
+		return nil
+	}
+	return &data
+}
+
+// methodMeta returns the last value of the meta with the given key of the
+// method of e or else of its service.
+func methodMeta(e *expr.HTTPEndpointExpr, key string) (string, bool) {
+	if v, ok := e.MethodExpr.Meta.Last(key); ok {
+		return v, true
+	}
+	return e.MethodExpr.Service.Meta.Last(key)
+}
+
+// hasBudgets returns true if at least one of the given endpoints defines a
+// budget.
+func hasBudgets(data *ServiceData) bool {
+	for _, e := range data.Endpoints {
+		if e.Budget != nil {
+			return true
+		}
+	}
+	return false
+}
+
+// input: ServiceData
+const serverBudgetsT = `{{ printf "UseBudgets enforces the response size and latency budgets of the %s methods configured with the \"budget:response-size\" or \"budget:latency\" meta using the given options." .Service.Name | comment }}
+func (s *{{ .ServerStruct }}) UseBudgets(opts ...httpmdlwr.BudgetOption) {
+{{- range $e := .Endpoints }}
+	{{- with .Budget }}
+	s.{{ $e.Method.VarName }} = httpmdlwr.Budget(&middleware.Budget{Service: {{ printf "%q" $.Service.Name }}, Method: {{ printf "%q" $e.Method.Name }}{{ if .ResponseSize }}, ResponseSize: {{ .ResponseSize }}{{ end }}{{ if .Latency }}, Latency: {{ .Latency }}{{ end }}}, opts...)(s.{{ $e.Method.VarName }})
+	{{- end }}
+{{- end }}
+}
+`
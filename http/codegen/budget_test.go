@@ -0,0 +1,27 @@
+package codegen
+
+import (
+	"testing"
+
+	"goa.design/goa/v3/codegen"
+	"goa.design/goa/v3/expr"
+	"goa.design/goa/v3/http/codegen/testdata"
+)
+
+func TestServerBudgets(t *testing.T) {
+	RunHTTPDSL(t, testdata.BudgetMethodDSL)
+	fs := ServerFiles("", expr.Root)
+	sections := fs[0].Section("server-budgets")
+	if len(sections) != 1 {
+		t.Fatalf("got %d sections, expected 1", len(sections))
+	}
+	code := codegen.SectionCode(t, sections[0])
+	if code != testdata.BudgetMethodCode {
+		t.Errorf("invalid code, got:\n%s\ngot vs. expected:\n%s", code, codegen.Diff(t, code, testdata.BudgetMethodCode))
+	}
+
+	RunHTTPDSL(t, testdata.ServerBatchDSL)
+	if sections := ServerFiles("", expr.Root)[0].Section("server-budgets"); len(sections) != 0 {
+		t.Errorf("got %d sections, expected none", len(sections))
+	}
+}
@@ -0,0 +1,23 @@
+package codegen
+
+// hasCachedMethods returns true if at least one of the given endpoints uses the
+// CacheControl DSL.
+func hasCachedMethods(data *ServiceData) bool {
+	for _, e := range data.Endpoints {
+		if e.CacheControl != "" {
+			return true
+		}
+	}
+	return false
+}
+
+// input: ServiceData
+const serverCacheT = `{{ printf "UseCache serves the responses of the %s methods that use the CacheControl DSL from the given cache as directed by their Cache-Control header." .Service.Name | comment }}
+func (s *{{ .ServerStruct }}) UseCache(c *httpmdlwr.ResponseCache) {
+{{- range .Endpoints }}
+	{{- if .CacheControl }}
+	s.{{ .Method.VarName }} = c.Handler({{ range $i, $h := .Vary }}{{ if $i }}, {{ end }}{{ printf "%q" $h }}{{ end }})(s.{{ .Method.VarName }})
+	{{- end }}
+{{- end }}
+}
+`
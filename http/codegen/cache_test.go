@@ -0,0 +1,35 @@
+package codegen
+
+import (
+	"testing"
+
+	"goa.design/goa/v3/codegen"
+	"goa.design/goa/v3/expr"
+	"goa.design/goa/v3/http/codegen/testdata"
+)
+
+func TestServerCache(t *testing.T) {
+	RunHTTPDSL(t, testdata.CacheControlDSL)
+	fs := ServerFiles("", expr.Root)
+	sections := fs[0].Section("server-cache")
+	if len(sections) != 1 {
+		t.Fatalf("got %d sections, expected 1", len(sections))
+	}
+	code := codegen.SectionCode(t, sections[0])
+	if code != testdata.CacheControlServerCode {
+		t.Errorf("invalid code, got:\n%s\ngot vs. expected:\n%s", code, codegen.Diff(t, code, testdata.CacheControlServerCode))
+	}
+	sections = fs[1].Section("response-encoder")
+	if len(sections) == 0 {
+		t.Fatal("no response encoder section")
+	}
+	code = codegen.SectionCode(t, sections[0])
+	if code != testdata.CacheControlEncodeCode {
+		t.Errorf("invalid code, got:\n%s\ngot vs. expected:\n%s", code, codegen.Diff(t, code, testdata.CacheControlEncodeCode))
+	}
+
+	RunHTTPDSL(t, testdata.ServerBatchDSL)
+	if sections := ServerFiles("", expr.Root)[0].Section("server-cache"); len(sections) != 0 {
+		t.Errorf("got %d sections, expected none", len(sections))
+	}
+}
@@ -0,0 +1,62 @@
+package codegen
+
+import (
+	"fmt"
+	"path/filepath"
+
+	"goa.design/goa/v3/codegen"
+	"goa.design/goa/v3/expr"
+)
+
+// callbackServerFile returns the file implementing the functions that send the
+// service callbacks if any.
+func callbackServerFile(genpkg string, svc *expr.HTTPServiceExpr) *codegen.File {
+	data := HTTPServices.Get(svc.Name())
+	var callbacks []*CallbackData
+	for _, e := range data.Endpoints {
+		callbacks = append(callbacks, e.Callbacks...)
+	}
+	if len(callbacks) == 0 {
+		return nil
+	}
+	svcName := data.Service.PathName
+	title := fmt.Sprintf("%s HTTP server callbacks", svc.Name())
+	imports := []*codegen.ImportSpec{
+		{Path: "context"},
+		{Path: "time"},
+		codegen.GoaNamedImport("http", "goahttp"),
+		{Path: genpkg + "/" + svcName, Name: data.Service.PkgName},
+	}
+	imports = append(imports, data.Service.UserTypeImports...)
+	sections := []*codegen.SectionTemplate{codegen.Header(title, "server", imports)}
+	for _, c := range callbacks {
+		sections = append(sections, &codegen.SectionTemplate{
+			Name:   "server-callback",
+			Source: callbackSenderT,
+			Data:   c,
+		})
+	}
+	return &codegen.File{
+		Path:             filepath.Join(codegen.Gendir, "http", svcName, "server", "callbacks.go"),
+		SectionTemplates: sections,
+	}
+}
+
+// input: CallbackData
+const callbackSenderT = `{{- if gt .MaxAttempts 1 }}
+{{ printf "%s sends the %q callback of the %q service %q endpoint to url. It makes at most %d delivery attempts." .FuncName .Name .ServiceName .EndpointName .MaxAttempts | comment }}
+{{- else }}
+{{ printf "%s sends the %q callback of the %q service %q endpoint to url. It makes a single delivery attempt." .FuncName .Name .ServiceName .EndpointName | comment }}
+{{- end }}
+{{- if .Description }}
+{{ comment .Description }}
+{{- end }}
+func {{ .FuncName }}(ctx context.Context, s *goahttp.WebhookSender, url string, p {{ .PayloadRef }}) error {
+{{- if and .Body .Body.Init }}
+	body := {{ .Body.Init.Name }}(p)
+	return s.Send(ctx, url, body, {{ .MaxAttempts }}, {{ .Backoff }})
+{{- else }}
+	return s.Send(ctx, url, p, {{ .MaxAttempts }}, {{ .Backoff }})
+{{- end }}
+}
+`
@@ -0,0 +1,27 @@
+package codegen
+
+import (
+	"path/filepath"
+	"testing"
+
+	"goa.design/goa/v3/codegen"
+	"goa.design/goa/v3/codegen/codegentest"
+	"goa.design/goa/v3/expr"
+	"goa.design/goa/v3/http/codegen/testdata"
+)
+
+func TestCallbackSenders(t *testing.T) {
+	RunHTTPDSL(t, testdata.ServerCallbackDSL)
+	fs := ServerFiles("", expr.Root)
+	sections := codegentest.Sections(fs, filepath.Join("", "callbacks.go"), "server-callback")
+	if len(sections) == 0 {
+		t.Fatal("section not found")
+	}
+	code := codegen.SectionsCode(t, sections)
+	if code != testdata.ServerCallbackSendersCode {
+		t.Errorf("invalid code, got:\n%s\ngot vs. expected:\n%s", code, codegen.Diff(t, code, testdata.ServerCallbackSendersCode))
+	}
+	if f := callbackServerFile("", expr.Root.API.HTTP.Services[0]); f == nil {
+		t.Error("expected a callbacks file")
+	}
+}
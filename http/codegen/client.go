@@ -83,6 +83,13 @@ func clientFile(genpkg string, svc *expr.HTTPServiceExpr) *codegen.File {
 				"responseStructPkg":   responseStructPkg,
 			},
 		})
+		if e.Batch != nil {
+			sections = append(sections, &codegen.SectionTemplate{
+				Name:   "client-batch-endpoint-init",
+				Source: batchEndpointInitT,
+				Data:   e,
+			})
+		}
 	}
 
 	return &codegen.File{Path: path, SectionTemplates: sections}
@@ -396,6 +403,46 @@ func (c *{{ .ClientStruct }}) {{ .EndpointInit }}({{ if .MultipartRequestEncoder
 }
 `
 
+// input: EndpointData
+const batchEndpointInitT = `{{ printf "%s returns an endpoint that makes HTTP batch requests to the %s service %s server. The endpoint payload is a slice of the method payloads and its result is a slice of *goahttp.BatchResult holding the result or error of each payload in order. The path and query string parameters, headers and cookies of the batch request are taken from the first payload." .Batch.EndpointInit .ServiceName .Method.Name | comment }}
+func (c *{{ .ClientStruct }}) {{ .Batch.EndpointInit }}() goa.Endpoint {
+	var (
+		{{- if .RequestEncoder }}
+		encodeRequest  = {{ .RequestEncoder }}(c.encoder)
+		{{- end }}
+		decodeResponse = {{ .ResponseDecoder }}(c.decoder, c.RestoreResponseBody)
+	)
+	return func(ctx context.Context, v any) (any, error) {
+		payloads, ok := v.([]{{ .Payload.Ref }})
+		if !ok {
+			return nil, goahttp.ErrInvalidType("{{ .ServiceName }}", "{{ .Method.Name }}", "[]{{ .Payload.Ref }}", v)
+		}
+		reqs := make([]*http.Request, len(payloads))
+		for i, v := range payloads {
+			req, err := c.{{ .RequestInit.Name }}(ctx, {{ range .RequestInit.ClientArgs }}{{ .Ref }}, {{ end }})
+			if err != nil {
+				return nil, err
+			}
+			{{- if .RequestEncoder }}
+			if err := encodeRequest(req, v); err != nil {
+				return nil, err
+			}
+			{{- end }}
+			reqs[i] = req
+		}
+		req, err := goahttp.NewBatchRequest(reqs, {{ printf "%q" .Batch.Suffix }})
+		if err != nil {
+			return nil, goahttp.ErrEncodingError("{{ .ServiceName }}", "{{ .Method.Name }}", err)
+		}
+		resp, err := c.{{ .Method.VarName }}Doer.Do(req)
+		if err != nil {
+			return nil, goahttp.ErrRequestError("{{ .ServiceName }}", "{{ .Method.Name }}", err)
+		}
+		return goahttp.DecodeBatchResponse(resp, decodeResponse)
+	}
+}
+`
+
 // input: EndpointData
 const requestBuilderT = `{{ comment .RequestInit.Description }}
 func (c *{{ .ClientStruct }}) {{ .RequestInit.Name }}(ctx context.Context, {{ range .RequestInit.ClientArgs }}{{ .VarName }} {{ .TypeRef }},{{ end }}) (*http.Request, error) {
@@ -432,7 +479,9 @@ func {{ .RequestEncoder }}(encoder func(*http.Request) goahttp.Encoder) func(*ht
 			req.Header.Set({{ printf "%q" .HTTPName }}, "Bearer "+head)
 		} else {
 			{{- end }}
-			{{- if eq .Type.Name "array" }}
+			{{- if .Encode }}
+			req.Header.Set({{ printf "%q" .HTTPName }}, {{ .Encode }}(head))
+			{{- else if eq .Type.Name "array" }}
 			for _, val := range head {
 				{{- if eq .Type.ElemType.Type.Name "string" }}
 				req.Header.Add({{ printf "%q" .HTTPName }}, val)
@@ -457,6 +506,11 @@ func {{ .RequestEncoder }}(encoder func(*http.Request) goahttp.Encoder) func(*ht
 		}
 		{{- end }}
 	{{- end }}
+	{{- if .CloudEvents }}
+		if req.Header.Get("ce-specversion") == "" {
+			req.Header.Set("ce-specversion", "1.0")
+		}
+	{{- end }}
 	{{- range .Payload.Request.Cookies }}
 		{{- if .FieldName }}
 			{{- if .FieldPointer }}
@@ -464,10 +518,14 @@ func {{ .RequestEncoder }}(encoder func(*http.Request) goahttp.Encoder) func(*ht
 			{{- else }}
 			{
 			{{- end }}
+			{{- if .Encode }}
+			v := {{ .Encode }}({{ if .FieldPointer }}*{{ end }}p.{{ .FieldName }})
+			{{- else }}
 			v{{ if not (eq .Type.Name "string") }}raw{{ end }} := {{ if .FieldPointer }}*{{ end }}p.{{ .FieldName }}
 			{{- if not (eq .Type.Name "string" ) }}
 			{{ template "type_conversion" (typeConversionData .Type .FieldType "vraw" "v") }}
 			{{- end }}
+			{{- end }}
 			req.AddCookie(&http.Cookie{
 				Name: {{ printf "%q" .HTTPName }},
 				Value: v,
@@ -523,17 +581,20 @@ func {{ .RequestEncoder }}(encoder func(*http.Request) goahttp.Encoder) func(*ht
 		if p.{{ .FieldName }} != nil {
 			{{- end }}
 		values.Add("{{ .HTTPName }}",
-			{{- if or (eq .Type.Name "bytes") (and (isAlias .FieldType) (eq (underlyingType .FieldType).Name "string")) }} string(
+			{{- if .Encode }} {{ .Encode }}(
+			{{- else if or (eq .Type.Name "bytes") (and (isAlias .FieldType) (eq (underlyingType .FieldType).Name "string")) }} string(
 			{{- else if not (eq .Type.Name "string") }} fmt.Sprintf("%v",
 			{{- end }}
 			{{- if .FieldPointer }}*{{ end }}p.{{ .FieldName }}
-			{{- if or (eq .Type.Name "bytes") (not (eq .Type.Name "string")) (and (isAlias .FieldType) (eq (underlyingType .FieldType).Name "string")) }})
+			{{- if or .Encode (eq .Type.Name "bytes") (not (eq .Type.Name "string")) (and (isAlias .FieldType) (eq (underlyingType .FieldType).Name "string")) }})
 			{{- end }})
 			{{- if .FieldPointer }}
 		}
 			{{- end }}
 		{{- else }}
-			{{- if eq .Type.Name "string" }}
+			{{- if .Encode }}
+				values.Add("{{ .HTTPName }}", {{ .Encode }}(p))
+			{{- else if eq .Type.Name "string" }}
 				values.Add("{{ .HTTPName }}", p)
 			{{- else if (and (isAlias .Type) (eq (underlyingType .Type).Name "string")) }}
 				values.Add("{{ .HTTPName }}", string(p))
@@ -556,7 +617,16 @@ func {{ .RequestEncoder }}(encoder func(*http.Request) goahttp.Encoder) func(*ht
 		{{- else }}
 		body := p{{ if .Payload.Request.PayloadAttr }}.{{ .Payload.Request.PayloadAttr }}{{ end }}
 		{{- end }}
+		{{- if .Payload.Request.PatchBody }}
+		if body != nil {
+			req.Header.Set("Content-Type", body.ContentType())
+		}
+		{{- end }}
+		{{- if .Payload.Request.PresentFields }}
+		if err := encoder(req).Encode(goahttp.WithNullFields(&body, body.{{ .Payload.Request.PresentFields }})); err != nil {
+		{{- else }}
 		if err := encoder(req).Encode(&body); err != nil {
+		{{- end }}
 			return goahttp.ErrEncodingError("{{ .ServiceName }}", "{{ .Method.Name }}", err)
 		}
 	{{- end }}
@@ -681,7 +751,7 @@ func {{ .ResponseDecoder }}(decoder func(*http.Response) goahttp.Decoder, restor
 				{{- end }}
 			vres := {{ if not $.Method.ViewedResult.IsCollection }}&{{ end }}{{ $.Method.ViewedResult.ViewsPkg}}.{{ $.Method.ViewedResult.VarName }}{Projected: p, View: view}
 				{{- if .ClientBody }}
-				if err = {{ $.Method.ViewedResult.ViewsPkg}}.Validate{{ $.Method.Result }}(vres); err != nil {
+				if err = {{ $.Method.ViewedResult.ViewsPkg}}.Validate{{ $.Method.Result }}(vres); err != nil && goahttp.RejectResponse(resp, err) {
 					return nil, goahttp.ErrValidationError("{{ $.ServiceName }}", "{{ $.Method.Name }}", err)
 				}
 				{{- end }}
@@ -757,13 +827,21 @@ const singleResponseT = ` {{- if .ClientBody }}
 				body {{ .ClientBody.VarName }}
 				err error
 			)
+		{{- with .UnknownFields }}
+			{{- if .Disallow }}
+			err = goahttp.DecodeStrict(decoder(resp), &body)
+			{{- else }}
+			err = goahttp.DecodeCollectUnknown(decoder(resp), &body, {{ printf "%#v" .Known }}, &body.{{ .FieldName }})
+			{{- end }}
+		{{- else }}
 			err = decoder(resp).Decode(&body)
+		{{- end }}
 			if err != nil {
 				return nil, goahttp.ErrDecodingError("{{ $.ServiceName }}", "{{ $.Method.Name }}", err)
 			}
 		{{- if .ClientBody.ValidateRef }}
 			{{ .ClientBody.ValidateRef }}
-			if err != nil {
+			if err != nil && goahttp.RejectResponse(resp, err) {
 				return nil, goahttp.ErrValidationError("{{ $.ServiceName }}", "{{ $.Method.Name }}", err)
 			}
 		{{- end }}
@@ -782,8 +860,8 @@ const singleResponseT = ` {{- if .ClientBody }}
 			)
 		{{- range .Headers }}
 
-		{{- if (or (eq .Type.Name "string") (eq .Type.Name "any")) }}
-			{{ .VarName }}Raw := resp.Header.Get("{{ .CanonicalName }}")
+		{{- if and (not .Decode) (or (eq .Type.Name "string") (eq .Type.Name "any")) }}
+			{{ .VarName }}Raw := {{ if .Disposition }}goahttp.ParseContentDisposition({{ end }}resp.Header.Get("{{ .CanonicalName }}"){{ if .Disposition }}){{ end }}
 			{{- if .Required }}
 				if {{ .VarName }}Raw == "" {
 					err = goa.MergeErrors(err, goa.MissingFieldError("{{ .Name }}", "header"))
@@ -888,7 +966,7 @@ const singleResponseT = ` {{- if .ClientBody }}
 		}
 		{{- range .Cookies }}
 
-		{{- if (or (eq .Type.Name "string") (eq .Type.Name "any")) }}
+		{{- if and (not .Decode) (or (eq .Type.Name "string") (eq .Type.Name "any")) }}
 			{{- if .Required }}
 				if {{ .VarName }}Raw == "" {
 					err = goa.MergeErrors(err, goa.MissingFieldError("{{ .Name }}", "cookie"))
@@ -933,7 +1011,7 @@ const singleResponseT = ` {{- if .ClientBody }}
 	{{- end }}
 
 	{{- if .MustValidate }}
-			if err != nil {
+			if err != nil && goahttp.RejectResponse(resp, err) {
 				return nil, goahttp.ErrValidationError("{{ $.ServiceName }}", "{{ $.Method.Name }}", err)
 			}
 	{{- end }}
@@ -230,6 +230,10 @@ func makeFlags(e *EndpointData, args []*InitArgData, payload expr.DataType) ([]*
 		}
 
 		f := cli.NewFlagData(e.ServiceName, e.Method.Name, arg.VarName, arg.TypeName, arg.Description, arg.Required, arg.Example, arg.DefaultValue)
+		if arg.Decode != "" {
+			f.Type = "STRING"
+			f.Decode = arg.Decode
+		}
 		flags[i] = f
 		params[i] = f.FullName
 		if arg.FieldName == "" && arg.VarName != "body" {
@@ -42,6 +42,7 @@ func TestClientCLIFiles(t *testing.T) {
 		{"map-query-object", testdata.PayloadMapQueryObjectDSL, testdata.MapQueryObjectBuildCode, 1, 1},
 		{"empty-body-build", testdata.PayloadBodyPrimitiveFieldEmptyDSL, testdata.EmptyBodyBuildCode, 1, 1},
 		{"with-params-and-headers-dsl", testdata.WithParamsAndHeadersBlockDSL, testdata.WithParamsAndHeadersBlockBuildCode, 1, 1},
+		{"custom-type-funcs-build", testdata.PayloadCustomTypeFuncsDSL, testdata.CustomTypeFuncsBuildCode, 1, 1},
 	}
 
 	for _, c := range cases {
@@ -120,6 +120,8 @@ func TestClientEncode(t *testing.T) {
 		{"body-string-validate", testdata.PayloadBodyStringValidateDSL, testdata.PayloadBodyStringValidateEncodeCode},
 		{"body-user", testdata.PayloadBodyUserDSL, testdata.PayloadBodyUserEncodeCode},
 		{"body-user-validate", testdata.PayloadBodyUserValidateDSL, testdata.PayloadBodyUserValidateEncodeCode},
+		{"body-patch", testdata.PayloadBodyPatchDSL, testdata.PayloadBodyPatchEncodeCode},
+		{"body-field-presence", testdata.PayloadBodyFieldPresenceDSL, testdata.PayloadBodyFieldPresenceEncodeCode},
 		{"body-array-string", testdata.PayloadBodyArrayStringDSL, testdata.PayloadBodyArrayStringEncodeCode},
 		{"body-array-string-validate", testdata.PayloadBodyArrayStringValidateDSL, testdata.PayloadBodyArrayStringValidateEncodeCode},
 		{"body-array-user", testdata.PayloadBodyArrayUserDSL, testdata.PayloadBodyArrayUserEncodeCode},
@@ -169,6 +171,8 @@ func TestClientEncode(t *testing.T) {
 		{"query-map-alias", testdata.QueryMapAliasDSL, testdata.QueryMapAliasEncodeCode},
 		{"query-map-alias-validate", testdata.QueryMapAliasValidateDSL, testdata.QueryMapAliasValidateEncodeCode},
 		{"query-array-nested-alias-validate", testdata.QueryArrayNestedAliasValidateDSL, testdata.QueryArrayNestedAliasValidateEncodeCode},
+		{"cloud-events", testdata.PayloadCloudEventsDSL, testdata.PayloadCloudEventsEncodeCode},
+		{"custom-type-funcs", testdata.PayloadCustomTypeFuncsDSL, testdata.PayloadCustomTypeFuncsEncodeCode},
 	}
 	golden := makeGolden(t, "testdata/payload_encode_functions.go")
 	if golden != nil {
@@ -1,9 +1,11 @@
 package codegen
 
 import (
+	"path/filepath"
 	"testing"
 
 	"goa.design/goa/v3/codegen"
+	"goa.design/goa/v3/codegen/codegentest"
 	"goa.design/goa/v3/expr"
 	"goa.design/goa/v3/http/codegen/testdata"
 )
@@ -37,3 +39,16 @@ func TestClientInit(t *testing.T) {
 		})
 	}
 }
+
+func TestClientBatchEndpointInit(t *testing.T) {
+	RunHTTPDSL(t, testdata.ServerBatchDSL)
+	fs := ClientFiles("", expr.Root)
+	sections := codegentest.Sections(fs, filepath.Join("", "client.go"), "client-batch-endpoint-init")
+	if len(sections) == 0 {
+		t.Fatal("section not found")
+	}
+	code := codegen.SectionCode(t, sections[0])
+	if code != testdata.BatchClientEndpointInitCode {
+		t.Errorf("invalid code, got:\n%s\ngot vs. expected:\n%s", code, codegen.Diff(t, code, testdata.BatchClientEndpointInitCode))
+	}
+}
@@ -0,0 +1,451 @@
+package codegen
+
+import (
+	"encoding/json"
+	"net/url"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"goa.design/goa/v3/codegen"
+	"goa.design/goa/v3/codegen/example"
+	"goa.design/goa/v3/expr"
+	"goa.design/goa/v3/http/codegen/openapi"
+)
+
+type (
+	// contractCaseData describes a request sent by the generated contract
+	// tests together with the expected response status codes.
+	contractCaseData struct {
+		// Name is the name of the test case.
+		Name string
+		// Method is the request HTTP method.
+		Method string
+		// Path is the request path including the query string.
+		Path string
+		// Header lists the request headers.
+		Header [][2]string
+		// Body is the request body.
+		Body string
+		// Invalid is true if the request is invalid in which case the
+		// response is not validated against the OpenAPI document.
+		Invalid bool
+		// Statuses lists the expected response status codes.
+		Statuses []int
+	}
+)
+
+// ExampleContractFiles returns the contract tests of the example HTTP servers.
+// The tests serve the example service implementations and exercise every HTTP
+// endpoint with requests built from the examples defined in the design as well
+// as with invalid requests. They assert the response status codes and validate
+// the responses against the generated OpenAPI document.
+func ExampleContractFiles(genpkg string, root *expr.RootExpr) []*codegen.File {
+	var fw []*codegen.File
+	for _, svr := range root.API.Servers {
+		if f := exampleContract(genpkg, root, svr); f != nil {
+			fw = append(fw, f)
+		}
+	}
+	return fw
+}
+
+// exampleContract returns the contract test of the given example server, nil
+// if the server does not host HTTP services.
+func exampleContract(genpkg string, root *expr.RootExpr, svr *expr.ServerExpr) *codegen.File {
+	var (
+		svcdata   []*ServiceData
+		endpoints bool
+	)
+	for _, svc := range svr.Services {
+		if data := HTTPServices.Get(svc); data != nil {
+			svcdata = append(svcdata, data)
+			endpoints = endpoints || len(data.Endpoints) > 0
+		}
+	}
+	if !endpoints {
+		return nil
+	}
+	svrdata := example.Servers.Get(svr)
+	fpath := filepath.Join("cmd", svrdata.Dir, "http_contract_test.go")
+	specs := []*codegen.ImportSpec{
+		{Path: "io"},
+		{Path: "log"},
+		{Path: "net/http"},
+		{Path: "net/http/httptest"},
+		{Path: "os"},
+		{Path: "strings"},
+		{Path: "testing"},
+		codegen.GoaNamedImport("http", "goahttp"),
+		codegen.GoaNamedImport("http/middleware", "httpmdlwr"),
+	}
+	if needStream(svcdata) {
+		specs = append(specs, &codegen.ImportSpec{Path: "github.com/gorilla/websocket"})
+	}
+
+	scope := codegen.NewNameScope()
+	for _, sd := range svcdata {
+		svcName := sd.Service.PathName
+		specs = append(specs, &codegen.ImportSpec{
+			Path: path.Join(genpkg, "http", svcName, "server"),
+			Name: scope.Unique(sd.Service.PkgName + "svr"),
+		})
+		specs = append(specs, &codegen.ImportSpec{
+			Path: path.Join(genpkg, svcName),
+			Name: scope.Unique(sd.Service.PkgName),
+		})
+	}
+
+	var (
+		rootPath string
+		apiPkg   string
+	)
+	{
+		// genpkg is created by path.Join so the separator is / regardless of operating system
+		idx := strings.LastIndex(genpkg, string("/"))
+		rootPath = "."
+		if idx > 0 {
+			rootPath = genpkg[:idx]
+		}
+		apiPkg = scope.Unique(strings.ToLower(codegen.Goify(root.API.Name, false)), "api")
+	}
+	specs = append(specs, &codegen.ImportSpec{Path: rootPath, Name: apiPkg})
+
+	var cases []*contractCaseData
+	for _, sd := range svcdata {
+		if svc := root.API.HTTP.Service(sd.Service.Name); svc != nil {
+			for _, e := range svc.HTTPEndpoints {
+				cases = append(cases, contractCases(e, root.API.ExampleGenerator)...)
+			}
+		}
+	}
+
+	sections := []*codegen.SectionTemplate{
+		codegen.Header("", "main", specs),
+		{
+			Name:   "contract-test",
+			Source: contractTestT,
+			Data: map[string]any{
+				"Cases":   cases,
+				"OpenAPI": path.Join("..", "..", codegen.Gendir, "http", "openapi3.json"),
+			},
+		},
+		{
+			Name:   "contract-handler-start",
+			Source: contractHandlerStartT,
+			Data: map[string]any{
+				"Services": svcdata,
+				"APIPkg":   apiPkg,
+			},
+		},
+		{Name: "server-http-encoding", Source: httpSvrEncodingT},
+		{Name: "server-http-mux", Source: httpSvrMuxT},
+		{
+			Name:   "server-http-init",
+			Source: httpSvrInitT,
+			Data: map[string]any{
+				"Services": svcdata,
+				"APIPkg":   apiPkg,
+			},
+			FuncMap: map[string]any{
+				"needStream":   needStream,
+				"hasWebSocket": hasWebSocket,
+				// the contract tests exercise the methods gated by
+				// feature flags regardless of the flags
+				"hasFeatureFlags": func(*ServiceData) bool { return false },
+				// retried requests are not replayed by the contract tests
+				"hasIdempotentMethods": func(*ServiceData) bool { return false },
+				// the contract tests check the responses of the methods
+				"hasCachedMethods": func(*ServiceData) bool { return false },
+			},
+		},
+		{
+			Name:   "contract-handler-end",
+			Source: contractHandlerEndT,
+			Data: map[string]any{
+				"OpenAPI": path.Join("..", "..", codegen.Gendir, "http", "openapi3.json"),
+			},
+		},
+	}
+	return &codegen.File{Path: fpath, SectionTemplates: sections, SkipExist: true}
+}
+
+// contractCases returns the contract test cases of the given endpoint, nil if
+// the endpoint cannot be exercised by the contract tests.
+func contractCases(e *expr.HTTPEndpointExpr, rand *expr.ExampleGenerator) []*contractCaseData {
+	m := e.MethodExpr
+	if m.IsStreaming() || e.SkipRequestBodyEncodeDecode || e.SkipResponseBodyEncodeDecode || e.MultipartRequest || len(m.Requirements) > 0 {
+		return nil
+	}
+	valid := exampleRequest(e, rand)
+	if valid == nil {
+		return nil
+	}
+	cases := []*contractCaseData{valid}
+	if valid.Body == "" {
+		return cases
+	}
+	cases = append(cases, &contractCaseData{
+		Name:     valid.Name + " malformed body",
+		Method:   valid.Method,
+		Path:     valid.Path,
+		Header:   valid.Header,
+		Body:     "{",
+		Invalid:  true,
+		Statuses: []int{400},
+	})
+	if expr.IsObject(e.Body.Type) {
+		if v := bodyValidation(e.Body); v != nil && len(v.Required) > 0 {
+			cases = append(cases, &contractCaseData{
+				Name:     valid.Name + " missing required attributes",
+				Method:   valid.Method,
+				Path:     valid.Path,
+				Header:   valid.Header,
+				Body:     "{}",
+				Invalid:  true,
+				Statuses: []int{400},
+			})
+		}
+	}
+	return cases
+}
+
+// exampleRequest returns the request built from the examples defined in the
+// design for the first route of the given endpoint together with the status
+// codes of the endpoint responses. It returns nil if the example body cannot
+// be serialized.
+func exampleRequest(e *expr.HTTPEndpointExpr, rand *expr.ExampleGenerator) *contractCaseData {
+	r := e.Routes[0]
+	p := r.FullPaths()[0]
+	wildcards := expr.ExtractHTTPWildcards(p)
+	var query []string
+	codegen.WalkMappedAttr(e.Params, func(n, pn string, _ bool, at *expr.AttributeExpr) error { // nolint: errcheck
+		ex := at.Example(rand)
+		for _, w := range wildcards {
+			if n == w {
+				v := exampleString(ex, ",")
+				p = strings.NewReplacer("{"+w+"}", url.PathEscape(v), "{*"+w+"}", escapeSegments(v)).Replace(p)
+				return nil
+			}
+		}
+		if ex == nil {
+			return nil
+		}
+		if arr, ok := exampleSlice(ex); ok {
+			for _, v := range arr {
+				query = append(query, url.QueryEscape(pn)+"="+url.QueryEscape(exampleString(v, ",")))
+			}
+			return nil
+		}
+		query = append(query, url.QueryEscape(pn)+"="+url.QueryEscape(exampleString(ex, ",")))
+		return nil
+	})
+	if len(query) > 0 {
+		p += "?" + strings.Join(query, "&")
+	}
+	var header [][2]string
+	expr.WalkMappedAttr(e.Headers, func(_, elem string, at *expr.AttributeExpr) error { // nolint: errcheck
+		if ex := at.Example(rand); ex != nil {
+			header = append(header, [2]string{elem, exampleString(ex, ", ")})
+		}
+		return nil
+	})
+	var cookies []string
+	expr.WalkMappedAttr(e.Cookies, func(_, elem string, at *expr.AttributeExpr) error { // nolint: errcheck
+		if ex := at.Example(rand); ex != nil {
+			cookies = append(cookies, elem+"="+exampleString(ex, ","))
+		}
+		return nil
+	})
+	if len(cookies) > 0 {
+		header = append(header, [2]string{"Cookie", strings.Join(cookies, "; ")})
+	}
+	if e.CloudEvents {
+		header = append(header, cloudEventsHeaders(header)...)
+	}
+	if buildJSONAPIData(e.MethodExpr.Result) != nil {
+		header = append(header, [2]string{"Accept", "application/vnd.api+json"})
+	}
+	var (
+		statuses []int
+		seen     = make(map[int]struct{})
+	)
+	for _, resp := range e.Responses {
+		if _, ok := seen[resp.StatusCode]; !ok {
+			seen[resp.StatusCode] = struct{}{}
+			statuses = append(statuses, resp.StatusCode)
+		}
+	}
+	sort.Ints(statuses)
+
+	req := &contractCaseData{
+		Name:     e.Service.Name() + " " + e.Name(),
+		Method:   r.Method,
+		Path:     p,
+		Header:   header,
+		Statuses: statuses,
+	}
+	if e.Body == nil || e.Body.Type == expr.Empty {
+		return req
+	}
+	ex := e.Body.Example(rand)
+	if m, ok := ex.(map[string]any); ok {
+		// the attributes collected by the decoder are not part of the
+		// body
+		delete(m, e.PresentFields)
+		delete(m, e.UnknownFields)
+	}
+	body, err := json.Marshal(openapi.ToStringMap(ex))
+	if err != nil {
+		return nil
+	}
+	req.Body = string(body)
+	req.Header = append([][2]string{{"Content-Type", "application/json"}}, header...)
+	return req
+}
+
+// cloudEventsHeaders returns the CloudEvents binary mode headers required by
+// goahttp.NormalizeCloudEvent that are missing from the given headers.
+func cloudEventsHeaders(header [][2]string) [][2]string {
+	var missing [][2]string
+	for _, h := range [][2]string{
+		{"ce-specversion", "1.0"},
+		{"ce-id", "contract"},
+		{"ce-source", "/contract"},
+		{"ce-type", "goa.contract"},
+	} {
+		found := false
+		for _, e := range header {
+			if strings.EqualFold(e[0], h[0]) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			missing = append(missing, h)
+		}
+	}
+	return missing
+}
+
+// escapeSegments escapes each segment of the given catch-all wildcard value
+// so that it may be used in a request path.
+func escapeSegments(v string) string {
+	segs := strings.Split(v, "/")
+	for i, seg := range segs {
+		segs[i] = url.PathEscape(seg)
+	}
+	return strings.Join(segs, "/")
+}
+
+// bodyValidation returns the validation of the given body attribute or of its
+// underlying user type.
+func bodyValidation(body *expr.AttributeExpr) *expr.ValidationExpr {
+	if body.Validation != nil {
+		return body.Validation
+	}
+	if ut, ok := body.Type.(expr.UserType); ok {
+		return ut.Attribute().Validation
+	}
+	return nil
+}
+
+// input: map[string]any{"Cases": []*contractCaseData, "OpenAPI": string}
+const contractTestT = `{{ printf "TestHTTPContract exercises the HTTP endpoints of the example services with requests built from the examples defined in the design and with invalid requests. It asserts the response status codes and validates the responses against the OpenAPI document %s. Streaming endpoints, endpoints that skip the body encoding, multipart endpoints and endpoints that require authentication are not exercised." .OpenAPI | comment }}
+func TestHTTPContract(t *testing.T) {
+	cases := []struct {
+		Name    string
+		Method  string
+		Path    string
+		Header  [][2]string
+		Body    string
+		Invalid bool
+		// output
+		Statuses []int
+	}{
+	{{- range .Cases }}
+		{
+			{{ printf "%q" .Name }},
+			{{ printf "%q" .Method }},
+			{{ printf "%q" .Path }},
+			{{- if .Header }}
+			[][2]string{ {{- range $i, $h := .Header }}{{ if $i }}, {{ end }}{ {{ printf "%q" (index $h 0) }}, {{ printf "%q" (index $h 1) }} }{{ end -}} },
+			{{- else }}
+			nil,
+			{{- end }}
+			{{ printf "%q" .Body }},
+			{{ .Invalid }},
+			[]int{ {{- range $i, $s := .Statuses }}{{ if $i }}, {{ end }}{{ $s }}{{ end -}} },
+		},
+	{{- end }}
+	}
+	for _, c := range cases {
+		t.Run(c.Name, func(t *testing.T) {
+			req := httptest.NewRequest(c.Method, c.Path, strings.NewReader(c.Body))
+			for _, h := range c.Header {
+				req.Header.Add(h[0], h[1])
+			}
+			w := httptest.NewRecorder()
+
+			newContractHandler(t, !c.Invalid).ServeHTTP(w, req)
+
+			for _, s := range c.Statuses {
+				if w.Code == s {
+					return
+				}
+			}
+			t.Errorf("got status %d, expected one of %v, response body: %s", w.Code, c.Statuses, w.Body.String())
+		})
+	}
+}
+`
+
+// input: map[string]any{"Services": []*ServiceData, "APIPkg": string}
+const contractHandlerStartT = `
+{{ comment "newContractHandler returns the HTTP handler serving the example services. If validate is true the handler validates the responses against the OpenAPI document and reports the violations as test errors." }}
+func newContractHandler(t *testing.T, validate bool) http.Handler {
+	logger := log.New(io.Discard, "", 0)
+	debug := false
+
+	// Initialize the services and wrap them in endpoints.
+	var (
+	{{- range .Services }}
+		{{- if .Endpoints }}
+		{{ .Service.VarName }}Endpoints *{{ .Service.PkgName }}.Endpoints
+		{{- end }}
+	{{- end }}
+	)
+	{
+	{{- range .Services }}
+		{{- if .Endpoints }}
+		{{ .Service.VarName }}Endpoints = {{ .Service.PkgName }}.NewEndpoints({{ $.APIPkg }}.New{{ .Service.StructName }}(logger))
+		{{- end }}
+	{{- end }}
+	}
+`
+
+// input: map[string]any{"OpenAPI": string}
+const contractHandlerEndT = `
+	var handler http.Handler = mux
+	if validate {
+		spec, err := os.ReadFile({{ printf "%q" .OpenAPI }})
+		if err != nil {
+			t.Fatalf("failed to read the OpenAPI document: %v", err)
+		}
+		report := func(r *http.Request, vs []*httpmdlwr.ContractViolation) {
+			for _, v := range vs {
+				if v.Response {
+					t.Errorf("%s %s: %v", r.Method, r.URL.Path, v)
+				}
+			}
+		}
+		mdlwr, err := httpmdlwr.ValidateOpenAPI(spec, httpmdlwr.OpenAPIReportOnly(), httpmdlwr.OpenAPIReporter(report))
+		if err != nil {
+			t.Fatalf("invalid OpenAPI document: %v", err)
+		}
+		handler = mdlwr(handler)
+	}
+	return httpmdlwr.RequestID()(handler)
+}
+`
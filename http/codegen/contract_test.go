@@ -0,0 +1,95 @@
+package codegen
+
+import (
+	"bytes"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"goa.design/goa/v3/codegen"
+	"goa.design/goa/v3/codegen/example"
+	"goa.design/goa/v3/expr"
+	"goa.design/goa/v3/http/codegen/testdata"
+)
+
+func TestExampleContractFiles(t *testing.T) {
+	example.Servers = make(example.ServersData)
+	root := RunHTTPDSL(t, testdata.ContractDSL)
+	fs := ExampleContractFiles("goa.design/goa/example/gen", root)
+	if len(fs) != 1 {
+		t.Fatalf("got %d files, expected 1", len(fs))
+	}
+	if fs[0].Path != filepath.Join("cmd", "test_api", "http_contract_test.go") {
+		t.Errorf("invalid path %q", fs[0].Path)
+	}
+	var buf bytes.Buffer
+	if err := fs[0].SectionTemplates[1].Write(&buf); err != nil {
+		t.Fatal(err)
+	}
+	code := codegen.FormatTestCode(t, "package foo\n"+buf.String())
+	if code != testdata.ContractTestCode {
+		t.Errorf("invalid code, got:\n%s\ngot vs. expected:\n%s", code, codegen.Diff(t, code, testdata.ContractTestCode))
+	}
+}
+
+func TestContractCases(t *testing.T) {
+	root := RunHTTPDSL(t, testdata.ContractDSL)
+	svc := root.API.HTTP.Service("svc")
+	cases := map[string]struct {
+		Endpoint string
+		// output
+		Expected []string
+	}{
+		"body":     {"update", []string{"svc update", "svc update malformed body", "svc update missing required attributes"}},
+		"no-body":  {"list", []string{"svc list"}},
+		"security": {"secure", nil},
+	}
+	for k, c := range cases {
+		t.Run(k, func(t *testing.T) {
+			got := contractCases(svc.Endpoint(c.Endpoint), expr.Root.API.ExampleGenerator)
+			if len(got) != len(c.Expected) {
+				t.Fatalf("got %d cases, expected %d", len(got), len(c.Expected))
+			}
+			for i, tc := range got {
+				if tc.Name != c.Expected[i] {
+					t.Errorf("got case %q, expected %q", tc.Name, c.Expected[i])
+				}
+			}
+		})
+	}
+}
+
+func TestContractCasesFeatures(t *testing.T) {
+	root := RunHTTPDSL(t, testdata.ContractFeaturesDSL)
+	svc := root.API.HTTP.Service("svc")
+	cases := map[string]struct {
+		Endpoint string
+		// output
+		Header [][2]string
+		Body   string
+	}{
+		"jsonapi":        {"show", [][2]string{{"Accept", "application/vnd.api+json"}}, ""},
+		"field-presence": {"patch", [][2]string{{"Content-Type", "application/json"}}, `{"name":"goa"}`},
+		"cloudevents": {"receive", [][2]string{
+			{"Content-Type", "application/json"},
+			{"ce-type", "com.example.created"},
+			{"ce-specversion", "1.0"},
+			{"ce-id", "contract"},
+			{"ce-source", "/contract"},
+		}, `{"name":"goa"}`},
+	}
+	for k, c := range cases {
+		t.Run(k, func(t *testing.T) {
+			got := contractCases(svc.Endpoint(c.Endpoint), expr.Root.API.ExampleGenerator)
+			if len(got) == 0 {
+				t.Fatal("got no case")
+			}
+			if !reflect.DeepEqual(got[0].Header, c.Header) {
+				t.Errorf("got header %v, expected %v", got[0].Header, c.Header)
+			}
+			if got[0].Body != c.Body {
+				t.Errorf("got body %q, expected %q", got[0].Body, c.Body)
+			}
+		})
+	}
+}
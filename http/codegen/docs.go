@@ -0,0 +1,722 @@
+package codegen
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"goa.design/goa/v3/codegen"
+	"goa.design/goa/v3/expr"
+	"goa.design/goa/v3/http/codegen/openapi"
+)
+
+type (
+	// docsService is the data used to render the reference of a service.
+	docsService struct {
+		// Name is the service name.
+		Name string
+		// Description is the service description.
+		Description string
+		// Endpoints lists the service endpoints.
+		Endpoints []*docsEndpoint
+		// Types lists the user types used by the endpoints.
+		Types []*docsType
+	}
+
+	// docsEndpoint is the data used to render the reference of an endpoint.
+	docsEndpoint struct {
+		// Name is the endpoint name.
+		Name string
+		// Anchor is the name of the endpoint section anchor.
+		Anchor string
+		// Description is the endpoint description.
+		Description string
+		// Routes lists the endpoint routes.
+		Routes []*docsRoute
+		// Params lists the non-empty groups of path parameters, query
+		// string parameters, headers and cookies.
+		Params []*docsFieldGroup
+		// Body describes the request body if any.
+		Body *docsBody
+		// Responses lists the success responses.
+		Responses []*docsResponse
+		// Errors lists the error responses.
+		Errors []*docsResponse
+		// Curl is an example curl command that sends a request to the
+		// endpoint.
+		Curl string
+	}
+
+	// docsRoute is a HTTP method and path.
+	docsRoute struct {
+		Method string
+		Path   string
+	}
+
+	// docsFieldGroup is a titled list of fields.
+	docsFieldGroup struct {
+		Title  string
+		Fields []*docsField
+	}
+
+	// docsBody describes a request or response body.
+	docsBody struct {
+		// Type is the name of the body type.
+		Type string
+		// Fields lists the body fields if the body is an object.
+		Fields []*docsField
+	}
+
+	// docsResponse describes a HTTP response.
+	docsResponse struct {
+		// Name is the error name for error responses.
+		Name string
+		// Status is the HTTP status code.
+		Status int
+		// StatusText is the text describing the status code.
+		StatusText string
+		// Description describes the response.
+		Description string
+		// Headers lists the response headers.
+		Headers []*docsField
+		// Body describes the response body if any.
+		Body *docsBody
+	}
+
+	// docsType describes a user type.
+	docsType struct {
+		// Name is the type name.
+		Name string
+		// Anchor is the name of the type section anchor.
+		Anchor string
+		// Description is the type description.
+		Description string
+		// Body describes the type attributes.
+		Body *docsBody
+	}
+
+	// docsField describes a parameter, header or object attribute.
+	docsField struct {
+		Name        string
+		Type        string
+		Required    bool
+		Description string
+	}
+)
+
+// DocsFiles returns the files containing a human readable reference of the
+// HTTP services of the given root: one file per service and an index file.
+// format is either "markdown" or "asciidoc".
+func DocsFiles(root *expr.RootExpr, format string) ([]*codegen.File, error) {
+	if len(root.API.HTTP.Services) == 0 {
+		return nil, nil
+	}
+	var (
+		ext      string
+		indexT   string
+		serviceT string
+	)
+	switch format {
+	case "markdown":
+		ext, indexT, serviceT = ".md", docsIndexMarkdownT, docsServiceMarkdownT
+	case "asciidoc":
+		ext, indexT, serviceT = ".adoc", docsIndexAsciiDocT, docsServiceAsciiDocT
+	default:
+		return nil, fmt.Errorf("unknown documentation format %q, must be one of markdown or asciidoc", format)
+	}
+	var (
+		dir      = filepath.Join(codegen.Gendir, "http", "docs")
+		funcs    = map[string]any{"cell": docsCell, "anchor": docsAnchor}
+		services []*docsService
+		files    []*codegen.File
+	)
+	for _, svc := range root.API.HTTP.Services {
+		data := buildDocsService(root, svc)
+		services = append(services, data)
+		files = append(files, &codegen.File{
+			Path: filepath.Join(dir, codegen.SnakeCase(svc.Name())+ext),
+			SectionTemplates: []*codegen.SectionTemplate{{
+				Name:    "docs-service",
+				Source:  serviceT,
+				Data:    data,
+				FuncMap: funcs,
+			}},
+		})
+	}
+	title := root.API.Title
+	if title == "" {
+		title = root.API.Name
+	}
+	index := &codegen.File{
+		Path: filepath.Join(dir, "index"+ext),
+		SectionTemplates: []*codegen.SectionTemplate{{
+			Name:   "docs-index",
+			Source: indexT,
+			Data: map[string]any{
+				"Title":       title,
+				"Description": root.API.Description,
+				"Version":     root.API.Version,
+				"BaseURL":     exampleBaseURL(root),
+				"Services":    services,
+				"Ext":         ext,
+			},
+			FuncMap: map[string]any{"cell": docsCell, "snake": codegen.SnakeCase},
+		}},
+	}
+	return append([]*codegen.File{index}, files...), nil
+}
+
+// buildDocsService builds the data needed to render the reference of the
+// given HTTP service.
+func buildDocsService(root *expr.RootExpr, svc *expr.HTTPServiceExpr) *docsService {
+	var (
+		rand  = root.API.ExampleGenerator
+		types = make(map[string]*expr.AttributeExpr)
+	)
+	data := &docsService{Name: svc.Name(), Description: svc.Description()}
+	for _, e := range svc.HTTPEndpoints {
+		ed := &docsEndpoint{
+			Name:        e.Name(),
+			Anchor:      docsAnchor(e.Name()),
+			Description: e.Description(),
+		}
+		for _, r := range e.Routes {
+			for _, p := range r.FullPaths() {
+				ed.Routes = append(ed.Routes, &docsRoute{Method: r.Method, Path: p})
+			}
+		}
+		var wildcards []string
+		for _, r := range e.Routes {
+			for _, p := range r.FullPaths() {
+				wildcards = append(wildcards, expr.ExtractHTTPWildcards(p)...)
+			}
+		}
+		var pathParams, queryParams []*docsField
+		codegen.WalkMappedAttr(e.Params, func(n, pn string, required bool, at *expr.AttributeExpr) error { // nolint: errcheck
+			f := docsNewField(pn, at, required, types)
+			for _, w := range wildcards {
+				if w == n {
+					pathParams = append(pathParams, f)
+					return nil
+				}
+			}
+			queryParams = append(queryParams, f)
+			return nil
+		})
+		for _, g := range []*docsFieldGroup{
+			{Title: "Path parameters", Fields: pathParams},
+			{Title: "Query parameters", Fields: queryParams},
+			{Title: "Headers", Fields: docsMappedFields(e.Headers, types)},
+			{Title: "Cookies", Fields: docsMappedFields(e.Cookies, types)},
+		} {
+			if len(g.Fields) > 0 {
+				ed.Params = append(ed.Params, g)
+			}
+		}
+		if e.Body != nil && e.Body.Type != expr.Empty {
+			ed.Body = docsNewBody(e.Body, types)
+		}
+		for _, r := range e.Responses {
+			ed.Responses = append(ed.Responses, docsNewResponse("", r, types))
+		}
+		for _, he := range e.HTTPErrors {
+			// Error bodies are not documented, do not record their types.
+			resp := docsNewResponse(he.Name, he.Response, nil)
+			if resp.Description == "" {
+				resp.Description = he.Description
+			}
+			ed.Errors = append(ed.Errors, resp)
+		}
+		if !e.MethodExpr.IsStreaming() && len(e.Routes) > 0 {
+			ed.Curl = docsCurl(root, e, rand)
+		}
+		data.Endpoints = append(data.Endpoints, ed)
+	}
+	names := make([]string, 0, len(types))
+	for n := range types {
+		names = append(names, n)
+	}
+	sort.Strings(names)
+	for _, n := range names {
+		att := types[n]
+		data.Types = append(data.Types, &docsType{
+			Name:        n,
+			Anchor:      docsAnchor(n),
+			Description: att.Description,
+			Body:        docsNewBody(att, nil),
+		})
+	}
+	return data
+}
+
+// docsNewResponse builds the data describing the given response.
+func docsNewResponse(name string, r *expr.HTTPResponseExpr, types map[string]*expr.AttributeExpr) *docsResponse {
+	resp := &docsResponse{
+		Name:        name,
+		Status:      r.StatusCode,
+		StatusText:  http.StatusText(r.StatusCode),
+		Description: r.Description,
+		Headers:     docsMappedFields(r.Headers, types),
+	}
+	if r.Body != nil && r.Body.Type != expr.Empty {
+		resp.Body = docsNewBody(r.Body, types)
+	}
+	return resp
+}
+
+// docsNewBody describes the given body attribute. The fields of object bodies
+// are listed directly, the user types they use are recorded in types if not
+// nil.
+func docsNewBody(att *expr.AttributeExpr, types map[string]*expr.AttributeExpr) *docsBody {
+	body := &docsBody{Type: docsTypeName(att.Type)}
+	obj := expr.AsObject(att.Type)
+	if obj == nil {
+		docsCollectTypes(att.Type, types)
+		return body
+	}
+	for _, nat := range *obj {
+		body.Fields = append(body.Fields, docsNewField(nat.Name, nat.Attribute, att.IsRequired(nat.Name), types))
+	}
+	return body
+}
+
+// docsMappedFields describes the attributes of the given mapped attribute
+// (headers or cookies) using their HTTP names.
+func docsMappedFields(m *expr.MappedAttributeExpr, types map[string]*expr.AttributeExpr) []*docsField {
+	var fields []*docsField
+	codegen.WalkMappedAttr(m, func(_, elem string, required bool, at *expr.AttributeExpr) error { // nolint: errcheck
+		fields = append(fields, docsNewField(elem, at, required, types))
+		return nil
+	})
+	return fields
+}
+
+// docsNewField describes the attribute with the given name.
+func docsNewField(name string, att *expr.AttributeExpr, required bool, types map[string]*expr.AttributeExpr) *docsField {
+	docsCollectTypes(att.Type, types)
+	desc := att.Description
+	if att.DefaultValue != nil {
+		if desc != "" {
+			desc += " "
+		}
+		desc += "(default: " + exampleString(att.DefaultValue, ", ") + ")"
+	}
+	return &docsField{
+		Name:        name,
+		Type:        docsTypeName(att.Type),
+		Required:    required,
+		Description: desc,
+	}
+}
+
+// docsCollectTypes records the object user types used by dt in types.
+func docsCollectTypes(dt expr.DataType, types map[string]*expr.AttributeExpr) {
+	if types == nil {
+		return
+	}
+	switch actual := dt.(type) {
+	case expr.UserType:
+		if _, ok := types[actual.Name()]; ok {
+			return
+		}
+		if expr.IsObject(actual) {
+			types[actual.Name()] = actual.Attribute()
+			for _, nat := range *expr.AsObject(actual) {
+				docsCollectTypes(nat.Attribute.Type, types)
+			}
+			return
+		}
+		docsCollectTypes(actual.Attribute().Type, types)
+	case *expr.Array:
+		docsCollectTypes(actual.ElemType.Type, types)
+	case *expr.Map:
+		docsCollectTypes(actual.KeyType.Type, types)
+		docsCollectTypes(actual.ElemType.Type, types)
+	case *expr.Union:
+		for _, nat := range actual.Values {
+			docsCollectTypes(nat.Attribute.Type, types)
+		}
+	}
+}
+
+// docsTypeName returns the name of the given type as shown in the reference.
+func docsTypeName(dt expr.DataType) string {
+	switch actual := dt.(type) {
+	case expr.UserType:
+		if expr.IsObject(actual) {
+			return actual.Name()
+		}
+		return docsTypeName(actual.Attribute().Type)
+	case *expr.Array:
+		return "array of " + docsTypeName(actual.ElemType.Type)
+	case *expr.Map:
+		return "map of " + docsTypeName(actual.KeyType.Type) + " to " + docsTypeName(actual.ElemType.Type)
+	case *expr.Union:
+		names := make([]string, len(actual.Values))
+		for i, nat := range actual.Values {
+			names[i] = docsTypeName(nat.Attribute.Type)
+		}
+		return "one of " + strings.Join(names, ", ")
+	default:
+		return dt.Name()
+	}
+}
+
+// docsCurl returns an example curl command sending a request to the first
+// route of the given endpoint using the examples defined in the design.
+func docsCurl(root *expr.RootExpr, e *expr.HTTPEndpointExpr, rand *expr.ExampleGenerator) string {
+	r := e.Routes[0]
+	path := r.FullPaths()[0]
+	wildcards := expr.ExtractHTTPWildcards(path)
+	var query []string
+	codegen.WalkMappedAttr(e.Params, func(n, pn string, _ bool, at *expr.AttributeExpr) error { // nolint: errcheck
+		ex := at.Example(rand)
+		for _, w := range wildcards {
+			if n == w {
+				path = strings.NewReplacer("{"+w+"}", url.PathEscape(exampleString(ex, ",")), "{*"+w+"}", exampleString(ex, ",")).Replace(path)
+				return nil
+			}
+		}
+		if arr, ok := exampleSlice(ex); ok {
+			for _, v := range arr {
+				query = append(query, url.QueryEscape(pn)+"="+url.QueryEscape(exampleString(v, ",")))
+			}
+			return nil
+		}
+		query = append(query, url.QueryEscape(pn)+"="+url.QueryEscape(exampleString(ex, ",")))
+		return nil
+	})
+	u := exampleBaseURL(root) + path
+	if len(query) > 0 {
+		u += "?" + strings.Join(query, "&")
+	}
+	args := []string{"curl", "-X", r.Method, docsQuote(u)}
+	expr.WalkMappedAttr(e.Headers, func(_, elem string, at *expr.AttributeExpr) error { // nolint: errcheck
+		args = append(args, "-H", docsQuote(elem+": "+exampleString(at.Example(rand), ", ")))
+		return nil
+	})
+	var cookies []string
+	expr.WalkMappedAttr(e.Cookies, func(_, elem string, at *expr.AttributeExpr) error { // nolint: errcheck
+		cookies = append(cookies, elem+"="+exampleString(at.Example(rand), ","))
+		return nil
+	})
+	if len(cookies) > 0 {
+		args = append(args, "-b", docsQuote(strings.Join(cookies, "; ")))
+	}
+	if e.Body != nil && e.Body.Type != expr.Empty && !e.SkipRequestBodyEncodeDecode {
+		ex := openapi.ToStringMap(e.Body.Example(rand))
+		if e.MultipartRequest {
+			if m, ok := ex.(map[string]any); ok {
+				keys := make([]string, 0, len(m))
+				for k := range m {
+					keys = append(keys, k)
+				}
+				sort.Strings(keys)
+				for _, k := range keys {
+					args = append(args, "-F", docsQuote(k+"="+exampleString(m[k], ",")))
+				}
+			}
+		} else if b, err := json.Marshal(ex); err == nil {
+			args = append(args, "-H", docsQuote("Content-Type: application/json"), "-d", docsQuote(string(b)))
+		}
+	}
+	return strings.Join(args, " ")
+}
+
+// docsQuote quotes s for use in a POSIX shell command line.
+func docsQuote(s string) string {
+	return "'" + strings.ReplaceAll(s, "'", `'\''`) + "'"
+}
+
+// docsCell formats s so that it can be used in a table cell.
+func docsCell(s string) string {
+	s = strings.Join(strings.Fields(s), " ")
+	return strings.ReplaceAll(s, "|", `\|`)
+}
+
+// docsAnchor returns the anchor of the section with the given title.
+func docsAnchor(s string) string {
+	return strings.ReplaceAll(codegen.SnakeCase(s), "_", "-")
+}
+
+// input: map[string]any{"Title": string, "Description": string, "Version":
+// string, "BaseURL": string, "Services": []*docsService, "Ext": string}
+const docsIndexMarkdownT = `# {{ .Title }}
+{{- if .Description }}
+
+{{ .Description }}
+{{- end }}
+{{- if .Version }}
+
+Version: {{ .Version }}
+{{- end }}
+
+Base URL: ` + "`{{ .BaseURL }}`" + `
+
+| Service | Description |
+|---------|-------------|
+{{- range .Services }}
+| [{{ .Name }}]({{ snake .Name }}{{ $.Ext }}) | {{ cell .Description }} |
+{{- end }}
+`
+
+// input: *docsService
+const docsServiceMarkdownT = `# {{ .Name }}
+{{- if .Description }}
+
+{{ .Description }}
+{{- end }}
+
+## Endpoints
+
+| Endpoint | Method | Path | Description |
+|----------|--------|------|-------------|
+{{- range $e := .Endpoints }}{{ range .Routes }}
+| [{{ $e.Name }}](#{{ $e.Anchor }}) | {{ .Method }} | ` + "`{{ .Path }}`" + ` | {{ cell $e.Description }} |
+{{- end }}{{ end }}
+{{- range .Endpoints }}
+
+## {{ .Name }}
+{{- if .Description }}
+
+{{ .Description }}
+{{- end }}
+{{ range .Routes }}
+` + "`{{ .Method }} {{ .Path }}`" + `
+{{- end }}
+{{- range .Params }}
+
+### {{ .Title }}
+
+{{ template "fields" .Fields }}
+{{- end }}
+{{- with .Body }}
+
+### Request body
+
+{{ template "body" . }}
+{{- end }}
+{{- if .Responses }}
+
+### Responses
+{{- range .Responses }}
+
+#### {{ .Status }} {{ .StatusText }}
+{{- if .Description }}
+
+{{ .Description }}
+{{- end }}
+{{- with .Headers }}
+
+{{ template "fields" . }}
+{{- end }}
+{{- with .Body }}
+
+{{ template "body" . }}
+{{- end }}
+{{- end }}
+{{- end }}
+{{- if .Errors }}
+
+### Errors
+
+| Name | Status | Description |
+|------|--------|-------------|
+{{- range .Errors }}
+| {{ .Name }} | {{ .Status }} {{ .StatusText }} | {{ cell .Description }} |
+{{- end }}
+{{- end }}
+{{- if .Curl }}
+
+### Example
+
+` + "```sh" + `
+{{ .Curl }}
+` + "```" + `
+{{- end }}
+{{- end }}
+{{- if .Types }}
+
+## Types
+{{- range .Types }}
+
+### {{ .Name }}
+{{- if .Description }}
+
+{{ .Description }}
+{{- end }}
+
+{{ template "body" .Body }}
+{{- end }}
+{{- end }}
+` + docsMarkdownPartialsT
+
+const docsMarkdownPartialsT = `
+{{- define "body" }}
+{{- if .Fields }}
+{{- template "fields" .Fields }}
+{{- else -}}
+Type: {{ .Type }}
+{{- end }}
+{{- end }}
+{{- define "fields" -}}
+| Name | Type | Required | Description |
+|------|------|----------|-------------|
+{{- range . }}
+| {{ .Name }} | {{ .Type }} | {{ if .Required }}yes{{ else }}no{{ end }} | {{ cell .Description }} |
+{{- end }}
+{{- end }}
+`
+
+// input: map[string]any{"Title": string, "Description": string, "Version":
+// string, "BaseURL": string, "Services": []*docsService, "Ext": string}
+const docsIndexAsciiDocT = `= {{ .Title }}
+{{- if .Description }}
+
+{{ .Description }}
+{{- end }}
+{{- if .Version }}
+
+Version: {{ .Version }}
+{{- end }}
+
+Base URL: ` + "`{{ .BaseURL }}`" + `
+
+[cols="1,3"]
+|===
+|Service |Description
+{{ range .Services }}
+|xref:{{ snake .Name }}{{ $.Ext }}[{{ .Name }}]
+|{{ cell .Description }}
+{{ end -}}
+|===
+`
+
+// input: *docsService
+const docsServiceAsciiDocT = `= {{ .Name }}
+{{- if .Description }}
+
+{{ .Description }}
+{{- end }}
+
+== Endpoints
+
+[cols="1,1,2,3"]
+|===
+|Endpoint |Method |Path |Description
+{{ range $e := .Endpoints }}{{ range .Routes }}
+|<<{{ $e.Anchor }},{{ $e.Name }}>>
+|{{ .Method }}
+|` + "`{{ .Path }}`" + `
+|{{ cell $e.Description }}
+{{ end }}{{ end -}}
+|===
+{{- range .Endpoints }}
+
+[#{{ .Anchor }}]
+== {{ .Name }}
+{{- if .Description }}
+
+{{ .Description }}
+{{- end }}
+{{ range .Routes }}
+` + "`{{ .Method }} {{ .Path }}`" + `
+{{- end }}
+{{- range .Params }}
+
+=== {{ .Title }}
+
+{{ template "fields" .Fields }}
+{{- end }}
+{{- with .Body }}
+
+=== Request body
+
+{{ template "body" . }}
+{{- end }}
+{{- if .Responses }}
+
+=== Responses
+{{- range .Responses }}
+
+==== {{ .Status }} {{ .StatusText }}
+{{- if .Description }}
+
+{{ .Description }}
+{{- end }}
+{{- with .Headers }}
+
+{{ template "fields" . }}
+{{- end }}
+{{- with .Body }}
+
+{{ template "body" . }}
+{{- end }}
+{{- end }}
+{{- end }}
+{{- if .Errors }}
+
+=== Errors
+
+[cols="1,1,3"]
+|===
+|Name |Status |Description
+{{ range .Errors }}
+|{{ .Name }}
+|{{ .Status }} {{ .StatusText }}
+|{{ cell .Description }}
+{{ end -}}
+|===
+{{- end }}
+{{- if .Curl }}
+
+=== Example
+
+[source,sh]
+----
+{{ .Curl }}
+----
+{{- end }}
+{{- end }}
+{{- if .Types }}
+
+== Types
+{{- range .Types }}
+
+[#{{ .Anchor }}]
+=== {{ .Name }}
+{{- if .Description }}
+
+{{ .Description }}
+{{- end }}
+
+{{ template "body" .Body }}
+{{- end }}
+{{- end }}
+` + docsAsciiDocPartialsT
+
+const docsAsciiDocPartialsT = `
+{{- define "body" }}
+{{- if .Fields }}
+{{- template "fields" .Fields }}
+{{- else -}}
+Type: {{ .Type }}
+{{- end }}
+{{- end }}
+{{- define "fields" -}}
+[cols="1,1,1,3"]
+|===
+|Name |Type |Required |Description
+{{ range . }}
+|{{ .Name }}
+|{{ .Type }}
+|{{ if .Required }}yes{{ else }}no{{ end }}
+|{{ cell .Description }}
+{{ end -}}
+|===
+{{- end }}
+`
@@ -0,0 +1,55 @@
+package codegen
+
+import (
+	"bytes"
+	"path/filepath"
+	"testing"
+
+	"goa.design/goa/v3/codegen"
+	"goa.design/goa/v3/http/codegen/testdata"
+)
+
+func TestDocsFiles(t *testing.T) {
+	cases := []struct {
+		Format string
+		Paths  []string
+		Index  string
+		Code   string
+	}{
+		{"markdown", []string{"index.md", "sommelier.md"}, testdata.DocsMarkdownIndexCode, testdata.DocsMarkdownServiceCode},
+		{"asciidoc", []string{"index.adoc", "sommelier.adoc"}, testdata.DocsAsciiDocIndexCode, testdata.DocsAsciiDocServiceCode},
+	}
+	for _, c := range cases {
+		t.Run(c.Format, func(t *testing.T) {
+			root := RunHTTPDSL(t, testdata.DocsDSL)
+			fs, err := DocsFiles(root, c.Format)
+			if err != nil {
+				t.Fatalf("DocsFiles failed with %s", err)
+			}
+			if len(fs) != len(c.Paths) {
+				t.Fatalf("got %d files, expected %d", len(fs), len(c.Paths))
+			}
+			for i, p := range c.Paths {
+				if fs[i].Path != filepath.Join("gen", "http", "docs", p) {
+					t.Errorf("got path %q, expected %q", fs[i].Path, p)
+				}
+			}
+			for i, expected := range []string{c.Index, c.Code} {
+				var buf bytes.Buffer
+				if err := fs[i].SectionTemplates[0].Write(&buf); err != nil {
+					t.Fatal(err)
+				}
+				if got := buf.String(); got != expected {
+					t.Errorf("invalid %s content, got:\n%s\ngot vs. expected:\n%s", c.Paths[i], got, codegen.Diff(t, got, expected))
+				}
+			}
+		})
+	}
+}
+
+func TestDocsFilesInvalidFormat(t *testing.T) {
+	root := RunHTTPDSL(t, testdata.DocsDSL)
+	if _, err := DocsFiles(root, "html"); err == nil {
+		t.Error("expected an error for an unknown format")
+	}
+}
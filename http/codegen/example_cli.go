@@ -1,7 +1,6 @@
 package codegen
 
 import (
-	"os"
 	"path/filepath"
 	"strings"
 
@@ -27,9 +26,6 @@ func ExampleCLIFiles(genpkg string, root *expr.RootExpr) []*codegen.File {
 func exampleCLI(genpkg string, root *expr.RootExpr, svr *expr.ServerExpr) *codegen.File {
 	svrdata := example.Servers.Get(svr)
 	path := filepath.Join("cmd", svrdata.Dir+"-cli", "http.go")
-	if _, err := os.Stat(path); !os.IsNotExist(err) {
-		return nil // file already exists, skip it.
-	}
 	var (
 		rootPath string
 		apiPkg   string
@@ -1,7 +1,6 @@
 package codegen
 
 import (
-	"os"
 	"path"
 	"path/filepath"
 	"strings"
@@ -100,9 +99,24 @@ func exampleServer(genpkg string, root *expr.RootExpr, svr *expr.ServerExpr) *co
 				"Services": svcdata,
 				"APIPkg":   apiPkg,
 			},
-			FuncMap: map[string]any{"needStream": needStream, "hasWebSocket": hasWebSocket},
+			FuncMap: map[string]any{"needStream": needStream, "hasWebSocket": hasWebSocket, "hasFeatureFlags": hasFeatureFlags, "hasIdempotentMethods": hasIdempotentMethods, "hasCachedMethods": hasCachedMethods},
 		},
 		{Name: "server-http-middleware", Source: httpSvrMiddlewareT},
+	}
+	var websvcdata []*ServiceData
+	for _, svc := range svrdata.WebServices {
+		websvcdata = append(websvcdata, HTTPServices.Get(svc))
+	}
+	if len(websvcdata) > 0 {
+		sections = append(sections, &codegen.SectionTemplate{
+			Name:   "server-http-grpc-web",
+			Source: httpSvrGRPCWebT,
+			Data: map[string]any{
+				"Services": websvcdata,
+			},
+		})
+	}
+	sections = append(sections, []*codegen.SectionTemplate{
 		{
 			Name:   "server-http-end",
 			Source: httpSvrEndT,
@@ -111,7 +125,7 @@ func exampleServer(genpkg string, root *expr.RootExpr, svr *expr.ServerExpr) *co
 			},
 		},
 		{Name: "server-http-errorhandler", Source: httpSvrErrorHandlerT},
-	}
+	}...)
 
 	return &codegen.File{Path: fpath, SectionTemplates: sections, SkipExist: true}
 }
@@ -120,9 +134,6 @@ func exampleServer(genpkg string, root *expr.RootExpr, svr *expr.ServerExpr) *co
 // and encoders.
 func dummyMultipartFile(genpkg string, root *expr.RootExpr, svc *expr.HTTPServiceExpr) *codegen.File {
 	mpath := "multipart.go"
-	if _, err := os.Stat(mpath); !os.IsNotExist(err) {
-		return nil // file already exists, skip it.
-	}
 	var (
 		sections []*codegen.SectionTemplate
 		mustGen  bool
@@ -255,6 +266,21 @@ func handleHTTPServer(ctx context.Context, u *url.URL{{ range $.Services }}{{ if
 		{{-  else }}
 		{{ .Service.VarName }}Server = {{ .Service.PkgName }}svr.New(nil, mux, dec, enc, eh, nil{{ range .FileServers }}, nil{{ end }})
 		{{-  end }}
+		{{- if hasFeatureFlags $svc }}
+		// Methods gated by a feature flag are enabled by setting the
+		// FEATURE_<FLAG> environment variable to true.
+		{{ .Service.VarName }}Server.UseFeatureFlags(middleware.EnvFlags("feature_"))
+		{{- end }}
+		{{- if hasIdempotentMethods $svc }}
+		// Responses to idempotent requests are kept in memory, use a store
+		// shared by all the server instances in production.
+		{{ .Service.VarName }}Server.UseIdempotency(httpmdlwr.NewMemoryIdempotencyStore(24 * time.Hour))
+		{{- end }}
+		{{- if hasCachedMethods $svc }}
+		// Cached responses are kept in memory, use a store shared by all
+		// the server instances in production.
+		{{ .Service.VarName }}Server.UseCache(httpmdlwr.NewResponseCache(httpmdlwr.NewMemoryCacheStore()))
+		{{- end }}
 	{{- end }}
 	{{- if .Services }}
 		if debug {
@@ -283,6 +309,13 @@ func handleHTTPServer(ctx context.Context, u *url.URL{{ range $.Services }}{{ if
 	}
 `
 
+	// input: map[string]any{"Services":[]*ServiceData}
+	httpSvrGRPCWebT = `
+	// Serve the gRPC-Web and Connect requests made to the gRPC endpoints of
+	// the services on the same port, see handleGRPCWeb.
+	handler = handleGRPCWeb(handler{{ range .Services }}{{ if .Service.Methods }}, {{ .Service.VarName }}Endpoints{{ end }}{{ end }}, logger)
+`
+
 	// input: map[string]any{"Services":[]*ServiceData}
 	httpSvrEndT = `
 	// Start HTTP server using default configuration, change the code to
@@ -72,6 +72,7 @@ func TestExampleServerFiles(t *testing.T) {
 			{"server-hosting-service-with-file-server", ctestdata.ServerHostingServiceWithFileServerDSL, testdata.ServerHostingServiceWithFileServerHandlerCode},
 			{"server-hosting-service-subset", ctestdata.ServerHostingServiceSubsetDSL, testdata.ServerHostingServiceSubsetServerHandleCode},
 			{"server-hosting-multiple-services", ctestdata.ServerHostingMultipleServicesDSL, testdata.ServerHostingMultipleServicesServerHandleCode},
+			{"server-hosting-grpc-web", ctestdata.ServerHostingGRPCWebDSL, testdata.ServerHostingGRPCWebServerHandleCode},
 			{"streaming", testdata.StreamingMultipleServicesDSL, testdata.StreamingServerHandleCode},
 		}
 		for _, c := range cases {
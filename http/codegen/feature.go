@@ -0,0 +1,60 @@
+package codegen
+
+import (
+	"strconv"
+
+	"goa.design/goa/v3/expr"
+)
+
+// FeatureFlagData contains the data needed to render the code that gates an
+// endpoint with a feature flag.
+type FeatureFlagData struct {
+	// Name is the name of the feature flag.
+	Name string
+	// Status is the Go constant of the HTTP status code of the responses
+	// sent while the flag is disabled.
+	Status string
+}
+
+// featureFlag returns the feature flag gating the given endpoint as defined by
+// the "feature:flag" meta of the method or of its service, nil if the endpoint
+// is not gated.
+func featureFlag(e *expr.HTTPEndpointExpr) *FeatureFlagData {
+	meta := e.MethodExpr.Meta
+	if _, ok := meta["feature:flag"]; !ok {
+		meta = e.MethodExpr.Service.Meta
+	}
+	name, ok := meta.Last("feature:flag")
+	if !ok || name == "" {
+		return nil
+	}
+	status := 404
+	if s, ok := meta.Last("feature:flag:status"); ok {
+		if code, err := strconv.Atoi(s); err == nil {
+			status = code
+		}
+	}
+	return &FeatureFlagData{Name: name, Status: statusCodeToHTTPConst(status)}
+}
+
+// hasFeatureFlags returns true if at least one of the given endpoints is gated
+// by a feature flag.
+func hasFeatureFlags(data *ServiceData) bool {
+	for _, e := range data.Endpoints {
+		if e.FeatureFlag != nil {
+			return true
+		}
+	}
+	return false
+}
+
+// input: ServiceData
+const serverFeatureFlagsT = `{{ printf "UseFeatureFlags gates the handlers of the %s methods configured with the \"feature:flag\" meta using the given flag provider: the requests made while the flag of a method is disabled are rejected without calling the method." .Service.Name | comment }}
+func (s *{{ .ServerStruct }}) UseFeatureFlags(p middleware.FlagProvider) {
+{{- range $e := .Endpoints }}
+	{{- with .FeatureFlag }}
+	s.{{ $e.Method.VarName }} = httpmdlwr.FeatureFlag(p, {{ printf "%q" .Name }}, {{ .Status }})(s.{{ $e.Method.VarName }})
+	{{- end }}
+{{- end }}
+}
+`
@@ -0,0 +1,39 @@
+package codegen
+
+import (
+	"testing"
+
+	"goa.design/goa/v3/codegen"
+	"goa.design/goa/v3/expr"
+	"goa.design/goa/v3/http/codegen/testdata"
+)
+
+func TestServerFeatureFlags(t *testing.T) {
+	cases := []struct {
+		Name string
+		DSL  func()
+		Code string
+	}{
+		{"method", testdata.FeatureFlagMethodDSL, testdata.FeatureFlagMethodCode},
+		{"service", testdata.FeatureFlagServiceDSL, testdata.FeatureFlagServiceCode},
+	}
+	for _, c := range cases {
+		t.Run(c.Name, func(t *testing.T) {
+			RunHTTPDSL(t, c.DSL)
+			fs := ServerFiles("", expr.Root)
+			sections := fs[0].Section("server-feature-flags")
+			if len(sections) != 1 {
+				t.Fatalf("got %d sections, expected 1", len(sections))
+			}
+			code := codegen.SectionCode(t, sections[0])
+			if code != c.Code {
+				t.Errorf("invalid code, got:\n%s\ngot vs. expected:\n%s", code, codegen.Diff(t, code, c.Code))
+			}
+		})
+	}
+
+	RunHTTPDSL(t, testdata.ServerBatchDSL)
+	if sections := ServerFiles("", expr.Root)[0].Section("server-feature-flags"); len(sections) != 0 {
+		t.Errorf("got %d sections, expected none", len(sections))
+	}
+}
@@ -0,0 +1,185 @@
+package codegen
+
+import (
+	"encoding/json"
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"goa.design/goa/v3/codegen"
+	"goa.design/goa/v3/expr"
+	"goa.design/goa/v3/http/codegen/openapi"
+)
+
+type (
+	// fuzzDecoderData contains the data needed to render the fuzz target of
+	// a request decoder.
+	fuzzDecoderData struct {
+		// Name is the name of the fuzz target.
+		Name string
+		// Decoder is the name of the request decoder function.
+		Decoder string
+		// ServiceName is the name of the service.
+		ServiceName string
+		// MethodName is the name of the method.
+		MethodName string
+		// Verb is the HTTP method of the request.
+		Verb string
+		// Pattern is the route path used to mount the decoder.
+		Pattern string
+		// Path is the request path built from the design examples.
+		Path string
+		// Query is the query string used to seed the corpus.
+		Query string
+		// Header lists the request headers.
+		Header [][2]string
+		// Body is the request body used to seed the corpus.
+		Body string
+	}
+
+	// fuzzValidatorData contains the data needed to render the fuzz target
+	// of a request body validation function.
+	fuzzValidatorData struct {
+		// Name is the name of the fuzz target.
+		Name string
+		// VarName is the Go name of the request body type.
+		VarName string
+		// Body is the JSON representation of the type example used to
+		// seed the corpus.
+		Body string
+	}
+)
+
+// ServerFuzzFiles returns the fuzz tests of the HTTP server request decoders
+// and request body validation functions. Running the tests with "go test
+// -fuzz" feeds the generated code with arbitrary request bodies and query
+// strings in order to find inputs that cause panics or excessive allocations.
+// The files are generated by the "gen" command when given the -fuzz flag.
+func ServerFuzzFiles(root *expr.RootExpr) []*codegen.File {
+	var fw []*codegen.File
+	for _, svc := range root.API.HTTP.Services {
+		if f := serverFuzz(svc); f != nil {
+			fw = append(fw, f)
+		}
+	}
+	return fw
+}
+
+// serverFuzz returns the file containing the fuzz targets of the given
+// service server, nil if the service has no request decoder.
+func serverFuzz(svc *expr.HTTPServiceExpr) *codegen.File {
+	var (
+		decoders   []*fuzzDecoderData
+		validators []*fuzzValidatorData
+
+		data = HTTPServices.Get(svc.Name())
+		seen = make(map[string]struct{})
+	)
+	for _, e := range svc.HTTPEndpoints {
+		ed := data.Endpoint(e.Name())
+		if !mustDecodeRequest(ed) || ed.MultipartRequestDecoder != nil {
+			continue
+		}
+		req := exampleRequest(e, expr.Root.API.ExampleGenerator)
+		if req == nil {
+			continue
+		}
+		p, q, _ := strings.Cut(req.Path, "?")
+		decoders = append(decoders, &fuzzDecoderData{
+			Name:        "Fuzz" + ed.RequestDecoder,
+			Decoder:     ed.RequestDecoder,
+			ServiceName: data.Service.Name,
+			MethodName:  ed.Method.Name,
+			Verb:        ed.Routes[0].Verb,
+			Pattern:     ed.Routes[0].Path,
+			Path:        p,
+			Query:       q,
+			Header:      req.Header,
+			Body:        req.Body,
+		})
+		body := ed.Payload.Request.ServerBody
+		if body == nil || body.ValidateDef == "" {
+			continue
+		}
+		if _, ok := seen[body.VarName]; ok {
+			continue
+		}
+		seen[body.VarName] = struct{}{}
+		ex, err := json.Marshal(openapi.ToStringMap(body.Example))
+		if err != nil {
+			continue
+		}
+		validators = append(validators, &fuzzValidatorData{
+			Name:    fmt.Sprintf("FuzzValidate%s", body.VarName),
+			VarName: body.VarName,
+			Body:    string(ex),
+		})
+	}
+	if len(decoders) == 0 {
+		return nil
+	}
+
+	path := filepath.Join(codegen.Gendir, "http", data.Service.PathName, "server", "fuzz_test.go")
+	imports := []*codegen.ImportSpec{
+		{Path: "bytes"},
+		{Path: "net/http"},
+		{Path: "net/http/httptest"},
+		{Path: "testing"},
+		codegen.GoaNamedImport("http", "goahttp"),
+	}
+	if len(validators) > 0 {
+		imports = append(imports, &codegen.ImportSpec{Path: "encoding/json"})
+	}
+	sections := []*codegen.SectionTemplate{
+		codegen.Header(svc.Name()+" HTTP server fuzz tests", "server", imports),
+	}
+	for _, d := range decoders {
+		sections = append(sections, &codegen.SectionTemplate{
+			Name:   "fuzz-request-decoder",
+			Source: fuzzRequestDecoderT,
+			Data:   d,
+		})
+	}
+	for _, v := range validators {
+		sections = append(sections, &codegen.SectionTemplate{
+			Name:   "fuzz-validate",
+			Source: fuzzValidateT,
+			Data:   v,
+		})
+	}
+	return &codegen.File{Path: path, SectionTemplates: sections}
+}
+
+// input: fuzzDecoderData
+const fuzzRequestDecoderT = `{{ printf "%s checks that %s does not panic when decoding arbitrary requests sent to the %s %s endpoint. The corpus is seeded with the examples defined in the design." .Name .Decoder .ServiceName .MethodName | comment }}
+func {{ .Name }}(f *testing.F) {
+	f.Add([]byte({{ printf "%q" .Body }}), {{ printf "%q" .Query }})
+	f.Fuzz(func(t *testing.T, body []byte, query string) {
+		mux := goahttp.NewMuxer()
+		decode := {{ .Decoder }}(mux, goahttp.RequestDecoder)
+		mux.Handle({{ printf "%q" .Verb }}, {{ printf "%q" .Pattern }}, func(w http.ResponseWriter, r *http.Request) {
+			_, _ = decode(r)
+		})
+		req := httptest.NewRequest({{ printf "%q" .Verb }}, {{ printf "%q" .Path }}, bytes.NewReader(body))
+		req.URL.RawQuery = query
+	{{- range .Header }}
+		req.Header.Add({{ printf "%q" (index . 0) }}, {{ printf "%q" (index . 1) }})
+	{{- end }}
+		mux.ServeHTTP(httptest.NewRecorder(), req)
+	})
+}
+`
+
+// input: fuzzValidatorData
+const fuzzValidateT = `{{ printf "%s checks that Validate%s does not panic when validating arbitrary JSON documents. The corpus is seeded with the example defined in the design." .Name .VarName | comment }}
+func {{ .Name }}(f *testing.F) {
+	f.Add([]byte({{ printf "%q" .Body }}))
+	f.Fuzz(func(t *testing.T, data []byte) {
+		var body {{ .VarName }}
+		if err := json.Unmarshal(data, &body); err != nil {
+			return
+		}
+		_ = Validate{{ .VarName }}(&body)
+	})
+}
+`
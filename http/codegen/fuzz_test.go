@@ -0,0 +1,31 @@
+package codegen
+
+import (
+	"bytes"
+	"path/filepath"
+	"testing"
+
+	"goa.design/goa/v3/codegen"
+	"goa.design/goa/v3/http/codegen/testdata"
+)
+
+func TestServerFuzzFiles(t *testing.T) {
+	root := RunHTTPDSL(t, testdata.FuzzDSL)
+	fs := ServerFuzzFiles(root)
+	if len(fs) != 1 {
+		t.Fatalf("got %d files, expected 1", len(fs))
+	}
+	if fs[0].Path != filepath.Join("gen", "http", "svc", "server", "fuzz_test.go") {
+		t.Errorf("invalid path %q", fs[0].Path)
+	}
+	var buf bytes.Buffer
+	for _, s := range fs[0].SectionTemplates[1:] {
+		if err := s.Write(&buf); err != nil {
+			t.Fatal(err)
+		}
+	}
+	code := codegen.FormatTestCode(t, "package foo\n"+buf.String())
+	if code != testdata.FuzzCode {
+		t.Errorf("invalid code, got:\n%s\ngot vs. expected:\n%s", code, codegen.Diff(t, code, testdata.FuzzCode))
+	}
+}
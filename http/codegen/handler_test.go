@@ -20,11 +20,16 @@ func TestHandlerInit(t *testing.T) {
 		{"no payload no result", testdata.ServerNoPayloadNoResultDSL, testdata.ServerNoPayloadNoResultHandlerConstructorCode},
 		{"no payload no result with a redirect", testdata.ServerNoPayloadNoResultWithRedirectDSL, testdata.ServerNoPayloadNoResultWithRedirectHandlerConstructorCode},
 		{"payload no result", testdata.ServerPayloadNoResultDSL, testdata.ServerPayloadNoResultHandlerConstructorCode},
+		{"payload migration default", testdata.PayloadBodyUserMigrationDSL, testdata.ServerMigrationDefaultHandlerConstructorCode},
 		{"payload no result with a redirect", testdata.ServerPayloadNoResultWithRedirectDSL, testdata.ServerPayloadNoResultWithRedirectHandlerConstructorCode},
 		{"no payload result", testdata.ServerNoPayloadResultDSL, testdata.ServerNoPayloadResultHandlerConstructorCode},
 		{"payload result", testdata.ServerPayloadResultDSL, testdata.ServerPayloadResultHandlerConstructorCode},
 		{"payload result error", testdata.ServerPayloadResultErrorDSL, testdata.ServerPayloadResultErrorHandlerConstructorCode},
 		{"skip response body encode decode", testdata.ServerSkipResponseBodyEncodeDecodeDSL, testdata.ServerSkipResponseBodyEncodeDecodeCode},
+		{"jsonapi result", testdata.JSONAPIErrorResponseDSL, testdata.ServerJSONAPIHandlerConstructorCode},
+		{"field selection", testdata.ServerFieldSelectionDSL, testdata.ServerFieldSelectionHandlerConstructorCode},
+		{"criteria", testdata.ServerCriteriaDSL, testdata.ServerCriteriaHandlerConstructorCode},
+		{"cloudevents", testdata.ServerCloudEventsDSL, testdata.ServerCloudEventsHandlerConstructorCode},
 	}
 	for _, c := range cases {
 		t.Run(c.Name, func(t *testing.T) {
@@ -0,0 +1,23 @@
+package codegen
+
+// hasIdempotentMethods returns true if at least one of the given endpoints uses
+// the Idempotent DSL.
+func hasIdempotentMethods(data *ServiceData) bool {
+	for _, e := range data.Endpoints {
+		if e.IdempotencyKey != "" {
+			return true
+		}
+	}
+	return false
+}
+
+// input: ServiceData
+const serverIdempotencyT = `{{ printf "UseIdempotency records the responses of the %s methods that use the Idempotent DSL in store and replays them to the requests retried with the same idempotency key without calling the methods again." .Service.Name | comment }}
+func (s *{{ .ServerStruct }}) UseIdempotency(store httpmdlwr.IdempotencyStore, opts ...httpmdlwr.IdempotencyOption) {
+{{- range .Endpoints }}
+	{{- if .IdempotencyKey }}
+	s.{{ .Method.VarName }} = httpmdlwr.Idempotency(store, {{ printf "%q" .IdempotencyKey }}, opts...)(s.{{ .Method.VarName }})
+	{{- end }}
+{{- end }}
+}
+`
@@ -0,0 +1,294 @@
+package codegen
+
+import (
+	"encoding/json"
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"goa.design/goa/v3/codegen"
+	"goa.design/goa/v3/expr"
+	"goa.design/goa/v3/http/codegen/openapi"
+)
+
+// fastJSONData contains the data needed to render the tests and benchmarks of
+// the AppendJSON method of a response body type.
+type fastJSONData struct {
+	// VarName is the Go name of the response body type.
+	VarName string
+	// Example is the JSON representation of the type example used to
+	// initialize the benchmarked value.
+	Example string
+}
+
+// fastJSON returns true if the response body types of the given service must
+// implement the JSON encoding without reflection, that is if the service or
+// the API define the "http:json:fast" meta.
+func fastJSON(svc *expr.ServiceExpr) bool {
+	if _, ok := svc.Meta["http:json:fast"]; ok {
+		return true
+	}
+	_, ok := expr.Root.API.Meta["http:json:fast"]
+	return ok
+}
+
+// appendJSONCode returns the code of the AppendJSON method of the server
+// response body type built from the given user type. The code appends the
+// JSON encoding of the struct fields to the byte slice b without reflection.
+// It returns an empty string if the user type is not an object or if one of
+// its fields uses custom struct tags, a custom Go type or a union in which case
+// the type is encoded by encoding/json.
+func appendJSONCode(ut expr.UserType) string {
+	att := ut.Attribute()
+	if !expr.IsObject(att.Type) {
+		return ""
+	}
+	ma := expr.NewMappedAttributeExpr(att)
+	mat := ma.Attribute()
+	for _, nat := range *expr.AsObject(att.Type) {
+		if codegen.AttributeTags(mat, nat.Attribute) != "" || expr.IsUnion(nat.Attribute.Type) {
+			return ""
+		}
+		if t, _ := codegen.GetMetaType(nat.Attribute); t != "" {
+			return ""
+		}
+	}
+	lines := []string{"b = append(b, '{')"}
+	codegen.WalkMappedAttr(ma, func(name, elem string, _ bool, at *expr.AttributeExpr) error { // nolint: errcheck
+		var (
+			field = "body." + codegen.GoifyAtt(at, name, true)
+			ptr   = mat.IsPrimitivePointer(name, true) && at.Type != expr.Bytes && at.Type != expr.Any
+			src   = field
+		)
+		if ptr {
+			src = "*" + field
+		}
+		var (
+			key, _ = json.Marshal(elem)
+			value  = appendJSONValue(at, src, 0)
+		)
+		optional := !ma.IsRequired(name) && !ma.HasDefaultValue(name)
+		if optional {
+			// empty collections are omitted so they cannot be nil
+			if c := appendJSONCollection(at, src, 0); c != "" {
+				value = c
+			}
+		}
+		code := fmt.Sprintf("b = goahttp.AppendJSONField(b, %s)\n%s", goStringLiteral(string(key)+":"), value)
+		if optional {
+			cond := field + " != nil"
+			if expr.IsArray(at.Type) || expr.IsMap(at.Type) || at.Type == expr.Bytes {
+				cond = "len(" + field + ") > 0"
+			}
+			code = fmt.Sprintf("if %s {\n%s\n}", cond, code)
+		}
+		lines = append(lines, code)
+		return nil
+	})
+	code := strings.Join(lines, "\n")
+	if strings.Contains(code, "; err != nil {") {
+		code = "var err error\n" + code
+	}
+	return code
+}
+
+// appendJSONValue returns the code that appends the JSON encoding of src, a
+// value of the given attribute type, to b. depth is used to name the loop
+// variables.
+func appendJSONValue(att *expr.AttributeExpr, src string, depth int) string {
+	if c := appendJSONCollection(att, src, depth); c != "" {
+		return fmt.Sprintf("if %s == nil {\nb = append(b, \"null\"...)\n} else {\n%s\n}", src, c)
+	}
+	fallback := fmt.Sprintf("if b, err = goahttp.AppendJSON(b, %s); err != nil {\nreturn nil, err\n}", src)
+	switch dt := att.Type.(type) {
+	case expr.Primitive:
+		switch dt.Kind() {
+		case expr.BooleanKind:
+			return fmt.Sprintf("b = strconv.AppendBool(b, %s)", src)
+		case expr.IntKind, expr.Int32Kind:
+			return fmt.Sprintf("b = strconv.AppendInt(b, int64(%s), 10)", src)
+		case expr.Int64Kind:
+			return fmt.Sprintf("b = strconv.AppendInt(b, %s, 10)", src)
+		case expr.UIntKind, expr.UInt32Kind:
+			return fmt.Sprintf("b = strconv.AppendUint(b, uint64(%s), 10)", src)
+		case expr.UInt64Kind:
+			return fmt.Sprintf("b = strconv.AppendUint(b, %s, 10)", src)
+		case expr.Float32Kind:
+			return fmt.Sprintf("if b, err = goahttp.AppendJSONFloat(b, float64(%s), 32); err != nil {\nreturn nil, err\n}", src)
+		case expr.Float64Kind:
+			return fmt.Sprintf("if b, err = goahttp.AppendJSONFloat(b, %s, 64); err != nil {\nreturn nil, err\n}", src)
+		case expr.StringKind:
+			return fmt.Sprintf("b = goahttp.AppendJSONString(b, %s)", src)
+		case expr.BytesKind:
+			return fmt.Sprintf("b = goahttp.AppendJSONBytes(b, %s)", src)
+		}
+	}
+	// user types, inline objects and any values
+	return fallback
+}
+
+// appendJSONCollection returns the code that appends the JSON encoding of
+// src, a non-nil array or map with string keys, to b. It returns an empty
+// string if att is not such an array or map.
+func appendJSONCollection(att *expr.AttributeExpr, src string, depth int) string {
+	switch dt := att.Type.(type) {
+	case *expr.Array:
+		i, v := fmt.Sprintf("i%d", depth), fmt.Sprintf("v%d", depth)
+		return fmt.Sprintf("b = append(b, '[')\nfor %s, %s := range %s {\nif %s > 0 {\nb = append(b, ',')\n}\n%s\n}\nb = append(b, ']')",
+			i, v, src, i, appendJSONValue(dt.ElemType, v, depth+1))
+	case *expr.Map:
+		if dt.KeyType.Type != expr.String {
+			return ""
+		}
+		i, k, keys := fmt.Sprintf("i%d", depth), fmt.Sprintf("k%d", depth), fmt.Sprintf("keys%d", depth)
+		return fmt.Sprintf("%s := make([]string, 0, len(%s))\nfor %s := range %s {\n%s = append(%s, %s)\n}\nsort.Strings(%s)\nb = append(b, '{')\nfor %s, %s := range %s {\nif %s > 0 {\nb = append(b, ',')\n}\nb = goahttp.AppendJSONString(b, %s)\nb = append(b, ':')\n%s\n}\nb = append(b, '}')",
+			keys, src, k, src, keys, keys, k, keys, i, k, keys, i, k, appendJSONValue(dt.ElemType, src+"["+k+"]", depth+1))
+	}
+	return ""
+}
+
+// goStringLiteral returns the Go literal of s, a raw string literal if
+// possible.
+func goStringLiteral(s string) string {
+	if strings.Contains(s, "`") {
+		return fmt.Sprintf("%q", s)
+	}
+	return "`" + s + "`"
+}
+
+// ServerJSONTestFiles returns the tests and benchmarks of the AppendJSON
+// methods generated for the response body types of the services that enable
+// the "http:json:fast" meta. The tests check that the encoding is identical to
+// the one produced by encoding/json and the benchmarks compare the two
+// encodings using the examples defined in the design.
+func ServerJSONTestFiles(root *expr.RootExpr) []*codegen.File {
+	var fw []*codegen.File
+	for _, svc := range root.API.HTTP.Services {
+		if f := serverJSONTest(svc); f != nil {
+			fw = append(fw, f)
+		}
+	}
+	return fw
+}
+
+// serverJSONTest returns the file containing the AppendJSON tests and
+// benchmarks of the given service server, nil if no response body type
+// implements AppendJSON.
+func serverJSONTest(svc *expr.HTTPServiceExpr) *codegen.File {
+	var (
+		types []*fastJSONData
+
+		data = HTTPServices.Get(svc.Name())
+		seen = make(map[string]struct{})
+	)
+	add := func(td *TypeData) {
+		if td == nil || td.AppendDef == "" {
+			return
+		}
+		if _, ok := seen[td.VarName]; ok {
+			return
+		}
+		seen[td.VarName] = struct{}{}
+		ex, err := json.Marshal(openapi.ToStringMap(td.Example))
+		if err != nil {
+			return
+		}
+		types = append(types, &fastJSONData{VarName: td.VarName, Example: string(ex)})
+	}
+	for _, ed := range data.Endpoints {
+		for _, resp := range ed.Result.Responses {
+			for _, td := range resp.ServerBody {
+				add(td)
+			}
+		}
+		for _, gerr := range ed.Errors {
+			for _, herr := range gerr.Errors {
+				for _, td := range herr.Response.ServerBody {
+					add(td)
+				}
+			}
+		}
+	}
+	for _, td := range data.ServerBodyAttributeTypes {
+		add(td)
+	}
+	if len(types) == 0 {
+		return nil
+	}
+	path := filepath.Join(codegen.Gendir, "http", data.Service.PathName, "server", "json_test.go")
+	imports := []*codegen.ImportSpec{
+		{Path: "encoding/json"},
+		{Path: "testing"},
+	}
+	sections := []*codegen.SectionTemplate{
+		codegen.Header(svc.Name()+" HTTP server JSON encoding tests", "server", imports),
+	}
+	for _, t := range types {
+		sections = append(sections, &codegen.SectionTemplate{
+			Name:   "append-json-test",
+			Source: appendJSONTestT,
+			Data:   t,
+		})
+	}
+	return &codegen.File{Path: path, SectionTemplates: sections}
+}
+
+// input: TypeData
+const appendJSONT = `{{ printf "MarshalJSON implements json.Marshaler using AppendJSON so that encoding %s does not rely on reflection." .VarName | comment }}
+func (body *{{ .VarName }}) MarshalJSON() ([]byte, error) {
+	return body.AppendJSON(make([]byte, 0, 256))
+}
+
+{{ printf "AppendJSON appends the JSON encoding of body to b." | comment }}
+func (body *{{ .VarName }}) AppendJSON(b []byte) ([]byte, error) {
+	if body == nil {
+		return append(b, "null"...), nil
+	}
+	{{ .AppendDef }}
+	return append(b, '}'), nil
+}
+`
+
+// input: fastJSONData
+const appendJSONTestT = `{{ printf "Test%sAppendJSON checks that AppendJSON produces the same encoding as encoding/json." .VarName | comment }}
+func Test{{ .VarName }}AppendJSON(t *testing.T) {
+	type stdlib {{ .VarName }}
+	var body {{ .VarName }}
+	if err := json.Unmarshal([]byte({{ printf "%q" .Example }}), &body); err != nil {
+		t.Fatal(err)
+	}
+	expected, err := json.Marshal((*stdlib)(&body))
+	if err != nil {
+		t.Fatal(err)
+	}
+	got, err := body.AppendJSON(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(got) != string(expected) {
+		t.Errorf("got %s, expected %s", got, expected)
+	}
+}
+
+{{ printf "Benchmark%sJSON compares the encoding of %s using AppendJSON with encoding/json." .VarName .VarName | comment }}
+func Benchmark{{ .VarName }}JSON(b *testing.B) {
+	type stdlib {{ .VarName }}
+	var body {{ .VarName }}
+	if err := json.Unmarshal([]byte({{ printf "%q" .Example }}), &body); err != nil {
+		b.Fatal(err)
+	}
+	b.Run("AppendJSON", func(b *testing.B) {
+		b.ReportAllocs()
+		buf := make([]byte, 0, 1024)
+		for i := 0; i < b.N; i++ {
+			buf, _ = body.AppendJSON(buf[:0])
+		}
+	})
+	b.Run("stdlib", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			_, _ = json.Marshal((*stdlib)(&body))
+		}
+	})
+}
+`
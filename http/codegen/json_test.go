@@ -0,0 +1,59 @@
+package codegen
+
+import (
+	"bytes"
+	"path/filepath"
+	"testing"
+
+	"goa.design/goa/v3/codegen"
+	"goa.design/goa/v3/http/codegen/testdata"
+)
+
+func TestAppendJSON(t *testing.T) {
+	root := RunHTTPDSL(t, testdata.FastJSONDSL)
+	fs := ServerTypeFiles("", root)
+	if len(fs) != 1 {
+		t.Fatalf("got %d files, expected 1", len(fs))
+	}
+	sections := fs[0].Section("server-append-json")
+	if len(sections) != 2 {
+		t.Fatalf("got %d sections, expected 2", len(sections))
+	}
+	var buf bytes.Buffer
+	for _, s := range sections {
+		if err := s.Write(&buf); err != nil {
+			t.Fatal(err)
+		}
+	}
+	code := codegen.FormatTestCode(t, "package foo\n"+buf.String())
+	if code != testdata.AppendJSONCode {
+		t.Errorf("invalid code, got:\n%s\ngot vs. expected:\n%s", code, codegen.Diff(t, code, testdata.AppendJSONCode))
+	}
+}
+
+func TestServerJSONTestFiles(t *testing.T) {
+	root := RunHTTPDSL(t, testdata.FastJSONDSL)
+	ServerTypeFiles("", root)
+	fs := ServerJSONTestFiles(root)
+	if len(fs) != 1 {
+		t.Fatalf("got %d files, expected 1", len(fs))
+	}
+	if fs[0].Path != filepath.Join("gen", "http", "svc", "server", "json_test.go") {
+		t.Errorf("invalid path %q", fs[0].Path)
+	}
+	var buf bytes.Buffer
+	for _, s := range fs[0].SectionTemplates[1:] {
+		if err := s.Write(&buf); err != nil {
+			t.Fatal(err)
+		}
+	}
+	code := codegen.FormatTestCode(t, "package foo\n"+buf.String())
+	if code != testdata.AppendJSONTestCode {
+		t.Errorf("invalid code, got:\n%s\ngot vs. expected:\n%s", code, codegen.Diff(t, code, testdata.AppendJSONTestCode))
+	}
+
+	root = RunHTTPDSL(t, testdata.NoFastJSONDSL)
+	if fs := ServerJSONTestFiles(root); len(fs) != 0 {
+		t.Errorf("got %d files, expected none", len(fs))
+	}
+}
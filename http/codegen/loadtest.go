@@ -0,0 +1,181 @@
+package codegen
+
+import (
+	"encoding/json"
+	"fmt"
+	"path/filepath"
+	"strconv"
+	"text/template"
+
+	"goa.design/goa/v3/codegen"
+	"goa.design/goa/v3/expr"
+	goa "goa.design/goa/v3/pkg"
+)
+
+type (
+	// loadTestRequest is a request sent by the generated load test
+	// scenarios.
+	loadTestRequest struct {
+		Name     string            `json:"name"`
+		Weight   int               `json:"weight"`
+		Method   string            `json:"method"`
+		Path     string            `json:"path"`
+		Headers  map[string]string `json:"headers"`
+		Body     *string           `json:"body"`
+		Statuses []int             `json:"statuses"`
+	}
+
+	// vegetaTarget is a target of the vegeta JSON targets format.
+	vegetaTarget struct {
+		Method string              `json:"method"`
+		URL    string              `json:"url"`
+		Body   []byte              `json:"body,omitempty"`
+		Header map[string][]string `json:"header,omitempty"`
+	}
+)
+
+// LoadTestFiles returns the load test scenario covering the HTTP endpoints of
+// the given API in the given format: "k6" generates a k6 script and "vegeta" a
+// vegeta JSON targets file. The requests use the examples defined in the
+// design and are picked according to the weights given by the
+// "loadtest:weight" meta of the methods. Streaming endpoints, multipart
+// endpoints, endpoints that skip the request body encoding and methods with
+// the "loadtest:skip" meta are not covered.
+func LoadTestFiles(root *expr.RootExpr, format string) ([]*codegen.File, error) {
+	if len(root.API.HTTP.Services) == 0 {
+		return nil, nil
+	}
+	reqs, err := buildLoadTestRequests(root)
+	if err != nil || len(reqs) == 0 {
+		return nil, err
+	}
+	base := exampleBaseURL(root)
+	dir := filepath.Join(codegen.Gendir, "http", "loadtest")
+	switch format {
+	case "k6":
+		return []*codegen.File{{
+			Path: filepath.Join(dir, "k6.js"),
+			SectionTemplates: []*codegen.SectionTemplate{{
+				Name:    "loadtest-k6",
+				FuncMap: template.FuncMap{"toJSON": postmanJSON},
+				Source:  loadTestK6T,
+				Data: map[string]any{
+					"Version":  goa.Version(),
+					"BaseURL":  base,
+					"Requests": reqs,
+				},
+			}},
+		}}, nil
+	case "vegeta":
+		var lines []string
+		for _, r := range reqs {
+			t := &vegetaTarget{Method: r.Method, URL: base + r.Path}
+			if r.Body != nil {
+				t.Body = []byte(*r.Body)
+			}
+			if len(r.Headers) > 0 {
+				t.Header = make(map[string][]string, len(r.Headers))
+				for k, v := range r.Headers {
+					t.Header[k] = []string{v}
+				}
+			}
+			b, err := json.Marshal(t)
+			if err != nil {
+				return nil, err
+			}
+			for i := 0; i < r.Weight; i++ {
+				lines = append(lines, string(b))
+			}
+		}
+		return []*codegen.File{{
+			Path: filepath.Join(dir, "vegeta.json"),
+			SectionTemplates: []*codegen.SectionTemplate{{
+				Name:   "loadtest-vegeta",
+				Source: "{{ range . }}{{ . }}\n{{ end }}",
+				Data:   lines,
+			}},
+		}}, nil
+	}
+	return nil, fmt.Errorf("invalid load test format %q, must be k6 or vegeta", format)
+}
+
+// buildLoadTestRequests returns the requests sent by the load test scenarios,
+// one per endpoint.
+func buildLoadTestRequests(root *expr.RootExpr) ([]*loadTestRequest, error) {
+	var reqs []*loadTestRequest
+	for _, svc := range root.API.HTTP.Services {
+		for _, e := range svc.HTTPEndpoints {
+			m := e.MethodExpr
+			if _, ok := m.Meta["loadtest:skip"]; ok {
+				continue
+			}
+			if m.IsStreaming() || e.MultipartRequest || e.SkipRequestBodyEncodeDecode {
+				continue
+			}
+			weight := 1
+			if w, ok := m.Meta.Last("loadtest:weight"); ok {
+				n, err := strconv.Atoi(w)
+				if err != nil || n < 1 {
+					return nil, fmt.Errorf("invalid loadtest:weight meta %q of method %q of service %q, must be a positive integer", w, m.Name, m.Service.Name)
+				}
+				weight = n
+			}
+			ex := exampleRequest(e, root.API.ExampleGenerator)
+			if ex == nil {
+				continue
+			}
+			req := &loadTestRequest{
+				Name:     ex.Name,
+				Weight:   weight,
+				Method:   ex.Method,
+				Path:     ex.Path,
+				Headers:  make(map[string]string, len(ex.Header)),
+				Statuses: ex.Statuses,
+			}
+			for _, h := range ex.Header {
+				if v, ok := req.Headers[h[0]]; ok {
+					req.Headers[h[0]] = v + ", " + h[1]
+					continue
+				}
+				req.Headers[h[0]] = h[1]
+			}
+			if ex.Body != "" {
+				req.Body = &ex.Body
+			}
+			reqs = append(reqs, req)
+		}
+	}
+	return reqs, nil
+}
+
+// input: map[string]any{"Version": string, "BaseURL": string, "Requests": []*loadTestRequest}
+const loadTestK6T = `// Code generated by goa {{ .Version }}, DO NOT EDIT.
+//
+// k6 load test scenario covering the HTTP endpoints, run with:
+//
+//	k6 run --vus 10 --duration 30s -e BASE_URL={{ .BaseURL }} k6.js
+//
+// Each iteration sends one request picked at random according to the
+// request weights set with the "loadtest:weight" meta in the design.
+
+import http from "k6/http";
+import { check } from "k6";
+
+const baseUrl = __ENV.BASE_URL || {{ printf "%q" .BaseURL }};
+
+const requests = {{ toJSON .Requests }};
+
+const totalWeight = requests.reduce((sum, r) => sum + r.weight, 0);
+
+export default function () {
+  let n = Math.random() * totalWeight;
+  const req = requests.find((r) => (n -= r.weight) < 0) || requests[requests.length - 1];
+  const res = http.request(req.method, baseUrl + req.path, req.body, {
+    headers: req.headers,
+    tags: { name: req.name },
+  });
+  check(res, {
+    [req.name + " status"]: (r) => req.statuses.includes(r.status),
+  });
+}
+`
@@ -0,0 +1,77 @@
+package codegen
+
+import (
+	"bytes"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"goa.design/goa/v3/http/codegen/testdata"
+)
+
+func TestLoadTestFiles(t *testing.T) {
+	cases := map[string]struct {
+		Format string
+		// output
+		Path     string
+		Expected []string
+	}{
+		"k6": {"k6", filepath.Join("gen", "http", "loadtest", "k6.js"), []string{
+			`const baseUrl = __ENV.BASE_URL || "http://localhost:8080";`,
+			`"name": "svc update",`,
+			`"weight": 3,`,
+			`"path": "/items/42",`,
+			`"X-Token": "secret"`,
+			`"body": "{\"name\":\"goa\"}",`,
+			`"name": "svc list",`,
+		}},
+		"vegeta": {"vegeta", filepath.Join("gen", "http", "loadtest", "vegeta.json"), []string{
+			`{"method":"PUT","url":"http://localhost:8080/items/42","body":"eyJuYW1lIjoiZ29hIn0=","header":{"Content-Type":["application/json"],"X-Token":["secret"]}}` + "\n" +
+				`{"method":"PUT","url":"http://localhost:8080/items/42","body":"eyJuYW1lIjoiZ29hIn0=","header":{"Content-Type":["application/json"],"X-Token":["secret"]}}` + "\n" +
+				`{"method":"PUT","url":"http://localhost:8080/items/42","body":"eyJuYW1lIjoiZ29hIn0=","header":{"Content-Type":["application/json"],"X-Token":["secret"]}}` + "\n" +
+				`{"method":"GET","url":"http://localhost:8080/items"}` + "\n",
+		}},
+	}
+	for k, c := range cases {
+		t.Run(k, func(t *testing.T) {
+			root := RunHTTPDSL(t, testdata.LoadTestDSL)
+			fs, err := LoadTestFiles(root, c.Format)
+			if err != nil {
+				t.Fatalf("LoadTestFiles failed with %s", err)
+			}
+			if len(fs) != 1 {
+				t.Fatalf("got %d files, expected 1", len(fs))
+			}
+			if fs[0].Path != c.Path {
+				t.Errorf("got path %q, expected %q", fs[0].Path, c.Path)
+			}
+			var buf bytes.Buffer
+			if err := fs[0].SectionTemplates[0].Write(&buf); err != nil {
+				t.Fatal(err)
+			}
+			code := buf.String()
+			if strings.Contains(code, "skipped") {
+				t.Errorf("got skipped endpoint in:\n%s", code)
+			}
+			for _, e := range c.Expected {
+				if !strings.Contains(code, e) {
+					t.Errorf("expected %q in:\n%s", e, code)
+				}
+			}
+		})
+	}
+}
+
+func TestLoadTestFilesErrors(t *testing.T) {
+	root := RunHTTPDSL(t, testdata.InvalidLoadTestWeightDSL)
+	_, err := LoadTestFiles(root, "k6")
+	if err == nil || !strings.Contains(err.Error(), `invalid loadtest:weight meta "0"`) {
+		t.Errorf("got error %v, expected invalid weight", err)
+	}
+
+	root = RunHTTPDSL(t, testdata.LoadTestDSL)
+	_, err = LoadTestFiles(root, "jmeter")
+	if err == nil || !strings.Contains(err.Error(), `invalid load test format "jmeter"`) {
+		t.Errorf("got error %v, expected invalid format", err)
+	}
+}
@@ -2,7 +2,9 @@ package openapi
 
 import (
 	"encoding/json"
+	"strconv"
 	"strings"
+	"time"
 
 	"goa.design/goa/v3/expr"
 )
@@ -24,6 +26,39 @@ func ExtensionsFromExpr(mdata expr.MetaExpr) map[string]any {
 	return swag
 }
 
+// OperationExtensions returns the extensions of the operation describing the
+// given method: the extensions defined with meta and the x-goa-budget
+// extension documenting the "budget:response-size" and "budget:latency" meta
+// of the method or of its service if any.
+func OperationExtensions(m *expr.MethodExpr) map[string]any {
+	ext := ExtensionsFromExpr(m.Meta)
+	budget := make(map[string]any)
+	for _, key := range []string{"budget:response-size", "budget:latency"} {
+		v, ok := m.Meta.Last(key)
+		if !ok && m.Service != nil {
+			v, ok = m.Service.Meta.Last(key)
+		}
+		if !ok {
+			continue
+		}
+		if key == "budget:latency" {
+			if _, err := time.ParseDuration(v); err == nil {
+				budget["latency"] = v
+			}
+		} else if n, err := strconv.ParseInt(v, 10, 64); err == nil {
+			budget["responseSize"] = n
+		}
+	}
+	if len(budget) == 0 {
+		return ext
+	}
+	if ext == nil {
+		ext = make(map[string]any)
+	}
+	ext["x-goa-budget"] = budget
+	return ext
+}
+
 // extensionsFromExprWithPrefix generates openapi extensions from
 // the given meta expression with keys starting the given prefix.
 func extensionsFromExprWithPrefix(mdata expr.MetaExpr, prefix string) map[string]any {
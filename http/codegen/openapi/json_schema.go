@@ -331,6 +331,9 @@ func TypeSchemaWithPrefix(api *expr.APIExpr, t expr.DataType, prefix string) *Sc
 	case *expr.Object:
 		s.Type = Object
 		for _, nat := range *actual {
+			if !IsSerialized(nat.Attribute) {
+				continue
+			}
 			prop := NewSchema()
 			buildAttributeSchema(api, prop, nat.Attribute)
 			s.Properties[nat.Name] = prop
@@ -407,6 +410,63 @@ func ToStringMap(val any) any {
 	}
 }
 
+// IsSerialized returns false if the given object attribute is not serialized
+// in JSON bodies, i.e. if it defines the "struct:tag:json" meta with value
+// "-". This is the case of the attributes initialized by the generated
+// decoders such as the attributes used with FieldPresence or
+// CollectUnknownFields.
+func IsSerialized(att *expr.AttributeExpr) bool {
+	tag, ok := att.Meta["struct:tag:json"]
+	return !ok || len(tag) == 0 || tag[0] != "-"
+}
+
+// SerializedExample returns the given example of a value of type t without
+// the object attributes that are not serialized in JSON bodies.
+func SerializedExample(t expr.DataType, ex any) any {
+	obj := expr.AsObject(t)
+	if obj == nil {
+		return ex
+	}
+	m, ok := ex.(map[string]any)
+	if !ok {
+		return ex
+	}
+	var res map[string]any
+	for _, nat := range *obj {
+		if _, ok := m[nat.Name]; !ok || IsSerialized(nat.Attribute) {
+			continue
+		}
+		if res == nil {
+			// do not modify the example which may be defined in the
+			// design
+			res = make(map[string]any, len(m))
+			for k, v := range m {
+				res[k] = v
+			}
+		}
+		delete(res, nat.Name)
+	}
+	if res == nil {
+		return m
+	}
+	return res
+}
+
+// BodyDescription returns the description of the request body of the given
+// endpoint. The description of the bodies of endpoints that record the fields
+// set by the client (see dsl.FieldPresence) explains that explicit nulls are
+// significant.
+func BodyDescription(e *expr.HTTPEndpointExpr) string {
+	if e.PresentFields == "" {
+		return e.Body.Description
+	}
+	note := "The server records the names of the top level fields present in the request body, fields explicitly set to null are distinguished from absent fields."
+	if e.Body.Description == "" {
+		return note
+	}
+	return e.Body.Description + "\n" + note
+}
+
 // MarshalJSON returns the JSON encoding of s.
 func (s *Schema) MarshalJSON() ([]byte, error) {
 	return MarshalJSON((*_Schema)(s), s.Extensions)
@@ -465,7 +525,7 @@ func buildAttributeSchema(api *expr.APIExpr, s *Schema, at *expr.AttributeExpr)
 	}
 	s.DefaultValue = ToStringMap(at.DefaultValue)
 	s.Description = at.Description
-	s.Example = at.Example(api.ExampleGenerator)
+	s.Example = SerializedExample(at.Type, at.Example(api.ExampleGenerator))
 	s.Extensions = ExtensionsFromExpr(at.Meta)
 	initAttributeValidation(s, at)
 
@@ -0,0 +1,214 @@
+package openapi
+
+import (
+	"fmt"
+	"reflect"
+
+	"goa.design/goa/v3/expr"
+)
+
+// JSONAPIContentType is the media type of the responses rendered following the
+// JSON:API convention.
+const JSONAPIContentType = "application/vnd.api+json"
+
+// JSONAPIDocumentSchema returns the schema of the JSON:API documents rendered
+// by the generated servers for the given response body and JSON:API result
+// type. The document "data" member holds the resource object (or the array of
+// resource objects if body is a collection) whose "id" and "links" members are
+// the "id" and "links" attributes of the body, whose "relationships" member
+// lists the resource identifiers of the relationships and whose "attributes"
+// member holds the other attributes. schema returns the schema of the given
+// body attribute.
+func JSONAPIDocumentSchema(body *expr.AttributeExpr, rt *expr.ResultTypeExpr, schema func(*expr.AttributeExpr) *Schema) *Schema {
+	elem := body
+	if arr := expr.AsArray(body.Type); arr != nil {
+		elem = arr.ElemType
+	}
+	res := NewSchema()
+	res.Type = Object
+	res.Required = []string{"type"}
+	res.Properties["type"] = &Schema{Type: String, Enum: []any{rt.JSONAPIType()}}
+	if obj := expr.AsObject(elem.Type); obj != nil {
+		rels := rt.JSONAPIRelationships()
+		attributes := NewSchema()
+		attributes.Type = Object
+		relationships := NewSchema()
+		relationships.Type = Object
+		for _, nat := range *obj {
+			if !IsSerialized(nat.Attribute) {
+				continue
+			}
+			switch name := nat.Name; {
+			case name == "id":
+				res.Properties["id"] = &Schema{Type: String}
+				if elem.IsRequired(name) {
+					res.Required = append(res.Required, "id")
+				}
+			case name == "links":
+				res.Properties["links"] = schema(nat.Attribute)
+			case rels[name] != "":
+				id := NewSchema()
+				id.Type = Object
+				id.Required = []string{"type", "id"}
+				id.Properties["type"] = &Schema{Type: String, Enum: []any{rels[name]}}
+				id.Properties["id"] = &Schema{Type: String}
+				if expr.IsArray(nat.Attribute.Type) {
+					id = &Schema{Type: Array, Items: id}
+				}
+				rel := NewSchema()
+				rel.Type = Object
+				rel.Properties["data"] = id
+				relationships.Properties[name] = rel
+			default:
+				attributes.Properties[name] = schema(nat.Attribute)
+				if elem.IsRequired(name) {
+					attributes.Required = append(attributes.Required, name)
+				}
+			}
+		}
+		if len(attributes.Properties) > 0 {
+			res.Properties["attributes"] = attributes
+		}
+		if len(relationships.Properties) > 0 {
+			res.Properties["relationships"] = relationships
+		}
+	}
+	data := res
+	if expr.IsArray(body.Type) {
+		data = &Schema{Type: Array, Items: res}
+	}
+	doc := NewSchema()
+	doc.Type = Object
+	doc.Required = []string{"data"}
+	doc.Properties["data"] = data
+	return doc
+}
+
+// JSONAPIErrorsSchema returns the schema of the JSON:API error documents
+// rendered by the generated servers for the errors of the endpoints whose
+// result follows the JSON:API convention.
+func JSONAPIErrorsSchema() *Schema {
+	obj := NewSchema()
+	obj.Type = Object
+	for _, n := range []string{"id", "status", "code", "title", "detail"} {
+		obj.Properties[n] = &Schema{Type: String}
+	}
+	obj.Properties["meta"] = &Schema{Type: Object, AdditionalProperties: true}
+	doc := NewSchema()
+	doc.Type = Object
+	doc.Required = []string{"errors"}
+	doc.Properties["errors"] = &Schema{Type: Array, Items: obj}
+	return doc
+}
+
+// JSONAPIDocumentExample returns the JSON:API document corresponding to the
+// given example of a result of type rt.
+func JSONAPIDocumentExample(ex any, rt *expr.ResultTypeExpr) any {
+	rels := rt.JSONAPIRelationships()
+	if obj, ok := ex.(map[string]any); ok {
+		return map[string]any{"data": jsonapiResourceExample(obj, rt.JSONAPIType(), rels)}
+	}
+	elems, ok := exampleSlice(ex)
+	if !ok {
+		return ex
+	}
+	data := make([]any, len(elems))
+	for i, elem := range elems {
+		if obj, ok := elem.(map[string]any); ok {
+			data[i] = jsonapiResourceExample(obj, rt.JSONAPIType(), rels)
+		} else {
+			data[i] = elem
+		}
+	}
+	return map[string]any{"data": data}
+}
+
+// JSONAPIErrorsExample returns the JSON:API error document corresponding to
+// the given example of an error rendered with the given status code. The "id",
+// "name" and "message" members of the example map to the error object id,
+// code and detail, the other members are rendered in the error object meta.
+func JSONAPIErrorsExample(ex any, status int) any {
+	obj, ok := ex.(map[string]any)
+	if !ok {
+		return ex
+	}
+	jerr := map[string]any{"status": fmt.Sprint(status)}
+	meta := make(map[string]any)
+	for k, v := range obj {
+		switch k {
+		case "id":
+			jerr["id"] = v
+		case "name":
+			jerr["code"] = v
+			jerr["title"] = v
+		case "message":
+			jerr["detail"] = v
+		default:
+			meta[k] = v
+		}
+	}
+	if len(meta) > 0 {
+		jerr["meta"] = meta
+	}
+	return map[string]any{"errors": []any{jerr}}
+}
+
+// jsonapiResourceExample returns the JSON:API resource object of the given
+// type corresponding to the example obj.
+func jsonapiResourceExample(obj map[string]any, typ string, rels map[string]string) map[string]any {
+	res := map[string]any{"type": typ}
+	attributes := make(map[string]any)
+	relationships := make(map[string]any)
+	for name, val := range obj {
+		if val == nil {
+			continue
+		}
+		switch {
+		case name == "id":
+			res["id"] = fmt.Sprint(val)
+		case name == "links":
+			res["links"] = val
+		case rels[name] != "":
+			relationships[name] = map[string]any{"data": jsonapiIdentifiersExample(rels[name], val)}
+		default:
+			attributes[name] = val
+		}
+	}
+	if len(attributes) > 0 {
+		res["attributes"] = attributes
+	}
+	if len(relationships) > 0 {
+		res["relationships"] = relationships
+	}
+	return res
+}
+
+// jsonapiIdentifiersExample returns the JSON:API resource identifiers of the
+// related resources val of the given type.
+func jsonapiIdentifiersExample(typ string, val any) any {
+	if obj, ok := val.(map[string]any); ok {
+		return map[string]any{"type": typ, "id": fmt.Sprint(obj["id"])}
+	}
+	if elems, ok := exampleSlice(val); ok {
+		ids := make([]any, len(elems))
+		for i, elem := range elems {
+			ids[i] = jsonapiIdentifiersExample(typ, elem)
+		}
+		return ids
+	}
+	return map[string]any{"type": typ, "id": fmt.Sprint(val)}
+}
+
+// exampleSlice returns the elements of the given example if it is a slice.
+// The examples of arrays are slices of the element Go type.
+func exampleSlice(ex any) ([]any, bool) {
+	v := reflect.ValueOf(ex)
+	if v.Kind() != reflect.Slice || v.Type().Elem().Kind() == reflect.Uint8 {
+		return nil, false
+	}
+	elems := make([]any, v.Len())
+	for i := range elems {
+		elems[i] = v.Index(i).Interface()
+	}
+	return elems, true
+}
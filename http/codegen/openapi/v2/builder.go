@@ -245,28 +245,34 @@ func hasAbsoluteRoutes(root *expr.RootExpr) bool {
 }
 
 func summaryFromExpr(name string, e *expr.HTTPEndpointExpr) string {
-	for n, mdata := range e.Meta {
-		if (n == "openapi:summary" || n == "swagger:summary") && len(mdata) > 0 {
-			return mdata[0]
-		}
+	if s, ok := summaryMeta(e.Meta); ok {
+		return s
 	}
-	for n, mdata := range e.MethodExpr.Meta {
-		if (n == "openapi:summary" || n == "swagger:summary") && len(mdata) > 0 {
-			return mdata[0]
-		}
+	if s, ok := summaryMeta(e.MethodExpr.Meta); ok {
+		return s
 	}
 	return name
 }
 
 func summaryFromMeta(name string, meta expr.MetaExpr) string {
-	for n, mdata := range meta {
-		if (n == "openapi:summary" || n == "swagger:summary") && len(mdata) > 0 {
-			return mdata[0]
-		}
+	if s, ok := summaryMeta(meta); ok {
+		return s
 	}
 	return name
 }
 
+// summaryMeta returns the value of the "openapi:summary" meta or of the legacy
+// "swagger:summary" meta if the former is not set. Looking up the keys in
+// order keeps the result deterministic when both are set.
+func summaryMeta(meta expr.MetaExpr) (string, bool) {
+	for _, n := range []string{"openapi:summary", "swagger:summary"} {
+		if mdata := meta[n]; len(mdata) > 0 {
+			return mdata[0], true
+		}
+	}
+	return "", false
+}
+
 func paramsFromExpr(params *expr.MappedAttributeExpr, path string) []*Parameter {
 	if params == nil {
 		return nil
@@ -420,6 +426,41 @@ func responseSpecFromExpr(_ *V2, root *expr.RootExpr, r *expr.HTTPResponseExpr,
 	}
 }
 
+// isJSON returns true if the generated servers render the responses with the
+// given content type as JSON:API documents when the result follows the
+// JSON:API convention.
+func isJSON(ct string) bool {
+	return ct == "" || ct == "application/json" || ct == openapi.JSONAPIContentType || ct == expr.ErrorResultIdentifier
+}
+
+// projectedBody returns the body of the given response projected using the
+// view defined in the design if any.
+func projectedBody(r *expr.HTTPResponseExpr) *expr.AttributeExpr {
+	v, ok := r.Body.Meta["view"]
+	if !ok {
+		return r.Body
+	}
+	rt, ok := r.Body.Type.(*expr.ResultTypeExpr)
+	if !ok {
+		return r.Body
+	}
+	prt, err := expr.Project(expr.Dup(rt).(*expr.ResultTypeExpr), v[0])
+	if err != nil {
+		return r.Body
+	}
+	return &expr.AttributeExpr{Type: prt, Validation: r.Body.Validation}
+}
+
+// appendContentType appends ct to cts unless it is already listed.
+func appendContentType(cts []string, ct string) []string {
+	for _, c := range cts {
+		if c == ct {
+			return cts
+		}
+	}
+	return append(cts, ct)
+}
+
 func headersFromExpr(headers *expr.MappedAttributeExpr) map[string]*Header {
 	if headers == nil {
 		return nil
@@ -522,7 +563,13 @@ func buildPathFromExpr(s *V2, root *expr.RootExpr, h *expr.HostExpr, route *expr
 		// https://github.com/OAI/OpenAPI-Specification/issues/291
 		key = expr.HTTPWildcardRegex.ReplaceAllString(key, "/{$1}")
 		params := paramsFromExpr(endpoint.Params, key)
+		if att := endpoint.FieldSelectionAttribute(); att != nil {
+			params = append(params, paramFor(att, endpoint.FieldSelection, "query", false))
+		}
 		params = append(params, paramsFromHeaders(endpoint)...)
+		for _, h := range append(endpoint.LocaleHeaders(), endpoint.IdempotencyHeaders()...) {
+			params = append(params, paramFor(h.Attribute, h.Name, "header", false))
+		}
 		var produces []string
 
 		responses := make(map[string]*Response, len(endpoint.Responses))
@@ -537,6 +584,13 @@ func buildPathFromExpr(s *V2, root *expr.RootExpr, h *expr.HostExpr, route *expr
 				}
 			}
 			resp := responseSpecFromExpr(s, root, r, endpoint.Service.Name())
+			if rt := expr.JSONAPIResult(endpoint.MethodExpr.Result); rt != nil && resp.Schema != nil && isJSON(r.ContentType) {
+				prefix := endpoint.Service.Name()
+				resp.Schema = openapi.JSONAPIDocumentSchema(projectedBody(r), rt, func(att *expr.AttributeExpr) *openapi.Schema {
+					return openapi.AttributeTypeSchemaWithPrefix(root.API, att, prefix)
+				})
+				produces = appendContentType(produces, openapi.JSONAPIContentType)
+			}
 			responses[strconv.Itoa(r.StatusCode)] = resp
 			if r.ContentType != "" {
 				foundCT := false
@@ -553,6 +607,9 @@ func buildPathFromExpr(s *V2, root *expr.RootExpr, h *expr.HostExpr, route *expr
 		}
 		for _, er := range endpoint.HTTPErrors {
 			resp := responseSpecFromExpr(s, root, er.Response, endpoint.Service.Name())
+			if expr.JSONAPIResult(endpoint.MethodExpr.Result) != nil && resp.Schema != nil && isJSON(er.Response.ContentType) {
+				resp.Schema = openapi.JSONAPIErrorsSchema()
+			}
 			responses[strconv.Itoa(er.Response.StatusCode)] = resp
 		}
 
@@ -569,7 +626,7 @@ func buildPathFromExpr(s *V2, root *expr.RootExpr, h *expr.HostExpr, route *expr
 			pp := &Parameter{
 				Name:        endpoint.Body.Type.Name(),
 				In:          in,
-				Description: endpoint.Body.Description,
+				Description: openapi.BodyDescription(endpoint),
 				Required:    true,
 				Schema:      openapi.AttributeTypeSchemaWithPrefix(root.API, endpoint.Body, codegen.Goify(endpoint.Service.Name(), true)),
 			}
@@ -650,7 +707,7 @@ func buildPathFromExpr(s *V2, root *expr.RootExpr, h *expr.HostExpr, route *expr
 			Responses:    responses,
 			Schemes:      schemes,
 			Deprecated:   false,
-			Extensions:   openapi.ExtensionsFromExpr(endpoint.MethodExpr.Meta),
+			Extensions:   openapi.OperationExtensions(endpoint.MethodExpr),
 			Security:     requirements,
 		}
 
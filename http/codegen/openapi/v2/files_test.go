@@ -40,6 +40,9 @@ func TestSections(t *testing.T) {
 		{"with-spaces", testdata.WithSpacesDSL},
 		{"with-map", testdata.WithMapDSL},
 		{"path-with-wildcards", testdata.PathWithWildcardDSL},
+		{"locale", testdata.LocaleDSL},
+		{"field-presence", testdata.FieldPresenceDSL},
+		{"jsonapi", testdata.JSONAPIDSL},
 	}
 	for _, c := range cases {
 		t.Run(c.Name, func(t *testing.T) {
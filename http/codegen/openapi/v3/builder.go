@@ -203,13 +203,11 @@ func buildOperation(key string, r *expr.RouteExpr, bodies *EndpointBodies, rand
 	// OpenAPI summary
 	var summary string
 	setSummary := func(meta expr.MetaExpr) {
-		for n, mdata := range meta {
-			if (n == "openapi:summary" || n == "swagger:summary") && len(mdata) > 0 {
-				if mdata[0] == "{path}" {
-					summary = r.Path
-				} else {
-					summary = mdata[0]
-				}
+		if s, ok := summaryMeta(meta); ok {
+			if s == "{path}" {
+				summary = r.Path
+			} else {
+				summary = s
 			}
 		}
 	}
@@ -249,7 +247,7 @@ func buildOperation(key string, r *expr.RouteExpr, bodies *EndpointBodies, rand
 		mt := &MediaType{Schema: bodies.RequestBody}
 		initExamples(mt, e.Body, rand)
 		requestBody = &RequestBodyRef{Value: &RequestBody{
-			Description: e.Body.Description,
+			Description: openapi.BodyDescription(e),
 			Required:    e.Body.Type != expr.Empty,
 			Content:     map[string]*MediaType{ct: mt},
 			Extensions:  openapi.ExtensionsFromExpr(e.Body.Meta),
@@ -260,7 +258,13 @@ func buildOperation(key string, r *expr.RouteExpr, bodies *EndpointBodies, rand
 	var params []*ParameterRef
 	{
 		ps := paramsFromPath(e.Params, key, rand)
+		if att := e.FieldSelectionAttribute(); att != nil {
+			ps = append(ps, paramFor(att, e.FieldSelection, "query", false, rand))
+		}
 		ps = append(ps, paramsFromHeadersAndCookies(e, rand)...)
+		for _, h := range append(e.LocaleHeaders(), e.IdempotencyHeaders()...) {
+			ps = append(ps, paramFor(h.Attribute, h.Name, "header", false, rand))
+		}
 		params = make([]*ParameterRef, len(ps))
 		for i, p := range ps {
 			params[i] = &ParameterRef{Value: p}
@@ -285,6 +289,9 @@ func buildOperation(key string, r *expr.RouteExpr, bodies *EndpointBodies, rand
 				}
 			}
 			resp := responseFromExpr(r, bodies.ResponseBodies, rand)
+			if rt := expr.JSONAPIResult(m.Result); rt != nil {
+				jsonapiResponse(resp, func(ex any) any { return openapi.JSONAPIDocumentExample(ex, rt) })
+			}
 			responses[strconv.Itoa(r.StatusCode)] = &ResponseRef{Value: resp}
 		}
 		for _, er := range e.HTTPErrors {
@@ -302,10 +309,25 @@ func buildOperation(key string, r *expr.RouteExpr, bodies *EndpointBodies, rand
 					content.Example = nil
 				}
 			}
+			if expr.JSONAPIResult(m.Result) != nil {
+				status := er.Response.StatusCode
+				jsonapiResponse(resp, func(ex any) any { return openapi.JSONAPIErrorsExample(ex, status) })
+			}
 			responses[strconv.Itoa(er.Response.StatusCode)] = &ResponseRef{Value: resp}
 		}
 	}
 
+	// callbacks
+	var callbacks map[string]*CallbackRef
+	if len(e.Callbacks) > 0 {
+		callbacks = make(map[string]*CallbackRef, len(e.Callbacks))
+		for _, c := range e.Callbacks {
+			callbacks[c.Name] = &CallbackRef{Value: map[string]*PathItem{
+				c.URL: {Post: buildCallbackOperation(c, bodies.CallbackBodies[c.Name], rand)},
+			}}
+		}
+	}
+
 	// tag names
 	var tagNames []string
 	{
@@ -334,10 +356,30 @@ func buildOperation(key string, r *expr.RouteExpr, bodies *EndpointBodies, rand
 		Parameters:   params,
 		RequestBody:  requestBody,
 		Responses:    responses,
+		Callbacks:    callbacks,
 		Security:     buildSecurityRequirements(e.Requirements),
 		Deprecated:   false,
 		ExternalDocs: openapi.DocsFromExpr(m.Docs, m.Meta),
-		Extensions:   openapi.ExtensionsFromExpr(m.Meta),
+		Extensions:   openapi.OperationExtensions(m),
+	}
+}
+
+// buildCallbackOperation builds the OpenAPI Operation object that describes
+// the request sent by the server for the given callback.
+func buildCallbackOperation(c *expr.HTTPCallbackExpr, body *openapi.Schema, rand *expr.ExampleGenerator) *Operation {
+	mt := &MediaType{Schema: body}
+	initExamples(mt, c.Body, rand)
+	desc := "Callback received."
+	return &Operation{
+		Description: c.Description,
+		RequestBody: &RequestBodyRef{Value: &RequestBody{
+			Description: c.Body.Description,
+			Required:    true,
+			Content:     map[string]*MediaType{"application/json": mt},
+		}},
+		Responses: map[string]*ResponseRef{
+			"200": {Value: &Response{Description: &desc}},
+		},
 	}
 }
 
@@ -388,10 +430,8 @@ func buildFileServerOperation(key string, fs *expr.HTTPFileServerExpr, api *expr
 	var summary string
 	{
 		summary = fmt.Sprintf("Download %s", fs.FilePath)
-		for n, mdata := range fs.Meta {
-			if (n == "openapi:summary" || n == "swagger:summary") && len(mdata) > 0 {
-				summary = mdata[0]
-			}
+		if s, ok := summaryMeta(fs.Meta); ok {
+			summary = s
 		}
 	}
 
@@ -661,6 +701,18 @@ func buildTags(api *expr.APIExpr) []*openapi.Tag {
 	return tags
 }
 
+// summaryMeta returns the value of the "openapi:summary" meta or of the legacy
+// "swagger:summary" meta if the former is not set. Looking up the keys in
+// order keeps the result deterministic when both are set.
+func summaryMeta(meta expr.MetaExpr) (string, bool) {
+	for _, n := range []string{"openapi:summary", "swagger:summary"} {
+		if mdata := meta[n]; len(mdata) > 0 {
+			return mdata[0], true
+		}
+	}
+	return "", false
+}
+
 // mustGenerate returns true if the meta indicates that a OpenAPI specification should be
 // generated, false otherwise.
 func mustGenerate(meta expr.MetaExpr) bool {
@@ -1,6 +1,9 @@
 package openapiv3
 
-import "goa.design/goa/v3/expr"
+import (
+	"goa.design/goa/v3/expr"
+	"goa.design/goa/v3/http/codegen/openapi"
+)
 
 type (
 	// exampler is the interface used to initialize the example of an
@@ -30,6 +33,6 @@ func initExamples(obj exampler, attr *expr.AttributeExpr, r *expr.ExampleGenerat
 	case len(examples) > 0:
 		obj.setExample(examples[0].Value)
 	default:
-		obj.setExample(attr.Example(r))
+		obj.setExample(openapi.SerializedExample(attr.Type, attr.Example(r)))
 	}
 }
@@ -45,6 +45,9 @@ func TestFiles(t *testing.T) {
 		{"with-tags", testdata.WithTagsDSL},
 		{"with-tags-swagger", testdata.WithTagsSwaggerDSL},
 		{"typename", testdata.TypenameDSL},
+		{"locale", testdata.LocaleDSL},
+		{"field-presence", testdata.FieldPresenceDSL},
+		{"jsonapi", testdata.JSONAPIDSL},
 		// TestEndpoints
 		{"endpoint", testdata.ExtensionDSL},
 		{"endpoint-swagger", testdata.ExtensionSwaggerDSL},
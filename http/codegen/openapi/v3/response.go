@@ -10,15 +10,7 @@ import (
 )
 
 func responseFromExpr(r *expr.HTTPResponseExpr, bodies map[int][]*openapi.Schema, rand *expr.ExampleGenerator) *Response {
-	ct := r.ContentType
-	rt, ok := r.Body.Type.(*expr.ResultTypeExpr)
-	if ok && ct == "" {
-		ct = rt.ContentType
-	}
-	if ct == "" {
-		// Default to application/json
-		ct = "application/json"
-	}
+	ct := responseContentType(r)
 	var headers map[string]*HeaderRef
 	o := expr.AsObject(r.Headers.Type)
 	if len(*o) > 0 {
@@ -76,3 +68,47 @@ func isSkipResponseBodyEncodeDecode(parent eval.Expression) bool {
 	ee, ok := parent.(*expr.HTTPEndpointExpr)
 	return ok && ee.SkipResponseBodyEncodeDecode
 }
+
+// responseContentType returns the content type of the given response,
+// "application/json" unless specified otherwise in the design.
+func responseContentType(r *expr.HTTPResponseExpr) string {
+	ct := r.ContentType
+	rt, ok := r.Body.Type.(*expr.ResultTypeExpr)
+	if ok && ct == "" {
+		ct = rt.ContentType
+	}
+	if ct == "" {
+		// Default to application/json
+		ct = "application/json"
+	}
+	return ct
+}
+
+// isJSON returns true if the generated servers render the responses with the
+// given content type as JSON:API documents when the result follows the
+// JSON:API convention.
+func isJSON(ct string) bool {
+	return ct == "application/json" || ct == openapi.JSONAPIContentType || ct == expr.ErrorResultIdentifier
+}
+
+// jsonapiResponse documents resp as a JSON:API document: its JSON content is
+// moved to the JSON:API media type and its examples are converted using the
+// given function.
+func jsonapiResponse(resp *Response, convert func(any) any) {
+	for ct, mt := range resp.Content {
+		if !isJSON(ct) {
+			continue
+		}
+		delete(resp.Content, ct)
+		if mt.Example != nil {
+			mt.Example = convert(mt.Example)
+		}
+		for _, ex := range mt.Examples {
+			if ex.Value != nil {
+				ex.Value.Value = convert(ex.Value.Value)
+			}
+		}
+		resp.Content[openapi.JSONAPIContentType] = mt
+		return
+	}
+}
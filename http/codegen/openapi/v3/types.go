@@ -25,6 +25,9 @@ type (
 	EndpointBodies struct {
 		RequestBody    *openapi.Schema
 		ResponseBodies map[int][]*openapi.Schema
+		// CallbackBodies holds the callback request body schemas
+		// indexed by callback name.
+		CallbackBodies map[string]*openapi.Schema
 	}
 
 	// schemafier is an internal data structure used to keep the state required to
@@ -115,7 +118,8 @@ func buildBodyTypes(api *expr.APIExpr) (map[string]map[string]*EndpointBodies, m
 			for _, er := range e.HTTPErrors {
 				resps = append(resps, er.Response)
 			}
-			for _, resp := range resps {
+			jsonapi := expr.JSONAPIResult(e.MethodExpr.Result)
+			for i, resp := range resps {
 				var view string
 				if vs, ok := resp.Body.Meta["view"]; ok {
 					view = vs[0]
@@ -130,10 +134,25 @@ func buildBodyTypes(api *expr.APIExpr) (map[string]map[string]*EndpointBodies, m
 					}
 					body.Type = rt
 				}
-				js := sf.schemafy(body)
+				var js *openapi.Schema
+				switch {
+				case jsonapi == nil || !isJSON(responseContentType(resp)) || body.Type == expr.Empty:
+					js = sf.schemafy(body)
+				case i < len(e.Responses):
+					js = openapi.JSONAPIDocumentSchema(body, jsonapi, func(att *expr.AttributeExpr) *openapi.Schema { return sf.schemafy(att) })
+				default:
+					js = openapi.JSONAPIErrorsSchema()
+				}
 				res[resp.StatusCode] = append(res[resp.StatusCode], js)
 			}
-			sbodies[e.Name()] = &EndpointBodies{req, res}
+			var cbs map[string]*openapi.Schema
+			if len(e.Callbacks) > 0 {
+				cbs = make(map[string]*openapi.Schema, len(e.Callbacks))
+				for _, c := range e.Callbacks {
+					cbs[c.Name] = sf.schemafy(c.Body)
+				}
+			}
+			sbodies[e.Name()] = &EndpointBodies{req, res, cbs}
 		}
 		bodies[s.Name()] = sbodies
 	}
@@ -187,6 +206,9 @@ func (sf *schemafier) schemafy(attr *expr.AttributeExpr, noref ...bool) *openapi
 		s.Type = openapi.Object
 		var itemNotes []string
 		for _, nat := range *t {
+			if !openapi.IsSerialized(nat.Attribute) {
+				continue
+			}
 			s.Properties[nat.Name] = sf.schemafy(nat.Attribute)
 		}
 		if len(itemNotes) > 0 {
@@ -254,7 +276,7 @@ func (sf *schemafier) schemafy(attr *expr.AttributeExpr, noref ...bool) *openapi
 
 	// Default value, example, extensions
 	s.DefaultValue = toStringMap(attr.DefaultValue)
-	s.Example = attr.Example(sf.rand)
+	s.Example = openapi.SerializedExample(attr.Type, attr.Example(sf.rand))
 	s.Extensions = openapi.ExtensionsFromExpr(attr.Meta)
 
 	// Validations
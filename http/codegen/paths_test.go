@@ -29,6 +29,7 @@ func TestPaths(t *testing.T) {
 		{"path-with-float64-slice-param", testdata.PathFloat64SliceParamDSL, testdata.PathFloat64SliceParamCode},
 		{"path-with-bool-slice-param", testdata.PathBoolSliceParamDSL, testdata.PathBoolSliceParamCode},
 		{"path-with-interface-slice-param", testdata.PathInterfaceSliceParamDSL, testdata.PathInterfaceSliceParamCode},
+		{"path-with-custom-type-param", testdata.PayloadCustomTypeFuncsDSL, testdata.PathCustomTypeParamCode},
 	}
 
 	for _, c := range cases {
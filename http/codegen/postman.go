@@ -0,0 +1,344 @@
+package codegen
+
+import (
+	"encoding/json"
+	"fmt"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strings"
+	"text/template"
+
+	"goa.design/goa/v3/codegen"
+	"goa.design/goa/v3/expr"
+	"goa.design/goa/v3/http/codegen/openapi"
+)
+
+// postmanSchema is the URL of the Postman collection format JSON schema.
+const postmanSchema = "https://schema.getpostman.com/json/collection/v2.1.0/collection.json"
+
+type (
+	// postmanCollection is a Postman collection (format v2.1).
+	postmanCollection struct {
+		Info     *postmanInfo       `json:"info"`
+		Item     []*postmanItem     `json:"item"`
+		Variable []*postmanKeyValue `json:"variable,omitempty"`
+	}
+
+	// postmanInfo describes a Postman collection.
+	postmanInfo struct {
+		Name        string `json:"name"`
+		Description string `json:"description,omitempty"`
+		Schema      string `json:"schema"`
+	}
+
+	// postmanItem is either a folder (one per service) or a request (one
+	// per endpoint route).
+	postmanItem struct {
+		Name        string          `json:"name"`
+		Description string          `json:"description,omitempty"`
+		Item        []*postmanItem  `json:"item,omitempty"`
+		Request     *postmanRequest `json:"request,omitempty"`
+	}
+
+	// postmanRequest describes a HTTP request.
+	postmanRequest struct {
+		Method      string             `json:"method"`
+		Header      []*postmanKeyValue `json:"header"`
+		URL         *postmanURL        `json:"url"`
+		Body        *postmanBody       `json:"body,omitempty"`
+		Description string             `json:"description,omitempty"`
+	}
+
+	// postmanURL describes a request URL, path parameters use the ":name"
+	// syntax and are listed in Variable.
+	postmanURL struct {
+		Raw      string             `json:"raw"`
+		Host     []string           `json:"host"`
+		Path     []string           `json:"path,omitempty"`
+		Query    []*postmanKeyValue `json:"query,omitempty"`
+		Variable []*postmanKeyValue `json:"variable,omitempty"`
+	}
+
+	// postmanBody describes a request body.
+	postmanBody struct {
+		Mode     string             `json:"mode"`
+		Raw      string             `json:"raw,omitempty"`
+		FormData []*postmanKeyValue `json:"formdata,omitempty"`
+		Options  map[string]any     `json:"options,omitempty"`
+	}
+
+	// postmanKeyValue is a named value used for headers, query strings,
+	// path variables and collection variables.
+	postmanKeyValue struct {
+		Key         string `json:"key"`
+		Value       string `json:"value"`
+		Description string `json:"description,omitempty"`
+		Type        string `json:"type,omitempty"`
+	}
+
+	// postmanEnvironment is a Postman environment.
+	postmanEnvironment struct {
+		Name   string             `json:"name"`
+		Values []*postmanEnvValue `json:"values"`
+		Scope  string             `json:"_postman_variable_scope"`
+	}
+
+	// postmanEnvValue is a Postman environment variable.
+	postmanEnvValue struct {
+		Key     string `json:"key"`
+		Value   string `json:"value"`
+		Type    string `json:"type"`
+		Enabled bool   `json:"enabled"`
+	}
+)
+
+// PostmanFiles returns the files containing a Postman collection that covers
+// all the HTTP endpoints of the given API together with a Postman environment
+// that defines the "baseUrl" variable used by the collection requests. The
+// request payloads use the examples defined in the design.
+func PostmanFiles(root *expr.RootExpr) ([]*codegen.File, error) {
+	if len(root.API.HTTP.Services) == 0 {
+		return nil, nil
+	}
+	coll := buildPostmanCollection(root)
+	env := buildPostmanEnvironment(root)
+	render := func(path string, data any) *codegen.File {
+		return &codegen.File{
+			Path: filepath.Join(codegen.Gendir, "http", path),
+			SectionTemplates: []*codegen.SectionTemplate{{
+				Name:    "postman",
+				FuncMap: template.FuncMap{"toJSON": postmanJSON},
+				Source:  "{{ toJSON . }}\n",
+				Data:    data,
+			}},
+		}
+	}
+	return []*codegen.File{
+		render("postman_collection.json", coll),
+		render("postman_environment.json", env),
+	}, nil
+}
+
+// buildPostmanCollection builds the Postman collection for the HTTP services
+// of root, one folder per service and one request per endpoint route.
+func buildPostmanCollection(root *expr.RootExpr) *postmanCollection {
+	var (
+		api  = root.API
+		rand = api.ExampleGenerator
+	)
+	coll := &postmanCollection{
+		Info: &postmanInfo{
+			Name:        api.Name,
+			Description: api.Description,
+			Schema:      postmanSchema,
+		},
+		Variable: []*postmanKeyValue{{Key: "baseUrl", Value: exampleBaseURL(root), Type: "string"}},
+	}
+	if api.Title != "" {
+		coll.Info.Name = api.Title
+	}
+	for _, svc := range root.API.HTTP.Services {
+		folder := &postmanItem{Name: svc.Name(), Description: svc.Description()}
+		for _, e := range svc.HTTPEndpoints {
+			if e.MethodExpr.IsStreaming() {
+				continue // websocket endpoints cannot be described in a collection
+			}
+			for _, r := range e.Routes {
+				for _, p := range r.FullPaths() {
+					name := e.Name()
+					if len(e.Routes) > 1 || len(r.FullPaths()) > 1 {
+						name = fmt.Sprintf("%s (%s %s)", e.Name(), r.Method, p)
+					}
+					folder.Item = append(folder.Item, &postmanItem{
+						Name:    name,
+						Request: buildPostmanRequest(e, r.Method, p, rand),
+					})
+				}
+			}
+		}
+		if len(folder.Item) > 0 {
+			coll.Item = append(coll.Item, folder)
+		}
+	}
+	return coll
+}
+
+// buildPostmanRequest builds the Postman request for the given endpoint route.
+func buildPostmanRequest(e *expr.HTTPEndpointExpr, method, path string, rand *expr.ExampleGenerator) *postmanRequest {
+	req := &postmanRequest{
+		Method:      method,
+		Header:      []*postmanKeyValue{},
+		Description: e.Description(),
+	}
+
+	// URL
+	u := &postmanURL{Host: []string{"{{baseUrl}}"}}
+	{
+		wildcards := expr.ExtractHTTPWildcards(path)
+		path = expr.HTTPWildcardRegex.ReplaceAllString(path, "/:$1")
+		u.Path = strings.Split(strings.TrimPrefix(path, "/"), "/")
+		params := e.Params
+		codegen.WalkMappedAttr(params, func(n, pn string, _ bool, at *expr.AttributeExpr) error { // nolint: errcheck
+			for _, w := range wildcards {
+				if n == w {
+					u.Variable = append(u.Variable, &postmanKeyValue{
+						Key:         pn,
+						Value:       exampleString(at.Example(rand), ","),
+						Description: at.Description,
+					})
+					return nil
+				}
+			}
+			if arr, ok := exampleSlice(at.Example(rand)); ok {
+				for _, v := range arr {
+					u.Query = append(u.Query, &postmanKeyValue{Key: pn, Value: exampleString(v, ","), Description: at.Description})
+				}
+				return nil
+			}
+			u.Query = append(u.Query, &postmanKeyValue{Key: pn, Value: exampleString(at.Example(rand), ","), Description: at.Description})
+			return nil
+		})
+		u.Raw = "{{baseUrl}}" + path
+		if len(u.Query) > 0 {
+			qs := make([]string, len(u.Query))
+			for i, q := range u.Query {
+				qs[i] = q.Key + "=" + q.Value
+			}
+			u.Raw += "?" + strings.Join(qs, "&")
+		}
+	}
+	req.URL = u
+
+	// Headers
+	expr.WalkMappedAttr(e.Headers, func(_, elem string, at *expr.AttributeExpr) error { // nolint: errcheck
+		req.Header = append(req.Header, &postmanKeyValue{
+			Key:         elem,
+			Value:       exampleString(at.Example(rand), ", "),
+			Description: at.Description,
+		})
+		return nil
+	})
+	var cookies []string
+	expr.WalkMappedAttr(e.Cookies, func(_, elem string, at *expr.AttributeExpr) error { // nolint: errcheck
+		cookies = append(cookies, elem+"="+exampleString(at.Example(rand), ","))
+		return nil
+	})
+	if len(cookies) > 0 {
+		req.Header = append(req.Header, &postmanKeyValue{Key: "Cookie", Value: strings.Join(cookies, "; ")})
+	}
+
+	// Body
+	if e.Body.Type != expr.Empty && !e.SkipRequestBodyEncodeDecode {
+		ex := openapi.ToStringMap(e.Body.Example(rand))
+		if e.MultipartRequest {
+			body := &postmanBody{Mode: "formdata"}
+			if m, ok := ex.(map[string]any); ok {
+				keys := make([]string, 0, len(m))
+				for k := range m {
+					keys = append(keys, k)
+				}
+				sort.Strings(keys)
+				for _, k := range keys {
+					body.FormData = append(body.FormData, &postmanKeyValue{Key: k, Value: exampleString(m[k], ","), Type: "text"})
+				}
+			}
+			req.Body = body
+		} else {
+			raw, err := json.MarshalIndent(ex, "", "    ")
+			if err != nil {
+				raw = []byte(fmt.Sprintf("%v", ex))
+			}
+			req.Body = &postmanBody{
+				Mode:    "raw",
+				Raw:     string(raw),
+				Options: map[string]any{"raw": map[string]string{"language": "json"}},
+			}
+			req.Header = append(req.Header, &postmanKeyValue{Key: "Content-Type", Value: "application/json"})
+		}
+	}
+
+	return req
+}
+
+// buildPostmanEnvironment builds the Postman environment defining the
+// variables used by the collection.
+func buildPostmanEnvironment(root *expr.RootExpr) *postmanEnvironment {
+	name := root.API.Name
+	if root.API.Title != "" {
+		name = root.API.Title
+	}
+	return &postmanEnvironment{
+		Name:   name,
+		Values: []*postmanEnvValue{{Key: "baseUrl", Value: exampleBaseURL(root), Type: "default", Enabled: true}},
+		Scope:  "environment",
+	}
+}
+
+// exampleBaseURL returns the first HTTP URI defined in the design servers, the
+// default localhost URL if there is none.
+func exampleBaseURL(root *expr.RootExpr) string {
+	for _, svr := range root.API.Servers {
+		for _, h := range svr.Hosts {
+			for _, u := range h.URIs {
+				if s := u.Scheme(); s != "http" && s != "https" {
+					continue
+				}
+				if uri, err := h.URIString(u); err == nil {
+					return strings.TrimSuffix(uri, "/")
+				}
+			}
+		}
+	}
+	return "http://localhost:80"
+}
+
+// exampleString formats the example value of a parameter, arrays elements
+// are joined with sep.
+func exampleString(v any, sep string) string {
+	if arr, ok := exampleSlice(v); ok {
+		elems := make([]string, len(arr))
+		for i, e := range arr {
+			elems[i] = exampleString(e, sep)
+		}
+		return strings.Join(elems, sep)
+	}
+	switch actual := v.(type) {
+	case nil:
+		return ""
+	case []byte:
+		return string(actual)
+	case string:
+		return actual
+	default:
+		if b, err := json.Marshal(openapi.ToStringMap(v)); err == nil {
+			return string(b)
+		}
+		return fmt.Sprintf("%v", v)
+	}
+}
+
+// exampleSlice returns the elements of v if v is a slice other than []byte.
+func exampleSlice(v any) ([]any, bool) {
+	if _, ok := v.([]byte); ok || v == nil {
+		return nil, false
+	}
+	rv := reflect.ValueOf(v)
+	if rv.Kind() != reflect.Slice {
+		return nil, false
+	}
+	elems := make([]any, rv.Len())
+	for i := range elems {
+		elems[i] = rv.Index(i).Interface()
+	}
+	return elems, true
+}
+
+// postmanJSON returns the indented JSON representation of v.
+func postmanJSON(v any) string {
+	b, err := json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		panic("postman: " + err.Error()) // bug
+	}
+	return string(b)
+}
@@ -0,0 +1,71 @@
+package codegen
+
+import (
+	"path/filepath"
+	"testing"
+
+	"goa.design/goa/v3/http/codegen/testdata"
+)
+
+func TestPostmanFiles(t *testing.T) {
+	root := RunHTTPDSL(t, testdata.PostmanDSL)
+	fs, err := PostmanFiles(root)
+	if err != nil {
+		t.Fatalf("PostmanFiles failed with %s", err)
+	}
+	if len(fs) != 2 {
+		t.Fatalf("got %d files, expected 2", len(fs))
+	}
+	if fs[0].Path != filepath.Join("gen", "http", "postman_collection.json") {
+		t.Errorf("invalid collection path %q", fs[0].Path)
+	}
+	if fs[1].Path != filepath.Join("gen", "http", "postman_environment.json") {
+		t.Errorf("invalid environment path %q", fs[1].Path)
+	}
+}
+
+func TestPostmanCollection(t *testing.T) {
+	root := RunHTTPDSL(t, testdata.PostmanDSL)
+	coll := buildPostmanCollection(root)
+
+	if coll.Info.Name != "Test API" {
+		t.Errorf("got name %q, expected %q", coll.Info.Name, "Test API")
+	}
+	if len(coll.Variable) != 1 || coll.Variable[0].Value != "https://goa.design/api" {
+		t.Errorf("got variables %v, expected baseUrl https://goa.design/api", coll.Variable)
+	}
+	if len(coll.Item) != 1 || len(coll.Item[0].Item) != 1 {
+		t.Fatalf("expected one folder with one request, got %v", coll.Item)
+	}
+	req := coll.Item[0].Item[0].Request
+	if req.Method != "PUT" {
+		t.Errorf("got method %q, expected PUT", req.Method)
+	}
+	if expected := "{{baseUrl}}/items/:id?tags=a&tags=b"; req.URL.Raw != expected {
+		t.Errorf("got raw URL %q, expected %q", req.URL.Raw, expected)
+	}
+	if len(req.URL.Variable) != 1 || req.URL.Variable[0].Key != "id" || req.URL.Variable[0].Value != "42" {
+		t.Errorf("got path variables %v, expected id=42", req.URL.Variable)
+	}
+	headers := make(map[string]string)
+	for _, h := range req.Header {
+		headers[h.Key] = h.Value
+	}
+	if headers["X-Token"] != "secret" {
+		t.Errorf("got X-Token header %q, expected %q", headers["X-Token"], "secret")
+	}
+	if headers["Content-Type"] != "application/json" {
+		t.Errorf("got Content-Type header %q, expected application/json", headers["Content-Type"])
+	}
+	if req.Body == nil || req.Body.Mode != "raw" {
+		t.Fatalf("expected raw body, got %v", req.Body)
+	}
+	if expected := "{\n    \"name\": \"goa\"\n}"; req.Body.Raw != expected {
+		t.Errorf("got body %q, expected %q", req.Body.Raw, expected)
+	}
+
+	env := buildPostmanEnvironment(root)
+	if len(env.Values) != 1 || env.Values[0].Key != "baseUrl" || !env.Values[0].Enabled {
+		t.Errorf("got environment values %v, expected enabled baseUrl", env.Values)
+	}
+}
@@ -19,6 +19,9 @@ func ServerFiles(genpkg string, root *expr.RootExpr) []*codegen.File {
 		if f := websocketServerFile(genpkg, svc); f != nil {
 			files = append(files, f)
 		}
+		if f := callbackServerFile(genpkg, svc); f != nil {
+			files = append(files, f)
+		}
 	}
 	for _, svc := range root.API.HTTP.Services {
 		if f := serverEncodeDecodeFile(genpkg, svc); f != nil {
@@ -53,9 +56,12 @@ func serverFile(genpkg string, svc *expr.HTTPServiceExpr) *codegen.File {
 			{Path: "net/http"},
 			{Path: "path"},
 			{Path: "strings"},
+			{Path: "time"},
 			{Path: "github.com/gorilla/websocket"},
 			codegen.GoaImport(""),
 			codegen.GoaNamedImport("http", "goahttp"),
+			codegen.GoaNamedImport("http/middleware", "httpmdlwr"),
+			codegen.GoaImport("middleware"),
 			{Path: genpkg + "/" + svcName, Name: data.Service.PkgName},
 			{Path: genpkg + "/" + svcName + "/" + "views", Name: data.Service.ViewsPkg},
 		}),
@@ -77,7 +83,23 @@ func serverFile(genpkg string, svc *expr.HTTPServiceExpr) *codegen.File {
 	sections = append(sections, &codegen.SectionTemplate{Name: "server-init", Source: serverInitT, Data: data, FuncMap: funcs})
 	sections = append(sections, &codegen.SectionTemplate{Name: "server-service", Source: serverServiceT, Data: data})
 	sections = append(sections, &codegen.SectionTemplate{Name: "server-use", Source: serverUseT, Data: data})
+	if hasFeatureFlags(data) {
+		sections = append(sections, &codegen.SectionTemplate{Name: "server-feature-flags", Source: serverFeatureFlagsT, Data: data})
+	}
+	if hasSLOs(data) {
+		sections = append(sections, &codegen.SectionTemplate{Name: "server-slos", Source: serverSLOsT, Data: data})
+	}
+	if hasBudgets(data) {
+		sections = append(sections, &codegen.SectionTemplate{Name: "server-budgets", Source: serverBudgetsT, Data: data})
+	}
+	if hasIdempotentMethods(data) {
+		sections = append(sections, &codegen.SectionTemplate{Name: "server-idempotency", Source: serverIdempotencyT, Data: data})
+	}
+	if hasCachedMethods(data) {
+		sections = append(sections, &codegen.SectionTemplate{Name: "server-cache", Source: serverCacheT, Data: data})
+	}
 	sections = append(sections, &codegen.SectionTemplate{Name: "server-method-names", Source: serverMethodNamesT, Data: data})
+	sections = append(sections, &codegen.SectionTemplate{Name: "server-routes", Source: serverRoutesT, Data: data})
 	sections = append(sections, &codegen.SectionTemplate{Name: "server-mount", Source: serverMountT, Data: data, FuncMap: funcs})
 
 	for _, e := range data.Endpoints {
@@ -129,6 +151,7 @@ func serverEncodeDecodeFile(genpkg string, svc *expr.HTTPServiceExpr) *codegen.F
 		if mustDecodeRequest(e) {
 			fm := transTmplFuncs(svc)
 			fm["mapQueryDecodeData"] = mapQueryDecodeData
+			fm["fieldCode"] = fieldCode
 			sections = append(sections, &codegen.SectionTemplate{
 				Name:    "request-decoder",
 				Source:  requestDecoderT,
@@ -155,6 +178,22 @@ func serverEncodeDecodeFile(genpkg string, svc *expr.HTTPServiceExpr) *codegen.F
 			})
 		}
 	}
+	seenLinks := make(map[string]struct{})
+	for _, e := range data.Endpoints {
+		l := e.Result.Links
+		if l == nil {
+			continue
+		}
+		if _, ok := seenLinks[l.SetterName]; ok {
+			continue
+		}
+		seenLinks[l.SetterName] = struct{}{}
+		sections = append(sections, &codegen.SectionTemplate{
+			Name:   "server-links",
+			Source: serverLinksT,
+			Data:   l,
+		})
+	}
 	for _, h := range data.ServerTransformHelpers {
 		sections = append(sections, &codegen.SectionTemplate{
 			Name:   "server-transform-helper",
@@ -330,6 +369,11 @@ func {{ .ServerInit }}(
 				{{- range $e.Routes }}
 			{"{{ $e.Method.VarName }}", "{{ .Verb }}", "{{ .Path }}"},
 				{{- end }}
+				{{- with $e.Batch }}
+					{{- range .Paths }}
+			{"{{ $e.Method.VarName }}", "POST", "{{ . }}"},
+					{{- end }}
+				{{- end }}
 			{{- end }}
 			{{- range .FileServers }}
 				{{- $filepath := .FilePath }}
@@ -359,19 +403,44 @@ func (s *{{ .ServerStruct }}) MethodNames() []string { return {{ .Service.PkgNam
 `
 
 // input: ServiceData
-const serverUseT = `{{ printf "Use wraps the server handlers with the given middleware." | comment }}
+const serverRoutesT = `{{ printf "Routes returns the routes of the %s service endpoints. The routes may be used to build the URLs of requests made to the endpoints, see goahttp.RouteTable." .Service.Name | comment }}
+func (s *{{ .ServerStruct }}) Routes() []*goahttp.Route {
+	return []*goahttp.Route{
+	{{- range $e := .Endpoints }}
+		{{- range $e.Routes }}
+		{Service: "{{ $.Service.Name }}", Method: "{{ $e.Method.Name }}", Verb: "{{ .Verb }}", Pattern: "{{ .Path }}"},
+		{{- end }}
+	{{- end }}
+	}
+}
+`
+
+// input: ServiceData
+const serverUseT = `{{ printf "Use wraps the server handlers with the given middleware. Middlewares apply in the reverse order of the calls to Use and UseMethod: the last middleware added runs first. Use may be called after the server is mounted but not while it serves requests." | comment }}
 func (s *{{ .ServerStruct }}) Use(m func(http.Handler) http.Handler) {
 {{- range .Endpoints }}
 	s.{{ .Method.VarName }} = m(s.{{ .Method.VarName }})
 {{- end }}
 }
+
+{{ printf "UseMethod wraps the handler of the method with the given name (as returned by MethodNames) with the given middleware. Names of methods that are not served are ignored. See Use for the order in which middlewares apply." | comment }}
+func (s *{{ .ServerStruct }}) UseMethod(name string, m func(http.Handler) http.Handler) {
+	switch name {
+{{- range .Endpoints }}
+	case "{{ .Method.Name }}":
+		s.{{ .Method.VarName }} = m(s.{{ .Method.VarName }})
+{{- end }}
+	}
+}
 `
 
 // input: ServiceData
 const serverMountT = `{{ printf "%s configures the mux to serve the %s endpoints." .MountServer .Service.Name | comment }}
 func {{ .MountServer }}(mux goahttp.Muxer, h *{{ .ServerStruct }}) {
 	{{- range .Endpoints }}
-	{{ .MountHandler }}(mux, h.{{ .Method.VarName }})
+	{{ .MountHandler }}(mux, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		h.{{ .Method.VarName }}.ServeHTTP(w, r)
+	}))
 	{{- end }}
 	{{- range .FileServers }}
 		{{- if .Redirect }}
@@ -406,6 +475,12 @@ func {{ .MountHandler }}(mux goahttp.Muxer, h http.Handler) {
 	{{- range .Routes }}
 	mux.Handle("{{ .Verb }}", "{{ .Path }}", f)
 	{{- end }}
+	{{- with .Batch }}
+	batch := goahttp.NewBatchHandler(f)
+		{{- range .Paths }}
+	mux.Handle("POST", "{{ . }}", batch.ServeHTTP)
+		{{- end }}
+	{{- end }}
 }
 `
 
@@ -455,9 +530,40 @@ func {{ .HandlerInit }}(
 	)
 	{{- end }}
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+	{{- if .Payload.Request.Migrations }}
+		r = r.WithContext(goahttp.WithWarnings(r.Context()))
+	{{- end }}
 		ctx := context.WithValue(r.Context(), goahttp.AcceptTypeKey, r.Header.Get("Accept"))
 		ctx = context.WithValue(ctx, goa.MethodKey, {{ printf "%q" .Method.Name }})
 		ctx = context.WithValue(ctx, goa.ServiceKey, {{ printf "%q" .ServiceName }})
+	{{- with .Result.JSONAPI }}
+		ctx = goahttp.WithJSONAPI(ctx, r, &goahttp.JSONAPIResource{Type: {{ printf "%q" .Type }}{{ if .Relationships }}, Relationships: map[string]string{ {{- range $i, $r := .Relationships }}{{ if $i }}, {{ end }}{{ printf "%q" $r.Name }}: {{ printf "%q" $r.Type }}{{ end }}}{{ end }}})
+	{{- end }}
+	{{- with .FieldSelection }}
+		ctx, ferr := goahttp.WithFieldSelection(ctx, r, {{ printf "%q" .Param }}, []string{ {{- range $i, $f := .Fields }}{{ if $i }}, {{ end }}{{ printf "%q" $f }}{{ end }}})
+		if ferr != nil {
+			if err := encodeError(ctx, w, ferr); err != nil {
+				errhandler(ctx, w, err)
+			}
+			return
+		}
+	{{- end }}
+	{{- if .Filters }}
+		if err := goahttp.ValidateFilters(r, []string{ {{- range $i, $f := .Filters }}{{ if $i }}, {{ end }}{{ printf "%q" $f }}{{ end }}}); err != nil {
+			if err := encodeError(ctx, w, err); err != nil {
+				errhandler(ctx, w, err)
+			}
+			return
+		}
+	{{- end }}
+	{{- if .CloudEvents }}
+		if err := goahttp.NormalizeCloudEvent(r); err != nil {
+			if err := encodeError(ctx, w, err); err != nil {
+				errhandler(ctx, w, err)
+			}
+			return
+		}
+	{{- end }}
 
 	{{- if mustDecodeRequest . }}
 		{{ if .Redirect }}_{{ else }}payload{{ end }}, err := decodeRequest(r)
@@ -467,6 +573,9 @@ func {{ .HandlerInit }}(
 			}
 			return
 		}
+		{{- if .Payload.Request.Migrations }}
+		goahttp.SetWarnings(ctx, w)
+		{{- end }}
 	{{- else if not .Redirect }}
 		var err error
 	{{- end }}
@@ -512,6 +621,10 @@ func {{ .HandlerInit }}(
 	{{- if .Method.SkipResponseBodyEncodeDecode }}
 		o := res.(*{{ .ServicePkgName }}.{{ .Method.ResponseStruct }})
 		defer o.Body.Close()
+		if rs, ok := o.Body.(io.ReadSeeker); ok {
+			// serve the requested range of seekable bodies
+			w = goahttp.NewRangeWriter(w, r, rs)
+		}
 		// handle immediate read error like a returned error
 		buf := bufio.NewReader(o.Body)
 		if _, err := buf.Peek(1); err != nil && err != io.EOF {
@@ -530,7 +643,7 @@ func {{ .HandlerInit }}(
 		}
 	{{- end }}
 	{{- if .Method.SkipResponseBodyEncodeDecode }}
-		if _, err := io.Copy(w, buf); err != nil {
+		if _, err := io.Copy(w, goahttp.RangeBody(w, buf)); err != nil {
 			if f, ok := w.(http.Flusher); ok {
 				f.Flush()
 			}
@@ -558,41 +671,46 @@ func {{ .RequestDecoder }}(mux goahttp.Muxer, decoder func(*http.Request) goahtt
 		if err := decoder(r).Decode(&payload); err != nil {
 			return nil, goa.DecodePayloadError(err.Error())
 		}
-{{- else if .Payload.Request.ServerBody }}
-		var (
-			body {{ .Payload.Request.ServerBody.VarName }}
-			err  error
-		)
-		err = decoder(r).Decode(&body)
-		if err != nil {
-	{{- if .Payload.Request.MustHaveBody }}
-			if err == io.EOF {
-				return nil, goa.MissingPayloadError()
-			}
-	{{- else }}
-			if err == io.EOF {
-				err = nil
-			} else {
-	{{- end }}
-			return nil, goa.DecodePayloadError(err.Error())
-	{{- if not .Payload.Request.MustHaveBody }}
-			}
-	{{- end }}
-		}
-	{{- if .Payload.Request.ServerBody.ValidateRef }}
-		{{ .Payload.Request.ServerBody.ValidateRef }}
-		if err != nil {
-			return nil, err
-		}
-	{{- end }}
-{{- end }}
-{{- if not .MultipartRequestDecoder }}
+{{- else }}
 	{{- template "request_elements" .Payload.Request }}
 	{{- if .Payload.Request.MustValidate }}
 		if err != nil {
 			return nil, err
 		}
 	{{- end }}
+	{{- if .DeferBody }}
+	{{- if or .Payload.Request.PathParams .Payload.Request.QueryParams .Payload.Request.Headers .Payload.Request.Cookies }}{{/* we want a newline only if there was code before */}}
+{{ end }}
+		decodeBody := func() ({{ .Payload.Request.PayloadInit.ReturnTypeRef }}, error) {
+		{{- template "request_body" . }}
+			payload := {{ .Payload.Request.PayloadInit.Name }}({{ range .Payload.Request.PayloadInit.ServerArgs }}{{ .Ref }}, {{ end }})
+			return payload, nil
+		}
+		v := &{{ .Payload.Request.PayloadInit.ReturnTypeName }}{}
+		{{ fieldCode .Payload.Request.PayloadInit "server" }}
+		payload := v
+		load := func() error {
+			full, err := decodeBody()
+			if err != nil {
+				return err
+			}
+		{{- with .BasicScheme }}
+			full.{{ .UsernameField }} = payload.{{ .UsernameField }}
+			full.{{ .PasswordField }} = payload.{{ .PasswordField }}
+		{{- end }}
+		{{- range .HeaderSchemes }}
+			full.{{ .CredField }} = payload.{{ .CredField }}
+		{{- end }}
+			*payload = *full
+			return nil
+		}
+		if !goa.DeferBody(r.Context(), load) {
+			if err := load(); err != nil {
+				return nil, err
+			}
+		}
+	{{- else }}
+	{{- template "request_body" . }}
 	{{- if .Payload.Request.PayloadInit }}
 	payload := {{ .Payload.Request.PayloadInit.Name }}({{ range .Payload.Request.PayloadInit.ServerArgs }}{{ .Ref }}, {{ end }})
 	{{- else if .Payload.DecoderReturnValue }}
@@ -600,6 +718,7 @@ func {{ .RequestDecoder }}(mux goahttp.Muxer, decoder func(*http.Request) goahtt
 	{{- else }}
 	payload := body
 	{{- end }}
+	{{- end }}
 {{- end }}
 {{- if .BasicScheme }}{{ with .BasicScheme }}
 	user, pass, {{ if or .UsernameRequired .PasswordRequired }}ok{{ else }}_{{ end }} := r.BasicAuth()
@@ -628,12 +747,68 @@ func {{ .RequestDecoder }}(mux goahttp.Muxer, decoder func(*http.Request) goahtt
 	return payload, nil
 	}
 }
-` + requestElementsT
+` + requestElementsT + requestBodyT
+
+// input: EndpointData
+const requestBodyT = `{{- define "request_body" }}
+	{{- with .Payload.Request.ServerBody }}
+	{{- if and (not $.DeferBody) (or $.Payload.Request.PathParams $.Payload.Request.QueryParams $.Payload.Request.Headers $.Payload.Request.Cookies) }}{{/* we want a newline only if there was code before */}}
+{{ end }}
+	{{- if and $.Payload.Request.MustValidate (not $.DeferBody) }}
+		var body {{ .VarName }}
+	{{- else }}
+		var (
+			body {{ .VarName }}
+			err  error
+		)
+	{{- end }}
+	{{- $decoder := "decoder(r)" }}
+	{{- with $.Payload.Request.PresentFields }}
+		{{- $decoder = printf "goahttp.NewPresenceDecoder(decoder(r), &body.%s)" . }}
+	{{- end }}
+	{{- with $.Payload.Request.UnknownFields }}
+		{{- if .Disallow }}
+		err = goahttp.DecodeStrict({{ $decoder }}, &body)
+		{{- else }}
+		err = goahttp.DecodeCollectUnknown({{ $decoder }}, &body, {{ printf "%#v" .Known }}, &body.{{ .FieldName }})
+		{{- end }}
+	{{- else }}
+		err = {{ $decoder }}.Decode(&body)
+	{{- end }}
+		if err != nil {
+	{{- if $.Payload.Request.MustHaveBody }}
+			if err == io.EOF {
+				return nil, goa.MissingPayloadError()
+			}
+	{{- else }}
+			if err == io.EOF {
+				err = nil
+			} else {
+	{{- end }}
+			return nil, goa.DecodePayloadError(err.Error())
+	{{- if not $.Payload.Request.MustHaveBody }}
+			}
+	{{- end }}
+		}
+	{{- range $.Payload.Request.Migrations }}
+		if body.{{ .FieldName }} == nil && goahttp.UseMigrationDefault(r.Context(), {{ printf "%q" .Name }}, {{ printf "%q" .Until }}) {
+			var v {{ .TypeRef }} = {{ printf "%#v" .DefaultValue }}
+			body.{{ .FieldName }} = &v
+		}
+	{{- end }}
+	{{- if .ValidateRef }}
+		{{ .ValidateRef }}
+		if err != nil {
+			return nil, err
+		}
+	{{- end }}
+	{{- end }}
+{{- end }}`
 
 // input: RequestData
 const requestElementsT = `{{- define "request_elements" }}
 {{- if or .PathParams .QueryParams .Headers .Cookies }}
-{{- if .ServerBody }}{{/* we want a newline only if there was code before */}}
+{{- if .Multipart }}{{/* we want a newline only if there was code before */}}
 {{ end }}
 		var (
 		{{- range .PathParams }}
@@ -648,7 +823,7 @@ const requestElementsT = `{{- define "request_elements" }}
 		{{- range .Cookies }}
 			{{ .VarName }} {{ .TypeRef }}
 		{{- end }}
-		{{- if and .MustValidate (or (not .ServerBody) .Multipart) }}
+		{{- if .MustValidate }}
 			err error
 		{{- end }}
 		{{- if .Cookies }}
@@ -658,10 +833,13 @@ const requestElementsT = `{{- define "request_elements" }}
 
 			params = mux.Vars(r)
 		{{- end }}
+		{{- if .QueryParams }}
+			qp = r.URL.Query()
+		{{- end }}
 		)
 
 {{- range .PathParams }}
-	{{- if and (or (eq .Type.Name "string") (eq .Type.Name "any")) }}
+	{{- if and (not .Decode) (or (eq .Type.Name "string") (eq .Type.Name "any")) }}
 		{{ .VarName }} = params["{{ .HTTPName }}"]
 
 	{{- else }}{{/* not string and not any */}}
@@ -677,14 +855,14 @@ const requestElementsT = `{{- define "request_elements" }}
 {{- end }}
 
 {{- range .QueryParams }}
-	{{- if and (or (eq .Type.Name "string") (eq .Type.Name "any")) .Required }}
-		{{ .VarName }} = r.URL.Query().Get("{{ .HTTPName }}")
+	{{- if and (not .Decode) (or (eq .Type.Name "string") (eq .Type.Name "any")) .Required }}
+		{{ .VarName }} = qp.Get("{{ .HTTPName }}")
 		if {{ .VarName }} == "" {
 			err = goa.MergeErrors(err, goa.MissingFieldError("{{ .Name }}", "query string"))
 		}
 
-	{{- else if (or (eq .Type.Name "string") (eq .Type.Name "any")) }}
-		{{ .VarName }}Raw := r.URL.Query().Get("{{ .HTTPName }}")
+	{{- else if and (not .Decode) (or (eq .Type.Name "string") (eq .Type.Name "any")) }}
+		{{ .VarName }}Raw := qp.Get("{{ .HTTPName }}")
 		if {{ .VarName }}Raw != "" {
 			{{ .VarName }} = {{ if and (eq .Type.Name "string") .Pointer }}&{{ end }}{{ .VarName }}Raw
 		}
@@ -694,7 +872,7 @@ const requestElementsT = `{{- define "request_elements" }}
 		{{- end }}
 
 	{{- else if .StringSlice }}
-		{{ .VarName }} = r.URL.Query()["{{ .HTTPName }}"]
+		{{ .VarName }} = qp["{{ .HTTPName }}"]
 		{{- if .Required }}
 		if {{ .VarName }} == nil {
 			err = goa.MergeErrors(err, goa.MissingFieldError("{{ .Name }}", "query string"))
@@ -711,7 +889,7 @@ const requestElementsT = `{{- define "request_elements" }}
 
 	{{- else if .Slice }}
 	{
-		{{ .VarName }}Raw := r.URL.Query()["{{ .HTTPName }}"]
+		{{ .VarName }}Raw := qp["{{ .HTTPName }}"]
 		{{- if .Required }}
 		if {{ .VarName }}Raw == nil {
 			err = goa.MergeErrors(err, goa.MissingFieldError("{{ .Name }}", "query string"))
@@ -734,22 +912,21 @@ const requestElementsT = `{{- define "request_elements" }}
 
 	{{- else if .Map }}
 	{
-		{{ .VarName }}Raw := r.URL.Query()
 		{{- if .Required }}
-		if len({{ .VarName }}Raw) == 0 {
+		if len(qp) == 0 {
 			err = goa.MergeErrors(err, goa.MissingFieldError("{{ .Name }}", "query string"))
 		}
 		{{- else if .DefaultValue }}
-		if len({{ .VarName }}Raw) == 0 {
+		if len(qp) == 0 {
 			{{ .VarName }} = {{ printf "%#v" .DefaultValue }}
 		}
 		{{- end }}
 
 		{{- if .DefaultValue }}else {
 		{{- else if not .Required }}
-		if len({{ .VarName }}Raw) != 0 {
+		if len(qp) != 0 {
 		{{- end }}
-		for keyRaw, valRaw := range {{ .VarName }}Raw {
+		for keyRaw, valRaw := range qp {
 			if strings.HasPrefix(keyRaw, "{{ .HTTPName }}[") {
 				{{- template "map_conversion" (mapQueryDecodeData .Type .VarName 0) }}
 			}
@@ -761,22 +938,21 @@ const requestElementsT = `{{- define "request_elements" }}
 
 	{{- else if .MapQueryParams }}
 	{
-		{{ .VarName }}Raw := r.URL.Query()
 		{{- if .Required }}
-		if len({{ .VarName }}Raw) == 0 {
+		if len(qp) == 0 {
 			err = goa.MergeErrors(err, goa.MissingFieldError("{{ .Name }}", "query string"))
 		}
 		{{- else if .DefaultValue }}
-		if len({{ .VarName }}Raw) == 0 {
+		if len(qp) == 0 {
 			{{ .VarName }} = {{ printf "%#v" .DefaultValue }}
 		}
 		{{- end }}
 
 		{{- if .DefaultValue }}else {
 		{{- else if not .Required }}
-		if len({{ .VarName }}Raw) != 0 {
+		if len(qp) != 0 {
 		{{- end }}
-		for keyRaw, valRaw := range {{ .VarName }}Raw {
+		for keyRaw, valRaw := range qp {
 			if strings.HasPrefix(keyRaw, "{{ .HTTPName }}[") {
 				{{- template "map_conversion" (mapQueryDecodeData .Type .VarName 0) }}
 			}
@@ -788,7 +964,7 @@ const requestElementsT = `{{- define "request_elements" }}
 
 	{{- else }}{{/* not string, not any, not slice and not map */}}
 	{
-		{{ .VarName }}Raw := r.URL.Query().Get("{{ .HTTPName }}")
+		{{ .VarName }}Raw := qp.Get("{{ .HTTPName }}")
 		{{- if .Required }}
 		if {{ .VarName }}Raw == "" {
 			err = goa.MergeErrors(err, goa.MissingFieldError("{{ .Name }}", "query string"))
@@ -816,13 +992,13 @@ const requestElementsT = `{{- define "request_elements" }}
 {{- end }}
 
 {{- range .Headers }}
-	{{- if and (or (eq .Type.Name "string") (eq .Type.Name "any")) .Required }}
+	{{- if and (not .Decode) (or (eq .Type.Name "string") (eq .Type.Name "any")) .Required }}
 		{{ .VarName }} = r.Header.Get("{{ .HTTPName }}")
 		if {{ .VarName }} == "" {
 			err = goa.MergeErrors(err, goa.MissingFieldError("{{ .Name }}", "header"))
 		}
 
-	{{- else if (or (eq .Type.Name "string") (eq .Type.Name "any")) }}
+	{{- else if and (not .Decode) (or (eq .Type.Name "string") (eq .Type.Name "any")) }}
 		{{ .VarName }}Raw := r.Header.Get("{{ .HTTPName }}")
 		if {{ .VarName }}Raw != "" {
 			{{ .VarName }} = {{ if and (eq .Type.Name "string") .Pointer }}&{{ end }}{{ .VarName }}Raw
@@ -896,14 +1072,14 @@ const requestElementsT = `{{- define "request_elements" }}
 
 {{- range .Cookies }}
 	c, {{ if not .Required }}_{{ else }}err{{ end }} = r.Cookie("{{ .HTTPName }}")
-	{{- if and (or (eq .Type.Name "string") (eq .Type.Name "any")) .Required }}
+	{{- if and (not .Decode) (or (eq .Type.Name "string") (eq .Type.Name "any")) .Required }}
 		if err == http.ErrNoCookie {
 			err = goa.MergeErrors(err, goa.MissingFieldError("{{ .Name }}", "cookie"))
 		} else {
 			{{ .VarName }} = c.Value
 		}
 
-	{{- else if (or (eq .Type.Name "string") (eq .Type.Name "any")) }}
+	{{- else if and (not .Decode) (or (eq .Type.Name "string") (eq .Type.Name "any")) }}
 		var {{ .VarName }}Raw string
 		if c != nil {
 			{{ .VarName }}Raw = c.Value
@@ -1012,7 +1188,13 @@ const typeConversionT = `{{- define "slice_conversion" }}
 {{- end }}
 
 {{- define "type_conversion" }}
-	{{- if eq .Type.Name "bytes" }}
+	{{- if .Decode }}
+		v, err2 := {{ .Decode }}({{ .VarName }}Raw)
+		if err2 != nil {
+			err = goa.MergeErrors(err, goa.InvalidValueError({{ printf "%q" .Name }}, {{ .VarName }}Raw, err2))
+		}
+		{{ .VarName }} = {{ if .Pointer }}&{{ end }}v
+	{{- else if eq .Type.Name "bytes" }}
 		{{ .VarName }} = []byte({{.VarName}}Raw)
 	{{- else if eq .Type.Name "int" }}
 		v, err2 := strconv.ParseInt({{ .VarName }}Raw, 10, strconv.IntSize)
@@ -1168,12 +1350,27 @@ const typeConversionT = `{{- define "slice_conversion" }}
 const responseEncoderT = `{{ printf "%s returns an encoder for responses returned by the %s %s endpoint." .ResponseEncoder .ServiceName .Method.Name | comment }}
 func {{ .ResponseEncoder }}(encoder func(context.Context, http.ResponseWriter) goahttp.Encoder) func(context.Context, http.ResponseWriter, any) error {
 	return func(ctx context.Context, w http.ResponseWriter, v any) error {
+	{{- if .CacheControl }}
+		w.Header().Set("Cache-Control", {{ printf "%q" .CacheControl }})
+		{{- if .Vary }}
+		w.Header().Set("Vary", "{{ range $i, $h := .Vary }}{{ if $i }}, {{ end }}{{ $h }}{{ end }}")
+		{{- end }}
+	{{- end }}
 	{{- if .Result.MustInit }}
 		{{- if .Method.ViewedResult }}
 			res := v.({{ .Method.ViewedResult.FullRef }})
 			{{- if not .Method.ViewedResult.ViewName }}
 				w.Header().Set("goa-view", res.View)
 			{{- end }}
+			{{- with .Result.Links }}
+				{{- if .IsCollection }}
+			for _, r := range res.Projected {
+				{{ .SetterName }}(r)
+			}
+				{{- else }}
+			{{ .SetterName }}(res.Projected)
+				{{- end }}
+			{{- end }}
 		{{- else }}
 			res, _ := v.({{ .Result.Ref }})
 		{{- end }}
@@ -1189,7 +1386,9 @@ func {{ .ResponseEncoder }}(encoder func(context.Context, http.ResponseWriter) g
 				{{- end }}
 			{{- end -}}
 			{{ template "response" . }}
-			{{- if .ServerBody }}
+			{{- if and .ServerBody $.ChunkSize }}
+				return goahttp.EncodeChunked(w, enc, body, {{ $.ChunkSize }})
+			{{- else if .ServerBody }}
 				return enc.Encode(body)
 			{{- else }}
 				return nil
@@ -1208,11 +1407,31 @@ func {{ .ResponseEncoder }}(encoder func(context.Context, http.ResponseWriter) g
 }
 ` + responseT
 
+// input: LinksData
+const serverLinksT = `{{ printf "%s sets the links of the %s result type to the URLs of the related resources." .SetterName .ResultName | comment }}
+func {{ .SetterName }}(res {{ .TypeRef }}) {
+	if res == nil {
+		return
+	}
+	links := make(map[string]map[string]string)
+	{{- range .Links }}
+	goahttp.AddLink(links, {{ printf "%q" .Name }}, {{ printf "%q" .Pattern }}, {{ if .Params }}map[string]any{ {{- range $i, $p := .Params }}{{ if $i }}, {{ end }}{{ printf "%q" $p.Name }}: res.{{ $p.FieldName }}{{ end }}}{{ else }}nil{{ end }})
+	{{- end }}
+	res.{{ .FieldName }} = links
+}
+`
+
 // input: EndpointData
 const errorEncoderT = `{{ printf "%s returns an encoder for errors returned by the %s %s endpoint." .ErrorEncoder .Method.Name .ServiceName | comment }}
 func {{ .ErrorEncoder }}(encoder func(context.Context, http.ResponseWriter) goahttp.Encoder, formatter func(ctx context.Context, err error) goahttp.Statuser) func(context.Context, http.ResponseWriter, error) error {
 	encodeError := goahttp.ErrorEncoder(encoder, formatter)
 	return func(ctx context.Context, w http.ResponseWriter, v error) error {
+	{{- if .Result.JSONAPI }}
+		ctx = goahttp.WithJSONAPIErrors(ctx)
+	{{- end }}
+	{{- if .FieldSelection }}
+		ctx = goahttp.WithoutFieldSelection(ctx)
+	{{- end }}
 		var en goa.GoaErrorNamer
 		if !errors.As(v, &en) {
 			return encodeError(ctx, w, v)
@@ -1281,8 +1500,10 @@ const responseT = `{{ define "response" -}}
 	if res{{ if .FieldName }}.{{ end }}{{ if $.ViewedResult }}Projected.{{ end }}{{ if .FieldName }}{{ .FieldName }}{{ end }} != nil {
 		{{- end }}
 
-		{{- if and (eq .Type.Name "string") (not (isAliased .FieldType)) }}
-	w.Header().Set("{{ .CanonicalName }}", {{ if or .FieldPointer $.ViewedResult }}*{{ end }}res{{ if $.ViewedResult }}.Projected{{ end }}{{ if .FieldName }}.{{ .FieldName }}{{ end }})
+		{{- if .Encode }}
+	w.Header().Set("{{ .CanonicalName }}", {{ .Encode }}({{ if or .FieldPointer $.ViewedResult }}*{{ end }}res{{ if $.ViewedResult }}.Projected{{ end }}{{ if .FieldName }}.{{ .FieldName }}{{ end }}))
+		{{- else if and (eq .Type.Name "string") (not (isAliased .FieldType)) }}
+	w.Header().Set("{{ .CanonicalName }}", {{ if .Disposition }}goahttp.FormatContentDisposition("{{ .Disposition }}", {{ end }}{{ if or .FieldPointer $.ViewedResult }}*{{ end }}res{{ if $.ViewedResult }}.Projected{{ end }}{{ if .FieldName }}.{{ .FieldName }}{{ end }}{{ if .Disposition }}){{ end }})
 		{{- else }}
 {{- if not $checkNil }}
 {
@@ -1318,7 +1539,9 @@ const responseT = `{{ define "response" -}}
 	if res.{{ if $.ViewedResult }}Projected.{{ end }}{{ .FieldName }} != nil {
 		{{- end }}
 
-		{{- if eq .Type.Name "string" }}
+		{{- if .Encode }}
+	{{ .VarName }} := {{ .Encode }}({{ if or .FieldPointer $.ViewedResult }}*{{ end }}res{{ if $.ViewedResult }}.Projected{{ end }}{{ if .FieldName }}.{{ .FieldName }}{{ end }})
+		{{- else if eq .Type.Name "string" }}
 	{{ .VarName }} := {{ if or .FieldPointer $.ViewedResult }}*{{ end }}res{{ if $.ViewedResult }}.Projected{{ end }}{{ if .FieldName }}.{{ .FieldName }}{{ end }}
 		{{- else }}
 			{{- if isAliased .FieldType }}
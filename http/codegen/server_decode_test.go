@@ -23,6 +23,7 @@ func TestDecode(t *testing.T) {
 		{"decode-path-custom-uint", testdata.PayloadPathCustomUIntDSL, testdata.PayloadPathCustomUIntDecodeCode},
 		{"decode-path-custom-uint32", testdata.PayloadPathCustomUInt32DSL, testdata.PayloadPathCustomUInt32DecodeCode},
 		{"decode-path-custom-uint64", testdata.PayloadPathCustomUInt64DSL, testdata.PayloadPathCustomUInt64DecodeCode},
+		{"decode-custom-type-funcs", testdata.PayloadCustomTypeFuncsDSL, testdata.PayloadCustomTypeFuncsDecodeCode},
 		{"decode-query-bool", testdata.PayloadQueryBoolDSL, testdata.PayloadQueryBoolDecodeCode},
 		{"decode-query-bool-validate", testdata.PayloadQueryBoolValidateDSL, testdata.PayloadQueryBoolValidateDecodeCode},
 		{"decode-query-int", testdata.PayloadQueryIntDSL, testdata.PayloadQueryIntDecodeCode},
@@ -126,6 +127,7 @@ func TestDecode(t *testing.T) {
 		{"decode-header-primitive-bool-validate", testdata.PayloadHeaderPrimitiveBoolValidateDSL, testdata.PayloadHeaderPrimitiveBoolValidateDecodeCode},
 		{"decode-header-primitive-array-string-validate", testdata.PayloadHeaderPrimitiveArrayStringValidateDSL, testdata.PayloadHeaderPrimitiveArrayStringValidateDecodeCode},
 		{"decode-header-primitive-array-bool-validate", testdata.PayloadHeaderPrimitiveArrayBoolValidateDSL, testdata.PayloadHeaderPrimitiveArrayBoolValidateDecodeCode},
+		{"decode-header-inherited", testdata.PayloadHeaderInheritedDSL, testdata.PayloadHeaderInheritedDecodeCode},
 
 		{"decode-header-string-default", testdata.PayloadHeaderStringDefaultDSL, testdata.PayloadHeaderStringDefaultDecodeCode},
 		{"decode-header-string-default-validate", testdata.PayloadHeaderStringDefaultValidateDSL, testdata.PayloadHeaderStringDefaultValidateDecodeCode},
@@ -145,6 +147,11 @@ func TestDecode(t *testing.T) {
 		{"decode-body-string-validate", testdata.PayloadBodyStringValidateDSL, testdata.PayloadBodyStringValidateDecodeCode},
 		{"decode-body-user", testdata.PayloadBodyUserDSL, testdata.PayloadBodyUserDecodeCode},
 		{"decode-body-user-required", testdata.PayloadBodyUserRequiredDSL, testdata.PayloadBodyUserRequiredDecodeCode},
+		{"decode-body-user-migration", testdata.PayloadBodyUserMigrationDSL, testdata.PayloadBodyUserMigrationDecodeCode},
+		{"decode-body-user-unknown-fields", testdata.PayloadBodyUserUnknownFieldsDSL, testdata.PayloadBodyUserUnknownFieldsDecodeCode},
+		{"decode-body-field-presence", testdata.PayloadBodyFieldPresenceDSL, testdata.PayloadBodyFieldPresenceDecodeCode},
+		{"decode-body-secure", testdata.PayloadBodySecureDSL, testdata.PayloadBodySecureDecodeCode},
+		{"decode-reserved-names", testdata.PayloadReservedNamesDSL, testdata.PayloadReservedNamesDecodeCode},
 		{"decode-body-user-nested", testdata.PayloadBodyNestedUserDSL, testdata.PayloadBodyNestedUserDecodeCode},
 		{"decode-body-user-validate", testdata.PayloadBodyUserValidateDSL, testdata.PayloadBodyUserValidateDecodeCode},
 		{"decode-body-object", testdata.PayloadBodyObjectDSL, testdata.PayloadBodyObjectDecodeCode},
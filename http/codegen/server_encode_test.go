@@ -1,9 +1,14 @@
 package codegen
 
 import (
+	"fmt"
+	"net/url"
+	"path/filepath"
+	"reflect"
 	"testing"
 
 	"goa.design/goa/v3/codegen"
+	"goa.design/goa/v3/codegen/codegentest"
 	"goa.design/goa/v3/expr"
 	"goa.design/goa/v3/http/codegen/testdata"
 )
@@ -24,6 +29,7 @@ func TestEncode(t *testing.T) {
 		{"header-float32", testdata.ResultHeaderFloat32DSL, testdata.ResultHeaderFloat32EncodeCode},
 		{"header-float64", testdata.ResultHeaderFloat64DSL, testdata.ResultHeaderFloat64EncodeCode},
 		{"header-string", testdata.ResultHeaderStringDSL, testdata.ResultHeaderStringEncodeCode},
+		{"header-content-disposition", testdata.ResultHeaderContentDispositionDSL, testdata.ResultHeaderContentDispositionEncodeCode},
 		{"header-bytes", testdata.ResultHeaderBytesDSL, testdata.ResultHeaderBytesEncodeCode},
 		{"header-any", testdata.ResultHeaderAnyDSL, testdata.ResultHeaderAnyEncodeCode},
 		{"header-array-bool", testdata.ResultHeaderArrayBoolDSL, testdata.ResultHeaderArrayBoolEncodeCode},
@@ -82,6 +88,8 @@ func TestEncode(t *testing.T) {
 		{"empty-server-response", testdata.EmptyServerResponseDSL, testdata.EmptyServerResponseEncodeCode},
 		{"empty-server-response-with-tags", testdata.EmptyServerResponseWithTagsDSL, testdata.EmptyServerResponseWithTagsEncodeCode},
 
+		{"chunked-result", testdata.ResultBodyChunkedDSL, testdata.ResultBodyChunkedEncodeCode},
+
 		{"result-with-custom-pkg-type", testdata.ResultWithCustomPkgTypeDSL, testdata.ResultWithCustomPkgTypeEncodeCode},
 		{"result-with-embedded-custom-pkg-type", testdata.EmbeddedCustomPkgTypeDSL, testdata.ResultWithEmbeddedCustomPkgTypeEncodeCode},
 	}
@@ -145,3 +153,47 @@ func TestEncodeMarshallingAndUnmarshalling(t *testing.T) {
 		})
 	}
 }
+
+func TestEncodeLinks(t *testing.T) {
+	cases := []struct {
+		Name        string
+		DSL         func()
+		SectionName string
+		Code        string
+	}{
+		{"encoder", testdata.ResultBodyLinksDSL, "response-encoder", testdata.ResultBodyLinksEncodeCode},
+		{"setter", testdata.ResultBodyLinksDSL, "server-links", testdata.ResultBodyLinksSetterCode},
+		{"collection-encoder", testdata.ResultBodyCollectionLinksDSL, "response-encoder", testdata.ResultBodyCollectionLinksEncodeCode},
+		{"collection-setter", testdata.ResultBodyCollectionLinksDSL, "server-links", testdata.ResultBodyCollectionLinksSetterCode},
+	}
+	for _, c := range cases {
+		t.Run(c.Name, func(t *testing.T) {
+			RunHTTPDSL(t, c.DSL)
+			fs := ServerFiles("", expr.Root)
+			sections := codegentest.Sections(fs, filepath.Join("", "encode_decode.go"), c.SectionName)
+			if len(sections) == 0 {
+				t.Fatalf("got no %q section", c.SectionName)
+			}
+			code := codegen.SectionsCode(t, sections)
+			if code != c.Code {
+				t.Errorf("invalid code, got:\n%s\ngot vs. expected:\n%s", code, codegen.Diff(t, code, c.Code))
+			}
+		})
+	}
+}
+
+func TestLinksExample(t *testing.T) {
+	RunHTTPDSL(t, testdata.ResultBodyLinksDSL)
+	rt := expr.Root.Service("ServiceBodyLinks").Method("MethodShow").Result.Type.(*expr.ResultTypeExpr)
+	ex, ok := rt.Example(expr.Root.API.ExampleGenerator).(map[string]any)
+	if !ok {
+		t.Fatalf("got example of type %T, expected map", ex)
+	}
+	expected := map[string]map[string]string{
+		"self":  {"href": fmt.Sprintf("/items/%v", ex["id"])},
+		"owner": {"href": "/owners/" + url.PathEscape(fmt.Sprint(ex["owner_id"]))},
+	}
+	if !reflect.DeepEqual(ex["_links"], expected) {
+		t.Errorf("got links %v, expected %v", ex["_links"], expected)
+	}
+}
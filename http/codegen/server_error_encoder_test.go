@@ -28,6 +28,8 @@ func TestEncodeError(t *testing.T) {
 		{"api-no-body-error-response-with-content-type", testdata.APINoBodyErrorResponseWithContentTypeDSL, testdata.NoBodyErrorResponseWithContentTypeEncoderCode},
 		{"empty-error-response-body", testdata.EmptyErrorResponseBodyDSL, testdata.EmptyErrorResponseBodyEncoderCode},
 		{"empty-custom-error-response-body", testdata.EmptyCustomErrorResponseBodyDSL, testdata.EmptyCustomErrorResponseBodyEncoderCode},
+		{"jsonapi-error-response", testdata.JSONAPIErrorResponseDSL, testdata.JSONAPIErrorResponseEncoderCode},
+		{"field-selection-error-response", testdata.ServerFieldSelectionDSL, testdata.FieldSelectionErrorResponseEncoderCode},
 	}
 	for _, c := range cases {
 		t.Run(c.Name, func(t *testing.T) {
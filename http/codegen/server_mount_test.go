@@ -28,6 +28,8 @@ func TestServerMount(t *testing.T) {
 		{"multiple files mounter /w prefix path", testdata.ServerMultipleFilesWithPrefixPathDSL, testdata.ServerMultipleFilesWithPrefixPathMounterCode, 3, "server-files"},
 		{"multiple files with a redirect constructor", testdata.ServerMultipleFilesWithRedirectDSL, testdata.ServerMultipleFilesWithRedirectConstructorCode, 0, "server-mount"},
 		{"multiple files with a redirect mounter", testdata.ServerMultipleFilesWithRedirectDSL, testdata.ServerMultipleFilesMounterCode, 3, "server-files"},
+		{"multiple endpoints use", testdata.ServerMultiEndpointsDSL, testdata.ServerMultiEndpointsUseCode, 0, "server-use"},
+		{"multiple bases routes", testdata.ServerMultiBasesDSL, testdata.ServerMultiBasesRoutesCode, 0, "server-routes"},
 	}
 	for _, c := range cases {
 		t.Run(c.Name, func(t *testing.T) {
@@ -50,9 +50,12 @@ func serverType(genpkg string, svc *expr.HTTPServiceExpr, _ map[string]struct{})
 	path = filepath.Join(codegen.Gendir, "http", svcName, "server", "types.go")
 	imports := []*codegen.ImportSpec{
 		{Path: "encoding/json"},
+		{Path: "sort"},
+		{Path: "strconv"},
 		{Path: "unicode/utf8"},
 		{Path: genpkg + "/" + svcName, Name: data.Service.PkgName},
 		codegen.GoaImport(""),
+		codegen.GoaNamedImport("http", "goahttp"),
 		{Path: genpkg + "/" + svcName + "/" + "views", Name: data.Service.ViewsPkg},
 	}
 	imports = append(imports, data.Service.UserTypeImports...)
@@ -61,6 +64,7 @@ func serverType(genpkg string, svc *expr.HTTPServiceExpr, _ map[string]struct{})
 	var (
 		initData       []*InitData
 		validatedTypes []*TypeData
+		appendedTypes  []*TypeData
 
 		sections = []*codegen.SectionTemplate{header}
 	)
@@ -115,12 +119,38 @@ func serverType(genpkg string, svc *expr.HTTPServiceExpr, _ map[string]struct{})
 					if tdata.ValidateDef != "" {
 						validatedTypes = append(validatedTypes, tdata)
 					}
+					if tdata.AppendDef != "" {
+						appendedTypes = append(appendedTypes, tdata)
+					}
 					data.ServerTypeNames[tdata.Name] = true
 				}
 			}
 		}
 	}
 
+	// callback body types
+	for _, adata := range data.Endpoints {
+		for _, c := range adata.Callbacks {
+			tdata := c.Body
+			if tdata == nil {
+				continue
+			}
+			if generated, ok := data.ServerTypeNames[tdata.Name]; ok && !generated {
+				if tdata.Def != "" {
+					sections = append(sections, &codegen.SectionTemplate{
+						Name:   "callback-server-body",
+						Source: typeDeclT,
+						Data:   tdata,
+					})
+				}
+				if tdata.Init != nil {
+					initData = append(initData, tdata.Init)
+				}
+				data.ServerTypeNames[tdata.Name] = true
+			}
+		}
+	}
+
 	// error body types
 	for _, a := range svc.HTTPEndpoints {
 		adata := data.Endpoint(a.Name())
@@ -140,6 +170,9 @@ func serverType(genpkg string, svc *expr.HTTPServiceExpr, _ map[string]struct{})
 					if data.ValidateDef != "" {
 						validatedTypes = append(validatedTypes, data)
 					}
+					if data.AppendDef != "" {
+						appendedTypes = append(appendedTypes, data)
+					}
 				}
 			}
 		}
@@ -158,6 +191,9 @@ func serverType(genpkg string, svc *expr.HTTPServiceExpr, _ map[string]struct{})
 		if tdata.ValidateDef != "" {
 			validatedTypes = append(validatedTypes, tdata)
 		}
+		if tdata.AppendDef != "" {
+			appendedTypes = append(appendedTypes, tdata)
+		}
 	}
 
 	// body constructors
@@ -200,6 +236,15 @@ func serverType(genpkg string, svc *expr.HTTPServiceExpr, _ map[string]struct{})
 		})
 	}
 
+	// JSON encoding methods
+	for _, data := range appendedTypes {
+		sections = append(sections, &codegen.SectionTemplate{
+			Name:   "server-append-json",
+			Source: appendJSONT,
+			Data:   data,
+		})
+	}
+
 	return &codegen.File{Path: path, SectionTemplates: sections}
 }
 
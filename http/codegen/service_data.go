@@ -8,6 +8,7 @@ import (
 	"strconv"
 	"strings"
 	"text/template"
+	"time"
 
 	"goa.design/goa/v3/codegen"
 	"goa.design/goa/v3/codegen/service"
@@ -124,6 +125,50 @@ type (
 		// Requirements contains the security requirements for the
 		// method.
 		Requirements service.RequirementsData
+		// FieldSelection contains the data needed to render the code
+		// that applies the client field selection, nil if the endpoint
+		// does not use the FieldSelection DSL.
+		FieldSelection *FieldSelectionData
+		// Filters lists the names of the filters defined with the
+		// Filter DSL that are mapped to "filter[NAME]" query string
+		// parameters sorted alphabetically.
+		Filters []string
+		// Batch contains the data needed to render the code that serves
+		// and makes batch requests, nil if the endpoint does not use
+		// the Batch DSL.
+		Batch *BatchData
+		// ChunkSize is the number of result array elements written by
+		// the response encoder before flushing the response, 0 if the
+		// endpoint does not use the ChunkedResult DSL.
+		ChunkSize int
+		// FeatureFlag contains the data needed to render the code that
+		// gates the endpoint with a feature flag, nil if the endpoint
+		// is not gated.
+		FeatureFlag *FeatureFlagData
+		// SLO contains the data needed to render the code that records
+		// the requests of the endpoint with a SLO tracker, nil if the
+		// endpoint has no service level objective.
+		SLO *SLOData
+		// Budget contains the data needed to render the code that
+		// enforces the response size and latency budget of the
+		// endpoint, nil if the endpoint has no budget.
+		Budget *BudgetData
+		// IdempotencyKey is the name of the request header holding the
+		// idempotency key, empty if the endpoint does not use the
+		// Idempotent DSL.
+		IdempotencyKey string
+		// CacheControl is the value of the Cache-Control header of the
+		// successful responses, empty if the endpoint does not use the
+		// CacheControl DSL.
+		CacheControl string
+		// Vary lists the request headers listed in the Vary header of
+		// the successful responses.
+		Vary []string
+		// CloudEvents is true if the endpoint receives CloudEvents.
+		CloudEvents bool
+		// Callbacks lists the data needed to render the functions that
+		// send the endpoint callbacks.
+		Callbacks []*CallbackData
 
 		// server
 
@@ -141,6 +186,10 @@ type (
 		// MultipartRequestDecoder indicates the request decoder for
 		// multipart content type.
 		MultipartRequestDecoder *MultipartData
+		// DeferBody is true if the request decoder lets the endpoint
+		// decode the request body once the security requirements are
+		// met, see goa.WithDeferredBody.
+		DeferBody bool
 		// ServerWebSocket holds the data to render the server struct which
 		// implements the server stream interface.
 		ServerWebSocket *WebSocketData
@@ -216,6 +265,53 @@ type (
 		DecoderReturnValue string
 	}
 
+	// FieldSelectionData contains the data needed to render the code that
+	// applies the client field selection in the server handler.
+	FieldSelectionData struct {
+		// Param is the name of the query string parameter.
+		Param string
+		// Fields lists the paths to the fields that may be selected
+		// sorted alphabetically.
+		Fields []string
+	}
+
+	// BatchData contains the data needed to render the code that serves and
+	// makes batch requests.
+	BatchData struct {
+		// Suffix is the path appended to the endpoint route paths.
+		Suffix string
+		// Paths lists the paths of the batch routes, one per endpoint
+		// route.
+		Paths []string
+		// EndpointInit is the name of the client batch endpoint
+		// constructor.
+		EndpointInit string
+	}
+
+	// CallbackData contains the data needed to render the function that
+	// sends a callback.
+	CallbackData struct {
+		// Name is the name of the callback.
+		Name string
+		// Description is the callback description.
+		Description string
+		// ServiceName is the name of the service.
+		ServiceName string
+		// EndpointName is the name of the endpoint that defines the
+		// callback.
+		EndpointName string
+		// FuncName is the name of the function that sends the callback.
+		FuncName string
+		// PayloadRef is the reference to the callback payload type.
+		PayloadRef string
+		// Body is the callback request body type data.
+		Body *TypeData
+		// MaxAttempts is the maximum number of delivery attempts.
+		MaxAttempts int
+		// Backoff is the code of the initial retry backoff duration.
+		Backoff string
+	}
+
 	// ResultData contains the result information required to generate the
 	// transport decode (client) and encode (server) code.
 	ResultData struct {
@@ -239,6 +335,71 @@ type (
 		// the result variable only if there are multiple responses, or the
 		// response has a body, a header or a cookie.
 		MustInit bool
+		// Links contains the data needed to set the result links, nil if
+		// the result type has no link.
+		Links *LinksData
+		// JSONAPI contains the data needed to render the result following
+		// the JSON:API convention, nil if the result type does not define
+		// the "jsonapi:type" meta.
+		JSONAPI *JSONAPIData
+	}
+
+	// JSONAPIData contains the data needed to render a result as a JSON:API
+	// document.
+	JSONAPIData struct {
+		// Type is the JSON:API resource type.
+		Type string
+		// Relationships lists the result type relationships sorted by
+		// attribute name.
+		Relationships []*JSONAPIRelationshipData
+	}
+
+	// JSONAPIRelationshipData describes a JSON:API relationship.
+	JSONAPIRelationshipData struct {
+		// Name is the name of the attribute holding the related resources.
+		Name string
+		// Type is the JSON:API type of the related resources.
+		Type string
+	}
+
+	// LinksData contains the data needed to render the function that sets the
+	// links of a projected result type prior to encoding the response.
+	LinksData struct {
+		// SetterName is the name of the function that sets the links.
+		SetterName string
+		// ResultName is the name of the result type as defined in the
+		// design.
+		ResultName string
+		// TypeRef is the reference to the projected result type.
+		TypeRef string
+		// FieldName is the name of the projected result type field that
+		// holds the links.
+		FieldName string
+		// IsCollection is true if the method result is a collection of
+		// the result type, the links of each element are set in this
+		// case.
+		IsCollection bool
+		// Links lists the result type links.
+		Links []*LinkData
+	}
+
+	// LinkData describes a result type link.
+	LinkData struct {
+		// Name is the link relation name.
+		Name string
+		// Pattern is the path of the linked endpoint.
+		Pattern string
+		// Params lists the path parameters.
+		Params []*LinkParamData
+	}
+
+	// LinkParamData describes a link path parameter.
+	LinkParamData struct {
+		// Name is the path parameter name.
+		Name string
+		// FieldName is the name of the projected result type field that
+		// holds the parameter value.
+		FieldName string
 	}
 
 	// ErrorGroupData contains the error information required to generate
@@ -293,6 +454,9 @@ type (
 		// attribute. This field is set when the design uses Body("name") syntax
 		// to set the request body and the payload type is an object.
 		PayloadAttr string
+		// PatchBody is true if the request body is a JSON Patch or
+		// JSON Merge Patch document defined with the Patch DSL.
+		PatchBody bool
 		// MustHaveBody is true if the request body cannot be empty.
 		MustHaveBody bool
 		// MustValidate is true if the request body or at least one
@@ -301,6 +465,47 @@ type (
 		// Multipart if true indicates the request is a multipart
 		// request.
 		Multipart bool
+		// Migrations lists the request body fields that have a migration
+		// default.
+		Migrations []*MigrationData
+		// UnknownFields describes how the server handles the request
+		// body fields that are not defined in the design, nil if they
+		// are ignored.
+		UnknownFields *UnknownFieldsData
+		// PresentFields is the name of the body struct field that lists
+		// the request body fields set by the client, empty if the
+		// presence of the fields is not recorded. See dsl.FieldPresence.
+		PresentFields string
+	}
+
+	// UnknownFieldsData describes how a body decoder handles the fields
+	// that are not defined in the design. See dsl.DisallowUnknownFields
+	// and dsl.CollectUnknownFields.
+	UnknownFieldsData struct {
+		// Disallow is true if decoding fails when the body contains
+		// unknown fields.
+		Disallow bool
+		// FieldName is the name of the body struct field that collects
+		// the unknown fields if any.
+		FieldName string
+		// Known lists the names of the body fields defined in the
+		// design.
+		Known []string
+	}
+
+	// MigrationData describes a required request body field that may be
+	// omitted by clients during a grace period. See dsl.MigrationDefault.
+	MigrationData struct {
+		// Name is the name of the attribute.
+		Name string
+		// FieldName is the name of the server body struct field.
+		FieldName string
+		// TypeRef is the reference to the field type (not a pointer).
+		TypeRef string
+		// DefaultValue is the value assigned to the field when missing.
+		DefaultValue any
+		// Until is the last day of the grace period (YYYY-MM-DD).
+		Until string
 	}
 
 	// ResponseData describes a response.
@@ -357,6 +562,10 @@ type (
 		// ViewedResult indicates whether the response body type is a
 		// result type.
 		ViewedResult *service.ViewedResultTypeData
+		// UnknownFields describes how the client handles the response
+		// body fields that are not defined in the design, nil if they
+		// are ignored.
+		UnknownFields *UnknownFieldsData
 	}
 
 	// InitData contains the data required to render a constructor.
@@ -434,6 +643,14 @@ type (
 		DefaultValue any
 		// Validate contains the validation code for the attribute value if any.
 		Validate string
+		// Decode is the function that decodes the attribute value from
+		// its string representation if any, see the "struct:field:decode"
+		// metadata.
+		Decode string
+		// Encode is the function that encodes the attribute value into
+		// its string representation if any, see the "struct:field:encode"
+		// metadata.
+		Encode string
 		// Example is an example attribute value
 		Example any
 	}
@@ -491,6 +708,9 @@ type (
 		*Element
 		// CanonicalName is the canonical header key.
 		CanonicalName string
+		// Disposition is the disposition type of the Content-Disposition
+		// response header whose filename is the header value if any.
+		Disposition string
 	}
 
 	// CookieData describes a HTTP request or response cookie.
@@ -527,6 +747,9 @@ type (
 		ValidateDef string
 		// ValidateRef contains the call to the validation code.
 		ValidateRef string
+		// AppendDef contains the code of the AppendJSON method used to
+		// encode server response bodies without reflection if any.
+		AppendDef string
 		// Example is an example value for the type.
 		Example any
 		// View is the view used to render the (result) type if any.
@@ -578,6 +801,24 @@ func (svc *ServiceData) Endpoint(name string) *EndpointData {
 	return nil
 }
 
+// deferBody returns true if the request decoder of the endpoint may defer the
+// decoding of the request body until the endpoint meets the security
+// requirements. This requires credentials that are not read from the body, no
+// server interceptor (they may read the payload before the requirements are
+// checked) and a payload struct built from the body and the other request
+// elements.
+func deferBody(ad *EndpointData, a *expr.HTTPEndpointExpr) bool {
+	if len(ad.Requirements) == 0 || len(ad.BodySchemes) > 0 || len(ad.Method.ServerInterceptors) > 0 {
+		return false
+	}
+	if a.MethodExpr.IsStreaming() || a.MultipartRequest || a.SkipRequestBodyEncodeDecode || ad.Batch != nil {
+		return false
+	}
+	init := ad.Payload.Request.PayloadInit
+	return ad.Payload.Request.ServerBody != nil && init != nil && init.ServerCode != "" &&
+		init.ReturnIsStruct && init.ReturnTypeAttribute == ""
+}
+
 // analyze creates the data necessary to render the code of the given service.
 // It records the user types needed by the service definition in userTypes.
 func (ServicesData) analyze(hs *expr.HTTPServiceExpr) *ServiceData {
@@ -585,6 +826,11 @@ func (ServicesData) analyze(hs *expr.HTTPServiceExpr) *ServiceData {
 	scope := codegen.NewNameScope()
 	scope.Unique("c") // 'c' is reserved as the client's receiver name.
 	scope.Unique("v") // 'v' is reserved as the request builder payload argument name.
+	// The names below are reserved as the server request decoder local
+	// variable names.
+	for _, n := range []string{"params", "qp", "decodeBody", "load", "full"} {
+		scope.Unique(n)
+	}
 	rd := &ServiceData{
 		Service:          svc,
 		ServerStruct:     "Server",
@@ -663,6 +909,7 @@ func (ServicesData) analyze(hs *expr.HTTPServiceExpr) *ServiceData {
 							pointer = a.MethodExpr.Payload.IsPrimitivePointer(arg, true)
 						}
 						name := rd.Scope.Name(codegen.Goify(arg, false))
+						_, encode := elementFuncs(att)
 						var vcode string
 						if att.Validation != nil {
 							ctx := httpContext("", rd.Scope, true, false)
@@ -683,6 +930,7 @@ func (ServicesData) analyze(hs *expr.HTTPServiceExpr) *ServiceData {
 								Required:    true,
 								Example:     att.Example(expr.Root.API.ExampleGenerator),
 								Validate:    vcode,
+								Encode:      encode,
 							},
 						}
 					}
@@ -832,7 +1080,38 @@ func (ServicesData) analyze(hs *expr.HTTPServiceExpr) *ServiceData {
 			RequestEncoder:  requestEncoder,
 			ResponseDecoder: fmt.Sprintf("Decode%sResponse", ep.VarName),
 			Requirements:    reqs,
+			ChunkSize:       a.ChunkSize,
+			FeatureFlag:     featureFlag(a),
+			SLO:             endpointSLO(a),
+			Budget:          endpointBudget(a),
+			IdempotencyKey:  a.IdempotencyHeader,
+			CacheControl:    a.CacheControl,
+			Vary:            a.Vary,
+		}
+		if a.FieldSelection != "" {
+			ad.FieldSelection = &FieldSelectionData{Param: a.FieldSelection, Fields: a.SelectableFields()}
+		}
+		if a.BatchPath != "" {
+			ad.Batch = &BatchData{Suffix: a.BatchPath, EndpointInit: ep.VarName + "Batch"}
+			seen := make(map[string]struct{})
+			for _, r := range routes {
+				p := strings.TrimSuffix(r.Path, "/") + a.BatchPath
+				if _, ok := seen[p]; ok {
+					continue
+				}
+				seen[p] = struct{}{}
+				ad.Batch.Paths = append(ad.Batch.Paths, p)
+			}
+		}
+		for _, c := range a.Callbacks {
+			ad.Callbacks = append(ad.Callbacks, buildCallbackData(c, rd))
 		}
+		for _, f := range a.MethodExpr.Filters() {
+			if a.Params.ElemName(f) == expr.FilterParamName(f) {
+				ad.Filters = append(ad.Filters, f)
+			}
+		}
+		ad.CloudEvents = a.CloudEvents
 		if a.MethodExpr.IsStreaming() {
 			initWebSocketData(ad, a, rd)
 		}
@@ -866,6 +1145,7 @@ func (ServicesData) analyze(hs *expr.HTTPServiceExpr) *ServiceData {
 				StatusCode: statusCodeToHTTPConst(a.Redirect.StatusCode),
 			}
 		}
+		ad.DeferBody = deferBody(ad, a)
 
 		rd.Endpoints = append(rd.Endpoints, ad)
 	}
@@ -1032,6 +1312,7 @@ func buildPayloadData(e *expr.HTTPEndpointExpr, sd *ServiceData) *PayloadData {
 
 			mustValidate bool
 			mustHaveBody = true
+			patchBody    bool
 		)
 		{
 			if e.MapQueryParams != nil {
@@ -1075,14 +1356,14 @@ func buildPayloadData(e *expr.HTTPEndpointExpr, sd *ServiceData) *PayloadData {
 				sd.ClientTypeNames[serverBodyData.Name] = false
 			}
 			for _, p := range cookiesData {
-				if p.Required || p.Validate != "" || needConversion(p.Type) {
+				if p.Required || p.Validate != "" || needConversion(p.Type) || p.Decode != "" {
 					mustValidate = true
 					break
 				}
 			}
 			if !mustValidate {
 				for _, p := range paramsData {
-					if p.Validate != "" || needConversion(p.Type) {
+					if p.Validate != "" || needConversion(p.Type) || p.Decode != "" {
 						mustValidate = true
 						break
 					}
@@ -1090,7 +1371,7 @@ func buildPayloadData(e *expr.HTTPEndpointExpr, sd *ServiceData) *PayloadData {
 			}
 			if !mustValidate {
 				for _, q := range queryData {
-					if q.Validate != "" || q.Required || needConversion(q.Type) {
+					if q.Validate != "" || q.Required || needConversion(q.Type) || q.Decode != "" {
 						mustValidate = true
 						break
 					}
@@ -1098,7 +1379,7 @@ func buildPayloadData(e *expr.HTTPEndpointExpr, sd *ServiceData) *PayloadData {
 			}
 			if !mustValidate {
 				for _, h := range headersData {
-					if h.Validate != "" || h.Required || needConversion(h.Type) {
+					if h.Validate != "" || h.Required || needConversion(h.Type) || h.Decode != "" {
 						mustValidate = true
 						break
 					}
@@ -1113,6 +1394,9 @@ func buildPayloadData(e *expr.HTTPEndpointExpr, sd *ServiceData) *PayloadData {
 					if !payload.IsRequired(o[0]) {
 						mustHaveBody = false
 					}
+					if att := payload.Find(origin); att != nil && expr.PatchTarget(att) != nil {
+						patchBody = true
+					}
 				}
 			}
 		}
@@ -1124,10 +1408,15 @@ func buildPayloadData(e *expr.HTTPEndpointExpr, sd *ServiceData) *PayloadData {
 			ServerBody:   serverBodyData,
 			ClientBody:   clientBodyData,
 			PayloadAttr:  codegen.Goify(origin, true),
+			PatchBody:    patchBody,
 			PayloadType:  e.MethodExpr.Payload.Type,
 			MustHaveBody: mustHaveBody,
 			MustValidate: mustValidate,
 			Multipart:    e.MultipartRequest,
+			Migrations:   extractMigrations(e.Body, serverBodyData, sd.Scope),
+
+			UnknownFields: extractUnknownFields(e.Body, e.DisallowUnknownFields, e.UnknownFields),
+			PresentFields: extractPresentFields(e.Body, e.PresentFields),
 		}
 	}
 
@@ -1210,6 +1499,7 @@ func buildPayloadData(e *expr.HTTPEndpointExpr, sd *ServiceData) *PayloadData {
 					Pointer:      p.Pointer,
 					Required:     p.Required,
 					Validate:     p.Validate,
+					Decode:       p.Decode,
 					Example:      p.Example,
 				},
 			})
@@ -1230,6 +1520,7 @@ func buildPayloadData(e *expr.HTTPEndpointExpr, sd *ServiceData) *PayloadData {
 					Required:     p.Required,
 					DefaultValue: p.DefaultValue,
 					Validate:     p.Validate,
+					Decode:       p.Decode,
 					Example:      p.Example,
 				},
 			})
@@ -1250,6 +1541,7 @@ func buildPayloadData(e *expr.HTTPEndpointExpr, sd *ServiceData) *PayloadData {
 					Required:     h.Required,
 					DefaultValue: h.DefaultValue,
 					Validate:     h.Validate,
+					Decode:       h.Decode,
 					Example:      h.Example,
 				},
 			})
@@ -1270,6 +1562,7 @@ func buildPayloadData(e *expr.HTTPEndpointExpr, sd *ServiceData) *PayloadData {
 					Required:     c.Required,
 					DefaultValue: c.DefaultValue,
 					Validate:     c.Validate,
+					Decode:       c.Decode,
 					Example:      c.Example,
 				},
 			})
@@ -1464,11 +1757,14 @@ func buildResultData(e *expr.HTTPEndpointExpr, sd *ServiceData) *ResultData {
 	var (
 		mustInit  bool
 		responses []*ResponseData
+		links     *LinksData
 	)
 	{
 		viewed := false
 		if ep.ViewedResult != nil {
-			result = expr.AsObject(ep.ViewedResult.Type).Attribute("projected")
+			projected := expr.AsObject(ep.ViewedResult.Type).Attribute("projected")
+			links = buildLinksData(result, projected, sd)
+			result = projected
 			viewed = true
 		}
 		responses = buildResponses(e, result, viewed, sd)
@@ -1486,9 +1782,125 @@ func buildResultData(e *expr.HTTPEndpointExpr, sd *ServiceData) *ResultData {
 		Responses: responses,
 		View:      view,
 		MustInit:  mustInit,
+		Links:     links,
+		JSONAPI:   buildJSONAPIData(e.MethodExpr.Result),
+	}
+}
+
+// buildJSONAPIData builds the data needed to render the given method result
+// following the JSON:API convention, it returns nil if the result type (or the
+// element result type if the result is a collection) does not define the
+// "jsonapi:type" meta.
+func buildJSONAPIData(result *expr.AttributeExpr) *JSONAPIData {
+	rt := expr.JSONAPIResult(result)
+	if rt == nil {
+		return nil
+	}
+	data := &JSONAPIData{Type: rt.JSONAPIType()}
+	rels := rt.JSONAPIRelationships()
+	names := make([]string, 0, len(rels))
+	for n := range rels {
+		names = append(names, n)
+	}
+	sort.Strings(names)
+	for _, n := range names {
+		data.Relationships = append(data.Relationships, &JSONAPIRelationshipData{Name: n, Type: rels[n]})
+	}
+	return data
+}
+
+// buildCallbackData builds the data needed to render the function that sends
+// the given callback.
+func buildCallbackData(c *expr.HTTPCallbackExpr, sd *ServiceData) *CallbackData {
+	var loc *codegen.Location
+	if ut, ok := c.Payload.Type.(expr.UserType); ok {
+		loc = codegen.UserTypeLocation(ut)
+	}
+	e := c.Endpoint
+	name := codegen.Goify(e.Name(), true) + codegen.Goify(c.Name, true)
+	body := buildResponseBodyType(c.Body, c.Payload, loc, e, true, nil, sd)
+	if body != nil {
+		if _, ok := c.Body.Type.(expr.UserType); ok {
+			body.Description = fmt.Sprintf("%s is the type of the %q service %q endpoint %q callback HTTP request body.",
+				body.VarName, sd.Service.Name, e.Name(), c.Name)
+		}
+		if body.Init != nil {
+			body.Init.Description = fmt.Sprintf("%s builds the HTTP request body of the %q callback of the %q endpoint of the %q service.",
+				body.Init.Name, c.Name, e.Name(), sd.Service.Name)
+		}
+	}
+	return &CallbackData{
+		Name:         c.Name,
+		Description:  c.Description,
+		ServiceName:  sd.Service.Name,
+		EndpointName: e.Name(),
+		FuncName:     "Send" + name + "Callback",
+		PayloadRef:   sd.Service.Scope.GoFullTypeRef(c.Payload, pkgWithDefault(loc, sd.Service.PkgName)),
+		Body:         body,
+		MaxAttempts:  c.MaxAttempts,
+		Backoff:      durationCode(c.Backoff),
 	}
 }
 
+// durationCode returns the Go code of the given duration, e.g.
+// "500 * time.Millisecond".
+func durationCode(d time.Duration) string {
+	switch {
+	case d == 0:
+		return "0"
+	case d%time.Second == 0:
+		return fmt.Sprintf("%d * time.Second", d/time.Second)
+	case d%time.Millisecond == 0:
+		return fmt.Sprintf("%d * time.Millisecond", d/time.Millisecond)
+	default:
+		return fmt.Sprintf("%d", d)
+	}
+}
+
+// buildLinksData builds the data needed to set the links of the given method
+// result, it returns nil if the result type has no link. projected is the
+// corresponding projected result attribute.
+func buildLinksData(result, projected *expr.AttributeExpr, sd *ServiceData) *LinksData {
+	rt, ok := result.Type.(*expr.ResultTypeExpr)
+	if !ok {
+		return nil
+	}
+	collection := false
+	if arr := expr.AsArray(rt); arr != nil {
+		elem, ok := arr.ElemType.Type.(*expr.ResultTypeExpr)
+		if !ok {
+			return nil
+		}
+		rt = elem
+		collection = true
+		projected = expr.AsArray(projected.Type).ElemType
+	}
+	nat := rt.LinksAttribute()
+	if nat == nil {
+		return nil
+	}
+	obj := expr.AsObject(rt.Type)
+	data := &LinksData{
+		SetterName:   "set" + codegen.Goify(rt.Name(), true) + "Links",
+		ResultName:   rt.Name(),
+		TypeRef:      sd.Service.ViewScope.GoFullTypeRef(projected, sd.Service.ViewsPkg),
+		FieldName:    codegen.GoifyAtt(nat.Attribute, nat.Name, true),
+		IsCollection: collection,
+	}
+	for _, l := range rt.Links {
+		ld := &LinkData{Name: l.Name, Pattern: l.Pattern()}
+		for _, wc := range expr.ExtractHTTPWildcards(ld.Pattern) {
+			name := l.Attribute(wc)
+			ld.Params = append(ld.Params, &LinkParamData{
+				Name:      wc,
+				FieldName: codegen.GoifyAtt(obj.Attribute(name), name, true),
+			})
+		}
+		data.Links = append(data.Links, ld)
+	}
+	return data
+}
+
 // buildResponses builds the response data for all the responses in the endpoint
 // expression. The response headers, cookies and body for each response are
 // inferred from the method's result expression if not specified explicitly.
@@ -1584,13 +1996,13 @@ func buildResponses(e *expr.HTTPEndpointExpr, result *expr.AttributeExpr, viewed
 					sd.ClientTypeNames[clientBodyData.Name] = false
 				}
 				for _, h := range headersData {
-					if h.Validate != "" || h.Required || needConversion(h.Type) {
+					if h.Validate != "" || h.Required || needConversion(h.Type) || h.Decode != "" {
 						mustValidate = true
 						break
 					}
 				}
 				for _, c := range cookiesData {
-					if c.Validate != "" || c.Required || needConversion(c.Type) {
+					if c.Validate != "" || c.Required || needConversion(c.Type) || c.Decode != "" {
 						mustValidate = true
 						break
 					}
@@ -1691,6 +2103,7 @@ func buildResponses(e *expr.HTTPEndpointExpr, result *expr.AttributeExpr, viewed
 									TypeRef:      h.TypeRef,
 									Type:         h.Type,
 									Validate:     h.Validate,
+									Decode:       h.Decode,
 									Example:      h.Example,
 								},
 							})
@@ -1709,6 +2122,7 @@ func buildResponses(e *expr.HTTPEndpointExpr, result *expr.AttributeExpr, viewed
 									TypeRef:      c.TypeRef,
 									Type:         c.Type,
 									Validate:     c.Validate,
+									Decode:       c.Decode,
 									Example:      c.Example,
 								},
 							})
@@ -1755,6 +2169,8 @@ func buildResponses(e *expr.HTTPEndpointExpr, result *expr.AttributeExpr, viewed
 					MustValidate: mustValidate,
 					ResultAttr:   codegen.Goify(origin, true),
 					ViewedResult: md.ViewedResult,
+
+					UnknownFields: extractUnknownFields(resp.Body, resp.DisallowUnknownFields, resp.UnknownFields),
 				})
 			}
 		}
@@ -1822,6 +2238,7 @@ func buildErrorsData(e *expr.HTTPEndpointExpr, sd *ServiceData) []*ErrorGroupDat
 							TypeRef:      h.TypeRef,
 							Type:         h.Type,
 							Validate:     h.Validate,
+							Decode:       h.Decode,
 							Example:      h.Example,
 						},
 					})
@@ -1838,6 +2255,7 @@ func buildErrorsData(e *expr.HTTPEndpointExpr, sd *ServiceData) []*ErrorGroupDat
 							TypeRef:      c.TypeRef,
 							Type:         c.Type,
 							Validate:     c.Validate,
+							Decode:       c.Decode,
 							Example:      c.Example,
 						},
 					})
@@ -1919,13 +2337,13 @@ func buildErrorsData(e *expr.HTTPEndpointExpr, sd *ServiceData) []*ErrorGroupDat
 			var mustValidate bool
 			{
 				for _, h := range headers {
-					if h.Validate != "" || h.Required || needConversion(h.Type) {
+					if h.Validate != "" || h.Required || needConversion(h.Type) || h.Decode != "" {
 						mustValidate = true
 						break
 					}
 				}
 				for _, c := range cookies {
-					if c.Validate != "" || c.Required || needConversion(c.Type) {
+					if c.Validate != "" || c.Required || needConversion(c.Type) || c.Decode != "" {
 						mustValidate = true
 						break
 					}
@@ -2141,6 +2559,7 @@ func buildResponseBodyType(body, att *expr.AttributeExpr, loc *codegen.Location,
 		ref         string
 		validateDef string
 		validateRef string
+		appendDef   string
 		viewName    string
 		mustInit    bool
 
@@ -2179,6 +2598,9 @@ func buildResponseBodyType(body, att *expr.AttributeExpr, loc *codegen.Location,
 			def = goTypeDef(sd.Scope, ut.Attribute(), !svr, svr)
 			desc = fmt.Sprintf("%s is the type of the %q service %q endpoint HTTP response body.",
 				varname, svc.Name, e.Name())
+			if svr && fastJSON(e.Service.ServiceExpr) {
+				appendDef = appendJSONCode(ut)
+			}
 			if !svr && view == nil {
 				// generate validation code for unmarshaled type (client-side).
 				validateDef = codegen.ValidationCode(body, ut, httpctx, true, expr.IsAlias(body.Type), "body")
@@ -2307,6 +2729,7 @@ func buildResponseBodyType(body, att *expr.AttributeExpr, loc *codegen.Location,
 		Init:        init,
 		ValidateDef: validateDef,
 		ValidateRef: validateRef,
+		AppendDef:   appendDef,
 		Example:     body.Example(expr.Root.API.ExampleGenerator),
 		View:        viewName,
 	}
@@ -2323,10 +2746,11 @@ func extractPathParams(a *expr.MappedAttributeExpr, service *expr.AttributeExpr,
 
 		c = makeHTTPType(c)
 		var (
-			varn = scope.Name(codegen.Goify(name, false))
-			arr  = expr.AsArray(c.Type)
-			ctx  = serviceContext("", scope)
-			ft   = service.Type
+			varn           = scope.Name(codegen.Goify(name, false))
+			arr            = expr.AsArray(c.Type)
+			ctx            = serviceContext("", scope)
+			ft             = service.Type
+			decode, encode = elementFuncs(c)
 
 			fptr bool
 		)
@@ -2358,6 +2782,8 @@ func extractPathParams(a *expr.MappedAttributeExpr, service *expr.AttributeExpr,
 					TypeRef:      scope.GoTypeRef(c),
 					Pointer:      false,
 					Validate:     codegen.AttributeValidationCode(c, nil, ctx, true, expr.IsAlias(c.Type), varn, name),
+					Decode:       decode,
+					Encode:       encode,
 					DefaultValue: c.DefaultValue,
 					Example:      c.Example(expr.Root.API.ExampleGenerator),
 				},
@@ -2387,8 +2813,9 @@ func extractQueryParams(a *expr.MappedAttributeExpr, service *expr.AttributeExpr
 			ctx     = serviceContext("", scope)
 			ft      = service.Type
 
-			pointer bool
-			fptr    bool
+			decode, encode = elementFuncs(c)
+			pointer        bool
+			fptr           bool
 		)
 		pointer = a.IsPrimitivePointer(name, true)
 		if pointer {
@@ -2425,6 +2852,8 @@ func extractQueryParams(a *expr.MappedAttributeExpr, service *expr.AttributeExpr
 					TypeRef:      typeRef,
 					Pointer:      pointer,
 					Validate:     codegen.AttributeValidationCode(c, nil, ctx, required, expr.IsAlias(c.Type), varn, name),
+					Decode:       decode,
+					Encode:       encode,
 					DefaultValue: c.DefaultValue,
 					Example:      c.Example(expr.Root.API.ExampleGenerator),
 				},
@@ -2459,9 +2888,10 @@ func extractHeaders(a *expr.MappedAttributeExpr, svcAtt *expr.AttributeExpr, svc
 			typeRef = scope.GoTypeRef(hattr)
 			ft      = attr.Type
 
-			fieldName string
-			pointer   bool
-			fptr      bool
+			decode, encode = elementFuncs(hattr)
+			fieldName      string
+			pointer        bool
+			fptr           bool
 		)
 		{
 			pointer = a.IsPrimitivePointer(name, true)
@@ -2473,8 +2903,13 @@ func extractHeaders(a *expr.MappedAttributeExpr, svcAtt *expr.AttributeExpr, svc
 				typeRef = "*" + typeRef
 			}
 		}
+		var disposition string
+		if http.CanonicalHeaderKey(elem) == "Content-Disposition" {
+			disposition, _ = a.Meta.Last("http:content-disposition")
+		}
 		headers = append(headers, &HeaderData{
 			CanonicalName: http.CanonicalHeaderKey(elem),
+			Disposition:   disposition,
 			Element: &Element{
 				HTTPName:      elem,
 				Slice:         arr != nil,
@@ -2493,6 +2928,8 @@ func extractHeaders(a *expr.MappedAttributeExpr, svcAtt *expr.AttributeExpr, svc
 					Pointer:      pointer,
 					Type:         hattr.Type,
 					Validate:     codegen.AttributeValidationCode(hattr, nil, svcCtx, required, expr.IsAlias(hattr.Type), varn, name),
+					Decode:       decode,
+					Encode:       encode,
 					DefaultValue: hattr.DefaultValue,
 					Example:      hattr.Example(expr.Root.API.ExampleGenerator),
 				},
@@ -2518,9 +2955,10 @@ func extractCookies(a *expr.MappedAttributeExpr, svcAtt *expr.AttributeExpr, svc
 			typeRef = scope.GoTypeRef(hattr)
 			ft      = svcAtt.Type
 
-			fieldName string
-			pointer   bool
-			fptr      bool
+			decode, encode = elementFuncs(hattr)
+			fieldName      string
+			pointer        bool
+			fptr           bool
 		)
 		{
 			pointer = a.IsPrimitivePointer(name, true)
@@ -2550,6 +2988,8 @@ func extractCookies(a *expr.MappedAttributeExpr, svcAtt *expr.AttributeExpr, svc
 					Pointer:      pointer,
 					Type:         hattr.Type,
 					Validate:     codegen.AttributeValidationCode(hattr, nil, svcCtx, required, expr.IsAlias(hattr.Type), varn, name),
+					Decode:       decode,
+					Encode:       encode,
 					DefaultValue: hattr.DefaultValue,
 					Example:      hattr.Example(expr.Root.API.ExampleGenerator),
 				},
@@ -2575,6 +3015,90 @@ func extractCookies(a *expr.MappedAttributeExpr, svcAtt *expr.AttributeExpr, svc
 	return cookies
 }
 
+// extractMigrations returns the required attributes of the request body that
+// have a migration default. It returns nil if the server does not decode a
+// request body or if the body is not an object.
+func extractMigrations(body *expr.AttributeExpr, serverBody *TypeData, scope *codegen.NameScope) []*MigrationData {
+	if serverBody == nil {
+		return nil
+	}
+	obj := expr.AsObject(body.Type)
+	if obj == nil {
+		return nil
+	}
+	var migrations []*MigrationData
+	for _, nat := range *obj {
+		until, ok := nat.Attribute.Meta.Last("migration:until")
+		if !ok || !body.IsRequired(nat.Name) || nat.Attribute.DefaultValue == nil {
+			continue
+		}
+		migrations = append(migrations, &MigrationData{
+			Name:         nat.Name,
+			FieldName:    codegen.GoifyAtt(nat.Attribute, nat.Name, true),
+			TypeRef:      scope.GoTypeRef(nat.Attribute),
+			DefaultValue: nat.Attribute.DefaultValue,
+			Until:        until,
+		})
+	}
+	return migrations
+}
+
+// extractUnknownFields returns the data describing how the decoder handles the
+// fields of body that are not defined in the design given the
+// DisallowUnknownFields and CollectUnknownFields DSL settings. It returns nil
+// if the unknown fields are ignored or if body is not an object.
+func extractUnknownFields(body *expr.AttributeExpr, disallow bool, collect string) *UnknownFieldsData {
+	if !disallow && collect == "" {
+		return nil
+	}
+	obj := expr.AsObject(body.Type)
+	if obj == nil {
+		return nil
+	}
+	data := &UnknownFieldsData{Disallow: disallow}
+	if collect == "" {
+		return data
+	}
+	for _, nat := range *obj {
+		if nat.Name == collect {
+			data.FieldName = codegen.GoifyAtt(nat.Attribute, nat.Name, true)
+			continue
+		}
+		name := nat.Name
+		if tag, ok := nat.Attribute.Meta.Last("struct:tag:json"); ok {
+			if n, _, _ := strings.Cut(tag, ","); n == "-" {
+				continue
+			} else if n != "" {
+				name = n
+			}
+		}
+		data.Known = append(data.Known, name)
+	}
+	if data.FieldName == "" {
+		return nil
+	}
+	return data
+}
+
+// extractPresentFields returns the name of the body struct field that lists the
+// request body fields set by the client given the FieldPresence DSL setting. It
+// returns an empty string if the presence of the fields is not recorded or if
+// body is not an object.
+func extractPresentFields(body *expr.AttributeExpr, name string) string {
+	if name == "" {
+		return ""
+	}
+	obj := expr.AsObject(body.Type)
+	if obj == nil {
+		return ""
+	}
+	nat := obj.Attribute(name)
+	if nat == nil {
+		return ""
+	}
+	return codegen.GoifyAtt(nat, name, true)
+}
+
 // collectUserTypes traverses the given data type recursively and calls back the
 // given function for each attribute using a user type.
 func collectUserTypes(dt expr.DataType, cb func(expr.UserType), seen ...map[string]struct{}) {
@@ -2625,6 +3149,7 @@ func attributeTypeData(ut expr.UserType, req, ptr, server bool, rd *ServiceData)
 		desc        string
 		validate    string
 		validateRef string
+		appendDef   string
 
 		att  = &expr.AttributeExpr{Type: ut}
 		hctx = httpContext("", rd.Scope, req, server)
@@ -2644,6 +3169,9 @@ func attributeTypeData(ut expr.UserType, req, ptr, server bool, rd *ServiceData)
 		if validate != "" {
 			validateRef = fmt.Sprintf("err = Validate%s(v)", name)
 		}
+		if server && !req && !ptr && fastJSON(expr.Root.Service(rd.Service.Name)) {
+			appendDef = appendJSONCode(ut)
+		}
 	}
 	return &TypeData{
 		Name:        ut.Name(),
@@ -2653,6 +3181,7 @@ func attributeTypeData(ut expr.UserType, req, ptr, server bool, rd *ServiceData)
 		Ref:         rd.Scope.GoTypeRef(att),
 		ValidateDef: validate,
 		ValidateRef: validateRef,
+		AppendDef:   appendDef,
 		Example:     att.Example(expr.Root.API.ExampleGenerator),
 	}
 }
@@ -2744,6 +3273,19 @@ func needConversion(dt expr.DataType) bool {
 	}
 }
 
+// elementFuncs returns the functions that decode and encode the value of the
+// HTTP element defined by att from and to its string representation if any,
+// see the "struct:field:decode" and "struct:field:encode" metadata. Only
+// primitive values may be decoded and encoded with custom functions.
+func elementFuncs(att *expr.AttributeExpr) (decode, encode string) {
+	if !expr.IsPrimitive(att.Type) {
+		return
+	}
+	decode, _ = codegen.GetMetaFunc(att, "struct:field:decode")
+	encode, _ = codegen.GetMetaFunc(att, "struct:field:encode")
+	return
+}
+
 // AddMarshalTags adds JSON, XML and Form tags to all inline object attributes recursively.
 func AddMarshalTags(att *expr.AttributeExpr, seen map[string]struct{}) {
 	if !expr.IsObject(att.Type) {
@@ -2834,6 +3376,7 @@ const (
 	{{- end }}
 	return fmt.Sprintf("{{ .PathFormat }}", {{ range $i, $arg := .Args }}
 	{{- if eq (index $.PathParams $i).Attribute.Type.Name "array" }}strings.Join({{ .VarName }}Slice, ",")
+	{{- else if .Encode }}{{ .Encode }}({{ .VarName }})
 	{{- else }}{{ .VarName }}
 	{{- end }}, {{ end }})
 {{- else }}
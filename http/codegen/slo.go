@@ -0,0 +1,90 @@
+package codegen
+
+import (
+	"fmt"
+	"math/big"
+	"strings"
+	"time"
+
+	"goa.design/goa/v3/expr"
+)
+
+// SLOData contains the data needed to render the code that records the
+// requests of an endpoint with a SLO tracker.
+type SLOData struct {
+	// Target is the Go literal of the fraction of requests that must
+	// succeed, e.g. "0.999".
+	Target string
+	// Latency is the Go expression of the maximum duration of a successful
+	// request, e.g. "300 * time.Millisecond", empty if there is none.
+	Latency string
+}
+
+// endpointSLO returns the service level objective of the given endpoint as
+// defined by the "slo:target" and "slo:latency" meta of the method or of its
+// service, nil if there is none.
+func endpointSLO(e *expr.HTTPEndpointExpr) *SLOData {
+	meta := e.MethodExpr.Meta
+	if _, ok := meta["slo:target"]; !ok {
+		meta = e.MethodExpr.Service.Meta
+	}
+	target, ok := meta.Last("slo:target")
+	if !ok {
+		return nil
+	}
+	r, ok := new(big.Rat).SetString(target)
+	if !ok {
+		return nil
+	}
+	r.Quo(r, big.NewRat(100, 1))
+	data := &SLOData{Target: strings.TrimRight(strings.TrimRight(r.FloatString(10), "0"), ".")}
+	if l, ok := meta.Last("slo:latency"); ok {
+		if d, err := time.ParseDuration(l); err == nil {
+			data.Latency = durationLiteral(d)
+		}
+	}
+	return data
+}
+
+// durationLiteral returns the Go expression of d using the largest unit that
+// divides it.
+func durationLiteral(d time.Duration) string {
+	units := []struct {
+		d    time.Duration
+		name string
+	}{
+		{time.Hour, "Hour"},
+		{time.Minute, "Minute"},
+		{time.Second, "Second"},
+		{time.Millisecond, "Millisecond"},
+		{time.Microsecond, "Microsecond"},
+	}
+	for _, u := range units {
+		if d%u.d == 0 {
+			return fmt.Sprintf("%d * time.%s", d/u.d, u.name)
+		}
+	}
+	return fmt.Sprintf("%d * time.Nanosecond", d)
+}
+
+// hasSLOs returns true if at least one of the given endpoints defines a
+// service level objective.
+func hasSLOs(data *ServiceData) bool {
+	for _, e := range data.Endpoints {
+		if e.SLO != nil {
+			return true
+		}
+	}
+	return false
+}
+
+// input: ServiceData
+const serverSLOsT = `{{ printf "UseSLOs records the requests handled by the %s methods configured with the \"slo:target\" meta with the given tracker so that it tracks the compliance of their service level objectives." .Service.Name | comment }}
+func (s *{{ .ServerStruct }}) UseSLOs(t *middleware.SLOTracker) {
+{{- range $e := .Endpoints }}
+	{{- with .SLO }}
+	s.{{ $e.Method.VarName }} = httpmdlwr.SLO(t, &middleware.SLO{Service: {{ printf "%q" $.Service.Name }}, Method: {{ printf "%q" $e.Method.Name }}{{ if .Latency }}, Latency: {{ .Latency }}{{ end }}, Target: {{ .Target }}})(s.{{ $e.Method.VarName }})
+	{{- end }}
+{{- end }}
+}
+`
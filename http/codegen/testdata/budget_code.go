@@ -0,0 +1,10 @@
+package testdata
+
+var BudgetMethodCode = `// UseBudgets enforces the response size and latency budgets of the
+// ServiceBudget methods configured with the "budget:response-size" or
+// "budget:latency" meta using the given options.
+func (s *Server) UseBudgets(opts ...httpmdlwr.BudgetOption) {
+	s.List = httpmdlwr.Budget(&middleware.Budget{Service: "ServiceBudget", Method: "List", ResponseSize: 1048576, Latency: 2 * time.Second}, opts...)(s.List)
+	s.Show = httpmdlwr.Budget(&middleware.Budget{Service: "ServiceBudget", Method: "Show", Latency: 2 * time.Second}, opts...)(s.Show)
+}
+`
@@ -0,0 +1,22 @@
+package testdata
+
+import (
+	. "goa.design/goa/v3/dsl"
+)
+
+var BudgetMethodDSL = func() {
+	Service("ServiceBudget", func() {
+		Meta("budget:latency", "2s")
+		Method("List", func() {
+			Meta("budget:response-size", "1048576")
+			HTTP(func() {
+				GET("/list")
+			})
+		})
+		Method("Show", func() {
+			HTTP(func() {
+				GET("/show")
+			})
+		})
+	})
+}
@@ -0,0 +1,25 @@
+package testdata
+
+var CacheControlServerCode = `// UseCache serves the responses of the ServiceCacheControl methods that use
+// the CacheControl DSL from the given cache as directed by their Cache-Control
+// header.
+func (s *Server) UseCache(c *httpmdlwr.ResponseCache) {
+	s.List = c.Handler("Accept-Language", "Accept")(s.List)
+	s.Show = c.Handler()(s.Show)
+}
+`
+
+var CacheControlEncodeCode = `// EncodeListResponse returns an encoder for responses returned by the
+// ServiceCacheControl List endpoint.
+func EncodeListResponse(encoder func(context.Context, http.ResponseWriter) goahttp.Encoder) func(context.Context, http.ResponseWriter, any) error {
+	return func(ctx context.Context, w http.ResponseWriter, v any) error {
+		w.Header().Set("Cache-Control", "public, max-age=60, stale-while-revalidate=30")
+		w.Header().Set("Vary", "Accept-Language, Accept")
+		res, _ := v.([]string)
+		enc := encoder(ctx, w)
+		body := res
+		w.WriteHeader(http.StatusOK)
+		return enc.Encode(body)
+	}
+}
+`
@@ -0,0 +1,29 @@
+package testdata
+
+import (
+	. "goa.design/goa/v3/dsl"
+)
+
+var CacheControlDSL = func() {
+	Service("ServiceCacheControl", func() {
+		Method("List", func() {
+			Result(ArrayOf(String))
+			HTTP(func() {
+				GET("/")
+				CacheControl("public, max-age=60, stale-while-revalidate=30", "Accept-Language", "Accept")
+			})
+		})
+		Method("Show", func() {
+			Result(String)
+			HTTP(func() {
+				GET("/show")
+				CacheControl("max-age=10")
+			})
+		})
+		Method("Update", func() {
+			HTTP(func() {
+				PUT("/")
+			})
+		})
+	})
+}
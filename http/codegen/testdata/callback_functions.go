@@ -0,0 +1,18 @@
+package testdata
+
+var ServerCallbackSendersCode = `// SendMethodCallbackOnEventCallback sends the "onEvent" callback of the
+// "ServiceCallback" service "MethodCallback" endpoint to url. It makes at most
+// 3 delivery attempts.
+// Sent when an event occurs.
+func SendMethodCallbackOnEventCallback(ctx context.Context, s *goahttp.WebhookSender, url string, p *servicecallback.Event) error {
+	body := NewMethodCallbackOnEventCallbackBody(p)
+	return s.Send(ctx, url, body, 3, 500*time.Millisecond)
+}
+
+// SendMethodCallbackOnCountCallback sends the "onCount" callback of the
+// "ServiceCallback" service "MethodCallback" endpoint to url. It makes a
+// single delivery attempt.
+func SendMethodCallbackOnCountCallback(ctx context.Context, s *goahttp.WebhookSender, url string, p int) error {
+	return s.Send(ctx, url, p, 1, 0)
+}
+`
@@ -51,3 +51,43 @@ func NewClient(
 }
 `
 )
+
+var BatchClientEndpointInitCode = `// MethodBatchBatch returns an endpoint that makes HTTP batch requests to the
+// ServiceBatch service MethodBatch server. The endpoint payload is a slice of
+// the method payloads and its result is a slice of *goahttp.BatchResult
+// holding the result or error of each payload in order. The path and query
+// string parameters, headers and cookies of the batch request are taken from
+// the first payload.
+func (c *Client) MethodBatchBatch() goa.Endpoint {
+	var (
+		encodeRequest  = EncodeMethodBatchRequest(c.encoder)
+		decodeResponse = DecodeMethodBatchResponse(c.decoder, c.RestoreResponseBody)
+	)
+	return func(ctx context.Context, v any) (any, error) {
+		payloads, ok := v.([]*servicebatch.MethodBatchPayload)
+		if !ok {
+			return nil, goahttp.ErrInvalidType("ServiceBatch", "MethodBatch", "[]*servicebatch.MethodBatchPayload", v)
+		}
+		reqs := make([]*http.Request, len(payloads))
+		for i, v := range payloads {
+			req, err := c.BuildMethodBatchRequest(ctx, v)
+			if err != nil {
+				return nil, err
+			}
+			if err := encodeRequest(req, v); err != nil {
+				return nil, err
+			}
+			reqs[i] = req
+		}
+		req, err := goahttp.NewBatchRequest(reqs, "/batch")
+		if err != nil {
+			return nil, goahttp.ErrEncodingError("ServiceBatch", "MethodBatch", err)
+		}
+		resp, err := c.MethodBatchDoer.Do(req)
+		if err != nil {
+			return nil, goahttp.ErrRequestError("ServiceBatch", "MethodBatch", err)
+		}
+		return goahttp.DecodeBatchResponse(resp, decodeResponse)
+	}
+}
+`
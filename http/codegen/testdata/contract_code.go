@@ -0,0 +1,67 @@
+package testdata
+
+var ContractTestCode = `// TestHTTPContract exercises the HTTP endpoints of the example services with
+// requests built from the examples defined in the design and with invalid
+// requests. It asserts the response status codes and validates the responses
+// against the OpenAPI document ../../gen/http/openapi3.json. Streaming
+// endpoints, endpoints that skip the body encoding, multipart endpoints and
+// endpoints that require authentication are not exercised.
+func TestHTTPContract(t *testing.T) {
+	cases := []struct {
+		Name    string
+		Method  string
+		Path    string
+		Header  [][2]string
+		Body    string
+		Invalid bool
+		// output
+		Statuses []int
+	}{
+		{
+			"svc update",
+			"PUT",
+			"/items/42?tags=a&tags=b",
+			[][2]string{{"Content-Type", "application/json"}, {"X-Token", "secret"}},
+			"{\"name\":\"goa\"}",
+			false,
+			[]int{202},
+		},
+		{
+			"svc update malformed body",
+			"PUT",
+			"/items/42?tags=a&tags=b",
+			[][2]string{{"Content-Type", "application/json"}, {"X-Token", "secret"}},
+			"{",
+			true,
+			[]int{400},
+		},
+		{
+			"svc list",
+			"GET",
+			"/items",
+			nil,
+			"",
+			false,
+			[]int{204},
+		},
+	}
+	for _, c := range cases {
+		t.Run(c.Name, func(t *testing.T) {
+			req := httptest.NewRequest(c.Method, c.Path, strings.NewReader(c.Body))
+			for _, h := range c.Header {
+				req.Header.Add(h[0], h[1])
+			}
+			w := httptest.NewRecorder()
+
+			newContractHandler(t, !c.Invalid).ServeHTTP(w, req)
+
+			for _, s := range c.Statuses {
+				if w.Code == s {
+					return
+				}
+			}
+			t.Errorf("got status %d, expected one of %v, response body: %s", w.Code, c.Statuses, w.Body.String())
+		})
+	}
+}
+`
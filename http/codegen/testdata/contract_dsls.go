@@ -0,0 +1,106 @@
+package testdata
+
+import . "goa.design/goa/v3/dsl"
+
+var ContractDSL = func() {
+	var JWT = JWTSecurity("jwt")
+	Service("svc", func() {
+		Method("update", func() {
+			Payload(func() {
+				Attribute("id", Int, func() {
+					Example(42)
+				})
+				Attribute("tags", ArrayOf(String), func() {
+					Example([]string{"a", "b"})
+				})
+				Attribute("token", String, func() {
+					Example("secret")
+				})
+				Attribute("name", String, func() {
+					Example("goa")
+				})
+				Required("id", "name")
+			})
+			HTTP(func() {
+				PUT("/items/{id}")
+				Param("tags")
+				Header("token:X-Token")
+				Body(func() {
+					Attribute("name")
+					Required("name")
+				})
+				Response(StatusAccepted)
+			})
+		})
+		Method("list", func() {
+			HTTP(func() {
+				GET("/items")
+			})
+		})
+		Method("secure", func() {
+			Security(JWT)
+			Payload(func() {
+				Token("token", String)
+			})
+			HTTP(func() {
+				GET("/secure")
+			})
+		})
+	})
+}
+
+var ContractFeaturesDSL = func() {
+	var Item = ResultType("application/vnd.item", func() {
+		Meta("jsonapi:type", "items")
+		Attributes(func() {
+			Attribute("id", String, func() {
+				Example("1")
+			})
+			Attribute("name", String, func() {
+				Example("goa")
+			})
+			Required("id")
+		})
+	})
+	Service("svc", func() {
+		Method("show", func() {
+			Payload(func() {
+				Attribute("id", String, func() {
+					Example("1")
+				})
+			})
+			Result(Item)
+			HTTP(func() {
+				GET("/items/{id}")
+			})
+		})
+		Method("patch", func() {
+			Payload(func() {
+				Attribute("name", String, func() {
+					Example("goa")
+				})
+				Attribute("fields", ArrayOf(String))
+			})
+			HTTP(func() {
+				PATCH("/items")
+				DisallowUnknownFields()
+				FieldPresence("fields")
+			})
+		})
+		Method("receive", func() {
+			Payload(func() {
+				Attribute("type", String, func() {
+					Example("com.example.created")
+				})
+				Attribute("name", String, func() {
+					Example("goa")
+				})
+			})
+			HTTP(func() {
+				POST("/events")
+				CloudEvents()
+				Header("type:ce-type")
+			})
+		})
+	})
+}
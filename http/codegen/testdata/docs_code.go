@@ -0,0 +1,180 @@
+package testdata
+
+var DocsMarkdownIndexCode = `# Cellar API
+
+The cellar API
+
+Base URL: ` + "`" + `http://localhost:80` + "`" + `
+
+| Service | Description |
+|---------|-------------|
+| [sommelier](sommelier.md) | The sommelier service |
+`
+
+var DocsMarkdownServiceCode = `# sommelier
+
+The sommelier service
+
+## Endpoints
+
+| Endpoint | Method | Path | Description |
+|----------|--------|------|-------------|
+| [pick](#pick) | POST | ` + "`" + `/bottles/{id}` + "`" + ` | Pick a bottle |
+
+## pick
+
+Pick a bottle
+
+` + "`" + `POST /bottles/{id}` + "`" + `
+
+### Path parameters
+
+| Name | Type | Required | Description |
+|------|------|----------|-------------|
+| id | int | yes | Bottle ID |
+
+### Query parameters
+
+| Name | Type | Required | Description |
+|------|------|----------|-------------|
+| vintage | int | no | Vintage \| year (default: 2015) |
+
+### Request body
+
+| Name | Type | Required | Description |
+|------|------|----------|-------------|
+| name | string | yes |  |
+
+### Responses
+
+#### 200 OK
+
+| Name | Type | Required | Description |
+|------|------|----------|-------------|
+| id | int | yes | Bottle ID |
+| name | string | yes | Bottle name |
+
+### Errors
+
+| Name | Status | Description |
+|------|--------|-------------|
+| not_found | 404 Not Found |  |
+
+### Example
+
+` + "`" + `` + "`" + `` + "`" + `sh
+curl -X POST 'http://localhost:80/bottles/1?vintage=2016' -H 'Content-Type: application/json' -d '{"name":"Sterling"}'
+` + "`" + `` + "`" + `` + "`" + `
+`
+
+var DocsAsciiDocIndexCode = `= Cellar API
+
+The cellar API
+
+Base URL: ` + "`" + `http://localhost:80` + "`" + `
+
+[cols="1,3"]
+|===
+|Service |Description
+
+|xref:sommelier.adoc[sommelier]
+|The sommelier service
+|===
+`
+
+var DocsAsciiDocServiceCode = `= sommelier
+
+The sommelier service
+
+== Endpoints
+
+[cols="1,1,2,3"]
+|===
+|Endpoint |Method |Path |Description
+
+|<<pick,pick>>
+|POST
+|` + "`" + `/bottles/{id}` + "`" + `
+|Pick a bottle
+|===
+
+[#pick]
+== pick
+
+Pick a bottle
+
+` + "`" + `POST /bottles/{id}` + "`" + `
+
+=== Path parameters
+
+[cols="1,1,1,3"]
+|===
+|Name |Type |Required |Description
+
+|id
+|int
+|yes
+|Bottle ID
+|===
+
+=== Query parameters
+
+[cols="1,1,1,3"]
+|===
+|Name |Type |Required |Description
+
+|vintage
+|int
+|no
+|Vintage \| year (default: 2015)
+|===
+
+=== Request body
+
+[cols="1,1,1,3"]
+|===
+|Name |Type |Required |Description
+
+|name
+|string
+|yes
+|
+|===
+
+=== Responses
+
+==== 200 OK
+
+[cols="1,1,1,3"]
+|===
+|Name |Type |Required |Description
+
+|id
+|int
+|yes
+|Bottle ID
+
+|name
+|string
+|yes
+|Bottle name
+|===
+
+=== Errors
+
+[cols="1,1,3"]
+|===
+|Name |Status |Description
+
+|not_found
+|404 Not Found
+|
+|===
+
+=== Example
+
+[source,sh]
+----
+curl -X POST 'http://localhost:80/bottles/1?vintage=2016' -H 'Content-Type: application/json' -d '{"name":"Sterling"}'
+----
+`
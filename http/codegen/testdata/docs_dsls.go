@@ -0,0 +1,49 @@
+package testdata
+
+import . "goa.design/goa/v3/dsl"
+
+var DocsDSL = func() {
+	var Bottle = ResultType("application/vnd.bottle", "Bottle", func() {
+		Description("A bottle of wine")
+		Attributes(func() {
+			Attribute("id", Int, "Bottle ID", func() {
+				Example(1)
+			})
+			Attribute("name", String, "Bottle name", func() {
+				Example("Sterling")
+			})
+			Required("id", "name")
+		})
+	})
+	var _ = API("cellar", func() {
+		Title("Cellar API")
+		Description("The cellar API")
+	})
+	Service("sommelier", func() {
+		Description("The sommelier service")
+		Method("pick", func() {
+			Description("Pick a bottle")
+			Payload(func() {
+				Attribute("id", Int, "Bottle ID", func() {
+					Example(1)
+				})
+				Attribute("vintage", Int, "Vintage | year", func() {
+					Default(2015)
+					Example(2016)
+				})
+				Attribute("name", String, func() {
+					Example("Sterling")
+				})
+				Required("id", "name")
+			})
+			Result(Bottle)
+			Error("not_found", String, "Bottle not found")
+			HTTP(func() {
+				POST("/bottles/{id}")
+				Param("vintage")
+				Response(StatusOK)
+				Response("not_found", StatusNotFound)
+			})
+		})
+	})
+}
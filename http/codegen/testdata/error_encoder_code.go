@@ -390,3 +390,63 @@ func EncodeMethodEmptyCustomErrorResponseBodyError(encoder func(context.Context,
 	}
 }
 `
+
+var JSONAPIErrorResponseEncoderCode = `// EncodeMethodJSONAPIErrorResponseError returns an encoder for errors returned
+// by the MethodJSONAPIErrorResponse ServiceJSONAPIErrorResponse endpoint.
+func EncodeMethodJSONAPIErrorResponseError(encoder func(context.Context, http.ResponseWriter) goahttp.Encoder, formatter func(ctx context.Context, err error) goahttp.Statuser) func(context.Context, http.ResponseWriter, error) error {
+	encodeError := goahttp.ErrorEncoder(encoder, formatter)
+	return func(ctx context.Context, w http.ResponseWriter, v error) error {
+		ctx = goahttp.WithJSONAPIErrors(ctx)
+		var en goa.GoaErrorNamer
+		if !errors.As(v, &en) {
+			return encodeError(ctx, w, v)
+		}
+		switch en.GoaErrorName() {
+		case "bad_request":
+			var res *goa.ServiceError
+			errors.As(v, &res)
+			enc := encoder(ctx, w)
+			var body any
+			if formatter != nil {
+				body = formatter(ctx, res)
+			} else {
+				body = NewMethodJSONAPIErrorResponseBadRequestResponseBody(res)
+			}
+			w.Header().Set("goa-error", res.GoaErrorName())
+			w.WriteHeader(http.StatusBadRequest)
+			return enc.Encode(body)
+		default:
+			return encodeError(ctx, w, v)
+		}
+	}
+}
+`
+
+var FieldSelectionErrorResponseEncoderCode = `// DecodeMethodFieldSelectionRequest returns a decoder for requests sent to the
+// ServiceFieldSelection MethodFieldSelection endpoint.
+func DecodeMethodFieldSelectionRequest(mux goahttp.Muxer, decoder func(*http.Request) goahttp.Decoder) func(*http.Request) (any, error) {
+	return func(r *http.Request) (any, error) {
+		var (
+			a   *bool
+			err error
+			qp  = r.URL.Query()
+		)
+		{
+			aRaw := qp.Get("a")
+			if aRaw != "" {
+				v, err2 := strconv.ParseBool(aRaw)
+				if err2 != nil {
+					err = goa.MergeErrors(err, goa.InvalidFieldTypeError("a", aRaw, "boolean"))
+				}
+				a = &v
+			}
+		}
+		if err != nil {
+			return nil, err
+		}
+		payload := NewMethodFieldSelectionPayload(a)
+
+		return payload, nil
+	}
+}
+`
@@ -254,3 +254,26 @@ var ErrorExamplesDSL = func() {
 		})
 	})
 }
+
+var JSONAPIErrorResponseDSL = func() {
+	var RT = ResultType("application/vnd.jsonapi.result", func() {
+		TypeName("JSONAPIResult")
+		Meta("jsonapi:type", "results")
+		Attributes(func() {
+			Attribute("id", String)
+			Attribute("parent", String, func() {
+				Meta("jsonapi:relationship", "parents")
+			})
+		})
+	})
+	Service("ServiceJSONAPIErrorResponse", func() {
+		Method("MethodJSONAPIErrorResponse", func() {
+			Result(RT)
+			Error("bad_request")
+			HTTP(func() {
+				GET("/one/two")
+				Response("bad_request", StatusBadRequest)
+			})
+		})
+	})
+}
@@ -0,0 +1,21 @@
+package testdata
+
+var FeatureFlagMethodCode = `// UseFeatureFlags gates the handlers of the ServiceFeatureFlag methods
+// configured with the "feature:flag" meta using the given flag provider: the
+// requests made while the flag of a method is disabled are rejected without
+// calling the method.
+func (s *Server) UseFeatureFlags(p middleware.FlagProvider) {
+	s.Gated = httpmdlwr.FeatureFlag(p, "new-gated", http.StatusForbidden)(s.Gated)
+	s.Hidden = httpmdlwr.FeatureFlag(p, "new-hidden", http.StatusNotFound)(s.Hidden)
+}
+`
+
+var FeatureFlagServiceCode = `// UseFeatureFlags gates the handlers of the ServiceFeatureFlag methods
+// configured with the "feature:flag" meta using the given flag provider: the
+// requests made while the flag of a method is disabled are rejected without
+// calling the method.
+func (s *Server) UseFeatureFlags(p middleware.FlagProvider) {
+	s.A = httpmdlwr.FeatureFlag(p, "beta", http.StatusNotFound)(s.A)
+	s.B = httpmdlwr.FeatureFlag(p, "beta-b", http.StatusNotFound)(s.B)
+}
+`
@@ -0,0 +1,45 @@
+package testdata
+
+import (
+	. "goa.design/goa/v3/dsl"
+)
+
+var FeatureFlagMethodDSL = func() {
+	Service("ServiceFeatureFlag", func() {
+		Method("Gated", func() {
+			Meta("feature:flag", "new-gated")
+			Meta("feature:flag:status", "403")
+			HTTP(func() {
+				GET("/gated")
+			})
+		})
+		Method("Hidden", func() {
+			Meta("feature:flag", "new-hidden")
+			HTTP(func() {
+				GET("/hidden")
+			})
+		})
+		Method("Public", func() {
+			HTTP(func() {
+				GET("/public")
+			})
+		})
+	})
+}
+
+var FeatureFlagServiceDSL = func() {
+	Service("ServiceFeatureFlag", func() {
+		Meta("feature:flag", "beta")
+		Method("A", func() {
+			HTTP(func() {
+				GET("/a")
+			})
+		})
+		Method("B", func() {
+			Meta("feature:flag", "beta-b")
+			HTTP(func() {
+				GET("/b")
+			})
+		})
+	})
+}
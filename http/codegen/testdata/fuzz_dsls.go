@@ -0,0 +1,36 @@
+package testdata
+
+import . "goa.design/goa/v3/dsl"
+
+var FuzzDSL = func() {
+	Service("svc", func() {
+		Method("update", func() {
+			Payload(func() {
+				Attribute("id", Int, func() {
+					Example(42)
+				})
+				Attribute("filter", String, func() {
+					Example("all")
+				})
+				Attribute("token", String, func() {
+					Example("secret")
+				})
+				Attribute("name", String, func() {
+					MinLength(2)
+					Example("goa")
+				})
+				Required("id", "name")
+			})
+			HTTP(func() {
+				PUT("/items/{id}")
+				Param("filter")
+				Header("token:X-Token")
+			})
+		})
+		Method("list", func() {
+			HTTP(func() {
+				GET("/items")
+			})
+		})
+	})
+}
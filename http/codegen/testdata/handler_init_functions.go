@@ -271,6 +271,10 @@ func NewMethodSkipResponseBodyEncodeDecodeHandler(
 		}
 		o := res.(*serviceskipresponsebodyencodedecode.MethodSkipResponseBodyEncodeDecodeResponseData)
 		defer o.Body.Close()
+		if rs, ok := o.Body.(io.ReadSeeker); ok {
+			// serve the requested range of seekable bodies
+			w = goahttp.NewRangeWriter(w, r, rs)
+		}
 		// handle immediate read error like a returned error
 		buf := bufio.NewReader(o.Body)
 		if _, err := buf.Peek(1); err != nil && err != io.EOF {
@@ -283,7 +287,7 @@ func NewMethodSkipResponseBodyEncodeDecodeHandler(
 			errhandler(ctx, w, err)
 			return
 		}
-		if _, err := io.Copy(w, buf); err != nil {
+		if _, err := io.Copy(w, goahttp.RangeBody(w, buf)); err != nil {
 			if f, ok := w.(http.Flusher); ok {
 				f.Flush()
 			}
@@ -292,3 +296,221 @@ func NewMethodSkipResponseBodyEncodeDecodeHandler(
 	})
 }
 `
+
+var ServerMigrationDefaultHandlerConstructorCode = `// NewMethodBodyUserHandler creates a HTTP handler which loads the HTTP request
+// and calls the "ServiceBodyUser" service "MethodBodyUser" endpoint.
+func NewMethodBodyUserHandler(
+	endpoint goa.Endpoint,
+	mux goahttp.Muxer,
+	decoder func(*http.Request) goahttp.Decoder,
+	encoder func(context.Context, http.ResponseWriter) goahttp.Encoder,
+	errhandler func(context.Context, http.ResponseWriter, error),
+	formatter func(ctx context.Context, err error) goahttp.Statuser,
+) http.Handler {
+	var (
+		decodeRequest  = DecodeMethodBodyUserRequest(mux, decoder)
+		encodeResponse = EncodeMethodBodyUserResponse(encoder)
+		encodeError    = goahttp.ErrorEncoder(encoder, formatter)
+	)
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		r = r.WithContext(goahttp.WithWarnings(r.Context()))
+		ctx := context.WithValue(r.Context(), goahttp.AcceptTypeKey, r.Header.Get("Accept"))
+		ctx = context.WithValue(ctx, goa.MethodKey, "MethodBodyUser")
+		ctx = context.WithValue(ctx, goa.ServiceKey, "ServiceBodyUser")
+		payload, err := decodeRequest(r)
+		if err != nil {
+			if err := encodeError(ctx, w, err); err != nil {
+				errhandler(ctx, w, err)
+			}
+			return
+		}
+		goahttp.SetWarnings(ctx, w)
+		res, err := endpoint(ctx, payload)
+		if err != nil {
+			if err := encodeError(ctx, w, err); err != nil {
+				errhandler(ctx, w, err)
+			}
+			return
+		}
+		if err := encodeResponse(ctx, w, res); err != nil {
+			errhandler(ctx, w, err)
+		}
+	})
+}
+`
+
+var ServerJSONAPIHandlerConstructorCode = `// NewMethodJSONAPIErrorResponseHandler creates a HTTP handler which loads the
+// HTTP request and calls the "ServiceJSONAPIErrorResponse" service
+// "MethodJSONAPIErrorResponse" endpoint.
+func NewMethodJSONAPIErrorResponseHandler(
+	endpoint goa.Endpoint,
+	mux goahttp.Muxer,
+	decoder func(*http.Request) goahttp.Decoder,
+	encoder func(context.Context, http.ResponseWriter) goahttp.Encoder,
+	errhandler func(context.Context, http.ResponseWriter, error),
+	formatter func(ctx context.Context, err error) goahttp.Statuser,
+) http.Handler {
+	var (
+		encodeResponse = EncodeMethodJSONAPIErrorResponseResponse(encoder)
+		encodeError    = EncodeMethodJSONAPIErrorResponseError(encoder, formatter)
+	)
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ctx := context.WithValue(r.Context(), goahttp.AcceptTypeKey, r.Header.Get("Accept"))
+		ctx = context.WithValue(ctx, goa.MethodKey, "MethodJSONAPIErrorResponse")
+		ctx = context.WithValue(ctx, goa.ServiceKey, "ServiceJSONAPIErrorResponse")
+		ctx = goahttp.WithJSONAPI(ctx, r, &goahttp.JSONAPIResource{Type: "results", Relationships: map[string]string{"parent": "parents"}})
+		var err error
+		res, err := endpoint(ctx, nil)
+		if err != nil {
+			if err := encodeError(ctx, w, err); err != nil {
+				errhandler(ctx, w, err)
+			}
+			return
+		}
+		if err := encodeResponse(ctx, w, res); err != nil {
+			errhandler(ctx, w, err)
+		}
+	})
+}
+`
+
+var ServerFieldSelectionHandlerConstructorCode = `// NewMethodFieldSelectionHandler creates a HTTP handler which loads the HTTP
+// request and calls the "ServiceFieldSelection" service "MethodFieldSelection"
+// endpoint.
+func NewMethodFieldSelectionHandler(
+	endpoint goa.Endpoint,
+	mux goahttp.Muxer,
+	decoder func(*http.Request) goahttp.Decoder,
+	encoder func(context.Context, http.ResponseWriter) goahttp.Encoder,
+	errhandler func(context.Context, http.ResponseWriter, error),
+	formatter func(ctx context.Context, err error) goahttp.Statuser,
+) http.Handler {
+	var (
+		decodeRequest  = DecodeMethodFieldSelectionRequest(mux, decoder)
+		encodeResponse = EncodeMethodFieldSelectionResponse(encoder)
+		encodeError    = EncodeMethodFieldSelectionError(encoder, formatter)
+	)
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ctx := context.WithValue(r.Context(), goahttp.AcceptTypeKey, r.Header.Get("Accept"))
+		ctx = context.WithValue(ctx, goa.MethodKey, "MethodFieldSelection")
+		ctx = context.WithValue(ctx, goa.ServiceKey, "ServiceFieldSelection")
+		ctx, ferr := goahttp.WithFieldSelection(ctx, r, "select", []string{"account", "account.name", "b"})
+		if ferr != nil {
+			if err := encodeError(ctx, w, ferr); err != nil {
+				errhandler(ctx, w, err)
+			}
+			return
+		}
+		payload, err := decodeRequest(r)
+		if err != nil {
+			if err := encodeError(ctx, w, err); err != nil {
+				errhandler(ctx, w, err)
+			}
+			return
+		}
+		res, err := endpoint(ctx, payload)
+		if err != nil {
+			if err := encodeError(ctx, w, err); err != nil {
+				errhandler(ctx, w, err)
+			}
+			return
+		}
+		if err := encodeResponse(ctx, w, res); err != nil {
+			errhandler(ctx, w, err)
+		}
+	})
+}
+`
+
+var ServerCriteriaHandlerConstructorCode = `// NewMethodCriteriaHandler creates a HTTP handler which loads the HTTP request
+// and calls the "ServiceCriteria" service "MethodCriteria" endpoint.
+func NewMethodCriteriaHandler(
+	endpoint goa.Endpoint,
+	mux goahttp.Muxer,
+	decoder func(*http.Request) goahttp.Decoder,
+	encoder func(context.Context, http.ResponseWriter) goahttp.Encoder,
+	errhandler func(context.Context, http.ResponseWriter, error),
+	formatter func(ctx context.Context, err error) goahttp.Statuser,
+) http.Handler {
+	var (
+		decodeRequest  = DecodeMethodCriteriaRequest(mux, decoder)
+		encodeResponse = EncodeMethodCriteriaResponse(encoder)
+		encodeError    = goahttp.ErrorEncoder(encoder, formatter)
+	)
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ctx := context.WithValue(r.Context(), goahttp.AcceptTypeKey, r.Header.Get("Accept"))
+		ctx = context.WithValue(ctx, goa.MethodKey, "MethodCriteria")
+		ctx = context.WithValue(ctx, goa.ServiceKey, "ServiceCriteria")
+		if err := goahttp.ValidateFilters(r, []string{"name"}); err != nil {
+			if err := encodeError(ctx, w, err); err != nil {
+				errhandler(ctx, w, err)
+			}
+			return
+		}
+		payload, err := decodeRequest(r)
+		if err != nil {
+			if err := encodeError(ctx, w, err); err != nil {
+				errhandler(ctx, w, err)
+			}
+			return
+		}
+		res, err := endpoint(ctx, payload)
+		if err != nil {
+			if err := encodeError(ctx, w, err); err != nil {
+				errhandler(ctx, w, err)
+			}
+			return
+		}
+		if err := encodeResponse(ctx, w, res); err != nil {
+			errhandler(ctx, w, err)
+		}
+	})
+}
+`
+
+var ServerCloudEventsHandlerConstructorCode = `// NewMethodCloudEventsHandler creates a HTTP handler which loads the HTTP
+// request and calls the "ServiceCloudEvents" service "MethodCloudEvents"
+// endpoint.
+func NewMethodCloudEventsHandler(
+	endpoint goa.Endpoint,
+	mux goahttp.Muxer,
+	decoder func(*http.Request) goahttp.Decoder,
+	encoder func(context.Context, http.ResponseWriter) goahttp.Encoder,
+	errhandler func(context.Context, http.ResponseWriter, error),
+	formatter func(ctx context.Context, err error) goahttp.Statuser,
+) http.Handler {
+	var (
+		decodeRequest  = DecodeMethodCloudEventsRequest(mux, decoder)
+		encodeResponse = EncodeMethodCloudEventsResponse(encoder)
+		encodeError    = goahttp.ErrorEncoder(encoder, formatter)
+	)
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ctx := context.WithValue(r.Context(), goahttp.AcceptTypeKey, r.Header.Get("Accept"))
+		ctx = context.WithValue(ctx, goa.MethodKey, "MethodCloudEvents")
+		ctx = context.WithValue(ctx, goa.ServiceKey, "ServiceCloudEvents")
+		if err := goahttp.NormalizeCloudEvent(r); err != nil {
+			if err := encodeError(ctx, w, err); err != nil {
+				errhandler(ctx, w, err)
+			}
+			return
+		}
+		payload, err := decodeRequest(r)
+		if err != nil {
+			if err := encodeError(ctx, w, err); err != nil {
+				errhandler(ctx, w, err)
+			}
+			return
+		}
+		res, err := endpoint(ctx, payload)
+		if err != nil {
+			if err := encodeError(ctx, w, err); err != nil {
+				errhandler(ctx, w, err)
+			}
+			return
+		}
+		if err := encodeResponse(ctx, w, res); err != nil {
+			errhandler(ctx, w, err)
+		}
+	})
+}
+`
@@ -0,0 +1,10 @@
+package testdata
+
+var IdempotentCode = `// UseIdempotency records the responses of the ServiceIdempotent methods that
+// use the Idempotent DSL in store and replays them to the requests retried
+// with the same idempotency key without calling the methods again.
+func (s *Server) UseIdempotency(store httpmdlwr.IdempotencyStore, opts ...httpmdlwr.IdempotencyOption) {
+	s.Create = httpmdlwr.Idempotency(store, "Idempotency-Key", opts...)(s.Create)
+	s.Update = httpmdlwr.Idempotency(store, "X-Request-Key", opts...)(s.Update)
+}
+`
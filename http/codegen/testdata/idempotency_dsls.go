@@ -0,0 +1,29 @@
+package testdata
+
+import (
+	. "goa.design/goa/v3/dsl"
+)
+
+var IdempotentDSL = func() {
+	Service("ServiceIdempotent", func() {
+		Method("Create", func() {
+			Payload(String)
+			HTTP(func() {
+				POST("/")
+				Idempotent()
+			})
+		})
+		Method("Update", func() {
+			Payload(String)
+			HTTP(func() {
+				PATCH("/")
+				Idempotent("X-Request-Key")
+			})
+		})
+		Method("Show", func() {
+			HTTP(func() {
+				GET("/")
+			})
+		})
+	})
+}
@@ -0,0 +1,188 @@
+package testdata
+
+var AppendJSONCode = `// MarshalJSON implements json.Marshaler using AppendJSON so that encoding
+// ShowResponseBody does not rely on reflection.
+func (body *ShowResponseBody) MarshalJSON() ([]byte, error) {
+	return body.AppendJSON(make([]byte, 0, 256))
+}
+
+// AppendJSON appends the JSON encoding of body to b.
+func (body *ShowResponseBody) AppendJSON(b []byte) ([]byte, error) {
+	if body == nil {
+		return append(b, "null"...), nil
+	}
+	var err error
+	b = append(b, '{')
+	b = goahttp.AppendJSONField(b, ` + "`" + `"id":` + "`" + `)
+	b = strconv.AppendInt(b, body.ID, 10)
+	if body.Title != nil {
+		b = goahttp.AppendJSONField(b, ` + "`" + `"title":` + "`" + `)
+		b = goahttp.AppendJSONString(b, *body.Title)
+	}
+	b = goahttp.AppendJSONField(b, ` + "`" + `"count":` + "`" + `)
+	b = strconv.AppendUint(b, uint64(body.Count), 10)
+	if body.OK != nil {
+		b = goahttp.AppendJSONField(b, ` + "`" + `"ok":` + "`" + `)
+		b = strconv.AppendBool(b, *body.OK)
+	}
+	if len(body.Data) > 0 {
+		b = goahttp.AppendJSONField(b, ` + "`" + `"data":` + "`" + `)
+		b = goahttp.AppendJSONBytes(b, body.Data)
+	}
+	if len(body.Tags) > 0 {
+		b = goahttp.AppendJSONField(b, ` + "`" + `"tags":` + "`" + `)
+		b = append(b, '[')
+		for i0, v0 := range body.Tags {
+			if i0 > 0 {
+				b = append(b, ',')
+			}
+			b = goahttp.AppendJSONString(b, v0)
+		}
+		b = append(b, ']')
+	}
+	if len(body.Labels) > 0 {
+		b = goahttp.AppendJSONField(b, ` + "`" + `"labels":` + "`" + `)
+		keys0 := make([]string, 0, len(body.Labels))
+		for k0 := range body.Labels {
+			keys0 = append(keys0, k0)
+		}
+		sort.Strings(keys0)
+		b = append(b, '{')
+		for i0, k0 := range keys0 {
+			if i0 > 0 {
+				b = append(b, ',')
+			}
+			b = goahttp.AppendJSONString(b, k0)
+			b = append(b, ':')
+			b = strconv.AppendInt(b, int64(body.Labels[k0]), 10)
+		}
+		b = append(b, '}')
+	}
+	if body.Child != nil {
+		b = goahttp.AppendJSONField(b, ` + "`" + `"child":` + "`" + `)
+		if b, err = goahttp.AppendJSON(b, body.Child); err != nil {
+			return nil, err
+		}
+	}
+	if body.Tagged != nil {
+		b = goahttp.AppendJSONField(b, ` + "`" + `"tagged":` + "`" + `)
+		if b, err = goahttp.AppendJSON(b, body.Tagged); err != nil {
+			return nil, err
+		}
+	}
+	return append(b, '}'), nil
+}
+
+// MarshalJSON implements json.Marshaler using AppendJSON so that encoding
+// ChildResponseBody does not rely on reflection.
+func (body *ChildResponseBody) MarshalJSON() ([]byte, error) {
+	return body.AppendJSON(make([]byte, 0, 256))
+}
+
+// AppendJSON appends the JSON encoding of body to b.
+func (body *ChildResponseBody) AppendJSON(b []byte) ([]byte, error) {
+	if body == nil {
+		return append(b, "null"...), nil
+	}
+	var err error
+	b = append(b, '{')
+	b = goahttp.AppendJSONField(b, ` + "`" + `"name":` + "`" + `)
+	b = goahttp.AppendJSONString(b, body.Name)
+	if body.Score != nil {
+		b = goahttp.AppendJSONField(b, ` + "`" + `"score":` + "`" + `)
+		if b, err = goahttp.AppendJSONFloat(b, float64(*body.Score), 32); err != nil {
+			return nil, err
+		}
+	}
+	return append(b, '}'), nil
+}
+`
+
+var AppendJSONTestCode = `// TestShowResponseBodyAppendJSON checks that AppendJSON produces the same
+// encoding as encoding/json.
+func TestShowResponseBodyAppendJSON(t *testing.T) {
+	type stdlib ShowResponseBody
+	var body ShowResponseBody
+	if err := json.Unmarshal([]byte("{\"child\":{\"name\":\"Quo qui molestiae iure.\",\"score\":0.19859962},\"count\":2183360095703099606,\"data\":\"UHJvdmlkZW50IGFsaXF1YW0gdGVtcG9yYSBiZWF0YWUgdml0YWUu\",\"id\":1,\"labels\":{\"Aperiam qui aut dicta.\":2850694428022055785,\"Aspernatur quo error explicabo pariatur.\":8867148869158856261,\"Voluptatem et distinctio aliquam nihil.\":7535444955450081856},\"ok\":true,\"tagged\":{\"value\":\"Sint voluptate rem perspiciatis voluptatum laudantium.\"},\"tags\":[\"Minus explicabo nemo.\",\"Vel repellat aut.\"],\"title\":\"goa\"}"), &body); err != nil {
+		t.Fatal(err)
+	}
+	expected, err := json.Marshal((*stdlib)(&body))
+	if err != nil {
+		t.Fatal(err)
+	}
+	got, err := body.AppendJSON(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(got) != string(expected) {
+		t.Errorf("got %s, expected %s", got, expected)
+	}
+}
+
+// BenchmarkShowResponseBodyJSON compares the encoding of ShowResponseBody
+// using AppendJSON with encoding/json.
+func BenchmarkShowResponseBodyJSON(b *testing.B) {
+	type stdlib ShowResponseBody
+	var body ShowResponseBody
+	if err := json.Unmarshal([]byte("{\"child\":{\"name\":\"Quo qui molestiae iure.\",\"score\":0.19859962},\"count\":2183360095703099606,\"data\":\"UHJvdmlkZW50IGFsaXF1YW0gdGVtcG9yYSBiZWF0YWUgdml0YWUu\",\"id\":1,\"labels\":{\"Aperiam qui aut dicta.\":2850694428022055785,\"Aspernatur quo error explicabo pariatur.\":8867148869158856261,\"Voluptatem et distinctio aliquam nihil.\":7535444955450081856},\"ok\":true,\"tagged\":{\"value\":\"Sint voluptate rem perspiciatis voluptatum laudantium.\"},\"tags\":[\"Minus explicabo nemo.\",\"Vel repellat aut.\"],\"title\":\"goa\"}"), &body); err != nil {
+		b.Fatal(err)
+	}
+	b.Run("AppendJSON", func(b *testing.B) {
+		b.ReportAllocs()
+		buf := make([]byte, 0, 1024)
+		for i := 0; i < b.N; i++ {
+			buf, _ = body.AppendJSON(buf[:0])
+		}
+	})
+	b.Run("stdlib", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			_, _ = json.Marshal((*stdlib)(&body))
+		}
+	})
+}
+
+// TestChildResponseBodyAppendJSON checks that AppendJSON produces the same
+// encoding as encoding/json.
+func TestChildResponseBodyAppendJSON(t *testing.T) {
+	type stdlib ChildResponseBody
+	var body ChildResponseBody
+	if err := json.Unmarshal([]byte("{\"name\":\"Quo qui molestiae iure.\",\"score\":0.19859962}"), &body); err != nil {
+		t.Fatal(err)
+	}
+	expected, err := json.Marshal((*stdlib)(&body))
+	if err != nil {
+		t.Fatal(err)
+	}
+	got, err := body.AppendJSON(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(got) != string(expected) {
+		t.Errorf("got %s, expected %s", got, expected)
+	}
+}
+
+// BenchmarkChildResponseBodyJSON compares the encoding of ChildResponseBody
+// using AppendJSON with encoding/json.
+func BenchmarkChildResponseBodyJSON(b *testing.B) {
+	type stdlib ChildResponseBody
+	var body ChildResponseBody
+	if err := json.Unmarshal([]byte("{\"name\":\"Quo qui molestiae iure.\",\"score\":0.19859962}"), &body); err != nil {
+		b.Fatal(err)
+	}
+	b.Run("AppendJSON", func(b *testing.B) {
+		b.ReportAllocs()
+		buf := make([]byte, 0, 1024)
+		for i := 0; i < b.N; i++ {
+			buf, _ = body.AppendJSON(buf[:0])
+		}
+	})
+	b.Run("stdlib", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			_, _ = json.Marshal((*stdlib)(&body))
+		}
+	})
+}
+`
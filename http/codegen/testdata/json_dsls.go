@@ -0,0 +1,55 @@
+package testdata
+
+import . "goa.design/goa/v3/dsl"
+
+var FastJSONDSL = func() {
+	var Child = Type("Child", func() {
+		Attribute("name", String)
+		Attribute("score", Float32)
+		Required("name")
+	})
+	var Tagged = Type("Tagged", func() {
+		Attribute("value", String, func() {
+			Meta("struct:tag:json", "v")
+		})
+	})
+	Service("svc", func() {
+		Meta("http:json:fast")
+		Method("show", func() {
+			Result(func() {
+				Attribute("id", Int64, func() {
+					Example(1)
+				})
+				Attribute("title", String, func() {
+					Example("goa")
+				})
+				Attribute("count", UInt, func() {
+					Default(3)
+				})
+				Attribute("ok", Boolean)
+				Attribute("data", Bytes)
+				Attribute("tags", ArrayOf(String))
+				Attribute("labels", MapOf(String, Int))
+				Attribute("child", Child)
+				Attribute("tagged", Tagged)
+				Required("id")
+			})
+			HTTP(func() {
+				GET("/")
+			})
+		})
+	})
+}
+
+var NoFastJSONDSL = func() {
+	Service("svc", func() {
+		Method("show", func() {
+			Result(func() {
+				Attribute("id", Int64)
+			})
+			HTTP(func() {
+				GET("/")
+			})
+		})
+	})
+}
@@ -0,0 +1,56 @@
+package testdata
+
+import . "goa.design/goa/v3/dsl"
+
+var LoadTestDSL = func() {
+	API("test", func() {
+		Server("test", func() {
+			Host("dev", func() {
+				URI("http://localhost:8080")
+			})
+		})
+	})
+	Service("svc", func() {
+		Method("update", func() {
+			Meta("loadtest:weight", "3")
+			Payload(func() {
+				Attribute("id", Int, func() {
+					Example(42)
+				})
+				Attribute("token", String, func() {
+					Example("secret")
+				})
+				Attribute("name", String, func() {
+					Example("goa")
+				})
+			})
+			HTTP(func() {
+				PUT("/items/{id}")
+				Header("token:X-Token")
+				Response(StatusAccepted)
+			})
+		})
+		Method("list", func() {
+			HTTP(func() {
+				GET("/items")
+			})
+		})
+		Method("skipped", func() {
+			Meta("loadtest:skip")
+			HTTP(func() {
+				GET("/skipped")
+			})
+		})
+	})
+}
+
+var InvalidLoadTestWeightDSL = func() {
+	Service("svc", func() {
+		Method("list", func() {
+			Meta("loadtest:weight", "0")
+			HTTP(func() {
+				GET("/items")
+			})
+		})
+	})
+}
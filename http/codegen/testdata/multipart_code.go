@@ -146,13 +146,13 @@ func NewServiceMultipartWithParamMethodMultipartWithParamDecoder(mux goahttp.Mux
 			var (
 				c2  map[int][]string
 				err error
+				qp  = r.URL.Query()
 			)
 			{
-				c2Raw := r.URL.Query()
-				if len(c2Raw) == 0 {
+				if len(qp) == 0 {
 					err = goa.MergeErrors(err, goa.MissingFieldError("c", "query string"))
 				}
-				for keyRaw, valRaw := range c2Raw {
+				for keyRaw, valRaw := range qp {
 					if strings.HasPrefix(keyRaw, "c[") {
 						if c2 == nil {
 							c2 = make(map[int][]string)
@@ -197,6 +197,7 @@ func NewServiceMultipartWithParamsAndHeadersMethodMultipartWithParamsAndHeadersD
 			if err := serviceMultipartWithParamsAndHeadersMethodMultipartWithParamsAndHeadersDecoderFn(mr, p); err != nil {
 				return err
 			}
+
 			var (
 				a   string
 				c2  map[int][]string
@@ -204,15 +205,15 @@ func NewServiceMultipartWithParamsAndHeadersMethodMultipartWithParamsAndHeadersD
 				err error
 
 				params = mux.Vars(r)
+				qp     = r.URL.Query()
 			)
 			a = params["a"]
 			err = goa.MergeErrors(err, goa.ValidatePattern("a", a, "patterna"))
 			{
-				c2Raw := r.URL.Query()
-				if len(c2Raw) == 0 {
+				if len(qp) == 0 {
 					err = goa.MergeErrors(err, goa.MissingFieldError("c", "query string"))
 				}
-				for keyRaw, valRaw := range c2Raw {
+				for keyRaw, valRaw := range qp {
 					if strings.HasPrefix(keyRaw, "c[") {
 						if c2 == nil {
 							c2 = make(map[int][]string)
@@ -718,3 +718,90 @@ var SkipResponseBodyEncodeDecodeDSL = func() {
 		})
 	})
 }
+
+var LocaleDSL = func() {
+	var _ = API("test", func() {
+		Meta("http:locale")
+	})
+	Service("testService", func() {
+		Method("dates", func() {
+			Payload(func() {
+				Attribute("lang", String)
+			})
+			Result(String)
+			HTTP(func() {
+				GET("/dates")
+				Header("lang:Accept-Language")
+			})
+		})
+		Method("schedule", func() {
+			Meta("http:locale", "X-Time-Zone")
+			Result(String)
+			HTTP(func() {
+				GET("/schedule")
+			})
+		})
+	})
+}
+
+var FieldPresenceDSL = func() {
+	Service("testService", func() {
+		Method("update", func() {
+			Payload(func() {
+				Attribute("id", String, func() {
+					Example("42")
+				})
+				Attribute("nickname", String, func() {
+					Example("goa")
+				})
+				Attribute("fields", ArrayOf(String))
+				Attribute("unknown", MapOf(String, Any))
+				Required("id")
+			})
+			HTTP(func() {
+				PATCH("/{id}")
+				FieldPresence("fields")
+				CollectUnknownFields("unknown")
+			})
+		})
+	})
+}
+
+var JSONAPIDSL = func() {
+	var Article = ResultType("application/vnd.article", func() {
+		TypeName("Article")
+		Meta("jsonapi:type", "articles")
+		Attributes(func() {
+			Attribute("id", String, func() {
+				Example("1")
+			})
+			Attribute("title", String, func() {
+				Example("JSON:API")
+			})
+			Attribute("author", String, func() {
+				Meta("jsonapi:relationship", "people")
+				Example("9")
+			})
+			Required("id", "title")
+		})
+	})
+	Service("testService", func() {
+		Method("show", func() {
+			Payload(func() {
+				Attribute("id", String)
+			})
+			Result(Article)
+			Error("not_found")
+			HTTP(func() {
+				GET("/articles/{id}")
+				Response("not_found", StatusNotFound)
+			})
+		})
+		Method("list", func() {
+			Result(CollectionOf(Article))
+			HTTP(func() {
+				GET("/articles")
+			})
+		})
+	})
+}
@@ -1374,3 +1374,46 @@ func BuildMethodAPayload(serviceWithParamsAndHeadersBlockMethodABody string, ser
 	return v, nil
 }
 `
+
+var CustomTypeFuncsBuildCode = `// BuildMethodCustomTypeFuncsPayload builds the payload for the
+// ServiceCustomTypeFuncs MethodCustomTypeFuncs endpoint from CLI flags.
+func BuildMethodCustomTypeFuncsPayload(serviceCustomTypeFuncsMethodCustomTypeFuncsID string, serviceCustomTypeFuncsMethodCustomTypeFuncsTimeout string, serviceCustomTypeFuncsMethodCustomTypeFuncsAmount string) (*servicecustomtypefuncs.MethodCustomTypeFuncsPayload, error) {
+	var err error
+	var id uuid.UUID
+	{
+		id, err = uuid.Parse(serviceCustomTypeFuncsMethodCustomTypeFuncsID)
+		if err != nil {
+			return nil, fmt.Errorf("invalid value for id, %s", err)
+		}
+	}
+	var timeout *time.Duration
+	{
+		if serviceCustomTypeFuncsMethodCustomTypeFuncsTimeout != "" {
+			val, err := time.ParseDuration(serviceCustomTypeFuncsMethodCustomTypeFuncsTimeout)
+			timeout = &val
+			if err != nil {
+				return nil, fmt.Errorf("invalid value for timeout, %s", err)
+			}
+			if err2 := timeouts.Validate(*timeout); err2 != nil {
+				err = goa.MergeErrors(err, goa.InvalidValueError("timeout", *timeout, err2))
+			}
+			if err != nil {
+				return nil, err
+			}
+		}
+	}
+	var amount decimal.Decimal
+	{
+		amount, err = decimal.NewFromString(serviceCustomTypeFuncsMethodCustomTypeFuncsAmount)
+		if err != nil {
+			return nil, fmt.Errorf("invalid value for amount, %s", err)
+		}
+	}
+	v := &servicecustomtypefuncs.MethodCustomTypeFuncsPayload{}
+	v.ID = id
+	v.Timeout = timeout
+	v.Amount = amount
+
+	return v, nil
+}
+`
@@ -174,3 +174,9 @@ func MethodPathInterfaceSliceParamServicePathInterfaceSliceParamPath(a []any) st
 	return fmt.Sprintf("/one/%v/two", strings.Join(aSlice, ","))
 }
 `
+
+var PathCustomTypeParamCode = `// MethodCustomTypeFuncsServiceCustomTypeFuncsPath returns the URL path to the ServiceCustomTypeFuncs service MethodCustomTypeFuncs HTTP endpoint.
+func MethodCustomTypeFuncsServiceCustomTypeFuncsPath(id uuid.UUID) string {
+	return fmt.Sprintf("/%v", uuid.UUID.String(id))
+}
+`
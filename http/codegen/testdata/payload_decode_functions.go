@@ -7,9 +7,10 @@ func DecodeMethodQueryBoolRequest(mux goahttp.Muxer, decoder func(*http.Request)
 		var (
 			q   *bool
 			err error
+			qp  = r.URL.Query()
 		)
 		{
-			qRaw := r.URL.Query().Get("q")
+			qRaw := qp.Get("q")
 			if qRaw != "" {
 				v, err2 := strconv.ParseBool(qRaw)
 				if err2 != nil {
@@ -35,9 +36,10 @@ func DecodeMethodQueryBoolValidateRequest(mux goahttp.Muxer, decoder func(*http.
 		var (
 			q   bool
 			err error
+			qp  = r.URL.Query()
 		)
 		{
-			qRaw := r.URL.Query().Get("q")
+			qRaw := qp.Get("q")
 			if qRaw == "" {
 				err = goa.MergeErrors(err, goa.MissingFieldError("q", "query string"))
 			}
@@ -67,9 +69,10 @@ func DecodeMethodQueryIntRequest(mux goahttp.Muxer, decoder func(*http.Request)
 		var (
 			q   *int
 			err error
+			qp  = r.URL.Query()
 		)
 		{
-			qRaw := r.URL.Query().Get("q")
+			qRaw := qp.Get("q")
 			if qRaw != "" {
 				v, err2 := strconv.ParseInt(qRaw, 10, strconv.IntSize)
 				if err2 != nil {
@@ -96,9 +99,10 @@ func DecodeMethodQueryIntValidateRequest(mux goahttp.Muxer, decoder func(*http.R
 		var (
 			q   int
 			err error
+			qp  = r.URL.Query()
 		)
 		{
-			qRaw := r.URL.Query().Get("q")
+			qRaw := qp.Get("q")
 			if qRaw == "" {
 				err = goa.MergeErrors(err, goa.MissingFieldError("q", "query string"))
 			}
@@ -128,9 +132,10 @@ func DecodeMethodQueryInt32Request(mux goahttp.Muxer, decoder func(*http.Request
 		var (
 			q   *int32
 			err error
+			qp  = r.URL.Query()
 		)
 		{
-			qRaw := r.URL.Query().Get("q")
+			qRaw := qp.Get("q")
 			if qRaw != "" {
 				v, err2 := strconv.ParseInt(qRaw, 10, 32)
 				if err2 != nil {
@@ -157,9 +162,10 @@ func DecodeMethodQueryInt32ValidateRequest(mux goahttp.Muxer, decoder func(*http
 		var (
 			q   int32
 			err error
+			qp  = r.URL.Query()
 		)
 		{
-			qRaw := r.URL.Query().Get("q")
+			qRaw := qp.Get("q")
 			if qRaw == "" {
 				err = goa.MergeErrors(err, goa.MissingFieldError("q", "query string"))
 			}
@@ -189,9 +195,10 @@ func DecodeMethodQueryInt64Request(mux goahttp.Muxer, decoder func(*http.Request
 		var (
 			q   *int64
 			err error
+			qp  = r.URL.Query()
 		)
 		{
-			qRaw := r.URL.Query().Get("q")
+			qRaw := qp.Get("q")
 			if qRaw != "" {
 				v, err2 := strconv.ParseInt(qRaw, 10, 64)
 				if err2 != nil {
@@ -217,9 +224,10 @@ func DecodeMethodQueryInt64ValidateRequest(mux goahttp.Muxer, decoder func(*http
 		var (
 			q   int64
 			err error
+			qp  = r.URL.Query()
 		)
 		{
-			qRaw := r.URL.Query().Get("q")
+			qRaw := qp.Get("q")
 			if qRaw == "" {
 				err = goa.MergeErrors(err, goa.MissingFieldError("q", "query string"))
 			}
@@ -249,9 +257,10 @@ func DecodeMethodQueryUIntRequest(mux goahttp.Muxer, decoder func(*http.Request)
 		var (
 			q   *uint
 			err error
+			qp  = r.URL.Query()
 		)
 		{
-			qRaw := r.URL.Query().Get("q")
+			qRaw := qp.Get("q")
 			if qRaw != "" {
 				v, err2 := strconv.ParseUint(qRaw, 10, strconv.IntSize)
 				if err2 != nil {
@@ -278,9 +287,10 @@ func DecodeMethodQueryUIntValidateRequest(mux goahttp.Muxer, decoder func(*http.
 		var (
 			q   uint
 			err error
+			qp  = r.URL.Query()
 		)
 		{
-			qRaw := r.URL.Query().Get("q")
+			qRaw := qp.Get("q")
 			if qRaw == "" {
 				err = goa.MergeErrors(err, goa.MissingFieldError("q", "query string"))
 			}
@@ -310,9 +320,10 @@ func DecodeMethodQueryUInt32Request(mux goahttp.Muxer, decoder func(*http.Reques
 		var (
 			q   *uint32
 			err error
+			qp  = r.URL.Query()
 		)
 		{
-			qRaw := r.URL.Query().Get("q")
+			qRaw := qp.Get("q")
 			if qRaw != "" {
 				v, err2 := strconv.ParseUint(qRaw, 10, 32)
 				if err2 != nil {
@@ -339,9 +350,10 @@ func DecodeMethodQueryUInt32ValidateRequest(mux goahttp.Muxer, decoder func(*htt
 		var (
 			q   uint32
 			err error
+			qp  = r.URL.Query()
 		)
 		{
-			qRaw := r.URL.Query().Get("q")
+			qRaw := qp.Get("q")
 			if qRaw == "" {
 				err = goa.MergeErrors(err, goa.MissingFieldError("q", "query string"))
 			}
@@ -371,9 +383,10 @@ func DecodeMethodQueryUInt64Request(mux goahttp.Muxer, decoder func(*http.Reques
 		var (
 			q   *uint64
 			err error
+			qp  = r.URL.Query()
 		)
 		{
-			qRaw := r.URL.Query().Get("q")
+			qRaw := qp.Get("q")
 			if qRaw != "" {
 				v, err2 := strconv.ParseUint(qRaw, 10, 64)
 				if err2 != nil {
@@ -399,9 +412,10 @@ func DecodeMethodQueryUInt64ValidateRequest(mux goahttp.Muxer, decoder func(*htt
 		var (
 			q   uint64
 			err error
+			qp  = r.URL.Query()
 		)
 		{
-			qRaw := r.URL.Query().Get("q")
+			qRaw := qp.Get("q")
 			if qRaw == "" {
 				err = goa.MergeErrors(err, goa.MissingFieldError("q", "query string"))
 			}
@@ -431,9 +445,10 @@ func DecodeMethodQueryFloat32Request(mux goahttp.Muxer, decoder func(*http.Reque
 		var (
 			q   *float32
 			err error
+			qp  = r.URL.Query()
 		)
 		{
-			qRaw := r.URL.Query().Get("q")
+			qRaw := qp.Get("q")
 			if qRaw != "" {
 				v, err2 := strconv.ParseFloat(qRaw, 32)
 				if err2 != nil {
@@ -460,9 +475,10 @@ func DecodeMethodQueryFloat32ValidateRequest(mux goahttp.Muxer, decoder func(*ht
 		var (
 			q   float32
 			err error
+			qp  = r.URL.Query()
 		)
 		{
-			qRaw := r.URL.Query().Get("q")
+			qRaw := qp.Get("q")
 			if qRaw == "" {
 				err = goa.MergeErrors(err, goa.MissingFieldError("q", "query string"))
 			}
@@ -492,9 +508,10 @@ func DecodeMethodQueryFloat64Request(mux goahttp.Muxer, decoder func(*http.Reque
 		var (
 			q   *float64
 			err error
+			qp  = r.URL.Query()
 		)
 		{
-			qRaw := r.URL.Query().Get("q")
+			qRaw := qp.Get("q")
 			if qRaw != "" {
 				v, err2 := strconv.ParseFloat(qRaw, 64)
 				if err2 != nil {
@@ -520,9 +537,10 @@ func DecodeMethodQueryFloat64ValidateRequest(mux goahttp.Muxer, decoder func(*ht
 		var (
 			q   float64
 			err error
+			qp  = r.URL.Query()
 		)
 		{
-			qRaw := r.URL.Query().Get("q")
+			qRaw := qp.Get("q")
 			if qRaw == "" {
 				err = goa.MergeErrors(err, goa.MissingFieldError("q", "query string"))
 			}
@@ -550,9 +568,10 @@ var PayloadQueryStringDecodeCode = `// DecodeMethodQueryStringRequest returns a
 func DecodeMethodQueryStringRequest(mux goahttp.Muxer, decoder func(*http.Request) goahttp.Decoder) func(*http.Request) (any, error) {
 	return func(r *http.Request) (any, error) {
 		var (
-			q *string
+			q  *string
+			qp = r.URL.Query()
 		)
-		qRaw := r.URL.Query().Get("q")
+		qRaw := qp.Get("q")
 		if qRaw != "" {
 			q = &qRaw
 		}
@@ -570,8 +589,9 @@ func DecodeMethodQueryStringValidateRequest(mux goahttp.Muxer, decoder func(*htt
 		var (
 			q   string
 			err error
+			qp  = r.URL.Query()
 		)
-		q = r.URL.Query().Get("q")
+		q = qp.Get("q")
 		if q == "" {
 			err = goa.MergeErrors(err, goa.MissingFieldError("q", "query string"))
 		}
@@ -596,8 +616,9 @@ func DecodeMethodQueryStringNotRequiredValidateRequest(mux goahttp.Muxer, decode
 		var (
 			q   *string
 			err error
+			qp  = r.URL.Query()
 		)
-		qRaw := r.URL.Query().Get("q")
+		qRaw := qp.Get("q")
 		if qRaw != "" {
 			q = &qRaw
 		}
@@ -621,10 +642,11 @@ var PayloadQueryBytesDecodeCode = `// DecodeMethodQueryBytesRequest returns a de
 func DecodeMethodQueryBytesRequest(mux goahttp.Muxer, decoder func(*http.Request) goahttp.Decoder) func(*http.Request) (any, error) {
 	return func(r *http.Request) (any, error) {
 		var (
-			q []byte
+			q  []byte
+			qp = r.URL.Query()
 		)
 		{
-			qRaw := r.URL.Query().Get("q")
+			qRaw := qp.Get("q")
 			if qRaw != "" {
 				q = []byte(qRaw)
 			}
@@ -643,9 +665,10 @@ func DecodeMethodQueryBytesValidateRequest(mux goahttp.Muxer, decoder func(*http
 		var (
 			q   []byte
 			err error
+			qp  = r.URL.Query()
 		)
 		{
-			qRaw := r.URL.Query().Get("q")
+			qRaw := qp.Get("q")
 			if qRaw == "" {
 				err = goa.MergeErrors(err, goa.MissingFieldError("q", "query string"))
 			}
@@ -669,9 +692,10 @@ var PayloadQueryAnyDecodeCode = `// DecodeMethodQueryAnyRequest returns a decode
 func DecodeMethodQueryAnyRequest(mux goahttp.Muxer, decoder func(*http.Request) goahttp.Decoder) func(*http.Request) (any, error) {
 	return func(r *http.Request) (any, error) {
 		var (
-			q any
+			q  any
+			qp = r.URL.Query()
 		)
-		qRaw := r.URL.Query().Get("q")
+		qRaw := qp.Get("q")
 		if qRaw != "" {
 			q = qRaw
 		}
@@ -689,8 +713,9 @@ func DecodeMethodQueryAnyValidateRequest(mux goahttp.Muxer, decoder func(*http.R
 		var (
 			q   any
 			err error
+			qp  = r.URL.Query()
 		)
-		q = r.URL.Query().Get("q")
+		q = qp.Get("q")
 		if q == "" {
 			err = goa.MergeErrors(err, goa.MissingFieldError("q", "query string"))
 		}
@@ -714,9 +739,10 @@ func DecodeMethodQueryArrayBoolRequest(mux goahttp.Muxer, decoder func(*http.Req
 		var (
 			q   []bool
 			err error
+			qp  = r.URL.Query()
 		)
 		{
-			qRaw := r.URL.Query()["q"]
+			qRaw := qp["q"]
 			if qRaw != nil {
 				q = make([]bool, len(qRaw))
 				for i, rv := range qRaw {
@@ -746,9 +772,10 @@ func DecodeMethodQueryArrayBoolValidateRequest(mux goahttp.Muxer, decoder func(*
 		var (
 			q   []bool
 			err error
+			qp  = r.URL.Query()
 		)
 		{
-			qRaw := r.URL.Query()["q"]
+			qRaw := qp["q"]
 			if qRaw == nil {
 				err = goa.MergeErrors(err, goa.MissingFieldError("q", "query string"))
 			}
@@ -786,9 +813,10 @@ func DecodeMethodQueryArrayIntRequest(mux goahttp.Muxer, decoder func(*http.Requ
 		var (
 			q   []int
 			err error
+			qp  = r.URL.Query()
 		)
 		{
-			qRaw := r.URL.Query()["q"]
+			qRaw := qp["q"]
 			if qRaw != nil {
 				q = make([]int, len(qRaw))
 				for i, rv := range qRaw {
@@ -817,9 +845,10 @@ func DecodeMethodQueryArrayIntValidateRequest(mux goahttp.Muxer, decoder func(*h
 		var (
 			q   []int
 			err error
+			qp  = r.URL.Query()
 		)
 		{
-			qRaw := r.URL.Query()["q"]
+			qRaw := qp["q"]
 			if qRaw == nil {
 				err = goa.MergeErrors(err, goa.MissingFieldError("q", "query string"))
 			}
@@ -857,9 +886,10 @@ func DecodeMethodQueryArrayInt32Request(mux goahttp.Muxer, decoder func(*http.Re
 		var (
 			q   []int32
 			err error
+			qp  = r.URL.Query()
 		)
 		{
-			qRaw := r.URL.Query()["q"]
+			qRaw := qp["q"]
 			if qRaw != nil {
 				q = make([]int32, len(qRaw))
 				for i, rv := range qRaw {
@@ -889,9 +919,10 @@ func DecodeMethodQueryArrayInt32ValidateRequest(mux goahttp.Muxer, decoder func(
 		var (
 			q   []int32
 			err error
+			qp  = r.URL.Query()
 		)
 		{
-			qRaw := r.URL.Query()["q"]
+			qRaw := qp["q"]
 			if qRaw == nil {
 				err = goa.MergeErrors(err, goa.MissingFieldError("q", "query string"))
 			}
@@ -929,9 +960,10 @@ func DecodeMethodQueryArrayInt64Request(mux goahttp.Muxer, decoder func(*http.Re
 		var (
 			q   []int64
 			err error
+			qp  = r.URL.Query()
 		)
 		{
-			qRaw := r.URL.Query()["q"]
+			qRaw := qp["q"]
 			if qRaw != nil {
 				q = make([]int64, len(qRaw))
 				for i, rv := range qRaw {
@@ -961,9 +993,10 @@ func DecodeMethodQueryArrayInt64ValidateRequest(mux goahttp.Muxer, decoder func(
 		var (
 			q   []int64
 			err error
+			qp  = r.URL.Query()
 		)
 		{
-			qRaw := r.URL.Query()["q"]
+			qRaw := qp["q"]
 			if qRaw == nil {
 				err = goa.MergeErrors(err, goa.MissingFieldError("q", "query string"))
 			}
@@ -1001,9 +1034,10 @@ func DecodeMethodQueryArrayUIntRequest(mux goahttp.Muxer, decoder func(*http.Req
 		var (
 			q   []uint
 			err error
+			qp  = r.URL.Query()
 		)
 		{
-			qRaw := r.URL.Query()["q"]
+			qRaw := qp["q"]
 			if qRaw != nil {
 				q = make([]uint, len(qRaw))
 				for i, rv := range qRaw {
@@ -1033,9 +1067,10 @@ func DecodeMethodQueryArrayUIntValidateRequest(mux goahttp.Muxer, decoder func(*
 		var (
 			q   []uint
 			err error
+			qp  = r.URL.Query()
 		)
 		{
-			qRaw := r.URL.Query()["q"]
+			qRaw := qp["q"]
 			if qRaw == nil {
 				err = goa.MergeErrors(err, goa.MissingFieldError("q", "query string"))
 			}
@@ -1073,9 +1108,10 @@ func DecodeMethodQueryArrayUInt32Request(mux goahttp.Muxer, decoder func(*http.R
 		var (
 			q   []uint32
 			err error
+			qp  = r.URL.Query()
 		)
 		{
-			qRaw := r.URL.Query()["q"]
+			qRaw := qp["q"]
 			if qRaw != nil {
 				q = make([]uint32, len(qRaw))
 				for i, rv := range qRaw {
@@ -1105,9 +1141,10 @@ func DecodeMethodQueryArrayUInt32ValidateRequest(mux goahttp.Muxer, decoder func
 		var (
 			q   []uint32
 			err error
+			qp  = r.URL.Query()
 		)
 		{
-			qRaw := r.URL.Query()["q"]
+			qRaw := qp["q"]
 			if qRaw == nil {
 				err = goa.MergeErrors(err, goa.MissingFieldError("q", "query string"))
 			}
@@ -1145,9 +1182,10 @@ func DecodeMethodQueryArrayUInt64Request(mux goahttp.Muxer, decoder func(*http.R
 		var (
 			q   []uint64
 			err error
+			qp  = r.URL.Query()
 		)
 		{
-			qRaw := r.URL.Query()["q"]
+			qRaw := qp["q"]
 			if qRaw != nil {
 				q = make([]uint64, len(qRaw))
 				for i, rv := range qRaw {
@@ -1177,9 +1215,10 @@ func DecodeMethodQueryArrayUInt64ValidateRequest(mux goahttp.Muxer, decoder func
 		var (
 			q   []uint64
 			err error
+			qp  = r.URL.Query()
 		)
 		{
-			qRaw := r.URL.Query()["q"]
+			qRaw := qp["q"]
 			if qRaw == nil {
 				err = goa.MergeErrors(err, goa.MissingFieldError("q", "query string"))
 			}
@@ -1217,9 +1256,10 @@ func DecodeMethodQueryArrayFloat32Request(mux goahttp.Muxer, decoder func(*http.
 		var (
 			q   []float32
 			err error
+			qp  = r.URL.Query()
 		)
 		{
-			qRaw := r.URL.Query()["q"]
+			qRaw := qp["q"]
 			if qRaw != nil {
 				q = make([]float32, len(qRaw))
 				for i, rv := range qRaw {
@@ -1249,9 +1289,10 @@ func DecodeMethodQueryArrayFloat32ValidateRequest(mux goahttp.Muxer, decoder fun
 		var (
 			q   []float32
 			err error
+			qp  = r.URL.Query()
 		)
 		{
-			qRaw := r.URL.Query()["q"]
+			qRaw := qp["q"]
 			if qRaw == nil {
 				err = goa.MergeErrors(err, goa.MissingFieldError("q", "query string"))
 			}
@@ -1289,9 +1330,10 @@ func DecodeMethodQueryArrayFloat64Request(mux goahttp.Muxer, decoder func(*http.
 		var (
 			q   []float64
 			err error
+			qp  = r.URL.Query()
 		)
 		{
-			qRaw := r.URL.Query()["q"]
+			qRaw := qp["q"]
 			if qRaw != nil {
 				q = make([]float64, len(qRaw))
 				for i, rv := range qRaw {
@@ -1321,9 +1363,10 @@ func DecodeMethodQueryArrayFloat64ValidateRequest(mux goahttp.Muxer, decoder fun
 		var (
 			q   []float64
 			err error
+			qp  = r.URL.Query()
 		)
 		{
-			qRaw := r.URL.Query()["q"]
+			qRaw := qp["q"]
 			if qRaw == nil {
 				err = goa.MergeErrors(err, goa.MissingFieldError("q", "query string"))
 			}
@@ -1359,9 +1402,10 @@ var PayloadQueryArrayStringDecodeCode = `// DecodeMethodQueryArrayStringRequest
 func DecodeMethodQueryArrayStringRequest(mux goahttp.Muxer, decoder func(*http.Request) goahttp.Decoder) func(*http.Request) (any, error) {
 	return func(r *http.Request) (any, error) {
 		var (
-			q []string
+			q  []string
+			qp = r.URL.Query()
 		)
-		q = r.URL.Query()["q"]
+		q = qp["q"]
 		payload := NewMethodQueryArrayStringPayload(q)
 
 		return payload, nil
@@ -1377,8 +1421,9 @@ func DecodeMethodQueryArrayStringValidateRequest(mux goahttp.Muxer, decoder func
 		var (
 			q   []string
 			err error
+			qp  = r.URL.Query()
 		)
-		q = r.URL.Query()["q"]
+		q = qp["q"]
 		if q == nil {
 			err = goa.MergeErrors(err, goa.MissingFieldError("q", "query string"))
 		}
@@ -1405,10 +1450,11 @@ var PayloadQueryArrayBytesDecodeCode = `// DecodeMethodQueryArrayBytesRequest re
 func DecodeMethodQueryArrayBytesRequest(mux goahttp.Muxer, decoder func(*http.Request) goahttp.Decoder) func(*http.Request) (any, error) {
 	return func(r *http.Request) (any, error) {
 		var (
-			q [][]byte
+			q  [][]byte
+			qp = r.URL.Query()
 		)
 		{
-			qRaw := r.URL.Query()["q"]
+			qRaw := qp["q"]
 			if qRaw != nil {
 				q = make([][]byte, len(qRaw))
 				for i, rv := range qRaw {
@@ -1431,9 +1477,10 @@ func DecodeMethodQueryArrayBytesValidateRequest(mux goahttp.Muxer, decoder func(
 		var (
 			q   [][]byte
 			err error
+			qp  = r.URL.Query()
 		)
 		{
-			qRaw := r.URL.Query()["q"]
+			qRaw := qp["q"]
 			if qRaw == nil {
 				err = goa.MergeErrors(err, goa.MissingFieldError("q", "query string"))
 			}
@@ -1465,10 +1512,11 @@ var PayloadQueryArrayAnyDecodeCode = `// DecodeMethodQueryArrayAnyRequest return
 func DecodeMethodQueryArrayAnyRequest(mux goahttp.Muxer, decoder func(*http.Request) goahttp.Decoder) func(*http.Request) (any, error) {
 	return func(r *http.Request) (any, error) {
 		var (
-			q []any
+			q  []any
+			qp = r.URL.Query()
 		)
 		{
-			qRaw := r.URL.Query()["q"]
+			qRaw := qp["q"]
 			if qRaw != nil {
 				q = make([]any, len(qRaw))
 				for i, rv := range qRaw {
@@ -1490,9 +1538,10 @@ func DecodeMethodQueryArrayAnyValidateRequest(mux goahttp.Muxer, decoder func(*h
 		var (
 			q   []any
 			err error
+			qp  = r.URL.Query()
 		)
 		{
-			qRaw := r.URL.Query()["q"]
+			qRaw := qp["q"]
 			if qRaw == nil {
 				err = goa.MergeErrors(err, goa.MissingFieldError("q", "query string"))
 			}
@@ -1524,12 +1573,12 @@ var PayloadQueryMapStringStringDecodeCode = `// DecodeMethodQueryMapStringString
 func DecodeMethodQueryMapStringStringRequest(mux goahttp.Muxer, decoder func(*http.Request) goahttp.Decoder) func(*http.Request) (any, error) {
 	return func(r *http.Request) (any, error) {
 		var (
-			q map[string]string
+			q  map[string]string
+			qp = r.URL.Query()
 		)
 		{
-			qRaw := r.URL.Query()
-			if len(qRaw) != 0 {
-				for keyRaw, valRaw := range qRaw {
+			if len(qp) != 0 {
+				for keyRaw, valRaw := range qp {
 					if strings.HasPrefix(keyRaw, "q[") {
 						if q == nil {
 							q = make(map[string]string)
@@ -1560,13 +1609,13 @@ func DecodeMethodQueryMapStringStringValidateRequest(mux goahttp.Muxer, decoder
 		var (
 			q   map[string]string
 			err error
+			qp  = r.URL.Query()
 		)
 		{
-			qRaw := r.URL.Query()
-			if len(qRaw) == 0 {
+			if len(qp) == 0 {
 				err = goa.MergeErrors(err, goa.MissingFieldError("q", "query string"))
 			}
-			for keyRaw, valRaw := range qRaw {
+			for keyRaw, valRaw := range qp {
 				if strings.HasPrefix(keyRaw, "q[") {
 					if q == nil {
 						q = make(map[string]string)
@@ -1609,11 +1658,11 @@ func DecodeMethodQueryMapStringBoolRequest(mux goahttp.Muxer, decoder func(*http
 		var (
 			q   map[string]bool
 			err error
+			qp  = r.URL.Query()
 		)
 		{
-			qRaw := r.URL.Query()
-			if len(qRaw) != 0 {
-				for keyRaw, valRaw := range qRaw {
+			if len(qp) != 0 {
+				for keyRaw, valRaw := range qp {
 					if strings.HasPrefix(keyRaw, "q[") {
 						if q == nil {
 							q = make(map[string]bool)
@@ -1656,13 +1705,13 @@ func DecodeMethodQueryMapStringBoolValidateRequest(mux goahttp.Muxer, decoder fu
 		var (
 			q   map[string]bool
 			err error
+			qp  = r.URL.Query()
 		)
 		{
-			qRaw := r.URL.Query()
-			if len(qRaw) == 0 {
+			if len(qp) == 0 {
 				err = goa.MergeErrors(err, goa.MissingFieldError("q", "query string"))
 			}
-			for keyRaw, valRaw := range qRaw {
+			for keyRaw, valRaw := range qp {
 				if strings.HasPrefix(keyRaw, "q[") {
 					if q == nil {
 						q = make(map[string]bool)
@@ -1714,11 +1763,11 @@ func DecodeMethodQueryMapBoolStringRequest(mux goahttp.Muxer, decoder func(*http
 		var (
 			q   map[bool]string
 			err error
+			qp  = r.URL.Query()
 		)
 		{
-			qRaw := r.URL.Query()
-			if len(qRaw) != 0 {
-				for keyRaw, valRaw := range qRaw {
+			if len(qp) != 0 {
+				for keyRaw, valRaw := range qp {
 					if strings.HasPrefix(keyRaw, "q[") {
 						if q == nil {
 							q = make(map[bool]string)
@@ -1757,13 +1806,13 @@ func DecodeMethodQueryMapBoolStringValidateRequest(mux goahttp.Muxer, decoder fu
 		var (
 			q   map[bool]string
 			err error
+			qp  = r.URL.Query()
 		)
 		{
-			qRaw := r.URL.Query()
-			if len(qRaw) == 0 {
+			if len(qp) == 0 {
 				err = goa.MergeErrors(err, goa.MissingFieldError("q", "query string"))
 			}
-			for keyRaw, valRaw := range qRaw {
+			for keyRaw, valRaw := range qp {
 				if strings.HasPrefix(keyRaw, "q[") {
 					if q == nil {
 						q = make(map[bool]string)
@@ -1811,11 +1860,11 @@ func DecodeMethodQueryMapBoolBoolRequest(mux goahttp.Muxer, decoder func(*http.R
 		var (
 			q   map[bool]bool
 			err error
+			qp  = r.URL.Query()
 		)
 		{
-			qRaw := r.URL.Query()
-			if len(qRaw) != 0 {
-				for keyRaw, valRaw := range qRaw {
+			if len(qp) != 0 {
+				for keyRaw, valRaw := range qp {
 					if strings.HasPrefix(keyRaw, "q[") {
 						if q == nil {
 							q = make(map[bool]bool)
@@ -1863,13 +1912,13 @@ func DecodeMethodQueryMapBoolBoolValidateRequest(mux goahttp.Muxer, decoder func
 		var (
 			q   map[bool]bool
 			err error
+			qp  = r.URL.Query()
 		)
 		{
-			qRaw := r.URL.Query()
-			if len(qRaw) == 0 {
+			if len(qp) == 0 {
 				err = goa.MergeErrors(err, goa.MissingFieldError("q", "query string"))
 			}
-			for keyRaw, valRaw := range qRaw {
+			for keyRaw, valRaw := range qp {
 				if strings.HasPrefix(keyRaw, "q[") {
 					if q == nil {
 						q = make(map[bool]bool)
@@ -1925,12 +1974,12 @@ var PayloadQueryMapStringArrayStringDecodeCode = `// DecodeMethodQueryMapStringA
 func DecodeMethodQueryMapStringArrayStringRequest(mux goahttp.Muxer, decoder func(*http.Request) goahttp.Decoder) func(*http.Request) (any, error) {
 	return func(r *http.Request) (any, error) {
 		var (
-			q map[string][]string
+			q  map[string][]string
+			qp = r.URL.Query()
 		)
 		{
-			qRaw := r.URL.Query()
-			if len(qRaw) != 0 {
-				for keyRaw, valRaw := range qRaw {
+			if len(qp) != 0 {
+				for keyRaw, valRaw := range qp {
 					if strings.HasPrefix(keyRaw, "q[") {
 						if q == nil {
 							q = make(map[string][]string)
@@ -1961,13 +2010,13 @@ func DecodeMethodQueryMapStringArrayStringValidateRequest(mux goahttp.Muxer, dec
 		var (
 			q   map[string][]string
 			err error
+			qp  = r.URL.Query()
 		)
 		{
-			qRaw := r.URL.Query()
-			if len(qRaw) == 0 {
+			if len(qp) == 0 {
 				err = goa.MergeErrors(err, goa.MissingFieldError("q", "query string"))
 			}
-			for keyRaw, valRaw := range qRaw {
+			for keyRaw, valRaw := range qp {
 				if strings.HasPrefix(keyRaw, "q[") {
 					if q == nil {
 						q = make(map[string][]string)
@@ -2011,11 +2060,11 @@ func DecodeMethodQueryMapStringArrayBoolRequest(mux goahttp.Muxer, decoder func(
 		var (
 			q   map[string][]bool
 			err error
+			qp  = r.URL.Query()
 		)
 		{
-			qRaw := r.URL.Query()
-			if len(qRaw) != 0 {
-				for keyRaw, valRaw := range qRaw {
+			if len(qp) != 0 {
+				for keyRaw, valRaw := range qp {
 					if strings.HasPrefix(keyRaw, "q[") {
 						if q == nil {
 							q = make(map[string][]bool)
@@ -2060,13 +2109,13 @@ func DecodeMethodQueryMapStringArrayBoolValidateRequest(mux goahttp.Muxer, decod
 		var (
 			q   map[string][]bool
 			err error
+			qp  = r.URL.Query()
 		)
 		{
-			qRaw := r.URL.Query()
-			if len(qRaw) == 0 {
+			if len(qp) == 0 {
 				err = goa.MergeErrors(err, goa.MissingFieldError("q", "query string"))
 			}
-			for keyRaw, valRaw := range qRaw {
+			for keyRaw, valRaw := range qp {
 				if strings.HasPrefix(keyRaw, "q[") {
 					if q == nil {
 						q = make(map[string][]bool)
@@ -2121,11 +2170,11 @@ func DecodeMethodQueryMapBoolArrayStringRequest(mux goahttp.Muxer, decoder func(
 		var (
 			q   map[bool][]string
 			err error
+			qp  = r.URL.Query()
 		)
 		{
-			qRaw := r.URL.Query()
-			if len(qRaw) != 0 {
-				for keyRaw, valRaw := range qRaw {
+			if len(qp) != 0 {
+				for keyRaw, valRaw := range qp {
 					if strings.HasPrefix(keyRaw, "q[") {
 						if q == nil {
 							q = make(map[bool][]string)
@@ -2164,11 +2213,11 @@ func DecodeMethodQueryMapBoolArrayStringValidateRequest(mux goahttp.Muxer, decod
 		var (
 			q   map[bool][]string
 			err error
+			qp  = r.URL.Query()
 		)
 		{
-			qRaw := r.URL.Query()
-			if len(qRaw) != 0 {
-				for keyRaw, valRaw := range qRaw {
+			if len(qp) != 0 {
+				for keyRaw, valRaw := range qp {
 					if strings.HasPrefix(keyRaw, "q[") {
 						if q == nil {
 							q = make(map[bool][]string)
@@ -2217,11 +2266,11 @@ func DecodeMethodQueryMapBoolArrayBoolRequest(mux goahttp.Muxer, decoder func(*h
 		var (
 			q   map[bool][]bool
 			err error
+			qp  = r.URL.Query()
 		)
 		{
-			qRaw := r.URL.Query()
-			if len(qRaw) != 0 {
-				for keyRaw, valRaw := range qRaw {
+			if len(qp) != 0 {
+				for keyRaw, valRaw := range qp {
 					if strings.HasPrefix(keyRaw, "q[") {
 						if q == nil {
 							q = make(map[bool][]bool)
@@ -2271,13 +2320,13 @@ func DecodeMethodQueryMapBoolArrayBoolValidateRequest(mux goahttp.Muxer, decoder
 		var (
 			q   map[bool][]bool
 			err error
+			qp  = r.URL.Query()
 		)
 		{
-			qRaw := r.URL.Query()
-			if len(qRaw) == 0 {
+			if len(qp) == 0 {
 				err = goa.MergeErrors(err, goa.MissingFieldError("q", "query string"))
 			}
-			for keyRaw, valRaw := range qRaw {
+			for keyRaw, valRaw := range qp {
 				if strings.HasPrefix(keyRaw, "q[") {
 					if q == nil {
 						q = make(map[bool][]bool)
@@ -2337,8 +2386,9 @@ func DecodeMethodQueryPrimitiveStringValidateRequest(mux goahttp.Muxer, decoder
 		var (
 			q   string
 			err error
+			qp  = r.URL.Query()
 		)
-		q = r.URL.Query().Get("q")
+		q = qp.Get("q")
 		if q == "" {
 			err = goa.MergeErrors(err, goa.MissingFieldError("q", "query string"))
 		}
@@ -2363,9 +2413,10 @@ func DecodeMethodQueryPrimitiveBoolValidateRequest(mux goahttp.Muxer, decoder fu
 		var (
 			q   bool
 			err error
+			qp  = r.URL.Query()
 		)
 		{
-			qRaw := r.URL.Query().Get("q")
+			qRaw := qp.Get("q")
 			if qRaw == "" {
 				err = goa.MergeErrors(err, goa.MissingFieldError("q", "query string"))
 			}
@@ -2396,8 +2447,9 @@ func DecodeMethodQueryPrimitiveArrayStringValidateRequest(mux goahttp.Muxer, dec
 		var (
 			q   []string
 			err error
+			qp  = r.URL.Query()
 		)
-		q = r.URL.Query()["q"]
+		q = qp["q"]
 		if q == nil {
 			err = goa.MergeErrors(err, goa.MissingFieldError("q", "query string"))
 		}
@@ -2427,9 +2479,10 @@ func DecodeMethodQueryPrimitiveArrayBoolValidateRequest(mux goahttp.Muxer, decod
 		var (
 			q   []bool
 			err error
+			qp  = r.URL.Query()
 		)
 		{
-			qRaw := r.URL.Query()["q"]
+			qRaw := qp["q"]
 			if qRaw == nil {
 				err = goa.MergeErrors(err, goa.MissingFieldError("q", "query string"))
 			}
@@ -2469,13 +2522,13 @@ func DecodeMethodQueryPrimitiveMapStringArrayStringValidateRequest(mux goahttp.M
 		var (
 			q   map[string][]string
 			err error
+			qp  = r.URL.Query()
 		)
 		{
-			qRaw := r.URL.Query()
-			if len(qRaw) == 0 {
+			if len(qp) == 0 {
 				err = goa.MergeErrors(err, goa.MissingFieldError("q", "query string"))
 			}
-			for keyRaw, valRaw := range qRaw {
+			for keyRaw, valRaw := range qp {
 				if strings.HasPrefix(keyRaw, "q[") {
 					if q == nil {
 						q = make(map[string][]string)
@@ -2520,13 +2573,13 @@ func DecodeMethodQueryPrimitiveMapStringBoolValidateRequest(mux goahttp.Muxer, d
 		var (
 			q   map[string]bool
 			err error
+			qp  = r.URL.Query()
 		)
 		{
-			qRaw := r.URL.Query()
-			if len(qRaw) == 0 {
+			if len(qp) == 0 {
 				err = goa.MergeErrors(err, goa.MissingFieldError("q", "query string"))
 			}
-			for keyRaw, valRaw := range qRaw {
+			for keyRaw, valRaw := range qp {
 				if strings.HasPrefix(keyRaw, "q[") {
 					if q == nil {
 						q = make(map[string]bool)
@@ -2577,13 +2630,13 @@ func DecodeMethodQueryPrimitiveMapBoolArrayBoolValidateRequest(mux goahttp.Muxer
 		var (
 			q   map[bool][]bool
 			err error
+			qp  = r.URL.Query()
 		)
 		{
-			qRaw := r.URL.Query()
-			if len(qRaw) == 0 {
+			if len(qp) == 0 {
 				err = goa.MergeErrors(err, goa.MissingFieldError("q", "query string"))
 			}
-			for keyRaw, valRaw := range qRaw {
+			for keyRaw, valRaw := range qp {
 				if strings.HasPrefix(keyRaw, "q[") {
 					if q == nil {
 						q = make(map[bool][]bool)
@@ -2648,13 +2701,13 @@ func DecodeMethodQueryMapStringMapIntStringValidateRequest(mux goahttp.Muxer, de
 		var (
 			q   map[string]map[int]string
 			err error
+			qp  = r.URL.Query()
 		)
 		{
-			qRaw := r.URL.Query()
-			if len(qRaw) == 0 {
+			if len(qp) == 0 {
 				err = goa.MergeErrors(err, goa.MissingFieldError("q", "query string"))
 			}
-			for keyRaw, valRaw := range qRaw {
+			for keyRaw, valRaw := range qp {
 				if strings.HasPrefix(keyRaw, "q[") {
 					if q == nil {
 						q = make(map[string]map[int]string)
@@ -2707,13 +2760,13 @@ func DecodeMethodQueryMapIntMapStringArrayIntValidateRequest(mux goahttp.Muxer,
 		var (
 			q   map[int]map[string][]int
 			err error
+			qp  = r.URL.Query()
 		)
 		{
-			qRaw := r.URL.Query()
-			if len(qRaw) == 0 {
+			if len(qp) == 0 {
 				err = goa.MergeErrors(err, goa.MissingFieldError("q", "query string"))
 			}
-			for keyRaw, valRaw := range qRaw {
+			for keyRaw, valRaw := range qp {
 				if strings.HasPrefix(keyRaw, "q[") {
 					if q == nil {
 						q = make(map[int]map[string][]int)
@@ -2775,8 +2828,9 @@ func DecodeMethodQueryStringMappedRequest(mux goahttp.Muxer, decoder func(*http.
 	return func(r *http.Request) (any, error) {
 		var (
 			query *string
+			qp    = r.URL.Query()
 		)
-		queryRaw := r.URL.Query().Get("q")
+		queryRaw := qp.Get("q")
 		if queryRaw != "" {
 			query = &queryRaw
 		}
@@ -2792,9 +2846,10 @@ var PayloadQueryStringDefaultDecodeCode = `// DecodeMethodQueryStringDefaultRequ
 func DecodeMethodQueryStringDefaultRequest(mux goahttp.Muxer, decoder func(*http.Request) goahttp.Decoder) func(*http.Request) (any, error) {
 	return func(r *http.Request) (any, error) {
 		var (
-			q string
+			q  string
+			qp = r.URL.Query()
 		)
-		qRaw := r.URL.Query().Get("q")
+		qRaw := qp.Get("q")
 		if qRaw != "" {
 			q = qRaw
 		} else {
@@ -2813,9 +2868,10 @@ var PayloadQueryStringSliceDefaultDecodeCode = `// DecodeMethodQueryStringSliceD
 func DecodeMethodQueryStringSliceDefaultRequest(mux goahttp.Muxer, decoder func(*http.Request) goahttp.Decoder) func(*http.Request) (any, error) {
 	return func(r *http.Request) (any, error) {
 		var (
-			q []string
+			q  []string
+			qp = r.URL.Query()
 		)
-		q = r.URL.Query()["q"]
+		q = qp["q"]
 		if q == nil {
 			q = []string{"hello", "goodbye"}
 		}
@@ -2834,8 +2890,9 @@ func DecodeMethodQueryStringDefaultValidateRequest(mux goahttp.Muxer, decoder fu
 		var (
 			q   string
 			err error
+			qp  = r.URL.Query()
 		)
-		qRaw := r.URL.Query().Get("q")
+		qRaw := qp.Get("q")
 		if qRaw != "" {
 			q = qRaw
 		} else {
@@ -2862,8 +2919,9 @@ func DecodeMethodQueryPrimitiveStringDefaultRequest(mux goahttp.Muxer, decoder f
 		var (
 			q   string
 			err error
+			qp  = r.URL.Query()
 		)
-		q = r.URL.Query().Get("q")
+		q = qp.Get("q")
 		if q == "" {
 			err = goa.MergeErrors(err, goa.MissingFieldError("q", "query string"))
 		}
@@ -2883,9 +2941,10 @@ var PayloadExtendedQueryStringDecodeCode = `// DecodeMethodQueryStringExtendedPa
 func DecodeMethodQueryStringExtendedPayloadRequest(mux goahttp.Muxer, decoder func(*http.Request) goahttp.Decoder) func(*http.Request) (any, error) {
 	return func(r *http.Request) (any, error) {
 		var (
-			q *string
+			q  *string
+			qp = r.URL.Query()
 		)
-		qRaw := r.URL.Query().Get("q")
+		qRaw := qp.Get("q")
 		if qRaw != "" {
 			q = &qRaw
 		}
@@ -3211,6 +3270,34 @@ func DecodeMethodHeaderArrayStringValidateRequest(mux goahttp.Muxer, decoder fun
 }
 `
 
+var PayloadHeaderInheritedDecodeCode = `// DecodeMethodHeaderInheritedRequest returns a decoder for requests sent to
+// the ServiceHeaderInherited MethodHeaderInherited endpoint.
+func DecodeMethodHeaderInheritedRequest(mux goahttp.Muxer, decoder func(*http.Request) goahttp.Decoder) func(*http.Request) (any, error) {
+	return func(r *http.Request) (any, error) {
+		var (
+			tenant string
+			key    *string
+			err    error
+		)
+		tenant = r.Header.Get("X-Tenant-Id")
+		if tenant == "" {
+			err = goa.MergeErrors(err, goa.MissingFieldError("tenant", "header"))
+		}
+		err = goa.MergeErrors(err, goa.ValidatePattern("tenant", tenant, "^[a-z]+$"))
+		keyRaw := r.Header.Get("Idempotency-Key")
+		if keyRaw != "" {
+			key = &keyRaw
+		}
+		if err != nil {
+			return nil, err
+		}
+		payload := NewMethodHeaderInheritedPayload(tenant, key)
+
+		return payload, nil
+	}
+}
+`
+
 var PayloadHeaderPrimitiveStringValidateDecodeCode = `// DecodeMethodHeaderPrimitiveStringValidateRequest returns a decoder for
 // requests sent to the ServiceHeaderPrimitiveStringValidate
 // MethodHeaderPrimitiveStringValidate endpoint.
@@ -3710,6 +3797,36 @@ func DecodeMethodBodyUserRequest(mux goahttp.Muxer, decoder func(*http.Request)
 }
 `
 
+var PayloadBodyUserMigrationDecodeCode = `// DecodeMethodBodyUserRequest returns a decoder for requests sent to the
+// ServiceBodyUser MethodBodyUser endpoint.
+func DecodeMethodBodyUserRequest(mux goahttp.Muxer, decoder func(*http.Request) goahttp.Decoder) func(*http.Request) (any, error) {
+	return func(r *http.Request) (any, error) {
+		var (
+			body MethodBodyUserRequestBody
+			err  error
+		)
+		err = decoder(r).Decode(&body)
+		if err != nil {
+			if err == io.EOF {
+				return nil, goa.MissingPayloadError()
+			}
+			return nil, goa.DecodePayloadError(err.Error())
+		}
+		if body.B == nil && goahttp.UseMigrationDefault(r.Context(), "b", "2024-12-31") {
+			var v int32 = 42
+			body.B = &v
+		}
+		err = ValidateMethodBodyUserRequestBody(&body)
+		if err != nil {
+			return nil, err
+		}
+		payload := NewMethodBodyUserPayloadType(&body)
+
+		return payload, nil
+	}
+}
+`
+
 var PayloadBodyNestedUserDecodeCode = `// DecodeMethodBodyUserRequest returns a decoder for requests sent to the
 // ServiceBodyUser MethodBodyUser endpoint.
 func DecodeMethodBodyUserRequest(mux goahttp.Muxer, decoder func(*http.Request) goahttp.Decoder) func(*http.Request) (any, error) {
@@ -4363,6 +4480,15 @@ var PayloadBodyQueryObjectDecodeCode = `// DecodeMethodBodyQueryObjectRequest re
 // the ServiceBodyQueryObject MethodBodyQueryObject endpoint.
 func DecodeMethodBodyQueryObjectRequest(mux goahttp.Muxer, decoder func(*http.Request) goahttp.Decoder) func(*http.Request) (any, error) {
 	return func(r *http.Request) (any, error) {
+		var (
+			b  *string
+			qp = r.URL.Query()
+		)
+		bRaw := qp.Get("b")
+		if bRaw != "" {
+			b = &bRaw
+		}
+
 		var (
 			body MethodBodyQueryObjectRequestBody
 			err  error
@@ -4374,14 +4500,6 @@ func DecodeMethodBodyQueryObjectRequest(mux goahttp.Muxer, decoder func(*http.Re
 			}
 			return nil, goa.DecodePayloadError(err.Error())
 		}
-
-		var (
-			b *string
-		)
-		bRaw := r.URL.Query().Get("b")
-		if bRaw != "" {
-			b = &bRaw
-		}
 		payload := NewMethodBodyQueryObjectPayload(&body, b)
 
 		return payload, nil
@@ -4395,9 +4513,20 @@ var PayloadBodyQueryObjectValidateDecodeCode = `// DecodeMethodBodyQueryObjectVa
 func DecodeMethodBodyQueryObjectValidateRequest(mux goahttp.Muxer, decoder func(*http.Request) goahttp.Decoder) func(*http.Request) (any, error) {
 	return func(r *http.Request) (any, error) {
 		var (
-			body MethodBodyQueryObjectValidateRequestBody
-			err  error
+			b   string
+			err error
+			qp  = r.URL.Query()
 		)
+		b = qp.Get("b")
+		if b == "" {
+			err = goa.MergeErrors(err, goa.MissingFieldError("b", "query string"))
+		}
+		err = goa.MergeErrors(err, goa.ValidatePattern("b", b, "patternb"))
+		if err != nil {
+			return nil, err
+		}
+
+		var body MethodBodyQueryObjectValidateRequestBody
 		err = decoder(r).Decode(&body)
 		if err != nil {
 			if err == io.EOF {
@@ -4409,18 +4538,6 @@ func DecodeMethodBodyQueryObjectValidateRequest(mux goahttp.Muxer, decoder func(
 		if err != nil {
 			return nil, err
 		}
-
-		var (
-			b string
-		)
-		b = r.URL.Query().Get("b")
-		if b == "" {
-			err = goa.MergeErrors(err, goa.MissingFieldError("b", "query string"))
-		}
-		err = goa.MergeErrors(err, goa.ValidatePattern("b", b, "patternb"))
-		if err != nil {
-			return nil, err
-		}
 		payload := NewMethodBodyQueryObjectValidatePayload(&body, b)
 
 		return payload, nil
@@ -4432,6 +4549,15 @@ var PayloadBodyQueryUserDecodeCode = `// DecodeMethodBodyQueryUserRequest return
 // ServiceBodyQueryUser MethodBodyQueryUser endpoint.
 func DecodeMethodBodyQueryUserRequest(mux goahttp.Muxer, decoder func(*http.Request) goahttp.Decoder) func(*http.Request) (any, error) {
 	return func(r *http.Request) (any, error) {
+		var (
+			b  *string
+			qp = r.URL.Query()
+		)
+		bRaw := qp.Get("b")
+		if bRaw != "" {
+			b = &bRaw
+		}
+
 		var (
 			body MethodBodyQueryUserRequestBody
 			err  error
@@ -4443,14 +4569,6 @@ func DecodeMethodBodyQueryUserRequest(mux goahttp.Muxer, decoder func(*http.Requ
 			}
 			return nil, goa.DecodePayloadError(err.Error())
 		}
-
-		var (
-			b *string
-		)
-		bRaw := r.URL.Query().Get("b")
-		if bRaw != "" {
-			b = &bRaw
-		}
 		payload := NewMethodBodyQueryUserPayloadType(&body, b)
 
 		return payload, nil
@@ -4463,9 +4581,20 @@ var PayloadBodyQueryUserValidateDecodeCode = `// DecodeMethodBodyQueryUserValida
 func DecodeMethodBodyQueryUserValidateRequest(mux goahttp.Muxer, decoder func(*http.Request) goahttp.Decoder) func(*http.Request) (any, error) {
 	return func(r *http.Request) (any, error) {
 		var (
-			body MethodBodyQueryUserValidateRequestBody
-			err  error
+			b   string
+			err error
+			qp  = r.URL.Query()
 		)
+		b = qp.Get("b")
+		if b == "" {
+			err = goa.MergeErrors(err, goa.MissingFieldError("b", "query string"))
+		}
+		err = goa.MergeErrors(err, goa.ValidatePattern("b", b, "patternb"))
+		if err != nil {
+			return nil, err
+		}
+
+		var body MethodBodyQueryUserValidateRequestBody
 		err = decoder(r).Decode(&body)
 		if err != nil {
 			if err == io.EOF {
@@ -4477,18 +4606,6 @@ func DecodeMethodBodyQueryUserValidateRequest(mux goahttp.Muxer, decoder func(*h
 		if err != nil {
 			return nil, err
 		}
-
-		var (
-			b string
-		)
-		b = r.URL.Query().Get("b")
-		if b == "" {
-			err = goa.MergeErrors(err, goa.MissingFieldError("b", "query string"))
-		}
-		err = goa.MergeErrors(err, goa.ValidatePattern("b", b, "patternb"))
-		if err != nil {
-			return nil, err
-		}
 		payload := NewMethodBodyQueryUserValidatePayloadType(&body, b)
 
 		return payload, nil
@@ -4500,6 +4617,13 @@ var PayloadBodyPathObjectDecodeCode = `// DecodeMethodBodyPathObjectRequest retu
 // ServiceBodyPathObject MethodBodyPathObject endpoint.
 func DecodeMethodBodyPathObjectRequest(mux goahttp.Muxer, decoder func(*http.Request) goahttp.Decoder) func(*http.Request) (any, error) {
 	return func(r *http.Request) (any, error) {
+		var (
+			b string
+
+			params = mux.Vars(r)
+		)
+		b = params["b"]
+
 		var (
 			body MethodBodyPathObjectRequestBody
 			err  error
@@ -4511,13 +4635,6 @@ func DecodeMethodBodyPathObjectRequest(mux goahttp.Muxer, decoder func(*http.Req
 			}
 			return nil, goa.DecodePayloadError(err.Error())
 		}
-
-		var (
-			b string
-
-			params = mux.Vars(r)
-		)
-		b = params["b"]
 		payload := NewMethodBodyPathObjectPayload(&body, b)
 
 		return payload, nil
@@ -4531,9 +4648,18 @@ var PayloadBodyPathObjectValidateDecodeCode = `// DecodeMethodBodyPathObjectVali
 func DecodeMethodBodyPathObjectValidateRequest(mux goahttp.Muxer, decoder func(*http.Request) goahttp.Decoder) func(*http.Request) (any, error) {
 	return func(r *http.Request) (any, error) {
 		var (
-			body MethodBodyPathObjectValidateRequestBody
-			err  error
+			b   string
+			err error
+
+			params = mux.Vars(r)
 		)
+		b = params["b"]
+		err = goa.MergeErrors(err, goa.ValidatePattern("b", b, "patternb"))
+		if err != nil {
+			return nil, err
+		}
+
+		var body MethodBodyPathObjectValidateRequestBody
 		err = decoder(r).Decode(&body)
 		if err != nil {
 			if err == io.EOF {
@@ -4545,17 +4671,6 @@ func DecodeMethodBodyPathObjectValidateRequest(mux goahttp.Muxer, decoder func(*
 		if err != nil {
 			return nil, err
 		}
-
-		var (
-			b string
-
-			params = mux.Vars(r)
-		)
-		b = params["b"]
-		err = goa.MergeErrors(err, goa.ValidatePattern("b", b, "patternb"))
-		if err != nil {
-			return nil, err
-		}
 		payload := NewMethodBodyPathObjectValidatePayload(&body, b)
 
 		return payload, nil
@@ -4567,6 +4682,13 @@ var PayloadBodyPathUserDecodeCode = `// DecodeMethodBodyPathUserRequest returns
 // ServiceBodyPathUser MethodBodyPathUser endpoint.
 func DecodeMethodBodyPathUserRequest(mux goahttp.Muxer, decoder func(*http.Request) goahttp.Decoder) func(*http.Request) (any, error) {
 	return func(r *http.Request) (any, error) {
+		var (
+			b string
+
+			params = mux.Vars(r)
+		)
+		b = params["b"]
+
 		var (
 			body MethodBodyPathUserRequestBody
 			err  error
@@ -4578,13 +4700,6 @@ func DecodeMethodBodyPathUserRequest(mux goahttp.Muxer, decoder func(*http.Reque
 			}
 			return nil, goa.DecodePayloadError(err.Error())
 		}
-
-		var (
-			b string
-
-			params = mux.Vars(r)
-		)
-		b = params["b"]
 		payload := NewMethodBodyPathUserPayloadType(&body, b)
 
 		return payload, nil
@@ -4597,9 +4712,18 @@ var PayloadBodyPathUserValidateDecodeCode = `// DecodeMethodUserBodyPathValidate
 func DecodeMethodUserBodyPathValidateRequest(mux goahttp.Muxer, decoder func(*http.Request) goahttp.Decoder) func(*http.Request) (any, error) {
 	return func(r *http.Request) (any, error) {
 		var (
-			body MethodUserBodyPathValidateRequestBody
-			err  error
+			b   string
+			err error
+
+			params = mux.Vars(r)
 		)
+		b = params["b"]
+		err = goa.MergeErrors(err, goa.ValidatePattern("b", b, "patternb"))
+		if err != nil {
+			return nil, err
+		}
+
+		var body MethodUserBodyPathValidateRequestBody
 		err = decoder(r).Decode(&body)
 		if err != nil {
 			if err == io.EOF {
@@ -4611,17 +4735,6 @@ func DecodeMethodUserBodyPathValidateRequest(mux goahttp.Muxer, decoder func(*ht
 		if err != nil {
 			return nil, err
 		}
-
-		var (
-			b string
-
-			params = mux.Vars(r)
-		)
-		b = params["b"]
-		err = goa.MergeErrors(err, goa.ValidatePattern("b", b, "patternb"))
-		if err != nil {
-			return nil, err
-		}
 		payload := NewMethodUserBodyPathValidatePayloadType(&body, b)
 
 		return payload, nil
@@ -4633,6 +4746,19 @@ var PayloadBodyQueryPathObjectDecodeCode = `// DecodeMethodBodyQueryPathObjectRe
 // to the ServiceBodyQueryPathObject MethodBodyQueryPathObject endpoint.
 func DecodeMethodBodyQueryPathObjectRequest(mux goahttp.Muxer, decoder func(*http.Request) goahttp.Decoder) func(*http.Request) (any, error) {
 	return func(r *http.Request) (any, error) {
+		var (
+			c2 string
+			b  *string
+
+			params = mux.Vars(r)
+			qp     = r.URL.Query()
+		)
+		c2 = params["c"]
+		bRaw := qp.Get("b")
+		if bRaw != "" {
+			b = &bRaw
+		}
+
 		var (
 			body MethodBodyQueryPathObjectRequestBody
 			err  error
@@ -4644,18 +4770,6 @@ func DecodeMethodBodyQueryPathObjectRequest(mux goahttp.Muxer, decoder func(*htt
 			}
 			return nil, goa.DecodePayloadError(err.Error())
 		}
-
-		var (
-			c2 string
-			b  *string
-
-			params = mux.Vars(r)
-		)
-		c2 = params["c"]
-		bRaw := r.URL.Query().Get("b")
-		if bRaw != "" {
-			b = &bRaw
-		}
 		payload := NewMethodBodyQueryPathObjectPayload(&body, c2, b)
 
 		return payload, nil
@@ -4669,30 +4783,16 @@ var PayloadBodyQueryPathObjectValidateDecodeCode = `// DecodeMethodBodyQueryPath
 func DecodeMethodBodyQueryPathObjectValidateRequest(mux goahttp.Muxer, decoder func(*http.Request) goahttp.Decoder) func(*http.Request) (any, error) {
 	return func(r *http.Request) (any, error) {
 		var (
-			body MethodBodyQueryPathObjectValidateRequestBody
-			err  error
-		)
-		err = decoder(r).Decode(&body)
-		if err != nil {
-			if err == io.EOF {
-				return nil, goa.MissingPayloadError()
-			}
-			return nil, goa.DecodePayloadError(err.Error())
-		}
-		err = ValidateMethodBodyQueryPathObjectValidateRequestBody(&body)
-		if err != nil {
-			return nil, err
-		}
-
-		var (
-			c2 string
-			b  string
+			c2  string
+			b   string
+			err error
 
 			params = mux.Vars(r)
+			qp     = r.URL.Query()
 		)
 		c2 = params["c"]
 		err = goa.MergeErrors(err, goa.ValidatePattern("c", c2, "patternc"))
-		b = r.URL.Query().Get("b")
+		b = qp.Get("b")
 		if b == "" {
 			err = goa.MergeErrors(err, goa.MissingFieldError("b", "query string"))
 		}
@@ -4700,6 +4800,19 @@ func DecodeMethodBodyQueryPathObjectValidateRequest(mux goahttp.Muxer, decoder f
 		if err != nil {
 			return nil, err
 		}
+
+		var body MethodBodyQueryPathObjectValidateRequestBody
+		err = decoder(r).Decode(&body)
+		if err != nil {
+			if err == io.EOF {
+				return nil, goa.MissingPayloadError()
+			}
+			return nil, goa.DecodePayloadError(err.Error())
+		}
+		err = ValidateMethodBodyQueryPathObjectValidateRequestBody(&body)
+		if err != nil {
+			return nil, err
+		}
 		payload := NewMethodBodyQueryPathObjectValidatePayload(&body, c2, b)
 
 		return payload, nil
@@ -4711,6 +4824,19 @@ var PayloadBodyQueryPathUserDecodeCode = `// DecodeMethodBodyQueryPathUserReques
 // the ServiceBodyQueryPathUser MethodBodyQueryPathUser endpoint.
 func DecodeMethodBodyQueryPathUserRequest(mux goahttp.Muxer, decoder func(*http.Request) goahttp.Decoder) func(*http.Request) (any, error) {
 	return func(r *http.Request) (any, error) {
+		var (
+			c2 string
+			b  *string
+
+			params = mux.Vars(r)
+			qp     = r.URL.Query()
+		)
+		c2 = params["c"]
+		bRaw := qp.Get("b")
+		if bRaw != "" {
+			b = &bRaw
+		}
+
 		var (
 			body MethodBodyQueryPathUserRequestBody
 			err  error
@@ -4722,18 +4848,6 @@ func DecodeMethodBodyQueryPathUserRequest(mux goahttp.Muxer, decoder func(*http.
 			}
 			return nil, goa.DecodePayloadError(err.Error())
 		}
-
-		var (
-			c2 string
-			b  *string
-
-			params = mux.Vars(r)
-		)
-		c2 = params["c"]
-		bRaw := r.URL.Query().Get("b")
-		if bRaw != "" {
-			b = &bRaw
-		}
 		payload := NewMethodBodyQueryPathUserPayloadType(&body, c2, b)
 
 		return payload, nil
@@ -4747,30 +4861,16 @@ var PayloadBodyQueryPathUserValidateDecodeCode = `// DecodeMethodBodyQueryPathUs
 func DecodeMethodBodyQueryPathUserValidateRequest(mux goahttp.Muxer, decoder func(*http.Request) goahttp.Decoder) func(*http.Request) (any, error) {
 	return func(r *http.Request) (any, error) {
 		var (
-			body MethodBodyQueryPathUserValidateRequestBody
-			err  error
-		)
-		err = decoder(r).Decode(&body)
-		if err != nil {
-			if err == io.EOF {
-				return nil, goa.MissingPayloadError()
-			}
-			return nil, goa.DecodePayloadError(err.Error())
-		}
-		err = ValidateMethodBodyQueryPathUserValidateRequestBody(&body)
-		if err != nil {
-			return nil, err
-		}
-
-		var (
-			c2 string
-			b  string
+			c2  string
+			b   string
+			err error
 
 			params = mux.Vars(r)
+			qp     = r.URL.Query()
 		)
 		c2 = params["c"]
 		err = goa.MergeErrors(err, goa.ValidatePattern("c", c2, "patternc"))
-		b = r.URL.Query().Get("b")
+		b = qp.Get("b")
 		if b == "" {
 			err = goa.MergeErrors(err, goa.MissingFieldError("b", "query string"))
 		}
@@ -4778,6 +4878,19 @@ func DecodeMethodBodyQueryPathUserValidateRequest(mux goahttp.Muxer, decoder fun
 		if err != nil {
 			return nil, err
 		}
+
+		var body MethodBodyQueryPathUserValidateRequestBody
+		err = decoder(r).Decode(&body)
+		if err != nil {
+			if err == io.EOF {
+				return nil, goa.MissingPayloadError()
+			}
+			return nil, goa.DecodePayloadError(err.Error())
+		}
+		err = ValidateMethodBodyQueryPathUserValidateRequestBody(&body)
+		if err != nil {
+			return nil, err
+		}
 		payload := NewMethodBodyQueryPathUserValidatePayloadType(&body, c2, b)
 
 		return payload, nil
@@ -4792,13 +4905,13 @@ func DecodeMapQueryPrimitivePrimitiveRequest(mux goahttp.Muxer, decoder func(*ht
 		var (
 			query map[string]string
 			err   error
+			qp    = r.URL.Query()
 		)
 		{
-			queryRaw := r.URL.Query()
-			if len(queryRaw) == 0 {
+			if len(qp) == 0 {
 				err = goa.MergeErrors(err, goa.MissingFieldError("query", "query string"))
 			}
-			for keyRaw, valRaw := range queryRaw {
+			for keyRaw, valRaw := range qp {
 				if strings.HasPrefix(keyRaw, "query[") {
 					if query == nil {
 						query = make(map[string]string)
@@ -4830,13 +4943,13 @@ func DecodeMapQueryPrimitiveArrayRequest(mux goahttp.Muxer, decoder func(*http.R
 		var (
 			query map[string][]uint
 			err   error
+			qp    = r.URL.Query()
 		)
 		{
-			queryRaw := r.URL.Query()
-			if len(queryRaw) == 0 {
+			if len(qp) == 0 {
 				err = goa.MergeErrors(err, goa.MissingFieldError("query", "query string"))
 			}
-			for keyRaw, valRaw := range queryRaw {
+			for keyRaw, valRaw := range qp {
 				if strings.HasPrefix(keyRaw, "query[") {
 					if query == nil {
 						query = make(map[string][]uint)
@@ -4877,35 +4990,20 @@ var PayloadMapQueryObjectDecodeCode = `// DecodeMethodMapQueryObjectRequest retu
 func DecodeMethodMapQueryObjectRequest(mux goahttp.Muxer, decoder func(*http.Request) goahttp.Decoder) func(*http.Request) (any, error) {
 	return func(r *http.Request) (any, error) {
 		var (
-			body MethodMapQueryObjectRequestBody
-			err  error
-		)
-		err = decoder(r).Decode(&body)
-		if err != nil {
-			if err == io.EOF {
-				return nil, goa.MissingPayloadError()
-			}
-			return nil, goa.DecodePayloadError(err.Error())
-		}
-		err = ValidateMethodMapQueryObjectRequestBody(&body)
-		if err != nil {
-			return nil, err
-		}
-
-		var (
-			a string
-			c map[int][]string
+			a   string
+			c   map[int][]string
+			err error
 
 			params = mux.Vars(r)
+			qp     = r.URL.Query()
 		)
 		a = params["a"]
 		err = goa.MergeErrors(err, goa.ValidatePattern("a", a, "patterna"))
 		{
-			cRaw := r.URL.Query()
-			if len(cRaw) == 0 {
+			if len(qp) == 0 {
 				err = goa.MergeErrors(err, goa.MissingFieldError("c", "query string"))
 			}
-			for keyRaw, valRaw := range cRaw {
+			for keyRaw, valRaw := range qp {
 				if strings.HasPrefix(keyRaw, "c[") {
 					if c == nil {
 						c = make(map[int][]string)
@@ -4928,6 +5026,19 @@ func DecodeMethodMapQueryObjectRequest(mux goahttp.Muxer, decoder func(*http.Req
 		if err != nil {
 			return nil, err
 		}
+
+		var body MethodMapQueryObjectRequestBody
+		err = decoder(r).Decode(&body)
+		if err != nil {
+			if err == io.EOF {
+				return nil, goa.MissingPayloadError()
+			}
+			return nil, goa.DecodePayloadError(err.Error())
+		}
+		err = ValidateMethodMapQueryObjectRequestBody(&body)
+		if err != nil {
+			return nil, err
+		}
 		payload := NewMethodMapQueryObjectPayloadType(&body, a, c)
 
 		return payload, nil
@@ -4995,26 +5106,16 @@ var WithParamsAndHeadersBlockDecodeCode = `// DecodeMethodARequest returns a dec
 // ServiceWithParamsAndHeadersBlock MethodA endpoint.
 func DecodeMethodARequest(mux goahttp.Muxer, decoder func(*http.Request) goahttp.Decoder) func(*http.Request) (any, error) {
 	return func(r *http.Request) (any, error) {
-		var (
-			body MethodARequestBody
-			err  error
-		)
-		err = decoder(r).Decode(&body)
-		if err != nil {
-			if err == io.EOF {
-				return nil, goa.MissingPayloadError()
-			}
-			return nil, goa.DecodePayloadError(err.Error())
-		}
-
 		var (
 			path                      uint
 			optional                  *int
 			optionalButRequiredParam  float32
 			required                  string
 			optionalButRequiredHeader float32
+			err                       error
 
 			params = mux.Vars(r)
+			qp     = r.URL.Query()
 		)
 		{
 			pathRaw := params["path"]
@@ -5025,7 +5126,7 @@ func DecodeMethodARequest(mux goahttp.Muxer, decoder func(*http.Request) goahttp
 			path = uint(v)
 		}
 		{
-			optionalRaw := r.URL.Query().Get("optional")
+			optionalRaw := qp.Get("optional")
 			if optionalRaw != "" {
 				v, err2 := strconv.ParseInt(optionalRaw, 10, strconv.IntSize)
 				if err2 != nil {
@@ -5036,7 +5137,7 @@ func DecodeMethodARequest(mux goahttp.Muxer, decoder func(*http.Request) goahttp
 			}
 		}
 		{
-			optionalButRequiredParamRaw := r.URL.Query().Get("optional_but_required_param")
+			optionalButRequiredParamRaw := qp.Get("optional_but_required_param")
 			if optionalButRequiredParamRaw == "" {
 				err = goa.MergeErrors(err, goa.MissingFieldError("optional_but_required_param", "query string"))
 			}
@@ -5064,6 +5165,15 @@ func DecodeMethodARequest(mux goahttp.Muxer, decoder func(*http.Request) goahttp
 		if err != nil {
 			return nil, err
 		}
+
+		var body MethodARequestBody
+		err = decoder(r).Decode(&body)
+		if err != nil {
+			if err == io.EOF {
+				return nil, goa.MissingPayloadError()
+			}
+			return nil, goa.DecodePayloadError(err.Error())
+		}
 		payload := NewMethodAPayload(&body, path, optional, optionalButRequiredParam, required, optionalButRequiredHeader)
 
 		return payload, nil
@@ -5080,9 +5190,10 @@ func DecodeMethodARequest(mux goahttp.Muxer, decoder func(*http.Request) goahttp
 			int32_ *int32
 			int64_ *int64
 			err    error
+			qp     = r.URL.Query()
 		)
 		{
-			int_Raw := r.URL.Query().Get("int")
+			int_Raw := qp.Get("int")
 			if int_Raw != "" {
 				v, err2 := strconv.ParseInt(int_Raw, 10, strconv.IntSize)
 				if err2 != nil {
@@ -5093,7 +5204,7 @@ func DecodeMethodARequest(mux goahttp.Muxer, decoder func(*http.Request) goahttp
 			}
 		}
 		{
-			int32_Raw := r.URL.Query().Get("int32")
+			int32_Raw := qp.Get("int32")
 			if int32_Raw != "" {
 				v, err2 := strconv.ParseInt(int32_Raw, 10, 32)
 				if err2 != nil {
@@ -5104,7 +5215,7 @@ func DecodeMethodARequest(mux goahttp.Muxer, decoder func(*http.Request) goahttp
 			}
 		}
 		{
-			int64_Raw := r.URL.Query().Get("int64")
+			int64_Raw := qp.Get("int64")
 			if int64_Raw != "" {
 				v, err2 := strconv.ParseInt(int64_Raw, 10, 64)
 				if err2 != nil {
@@ -5132,9 +5243,10 @@ func DecodeMethodARequest(mux goahttp.Muxer, decoder func(*http.Request) goahttp
 			int32_ *int32
 			int64_ *int64
 			err    error
+			qp     = r.URL.Query()
 		)
 		{
-			int_Raw := r.URL.Query().Get("int")
+			int_Raw := qp.Get("int")
 			if int_Raw != "" {
 				v, err2 := strconv.ParseInt(int_Raw, 10, strconv.IntSize)
 				if err2 != nil {
@@ -5150,7 +5262,7 @@ func DecodeMethodARequest(mux goahttp.Muxer, decoder func(*http.Request) goahttp
 			}
 		}
 		{
-			int32_Raw := r.URL.Query().Get("int32")
+			int32_Raw := qp.Get("int32")
 			if int32_Raw != "" {
 				v, err2 := strconv.ParseInt(int32_Raw, 10, 32)
 				if err2 != nil {
@@ -5166,7 +5278,7 @@ func DecodeMethodARequest(mux goahttp.Muxer, decoder func(*http.Request) goahttp
 			}
 		}
 		{
-			int64_Raw := r.URL.Query().Get("int64")
+			int64_Raw := qp.Get("int64")
 			if int64_Raw != "" {
 				v, err2 := strconv.ParseInt(int64_Raw, 10, 64)
 				if err2 != nil {
@@ -5197,9 +5309,10 @@ func DecodeMethodARequest(mux goahttp.Muxer, decoder func(*http.Request) goahttp
 		var (
 			array []uint
 			err   error
+			qp    = r.URL.Query()
 		)
 		{
-			arrayRaw := r.URL.Query()["array"]
+			arrayRaw := qp["array"]
 			if arrayRaw != nil {
 				array = make([]uint, len(arrayRaw))
 				for i, rv := range arrayRaw {
@@ -5228,9 +5341,10 @@ func DecodeMethodARequest(mux goahttp.Muxer, decoder func(*http.Request) goahttp
 		var (
 			array []uint
 			err   error
+			qp    = r.URL.Query()
 		)
 		{
-			arrayRaw := r.URL.Query()["array"]
+			arrayRaw := qp["array"]
 			if arrayRaw != nil {
 				array = make([]uint, len(arrayRaw))
 				for i, rv := range arrayRaw {
@@ -5266,11 +5380,11 @@ func DecodeMethodARequest(mux goahttp.Muxer, decoder func(*http.Request) goahttp
 		var (
 			map_ map[float32]bool
 			err  error
+			qp   = r.URL.Query()
 		)
 		{
-			map_Raw := r.URL.Query()
-			if len(map_Raw) != 0 {
-				for keyRaw, valRaw := range map_Raw {
+			if len(qp) != 0 {
+				for keyRaw, valRaw := range qp {
 					if strings.HasPrefix(keyRaw, "map[") {
 						if map_ == nil {
 							map_ = make(map[float32]bool)
@@ -5317,11 +5431,11 @@ func DecodeMethodARequest(mux goahttp.Muxer, decoder func(*http.Request) goahttp
 		var (
 			map_ map[float32]bool
 			err  error
+			qp   = r.URL.Query()
 		)
 		{
-			map_Raw := r.URL.Query()
-			if len(map_Raw) != 0 {
-				for keyRaw, valRaw := range map_Raw {
+			if len(qp) != 0 {
+				for keyRaw, valRaw := range qp {
 					if strings.HasPrefix(keyRaw, "map[") {
 						if map_ == nil {
 							map_ = make(map[float32]bool)
@@ -5371,9 +5485,10 @@ func DecodeMethodARequest(mux goahttp.Muxer, decoder func(*http.Request) goahttp
 		var (
 			array []float64
 			err   error
+			qp    = r.URL.Query()
 		)
 		{
-			arrayRaw := r.URL.Query()["array"]
+			arrayRaw := qp["array"]
 			if arrayRaw != nil {
 				array = make([]float64, len(arrayRaw))
 				for i, rv := range arrayRaw {
@@ -5717,3 +5832,191 @@ func DecodeMethodPathCustomUInt64Request(mux goahttp.Muxer, decoder func(*http.R
 	}
 }
 `
+
+var PayloadBodyUserUnknownFieldsDecodeCode = `// DecodeMethodBodyUserRequest returns a decoder for requests sent to the
+// ServiceBodyUser MethodBodyUser endpoint.
+func DecodeMethodBodyUserRequest(mux goahttp.Muxer, decoder func(*http.Request) goahttp.Decoder) func(*http.Request) (any, error) {
+	return func(r *http.Request) (any, error) {
+		var (
+			body MethodBodyUserRequestBody
+			err  error
+		)
+		err = goahttp.DecodeCollectUnknown(decoder(r), &body, []string{"a", "bee"}, &body.Extra)
+		if err != nil {
+			if err == io.EOF {
+				return nil, goa.MissingPayloadError()
+			}
+			return nil, goa.DecodePayloadError(err.Error())
+		}
+		payload := NewMethodBodyUserPayloadType(&body)
+
+		return payload, nil
+	}
+}
+`
+
+var PayloadBodyFieldPresenceDecodeCode = `// DecodeMethodBodyFieldPresenceRequest returns a decoder for requests sent to
+// the ServiceBodyFieldPresence MethodBodyFieldPresence endpoint.
+func DecodeMethodBodyFieldPresenceRequest(mux goahttp.Muxer, decoder func(*http.Request) goahttp.Decoder) func(*http.Request) (any, error) {
+	return func(r *http.Request) (any, error) {
+		var (
+			body MethodBodyFieldPresenceRequestBody
+			err  error
+		)
+		err = goahttp.NewPresenceDecoder(decoder(r), &body.Fields).Decode(&body)
+		if err != nil {
+			if err == io.EOF {
+				return nil, goa.MissingPayloadError()
+			}
+			return nil, goa.DecodePayloadError(err.Error())
+		}
+		payload := NewMethodBodyFieldPresencePayload(&body)
+
+		return payload, nil
+	}
+}
+`
+
+var PayloadBodySecureDecodeCode = `// DecodeMethodBodySecureRequest returns a decoder for requests sent to the
+// ServiceBodySecure MethodBodySecure endpoint.
+func DecodeMethodBodySecureRequest(mux goahttp.Muxer, decoder func(*http.Request) goahttp.Decoder) func(*http.Request) (any, error) {
+	return func(r *http.Request) (any, error) {
+		var (
+			token string
+			err   error
+		)
+		token = r.Header.Get("Authorization")
+		if token == "" {
+			err = goa.MergeErrors(err, goa.MissingFieldError("token", "header"))
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		decodeBody := func() (*servicebodysecure.MethodBodySecurePayload, error) {
+			var (
+				body MethodBodySecureRequestBody
+				err  error
+			)
+			err = decoder(r).Decode(&body)
+			if err != nil {
+				if err == io.EOF {
+					return nil, goa.MissingPayloadError()
+				}
+				return nil, goa.DecodePayloadError(err.Error())
+			}
+			err = ValidateMethodBodySecureRequestBody(&body)
+			if err != nil {
+				return nil, err
+			}
+			payload := NewMethodBodySecurePayload(&body, token)
+			return payload, nil
+		}
+		v := &servicebodysecure.MethodBodySecurePayload{}
+		v.Token = token
+
+		payload := v
+		load := func() error {
+			full, err := decodeBody()
+			if err != nil {
+				return err
+			}
+			full.Token = payload.Token
+			*payload = *full
+			return nil
+		}
+		if !goa.DeferBody(r.Context(), load) {
+			if err := load(); err != nil {
+				return nil, err
+			}
+		}
+		if strings.Contains(payload.Token, " ") {
+			// Remove authorization scheme prefix (e.g. "Bearer")
+			cred := strings.SplitN(payload.Token, " ", 2)[1]
+			payload.Token = cred
+		}
+
+		return payload, nil
+	}
+}
+`
+
+var PayloadReservedNamesDecodeCode = `// DecodeMethodReservedNamesRequest returns a decoder for requests sent to the
+// ServiceReservedNames MethodReservedNames endpoint.
+func DecodeMethodReservedNamesRequest(mux goahttp.Muxer, decoder func(*http.Request) goahttp.Decoder) func(*http.Request) (any, error) {
+	return func(r *http.Request) (any, error) {
+		var (
+			params2 string
+			qp2     *string
+
+			params = mux.Vars(r)
+			qp     = r.URL.Query()
+		)
+		params2 = params["params"]
+		qp2Raw := qp.Get("qp")
+		if qp2Raw != "" {
+			qp2 = &qp2Raw
+		}
+		payload := NewMethodReservedNamesPayload(params2, qp2)
+
+		return payload, nil
+	}
+}
+`
+
+var PayloadCustomTypeFuncsDecodeCode = `// DecodeMethodCustomTypeFuncsRequest returns a decoder for requests sent to
+// the ServiceCustomTypeFuncs MethodCustomTypeFuncs endpoint.
+func DecodeMethodCustomTypeFuncsRequest(mux goahttp.Muxer, decoder func(*http.Request) goahttp.Decoder) func(*http.Request) (any, error) {
+	return func(r *http.Request) (any, error) {
+		var (
+			id      uuid.UUID
+			timeout *time.Duration
+			amount  decimal.Decimal
+			err     error
+
+			params = mux.Vars(r)
+			qp     = r.URL.Query()
+		)
+		{
+			idRaw := params["id"]
+			v, err2 := uuid.Parse(idRaw)
+			if err2 != nil {
+				err = goa.MergeErrors(err, goa.InvalidValueError("id", idRaw, err2))
+			}
+			id = v
+		}
+		{
+			timeoutRaw := qp.Get("timeout")
+			if timeoutRaw != "" {
+				v, err2 := time.ParseDuration(timeoutRaw)
+				if err2 != nil {
+					err = goa.MergeErrors(err, goa.InvalidValueError("timeout", timeoutRaw, err2))
+				}
+				timeout = &v
+			}
+		}
+		if timeout != nil {
+			if err2 := timeouts.Validate(*timeout); err2 != nil {
+				err = goa.MergeErrors(err, goa.InvalidValueError("timeout", *timeout, err2))
+			}
+		}
+		{
+			amountRaw := r.Header.Get("X-Amount")
+			if amountRaw == "" {
+				err = goa.MergeErrors(err, goa.MissingFieldError("amount", "header"))
+			}
+			v, err2 := decimal.NewFromString(amountRaw)
+			if err2 != nil {
+				err = goa.MergeErrors(err, goa.InvalidValueError("amount", amountRaw, err2))
+			}
+			amount = v
+		}
+		if err != nil {
+			return nil, err
+		}
+		payload := NewMethodCustomTypeFuncsPayload(id, timeout, amount)
+
+		return payload, nil
+	}
+}
+`
@@ -1691,6 +1691,29 @@ var PayloadHeaderPrimitiveStringValidateDSL = func() {
 	})
 }
 
+var PayloadHeaderInheritedDSL = func() {
+	API("test", func() {
+		HTTP(func() {
+			Headers(func() {
+				Header("tenant:X-Tenant-Id", String, func() {
+					Pattern("^[a-z]+$")
+				})
+				Required("tenant")
+			})
+		})
+	})
+	Service("ServiceHeaderInherited", func() {
+		HTTP(func() {
+			Header("key:Idempotency-Key", String)
+		})
+		Method("MethodHeaderInherited", func() {
+			HTTP(func() {
+				POST("/")
+			})
+		})
+	})
+}
+
 var PayloadHeaderPrimitiveBoolValidateDSL = func() {
 	Service("ServiceHeaderPrimitiveBoolValidate", func() {
 		Method("MethodHeaderPrimitiveBoolValidate", func() {
@@ -1986,6 +2009,114 @@ var PayloadBodyUserRequiredDSL = func() {
 	})
 }
 
+var PayloadBodyUserMigrationDSL = func() {
+	var PayloadType = Type("PayloadType", func() {
+		Attribute("a", String)
+		Attribute("b", Int32, func() {
+			MigrationDefault(42, "2024-12-31")
+		})
+		Required("a", "b")
+	})
+	Service("ServiceBodyUser", func() {
+		Method("MethodBodyUser", func() {
+			Payload(PayloadType)
+			HTTP(func() {
+				POST("/")
+			})
+		})
+	})
+}
+
+var PayloadBodyUserUnknownFieldsDSL = func() {
+	var PayloadType = Type("PayloadType", func() {
+		Attribute("a", String)
+		Attribute("b", Int32, func() {
+			Meta("struct:tag:json", "bee,omitempty")
+		})
+		Attribute("extra", MapOf(String, Any))
+	})
+	Service("ServiceBodyUser", func() {
+		Method("MethodBodyUser", func() {
+			Payload(PayloadType)
+			HTTP(func() {
+				POST("/")
+				CollectUnknownFields("extra")
+			})
+		})
+	})
+}
+
+var PayloadBodyPatchDSL = func() {
+	var Account = Type("Account", func() {
+		Attribute("name", String)
+	})
+	Service("ServiceBodyPatch", func() {
+		Method("MethodBodyPatch", func() {
+			Payload(func() {
+				Attribute("id", String)
+				Patch("patch", Account)
+				Required("id", "patch")
+			})
+			HTTP(func() {
+				PATCH("/{id}")
+				Body("patch")
+			})
+		})
+	})
+}
+
+var PayloadBodyFieldPresenceDSL = func() {
+	Service("ServiceBodyFieldPresence", func() {
+		Method("MethodBodyFieldPresence", func() {
+			Payload(func() {
+				Attribute("name", String)
+				Attribute("fields", ArrayOf(String))
+			})
+			HTTP(func() {
+				PATCH("/")
+				FieldPresence("fields")
+			})
+		})
+	})
+}
+
+var PayloadBodySecureDSL = func() {
+	var JWT = JWTSecurity("jwt", func() {
+		Scope("api:write")
+	})
+	Service("ServiceBodySecure", func() {
+		Method("MethodBodySecure", func() {
+			Security(JWT)
+			Payload(func() {
+				Token("token", String)
+				Attribute("a", String)
+				Attribute("b", Int)
+				Required("token", "a")
+			})
+			HTTP(func() {
+				POST("/")
+				Header("token:Authorization")
+			})
+		})
+	})
+}
+
+var PayloadReservedNamesDSL = func() {
+	Service("ServiceReservedNames", func() {
+		Method("MethodReservedNames", func() {
+			Payload(func() {
+				Attribute("params", String)
+				Attribute("qp", String)
+				Required("params")
+			})
+			HTTP(func() {
+				GET("/{params}")
+				Param("qp")
+			})
+		})
+	})
+}
+
 var PayloadBodyNestedUserDSL = func() {
 	var NestedType = Type("NestedType", func() {
 		Attribute("a", String)
@@ -3500,3 +3631,51 @@ var PayloadPathCustomUInt64DSL = func() {
 		})
 	})
 }
+
+var PayloadCustomTypeFuncsDSL = func() {
+	Service("ServiceCustomTypeFuncs", func() {
+		Method("MethodCustomTypeFuncs", func() {
+			Payload(func() {
+				Attribute("id", String, func() {
+					Meta("struct:field:type", "uuid.UUID", "github.com/google/uuid")
+					Meta("struct:field:decode", "uuid.Parse")
+					Meta("struct:field:encode", "uuid.UUID.String")
+				})
+				Attribute("timeout", String, func() {
+					Meta("struct:field:type", "time.Duration", "time")
+					Meta("struct:field:decode", "time.ParseDuration")
+					Meta("struct:field:encode", "time.Duration.String")
+					Meta("struct:field:validate", "timeouts.Validate", "example.com/timeouts")
+				})
+				Attribute("amount", String, func() {
+					Meta("struct:field:type", "decimal.Decimal", "github.com/shopspring/decimal")
+					Meta("struct:field:decode", "decimal.NewFromString")
+					Meta("struct:field:encode", "decimal.Decimal.String")
+				})
+				Required("id", "amount")
+			})
+			HTTP(func() {
+				GET("/{id}")
+				Param("timeout")
+				Header("amount:X-Amount")
+			})
+		})
+	})
+}
+
+var PayloadCloudEventsDSL = func() {
+	Service("ServiceCloudEvents", func() {
+		Method("MethodCloudEvents", func() {
+			Payload(func() {
+				Attribute("id", String)
+				Attribute("data", String)
+			})
+			HTTP(func() {
+				POST("/events")
+				CloudEvents()
+				Header("id:ce-id")
+				Body("data")
+			})
+		})
+	})
+}
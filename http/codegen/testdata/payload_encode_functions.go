@@ -2613,3 +2613,86 @@ func EncodeMethodARequest(encoder func(*http.Request) goahttp.Encoder) func(*htt
 	}
 }
 `
+
+var PayloadCloudEventsEncodeCode = `// EncodeMethodCloudEventsRequest returns an encoder for requests sent to the
+// ServiceCloudEvents MethodCloudEvents server.
+func EncodeMethodCloudEventsRequest(encoder func(*http.Request) goahttp.Encoder) func(*http.Request, any) error {
+	return func(req *http.Request, v any) error {
+		p, ok := v.(*servicecloudevents.MethodCloudEventsPayload)
+		if !ok {
+			return goahttp.ErrInvalidType("ServiceCloudEvents", "MethodCloudEvents", "*servicecloudevents.MethodCloudEventsPayload", v)
+		}
+		if p.ID != nil {
+			head := *p.ID
+			req.Header.Set("ce-id", head)
+		}
+		if req.Header.Get("ce-specversion") == "" {
+			req.Header.Set("ce-specversion", "1.0")
+		}
+		body := p.Data
+		if err := encoder(req).Encode(&body); err != nil {
+			return goahttp.ErrEncodingError("ServiceCloudEvents", "MethodCloudEvents", err)
+		}
+		return nil
+	}
+}
+`
+
+var PayloadBodyPatchEncodeCode = `// EncodeMethodBodyPatchRequest returns an encoder for requests sent to the
+// ServiceBodyPatch MethodBodyPatch server.
+func EncodeMethodBodyPatchRequest(encoder func(*http.Request) goahttp.Encoder) func(*http.Request, any) error {
+	return func(req *http.Request, v any) error {
+		p, ok := v.(*servicebodypatch.MethodBodyPatchPayload)
+		if !ok {
+			return goahttp.ErrInvalidType("ServiceBodyPatch", "MethodBodyPatch", "*servicebodypatch.MethodBodyPatchPayload", v)
+		}
+		body := p.Patch
+		if body != nil {
+			req.Header.Set("Content-Type", body.ContentType())
+		}
+		if err := encoder(req).Encode(&body); err != nil {
+			return goahttp.ErrEncodingError("ServiceBodyPatch", "MethodBodyPatch", err)
+		}
+		return nil
+	}
+}
+`
+
+var PayloadBodyFieldPresenceEncodeCode = `// EncodeMethodBodyFieldPresenceRequest returns an encoder for requests sent to
+// the ServiceBodyFieldPresence MethodBodyFieldPresence server.
+func EncodeMethodBodyFieldPresenceRequest(encoder func(*http.Request) goahttp.Encoder) func(*http.Request, any) error {
+	return func(req *http.Request, v any) error {
+		p, ok := v.(*servicebodyfieldpresence.MethodBodyFieldPresencePayload)
+		if !ok {
+			return goahttp.ErrInvalidType("ServiceBodyFieldPresence", "MethodBodyFieldPresence", "*servicebodyfieldpresence.MethodBodyFieldPresencePayload", v)
+		}
+		body := NewMethodBodyFieldPresenceRequestBody(p)
+		if err := encoder(req).Encode(goahttp.WithNullFields(&body, body.Fields)); err != nil {
+			return goahttp.ErrEncodingError("ServiceBodyFieldPresence", "MethodBodyFieldPresence", err)
+		}
+		return nil
+	}
+}
+`
+
+var PayloadCustomTypeFuncsEncodeCode = `// EncodeMethodCustomTypeFuncsRequest returns an encoder for requests sent to
+// the ServiceCustomTypeFuncs MethodCustomTypeFuncs server.
+func EncodeMethodCustomTypeFuncsRequest(encoder func(*http.Request) goahttp.Encoder) func(*http.Request, any) error {
+	return func(req *http.Request, v any) error {
+		p, ok := v.(*servicecustomtypefuncs.MethodCustomTypeFuncsPayload)
+		if !ok {
+			return goahttp.ErrInvalidType("ServiceCustomTypeFuncs", "MethodCustomTypeFuncs", "*servicecustomtypefuncs.MethodCustomTypeFuncsPayload", v)
+		}
+		{
+			head := p.Amount
+			req.Header.Set("X-Amount", decimal.Decimal.String(head))
+		}
+		values := req.URL.Query()
+		if p.Timeout != nil {
+			values.Add("timeout", time.Duration.String(*p.Timeout))
+		}
+		req.URL.RawQuery = values.Encode()
+		return nil
+	}
+}
+`
@@ -0,0 +1,41 @@
+package testdata
+
+import . "goa.design/goa/v3/dsl"
+
+var PostmanDSL = func() {
+	var _ = API("test", func() {
+		Title("Test API")
+		Server("test", func() {
+			Host("localhost", func() {
+				URI("https://goa.design/api/")
+			})
+		})
+	})
+	Service("svc", func() {
+		Method("update", func() {
+			Payload(func() {
+				Attribute("id", Int, func() {
+					Example(42)
+				})
+				Attribute("tags", ArrayOf(String), func() {
+					Example([]string{"a", "b"})
+				})
+				Attribute("token", String, func() {
+					Example("secret")
+				})
+				Attribute("name", String, func() {
+					Example("goa")
+				})
+				Required("id")
+			})
+			HTTP(func() {
+				PUT("/items/{id}")
+				Param("tags")
+				Header("token:X-Token")
+				Body(func() {
+					Attribute("name")
+				})
+			})
+		})
+	})
+}
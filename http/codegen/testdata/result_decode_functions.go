@@ -68,7 +68,7 @@ func DecodeMethodBodyMultipleViewResponse(decoder func(*http.Response) goahttp.D
 			p := NewMethodBodyMultipleViewResulttypemultipleviewsOK(&body, c)
 			view := resp.Header.Get("goa-view")
 			vres := &servicebodymultipleviewviews.Resulttypemultipleviews{Projected: p, View: view}
-			if err = servicebodymultipleviewviews.ValidateResulttypemultipleviews(vres); err != nil {
+			if err = servicebodymultipleviewviews.ValidateResulttypemultipleviews(vres); err != nil && goahttp.RejectResponse(resp, err) {
 				return nil, goahttp.ErrValidationError("ServiceBodyMultipleView", "MethodBodyMultipleView", err)
 			}
 			res := servicebodymultipleview.NewResulttypemultipleviews(vres)
@@ -153,7 +153,7 @@ func DecodeMethodExplicitBodyPrimitiveResultMultipleViewResponse(decoder func(*h
 			if utf8.RuneCountInString(body) < 5 {
 				err = goa.MergeErrors(err, goa.InvalidLengthError("body", body, utf8.RuneCountInString(body), 5, true))
 			}
-			if err != nil {
+			if err != nil && goahttp.RejectResponse(resp, err) {
 				return nil, goahttp.ErrValidationError("ServiceExplicitBodyPrimitiveResultMultipleView", "MethodExplicitBodyPrimitiveResultMultipleView", err)
 			}
 			var (
@@ -166,7 +166,7 @@ func DecodeMethodExplicitBodyPrimitiveResultMultipleViewResponse(decoder func(*h
 			p := NewMethodExplicitBodyPrimitiveResultMultipleViewResulttypemultipleviewsOK(body, c)
 			view := resp.Header.Get("goa-view")
 			vres := &serviceexplicitbodyprimitiveresultmultipleviewviews.Resulttypemultipleviews{Projected: p, View: view}
-			if err = serviceexplicitbodyprimitiveresultmultipleviewviews.ValidateResulttypemultipleviews(vres); err != nil {
+			if err = serviceexplicitbodyprimitiveresultmultipleviewviews.ValidateResulttypemultipleviews(vres); err != nil && goahttp.RejectResponse(resp, err) {
 				return nil, goahttp.ErrValidationError("ServiceExplicitBodyPrimitiveResultMultipleView", "MethodExplicitBodyPrimitiveResultMultipleView", err)
 			}
 			res := serviceexplicitbodyprimitiveresultmultipleview.NewResulttypemultipleviews(vres)
@@ -217,7 +217,7 @@ func DecodeMethodExplicitBodyUserResultMultipleViewResponse(decoder func(*http.R
 			p := NewMethodExplicitBodyUserResultMultipleViewResulttypemultipleviewsOK(&body, c)
 			view := resp.Header.Get("goa-view")
 			vres := &serviceexplicitbodyuserresultmultipleviewviews.Resulttypemultipleviews{Projected: p, View: view}
-			if err = serviceexplicitbodyuserresultmultipleviewviews.ValidateResulttypemultipleviews(vres); err != nil {
+			if err = serviceexplicitbodyuserresultmultipleviewviews.ValidateResulttypemultipleviews(vres); err != nil && goahttp.RejectResponse(resp, err) {
 				return nil, goahttp.ErrValidationError("ServiceExplicitBodyUserResultMultipleView", "MethodExplicitBodyUserResultMultipleView", err)
 			}
 			res := serviceexplicitbodyuserresultmultipleview.NewResulttypemultipleviews(vres)
@@ -259,7 +259,7 @@ func DecodeMethodExplicitBodyResultCollectionResponse(decoder func(*http.Respons
 				return nil, goahttp.ErrDecodingError("ServiceExplicitBodyResultCollection", "MethodExplicitBodyResultCollection", err)
 			}
 			err = ValidateResulttypeCollection(body)
-			if err != nil {
+			if err != nil && goahttp.RejectResponse(resp, err) {
 				return nil, goahttp.ErrValidationError("ServiceExplicitBodyResultCollection", "MethodExplicitBodyResultCollection", err)
 			}
 			res := NewMethodExplicitBodyResultCollectionResultOK(body)
@@ -312,7 +312,7 @@ func DecodeMethodTagMultipleViewsResponse(decoder func(*http.Response) goahttp.D
 			p.B = &tmp
 			view := resp.Header.Get("goa-view")
 			vres := &servicetagmultipleviewsviews.Resulttypemultipleviews{Projected: p, View: view}
-			if err = servicetagmultipleviewsviews.ValidateResulttypemultipleviews(vres); err != nil {
+			if err = servicetagmultipleviewsviews.ValidateResulttypemultipleviews(vres); err != nil && goahttp.RejectResponse(resp, err) {
 				return nil, goahttp.ErrValidationError("ServiceTagMultipleViews", "MethodTagMultipleViews", err)
 			}
 			res := servicetagmultipleviews.NewResulttypemultipleviews(vres)
@@ -329,7 +329,7 @@ func DecodeMethodTagMultipleViewsResponse(decoder func(*http.Response) goahttp.D
 			p := NewMethodTagMultipleViewsResulttypemultipleviewsOK(&body)
 			view := resp.Header.Get("goa-view")
 			vres := &servicetagmultipleviewsviews.Resulttypemultipleviews{Projected: p, View: view}
-			if err = servicetagmultipleviewsviews.ValidateResulttypemultipleviews(vres); err != nil {
+			if err = servicetagmultipleviewsviews.ValidateResulttypemultipleviews(vres); err != nil && goahttp.RejectResponse(resp, err) {
 				return nil, goahttp.ErrValidationError("ServiceTagMultipleViews", "MethodTagMultipleViews", err)
 			}
 			res := servicetagmultipleviews.NewResulttypemultipleviews(vres)
@@ -405,7 +405,7 @@ func DecodeMethodHeaderStringImplicitResponse(decoder func(*http.Response) goaht
 				err = goa.MergeErrors(err, goa.MissingFieldError("h", "header"))
 			}
 			h = hRaw
-			if err != nil {
+			if err != nil && goahttp.RejectResponse(resp, err) {
 				return nil, goahttp.ErrValidationError("ServiceHeaderStringImplicit", "MethodHeaderStringImplicit", err)
 			}
 			return h, nil
@@ -417,6 +417,53 @@ func DecodeMethodHeaderStringImplicitResponse(decoder func(*http.Response) goaht
 }
 `
 
+var ResultHeaderContentDispositionResponseDecodeCode = `// DecodeMethodHeaderContentDispositionResponse returns a decoder for responses
+// returned by the ServiceHeaderContentDisposition
+// MethodHeaderContentDisposition endpoint. restoreBody controls whether the
+// response body should be restored after having been read.
+func DecodeMethodHeaderContentDispositionResponse(decoder func(*http.Response) goahttp.Decoder, restoreBody bool) func(*http.Response) (any, error) {
+	return func(resp *http.Response) (any, error) {
+		if restoreBody {
+			b, err := io.ReadAll(resp.Body)
+			if err != nil {
+				return nil, err
+			}
+			resp.Body = io.NopCloser(bytes.NewBuffer(b))
+			defer func() {
+				resp.Body = io.NopCloser(bytes.NewBuffer(b))
+			}()
+		} else {
+			defer resp.Body.Close()
+		}
+		switch resp.StatusCode {
+		case http.StatusOK:
+			var (
+				filename    string
+				contentType *string
+				err         error
+			)
+			filenameRaw := goahttp.ParseContentDisposition(resp.Header.Get("Content-Disposition"))
+			if filenameRaw == "" {
+				err = goa.MergeErrors(err, goa.MissingFieldError("filename", "header"))
+			}
+			filename = filenameRaw
+			contentTypeRaw := resp.Header.Get("Content-Type")
+			if contentTypeRaw != "" {
+				contentType = &contentTypeRaw
+			}
+			if err != nil && goahttp.RejectResponse(resp, err) {
+				return nil, goahttp.ErrValidationError("ServiceHeaderContentDisposition", "MethodHeaderContentDisposition", err)
+			}
+			res := NewMethodHeaderContentDispositionResultOK(filename, contentType)
+			return res, nil
+		default:
+			body, _ := io.ReadAll(resp.Body)
+			return nil, goahttp.ErrInvalidResponse("ServiceHeaderContentDisposition", "MethodHeaderContentDisposition", resp.StatusCode, string(body))
+		}
+	}
+}
+`
+
 var ResultHeaderStringArrayResponseDecodeCode = `// DecodeMethodAResponse returns a decoder for responses returned by the
 // ServiceHeaderStringArrayResponse MethodA endpoint. restoreBody controls
 // whether the response body should be restored after having been read.
@@ -479,7 +526,7 @@ func DecodeMethodAResponse(decoder func(*http.Response) goahttp.Decoder, restore
 			if len(array) < 5 {
 				err = goa.MergeErrors(err, goa.InvalidLengthError("array", array, len(array), 5, true))
 			}
-			if err != nil {
+			if err != nil && goahttp.RejectResponse(resp, err) {
 				return nil, goahttp.ErrValidationError("ServiceHeaderStringArrayValidateResponse", "MethodA", err)
 			}
 			res := NewMethodAResultOK(array)
@@ -529,7 +576,7 @@ func DecodeMethodAResponse(decoder func(*http.Response) goahttp.Decoder, restore
 					}
 				}
 			}
-			if err != nil {
+			if err != nil && goahttp.RejectResponse(resp, err) {
 				return nil, goahttp.ErrValidationError("ServiceHeaderArrayResponse", "MethodA", err)
 			}
 			res := NewMethodAResultOK(array)
@@ -584,7 +631,7 @@ func DecodeMethodAResponse(decoder func(*http.Response) goahttp.Decoder, restore
 					err = goa.MergeErrors(err, goa.InvalidRangeError("array[*]", e, 5, true))
 				}
 			}
-			if err != nil {
+			if err != nil && goahttp.RejectResponse(resp, err) {
 				return nil, goahttp.ErrValidationError("ServiceHeaderArrayValidateResponse", "MethodA", err)
 			}
 			res := NewMethodAResultOK(array)
@@ -655,7 +702,7 @@ func DecodeMethodAResponse(decoder func(*http.Response) goahttp.Decoder, restore
 				}
 				optionalButRequired = uint(v)
 			}
-			if err != nil {
+			if err != nil && goahttp.RejectResponse(resp, err) {
 				return nil, goahttp.ErrValidationError("ServiceWithHeadersBlock", "MethodA", err)
 			}
 			res := NewMethodAResultOK(required, optional, optionalButRequired)
@@ -726,7 +773,7 @@ func DecodeMethodAResponse(decoder func(*http.Response) goahttp.Decoder, restore
 				}
 				optionalButRequired = uint(v)
 			}
-			if err != nil {
+			if err != nil && goahttp.RejectResponse(resp, err) {
 				return nil, goahttp.ErrValidationError("ServiceWithHeadersBlockViewedResult", "MethodA", err)
 			}
 			p := NewMethodAAResultOK(required, optional, optionalButRequired)
@@ -779,7 +826,7 @@ func DecodeMethodAResponse(decoder func(*http.Response) goahttp.Decoder, restore
 				}
 				required = int(v)
 			}
-			if err != nil {
+			if err != nil && goahttp.RejectResponse(resp, err) {
 				return nil, goahttp.ErrValidationError("ValidateErrorResponseType", "MethodA", err)
 			}
 			p := NewMethodAAResultOK(required)
@@ -814,7 +861,7 @@ func DecodeMethodAResponse(decoder func(*http.Response) goahttp.Decoder, restore
 					err = goa.MergeErrors(err, goa.InvalidRangeError("num_occur", *numOccur, 1, true))
 				}
 			}
-			if err != nil {
+			if err != nil && goahttp.RejectResponse(resp, err) {
 				return nil, goahttp.ErrValidationError("ValidateErrorResponseType", "MethodA", err)
 			}
 			return nil, NewMethodASomeError(error, numOccur)
@@ -909,7 +956,7 @@ func DecodeMethodEmptyErrorResponseBodyResponse(decoder func(*http.Response) goa
 				}
 				fault = v
 			}
-			if err != nil {
+			if err != nil && goahttp.RejectResponse(resp, err) {
 				return nil, goahttp.ErrValidationError("ServiceEmptyErrorResponseBody", "MethodEmptyErrorResponseBody", err)
 			}
 			return nil, NewMethodEmptyErrorResponseBodyInternalError(name, id, message, temporary, timeout, fault)
@@ -923,7 +970,7 @@ func DecodeMethodEmptyErrorResponseBodyResponse(decoder func(*http.Response) goa
 				err = goa.MergeErrors(err, goa.MissingFieldError("in-header", "header"))
 			}
 			inHeader = inHeaderRaw
-			if err != nil {
+			if err != nil && goahttp.RejectResponse(resp, err) {
 				return nil, goahttp.ErrValidationError("ServiceEmptyErrorResponseBody", "MethodEmptyErrorResponseBody", err)
 			}
 			return nil, NewMethodEmptyErrorResponseBodyNotFound(inHeader)
@@ -934,3 +981,41 @@ func DecodeMethodEmptyErrorResponseBodyResponse(decoder func(*http.Response) goa
 	}
 }
 `
+
+var ResultBodyDisallowUnknownFieldsDecodeCode = `// DecodeMethodBodyDisallowUnknownFieldsResponse returns a decoder for
+// responses returned by the ServiceBodyDisallowUnknownFields
+// MethodBodyDisallowUnknownFields endpoint. restoreBody controls whether the
+// response body should be restored after having been read.
+func DecodeMethodBodyDisallowUnknownFieldsResponse(decoder func(*http.Response) goahttp.Decoder, restoreBody bool) func(*http.Response) (any, error) {
+	return func(resp *http.Response) (any, error) {
+		if restoreBody {
+			b, err := io.ReadAll(resp.Body)
+			if err != nil {
+				return nil, err
+			}
+			resp.Body = io.NopCloser(bytes.NewBuffer(b))
+			defer func() {
+				resp.Body = io.NopCloser(bytes.NewBuffer(b))
+			}()
+		} else {
+			defer resp.Body.Close()
+		}
+		switch resp.StatusCode {
+		case http.StatusOK:
+			var (
+				body MethodBodyDisallowUnknownFieldsResponseBody
+				err  error
+			)
+			err = goahttp.DecodeStrict(decoder(resp), &body)
+			if err != nil {
+				return nil, goahttp.ErrDecodingError("ServiceBodyDisallowUnknownFields", "MethodBodyDisallowUnknownFields", err)
+			}
+			res := NewMethodBodyDisallowUnknownFieldsResultOK(&body)
+			return res, nil
+		default:
+			body, _ := io.ReadAll(resp.Body)
+			return nil, goahttp.ErrInvalidResponse("ServiceBodyDisallowUnknownFields", "MethodBodyDisallowUnknownFields", resp.StatusCode, string(body))
+		}
+	}
+}
+`
@@ -170,6 +170,42 @@ var ResultHeaderStringDSL = func() {
 	})
 }
 
+var ResultHeaderContentDispositionDSL = func() {
+	Service("ServiceHeaderContentDisposition", func() {
+		Method("MethodHeaderContentDisposition", func() {
+			Result(func() {
+				Attribute("filename", String)
+				Attribute("content_type", String)
+				Required("filename")
+			})
+			HTTP(func() {
+				GET("/")
+				Response(StatusOK, func() {
+					ContentDisposition("attachment", "filename")
+					Header("content_type:Content-Type")
+				})
+			})
+		})
+	})
+}
+
+var ResultBodyDisallowUnknownFieldsDSL = func() {
+	Service("ServiceBodyDisallowUnknownFields", func() {
+		Method("MethodBodyDisallowUnknownFields", func() {
+			Result(func() {
+				Attribute("a", String)
+				Attribute("b", Int)
+			})
+			HTTP(func() {
+				GET("/")
+				Response(StatusOK, func() {
+					DisallowUnknownFields()
+				})
+			})
+		})
+	})
+}
+
 var ResultHeaderBytesDSL = func() {
 	Service("ServiceHeaderBytes", func() {
 		Method("MethodHeaderBytes", func() {
@@ -1596,3 +1632,89 @@ var ValidateErrorResponseTypeDSL = func() {
 		})
 	})
 }
+
+var ResultBodyLinksDSL = func() {
+	var RT = ResultType("ResultTypeLinks", func() {
+		Attributes(func() {
+			Attribute("id", Int)
+			Attribute("owner_id", String)
+			Required("id")
+		})
+		Links(func() {
+			Link("self", "ServiceBodyLinks", "MethodShow")
+			Link("owner", "ServiceBodyLinks", "MethodOwner", "oid:owner_id")
+		})
+	})
+	Service("ServiceBodyLinks", func() {
+		Method("MethodShow", func() {
+			Payload(func() {
+				Attribute("id", Int)
+			})
+			Result(RT)
+			HTTP(func() {
+				GET("/items/{id}")
+			})
+		})
+		Method("MethodOwner", func() {
+			Payload(func() {
+				Attribute("oid", String)
+			})
+			HTTP(func() {
+				GET("/owners/{oid}")
+			})
+		})
+	})
+}
+
+var ResultBodyCollectionLinksDSL = func() {
+	var RT = ResultType("ResultTypeLinks", func() {
+		Attributes(func() {
+			Attribute("id", Int)
+		})
+		Links(func() {
+			Link("self", "ServiceBodyCollectionLinks", "MethodShow")
+		})
+	})
+	Service("ServiceBodyCollectionLinks", func() {
+		Method("MethodShow", func() {
+			Payload(func() {
+				Attribute("id", Int)
+			})
+			Result(RT)
+			HTTP(func() {
+				GET("/items/{id}")
+			})
+		})
+		Method("MethodList", func() {
+			Result(CollectionOf(RT))
+			HTTP(func() {
+				GET("/items")
+			})
+		})
+	})
+}
+
+var ResultBodyChunkedDSL = func() {
+	var RT = ResultType("ResultTypeChunked", func() {
+		Attributes(func() {
+			Attribute("a", String)
+			Attribute("b", String)
+		})
+		View("default", func() {
+			Attribute("a")
+			Attribute("b")
+		})
+		View("tiny", func() {
+			Attribute("a")
+		})
+	})
+	Service("ServiceBodyChunked", func() {
+		Method("MethodBodyChunked", func() {
+			Result(CollectionOf(RT))
+			HTTP(func() {
+				GET("/")
+				ChunkedResult(50)
+			})
+		})
+	})
+}
@@ -470,6 +470,22 @@ func EncodeMethodHeaderBoolRequiredDefaultResponse(encoder func(context.Context,
 }
 `
 
+var ResultHeaderContentDispositionEncodeCode = `// EncodeMethodHeaderContentDispositionResponse returns an encoder for
+// responses returned by the ServiceHeaderContentDisposition
+// MethodHeaderContentDisposition endpoint.
+func EncodeMethodHeaderContentDispositionResponse(encoder func(context.Context, http.ResponseWriter) goahttp.Encoder) func(context.Context, http.ResponseWriter, any) error {
+	return func(ctx context.Context, w http.ResponseWriter, v any) error {
+		res, _ := v.(*serviceheadercontentdisposition.MethodHeaderContentDispositionResult)
+		w.Header().Set("Content-Disposition", goahttp.FormatContentDisposition("attachment", res.Filename))
+		if res.ContentType != nil {
+			w.Header().Set("Content-Type", *res.ContentType)
+		}
+		w.WriteHeader(http.StatusOK)
+		return nil
+	}
+}
+`
+
 var ResultHeaderStringDefaultEncodeCode = `// EncodeMethodHeaderStringDefaultResponse returns an encoder for responses
 // returned by the ServiceHeaderStringDefault MethodHeaderStringDefault
 // endpoint.
@@ -840,6 +856,7 @@ func EncodeMethodBodyPrimitiveBoolResponse(encoder func(context.Context, http.Re
 	}
 }
 `
+
 var ResultBodyPrimitiveAnyEncodeCode = `// EncodeMethodBodyPrimitiveAnyResponse returns an encoder for responses
 // returned by the ServiceBodyPrimitiveAny MethodBodyPrimitiveAny endpoint.
 func EncodeMethodBodyPrimitiveAnyResponse(encoder func(context.Context, http.ResponseWriter) goahttp.Encoder) func(context.Context, http.ResponseWriter, any) error {
@@ -1063,3 +1080,100 @@ func EncodeMethodResultWithEmbeddedCustomPkgTypeDSLResponse(encoder func(context
 	}
 }
 `
+
+var ResultBodyLinksEncodeCode = `// EncodeMethodShowResponse returns an encoder for responses returned by the
+// ServiceBodyLinks MethodShow endpoint.
+func EncodeMethodShowResponse(encoder func(context.Context, http.ResponseWriter) goahttp.Encoder) func(context.Context, http.ResponseWriter, any) error {
+	return func(ctx context.Context, w http.ResponseWriter, v any) error {
+		res := v.(*servicebodylinksviews.Resulttypelinks)
+		setResulttypelinksLinks(res.Projected)
+		enc := encoder(ctx, w)
+		body := NewMethodShowResponseBody(res.Projected)
+		w.WriteHeader(http.StatusOK)
+		return enc.Encode(body)
+	}
+}
+
+// EncodeMethodOwnerResponse returns an encoder for responses returned by the
+// ServiceBodyLinks MethodOwner endpoint.
+func EncodeMethodOwnerResponse(encoder func(context.Context, http.ResponseWriter) goahttp.Encoder) func(context.Context, http.ResponseWriter, any) error {
+	return func(ctx context.Context, w http.ResponseWriter, v any) error {
+		w.WriteHeader(http.StatusNoContent)
+		return nil
+	}
+}
+`
+
+var ResultBodyLinksSetterCode = `// setResulttypelinksLinks sets the links of the Resulttypelinks result type to
+// the URLs of the related resources.
+func setResulttypelinksLinks(res *servicebodylinksviews.ResulttypelinksView) {
+	if res == nil {
+		return
+	}
+	links := make(map[string]map[string]string)
+	goahttp.AddLink(links, "self", "/items/{id}", map[string]any{"id": res.ID})
+	goahttp.AddLink(links, "owner", "/owners/{oid}", map[string]any{"oid": res.OwnerID})
+	res.Links = links
+}
+`
+
+var ResultBodyCollectionLinksEncodeCode = `// EncodeMethodShowResponse returns an encoder for responses returned by the
+// ServiceBodyCollectionLinks MethodShow endpoint.
+func EncodeMethodShowResponse(encoder func(context.Context, http.ResponseWriter) goahttp.Encoder) func(context.Context, http.ResponseWriter, any) error {
+	return func(ctx context.Context, w http.ResponseWriter, v any) error {
+		res := v.(*servicebodycollectionlinksviews.Resulttypelinks)
+		setResulttypelinksLinks(res.Projected)
+		enc := encoder(ctx, w)
+		body := NewMethodShowResponseBody(res.Projected)
+		w.WriteHeader(http.StatusOK)
+		return enc.Encode(body)
+	}
+}
+
+// EncodeMethodListResponse returns an encoder for responses returned by the
+// ServiceBodyCollectionLinks MethodList endpoint.
+func EncodeMethodListResponse(encoder func(context.Context, http.ResponseWriter) goahttp.Encoder) func(context.Context, http.ResponseWriter, any) error {
+	return func(ctx context.Context, w http.ResponseWriter, v any) error {
+		res := v.(servicebodycollectionlinksviews.ResulttypelinksCollection)
+		for _, r := range res.Projected {
+			setResulttypelinksLinks(r)
+		}
+		enc := encoder(ctx, w)
+		body := NewResulttypelinksResponseCollection(res.Projected)
+		w.WriteHeader(http.StatusOK)
+		return enc.Encode(body)
+	}
+}
+`
+
+var ResultBodyCollectionLinksSetterCode = `// setResulttypelinksLinks sets the links of the Resulttypelinks result type to
+// the URLs of the related resources.
+func setResulttypelinksLinks(res *servicebodycollectionlinksviews.ResulttypelinksView) {
+	if res == nil {
+		return
+	}
+	links := make(map[string]map[string]string)
+	goahttp.AddLink(links, "self", "/items/{id}", map[string]any{"id": res.ID})
+	res.Links = links
+}
+`
+
+var ResultBodyChunkedEncodeCode = `// EncodeMethodBodyChunkedResponse returns an encoder for responses returned by
+// the ServiceBodyChunked MethodBodyChunked endpoint.
+func EncodeMethodBodyChunkedResponse(encoder func(context.Context, http.ResponseWriter) goahttp.Encoder) func(context.Context, http.ResponseWriter, any) error {
+	return func(ctx context.Context, w http.ResponseWriter, v any) error {
+		res := v.(servicebodychunkedviews.ResulttypechunkedCollection)
+		w.Header().Set("goa-view", res.View)
+		enc := encoder(ctx, w)
+		var body any
+		switch res.View {
+		case "default", "":
+			body = NewResulttypechunkedResponseCollection(res.Projected)
+		case "tiny":
+			body = NewResulttypechunkedResponseTinyCollection(res.Projected)
+		}
+		w.WriteHeader(http.StatusOK)
+		return goahttp.EncodeChunked(w, enc, body, 50)
+	}
+}
+`
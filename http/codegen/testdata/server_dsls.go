@@ -83,6 +83,66 @@ var ServerPayloadResultDSL = func() {
 	})
 }
 
+var ServerFieldSelectionDSL = func() {
+	var Account = Type("Account", func() {
+		Attribute("name", String)
+	})
+	Service("ServiceFieldSelection", func() {
+		Method("MethodFieldSelection", func() {
+			Payload(func() {
+				Attribute("a", Boolean)
+			})
+			Result(func() {
+				Attribute("b", Boolean)
+				Attribute("account", Account)
+			})
+			Error("bad_request")
+			HTTP(func() {
+				GET("/")
+				Param("a")
+				FieldSelection("select")
+				Response(StatusOK)
+				Response("bad_request", StatusBadRequest)
+			})
+		})
+	})
+}
+
+var ServerCriteriaDSL = func() {
+	Service("ServiceCriteria", func() {
+		Method("MethodCriteria", func() {
+			Payload(func() {
+				Filter(func() {
+					Attribute("name", String)
+				})
+				Sort("name")
+			})
+			HTTP(func() {
+				GET("/")
+			})
+		})
+	})
+}
+
+var ServerCloudEventsDSL = func() {
+	Service("ServiceCloudEvents", func() {
+		Method("MethodCloudEvents", func() {
+			Payload(func() {
+				Attribute("id", String)
+				Attribute("type", String)
+				Attribute("data", String)
+			})
+			HTTP(func() {
+				POST("/events")
+				CloudEvents()
+				Header("id:ce-id")
+				Header("type:ce-type")
+				Body("data")
+			})
+		})
+	})
+}
+
 var ServerPayloadResultErrorDSL = func() {
 	Service("ServicePayloadResultError", func() {
 		Method("MethodPayloadResultError", func() {
@@ -248,6 +308,48 @@ var ServerSimpleRoutingDSL = func() {
 	})
 }
 
+var ServerBatchDSL = func() {
+	Service("ServiceBatch", func() {
+		Method("MethodBatch", func() {
+			Payload(func() {
+				Attribute("id", String)
+				Attribute("name", String)
+			})
+			Result(Int)
+			HTTP(func() {
+				POST("/accounts/{id}/bottles")
+				POST("/accounts/{id}/bottles/")
+				Batch()
+			})
+		})
+	})
+}
+
+var ServerCallbackDSL = func() {
+	var Event = Type("Event", func() {
+		Attribute("id", String)
+		Required("id")
+	})
+	Service("ServiceCallback", func() {
+		Method("MethodCallback", func() {
+			Payload(func() {
+				Attribute("url", String)
+			})
+			HTTP(func() {
+				POST("/")
+				Callback("onEvent", Event, func() {
+					Description("Sent when an event occurs.")
+					URL("{$request.body#/url}")
+					Retry(3, "500ms")
+				})
+				Callback("onCount", Int, func() {
+					URL("{$request.body#/url}")
+				})
+			})
+		})
+	})
+}
+
 var ServerTrailingSlashRoutingDSL = func() {
 	Service("ServiceTrailingSlashRoutingServer", func() {
 		Method("server-trailing-slash-routing", func() {
@@ -223,7 +223,9 @@ func (s *Server) Mount(mux goahttp.Muxer) {
 
 var ServerSimpleRoutingConstructorCode = `// Mount configures the mux to serve the ServiceSimpleRoutingServer endpoints.
 func Mount(mux goahttp.Muxer, h *Server) {
-	MountServerSimpleRoutingHandler(mux, h.ServerSimpleRouting)
+	MountServerSimpleRoutingHandler(mux, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		h.ServerSimpleRouting.ServeHTTP(w, r)
+	}))
 }
 
 // Mount configures the mux to serve the ServiceSimpleRoutingServer endpoints.
@@ -273,3 +275,52 @@ func MountServerTrailingSlashRoutingHandler(mux goahttp.Muxer, h http.Handler) {
 	mux.Handle("GET", "/trailing/slash/", f)
 }
 `
+
+var ServerMultiEndpointsUseCode = `// Use wraps the server handlers with the given middleware. Middlewares apply
+// in the reverse order of the calls to Use and UseMethod: the last middleware
+// added runs first. Use may be called after the server is mounted but not
+// while it serves requests.
+func (s *Server) Use(m func(http.Handler) http.Handler) {
+	s.MethodMultiEndpoints1 = m(s.MethodMultiEndpoints1)
+	s.MethodMultiEndpoints2 = m(s.MethodMultiEndpoints2)
+}
+
+// UseMethod wraps the handler of the method with the given name (as returned
+// by MethodNames) with the given middleware. Names of methods that are not
+// served are ignored. See Use for the order in which middlewares apply.
+func (s *Server) UseMethod(name string, m func(http.Handler) http.Handler) {
+	switch name {
+	case "MethodMultiEndpoints1":
+		s.MethodMultiEndpoints1 = m(s.MethodMultiEndpoints1)
+	case "MethodMultiEndpoints2":
+		s.MethodMultiEndpoints2 = m(s.MethodMultiEndpoints2)
+	}
+}
+`
+
+var ServerMultiBasesRoutesCode = `// Routes returns the routes of the ServiceMultiBases service endpoints. The
+// routes may be used to build the URLs of requests made to the endpoints, see
+// goahttp.RouteTable.
+func (s *Server) Routes() []*goahttp.Route {
+	return []*goahttp.Route{
+		{Service: "ServiceMultiBases", Method: "MethodMultiBases", Verb: "GET", Pattern: "/base_1/{id}"},
+		{Service: "ServiceMultiBases", Method: "MethodMultiBases", Verb: "GET", Pattern: "/base_2/{id}"},
+	}
+}
+`
+
+var ServerBatchRoutingCode = `// MountMethodBatchHandler configures the mux to serve the "ServiceBatch"
+// service "MethodBatch" endpoint.
+func MountMethodBatchHandler(mux goahttp.Muxer, h http.Handler) {
+	f, ok := h.(http.HandlerFunc)
+	if !ok {
+		f = func(w http.ResponseWriter, r *http.Request) {
+			h.ServeHTTP(w, r)
+		}
+	}
+	mux.Handle("POST", "/accounts/{id}/bottles", f)
+	mux.Handle("POST", "/accounts/{id}/bottles/", f)
+	batch := goahttp.NewBatchHandler(f)
+	mux.Handle("POST", "/accounts/{id}/bottles/batch", batch.ServeHTTP)
+}
+`
@@ -0,0 +1,10 @@
+package testdata
+
+var SLOMethodCode = `// UseSLOs records the requests handled by the ServiceSLO methods configured
+// with the "slo:target" meta with the given tracker so that it tracks the
+// compliance of their service level objectives.
+func (s *Server) UseSLOs(t *middleware.SLOTracker) {
+	s.List = httpmdlwr.SLO(t, &middleware.SLO{Service: "ServiceSLO", Method: "List", Latency: 300 * time.Millisecond, Target: 0.9995})(s.List)
+	s.Show = httpmdlwr.SLO(t, &middleware.SLO{Service: "ServiceSLO", Method: "Show", Target: 0.99})(s.Show)
+}
+`
@@ -0,0 +1,23 @@
+package testdata
+
+import (
+	. "goa.design/goa/v3/dsl"
+)
+
+var SLOMethodDSL = func() {
+	Service("ServiceSLO", func() {
+		Meta("slo:target", "99")
+		Method("List", func() {
+			Meta("slo:target", "99.95")
+			Meta("slo:latency", "300ms")
+			HTTP(func() {
+				GET("/list")
+			})
+		})
+		Method("Show", func() {
+			HTTP(func() {
+				GET("/show")
+			})
+		})
+	})
+}
@@ -96,14 +96,12 @@ func goTypeDef(scope *codegen.NameScope, att *expr.AttributeExpr, ptr, useDefaul
 	}
 }
 
-// attributeTags computes the struct field tags.
-func attributeTags(parent, att *expr.AttributeExpr, t string, optional bool) string {
-	if tags := codegen.AttributeTags(parent, att); tags != "" {
-		return tags
-	}
-	var o string
+// attributeTags computes the struct field tags. The form, json and xml tags
+// may be overridden and other tags (e.g. bson or db) added with the
+// "struct:tag:xxx" metadata.
+func attributeTags(_, att *expr.AttributeExpr, t string, optional bool) string {
 	if optional {
-		o = ",omitempty"
+		t += ",omitempty"
 	}
-	return fmt.Sprintf(" `form:\"%s%s\" json:\"%s%s\" xml:\"%s%s\"`", t, o, t, o, t, o)
+	return codegen.FieldTags(att, map[string]string{"form": t, "json": t, "xml": t})
 }
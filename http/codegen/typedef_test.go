@@ -56,6 +56,10 @@ func TestGoTypeDef(t *testing.T) {
 					Name:      "custom_tag",
 					Attribute: &expr.AttributeExpr{Type: expr.String, Meta: expr.MetaExpr{"struct:tag:foo": []string{"bar"}}},
 				},
+				&expr.NamedAttributeExpr{
+					Name:      "custom_json_tag",
+					Attribute: &expr.AttributeExpr{Type: expr.String, Meta: expr.MetaExpr{"struct:tag:json": []string{"json_tag"}}},
+				},
 			},
 			Validation: &expr.ValidationExpr{
 				Required: []string{"required", "required_bytes", "required_any"},
@@ -96,7 +100,8 @@ var (
 	DefaultBytes []byte ` + "`" + `form:"default_bytes,omitempty" json:"default_bytes,omitempty" xml:"default_bytes,omitempty"` + "`" + `
 	DefaultAny any ` + "`" + `form:"default_any,omitempty" json:"default_any,omitempty" xml:"default_any,omitempty"` + "`" + `
 	CustomType *pkg.String ` + "`" + `form:"custom_type,omitempty" json:"custom_type,omitempty" xml:"custom_type,omitempty"` + "`" + `
-	CustomTag *string ` + "`" + `foo:"bar"` + "`" + `
+	CustomTag *string ` + "`" + `foo:"bar" form:"custom_tag,omitempty" json:"custom_tag,omitempty" xml:"custom_tag,omitempty"` + "`" + `
+	CustomJSONTag *string ` + "`" + `form:"custom_json_tag,omitempty" json:"json_tag" xml:"custom_json_tag,omitempty"` + "`" + `
 }`
 
 	mixedUseDefault = `struct {
@@ -110,7 +115,8 @@ var (
 	DefaultBytes []byte ` + "`" + `form:"default_bytes" json:"default_bytes" xml:"default_bytes"` + "`" + `
 	DefaultAny any ` + "`" + `form:"default_any" json:"default_any" xml:"default_any"` + "`" + `
 	CustomType *pkg.String ` + "`" + `form:"custom_type,omitempty" json:"custom_type,omitempty" xml:"custom_type,omitempty"` + "`" + `
-	CustomTag *string ` + "`" + `foo:"bar"` + "`" + `
+	CustomTag *string ` + "`" + `foo:"bar" form:"custom_tag,omitempty" json:"custom_tag,omitempty" xml:"custom_tag,omitempty"` + "`" + `
+	CustomJSONTag *string ` + "`" + `form:"custom_json_tag,omitempty" json:"json_tag" xml:"custom_json_tag,omitempty"` + "`" + `
 }`
 
 	mixedUsePointer = `struct {
@@ -124,6 +130,7 @@ var (
 	DefaultBytes []byte ` + "`" + `form:"default_bytes,omitempty" json:"default_bytes,omitempty" xml:"default_bytes,omitempty"` + "`" + `
 	DefaultAny any ` + "`" + `form:"default_any,omitempty" json:"default_any,omitempty" xml:"default_any,omitempty"` + "`" + `
 	CustomType *pkg.String ` + "`" + `form:"custom_type,omitempty" json:"custom_type,omitempty" xml:"custom_type,omitempty"` + "`" + `
-	CustomTag *string ` + "`" + `foo:"bar"` + "`" + `
+	CustomTag *string ` + "`" + `foo:"bar" form:"custom_tag,omitempty" json:"custom_tag,omitempty" xml:"custom_tag,omitempty"` + "`" + `
+	CustomJSONTag *string ` + "`" + `form:"custom_json_tag,omitempty" json:"json_tag" xml:"custom_json_tag,omitempty"` + "`" + `
 }`
 )
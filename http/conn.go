@@ -0,0 +1,144 @@
+package http
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"sync/atomic"
+	"time"
+)
+
+type (
+	// tcpListener is a listener that sets the TCP options configured with
+	// WithTCPNoDelay, WithTCPLinger and WithTCPKeepAlive on the accepted
+	// connections.
+	tcpListener struct {
+		net.Listener
+		noDelay   *bool
+		linger    *int
+		keepAlive time.Duration
+	}
+
+	// connRequestsKey is the context key used to store the number of
+	// requests served on a connection.
+	connRequestsKey struct{}
+)
+
+// WithMaxRequestsPerConn makes Run close the HTTP/1.x connections once they
+// have served n requests: the response to the n-th request has the
+// "Connection: close" header so that clients open a new connection, possibly
+// to another instance behind the load balancer. This spreads the load of the
+// long-lived connections across the instances as they are added.
+//
+// Example:
+//
+//	err := goahttp.Run(ctx, srv, goahttp.WithMaxRequestsPerConn(1000))
+func WithMaxRequestsPerConn(n int) RunOption {
+	return func(o *runOptions) { o.maxConnRequests = n }
+}
+
+// WithKeepAlives makes Run close the HTTP/1.x connections after the current
+// request whenever enabled returns false, e.g. while the server sheds load.
+// Keep-alives may also be disabled for all the connections with the
+// SetKeepAlivesEnabled method of the server.
+//
+// Example:
+//
+//	err := goahttp.Run(ctx, srv, goahttp.WithKeepAlives(func() bool { return !shedder.Shedding() }))
+func WithKeepAlives(enabled func() bool) RunOption {
+	return func(o *runOptions) { o.keepAlives = enabled }
+}
+
+// WithTCPNoDelay sets the TCP_NODELAY option of the accepted connections,
+// false enables Nagle's algorithm. Go enables TCP_NODELAY by default.
+func WithTCPNoDelay(noDelay bool) RunOption {
+	return func(o *runOptions) { o.tcpNoDelay = &noDelay }
+}
+
+// WithTCPLinger sets the SO_LINGER option of the accepted connections, see
+// net.TCPConn.SetLinger: a negative value lets the operating system send the
+// pending data in the background, 0 discards it and resets the connection on
+// close and a positive value blocks close for at most that many seconds.
+func WithTCPLinger(sec int) RunOption {
+	return func(o *runOptions) { o.tcpLinger = &sec }
+}
+
+// WithTCPKeepAlive sets the period of the TCP keep-alive probes of the
+// accepted connections, a negative value disables the probes.
+func WithTCPKeepAlive(d time.Duration) RunOption {
+	return func(o *runOptions) { o.tcpKeepAlive = d }
+}
+
+// connListener returns l wrapped so that the TCP options are set on the
+// accepted connections if any.
+func (o *runOptions) connListener(l net.Listener) net.Listener {
+	if o.tcpNoDelay == nil && o.tcpLinger == nil && o.tcpKeepAlive == 0 {
+		return l
+	}
+	return &tcpListener{Listener: l, noDelay: o.tcpNoDelay, linger: o.tcpLinger, keepAlive: o.tcpKeepAlive}
+}
+
+// connHandler configures srv so that the connections are closed as
+// configured with WithMaxRequestsPerConn and WithKeepAlives.
+func (o *runOptions) connHandler(srv *http.Server) {
+	if o.maxConnRequests <= 0 && o.keepAlives == nil {
+		return
+	}
+	if o.maxConnRequests > 0 {
+		connContext := srv.ConnContext
+		srv.ConnContext = func(ctx context.Context, c net.Conn) context.Context {
+			if connContext != nil {
+				ctx = connContext(ctx, c)
+			}
+			return context.WithValue(ctx, connRequestsKey{}, new(int64))
+		}
+	}
+	h := srv.Handler
+	if h == nil {
+		h = http.DefaultServeMux
+	}
+	srv.Handler = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.ProtoMajor == 1 && o.closeConn(r) {
+			w.Header().Set("Connection", "close")
+		}
+		h.ServeHTTP(w, r)
+	})
+}
+
+// closeConn returns true if the connection of r must be closed after the
+// response is written.
+func (o *runOptions) closeConn(r *http.Request) bool {
+	if o.keepAlives != nil && !o.keepAlives() {
+		return true
+	}
+	if n, ok := r.Context().Value(connRequestsKey{}).(*int64); ok {
+		return atomic.AddInt64(n, 1) >= int64(o.maxConnRequests)
+	}
+	return false
+}
+
+// Accept accepts a connection and sets its TCP options. The options are set
+// on a best effort basis: errors would otherwise stop the server.
+func (l *tcpListener) Accept() (net.Conn, error) {
+	c, err := l.Listener.Accept()
+	if err != nil {
+		return nil, err
+	}
+	tc, ok := c.(*net.TCPConn)
+	if !ok {
+		return c, nil
+	}
+	if l.noDelay != nil {
+		tc.SetNoDelay(*l.noDelay) // nolint: errcheck
+	}
+	if l.linger != nil {
+		tc.SetLinger(*l.linger) // nolint: errcheck
+	}
+	if l.keepAlive < 0 {
+		tc.SetKeepAlive(false) // nolint: errcheck
+	} else if l.keepAlive > 0 {
+		tc.SetKeepAlive(true)              // nolint: errcheck
+		tc.SetKeepAlivePeriod(l.keepAlive) // nolint: errcheck
+	}
+	return c, nil
+}
@@ -0,0 +1,71 @@
+package http
+
+import (
+	"context"
+	"io"
+	"net"
+	"net/http"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestRunConnControls(t *testing.T) {
+	var shedding int32
+	cases := []struct {
+		Name          string
+		Options       []RunOption
+		Shedding      bool
+		ExpectedConns int32
+	}{
+		{"default", nil, false, 1},
+		{"max-requests", []RunOption{WithMaxRequestsPerConn(2)}, false, 3},
+		{"keep-alives", []RunOption{WithKeepAlives(func() bool { return atomic.LoadInt32(&shedding) == 0 })}, false, 1},
+		{"keep-alives-shedding", []RunOption{WithKeepAlives(func() bool { return atomic.LoadInt32(&shedding) == 0 })}, true, 6},
+		{"tcp-options", []RunOption{WithTCPNoDelay(false), WithTCPLinger(0), WithTCPKeepAlive(time.Minute)}, false, 1},
+	}
+	for _, c := range cases {
+		t.Run(c.Name, func(t *testing.T) {
+			if c.Shedding {
+				atomic.StoreInt32(&shedding, 1)
+				defer atomic.StoreInt32(&shedding, 0)
+			}
+			l, err := net.Listen("tcp", "127.0.0.1:0")
+			if err != nil {
+				t.Fatal(err)
+			}
+			var conns int32
+			srv := &http.Server{
+				Handler: http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+					w.Write([]byte("ok")) // nolint: errcheck
+				}),
+				ConnState: func(_ net.Conn, s http.ConnState) {
+					if s == http.StateNew {
+						atomic.AddInt32(&conns, 1)
+					}
+				},
+				ReadHeaderTimeout: time.Second,
+			}
+			ctx, cancel := context.WithCancel(context.Background())
+			done := make(chan error)
+			go func() { done <- Run(ctx, srv, append(c.Options, WithListener(l))...) }()
+
+			client := &http.Client{Transport: &http.Transport{}}
+			for i := 0; i < 6; i++ {
+				resp, err := client.Get("http://" + l.Addr().String())
+				if err != nil {
+					t.Fatal(err)
+				}
+				io.Copy(io.Discard, resp.Body) // nolint: errcheck
+				resp.Body.Close()
+			}
+			cancel()
+			if err := <-done; err != nil {
+				t.Fatal(err)
+			}
+			if got := atomic.LoadInt32(&conns); got != c.ExpectedConns {
+				t.Errorf("got %d connections, expected %d", got, c.ExpectedConns)
+			}
+		})
+	}
+}
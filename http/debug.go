@@ -0,0 +1,164 @@
+package http
+
+import (
+	"expvar"
+	"io"
+	"net"
+	"net/http"
+	"net/http/pprof"
+	"strings"
+)
+
+type (
+	// DebugOption configures MountDebug.
+	DebugOption func(*debugOptions)
+
+	// debugOptions contains the MountDebug options.
+	debugOptions struct {
+		prefix    string
+		authorize func(*http.Request) bool
+		routes    RouteTable
+		toggles   []*debugToggle
+	}
+
+	// debugToggle is a runtime setting exposed by MountDebug.
+	debugToggle struct {
+		name string
+		get  func() string
+		set  func(string) error
+	}
+)
+
+// MountDebug mounts debug handlers on mux so that they are served by the
+// application server instead of requiring a second server. The handlers are
+// mounted under "/debug" unless configured otherwise with WithDebugPrefix:
+//
+//   - /debug/pprof/ serves the net/http/pprof profiles.
+//   - /debug/vars serves the variables published with expvar.
+//   - /debug/routes lists the routes given with WithDebugRoutes.
+//   - /debug/<name> reads (GET) or updates (PUT with the new value as body)
+//     the runtime toggles added with WithDebugToggle, e.g. the log level.
+//
+// The handlers respond with 403 Forbidden unless the request is authorized by
+// the function given to WithDebugAuth: all the requests are denied if
+// MountDebug is not configured with WithDebugAuth. DebugLoopbackAuth
+// authorizes the requests coming from the loopback interface.
+//
+// Example:
+//
+//	var level atomic.Value
+//	level.Store("info")
+//	goahttp.MountDebug(mux,
+//	    goahttp.WithDebugAuth(func(r *http.Request) bool { return r.Header.Get("X-Admin-Token") == token }),
+//	    goahttp.WithDebugRoutes(servers.Routes()),
+//	    goahttp.WithDebugToggle("loglevel", func() string { return level.Load().(string) }, setLevel))
+func MountDebug(mux Muxer, opts ...DebugOption) {
+	o := &debugOptions{prefix: "/debug", authorize: func(*http.Request) bool { return false }}
+	for _, opt := range opts {
+		opt(o)
+	}
+	prefix := strings.TrimSuffix(o.prefix, "/")
+	handle := func(method, path string, h http.Handler) {
+		mux.Handle(method, prefix+path, func(w http.ResponseWriter, r *http.Request) {
+			if !o.authorize(r) {
+				http.Error(w, http.StatusText(http.StatusForbidden), http.StatusForbidden)
+				return
+			}
+			h.ServeHTTP(w, r)
+		})
+	}
+
+	// pprof.Index expects the profiles to be served under /debug/pprof/.
+	index := Replace(prefix+"/pprof/", "/debug/pprof/", http.HandlerFunc(pprof.Index))
+	handle("GET", "/pprof/", index)
+	handle("GET", "/pprof/cmdline", http.HandlerFunc(pprof.Cmdline))
+	handle("GET", "/pprof/profile", http.HandlerFunc(pprof.Profile))
+	handle("GET", "/pprof/symbol", http.HandlerFunc(pprof.Symbol))
+	handle("POST", "/pprof/symbol", http.HandlerFunc(pprof.Symbol))
+	handle("GET", "/pprof/trace", http.HandlerFunc(pprof.Trace))
+	for _, name := range []string{"allocs", "block", "goroutine", "heap", "mutex", "threadcreate"} {
+		handle("GET", "/pprof/"+name, pprof.Handler(name))
+	}
+	handle("GET", "/vars", expvar.Handler())
+	if o.routes != nil {
+		handle("GET", "/routes", http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+			w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+			io.WriteString(w, o.routes.String()) // nolint: errcheck
+		}))
+	}
+	for _, t := range o.toggles {
+		handle("GET", "/"+t.name, http.HandlerFunc(t.serveGet))
+		handle("PUT", "/"+t.name, http.HandlerFunc(t.servePut))
+	}
+}
+
+// WithDebugPrefix sets the path prefix of the debug handlers, "/debug" by
+// default.
+func WithDebugPrefix(prefix string) DebugOption {
+	return func(o *debugOptions) { o.prefix = prefix }
+}
+
+// WithDebugAuth sets the function that authorizes the requests made to the
+// debug handlers.
+func WithDebugAuth(authorize func(*http.Request) bool) DebugOption {
+	return func(o *debugOptions) { o.authorize = authorize }
+}
+
+// WithDebugRoutes serves the given route table under /debug/routes.
+func WithDebugRoutes(routes RouteTable) DebugOption {
+	return func(o *debugOptions) { o.routes = routes }
+}
+
+// WithDebugToggle exposes a runtime setting under /debug/<name>: GET requests
+// return the value returned by get and PUT requests call set with the request
+// body. Errors returned by set are written in 400 Bad Request responses.
+func WithDebugToggle(name string, get func() string, set func(string) error) DebugOption {
+	return func(o *debugOptions) {
+		o.toggles = append(o.toggles, &debugToggle{name: name, get: get, set: set})
+	}
+}
+
+// serveGet writes the current value of the toggle.
+func (t *debugToggle) serveGet(w http.ResponseWriter, _ *http.Request) {
+	w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+	io.WriteString(w, t.get()+"\n") // nolint: errcheck
+}
+
+// servePut sets the value of the toggle to the request body.
+func (t *debugToggle) servePut(w http.ResponseWriter, r *http.Request) {
+	b, err := io.ReadAll(io.LimitReader(r.Body, 1024))
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	if err := t.set(strings.TrimSpace(string(b))); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	t.serveGet(w, r)
+}
+
+// DebugLoopbackAuth authorizes the requests made to the debug handlers that
+// come from the loopback interface and that carry no "Forwarded",
+// "X-Forwarded-For" or "X-Real-IP" header. Note that the requests relayed by a
+// reverse proxy running on the same host also come from the loopback
+// interface: DebugLoopbackAuth relies on the proxy setting one of these
+// headers to deny them, use an authorization based on credentials if the
+// proxy does not.
+//
+// Example:
+//
+//	goahttp.MountDebug(mux, goahttp.WithDebugAuth(goahttp.DebugLoopbackAuth))
+func DebugLoopbackAuth(r *http.Request) bool {
+	for _, h := range []string{"Forwarded", "X-Forwarded-For", "X-Real-IP"} {
+		if r.Header.Get(h) != "" {
+			return false
+		}
+	}
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		host = r.RemoteAddr
+	}
+	ip := net.ParseIP(host)
+	return ip != nil && ip.IsLoopback()
+}
@@ -0,0 +1,73 @@
+package http
+
+import (
+	"errors"
+	"io"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestMountDebug(t *testing.T) {
+	level := "info"
+	mux := NewMuxer()
+	MountDebug(mux,
+		WithDebugPrefix("/admin/"),
+		WithDebugAuth(DebugLoopbackAuth),
+		WithDebugRoutes(RouteTable{{Service: "svc", Method: "list", Verb: "GET", Pattern: "/items"}}),
+		WithDebugToggle("loglevel", func() string { return level }, func(v string) error {
+			if v != "debug" && v != "info" {
+				return errors.New("invalid level")
+			}
+			level = v
+			return nil
+		}))
+	cases := []struct {
+		name               string
+		method, path, body string
+		remote             string
+		forwarded          string
+		status             int
+		contains           string
+	}{
+		{"pprof", "GET", "/admin/pprof/", "", "127.0.0.1:1234", "", 200, "goroutine"},
+		{"pprof-profile", "GET", "/admin/pprof/heap?debug=1", "", "127.0.0.1:1234", "", 200, "heap profile"},
+		{"vars", "GET", "/admin/vars", "", "[::1]:1234", "", 200, "memstats"},
+		{"routes", "GET", "/admin/routes", "", "127.0.0.1:1234", "", 200, "/items (svc.list)"},
+		{"toggle-get", "GET", "/admin/loglevel", "", "127.0.0.1:1234", "", 200, "info"},
+		{"toggle-put", "PUT", "/admin/loglevel", "debug\n", "127.0.0.1:1234", "", 200, "debug"},
+		{"toggle-invalid", "PUT", "/admin/loglevel", "trace", "127.0.0.1:1234", "", 400, "invalid level"},
+		{"forbidden", "GET", "/admin/vars", "", "10.0.0.1:1234", "", 403, "Forbidden"},
+		{"forwarded", "GET", "/admin/vars", "", "127.0.0.1:1234", "203.0.113.7", 403, "Forbidden"},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			req := httptest.NewRequest(c.method, c.path, strings.NewReader(c.body))
+			req.RemoteAddr = c.remote
+			if c.forwarded != "" {
+				req.Header.Set("X-Forwarded-For", c.forwarded)
+			}
+			w := httptest.NewRecorder()
+			mux.ServeHTTP(w, req)
+			b, _ := io.ReadAll(w.Body)
+			if w.Code != c.status || !strings.Contains(string(b), c.contains) {
+				t.Errorf("got %d %q, expected %d containing %q", w.Code, b, c.status, c.contains)
+			}
+		})
+	}
+	if level != "debug" {
+		t.Errorf("got level %q, expected %q", level, "debug")
+	}
+}
+
+func TestMountDebugDenyByDefault(t *testing.T) {
+	mux := NewMuxer()
+	MountDebug(mux)
+	req := httptest.NewRequest("GET", "/debug/vars", nil)
+	req.RemoteAddr = "127.0.0.1:1234"
+	w := httptest.NewRecorder()
+	mux.ServeHTTP(w, req)
+	if w.Code != 403 {
+		t.Errorf("got status %d, expected 403", w.Code)
+	}
+}
@@ -0,0 +1,41 @@
+package http
+
+import (
+	"mime"
+	"strings"
+)
+
+// FormatContentDisposition returns the value of a Content-Disposition header
+// with the given disposition type, "attachment" or "inline", and filename. The
+// filename is encoded with the RFC 5987 "filename*" parameter when it contains
+// non-ASCII characters. The generated servers use it to write the headers of
+// the responses defined with the ContentDisposition DSL.
+func FormatContentDisposition(disposition, filename string) string {
+	if filename == "" {
+		return disposition
+	}
+	if v := mime.FormatMediaType(disposition, map[string]string{"filename": filename}); v != "" {
+		return v
+	}
+	return disposition
+}
+
+// ParseContentDisposition returns the filename of the given Content-Disposition
+// header value stripped of any directory component. It returns an empty string
+// if the value is malformed or does not define a filename. The generated
+// clients use it to decode the headers of the responses defined with the
+// ContentDisposition DSL.
+func ParseContentDisposition(v string) string {
+	if v == "" {
+		return ""
+	}
+	_, params, err := mime.ParseMediaType(v)
+	if err != nil {
+		return ""
+	}
+	name := params["filename"]
+	if i := strings.LastIndexAny(name, `/\`); i >= 0 {
+		name = name[i+1:]
+	}
+	return name
+}
@@ -0,0 +1,43 @@
+package http
+
+import "testing"
+
+func TestContentDisposition(t *testing.T) {
+	cases := []struct {
+		Name        string
+		Disposition string
+		Filename    string
+		Expected    string
+	}{
+		{"attachment", "attachment", "report.pdf", "attachment; filename=report.pdf"},
+		{"inline", "inline", "logo.png", "inline; filename=logo.png"},
+		{"quoted", "attachment", "my report.pdf", `attachment; filename="my report.pdf"`},
+		{"non-ascii", "attachment", "résumé.pdf", "attachment; filename*=utf-8''r%C3%A9sum%C3%A9.pdf"},
+		{"no-filename", "attachment", "", "attachment"},
+	}
+	for _, c := range cases {
+		t.Run(c.Name, func(t *testing.T) {
+			v := FormatContentDisposition(c.Disposition, c.Filename)
+			if v != c.Expected {
+				t.Errorf("got %q, expected %q", v, c.Expected)
+			}
+			if name := ParseContentDisposition(v); name != c.Filename {
+				t.Errorf("got filename %q, expected %q", name, c.Filename)
+			}
+		})
+	}
+}
+
+func TestParseContentDispositionStripsDirectories(t *testing.T) {
+	cases := map[string]string{
+		`attachment; filename="../../etc/passwd"`: "passwd",
+		`attachment; filename="C:\\tmp\\x.txt"`:   "x.txt",
+		`attachment; filename=`:                   "",
+		`;;`:                                      "",
+	}
+	for v, expected := range cases {
+		if name := ParseContentDisposition(v); name != expected {
+			t.Errorf("%s: got %q, expected %q", v, name, expected)
+		}
+	}
+}
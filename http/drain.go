@@ -0,0 +1,134 @@
+package http
+
+import (
+	"context"
+	"net"
+	"sync"
+	"time"
+)
+
+type (
+	// Drainer notifies the long-lived requests - websocket connections and
+	// server-sent event streams - that the server is shutting down so that
+	// they can flush their state and complete before the connections are
+	// closed. Drainer is safe for concurrent use.
+	Drainer struct {
+		mu        sync.Mutex
+		next      int
+		callbacks map[int]func()
+		done      chan struct{}
+	}
+
+	// drainerKey is the context key used to store the drainer.
+	drainerKey struct{}
+)
+
+// NewDrainer returns a drainer.
+func NewDrainer() *Drainer {
+	return &Drainer{callbacks: make(map[int]func()), done: make(chan struct{})}
+}
+
+// WithDrain makes Run shut the server down in two phases: Run stops accepting
+// new connections and calls the callbacks registered with d, then waits at
+// most grace for the requests in flight to complete before closing the
+// connections that remain. The overall shutdown, including the shutdown
+// hooks, is still bounded by the shutdown timeout. Run stores d in the context
+// of the requests so that the handlers can register callbacks with OnDrain.
+//
+// Example:
+//
+//	err := goahttp.Run(ctx, srv, goahttp.WithDrain(goahttp.NewDrainer(), 10*time.Second))
+func WithDrain(d *Drainer, grace time.Duration) RunOption {
+	return func(o *runOptions) {
+		o.drainer = d
+		o.drainGrace = grace
+	}
+}
+
+// OnDrain registers fn with the drainer stored in ctx by Run so that it is
+// called when the server starts shutting down, fn is called right away if
+// the server is already shutting down. Streaming endpoints typically call
+// OnDrain to send a final message and close their stream. The returned
+// function unregisters fn and must be called once the request completes.
+// OnDrain does nothing if there is no drainer in ctx.
+//
+// Example:
+//
+//	func (s *svc) Subscribe(ctx context.Context, stream events.SubscribeServerStream) error {
+//		stop := make(chan struct{})
+//		defer goahttp.OnDrain(ctx, func() { close(stop) })()
+//		...
+//	}
+func OnDrain(ctx context.Context, fn func()) (unregister func()) {
+	d, ok := ctx.Value(drainerKey{}).(*Drainer)
+	if !ok {
+		return func() {}
+	}
+	return d.OnDrain(fn)
+}
+
+// Draining returns a channel that is closed when the server stored in ctx
+// starts shutting down, nil if there is no drainer in ctx.
+func Draining(ctx context.Context) <-chan struct{} {
+	d, ok := ctx.Value(drainerKey{}).(*Drainer)
+	if !ok {
+		return nil
+	}
+	return d.Done()
+}
+
+// OnDrain registers fn so that it is called when Drain is called, fn is
+// called right away if Drain was already called. The returned function
+// unregisters fn.
+func (d *Drainer) OnDrain(fn func()) (unregister func()) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	select {
+	case <-d.done:
+		go fn()
+		return func() {}
+	default:
+	}
+	id := d.next
+	d.next++
+	d.callbacks[id] = fn
+	return func() {
+		d.mu.Lock()
+		defer d.mu.Unlock()
+		delete(d.callbacks, id)
+	}
+}
+
+// Drain closes the Done channel and calls the registered callbacks
+// concurrently. Calling Drain more than once has no effect.
+func (d *Drainer) Drain() {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	select {
+	case <-d.done:
+		return
+	default:
+	}
+	close(d.done)
+	for id, fn := range d.callbacks {
+		delete(d.callbacks, id)
+		go fn()
+	}
+}
+
+// Done returns a channel that is closed when Drain is called.
+func (d *Drainer) Done() <-chan struct{} {
+	return d.done
+}
+
+// baseContext returns a function that stores d in the base context returned
+// by base.
+func (d *Drainer) baseContext(base func(net.Listener) context.Context) func(net.Listener) context.Context {
+	return func(l net.Listener) context.Context {
+		ctx := context.Background()
+		if base != nil {
+			ctx = base(l)
+		}
+		return context.WithValue(ctx, drainerKey{}, d)
+	}
+}
@@ -0,0 +1,89 @@
+package http
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"testing"
+	"time"
+)
+
+func TestRunDrain(t *testing.T) {
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	started := make(chan struct{}, 2)
+	srv := &http.Server{Handler: http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/stuck" {
+			started <- struct{}{}
+			<-r.Context().Done()
+			return
+		}
+		// streaming request that completes when notified
+		stop := make(chan struct{})
+		defer OnDrain(r.Context(), func() { close(stop) })()
+		started <- struct{}{}
+		<-stop
+		w.Write([]byte("drained")) // nolint: errcheck
+	}), ReadHeaderTimeout: time.Second}
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error)
+	go func() {
+		done <- Run(ctx, srv, WithListener(l), WithDrain(NewDrainer(), 100*time.Millisecond))
+	}()
+
+	bodies := make(chan string, 2)
+	for _, path := range []string{"/stream", "/stuck"} {
+		go func(path string) {
+			resp, err := http.Get("http://" + l.Addr().String() + path)
+			if err != nil {
+				bodies <- "error"
+				return
+			}
+			defer resp.Body.Close()
+			b := make([]byte, 16)
+			n, _ := resp.Body.Read(b)
+			bodies <- string(b[:n])
+		}(path)
+	}
+	<-started
+	<-started
+	start := time.Now()
+	cancel()
+	if err := <-done; err != nil {
+		t.Errorf("got error %v, expected nil", err)
+	}
+	if d := time.Since(start); d > 2*time.Second {
+		t.Errorf("shutdown took %s, expected the stuck request to be closed after the grace period", d)
+	}
+	got := map[string]bool{<-bodies: true, <-bodies: true}
+	if !got["drained"] || !got["error"] {
+		t.Errorf("got bodies %v, expected the stream to be drained and the stuck request closed", got)
+	}
+}
+
+func TestDrainer(t *testing.T) {
+	d := NewDrainer()
+	called := make(chan string, 3)
+	d.OnDrain(func() { called <- "a" })
+	unregister := d.OnDrain(func() { called <- "b" })
+	unregister()
+	d.Drain()
+	d.Drain()
+	if got := <-called; got != "a" {
+		t.Errorf("got callback %q, expected a", got)
+	}
+	d.OnDrain(func() { called <- "late" })
+	if got := <-called; got != "late" {
+		t.Errorf("got callback %q, expected late", got)
+	}
+	select {
+	case <-d.Done():
+	default:
+		t.Error("expected Done to be closed")
+	}
+	if Draining(context.Background()) != nil {
+		t.Error("expected no drainer in the background context")
+	}
+}
@@ -23,6 +23,22 @@ const (
 	// response Content-Type header when explicitly set in the DSL. The value
 	// may be used by encoders to set the header appropriately.
 	ContentTypeKey
+
+	// jsonapiKey is the context key used to store the JSON:API rendering
+	// information set by WithJSONAPI.
+	jsonapiKey
+
+	// fieldsKey is the context key used to store the fields selected with
+	// WithFieldSelection.
+	fieldsKey
+
+	// languagesKey is the context key used to store the language tags set
+	// by WithLanguages.
+	languagesKey
+
+	// timeZoneKey is the context key used to store the time zone set by
+	// WithTimeZone.
+	timeZoneKey
 )
 
 type (
@@ -95,13 +111,18 @@ func RequestDecoder(r *http.Request) Decoder {
 //
 // ResponseEncoder defaults to the JSON encoder if the context AcceptTypeKey or
 // ContentTypeKey value does not match any of the supported mime types or is
-// missing altogether.
+// missing altogether. JSON responses are rendered as JSON:API documents with
+// the application/vnd.api+json content type if the context was initialized
+// with WithJSONAPI. JSON responses only render the fields selected with
+// WithFieldSelection if any.
 func ResponseEncoder(ctx context.Context, w http.ResponseWriter) Encoder {
 	negotiate := func(a string) (Encoder, string) {
 		switch a {
 		case "", "application/json":
 			// default to JSON
 			return json.NewEncoder(w), "application/json"
+		case JSONAPIContentType:
+			return json.NewEncoder(w), JSONAPIContentType
 		case "application/xml":
 			return xml.NewEncoder(w), "application/xml"
 		case "application/gob":
@@ -147,8 +168,7 @@ func ResponseEncoder(ctx context.Context, w http.ResponseWriter) Encoder {
 					enc = json.NewEncoder(w)
 				}
 			}
-			SetContentType(w, mt)
-			return enc
+			return withFieldSelection(ctx, w, withJSONAPI(ctx, w, enc, mt))
 		}
 		// If Accept header exists in the request, infer the response encoder
 		// from the header value.
@@ -162,6 +182,18 @@ func ResponseEncoder(ctx context.Context, w http.ResponseWriter) Encoder {
 			enc, mt = negotiate("")
 		}
 	}
+	return withFieldSelection(ctx, w, withJSONAPI(ctx, w, enc, mt))
+}
+
+// withJSONAPI sets the response content type and returns enc unless the
+// context was initialized with WithJSONAPI and mt is a JSON media type in
+// which case it returns a JSON:API encoder and sets the content type
+// accordingly.
+func withJSONAPI(ctx context.Context, w http.ResponseWriter, enc Encoder, mt string) Encoder {
+	if c := jsonapiFromContext(ctx); c != nil && (mt == "application/json" || mt == JSONAPIContentType) {
+		enc = &jsonapiEncoder{w: w, res: c.res, fields: c.fields, errors: c.errors}
+		mt = JSONAPIContentType
+	}
 	SetContentType(w, mt)
 	return enc
 }
@@ -217,6 +249,7 @@ func ResponseDecoder(resp *http.Response) Decoder {
 // shape of the response can be overridden by providing a non-nil formatter.
 func ErrorEncoder(encoder func(context.Context, http.ResponseWriter) Encoder, formatter func(ctx context.Context, err error) Statuser) func(context.Context, http.ResponseWriter, error) error {
 	return func(ctx context.Context, w http.ResponseWriter, err error) error {
+		ctx = WithoutFieldSelection(ctx)
 		enc := encoder(ctx, w)
 		if formatter == nil {
 			formatter = NewErrorResponse
@@ -271,9 +304,9 @@ type textEncoder struct {
 func (e *textEncoder) Encode(v any) (err error) {
 	switch c := v.(type) {
 	case string:
-		_, err = e.w.Write([]byte(c))
+		_, err = io.WriteString(e.w, c)
 	case *string: // v may be a string pointer when the Response Body is set to the field of a custom response type.
-		_, err = e.w.Write([]byte(*c))
+		_, err = io.WriteString(e.w, *c)
 	case []byte:
 		_, err = e.w.Write(c)
 	default:
@@ -224,3 +224,15 @@ func makeTextDecoder() Decoder {
 	buffer.WriteString(testString)
 	return newTextDecoder(&buffer, "content/type")
 }
+
+func BenchmarkTextEncoder(b *testing.B) {
+	var buf bytes.Buffer
+	enc := newTextEncoder(&buf, "text/plain")
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		buf.Reset()
+		if err := enc.Encode("hello, world"); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
@@ -2,9 +2,11 @@ package http
 
 import (
 	"context"
+	"errors"
 	"net/http"
 
 	goa "goa.design/goa/v3/pkg"
+	"goa.design/goa/v3/security"
 )
 
 type (
@@ -26,6 +28,23 @@ type (
 		Fault bool `json:"fault" xml:"fault" form:"fault"`
 	}
 
+	// ForbiddenResponse is the data structure encoded in the HTTP responses
+	// of the requests denied by the endpoint authorization policies.
+	ForbiddenResponse struct {
+		// Name is the name of the error, always "forbidden".
+		Name string `json:"name" xml:"name" form:"name"`
+		// ID is the unique error instance identifier.
+		ID string `json:"id" xml:"id" form:"id"`
+		// Message describes why the request was denied.
+		Message string `json:"message" xml:"message" form:"message"`
+		// MissingRoles lists the roles authorized by the policy when the
+		// caller has none of them.
+		MissingRoles []string `json:"missing_roles,omitempty" xml:"missing_roles,omitempty" form:"missing_roles,omitempty"`
+		// MissingPermissions lists the permissions required by the policy
+		// that the caller does not have.
+		MissingPermissions []string `json:"missing_permissions,omitempty" xml:"missing_permissions,omitempty" form:"missing_permissions,omitempty"`
+	}
+
 	// Statuser is implemented by error response object to provide the response
 	// HTTP status code.
 	Statuser interface {
@@ -35,8 +54,19 @@ type (
 	}
 )
 
-// NewErrorResponse creates a HTTP response from the given error.
+// NewErrorResponse creates a HTTP response from the given error. Errors
+// returned by the endpoint authorizers are encoded with a ForbiddenResponse.
 func NewErrorResponse(ctx context.Context, err error) Statuser {
+	var aerr *security.AuthorizationError
+	if errors.As(err, &aerr) {
+		return &ForbiddenResponse{
+			Name:               "forbidden",
+			ID:                 goa.NewErrorID(),
+			Message:            aerr.Error(),
+			MissingRoles:       aerr.MissingRoles,
+			MissingPermissions: aerr.MissingPermissions,
+		}
+	}
 	if gerr, ok := err.(*goa.ServiceError); ok {
 		return &ErrorResponse{
 			Name:      gerr.Name,
@@ -50,6 +80,9 @@ func NewErrorResponse(ctx context.Context, err error) Statuser {
 	return NewErrorResponse(ctx, goa.Fault(err.Error()))
 }
 
+// StatusCode returns http.StatusForbidden.
+func (resp *ForbiddenResponse) StatusCode() int { return http.StatusForbidden }
+
 // StatusCode implements a heuristic that computes a HTTP response status code
 // appropriate for the timeout, temporary and fault characteristics of the
 // error. This method is used by the generated server code when the error is not
@@ -0,0 +1,35 @@
+package http
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+
+	"goa.design/goa/v3/security"
+)
+
+func TestErrorEncoderForbidden(t *testing.T) {
+	aerr := &security.AuthorizationError{Service: "svc", Method: "m", MissingRoles: []string{"admin"}, MissingPermissions: []string{"m:write"}}
+	w := httptest.NewRecorder()
+	encode := ErrorEncoder(ResponseEncoder, nil)
+	if err := encode(context.Background(), w, fmt.Errorf("wrapped: %w", aerr)); err != nil {
+		t.Fatal(err)
+	}
+	if w.Code != http.StatusForbidden {
+		t.Errorf("got status %d, expected %d", w.Code, http.StatusForbidden)
+	}
+	var resp ForbiddenResponse
+	if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+		t.Fatal(err)
+	}
+	if resp.Name != "forbidden" || resp.ID == "" || resp.Message != aerr.Error() {
+		t.Errorf("got %+v, expected forbidden error", resp)
+	}
+	if !reflect.DeepEqual(resp.MissingRoles, aerr.MissingRoles) || !reflect.DeepEqual(resp.MissingPermissions, aerr.MissingPermissions) {
+		t.Errorf("got missing roles %v and permissions %v, expected %v and %v", resp.MissingRoles, resp.MissingPermissions, aerr.MissingRoles, aerr.MissingPermissions)
+	}
+}
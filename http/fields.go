@@ -0,0 +1,141 @@
+package http
+
+import (
+	"context"
+	"net/http"
+	"sort"
+	"strings"
+
+	goa "goa.design/goa/v3/pkg"
+)
+
+type (
+	// fieldTree is the set of selected fields indexed by name. A nil
+	// sub-tree means that all the sub-fields are selected.
+	fieldTree map[string]fieldTree
+
+	// fieldsEncoder is an encoder that only renders the selected fields.
+	fieldsEncoder struct {
+		enc    Encoder
+		fields fieldTree
+	}
+)
+
+// WithFieldSelection returns a copy of ctx that causes ResponseEncoder to only
+// render the fields selected with the param query string parameter of r in
+// JSON responses. The parameter value is a comma separated list of dot
+// separated paths to the selected fields, e.g. "id,account.name". fields lists
+// the paths to the fields that may be selected and must be sorted.
+// WithFieldSelection returns an error if r selects a field that is not listed
+// in fields. ctx is returned unchanged if r does not define the parameter. The
+// generated HTTP handlers call WithFieldSelection for the endpoints that use
+// the FieldSelection DSL.
+func WithFieldSelection(ctx context.Context, r *http.Request, param string, fields []string) (context.Context, error) {
+	vals, ok := r.URL.Query()[param]
+	if !ok {
+		return ctx, nil
+	}
+	tree := make(fieldTree)
+	for _, v := range vals {
+		for _, f := range strings.Split(v, ",") {
+			f = strings.TrimSpace(f)
+			if f == "" {
+				continue
+			}
+			if i := sort.SearchStrings(fields, f); i == len(fields) || fields[i] != f {
+				allowed := make([]any, len(fields))
+				for i, f := range fields {
+					allowed[i] = f
+				}
+				return ctx, goa.InvalidEnumValueError(param, f, allowed)
+			}
+			tree.add(strings.Split(f, "."))
+		}
+	}
+	if len(tree) == 0 {
+		return ctx, nil
+	}
+	return context.WithValue(ctx, fieldsKey, tree), nil
+}
+
+// WithoutFieldSelection returns a copy of ctx that causes ResponseEncoder to
+// render all the fields regardless of the field selection set in ctx with
+// WithFieldSelection. The error encoders use it so that errors are always
+// rendered in full.
+func WithoutFieldSelection(ctx context.Context) context.Context {
+	if _, ok := ctx.Value(fieldsKey).(fieldTree); !ok {
+		return ctx
+	}
+	return context.WithValue(ctx, fieldsKey, nil)
+}
+
+// withFieldSelection returns an encoder that only renders the fields selected
+// in ctx if any and if the response content type is JSON, enc otherwise.
+func withFieldSelection(ctx context.Context, w http.ResponseWriter, enc Encoder) Encoder {
+	tree, ok := ctx.Value(fieldsKey).(fieldTree)
+	if !ok || len(tree) == 0 {
+		return enc
+	}
+	if ct := w.Header().Get("Content-Type"); !strings.Contains(ct, "json") {
+		return enc
+	}
+	return &fieldsEncoder{enc: enc, fields: tree}
+}
+
+// Encode renders the selected fields of v.
+func (e *fieldsEncoder) Encode(v any) error {
+	if _, ok := v.(Statuser); ok {
+		return e.enc.Encode(v)
+	}
+	raw, err := decodeRaw(v)
+	if err != nil {
+		return err
+	}
+	return e.enc.Encode(e.fields.prune(raw))
+}
+
+// add adds the field with the given path to t.
+func (t fieldTree) add(path []string) {
+	sub, ok := t[path[0]]
+	if ok && sub == nil {
+		// all sub-fields already selected
+		return
+	}
+	if len(path) == 1 {
+		t[path[0]] = nil
+		return
+	}
+	if sub == nil {
+		sub = make(fieldTree)
+		t[path[0]] = sub
+	}
+	sub.add(path[1:])
+}
+
+// prune returns the generic JSON value raw with only the fields in t.
+func (t fieldTree) prune(raw any) any {
+	switch v := raw.(type) {
+	case []any:
+		res := make([]any, len(v))
+		for i, e := range v {
+			res[i] = t.prune(e)
+		}
+		return res
+	case map[string]any:
+		res := make(map[string]any, len(t))
+		for name, sub := range t {
+			val, ok := v[name]
+			if !ok {
+				continue
+			}
+			if sub == nil {
+				res[name] = val
+			} else {
+				res[name] = sub.prune(val)
+			}
+		}
+		return res
+	default:
+		return raw
+	}
+}
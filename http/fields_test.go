@@ -0,0 +1,93 @@
+package http
+
+import (
+	"context"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestFieldSelection(t *testing.T) {
+	fields := []string{"account", "account.id", "account.name", "id", "name", "vintage"}
+	bottle := map[string]any{
+		"id":      1,
+		"name":    "merlot",
+		"vintage": 2019,
+		"account": map[string]any{"id": "a1", "name": "joe"},
+	}
+	cases := []struct {
+		name  string
+		url   string
+		value any
+		want  string
+		err   bool
+	}{
+		{"none", "/", bottle, `{"account":{"id":"a1","name":"joe"},"id":1,"name":"merlot","vintage":2019}`, false},
+		{"empty", "/?fields=", bottle, `{"account":{"id":"a1","name":"joe"},"id":1,"name":"merlot","vintage":2019}`, false},
+		{"top-level", "/?fields=id,name", bottle, `{"id":1,"name":"merlot"}`, false},
+		{"nested", "/?fields=id,account.name", bottle, `{"account":{"name":"joe"},"id":1}`, false},
+		{"parent-and-nested", "/?fields=account.name,account", bottle, `{"account":{"id":"a1","name":"joe"}}`, false},
+		{"multiple-params", "/?fields=id&fields=vintage", bottle, `{"id":1,"vintage":2019}`, false},
+		{"collection", "/?fields=id", []any{bottle, bottle}, `[{"id":1},{"id":1}]`, false},
+		{"error-response", "/?fields=id", &ErrorResponse{Name: "n"}, `{"name":"n","id":"","message":"","temporary":false,"timeout":false,"fault":false}`, false},
+		{"invalid", "/?fields=id,foo", bottle, "", true},
+		{"invalid-nested", "/?fields=account.foo", bottle, "", true},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			r := httptest.NewRequest("GET", c.url, nil)
+			ctx, err := WithFieldSelection(context.Background(), r, "fields", fields)
+			if c.err {
+				if err == nil {
+					t.Fatal("expected an error")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+			w := httptest.NewRecorder()
+			if err := ResponseEncoder(ctx, w).Encode(c.value); err != nil {
+				t.Fatalf("unexpected encoding error: %s", err)
+			}
+			if got := strings.TrimSpace(w.Body.String()); got != c.want {
+				t.Errorf("got\n%s\nexpected\n%s", got, c.want)
+			}
+		})
+	}
+}
+
+func TestWithoutFieldSelection(t *testing.T) {
+	r := httptest.NewRequest("GET", "/?fields=id", nil)
+	ctx, err := WithFieldSelection(context.Background(), r, "fields", []string{"id", "name"})
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	w := httptest.NewRecorder()
+	ResponseEncoder(WithoutFieldSelection(ctx), w).Encode(map[string]any{"id": 1, "name": "n"}) // nolint: errcheck
+	if got, want := strings.TrimSpace(w.Body.String()), `{"id":1,"name":"n"}`; got != want {
+		t.Errorf("got %s, expected %s", got, want)
+	}
+}
+
+func BenchmarkFieldSelection(b *testing.B) {
+	fields := []string{"account", "account.id", "account.name", "id", "name", "vintage"}
+	bottle := map[string]any{
+		"id":      1,
+		"name":    "merlot",
+		"vintage": 2019,
+		"account": map[string]any{"id": "a1", "name": "joe"},
+	}
+	r := httptest.NewRequest("GET", "/?fields=id,account.name", nil)
+	ctx, err := WithFieldSelection(context.Background(), r, "fields", fields)
+	if err != nil {
+		b.Fatal(err)
+	}
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		w := httptest.NewRecorder()
+		if err := ResponseEncoder(ctx, w).Encode(bottle); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
@@ -0,0 +1,31 @@
+package http
+
+import (
+	"net/http"
+	"sort"
+	"strings"
+
+	goa "goa.design/goa/v3/pkg"
+)
+
+// ValidateFilters returns an error if the query string of r uses a
+// "filter[NAME]" parameter whose name is not listed in filters. filters must
+// be sorted. The generated HTTP handlers call ValidateFilters for the
+// endpoints whose payload defines filters with the Filter DSL.
+func ValidateFilters(r *http.Request, filters []string) error {
+	for k := range r.URL.Query() {
+		if !strings.HasPrefix(k, "filter[") || !strings.HasSuffix(k, "]") {
+			continue
+		}
+		name := k[len("filter[") : len(k)-1]
+		if i := sort.SearchStrings(filters, name); i < len(filters) && filters[i] == name {
+			continue
+		}
+		allowed := make([]any, len(filters))
+		for i, f := range filters {
+			allowed[i] = f
+		}
+		return goa.InvalidEnumValueError("filter", name, allowed)
+	}
+	return nil
+}
@@ -0,0 +1,32 @@
+package http
+
+import (
+	"net/http/httptest"
+	"testing"
+)
+
+func TestValidateFilters(t *testing.T) {
+	filters := []string{"name", "vintage"}
+	cases := []struct {
+		name string
+		url  string
+		err  bool
+	}{
+		{"none", "/", false},
+		{"declared", "/?filter[name]=a&filter[vintage]=2019&sort=-name", false},
+		{"other-params", "/?filters=a&filter=b&filter[=c", false},
+		{"undeclared", "/?filter[name]=a&filter[color]=red", true},
+		{"empty-name", "/?filter[]=a", true},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			err := ValidateFilters(httptest.NewRequest("GET", c.url, nil), filters)
+			if c.err && err == nil {
+				t.Error("expected an error")
+			}
+			if !c.err && err != nil {
+				t.Errorf("unexpected error: %s", err)
+			}
+		})
+	}
+}
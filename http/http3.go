@@ -0,0 +1,65 @@
+package http
+
+import (
+	"context"
+	"crypto/tls"
+	"errors"
+	"net/http"
+)
+
+// HTTP3Server is the interface implemented by the HTTP/3 servers served by
+// Run alongside the TCP server, e.g. *http3.Server of the
+// github.com/quic-go/quic-go/http3 package. HTTP/3 support is experimental.
+type HTTP3Server interface {
+	// ListenAndServe listens on the UDP address of the server and serves
+	// the QUIC connections.
+	ListenAndServe() error
+	// SetQUICHeaders sets the Alt-Svc header advertising the HTTP/3
+	// endpoint of the server.
+	SetQUICHeaders(http.Header) error
+	// Close closes the server immediately.
+	Close() error
+}
+
+// errHTTP3NoTLS is the error returned by Run when configured with WithHTTP3
+// without TLS certificates.
+var errHTTP3NoTLS = errors.New("HTTP/3 requires the server to be served with TLS")
+
+// WithHTTP3 makes Run serve the HTTP/3 server returned by newServer alongside
+// the TCP server. newServer is given the handler and the TLS configuration of
+// the TCP server so that both servers share the same router and middleware
+// chain. The responses served over TCP advertise the HTTP/3 endpoint with the
+// Alt-Svc header so that the clients that support HTTP/3, e.g. mobile clients,
+// switch to it. Run returns an error if the TCP server is not served with TLS
+// as QUIC requires TLS 1.3. Run shuts the HTTP/3 server down gracefully if it
+// implements the Shutdown(context.Context) error method, otherwise Run closes
+// it. HTTP/3 support is experimental.
+//
+// Example:
+//
+//	err := goahttp.Run(ctx, srv, goahttp.WithHTTP3(func(h http.Handler, cfg *tls.Config) goahttp.HTTP3Server {
+//		return &http3.Server{Addr: srv.Addr, Handler: h, TLSConfig: http3.ConfigureTLSConfig(cfg)}
+//	}))
+func WithHTTP3(newServer func(http.Handler, *tls.Config) HTTP3Server) RunOption {
+	return func(o *runOptions) { o.http3 = newServer }
+}
+
+// altSvcHandler returns a handler that sets the Alt-Svc header advertising the
+// HTTP/3 endpoint of h3 before calling h. The header is set on a best effort
+// basis as it can only be computed once h3 listens.
+func altSvcHandler(h http.Handler, h3 HTTP3Server) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.ProtoMajor < 3 {
+			h3.SetQUICHeaders(w.Header()) // nolint: errcheck
+		}
+		h.ServeHTTP(w, r)
+	})
+}
+
+// shutdownHTTP3 shuts h3 down gracefully if supported and closes it otherwise.
+func shutdownHTTP3(ctx context.Context, h3 HTTP3Server) error {
+	if s, ok := h3.(interface{ Shutdown(context.Context) error }); ok {
+		return s.Shutdown(ctx)
+	}
+	return h3.Close()
+}
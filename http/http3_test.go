@@ -0,0 +1,97 @@
+package http
+
+import (
+	"context"
+	"crypto/tls"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+type fakeHTTP3Server struct {
+	handler  http.Handler
+	tls      *tls.Config
+	closed   chan struct{}
+	shutdown bool
+}
+
+func (s *fakeHTTP3Server) ListenAndServe() error {
+	<-s.closed
+	return http.ErrServerClosed
+}
+
+func (s *fakeHTTP3Server) SetQUICHeaders(h http.Header) error {
+	h.Set("Alt-Svc", `h3=":443"; ma=2592000`)
+	return nil
+}
+
+func (s *fakeHTTP3Server) Close() error {
+	close(s.closed)
+	return nil
+}
+
+func (s *fakeHTTP3Server) Shutdown(context.Context) error {
+	s.shutdown = true
+	return s.Close()
+}
+
+func TestRunHTTP3(t *testing.T) {
+	ts := httptest.NewUnstartedServer(nil)
+	ts.StartTLS()
+	cfg := ts.TLS.Clone()
+	ts.Close()
+
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	handler := http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.Write([]byte("ok")) // nolint: errcheck
+	})
+	srv := &http.Server{Handler: handler, TLSConfig: cfg, ReadHeaderTimeout: time.Second}
+	h3 := &fakeHTTP3Server{closed: make(chan struct{})}
+	newServer := func(h http.Handler, cfg *tls.Config) HTTP3Server {
+		h3.handler, h3.tls = h, cfg
+		return h3
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error)
+	go func() { done <- Run(ctx, srv, WithListener(l), WithHTTP3(newServer)) }()
+
+	client := &http.Client{Transport: &http.Transport{TLSClientConfig: &tls.Config{InsecureSkipVerify: true}}} // nolint: gosec
+	resp, err := client.Get("https://" + l.Addr().String())
+	if err != nil {
+		t.Fatal(err)
+	}
+	body, _ := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	if string(body) != "ok" {
+		t.Errorf("got body %q, expected \"ok\"", body)
+	}
+	if alt := resp.Header.Get("Alt-Svc"); alt != `h3=":443"; ma=2592000` {
+		t.Errorf("got Alt-Svc %q, expected the HTTP/3 endpoint", alt)
+	}
+	cancel()
+	if err := <-done; err != nil {
+		t.Errorf("got error %v, expected the servers to shut down gracefully", err)
+	}
+	if !h3.shutdown {
+		t.Error("expected the HTTP/3 server to be shut down")
+	}
+	if h3.tls != cfg {
+		t.Error("expected the HTTP/3 server to share the TLS configuration")
+	}
+	w := httptest.NewRecorder()
+	h3.handler.ServeHTTP(w, httptest.NewRequest("GET", "/", nil))
+	if w.Body.String() != "ok" {
+		t.Errorf("got HTTP/3 body %q, expected \"ok\"", w.Body.String())
+	}
+
+	plain := &http.Server{Handler: handler, ReadHeaderTimeout: time.Second}
+	if err := Run(context.Background(), plain, WithHTTP3(newServer)); err != errHTTP3NoTLS {
+		t.Errorf("got error %v, expected %v", err, errHTTP3NoTLS)
+	}
+}
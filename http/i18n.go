@@ -0,0 +1,83 @@
+package http
+
+import (
+	"context"
+	"sort"
+	"strconv"
+	"strings"
+
+	goa "goa.design/goa/v3/pkg"
+)
+
+// NewLocalizedErrorResponse returns an error formatter that creates the same
+// responses as NewErrorResponse but with messages translated by t in the
+// languages stored in the request context by the AcceptLanguage middleware,
+// see goa.Translate. The formatter is meant to be given to the generated
+// server constructors.
+//
+// Example:
+//
+//	handler = httpmdlwr.AcceptLanguage()(handler)
+//	server := calcsvr.New(endpoints, mux, dec, enc, eh, goahttp.NewLocalizedErrorResponse(catalog))
+func NewLocalizedErrorResponse(t goa.Translator) func(context.Context, error) Statuser {
+	return func(ctx context.Context, err error) Statuser {
+		resp := NewErrorResponse(ctx, err)
+		if er, ok := resp.(*ErrorResponse); ok {
+			if langs := Languages(ctx); len(langs) > 0 {
+				er.Message = goa.Translate(err, t, langs)
+			}
+		}
+		return resp
+	}
+}
+
+// WithLanguages returns a copy of ctx that holds the given language tags
+// listed by order of preference.
+func WithLanguages(ctx context.Context, langs []string) context.Context {
+	return context.WithValue(ctx, languagesKey, langs)
+}
+
+// Languages returns the language tags stored in ctx by WithLanguages by order
+// of preference, nil if there are none.
+func Languages(ctx context.Context) []string {
+	langs, _ := ctx.Value(languagesKey).([]string)
+	return langs
+}
+
+// ParseAcceptLanguage returns the language tags listed in the given
+// Accept-Language header value sorted by decreasing quality. Tags with the
+// same quality keep their order, the tags with a zero quality and the wildcard
+// are omitted. The tags are lower cased.
+func ParseAcceptLanguage(v string) []string {
+	type tag struct {
+		name string
+		q    float64
+	}
+	var tags []tag
+	for _, part := range strings.Split(v, ",") {
+		name, params, _ := strings.Cut(part, ";")
+		name = strings.ToLower(strings.TrimSpace(name))
+		if name == "" || name == "*" {
+			continue
+		}
+		q := 1.0
+		for _, p := range strings.Split(params, ";") {
+			k, val, ok := strings.Cut(strings.TrimSpace(p), "=")
+			if ok && strings.TrimSpace(k) == "q" {
+				if f, err := strconv.ParseFloat(strings.TrimSpace(val), 64); err == nil {
+					q = f
+				}
+			}
+		}
+		if q <= 0 {
+			continue
+		}
+		tags = append(tags, tag{name, q})
+	}
+	sort.SliceStable(tags, func(i, j int) bool { return tags[i].q > tags[j].q })
+	langs := make([]string, len(tags))
+	for i, t := range tags {
+		langs[i] = t.name
+	}
+	return langs
+}
@@ -0,0 +1,51 @@
+package http
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	goa "goa.design/goa/v3/pkg"
+)
+
+func TestParseAcceptLanguage(t *testing.T) {
+	cases := map[string]struct {
+		Value    string
+		Expected []string
+	}{
+		"empty":    {"", []string{}},
+		"single":   {"fr-CA", []string{"fr-ca"}},
+		"quality":  {"en;q=0.5, fr-CA, fr;q=0.8, *;q=0.1", []string{"fr-ca", "fr", "en"}},
+		"stable":   {"de;q=0.7, es;q=0.7", []string{"de", "es"}},
+		"zero":     {"fr, en;q=0", []string{"fr"}},
+		"spaces":   {" fr ; q = 0.2 , en ", []string{"en", "fr"}},
+		"invalid":  {"fr;q=high", []string{"fr"}},
+		"wildcard": {"*", []string{}},
+	}
+	for k, c := range cases {
+		t.Run(k, func(t *testing.T) {
+			if got := ParseAcceptLanguage(c.Value); !reflect.DeepEqual(got, c.Expected) {
+				t.Errorf("got %v, expected %v", got, c.Expected)
+			}
+		})
+	}
+}
+
+func TestNewLocalizedErrorResponse(t *testing.T) {
+	var (
+		catalog = goa.Catalog{"fr": {goa.MsgMissingField: "{field} est manquant"}}
+		format  = NewLocalizedErrorResponse(catalog)
+		err     = goa.MissingFieldError("name", "body")
+	)
+	resp := format(WithLanguages(context.Background(), []string{"fr"}), err).(*ErrorResponse)
+	if resp.Message != "name est manquant" {
+		t.Errorf("got message %q, expected translation", resp.Message)
+	}
+	if resp.Name != goa.MissingField || resp.StatusCode() != 400 {
+		t.Errorf("got name %q and status %d, expected missing_field and 400", resp.Name, resp.StatusCode())
+	}
+	resp = format(context.Background(), err).(*ErrorResponse)
+	if resp.Message != err.Error() {
+		t.Errorf("got message %q, expected %q", resp.Message, err.Error())
+	}
+}
@@ -0,0 +1,142 @@
+package http
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"math"
+	"reflect"
+	"strconv"
+	"unicode/utf8"
+)
+
+// JSONAppender is implemented by the response body types generated for
+// services that enable the "http:json:fast" meta. AppendJSON appends the JSON
+// encoding of the value to b without relying on reflection. The encoding is
+// identical to the one produced by the encoding/json package.
+type JSONAppender interface {
+	AppendJSON(b []byte) ([]byte, error)
+}
+
+// hexDigits contains the hexadecimal digits used to escape control characters.
+const hexDigits = "0123456789abcdef"
+
+// AppendJSON appends the JSON encoding of v to b. It uses the AppendJSON
+// method of v if v implements JSONAppender and falls back to json.Marshal
+// otherwise.
+func AppendJSON(b []byte, v any) ([]byte, error) {
+	if a, ok := v.(JSONAppender); ok {
+		return a.AppendJSON(b)
+	}
+	js, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+	return append(b, js...), nil
+}
+
+// AppendJSONField appends the given JSON object key to b preceded with a comma
+// unless the key is the first of the object. key must be the JSON encoded
+// field name followed with a colon.
+func AppendJSONField(b []byte, key string) []byte {
+	if len(b) > 0 && b[len(b)-1] != '{' {
+		b = append(b, ',')
+	}
+	return append(b, key...)
+}
+
+// AppendJSONString appends the JSON encoding of s to b. Like encoding/json it
+// escapes the HTML characters <, > and &, the U+2028 and U+2029 separators and
+// replaces invalid UTF-8 sequences with the replacement character.
+func AppendJSONString(b []byte, s string) []byte {
+	b = append(b, '"')
+	start := 0
+	for i := 0; i < len(s); {
+		if c := s[i]; c < utf8.RuneSelf {
+			if c >= 0x20 && c != '"' && c != '\\' && c != '<' && c != '>' && c != '&' {
+				i++
+				continue
+			}
+			b = append(b, s[start:i]...)
+			switch c {
+			case '"', '\\':
+				b = append(b, '\\', c)
+			case '\b':
+				b = append(b, '\\', 'b')
+			case '\f':
+				b = append(b, '\\', 'f')
+			case '\n':
+				b = append(b, '\\', 'n')
+			case '\r':
+				b = append(b, '\\', 'r')
+			case '\t':
+				b = append(b, '\\', 't')
+			default:
+				b = append(b, '\\', 'u', '0', '0', hexDigits[c>>4], hexDigits[c&0xF])
+			}
+			i++
+			start = i
+			continue
+		}
+		r, size := utf8.DecodeRuneInString(s[i:])
+		if r == utf8.RuneError && size == 1 {
+			b = append(b, s[start:i]...)
+			b = append(b, `\ufffd`...)
+			i += size
+			start = i
+			continue
+		}
+		if r == '\u2028' || r == '\u2029' {
+			b = append(b, s[start:i]...)
+			b = append(b, '\\', 'u', '2', '0', '2', hexDigits[r&0xF])
+			i += size
+			start = i
+			continue
+		}
+		i += size
+	}
+	b = append(b, s[start:]...)
+	return append(b, '"')
+}
+
+// AppendJSONFloat appends the JSON encoding of the floating point number f of
+// the given bit size (32 or 64) to b using the same format as encoding/json. It
+// returns an error if f is NaN or infinite.
+func AppendJSONFloat(b []byte, f float64, bits int) ([]byte, error) {
+	if math.IsInf(f, 0) || math.IsNaN(f) {
+		return nil, &json.UnsupportedValueError{
+			Value: reflect.ValueOf(f),
+			Str:   strconv.FormatFloat(f, 'g', -1, bits),
+		}
+	}
+	abs := math.Abs(f)
+	fmt := byte('f')
+	if abs != 0 {
+		if bits == 64 && (abs < 1e-6 || abs >= 1e21) || bits == 32 && (float32(abs) < 1e-6 || float32(abs) >= 1e21) {
+			fmt = 'e'
+		}
+	}
+	b = strconv.AppendFloat(b, f, fmt, -1, bits)
+	if fmt == 'e' {
+		// clean up e-09 to e-9
+		n := len(b)
+		if n >= 4 && b[n-4] == 'e' && b[n-3] == '-' && b[n-2] == '0' {
+			b[n-2] = b[n-1]
+			b = b[:n-1]
+		}
+	}
+	return b, nil
+}
+
+// AppendJSONBytes appends the JSON encoding of v to b, that is the base64
+// encoding of v or null if v is nil.
+func AppendJSONBytes(b []byte, v []byte) []byte {
+	if v == nil {
+		return append(b, "null"...)
+	}
+	n := base64.StdEncoding.EncodedLen(len(v))
+	b = append(b, '"')
+	start := len(b)
+	b = append(b, make([]byte, n)...)
+	base64.StdEncoding.Encode(b[start:], v)
+	return append(b, '"')
+}
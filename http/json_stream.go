@@ -0,0 +1,445 @@
+package http
+
+import (
+	"encoding"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"reflect"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+type (
+	// StreamingDecoderOption configures the decoders returned by
+	// NewStreamingJSONDecoder.
+	StreamingDecoderOption func(*streamingDecoder)
+
+	// streamingDecoder is a JSON decoder that decodes the tokens read from
+	// a stream directly into the target value.
+	streamingDecoder struct {
+		dec      *json.Decoder
+		maxDepth int
+		maxSize  int64
+		r        *limitedReader
+	}
+
+	// limitedReader is a reader that fails once more than max bytes are
+	// read.
+	limitedReader struct {
+		r   io.Reader
+		n   int64
+		max int64
+	}
+
+	// jsonField is an encoded struct field.
+	jsonField struct {
+		name  string
+		index []int
+	}
+
+	// jsonFields contains the encoded fields of a struct type.
+	jsonFields struct {
+		byName map[string]*jsonField
+		byFold map[string]*jsonField
+	}
+)
+
+var (
+	// ErrJSONTooDeep is the error returned by the streaming JSON decoders
+	// when the document nests more objects and arrays than allowed.
+	ErrJSONTooDeep = errors.New("JSON document exceeds the maximum nesting depth")
+
+	// ErrJSONTooLarge is the error returned by the streaming JSON decoders
+	// when the document is larger than allowed.
+	ErrJSONTooLarge = errors.New("JSON document exceeds the maximum size")
+
+	// errJSONTruncated is the error returned when the input ends in the
+	// middle of a document.
+	errJSONTruncated = errors.New("unexpected end of JSON input")
+
+	// jsonFieldsCache caches the encoded fields of the struct types.
+	jsonFieldsCache sync.Map
+
+	// jsonUnmarshalerType is the reflection type of json.Unmarshaler.
+	jsonUnmarshalerType = reflect.TypeOf((*json.Unmarshaler)(nil)).Elem()
+
+	// textUnmarshalerType is the reflection type of
+	// encoding.TextUnmarshaler.
+	textUnmarshalerType = reflect.TypeOf((*encoding.TextUnmarshaler)(nil)).Elem()
+)
+
+// NewStreamingJSONDecoder returns a JSON decoder that reads the document from
+// r token by token and decodes the values directly into the target, so that
+// the memory used to decode large documents is not proportional to their
+// size. The decoder enforces a maximum nesting depth of 64 and no size limit
+// unless configured otherwise with WithMaxJSONDepth and WithMaxJSONSize.
+// Values of types that implement json.Unmarshaler and values decoded into
+// interfaces are decoded with encoding/json. The decoding rules are otherwise
+// the same as encoding/json: field names are matched case-insensitively and
+// unknown fields are ignored.
+func NewStreamingJSONDecoder(r io.Reader, opts ...StreamingDecoderOption) Decoder {
+	d := &streamingDecoder{maxDepth: 64}
+	for _, opt := range opts {
+		opt(d)
+	}
+	if d.maxSize > 0 {
+		d.r = &limitedReader{r: r, max: d.maxSize}
+		r = d.r
+	}
+	d.dec = json.NewDecoder(r)
+	d.dec.UseNumber()
+	return d
+}
+
+// StreamingRequestDecoder returns a request decoder that decodes the JSON
+// request bodies with a streaming JSON decoder configured with the given
+// options and the other bodies like RequestDecoder. It is meant to be given
+// to the generated server constructors of services that receive multi-MB
+// payloads.
+//
+// Example:
+//
+//	dec := goahttp.StreamingRequestDecoder(goahttp.WithMaxJSONSize(64 << 20))
+//	server := uploadsvr.New(endpoints, mux, dec, goahttp.ResponseEncoder, nil, nil)
+func StreamingRequestDecoder(opts ...StreamingDecoderOption) func(*http.Request) Decoder {
+	return func(r *http.Request) Decoder {
+		ct := r.Header.Get("Content-Type")
+		if mt, _, err := mime.ParseMediaType(ct); err == nil {
+			ct = mt
+		}
+		switch ct {
+		case "", "application/json":
+			return NewStreamingJSONDecoder(r.Body, opts...)
+		}
+		return RequestDecoder(r)
+	}
+}
+
+// WithMaxJSONDepth sets the maximum nesting depth of the objects and arrays
+// of the decoded documents.
+func WithMaxJSONDepth(n int) StreamingDecoderOption {
+	return func(d *streamingDecoder) { d.maxDepth = n }
+}
+
+// WithMaxJSONSize sets the maximum size in bytes of the decoded documents.
+func WithMaxJSONSize(n int64) StreamingDecoderOption {
+	return func(d *streamingDecoder) { d.maxSize = n }
+}
+
+// Decode decodes the next JSON document into v which must be a non-nil
+// pointer.
+func (d *streamingDecoder) Decode(v any) error {
+	rv := reflect.ValueOf(v)
+	if rv.Kind() != reflect.Ptr || rv.IsNil() {
+		return fmt.Errorf("cannot decode JSON into %T", v)
+	}
+	if err := d.value(rv.Elem(), 0); err != nil {
+		if d.r != nil && d.r.n > d.r.max {
+			return ErrJSONTooLarge
+		}
+		return err
+	}
+	return nil
+}
+
+// value decodes the next value into v.
+func (d *streamingDecoder) value(v reflect.Value, depth int) error {
+	if v.Kind() == reflect.Interface || isJSONUnmarshaler(v.Type()) {
+		// Decode the raw value with encoding/json so that the numbers
+		// decoded into interfaces are float64 values as with
+		// json.Unmarshal and not json.Number values.
+		var raw json.RawMessage
+		if err := d.dec.Decode(&raw); err != nil {
+			return truncated(err, depth)
+		}
+		return json.Unmarshal(raw, v.Addr().Interface())
+	}
+	tok, err := d.next(depth)
+	if err != nil {
+		return err
+	}
+	if tok == nil {
+		switch v.Kind() {
+		case reflect.Ptr, reflect.Map, reflect.Slice:
+			v.Set(reflect.Zero(v.Type()))
+		}
+		return nil
+	}
+	if v.Kind() == reflect.Ptr {
+		if v.IsNil() {
+			v.Set(reflect.New(v.Type().Elem()))
+		}
+		return d.token(tok, v.Elem(), depth)
+	}
+	return d.token(tok, v, depth)
+}
+
+// token decodes the value starting with tok into v.
+func (d *streamingDecoder) token(tok json.Token, v reflect.Value, depth int) error {
+	if v.Kind() == reflect.Ptr {
+		if v.IsNil() {
+			v.Set(reflect.New(v.Type().Elem()))
+		}
+		return d.token(tok, v.Elem(), depth)
+	}
+	switch t := tok.(type) {
+	case json.Delim:
+		if depth >= d.maxDepth {
+			return ErrJSONTooDeep
+		}
+		if t == '{' {
+			return d.object(v, depth+1)
+		}
+		return d.array(v, depth+1)
+	case string:
+		return setString(v, t)
+	case json.Number:
+		return setNumber(v, t)
+	case bool:
+		if v.Kind() != reflect.Bool {
+			return typeError("boolean", v)
+		}
+		v.SetBool(t)
+		return nil
+	}
+	return fmt.Errorf("unexpected JSON token %v", tok)
+}
+
+// object decodes the members of an object into the struct or map v.
+func (d *streamingDecoder) object(v reflect.Value, depth int) error {
+	var fields *jsonFields
+	switch v.Kind() {
+	case reflect.Struct:
+		fields = cachedJSONFields(v.Type())
+	case reflect.Map:
+		if v.Type().Key().Kind() != reflect.String {
+			return typeError("object", v)
+		}
+		if v.IsNil() {
+			v.Set(reflect.MakeMap(v.Type()))
+		}
+	default:
+		return typeError("object", v)
+	}
+	for d.dec.More() {
+		tok, err := d.next(depth)
+		if err != nil {
+			return err
+		}
+		key, _ := tok.(string)
+		if fields == nil {
+			ev := reflect.New(v.Type().Elem()).Elem()
+			if err := d.value(ev, depth); err != nil {
+				return err
+			}
+			v.SetMapIndex(reflect.ValueOf(key).Convert(v.Type().Key()), ev)
+			continue
+		}
+		f, ok := fields.byName[key]
+		if !ok {
+			f, ok = fields.byFold[strings.ToLower(key)]
+		}
+		if !ok {
+			if err := d.skip(depth); err != nil {
+				return err
+			}
+			continue
+		}
+		if err := d.value(v.FieldByIndex(f.index), depth); err != nil {
+			return fmt.Errorf("%s: %w", key, err)
+		}
+	}
+	_, err := d.next(depth) // closing brace
+	return err
+}
+
+// array decodes the elements of an array into the slice or array v.
+func (d *streamingDecoder) array(v reflect.Value, depth int) error {
+	switch v.Kind() {
+	case reflect.Slice:
+		s := reflect.MakeSlice(v.Type(), 0, 0)
+		for d.dec.More() {
+			ev := reflect.New(v.Type().Elem()).Elem()
+			if err := d.value(ev, depth); err != nil {
+				return fmt.Errorf("[%d]: %w", s.Len(), err)
+			}
+			s = reflect.Append(s, ev)
+		}
+		v.Set(s)
+	case reflect.Array:
+		i := 0
+		for ; d.dec.More(); i++ {
+			if i >= v.Len() {
+				if err := d.skip(depth); err != nil {
+					return err
+				}
+				continue
+			}
+			if err := d.value(v.Index(i), depth); err != nil {
+				return fmt.Errorf("[%d]: %w", i, err)
+			}
+		}
+		for ; i < v.Len(); i++ {
+			v.Index(i).Set(reflect.Zero(v.Type().Elem()))
+		}
+	default:
+		return typeError("array", v)
+	}
+	_, err := d.next(depth) // closing bracket
+	return err
+}
+
+// skip consumes the next value.
+func (d *streamingDecoder) skip(depth int) error {
+	nested := 0
+	for {
+		tok, err := d.next(depth + nested)
+		if err != nil {
+			return err
+		}
+		if delim, ok := tok.(json.Delim); ok {
+			switch delim {
+			case '{', '[':
+				if depth+nested >= d.maxDepth {
+					return ErrJSONTooDeep
+				}
+				nested++
+			default:
+				nested--
+			}
+		}
+		if nested == 0 {
+			return nil
+		}
+	}
+}
+
+// next returns the next token of the document at the given nesting depth.
+func (d *streamingDecoder) next(depth int) (json.Token, error) {
+	tok, err := d.dec.Token()
+	return tok, truncated(err, depth)
+}
+
+// truncated returns the error reported when the input ends at the given
+// nesting depth. io.EOF is only returned if the input ends before the document
+// starts so that truncated documents are not mistaken for empty bodies.
+func truncated(err error, depth int) error {
+	if err == io.EOF && depth > 0 {
+		return errJSONTruncated
+	}
+	return err
+}
+
+// isJSONUnmarshaler returns true if t, or the type t points to, implements
+// json.Unmarshaler.
+func isJSONUnmarshaler(t reflect.Type) bool {
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	return reflect.PtrTo(t).Implements(jsonUnmarshalerType)
+}
+
+// setString sets v to the JSON string s.
+func setString(v reflect.Value, s string) error {
+	if v.CanAddr() && v.Addr().Type().Implements(textUnmarshalerType) {
+		return v.Addr().Interface().(encoding.TextUnmarshaler).UnmarshalText([]byte(s))
+	}
+	switch {
+	case v.Kind() == reflect.String:
+		v.SetString(s)
+	case v.Kind() == reflect.Slice && v.Type().Elem().Kind() == reflect.Uint8:
+		b, err := base64.StdEncoding.DecodeString(s)
+		if err != nil {
+			return err
+		}
+		v.SetBytes(b)
+	default:
+		return typeError("string", v)
+	}
+	return nil
+}
+
+// setNumber sets v to the JSON number n.
+func setNumber(v reflect.Value, n json.Number) error {
+	switch v.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		i, err := strconv.ParseInt(string(n), 10, v.Type().Bits())
+		if err != nil {
+			return typeError("number "+string(n), v)
+		}
+		v.SetInt(i)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		u, err := strconv.ParseUint(string(n), 10, v.Type().Bits())
+		if err != nil {
+			return typeError("number "+string(n), v)
+		}
+		v.SetUint(u)
+	case reflect.Float32, reflect.Float64:
+		f, err := strconv.ParseFloat(string(n), v.Type().Bits())
+		if err != nil {
+			return typeError("number "+string(n), v)
+		}
+		v.SetFloat(f)
+	default:
+		return typeError("number", v)
+	}
+	return nil
+}
+
+// typeError returns the error reported when a JSON value of the given kind
+// cannot be decoded into v.
+func typeError(kind string, v reflect.Value) error {
+	return fmt.Errorf("cannot decode JSON %s into Go value of type %s", kind, v.Type())
+}
+
+// cachedJSONFields returns the encoded fields of the struct type t.
+func cachedJSONFields(t reflect.Type) *jsonFields {
+	if f, ok := jsonFieldsCache.Load(t); ok {
+		return f.(*jsonFields)
+	}
+	fields := &jsonFields{byName: make(map[string]*jsonField), byFold: make(map[string]*jsonField)}
+	for i := 0; i < t.NumField(); i++ {
+		sf := t.Field(i)
+		if !sf.IsExported() {
+			continue
+		}
+		tag := sf.Tag.Get("json")
+		if tag == "-" {
+			continue
+		}
+		name, _, _ := strings.Cut(tag, ",")
+		if name == "" {
+			name = sf.Name
+		}
+		f := &jsonField{name: name, index: sf.Index}
+		fields.byName[name] = f
+		if _, ok := fields.byFold[strings.ToLower(name)]; !ok {
+			fields.byFold[strings.ToLower(name)] = f
+		}
+	}
+	f, _ := jsonFieldsCache.LoadOrStore(t, fields)
+	return f.(*jsonFields)
+}
+
+// Read reads from the underlying reader and fails once more than max bytes
+// are read.
+func (r *limitedReader) Read(p []byte) (int, error) {
+	if r.n > r.max {
+		return 0, ErrJSONTooLarge
+	}
+	if int64(len(p)) > r.max-r.n+1 {
+		p = p[:r.max-r.n+1]
+	}
+	n, err := r.r.Read(p)
+	r.n += int64(n)
+	if r.n > r.max {
+		return n, ErrJSONTooLarge
+	}
+	return n, err
+}
@@ -0,0 +1,143 @@
+package http
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+type streamPayload struct {
+	Name     string            `json:"name"`
+	Count    *int              `json:"count,omitempty"`
+	Ratio    float32           `json:"ratio"`
+	Enabled  bool              `json:"enabled"`
+	Data     []byte            `json:"data"`
+	Tags     []string          `json:"tags"`
+	Labels   map[string]string `json:"labels"`
+	Child    *streamPayload    `json:"child,omitempty"`
+	Created  time.Time         `json:"created"`
+	Extra    any               `json:"extra"`
+	Raw      json.RawMessage   `json:"raw"`
+	Fixed    [2]int            `json:"fixed"`
+	Internal string            `json:"-"`
+}
+
+func TestStreamingJSONDecoder(t *testing.T) {
+	count := 3
+	expected := &streamPayload{
+		Name:    "bottle",
+		Count:   &count,
+		Ratio:   0.5,
+		Enabled: true,
+		Data:    []byte("hello"),
+		Tags:    []string{"a", "b"},
+		Labels:  map[string]string{"env": "prod"},
+		Child:   &streamPayload{Name: "child", Tags: []string{}},
+		Created: time.Date(2020, 1, 2, 3, 4, 5, 0, time.UTC),
+		Extra:   map[string]any{"k": []any{1.0, "v"}},
+		Raw:     json.RawMessage(`{"x":1}`),
+		Fixed:   [2]int{1, 2},
+	}
+	body, err := json.Marshal(expected)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// unknown fields are ignored and names are matched case-insensitively
+	body = bytes.Replace(body, []byte(`"name":"bottle"`), []byte(`"unknown":{"a":[1,{"b":2}]},"NAME":"bottle"`), 1)
+	var got, std streamPayload
+	if err := NewStreamingJSONDecoder(bytes.NewReader(body)).Decode(&got); err != nil {
+		t.Fatal(err)
+	}
+	if err := json.Unmarshal(body, &std); err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(got, std) {
+		t.Errorf("got\n%+v\nexpected\n%+v", got, std)
+	}
+	if got.Name != expected.Name || *got.Count != count || got.Child.Name != "child" {
+		t.Errorf("got %+v, expected %+v", got, *expected)
+	}
+}
+
+func TestStreamingJSONDecoderErrors(t *testing.T) {
+	cases := []struct {
+		Name  string
+		Body  string
+		Opts  []StreamingDecoderOption
+		Error error
+		Msg   string
+	}{
+		{"too deep", `{"child":{"child":{"child":{}}}}`, []StreamingDecoderOption{WithMaxJSONDepth(3)}, ErrJSONTooDeep, ""},
+		{"too deep unknown", `{"unknown":[[[[1]]]]}`, []StreamingDecoderOption{WithMaxJSONDepth(3)}, ErrJSONTooDeep, ""},
+		{"too large", `{"name":"` + strings.Repeat("a", 100) + `"}`, []StreamingDecoderOption{WithMaxJSONSize(50)}, ErrJSONTooLarge, ""},
+		{"type mismatch", `{"name":1}`, nil, nil, "name: cannot decode JSON number into Go value of type string"},
+		{"overflow", `{"fixed":[1e3, 300000000000000000000]}`, nil, nil, "fixed: [0]: cannot decode JSON number 1e3 into Go value of type int"},
+		{"truncated", `{"name":"a"`, nil, nil, "unexpected end of JSON input"},
+		{"truncated-value", `{"name":`, nil, nil, "name: unexpected end of JSON input"},
+		{"truncated-skipped", `{"other":[1`, nil, nil, "unexpected end of JSON input"},
+		{"empty", ``, nil, io.EOF, ""},
+	}
+	for _, c := range cases {
+		t.Run(c.Name, func(t *testing.T) {
+			var v streamPayload
+			err := NewStreamingJSONDecoder(strings.NewReader(c.Body), c.Opts...).Decode(&v)
+			if err == nil {
+				t.Fatal("expected an error")
+			}
+			if c.Error != nil && !errors.Is(err, c.Error) {
+				t.Errorf("got error %v, expected %v", err, c.Error)
+			}
+			if c.Msg != "" && err.Error() != c.Msg {
+				t.Errorf("got error %q, expected %q", err.Error(), c.Msg)
+			}
+		})
+	}
+}
+
+func TestStreamingRequestDecoder(t *testing.T) {
+	dec := StreamingRequestDecoder(WithMaxJSONSize(1024))
+	r := httptest.NewRequest("POST", "/", strings.NewReader(`{"name":"a"}`))
+	r.Header.Set("Content-Type", "application/json; charset=utf-8")
+	if _, ok := dec(r).(*streamingDecoder); !ok {
+		t.Errorf("got %T, expected a streaming decoder", dec(r))
+	}
+	r.Header.Set("Content-Type", "application/xml")
+	if _, ok := dec(r).(*streamingDecoder); ok {
+		t.Error("expected XML bodies not to use the streaming decoder")
+	}
+}
+
+func BenchmarkStreamingJSONDecoder(b *testing.B) {
+	items := make([]*streamPayload, 10000)
+	for i := range items {
+		items[i] = &streamPayload{Name: "bottle", Tags: []string{"a", "b"}, Labels: map[string]string{"k": "v"}}
+	}
+	large, err := json.Marshal(items)
+	if err != nil {
+		b.Fatal(err)
+	}
+	b.Run("streaming", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			var v []*streamPayload
+			if err := NewStreamingJSONDecoder(bytes.NewReader(large)).Decode(&v); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+	b.Run("stdlib", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			var v []*streamPayload
+			if err := json.NewDecoder(bytes.NewReader(large)).Decode(&v); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+}
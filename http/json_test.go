@@ -0,0 +1,117 @@
+package http
+
+import (
+	"encoding/json"
+	"math"
+	"testing"
+)
+
+type appender struct{}
+
+func (appender) AppendJSON(b []byte) ([]byte, error) {
+	return append(b, `"appended"`...), nil
+}
+
+func TestAppendJSONString(t *testing.T) {
+	cases := []struct {
+		Name  string
+		Value string
+	}{
+		{"empty", ""},
+		{"ascii", "hello world"},
+		{"quotes", `say "hi" \o/`},
+		{"control", "a\b\f\n\r\t\x00\x1fz"},
+		{"html", "<a href=\"x\">&amp;</a>"},
+		{"unicode", "h\u00e9llo, \u4e16\u754c"},
+		{"separators", "a\u2028b\u2029c"},
+		{"invalid utf-8", "a\xffb\xc3"},
+	}
+	for _, c := range cases {
+		t.Run(c.Name, func(t *testing.T) {
+			expected, _ := json.Marshal(c.Value)
+			if got := AppendJSONString([]byte("x"), c.Value); string(got) != "x"+string(expected) {
+				t.Errorf("got %s, expected x%s", got, expected)
+			}
+		})
+	}
+}
+
+func TestAppendJSONFloat(t *testing.T) {
+	cases := []struct {
+		Name  string
+		Value float64
+		Bits  int
+	}{
+		{"zero", 0, 64},
+		{"integer", 42, 64},
+		{"negative", -1.5, 64},
+		{"small", 1e-7, 64},
+		{"large", 1e21, 64},
+		{"exponent", 1.234e-9, 64},
+		{"float32", 3.14, 32},
+		{"float32 small", 1e-7, 32},
+	}
+	for _, c := range cases {
+		t.Run(c.Name, func(t *testing.T) {
+			var expected []byte
+			if c.Bits == 32 {
+				expected, _ = json.Marshal(float32(c.Value))
+			} else {
+				expected, _ = json.Marshal(c.Value)
+			}
+			got, err := AppendJSONFloat(nil, c.Value, c.Bits)
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+			if string(got) != string(expected) {
+				t.Errorf("got %s, expected %s", got, expected)
+			}
+		})
+	}
+	for _, f := range []float64{math.NaN(), math.Inf(1), math.Inf(-1)} {
+		if _, err := AppendJSONFloat(nil, f, 64); err == nil {
+			t.Errorf("expected error for %v", f)
+		}
+	}
+}
+
+func TestAppendJSONBytes(t *testing.T) {
+	for _, v := range [][]byte{nil, {}, []byte("hello"), {0, 1, 2, 255}} {
+		expected, _ := json.Marshal(v)
+		if got := AppendJSONBytes(nil, v); string(got) != string(expected) {
+			t.Errorf("got %s, expected %s", got, expected)
+		}
+	}
+}
+
+func TestAppendJSONField(t *testing.T) {
+	b := append([]byte{}, '{')
+	b = AppendJSONField(b, `"a":`)
+	b = append(b, '1')
+	b = AppendJSONField(b, `"b":`)
+	b = append(b, "{}"...)
+	b = append(b, '}')
+	if string(b) != `{"a":1,"b":{}}` {
+		t.Errorf("got %s, expected {\"a\":1,\"b\":{}}", b)
+	}
+}
+
+func TestAppendJSON(t *testing.T) {
+	got, err := AppendJSON(nil, appender{})
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if string(got) != `"appended"` {
+		t.Errorf("got %s, expected \"appended\"", got)
+	}
+	got, err = AppendJSON([]byte("["), map[string]int{"b": 2, "a": 1})
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if string(got) != `[{"a":1,"b":2}` {
+		t.Errorf("got %s, expected [{\"a\":1,\"b\":2}", got)
+	}
+	if _, err := AppendJSON(nil, math.NaN()); err == nil {
+		t.Error("expected error for NaN")
+	}
+}
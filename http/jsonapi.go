@@ -0,0 +1,301 @@
+package http
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+)
+
+// JSONAPIContentType is the media type of JSON:API documents, see
+// https://jsonapi.org/format/#content-negotiation.
+const JSONAPIContentType = "application/vnd.api+json"
+
+type (
+	// JSONAPIResource describes how results are rendered as JSON:API
+	// resource objects.
+	JSONAPIResource struct {
+		// Type is the JSON:API resource type.
+		Type string
+		// Relationships maps the names of the result attributes that hold
+		// related resources to the JSON:API type of the related resources.
+		Relationships map[string]string
+	}
+
+	// jsonapiEncoder renders values as JSON:API documents.
+	jsonapiEncoder struct {
+		w      io.Writer
+		res    *JSONAPIResource
+		fields map[string][]string
+		errors bool
+	}
+
+	// jsonapiContext is the value stored in the request context by
+	// WithJSONAPI.
+	jsonapiContext struct {
+		res    *JSONAPIResource
+		fields map[string][]string
+		errors bool
+	}
+
+	// jsonapiError is a JSON:API error object.
+	jsonapiError struct {
+		ID     string         `json:"id,omitempty"`
+		Status string         `json:"status,omitempty"`
+		Code   string         `json:"code,omitempty"`
+		Title  string         `json:"title,omitempty"`
+		Detail string         `json:"detail,omitempty"`
+		Meta   map[string]any `json:"meta,omitempty"`
+	}
+)
+
+// WithJSONAPI returns a copy of ctx that causes ResponseEncoder to render
+// JSON responses as JSON:API documents using res to build the resource
+// objects. The sparse fieldsets are read from the "fields[TYPE]" query
+// string parameters of r. The generated HTTP handlers call WithJSONAPI for
+// the endpoints whose result types define the "jsonapi:type" meta.
+func WithJSONAPI(ctx context.Context, r *http.Request, res *JSONAPIResource) context.Context {
+	return context.WithValue(ctx, jsonapiKey, &jsonapiContext{res: res, fields: JSONAPIFields(r)})
+}
+
+// WithJSONAPIErrors returns a copy of ctx initialized with WithJSONAPI that
+// causes ResponseEncoder to render the encoded values as JSON:API error
+// objects. The generated error encoders of the JSON:API endpoints call
+// WithJSONAPIErrors prior to encoding the errors defined in the design. ctx is
+// returned unchanged if it was not initialized with WithJSONAPI.
+func WithJSONAPIErrors(ctx context.Context) context.Context {
+	c := jsonapiFromContext(ctx)
+	if c == nil {
+		return ctx
+	}
+	return context.WithValue(ctx, jsonapiKey, &jsonapiContext{res: c.res, fields: c.fields, errors: true})
+}
+
+// JSONAPIFields returns the sparse fieldsets requested with the
+// "fields[TYPE]=a,b" query string parameters of r indexed by resource type,
+// see https://jsonapi.org/format/#fetching-sparse-fieldsets.
+func JSONAPIFields(r *http.Request) map[string][]string {
+	var fields map[string][]string
+	for k, vals := range r.URL.Query() {
+		if !strings.HasPrefix(k, "fields[") || !strings.HasSuffix(k, "]") {
+			continue
+		}
+		typ := k[len("fields[") : len(k)-1]
+		if typ == "" {
+			continue
+		}
+		if fields == nil {
+			fields = make(map[string][]string)
+		}
+		names := []string{}
+		for _, v := range vals {
+			for _, n := range strings.Split(v, ",") {
+				if n = strings.TrimSpace(n); n != "" {
+					names = append(names, n)
+				}
+			}
+		}
+		fields[typ] = append(fields[typ], names...)
+	}
+	return fields
+}
+
+// NewJSONAPIEncoder returns an encoder that writes values to w as JSON:API
+// documents. Objects are rendered as resource objects whose "id" member is
+// the value of the "id" attribute, the attributes listed in
+// res.Relationships are rendered as relationships, a "links" attribute is
+// rendered as the resource links and all other attributes are rendered as the
+// resource attributes. Arrays are rendered as collections of resource
+// objects. fields lists the sparse fieldsets indexed by resource type and may
+// be nil. Error responses (*ErrorResponse) are rendered as JSON:API error
+// objects.
+func NewJSONAPIEncoder(w io.Writer, res *JSONAPIResource, fields map[string][]string) Encoder {
+	return &jsonapiEncoder{w: w, res: res, fields: fields}
+}
+
+// Encode writes the JSON:API document for v.
+func (e *jsonapiEncoder) Encode(v any) error {
+	if er, ok := v.(*ErrorResponse); ok {
+		return e.encode("errors", []*jsonapiError{{
+			ID:     er.ID,
+			Status: fmt.Sprint(er.StatusCode()),
+			Code:   er.Name,
+			Title:  er.Name,
+			Detail: er.Message,
+		}})
+	}
+	raw, err := decodeRaw(v)
+	if err != nil {
+		return err
+	}
+	if e.errors {
+		return e.encode("errors", errorObjects(raw))
+	}
+	return e.encode("data", e.data(raw))
+}
+
+// encode writes a JSON:API document with the given top level member.
+func (e *jsonapiEncoder) encode(member string, v any) error {
+	return json.NewEncoder(e.w).Encode(map[string]any{member: v})
+}
+
+// data returns the JSON:API primary data rendered from the JSON
+// representation raw of a result.
+func (e *jsonapiEncoder) data(raw any) any {
+	switch val := raw.(type) {
+	case map[string]any:
+		return e.resource(val)
+	case []any:
+		data := make([]any, len(val))
+		for i, elem := range val {
+			if obj, ok := elem.(map[string]any); ok {
+				data[i] = e.resource(obj)
+			} else {
+				data[i] = elem
+			}
+		}
+		return data
+	default:
+		return raw
+	}
+}
+
+// rawBuffers is the pool of buffers holding the JSON encoding of the values
+// decoded by decodeRaw.
+var rawBuffers = sync.Pool{New: func() any { return new(bytes.Buffer) }}
+
+// decodeRaw returns the generic JSON representation of v.
+func decodeRaw(v any) (any, error) {
+	buf := rawBuffers.Get().(*bytes.Buffer)
+	defer func() {
+		buf.Reset()
+		rawBuffers.Put(buf)
+	}()
+	if err := json.NewEncoder(buf).Encode(v); err != nil {
+		return nil, err
+	}
+	dec := json.NewDecoder(buf)
+	dec.UseNumber()
+	var raw any
+	if err := dec.Decode(&raw); err != nil {
+		return nil, err
+	}
+	return raw, nil
+}
+
+// errorObjects returns the JSON:API error objects rendered from the JSON
+// representation raw of an error. The "id", "name" and "message" members of
+// the goa error bodies map to the error object id, code and detail, the other
+// members are rendered in the error object meta.
+func errorObjects(raw any) []*jsonapiError {
+	if arr, ok := raw.([]any); ok {
+		var errs []*jsonapiError
+		for _, elem := range arr {
+			errs = append(errs, errorObjects(elem)...)
+		}
+		return errs
+	}
+	obj, ok := raw.(map[string]any)
+	if !ok {
+		return []*jsonapiError{{Detail: fmt.Sprint(raw)}}
+	}
+	jerr := &jsonapiError{}
+	for k, v := range obj {
+		s, isString := v.(string)
+		switch {
+		case k == "id" && isString:
+			jerr.ID = s
+		case k == "name" && isString:
+			jerr.Code = s
+			jerr.Title = s
+		case k == "message" && isString:
+			jerr.Detail = s
+		default:
+			if jerr.Meta == nil {
+				jerr.Meta = make(map[string]any)
+			}
+			jerr.Meta[k] = v
+		}
+	}
+	return []*jsonapiError{jerr}
+}
+
+// resource builds the JSON:API resource object corresponding to obj.
+func (e *jsonapiEncoder) resource(obj map[string]any) map[string]any {
+	res := map[string]any{"type": e.res.Type}
+	if id, ok := obj["id"]; ok && id != nil {
+		res["id"] = fmt.Sprint(id)
+	}
+	if links, ok := obj["links"]; ok && links != nil {
+		res["links"] = links
+	}
+	fieldset, sparse := e.fields[e.res.Type]
+	included := func(name string) bool {
+		if !sparse {
+			return true
+		}
+		for _, f := range fieldset {
+			if f == name {
+				return true
+			}
+		}
+		return false
+	}
+	attributes := make(map[string]any)
+	relationships := make(map[string]any)
+	for name, val := range obj {
+		if name == "id" || name == "links" || !included(name) {
+			continue
+		}
+		if typ, ok := e.res.Relationships[name]; ok {
+			relationships[name] = map[string]any{"data": identifiers(typ, val)}
+			continue
+		}
+		attributes[name] = val
+	}
+	if len(attributes) > 0 {
+		res["attributes"] = attributes
+	}
+	if len(relationships) > 0 {
+		res["relationships"] = relationships
+	}
+	return res
+}
+
+// identifiers returns the JSON:API resource identifier objects of the related
+// resources val of the given type.
+func identifiers(typ string, val any) any {
+	switch v := val.(type) {
+	case map[string]any:
+		id, ok := v["id"]
+		if !ok || id == nil {
+			return nil
+		}
+		return map[string]any{"type": typ, "id": fmt.Sprint(id)}
+	case []any:
+		ids := make([]any, 0, len(v))
+		for _, elem := range v {
+			if id := identifiers(typ, elem); id != nil {
+				ids = append(ids, id)
+			}
+		}
+		return ids
+	case nil:
+		return nil
+	default:
+		return map[string]any{"type": typ, "id": fmt.Sprint(v)}
+	}
+}
+
+// jsonapiFromContext returns the JSON:API rendering information stored in
+// ctx by WithJSONAPI if any.
+func jsonapiFromContext(ctx context.Context) *jsonapiContext {
+	if c, ok := ctx.Value(jsonapiKey).(*jsonapiContext); ok {
+		return c
+	}
+	return nil
+}
@@ -0,0 +1,106 @@
+package http
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+type (
+	jsonapiAccount struct {
+		ID string `json:"id"`
+	}
+
+	jsonapiBottle struct {
+		ID      int                          `json:"id"`
+		Name    string                       `json:"name,omitempty"`
+		Vintage int                          `json:"vintage,omitempty"`
+		Account *jsonapiAccount              `json:"account,omitempty"`
+		Links   map[string]map[string]string `json:"links,omitempty"`
+	}
+
+	jsonapiServiceError struct {
+		Name    string `json:"name"`
+		ID      string `json:"id"`
+		Message string `json:"message"`
+		Fault   bool   `json:"fault"`
+	}
+)
+
+func TestJSONAPIEncoder(t *testing.T) {
+	res := &JSONAPIResource{Type: "bottles", Relationships: map[string]string{"account": "accounts"}}
+	bottle := &jsonapiBottle{
+		ID:      1,
+		Name:    "merlot",
+		Vintage: 2019,
+		Account: &jsonapiAccount{ID: "a1"},
+		Links:   map[string]map[string]string{"self": {"href": "/bottles/1"}},
+	}
+	cases := []struct {
+		name   string
+		url    string
+		errors bool
+		value  any
+		want   string
+	}{
+		{"resource", "/", false, bottle,
+			`{"data":{"attributes":{"name":"merlot","vintage":2019},"id":"1","links":{"self":{"href":"/bottles/1"}},"relationships":{"account":{"data":{"id":"a1","type":"accounts"}}},"type":"bottles"}}`},
+		{"collection", "/", false, []*jsonapiBottle{{ID: 1}, {ID: 2}},
+			`{"data":[{"id":"1","type":"bottles"},{"id":"2","type":"bottles"}]}`},
+		{"sparse-fieldset", "/?fields[bottles]=name,account", false, bottle,
+			`{"data":{"attributes":{"name":"merlot"},"id":"1","links":{"self":{"href":"/bottles/1"}},"relationships":{"account":{"data":{"id":"a1","type":"accounts"}}},"type":"bottles"}}`},
+		{"other-type-fieldset", "/?fields[accounts]=id", false, &jsonapiBottle{ID: 1, Name: "merlot"},
+			`{"data":{"attributes":{"name":"merlot"},"id":"1","type":"bottles"}}`},
+		{"error-response", "/", false, &ErrorResponse{Name: "bad_request", ID: "x", Message: "invalid"},
+			`{"errors":[{"id":"x","status":"400","code":"bad_request","title":"bad_request","detail":"invalid"}]}`},
+		{"design-error", "/", true, &jsonapiServiceError{Name: "not_found", ID: "y", Message: "no bottle", Fault: false},
+			`{"errors":[{"id":"y","code":"not_found","title":"not_found","detail":"no bottle","meta":{"fault":false}}]}`},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			r := httptest.NewRequest("GET", c.url, nil)
+			ctx := context.WithValue(context.Background(), AcceptTypeKey, "application/json")
+			ctx = WithJSONAPI(ctx, r, res)
+			if c.errors {
+				ctx = WithJSONAPIErrors(ctx)
+			}
+			w := httptest.NewRecorder()
+			if err := ResponseEncoder(ctx, w).Encode(c.value); err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+			if ct := w.Header().Get("Content-Type"); ct != JSONAPIContentType {
+				t.Errorf("got content type %q, expected %q", ct, JSONAPIContentType)
+			}
+			if got := strings.TrimSpace(w.Body.String()); got != c.want {
+				t.Errorf("got\n%s\nexpected\n%s", got, c.want)
+			}
+		})
+	}
+}
+
+func TestJSONAPIEncoderNotJSON(t *testing.T) {
+	r := httptest.NewRequest("GET", "/", nil)
+	ctx := context.WithValue(context.Background(), AcceptTypeKey, "application/xml")
+	ctx = WithJSONAPI(ctx, r, &JSONAPIResource{Type: "bottles"})
+	w := httptest.NewRecorder()
+	ResponseEncoder(ctx, w)
+	if ct := w.Header().Get("Content-Type"); ct != "application/xml" {
+		t.Errorf("got content type %q, expected %q", ct, "application/xml")
+	}
+}
+
+func TestJSONAPIFields(t *testing.T) {
+	r, _ := http.NewRequest("GET", "/?fields[bottles]=name,%20vintage&fields[accounts]=id&fields[]=x&filter=y", nil)
+	fields := JSONAPIFields(r)
+	if len(fields) != 2 {
+		t.Fatalf("got %d fieldsets, expected 2: %v", len(fields), fields)
+	}
+	if got := strings.Join(fields["bottles"], ","); got != "name,vintage" {
+		t.Errorf("got bottles fields %q, expected %q", got, "name,vintage")
+	}
+	if got := strings.Join(fields["accounts"], ","); got != "id" {
+		t.Errorf("got accounts fields %q, expected %q", got, "id")
+	}
+}
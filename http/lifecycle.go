@@ -0,0 +1,139 @@
+package http
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+)
+
+type (
+	// Lifecycle contains the hooks that Run calls when configured with
+	// WithLifecycle: the startup hooks, e.g. warming caches or filling
+	// connection pools, run once the server listens and the shutdown hooks
+	// run once it is shut down. Lifecycle also reports whether the startup
+	// hooks completed so that the instance only reports ready once it is
+	// warm. Lifecycle is safe for concurrent use.
+	Lifecycle struct {
+		mu       sync.Mutex
+		startup  []*lifecycleHook
+		shutdown []*lifecycleHook
+		started  bool
+		err      error
+	}
+
+	// lifecycleHook is a named startup or shutdown hook.
+	lifecycleHook struct {
+		name string
+		fn   func(context.Context) error
+	}
+)
+
+// ErrNotStarted is the error returned by Lifecycle.Ready while the startup
+// hooks run.
+var ErrNotStarted = errors.New("startup hooks not completed")
+
+// NewLifecycle returns an empty lifecycle.
+//
+// Example:
+//
+//	lc := goahttp.NewLifecycle()
+//	lc.RegisterStartupHook("cache", cache.Warm)
+//	lc.RegisterShutdownHook("db", func(context.Context) error { return db.Close() })
+//	admin := goahttp.NewAdminServer(":9090", goahttp.WithHealthCheck("startup", lc.Ready))
+//	go admin.Run(ctx)
+//	err := goahttp.Run(ctx, srv, goahttp.WithLifecycle(lc))
+func NewLifecycle() *Lifecycle {
+	return &Lifecycle{}
+}
+
+// WithLifecycle makes Run call the hooks of lc. Run runs the startup hooks
+// concurrently once the server listens and stops the server and returns the
+// error if one of them fails. Run runs the shutdown hooks in the reverse order
+// of their registration once the server is shut down, within the shutdown
+// timeout.
+func WithLifecycle(lc *Lifecycle) RunOption {
+	return func(o *runOptions) { o.lifecycle = lc }
+}
+
+// RegisterStartupHook registers a function called by Run once the server
+// listens.
+func (lc *Lifecycle) RegisterStartupHook(name string, fn func(context.Context) error) {
+	lc.mu.Lock()
+	defer lc.mu.Unlock()
+	lc.startup = append(lc.startup, &lifecycleHook{name: name, fn: fn})
+}
+
+// RegisterShutdownHook registers a function called by Run once the server is
+// shut down.
+func (lc *Lifecycle) RegisterShutdownHook(name string, fn func(context.Context) error) {
+	lc.mu.Lock()
+	defer lc.mu.Unlock()
+	lc.shutdown = append(lc.shutdown, &lifecycleHook{name: name, fn: fn})
+}
+
+// Ready returns nil once all the startup hooks completed successfully,
+// ErrNotStarted while they run and the error of the first hook that failed
+// otherwise. Ready has the signature of a readiness check, see
+// AdminServer.AddHealthCheck.
+func (lc *Lifecycle) Ready(context.Context) error {
+	lc.mu.Lock()
+	defer lc.mu.Unlock()
+	if lc.err != nil {
+		return lc.err
+	}
+	if !lc.started {
+		return ErrNotStarted
+	}
+	return nil
+}
+
+// Start runs the startup hooks concurrently and returns the error of the
+// first hook that failed, if any.
+func (lc *Lifecycle) Start(ctx context.Context) error {
+	lc.mu.Lock()
+	hooks := lc.startup
+	lc.mu.Unlock()
+
+	errs := make([]error, len(hooks))
+	var wg sync.WaitGroup
+	for i, h := range hooks {
+		wg.Add(1)
+		go func(i int, h *lifecycleHook) {
+			defer wg.Done()
+			if err := h.fn(ctx); err != nil {
+				errs[i] = fmt.Errorf("startup hook %q: %w", h.name, err)
+			}
+		}(i, h)
+	}
+	wg.Wait()
+
+	lc.mu.Lock()
+	defer lc.mu.Unlock()
+	for _, err := range errs {
+		if err != nil {
+			lc.err = err
+			return err
+		}
+	}
+	lc.started = true
+	return nil
+}
+
+// Stop runs the shutdown hooks in the reverse order of their registration and
+// returns the error of the first hook that failed, if any. All the hooks run
+// even if one of them fails.
+func (lc *Lifecycle) Stop(ctx context.Context) error {
+	lc.mu.Lock()
+	hooks := lc.shutdown
+	lc.started = false
+	lc.mu.Unlock()
+
+	var res error
+	for i := len(hooks) - 1; i >= 0; i-- {
+		if err := hooks[i].fn(ctx); err != nil && res == nil {
+			res = fmt.Errorf("shutdown hook %q: %w", hooks[i].name, err)
+		}
+	}
+	return res
+}
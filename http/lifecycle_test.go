@@ -0,0 +1,65 @@
+package http
+
+import (
+	"context"
+	"errors"
+	"net"
+	"net/http"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestRunLifecycle(t *testing.T) {
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	lc := NewLifecycle()
+	release := make(chan struct{})
+	lc.RegisterStartupHook("cache", func(context.Context) error { <-release; return nil })
+	var stopped []string
+	lc.RegisterShutdownHook("db", func(context.Context) error { stopped = append(stopped, "db"); return nil })
+	lc.RegisterShutdownHook("queue", func(context.Context) error { stopped = append(stopped, "queue"); return nil })
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error)
+	srv := &http.Server{Handler: http.NotFoundHandler(), ReadHeaderTimeout: time.Second}
+	go func() { done <- Run(ctx, srv, WithListener(l), WithLifecycle(lc)) }()
+
+	if err := lc.Ready(ctx); !errors.Is(err, ErrNotStarted) {
+		t.Errorf("got %v before the startup hooks completed, expected ErrNotStarted", err)
+	}
+	close(release)
+	deadline := time.Now().Add(time.Second)
+	for lc.Ready(ctx) != nil {
+		if time.Now().After(deadline) {
+			t.Fatal("timeout waiting for the startup hooks")
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+	cancel()
+	if err := <-done; err != nil {
+		t.Fatalf("got error %v, expected the server to shut down gracefully", err)
+	}
+	if expected := []string{"queue", "db"}; !reflect.DeepEqual(stopped, expected) {
+		t.Errorf("got shutdown hooks %v, expected %v", stopped, expected)
+	}
+}
+
+func TestRunLifecycleStartupError(t *testing.T) {
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	lc := NewLifecycle()
+	boom := errors.New("boom")
+	lc.RegisterStartupHook("pool", func(context.Context) error { return boom })
+	srv := &http.Server{Handler: http.NotFoundHandler(), ReadHeaderTimeout: time.Second}
+	if err := Run(context.Background(), srv, WithListener(l), WithLifecycle(lc)); !errors.Is(err, boom) {
+		t.Errorf("got error %v, expected %v", err, boom)
+	}
+	if err := lc.Ready(context.Background()); !errors.Is(err, boom) {
+		t.Errorf("got readiness %v, expected %v", err, boom)
+	}
+}
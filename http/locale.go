@@ -0,0 +1,53 @@
+package http
+
+import (
+	"context"
+	"strings"
+	"time"
+)
+
+// TimeZoneHeader is the name of the request header that holds the IANA name of
+// the time zone of the client by default, e.g. "Europe/Paris".
+const TimeZoneHeader = "Time-Zone"
+
+// WithTimeZone returns a copy of ctx that holds the given time zone.
+func WithTimeZone(ctx context.Context, loc *time.Location) context.Context {
+	return context.WithValue(ctx, timeZoneKey, loc)
+}
+
+// TimeZone returns the time zone stored in ctx by WithTimeZone, UTC if there is
+// none.
+func TimeZone(ctx context.Context) *time.Location {
+	if loc, ok := ctx.Value(timeZoneKey).(*time.Location); ok && loc != nil {
+		return loc
+	}
+	return time.UTC
+}
+
+// Language returns the first of the language tags stored in ctx by
+// WithLanguages that matches one of the supported tags, def if none does. A
+// tag matches a supported tag if they are equal or if the tag is the language
+// of the supported tag or reciprocally (e.g. "fr" matches "fr-CA" and "fr-CA"
+// matches "fr"). The comparison is case insensitive and the returned value is
+// the supported tag.
+//
+// Example:
+//
+//	lang := goahttp.Language(ctx, []string{"en", "fr", "pt-BR"}, "en")
+func Language(ctx context.Context, supported []string, def string) string {
+	for _, lang := range Languages(ctx) {
+		for _, s := range supported {
+			if strings.EqualFold(lang, s) {
+				return s
+			}
+		}
+		base, _, _ := strings.Cut(lang, "-")
+		for _, s := range supported {
+			sbase, _, _ := strings.Cut(s, "-")
+			if strings.EqualFold(base, sbase) {
+				return s
+			}
+		}
+	}
+	return def
+}
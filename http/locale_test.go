@@ -0,0 +1,40 @@
+package http
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestTimeZone(t *testing.T) {
+	if got := TimeZone(context.Background()); got != time.UTC {
+		t.Errorf("got %v, expected UTC", got)
+	}
+	loc := time.FixedZone("test", 3600)
+	if got := TimeZone(WithTimeZone(context.Background(), loc)); got != loc {
+		t.Errorf("got %v, expected %v", got, loc)
+	}
+}
+
+func TestLanguage(t *testing.T) {
+	supported := []string{"en", "fr-CA", "pt"}
+	cases := map[string]struct {
+		Langs    []string
+		Expected string
+	}{
+		"none":        {nil, "en"},
+		"exact":       {[]string{"fr-ca"}, "fr-CA"},
+		"base":        {[]string{"fr"}, "fr-CA"},
+		"region":      {[]string{"pt-br"}, "pt"},
+		"order":       {[]string{"de", "pt", "en"}, "pt"},
+		"exact-first": {[]string{"en-us", "en"}, "en"},
+		"unsupported": {[]string{"de"}, "en"},
+	}
+	for k, c := range cases {
+		t.Run(k, func(t *testing.T) {
+			if got := Language(WithLanguages(context.Background(), c.Langs), supported, "en"); got != c.Expected {
+				t.Errorf("got %q, expected %q", got, c.Expected)
+			}
+		})
+	}
+}
@@ -0,0 +1,121 @@
+package middleware
+
+import (
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// AccessLogFormat is the format of the lines written by the AccessLog
+// middleware.
+type AccessLogFormat int
+
+const (
+	// CommonLogFormat is the NCSA Common Log Format:
+	// host ident authuser [date] "request" status bytes
+	CommonLogFormat AccessLogFormat = iota
+	// CombinedLogFormat is the Apache Combined Log Format, the Common Log
+	// Format followed by the quoted Referer and User-Agent headers.
+	CombinedLogFormat
+)
+
+// accessLogTimeFormat is the format of the date field of the access logs.
+const accessLogTimeFormat = "02/Jan/2006:15:04:05 -0700"
+
+// AccessLog returns a middleware that writes one line per request to w in the
+// given format once the request has been served, e.g.:
+//
+//	127.0.0.1 - frank [10/Oct/2000:13:55:36 -0700] "GET /bottles/1 HTTP/1.1" 200 2326 "http://example.com/" "curl/8.0"
+//
+// Each line is written with a single call to w.Write so that w may be shared
+// by concurrent requests provided its Write method is safe for concurrent
+// use, e.g. a *os.File or a *middleware.RotatingFile.
+//
+// Example:
+//
+//	f, err := middleware.NewRotatingFile("/var/log/app/access.log", middleware.RotateAge(24*time.Hour))
+//	if err != nil {
+//	    return err
+//	}
+//	go f.ReopenOnSignal(ctx, logErr)
+//	handler = httpmdlwr.AccessLog(f, httpmdlwr.CombinedLogFormat)(handler)
+func AccessLog(w io.Writer, format AccessLogFormat) func(http.Handler) http.Handler {
+	return func(h http.Handler) http.Handler {
+		return http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+			started := time.Now()
+			capture := CaptureResponse(rw)
+			h.ServeHTTP(capture, r)
+			w.Write(accessLogLine(r, capture, started, format)) // nolint: errcheck
+		})
+	}
+}
+
+// accessLogLine formats the access log line of the request.
+func accessLogLine(r *http.Request, rw *ResponseCapture, started time.Time, format AccessLogFormat) []byte {
+	user := "-"
+	if u, _, ok := r.BasicAuth(); ok && u != "" {
+		user = u
+	} else if r.URL.User != nil && r.URL.User.Username() != "" {
+		user = r.URL.User.Username()
+	}
+	status := rw.StatusCode
+	if status == 0 {
+		status = http.StatusOK
+	}
+	size := "-"
+	if rw.ContentLength > 0 {
+		size = strconv.Itoa(rw.ContentLength)
+	}
+	b := make([]byte, 0, 256)
+	b = append(b, accessLogField(from(r))...)
+	b = append(b, " - "...)
+	b = append(b, accessLogField(user)...)
+	b = append(b, " ["...)
+	b = started.AppendFormat(b, accessLogTimeFormat)
+	b = append(b, "] \""...)
+	b = append(b, accessLogEscape(r.Method+" "+r.URL.RequestURI()+" "+r.Proto)...)
+	b = append(b, "\" "...)
+	b = strconv.AppendInt(b, int64(status), 10)
+	b = append(b, ' ')
+	b = append(b, size...)
+	if format == CombinedLogFormat {
+		b = append(b, " \""...)
+		b = append(b, accessLogEscape(r.Referer())...)
+		b = append(b, "\" \""...)
+		b = append(b, accessLogEscape(r.UserAgent())...)
+		b = append(b, '"')
+	}
+	return append(b, '\n')
+}
+
+// accessLogField returns "-" if s is empty and s with spaces and control
+// characters escaped otherwise.
+func accessLogField(s string) string {
+	if s == "" {
+		return "-"
+	}
+	return strings.ReplaceAll(accessLogEscape(s), " ", "\\x20")
+}
+
+// accessLogEscape escapes the double quotes, backslashes and control
+// characters of s so that it cannot break the log line.
+func accessLogEscape(s string) string {
+	var b strings.Builder
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		switch {
+		case c == '"' || c == '\\':
+			b.WriteByte('\\')
+			b.WriteByte(c)
+		case c < 0x20 || c == 0x7f:
+			b.WriteString("\\x")
+			b.WriteString(strconv.FormatUint(uint64(c)>>4, 16))
+			b.WriteString(strconv.FormatUint(uint64(c)&0xf, 16))
+		default:
+			b.WriteByte(c)
+		}
+	}
+	return b.String()
+}
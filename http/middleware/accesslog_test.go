@@ -0,0 +1,52 @@
+package middleware_test
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"regexp"
+	"testing"
+
+	httpm "goa.design/goa/v3/http/middleware"
+)
+
+func TestAccessLog(t *testing.T) {
+	h := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/empty" {
+			w.WriteHeader(http.StatusNoContent)
+			return
+		}
+		w.Write([]byte("hello")) // nolint: errcheck
+	})
+	cases := []struct {
+		Name     string
+		Format   httpm.AccessLogFormat
+		Path     string
+		Setup    func(*http.Request)
+		Expected string
+	}{
+		{"common", httpm.CommonLogFormat, "/bottles?id=1", nil,
+			`^192\.0\.2\.1 - - \[\d{2}/\w{3}/\d{4}:\d{2}:\d{2}:\d{2} [+-]\d{4}\] "GET /bottles\?id=1 HTTP/1\.1" 200 5\n$`},
+		{"combined", httpm.CombinedLogFormat, "/empty", func(r *http.Request) {
+			r.SetBasicAuth("frank", "secret")
+			r.Header.Set("Referer", "http://example.com/")
+			r.Header.Set("User-Agent", `evil "agent"`)
+		}, `^192\.0\.2\.1 - frank \[.+\] "GET /empty HTTP/1\.1" 204 - "http://example\.com/" "evil \\"agent\\""\n$`},
+		{"forwarded", httpm.CombinedLogFormat, "/", func(r *http.Request) {
+			r.Header.Set("X-Forwarded-For", "10.0.0.1")
+		}, `^10\.0\.0\.1 - - \[.+\] "GET / HTTP/1\.1" 200 5 "" ""\n$`},
+	}
+	for _, c := range cases {
+		t.Run(c.Name, func(t *testing.T) {
+			var buf bytes.Buffer
+			req := httptest.NewRequest("GET", c.Path, nil)
+			if c.Setup != nil {
+				c.Setup(req)
+			}
+			httpm.AccessLog(&buf, c.Format)(h).ServeHTTP(httptest.NewRecorder(), req)
+			if !regexp.MustCompile(c.Expected).Match(buf.Bytes()) {
+				t.Errorf("got %q, expected match for %s", buf.String(), c.Expected)
+			}
+		})
+	}
+}
@@ -0,0 +1,177 @@
+package middleware
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+	"time"
+
+	goahttp "goa.design/goa/v3/http"
+	"goa.design/goa/v3/middleware"
+	goa "goa.design/goa/v3/pkg"
+)
+
+type (
+	// BudgetPolicy defines how the Budget middleware handles the responses
+	// that exceed their budget.
+	BudgetPolicy int
+
+	// BudgetOption configures the Budget middleware.
+	BudgetOption func(*budgetOptions)
+
+	// budgetOptions contains the Budget middleware options.
+	budgetOptions struct {
+		policy   BudgetPolicy
+		exceeded middleware.Counter
+	}
+
+	// budgetWriter is the response writer used to enforce the response
+	// size budget.
+	budgetWriter struct {
+		http.ResponseWriter
+		policy   BudgetPolicy
+		max      int64
+		written  int64
+		status   int
+		buf      bytes.Buffer
+		exceeded bool
+	}
+)
+
+const (
+	// BudgetReject replaces the responses whose body exceeds the size
+	// budget with a 500 Internal Server Error response and cancels the
+	// request context once the latency budget is exhausted. The response
+	// bodies are buffered up to the size budget.
+	BudgetReject BudgetPolicy = iota
+	// BudgetTruncate truncates the response bodies that exceed the size
+	// budget. The latency budget is only reported.
+	BudgetTruncate
+	// BudgetReport only reports the responses that exceed their budget.
+	BudgetReport
+)
+
+// BudgetEnforce sets the policy applied to the responses that exceed their
+// budget, BudgetReject by default.
+func BudgetEnforce(p BudgetPolicy) BudgetOption {
+	return func(o *budgetOptions) { o.policy = p }
+}
+
+// BudgetMetrics records the number of requests that exceed their budget with
+// the http_budget_exceeded_total counter created with p. The counter has the
+// service, method and budget ("response_size" or "latency") labels.
+func BudgetMetrics(p middleware.MetricsProvider) BudgetOption {
+	return func(o *budgetOptions) {
+		o.exceeded = p.NewCounter("http_budget_exceeded_total", "Number of HTTP requests that exceeded their response size or latency budget.")
+	}
+}
+
+// Budget returns a middleware that enforces the response size and
+// latency budget of the wrapped handler. The generated servers of services
+// whose methods define the "budget:response-size" or "budget:latency" meta
+// apply the middleware to the corresponding methods in UseBudgets.
+//
+// Example:
+//
+//	b := &middleware.Budget{Service: "orders", Method: "list", ResponseSize: 1 << 20, Latency: time.Second}
+//	srv.UseMethod("list", httpmdlwr.Budget(b, httpmdlwr.BudgetMetrics(provider)))
+func Budget(b *middleware.Budget, opts ...BudgetOption) func(http.Handler) http.Handler {
+	o := &budgetOptions{}
+	for _, opt := range opts {
+		opt(o)
+	}
+	return func(h http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			start := time.Now()
+			if b.Latency > 0 && o.policy == BudgetReject {
+				ctx, cancel := context.WithTimeout(r.Context(), b.Latency)
+				defer cancel()
+				r = r.WithContext(ctx)
+			}
+			if b.ResponseSize > 0 {
+				bw := &budgetWriter{ResponseWriter: w, policy: o.policy, max: b.ResponseSize}
+				h.ServeHTTP(bw, r)
+				bw.finish(r.Context())
+				if bw.exceeded {
+					o.report(b, "response_size")
+				}
+			} else {
+				h.ServeHTTP(w, r)
+			}
+			if b.Latency > 0 && time.Since(start) > b.Latency {
+				o.report(b, "latency")
+			}
+		})
+	}
+}
+
+// report increments the exceeded budget counter if any.
+func (o *budgetOptions) report(b *middleware.Budget, budget string) {
+	if o.exceeded != nil {
+		o.exceeded.Add(1, "service", b.Service, "method", b.Method, "budget", budget)
+	}
+}
+
+// WriteHeader records the status code, it is written with the body when
+// rejecting over-budget responses.
+func (w *budgetWriter) WriteHeader(code int) {
+	if w.status != 0 {
+		return
+	}
+	w.status = code
+	if w.policy != BudgetReject {
+		w.ResponseWriter.WriteHeader(code)
+	}
+}
+
+// Write writes b applying the budget policy.
+func (w *budgetWriter) Write(b []byte) (int, error) {
+	if w.status == 0 {
+		w.WriteHeader(http.StatusOK)
+	}
+	n := len(b)
+	w.written += int64(n)
+	if w.written > w.max {
+		w.exceeded = true
+	}
+	switch w.policy {
+	case BudgetReject:
+		if !w.exceeded {
+			w.buf.Write(b)
+		}
+		return n, nil
+	case BudgetTruncate:
+		if over := w.written - w.max; over > 0 {
+			if over >= int64(len(b)) {
+				return n, nil
+			}
+			b = b[:int64(len(b))-over]
+		}
+	}
+	if _, err := w.ResponseWriter.Write(b); err != nil {
+		return 0, err
+	}
+	return n, nil
+}
+
+// finish writes the buffered response or the error response if the response
+// exceeded the size budget when rejecting over-budget responses.
+func (w *budgetWriter) finish(ctx context.Context) {
+	if w.policy != BudgetReject {
+		return
+	}
+	if !w.exceeded {
+		if w.status != 0 {
+			w.ResponseWriter.WriteHeader(w.status)
+		}
+		w.ResponseWriter.Write(w.buf.Bytes()) // nolint: errcheck
+		return
+	}
+	for k := range w.Header() {
+		w.Header().Del(k)
+	}
+	err := goa.Fault("response exceeds the %d bytes size budget", w.max)
+	enc := goahttp.ResponseEncoder(ctx, w.ResponseWriter)
+	w.ResponseWriter.WriteHeader(http.StatusInternalServerError)
+	enc.Encode(goahttp.NewErrorResponse(ctx, err)) // nolint: errcheck
+}
@@ -0,0 +1,78 @@
+package middleware_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	httpm "goa.design/goa/v3/http/middleware"
+	"goa.design/goa/v3/middleware"
+)
+
+func TestBudget(t *testing.T) {
+	body := strings.Repeat("a", 10)
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/plain")
+		w.WriteHeader(http.StatusCreated)
+		w.Write([]byte(body[:5])) // nolint: errcheck
+		w.Write([]byte(body[5:])) // nolint: errcheck
+	})
+	cases := []struct {
+		Name         string
+		Size         int64
+		Policy       httpm.BudgetPolicy
+		ExpectedCode int
+		ExpectedBody string
+		Exceeded     float64
+	}{
+		{"reject-within", 10, httpm.BudgetReject, http.StatusCreated, body, 0},
+		{"reject-exceeded", 8, httpm.BudgetReject, http.StatusInternalServerError, "", 1},
+		{"truncate", 8, httpm.BudgetTruncate, http.StatusCreated, body[:8], 1},
+		{"truncate-first-write", 4, httpm.BudgetTruncate, http.StatusCreated, body[:4], 1},
+		{"report", 8, httpm.BudgetReport, http.StatusCreated, body, 1},
+	}
+	for _, c := range cases {
+		t.Run(c.Name, func(t *testing.T) {
+			p := &recordingProvider{values: make(map[string]float64)}
+			b := &middleware.Budget{Service: "svc", Method: "list", ResponseSize: c.Size}
+			h := httpm.Budget(b, httpm.BudgetEnforce(c.Policy), httpm.BudgetMetrics(p))(handler)
+			w := httptest.NewRecorder()
+			h.ServeHTTP(w, httptest.NewRequest("GET", "/", nil))
+			if w.Code != c.ExpectedCode {
+				t.Errorf("got status %d, expected %d", w.Code, c.ExpectedCode)
+			}
+			if c.ExpectedBody != "" && w.Body.String() != c.ExpectedBody {
+				t.Errorf("got body %q, expected %q", w.Body.String(), c.ExpectedBody)
+			}
+			if c.ExpectedCode == http.StatusInternalServerError && !strings.Contains(w.Body.String(), "size budget") {
+				t.Errorf("got body %q, expected error response", w.Body.String())
+			}
+			if got := p.values["http_budget_exceeded_totalservice,svc,method,list,budget,response_size"]; got != c.Exceeded {
+				t.Errorf("got %v exceeded budgets, expected %v", got, c.Exceeded)
+			}
+		})
+	}
+}
+
+func TestBudgetLatency(t *testing.T) {
+	var ctxErr error
+	h := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case <-r.Context().Done():
+			ctxErr = r.Context().Err()
+		case <-time.After(time.Second):
+		}
+	})
+	p := &recordingProvider{values: make(map[string]float64)}
+	b := &middleware.Budget{Service: "svc", Method: "list", Latency: 10 * time.Millisecond}
+	httpm.Budget(b, httpm.BudgetMetrics(p))(h).ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("GET", "/", nil))
+	if ctxErr != context.DeadlineExceeded {
+		t.Errorf("got context error %v, expected %v", ctxErr, context.DeadlineExceeded)
+	}
+	if got := p.values["http_budget_exceeded_totalservice,svc,method,list,budget,latency"]; got != 1 {
+		t.Errorf("got %v exceeded latency budgets, expected 1", got)
+	}
+}
@@ -0,0 +1,322 @@
+package middleware
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+type (
+	// CacheStore stores the responses cached by ResponseCache. The
+	// responses are indexed by request path and by variant, the variant
+	// identifying the query string and the values of the headers the
+	// response varies on. This makes it possible to invalidate all the
+	// responses cached for a path at once, e.g. using a Redis hash per path.
+	// Implementations must be safe for concurrent use.
+	CacheStore interface {
+		// Get returns the response cached for the given path and
+		// variant, nil if there is none.
+		Get(ctx context.Context, path, variant string) (*CachedResponse, error)
+		// Set caches resp for the given path and variant. The response
+		// must be kept at least until resp.Expires.
+		Set(ctx context.Context, path, variant string, resp *CachedResponse) error
+		// Invalidate deletes all the responses cached for path.
+		Invalidate(ctx context.Context, path string) error
+	}
+
+	// CachedResponse is a response stored in a CacheStore.
+	CachedResponse struct {
+		// Status is the response status code.
+		Status int
+		// Header contains the response headers.
+		Header http.Header
+		// Body is the response body.
+		Body []byte
+		// Stored is the time the response was stored.
+		Stored time.Time
+		// Fresh is the time until which the response is served without
+		// being revalidated.
+		Fresh time.Time
+		// Expires is the time until which the response may be served
+		// while being revalidated in the background.
+		Expires time.Time
+	}
+
+	// ResponseCache caches the responses of GET requests in a CacheStore
+	// as directed by their Cache-Control header, see Handler.
+	ResponseCache struct {
+		store CacheStore
+		now   func() time.Time
+
+		mu         sync.Mutex
+		refreshing map[string]struct{}
+	}
+
+	// MemoryCacheStore is a CacheStore that keeps the responses in memory.
+	// Use a shared store (e.g. backed by Redis) when running multiple
+	// server instances so that invalidations apply to all of them.
+	MemoryCacheStore struct {
+		now func() time.Time
+
+		mu    sync.Mutex
+		paths map[string]map[string]*CachedResponse
+	}
+
+	// detachedContext is a context that carries the values of its parent
+	// but is never canceled. It mirrors context.WithoutCancel which is not
+	// available in go1.20, the minimum version in go.mod: drop it in favor
+	// of context.WithoutCancel once the minimum moves to go1.21.
+	detachedContext struct {
+		context.Context
+	}
+)
+
+// NewResponseCache returns a response cache backed by store.
+func NewResponseCache(store CacheStore) *ResponseCache {
+	return &ResponseCache{store: store, now: time.Now, refreshing: make(map[string]struct{})}
+}
+
+// Handler returns a middleware that serves the GET requests from the cache.
+// Requests that are not in the cache are served by the handler and the
+// responses are cached for the duration set by the max-age (or s-maxage)
+// directive of their Cache-Control header. Responses marked no-store, no-cache
+// or private, responses that set cookies and responses with a status code that
+// is not cacheable by default are not cached. Stale responses are served for
+// the duration given by the stale-while-revalidate directive while being
+// refreshed in the background. Responses served from the cache have the Age
+// header set. vary lists the request headers that identify different
+// representations of the same resource in addition to the query string, e.g.
+// "Accept-Language". Requests that set the Authorization header or that use
+// the no-cache directive bypass the cache. The generated servers of services
+// whose methods use the CacheControl DSL apply the middleware to these methods
+// in UseCache.
+//
+// Example:
+//
+//	cache := httpmdlwr.NewResponseCache(httpmdlwr.NewMemoryCacheStore())
+//	srv.UseMethod("list", cache.Handler("Accept-Language"))
+func (c *ResponseCache) Handler(vary ...string) func(http.Handler) http.Handler {
+	return func(h http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.Method != http.MethodGet || r.Header.Get("Authorization") != "" || hasDirective(r.Header.Get("Cache-Control"), "no-cache") {
+				h.ServeHTTP(w, r)
+				return
+			}
+			ctx := r.Context()
+			path, variant := r.URL.Path, cacheVariant(r, vary)
+			if resp, err := c.store.Get(ctx, path, variant); err == nil && resp != nil {
+				now := c.now()
+				if now.Before(resp.Fresh) {
+					c.write(w, resp, now)
+					return
+				}
+				if now.Before(resp.Expires) {
+					c.write(w, resp, now)
+					c.refresh(r, h, path, variant)
+					return
+				}
+			}
+			sw := &sharedResponse{header: make(http.Header)}
+			h.ServeHTTP(sw, r)
+			c.cacheResponse(ctx, path, variant, sw)
+			sw.writeTo(w)
+		})
+	}
+}
+
+// Invalidate deletes the responses cached for the given request paths. It is
+// meant to be called by the services after modifying the resources, the
+// cache is then typically given to the service constructors.
+//
+// Example:
+//
+//	func (s *svc) Update(ctx context.Context, p *items.UpdatePayload) error {
+//		// ... update item
+//		return s.cache.Invalidate(ctx, "/items", "/items/"+p.ID)
+//	}
+func (c *ResponseCache) Invalidate(ctx context.Context, paths ...string) error {
+	for _, p := range paths {
+		if err := c.store.Invalidate(ctx, p); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// refresh serves r in the background and caches the response, unless the
+// variant is already being refreshed.
+func (c *ResponseCache) refresh(r *http.Request, h http.Handler, path, variant string) {
+	key := path + "\n" + variant
+	c.mu.Lock()
+	if _, ok := c.refreshing[key]; ok {
+		c.mu.Unlock()
+		return
+	}
+	c.refreshing[key] = struct{}{}
+	c.mu.Unlock()
+	ctx := detachedContext{r.Context()}
+	req := r.Clone(ctx)
+	go func() {
+		defer func() {
+			c.mu.Lock()
+			delete(c.refreshing, key)
+			c.mu.Unlock()
+		}()
+		sw := &sharedResponse{header: make(http.Header)}
+		h.ServeHTTP(sw, req)
+		c.cacheResponse(ctx, path, variant, sw)
+	}()
+}
+
+// cacheResponse caches the response written in sw if it is cacheable.
+func (c *ResponseCache) cacheResponse(ctx context.Context, path, variant string, sw *sharedResponse) {
+	status := sw.status
+	if status == 0 {
+		status = http.StatusOK
+	}
+	if !isCacheableStatus(status) || sw.header.Get("Set-Cookie") != "" {
+		return
+	}
+	cc := sw.header.Get("Cache-Control")
+	if hasDirective(cc, "no-store") || hasDirective(cc, "no-cache") || hasDirective(cc, "private") {
+		return
+	}
+	maxAge, ok := directiveSeconds(cc, "s-maxage")
+	if !ok {
+		maxAge, ok = directiveSeconds(cc, "max-age")
+	}
+	if !ok || maxAge <= 0 {
+		return
+	}
+	swr, _ := directiveSeconds(cc, "stale-while-revalidate")
+	now := c.now()
+	resp := &CachedResponse{
+		Status:  status,
+		Header:  sw.header.Clone(),
+		Body:    append([]byte(nil), sw.body.Bytes()...),
+		Stored:  now,
+		Fresh:   now.Add(maxAge),
+		Expires: now.Add(maxAge + swr),
+	}
+	c.store.Set(ctx, path, variant, resp) // nolint: errcheck
+}
+
+// write writes the cached response resp to w.
+func (c *ResponseCache) write(w http.ResponseWriter, resp *CachedResponse, now time.Time) {
+	for k, v := range resp.Header {
+		w.Header()[k] = append([]string(nil), v...)
+	}
+	w.Header().Set("Age", strconv.Itoa(int(now.Sub(resp.Stored).Seconds())))
+	w.WriteHeader(resp.Status)
+	w.Write(resp.Body) // nolint: errcheck
+}
+
+// NewMemoryCacheStore returns an in-memory CacheStore.
+func NewMemoryCacheStore() *MemoryCacheStore {
+	return &MemoryCacheStore{now: time.Now, paths: make(map[string]map[string]*CachedResponse)}
+}
+
+// Get implements CacheStore.
+func (s *MemoryCacheStore) Get(_ context.Context, path, variant string) (*CachedResponse, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	resp, ok := s.paths[path][variant]
+	if !ok {
+		return nil, nil
+	}
+	if !s.now().Before(resp.Expires) {
+		delete(s.paths[path], variant)
+		if len(s.paths[path]) == 0 {
+			delete(s.paths, path)
+		}
+		return nil, nil
+	}
+	return resp, nil
+}
+
+// Set implements CacheStore.
+func (s *MemoryCacheStore) Set(_ context.Context, path, variant string, resp *CachedResponse) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	variants, ok := s.paths[path]
+	if !ok {
+		variants = make(map[string]*CachedResponse)
+		s.paths[path] = variants
+	}
+	variants[variant] = resp
+	return nil
+}
+
+// Invalidate implements CacheStore.
+func (s *MemoryCacheStore) Invalidate(_ context.Context, path string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.paths, path)
+	return nil
+}
+
+// Deadline implements context.Context.
+func (detachedContext) Deadline() (time.Time, bool) { return time.Time{}, false }
+
+// Done implements context.Context.
+func (detachedContext) Done() <-chan struct{} { return nil }
+
+// Err implements context.Context.
+func (detachedContext) Err() error { return nil }
+
+// cacheVariant computes the variant of the response to r given the names of
+// the headers the response varies on.
+func cacheVariant(r *http.Request, vary []string) string {
+	var b strings.Builder
+	b.WriteString(r.URL.Query().Encode())
+	for _, name := range vary {
+		b.WriteByte('\n')
+		b.WriteString(strings.Join(r.Header.Values(name), ","))
+	}
+	return b.String()
+}
+
+// isCacheableStatus returns true if responses with the given status code are
+// cacheable by default as defined by RFC 9110.
+func isCacheableStatus(status int) bool {
+	switch status {
+	case 200, 203, 204, 300, 301, 308, 404, 405, 410, 414, 501:
+		return true
+	}
+	return false
+}
+
+// hasDirective returns true if the given Cache-Control header value contains
+// the given directive.
+func hasDirective(cc, name string) bool {
+	_, ok := directive(cc, name)
+	return ok
+}
+
+// directiveSeconds returns the duration given by the value of the given
+// Cache-Control directive in seconds.
+func directiveSeconds(cc, name string) (time.Duration, bool) {
+	v, ok := directive(cc, name)
+	if !ok {
+		return 0, false
+	}
+	n, err := strconv.Atoi(strings.Trim(v, `"`))
+	if err != nil || n < 0 {
+		return 0, false
+	}
+	return time.Duration(n) * time.Second, true
+}
+
+// directive returns the value of the given Cache-Control directive.
+func directive(cc, name string) (string, bool) {
+	for _, d := range strings.Split(cc, ",") {
+		k, v, _ := strings.Cut(strings.TrimSpace(d), "=")
+		if strings.EqualFold(strings.TrimSpace(k), name) {
+			return strings.TrimSpace(v), true
+		}
+	}
+	return "", false
+}
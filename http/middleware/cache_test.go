@@ -0,0 +1,108 @@
+package middleware_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	httpm "goa.design/goa/v3/http/middleware"
+)
+
+func TestResponseCache(t *testing.T) {
+	var (
+		calls atomic.Int32
+		store = httpm.NewMemoryCacheStore()
+		cache = httpm.NewResponseCache(store)
+	)
+	h := cache.Handler("Accept-Language")(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		n := calls.Add(1)
+		switch r.URL.Path {
+		case "/private":
+			w.Header().Set("Cache-Control", "private, max-age=60")
+		case "/none":
+		default:
+			w.Header().Set("Cache-Control", "max-age=60, stale-while-revalidate=30")
+		}
+		w.Write([]byte(strconv.Itoa(int(n)))) // nolint: errcheck
+	}))
+	do := func(url, lang string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest("GET", url, nil)
+		if lang != "" {
+			req.Header.Set("Accept-Language", lang)
+		}
+		w := httptest.NewRecorder()
+		h.ServeHTTP(w, req)
+		return w
+	}
+
+	if w := do("/items", "en"); w.Body.String() != "1" || w.Header().Get("Age") != "" {
+		t.Fatalf("got %q with age %q, expected \"1\" and no age", w.Body.String(), w.Header().Get("Age"))
+	}
+	if w := do("/items", "en"); w.Body.String() != "1" || w.Header().Get("Age") != "0" {
+		t.Errorf("cached: got %q with age %q, expected \"1\" and age 0", w.Body.String(), w.Header().Get("Age"))
+	}
+	if w := do("/items", "fr"); w.Body.String() != "2" {
+		t.Errorf("vary: got %q, expected \"2\"", w.Body.String())
+	}
+	if w := do("/items?page=2", "en"); w.Body.String() != "3" {
+		t.Errorf("query: got %q, expected \"3\"", w.Body.String())
+	}
+	do("/private", "")
+	do("/none", "")
+	if w := do("/private", ""); w.Body.String() != "6" {
+		t.Errorf("private: got %q, expected \"6\"", w.Body.String())
+	}
+	if w := do("/none", ""); w.Body.String() != "7" {
+		t.Errorf("no cache control: got %q, expected \"7\"", w.Body.String())
+	}
+
+	// Stale responses are served while being refreshed.
+	resp, _ := store.Get(context.Background(), "/items", "\nen")
+	if resp == nil {
+		t.Fatal("response not found in cache")
+	}
+	resp.Fresh = time.Now().Add(-time.Second)
+	if w := do("/items", "en"); w.Body.String() != "1" {
+		t.Errorf("stale: got %q, expected \"1\"", w.Body.String())
+	}
+	for i := 0; i < 1000; i++ {
+		if resp, _ := store.Get(context.Background(), "/items", "\nen"); string(resp.Body) == "8" {
+			break
+		}
+		time.Sleep(time.Millisecond)
+	}
+	if w := do("/items", "en"); w.Body.String() != "8" {
+		t.Errorf("refreshed: got %q, expected \"8\"", w.Body.String())
+	}
+
+	if err := cache.Invalidate(context.Background(), "/items"); err != nil {
+		t.Fatal(err)
+	}
+	if w := do("/items", "fr"); w.Body.String() != "9" {
+		t.Errorf("invalidated: got %q, expected \"9\"", w.Body.String())
+	}
+}
+
+func TestResponseCacheBypass(t *testing.T) {
+	var calls int
+	h := httpm.NewResponseCache(httpm.NewMemoryCacheStore()).Handler()(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		w.Header().Set("Cache-Control", "max-age=60")
+	}))
+	for _, header := range []string{"Authorization", "Cache-Control"} {
+		for i := 0; i < 2; i++ {
+			req := httptest.NewRequest("GET", "/"+header, nil)
+			req.Header.Set(header, "no-cache")
+			h.ServeHTTP(httptest.NewRecorder(), req)
+		}
+	}
+	h.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("POST", "/", nil))
+	h.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("POST", "/", nil))
+	if calls != 6 {
+		t.Errorf("got %d calls, expected 6", calls)
+	}
+}
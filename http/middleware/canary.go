@@ -0,0 +1,167 @@
+package middleware
+
+import (
+	"expvar"
+	"net/http"
+	"sync/atomic"
+	"time"
+
+	"goa.design/goa/v3/middleware"
+)
+
+type (
+	// Canary is a http.Handler that splits the traffic between a stable and
+	// a canary handler, typically two muxers on which the v1 and v2
+	// implementations of the same services are mounted, enabling in-process
+	// canary releases. Requests are routed to the canary handler if they
+	// match the header or cookie set with CanaryHeader or CanaryCookie or
+	// else with the probability set with CanaryWeight. Canary records the
+	// number of requests, server errors and the handling time of each arm so
+	// that both implementations can be compared. Canary is safe for
+	// concurrent use.
+	Canary struct {
+		stable  http.Handler
+		canary  http.Handler
+		header  string
+		hvalue  string
+		cookie  string
+		cvalue  string
+		sampler middleware.Sampler
+
+		arms [2]canaryArm
+	}
+
+	// CanaryOption customizes the traffic split done by Canary.
+	CanaryOption func(*Canary)
+
+	// CanarySnapshot is a point in time copy of the metrics recorded by
+	// Canary.
+	CanarySnapshot struct {
+		// Stable contains the metrics of the stable handler.
+		Stable *CanaryArmSnapshot `json:"stable"`
+		// Canary contains the metrics of the canary handler.
+		Canary *CanaryArmSnapshot `json:"canary"`
+	}
+
+	// CanaryArmSnapshot contains the metrics of one of the handlers of a
+	// Canary.
+	CanaryArmSnapshot struct {
+		// Requests is the total number of requests handled.
+		Requests int64 `json:"requests"`
+		// Errors is the total number of responses with a 5xx status
+		// code.
+		Errors int64 `json:"errors"`
+		// HandleTime is the cumulated time spent handling requests.
+		HandleTime time.Duration `json:"handle_time"`
+	}
+
+	// canaryArm records the metrics of one of the handlers of a Canary.
+	canaryArm struct {
+		requests    atomic.Int64
+		errors      atomic.Int64
+		handleNanos atomic.Int64
+	}
+)
+
+// NewCanary returns a handler that splits the traffic between the stable and
+// canary handlers according to the given options. All the requests are routed
+// to the stable handler if no option is given.
+//
+// Example:
+//
+//	v1, v2 := goahttp.NewMuxer(), goahttp.NewMuxer()
+//	ordersv1svr.Mount(v1, ordersv1Server)
+//	ordersv2svr.Mount(v2, ordersv2Server)
+//	canary := httpmdlwr.NewCanary(v1, v2,
+//	    httpmdlwr.CanaryHeader("X-Canary", "always"),
+//	    httpmdlwr.CanaryWeight(5))
+//	canary.Publish("canary")
+//	srv := &http.Server{Addr: ":8080", Handler: canary}
+func NewCanary(stable, canary http.Handler, opts ...CanaryOption) *Canary {
+	c := &Canary{stable: stable, canary: canary, sampler: middleware.NewFixedSampler(0)}
+	for _, opt := range opts {
+		opt(c)
+	}
+	return c
+}
+
+// CanaryHeader routes the requests whose header with the given name has the
+// given value to the canary handler.
+func CanaryHeader(name, value string) CanaryOption {
+	return func(c *Canary) {
+		c.header = name
+		c.hvalue = value
+	}
+}
+
+// CanaryCookie routes the requests whose cookie with the given name has the
+// given value to the canary handler.
+func CanaryCookie(name, value string) CanaryOption {
+	return func(c *Canary) {
+		c.cookie = name
+		c.cvalue = value
+	}
+}
+
+// CanaryWeight routes the given percentage of the requests that match neither
+// the header nor the cookie to the canary handler.
+func CanaryWeight(percent int) CanaryOption {
+	return func(c *Canary) {
+		c.sampler = middleware.NewFixedSampler(percent)
+	}
+}
+
+// ServeHTTP routes the request to the stable or canary handler and records
+// the metrics of the arm that handled it.
+func (c *Canary) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	h, arm := c.stable, &c.arms[0]
+	if c.isCanary(r) {
+		h, arm = c.canary, &c.arms[1]
+	}
+	var (
+		start = time.Now()
+		rw    = CaptureResponse(w)
+	)
+	defer func() {
+		arm.requests.Add(1)
+		arm.handleNanos.Add(int64(time.Since(start)))
+		if rw.StatusCode >= 500 {
+			arm.errors.Add(1)
+		}
+	}()
+	h.ServeHTTP(rw, r)
+}
+
+// Snapshot returns the current values of the metrics.
+func (c *Canary) Snapshot() *CanarySnapshot {
+	return &CanarySnapshot{Stable: c.arms[0].snapshot(), Canary: c.arms[1].snapshot()}
+}
+
+// Publish publishes the metrics snapshot with expvar under the given name so
+// that it is served by the expvar handler (/debug/vars). Publish panics if
+// the name is already registered.
+func (c *Canary) Publish(name string) {
+	expvar.Publish(name, expvar.Func(func() any { return c.Snapshot() }))
+}
+
+// isCanary returns true if r must be routed to the canary handler.
+func (c *Canary) isCanary(r *http.Request) bool {
+	if c.header != "" && r.Header.Get(c.header) == c.hvalue {
+		return true
+	}
+	if c.cookie != "" {
+		if ck, err := r.Cookie(c.cookie); err == nil && ck.Value == c.cvalue {
+			return true
+		}
+	}
+	return c.sampler.Sample()
+}
+
+// snapshot returns the current values of the arm metrics.
+func (a *canaryArm) snapshot() *CanaryArmSnapshot {
+	return &CanaryArmSnapshot{
+		Requests:   a.requests.Load(),
+		Errors:     a.errors.Load(),
+		HandleTime: time.Duration(a.handleNanos.Load()),
+	}
+}
@@ -0,0 +1,58 @@
+package middleware_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	httpm "goa.design/goa/v3/http/middleware"
+)
+
+func TestCanary(t *testing.T) {
+	stable := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) { w.Write([]byte("v1")) }) // nolint: errcheck
+	canary := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+		w.Write([]byte("v2")) // nolint: errcheck
+	})
+	cases := map[string]struct {
+		opts     []httpm.CanaryOption
+		header   string
+		cookie   string
+		expected string
+	}{
+		"default":         {nil, "", "", "v1"},
+		"header":          {[]httpm.CanaryOption{httpm.CanaryHeader("X-Canary", "yes")}, "yes", "", "v2"},
+		"header-mismatch": {[]httpm.CanaryOption{httpm.CanaryHeader("X-Canary", "yes")}, "no", "", "v1"},
+		"cookie":          {[]httpm.CanaryOption{httpm.CanaryCookie("canary", "1")}, "", "1", "v2"},
+		"weight-all":      {[]httpm.CanaryOption{httpm.CanaryWeight(100)}, "", "", "v2"},
+		"weight-none":     {[]httpm.CanaryOption{httpm.CanaryWeight(0)}, "", "", "v1"},
+	}
+	for name, c := range cases {
+		t.Run(name, func(t *testing.T) {
+			h := httpm.NewCanary(stable, canary, c.opts...)
+			req := httptest.NewRequest("GET", "/", nil)
+			if c.header != "" {
+				req.Header.Set("X-Canary", c.header)
+			}
+			if c.cookie != "" {
+				req.AddCookie(&http.Cookie{Name: "canary", Value: c.cookie})
+			}
+			w := httptest.NewRecorder()
+			h.ServeHTTP(w, req)
+			if w.Body.String() != c.expected {
+				t.Errorf("got %q, expected %q", w.Body.String(), c.expected)
+			}
+			s := h.Snapshot()
+			arm, other := s.Stable, s.Canary
+			if c.expected == "v2" {
+				arm, other = s.Canary, s.Stable
+			}
+			if arm.Requests != 1 || other.Requests != 0 {
+				t.Errorf("got %d and %d requests, expected 1 and 0", arm.Requests, other.Requests)
+			}
+			if c.expected == "v2" && arm.Errors != 1 {
+				t.Errorf("got %d errors, expected 1", arm.Errors)
+			}
+		})
+	}
+}
@@ -59,3 +59,9 @@ func (w *ResponseCapture) Hijack() (net.Conn, *bufio.ReadWriter, error) {
 	}
 	return nil, nil, fmt.Errorf("response writer does not support hijacking: %T", w.ResponseWriter)
 }
+
+// Unwrap returns the underlying response writer so that http.ResponseController
+// may reach it.
+func (w *ResponseCapture) Unwrap() http.ResponseWriter {
+	return w.ResponseWriter
+}
@@ -0,0 +1,150 @@
+package middleware
+
+import (
+	"bytes"
+	"net/http"
+	"strings"
+	"sync"
+)
+
+type (
+	// coalescer tracks the requests being served by the Coalesce
+	// middleware.
+	coalescer struct {
+		vary []string
+
+		mu    sync.Mutex
+		calls map[string]*coalescedCall
+	}
+
+	// coalescedCall is a request being served on behalf of all the
+	// identical concurrent requests.
+	coalescedCall struct {
+		done chan struct{}
+		resp *sharedResponse
+	}
+
+	// sharedResponse is a http.ResponseWriter that buffers the response
+	// so that it can be written to multiple clients.
+	sharedResponse struct {
+		header http.Header
+		status int
+		body   bytes.Buffer
+	}
+)
+
+// Coalesce returns a middleware that coalesces the concurrent identical GET and
+// HEAD requests into a single execution of the handler and writes the shared
+// response to all the clients, protecting hot endpoints from thundering herds.
+// Requests are identical if they have the same method, host, path, query
+// string (regardless of the order of the parameters) and values for the
+// headers listed in vary. The Authorization and Cookie headers are always
+// taken into account so that responses are never shared between different
+// users. The handler is called with a context that carries the values of the
+// context of the first request but is not canceled when that request is so
+// that the other requests still receive the response. The response of the
+// handler is buffered in full before being written so the middleware should
+// not be used with endpoints that stream their responses.
+//
+// Example:
+//
+//	srv.UseMethod("list", httpmdlwr.Coalesce("Accept", "Accept-Language"))
+func Coalesce(vary ...string) func(http.Handler) http.Handler {
+	c := &coalescer{
+		vary:  append([]string{"Authorization", "Cookie"}, vary...),
+		calls: make(map[string]*coalescedCall),
+	}
+	return func(h http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.Method != http.MethodGet && r.Method != http.MethodHead {
+				h.ServeHTTP(w, r)
+				return
+			}
+			c.serve(w, r, h)
+		})
+	}
+}
+
+// serve writes the response of the call made on behalf of the requests
+// identical to r, making the call if there is none in flight.
+func (c *coalescer) serve(w http.ResponseWriter, r *http.Request, h http.Handler) {
+	key := c.key(r)
+	c.mu.Lock()
+	if call, ok := c.calls[key]; ok {
+		c.mu.Unlock()
+		select {
+		case <-call.done:
+			call.resp.writeTo(w)
+		case <-r.Context().Done():
+		}
+		return
+	}
+	call := &coalescedCall{done: make(chan struct{}), resp: &sharedResponse{header: make(http.Header)}}
+	c.calls[key] = call
+	c.mu.Unlock()
+
+	defer func() {
+		c.mu.Lock()
+		delete(c.calls, key)
+		c.mu.Unlock()
+		if p := recover(); p != nil {
+			call.resp = &sharedResponse{header: make(http.Header), status: http.StatusInternalServerError}
+			close(call.done)
+			panic(p)
+		}
+		close(call.done)
+	}()
+	h.ServeHTTP(call.resp, r.WithContext(detachedContext{r.Context()}))
+	call.resp.writeTo(w)
+}
+
+// key computes the key that identifies the requests identical to r.
+func (c *coalescer) key(r *http.Request) string {
+	var b strings.Builder
+	b.WriteString(r.Method)
+	b.WriteByte(' ')
+	b.WriteString(r.Host)
+	b.WriteString(r.URL.Path)
+	if q := r.URL.Query(); len(q) > 0 {
+		b.WriteByte('?')
+		b.WriteString(q.Encode()) // Encode sorts the parameters by name
+	}
+	for _, name := range c.vary {
+		b.WriteByte('\n')
+		b.WriteString(name)
+		b.WriteByte(':')
+		b.WriteString(strings.Join(r.Header.Values(name), ","))
+	}
+	return b.String()
+}
+
+// Header implements http.ResponseWriter.
+func (b *sharedResponse) Header() http.Header { return b.header }
+
+// WriteHeader implements http.ResponseWriter.
+func (b *sharedResponse) WriteHeader(status int) {
+	if b.status == 0 {
+		b.status = status
+	}
+}
+
+// Write implements http.ResponseWriter.
+func (b *sharedResponse) Write(p []byte) (int, error) {
+	if b.status == 0 {
+		b.status = http.StatusOK
+	}
+	return b.body.Write(p)
+}
+
+// writeTo writes the buffered response to w.
+func (b *sharedResponse) writeTo(w http.ResponseWriter) {
+	for k, v := range b.header {
+		w.Header()[k] = append([]string(nil), v...)
+	}
+	status := b.status
+	if status == 0 {
+		status = http.StatusOK
+	}
+	w.WriteHeader(status)
+	w.Write(b.body.Bytes()) // nolint: errcheck
+}
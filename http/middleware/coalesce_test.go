@@ -0,0 +1,156 @@
+package middleware_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	httpm "goa.design/goa/v3/http/middleware"
+)
+
+func TestCoalesce(t *testing.T) {
+	var (
+		calls   atomic.Int32
+		started = make(chan struct{}, 10)
+		release = make(chan struct{})
+	)
+	h := httpm.Coalesce("Accept")(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls.Add(1)
+		started <- struct{}{}
+		<-release
+		w.Header().Set("Content-Type", "text/plain")
+		w.WriteHeader(http.StatusAccepted)
+		w.Write([]byte("shared " + r.Header.Get("Accept"))) // nolint: errcheck
+	}))
+	do := func(url, accept string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest("GET", url, nil)
+		req.Header.Set("Accept", accept)
+		w := httptest.NewRecorder()
+		h.ServeHTTP(w, req)
+		return w
+	}
+
+	var (
+		wg      sync.WaitGroup
+		results = make([]*httptest.ResponseRecorder, 4)
+	)
+	wg.Add(1)
+	go func() { defer wg.Done(); results[0] = do("/items?a=1&b=2", "text/plain") }()
+	<-started
+	wg.Add(3)
+	go func() { defer wg.Done(); results[1] = do("/items?b=2&a=1", "text/plain") }()
+	go func() { defer wg.Done(); results[2] = do("/items?a=1&b=2", "text/plain") }()
+	go func() { defer wg.Done(); results[3] = do("/items?a=1&b=2", "text/html") }()
+	<-started                         // different Accept header
+	time.Sleep(50 * time.Millisecond) // let the identical requests wait for the first one
+	close(release)
+	wg.Wait()
+
+	if n := calls.Load(); n != 2 {
+		t.Errorf("got %d calls, expected 2", n)
+	}
+	for i, w := range results[:3] {
+		if w.Code != http.StatusAccepted || w.Body.String() != "shared text/plain" || w.Header().Get("Content-Type") != "text/plain" {
+			t.Errorf("response %d: got %d %q %v", i, w.Code, w.Body.String(), w.Header())
+		}
+	}
+	if w := results[3]; w.Body.String() != "shared text/html" {
+		t.Errorf("got %q, expected %q", w.Body.String(), "shared text/html")
+	}
+}
+
+func TestCoalesceLeaderCanceled(t *testing.T) {
+	var (
+		started = make(chan struct{})
+		release = make(chan struct{})
+	)
+	h := httpm.Coalesce()(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		close(started)
+		<-release
+		if err := r.Context().Err(); err != nil {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		w.Write([]byte("shared")) // nolint: errcheck
+	}))
+	ctx, cancel := context.WithCancel(context.Background())
+	go h.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("GET", "/items", nil).WithContext(ctx))
+	<-started
+	done := make(chan *httptest.ResponseRecorder)
+	go func() {
+		w := httptest.NewRecorder()
+		h.ServeHTTP(w, httptest.NewRequest("GET", "/items", nil))
+		done <- w
+	}()
+	time.Sleep(50 * time.Millisecond) // let the follower wait for the leader
+	cancel()
+	close(release)
+	if w := <-done; w.Code != http.StatusOK || w.Body.String() != "shared" {
+		t.Errorf("got %d %q, expected 200 \"shared\"", w.Code, w.Body.String())
+	}
+}
+
+func TestCoalesceUnsafeMethods(t *testing.T) {
+	var calls atomic.Int32
+	h := httpm.Coalesce()(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls.Add(1)
+		time.Sleep(10 * time.Millisecond)
+	}))
+	var wg sync.WaitGroup
+	for i := 0; i < 3; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			h.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("POST", "/items", nil))
+		}()
+	}
+	wg.Wait()
+	if n := calls.Load(); n != 3 {
+		t.Errorf("got %d calls, expected 3", n)
+	}
+}
+
+func TestCoalesceHosts(t *testing.T) {
+	var (
+		calls   atomic.Int32
+		started = make(chan struct{}, 2)
+		release = make(chan struct{})
+	)
+	h := httpm.Coalesce()(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls.Add(1)
+		started <- struct{}{}
+		<-release
+		w.Write([]byte(r.Host)) // nolint: errcheck
+	}))
+	var (
+		wg      sync.WaitGroup
+		results = make([]*httptest.ResponseRecorder, 2)
+	)
+	for i, host := range []string{"a.example.com", "b.example.com"} {
+		wg.Add(1)
+		go func(i int, host string) {
+			defer wg.Done()
+			req := httptest.NewRequest("GET", "/items", nil)
+			req.Host = host
+			results[i] = httptest.NewRecorder()
+			h.ServeHTTP(results[i], req)
+		}(i, host)
+	}
+	<-started
+	<-started // the requests to different hosts are not coalesced
+	close(release)
+	wg.Wait()
+
+	if n := calls.Load(); n != 2 {
+		t.Errorf("got %d calls, expected 2", n)
+	}
+	for i, host := range []string{"a.example.com", "b.example.com"} {
+		if got := results[i].Body.String(); got != host {
+			t.Errorf("response %d: got %q, expected %q", i, got, host)
+		}
+	}
+}
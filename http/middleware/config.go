@@ -0,0 +1,35 @@
+package middleware
+
+import (
+	"net/http"
+	"sync/atomic"
+
+	"goa.design/goa/v3/middleware"
+)
+
+// Reloadable returns a middleware that applies the middleware built by build
+// from the current settings of cfg. The middleware is built again each time the
+// settings change so that middlewares configured with static parameters (e.g.
+// a rate limiter, a logger with a given level or a CORS handler with a list of
+// allowed origins) can be reconfigured without restarting the server. Requests
+// in flight complete with the middleware that was current when they started.
+//
+// Example:
+//
+//	cfg := middleware.NewConfig(&Settings{LogLevel: "info"})
+//	handler = httpmdlwr.Reloadable(cfg, func(s *Settings) func(http.Handler) http.Handler {
+//		return httpmdlwr.Log(newLogger(s.LogLevel))
+//	})(handler)
+//	go cfg.ReloadOnSignal(ctx, middleware.LoadJSONFile[Settings]("settings.json"), nil)
+func Reloadable[T any](cfg *middleware.Config[T], build func(*T) func(http.Handler) http.Handler) func(http.Handler) http.Handler {
+	return func(h http.Handler) http.Handler {
+		var current atomic.Pointer[http.Handler]
+		cfg.OnChange(func(v *T) {
+			handler := build(v)(h)
+			current.Store(&handler)
+		})
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			(*current.Load()).ServeHTTP(w, r)
+		})
+	}
+}
@@ -0,0 +1,33 @@
+package middleware_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	httpm "goa.design/goa/v3/http/middleware"
+	"goa.design/goa/v3/middleware"
+)
+
+func TestReloadable(t *testing.T) {
+	cfg := middleware.NewConfig(&struct{ Origin string }{"a.com"})
+	build := func(s *struct{ Origin string }) func(http.Handler) http.Handler {
+		return func(h http.Handler) http.Handler {
+			return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.Header().Set("Access-Control-Allow-Origin", s.Origin)
+				h.ServeHTTP(w, r)
+			})
+		}
+	}
+	h := httpm.Reloadable(cfg, build)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	for _, origin := range []string{"a.com", "b.com"} {
+		if origin != "a.com" {
+			cfg.Store(&struct{ Origin string }{origin})
+		}
+		w := httptest.NewRecorder()
+		h.ServeHTTP(w, httptest.NewRequest("GET", "/", nil))
+		if got := w.Header().Get("Access-Control-Allow-Origin"); got != origin {
+			t.Errorf("got origin %q, expected %q", got, origin)
+		}
+	}
+}
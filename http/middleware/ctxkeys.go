@@ -65,4 +65,9 @@ const (
 	// RequestXCSRFTokenKey is the request context key used to store X-Csrf-Token header
 	// created by the PopulateRequestContext middleware.
 	RequestXCSRFTokenKey
+
+	// WebhookVerifiedKey is the request context key used to store whether
+	// the signature of a webhook request was verified by the VerifyWebhook
+	// middleware.
+	WebhookVerifiedKey
 )
@@ -0,0 +1,113 @@
+package middleware
+
+import (
+	"bufio"
+	"fmt"
+	"net"
+	"net/http"
+	"time"
+)
+
+// streamWriter is the response writer used by StreamWriteTimeout to extend
+// the write deadline of the connection as the response is written.
+type streamWriter struct {
+	http.ResponseWriter
+	rc      *http.ResponseController
+	timeout time.Duration
+}
+
+// WriteTimeout returns a middleware that overrides the write timeout of the
+// server for the requests handled by the wrapped handler: the response must
+// be written within d. A zero d disables the write deadline, which is needed
+// by the handlers that upgrade the connection to a websocket as the deadline
+// otherwise still applies to the hijacked connection. The deadline is left
+// unchanged if the response writer does not support deadlines.
+//
+// Example:
+//
+//	srv.UseMethod("export", middleware.WriteTimeout(5*time.Minute))
+func WriteTimeout(d time.Duration) func(http.Handler) http.Handler {
+	return func(h http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			http.NewResponseController(w).SetWriteDeadline(deadline(d)) // nolint: errcheck
+			h.ServeHTTP(w, r)
+		})
+	}
+}
+
+// ReadTimeout returns a middleware that overrides the read timeout of the
+// server for the requests handled by the wrapped handler: the request body
+// must be read within d. A zero d disables the read deadline so that the
+// streaming endpoints may read the request body for as long as the client
+// sends it.
+//
+// Example:
+//
+//	srv.UseMethod("upload", middleware.ReadTimeout(0))
+func ReadTimeout(d time.Duration) func(http.Handler) http.Handler {
+	return func(h http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			http.NewResponseController(w).SetReadDeadline(deadline(d)) // nolint: errcheck
+			h.ServeHTTP(w, r)
+		})
+	}
+}
+
+// StreamWriteTimeout returns a middleware that replaces the write timeout of
+// the server with an idle timeout for the requests handled by the wrapped
+// handler: the write deadline is pushed back by d each time the handler writes
+// or flushes the response. This keeps the connections of the long-lived
+// streams (e.g. server-sent events) that make progress open while still
+// closing the connections of the clients that stop reading.
+//
+// Example:
+//
+//	srv.UseMethod("subscribe", middleware.StreamWriteTimeout(30*time.Second))
+func StreamWriteTimeout(d time.Duration) func(http.Handler) http.Handler {
+	return func(h http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			sw := &streamWriter{ResponseWriter: w, rc: http.NewResponseController(w), timeout: d}
+			sw.extend()
+			h.ServeHTTP(sw, r)
+		})
+	}
+}
+
+// deadline returns the deadline corresponding to the timeout d, the zero time
+// if d is zero.
+func deadline(d time.Duration) time.Time {
+	if d <= 0 {
+		return time.Time{}
+	}
+	return time.Now().Add(d)
+}
+
+// extend pushes back the write deadline of the connection.
+func (w *streamWriter) extend() {
+	w.rc.SetWriteDeadline(deadline(w.timeout)) // nolint: errcheck
+}
+
+// Write extends the write deadline and writes b.
+func (w *streamWriter) Write(b []byte) (int, error) {
+	w.extend()
+	return w.ResponseWriter.Write(b)
+}
+
+// Flush extends the write deadline and flushes the response.
+func (w *streamWriter) Flush() {
+	w.extend()
+	w.rc.Flush() // nolint: errcheck
+}
+
+// Hijack supports the http.Hijacker interface.
+func (w *streamWriter) Hijack() (net.Conn, *bufio.ReadWriter, error) {
+	if h, ok := w.ResponseWriter.(http.Hijacker); ok {
+		return h.Hijack()
+	}
+	return nil, nil, fmt.Errorf("response writer does not support hijacking: %T", w.ResponseWriter)
+}
+
+// Unwrap returns the underlying response writer.
+func (w *streamWriter) Unwrap() http.ResponseWriter {
+	return w.ResponseWriter
+}
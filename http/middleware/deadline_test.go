@@ -0,0 +1,77 @@
+package middleware_test
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	httpm "goa.design/goa/v3/http/middleware"
+)
+
+func TestDeadline(t *testing.T) {
+	stream := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		for i := 0; i < 4; i++ {
+			w.Write([]byte("data\n")) // nolint: errcheck
+			w.(http.Flusher).Flush()
+			time.Sleep(50 * time.Millisecond)
+		}
+	})
+	cases := []struct {
+		Name       string
+		Middleware func(http.Handler) http.Handler
+		Complete   bool
+	}{
+		{"server-timeout", func(h http.Handler) http.Handler { return h }, false},
+		{"write-timeout", httpm.WriteTimeout(time.Second), true},
+		{"no-write-timeout", httpm.WriteTimeout(0), true},
+		{"stream-write-timeout", httpm.StreamWriteTimeout(100 * time.Millisecond), true},
+	}
+	for _, c := range cases {
+		t.Run(c.Name, func(t *testing.T) {
+			srv := httptest.NewUnstartedServer(c.Middleware(stream))
+			srv.Config.WriteTimeout = 75 * time.Millisecond
+			srv.Start()
+			defer srv.Close()
+			resp, err := http.Get(srv.URL)
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer resp.Body.Close()
+			body, err := io.ReadAll(resp.Body)
+			complete := err == nil && len(body) == 20
+			if complete != c.Complete {
+				t.Errorf("got complete response %v (%d bytes, error %v), expected %v", complete, len(body), err, c.Complete)
+			}
+		})
+	}
+}
+
+func TestReadTimeout(t *testing.T) {
+	h := httpm.ReadTimeout(0)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if _, err := io.ReadAll(r.Body); err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+		}
+	}))
+	srv := httptest.NewUnstartedServer(h)
+	srv.Config.ReadTimeout = 50 * time.Millisecond
+	srv.Start()
+	defer srv.Close()
+	pr, pw := io.Pipe()
+	go func() {
+		for i := 0; i < 3; i++ {
+			time.Sleep(40 * time.Millisecond)
+			pw.Write([]byte("data")) // nolint: errcheck
+		}
+		pw.Close()
+	}()
+	resp, err := http.Post(srv.URL, "text/plain", pr)
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		t.Errorf("got status %d, expected %d", resp.StatusCode, http.StatusOK)
+	}
+}
@@ -0,0 +1,26 @@
+package middleware
+
+import (
+	"net/http"
+
+	goa "goa.design/goa/v3/pkg"
+)
+
+// DeferBody returns a middleware that defers the decoding of the request
+// bodies of the methods with security requirements until the generated
+// endpoints authenticate the requests, see goa.WithDeferredBody. Requests
+// rejected by the authentication functions are thus never decoded. Endpoint
+// middlewares and interceptors that read the payload body fields must call
+// goa.DecodeBody first, the methods with server interceptors always decode
+// the body right away.
+//
+// Example:
+//
+//	handler = middleware.DeferBody()(handler)
+func DeferBody() func(http.Handler) http.Handler {
+	return func(h http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			h.ServeHTTP(w, r.WithContext(goa.WithDeferredBody(r.Context())))
+		})
+	}
+}
@@ -0,0 +1,21 @@
+package middleware_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	httpm "goa.design/goa/v3/http/middleware"
+	goa "goa.design/goa/v3/pkg"
+)
+
+func TestDeferBody(t *testing.T) {
+	var deferred bool
+	h := httpm.DeferBody()(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		deferred = goa.DeferBody(r.Context(), func() error { return nil })
+	}))
+	h.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("POST", "/", nil))
+	if !deferred {
+		t.Error("expected the request body decoding to be deferred")
+	}
+}
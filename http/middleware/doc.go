@@ -8,6 +8,8 @@ The package contains the following middlewares:
     a HTTP request.
   * Tracing middleware for server and client.
   * AWS X-Ray middleware for server and client that produce X-Ray segments.
+  * Timeout server middleware that sets the request context deadline from
+    the X-Request-Timeout or Grpc-Timeout header.
 
 Example to use the server middleware:
 
@@ -0,0 +1,246 @@
+package middleware
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	goahttp "goa.design/goa/v3/http"
+	"goa.design/goa/v3/middleware"
+	goa "goa.design/goa/v3/pkg"
+)
+
+type (
+	// dumpRecord collects the information printed by the Dump middleware
+	// that is only available deeper in the handler chain.
+	dumpRecord struct {
+		mu         sync.Mutex
+		timings    []*dumpTiming
+		hasPayload bool
+		payload    any
+		result     any
+		err        error
+	}
+
+	// dumpTiming is the time spent in a middleware wrapped with DumpTimed.
+	dumpTiming struct {
+		name string
+		d    time.Duration
+	}
+
+	// dumpKey is the context key used to store the dump record.
+	dumpKey struct{}
+)
+
+// ANSI escape sequences used to color the dumps.
+const (
+	dumpRequestColor  = "\033[36m"
+	dumpResponseColor = "\033[32m"
+	dumpErrorColor    = "\033[31m"
+	dumpDimColor      = "\033[90m"
+	dumpResetColor    = "\033[0m"
+)
+
+// Dump returns a development middleware that prints the full request and
+// response - headers, path parameters and bodies - in a readable colored
+// format to w once the request has been served. Only the requests that set the
+// given header to a non-empty value are dumped, all the requests are dumped if
+// header is empty. The dump also includes the decoded payload and the result
+// of the endpoint if the endpoints are wrapped with DumpPayloads and the time
+// spent in each middleware wrapped with DumpTimed. Colors are disabled when
+// the NO_COLOR environment variable is set. The middleware must be applied to
+// the muxer with Use so that the path parameters can be retrieved.
+//
+// The bodies are buffered in memory and the payloads are printed after being
+// redacted with goa.Redact: the middleware is not meant for production use.
+//
+// Example:
+//
+//	mux.Use(httpmdlwr.Dump(mux, os.Stderr, "X-Goa-Debug"))
+//	mux.Use(httpmdlwr.DumpTimed("log", httpmdlwr.Log(logger)))
+//	endpoints.Use(httpmdlwr.DumpPayloads())
+func Dump(mux goahttp.Muxer, w io.Writer, header string) func(http.Handler) http.Handler {
+	color := os.Getenv("NO_COLOR") == ""
+	return func(h http.Handler) http.Handler {
+		return http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+			if header != "" && r.Header.Get(header) == "" {
+				h.ServeHTTP(rw, r)
+				return
+			}
+			reqBody, err := io.ReadAll(r.Body)
+			if err != nil {
+				reqBody = []byte("failed to read body: " + err.Error())
+			}
+			r.Body = io.NopCloser(bytes.NewReader(reqBody))
+			rec := &dumpRecord{}
+			r = r.WithContext(context.WithValue(r.Context(), dumpKey{}, rec))
+			dupper := &responseDupper{ResponseWriter: rw, Buffer: &bytes.Buffer{}}
+			start := time.Now()
+			h.ServeHTTP(dupper, r)
+			elapsed := time.Since(start)
+
+			reqID := r.Context().Value(middleware.RequestIDKey)
+			if reqID == nil {
+				reqID = shortID()
+			}
+			d := &dumper{color: color}
+			d.line(dumpRequestColor, "> [%s] %s %s", reqID, r.Method, r.URL.RequestURI())
+			d.headers(dumpRequestColor, ">", r.Header)
+			params := mux.Vars(r)
+			for _, k := range sortedKeys(params) {
+				d.line(dumpRequestColor, "> {%s}: %s", k, params[k])
+			}
+			d.body(reqBody)
+			rec.mu.Lock()
+			defer rec.mu.Unlock()
+			if rec.hasPayload {
+				d.line(dumpDimColor, "  payload: %+v", goa.Redact(rec.payload))
+			}
+			status := dupper.Status
+			if status == 0 {
+				status = http.StatusOK
+			}
+			c := dumpResponseColor
+			if status >= 400 {
+				c = dumpErrorColor
+			}
+			d.line(c, "< %d %s (%s)", status, http.StatusText(status), elapsed)
+			d.headers(c, "<", dupper.Header())
+			d.body(dupper.Buffer.Bytes())
+			if rec.err != nil {
+				d.line(dumpErrorColor, "  error: %v", goa.Redact(rec.err))
+			} else if rec.hasPayload {
+				d.line(dumpDimColor, "  result: %+v", goa.Redact(rec.result))
+			}
+			if len(rec.timings) > 0 {
+				handler := elapsed
+				for _, t := range rec.timings {
+					d.line(dumpDimColor, "  %-12s %s", t.name, t.d)
+					handler -= t.d
+				}
+				d.line(dumpDimColor, "  %-12s %s", "handler", handler)
+			}
+			w.Write(d.buf.Bytes()) // nolint: errcheck
+		})
+	}
+}
+
+// DumpTimed wraps the given middleware so that the time spent in it, excluding
+// the time spent in the handlers it wraps, is printed by the Dump middleware.
+// DumpTimed must be applied to middlewares that wrap the Dump middleware's
+// handler, that is that run after it.
+func DumpTimed(name string, m func(http.Handler) http.Handler) func(http.Handler) http.Handler {
+	return func(h http.Handler) http.Handler {
+		var (
+			// inner measures the time spent in the handlers wrapped by
+			// the middleware.
+			inner = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				start := time.Now()
+				h.ServeHTTP(w, r)
+				if rec, ok := r.Context().Value(dumpKey{}).(*dumpRecord); ok {
+					rec.mu.Lock()
+					rec.timings = append(rec.timings, &dumpTiming{name: "-" + name, d: time.Since(start)})
+					rec.mu.Unlock()
+				}
+			})
+			wrapped = m(inner)
+		)
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			rec, ok := r.Context().Value(dumpKey{}).(*dumpRecord)
+			if !ok {
+				wrapped.ServeHTTP(w, r)
+				return
+			}
+			start := time.Now()
+			wrapped.ServeHTTP(w, r)
+			total := time.Since(start)
+			rec.mu.Lock()
+			defer rec.mu.Unlock()
+			// Subtract the time recorded by inner if it was called.
+			for i, t := range rec.timings {
+				if t.name == "-"+name {
+					total -= t.d
+					rec.timings = append(rec.timings[:i], rec.timings[i+1:]...)
+					break
+				}
+			}
+			rec.timings = append(rec.timings, &dumpTiming{name: name, d: total})
+		})
+	}
+}
+
+// DumpPayloads returns an endpoint middleware that records the decoded
+// payload and the result or error of the endpoint it wraps so that they are
+// printed by the Dump middleware.
+func DumpPayloads() func(goa.Endpoint) goa.Endpoint {
+	return func(e goa.Endpoint) goa.Endpoint {
+		return func(ctx context.Context, req any) (any, error) {
+			res, err := e(ctx, req)
+			if rec, ok := ctx.Value(dumpKey{}).(*dumpRecord); ok {
+				rec.mu.Lock()
+				rec.hasPayload, rec.payload, rec.result, rec.err = true, req, res, err
+				rec.mu.Unlock()
+			}
+			return res, err
+		}
+	}
+}
+
+// dumper formats the dump of a request.
+type dumper struct {
+	buf   bytes.Buffer
+	color bool
+}
+
+// line writes a line with the given color.
+func (d *dumper) line(color, format string, args ...any) {
+	if d.color {
+		d.buf.WriteString(color)
+	}
+	fmt.Fprintf(&d.buf, format, args...)
+	if d.color {
+		d.buf.WriteString(dumpResetColor)
+	}
+	d.buf.WriteByte('\n')
+}
+
+// headers writes the headers sorted by name.
+func (d *dumper) headers(color, prefix string, h http.Header) {
+	keys := make([]string, 0, len(h))
+	for k := range h {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		d.line(color, "%s %s: %s", prefix, k, strings.Join(h[k], ", "))
+	}
+}
+
+// body writes the body indented.
+func (d *dumper) body(b []byte) {
+	if len(b) == 0 {
+		return
+	}
+	for _, l := range strings.Split(strings.TrimRight(string(b), "\n"), "\n") {
+		d.buf.WriteString("  ")
+		d.buf.WriteString(l)
+		d.buf.WriteByte('\n')
+	}
+}
+
+// sortedKeys returns the keys of m sorted.
+func sortedKeys(m map[string]string) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
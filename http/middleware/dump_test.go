@@ -0,0 +1,70 @@
+package middleware_test
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	goahttp "goa.design/goa/v3/http"
+	httpm "goa.design/goa/v3/http/middleware"
+)
+
+func TestDump(t *testing.T) {
+	t.Setenv("NO_COLOR", "1")
+	var buf bytes.Buffer
+	mux := goahttp.NewMuxer()
+	mux.Use(httpm.Dump(mux, &buf, "X-Debug"))
+	mux.Use(httpm.DumpTimed("slow", func(h http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			time.Sleep(10 * time.Millisecond)
+			h.ServeHTTP(w, r)
+		})
+	}))
+	endpoint := httpm.DumpPayloads()(func(ctx context.Context, req any) (any, error) {
+		return "result " + req.(string), nil
+	})
+	mux.Handle("POST", "/items/{id}", func(w http.ResponseWriter, r *http.Request) {
+		b, _ := io.ReadAll(r.Body)
+		res, _ := endpoint(r.Context(), string(b))
+		w.Header().Set("Content-Type", "text/plain")
+		w.WriteHeader(http.StatusCreated)
+		w.Write([]byte(res.(string))) // nolint: errcheck
+	})
+
+	req := httptest.NewRequest("POST", "/items/42", strings.NewReader("payload"))
+	mux.ServeHTTP(httptest.NewRecorder(), req)
+	if buf.Len() != 0 {
+		t.Fatalf("got dump for request without debug header:\n%s", buf.String())
+	}
+
+	req = httptest.NewRequest("POST", "/items/42", strings.NewReader("payload"))
+	req.Header.Set("X-Debug", "1")
+	w := httptest.NewRecorder()
+	mux.ServeHTTP(w, req)
+	if w.Code != http.StatusCreated || w.Body.String() != "result payload" {
+		t.Errorf("got response %d %q", w.Code, w.Body.String())
+	}
+	dump := buf.String()
+	for _, expected := range []string{
+		"POST /items/42\n",
+		"> X-Debug: 1\n",
+		"> {id}: 42\n",
+		"  payload\n",
+		"  payload: payload\n",
+		"< 201 Created (",
+		"< Content-Type: text/plain\n",
+		"  result payload\n",
+		"  result: result payload\n",
+		"  slow         1",
+		"  handler      ",
+	} {
+		if !strings.Contains(dump, expected) {
+			t.Errorf("dump does not contain %q:\n%s", expected, dump)
+		}
+	}
+}
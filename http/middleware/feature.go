@@ -0,0 +1,30 @@
+package middleware
+
+import (
+	"net/http"
+
+	"goa.design/goa/v3/middleware"
+)
+
+// FeatureFlag returns a middleware that responds with the given status code
+// (typically 404 Not Found so that the endpoint stays hidden or 403 Forbidden)
+// to the requests made while the given feature flag is disabled. Errors
+// returned by the provider are handled as disabled flags so that dark
+// endpoints are never exposed by accident. The generated servers of services
+// whose methods define the "feature:flag" meta apply the middleware to the
+// gated methods in UseFeatureFlags.
+//
+// Example:
+//
+//	srv.UseMethod("checkout", httpmdlwr.FeatureFlag(middleware.EnvFlags("feature_"), "new-checkout", http.StatusNotFound))
+func FeatureFlag(p middleware.FlagProvider, flag string, status int) func(http.Handler) http.Handler {
+	return func(h http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if enabled, err := p.Enabled(r.Context(), flag); err != nil || !enabled {
+				http.Error(w, http.StatusText(status), status)
+				return
+			}
+			h.ServeHTTP(w, r)
+		})
+	}
+}
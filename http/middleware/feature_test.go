@@ -0,0 +1,45 @@
+package middleware_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	httpm "goa.design/goa/v3/http/middleware"
+	"goa.design/goa/v3/middleware"
+)
+
+func TestFeatureFlag(t *testing.T) {
+	cases := map[string]struct {
+		Enabled bool
+		Err     error
+		Status  int
+		// output
+		Expected int
+	}{
+		"enabled":   {true, nil, http.StatusNotFound, http.StatusOK},
+		"disabled":  {false, nil, http.StatusNotFound, http.StatusNotFound},
+		"forbidden": {false, nil, http.StatusForbidden, http.StatusForbidden},
+		"error":     {true, errors.New("unavailable"), http.StatusNotFound, http.StatusNotFound},
+	}
+	for k, c := range cases {
+		t.Run(k, func(t *testing.T) {
+			var flag string
+			p := middleware.FlagFunc(func(_ context.Context, f string) (bool, error) {
+				flag = f
+				return c.Enabled, c.Err
+			})
+			h := httpm.FeatureFlag(p, "beta", c.Status)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+			w := httptest.NewRecorder()
+			h.ServeHTTP(w, httptest.NewRequest("GET", "/", nil))
+			if w.Code != c.Expected {
+				t.Errorf("got status %d, expected %d", w.Code, c.Expected)
+			}
+			if flag != "beta" {
+				t.Errorf("got flag %q, expected beta", flag)
+			}
+		})
+	}
+}
@@ -0,0 +1,267 @@
+package middleware
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"io"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// IdempotencyKeyHeader is the name of the request header that holds the
+// idempotency key by default.
+const IdempotencyKeyHeader = "Idempotency-Key"
+
+type (
+	// IdempotencyStore records the responses of the requests made with an
+	// idempotency key. Implementations must be safe for concurrent use and
+	// Begin must reserve keys atomically across all the server instances
+	// that share the store.
+	IdempotencyStore interface {
+		// Begin reserves key for the request with the given
+		// fingerprint. It returns the response recorded for key if a
+		// request made with the same key already completed,
+		// ErrIdempotencyConflict if a request holding the key is in
+		// flight and ErrIdempotencyMismatch if the key was reserved by
+		// a request with a different fingerprint.
+		Begin(ctx context.Context, key, fingerprint string) (*IdempotentResponse, error)
+		// Complete records the response of the request that reserved
+		// key.
+		Complete(ctx context.Context, key string, resp *IdempotentResponse) error
+		// Release releases key without recording a response so that
+		// the request may be retried.
+		Release(ctx context.Context, key string) error
+	}
+
+	// IdempotencyOption customizes the Idempotency middleware.
+	IdempotencyOption func(*idempotencyOptions)
+
+	// idempotencyOptions lists the Idempotency middleware options.
+	idempotencyOptions struct {
+		scope func(r *http.Request) string
+	}
+
+	// IdempotentResponse is a response recorded by an IdempotencyStore.
+	IdempotentResponse struct {
+		// Status is the response status code.
+		Status int
+		// Header contains the response headers.
+		Header http.Header
+		// Body is the response body.
+		Body []byte
+	}
+
+	// MemoryIdempotencyStore is an IdempotencyStore that keeps the
+	// responses in memory. It is suitable for servers that run a single
+	// instance, use a shared store (e.g. backed by Redis) otherwise.
+	MemoryIdempotencyStore struct {
+		ttl time.Duration
+		now func() time.Time
+
+		mu      sync.Mutex
+		entries map[string]*idempotencyEntry
+	}
+
+	// idempotencyEntry is a key recorded by MemoryIdempotencyStore.
+	idempotencyEntry struct {
+		fingerprint string
+		resp        *IdempotentResponse
+		expires     time.Time
+	}
+
+	// idempotencyWriter records the response written by the handler.
+	idempotencyWriter struct {
+		*ResponseCapture
+		body bytes.Buffer
+	}
+)
+
+var (
+	// ErrIdempotencyConflict is returned by IdempotencyStore.Begin when a
+	// request made with the same key is in flight.
+	ErrIdempotencyConflict = errors.New("a request with the same idempotency key is in progress")
+	// ErrIdempotencyMismatch is returned by IdempotencyStore.Begin when the
+	// key was used by a different request.
+	ErrIdempotencyMismatch = errors.New("the idempotency key was used by a different request")
+)
+
+// Idempotency returns a middleware that makes the requests that use unsafe
+// HTTP methods (e.g. POST or PATCH) and set the idempotency key header safe to
+// retry. The first response to a request made with a given key is recorded in
+// store and replayed to the requests retried with the same key without calling
+// the handler again, the replayed responses have the "Idempotent-Replayed"
+// header set to "true". Requests made while a request with the same key is in
+// flight receive a 409 Conflict response and requests that reuse a key with a
+// different method, path or body receive a 422 Unprocessable Entity response.
+// Responses with a 5xx status code are not recorded so that the request may be
+// retried. Keys are scoped to the caller so that requests made by different
+// callers with the same key never share a response, the scope is the
+// Authorization header unless configured otherwise with WithIdempotencyScope.
+// header is the name of the idempotency key header, IdempotencyKeyHeader if
+// empty. The generated servers of services whose methods use the Idempotent
+// DSL apply the middleware to these methods in UseIdempotency.
+//
+// Example:
+//
+//	srv.UseMethod("create", httpmdlwr.Idempotency(httpmdlwr.NewMemoryIdempotencyStore(24*time.Hour), ""))
+func Idempotency(store IdempotencyStore, header string, opts ...IdempotencyOption) func(http.Handler) http.Handler {
+	if header == "" {
+		header = IdempotencyKeyHeader
+	}
+	o := &idempotencyOptions{scope: func(r *http.Request) string { return r.Header.Get("Authorization") }}
+	for _, opt := range opts {
+		opt(o)
+	}
+	return func(h http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			key := r.Header.Get(header)
+			if key == "" || isSafeMethod(r.Method) {
+				h.ServeHTTP(w, r)
+				return
+			}
+			body, err := io.ReadAll(r.Body)
+			if err != nil {
+				http.Error(w, http.StatusText(http.StatusBadRequest), http.StatusBadRequest)
+				return
+			}
+			r.Body = io.NopCloser(bytes.NewReader(body))
+			key = scopedKey(o.scope(r), key)
+			ctx := r.Context()
+			resp, err := store.Begin(ctx, key, fingerprint(r, body))
+			switch {
+			case errors.Is(err, ErrIdempotencyConflict):
+				http.Error(w, err.Error(), http.StatusConflict)
+				return
+			case errors.Is(err, ErrIdempotencyMismatch):
+				http.Error(w, err.Error(), http.StatusUnprocessableEntity)
+				return
+			case err != nil:
+				http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+				return
+			case resp != nil:
+				for k, v := range resp.Header {
+					w.Header()[k] = v
+				}
+				w.Header().Set("Idempotent-Replayed", "true")
+				w.WriteHeader(resp.Status)
+				w.Write(resp.Body) // nolint: errcheck
+				return
+			}
+
+			iw := &idempotencyWriter{ResponseCapture: CaptureResponse(w)}
+			completed := false
+			defer func() {
+				if !completed {
+					store.Release(context.Background(), key) // nolint: errcheck
+				}
+			}()
+			h.ServeHTTP(iw, r)
+			status := iw.StatusCode
+			if status == 0 {
+				status = http.StatusOK
+			}
+			if status >= 500 {
+				return
+			}
+			rec := &IdempotentResponse{Status: status, Header: w.Header().Clone(), Body: iw.body.Bytes()}
+			completed = store.Complete(context.Background(), key, rec) == nil
+		})
+	}
+}
+
+// WithIdempotencyScope sets the function that returns the identity of the
+// caller making the request, e.g. the authenticated user ID. Requests made with
+// the same idempotency key by callers with different scopes are processed
+// independently.
+func WithIdempotencyScope(scope func(r *http.Request) string) IdempotencyOption {
+	return func(o *idempotencyOptions) { o.scope = scope }
+}
+
+// NewMemoryIdempotencyStore returns an in-memory IdempotencyStore that keeps
+// the recorded responses for the given duration.
+func NewMemoryIdempotencyStore(ttl time.Duration) *MemoryIdempotencyStore {
+	return &MemoryIdempotencyStore{ttl: ttl, now: time.Now, entries: make(map[string]*idempotencyEntry)}
+}
+
+// Begin implements IdempotencyStore.
+func (s *MemoryIdempotencyStore) Begin(_ context.Context, key, fingerprint string) (*IdempotentResponse, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	now := s.now()
+	if e, ok := s.entries[key]; ok && now.Before(e.expires) {
+		switch {
+		case e.fingerprint != fingerprint:
+			return nil, ErrIdempotencyMismatch
+		case e.resp == nil:
+			return nil, ErrIdempotencyConflict
+		}
+		return e.resp, nil
+	}
+	for k, e := range s.entries {
+		if !now.Before(e.expires) {
+			delete(s.entries, k)
+		}
+	}
+	s.entries[key] = &idempotencyEntry{fingerprint: fingerprint, expires: now.Add(s.ttl)}
+	return nil, nil
+}
+
+// Complete implements IdempotencyStore.
+func (s *MemoryIdempotencyStore) Complete(_ context.Context, key string, resp *IdempotentResponse) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if e, ok := s.entries[key]; ok {
+		e.resp = resp
+		e.expires = s.now().Add(s.ttl)
+	}
+	return nil
+}
+
+// Release implements IdempotencyStore.
+func (s *MemoryIdempotencyStore) Release(_ context.Context, key string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if e, ok := s.entries[key]; ok && e.resp == nil {
+		delete(s.entries, key)
+	}
+	return nil
+}
+
+// Write records the body before writing it.
+func (w *idempotencyWriter) Write(b []byte) (int, error) {
+	w.body.Write(b)
+	return w.ResponseCapture.Write(b)
+}
+
+// isSafeMethod returns true if the given HTTP method is safe as defined by RFC
+// 9110 and thus need not be protected with an idempotency key.
+func isSafeMethod(method string) bool {
+	switch method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions, http.MethodTrace:
+		return true
+	}
+	return false
+}
+
+// fingerprint computes the fingerprint of the request used to detect the reuse
+// of idempotency keys.
+func fingerprint(r *http.Request, body []byte) string {
+	h := sha256.New()
+	io.WriteString(h, r.Method+" "+r.URL.RequestURI()+"\n") // nolint: errcheck
+	h.Write(body)
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// scopedKey returns the key recorded in the store for the given scope and
+// idempotency key. The scope is hashed so that credentials are not stored.
+func scopedKey(scope, key string) string {
+	if scope == "" {
+		return key
+	}
+	h := sha256.Sum256([]byte(scope))
+	return hex.EncodeToString(h[:]) + ":" + key
+}
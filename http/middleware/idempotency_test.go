@@ -0,0 +1,134 @@
+package middleware_test
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	httpm "goa.design/goa/v3/http/middleware"
+)
+
+func TestIdempotency(t *testing.T) {
+	var calls atomic.Int32
+	release := make(chan struct{})
+	h := httpm.Idempotency(httpm.NewMemoryIdempotencyStore(time.Hour), "")(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		n := calls.Add(1)
+		body, _ := io.ReadAll(r.Body)
+		if string(body) == "slow" {
+			<-release
+		}
+		if string(body) == "fail" {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		w.Header().Set("Location", "/orders/1")
+		w.WriteHeader(http.StatusCreated)
+		w.Write([]byte(strings.Repeat("x", int(n)))) // nolint: errcheck
+	}))
+	do := func(method, key, body string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(method, "/orders", strings.NewReader(body))
+		if key != "" {
+			req.Header.Set(httpm.IdempotencyKeyHeader, key)
+		}
+		w := httptest.NewRecorder()
+		h.ServeHTTP(w, req)
+		return w
+	}
+
+	first := do("POST", "k1", "order")
+	if first.Code != http.StatusCreated || first.Body.String() != "x" {
+		t.Fatalf("got %d %q, expected 201 \"x\"", first.Code, first.Body.String())
+	}
+	replay := do("POST", "k1", "order")
+	if replay.Code != http.StatusCreated || replay.Body.String() != "x" {
+		t.Errorf("replay: got %d %q, expected 201 \"x\"", replay.Code, replay.Body.String())
+	}
+	if replay.Header().Get("Location") != "/orders/1" || replay.Header().Get("Idempotent-Replayed") != "true" {
+		t.Errorf("replay: got headers %v", replay.Header())
+	}
+	if n := calls.Load(); n != 1 {
+		t.Errorf("got %d calls, expected 1", n)
+	}
+	if w := do("POST", "k1", "other"); w.Code != http.StatusUnprocessableEntity {
+		t.Errorf("mismatch: got status %d, expected 422", w.Code)
+	}
+	if w := do("POST", "", "order"); w.Code != http.StatusCreated || calls.Load() != 2 {
+		t.Errorf("no key: got status %d and %d calls, expected 201 and 2 calls", w.Code, calls.Load())
+	}
+
+	// Server errors are not recorded.
+	do("POST", "k2", "fail")
+	do("POST", "k2", "fail")
+	if n := calls.Load(); n != 4 {
+		t.Errorf("got %d calls, expected 4", n)
+	}
+
+	// Concurrent duplicates are rejected.
+	done := make(chan *httptest.ResponseRecorder)
+	go func() { done <- do("POST", "k3", "slow") }()
+	for calls.Load() != 5 {
+		time.Sleep(time.Millisecond)
+	}
+	if w := do("POST", "k3", "slow"); w.Code != http.StatusConflict {
+		t.Errorf("concurrent: got status %d, expected 409", w.Code)
+	}
+	close(release)
+	if w := <-done; w.Code != http.StatusCreated {
+		t.Errorf("got status %d, expected 201", w.Code)
+	}
+}
+
+func TestIdempotencyScope(t *testing.T) {
+	cases := []struct {
+		Name  string
+		Opts  []httpm.IdempotencyOption
+		Creds [2]string
+	}{
+		{"authorization", nil, [2]string{"Bearer alice", "Bearer bob"}},
+		{"custom", []httpm.IdempotencyOption{httpm.WithIdempotencyScope(func(r *http.Request) string { return r.Header.Get("X-User") })}, [2]string{"alice", "bob"}},
+	}
+	for _, c := range cases {
+		t.Run(c.Name, func(t *testing.T) {
+			h := httpm.Idempotency(httpm.NewMemoryIdempotencyStore(time.Hour), "", c.Opts...)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.Write([]byte(r.Header.Get("Authorization") + r.Header.Get("X-User"))) // nolint: errcheck
+			}))
+			do := func(cred string) *httptest.ResponseRecorder {
+				req := httptest.NewRequest("POST", "/orders", strings.NewReader("order"))
+				req.Header.Set(httpm.IdempotencyKeyHeader, "k1")
+				req.Header.Set("Authorization", cred)
+				req.Header.Set("X-User", cred)
+				w := httptest.NewRecorder()
+				h.ServeHTTP(w, req)
+				return w
+			}
+			for _, cred := range c.Creds {
+				w := do(cred)
+				if w.Body.String() != cred+cred || w.Header().Get("Idempotent-Replayed") != "" {
+					t.Errorf("got %q replayed %q, expected %q not replayed", w.Body.String(), w.Header().Get("Idempotent-Replayed"), cred+cred)
+				}
+			}
+			if w := do(c.Creds[0]); w.Header().Get("Idempotent-Replayed") != "true" {
+				t.Errorf("expected the response of the first caller to be replayed")
+			}
+		})
+	}
+}
+
+func TestIdempotencySafeMethods(t *testing.T) {
+	var calls int
+	h := httpm.Idempotency(httpm.NewMemoryIdempotencyStore(time.Hour), "X-Request-Key")(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+	}))
+	for i := 0; i < 2; i++ {
+		req := httptest.NewRequest("GET", "/", nil)
+		req.Header.Set("X-Request-Key", "k")
+		h.ServeHTTP(httptest.NewRecorder(), req)
+	}
+	if calls != 2 {
+		t.Errorf("got %d calls, expected 2", calls)
+	}
+}
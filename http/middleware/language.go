@@ -0,0 +1,29 @@
+package middleware
+
+import (
+	"net/http"
+
+	goahttp "goa.design/goa/v3/http"
+)
+
+// AcceptLanguage returns a middleware that stores the language tags listed in
+// the request Accept-Language header in the request context by order of
+// preference, see goahttp.ParseAcceptLanguage. The tags are retrieved with
+// goahttp.Languages, goahttp.NewLocalizedErrorResponse uses them to translate
+// the error messages.
+//
+// Example:
+//
+//	handler = middleware.AcceptLanguage()(handler)
+func AcceptLanguage() func(http.Handler) http.Handler {
+	return func(h http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			langs := goahttp.ParseAcceptLanguage(r.Header.Get("Accept-Language"))
+			if len(langs) == 0 {
+				h.ServeHTTP(w, r)
+				return
+			}
+			h.ServeHTTP(w, r.WithContext(goahttp.WithLanguages(r.Context(), langs)))
+		})
+	}
+}
@@ -0,0 +1,28 @@
+package middleware_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+
+	goahttp "goa.design/goa/v3/http"
+	httpm "goa.design/goa/v3/http/middleware"
+)
+
+func TestAcceptLanguage(t *testing.T) {
+	var langs []string
+	h := httpm.AcceptLanguage()(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		langs = goahttp.Languages(r.Context())
+	}))
+	req := httptest.NewRequest("GET", "/", nil)
+	req.Header.Set("Accept-Language", "en;q=0.5, fr-CA")
+	h.ServeHTTP(httptest.NewRecorder(), req)
+	if expected := []string{"fr-ca", "en"}; !reflect.DeepEqual(langs, expected) {
+		t.Errorf("got %v, expected %v", langs, expected)
+	}
+	h.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("GET", "/", nil))
+	if langs != nil {
+		t.Errorf("got %v, expected no language", langs)
+	}
+}
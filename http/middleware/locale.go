@@ -0,0 +1,58 @@
+package middleware
+
+import (
+	"net/http"
+	"strings"
+	"time"
+
+	goahttp "goa.design/goa/v3/http"
+)
+
+// TimeZone returns a middleware that loads the time zone whose IANA name is set
+// in the request header with the given name, goahttp.TimeZoneHeader if empty,
+// and stores it in the request context. The time zone is retrieved with
+// goahttp.TimeZone which returns UTC for the requests that do not set the
+// header. Requests whose header does not name a known time zone are rejected
+// with a 400 Bad Request response.
+//
+// Example:
+//
+//	handler = middleware.TimeZone("")(handler)
+func TimeZone(header string) func(http.Handler) http.Handler {
+	if header == "" {
+		header = goahttp.TimeZoneHeader
+	}
+	return func(h http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			name := strings.TrimSpace(r.Header.Get(header))
+			if name == "" {
+				h.ServeHTTP(w, r)
+				return
+			}
+			loc, err := time.LoadLocation(name)
+			if err != nil || name == "Local" {
+				http.Error(w, "invalid "+header+" header: unknown time zone "+name, http.StatusBadRequest)
+				return
+			}
+			h.ServeHTTP(w, r.WithContext(goahttp.WithTimeZone(r.Context(), loc)))
+		})
+	}
+}
+
+// Locale returns a middleware that combines the AcceptLanguage and TimeZone
+// middlewares. tzHeader is the name of the time zone header,
+// goahttp.TimeZoneHeader if empty. The middleware implements the behavior
+// advertised by the "http:locale" meta in the generated OpenAPI specifications.
+//
+// Example:
+//
+//	handler = middleware.Locale("")(handler)
+//	// ... in the service:
+//	loc := goahttp.TimeZone(ctx)
+//	lang := goahttp.Language(ctx, []string{"en", "fr"}, "en")
+func Locale(tzHeader string) func(http.Handler) http.Handler {
+	lang, tz := AcceptLanguage(), TimeZone(tzHeader)
+	return func(h http.Handler) http.Handler {
+		return lang(tz(h))
+	}
+}
@@ -0,0 +1,77 @@
+package middleware_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	goahttp "goa.design/goa/v3/http"
+	httpm "goa.design/goa/v3/http/middleware"
+)
+
+func TestTimeZone(t *testing.T) {
+	cases := map[string]struct {
+		Header   string
+		Value    string
+		Status   int
+		Expected string
+	}{
+		"default":      {"", "Europe/Paris", http.StatusOK, "Europe/Paris"},
+		"custom":       {"X-Time-Zone", "Asia/Tokyo", http.StatusOK, "Asia/Tokyo"},
+		"missing":      {"", "", http.StatusOK, "UTC"},
+		"unknown":      {"", "Mars/Olympus_Mons", http.StatusBadRequest, ""},
+		"server-local": {"", "Local", http.StatusBadRequest, ""},
+	}
+	for k, c := range cases {
+		t.Run(k, func(t *testing.T) {
+			var loc *time.Location
+			h := httpm.TimeZone(c.Header)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				loc = goahttp.TimeZone(r.Context())
+			}))
+			req := httptest.NewRequest("GET", "/", nil)
+			if c.Value != "" {
+				header := c.Header
+				if header == "" {
+					header = goahttp.TimeZoneHeader
+				}
+				req.Header.Set(header, c.Value)
+			}
+			w := httptest.NewRecorder()
+			h.ServeHTTP(w, req)
+			if w.Code != c.Status {
+				t.Fatalf("got status %d, expected %d", w.Code, c.Status)
+			}
+			if c.Expected == "" {
+				if loc != nil {
+					t.Errorf("handler called with time zone %v", loc)
+				}
+				return
+			}
+			if loc == nil || loc.String() != c.Expected {
+				t.Errorf("got time zone %v, expected %s", loc, c.Expected)
+			}
+		})
+	}
+}
+
+func TestLocale(t *testing.T) {
+	var (
+		lang string
+		loc  *time.Location
+	)
+	h := httpm.Locale("")(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		lang = goahttp.Language(r.Context(), []string{"en", "fr"}, "en")
+		loc = goahttp.TimeZone(r.Context())
+	}))
+	req := httptest.NewRequest("GET", "/", nil)
+	req.Header.Set("Accept-Language", "fr-CA, en;q=0.5")
+	req.Header.Set("Time-Zone", "America/Montreal")
+	h.ServeHTTP(httptest.NewRecorder(), req)
+	if lang != "fr" {
+		t.Errorf("got language %q, expected fr", lang)
+	}
+	if loc == nil || loc.String() != "America/Montreal" {
+		t.Errorf("got time zone %v, expected America/Montreal", loc)
+	}
+}
@@ -0,0 +1,168 @@
+package middleware
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"os"
+	"os/signal"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"time"
+)
+
+type (
+	// MaintenanceMode is an application wide switch that makes the
+	// Maintenance middleware reject the requests with 503 Service
+	// Unavailable while it is on. MaintenanceMode is safe for concurrent
+	// use.
+	MaintenanceMode struct {
+		on          atomic.Bool
+		retryAfter  time.Duration
+		contentType string
+		body        []byte
+		exempt      []string
+	}
+
+	// MaintenanceOption customizes a MaintenanceMode.
+	MaintenanceOption func(*MaintenanceMode)
+)
+
+// NewMaintenanceMode returns a maintenance switch that is initially off. The
+// rejected requests are given a Retry-After header of 5 minutes and a plain
+// text body unless configured otherwise.
+//
+// The switch can be flipped with Enable and Disable, by sending a signal to
+// the process (see ToggleOnSignal) or through the admin server by exposing it
+// as a debug toggle:
+//
+//	m := httpmdlwr.NewMaintenanceMode(httpmdlwr.MaintenanceExempt("/livez", "/readyz"))
+//	handler = httpmdlwr.Maintenance(m)(handler)
+//	go m.ToggleOnSignal(ctx)
+//	admin := goahttp.NewAdminServer(":9090",
+//	    goahttp.WithAdminDebug(
+//	        goahttp.WithDebugAuth(goahttp.DebugLoopbackAuth),
+//	        goahttp.WithDebugToggle("maintenance", m.String, m.Set)))
+func NewMaintenanceMode(opts ...MaintenanceOption) *MaintenanceMode {
+	m := &MaintenanceMode{
+		retryAfter:  5 * time.Minute,
+		contentType: "text/plain; charset=utf-8",
+		body:        []byte("Service under maintenance, please retry later.\n"),
+	}
+	for _, opt := range opts {
+		opt(m)
+	}
+	return m
+}
+
+// MaintenanceRetryAfter sets the value of the Retry-After header of the
+// rejected requests, no header is written if d is 0.
+func MaintenanceRetryAfter(d time.Duration) MaintenanceOption {
+	return func(m *MaintenanceMode) { m.retryAfter = d }
+}
+
+// MaintenanceBody sets the content type and body of the responses to the
+// rejected requests.
+func MaintenanceBody(contentType string, body []byte) MaintenanceOption {
+	return func(m *MaintenanceMode) {
+		m.contentType = contentType
+		m.body = body
+	}
+}
+
+// MaintenanceExempt exempts the requests whose path starts with one of the
+// given prefixes, e.g. the health checks, from the maintenance mode.
+func MaintenanceExempt(prefixes ...string) MaintenanceOption {
+	return func(m *MaintenanceMode) { m.exempt = append(m.exempt, prefixes...) }
+}
+
+// Maintenance returns a middleware that responds with 503 Service Unavailable
+// to the requests that are not exempt while m is on.
+func Maintenance(m *MaintenanceMode) func(http.Handler) http.Handler {
+	return func(h http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if !m.on.Load() || m.isExempt(r) {
+				h.ServeHTTP(w, r)
+				return
+			}
+			if m.retryAfter > 0 {
+				w.Header().Set("Retry-After", strconv.Itoa(int(m.retryAfter.Round(time.Second)/time.Second)))
+			}
+			w.Header().Set("Content-Type", m.contentType)
+			w.WriteHeader(http.StatusServiceUnavailable)
+			w.Write(m.body) // nolint: errcheck
+		})
+	}
+}
+
+// Enable turns the maintenance mode on.
+func (m *MaintenanceMode) Enable() { m.on.Store(true) }
+
+// Disable turns the maintenance mode off.
+func (m *MaintenanceMode) Disable() { m.on.Store(false) }
+
+// Enabled returns true if the maintenance mode is on.
+func (m *MaintenanceMode) Enabled() bool { return m.on.Load() }
+
+// String returns "on" or "off".
+func (m *MaintenanceMode) String() string {
+	if m.on.Load() {
+		return "on"
+	}
+	return "off"
+}
+
+// Set turns the maintenance mode on or off given "on" or "off" or a boolean
+// value as accepted by strconv.ParseBool.
+func (m *MaintenanceMode) Set(v string) error {
+	switch strings.ToLower(v) {
+	case "on":
+		m.Enable()
+		return nil
+	case "off":
+		m.Disable()
+		return nil
+	}
+	on, err := strconv.ParseBool(v)
+	if err != nil {
+		return fmt.Errorf("invalid maintenance mode %q, must be on or off", v)
+	}
+	m.on.Store(on)
+	return nil
+}
+
+// ToggleOnSignal flips the maintenance mode each time the process receives
+// one of the given signals, SIGUSR2 if none is given (no signal on Windows).
+// ToggleOnSignal blocks until ctx is canceled.
+func (m *MaintenanceMode) ToggleOnSignal(ctx context.Context, sigs ...os.Signal) {
+	if len(sigs) == 0 {
+		sigs = maintenanceSignals
+	}
+	if len(sigs) == 0 {
+		<-ctx.Done()
+		return
+	}
+	ch := make(chan os.Signal, 1)
+	signal.Notify(ch, sigs...)
+	defer signal.Stop(ch)
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ch:
+			m.on.Store(!m.on.Load())
+		}
+	}
+}
+
+// isExempt returns true if the request path starts with one of the exempt
+// prefixes.
+func (m *MaintenanceMode) isExempt(r *http.Request) bool {
+	for _, p := range m.exempt {
+		if strings.HasPrefix(r.URL.Path, p) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,50 @@
+package middleware_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	httpm "goa.design/goa/v3/http/middleware"
+)
+
+func TestMaintenance(t *testing.T) {
+	m := httpm.NewMaintenanceMode(
+		httpm.MaintenanceExempt("/readyz"),
+		httpm.MaintenanceRetryAfter(2*time.Minute),
+		httpm.MaintenanceBody("application/json", []byte(`{"maintenance":true}`)))
+	h := httpm.Maintenance(m)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("ok")) // nolint: errcheck
+	}))
+	serve := func(path string) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		h.ServeHTTP(w, httptest.NewRequest("GET", path, nil))
+		return w
+	}
+	if w := serve("/bottles"); w.Code != http.StatusOK {
+		t.Errorf("got status %d while off, expected 200", w.Code)
+	}
+	if err := m.Set("on"); err != nil {
+		t.Fatal(err)
+	}
+	w := serve("/bottles")
+	if w.Code != http.StatusServiceUnavailable {
+		t.Errorf("got status %d while on, expected 503", w.Code)
+	}
+	if ra := w.Header().Get("Retry-After"); ra != "120" {
+		t.Errorf("got Retry-After %q, expected 120", ra)
+	}
+	if w.Body.String() != `{"maintenance":true}` || w.Header().Get("Content-Type") != "application/json" {
+		t.Errorf("got body %q, expected the custom body", w.Body.String())
+	}
+	if w := serve("/readyz"); w.Code != http.StatusOK {
+		t.Errorf("got status %d for exempt path, expected 200", w.Code)
+	}
+	if err := m.Set("maybe"); err == nil {
+		t.Error("expected an error for an invalid value")
+	}
+	if err := m.Set("false"); err != nil || m.Enabled() {
+		t.Errorf("got %v, %v, expected the mode to be off", err, m.Enabled())
+	}
+}
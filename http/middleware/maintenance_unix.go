@@ -0,0 +1,12 @@
+//go:build !windows
+
+package middleware
+
+import (
+	"os"
+	"syscall"
+)
+
+// maintenanceSignals are the signals that toggle a MaintenanceMode by
+// default.
+var maintenanceSignals = []os.Signal{syscall.SIGUSR2}
@@ -0,0 +1,7 @@
+package middleware
+
+import "os"
+
+// maintenanceSignals are the signals that toggle a MaintenanceMode by
+// default, there is no user defined signal on Windows.
+var maintenanceSignals []os.Signal
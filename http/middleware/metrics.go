@@ -0,0 +1,332 @@
+package middleware
+
+import (
+	"expvar"
+	"fmt"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"goa.design/goa/v3/middleware"
+)
+
+// RequestStartHeader is the name of the header set by load balancers and
+// reverse proxies (e.g. nginx, Heroku) to the time they received the request.
+// The value is a Unix timestamp in seconds, milliseconds or microseconds,
+// optionally prefixed with "t=".
+const RequestStartHeader = "X-Request-Start"
+
+type (
+	// ServerMetrics collects runtime metrics of a HTTP server useful for
+	// capacity planning: the number of open, idle and hijacked connections,
+	// the number of handlers running concurrently, the number of requests
+	// per status class and the time requests spend queued and handled. The
+	// connection metrics are collected by the ConnState method which must
+	// be set as the http.Server ConnState hook, the request metrics by the
+	// Metrics middleware. The counters are plain integers updated
+	// atomically so that recording a request does not take a lock and the
+	// counters hold no pointers for the garbage collector to scan.
+	// ServerMetrics is safe for concurrent use.
+	//
+	// The metrics are exposed with Snapshot, published with expvar using
+	// Publish, rendered in the Prometheus text format by ServeHTTP or
+	// recorded with the instruments of the metrics providers given to
+	// NewServerMetrics.
+	ServerMetrics struct {
+		connections    atomic.Int64
+		openConns      atomic.Int64
+		idleConns      atomic.Int64
+		hijackedConns  atomic.Int64
+		activeHandlers atomic.Int64
+		requests       [6]atomic.Int64
+		handleNanos    atomic.Int64
+		queuedRequests atomic.Int64
+		queueNanos     atomic.Int64
+
+		// instruments records the metrics with the metrics providers.
+		instruments []*serverInstruments
+
+		// mu protects states.
+		mu sync.Mutex
+		// states records the last state of each open connection.
+		states map[net.Conn]http.ConnState
+	}
+
+	// MetricsSnapshot is a point in time copy of ServerMetrics.
+	MetricsSnapshot struct {
+		// Connections is the total number of accepted connections.
+		Connections int64 `json:"connections"`
+		// OpenConnections is the number of connections currently open,
+		// idle or not.
+		OpenConnections int64 `json:"open_connections"`
+		// IdleConnections is the number of open connections waiting
+		// for a new request.
+		IdleConnections int64 `json:"idle_connections"`
+		// HijackedConnections is the total number of hijacked
+		// connections (e.g. websockets).
+		HijackedConnections int64 `json:"hijacked_connections"`
+		// ActiveHandlers is the number of requests currently handled.
+		ActiveHandlers int64 `json:"active_handlers"`
+		// Requests is the total number of handled requests indexed by
+		// status class ("1xx" to "5xx" and "other" for invalid status
+		// codes).
+		Requests map[string]int64 `json:"requests"`
+		// HandleTime is the cumulated time spent handling requests.
+		HandleTime time.Duration `json:"handle_time"`
+		// QueuedRequests is the total number of requests whose queue
+		// time is known, that is requests with a X-Request-Start header.
+		QueuedRequests int64 `json:"queued_requests"`
+		// QueueTime is the cumulated time spent by requests between
+		// the time set in the X-Request-Start header and the time the
+		// handler starts.
+		QueueTime time.Duration `json:"queue_time"`
+	}
+
+	// serverInstruments contains the instruments created by a metrics
+	// provider for ServerMetrics.
+	serverInstruments struct {
+		connections    middleware.Counter
+		openConns      middleware.Gauge
+		idleConns      middleware.Gauge
+		hijackedConns  middleware.Counter
+		activeHandlers middleware.Gauge
+		requests       middleware.Counter
+		handleSeconds  middleware.Histogram
+		queueSeconds   middleware.Histogram
+	}
+)
+
+// NewServerMetrics returns a ServerMetrics with all the counters set to zero.
+// The metrics are also recorded with the instruments created by the given
+// providers, e.g. to send them to StatsD.
+//
+// Example:
+//
+//	sink, err := statsd.New("127.0.0.1:8125", statsd.WithDogStatsD())
+//	if err != nil {
+//	    return err
+//	}
+//	metrics := middleware.NewServerMetrics(sink)
+func NewServerMetrics(providers ...middleware.MetricsProvider) *ServerMetrics {
+	m := &ServerMetrics{states: make(map[net.Conn]http.ConnState)}
+	for _, p := range providers {
+		m.instruments = append(m.instruments, &serverInstruments{
+			connections:    p.NewCounter("http_server_connections_total", "Total number of accepted connections."),
+			openConns:      p.NewGauge("http_server_open_connections", "Number of open connections."),
+			idleConns:      p.NewGauge("http_server_idle_connections", "Number of idle connections."),
+			hijackedConns:  p.NewCounter("http_server_hijacked_connections_total", "Total number of hijacked connections."),
+			activeHandlers: p.NewGauge("http_server_active_handlers", "Number of requests currently handled."),
+			requests:       p.NewCounter("http_server_requests_total", "Total number of handled requests by status class."),
+			handleSeconds:  p.NewHistogram("http_server_handle_seconds", "Time spent handling requests."),
+			queueSeconds:   p.NewHistogram("http_server_queue_seconds", "Time spent by requests queued before being handled."),
+		})
+	}
+	return m
+}
+
+// ConnState records the connection state transitions. It must be set as the
+// ConnState hook of the http.Server.
+//
+// Example:
+//
+//	metrics := middleware.NewServerMetrics()
+//	srv := &http.Server{Addr: ":8080", Handler: handler, ConnState: metrics.ConnState}
+func (m *ServerMetrics) ConnState(c net.Conn, state http.ConnState) {
+	m.mu.Lock()
+	if m.states == nil {
+		m.states = make(map[net.Conn]http.ConnState)
+	}
+	prev, ok := m.states[c]
+	switch state {
+	case http.StateClosed, http.StateHijacked:
+		delete(m.states, c)
+	default:
+		m.states[c] = state
+	}
+	m.mu.Unlock()
+
+	if ok && prev == http.StateIdle {
+		m.idleConns.Add(-1)
+	}
+	switch state {
+	case http.StateNew:
+		m.connections.Add(1)
+		m.openConns.Add(1)
+	case http.StateIdle:
+		m.idleConns.Add(1)
+	case http.StateHijacked:
+		m.hijackedConns.Add(1)
+		m.openConns.Add(-1)
+	case http.StateClosed:
+		m.openConns.Add(-1)
+	}
+	for _, i := range m.instruments {
+		switch state {
+		case http.StateNew:
+			i.connections.Add(1)
+		case http.StateHijacked:
+			i.hijackedConns.Add(1)
+		}
+		i.openConns.Set(float64(m.openConns.Load()))
+		i.idleConns.Set(float64(m.idleConns.Load()))
+	}
+}
+
+// Metrics returns a middleware that records the number of active handlers,
+// the number of requests per response status class, the time spent handling
+// requests and the time requests spent queued in front of the server as
+// reported by the X-Request-Start header.
+//
+// Example:
+//
+//	metrics := middleware.NewServerMetrics()
+//	handler = middleware.Metrics(metrics)(handler)
+func Metrics(m *ServerMetrics) func(http.Handler) http.Handler {
+	return func(h http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			started := time.Now()
+			if t, ok := requestStart(r.Header.Get(RequestStartHeader)); ok && t.Before(started) {
+				m.queuedRequests.Add(1)
+				m.queueNanos.Add(int64(started.Sub(t)))
+				for _, i := range m.instruments {
+					i.queueSeconds.Observe(started.Sub(t).Seconds())
+				}
+			}
+			active := m.activeHandlers.Add(1)
+			for _, i := range m.instruments {
+				i.activeHandlers.Set(float64(active))
+			}
+			rw := CaptureResponse(w)
+			defer func() {
+				active := m.activeHandlers.Add(-1)
+				elapsed := time.Since(started)
+				m.handleNanos.Add(int64(elapsed))
+				code := rw.StatusCode
+				if code == 0 {
+					code = http.StatusOK
+				}
+				class := code / 100
+				if class < 1 || class > 5 {
+					class = 0
+				}
+				m.requests[class].Add(1)
+				for _, i := range m.instruments {
+					i.activeHandlers.Set(float64(active))
+					i.requests.Add(1, "code", statusClass(class))
+					i.handleSeconds.Observe(elapsed.Seconds(), "code", statusClass(class))
+				}
+			}()
+			h.ServeHTTP(rw, r)
+		})
+	}
+}
+
+// Snapshot returns the current values of the metrics.
+func (m *ServerMetrics) Snapshot() *MetricsSnapshot {
+	s := &MetricsSnapshot{
+		Connections:         m.connections.Load(),
+		OpenConnections:     m.openConns.Load(),
+		IdleConnections:     m.idleConns.Load(),
+		HijackedConnections: m.hijackedConns.Load(),
+		ActiveHandlers:      m.activeHandlers.Load(),
+		Requests:            make(map[string]int64, len(m.requests)),
+		HandleTime:          time.Duration(m.handleNanos.Load()),
+		QueuedRequests:      m.queuedRequests.Load(),
+		QueueTime:           time.Duration(m.queueNanos.Load()),
+	}
+	for class := range m.requests {
+		s.Requests[statusClass(class)] = m.requests[class].Load()
+	}
+	return s
+}
+
+// Publish publishes the metrics snapshot with expvar under the given name so
+// that it is served by the expvar handler (/debug/vars). Publish panics if
+// the name is already registered.
+func (m *ServerMetrics) Publish(name string) {
+	expvar.Publish(name, expvar.Func(func() any { return m.Snapshot() }))
+}
+
+// ServeHTTP renders the metrics in the Prometheus text exposition format so
+// that the handler can be scraped by Prometheus directly.
+//
+// Example:
+//
+//	mux.Handle("GET", "/metrics", metrics.ServeHTTP)
+func (m *ServerMetrics) ServeHTTP(w http.ResponseWriter, _ *http.Request) {
+	var (
+		s = m.Snapshot()
+		b strings.Builder
+	)
+	metric := func(name, typ, help string, values ...string) {
+		fmt.Fprintf(&b, "# HELP %s %s\n# TYPE %s %s\n", name, help, name, typ)
+		for _, v := range values {
+			b.WriteString(name)
+			b.WriteString(v)
+			b.WriteByte('\n')
+		}
+	}
+	value := func(v int64) string { return " " + strconv.FormatInt(v, 10) }
+	seconds := func(d time.Duration) string { return " " + strconv.FormatFloat(d.Seconds(), 'g', -1, 64) }
+
+	metric("http_server_connections_total", "counter", "Total number of accepted connections.", value(s.Connections))
+	metric("http_server_open_connections", "gauge", "Number of open connections.", value(s.OpenConnections))
+	metric("http_server_idle_connections", "gauge", "Number of idle connections.", value(s.IdleConnections))
+	metric("http_server_hijacked_connections_total", "counter", "Total number of hijacked connections.", value(s.HijackedConnections))
+	metric("http_server_active_handlers", "gauge", "Number of requests currently handled.", value(s.ActiveHandlers))
+	requests := make([]string, 0, len(m.requests))
+	for class := range m.requests {
+		requests = append(requests, fmt.Sprintf("{code=%q}%s", statusClass(class), value(s.Requests[statusClass(class)])))
+	}
+	metric("http_server_requests_total", "counter", "Total number of handled requests by status class.", requests...)
+	metric("http_server_handle_seconds", "summary", "Time spent handling requests.",
+		"_sum"+seconds(s.HandleTime), "_count"+value(totalRequests(s)))
+	metric("http_server_queue_seconds", "summary", "Time spent by requests queued before being handled.",
+		"_sum"+seconds(s.QueueTime), "_count"+value(s.QueuedRequests))
+
+	w.Header().Set("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+	w.Write([]byte(b.String())) // nolint: errcheck
+}
+
+// statusClass returns the label of the status class with the given index,
+// "other" for 0.
+func statusClass(class int) string {
+	if class == 0 {
+		return "other"
+	}
+	return strconv.Itoa(class) + "xx"
+}
+
+// totalRequests returns the total number of requests recorded in s.
+func totalRequests(s *MetricsSnapshot) int64 {
+	var n int64
+	for _, c := range s.Requests {
+		n += c
+	}
+	return n
+}
+
+// requestStart parses the value of the X-Request-Start header. The unit of
+// the timestamp is inferred from its magnitude.
+func requestStart(v string) (time.Time, bool) {
+	v = strings.TrimPrefix(strings.TrimSpace(v), "t=")
+	if v == "" {
+		return time.Time{}, false
+	}
+	f, err := strconv.ParseFloat(v, 64)
+	if err != nil || f <= 0 {
+		return time.Time{}, false
+	}
+	switch {
+	case f > 1e15: // microseconds
+		return time.UnixMicro(int64(f)), true
+	case f > 1e12: // milliseconds
+		return time.UnixMilli(int64(f)), true
+	default: // seconds
+		return time.Unix(0, int64(f*float64(time.Second))), true
+	}
+}
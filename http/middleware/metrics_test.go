@@ -0,0 +1,144 @@
+package middleware_test
+
+import (
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	httpm "goa.design/goa/v3/http/middleware"
+	"goa.design/goa/v3/middleware"
+)
+
+func TestMetricsConnState(t *testing.T) {
+	var (
+		m      = httpm.NewServerMetrics()
+		c1, c2 = &net.TCPConn{}, &net.TCPConn{}
+	)
+	m.ConnState(c1, http.StateNew)
+	m.ConnState(c2, http.StateNew)
+	m.ConnState(c1, http.StateActive)
+	m.ConnState(c1, http.StateIdle)
+	m.ConnState(c2, http.StateActive)
+	m.ConnState(c2, http.StateHijacked)
+	s := m.Snapshot()
+	if s.Connections != 2 || s.OpenConnections != 1 || s.IdleConnections != 1 || s.HijackedConnections != 1 {
+		t.Errorf("got %+v, expected 2 connections, 1 open, 1 idle and 1 hijacked", s)
+	}
+	m.ConnState(c1, http.StateClosed)
+	s = m.Snapshot()
+	if s.OpenConnections != 0 || s.IdleConnections != 0 {
+		t.Errorf("got %d open and %d idle connections, expected none", s.OpenConnections, s.IdleConnections)
+	}
+}
+
+func TestMetricsMiddleware(t *testing.T) {
+	var (
+		m      = httpm.NewServerMetrics()
+		active int64
+	)
+	h := httpm.Metrics(m)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		active = m.Snapshot().ActiveHandlers
+		if r.URL.Path == "/missing" {
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	h.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("GET", "/", nil))
+	req := httptest.NewRequest("GET", "/missing", nil)
+	start := time.Now().Add(-time.Second)
+	req.Header.Set(httpm.RequestStartHeader, "t="+strconv.FormatInt(start.UnixMicro(), 10))
+	h.ServeHTTP(httptest.NewRecorder(), req)
+
+	s := m.Snapshot()
+	if active != 1 {
+		t.Errorf("got %d active handlers while handling, expected 1", active)
+	}
+	if s.ActiveHandlers != 0 {
+		t.Errorf("got %d active handlers, expected 0", s.ActiveHandlers)
+	}
+	if s.Requests["2xx"] != 1 || s.Requests["4xx"] != 1 {
+		t.Errorf("got requests %v, expected one 2xx and one 4xx", s.Requests)
+	}
+	if s.QueuedRequests != 1 || s.QueueTime < time.Second {
+		t.Errorf("got %d queued requests for %s, expected 1 for at least 1s", s.QueuedRequests, s.QueueTime)
+	}
+
+	w := httptest.NewRecorder()
+	m.ServeHTTP(w, httptest.NewRequest("GET", "/metrics", nil))
+	body := w.Body.String()
+	for _, line := range []string{
+		"# TYPE http_server_requests_total counter",
+		`http_server_requests_total{code="4xx"} 1`,
+		"http_server_handle_seconds_count 2",
+		"http_server_queue_seconds_count 1",
+		"http_server_active_handlers 0",
+	} {
+		if !strings.Contains(body, line+"\n") {
+			t.Errorf("missing %q in metrics:\n%s", line, body)
+		}
+	}
+}
+
+type recordingProvider struct {
+	mu     sync.Mutex
+	values map[string]float64
+}
+
+type recordingInstrument struct {
+	p    *recordingProvider
+	name string
+	set  bool
+}
+
+func (p *recordingProvider) NewCounter(name, _ string) middleware.Counter {
+	return &recordingInstrument{p: p, name: name}
+}
+
+func (p *recordingProvider) NewGauge(name, _ string) middleware.Gauge {
+	return &recordingInstrument{p: p, name: name, set: true}
+}
+
+func (p *recordingProvider) NewHistogram(name, _ string) middleware.Histogram {
+	return &recordingInstrument{p: p, name: name}
+}
+
+func (i *recordingInstrument) Add(v float64, labels ...string) { i.record(v, labels) }
+
+func (i *recordingInstrument) Set(v float64, labels ...string) { i.record(v, labels) }
+
+func (i *recordingInstrument) Observe(v float64, labels ...string) { i.record(1, labels) }
+
+func (i *recordingInstrument) record(v float64, labels []string) {
+	i.p.mu.Lock()
+	defer i.p.mu.Unlock()
+	key := i.name + strings.Join(labels, ",")
+	if i.set {
+		i.p.values[key] = v
+		return
+	}
+	i.p.values[key] += v
+}
+
+func TestServerMetricsProvider(t *testing.T) {
+	p := &recordingProvider{values: make(map[string]float64)}
+	m := httpm.NewServerMetrics(p)
+	h := httpm.Metrics(m)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+	}))
+	h.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("GET", "/", nil))
+	h.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("GET", "/", nil))
+	expected := map[string]float64{
+		"http_server_requests_totalcode,4xx": 2,
+		"http_server_handle_secondscode,4xx": 2,
+		"http_server_active_handlers":        0,
+	}
+	for k, v := range expected {
+		if got := p.values[k]; got != v {
+			t.Errorf("%s: got %v, expected %v", k, got, v)
+		}
+	}
+}
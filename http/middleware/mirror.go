@@ -0,0 +1,196 @@
+package middleware
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"net/http"
+	"net/url"
+	"path"
+	"time"
+
+	goahttp "goa.design/goa/v3/http"
+	"goa.design/goa/v3/middleware"
+)
+
+type (
+	// MirrorOption customizes the Mirror middleware.
+	MirrorOption func(*mirrorOptions)
+
+	// mirrorOptions lists the Mirror middleware options.
+	mirrorOptions struct {
+		// doer sends the mirrored requests.
+		doer goahttp.Doer
+		// maxBodySize is the maximum size of the bodies of the requests
+		// that get mirrored.
+		maxBodySize int64
+		// maxInFlight is the maximum number of mirrored requests being
+		// sent concurrently.
+		maxInFlight int
+		// timeout is the deadline of the mirrored requests.
+		timeout time.Duration
+		// onError is called with the errors returned by doer.
+		onError func(*http.Request, error)
+	}
+)
+
+// MirroredHeader is the header set on the mirrored requests so that the shadow
+// service can tell them apart from regular traffic.
+const MirroredHeader = "X-Goa-Mirrored"
+
+// Mirror returns a server middleware that asynchronously sends a copy of the
+// given percentage of the requests to the shadow service found at the given
+// URL and discards its responses. Mirroring production traffic makes it
+// possible to safely exercise a new version of a service: the response
+// written to the client is always the one produced by the wrapped handler and
+// failures of the shadow service have no effect on it.
+//
+// The path of the mirrored requests is the path of the shadow URL joined with
+// the path of the original request. The middleware buffers the request bodies
+// so that they can be read by both the handler and the mirrored requests,
+// requests whose bodies are larger than the maximum size set with
+// MirrorMaxBodySize (1MB by default) are not mirrored. Requests are also
+// dropped instead of mirrored when the maximum number of in-flight mirrored
+// requests set with MirrorMaxInFlight is reached so that a slow shadow service
+// never holds up the production traffic.
+//
+// Example:
+//
+//	shadow, _ := url.Parse("http://orders-canary.internal:8080")
+//	handler = httpmdlwr.Mirror(shadow, 10, httpmdlwr.MirrorErrorHandler(logErr))(handler)
+func Mirror(shadow *url.URL, percent int, opts ...MirrorOption) func(http.Handler) http.Handler {
+	o := &mirrorOptions{
+		doer:        http.DefaultClient,
+		maxBodySize: 1 << 20,
+		maxInFlight: 100,
+		timeout:     10 * time.Second,
+	}
+	for _, opt := range opts {
+		opt(o)
+	}
+	var (
+		sampler  = middleware.NewFixedSampler(percent)
+		inFlight = make(chan struct{}, o.maxInFlight)
+	)
+	return func(h http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.Header.Get(MirroredHeader) != "" || !sampler.Sample() {
+				h.ServeHTTP(w, r)
+				return
+			}
+			select {
+			case inFlight <- struct{}{}:
+			default:
+				h.ServeHTTP(w, r)
+				return
+			}
+			body, ok := bufferBody(r, o.maxBodySize)
+			if !ok {
+				<-inFlight
+				h.ServeHTTP(w, r)
+				return
+			}
+			req := mirrorRequest(r, shadow, body)
+			go func() {
+				defer func() { <-inFlight }()
+				ctx, cancel := context.WithTimeout(detachedContext{r.Context()}, o.timeout)
+				defer cancel()
+				resp, err := o.doer.Do(req.WithContext(ctx))
+				if err != nil {
+					if o.onError != nil {
+						o.onError(req, err)
+					}
+					return
+				}
+				io.Copy(io.Discard, resp.Body) // nolint: errcheck
+				resp.Body.Close()
+			}()
+			h.ServeHTTP(w, r)
+		})
+	}
+}
+
+// MirrorDoer sets the client used to send the mirrored requests, for example
+// the doer given to the generated client of the shadow service. The default
+// is http.DefaultClient.
+func MirrorDoer(doer goahttp.Doer) MirrorOption {
+	return func(o *mirrorOptions) {
+		o.doer = doer
+	}
+}
+
+// MirrorMaxBodySize sets the maximum size in bytes of the bodies of the
+// requests that get mirrored. The default is 1MB.
+func MirrorMaxBodySize(n int64) MirrorOption {
+	return func(o *mirrorOptions) {
+		o.maxBodySize = n
+	}
+}
+
+// MirrorMaxInFlight sets the maximum number of mirrored requests being sent
+// concurrently. The default is 100.
+func MirrorMaxInFlight(n int) MirrorOption {
+	return func(o *mirrorOptions) {
+		o.maxInFlight = n
+	}
+}
+
+// MirrorTimeout sets the timeout of the mirrored requests. The default is 10
+// seconds.
+func MirrorTimeout(d time.Duration) MirrorOption {
+	return func(o *mirrorOptions) {
+		o.timeout = d
+	}
+}
+
+// MirrorErrorHandler sets a function called with the errors that occur when
+// sending the mirrored requests.
+func MirrorErrorHandler(fn func(*http.Request, error)) MirrorOption {
+	return func(o *mirrorOptions) {
+		o.onError = fn
+	}
+}
+
+// bufferBody reads the body of r so that it can be sent twice and replaces
+// r.Body with a reader of the buffered content. It returns false if the body
+// is larger than max in which case r.Body still reads the complete body.
+func bufferBody(r *http.Request, max int64) ([]byte, bool) {
+	if r.Body == nil || r.Body == http.NoBody {
+		return nil, true
+	}
+	body, err := io.ReadAll(io.LimitReader(r.Body, max+1))
+	if err != nil || int64(len(body)) > max {
+		r.Body = struct {
+			io.Reader
+			io.Closer
+		}{io.MultiReader(bytes.NewReader(body), r.Body), r.Body}
+		return nil, false
+	}
+	r.Body = io.NopCloser(bytes.NewReader(body))
+	return body, true
+}
+
+// mirrorRequest builds the copy of r sent to the shadow service.
+func mirrorRequest(r *http.Request, shadow *url.URL, body []byte) *http.Request {
+	req := r.Clone(context.Background())
+	u := *r.URL
+	u.Scheme = shadow.Scheme
+	u.Host = shadow.Host
+	if shadow.Path != "" {
+		u.Path = path.Join(shadow.Path, r.URL.Path)
+		u.RawPath = ""
+	}
+	req.URL = &u
+	req.Host = shadow.Host
+	req.RequestURI = ""
+	req.Header.Set(MirroredHeader, "true")
+	req.Body = http.NoBody
+	if body != nil {
+		req.Body = io.NopCloser(bytes.NewReader(body))
+		req.GetBody = func() (io.ReadCloser, error) {
+			return io.NopCloser(bytes.NewReader(body)), nil
+		}
+	}
+	req.ContentLength = int64(len(body))
+	return req
+}
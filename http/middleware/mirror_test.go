@@ -0,0 +1,86 @@
+package middleware_test
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	httpm "goa.design/goa/v3/http/middleware"
+)
+
+func TestMirror(t *testing.T) {
+	type mirrored struct {
+		path, body, header string
+	}
+	received := make(chan mirrored, 10)
+	shadow := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		b, _ := io.ReadAll(r.Body)
+		received <- mirrored{r.URL.Path, string(b), r.Header.Get(httpm.MirroredHeader)}
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer shadow.Close()
+	u, _ := url.Parse(shadow.URL + "/v2")
+
+	cases := map[string]struct {
+		percent int
+		body    string
+		opts    []httpm.MirrorOption
+		mirror  bool
+	}{
+		"all":          {100, "payload", nil, true},
+		"none":         {0, "payload", nil, false},
+		"no-body":      {100, "", nil, true},
+		"body-too-big": {100, "payload", []httpm.MirrorOption{httpm.MirrorMaxBodySize(3)}, false},
+	}
+	for name, c := range cases {
+		t.Run(name, func(t *testing.T) {
+			h := httpm.Mirror(u, c.percent, c.opts...)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				b, _ := io.ReadAll(r.Body)
+				w.Write(b) // nolint: errcheck
+			}))
+			req := httptest.NewRequest("POST", "/orders", strings.NewReader(c.body))
+			w := httptest.NewRecorder()
+			h.ServeHTTP(w, req)
+			if w.Code != http.StatusOK || w.Body.String() != c.body {
+				t.Errorf("got response %d %q, expected 200 %q", w.Code, w.Body.String(), c.body)
+			}
+			select {
+			case m := <-received:
+				if !c.mirror {
+					t.Fatalf("unexpected mirrored request %v", m)
+				}
+				if m.path != "/v2/orders" || m.body != c.body || m.header != "true" {
+					t.Errorf("got mirrored request %v", m)
+				}
+			case <-time.After(100 * time.Millisecond):
+				if c.mirror {
+					t.Error("request was not mirrored")
+				}
+			}
+		})
+	}
+}
+
+func TestMirrorErrorHandler(t *testing.T) {
+	errs := make(chan error, 1)
+	u, _ := url.Parse("http://127.0.0.1:1")
+	h := httpm.Mirror(u, 100, httpm.MirrorErrorHandler(func(_ *http.Request, err error) { errs <- err }))(
+		http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) { w.WriteHeader(http.StatusNoContent) }))
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, httptest.NewRequest("GET", "/", nil))
+	if w.Code != http.StatusNoContent {
+		t.Errorf("got status %d, expected %d", w.Code, http.StatusNoContent)
+	}
+	select {
+	case err := <-errs:
+		if err == nil {
+			t.Error("expected an error")
+		}
+	case <-time.After(5 * time.Second):
+		t.Error("error handler was not called")
+	}
+}
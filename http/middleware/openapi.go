@@ -0,0 +1,380 @@
+package middleware
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/getkin/kin-openapi/openapi3filter"
+	"github.com/getkin/kin-openapi/routers"
+	"github.com/getkin/kin-openapi/routers/legacy"
+)
+
+type (
+	// ContractViolation describes a difference between a request or a
+	// response and the OpenAPI document used to validate them.
+	ContractViolation struct {
+		// Operation is the ID of the OpenAPI operation matching the
+		// request.
+		Operation string `json:"operation,omitempty"`
+		// Response is true if the violation was found in the response,
+		// false if it was found in the request.
+		Response bool `json:"response"`
+		// Location is the part of the request or response that violates
+		// the contract: "path", "query", "header", "cookie", "body" or
+		// "status".
+		Location string `json:"location"`
+		// Name is the name of the invalid parameter if any.
+		Name string `json:"name,omitempty"`
+		// Pointer is the JSON pointer of the invalid value in the
+		// parameter or body if any.
+		Pointer string `json:"pointer,omitempty"`
+		// SchemaPath is the JSON pointer of the violated schema keyword in
+		// the OpenAPI document if any, for example
+		// "/components/schemas/User/properties/email/format".
+		SchemaPath string `json:"schema_path,omitempty"`
+		// Message describes the violation.
+		Message string `json:"message"`
+	}
+
+	// OpenAPIOption customizes the ValidateOpenAPI middleware.
+	OpenAPIOption func(*openAPIOptions)
+
+	// openAPIOptions lists the ValidateOpenAPI middleware options.
+	openAPIOptions struct {
+		// reporter is called with the violations found in each request or
+		// response.
+		reporter func(*http.Request, []*ContractViolation)
+		// reportOnly prevents the middleware from rejecting the invalid
+		// requests and responses.
+		reportOnly bool
+	}
+
+	// bufferedResponse is a http.ResponseWriter that buffers the response so
+	// that it can be validated before being written.
+	bufferedResponse struct {
+		http.ResponseWriter
+		status int
+		body   bytes.Buffer
+	}
+)
+
+func init() {
+	// The responses of the endpoints whose result follows the JSON:API
+	// convention are JSON documents, see goahttp.JSONAPIContentType.
+	openapi3filter.RegisterBodyDecoder("application/vnd.api+json", openapi3filter.RegisteredBodyDecoder("application/json"))
+}
+
+// ValidateOpenAPI returns a server middleware that validates the incoming
+// requests and the outgoing responses against the given OpenAPI 3 document,
+// typically the openapi3.json or openapi3.yaml file generated by goa. The
+// middleware is meant to be used in test and staging environments to detect
+// differences between the service implementation and its contract: it buffers
+// the responses and validates every request and response which makes it
+// unsuitable for production and for streaming endpoints.
+//
+// By default the middleware responds with status code 400 when a request
+// violates the contract and with status code 500 when a response does. The
+// body of these responses is the JSON representation of the list of
+// violations. Requests that match no operation of the document are served
+// without validation.
+//
+// Example:
+//
+//	spec, _ := os.ReadFile("gen/http/openapi3.json")
+//	validate, err := middleware.ValidateOpenAPI(spec, middleware.OpenAPIReporter(report))
+//	if err != nil {
+//	    return err
+//	}
+//	handler = validate(handler)
+func ValidateOpenAPI(spec []byte, opts ...OpenAPIOption) (func(http.Handler) http.Handler, error) {
+	doc, err := openapi3.NewLoader().LoadFromData(spec)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load OpenAPI document: %w", err)
+	}
+	// Match the requests regardless of the host they are sent to.
+	doc.Servers = nil
+	router, err := legacy.NewRouter(doc)
+	if err != nil {
+		return nil, err
+	}
+	o := new(openAPIOptions)
+	for _, opt := range opts {
+		opt(o)
+	}
+	return func(h http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			route, params, err := router.FindRoute(r)
+			if err != nil {
+				h.ServeHTTP(w, r)
+				return
+			}
+			input := &openapi3filter.RequestValidationInput{
+				Request:    r,
+				PathParams: params,
+				Route:      route,
+				Options: &openapi3filter.Options{
+					MultiError:            true,
+					IncludeResponseStatus: true,
+					SkipSettingDefaults:   true,
+					// Security is enforced by the generated code.
+					AuthenticationFunc: openapi3filter.NoopAuthenticationFunc,
+				},
+			}
+			if vs := contractViolations(route, r.Header, 0, openapi3filter.ValidateRequest(r.Context(), input)); len(vs) > 0 {
+				if o.reporter != nil {
+					o.reporter(r, vs)
+				}
+				if !o.reportOnly {
+					writeViolations(w, http.StatusBadRequest, vs)
+					return
+				}
+			}
+
+			rw := &bufferedResponse{ResponseWriter: w, status: http.StatusOK}
+			h.ServeHTTP(rw, r)
+
+			err = openapi3filter.ValidateResponse(r.Context(), &openapi3filter.ResponseValidationInput{
+				RequestValidationInput: input,
+				Status:                 rw.status,
+				Header:                 rw.Header(),
+				Body:                   io.NopCloser(bytes.NewReader(rw.body.Bytes())),
+				Options:                input.Options,
+			})
+			if vs := contractViolations(route, rw.Header(), rw.status, err); len(vs) > 0 {
+				if o.reporter != nil {
+					o.reporter(r, vs)
+				}
+				if !o.reportOnly {
+					writeViolations(w, http.StatusInternalServerError, vs)
+					return
+				}
+			}
+			w.WriteHeader(rw.status)
+			w.Write(rw.body.Bytes()) // nolint: errcheck
+		})
+	}, nil
+}
+
+// OpenAPIReporter sets the function called by the ValidateOpenAPI middleware
+// with the violations found in a request or in a response, for example to log
+// them.
+func OpenAPIReporter(fn func(*http.Request, []*ContractViolation)) OpenAPIOption {
+	return func(o *openAPIOptions) {
+		o.reporter = fn
+	}
+}
+
+// OpenAPIReportOnly prevents the ValidateOpenAPI middleware from rejecting the
+// requests and responses that violate the contract. The violations are only
+// given to the reporter set with OpenAPIReporter.
+func OpenAPIReportOnly() OpenAPIOption {
+	return func(o *openAPIOptions) {
+		o.reportOnly = true
+	}
+}
+
+// Error returns a description of the violation that includes its location.
+func (v *ContractViolation) Error() string {
+	kind := "request"
+	if v.Response {
+		kind = "response"
+	}
+	loc := v.Location
+	if v.Name != "" {
+		loc += " " + strconv.Quote(v.Name)
+	}
+	msg := fmt.Sprintf("%s %s", kind, loc)
+	if v.Pointer != "" {
+		msg += " at " + v.Pointer
+	}
+	msg += ": " + v.Message
+	if v.SchemaPath != "" {
+		msg += " (" + v.SchemaPath + ")"
+	}
+	return msg
+}
+
+// WriteHeader records the status code of the response.
+func (w *bufferedResponse) WriteHeader(code int) {
+	w.status = code
+}
+
+// Write buffers the response body.
+func (w *bufferedResponse) Write(b []byte) (int, error) {
+	return w.body.Write(b)
+}
+
+// contractViolations returns the violations described by the error returned by
+// the OpenAPI request or response validation. status is the status code of the
+// validated response, 0 when validating a request. header is the request or
+// response header used to find the schema of the body.
+func contractViolations(route *routers.Route, header http.Header, status int, err error) []*ContractViolation {
+	if err == nil {
+		return nil
+	}
+	base := &ContractViolation{Operation: route.Operation.OperationID, Response: status != 0}
+	op := "/paths/" + escapePointer(route.Path) + "/" + strings.ToLower(route.Method)
+	var (
+		vs   []*ContractViolation
+		walk func(err error, v *ContractViolation, root string, schema *openapi3.SchemaRef)
+	)
+	walk = func(err error, v *ContractViolation, root string, schema *openapi3.SchemaRef) {
+		switch e := err.(type) {
+		case openapi3.MultiError:
+			for _, e := range e {
+				walk(e, v, root, schema)
+			}
+		case *openapi3filter.RequestError:
+			nv := *v
+			switch {
+			case e.Parameter != nil:
+				nv.Location, nv.Name = e.Parameter.In, e.Parameter.Name
+				root, schema = parameterSchema(route, op, e.Parameter)
+			case e.RequestBody != nil:
+				nv.Location = "body"
+				root, schema = contentSchema(op+"/requestBody", e.RequestBody.Content, header)
+			default:
+				// Security requirements are validated by the generated code.
+				return
+			}
+			if e.Err == nil {
+				nv.Message = e.Error()
+				vs = append(vs, &nv)
+				return
+			}
+			walk(e.Err, &nv, root, schema)
+		case *openapi3filter.ResponseError:
+			nv := *v
+			switch {
+			case strings.HasPrefix(e.Reason, "status"):
+				nv.Location = "status"
+			case strings.Contains(e.Reason, "header"):
+				nv.Location = "header"
+			default:
+				nv.Location = "body"
+				key := strconv.Itoa(status)
+				ref, ok := route.Operation.Responses[key]
+				if !ok {
+					key, ref = "default", route.Operation.Responses.Default()
+				}
+				if ref != nil && ref.Value != nil {
+					root, schema = contentSchema(op+"/responses/"+key, ref.Value.Content, header)
+				}
+			}
+			if e.Err == nil {
+				nv.Message = e.Error()
+				vs = append(vs, &nv)
+				return
+			}
+			walk(e.Err, &nv, root, schema)
+		case *openapi3.SchemaError:
+			nv := *v
+			if ptr := e.JSONPointer(); len(ptr) > 0 {
+				for i, s := range ptr {
+					ptr[i] = escapePointer(s)
+				}
+				nv.Pointer = "/" + strings.Join(ptr, "/")
+			}
+			if root != "" {
+				nv.SchemaPath = schemaPath(root, schema, e)
+			}
+			nv.Message = e.Reason
+			vs = append(vs, &nv)
+		default:
+			nv := *v
+			nv.Message = err.Error()
+			vs = append(vs, &nv)
+		}
+	}
+	walk(err, base, "", nil)
+	return vs
+}
+
+// parameterSchema returns the path of the schema of the given parameter in the
+// OpenAPI document and the schema itself.
+func parameterSchema(route *routers.Route, op string, p *openapi3.Parameter) (string, *openapi3.SchemaRef) {
+	for i, ref := range route.Operation.Parameters {
+		if ref.Value == p {
+			return op + "/parameters/" + strconv.Itoa(i) + "/schema", p.Schema
+		}
+	}
+	for i, ref := range route.PathItem.Parameters {
+		if ref.Value == p {
+			return "/paths/" + escapePointer(route.Path) + "/parameters/" + strconv.Itoa(i) + "/schema", p.Schema
+		}
+	}
+	return "", nil
+}
+
+// contentSchema returns the path of the schema of the body described by the
+// given content in the OpenAPI document and the schema itself. The media type
+// is read from the Content-Type header.
+func contentSchema(path string, content openapi3.Content, header http.Header) (string, *openapi3.SchemaRef) {
+	mt, _, _ := mime.ParseMediaType(header.Get("Content-Type"))
+	media := content.Get(mt)
+	if media == nil {
+		return "", nil
+	}
+	for key, m := range content {
+		if m == media {
+			return path + "/content/" + escapePointer(key) + "/schema", media.Schema
+		}
+	}
+	return "", nil
+}
+
+// schemaPath returns the path of the schema keyword violated by the value
+// described by the given schema error. root is the path of the schema in the
+// OpenAPI document.
+func schemaPath(root string, schema *openapi3.SchemaRef, e *openapi3.SchemaError) string {
+	path := root
+	segments := e.JSONPointer()
+	if e.SchemaField == "required" && len(segments) > 0 {
+		// The pointer of required errors includes the missing property.
+		segments = segments[:len(segments)-1]
+	}
+	for _, seg := range segments {
+		if schema == nil || schema.Value == nil {
+			break
+		}
+		if strings.HasPrefix(schema.Ref, "#/") {
+			path = strings.TrimPrefix(schema.Ref, "#")
+		}
+		s := schema.Value
+		switch {
+		case s.Type == "array" && s.Items != nil:
+			path, schema = path+"/items", s.Items
+		case s.Properties[seg] != nil:
+			path, schema = path+"/properties/"+escapePointer(seg), s.Properties[seg]
+		case s.AdditionalProperties.Schema != nil:
+			path, schema = path+"/additionalProperties", s.AdditionalProperties.Schema
+		default:
+			schema = nil
+		}
+	}
+	if schema != nil && strings.HasPrefix(schema.Ref, "#/") {
+		path = strings.TrimPrefix(schema.Ref, "#")
+	}
+	return path + "/" + e.SchemaField
+}
+
+// escapePointer escapes the given JSON pointer reference token.
+func escapePointer(s string) string {
+	return strings.ReplaceAll(strings.ReplaceAll(s, "~", "~0"), "/", "~1")
+}
+
+// writeViolations writes a response with the given status code whose body is
+// the JSON representation of the given violations.
+func writeViolations(w http.ResponseWriter, code int, vs []*ContractViolation) {
+	w.Header().Del("Content-Length")
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(code)
+	json.NewEncoder(w).Encode(map[string]any{"violations": vs}) // nolint: errcheck
+}
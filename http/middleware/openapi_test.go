@@ -0,0 +1,135 @@
+package middleware_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	httpm "goa.design/goa/v3/http/middleware"
+)
+
+const openAPISpec = `{
+  "openapi": "3.0.3",
+  "info": {"title": "test", "version": "1.0"},
+  "paths": {
+    "/users/{id}": {
+      "put": {
+        "operationId": "users#update",
+        "parameters": [
+          {"name": "id", "in": "path", "required": true, "schema": {"type": "integer"}}
+        ],
+        "requestBody": {
+          "required": true,
+          "content": {"application/json": {"schema": {"$ref": "#/components/schemas/User"}}}
+        },
+        "responses": {
+          "200": {
+            "description": "OK",
+            "content": {"application/json": {"schema": {"$ref": "#/components/schemas/User"}}}
+          }
+        }
+      }
+    }
+  },
+  "components": {
+    "schemas": {
+      "User": {
+        "type": "object",
+        "properties": {
+          "name": {"type": "string"},
+          "emails": {"type": "array", "items": {"type": "string", "maxLength": 5}}
+        },
+        "required": ["name"]
+      }
+    }
+  }
+}`
+
+func TestValidateOpenAPI(t *testing.T) {
+	cases := map[string]struct {
+		Path     string
+		Body     string
+		Response string
+		Status   int
+		// output
+		ExpectedStatus     int
+		ExpectedViolations []string
+	}{
+		"valid":            {"/users/1", `{"name":"a"}`, `{"name":"a"}`, 200, 200, nil},
+		"unknown-route":    {"/other", `{}`, `{}`, 200, 200, nil},
+		"invalid-param":    {"/users/a", `{"name":"a"}`, `{"name":"a"}`, 200, 400, []string{`request path "id": value a: an invalid integer: invalid syntax`}},
+		"missing-property": {"/users/1", `{}`, `{"name":"a"}`, 200, 400, []string{`request body at /name: property "name" is missing (/components/schemas/User/required)`}},
+		"invalid-item":     {"/users/1", `{"name":"a","emails":["toolong"]}`, `{"name":"a"}`, 200, 400, []string{`request body at /emails/0: maximum string length is 5 (/components/schemas/User/properties/emails/items/maxLength)`}},
+		"invalid-response": {"/users/1", `{"name":"a"}`, `{"name":1}`, 200, 500, []string{`response body at /name: value must be a string (/components/schemas/User/properties/name/type)`}},
+		"invalid-status":   {"/users/1", `{"name":"a"}`, `{"name":"a"}`, 201, 500, []string{`response status: status is not supported`}},
+	}
+	for k, c := range cases {
+		t.Run(k, func(t *testing.T) {
+			var reported []string
+			report := func(_ *http.Request, vs []*httpm.ContractViolation) {
+				for _, v := range vs {
+					reported = append(reported, v.Error())
+				}
+			}
+			validate, err := httpm.ValidateOpenAPI([]byte(openAPISpec), httpm.OpenAPIReporter(report))
+			if err != nil {
+				t.Fatal(err)
+			}
+			h := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.Header().Set("Content-Type", "application/json")
+				w.WriteHeader(c.Status)
+				w.Write([]byte(c.Response)) // nolint: errcheck
+			})
+			req, _ := http.NewRequest("PUT", c.Path, strings.NewReader(c.Body))
+			req.Header.Set("Content-Type", "application/json")
+			w := httptest.NewRecorder()
+
+			validate(h).ServeHTTP(w, req)
+
+			if w.Code != c.ExpectedStatus {
+				t.Errorf("got status %d, expected %d", w.Code, c.ExpectedStatus)
+			}
+			if len(reported) != len(c.ExpectedViolations) {
+				t.Fatalf("got violations %q, expected %q", reported, c.ExpectedViolations)
+			}
+			for i, v := range reported {
+				if v != c.ExpectedViolations[i] {
+					t.Errorf("got violation %q, expected %q", v, c.ExpectedViolations[i])
+				}
+			}
+			if c.ExpectedStatus == 200 && w.Body.String() != c.Response {
+				t.Errorf("got body %q, expected %q", w.Body.String(), c.Response)
+			}
+		})
+	}
+}
+
+func TestValidateOpenAPIReportOnly(t *testing.T) {
+	validate, err := httpm.ValidateOpenAPI([]byte(openAPISpec), httpm.OpenAPIReportOnly())
+	if err != nil {
+		t.Fatal(err)
+	}
+	h := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"name":1}`)) // nolint: errcheck
+	})
+	req, _ := http.NewRequest("PUT", "/users/1", strings.NewReader(`{}`))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+
+	validate(h).ServeHTTP(w, req)
+
+	if w.Code != http.StatusOK {
+		t.Errorf("got status %d, expected %d", w.Code, http.StatusOK)
+	}
+	if w.Body.String() != `{"name":1}` {
+		t.Errorf("got body %q, expected the handler response", w.Body.String())
+	}
+}
+
+func TestValidateOpenAPIInvalidSpec(t *testing.T) {
+	if _, err := httpm.ValidateOpenAPI([]byte("{")); err == nil {
+		t.Error("expected an error")
+	}
+}
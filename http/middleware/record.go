@@ -0,0 +1,79 @@
+package middleware
+
+import (
+	"bytes"
+	"net/http"
+	"time"
+
+	goahttp "goa.design/goa/v3/http"
+)
+
+// recordWriter is a http.ResponseWriter that captures the response status
+// and body for the Record middleware.
+type recordWriter struct {
+	*ResponseCapture
+	max  int64
+	body bytes.Buffer
+	// truncated is true if the response body is larger than max.
+	truncated bool
+}
+
+// Record returns a development middleware that captures the requests served by
+// the wrapped handler together with the status code and body of their
+// responses and writes them to rec so that they can be replayed against
+// another environment with goahttp.Replay, for debugging and regression
+// comparison. Request and response bodies larger than maxBodySize bytes are
+// not recorded. The middleware records the values of all the headers,
+// including credentials, and is thus not meant for production use; headers
+// listed in redact are recorded with the value "REDACTED".
+//
+// Example:
+//
+//	f, _ := os.Create("requests.jsonl")
+//	handler = httpmdlwr.Record(goahttp.NewRequestRecorder(f), 1<<20, "Authorization")(handler)
+func Record(rec *goahttp.RequestRecorder, maxBodySize int64, redact ...string) func(http.Handler) http.Handler {
+	return func(h http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			recorded := &goahttp.RecordedRequest{
+				Time:   time.Now(),
+				Method: r.Method,
+				URI:    r.URL.RequestURI(),
+				Header: r.Header.Clone(),
+			}
+			for _, name := range redact {
+				if recorded.Header.Get(name) != "" {
+					recorded.Header.Set(name, "REDACTED")
+				}
+			}
+			body, ok := bufferBody(r, maxBodySize)
+			if ok {
+				recorded.Body = body
+			}
+			rw := &recordWriter{ResponseCapture: CaptureResponse(w), max: maxBodySize}
+			defer func() {
+				recorded.Status = rw.StatusCode
+				if recorded.Status == 0 {
+					recorded.Status = http.StatusOK
+				}
+				if !rw.truncated {
+					recorded.ResponseBody = rw.body.Bytes()
+				}
+				rec.Record(recorded) // nolint: errcheck
+			}()
+			h.ServeHTTP(rw, r)
+		})
+	}
+}
+
+// Write captures the response body up to the maximum size.
+func (w *recordWriter) Write(b []byte) (int, error) {
+	if !w.truncated {
+		if int64(w.body.Len()+len(b)) > w.max {
+			w.truncated = true
+			w.body.Reset()
+		} else {
+			w.body.Write(b)
+		}
+	}
+	return w.ResponseCapture.Write(b)
+}
@@ -0,0 +1,48 @@
+package middleware_test
+
+import (
+	"bytes"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	goahttp "goa.design/goa/v3/http"
+	httpm "goa.design/goa/v3/http/middleware"
+)
+
+func TestRecord(t *testing.T) {
+	var buf bytes.Buffer
+	h := httpm.Record(goahttp.NewRequestRecorder(&buf), 10, "Authorization")(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		b, _ := io.ReadAll(r.Body)
+		w.WriteHeader(http.StatusAccepted)
+		w.Write(b) // nolint: errcheck
+	}))
+	for _, body := range []string{"small", "larger than ten bytes"} {
+		req := httptest.NewRequest("POST", "/items?a=1", strings.NewReader(body))
+		req.Header.Set("Authorization", "Bearer secret")
+		w := httptest.NewRecorder()
+		h.ServeHTTP(w, req)
+		if w.Body.String() != body {
+			t.Errorf("got response body %q, expected %q", w.Body.String(), body)
+		}
+	}
+	recs, err := goahttp.ReadRecordedRequests(&buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(recs) != 2 {
+		t.Fatalf("got %d recorded requests, expected 2", len(recs))
+	}
+	r := recs[0]
+	if r.Method != "POST" || r.URI != "/items?a=1" || string(r.Body) != "small" || r.Status != http.StatusAccepted || string(r.ResponseBody) != "small" {
+		t.Errorf("got %+v", r)
+	}
+	if a := r.Header.Get("Authorization"); a != "REDACTED" {
+		t.Errorf("got Authorization %q, expected REDACTED", a)
+	}
+	if r := recs[1]; r.Body != nil || r.ResponseBody != nil {
+		t.Errorf("got bodies %q and %q, expected none", r.Body, r.ResponseBody)
+	}
+}
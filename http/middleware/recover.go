@@ -0,0 +1,62 @@
+package middleware
+
+import (
+	"context"
+	"net/http"
+
+	goahttp "goa.design/goa/v3/http"
+	goa "goa.design/goa/v3/pkg"
+)
+
+// Recover returns a server middleware that recovers from the panics that occur
+// in the handlers it wraps. The recovered panics are given to errhandler as
+// *goa.PanicError errors whose snapshot contains the route and path
+// parameters of the request before a 500 Internal Server Error response is
+// written. The middleware also stores the route in the request context so
+// that the snapshots of the errors produced by the endpoint Recover middleware
+// include it. The middleware must be applied to the muxer with Use so that the
+// path parameters can be retrieved. http.ErrAbortHandler panics are not
+// recovered.
+//
+// Example:
+//
+//	mux.Use(httpmdlwr.Recover(mux, errorHandler))
+//	endpoints.Use(middleware.Recover())
+func Recover(mux goahttp.Muxer, errhandler func(context.Context, http.ResponseWriter, error)) func(http.Handler) http.Handler {
+	return func(h http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			ctx := goa.WithRoute(r.Context(), func() (string, map[string]string) {
+				return r.Method + " " + r.URL.Path, vars(mux, r)
+			})
+			r = r.WithContext(ctx)
+			defer func() {
+				p := recover()
+				if p == nil {
+					return
+				}
+				if p == http.ErrAbortHandler {
+					panic(p)
+				}
+				err := goa.NewPanicError(ctx, p, nil)
+				if errhandler != nil {
+					errhandler(ctx, w, err)
+				}
+				enc := goahttp.ResponseEncoder(ctx, w)
+				w.WriteHeader(http.StatusInternalServerError)
+				enc.Encode(goahttp.NewErrorResponse(ctx, err)) // nolint: errcheck
+			}()
+			h.ServeHTTP(w, r)
+		})
+	}
+}
+
+// vars returns the path parameters of r or nil if r has not been routed by
+// mux.
+func vars(mux goahttp.Muxer, r *http.Request) (params map[string]string) {
+	defer func() {
+		if recover() != nil {
+			params = nil
+		}
+	}()
+	return mux.Vars(r)
+}
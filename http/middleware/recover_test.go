@@ -0,0 +1,72 @@
+package middleware_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	goahttp "goa.design/goa/v3/http"
+	httpm "goa.design/goa/v3/http/middleware"
+	"goa.design/goa/v3/middleware"
+	goa "goa.design/goa/v3/pkg"
+)
+
+func TestRecover(t *testing.T) {
+	var got error
+	errhandler := func(_ context.Context, _ http.ResponseWriter, err error) { got = err }
+	mux := goahttp.NewMuxer()
+	mux.Use(httpm.Recover(mux, errhandler))
+	type payload struct{ ID string }
+	endpoint := middleware.Recover()(func(ctx context.Context, req any) (any, error) {
+		panic("boom")
+	})
+	mux.Handle("GET", "/items/{id}", func(w http.ResponseWriter, r *http.Request) {
+		ctx := context.WithValue(r.Context(), goa.MethodKey, "show")
+		if _, err := endpoint(ctx, &payload{ID: "42"}); err != nil {
+			errhandler(ctx, w, err)
+			w.WriteHeader(http.StatusInternalServerError)
+		}
+	})
+	mux.Handle("GET", "/crash/{id}", func(w http.ResponseWriter, r *http.Request) {
+		panic(errors.New("crash"))
+	})
+
+	cases := map[string]struct {
+		path    string
+		route   string
+		value   any
+		method  string
+		payload bool
+	}{
+		"endpoint": {"/items/42", "GET /items/42", "boom", "show", true},
+		"handler":  {"/crash/42", "GET /crash/42", "crash", "", false},
+	}
+	for name, c := range cases {
+		t.Run(name, func(t *testing.T) {
+			got = nil
+			w := httptest.NewRecorder()
+			mux.ServeHTTP(w, httptest.NewRequest("GET", c.path, nil))
+			if w.Code != http.StatusInternalServerError {
+				t.Errorf("got status %d, expected 500", w.Code)
+			}
+			var perr *goa.PanicError
+			if !errors.As(got, &perr) {
+				t.Fatalf("got error %v, expected a *goa.PanicError", got)
+			}
+			if v := perr.Value; v != c.value {
+				if e, ok := v.(error); !ok || e.Error() != c.value {
+					t.Errorf("got panic value %v, expected %v", v, c.value)
+				}
+			}
+			s := perr.Snapshot
+			if s.Route != c.route || s.Params["id"] != "42" || s.Method != c.method || (s.Payload != nil) != c.payload {
+				t.Errorf("got snapshot %+v", s)
+			}
+			if len(perr.Stack) == 0 {
+				t.Error("missing stack trace")
+			}
+		})
+	}
+}
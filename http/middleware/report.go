@@ -0,0 +1,31 @@
+package middleware
+
+import (
+	"context"
+	"net/http"
+
+	"goa.design/goa/v3/middleware"
+)
+
+// ReportErrors returns a HTTP error handler that reports the server faults
+// given to it to r before calling errhandler if not nil. The returned handler
+// can be given to the generated server constructors and to the Recover
+// middleware so that the panics and the errors that could not be encoded are
+// reported. See middleware.ReportErrors to report the faults returned by the
+// endpoints.
+//
+// Example:
+//
+//	errhandler = httpmdlwr.ReportErrors(sentry, errhandler)
+//	mux.Use(httpmdlwr.Recover(mux, errhandler))
+func ReportErrors(r middleware.ErrorReporter, errhandler func(context.Context, http.ResponseWriter, error)) func(context.Context, http.ResponseWriter, error) {
+	return func(ctx context.Context, w http.ResponseWriter, err error) {
+		if middleware.IsFault(err) {
+			rep := middleware.NewErrorReport(ctx, err, nil)
+			go r.Report(context.Background(), rep) // nolint: errcheck
+		}
+		if errhandler != nil {
+			errhandler(ctx, w, err)
+		}
+	}
+}
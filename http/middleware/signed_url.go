@@ -0,0 +1,27 @@
+package middleware
+
+import (
+	"net/http"
+	"time"
+
+	goahttp "goa.design/goa/v3/http"
+)
+
+// VerifySignedURL returns a middleware that only serves the requests made to
+// URLs signed with goahttp.SignURL and one of the given secrets that have not
+// expired. Passing multiple secrets makes it possible to rotate them. Other
+// requests receive a 403 Forbidden response. The middleware is typically
+// applied to the file download endpoints of a server:
+//
+//	srv.UseMethod("download", httpmdlwr.VerifySignedURL(secret))
+func VerifySignedURL(secrets ...[]byte) func(http.Handler) http.Handler {
+	return func(h http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if err := goahttp.VerifyURL(r, time.Now(), secrets...); err != nil {
+				http.Error(w, err.Error(), http.StatusForbidden)
+				return
+			}
+			h.ServeHTTP(w, r)
+		})
+	}
+}
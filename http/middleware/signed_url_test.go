@@ -0,0 +1,33 @@
+package middleware_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	goahttp "goa.design/goa/v3/http"
+	httpm "goa.design/goa/v3/http/middleware"
+)
+
+func TestVerifySignedURL(t *testing.T) {
+	secret := []byte("secret")
+	h := httpm.VerifySignedURL(secret)(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {}))
+	signed, err := goahttp.SignURL(secret, "GET", "http://example.com/download?name=a.txt", time.Now().Add(time.Minute))
+	if err != nil {
+		t.Fatal(err)
+	}
+	expired, _ := goahttp.SignURL(secret, "GET", "http://example.com/download?name=a.txt", time.Now().Add(-time.Minute))
+	cases := map[string]int{
+		signed:                                   http.StatusOK,
+		expired:                                  http.StatusForbidden,
+		"http://example.com/download?name=a.txt": http.StatusForbidden,
+	}
+	for u, expected := range cases {
+		w := httptest.NewRecorder()
+		h.ServeHTTP(w, httptest.NewRequest("GET", u, nil))
+		if w.Code != expected {
+			t.Errorf("%s: got status %d, expected %d", u, w.Code, expected)
+		}
+	}
+}
@@ -0,0 +1,31 @@
+package middleware
+
+import (
+	"net/http"
+	"time"
+
+	"goa.design/goa/v3/middleware"
+)
+
+// SLO returns a middleware that records the requests handled by the wrapped
+// handler with the given tracker. Requests that result in a 5xx response are
+// counted as failed. The generated servers of services whose methods define
+// the "slo:target" meta apply the middleware to the corresponding methods in
+// UseSLOs.
+//
+// Example:
+//
+//	slo := &middleware.SLO{Service: "orders", Method: "list", Latency: 300 * time.Millisecond, Target: 0.999}
+//	srv.UseMethod("list", httpmdlwr.SLO(tracker, slo))
+func SLO(t *middleware.SLOTracker, slo *middleware.SLO) func(http.Handler) http.Handler {
+	return func(h http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			start := time.Now()
+			rw := CaptureResponse(w)
+			defer func() {
+				t.Observe(slo, time.Since(start), rw.StatusCode >= 500)
+			}()
+			h.ServeHTTP(rw, r)
+		})
+	}
+}
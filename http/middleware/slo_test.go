@@ -0,0 +1,27 @@
+package middleware_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	httpm "goa.design/goa/v3/http/middleware"
+	"goa.design/goa/v3/middleware"
+)
+
+func TestSLO(t *testing.T) {
+	tracker := middleware.NewSLOTracker(time.Hour)
+	slo := &middleware.SLO{Service: "svc", Method: "list", Target: 0.9}
+	status := http.StatusOK
+	h := httpm.SLO(tracker, slo)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(status)
+	}))
+	for _, status = range []int{http.StatusOK, http.StatusNotFound, http.StatusServiceUnavailable} {
+		h.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("GET", "/", nil))
+	}
+	s := tracker.Status()
+	if len(s) != 1 || s[0].Good != 2 || s[0].Total != 3 {
+		t.Errorf("got status %+v, expected 2 good requests out of 3", s[0])
+	}
+}
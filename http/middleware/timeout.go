@@ -0,0 +1,33 @@
+package middleware
+
+import (
+	"context"
+	"net/http"
+
+	goahttp "goa.design/goa/v3/http"
+)
+
+// Timeout returns a server middleware that sets the deadline of the request
+// context from the X-Request-Timeout or Grpc-Timeout request header if any, see
+// goahttp.RequestTimeout. The deadline only shortens any deadline already set
+// in the context. Clients created with a Doer returned by
+// goahttp.NewTimeoutDoer set the headers from the deadline of the client
+// context so that deadlines propagate across services.
+//
+// Example:
+//
+//	handler = middleware.Timeout()(handler)
+func Timeout() func(http.Handler) http.Handler {
+	return func(h http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			timeout, ok := goahttp.RequestTimeout(r.Header)
+			if !ok {
+				h.ServeHTTP(w, r)
+				return
+			}
+			ctx, cancel := context.WithTimeout(r.Context(), timeout)
+			defer cancel()
+			h.ServeHTTP(w, r.WithContext(ctx))
+		})
+	}
+}
@@ -0,0 +1,55 @@
+package middleware_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	goahttp "goa.design/goa/v3/http"
+	httpm "goa.design/goa/v3/http/middleware"
+)
+
+func TestTimeout(t *testing.T) {
+	cases := map[string]struct {
+		Timeout string
+		Parent  time.Duration
+		// output
+		Expected time.Duration
+	}{
+		"none":          {"", 0, 0},
+		"timeout":       {"2s", 0, 2 * time.Second},
+		"parent-sooner": {"1h", time.Second, time.Second},
+		"parent-later":  {"1s", time.Hour, time.Second},
+	}
+	for k, c := range cases {
+		t.Run(k, func(t *testing.T) {
+			req, _ := http.NewRequest("GET", "/", nil)
+			if c.Timeout != "" {
+				req.Header.Set(goahttp.RequestTimeoutHeader, c.Timeout)
+			}
+			if c.Parent != 0 {
+				ctx, cancel := context.WithTimeout(req.Context(), c.Parent)
+				defer cancel()
+				req = req.WithContext(ctx)
+			}
+			h := new(testHandler)
+			start := time.Now()
+			httpm.Timeout()(h).ServeHTTP(httptest.NewRecorder(), req)
+			deadline, ok := h.Context.Deadline()
+			if c.Expected == 0 {
+				if ok {
+					t.Errorf("got deadline %v, expected none", deadline)
+				}
+				return
+			}
+			if !ok {
+				t.Fatal("got no deadline")
+			}
+			if deadline.Before(start.Add(c.Expected-time.Millisecond)) || deadline.After(time.Now().Add(c.Expected)) {
+				t.Errorf("got timeout %v, expected %v", deadline.Sub(start), c.Expected)
+			}
+		})
+	}
+}
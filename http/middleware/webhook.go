@@ -0,0 +1,350 @@
+package middleware
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"hash"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+type (
+	// WebhookScheme describes how a webhook provider signs its requests.
+	// GitHubWebhook, StripeWebhook and SlackWebhook implement the schemes
+	// of these providers, GoaWebhook the scheme of goahttp.WebhookSender.
+	WebhookScheme struct {
+		// Extract returns the timestamp and the signatures carried by
+		// the request. The timestamp is empty if the scheme does not
+		// sign the time the request was sent.
+		Extract func(r *http.Request) (ts string, sigs []string, err error)
+		// Payload returns the signed content given the timestamp and
+		// the request body.
+		Payload func(ts string, body []byte) []byte
+		// Hash returns the hash used to compute the HMAC.
+		Hash func() hash.Hash
+		// Encode encodes the HMAC into a signature.
+		Encode func(mac []byte) string
+	}
+
+	// WebhookSecretProvider returns the secrets used to verify the
+	// signature of the given request. Returning multiple secrets makes it
+	// possible to rotate them, the request is verified if its signature
+	// matches any of the secrets.
+	WebhookSecretProvider func(r *http.Request) ([][]byte, error)
+
+	// ReplayCache records the webhook deliveries already processed.
+	// Implementations must be safe for concurrent use.
+	ReplayCache interface {
+		// Seen records key for the given duration and returns true if
+		// it was already recorded.
+		Seen(ctx context.Context, key string, ttl time.Duration) (bool, error)
+		// Forget removes key so that the delivery may be processed
+		// again. It is called when the handler fails to process the
+		// delivery so that the provider may redeliver it.
+		Forget(ctx context.Context, key string) error
+	}
+
+	// WebhookOption customizes the VerifyWebhook middleware.
+	WebhookOption func(*webhookOptions)
+
+	// webhookOptions lists the VerifyWebhook middleware options.
+	webhookOptions struct {
+		tolerance time.Duration
+		replayTTL time.Duration
+		replays   ReplayCache
+		optional  bool
+		maxBody   int64
+		now       func() time.Time
+	}
+
+	// MemoryReplayCache is a ReplayCache that keeps the keys in memory.
+	// Expired keys are removed at most once per minute.
+	MemoryReplayCache struct {
+		now func() time.Time
+
+		mu        sync.Mutex
+		keys      map[string]time.Time
+		nextSweep time.Time
+	}
+)
+
+var (
+	// ErrWebhookSignature is the error returned when the signature of a
+	// webhook request is missing or invalid.
+	ErrWebhookSignature = errors.New("invalid webhook signature")
+	// ErrWebhookExpired is the error returned when the timestamp of a
+	// webhook request is outside of the tolerance window.
+	ErrWebhookExpired = errors.New("webhook timestamp outside of tolerance")
+	// ErrWebhookReplayed is the error returned when a webhook delivery is
+	// received more than once.
+	ErrWebhookReplayed = errors.New("webhook delivery already processed")
+)
+
+// replaySweepInterval is the minimum duration between two removals of the
+// expired keys of a MemoryReplayCache.
+const replaySweepInterval = time.Minute
+
+var (
+	// GitHubWebhook verifies the "X-Hub-Signature-256" header of the
+	// GitHub webhooks. GitHub signs neither the delivery time nor the
+	// "X-GitHub-Delivery" header so the tolerance does not apply and
+	// replays are detected using the signature for the duration set with
+	// WithReplayTTL only, older replays are accepted.
+	GitHubWebhook = &WebhookScheme{
+		Extract: func(r *http.Request) (string, []string, error) {
+			sig, ok := strings.CutPrefix(r.Header.Get("X-Hub-Signature-256"), "sha256=")
+			if !ok {
+				return "", nil, ErrWebhookSignature
+			}
+			return "", []string{sig}, nil
+		},
+		Payload: func(_ string, body []byte) []byte { return body },
+		Hash:    sha256.New,
+		Encode:  hex.EncodeToString,
+	}
+
+	// StripeWebhook verifies the "Stripe-Signature" header of the Stripe
+	// webhooks.
+	StripeWebhook = timestampedScheme("Stripe-Signature")
+
+	// GoaWebhook verifies the signature of the requests sent by
+	// goahttp.WebhookSender.
+	GoaWebhook = timestampedScheme("Webhook-Signature")
+
+	// SlackWebhook verifies the "X-Slack-Signature" and
+	// "X-Slack-Request-Timestamp" headers of the Slack requests.
+	SlackWebhook = &WebhookScheme{
+		Extract: func(r *http.Request) (string, []string, error) {
+			sig, ok := strings.CutPrefix(r.Header.Get("X-Slack-Signature"), "v0=")
+			ts := r.Header.Get("X-Slack-Request-Timestamp")
+			if !ok || ts == "" {
+				return "", nil, ErrWebhookSignature
+			}
+			return ts, []string{sig}, nil
+		},
+		Payload: func(ts string, body []byte) []byte { return append([]byte("v0:"+ts+":"), body...) },
+		Hash:    sha256.New,
+		Encode:  hex.EncodeToString,
+	}
+)
+
+// VerifyWebhook returns a middleware that verifies the HMAC signature of the
+// webhook requests received from a provider using the given scheme and the
+// secrets returned by secrets. Requests whose timestamp differs from the
+// current time by more than the tolerance (5 minutes by default) and requests
+// already processed are rejected. Deliveries are identified by their verified
+// signature and recorded in memory unless configured otherwise with
+// WithReplayCache, for twice the tolerance if the scheme signs a timestamp and
+// for the duration set with WithReplayTTL (24 hours by default) otherwise.
+// Replays of deliveries without a timestamp received after that duration are
+// not detected. A delivery is forgotten if the handler responds with a 5xx
+// status code or panics so that the provider may redeliver it.
+// Rejected requests receive a 401 Unauthorized response unless the middleware
+// is configured with WithOptionalWebhookSignature. Handlers check whether a
+// request was verified with WebhookVerified. Requests whose body is larger than
+// 10MB receive a 413 Request Entity Too Large response.
+//
+// Example:
+//
+//	verify := httpmdlwr.VerifyWebhook(httpmdlwr.GitHubWebhook, httpmdlwr.StaticWebhookSecrets([]byte(secret)))
+//	srv.UseMethod("push", verify)
+func VerifyWebhook(scheme *WebhookScheme, secrets WebhookSecretProvider, opts ...WebhookOption) func(http.Handler) http.Handler {
+	o := &webhookOptions{tolerance: 5 * time.Minute, replayTTL: 24 * time.Hour, maxBody: 10 << 20, now: time.Now}
+	for _, opt := range opts {
+		opt(o)
+	}
+	if o.replays == nil {
+		o.replays = NewMemoryReplayCache()
+	}
+	return func(h http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			body, err := io.ReadAll(io.LimitReader(r.Body, o.maxBody+1))
+			if err != nil {
+				http.Error(w, http.StatusText(http.StatusBadRequest), http.StatusBadRequest)
+				return
+			}
+			if int64(len(body)) > o.maxBody {
+				http.Error(w, http.StatusText(http.StatusRequestEntityTooLarge), http.StatusRequestEntityTooLarge)
+				return
+			}
+			r.Body = io.NopCloser(bytes.NewReader(body))
+			key, err := verifyWebhook(r, body, scheme, secrets, o)
+			if err != nil && !o.optional {
+				http.Error(w, err.Error(), http.StatusUnauthorized)
+				return
+			}
+			ctx := context.WithValue(r.Context(), WebhookVerifiedKey, err == nil)
+			if key == "" {
+				h.ServeHTTP(w, r.WithContext(ctx))
+				return
+			}
+			rw := CaptureResponse(w)
+			processed := false
+			defer func() {
+				if !processed {
+					o.replays.Forget(ctx, key) // nolint: errcheck
+				}
+			}()
+			h.ServeHTTP(rw, r.WithContext(ctx))
+			processed = rw.StatusCode < http.StatusInternalServerError
+		})
+	}
+}
+
+// WebhookVerified returns true if the signature of the request was verified by
+// the VerifyWebhook middleware.
+func WebhookVerified(ctx context.Context) bool {
+	verified, _ := ctx.Value(WebhookVerifiedKey).(bool)
+	return verified
+}
+
+// StaticWebhookSecrets returns a secret provider that always returns the given
+// secrets.
+func StaticWebhookSecrets(secrets ...[]byte) WebhookSecretProvider {
+	return func(*http.Request) ([][]byte, error) { return secrets, nil }
+}
+
+// WithWebhookTolerance sets the maximum difference between the timestamp of
+// the requests and the current time.
+func WithWebhookTolerance(d time.Duration) WebhookOption {
+	return func(o *webhookOptions) { o.tolerance = d }
+}
+
+// WithReplayTTL sets the duration the deliveries of schemes that do not sign a
+// timestamp are recorded to detect replays. Deliveries of schemes that sign a
+// timestamp are recorded for twice the tolerance.
+func WithReplayTTL(d time.Duration) WebhookOption {
+	return func(o *webhookOptions) { o.replayTTL = d }
+}
+
+// WithReplayCache sets the cache used to detect the replayed deliveries, e.g.
+// a cache backed by Redis shared by all the server instances.
+func WithReplayCache(c ReplayCache) WebhookOption {
+	return func(o *webhookOptions) { o.replays = c }
+}
+
+// WithOptionalWebhookSignature makes the middleware serve the requests that
+// fail verification, the handlers use WebhookVerified to check whether the
+// request was verified.
+func WithOptionalWebhookSignature() WebhookOption {
+	return func(o *webhookOptions) { o.optional = true }
+}
+
+// NewMemoryReplayCache returns an in-memory ReplayCache.
+func NewMemoryReplayCache() *MemoryReplayCache {
+	return &MemoryReplayCache{now: time.Now, keys: make(map[string]time.Time)}
+}
+
+// Seen implements ReplayCache.
+func (c *MemoryReplayCache) Seen(_ context.Context, key string, ttl time.Duration) (bool, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	now := c.now()
+	if exp, ok := c.keys[key]; ok && now.Before(exp) {
+		return true, nil
+	}
+	if !now.Before(c.nextSweep) {
+		for k, exp := range c.keys {
+			if !now.Before(exp) {
+				delete(c.keys, k)
+			}
+		}
+		c.nextSweep = now.Add(replaySweepInterval)
+	}
+	c.keys[key] = now.Add(ttl)
+	return false, nil
+}
+
+// Forget implements ReplayCache.
+func (c *MemoryReplayCache) Forget(_ context.Context, key string) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	delete(c.keys, key)
+	return nil
+}
+
+// verifyWebhook verifies the signature, the timestamp and the uniqueness of the
+// webhook request r. It returns the key recorded in the replay cache once the
+// request is verified.
+func verifyWebhook(r *http.Request, body []byte, scheme *WebhookScheme, secrets WebhookSecretProvider, o *webhookOptions) (string, error) {
+	ts, sigs, err := scheme.Extract(r)
+	if err != nil {
+		return "", err
+	}
+	if ts != "" {
+		sec, err := strconv.ParseInt(ts, 10, 64)
+		if err != nil {
+			return "", ErrWebhookSignature
+		}
+		if d := o.now().Sub(time.Unix(sec, 0)); d > o.tolerance || d < -o.tolerance {
+			return "", ErrWebhookExpired
+		}
+	}
+	keys, err := secrets(r)
+	if err != nil {
+		return "", err
+	}
+	payload := scheme.Payload(ts, body)
+	var matched string
+	for _, key := range keys {
+		mac := hmac.New(scheme.Hash, key)
+		mac.Write(payload)
+		expected := scheme.Encode(mac.Sum(nil))
+		for _, sig := range sigs {
+			if hmac.Equal([]byte(sig), []byte(expected)) {
+				matched = sig
+			}
+		}
+	}
+	if matched == "" {
+		return "", ErrWebhookSignature
+	}
+	ttl := 2 * o.tolerance
+	if ts == "" {
+		ttl = o.replayTTL
+	}
+	seen, err := o.replays.Seen(r.Context(), matched, ttl)
+	if err != nil {
+		return "", err
+	}
+	if seen {
+		return "", ErrWebhookReplayed
+	}
+	return matched, nil
+}
+
+// timestampedScheme returns the scheme of the signatures of the form
+// "t=TIMESTAMP,v1=SIGNATURE[,v1=SIGNATURE...]" computed over "TIMESTAMP.BODY"
+// stored in the given header.
+func timestampedScheme(header string) *WebhookScheme {
+	return &WebhookScheme{
+		Extract: func(r *http.Request) (string, []string, error) {
+			var ts string
+			var sigs []string
+			for _, part := range strings.Split(r.Header.Get(header), ",") {
+				k, v, _ := strings.Cut(strings.TrimSpace(part), "=")
+				switch k {
+				case "t":
+					ts = v
+				case "v1":
+					sigs = append(sigs, v)
+				}
+			}
+			if ts == "" || len(sigs) == 0 {
+				return "", nil, ErrWebhookSignature
+			}
+			return ts, sigs, nil
+		},
+		Payload: func(ts string, body []byte) []byte { return append([]byte(ts+"."), body...) },
+		Hash:    sha256.New,
+		Encode:  hex.EncodeToString,
+	}
+}
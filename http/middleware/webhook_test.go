@@ -0,0 +1,172 @@
+package middleware_test
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	goahttp "goa.design/goa/v3/http"
+	httpm "goa.design/goa/v3/http/middleware"
+)
+
+func TestVerifyWebhook(t *testing.T) {
+	secret := []byte("secret")
+	body := `{"event":"paid"}`
+	now := time.Now()
+	hexMAC := func(payload string) string {
+		mac := hmac.New(sha256.New, secret)
+		mac.Write([]byte(payload))
+		return hex.EncodeToString(mac.Sum(nil))
+	}
+	ts := strconv.FormatInt(now.Unix(), 10)
+	old := strconv.FormatInt(now.Add(-time.Hour).Unix(), 10)
+
+	cases := []struct {
+		Name     string
+		Scheme   *httpm.WebhookScheme
+		Headers  map[string]string
+		Expected int
+	}{
+		{"goa", httpm.GoaWebhook, map[string]string{"Webhook-Signature": goahttp.SignWebhook(secret, now, []byte(body))}, http.StatusOK},
+		{"stripe-rotated", httpm.StripeWebhook, map[string]string{"Stripe-Signature": "t=" + ts + ",v1=deadbeef,v1=" + hexMAC(ts+"."+body)}, http.StatusOK},
+		{"stripe-expired", httpm.StripeWebhook, map[string]string{"Stripe-Signature": "t=" + old + ",v1=" + hexMAC(old+"."+body)}, http.StatusUnauthorized},
+		{"stripe-invalid", httpm.StripeWebhook, map[string]string{"Stripe-Signature": "t=" + ts + ",v1=" + hexMAC(ts+".other")}, http.StatusUnauthorized},
+		{"github", httpm.GitHubWebhook, map[string]string{"X-Hub-Signature-256": "sha256=" + hexMAC(body), "X-GitHub-Delivery": "d1"}, http.StatusOK},
+		{"github-missing", httpm.GitHubWebhook, nil, http.StatusUnauthorized},
+		{"slack", httpm.SlackWebhook, map[string]string{"X-Slack-Signature": "v0=" + hexMAC("v0:"+ts+":"+body), "X-Slack-Request-Timestamp": ts}, http.StatusOK},
+	}
+	for _, c := range cases {
+		t.Run(c.Name, func(t *testing.T) {
+			var received string
+			h := httpm.VerifyWebhook(c.Scheme, httpm.StaticWebhookSecrets([]byte("old"), secret))(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				b, _ := io.ReadAll(r.Body)
+				received = string(b)
+				if !httpm.WebhookVerified(r.Context()) {
+					t.Error("expected the request to be verified")
+				}
+			}))
+			do := func() int {
+				req := httptest.NewRequest("POST", "/hooks", strings.NewReader(body))
+				for k, v := range c.Headers {
+					req.Header.Set(k, v)
+				}
+				w := httptest.NewRecorder()
+				h.ServeHTTP(w, req)
+				return w.Code
+			}
+			if code := do(); code != c.Expected {
+				t.Fatalf("got status %d, expected %d", code, c.Expected)
+			}
+			if c.Expected != http.StatusOK {
+				return
+			}
+			if received != body {
+				t.Errorf("got body %q, expected %q", received, body)
+			}
+			if code := do(); code != http.StatusUnauthorized {
+				t.Errorf("replay: got status %d, expected 401", code)
+			}
+		})
+	}
+}
+
+func TestVerifyWebhookReplay(t *testing.T) {
+	secret := []byte("secret")
+	body := `{"event":"push"}`
+	mac := hmac.New(sha256.New, secret)
+	mac.Write([]byte(body))
+	sig := hex.EncodeToString(mac.Sum(nil))
+	replays := &recordingReplayCache{ReplayCache: httpm.NewMemoryReplayCache()}
+	h := httpm.VerifyWebhook(httpm.GitHubWebhook, httpm.StaticWebhookSecrets(secret), httpm.WithReplayCache(replays), httpm.WithReplayTTL(time.Hour))(http.HandlerFunc(func(http.ResponseWriter, *http.Request) {}))
+	do := func(delivery string) int {
+		req := httptest.NewRequest("POST", "/hooks", strings.NewReader(body))
+		req.Header.Set("X-Hub-Signature-256", "sha256="+sig)
+		req.Header.Set("X-GitHub-Delivery", delivery)
+		w := httptest.NewRecorder()
+		h.ServeHTTP(w, req)
+		return w.Code
+	}
+	if code := do("d1"); code != http.StatusOK {
+		t.Fatalf("got status %d, expected 200", code)
+	}
+	if code := do("d2"); code != http.StatusUnauthorized {
+		t.Errorf("replay with new delivery ID: got status %d, expected 401", code)
+	}
+	if replays.key != sig {
+		t.Errorf("got replay key %q, expected the signature %q", replays.key, sig)
+	}
+	if replays.ttl != time.Hour {
+		t.Errorf("got replay TTL %s, expected 1h", replays.ttl)
+	}
+}
+
+func TestVerifyWebhookOptional(t *testing.T) {
+	var verified bool
+	h := httpm.VerifyWebhook(httpm.GoaWebhook, httpm.StaticWebhookSecrets([]byte("secret")), httpm.WithOptionalWebhookSignature())(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		verified = httpm.WebhookVerified(r.Context())
+	}))
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, httptest.NewRequest("POST", "/hooks", strings.NewReader("{}")))
+	if w.Code != http.StatusOK || verified {
+		t.Errorf("got status %d and verified %v, expected 200 and false", w.Code, verified)
+	}
+}
+
+func TestVerifyWebhookRedelivery(t *testing.T) {
+	secret := []byte("secret")
+	body := `{"event":"paid"}`
+	status := http.StatusInternalServerError
+	h := httpm.VerifyWebhook(httpm.GoaWebhook, httpm.StaticWebhookSecrets(secret))(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(status)
+	}))
+	sig := goahttp.SignWebhook(secret, time.Now(), []byte(body))
+	do := func() int {
+		req := httptest.NewRequest("POST", "/hooks", strings.NewReader(body))
+		req.Header.Set("Webhook-Signature", sig)
+		w := httptest.NewRecorder()
+		h.ServeHTTP(w, req)
+		return w.Code
+	}
+	if code := do(); code != http.StatusInternalServerError {
+		t.Fatalf("got status %d, expected 500", code)
+	}
+	status = http.StatusOK
+	if code := do(); code != http.StatusOK {
+		t.Fatalf("redelivery: got status %d, expected 200", code)
+	}
+	if code := do(); code != http.StatusUnauthorized {
+		t.Errorf("replay: got status %d, expected 401", code)
+	}
+}
+
+func TestVerifyWebhookTooLarge(t *testing.T) {
+	var called bool
+	h := httpm.VerifyWebhook(httpm.GoaWebhook, httpm.StaticWebhookSecrets([]byte("secret")), httpm.WithOptionalWebhookSignature())(http.HandlerFunc(func(http.ResponseWriter, *http.Request) {
+		called = true
+	}))
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, httptest.NewRequest("POST", "/hooks", strings.NewReader(strings.Repeat("a", 10<<20+1))))
+	if w.Code != http.StatusRequestEntityTooLarge || called {
+		t.Errorf("got status %d and called %v, expected 413 and false", w.Code, called)
+	}
+}
+
+// recordingReplayCache records the last key and TTL passed to Seen.
+type recordingReplayCache struct {
+	httpm.ReplayCache
+	key string
+	ttl time.Duration
+}
+
+func (c *recordingReplayCache) Seen(ctx context.Context, key string, ttl time.Duration) (bool, error) {
+	c.key, c.ttl = key, ttl
+	return c.ReplayCache.Seen(ctx, key, ttl)
+}
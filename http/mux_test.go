@@ -1,6 +1,8 @@
 package http
 
 import (
+	"bytes"
+	"context"
 	"fmt"
 	"net/http"
 	"net/http/httptest"
@@ -128,3 +130,31 @@ func TestVars(t *testing.T) {
 		})
 	}
 }
+
+func BenchmarkMuxRequest(b *testing.B) {
+	type payload struct {
+		Name string `json:"name"`
+	}
+	mux := NewMuxer()
+	mux.Handle("POST", "/accounts/{id}", func(w http.ResponseWriter, r *http.Request) {
+		var (
+			p      payload
+			params = mux.Vars(r)
+			qp     = r.URL.Query()
+		)
+		if err := RequestDecoder(r).Decode(&p); err != nil {
+			b.Fatal(err)
+		}
+		ctx := context.WithValue(r.Context(), AcceptTypeKey, r.Header.Get("Accept"))
+		res := map[string]string{"id": params["id"], "name": p.Name, "view": qp.Get("view")}
+		if err := ResponseEncoder(ctx, w).Encode(res); err != nil {
+			b.Fatal(err)
+		}
+	})
+	body := []byte(`{"name":"joe"}`)
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		r := httptest.NewRequest("POST", "/accounts/42?view=full&limit=10", bytes.NewReader(body))
+		mux.ServeHTTP(httptest.NewRecorder(), r)
+	}
+}
@@ -0,0 +1,101 @@
+package http
+
+import (
+	"bytes"
+	"encoding/json"
+	"sort"
+)
+
+type (
+	// presenceDecoder is a decoder that records the names of the top level
+	// fields of the decoded JSON objects.
+	presenceDecoder struct {
+		*json.Decoder
+		present *[]string
+		strict  bool
+	}
+
+	// nullFields is a value whose JSON encoding is the encoding of body
+	// with the fields listed in fields that body omits encoded as null.
+	nullFields struct {
+		body   any
+		fields []string
+	}
+)
+
+// NewPresenceDecoder returns a decoder that decodes the JSON values with d and
+// stores the names of the top level fields of the decoded JSON objects in
+// present. The generated servers use it for the request bodies of the endpoints
+// defined with the FieldPresence DSL so that services can tell the fields set
+// to null from the absent fields. It returns d if d is not a JSON decoder.
+func NewPresenceDecoder(d Decoder, present *[]string) Decoder {
+	jd, ok := d.(*json.Decoder)
+	if !ok {
+		return d
+	}
+	return &presenceDecoder{Decoder: jd, present: present}
+}
+
+// WithNullFields returns a value whose JSON encoding is the encoding of body
+// where the fields listed in fields that body omits are set to null. The
+// generated clients use it to encode the request bodies of the endpoints
+// defined with the FieldPresence DSL.
+func WithNullFields(body any, fields []string) any {
+	if len(fields) == 0 {
+		return body
+	}
+	return &nullFields{body: body, fields: fields}
+}
+
+// DisallowUnknownFields causes the decoder to return an error when the
+// destination is a struct and the input contains object keys which do not
+// match any non-ignored, exported fields in the destination.
+func (d *presenceDecoder) DisallowUnknownFields() {
+	d.strict = true
+}
+
+// Decode decodes v and records the names of the fields of the JSON object.
+func (d *presenceDecoder) Decode(v any) error {
+	var raw json.RawMessage
+	if err := d.Decoder.Decode(&raw); err != nil {
+		return err
+	}
+	dec := json.NewDecoder(bytes.NewReader(raw))
+	if d.strict {
+		dec.DisallowUnknownFields()
+	}
+	if err := dec.Decode(v); err != nil {
+		return err
+	}
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(raw, &fields); err != nil || fields == nil {
+		// Not an object.
+		return nil
+	}
+	names := make([]string, 0, len(fields))
+	for name := range fields {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	*d.present = names
+	return nil
+}
+
+// MarshalJSON encodes the body and adds the missing null fields.
+func (n *nullFields) MarshalJSON() ([]byte, error) {
+	b, err := json.Marshal(n.body)
+	if err != nil {
+		return nil, err
+	}
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(b, &fields); err != nil || fields == nil {
+		// Not an object, nothing to add.
+		return b, nil
+	}
+	for _, f := range n.fields {
+		if _, ok := fields[f]; !ok {
+			fields[f] = json.RawMessage("null")
+		}
+	}
+	return json.Marshal(fields)
+}
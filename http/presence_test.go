@@ -0,0 +1,101 @@
+package http
+
+import (
+	"encoding/json"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestPresenceDecoder(t *testing.T) {
+	type body struct {
+		Name    *string  `json:"name"`
+		Age     *int     `json:"age"`
+		Present []string `json:"-"`
+	}
+	cases := []struct {
+		Name     string
+		Body     string
+		Strict   bool
+		Expected []string
+		Error    string
+	}{
+		{"empty", `{}`, false, []string{}, ""},
+		{"null-field", `{"name":null}`, false, []string{"name"}, ""},
+		{"sorted", `{"name":"a","age":1}`, false, []string{"age", "name"}, ""},
+		{"null", `null`, false, nil, ""},
+		{"unknown", `{"other":1}`, false, []string{"other"}, ""},
+		{"strict", `{"other":1}`, true, nil, `unknown field "other"`},
+	}
+	for _, c := range cases {
+		t.Run(c.Name, func(t *testing.T) {
+			var b body
+			d := NewPresenceDecoder(json.NewDecoder(strings.NewReader(c.Body)), &b.Present)
+			var err error
+			if c.Strict {
+				err = DecodeStrict(d, &b)
+			} else {
+				err = d.Decode(&b)
+			}
+			if c.Error != "" {
+				if err == nil || !strings.Contains(err.Error(), c.Error) {
+					t.Errorf("got error %v, expected %q", err, c.Error)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("got error %v, expected none", err)
+			}
+			if !reflect.DeepEqual(b.Present, c.Expected) {
+				t.Errorf("got present fields %#v, expected %#v", b.Present, c.Expected)
+			}
+		})
+	}
+}
+
+func TestPresenceDecoderCollectUnknown(t *testing.T) {
+	var b struct {
+		Name    *string        `json:"name"`
+		Extra   map[string]any `json:"-"`
+		Present []string       `json:"-"`
+	}
+	d := NewPresenceDecoder(json.NewDecoder(strings.NewReader(`{"name":null,"other":1}`)), &b.Present)
+	if err := DecodeCollectUnknown(d, &b, []string{"name"}, &b.Extra); err != nil {
+		t.Fatalf("got error %v, expected none", err)
+	}
+	if !reflect.DeepEqual(b.Present, []string{"name", "other"}) {
+		t.Errorf("got present fields %v, expected [name other]", b.Present)
+	}
+	if len(b.Extra) != 1 || b.Extra["other"] != 1.0 {
+		t.Errorf("got unknown fields %v, expected map[other:1]", b.Extra)
+	}
+}
+
+func TestWithNullFields(t *testing.T) {
+	type body struct {
+		Name *string `json:"name,omitempty"`
+		Age  *int    `json:"age,omitempty"`
+	}
+	name := "a"
+	cases := []struct {
+		Name     string
+		Body     any
+		Fields   []string
+		Expected string
+	}{
+		{"no-fields", &body{Name: &name}, nil, `{"name":"a"}`},
+		{"null-field", &body{Name: &name}, []string{"name", "age"}, `{"age":null,"name":"a"}`},
+		{"not-object", []string{"a"}, []string{"name"}, `["a"]`},
+	}
+	for _, c := range cases {
+		t.Run(c.Name, func(t *testing.T) {
+			b, err := json.Marshal(WithNullFields(c.Body, c.Fields))
+			if err != nil {
+				t.Fatalf("got error %v, expected none", err)
+			}
+			if string(b) != c.Expected {
+				t.Errorf("got %s, expected %s", b, c.Expected)
+			}
+		})
+	}
+}
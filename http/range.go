@@ -0,0 +1,253 @@
+package http
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+type (
+	// rangeWriter is a http.ResponseWriter that turns the 200 OK responses
+	// into 206 Partial Content responses when the request carries a
+	// satisfiable Range header.
+	rangeWriter struct {
+		http.ResponseWriter
+		r    *http.Request
+		body io.ReadSeeker
+		size int64
+		// start and length describe the range being served, length is
+		// negative if the whole body is served.
+		start, length int64
+	}
+
+	// resumeDoer is a Doer that resumes the interrupted downloads.
+	resumeDoer struct {
+		Doer
+		attempts int
+	}
+
+	// resumingBody is a response body that resumes the download when reading
+	// fails.
+	resumingBody struct {
+		doer      Doer
+		req       *http.Request
+		body      io.ReadCloser
+		validator string
+		read      int64
+		attempts  int
+	}
+
+	// errReader is a reader that always returns err.
+	errReader struct{ err error }
+)
+
+// NewRangeWriter returns a response writer that serves the range of body
+// requested with the Range header of r. The generated servers use it for the
+// endpoints that skip the response body encoding (see SkipResponseBodyEncodeDecode)
+// when the body returned by the service implements io.ReadSeeker, e.g. an
+// *os.File. The response carries an "Accept-Ranges: bytes" header. Requests
+// with a single satisfiable range whose If-Range header, if any, matches the
+// ETag or Last-Modified response header receive a 206 Partial Content response
+// with the corresponding Content-Range header, requests with an unsatisfiable
+// range receive a 416 Range Not Satisfiable response. The response body must
+// be copied from the reader returned by RangeBody.
+func NewRangeWriter(w http.ResponseWriter, r *http.Request, body io.ReadSeeker) http.ResponseWriter {
+	size, err := body.Seek(0, io.SeekEnd)
+	if err != nil {
+		return w
+	}
+	if _, err := body.Seek(0, io.SeekStart); err != nil {
+		return w
+	}
+	w.Header().Set("Accept-Ranges", "bytes")
+	return &rangeWriter{ResponseWriter: w, r: r, body: body, size: size, length: -1}
+}
+
+// RangeBody returns the reader of the response body served with w given the
+// reader of the whole body. It returns body unless w was created with
+// NewRangeWriter and serves a partial response.
+func RangeBody(w http.ResponseWriter, body io.Reader) io.Reader {
+	rw, ok := w.(*rangeWriter)
+	if !ok || rw.length < 0 {
+		return body
+	}
+	if _, err := rw.body.Seek(rw.start, io.SeekStart); err != nil {
+		return errReader{err}
+	}
+	return io.LimitReader(rw.body, rw.length)
+}
+
+// NewResumeDoer returns a Doer that resumes the GET downloads interrupted by a
+// network error up to attempts times. When reading the response body fails the
+// request is sent again with a Range header starting at the number of bytes
+// already read and an If-Range header set to the ETag or Last-Modified header
+// of the response. Reading continues transparently if the server responds
+// with the requested range, the original error is returned otherwise.
+func NewResumeDoer(d Doer, attempts int) Doer {
+	return &resumeDoer{Doer: d, attempts: attempts}
+}
+
+// WriteHeader negotiates the range when the status code is 200 OK.
+func (w *rangeWriter) WriteHeader(code int) {
+	if code != http.StatusOK {
+		w.ResponseWriter.WriteHeader(code)
+		return
+	}
+	rng := w.r.Header.Get("Range")
+	if rng == "" || !w.ifRange() {
+		w.ResponseWriter.WriteHeader(code)
+		return
+	}
+	start, length, ok := parseRange(rng, w.size)
+	if !ok {
+		// Multiple or malformed ranges, serve the whole body.
+		w.ResponseWriter.WriteHeader(code)
+		return
+	}
+	h := w.Header()
+	if length == 0 {
+		h.Set("Content-Range", fmt.Sprintf("bytes */%d", w.size))
+		h.Set("Content-Length", "0")
+		w.start, w.length = 0, 0
+		w.ResponseWriter.WriteHeader(http.StatusRequestedRangeNotSatisfiable)
+		return
+	}
+	w.start, w.length = start, length
+	h.Set("Content-Range", fmt.Sprintf("bytes %d-%d/%d", start, start+length-1, w.size))
+	h.Set("Content-Length", strconv.FormatInt(length, 10))
+	w.ResponseWriter.WriteHeader(http.StatusPartialContent)
+}
+
+// Flush implements http.Flusher.
+func (w *rangeWriter) Flush() {
+	if f, ok := w.ResponseWriter.(http.Flusher); ok {
+		f.Flush()
+	}
+}
+
+// Unwrap returns the underlying response writer.
+func (w *rangeWriter) Unwrap() http.ResponseWriter {
+	return w.ResponseWriter
+}
+
+// ifRange returns true if the request has no If-Range header or if the header
+// matches the response validators.
+func (w *rangeWriter) ifRange() bool {
+	ir := w.r.Header.Get("If-Range")
+	if ir == "" {
+		return true
+	}
+	if strings.HasPrefix(ir, `"`) {
+		etag := w.Header().Get("ETag")
+		return etag != "" && etag == ir
+	}
+	lm := w.Header().Get("Last-Modified")
+	return lm != "" && lm == ir
+}
+
+// parseRange parses a Range header value with a single byte range given the
+// size of the body. It returns a zero length if the range is not satisfiable
+// and false if the header is malformed or lists multiple ranges.
+func parseRange(s string, size int64) (start, length int64, ok bool) {
+	spec, found := strings.CutPrefix(s, "bytes=")
+	if !found || strings.Contains(spec, ",") {
+		return 0, 0, false
+	}
+	first, last, found := strings.Cut(strings.TrimSpace(spec), "-")
+	if !found {
+		return 0, 0, false
+	}
+	if first == "" {
+		// Suffix range: the last N bytes.
+		n, err := strconv.ParseInt(last, 10, 64)
+		if err != nil || n < 0 {
+			return 0, 0, false
+		}
+		if n > size {
+			n = size
+		}
+		return size - n, n, true
+	}
+	start, err := strconv.ParseInt(first, 10, 64)
+	if err != nil || start < 0 {
+		return 0, 0, false
+	}
+	if start >= size {
+		return 0, 0, true
+	}
+	end := size - 1
+	if last != "" {
+		e, err := strconv.ParseInt(last, 10, 64)
+		if err != nil || e < start {
+			return 0, 0, false
+		}
+		if e < end {
+			end = e
+		}
+	}
+	return start, end - start + 1, true
+}
+
+// Do sends the request and wraps the body of the successful GET responses so
+// that interrupted downloads are resumed.
+func (d *resumeDoer) Do(req *http.Request) (*http.Response, error) {
+	resp, err := d.Doer.Do(req)
+	if err != nil || req.Method != http.MethodGet || resp.StatusCode != http.StatusOK || resp.Header.Get("Accept-Ranges") != "bytes" {
+		return resp, err
+	}
+	validator := resp.Header.Get("ETag")
+	if validator == "" || strings.HasPrefix(validator, "W/") {
+		validator = resp.Header.Get("Last-Modified")
+	}
+	resp.Body = &resumingBody{doer: d.Doer, req: req, body: resp.Body, validator: validator, attempts: d.attempts}
+	return resp, nil
+}
+
+// Read reads from the response body and resumes the download if reading
+// fails.
+func (b *resumingBody) Read(p []byte) (int, error) {
+	n, err := b.body.Read(p)
+	b.read += int64(n)
+	if err == nil || errors.Is(err, io.EOF) || b.attempts <= 0 || b.req.Context().Err() != nil {
+		return n, err
+	}
+	if rerr := b.resume(); rerr != nil {
+		return n, err
+	}
+	if n > 0 {
+		return n, nil
+	}
+	return b.Read(p)
+}
+
+// Close closes the response body.
+func (b *resumingBody) Close() error {
+	return b.body.Close()
+}
+
+// resume requests the remainder of the body.
+func (b *resumingBody) resume() error {
+	b.attempts--
+	req := b.req.Clone(b.req.Context())
+	req.Header.Set("Range", fmt.Sprintf("bytes=%d-", b.read))
+	if b.validator != "" {
+		req.Header.Set("If-Range", b.validator)
+	}
+	resp, err := b.doer.Do(req)
+	if err != nil {
+		return err
+	}
+	if resp.StatusCode != http.StatusPartialContent || !strings.HasPrefix(resp.Header.Get("Content-Range"), fmt.Sprintf("bytes %d-", b.read)) {
+		resp.Body.Close()
+		return fmt.Errorf("cannot resume download: unexpected status %d", resp.StatusCode)
+	}
+	b.body.Close()
+	b.body = resp.Body
+	return nil
+}
+
+// Read returns the reader error.
+func (r errReader) Read([]byte) (int, error) { return 0, r.err }
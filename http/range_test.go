@@ -0,0 +1,112 @@
+package http
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+const rangeContent = "0123456789"
+
+func TestRangeWriter(t *testing.T) {
+	cases := []struct {
+		Name          string
+		Range         string
+		IfRange       string
+		Status        int
+		Body          string
+		ContentRange  string
+		ContentLength string
+	}{
+		{"no-range", "", "", http.StatusOK, rangeContent, "", ""},
+		{"range", "bytes=2-4", "", http.StatusPartialContent, "234", "bytes 2-4/10", "3"},
+		{"open-ended", "bytes=7-", "", http.StatusPartialContent, "789", "bytes 7-9/10", "3"},
+		{"suffix", "bytes=-2", "", http.StatusPartialContent, "89", "bytes 8-9/10", "2"},
+		{"end-past-size", "bytes=8-20", "", http.StatusPartialContent, "89", "bytes 8-9/10", "2"},
+		{"unsatisfiable", "bytes=10-", "", http.StatusRequestedRangeNotSatisfiable, "", "bytes */10", "0"},
+		{"multiple", "bytes=0-1,4-5", "", http.StatusOK, rangeContent, "", ""},
+		{"malformed", "items=0-1", "", http.StatusOK, rangeContent, "", ""},
+		{"if-range-match", "bytes=0-1", `"v1"`, http.StatusPartialContent, "01", "bytes 0-1/10", "2"},
+		{"if-range-mismatch", "bytes=0-1", `"v0"`, http.StatusOK, rangeContent, "", ""},
+	}
+	for _, c := range cases {
+		t.Run(c.Name, func(t *testing.T) {
+			r := httptest.NewRequest("GET", "/", nil)
+			if c.Range != "" {
+				r.Header.Set("Range", c.Range)
+			}
+			if c.IfRange != "" {
+				r.Header.Set("If-Range", c.IfRange)
+			}
+			rec := httptest.NewRecorder()
+			serveRange(rec, r, strings.NewReader(rangeContent))
+			if rec.Code != c.Status {
+				t.Errorf("got status %d, expected %d", rec.Code, c.Status)
+			}
+			if rec.Body.String() != c.Body {
+				t.Errorf("got body %q, expected %q", rec.Body.String(), c.Body)
+			}
+			if cr := rec.Header().Get("Content-Range"); cr != c.ContentRange {
+				t.Errorf("got Content-Range %q, expected %q", cr, c.ContentRange)
+			}
+			if cl := rec.Header().Get("Content-Length"); cl != c.ContentLength {
+				t.Errorf("got Content-Length %q, expected %q", cl, c.ContentLength)
+			}
+			if ar := rec.Header().Get("Accept-Ranges"); ar != "bytes" {
+				t.Errorf("got Accept-Ranges %q, expected bytes", ar)
+			}
+		})
+	}
+}
+
+func TestResumeDoer(t *testing.T) {
+	var requests []string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests = append(requests, r.Header.Get("Range")+"|"+r.Header.Get("If-Range"))
+		if r.Header.Get("Range") == "" {
+			// Send half of the body then drop the connection.
+			conn, buf, _ := w.(http.Hijacker).Hijack()
+			fmt.Fprintf(buf, "HTTP/1.1 200 OK\r\nAccept-Ranges: bytes\r\nETag: \"v1\"\r\nContent-Length: 10\r\n\r\n01234")
+			buf.Flush()
+			conn.Close()
+			return
+		}
+		serveRange(w, r, strings.NewReader(rangeContent))
+	}))
+	defer srv.Close()
+
+	req, err := http.NewRequest("GET", srv.URL, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp, err := NewResumeDoer(http.DefaultClient, 1).Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(body) != rangeContent {
+		t.Errorf("got body %q, expected %q", body, rangeContent)
+	}
+	if len(requests) != 2 || requests[1] != `bytes=5-|"v1"` {
+		t.Errorf("got requests %v, expected the download to resume at byte 5", requests)
+	}
+}
+
+// serveRange mimics the generated handlers of the endpoints that skip the
+// response body encoding.
+func serveRange(w http.ResponseWriter, r *http.Request, body io.ReadSeeker) {
+	w = NewRangeWriter(w, r, body)
+	buf := bufio.NewReader(body)
+	buf.Peek(1) // nolint: errcheck
+	w.Header().Set("ETag", `"v1"`)
+	w.WriteHeader(http.StatusOK)
+	io.Copy(w, RangeBody(w, buf)) // nolint: errcheck
+}
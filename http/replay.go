@@ -0,0 +1,141 @@
+package http
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+)
+
+type (
+	// RecordedRequest is a request captured by the Record middleware in a
+	// format that can be replayed with Replay.
+	RecordedRequest struct {
+		// Time is the time the request was received.
+		Time time.Time `json:"time"`
+		// Method is the request HTTP method.
+		Method string `json:"method"`
+		// URI is the request path and query string.
+		URI string `json:"uri"`
+		// Header contains the request headers.
+		Header http.Header `json:"header,omitempty"`
+		// Body is the request body.
+		Body []byte `json:"body,omitempty"`
+		// Status is the status code of the recorded response.
+		Status int `json:"status"`
+		// ResponseBody is the body of the recorded response, nil if it
+		// was larger than the recorder maximum body size.
+		ResponseBody []byte `json:"response_body,omitempty"`
+	}
+
+	// RequestRecorder writes recorded requests to a writer as JSON lines.
+	// RequestRecorder is safe for concurrent use.
+	RequestRecorder struct {
+		mu  sync.Mutex
+		enc *json.Encoder
+	}
+
+	// ReplayFunc is called by Replay with each recorded request and the
+	// response to the replayed request or the error returned by the doer.
+	// The response body is closed once the function returns. Replay stops
+	// and returns the error if the function returns one.
+	ReplayFunc func(rec *RecordedRequest, resp *http.Response, err error) error
+)
+
+// NewRequestRecorder returns a recorder that writes the recorded requests to
+// w, one JSON object per line.
+func NewRequestRecorder(w io.Writer) *RequestRecorder {
+	return &RequestRecorder{enc: json.NewEncoder(w)}
+}
+
+// Record writes the recorded request.
+func (r *RequestRecorder) Record(rec *RecordedRequest) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.enc.Encode(rec)
+}
+
+// ReadRecordedRequests reads the requests written by a RequestRecorder in the
+// order they were recorded.
+func ReadRecordedRequests(r io.Reader) ([]*RecordedRequest, error) {
+	var (
+		recs []*RecordedRequest
+		s    = bufio.NewScanner(r)
+	)
+	s.Buffer(nil, 64<<20)
+	for line := 1; s.Scan(); line++ {
+		if len(bytes.TrimSpace(s.Bytes())) == 0 {
+			continue
+		}
+		var rec RecordedRequest
+		if err := json.Unmarshal(s.Bytes(), &rec); err != nil {
+			return nil, fmt.Errorf("invalid recorded request on line %d: %w", line, err)
+		}
+		recs = append(recs, &rec)
+	}
+	return recs, s.Err()
+}
+
+// Replay sends the recorded requests to the given target one at a time and in
+// order using doer, typically the doer given to the generated client of the
+// service, so that playback is deterministic. The scheme and host of the
+// requests are the ones of target and their path is the path of target
+// followed by the recorded path. fn is called with each recorded request and
+// the corresponding response, making it possible to compare the responses of
+// different environments.
+//
+// Example:
+//
+//	f, _ := os.Open("requests.jsonl")
+//	recs, err := goahttp.ReadRecordedRequests(f)
+//	if err != nil {
+//	    return err
+//	}
+//	staging, _ := url.Parse("https://staging.example.com")
+//	return goahttp.Replay(ctx, recs, staging, http.DefaultClient, compare)
+func Replay(ctx context.Context, recs []*RecordedRequest, target *url.URL, doer Doer, fn ReplayFunc) error {
+	for _, rec := range recs {
+		req, err := rec.Request(ctx, target)
+		if err != nil {
+			return err
+		}
+		resp, err := doer.Do(req)
+		ferr := fn(rec, resp, err)
+		if resp != nil {
+			io.Copy(io.Discard, resp.Body) // nolint: errcheck
+			resp.Body.Close()
+		}
+		if ferr != nil {
+			return ferr
+		}
+	}
+	return nil
+}
+
+// Request builds the HTTP request that replays rec against target.
+func (rec *RecordedRequest) Request(ctx context.Context, target *url.URL) (*http.Request, error) {
+	u, err := url.Parse(rec.URI)
+	if err != nil {
+		return nil, fmt.Errorf("invalid recorded request URI %q: %w", rec.URI, err)
+	}
+	u.Scheme, u.Host = target.Scheme, target.Host
+	if p := strings.TrimSuffix(target.Path, "/"); p != "" {
+		u.Path = p + u.Path
+		u.RawPath = ""
+	}
+	req, err := http.NewRequestWithContext(ctx, rec.Method, u.String(), bytes.NewReader(rec.Body))
+	if err != nil {
+		return nil, err
+	}
+	for k, v := range rec.Header {
+		req.Header[k] = append([]string(nil), v...)
+	}
+	return req, nil
+}
@@ -0,0 +1,59 @@
+package http
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+)
+
+func TestReplay(t *testing.T) {
+	var buf bytes.Buffer
+	rec := NewRequestRecorder(&buf)
+	recs := []*RecordedRequest{
+		{Method: "POST", URI: "/orders?dry=true", Header: http.Header{"X-Test": {"1"}}, Body: []byte(`{"id":1}`), Status: 201},
+		{Method: "GET", URI: "/orders/1", Status: 200},
+	}
+	for _, r := range recs {
+		if err := rec.Record(r); err != nil {
+			t.Fatal(err)
+		}
+	}
+	read, err := ReadRecordedRequests(&buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(read) != 2 {
+		t.Fatalf("got %d recorded requests, expected 2", len(read))
+	}
+
+	var got []string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		b, _ := io.ReadAll(r.Body)
+		got = append(got, r.Method+" "+r.URL.RequestURI()+" "+r.Header.Get("X-Test")+" "+string(b))
+		w.WriteHeader(http.StatusCreated)
+	}))
+	defer srv.Close()
+	target, _ := url.Parse(srv.URL + "/v2/")
+	var statuses []int
+	err = Replay(context.Background(), read, target, http.DefaultClient, func(rec *RecordedRequest, resp *http.Response, err error) error {
+		if err != nil {
+			return err
+		}
+		statuses = append(statuses, resp.StatusCode)
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := []string{`POST /v2/orders?dry=true 1 {"id":1}`, "GET /v2/orders/1  "}
+	if len(got) != 2 || got[0] != expected[0] || got[1] != expected[1] {
+		t.Errorf("got %q, expected %q", got, expected)
+	}
+	if len(statuses) != 2 || statuses[0] != 201 || statuses[1] != 201 {
+		t.Errorf("got statuses %v", statuses)
+	}
+}
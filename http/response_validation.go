@@ -0,0 +1,84 @@
+package http
+
+import (
+	"context"
+	"errors"
+	"net/http"
+
+	goa "goa.design/goa/v3/pkg"
+)
+
+type (
+	// ContractError is the error returned by the generated clients when a
+	// response is properly received and decoded but does not honor the
+	// design, e.g. a required field is missing or a value violates the
+	// design validations. It makes it possible to tell the servers that
+	// break the API contract apart from transport and service errors.
+	ContractError struct {
+		*ClientError
+	}
+
+	// lenientDoer is a Doer that disables response validation.
+	lenientDoer struct {
+		Doer
+	}
+
+	// responseValidationKey is the context key used to store whether the
+	// responses are validated.
+	responseValidationKey struct{}
+)
+
+// WithResponseValidation returns a copy of ctx that controls whether the
+// generated clients reject the responses that violate the design validations.
+// Responses are validated by default. Responses that lack required fields or
+// whose values cannot be decoded are always rejected as the client cannot
+// build the result.
+func WithResponseValidation(ctx context.Context, enabled bool) context.Context {
+	return context.WithValue(ctx, responseValidationKey{}, enabled)
+}
+
+// NewLenientDoer returns a Doer that disables the validation of the responses
+// to the requests it sends, see WithResponseValidation. It is useful to
+// consume third-party or older servers that do not fully honor the design:
+//
+//	c := client.NewClient("https", host, goahttp.NewLenientDoer(http.DefaultClient), enc, dec, false)
+func NewLenientDoer(d Doer) Doer {
+	return &lenientDoer{Doer: d}
+}
+
+// RejectResponse returns true if the generated client must reject resp given
+// the error err returned by the response validation. It returns false if
+// response validation is disabled for the request and err only reports values
+// violating the design validations.
+func RejectResponse(resp *http.Response, err error) bool {
+	if err == nil {
+		return false
+	}
+	if resp == nil || resp.Request == nil {
+		return true
+	}
+	if enabled, ok := resp.Request.Context().Value(responseValidationKey{}).(bool); !ok || enabled {
+		return true
+	}
+	var serr *goa.ServiceError
+	if !errors.As(err, &serr) {
+		return true
+	}
+	for _, e := range serr.History() {
+		switch e.Name {
+		case goa.MissingField, goa.InvalidFieldType:
+			return true
+		}
+	}
+	return false
+}
+
+// Unwrap returns the client error.
+func (e *ContractError) Unwrap() error {
+	return e.ClientError
+}
+
+// Do sends the request with response validation disabled.
+func (d *lenientDoer) Do(req *http.Request) (*http.Response, error) {
+	return d.Doer.Do(req.WithContext(WithResponseValidation(req.Context(), false)))
+}
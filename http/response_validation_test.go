@@ -0,0 +1,80 @@
+package http
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+
+	goa "goa.design/goa/v3/pkg"
+)
+
+func TestRejectResponse(t *testing.T) {
+	var (
+		violation = goa.MergeErrors(goa.InvalidPatternError("body.name", "x", "^a"), goa.InvalidRangeError("body.n", 1, 2, true))
+		missing   = goa.MergeErrors(goa.InvalidPatternError("body.name", "x", "^a"), goa.MissingFieldError("id", "body"))
+		lenient   = WithResponseValidation(context.Background(), false)
+	)
+	cases := []struct {
+		Name     string
+		Ctx      context.Context
+		Err      error
+		Expected bool
+	}{
+		{"no-error", context.Background(), nil, false},
+		{"strict-by-default", context.Background(), violation, true},
+		{"strict", WithResponseValidation(context.Background(), true), violation, true},
+		{"lenient-violation", lenient, violation, false},
+		{"lenient-missing-field", lenient, missing, true},
+		{"lenient-invalid-type", lenient, goa.InvalidFieldTypeError("h", "x", "int"), true},
+		{"lenient-other-error", lenient, errors.New("boom"), true},
+	}
+	for _, c := range cases {
+		t.Run(c.Name, func(t *testing.T) {
+			req, _ := http.NewRequest("GET", "http://example.com", nil)
+			resp := &http.Response{Request: req.WithContext(c.Ctx)}
+			if got := RejectResponse(resp, c.Err); got != c.Expected {
+				t.Errorf("got %v, expected %v", got, c.Expected)
+			}
+		})
+	}
+}
+
+func TestLenientDoer(t *testing.T) {
+	var ctx context.Context
+	d := NewLenientDoer(doerFunc(func(req *http.Request) (*http.Response, error) {
+		ctx = req.Context()
+		return &http.Response{Request: req}, nil
+	}))
+	req, _ := http.NewRequest("GET", "http://example.com", nil)
+	resp, err := d.Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if enabled, ok := ctx.Value(responseValidationKey{}).(bool); !ok || enabled {
+		t.Errorf("got response validation %v, expected it to be disabled", enabled)
+	}
+	if RejectResponse(resp, goa.InvalidEnumValueError("body.kind", "x", []any{"a"})) {
+		t.Errorf("got response rejected, expected the violation to be ignored")
+	}
+}
+
+func TestErrValidationError(t *testing.T) {
+	verr := goa.MissingFieldError("id", "body")
+	err := ErrValidationError("svc", "m", verr)
+	var cerr *ContractError
+	if !errors.As(err, &cerr) {
+		t.Fatalf("got %T, expected *ContractError", err)
+	}
+	var clerr *ClientError
+	if !errors.As(err, &clerr) || clerr.Name != "validation_error" {
+		t.Errorf("got %v, expected a validation_error client error", err)
+	}
+	if !errors.Is(err, verr) {
+		t.Errorf("got %v, expected it to wrap the validation error", err)
+	}
+}
+
+type doerFunc func(*http.Request) (*http.Response, error)
+
+func (f doerFunc) Do(req *http.Request) (*http.Response, error) { return f(req) }
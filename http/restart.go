@@ -0,0 +1,176 @@
+package http
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"os/exec"
+	"os/signal"
+	"strconv"
+	"time"
+)
+
+// Environment variables used by Run to hand the listening socket over to the
+// process started on graceful restart. Their values are file descriptor
+// numbers.
+const (
+	// RestartListenerEnv is the environment variable that contains the
+	// file descriptor of the inherited listening socket.
+	RestartListenerEnv = "GOA_RESTART_LISTENER_FD"
+	// RestartReadyEnv is the environment variable that contains the file
+	// descriptor of the pipe closed by the new process once it serves the
+	// inherited socket.
+	RestartReadyEnv = "GOA_RESTART_READY_FD"
+)
+
+// restartTimeout is the maximum duration Run waits for the new process to
+// serve the inherited socket.
+const restartTimeout = time.Minute
+
+// WithGracefulRestart enables zero-downtime restarts: when the process
+// receives one of the given signals, Run starts a new process running the same
+// executable with the same arguments and hands the listening socket over to
+// it. Once the new process serves the socket, Run shuts the server down
+// gracefully so that no connection is dropped. The errors that prevent the
+// restart, e.g. a new binary that fails to start, are given to errh if not nil
+// and Run keeps serving.
+//
+// There is no default restart signal: the user defined signals are already
+// used by default by the other signal driven features of goa, so the signal
+// must be chosen explicitly among the signals the process does not otherwise
+// handle. The default signals are:
+//
+//   - SIGHUP: reload the configuration (middleware.Config.ReloadOnSignal)
+//   - SIGUSR1: reopen the log files (middleware.RotatingFile.ReopenOnSignal)
+//   - SIGUSR2: toggle the maintenance mode (http/middleware.MaintenanceMode.ToggleOnSignal)
+//
+// The handoff is coordinated through the RestartListenerEnv and
+// RestartReadyEnv environment variables: Run serves the socket inherited from
+// the parent process if RestartListenerEnv is set.
+//
+// Example, for a service that does not toggle the maintenance mode with
+// signals:
+//
+//	err := goahttp.Run(ctx, srv, goahttp.WithGracefulRestart(func(err error) {
+//		log.Printf("restart failed: %v", err)
+//	}, syscall.SIGUSR2))
+func WithGracefulRestart(errh func(error), sig os.Signal, sigs ...os.Signal) RunOption {
+	return func(o *runOptions) {
+		o.restart = true
+		o.restartErrh = errh
+		o.restartSigs = append([]os.Signal{sig}, sigs...)
+	}
+}
+
+// inheritedListener returns the listener inherited from the parent process,
+// nil if there is none.
+func inheritedListener() (net.Listener, error) {
+	v := os.Getenv(RestartListenerEnv)
+	if v == "" {
+		return nil, nil
+	}
+	os.Unsetenv(RestartListenerEnv) // nolint: errcheck
+	fd, err := strconv.Atoi(v)
+	if err != nil {
+		return nil, fmt.Errorf("invalid %s value %q", RestartListenerEnv, v)
+	}
+	f := os.NewFile(uintptr(fd), "listener")
+	defer f.Close() // nolint: errcheck
+	return net.FileListener(f)
+}
+
+// notifyParent writes to the pipe inherited from the parent process, if any,
+// to signal that the inherited socket is served.
+func notifyParent() {
+	v := os.Getenv(RestartReadyEnv)
+	if v == "" {
+		return
+	}
+	os.Unsetenv(RestartReadyEnv) // nolint: errcheck
+	if fd, err := strconv.Atoi(v); err == nil {
+		f := os.NewFile(uintptr(fd), "ready")
+		f.Write([]byte{1}) // nolint: errcheck
+		f.Close()          // nolint: errcheck
+	}
+}
+
+// watchRestart starts a new process serving l each time the process receives
+// one of the restart signals and closes restarted once a new process serves
+// l. watchRestart returns when done is closed.
+func watchRestart(l net.Listener, o *runOptions, restarted chan<- struct{}, done <-chan struct{}) {
+	if len(o.restartSigs) == 0 {
+		return
+	}
+	ch := make(chan os.Signal, 1)
+	signal.Notify(ch, o.restartSigs...)
+	defer signal.Stop(ch)
+	for {
+		select {
+		case <-done:
+			return
+		case <-ch:
+			if err := restart(l); err != nil {
+				if o.restartErrh != nil {
+					o.restartErrh(err)
+				}
+				continue
+			}
+			close(restarted)
+			return
+		}
+	}
+}
+
+// restart starts a new process that serves l and waits until it does.
+func restart(l net.Listener) error {
+	filer, ok := l.(interface{ File() (*os.File, error) })
+	if !ok {
+		return fmt.Errorf("cannot hand over listener of type %T", l)
+	}
+	lf, err := filer.File()
+	if err != nil {
+		return err
+	}
+	defer lf.Close() // nolint: errcheck
+	r, w, err := os.Pipe()
+	if err != nil {
+		return err
+	}
+	defer r.Close() // nolint: errcheck
+	exe, err := os.Executable()
+	if err != nil {
+		w.Close() // nolint: errcheck
+		return err
+	}
+	cmd := exec.Command(exe, os.Args[1:]...)
+	cmd.Stdin, cmd.Stdout, cmd.Stderr = os.Stdin, os.Stdout, os.Stderr
+	// ExtraFiles entry i becomes file descriptor 3+i in the new process.
+	cmd.ExtraFiles = []*os.File{lf, w}
+	cmd.Env = append(os.Environ(), RestartListenerEnv+"=3", RestartReadyEnv+"=4")
+	err = cmd.Start()
+	w.Close() // nolint: errcheck
+	if err != nil {
+		return err
+	}
+	exited := make(chan error, 1)
+	go func() { exited <- cmd.Wait() }()
+
+	// The read returns io.EOF without data if the new process exits
+	// before serving.
+	ready := make(chan bool, 1)
+	go func() {
+		n, _ := r.Read(make([]byte, 1))
+		ready <- n == 1
+	}()
+	select {
+	case ok := <-ready:
+		if !ok {
+			return fmt.Errorf("new process exited before serving: %v", <-exited)
+		}
+		return nil
+	case <-time.After(restartTimeout):
+		cmd.Process.Kill() // nolint: errcheck
+		return errors.New("timeout waiting for the new process to serve")
+	}
+}
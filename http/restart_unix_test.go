@@ -0,0 +1,75 @@
+//go:build !windows
+
+package http
+
+import (
+	"net"
+	"os"
+	"strconv"
+	"syscall"
+	"testing"
+)
+
+func TestInheritedListener(t *testing.T) {
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer l.Close()
+	f, err := l.(*net.TCPListener).File()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	t.Setenv(RestartListenerEnv, strconv.Itoa(dupFd(t, f)))
+	inherited, err := inheritedListener()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer inherited.Close()
+	if inherited.Addr().String() != l.Addr().String() {
+		t.Errorf("got address %s, expected %s", inherited.Addr(), l.Addr())
+	}
+	if v := os.Getenv(RestartListenerEnv); v != "" {
+		t.Errorf("got %s=%q, expected it to be unset", RestartListenerEnv, v)
+	}
+
+	os.Unsetenv(RestartListenerEnv) // nolint: errcheck
+	if l, err := inheritedListener(); l != nil || err != nil {
+		t.Errorf("got %v, %v without inherited listener, expected nil, nil", l, err)
+	}
+}
+
+func TestNotifyParent(t *testing.T) {
+	r, w, err := os.Pipe()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer r.Close()
+	t.Setenv(RestartReadyEnv, strconv.Itoa(dupFd(t, w)))
+	w.Close()
+	notifyParent()
+	b := make([]byte, 2)
+	if n, _ := r.Read(b); n != 1 {
+		t.Errorf("got %d bytes, expected the new process to write 1 byte", n)
+	}
+}
+
+func TestRestartUnsupportedListener(t *testing.T) {
+	if err := restart(&fakeListener{}); err == nil {
+		t.Error("expected an error for a listener without file")
+	}
+}
+
+type fakeListener struct{ net.Listener }
+
+// dupFd returns a duplicate of the file descriptor of f owned by the caller
+// so that it can be handed over without being closed twice.
+func dupFd(t *testing.T, f *os.File) int {
+	t.Helper()
+	fd, err := syscall.Dup(int(f.Fd()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return fd
+}
@@ -0,0 +1,177 @@
+package http
+
+import (
+	"fmt"
+	"net/url"
+	"reflect"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+type (
+	// Route describes a HTTP route served by a server.
+	Route struct {
+		// Service is the name of the service.
+		Service string `json:"service"`
+		// Method is the name of the service method as defined in the
+		// design.
+		Method string `json:"method"`
+		// Verb is the HTTP method, e.g. "GET".
+		Verb string `json:"verb"`
+		// Pattern is the request path pattern, e.g. "/bottles/{id}".
+		// See Muxer for the wildcard syntax.
+		Pattern string `json:"pattern"`
+	}
+
+	// Router is the interface implemented by the generated servers to list
+	// the routes they serve.
+	Router interface {
+		// Routes returns the routes in design order.
+		Routes() []*Route
+	}
+
+	// RouteTable is a list of routes.
+	RouteTable []*Route
+)
+
+// patternWildcard matches the wildcards of a route pattern.
+var patternWildcard = regexp.MustCompile(`{(\*?)([a-zA-Z0-9_]+)}`)
+
+// Routes returns the routes of the servers that implement Router.
+func (s Servers) Routes() RouteTable {
+	var table RouteTable
+	for _, v := range s {
+		if r, ok := v.(Router); ok {
+			table = append(table, r.Routes()...)
+		}
+	}
+	return table
+}
+
+// Lookup returns the routes of the given service method.
+func (t RouteTable) Lookup(service, method string) []*Route {
+	var res []*Route
+	for _, r := range t {
+		if r.Service == service && r.Method == method {
+			res = append(res, r)
+		}
+	}
+	return res
+}
+
+// URL returns the URL path and query string of a request made to the given
+// service method with the given parameters. URL uses the first route of the
+// method whose wildcards are all given a value in params, the parameters that
+// do not correspond to wildcards are encoded in the query string. URL returns
+// an error if there is no such route.
+func (t RouteTable) URL(service, method string, params map[string]any) (string, error) {
+	routes := t.Lookup(service, method)
+	if len(routes) == 0 {
+		return "", fmt.Errorf("no route for method %q of service %q", method, service)
+	}
+	var err error
+	for _, r := range routes {
+		var u string
+		if u, err = r.URL(params); err == nil {
+			return u, nil
+		}
+	}
+	return "", err
+}
+
+// URL returns the URL path and query string of a request made to the route
+// with the given parameters. The values of the "{name}" wildcards are path
+// escaped, the values of the "{*name}" wildcards may contain slashes. The
+// parameters that do not correspond to wildcards are encoded in the query
+// string, slice values produce one query string value per element. URL
+// returns an error if a wildcard has no value.
+func (r *Route) URL(params map[string]any) (string, error) {
+	used := make(map[string]bool)
+	var missing []string
+	path := patternWildcard.ReplaceAllStringFunc(r.Pattern, func(w string) string {
+		m := patternWildcard.FindStringSubmatch(w)
+		v, ok := params[m[2]]
+		if !ok {
+			missing = append(missing, m[2])
+			return w
+		}
+		used[m[2]] = true
+		s := fmt.Sprint(v)
+		if m[1] == "" {
+			return url.PathEscape(s)
+		}
+		segments := strings.Split(s, "/")
+		for i, seg := range segments {
+			segments[i] = url.PathEscape(seg)
+		}
+		return strings.Join(segments, "/")
+	})
+	if len(missing) > 0 {
+		return "", fmt.Errorf("missing value for path parameter(s) %s of route %s %s", strings.Join(missing, ", "), r.Verb, r.Pattern)
+	}
+	query := make(url.Values)
+	for k, v := range params {
+		if used[k] {
+			continue
+		}
+		switch vals := v.(type) {
+		case []string:
+			query[k] = append(query[k], vals...)
+		case []any:
+			for _, val := range vals {
+				query.Add(k, fmt.Sprint(val))
+			}
+		default:
+			query.Add(k, fmt.Sprint(v))
+		}
+	}
+	if len(query) == 0 {
+		return path, nil
+	}
+	return path + "?" + query.Encode(), nil
+}
+
+// AddLink adds the link with the given name to links. The link URL is built
+// from the route pattern and the given path parameters. Pointer values are
+// dereferenced, AddLink does nothing if a parameter is nil or missing. The
+// generated servers use AddLink to render the links of result types.
+func AddLink(links map[string]map[string]string, name, pattern string, params map[string]any) {
+	vals := make(map[string]any, len(params))
+	for k, v := range params {
+		rv := reflect.ValueOf(v)
+		for rv.Kind() == reflect.Ptr {
+			if rv.IsNil() {
+				return
+			}
+			rv = rv.Elem()
+		}
+		if !rv.IsValid() {
+			return
+		}
+		vals[k] = rv.Interface()
+	}
+	u, err := (&Route{Pattern: pattern}).URL(vals)
+	if err != nil {
+		return
+	}
+	links[name] = map[string]string{"href": u}
+}
+
+// String returns the routes formatted as a table sorted by pattern and
+// verb, e.g. for printing at server startup.
+func (t RouteTable) String() string {
+	sorted := make(RouteTable, len(t))
+	copy(sorted, t)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		if sorted[i].Pattern != sorted[j].Pattern {
+			return sorted[i].Pattern < sorted[j].Pattern
+		}
+		return sorted[i].Verb < sorted[j].Verb
+	})
+	var b strings.Builder
+	for _, r := range sorted {
+		fmt.Fprintf(&b, "%-7s %s (%s.%s)\n", r.Verb, r.Pattern, r.Service, r.Method)
+	}
+	return b.String()
+}
@@ -0,0 +1,85 @@
+package http
+
+import (
+	"net/http"
+	"strings"
+	"testing"
+)
+
+type routerServer struct{ routes []*Route }
+
+func (s *routerServer) Use(func(http.Handler) http.Handler) {}
+func (s *routerServer) Routes() []*Route                    { return s.routes }
+
+func TestRouteTableURL(t *testing.T) {
+	table := Servers{
+		&routerServer{routes: []*Route{
+			{"cellar", "show", "GET", "/bottles/{id}"},
+			{"cellar", "list", "GET", "/accounts/{account}/bottles"},
+			{"cellar", "list", "GET", "/bottles"},
+		}},
+		&routerServer{routes: []*Route{
+			{"files", "download", "GET", "/files/{*path}"},
+		}},
+	}.Routes()
+
+	cases := map[string]struct {
+		Service, Method string
+		Params          map[string]any
+		// output
+		Expected string
+		Error    string
+	}{
+		"path":         {"cellar", "show", map[string]any{"id": 42}, "/bottles/42", ""},
+		"escaped":      {"cellar", "show", map[string]any{"id": "a b/c"}, "/bottles/a%20b%2Fc", ""},
+		"query":        {"cellar", "show", map[string]any{"id": 1, "view": "tiny", "tag": []string{"b", "a"}}, "/bottles/1?tag=b&tag=a&view=tiny", ""},
+		"first route":  {"cellar", "list", map[string]any{"account": "x"}, "/accounts/x/bottles", ""},
+		"second route": {"cellar", "list", nil, "/bottles", ""},
+		"catch all":    {"files", "download", map[string]any{"path": "a/b c.txt"}, "/files/a/b%20c.txt", ""},
+		"missing":      {"cellar", "show", nil, "", "missing value for path parameter(s) id of route GET /bottles/{id}"},
+		"unknown":      {"cellar", "delete", nil, "", `no route for method "delete" of service "cellar"`},
+	}
+	for k, c := range cases {
+		t.Run(k, func(t *testing.T) {
+			u, err := table.URL(c.Service, c.Method, c.Params)
+			if c.Error != "" {
+				if err == nil || err.Error() != c.Error {
+					t.Errorf("got error %v, expected %q", err, c.Error)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if u != c.Expected {
+				t.Errorf("got URL %q, expected %q", u, c.Expected)
+			}
+		})
+	}
+
+	if got := table.String(); !strings.HasPrefix(got, "GET     /accounts/{account}/bottles (cellar.list)\n") {
+		t.Errorf("unexpected route table:\n%s", got)
+	}
+}
+
+func TestAddLink(t *testing.T) {
+	var (
+		id    = 42
+		nilID *int
+		links = make(map[string]map[string]string)
+	)
+	AddLink(links, "self", "/bottles/{id}", map[string]any{"id": &id})
+	AddLink(links, "account", "/accounts/{id}", map[string]any{"id": nilID})
+	AddLink(links, "list", "/bottles", nil)
+	AddLink(links, "missing", "/bottles/{id}", nil)
+
+	if len(links) != 2 {
+		t.Errorf("got links %v, expected self and list", links)
+	}
+	if h := links["self"]["href"]; h != "/bottles/42" {
+		t.Errorf("got self link %q, expected %q", h, "/bottles/42")
+	}
+	if h := links["list"]["href"]; h != "/bottles" {
+		t.Errorf("got list link %q, expected %q", h, "/bottles")
+	}
+}
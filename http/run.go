@@ -0,0 +1,273 @@
+package http
+
+import (
+	"context"
+	"crypto/tls"
+	"errors"
+	"io"
+	"net"
+	"net/http"
+	"os"
+	"time"
+
+	"golang.org/x/crypto/acme"
+	"golang.org/x/crypto/acme/autocert"
+)
+
+type (
+	// RunOption configures Run.
+	RunOption func(*runOptions)
+
+	// runOptions contains the Run options.
+	runOptions struct {
+		listener        net.Listener
+		shutdownTimeout time.Duration
+		certManager     *autocert.Manager
+		challengeAddr   string
+		banner          *Banner
+		bannerW         io.Writer
+		bannerJSON      bool
+		lifecycle       *Lifecycle
+		drainer         *Drainer
+		drainGrace      time.Duration
+		restart         bool
+		restartErrh     func(error)
+		restartSigs     []os.Signal
+		timeouts        *ServerTimeouts
+		maxConnRequests int
+		keepAlives      func() bool
+		tcpNoDelay      *bool
+		tcpLinger       *int
+		tcpKeepAlive    time.Duration
+		http3           func(http.Handler, *tls.Config) HTTP3Server
+	}
+)
+
+// Run serves srv until ctx is done and then shuts it down gracefully, waiting
+// for the requests in flight to complete for at most 30 seconds unless
+// configured otherwise with WithShutdownTimeout. srv is served with TLS if its
+// TLSConfig provides certificates or if certificates are obtained with
+// WithAutocert. Run returns nil once the server is shut down or the error that
+// caused it to stop. Run prints a startup banner once the server listens if
+// configured with WithBanner or WithBannerJSON and calls the startup and
+// shutdown hooks given with WithLifecycle. The server timeouts may be set with
+// WithServerTimeouts and the connections tuned with WithMaxRequestsPerConn,
+// WithKeepAlives and the TCP options. Run also serves HTTP/3 if configured with
+// WithHTTP3. Run hands the listening socket over to a new process on restart if
+// configured with WithGracefulRestart.
+//
+// Example:
+//
+//	srv := &http.Server{Addr: ":8080", Handler: handler, ReadHeaderTimeout: time.Minute}
+//	if err := goahttp.Run(ctx, srv); err != nil {
+//		log.Fatal(err)
+//	}
+func Run(ctx context.Context, srv *http.Server, opts ...RunOption) error {
+	o := &runOptions{shutdownTimeout: 30 * time.Second}
+	for _, opt := range opts {
+		opt(o)
+	}
+	if o.timeouts != nil {
+		o.timeouts.apply(srv)
+	}
+	o.connHandler(srv)
+	servers := []*http.Server{srv}
+	if o.certManager != nil {
+		srv.TLSConfig = autocertTLSConfig(srv.TLSConfig, o.certManager)
+		if o.challengeAddr != "" {
+			servers = append(servers, &http.Server{
+				Addr:              o.challengeAddr,
+				Handler:           o.certManager.HTTPHandler(nil),
+				ReadHeaderTimeout: 10 * time.Second,
+			})
+		}
+	}
+	secure := srv.TLSConfig != nil && (len(srv.TLSConfig.Certificates) > 0 || srv.TLSConfig.GetCertificate != nil)
+	var h3 HTTP3Server
+	if o.http3 != nil {
+		if !secure {
+			return errHTTP3NoTLS
+		}
+		h := srv.Handler
+		if h == nil {
+			h = http.DefaultServeMux
+		}
+		h3 = o.http3(h, srv.TLSConfig)
+		srv.Handler = altSvcHandler(h, h3)
+	}
+	l := o.listener
+	if l == nil && o.restart {
+		var err error
+		if l, err = inheritedListener(); err != nil {
+			return err
+		}
+	}
+	if l == nil {
+		addr := srv.Addr
+		if addr == "" {
+			addr = ":http"
+			if secure {
+				addr = ":https"
+			}
+		}
+		var err error
+		if l, err = net.Listen("tcp", addr); err != nil {
+			return err
+		}
+	}
+
+	if o.banner != nil {
+		b := *o.banner
+		scheme := "http"
+		if secure {
+			scheme = "https"
+		}
+		b.Addresses = []string{scheme + "://" + l.Addr().String()}
+		for _, s := range servers[1:] {
+			b.Addresses = append(b.Addresses, "http://"+s.Addr)
+		}
+		b.print(o.bannerW, o.bannerJSON)
+	}
+
+	if o.drainer != nil {
+		srv.BaseContext = o.drainer.baseContext(srv.BaseContext)
+		srv.RegisterOnShutdown(o.drainer.Drain)
+	}
+
+	errc := make(chan error, len(servers)+2)
+	go func() {
+		sl := o.connListener(l)
+		if secure {
+			errc <- srv.ServeTLS(sl, "", "")
+			return
+		}
+		errc <- srv.Serve(sl)
+	}()
+	for _, s := range servers[1:] {
+		go func(s *http.Server) { errc <- s.ListenAndServe() }(s)
+	}
+	if h3 != nil {
+		go func() { errc <- h3.ListenAndServe() }()
+	}
+	if o.lifecycle != nil {
+		go func() {
+			if err := o.lifecycle.Start(ctx); err != nil {
+				errc <- err
+			}
+		}()
+	}
+
+	restarted := make(chan struct{})
+	if o.restart {
+		notifyParent()
+		done := make(chan struct{})
+		defer close(done)
+		go watchRestart(l, o, restarted, done)
+	}
+
+	var err error
+	select {
+	case err = <-errc:
+	case <-ctx.Done():
+	case <-restarted:
+	}
+	sctx, cancel := context.WithTimeout(context.Background(), o.shutdownTimeout)
+	defer cancel()
+	if o.drainer != nil {
+		err = drain(sctx, srv, o.drainGrace, err)
+		servers = servers[1:]
+	}
+	for _, s := range servers {
+		if serr := s.Shutdown(sctx); serr != nil && err == nil {
+			err = serr
+		}
+	}
+	if h3 != nil {
+		if serr := shutdownHTTP3(sctx, h3); serr != nil && err == nil {
+			err = serr
+		}
+	}
+	if o.lifecycle != nil {
+		if serr := o.lifecycle.Stop(sctx); serr != nil && err == nil {
+			err = serr
+		}
+	}
+	if errors.Is(err, http.ErrServerClosed) {
+		return nil
+	}
+	return err
+}
+
+// drain shuts srv down in two phases, see WithDrain. The drainer callbacks are
+// called by Shutdown once the listeners are closed. drain returns err or the
+// error returned when closing the server if err is nil.
+func drain(ctx context.Context, srv *http.Server, grace time.Duration, err error) error {
+	gctx, cancel := context.WithTimeout(ctx, grace)
+	defer cancel()
+	serr := srv.Shutdown(gctx)
+	if serr != nil && !errors.Is(serr, http.ErrServerClosed) {
+		// The grace period expired: close the remaining connections.
+		serr = srv.Close()
+	}
+	if err == nil {
+		err = serr
+	}
+	return err
+}
+
+// WithListener makes Run serve the connections accepted by l instead of
+// listening on the server address.
+func WithListener(l net.Listener) RunOption {
+	return func(o *runOptions) { o.listener = l }
+}
+
+// WithShutdownTimeout sets the maximum duration Run waits for the requests in
+// flight to complete when shutting down.
+func WithShutdownTimeout(d time.Duration) RunOption {
+	return func(o *runOptions) { o.shutdownTimeout = d }
+}
+
+// WithAutocert makes Run serve the server with TLS using the certificates
+// obtained and renewed automatically by m from an ACME certificate authority
+// such as Let's Encrypt. The TLS-ALPN-01 challenges are answered by the server
+// itself. If challengeAddr is not empty (e.g. ":80") Run also listens on that
+// address to answer the HTTP-01 challenges and redirect the other requests to
+// HTTPS.
+//
+// Example:
+//
+//	m := &autocert.Manager{
+//		Prompt:     autocert.AcceptTOS,
+//		HostPolicy: autocert.HostWhitelist("api.example.com"),
+//		Cache:      autocert.DirCache("/var/lib/certs"),
+//		Email:      "ops@example.com",
+//	}
+//	srv := &http.Server{Addr: ":443", Handler: handler, ReadHeaderTimeout: time.Minute}
+//	err := goahttp.Run(ctx, srv, goahttp.WithAutocert(m, ":80"))
+func WithAutocert(m *autocert.Manager, challengeAddr string) RunOption {
+	return func(o *runOptions) {
+		o.certManager = m
+		o.challengeAddr = challengeAddr
+	}
+}
+
+// autocertTLSConfig returns a copy of cfg that gets the certificates from m
+// and accepts the TLS-ALPN-01 challenges.
+func autocertTLSConfig(cfg *tls.Config, m *autocert.Manager) *tls.Config {
+	if cfg == nil {
+		cfg = &tls.Config{MinVersion: tls.VersionTLS12}
+	} else {
+		cfg = cfg.Clone()
+	}
+	cfg.GetCertificate = m.GetCertificate
+	if len(cfg.NextProtos) == 0 {
+		cfg.NextProtos = []string{"h2", "http/1.1"}
+	}
+	for _, p := range cfg.NextProtos {
+		if p == acme.ALPNProto {
+			return cfg
+		}
+	}
+	cfg.NextProtos = append(cfg.NextProtos, acme.ALPNProto)
+	return cfg
+}
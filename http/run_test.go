@@ -0,0 +1,85 @@
+package http
+
+import (
+	"context"
+	"crypto/tls"
+	"io"
+	"net"
+	"net/http"
+	"reflect"
+	"testing"
+	"time"
+
+	"golang.org/x/crypto/acme/autocert"
+)
+
+func TestRun(t *testing.T) {
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	srv := &http.Server{Handler: http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.Write([]byte("ok")) // nolint: errcheck
+	}), ReadHeaderTimeout: time.Second}
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error)
+	go func() { done <- Run(ctx, srv, WithListener(l), WithShutdownTimeout(time.Second)) }()
+
+	resp, err := http.Get("http://" + l.Addr().String())
+	if err != nil {
+		t.Fatal(err)
+	}
+	body, _ := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	if string(body) != "ok" {
+		t.Errorf("got body %q, expected \"ok\"", body)
+	}
+	cancel()
+	if err := <-done; err != nil {
+		t.Errorf("got error %v, expected the server to shut down gracefully", err)
+	}
+	if _, err := http.Get("http://" + l.Addr().String()); err == nil {
+		t.Error("expected the server to be closed")
+	}
+
+	if err := Run(context.Background(), &http.Server{Addr: "invalid:address:1", ReadHeaderTimeout: time.Second}); err == nil {
+		t.Error("expected an error for an invalid address")
+	}
+}
+
+func TestRunServerTimeouts(t *testing.T) {
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	srv := &http.Server{Handler: http.NotFoundHandler(), ReadHeaderTimeout: time.Second}
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	timeouts := ServerTimeouts{ReadTimeout: 2 * time.Second, WriteTimeout: 3 * time.Second, IdleTimeout: 4 * time.Second}
+	if err := Run(ctx, srv, WithListener(l), WithServerTimeouts(timeouts)); err != nil {
+		t.Fatal(err)
+	}
+	got := ServerTimeouts{ReadHeaderTimeout: srv.ReadHeaderTimeout, ReadTimeout: srv.ReadTimeout, WriteTimeout: srv.WriteTimeout, IdleTimeout: srv.IdleTimeout}
+	if expected := (ServerTimeouts{time.Second, 2 * time.Second, 3 * time.Second, 4 * time.Second}); got != expected {
+		t.Errorf("got timeouts %+v, expected %+v", got, expected)
+	}
+}
+
+func TestAutocertTLSConfig(t *testing.T) {
+	m := &autocert.Manager{Prompt: autocert.AcceptTOS, HostPolicy: autocert.HostWhitelist("example.com")}
+	cfg := autocertTLSConfig(nil, m)
+	if cfg.GetCertificate == nil || cfg.MinVersion != tls.VersionTLS12 {
+		t.Errorf("got %+v, expected the certificates to be obtained from the manager", cfg)
+	}
+	if expected := []string{"h2", "http/1.1", "acme-tls/1"}; !reflect.DeepEqual(cfg.NextProtos, expected) {
+		t.Errorf("got protocols %v, expected %v", cfg.NextProtos, expected)
+	}
+	orig := &tls.Config{MinVersion: tls.VersionTLS13, NextProtos: []string{"http/1.1"}}
+	cfg = autocertTLSConfig(orig, m)
+	if cfg.MinVersion != tls.VersionTLS13 || !reflect.DeepEqual(cfg.NextProtos, []string{"http/1.1", "acme-tls/1"}) {
+		t.Errorf("got %+v, expected the configuration to be preserved", cfg)
+	}
+	if orig.GetCertificate != nil || len(orig.NextProtos) != 1 {
+		t.Error("expected the original configuration to be left unchanged")
+	}
+}
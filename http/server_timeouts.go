@@ -0,0 +1,62 @@
+package http
+
+import (
+	"net/http"
+	"time"
+)
+
+// ServerTimeouts contains the timeouts of the connections accepted by a
+// server, see the http.Server fields with the same names. The timeouts apply
+// to all the routes, use the WriteTimeout, ReadTimeout or StreamWriteTimeout
+// middlewares of the goa http middleware package to override them for the
+// routes that stream.
+type ServerTimeouts struct {
+	// ReadHeaderTimeout is the maximum duration for reading the request
+	// headers.
+	ReadHeaderTimeout time.Duration
+	// ReadTimeout is the maximum duration for reading the entire request
+	// including the body.
+	ReadTimeout time.Duration
+	// WriteTimeout is the maximum duration before timing out writes of
+	// the response, it is reset whenever a new request's header is read.
+	WriteTimeout time.Duration
+	// IdleTimeout is the maximum amount of time to wait for the next
+	// request when keep-alives are enabled.
+	IdleTimeout time.Duration
+}
+
+// DefaultServerTimeouts are timeouts suitable for servers exposed to the
+// Internet: they protect the server against slow clients (slow loris attacks)
+// while leaving enough time to handle regular requests.
+var DefaultServerTimeouts = ServerTimeouts{
+	ReadHeaderTimeout: 10 * time.Second,
+	ReadTimeout:       30 * time.Second,
+	WriteTimeout:      30 * time.Second,
+	IdleTimeout:       2 * time.Minute,
+}
+
+// WithServerTimeouts makes Run set the timeouts of the server to the non-zero
+// values of t prior to serving it.
+//
+// Example:
+//
+//	err := goahttp.Run(ctx, srv, goahttp.WithServerTimeouts(goahttp.DefaultServerTimeouts))
+func WithServerTimeouts(t ServerTimeouts) RunOption {
+	return func(o *runOptions) { o.timeouts = &t }
+}
+
+// apply sets the timeouts of srv to the non-zero values of t.
+func (t *ServerTimeouts) apply(srv *http.Server) {
+	if t.ReadHeaderTimeout > 0 {
+		srv.ReadHeaderTimeout = t.ReadHeaderTimeout
+	}
+	if t.ReadTimeout > 0 {
+		srv.ReadTimeout = t.ReadTimeout
+	}
+	if t.WriteTimeout > 0 {
+		srv.WriteTimeout = t.WriteTimeout
+	}
+	if t.IdleTimeout > 0 {
+		srv.IdleTimeout = t.IdleTimeout
+	}
+}
@@ -0,0 +1,48 @@
+package http
+
+import (
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestSignURL(t *testing.T) {
+	secret := []byte("secret")
+	now := time.Now()
+	signed, err := SignURL(secret, "GET", "https://api.example.com/files/report.pdf?version=2", now.Add(time.Hour))
+	if err != nil {
+		t.Fatal(err)
+	}
+	u, _ := url.Parse(signed)
+	if u.Query().Get("version") != "2" || u.Query().Get(SignedURLSignatureParam) == "" || u.Query().Get(SignedURLExpiresParam) == "" {
+		t.Fatalf("got URL %q, expected signed URL keeping the version parameter", signed)
+	}
+	tampered := strings.Replace(signed, "version=2", "version=3", 1)
+	cases := []struct {
+		Name     string
+		Method   string
+		URL      string
+		Now      time.Time
+		Secrets  [][]byte
+		Expected error
+	}{
+		{"valid", "GET", signed, now, [][]byte{secret}, nil},
+		{"rotated", "GET", signed, now, [][]byte{[]byte("new"), secret}, nil},
+		{"expired", "GET", signed, now.Add(2 * time.Hour), [][]byte{secret}, ErrURLExpired},
+		{"tampered", "GET", tampered, now, [][]byte{secret}, ErrURLSignature},
+		{"other-method", "DELETE", signed, now, [][]byte{secret}, ErrURLSignature},
+		{"other-path", "GET", strings.Replace(signed, "report", "other", 1), now, [][]byte{secret}, ErrURLSignature},
+		{"unsigned", "GET", "https://api.example.com/files/report.pdf", now, [][]byte{secret}, ErrURLSignature},
+		{"wrong-secret", "GET", signed, now, [][]byte{[]byte("other")}, ErrURLSignature},
+	}
+	for _, c := range cases {
+		t.Run(c.Name, func(t *testing.T) {
+			r := httptest.NewRequest(c.Method, c.URL, nil)
+			if err := VerifyURL(r, c.Now, c.Secrets...); err != c.Expected {
+				t.Errorf("got error %v, expected %v", err, c.Expected)
+			}
+		})
+	}
+}
@@ -0,0 +1,119 @@
+package http
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	// GRPCTimeoutHeader is the name of the header set by gRPC clients and
+	// proxies (e.g. Envoy) to propagate the request deadline. The value
+	// is a positive integer followed by a unit: "H" (hours), "M"
+	// (minutes), "S" (seconds), "m" (milliseconds), "u" (microseconds) or
+	// "n" (nanoseconds), e.g. "250m".
+	GRPCTimeoutHeader = "Grpc-Timeout"
+
+	// RequestTimeoutHeader is the name of the header used to propagate the
+	// request deadline between HTTP services. The value is a duration as
+	// accepted by time.ParseDuration (e.g. "1.5s") or a number of seconds.
+	RequestTimeoutHeader = "X-Request-Timeout"
+)
+
+// timeoutDoer is a Doer that sets the timeout headers of the requests.
+type timeoutDoer struct {
+	Doer
+}
+
+// NewTimeoutDoer returns a Doer that sets the timeout headers of the requests
+// from the deadline of their context, see SetTimeoutHeaders, so that deadlines
+// propagate to the services that use the Timeout middleware.
+//
+// Example:
+//
+//	doer := goahttp.NewTimeoutDoer(http.DefaultClient)
+//	client := svcsvr.NewClient(scheme, host, doer, enc, dec, restore)
+func NewTimeoutDoer(d Doer) Doer {
+	return &timeoutDoer{Doer: d}
+}
+
+// SetTimeoutHeaders sets the timeout headers of req from the deadline of its
+// context if any so that the server may derive the deadline of the request
+// context from it, see RequestTimeout.
+func SetTimeoutHeaders(req *http.Request) {
+	deadline, ok := req.Context().Deadline()
+	if !ok {
+		return
+	}
+	timeout := time.Until(deadline)
+	if timeout <= 0 {
+		return
+	}
+	ms := timeout.Milliseconds()
+	if ms == 0 {
+		ms = 1
+	}
+	req.Header.Set(RequestTimeoutHeader, timeout.String())
+	req.Header.Set(GRPCTimeoutHeader, strconv.FormatInt(ms, 10)+"m")
+}
+
+// RequestTimeout returns the timeout set in the request headers if any. The
+// X-Request-Timeout header takes precedence over the Grpc-Timeout header.
+// Invalid values are ignored.
+func RequestTimeout(h http.Header) (time.Duration, bool) {
+	if v := h.Get(RequestTimeoutHeader); v != "" {
+		if d, err := time.ParseDuration(v); err == nil && d > 0 {
+			return d, true
+		}
+		if s, err := strconv.ParseFloat(v, 64); err == nil && s > 0 {
+			return time.Duration(s * float64(time.Second)), true
+		}
+	}
+	if v := h.Get(GRPCTimeoutHeader); v != "" {
+		return parseGRPCTimeout(v)
+	}
+	return 0, false
+}
+
+// Do sets the timeout headers of a copy of req and sends it.
+func (d *timeoutDoer) Do(req *http.Request) (*http.Response, error) {
+	if _, ok := req.Context().Deadline(); ok {
+		req = req.Clone(req.Context())
+		SetTimeoutHeaders(req)
+	}
+	return d.Doer.Do(req)
+}
+
+// parseGRPCTimeout parses a timeout using the gRPC wire format.
+func parseGRPCTimeout(v string) (time.Duration, bool) {
+	if len(v) < 2 || len(v) > 9 {
+		return 0, false
+	}
+	var unit time.Duration
+	switch v[len(v)-1] {
+	case 'H':
+		unit = time.Hour
+	case 'M':
+		unit = time.Minute
+	case 'S':
+		unit = time.Second
+	case 'm':
+		unit = time.Millisecond
+	case 'u':
+		unit = time.Microsecond
+	case 'n':
+		unit = time.Nanosecond
+	default:
+		return 0, false
+	}
+	digits := v[:len(v)-1]
+	if strings.TrimLeft(digits, "0123456789") != "" {
+		return 0, false
+	}
+	n, err := strconv.ParseInt(digits, 10, 64)
+	if err != nil || n <= 0 {
+		return 0, false
+	}
+	return time.Duration(n) * unit, true
+}
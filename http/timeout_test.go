@@ -0,0 +1,84 @@
+package http
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+)
+
+func TestRequestTimeout(t *testing.T) {
+	cases := map[string]struct {
+		Header, Value string
+		// output
+		Expected time.Duration
+	}{
+		"none":                {"", "", 0},
+		"request-timeout":     {RequestTimeoutHeader, "2s", 2 * time.Second},
+		"request-seconds":     {RequestTimeoutHeader, "1.5", 1500 * time.Millisecond},
+		"request-invalid":     {RequestTimeoutHeader, "soon", 0},
+		"grpc-milliseconds":   {GRPCTimeoutHeader, "250m", 250 * time.Millisecond},
+		"grpc-hours":          {GRPCTimeoutHeader, "1H", time.Hour},
+		"grpc-invalid-unit":   {GRPCTimeoutHeader, "10x", 0},
+		"grpc-invalid-digits": {GRPCTimeoutHeader, "-10S", 0},
+		"grpc-too-long":       {GRPCTimeoutHeader, "123456789S", 0},
+	}
+	for k, c := range cases {
+		t.Run(k, func(t *testing.T) {
+			h := make(http.Header)
+			if c.Header != "" {
+				h.Set(c.Header, c.Value)
+			}
+			d, ok := RequestTimeout(h)
+			if ok != (c.Expected != 0) || d != c.Expected {
+				t.Errorf("got timeout %v (%v), expected %v", d, ok, c.Expected)
+			}
+		})
+	}
+}
+
+func TestSetTimeoutHeaders(t *testing.T) {
+	req, _ := http.NewRequest("GET", "/", nil)
+	SetTimeoutHeaders(req)
+	if v := req.Header.Get(RequestTimeoutHeader); v != "" {
+		t.Errorf("got timeout header %q without deadline", v)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+	defer cancel()
+	req = req.WithContext(ctx)
+	SetTimeoutHeaders(req)
+	d, ok := RequestTimeout(req.Header)
+	if !ok || d <= 0 || d > time.Minute {
+		t.Errorf("got request timeout %v (%v) from header %q", d, ok, req.Header.Get(RequestTimeoutHeader))
+	}
+	req.Header.Del(RequestTimeoutHeader)
+	d, ok = RequestTimeout(req.Header)
+	if !ok || d <= 59*time.Second || d > time.Minute {
+		t.Errorf("got gRPC timeout %v (%v) from header %q", d, ok, req.Header.Get(GRPCTimeoutHeader))
+	}
+}
+
+func TestTimeoutDoer(t *testing.T) {
+	var sent *http.Request
+	doer := NewTimeoutDoer(doerFunc(func(req *http.Request) (*http.Response, error) {
+		sent = req
+		return &http.Response{StatusCode: http.StatusOK}, nil
+	}))
+	req, _ := http.NewRequest("GET", "/", nil)
+	doer.Do(req) // nolint: errcheck
+	if v := sent.Header.Get(RequestTimeoutHeader); v != "" {
+		t.Errorf("got timeout header %q without deadline", v)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+	defer cancel()
+	req = req.WithContext(ctx)
+	doer.Do(req) // nolint: errcheck
+	if d, ok := RequestTimeout(sent.Header); !ok || d <= 0 || d > time.Minute {
+		t.Errorf("got request timeout %v (%v) from header %q", d, ok, sent.Header.Get(RequestTimeoutHeader))
+	}
+	if v := req.Header.Get(RequestTimeoutHeader); v != "" {
+		t.Errorf("got timeout header %q set on the original request", v)
+	}
+}
@@ -0,0 +1,69 @@
+package http
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// DecodeStrict decodes v with d rejecting the bodies that contain fields that
+// do not match any field of v. The generated code uses it for the bodies of the
+// endpoints and responses defined with the DisallowUnknownFields DSL. It has
+// no effect on decoders that do not implement DisallowUnknownFields such as
+// the XML and gob decoders, the JSON decoder returned by RequestDecoder and
+// ResponseDecoder does.
+func DecodeStrict(d Decoder, v any) error {
+	if sd, ok := d.(interface{ DisallowUnknownFields() }); ok {
+		sd.DisallowUnknownFields()
+	}
+	return d.Decode(v)
+}
+
+// DecodeCollectUnknown decodes v with d and stores the top level fields of the
+// JSON body whose names do not match any of the known names in unknown. The
+// names are compared case-insensitively as with encoding/json. The generated
+// code uses it for the bodies of the endpoints and responses defined with the
+// CollectUnknownFields DSL. It decodes v as usual if d is not a JSON decoder.
+func DecodeCollectUnknown[T any](d Decoder, v any, known []string, unknown *map[string]T) error {
+	switch d.(type) {
+	case *json.Decoder, *presenceDecoder:
+	default:
+		return d.Decode(v)
+	}
+	var raw json.RawMessage
+	if err := d.Decode(&raw); err != nil {
+		return err
+	}
+	if err := json.Unmarshal(raw, v); err != nil {
+		return err
+	}
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(raw, &fields); err != nil {
+		// Not an object, v was decoded from a null body.
+		return nil
+	}
+	for name, val := range fields {
+		if isKnownField(name, known) {
+			continue
+		}
+		var elem T
+		if err := json.Unmarshal(val, &elem); err != nil {
+			return fmt.Errorf("invalid value for unknown field %q: %w", name, err)
+		}
+		if *unknown == nil {
+			*unknown = make(map[string]T)
+		}
+		(*unknown)[name] = elem
+	}
+	return nil
+}
+
+// isKnownField returns true if name matches one of the known field names.
+func isKnownField(name string, known []string) bool {
+	for _, k := range known {
+		if strings.EqualFold(name, k) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,73 @@
+package http
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+func TestDecodeStrict(t *testing.T) {
+	var body struct {
+		Name *string `json:"name"`
+	}
+	if err := DecodeStrict(json.NewDecoder(strings.NewReader(`{"name":"a"}`)), &body); err != nil {
+		t.Errorf("got error %v, expected none", err)
+	}
+	err := DecodeStrict(json.NewDecoder(strings.NewReader(`{"name":"a","other":1}`)), &body)
+	if err == nil || !strings.Contains(err.Error(), `unknown field "other"`) {
+		t.Errorf("got error %v, expected unknown field error", err)
+	}
+}
+
+func TestDecodeCollectUnknown(t *testing.T) {
+	type body struct {
+		Name  *string        `json:"name"`
+		Count *int           `json:"n"`
+		Extra map[string]any `json:"-"`
+	}
+	cases := []struct {
+		Name  string
+		Body  string
+		Extra map[string]any
+		Error string
+	}{
+		{"no-unknown", `{"name":"a","n":1}`, nil, ""},
+		{"unknown", `{"name":"a","other":"b","more":2}`, map[string]any{"other": "b", "more": 2.0}, ""},
+		{"case-insensitive", `{"NAME":"a","N":1}`, nil, ""},
+		{"null", `null`, nil, ""},
+		{"invalid", `{"name":1}`, nil, "cannot unmarshal number"},
+	}
+	for _, c := range cases {
+		t.Run(c.Name, func(t *testing.T) {
+			var b body
+			err := DecodeCollectUnknown(json.NewDecoder(strings.NewReader(c.Body)), &b, []string{"name", "n"}, &b.Extra)
+			if c.Error != "" {
+				if err == nil || !strings.Contains(err.Error(), c.Error) {
+					t.Errorf("got error %v, expected %q", err, c.Error)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("got error %v, expected none", err)
+			}
+			if len(b.Extra) != len(c.Extra) {
+				t.Fatalf("got unknown fields %v, expected %v", b.Extra, c.Extra)
+			}
+			for k, v := range c.Extra {
+				if b.Extra[k] != v {
+					t.Errorf("got %v for unknown field %q, expected %v", b.Extra[k], k, v)
+				}
+			}
+		})
+	}
+}
+
+func TestDecodeCollectUnknownTyped(t *testing.T) {
+	var b struct {
+		Extra map[string]string `json:"-"`
+	}
+	err := DecodeCollectUnknown(json.NewDecoder(strings.NewReader(`{"other":1}`)), &b, nil, &b.Extra)
+	if err == nil || !strings.Contains(err.Error(), `unknown field "other"`) {
+		t.Errorf("got error %v, expected invalid unknown field error", err)
+	}
+}
@@ -0,0 +1,284 @@
+package http
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sync"
+)
+
+type (
+	// VCRMode defines whether a VCRDoer records or replays interactions.
+	VCRMode int
+
+	// VCRDoer is a Doer that records the requests made by a generated
+	// client and the corresponding responses to a fixture file and replays
+	// them later so that tests of code that calls goa clients do not need
+	// live servers. Use NewVCRDoer to create the Doer given to the
+	// generated client constructors in tests. VCRDoer is safe for
+	// concurrent use.
+	VCRDoer struct {
+		doer    Doer
+		path    string
+		mode    VCRMode
+		scrub   []string
+		matcher VCRMatcher
+
+		mu           sync.Mutex
+		interactions []*VCRInteraction
+		used         []bool
+	}
+
+	// VCROption configures a VCRDoer.
+	VCROption func(*VCRDoer)
+
+	// VCRMatcher returns true if the recorded request matches req. The
+	// body of req can be read with GetBody.
+	VCRMatcher func(req *http.Request, rec *VCRRequest) bool
+
+	// VCRInteraction is a request and its response as recorded in a
+	// fixture file.
+	VCRInteraction struct {
+		// Request is the recorded request.
+		Request *VCRRequest `json:"request"`
+		// Response is the recorded response.
+		Response *VCRResponse `json:"response"`
+	}
+
+	// VCRRequest is a recorded request.
+	VCRRequest struct {
+		// Method is the request HTTP method.
+		Method string `json:"method"`
+		// URL is the request URL.
+		URL string `json:"url"`
+		// Header contains the request headers.
+		Header http.Header `json:"header,omitempty"`
+		// Body is the request body.
+		Body string `json:"body,omitempty"`
+	}
+
+	// VCRResponse is a recorded response.
+	VCRResponse struct {
+		// Status is the response status code.
+		Status int `json:"status"`
+		// Header contains the response headers.
+		Header http.Header `json:"header,omitempty"`
+		// Body is the response body.
+		Body string `json:"body,omitempty"`
+	}
+)
+
+const (
+	// VCRReplay replays the interactions recorded in the fixture file
+	// and never sends requests.
+	VCRReplay VCRMode = iota
+	// VCRRecord sends the requests and records the interactions, the
+	// fixture file is overwritten by Save.
+	VCRRecord
+	// VCRRecordOnce records the interactions if the fixture file does not
+	// exist and replays them otherwise.
+	VCRRecordOnce
+)
+
+// scrubbedValue replaces the values of the scrubbed headers in fixtures.
+const scrubbedValue = "SCRUBBED"
+
+// ErrVCRNoInteraction is returned by VCRDoer in replay mode when no recorded
+// interaction matches the request.
+var ErrVCRNoInteraction = errors.New("no recorded interaction matches the request")
+
+// NewVCRDoer returns a Doer that records or replays the interactions stored in
+// the fixture file at path depending on mode. In record mode the requests are
+// made with d and Save must be called to write the fixture file. The values of
+// the Authorization, Cookie and Set-Cookie headers are scrubbed from the
+// recorded interactions unless configured otherwise with WithVCRScrubHeaders.
+// In replay mode requests are matched by method, URL and body unless
+// configured otherwise with WithVCRMatcher and each recorded interaction is
+// replayed at most once, in the order it was recorded.
+//
+// Example:
+//
+//	doer, err := goahttp.NewVCRDoer("testdata/list_items.json", goahttp.VCRRecordOnce, http.DefaultClient)
+//	if err != nil {
+//	    t.Fatal(err)
+//	}
+//	defer doer.Save()
+//	client := catalogc.NewClient("http", "localhost:8080", doer, enc, dec, false)
+func NewVCRDoer(path string, mode VCRMode, d Doer, opts ...VCROption) (*VCRDoer, error) {
+	v := &VCRDoer{
+		doer:    d,
+		path:    path,
+		mode:    mode,
+		scrub:   []string{"Authorization", "Cookie", "Set-Cookie"},
+		matcher: MatchVCRRequest,
+	}
+	for _, opt := range opts {
+		opt(v)
+	}
+	if v.mode == VCRRecord {
+		return v, nil
+	}
+	b, err := os.ReadFile(path)
+	if err != nil {
+		if v.mode == VCRRecordOnce && errors.Is(err, fs.ErrNotExist) {
+			v.mode = VCRRecord
+			return v, nil
+		}
+		return nil, err
+	}
+	if err := json.Unmarshal(b, &v.interactions); err != nil {
+		return nil, fmt.Errorf("invalid fixture file %q: %w", path, err)
+	}
+	v.mode = VCRReplay
+	v.used = make([]bool, len(v.interactions))
+	return v, nil
+}
+
+// WithVCRScrubHeaders sets the names of the request and response headers
+// whose values are replaced with "SCRUBBED" in the fixture file.
+func WithVCRScrubHeaders(names ...string) VCROption {
+	return func(v *VCRDoer) { v.scrub = names }
+}
+
+// WithVCRMatcher sets the function used to match the requests with the
+// recorded interactions in replay mode.
+func WithVCRMatcher(m VCRMatcher) VCROption {
+	return func(v *VCRDoer) { v.matcher = m }
+}
+
+// MatchVCRRequest is the default VCRMatcher, it matches requests with the
+// same method, URL and body.
+func MatchVCRRequest(req *http.Request, rec *VCRRequest) bool {
+	if req.Method != rec.Method || req.URL.String() != rec.URL {
+		return false
+	}
+	body, err := requestBody(req)
+	return err == nil && string(body) == rec.Body
+}
+
+// Do replays the recorded interaction matching req or makes the request and
+// records the interaction.
+func (v *VCRDoer) Do(req *http.Request) (*http.Response, error) {
+	body, err := requestBody(req)
+	if err != nil {
+		return nil, err
+	}
+	if v.mode == VCRReplay {
+		return v.replay(req)
+	}
+	r := req.Clone(req.Context())
+	r.Body = io.NopCloser(bytes.NewReader(body))
+	resp, err := v.doer.Do(r)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	rbody, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+	v.mu.Lock()
+	v.interactions = append(v.interactions, &VCRInteraction{
+		Request: &VCRRequest{
+			Method: req.Method,
+			URL:    req.URL.String(),
+			Header: v.scrubbed(req.Header),
+			Body:   string(body),
+		},
+		Response: &VCRResponse{
+			Status: resp.StatusCode,
+			Header: v.scrubbed(resp.Header),
+			Body:   string(rbody),
+		},
+	})
+	v.mu.Unlock()
+	resp.Body = io.NopCloser(bytes.NewReader(rbody))
+	return resp, nil
+}
+
+// Save writes the recorded interactions to the fixture file. Save does
+// nothing in replay mode.
+func (v *VCRDoer) Save() error {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	if v.mode == VCRReplay {
+		return nil
+	}
+	interactions := v.interactions
+	if interactions == nil {
+		interactions = []*VCRInteraction{}
+	}
+	b, err := json.MarshalIndent(interactions, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(v.path), 0o755); err != nil {
+		return err
+	}
+	return os.WriteFile(v.path, append(b, '\n'), 0o644)
+}
+
+// replay returns the response of the first unused interaction matching req.
+func (v *VCRDoer) replay(req *http.Request) (*http.Response, error) {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	for i, in := range v.interactions {
+		if v.used[i] || !v.matcher(req, in.Request) {
+			continue
+		}
+		v.used[i] = true
+		return &http.Response{
+			Status:        fmt.Sprintf("%d %s", in.Response.Status, http.StatusText(in.Response.Status)),
+			StatusCode:    in.Response.Status,
+			Proto:         "HTTP/1.1",
+			ProtoMajor:    1,
+			ProtoMinor:    1,
+			Header:        in.Response.Header.Clone(),
+			Body:          io.NopCloser(bytes.NewReader([]byte(in.Response.Body))),
+			ContentLength: int64(len(in.Response.Body)),
+			Request:       req,
+		}, nil
+	}
+	return nil, fmt.Errorf("%w: %s %s", ErrVCRNoInteraction, req.Method, req.URL)
+}
+
+// scrubbed returns a copy of h where the values of the scrubbed headers are
+// replaced.
+func (v *VCRDoer) scrubbed(h http.Header) http.Header {
+	h = h.Clone()
+	for _, name := range v.scrub {
+		if h.Get(name) != "" {
+			h.Set(name, scrubbedValue)
+		}
+	}
+	return h
+}
+
+// requestBody returns the body of req leaving req.Body readable.
+func requestBody(req *http.Request) ([]byte, error) {
+	if req.Body == nil || req.Body == http.NoBody {
+		return nil, nil
+	}
+	if req.GetBody != nil {
+		rc, err := req.GetBody()
+		if err != nil {
+			return nil, err
+		}
+		defer rc.Close()
+		return io.ReadAll(rc)
+	}
+	body, err := io.ReadAll(req.Body)
+	req.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+	req.Body = io.NopCloser(bytes.NewReader(body))
+	req.GetBody = func() (io.ReadCloser, error) { return io.NopCloser(bytes.NewReader(body)), nil }
+	return body, nil
+}
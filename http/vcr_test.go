@@ -0,0 +1,68 @@
+package http
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestVCRDoer(t *testing.T) {
+	var calls int
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		b, _ := io.ReadAll(r.Body)
+		w.Header().Set("Set-Cookie", "session=secret")
+		w.WriteHeader(http.StatusCreated)
+		w.Write([]byte("echo " + string(b))) // nolint: errcheck
+	}))
+	defer srv.Close()
+	path := filepath.Join(t.TempDir(), "fixtures", "items.json")
+	do := func(d Doer, body string) (*http.Response, string, error) {
+		req, _ := http.NewRequest("POST", srv.URL+"/items", strings.NewReader(body))
+		req.Header.Set("Authorization", "Bearer secret")
+		resp, err := d.Do(req)
+		if err != nil {
+			return nil, "", err
+		}
+		defer resp.Body.Close()
+		b, _ := io.ReadAll(resp.Body)
+		return resp, string(b), nil
+	}
+
+	rec, err := NewVCRDoer(path, VCRRecordOnce, http.DefaultClient)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, body := range []string{"a", "b"} {
+		if _, got, err := do(rec, body); err != nil || got != "echo "+body {
+			t.Fatalf("got %q, %v", got, err)
+		}
+	}
+	if err := rec.Save(); err != nil {
+		t.Fatal(err)
+	}
+	fixture, _ := os.ReadFile(path)
+	if strings.Contains(string(fixture), "secret") {
+		t.Errorf("fixture contains unscrubbed values:\n%s", fixture)
+	}
+
+	play, err := NewVCRDoer(path, VCRRecordOnce, http.DefaultClient)
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp, got, err := do(play, "b")
+	if err != nil || got != "echo b" || resp.StatusCode != http.StatusCreated {
+		t.Fatalf("got %v %q, %v", resp, got, err)
+	}
+	if _, _, err := do(play, "b"); !errors.Is(err, ErrVCRNoInteraction) {
+		t.Errorf("got error %v, expected %v", err, ErrVCRNoInteraction)
+	}
+	if calls != 2 {
+		t.Errorf("got %d calls, expected 2", calls)
+	}
+}
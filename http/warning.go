@@ -0,0 +1,77 @@
+package http
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+)
+
+type (
+	// warningsKey is the context key used to store the warnings recorded
+	// while handling a request.
+	warningsKey struct{}
+
+	// warnings is the list of warnings recorded while handling a request.
+	warnings struct {
+		mu    sync.Mutex
+		texts []string
+	}
+)
+
+// now returns the current time, overridden in tests.
+var now = time.Now
+
+// WithWarnings returns a copy of ctx that records the warnings added with
+// AddWarning so that they may be written to the response with SetWarnings.
+func WithWarnings(ctx context.Context) context.Context {
+	return context.WithValue(ctx, warningsKey{}, &warnings{})
+}
+
+// AddWarning records a warning in ctx. AddWarning does nothing if ctx was not
+// created with WithWarnings.
+func AddWarning(ctx context.Context, text string) {
+	ws, ok := ctx.Value(warningsKey{}).(*warnings)
+	if !ok {
+		return
+	}
+	ws.mu.Lock()
+	defer ws.mu.Unlock()
+	ws.texts = append(ws.texts, text)
+}
+
+// SetWarnings adds a Warning header to w for each warning recorded in ctx.
+// The headers use the 299 ("Miscellaneous persistent warning") code defined
+// in RFC 7234.
+func SetWarnings(ctx context.Context, w http.ResponseWriter) {
+	ws, ok := ctx.Value(warningsKey{}).(*warnings)
+	if !ok {
+		return
+	}
+	ws.mu.Lock()
+	defer ws.mu.Unlock()
+	for _, t := range ws.texts {
+		w.Header().Add("Warning", "299 - "+strconv.Quote(t))
+	}
+}
+
+// UseMigrationDefault returns true if the grace period of the migration default
+// of the request field with the given name has not elapsed yet. until is the
+// last day of the grace period formatted as YYYY-MM-DD (UTC). When it returns
+// true UseMigrationDefault also records a warning in ctx so that clients are
+// notified that the field will soon be required. Generated request decoders
+// call UseMigrationDefault when a field that has a migration default is
+// missing.
+func UseMigrationDefault(ctx context.Context, field, until string) bool {
+	t, err := time.Parse("2006-01-02", until)
+	if err != nil {
+		return false
+	}
+	if !now().Before(t.AddDate(0, 0, 1)) {
+		return false
+	}
+	AddWarning(ctx, fmt.Sprintf("missing field %q defaulted, the field becomes required after %s", field, until))
+	return true
+}
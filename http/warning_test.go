@@ -0,0 +1,55 @@
+package http
+
+import (
+	"context"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestUseMigrationDefault(t *testing.T) {
+	defer func() { now = time.Now }()
+	now = func() time.Time { return time.Date(2024, 6, 30, 23, 0, 0, 0, time.UTC) }
+
+	cases := []struct {
+		Name     string
+		Until    string
+		Expected bool
+	}{
+		{"before", "2024-07-01", true},
+		{"last day", "2024-06-30", true},
+		{"after", "2024-06-29", false},
+		{"invalid", "invalid", false},
+	}
+	for _, c := range cases {
+		t.Run(c.Name, func(t *testing.T) {
+			ctx := WithWarnings(context.Background())
+			if got := UseMigrationDefault(ctx, "region", c.Until); got != c.Expected {
+				t.Errorf("got %v, expected %v", got, c.Expected)
+			}
+			w := httptest.NewRecorder()
+			SetWarnings(ctx, w)
+			hs := w.Header().Values("Warning")
+			if !c.Expected {
+				if len(hs) != 0 {
+					t.Errorf("got Warning headers %v, expected none", hs)
+				}
+				return
+			}
+			expected := `299 - "missing field \"region\" defaulted, the field becomes required after ` + c.Until + `"`
+			if len(hs) != 1 || hs[0] != expected {
+				t.Errorf("got Warning headers %v, expected [%s]", hs, expected)
+			}
+		})
+	}
+}
+
+func TestAddWarningWithoutWarnings(t *testing.T) {
+	ctx := context.Background()
+	AddWarning(ctx, "ignored")
+	w := httptest.NewRecorder()
+	SetWarnings(ctx, w)
+	if hs := w.Header().Values("Warning"); len(hs) != 0 {
+		t.Errorf("got Warning headers %v, expected none", hs)
+	}
+}
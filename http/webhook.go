@@ -0,0 +1,99 @@
+package http
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// WebhookSignatureHeader is the name of the header that holds the signature of
+// the requests sent by WebhookSender.
+const WebhookSignatureHeader = "Webhook-Signature"
+
+// WebhookSender sends the callback requests defined in the design with the
+// Callback DSL. The generated server packages define one function per callback
+// that accepts a WebhookSender.
+type WebhookSender struct {
+	doer   Doer
+	secret []byte
+}
+
+// NewWebhookSender returns a webhook sender that makes requests using doer and
+// signs them with the given HMAC secret. The requests are not signed if secret
+// is empty.
+func NewWebhookSender(doer Doer, secret []byte) *WebhookSender {
+	return &WebhookSender{doer: doer, secret: secret}
+}
+
+// Send sends a POST request to url with body encoded in JSON. The request
+// carries a WebhookSignatureHeader header computed with SignWebhook if the
+// sender has a secret. Send makes at most attempts delivery attempts: requests
+// that fail because of a transport error or because the response status code is
+// 408, 429 or 5xx are retried after waiting for backoff which doubles after each
+// retry. Send returns the last error if all attempts fail or ctx is canceled.
+func (s *WebhookSender) Send(ctx context.Context, url string, body any, attempts int, backoff time.Duration) error {
+	b, err := json.Marshal(body)
+	if err != nil {
+		return err
+	}
+	for attempt := 1; ; attempt++ {
+		retry, err := s.send(ctx, url, b)
+		if err == nil || !retry || attempt >= attempts {
+			return err
+		}
+		select {
+		case <-ctx.Done():
+			return err
+		case <-time.After(backoff):
+		}
+		backoff *= 2
+	}
+}
+
+// SignWebhook returns the value of the WebhookSignatureHeader header for a
+// request sent at the given time with the given body. The value has the form
+// "t=TIMESTAMP,v1=SIGNATURE" where TIMESTAMP is the Unix time in seconds and
+// SIGNATURE the hex encoded HMAC-SHA256 of "TIMESTAMP.BODY" keyed with secret.
+// Receivers verify the signature with the http/middleware VerifyWebhook
+// middleware and the GoaWebhook scheme.
+func SignWebhook(secret []byte, t time.Time, body []byte) string {
+	ts := strconv.FormatInt(t.Unix(), 10)
+	mac := hmac.New(sha256.New, secret)
+	mac.Write([]byte(ts + "."))
+	mac.Write(body)
+	return "t=" + ts + ",v1=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+// send makes one delivery attempt, it returns whether the request may be
+// retried on failure.
+func (s *WebhookSender) send(ctx context.Context, url string, body []byte) (bool, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return false, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if len(s.secret) > 0 {
+		req.Header.Set(WebhookSignatureHeader, SignWebhook(s.secret, time.Now(), body))
+	}
+	resp, err := s.doer.Do(req)
+	if err != nil {
+		return ctx.Err() == nil, err
+	}
+	defer resp.Body.Close()
+	_, _ = io.Copy(io.Discard, resp.Body)
+	if resp.StatusCode >= 200 && resp.StatusCode < 300 {
+		return false, nil
+	}
+	retry := resp.StatusCode == http.StatusRequestTimeout ||
+		resp.StatusCode == http.StatusTooManyRequests ||
+		resp.StatusCode >= 500
+	return retry, fmt.Errorf("webhook %s: unexpected response status %s", url, resp.Status)
+}
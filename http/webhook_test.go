@@ -0,0 +1,75 @@
+package http
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestWebhookSender(t *testing.T) {
+	secret := []byte("secret")
+	cases := []struct {
+		name     string
+		secret   []byte
+		statuses []int
+		attempts int
+		calls    int
+		err      bool
+	}{
+		{"success", secret, []int{200}, 3, 1, false},
+		{"no-secret", nil, []int{204}, 1, 1, false},
+		{"retry", secret, []int{503, 429, 200}, 3, 3, false},
+		{"retries-exhausted", secret, []int{500, 500, 500}, 2, 2, true},
+		{"not-retryable", secret, []int{400, 200}, 3, 1, true},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			var calls int
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				body, _ := io.ReadAll(r.Body)
+				if got := string(body); got != `{"id":"1"}` {
+					t.Errorf("got body %s, expected %s", got, `{"id":"1"}`)
+				}
+				sig := r.Header.Get(WebhookSignatureHeader)
+				if c.secret == nil {
+					if sig != "" {
+						t.Errorf("got signature %q, expected none", sig)
+					}
+				} else {
+					ts := strings.TrimPrefix(strings.SplitN(sig, ",", 2)[0], "t=")
+					unix, _ := strconv.ParseInt(ts, 10, 64)
+					if want := SignWebhook(c.secret, time.Unix(unix, 0), body); sig != want {
+						t.Errorf("got signature %q, expected %q", sig, want)
+					}
+				}
+				w.WriteHeader(c.statuses[calls])
+				calls++
+			}))
+			defer srv.Close()
+			s := NewWebhookSender(srv.Client(), c.secret)
+			err := s.Send(context.Background(), srv.URL, map[string]string{"id": "1"}, c.attempts, time.Millisecond)
+			if c.err && err == nil {
+				t.Error("expected an error")
+			}
+			if !c.err && err != nil {
+				t.Errorf("unexpected error: %s", err)
+			}
+			if calls != c.calls {
+				t.Errorf("got %d calls, expected %d", calls, c.calls)
+			}
+		})
+	}
+}
+
+func TestSignWebhook(t *testing.T) {
+	got := SignWebhook([]byte("secret"), time.Unix(1700000000, 0), []byte(`{"id":"1"}`))
+	want := "t=1700000000,v1=086f6aff7bd084c98679825129c5a64dbad88c760016d6d2c0fb123f27951d54"
+	if got != want {
+		t.Errorf("got signature %q, expected %q", got, want)
+	}
+}
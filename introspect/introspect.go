@@ -0,0 +1,512 @@
+/*
+Package introspect exposes a read-only view of an evaluated design.
+
+The view lists the API, the services, their methods, the user types and the
+HTTP mapping of the methods using plain Go values that may be traversed
+without knowledge of the expr package internals and serialized to JSON.
+Tools such as custom generators, documentation sites or governance checks
+typically import the design package for its side effects and call Run:
+
+	package main
+
+	import (
+	    "encoding/json"
+	    "os"
+
+	    _ "goa.design/examples/cellar/design"
+	    "goa.design/goa/v3/introspect"
+	)
+
+	func main() {
+	    d, err := introspect.Run()
+	    if err != nil {
+	        panic(err)
+	    }
+	    json.NewEncoder(os.Stdout).Encode(d)
+	}
+
+Plugins and generators that already have access to the evaluated root
+expression use New instead.
+
+The view is a snapshot: changing it has no effect on the design. The data
+structures defined in this package follow semantic versioning, fields may be
+added in minor releases but are never removed or renamed.
+*/
+package introspect
+
+import (
+	"sort"
+
+	"goa.design/goa/v3/eval"
+	"goa.design/goa/v3/expr"
+)
+
+type (
+	// Design is the view of an evaluated design.
+	Design struct {
+		// API describes the API.
+		API *API `json:"api"`
+		// Services lists the services in design order.
+		Services []*Service `json:"services"`
+		// Types lists the user types and result types used or defined by
+		// the design sorted by name.
+		Types []*UserType `json:"types"`
+	}
+
+	// API describes the API.
+	API struct {
+		// Name is the API name.
+		Name string `json:"name"`
+		// Title is the API title.
+		Title string `json:"title,omitempty"`
+		// Description is the API description.
+		Description string `json:"description,omitempty"`
+		// Version is the API version.
+		Version string `json:"version,omitempty"`
+		// Meta is the API meta.
+		Meta map[string][]string `json:"meta,omitempty"`
+	}
+
+	// Service describes a service.
+	Service struct {
+		// Name is the service name.
+		Name string `json:"name"`
+		// Description is the service description.
+		Description string `json:"description,omitempty"`
+		// Methods lists the service methods in design order.
+		Methods []*Method `json:"methods"`
+		// Errors lists the errors common to all the service methods.
+		Errors []*Error `json:"errors,omitempty"`
+		// Meta is the service meta.
+		Meta map[string][]string `json:"meta,omitempty"`
+	}
+
+	// Method describes a service method.
+	Method struct {
+		// Name is the method name.
+		Name string `json:"name"`
+		// Description is the method description.
+		Description string `json:"description,omitempty"`
+		// Payload is the method payload, nil if the method has none.
+		Payload *Attribute `json:"payload,omitempty"`
+		// StreamingPayload is the payload sent through the stream, nil if
+		// the method does not stream its payload.
+		StreamingPayload *Attribute `json:"streaming_payload,omitempty"`
+		// Result is the method result, nil if the method has none.
+		Result *Attribute `json:"result,omitempty"`
+		// Stream is the kind of stream: "client", "server" or
+		// "bidirectional", empty if the method does not stream.
+		Stream string `json:"stream,omitempty"`
+		// Errors lists the method errors including the errors defined
+		// by the service.
+		Errors []*Error `json:"errors,omitempty"`
+		// HTTP describes the HTTP mapping of the method, nil if the
+		// method is not exposed through HTTP.
+		HTTP *HTTPEndpoint `json:"http,omitempty"`
+		// Meta is the method meta.
+		Meta map[string][]string `json:"meta,omitempty"`
+	}
+
+	// Error describes a method error.
+	Error struct {
+		// Name is the error name.
+		Name string `json:"name"`
+		*Attribute
+	}
+
+	// UserType describes a user type or a result type.
+	UserType struct {
+		// Name is the type name.
+		Name string `json:"name"`
+		// Attribute describes the type, its type is never a reference
+		// to a user type.
+		*Attribute
+	}
+
+	// Attribute describes a data structure: a payload, a result, an
+	// object field etc.
+	Attribute struct {
+		// Type is the attribute type.
+		Type *Type `json:"type"`
+		// Description is the attribute description.
+		Description string `json:"description,omitempty"`
+		// Default is the attribute default value if any.
+		Default any `json:"default,omitempty"`
+		// Validation lists the attribute validations, nil if there is
+		// none.
+		Validation *Validation `json:"validation,omitempty"`
+		// Meta is the attribute meta.
+		Meta map[string][]string `json:"meta,omitempty"`
+	}
+
+	// Type describes a data type.
+	Type struct {
+		// Kind is the type kind: the name of a primitive type ("boolean",
+		// "int", "int32", "int64", "uint", "uint32", "uint64", "float32",
+		// "float64", "string", "bytes" or "any"), "array", "map",
+		// "object", "union" or "user" for references to user types.
+		Kind string `json:"kind"`
+		// Ref is the name of the user type if Kind is "user", the type
+		// is listed in Design.Types.
+		Ref string `json:"ref,omitempty"`
+		// Elem is the type of the array or map elements.
+		Elem *Attribute `json:"elem,omitempty"`
+		// Key is the type of the map keys.
+		Key *Attribute `json:"key,omitempty"`
+		// Fields lists the object fields or the union values in design
+		// order.
+		Fields []*Field `json:"fields,omitempty"`
+	}
+
+	// Field describes an object field or a union value.
+	Field struct {
+		// Name is the field name.
+		Name string `json:"name"`
+		// Required is true if the field is required.
+		Required bool `json:"required,omitempty"`
+		*Attribute
+	}
+
+	// Validation lists the validations of an attribute.
+	Validation struct {
+		// Enum lists the allowed values.
+		Enum []any `json:"enum,omitempty"`
+		// Format is the string format, e.g. "date-time" or "uuid".
+		Format string `json:"format,omitempty"`
+		// Pattern is the regular expression string values must match.
+		Pattern string `json:"pattern,omitempty"`
+		// Minimum is the inclusive minimum of numbers.
+		Minimum *float64 `json:"minimum,omitempty"`
+		// Maximum is the inclusive maximum of numbers.
+		Maximum *float64 `json:"maximum,omitempty"`
+		// ExclusiveMinimum is the exclusive minimum of numbers.
+		ExclusiveMinimum *float64 `json:"exclusive_minimum,omitempty"`
+		// ExclusiveMaximum is the exclusive maximum of numbers.
+		ExclusiveMaximum *float64 `json:"exclusive_maximum,omitempty"`
+		// MinLength is the minimum length of strings, arrays and maps.
+		MinLength *int `json:"min_length,omitempty"`
+		// MaxLength is the maximum length of strings, arrays and maps.
+		MaxLength *int `json:"max_length,omitempty"`
+	}
+
+	// HTTPEndpoint describes the HTTP mapping of a method.
+	HTTPEndpoint struct {
+		// Routes lists the endpoint routes with their full paths.
+		Routes []*Route `json:"routes"`
+		// PathParams lists the path parameters.
+		PathParams []*Param `json:"path_params,omitempty"`
+		// QueryParams lists the query string parameters.
+		QueryParams []*Param `json:"query_params,omitempty"`
+		// Headers lists the request headers.
+		Headers []*Param `json:"headers,omitempty"`
+		// Cookies lists the request cookies.
+		Cookies []*Param `json:"cookies,omitempty"`
+		// Body describes the request body, nil if there is none.
+		Body *Attribute `json:"body,omitempty"`
+		// Responses lists the success responses.
+		Responses []*HTTPResponse `json:"responses"`
+		// Errors lists the error responses.
+		Errors []*HTTPError `json:"errors,omitempty"`
+	}
+
+	// Route is a HTTP route.
+	Route struct {
+		// Method is the HTTP method, e.g. "GET".
+		Method string `json:"method"`
+		// Path is the full route path including the service and API base
+		// paths, e.g. "/tasks/{id}".
+		Path string `json:"path"`
+	}
+
+	// Param maps a HTTP request or response element (path or query
+	// parameter, header or cookie) to a payload or result field.
+	Param struct {
+		// Name is the name of the HTTP element.
+		Name string `json:"name"`
+		// Field is the name of the payload or result field.
+		Field string `json:"field"`
+		// Required is true if the element is required.
+		Required bool `json:"required,omitempty"`
+	}
+
+	// HTTPResponse describes a HTTP response.
+	HTTPResponse struct {
+		// StatusCode is the response status code.
+		StatusCode int `json:"status_code"`
+		// ContentType is the response content type if set in the design.
+		ContentType string `json:"content_type,omitempty"`
+		// Headers lists the response headers.
+		Headers []*Param `json:"headers,omitempty"`
+		// Cookies lists the response cookies.
+		Cookies []*Param `json:"cookies,omitempty"`
+		// Body describes the response body, nil if there is none.
+		Body *Attribute `json:"body,omitempty"`
+	}
+
+	// HTTPError describes the HTTP response of a method error.
+	HTTPError struct {
+		// Name is the error name.
+		Name string `json:"name"`
+		// Response is the error response.
+		Response *HTTPResponse `json:"response"`
+	}
+
+	// builder builds the view of a design keeping track of the user types
+	// to list in Design.Types.
+	builder struct {
+		root  *expr.RootExpr
+		types map[string]*UserType
+	}
+)
+
+// Run evaluates the design DSL and returns its view. The design package must
+// be imported by the program so that its DSL is registered.
+func Run() (*Design, error) {
+	if err := eval.RunDSL(); err != nil {
+		return nil, err
+	}
+	return New(expr.Root), nil
+}
+
+// New returns the view of the given evaluated design root.
+func New(root *expr.RootExpr) *Design {
+	b := &builder{root: root, types: make(map[string]*UserType)}
+	d := &Design{Services: []*Service{}, Types: []*UserType{}}
+	if api := root.API; api != nil {
+		d.API = &API{
+			Name:        api.Name,
+			Title:       api.Title,
+			Description: api.Description,
+			Version:     api.Version,
+			Meta:        meta(api.Meta),
+		}
+	}
+	for _, s := range root.Services {
+		d.Services = append(d.Services, b.service(s))
+	}
+	for _, ut := range root.Types {
+		b.userType(ut)
+	}
+	for _, ut := range root.ResultTypes {
+		b.userType(ut)
+	}
+	for _, ut := range b.types {
+		d.Types = append(d.Types, ut)
+	}
+	sort.Slice(d.Types, func(i, j int) bool { return d.Types[i].Name < d.Types[j].Name })
+	return d
+}
+
+// service returns the view of s.
+func (b *builder) service(s *expr.ServiceExpr) *Service {
+	svc := &Service{
+		Name:        s.Name,
+		Description: s.Description,
+		Methods:     []*Method{},
+		Errors:      b.errors(s.Errors),
+		Meta:        meta(s.Meta),
+	}
+	var hs *expr.HTTPServiceExpr
+	if b.root.API != nil && b.root.API.HTTP != nil {
+		hs = b.root.HTTPServiceFor(s)
+	}
+	for _, m := range s.Methods {
+		meth := &Method{
+			Name:             m.Name,
+			Description:      m.Description,
+			Payload:          b.attribute(m.Payload),
+			StreamingPayload: b.attribute(m.StreamingPayload),
+			Result:           b.attribute(m.Result),
+			Stream:           stream(m.Stream),
+			Errors:           b.errors(m.Errors),
+			Meta:             meta(m.Meta),
+		}
+		if hs != nil {
+			if e := hs.Endpoint(m.Name); e != nil {
+				meth.HTTP = b.endpoint(e)
+			}
+		}
+		svc.Methods = append(svc.Methods, meth)
+	}
+	return svc
+}
+
+// errors returns the views of errs.
+func (b *builder) errors(errs []*expr.ErrorExpr) []*Error {
+	var res []*Error
+	for _, e := range errs {
+		res = append(res, &Error{Name: e.Name, Attribute: b.attribute(e.AttributeExpr)})
+	}
+	return res
+}
+
+// endpoint returns the view of the HTTP endpoint e.
+func (b *builder) endpoint(e *expr.HTTPEndpointExpr) *HTTPEndpoint {
+	ep := &HTTPEndpoint{
+		Routes:      []*Route{},
+		PathParams:  params(e.PathParams()),
+		QueryParams: params(e.QueryParams()),
+		Headers:     params(e.Headers),
+		Cookies:     params(e.Cookies),
+		Body:        b.attribute(e.Body),
+		Responses:   []*HTTPResponse{},
+	}
+	for _, r := range e.Routes {
+		for _, p := range r.FullPaths() {
+			ep.Routes = append(ep.Routes, &Route{Method: r.Method, Path: p})
+		}
+	}
+	for _, r := range e.Responses {
+		ep.Responses = append(ep.Responses, b.response(r))
+	}
+	for _, he := range e.HTTPErrors {
+		ep.Errors = append(ep.Errors, &HTTPError{Name: he.Name, Response: b.response(he.Response)})
+	}
+	return ep
+}
+
+// response returns the view of the HTTP response r.
+func (b *builder) response(r *expr.HTTPResponseExpr) *HTTPResponse {
+	if r == nil {
+		return nil
+	}
+	return &HTTPResponse{
+		StatusCode:  r.StatusCode,
+		ContentType: r.ContentType,
+		Headers:     params(r.Headers),
+		Cookies:     params(r.Cookies),
+		Body:        b.attribute(r.Body),
+	}
+}
+
+// attribute returns the view of att, nil if att is nil or empty.
+func (b *builder) attribute(att *expr.AttributeExpr) *Attribute {
+	if att == nil || att.Type == nil || att.Type == expr.Empty {
+		return nil
+	}
+	return &Attribute{
+		Type:        b.typ(att),
+		Description: att.Description,
+		Default:     att.DefaultValue,
+		Validation:  validation(att.Validation),
+		Meta:        meta(att.Meta),
+	}
+}
+
+// typ returns the view of the type of att. User types are referenced by name
+// and recorded in b.types.
+func (b *builder) typ(att *expr.AttributeExpr) *Type {
+	switch t := att.Type.(type) {
+	case expr.UserType:
+		b.userType(t)
+		return &Type{Kind: "user", Ref: t.Name()}
+	case *expr.Array:
+		return &Type{Kind: "array", Elem: b.attribute(t.ElemType)}
+	case *expr.Map:
+		return &Type{Kind: "map", Key: b.attribute(t.KeyType), Elem: b.attribute(t.ElemType)}
+	case *expr.Object:
+		return &Type{Kind: "object", Fields: b.fields(*t, att)}
+	case *expr.Union:
+		return &Type{Kind: "union", Fields: b.fields(t.Values, nil)}
+	default:
+		return &Type{Kind: t.Name()}
+	}
+}
+
+// fields returns the views of the given named attributes. parent is the
+// attribute holding the required validations, nil for union values.
+func (b *builder) fields(nats []*expr.NamedAttributeExpr, parent *expr.AttributeExpr) []*Field {
+	res := []*Field{}
+	for _, nat := range nats {
+		f := &Field{Name: nat.Name, Attribute: b.attribute(nat.Attribute)}
+		if parent != nil {
+			f.Required = parent.IsRequired(nat.Name)
+		}
+		res = append(res, f)
+	}
+	return res
+}
+
+// userType records the view of ut in b.types if not already done.
+func (b *builder) userType(ut expr.UserType) {
+	if _, ok := b.types[ut.Name()]; ok {
+		return
+	}
+	view := &UserType{Name: ut.Name()}
+	// Record first to handle recursive types.
+	b.types[ut.Name()] = view
+	att := ut.Attribute()
+	view.Attribute = &Attribute{
+		Description: att.Description,
+		Default:     att.DefaultValue,
+		Validation:  validation(att.Validation),
+		Meta:        meta(att.Meta),
+	}
+	view.Type = b.typ(att)
+}
+
+// params returns the HTTP elements mapped by ma.
+func params(ma *expr.MappedAttributeExpr) []*Param {
+	if ma == nil {
+		return nil
+	}
+	obj := expr.AsObject(ma.Type)
+	if obj == nil {
+		return nil
+	}
+	var res []*Param
+	for _, nat := range *obj {
+		res = append(res, &Param{
+			Name:     ma.ElemName(nat.Name),
+			Field:    nat.Name,
+			Required: ma.IsRequired(nat.Name),
+		})
+	}
+	return res
+}
+
+// validation returns the view of v, nil if v is nil.
+func validation(v *expr.ValidationExpr) *Validation {
+	if v == nil || len(v.Values) == 0 && v.Format == "" && v.Pattern == "" &&
+		v.Minimum == nil && v.Maximum == nil && v.ExclusiveMinimum == nil &&
+		v.ExclusiveMaximum == nil && v.MinLength == nil && v.MaxLength == nil {
+		return nil
+	}
+	return &Validation{
+		Enum:             v.Values,
+		Format:           string(v.Format),
+		Pattern:          v.Pattern,
+		Minimum:          v.Minimum,
+		Maximum:          v.Maximum,
+		ExclusiveMinimum: v.ExclusiveMinimum,
+		ExclusiveMaximum: v.ExclusiveMaximum,
+		MinLength:        v.MinLength,
+		MaxLength:        v.MaxLength,
+	}
+}
+
+// stream returns the view of the stream kind k.
+func stream(k expr.StreamKind) string {
+	switch k {
+	case expr.ClientStreamKind:
+		return "client"
+	case expr.ServerStreamKind:
+		return "server"
+	case expr.BidirectionalStreamKind:
+		return "bidirectional"
+	default:
+		return ""
+	}
+}
+
+// meta returns a copy of m, nil if m is empty.
+func meta(m expr.MetaExpr) map[string][]string {
+	if len(m) == 0 {
+		return nil
+	}
+	res := make(map[string][]string, len(m))
+	for k, v := range m {
+		res[k] = append([]string(nil), v...)
+	}
+	return res
+}
@@ -0,0 +1,97 @@
+package introspect
+
+import (
+	"encoding/json"
+	"testing"
+
+	"goa.design/goa/v3/codegen"
+	"goa.design/goa/v3/introspect/testdata"
+)
+
+func TestNew(t *testing.T) {
+	d := New(codegen.RunDSL(t, testdata.CellarDSL))
+
+	if len(d.Services) != 1 {
+		t.Fatalf("got %d services, expected 1", len(d.Services))
+	}
+	svc := d.Services[0]
+	if svc.Name != "sommelier" || svc.Description != "The sommelier service" || svc.Meta["owner"][0] != "cellar-team" {
+		t.Errorf("got service %q (%q, %v)", svc.Name, svc.Description, svc.Meta)
+	}
+	if len(svc.Methods) != 2 {
+		t.Fatalf("got %d methods, expected 2", len(svc.Methods))
+	}
+
+	pick := svc.Methods[0]
+	if pick.Payload == nil || pick.Payload.Type.Kind != "object" || len(pick.Payload.Type.Fields) != 3 {
+		t.Fatalf("unexpected payload %+v", pick.Payload)
+	}
+	id, color := pick.Payload.Type.Fields[0], pick.Payload.Type.Fields[2]
+	if id.Name != "id" || !id.Required || id.Type.Kind != "int" {
+		t.Errorf("got payload field %q (required: %v, kind: %q)", id.Name, id.Required, id.Type.Kind)
+	}
+	if color.Required || color.Validation == nil || len(color.Validation.Enum) != 2 {
+		t.Errorf("got payload field color (required: %v, validation: %+v)", color.Required, color.Validation)
+	}
+	if pick.Result == nil || pick.Result.Type.Kind != "user" || pick.Result.Type.Ref != "Bottle" {
+		t.Errorf("unexpected result %+v", pick.Result)
+	}
+	if len(pick.Errors) != 2 || pick.Errors[0].Name != "not_found" || pick.Errors[1].Name != "unauthorized" {
+		t.Errorf("unexpected errors %+v", pick.Errors)
+	}
+
+	h := pick.HTTP
+	if h == nil {
+		t.Fatal("missing HTTP endpoint")
+	}
+	if len(h.Routes) != 1 || h.Routes[0].Method != "GET" || h.Routes[0].Path != "/bottles/{id}" {
+		t.Errorf("unexpected routes %+v", h.Routes)
+	}
+	if len(h.PathParams) != 1 || h.PathParams[0].Name != "id" {
+		t.Errorf("unexpected path params %+v", h.PathParams)
+	}
+	if len(h.QueryParams) != 1 || h.QueryParams[0].Name != "color" {
+		t.Errorf("unexpected query params %+v", h.QueryParams)
+	}
+	if len(h.Headers) != 1 || *h.Headers[0] != (Param{Name: "Authorization", Field: "token", Required: true}) {
+		t.Errorf("unexpected headers %+v", h.Headers)
+	}
+	if len(h.Responses) != 1 || h.Responses[0].StatusCode != 200 {
+		t.Errorf("unexpected responses %+v", h.Responses)
+	}
+	if len(h.Errors) != 2 || h.Errors[0].Name != "not_found" || h.Errors[0].Response.StatusCode != 404 {
+		t.Errorf("unexpected HTTP errors %+v", h.Errors)
+	}
+
+	watch := svc.Methods[1]
+	if watch.Stream != "server" || watch.HTTP != nil {
+		t.Errorf("got method watch stream %q and HTTP %+v", watch.Stream, watch.HTTP)
+	}
+
+	var bottle *UserType
+	for _, ut := range d.Types {
+		if ut.Name == "Bottle" {
+			bottle = ut
+		}
+	}
+	if bottle == nil {
+		t.Fatal("missing type Bottle")
+	}
+	if bottle.Description != "A bottle of wine" || bottle.Type.Kind != "object" || len(bottle.Type.Fields) != 3 {
+		t.Fatalf("unexpected type Bottle %+v", bottle.Attribute)
+	}
+	name, vintage, pairs := bottle.Type.Fields[0], bottle.Type.Fields[1], bottle.Type.Fields[2]
+	if !name.Required || name.Validation == nil || *name.Validation.MinLength != 1 {
+		t.Errorf("unexpected field name %+v", name.Attribute)
+	}
+	if vintage.Required || vintage.Validation == nil || *vintage.Validation.Minimum != 1900 {
+		t.Errorf("unexpected field vintage %+v", vintage.Attribute)
+	}
+	if pairs.Type.Kind != "array" || pairs.Type.Elem.Type.Ref != "Bottle" {
+		t.Errorf("unexpected field pairs_with %+v", pairs.Type)
+	}
+
+	if _, err := json.Marshal(d); err != nil {
+		t.Errorf("failed to marshal design: %v", err)
+	}
+}
@@ -0,0 +1,45 @@
+package testdata
+
+import . "goa.design/goa/v3/dsl"
+
+var CellarDSL = func() {
+	var Bottle = Type("Bottle", func() {
+		Description("A bottle of wine")
+		Attribute("name", String, func() {
+			MinLength(1)
+		})
+		Attribute("vintage", Int, func() {
+			Minimum(1900)
+		})
+		Attribute("pairs_with", ArrayOf("Bottle"))
+		Required("name")
+	})
+	Service("sommelier", func() {
+		Description("The sommelier service")
+		Meta("owner", "cellar-team")
+		Error("unauthorized")
+		Method("pick", func() {
+			Payload(func() {
+				Attribute("id", Int)
+				Attribute("token", String)
+				Attribute("color", String, func() {
+					Enum("red", "white")
+				})
+				Required("id", "token")
+			})
+			Result(Bottle)
+			Error("not_found")
+			HTTP(func() {
+				GET("/bottles/{id}")
+				Header("token:Authorization")
+				Param("color")
+				Response(StatusOK)
+				Response("not_found", StatusNotFound)
+				Response("unauthorized", StatusUnauthorized)
+			})
+		})
+		Method("watch", func() {
+			StreamingResult(Bottle)
+		})
+	})
+}
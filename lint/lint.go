@@ -0,0 +1,133 @@
+/*
+Package lint implements the design linter run by the "goa lint" command.
+
+The linter runs a set of rules against the evaluated design and reports the
+violations. Package lint defines a few built-in rules, additional rules can be
+registered with Register, typically from the init function of a package
+imported by the design package:
+
+	func init() {
+	    lint.Register(lint.NewRule("no-get-with-body", "GET endpoints must not have a body", checkNoGetWithBody))
+	}
+
+A rule may be disabled for the whole design, a service or a method using the
+"lint:disable" meta:
+
+	var _ = Service("legacy", func() {
+	    Meta("lint:disable", "naming", "missing-example")
+	})
+*/
+package lint
+
+import (
+	"fmt"
+
+	"goa.design/goa/v3/expr"
+)
+
+type (
+	// Rule is a design lint rule.
+	Rule interface {
+		// Name returns the rule name used in reports and in the
+		// "lint:disable" meta.
+		Name() string
+		// Description returns a short description of the rule.
+		Description() string
+		// Check returns the violations of the rule found in the design.
+		Check(root *expr.RootExpr) []*Violation
+	}
+
+	// Violation describes a design expression that does not follow a rule.
+	Violation struct {
+		// Rule is the name of the violated rule, set by Run.
+		Rule string
+		// Service is the service the violation belongs to if any, used to
+		// honor the "lint:disable" meta.
+		Service *expr.ServiceExpr
+		// Method is the method the violation belongs to if any, used to
+		// honor the "lint:disable" meta.
+		Method *expr.MethodExpr
+		// Message describes the violation.
+		Message string
+	}
+
+	// rule is a Rule implemented by a function.
+	rule struct {
+		name, description string
+		check             func(*expr.RootExpr) []*Violation
+	}
+)
+
+// disableMeta is the meta key used to disable rules.
+const disableMeta = "lint:disable"
+
+// rules keeps track of the registered rules in registration order.
+var rules []Rule
+
+// NewRule returns a rule with the given name and description that uses check
+// to find violations.
+func NewRule(name, description string, check func(*expr.RootExpr) []*Violation) Rule {
+	return &rule{name: name, description: description, check: check}
+}
+
+// Register adds the rule to the set of rules run by Run. Register panics if a
+// rule with the same name is already registered.
+func Register(r Rule) {
+	for _, rl := range rules {
+		if rl.Name() == r.Name() {
+			panic(fmt.Sprintf("lint: rule %q already registered", r.Name())) // bug
+		}
+	}
+	rules = append(rules, r)
+}
+
+// Rules returns the registered rules in registration order.
+func Rules() []Rule {
+	return append([]Rule(nil), rules...)
+}
+
+// Run runs all the registered rules against root and returns the violations
+// that are not disabled via the "lint:disable" meta.
+func Run(root *expr.RootExpr) []*Violation {
+	var res []*Violation
+	for _, r := range rules {
+		if disabled(root.API.Meta, r.Name()) {
+			continue
+		}
+		for _, v := range r.Check(root) {
+			if v.Service != nil && disabled(v.Service.Meta, r.Name()) {
+				continue
+			}
+			if v.Method != nil && disabled(v.Method.Meta, r.Name()) {
+				continue
+			}
+			v.Rule = r.Name()
+			res = append(res, v)
+		}
+	}
+	return res
+}
+
+// String returns the violation formatted for display.
+func (v *Violation) String() string {
+	return fmt.Sprintf("%s: %s", v.Rule, v.Message)
+}
+
+// Name returns the rule name.
+func (r *rule) Name() string { return r.name }
+
+// Description returns the rule description.
+func (r *rule) Description() string { return r.description }
+
+// Check runs the rule check function.
+func (r *rule) Check(root *expr.RootExpr) []*Violation { return r.check(root) }
+
+// disabled returns true if meta disables the rule with the given name.
+func disabled(meta expr.MetaExpr, name string) bool {
+	for _, n := range meta[disableMeta] {
+		if n == name {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,70 @@
+package lint
+
+import (
+	"testing"
+
+	"goa.design/goa/v3/codegen"
+	"goa.design/goa/v3/expr"
+	"goa.design/goa/v3/lint/testdata"
+)
+
+func TestRun(t *testing.T) {
+	cases := []struct {
+		Name     string
+		DSL      func()
+		Expected []string
+	}{
+		{"clean", testdata.CleanDSL, nil},
+		{"violations", testdata.ViolationsDSL, []string{
+			`missing-description: service "sommelier" has no description`,
+			`missing-description: method "Pick" of service "sommelier" has no description`,
+			`missing-description: type "Bottle" has no description`,
+			`untyped-error: error "not_found" of method "Pick" of service "sommelier" uses type string instead of a user type`,
+			`naming: method "Pick" of service "sommelier" is not snake_case`,
+			`naming: attribute "vintageYear" of type "Bottle" is not snake_case`,
+			`missing-example: attribute "name" of method "Pick" payload has no example`,
+			`missing-example: attribute "vintageYear" of type "Bottle" has no example`,
+			`missing-security: method "Pick" of service "sommelier" has no security requirement`,
+		}},
+		{"disabled", testdata.DisabledDSL, nil},
+	}
+	for _, c := range cases {
+		t.Run(c.Name, func(t *testing.T) {
+			root := codegen.RunDSL(t, c.DSL)
+			vs := Run(root)
+			if len(vs) != len(c.Expected) {
+				t.Fatalf("got %d violations, expected %d: %v", len(vs), len(c.Expected), vs)
+			}
+			for i, v := range vs {
+				if v.String() != c.Expected[i] {
+					t.Errorf("violation %d: got %q, expected %q", i, v.String(), c.Expected[i])
+				}
+			}
+		})
+	}
+}
+
+func TestRegister(t *testing.T) {
+	saved := rules
+	defer func() { rules = saved }()
+	rules = nil
+
+	Register(NewRule("custom", "custom rule", func(root *expr.RootExpr) []*Violation {
+		return []*Violation{{Message: "api " + root.API.Name}}
+	}))
+	if len(Rules()) != 1 || Rules()[0].Description() != "custom rule" {
+		t.Fatalf("got rules %v, expected the custom rule", Rules())
+	}
+	root := codegen.RunDSL(t, testdata.CleanDSL)
+	vs := Run(root)
+	if len(vs) != 1 || vs[0].String() != "custom: api cellar" {
+		t.Errorf("got violations %v, expected custom: api cellar", vs)
+	}
+
+	defer func() {
+		if recover() == nil {
+			t.Error("expected Register to panic on duplicate rule name")
+		}
+	}()
+	Register(NewRule("custom", "", nil))
+}
@@ -0,0 +1,165 @@
+package lint
+
+import (
+	"fmt"
+
+	"goa.design/goa/v3/codegen"
+	"goa.design/goa/v3/expr"
+)
+
+func init() {
+	Register(NewRule("missing-description", "services, methods and user types must have a description", checkDescriptions))
+	Register(NewRule("untyped-error", "errors must use a user type such as ErrorResult", checkErrorTypes))
+	Register(NewRule("naming", "service, method and attribute names must be snake_case", checkNaming))
+	Register(NewRule("missing-example", "payload and result attributes of primitive types must have an example", checkExamples))
+	Register(NewRule("missing-security", "methods must define security requirements or use NoSecurity", checkSecurity))
+}
+
+// checkDescriptions implements the "missing-description" rule.
+func checkDescriptions(root *expr.RootExpr) []*Violation {
+	var vs []*Violation
+	for _, svc := range root.Services {
+		if svc.Description == "" {
+			vs = append(vs, &Violation{Service: svc, Message: fmt.Sprintf("service %q has no description", svc.Name)})
+		}
+		for _, m := range svc.Methods {
+			if m.Description == "" {
+				vs = append(vs, &Violation{Service: svc, Method: m, Message: fmt.Sprintf("method %q of service %q has no description", m.Name, svc.Name)})
+			}
+		}
+	}
+	for _, ut := range userTypes(root) {
+		if ut.Attribute().Description == "" {
+			vs = append(vs, &Violation{Message: fmt.Sprintf("type %q has no description", ut.Name())})
+		}
+	}
+	return vs
+}
+
+// checkErrorTypes implements the "untyped-error" rule.
+func checkErrorTypes(root *expr.RootExpr) []*Violation {
+	var vs []*Violation
+	check := func(e *expr.ErrorExpr, svc *expr.ServiceExpr, m *expr.MethodExpr, loc string) {
+		dt := e.Type
+		if ut, ok := dt.(expr.UserType); ok {
+			if ut == expr.ErrorResult || root.UserType(ut.Name()) != nil {
+				return
+			}
+			// Errors using other types are wrapped in a user type named
+			// after the error when the design is finalized.
+			dt = ut.Attribute().Type
+		}
+		vs = append(vs, &Violation{Service: svc, Method: m, Message: fmt.Sprintf("error %q of %s uses type %s instead of a user type", e.Name, loc, dt.Name())})
+	}
+	for _, e := range root.Errors {
+		check(e, nil, nil, "the API")
+	}
+	for _, svc := range root.Services {
+		for _, e := range svc.Errors {
+			check(e, svc, nil, fmt.Sprintf("service %q", svc.Name))
+		}
+		for _, m := range svc.Methods {
+			for _, e := range m.Errors {
+				check(e, svc, m, fmt.Sprintf("method %q of service %q", m.Name, svc.Name))
+			}
+		}
+	}
+	return vs
+}
+
+// checkNaming implements the "naming" rule.
+func checkNaming(root *expr.RootExpr) []*Violation {
+	var vs []*Violation
+	checkAttributes := func(att *expr.AttributeExpr, svc *expr.ServiceExpr, m *expr.MethodExpr, loc string) {
+		obj := expr.AsObject(att.Type)
+		if obj == nil {
+			return
+		}
+		for _, nat := range *obj {
+			if !isSnakeCase(nat.Name) {
+				vs = append(vs, &Violation{Service: svc, Method: m, Message: fmt.Sprintf("attribute %q of %s is not snake_case", nat.Name, loc)})
+			}
+		}
+	}
+	for _, svc := range root.Services {
+		if !isSnakeCase(svc.Name) {
+			vs = append(vs, &Violation{Service: svc, Message: fmt.Sprintf("service %q is not snake_case", svc.Name)})
+		}
+		for _, m := range svc.Methods {
+			if !isSnakeCase(m.Name) {
+				vs = append(vs, &Violation{Service: svc, Method: m, Message: fmt.Sprintf("method %q of service %q is not snake_case", m.Name, svc.Name)})
+			}
+			walkInlineTypes(m, func(att *expr.AttributeExpr, loc string) {
+				checkAttributes(att, svc, m, loc)
+			})
+		}
+	}
+	for _, ut := range userTypes(root) {
+		checkAttributes(ut.Attribute(), nil, nil, fmt.Sprintf("type %q", ut.Name()))
+	}
+	return vs
+}
+
+// checkExamples implements the "missing-example" rule.
+func checkExamples(root *expr.RootExpr) []*Violation {
+	var vs []*Violation
+	checkAttributes := func(att *expr.AttributeExpr, svc *expr.ServiceExpr, m *expr.MethodExpr, loc string) {
+		obj := expr.AsObject(att.Type)
+		if obj == nil {
+			return
+		}
+		for _, nat := range *obj {
+			if !expr.IsPrimitive(nat.Attribute.Type) || len(nat.Attribute.ExtractUserExamples()) > 0 {
+				continue
+			}
+			vs = append(vs, &Violation{Service: svc, Method: m, Message: fmt.Sprintf("attribute %q of %s has no example", nat.Name, loc)})
+		}
+	}
+	for _, svc := range root.Services {
+		for _, m := range svc.Methods {
+			walkInlineTypes(m, func(att *expr.AttributeExpr, loc string) {
+				checkAttributes(att, svc, m, loc)
+			})
+		}
+	}
+	for _, ut := range userTypes(root) {
+		checkAttributes(ut.Attribute(), nil, nil, fmt.Sprintf("type %q", ut.Name()))
+	}
+	return vs
+}
+
+// checkSecurity implements the "missing-security" rule.
+func checkSecurity(root *expr.RootExpr) []*Violation {
+	var vs []*Violation
+	for _, svc := range root.Services {
+		for _, m := range svc.Methods {
+			if len(m.Requirements) > 0 || len(svc.Requirements) > 0 || len(root.API.Requirements) > 0 {
+				continue
+			}
+			vs = append(vs, &Violation{Service: svc, Method: m, Message: fmt.Sprintf("method %q of service %q has no security requirement", m.Name, svc.Name)})
+		}
+	}
+	return vs
+}
+
+// walkInlineTypes calls fn with the payload and result of m when they are
+// not user types. User types are checked separately.
+func walkInlineTypes(m *expr.MethodExpr, fn func(att *expr.AttributeExpr, loc string)) {
+	if _, ok := m.Payload.Type.(expr.UserType); !ok {
+		fn(m.Payload, fmt.Sprintf("method %q payload", m.Name))
+	}
+	if _, ok := m.Result.Type.(expr.UserType); !ok {
+		fn(m.Result, fmt.Sprintf("method %q result", m.Name))
+	}
+}
+
+// userTypes returns the user types and result types defined in the design.
+func userTypes(root *expr.RootExpr) []expr.UserType {
+	uts := append([]expr.UserType(nil), root.Types...)
+	return append(uts, root.ResultTypes...)
+}
+
+// isSnakeCase returns true if name is written in snake_case.
+func isSnakeCase(name string) bool {
+	return codegen.SnakeCase(name) == name
+}
@@ -0,0 +1,64 @@
+package testdata
+
+import . "goa.design/goa/v3/dsl"
+
+var CleanDSL = func() {
+	var Bottle = Type("bottle", func() {
+		Description("A bottle of wine")
+		Attribute("name", String, func() {
+			Example("Sterling")
+		})
+	})
+	var _ = API("cellar", func() {
+		Security(APIKeyAuth)
+	})
+	Service("sommelier", func() {
+		Description("The sommelier service")
+		Method("pick", func() {
+			Description("Pick a bottle")
+			Payload(func() {
+				APIKey("api_key", "key", String, func() {
+					Example("secret")
+				})
+			})
+			Result(Bottle)
+			Error("not_found")
+		})
+		Method("list", func() {
+			Description("List the bottles")
+			NoSecurity()
+		})
+	})
+}
+
+var APIKeyAuth = APIKeySecurity("api_key")
+
+var ViolationsDSL = func() {
+	var Bottle = Type("Bottle", func() {
+		Attribute("vintageYear", Int)
+	})
+	Service("sommelier", func() {
+		Method("Pick", func() {
+			Payload(func() {
+				Attribute("name", String)
+			})
+			Result(Bottle)
+			Error("not_found", String)
+		})
+	})
+}
+
+var DisabledDSL = func() {
+	var _ = API("cellar", func() {
+		Meta("lint:disable", "missing-example", "naming")
+	})
+	Service("sommelier", func() {
+		Meta("lint:disable", "missing-description")
+		Method("pick", func() {
+			Meta("lint:disable", "missing-security")
+			Payload(func() {
+				Attribute("Name", String)
+			})
+		})
+	})
+}
@@ -0,0 +1,17 @@
+package middleware
+
+import "time"
+
+// Budget is the response size and latency budget of a service method as
+// defined by the "budget:response-size" and "budget:latency" meta.
+type Budget struct {
+	// Service is the name of the service.
+	Service string
+	// Method is the name of the method.
+	Method string
+	// ResponseSize is the maximum size in bytes of the response body, 0
+	// if unlimited.
+	ResponseSize int64
+	// Latency is the maximum duration of a request, 0 if unlimited.
+	Latency time.Duration
+}
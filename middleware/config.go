@@ -0,0 +1,146 @@
+package middleware
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"os/signal"
+	"sync"
+	"sync/atomic"
+	"syscall"
+	"time"
+)
+
+// Config holds the current settings of middlewares (e.g. rate limits, log
+// level, CORS origins or feature flags) and lets them be reloaded while the
+// server runs. The settings are stored in a value of type T that is swapped
+// atomically: middlewares call Load on each request and thus always observe a
+// complete and consistent set of settings. The value returned by Load must
+// not be modified, new settings are installed by storing a new value.
+//
+// Config is safe for concurrent use.
+type Config[T any] struct {
+	val atomic.Pointer[T]
+	// mu serializes updates and protects listeners.
+	mu        sync.Mutex
+	listeners []func(*T)
+}
+
+// NewConfig returns a Config initialized with v.
+func NewConfig[T any](v *T) *Config[T] {
+	c := &Config[T]{}
+	c.val.Store(v)
+	return c
+}
+
+// Load returns the current settings.
+func (c *Config[T]) Load() *T {
+	return c.val.Load()
+}
+
+// Store installs v as the current settings and calls the functions registered
+// with OnChange.
+func (c *Config[T]) Store(v *T) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.val.Store(v)
+	for _, fn := range c.listeners {
+		fn(v)
+	}
+}
+
+// OnChange calls fn with the current settings and registers it so that it is
+// called again with the new settings each time they change. Updates are
+// serialized so that fn is never called concurrently and never misses an
+// update.
+func (c *Config[T]) OnChange(fn func(*T)) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	fn(c.val.Load())
+	c.listeners = append(c.listeners, fn)
+}
+
+// Reload loads new settings using load and stores them. The current settings
+// are kept if load returns an error.
+func (c *Config[T]) Reload(load func() (*T, error)) error {
+	v, err := load()
+	if err != nil {
+		return err
+	}
+	c.Store(v)
+	return nil
+}
+
+// ReloadOnSignal reloads the settings using load each time the process
+// receives one of the given signals, SIGHUP if none is given. errh is called
+// with the errors returned by load if not nil. ReloadOnSignal blocks until ctx
+// is canceled and is meant to be run in a goroutine.
+//
+// Example:
+//
+//	cfg := middleware.NewConfig(settings)
+//	go cfg.ReloadOnSignal(ctx, middleware.LoadJSONFile[Settings]("settings.json"), logErr)
+func (c *Config[T]) ReloadOnSignal(ctx context.Context, load func() (*T, error), errh func(error), sigs ...os.Signal) {
+	if len(sigs) == 0 {
+		sigs = []os.Signal{syscall.SIGHUP}
+	}
+	ch := make(chan os.Signal, 1)
+	signal.Notify(ch, sigs...)
+	defer signal.Stop(ch)
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ch:
+			if err := c.Reload(load); err != nil && errh != nil {
+				errh(err)
+			}
+		}
+	}
+}
+
+// Watch reloads the settings using load each time the file at the given path
+// changes. The file modification time and size are checked every interval.
+// errh is called with the errors returned by load if not nil, a missing file
+// is not an error and keeps the current settings. Watch blocks until ctx is
+// canceled and is meant to be run in a goroutine.
+func (c *Config[T]) Watch(ctx context.Context, path string, interval time.Duration, load func() (*T, error), errh func(error)) {
+	var mod time.Time
+	var size int64
+	if fi, err := os.Stat(path); err == nil {
+		mod, size = fi.ModTime(), fi.Size()
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			fi, err := os.Stat(path)
+			if err != nil || fi.ModTime().Equal(mod) && fi.Size() == size {
+				continue
+			}
+			mod, size = fi.ModTime(), fi.Size()
+			if err := c.Reload(load); err != nil && errh != nil {
+				errh(err)
+			}
+		}
+	}
+}
+
+// LoadJSONFile returns a function that loads settings from the JSON file at
+// the given path for use with Reload, ReloadOnSignal and Watch.
+func LoadJSONFile[T any](path string) func() (*T, error) {
+	return func() (*T, error) {
+		b, err := os.ReadFile(path)
+		if err != nil {
+			return nil, err
+		}
+		var v T
+		if err := json.Unmarshal(b, &v); err != nil {
+			return nil, err
+		}
+		return &v, nil
+	}
+}
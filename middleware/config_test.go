@@ -0,0 +1,102 @@
+package middleware
+
+import (
+	"context"
+	"errors"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"syscall"
+	"testing"
+	"time"
+)
+
+type testSettings struct {
+	Level string `json:"level"`
+}
+
+func TestConfig(t *testing.T) {
+	c := NewConfig(&testSettings{Level: "info"})
+	var seen []string
+	c.OnChange(func(s *testSettings) { seen = append(seen, s.Level) })
+	c.Store(&testSettings{Level: "debug"})
+	if got := c.Load().Level; got != "debug" {
+		t.Errorf("got level %q, expected debug", got)
+	}
+	if err := c.Reload(func() (*testSettings, error) { return nil, errors.New("boom") }); err == nil {
+		t.Error("expected reload error")
+	}
+	if got := c.Load().Level; got != "debug" {
+		t.Errorf("got level %q after failed reload, expected debug", got)
+	}
+	if len(seen) != 2 || seen[0] != "info" || seen[1] != "debug" {
+		t.Errorf("got changes %v, expected [info debug]", seen)
+	}
+}
+
+func TestConfigWatch(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "settings.json")
+	if err := os.WriteFile(path, []byte(`{"level":"info"}`), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	c := NewConfig(&testSettings{Level: "info"})
+	changed := make(chan string, 1)
+	c.OnChange(func(s *testSettings) {
+		select {
+		case changed <- s.Level:
+		default:
+		}
+	})
+	<-changed
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go c.Watch(ctx, path, 10*time.Millisecond, LoadJSONFile[testSettings](path), nil)
+	time.Sleep(20 * time.Millisecond)
+	if err := os.WriteFile(path, []byte(`{"level":"warning"}`), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	select {
+	case got := <-changed:
+		if got != "warning" {
+			t.Errorf("got level %q, expected warning", got)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("timeout waiting for reload")
+	}
+}
+
+func TestConfigReloadOnSignal(t *testing.T) {
+	c := NewConfig(&testSettings{Level: "info"})
+	changed := make(chan string, 2)
+	c.OnChange(func(s *testSettings) { changed <- s.Level })
+	<-changed
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	// make sure the signal does not terminate the test if it is sent before
+	// ReloadOnSignal registers it
+	ignored := make(chan os.Signal, 1)
+	signal.Notify(ignored, syscall.SIGHUP)
+	defer signal.Stop(ignored)
+	load := func() (*testSettings, error) { return &testSettings{Level: "error"}, nil }
+	go c.ReloadOnSignal(ctx, load, nil)
+	p, err := os.FindProcess(os.Getpid())
+	if err != nil {
+		t.Fatal(err)
+	}
+	deadline := time.After(2 * time.Second)
+	for {
+		if err := p.Signal(syscall.SIGHUP); err != nil {
+			t.Skipf("cannot send SIGHUP: %s", err)
+		}
+		select {
+		case got := <-changed:
+			if got != "error" {
+				t.Errorf("got level %q, expected error", got)
+			}
+			return
+		case <-time.After(20 * time.Millisecond):
+		case <-deadline:
+			t.Fatal("timeout waiting for reload")
+		}
+	}
+}
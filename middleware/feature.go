@@ -0,0 +1,96 @@
+package middleware
+
+import (
+	"context"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
+
+type (
+	// FlagProvider is the interface implemented by feature flag providers.
+	// The generated HTTP servers of services whose methods are gated by
+	// feature flags (see the "feature:flag" meta) consult a FlagProvider
+	// on each request made to a gated method.
+	FlagProvider interface {
+		// Enabled returns true if the feature flag with the given name
+		// is enabled for the request with the given context.
+		Enabled(ctx context.Context, flag string) (bool, error)
+	}
+
+	// FlagFunc allows a function with appropriate signature to act as a
+	// FlagProvider. It makes it possible to adapt third party feature
+	// flag clients.
+	//
+	// Example using the LaunchDarkly client:
+	//
+	//	provider := middleware.FlagFunc(func(ctx context.Context, flag string) (bool, error) {
+	//		return ldClient.BoolVariation(flag, ldcontext.New(userID(ctx)), false)
+	//	})
+	FlagFunc func(ctx context.Context, flag string) (bool, error)
+
+	// FileFlags is a FlagProvider that reads the flags from a JSON file
+	// that maps flag names to booleans, e.g. {"new-checkout": true}. The
+	// flags may be reloaded while the server runs, see Watch.
+	FileFlags struct {
+		path  string
+		flags *Config[map[string]bool]
+	}
+
+	// envFlags is the FlagProvider returned by EnvFlags.
+	envFlags struct {
+		prefix string
+	}
+)
+
+// Enabled implements FlagProvider. It simply calls f(ctx, flag).
+func (f FlagFunc) Enabled(ctx context.Context, flag string) (bool, error) { return f(ctx, flag) }
+
+// EnvFlags returns a FlagProvider that reads the flags from the environment.
+// The name of the variable is the flag name prefixed with prefix, upper cased
+// and with the characters other than letters and digits replaced with
+// underscores (e.g. FEATURE_NEW_CHECKOUT for the flag "new-checkout" and the
+// prefix "feature_"). The value is parsed with strconv.ParseBool, flags whose
+// variable is not set are disabled.
+func EnvFlags(prefix string) FlagProvider {
+	return &envFlags{prefix: prefix}
+}
+
+// Enabled implements FlagProvider.
+func (f *envFlags) Enabled(_ context.Context, flag string) (bool, error) {
+	name := strings.Map(func(r rune) rune {
+		switch {
+		case r >= 'a' && r <= 'z':
+			return r - 'a' + 'A'
+		case r >= 'A' && r <= 'Z', r >= '0' && r <= '9':
+			return r
+		}
+		return '_'
+	}, f.prefix+flag)
+	v, ok := os.LookupEnv(name)
+	if !ok {
+		return false, nil
+	}
+	return strconv.ParseBool(v)
+}
+
+// NewFileFlags loads the feature flags from the JSON file at the given path.
+func NewFileFlags(path string) (*FileFlags, error) {
+	flags, err := LoadJSONFile[map[string]bool](path)()
+	if err != nil {
+		return nil, err
+	}
+	return &FileFlags{path: path, flags: NewConfig(flags)}, nil
+}
+
+// Enabled implements FlagProvider. Flags missing from the file are disabled.
+func (f *FileFlags) Enabled(_ context.Context, flag string) (bool, error) {
+	return (*f.flags.Load())[flag], nil
+}
+
+// Watch reloads the flags each time the file changes, see Config.Watch. Watch
+// blocks until ctx is canceled and is meant to be run in a goroutine.
+func (f *FileFlags) Watch(ctx context.Context, interval time.Duration, errh func(error)) {
+	f.flags.Watch(ctx, f.path, interval, LoadJSONFile[map[string]bool](f.path), errh)
+}
@@ -0,0 +1,55 @@
+package middleware
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestEnvFlags(t *testing.T) {
+	t.Setenv("FEATURE_NEW_CHECKOUT", "true")
+	t.Setenv("FEATURE_OLD_CHECKOUT", "0")
+	t.Setenv("FEATURE_INVALID", "maybe")
+	p := EnvFlags("feature_")
+	cases := map[string]struct {
+		Flag     string
+		Expected bool
+		Error    bool
+	}{
+		"enabled":  {"new-checkout", true, false},
+		"disabled": {"old.checkout", false, false},
+		"missing":  {"unknown", false, false},
+		"invalid":  {"invalid", false, true},
+	}
+	for k, c := range cases {
+		t.Run(k, func(t *testing.T) {
+			enabled, err := p.Enabled(context.Background(), c.Flag)
+			if (err != nil) != c.Error {
+				t.Errorf("got error %v, expected error %v", err, c.Error)
+			}
+			if enabled != c.Expected {
+				t.Errorf("got enabled %v, expected %v", enabled, c.Expected)
+			}
+		})
+	}
+}
+
+func TestFileFlags(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "flags.json")
+	if err := os.WriteFile(path, []byte(`{"beta": true, "alpha": false}`), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	p, err := NewFileFlags(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for flag, expected := range map[string]bool{"beta": true, "alpha": false, "unknown": false} {
+		if enabled, _ := p.Enabled(context.Background(), flag); enabled != expected {
+			t.Errorf("got %v for flag %q, expected %v", enabled, flag, expected)
+		}
+	}
+	if _, err := NewFileFlags(filepath.Join(t.TempDir(), "missing.json")); err == nil {
+		t.Error("expected error for missing file")
+	}
+}
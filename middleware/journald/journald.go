@@ -0,0 +1,186 @@
+// Package journald contains a middleware.Logger that sends the log entries to
+// the systemd journal using its native protocol so that the key/value pairs
+// are stored as journal fields.
+package journald
+
+import (
+	"encoding/binary"
+	"fmt"
+	"net"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+type (
+	// Logger is a middleware.Logger that sends the log entries to the
+	// systemd journal. Logger is safe for concurrent use.
+	Logger struct {
+		conn       *net.UnixConn
+		addr       *net.UnixAddr
+		identifier string
+		priority   int
+	}
+
+	// Option configures a Logger.
+	Option func(*options)
+
+	// options contains the settings of a Logger.
+	options struct {
+		socket     string
+		identifier string
+		priority   int
+	}
+)
+
+// DefaultSocket is the path to the journald native protocol socket.
+const DefaultSocket = "/run/systemd/journal/socket"
+
+// New returns a logger that sends the log entries to journald.
+//
+// The value of the "msg" key of an entry, if any, is stored in the MESSAGE
+// field and the value of its "level" key ("debug", "info", "warn", "error"
+// etc.) sets the PRIORITY field, 6 (info) by default. The other keys are
+// converted to valid field names: upper case with the invalid characters
+// replaced with underscores, e.g. "svc" is stored in the SVC field. Entries
+// must fit in a single datagram.
+//
+// Example:
+//
+//	logger, err := journald.New(journald.WithIdentifier("calc"))
+//	if err != nil {
+//	    return err
+//	}
+//	handler = httpmdlwr.Log(logger)(handler)
+func New(opts ...Option) (*Logger, error) {
+	o := &options{
+		socket:     DefaultSocket,
+		identifier: filepath.Base(os.Args[0]),
+		priority:   6,
+	}
+	for _, opt := range opts {
+		opt(o)
+	}
+	conn, err := net.ListenUnixgram("unixgram", &net.UnixAddr{Net: "unixgram"})
+	if err != nil {
+		return nil, err
+	}
+	addr := &net.UnixAddr{Name: o.socket, Net: "unixgram"}
+	return &Logger{conn: conn, addr: addr, identifier: o.identifier, priority: o.priority}, nil
+}
+
+// WithSocket sets the path to the journald socket, DefaultSocket by default.
+func WithSocket(path string) Option {
+	return func(o *options) { o.socket = path }
+}
+
+// WithIdentifier sets the SYSLOG_IDENTIFIER field of the entries, the name of
+// the executable by default.
+func WithIdentifier(id string) Option {
+	return func(o *options) { o.identifier = id }
+}
+
+// WithPriority sets the priority of the entries that have no "level" key, 6
+// (info) by default.
+func WithPriority(p int) Option {
+	return func(o *options) { o.priority = p }
+}
+
+// Log sends an entry made of the given alternating keys and values.
+func (l *Logger) Log(keyvals ...any) error {
+	if len(keyvals)%2 != 0 {
+		keyvals = append(keyvals, "MISSING")
+	}
+	var b []byte
+	priority := l.priority
+	msg := ""
+	for i := 0; i < len(keyvals); i += 2 {
+		k := fmt.Sprint(keyvals[i])
+		v := fmt.Sprintf("%+v", keyvals[i+1])
+		switch k {
+		case "level":
+			if p, ok := parsePriority(v); ok {
+				priority = p
+				continue
+			}
+		case "msg":
+			msg = v
+			continue
+		}
+		b = appendField(b, fieldName(k), v)
+	}
+	b = appendField(b, "MESSAGE", msg)
+	b = appendField(b, "PRIORITY", strconv.Itoa(priority))
+	if l.identifier != "" {
+		b = appendField(b, "SYSLOG_IDENTIFIER", l.identifier)
+	}
+	_, err := l.conn.WriteToUnix(b, l.addr)
+	return err
+}
+
+// Close closes the connection to journald.
+func (l *Logger) Close() error {
+	return l.conn.Close()
+}
+
+// appendField appends the field encoded with the journald native protocol to
+// b. Values that contain newlines are encoded with their length.
+func appendField(b []byte, name, value string) []byte {
+	b = append(b, name...)
+	if !strings.Contains(value, "\n") {
+		b = append(b, '=')
+		b = append(b, value...)
+		return append(b, '\n')
+	}
+	b = append(b, '\n')
+	b = binary.LittleEndian.AppendUint64(b, uint64(len(value)))
+	b = append(b, value...)
+	return append(b, '\n')
+}
+
+// fieldName returns a valid journal field name: upper case letters, digits
+// and underscores not starting with an underscore or a digit.
+func fieldName(k string) string {
+	n := strings.Map(func(r rune) rune {
+		switch {
+		case r >= 'a' && r <= 'z':
+			return r - 'a' + 'A'
+		case r >= 'A' && r <= 'Z', r >= '0' && r <= '9':
+			return r
+		}
+		return '_'
+	}, k)
+	n = strings.TrimLeft(n, "_")
+	if n == "" || n[0] >= '0' && n[0] <= '9' {
+		n = "X" + n
+	}
+	if len(n) > 64 {
+		n = n[:64]
+	}
+	return n
+}
+
+// parsePriority returns the syslog priority corresponding to the given level
+// name, e.g. "warn" or "error".
+func parsePriority(level string) (int, bool) {
+	switch strings.ToLower(level) {
+	case "emerg", "emergency", "panic":
+		return 0, true
+	case "alert":
+		return 1, true
+	case "crit", "critical", "fatal":
+		return 2, true
+	case "err", "error":
+		return 3, true
+	case "warn", "warning":
+		return 4, true
+	case "notice":
+		return 5, true
+	case "info":
+		return 6, true
+	case "debug", "trace":
+		return 7, true
+	}
+	return 0, false
+}
@@ -0,0 +1,53 @@
+package journald
+
+import (
+	"encoding/binary"
+	"net"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestLog(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "journal.sock")
+	server, err := net.ListenUnixgram("unixgram", &net.UnixAddr{Name: path, Net: "unixgram"})
+	if err != nil {
+		t.Skipf("cannot listen on unix socket: %s", err)
+	}
+	defer server.Close()
+	l, err := New(WithSocket(path), WithIdentifier("calc"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer l.Close()
+	if err := l.Log("msg", "hello", "level", "error", "req-id", 42, "stack", "a\nb"); err != nil {
+		t.Fatal(err)
+	}
+	buf := make([]byte, 1024)
+	server.SetReadDeadline(time.Now().Add(time.Second)) // nolint: errcheck
+	n, err := server.Read(buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	size := make([]byte, 8)
+	binary.LittleEndian.PutUint64(size, 3)
+	expected := "REQ_ID=42\nSTACK\n" + string(size) + "a\nb\nMESSAGE=hello\nPRIORITY=3\nSYSLOG_IDENTIFIER=calc\n"
+	if got := string(buf[:n]); got != expected {
+		t.Errorf("got %q, expected %q", got, expected)
+	}
+}
+
+func TestFieldName(t *testing.T) {
+	cases := map[string]string{
+		"svc":     "SVC",
+		"req-id":  "REQ_ID",
+		"_secret": "SECRET",
+		"1st":     "X1ST",
+		"":        "X",
+	}
+	for k, expected := range cases {
+		if got := fieldName(k); got != expected {
+			t.Errorf("%q: got %q, expected %q", k, got, expected)
+		}
+	}
+}
@@ -2,9 +2,13 @@ package middleware
 
 import (
 	"bytes"
+	"context"
 	"fmt"
 	"log"
 	"strings"
+	"time"
+
+	goa "goa.design/goa/v3/pkg"
 )
 
 type (
@@ -28,6 +32,71 @@ func NewLogger(l *log.Logger) Logger {
 	return &adapter{l}
 }
 
+// LogPayloads returns an endpoint middleware that logs the decoded payloads
+// and the results of the endpoints it wraps as structured fields. The values
+// that implement goa.Redacter - which includes the generated types that define
+// attributes marked as sensitive in the design - are redacted before being
+// logged. Errors are logged using their message or their redacted value if they
+// implement goa.Redacter.
+//
+// The middleware uses the request ID set by the transport RequestID middleware
+// so that the entries can be correlated with the ones created by the transport
+// Log middleware. It is meant to be applied to the generated endpoints via their
+// Use method. The transport Log middleware has no access to the decoded values.
+func LogPayloads(l Logger) func(goa.Endpoint) goa.Endpoint {
+	return func(e goa.Endpoint) goa.Endpoint {
+		return func(ctx context.Context, req any) (any, error) {
+			return logPayloads(ctx, l, e, req)
+		}
+	}
+}
+
+// LogPayloadsContext returns an endpoint middleware that logs the payloads and
+// results similarly to LogPayloads. LogPayloadsContext calls the given function
+// with the request context to extract the logger.
+func LogPayloadsContext(logFromCtx func(context.Context) Logger) func(goa.Endpoint) goa.Endpoint {
+	return func(e goa.Endpoint) goa.Endpoint {
+		return func(ctx context.Context, req any) (any, error) {
+			l := logFromCtx(ctx)
+			if l == nil {
+				return e(ctx, req)
+			}
+			return logPayloads(ctx, l, e, req)
+		}
+	}
+}
+
+// logPayloads does the actual logging given the logger.
+func logPayloads(ctx context.Context, l Logger, e goa.Endpoint, req any) (any, error) {
+	reqID := ctx.Value(RequestIDKey)
+	if reqID == nil {
+		reqID = shortID()
+	}
+	started := time.Now()
+
+	l.Log("id", reqID, // nolint: errcheck
+		"svc", ctx.Value(goa.ServiceKey),
+		"method", ctx.Value(goa.MethodKey),
+		"payload", goa.Redact(req))
+
+	res, err := e(ctx, req)
+	if err != nil {
+		var logged any = err.Error()
+		if _, ok := err.(goa.Redacter); ok {
+			logged = goa.Redact(err)
+		}
+		l.Log("id", reqID, // nolint: errcheck
+			"err", logged,
+			"time", time.Since(started).String())
+		return res, err
+	}
+
+	l.Log("id", reqID, // nolint: errcheck
+		"result", goa.Redact(res),
+		"time", time.Since(started).String())
+	return res, nil
+}
+
 func (a *adapter) Log(keyvals ...any) error {
 	n := (len(keyvals) + 1) / 2
 	if len(keyvals)%2 != 0 {
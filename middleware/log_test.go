@@ -0,0 +1,73 @@
+package middleware
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"log"
+	"strings"
+	"testing"
+
+	goa "goa.design/goa/v3/pkg"
+)
+
+type sensitivePayload struct {
+	Name   string
+	Secret string
+}
+
+func (p *sensitivePayload) Redact() any {
+	res := *p
+	res.Secret = goa.Redacted
+	return &res
+}
+
+func TestLogPayloads(t *testing.T) {
+	var buf bytes.Buffer
+	l := NewLogger(log.New(&buf, "", 0))
+	endpoint := func(_ context.Context, req any) (any, error) {
+		return req, nil
+	}
+	ctx := context.WithValue(context.Background(), RequestIDKey, "123")
+	ctx = context.WithValue(ctx, goa.ServiceKey, "svc")
+	ctx = context.WithValue(ctx, goa.MethodKey, "method")
+	p := &sensitivePayload{Name: "name", Secret: "secret"}
+
+	res, err := LogPayloads(l)(endpoint)(ctx, p)
+
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if res != p {
+		t.Errorf("got result %v, expected %v", res, p)
+	}
+	if p.Secret != "secret" {
+		t.Errorf("payload was modified, got secret %q", p.Secret)
+	}
+	got := buf.String()
+	if strings.Contains(got, "secret") {
+		t.Errorf("sensitive value was logged: %s", got)
+	}
+	for _, s := range []string{"id=123 svc=svc method=method payload=&{Name:name Secret:[REDACTED]}", "id=123 result=&{Name:name Secret:[REDACTED]}"} {
+		if !strings.Contains(got, s) {
+			t.Errorf("got log %q, expected it to contain %q", got, s)
+		}
+	}
+}
+
+func TestLogPayloadsError(t *testing.T) {
+	var buf bytes.Buffer
+	l := NewLogger(log.New(&buf, "", 0))
+	endpoint := func(context.Context, any) (any, error) {
+		return nil, errors.New("boom")
+	}
+
+	_, err := LogPayloadsContext(func(context.Context) Logger { return l })(endpoint)(context.Background(), nil)
+
+	if err == nil || err.Error() != "boom" {
+		t.Fatalf("got error %v, expected boom", err)
+	}
+	if got := buf.String(); !strings.Contains(got, "err=boom") {
+		t.Errorf("got log %q, expected it to contain the error", got)
+	}
+}
@@ -0,0 +1,39 @@
+package middleware
+
+type (
+	// MetricsProvider creates the instruments used by the built-in
+	// middlewares to record metrics so that the metrics can be sent to any
+	// backend, e.g. StatsD or Datadog, without going through a Prometheus
+	// bridge. Implementations must be safe for concurrent use.
+	MetricsProvider interface {
+		// NewCounter returns a counter with the given name and help text.
+		NewCounter(name, help string) Counter
+		// NewGauge returns a gauge with the given name and help text.
+		NewGauge(name, help string) Gauge
+		// NewHistogram returns a histogram with the given name and help
+		// text.
+		NewHistogram(name, help string) Histogram
+	}
+
+	// Counter is a metric whose value only increases. The labels are given
+	// as a sequence of alternating names and values.
+	Counter interface {
+		// Add increments the counter by delta.
+		Add(delta float64, labels ...string)
+	}
+
+	// Gauge is a metric whose value can go up and down. The labels are
+	// given as a sequence of alternating names and values.
+	Gauge interface {
+		// Set sets the value of the gauge.
+		Set(value float64, labels ...string)
+	}
+
+	// Histogram is a metric that samples observations, e.g. durations in
+	// seconds. The labels are given as a sequence of alternating names
+	// and values.
+	Histogram interface {
+		// Observe records the value.
+		Observe(value float64, labels ...string)
+	}
+)
@@ -0,0 +1,29 @@
+package middleware
+
+import (
+	"context"
+
+	goa "goa.design/goa/v3/pkg"
+)
+
+// Recover returns an endpoint middleware that recovers from the panics that
+// occur in the endpoints it wraps and returns them as *goa.PanicError errors.
+// The errors carry a snapshot of the request that includes the redacted
+// payload and, when the transport Recover middleware is used, the route and
+// path parameters of the request so that error handlers can report them.
+//
+// Example:
+//
+//	endpoints.Use(middleware.Recover())
+func Recover() func(goa.Endpoint) goa.Endpoint {
+	return func(e goa.Endpoint) goa.Endpoint {
+		return func(ctx context.Context, req any) (res any, err error) {
+			defer func() {
+				if p := recover(); p != nil {
+					res, err = nil, goa.NewPanicError(ctx, p, req)
+				}
+			}()
+			return e(ctx, req)
+		}
+	}
+}
@@ -0,0 +1,136 @@
+package middleware
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	goa "goa.design/goa/v3/pkg"
+)
+
+type (
+	// ErrorReporter is the interface implemented by the error reporting
+	// sinks, e.g. Sentry, that capture the server faults.
+	ErrorReporter interface {
+		// Report sends the report to the sink.
+		Report(ctx context.Context, r *ErrorReport) error
+	}
+
+	// ErrorReporterFunc is an adapter that makes it possible to use a
+	// function as ErrorReporter.
+	ErrorReporterFunc func(ctx context.Context, r *ErrorReport) error
+
+	// ErrorReport describes a server fault.
+	ErrorReport struct {
+		// Time is the time the error occurred.
+		Time time.Time `json:"time"`
+		// ID is the unique ID of the error occurrence if any.
+		ID string `json:"id,omitempty"`
+		// Err is the reported error.
+		Err error `json:"-"`
+		// Message is the error message.
+		Message string `json:"message"`
+		// Service is the name of the service as defined in the design.
+		Service string `json:"service,omitempty"`
+		// Method is the name of the method as defined in the design.
+		Method string `json:"method,omitempty"`
+		// Route describes the transport route of the request, e.g.
+		// "GET /bottles/42".
+		Route string `json:"route,omitempty"`
+		// Params contains the path parameters of the request.
+		Params map[string]string `json:"params,omitempty"`
+		// RequestID is the ID set by the RequestID middleware if any.
+		RequestID string `json:"request_id,omitempty"`
+		// Payload is the redacted decoded payload if any.
+		Payload any `json:"payload,omitempty"`
+		// Stack is the stack trace of the goroutine that panicked if the
+		// error was caused by a panic.
+		Stack string `json:"stack,omitempty"`
+	}
+)
+
+// ReportErrors returns an endpoint middleware that reports the server faults
+// returned by the endpoints it wraps to r, that is the errors that the
+// transports map to 5xx HTTP status codes or to the Internal and Unknown gRPC
+// codes: *goa.PanicError errors, *goa.ServiceError errors with the Fault field
+// set and errors that are neither service errors nor errors defined in the
+// design. The reports are sent in the background so that they do not delay
+// the responses, the errors returned by r are ignored.
+//
+// Example:
+//
+//	endpoints.Use(middleware.ReportErrors(sentry))
+//	endpoints.Use(middleware.Recover())
+func ReportErrors(r ErrorReporter) func(goa.Endpoint) goa.Endpoint {
+	return func(e goa.Endpoint) goa.Endpoint {
+		return func(ctx context.Context, req any) (any, error) {
+			res, err := e(ctx, req)
+			if err != nil && IsFault(err) {
+				rep := NewErrorReport(ctx, err, req)
+				go r.Report(context.Background(), rep) // nolint: errcheck
+			}
+			return res, err
+		}
+	}
+}
+
+// NewErrorReport builds the report of err given the request context and the
+// decoded payload if any. The payload is redacted with goa.Redact. The stack
+// trace, route and payload of *goa.PanicError errors are taken from their
+// snapshot.
+func NewErrorReport(ctx context.Context, err error, payload any) *ErrorReport {
+	r := &ErrorReport{
+		Time:    time.Now(),
+		Err:     err,
+		Message: err.Error(),
+		Payload: goa.Redact(payload),
+	}
+	r.Service, _ = ctx.Value(goa.ServiceKey).(string)
+	r.Method, _ = ctx.Value(goa.MethodKey).(string)
+	r.RequestID, _ = ctx.Value(RequestIDKey).(string)
+	r.Route, r.Params = goa.Route(ctx)
+	var (
+		perr *goa.PanicError
+		serr *goa.ServiceError
+	)
+	if errors.As(err, &perr) {
+		r.Stack = string(perr.Stack)
+		if s := perr.Snapshot; s != nil {
+			if s.Route != "" {
+				r.Route, r.Params = s.Route, s.Params
+			}
+			if s.Payload != nil {
+				r.Payload = s.Payload
+			}
+		}
+	}
+	if errors.As(err, &serr) {
+		r.ID = serr.ID
+	}
+	return r
+}
+
+// IsFault returns true if err is a server fault: a *goa.PanicError, a
+// *goa.ServiceError with the Fault field set or an error that is neither a
+// service error nor an error defined in the design.
+func IsFault(err error) bool {
+	var (
+		perr  *goa.PanicError
+		serr  *goa.ServiceError
+		namer goa.GoaErrorNamer
+	)
+	switch {
+	case errors.As(err, &perr):
+		return true
+	case errors.As(err, &serr):
+		return serr.Fault
+	case errors.As(err, &namer):
+		return false
+	}
+	return true
+}
+
+// Report calls f.
+func (f ErrorReporterFunc) Report(ctx context.Context, r *ErrorReport) error {
+	return f(ctx, r)
+}
@@ -0,0 +1,67 @@
+package report
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"goa.design/goa/v3/middleware"
+)
+
+func TestReporters(t *testing.T) {
+	var (
+		path, auth string
+		body       map[string]any
+	)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		path, auth = r.URL.Path, r.Header.Get("X-Sentry-Auth")+r.Header.Get("Authorization")
+		b, _ := io.ReadAll(r.Body)
+		body = nil
+		json.Unmarshal(b, &body) // nolint: errcheck
+	}))
+	defer srv.Close()
+	rep := &middleware.ErrorReport{
+		Time:    time.Date(2023, 1, 2, 3, 4, 5, 0, time.UTC),
+		Err:     errors.New("oops"),
+		Message: "oops",
+		Service: "svc",
+		Method:  "show",
+		Route:   "GET /items/1",
+		Stack:   "goroutine 1",
+	}
+
+	sentry, err := NewSentry(strings.Replace(srv.URL, "://", "://public@", 1)+"/42", nil, "prod", "v1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := sentry.Report(context.Background(), rep); err != nil {
+		t.Fatal(err)
+	}
+	if path != "/api/42/store/" || !strings.Contains(auth, "sentry_key=public") {
+		t.Errorf("got path %q and auth %q", path, auth)
+	}
+	if body["transaction"] != "svc.show" || body["environment"] != "prod" || body["timestamp"] != "2023-01-02T03:04:05.000Z" {
+		t.Errorf("got event %v", body)
+	}
+	if extra, _ := body["extra"].(map[string]any); extra["stack"] != "goroutine 1" || extra["route"] != "GET /items/1" {
+		t.Errorf("got extra %v", body["extra"])
+	}
+
+	webhook := NewWebhook(srv.URL+"/hook", nil, http.Header{"Authorization": {"Bearer token"}})
+	if err := webhook.Report(context.Background(), rep); err != nil {
+		t.Fatal(err)
+	}
+	if path != "/hook" || auth != "Bearer token" || body["message"] != "oops" || body["stack"] != "goroutine 1" {
+		t.Errorf("got path %q, auth %q and body %v", path, auth, body)
+	}
+
+	if _, err := NewSentry("https://o0.ingest.sentry.io/42", nil, "", ""); err == nil {
+		t.Error("expected an error for a DSN without key")
+	}
+}
@@ -0,0 +1,151 @@
+package report
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+
+	goahttp "goa.design/goa/v3/http"
+	"goa.design/goa/v3/middleware"
+	goa "goa.design/goa/v3/pkg"
+)
+
+type (
+	// Sentry is an ErrorReporter that sends the reports as events to Sentry
+	// using the Sentry store API so that the Sentry SDK is not required.
+	Sentry struct {
+		endpoint    string
+		key         string
+		doer        goahttp.Doer
+		environment string
+		release     string
+	}
+
+	// sentryEvent is the subset of the Sentry event payload produced by
+	// Sentry.
+	sentryEvent struct {
+		EventID     string            `json:"event_id"`
+		Timestamp   string            `json:"timestamp"`
+		Level       string            `json:"level"`
+		Platform    string            `json:"platform"`
+		Logger      string            `json:"logger"`
+		Transaction string            `json:"transaction,omitempty"`
+		Environment string            `json:"environment,omitempty"`
+		Release     string            `json:"release,omitempty"`
+		Message     string            `json:"message"`
+		Exception   *sentryExceptions `json:"exception"`
+		Tags        map[string]string `json:"tags,omitempty"`
+		Extra       map[string]any    `json:"extra,omitempty"`
+	}
+
+	// sentryExceptions is the exception interface of a Sentry event.
+	sentryExceptions struct {
+		Values []*sentryException `json:"values"`
+	}
+
+	// sentryException describes an exception of a Sentry event.
+	sentryException struct {
+		Type  string `json:"type"`
+		Value string `json:"value"`
+	}
+)
+
+// NewSentry returns a reporter that sends the reports to the Sentry project
+// identified by the given DSN (e.g. "https://<key>@o0.ingest.sentry.io/<id>")
+// using doer, http.DefaultClient if nil. environment and release are set on
+// the events if not empty.
+func NewSentry(dsn string, doer goahttp.Doer, environment, release string) (*Sentry, error) {
+	u, err := url.Parse(dsn)
+	if err != nil {
+		return nil, fmt.Errorf("invalid Sentry DSN: %w", err)
+	}
+	if u.User == nil || u.User.Username() == "" {
+		return nil, fmt.Errorf("invalid Sentry DSN: missing public key")
+	}
+	idx := strings.LastIndex(u.Path, "/")
+	project := u.Path[idx+1:]
+	if project == "" {
+		return nil, fmt.Errorf("invalid Sentry DSN: missing project ID")
+	}
+	if doer == nil {
+		doer = http.DefaultClient
+	}
+	endpoint := fmt.Sprintf("%s://%s%s/api/%s/store/", u.Scheme, u.Host, u.Path[:idx], project)
+	return &Sentry{
+		endpoint:    endpoint,
+		key:         u.User.Username(),
+		doer:        doer,
+		environment: environment,
+		release:     release,
+	}, nil
+}
+
+// Report sends the report to Sentry.
+func (s *Sentry) Report(ctx context.Context, r *middleware.ErrorReport) error {
+	body, err := json.Marshal(s.event(r))
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, s.endpoint, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("X-Sentry-Auth", fmt.Sprintf("Sentry sentry_version=7, sentry_client=goa/%s, sentry_key=%s", goa.Version(), s.key))
+	return send(s.doer, req)
+}
+
+// event builds the Sentry event corresponding to r.
+func (s *Sentry) event(r *middleware.ErrorReport) *sentryEvent {
+	id := make([]byte, 16)
+	rand.Read(id) // nolint: errcheck
+	typ := fmt.Sprintf("%T", r.Err)
+	if r.Err == nil {
+		typ = "error"
+	}
+	ev := &sentryEvent{
+		EventID:     hex.EncodeToString(id),
+		Timestamp:   r.Time.UTC().Format("2006-01-02T15:04:05.000Z"),
+		Level:       "error",
+		Platform:    "go",
+		Logger:      "goa",
+		Environment: s.environment,
+		Release:     s.release,
+		Message:     r.Message,
+		Exception:   &sentryExceptions{Values: []*sentryException{{Type: typ, Value: r.Message}}},
+		Tags:        make(map[string]string),
+		Extra:       make(map[string]any),
+	}
+	if r.Service != "" {
+		ev.Tags["service"] = r.Service
+		ev.Transaction = r.Service + "." + r.Method
+	}
+	if r.Method != "" {
+		ev.Tags["method"] = r.Method
+	}
+	if r.RequestID != "" {
+		ev.Tags["request_id"] = r.RequestID
+	}
+	if r.ID != "" {
+		ev.Tags["error_id"] = r.ID
+	}
+	if r.Route != "" {
+		ev.Extra["route"] = r.Route
+	}
+	if len(r.Params) > 0 {
+		ev.Extra["params"] = r.Params
+	}
+	if r.Payload != nil {
+		ev.Extra["payload"] = r.Payload
+	}
+	if r.Stack != "" {
+		ev.Extra["stack"] = r.Stack
+	}
+	return ev
+}
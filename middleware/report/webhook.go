@@ -0,0 +1,65 @@
+// Package report contains ErrorReporter implementations that send the server
+// faults reported by the ReportErrors middlewares to error reporting services.
+package report
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+
+	goahttp "goa.design/goa/v3/http"
+	"goa.design/goa/v3/middleware"
+)
+
+// Webhook is an ErrorReporter that posts the reports as JSON to a URL.
+type Webhook struct {
+	url    string
+	doer   goahttp.Doer
+	header http.Header
+}
+
+// NewWebhook returns a reporter that posts the JSON representation of the
+// reports to url using doer, http.DefaultClient if nil. The given header is
+// added to the requests, e.g. to authenticate them.
+func NewWebhook(url string, doer goahttp.Doer, header http.Header) *Webhook {
+	if doer == nil {
+		doer = http.DefaultClient
+	}
+	return &Webhook{url: url, doer: doer, header: header}
+}
+
+// Report posts the report to the webhook URL.
+func (w *Webhook) Report(ctx context.Context, r *middleware.ErrorReport) error {
+	body, err := json.Marshal(r)
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, w.url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	for k, v := range w.header {
+		req.Header[k] = v
+	}
+	req.Header.Set("Content-Type", "application/json")
+	return send(w.doer, req)
+}
+
+// send makes the request and returns an error if the response status code is
+// not 2xx.
+func send(doer goahttp.Doer, req *http.Request) error {
+	resp, err := doer.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		b, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		return fmt.Errorf("error report rejected with status %d: %s", resp.StatusCode, bytes.TrimSpace(b))
+	}
+	io.Copy(io.Discard, resp.Body) // nolint: errcheck
+	return nil
+}
@@ -0,0 +1,64 @@
+package middleware
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	goa "goa.design/goa/v3/pkg"
+)
+
+type designError struct{}
+
+func (designError) Error() string        { return "design error" }
+func (designError) GoaErrorName() string { return "design" }
+
+func TestReportErrors(t *testing.T) {
+	cases := map[string]struct {
+		err      error
+		panics   bool
+		reported bool
+	}{
+		"success":       {nil, false, false},
+		"fault":         {goa.Fault("oops"), false, true},
+		"not-fault":     {goa.PermanentError("invalid", "bad request"), false, false},
+		"design-error":  {designError{}, false, false},
+		"unknown-error": {errors.New("unknown"), false, true},
+		"panic":         {nil, true, true},
+	}
+	for name, c := range cases {
+		t.Run(name, func(t *testing.T) {
+			reports := make(chan *ErrorReport, 1)
+			rep := ErrorReporterFunc(func(_ context.Context, r *ErrorReport) error {
+				reports <- r
+				return nil
+			})
+			e := ReportErrors(rep)(Recover()(func(ctx context.Context, req any) (any, error) {
+				if c.panics {
+					panic("boom")
+				}
+				return nil, c.err
+			}))
+			ctx := context.WithValue(context.Background(), goa.ServiceKey, "svc")
+			ctx = context.WithValue(ctx, RequestIDKey, "req-1")
+			e(ctx, "payload") // nolint: errcheck
+			select {
+			case r := <-reports:
+				if !c.reported {
+					t.Fatalf("unexpected report %+v", r)
+				}
+				if r.Service != "svc" || r.RequestID != "req-1" || r.Payload != "payload" {
+					t.Errorf("got report %+v", r)
+				}
+				if c.panics && (r.Stack == "" || r.Message != "panic: boom") {
+					t.Errorf("got report %+v, expected a stack trace", r)
+				}
+			case <-time.After(50 * time.Millisecond):
+				if c.reported {
+					t.Error("error was not reported")
+				}
+			}
+		})
+	}
+}
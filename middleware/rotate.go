@@ -0,0 +1,209 @@
+package middleware
+
+import (
+	"context"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+type (
+	// RotatingFile is an io.WriteCloser that writes to a file and rotates
+	// it when it grows larger than a maximum size or gets older than a
+	// maximum age. Rotated files are renamed by appending the rotation time
+	// to their name. RotatingFile is safe for concurrent use.
+	RotatingFile struct {
+		path     string
+		maxSize  int64
+		maxAge   time.Duration
+		backups  int
+		now      func() time.Time
+		mu       sync.Mutex
+		file     *os.File
+		size     int64
+		openedAt time.Time
+	}
+
+	// RotateOption configures a RotatingFile.
+	RotateOption func(*RotatingFile)
+)
+
+// rotateTimeFormat is the format of the time appended to the rotated files.
+const rotateTimeFormat = "20060102T150405.000"
+
+// NewRotatingFile opens the file with the given path for appending, creating
+// it if needed. The file is not rotated unless RotateSize or RotateAge is
+// given.
+//
+// Example:
+//
+//	f, err := middleware.NewRotatingFile("/var/log/app/access.log",
+//	    middleware.RotateSize(100<<20), middleware.RotateBackups(10))
+//	if err != nil {
+//	    return err
+//	}
+//	defer f.Close()
+//	go f.ReopenOnSignal(ctx, logErr)
+func NewRotatingFile(path string, opts ...RotateOption) (*RotatingFile, error) {
+	f := &RotatingFile{path: path, now: time.Now}
+	for _, opt := range opts {
+		opt(f)
+	}
+	if err := f.open(); err != nil {
+		return nil, err
+	}
+	return f, nil
+}
+
+// RotateSize rotates the file before a write makes it larger than n bytes.
+func RotateSize(n int64) RotateOption {
+	return func(f *RotatingFile) { f.maxSize = n }
+}
+
+// RotateAge rotates the file on the first write that occurs d or more after
+// the file was opened.
+func RotateAge(d time.Duration) RotateOption {
+	return func(f *RotatingFile) { f.maxAge = d }
+}
+
+// RotateBackups deletes the oldest rotated files so that at most n of them
+// are kept. All the rotated files are kept if n is 0.
+func RotateBackups(n int) RotateOption {
+	return func(f *RotatingFile) { f.backups = n }
+}
+
+// Write writes b to the file, rotating it first if needed.
+func (f *RotatingFile) Write(b []byte) (int, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.file == nil {
+		return 0, os.ErrClosed
+	}
+	if f.size > 0 && (f.maxSize > 0 && f.size+int64(len(b)) > f.maxSize ||
+		f.maxAge > 0 && f.now().Sub(f.openedAt) >= f.maxAge) {
+		if err := f.rotate(); err != nil {
+			return 0, err
+		}
+	}
+	n, err := f.file.Write(b)
+	f.size += int64(n)
+	return n, err
+}
+
+// Rotate renames the file and opens a new one.
+func (f *RotatingFile) Rotate() error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.rotate()
+}
+
+// Reopen closes and reopens the file without renaming it. Reopen is meant to
+// be called after an external tool such as logrotate has moved the file.
+func (f *RotatingFile) Reopen() error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.file != nil {
+		f.file.Close() // nolint: errcheck
+	}
+	return f.open()
+}
+
+// ReopenOnSignal reopens the file each time the process receives one of the
+// given signals, SIGUSR1 if none is given (no signal on Windows). errh is
+// called with the errors returned by Reopen if not nil. ReopenOnSignal blocks
+// until ctx is canceled.
+func (f *RotatingFile) ReopenOnSignal(ctx context.Context, errh func(error), sigs ...os.Signal) {
+	if len(sigs) == 0 {
+		sigs = reopenSignals
+	}
+	if len(sigs) == 0 {
+		<-ctx.Done()
+		return
+	}
+	ch := make(chan os.Signal, 1)
+	signal.Notify(ch, sigs...)
+	defer signal.Stop(ch)
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ch:
+			if err := f.Reopen(); err != nil && errh != nil {
+				errh(err)
+			}
+		}
+	}
+}
+
+// Close closes the file.
+func (f *RotatingFile) Close() error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.file == nil {
+		return os.ErrClosed
+	}
+	err := f.file.Close()
+	f.file = nil
+	return err
+}
+
+// open opens the file and initializes its size and age.
+func (f *RotatingFile) open() error {
+	file, err := os.OpenFile(f.path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0644)
+	if err != nil {
+		f.file = nil
+		return err
+	}
+	info, err := file.Stat()
+	if err != nil {
+		file.Close() // nolint: errcheck
+		f.file = nil
+		return err
+	}
+	f.file, f.size, f.openedAt = file, info.Size(), f.now()
+	return nil
+}
+
+// rotate renames the file, opens a new one and deletes the extra backups.
+func (f *RotatingFile) rotate() error {
+	if f.file != nil {
+		f.file.Close() // nolint: errcheck
+		f.file = nil
+	}
+	if err := os.Rename(f.path, f.path+"."+f.now().Format(rotateTimeFormat)); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	if err := f.open(); err != nil {
+		return err
+	}
+	if f.backups > 0 {
+		f.prune()
+	}
+	return nil
+}
+
+// prune deletes the oldest rotated files so that at most f.backups are kept.
+// Errors are ignored as pruning is best effort.
+func (f *RotatingFile) prune() {
+	matches, err := filepath.Glob(f.path + ".*")
+	if err != nil {
+		return
+	}
+	var backups []string
+	for _, m := range matches {
+		if _, err := time.Parse(rotateTimeFormat, strings.TrimPrefix(m, f.path+".")); err == nil {
+			backups = append(backups, m)
+		}
+	}
+	if len(backups) <= f.backups {
+		return
+	}
+	sort.Strings(backups)
+	for _, b := range backups[:len(backups)-f.backups] {
+		os.Remove(b) // nolint: errcheck
+	}
+}
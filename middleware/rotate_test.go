@@ -0,0 +1,106 @@
+package middleware
+
+import (
+	"context"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"syscall"
+	"testing"
+	"time"
+)
+
+func TestRotatingFileSize(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "access.log")
+	f, err := NewRotatingFile(path, RotateSize(10), RotateBackups(2))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	now := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+	f.now = func() time.Time { now = now.Add(time.Second); return now }
+	for _, l := range []string{"line 1\n", "line 2\n", "line 3\n", "line 4\n"} {
+		if _, err := f.Write([]byte(l)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	b, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(b) != "line 4\n" {
+		t.Errorf("got content %q, expected %q", b, "line 4\n")
+	}
+	backups, _ := filepath.Glob(path + ".*")
+	if len(backups) != 2 {
+		t.Fatalf("got %d backups, expected 2", len(backups))
+	}
+	if b, _ := os.ReadFile(backups[0]); string(b) != "line 2\n" {
+		t.Errorf("got oldest backup %q, expected %q", b, "line 2\n")
+	}
+}
+
+func TestRotatingFileAge(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "access.log")
+	f, err := NewRotatingFile(path, RotateAge(time.Hour))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	now := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+	f.now, f.openedAt = func() time.Time { return now }, now
+	f.Write([]byte("a\n")) // nolint: errcheck
+	now = now.Add(2 * time.Hour)
+	f.Write([]byte("b\n")) // nolint: errcheck
+	f.Write([]byte("c\n")) // nolint: errcheck
+	if b, _ := os.ReadFile(path); string(b) != "b\nc\n" {
+		t.Errorf("got content %q, expected %q", b, "b\nc\n")
+	}
+	if backups, _ := filepath.Glob(path + ".*"); len(backups) != 1 {
+		t.Errorf("got %d backups, expected 1", len(backups))
+	}
+}
+
+func TestRotatingFileReopenOnSignal(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "access.log")
+	f, err := NewRotatingFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	f.Write([]byte("before\n")) // nolint: errcheck
+	if err := os.Rename(path, filepath.Join(dir, "moved.log")); err != nil {
+		t.Fatal(err)
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	// make sure the signal does not terminate the test if it is sent before
+	// ReopenOnSignal registers it
+	ignored := make(chan os.Signal, 1)
+	signal.Notify(ignored, syscall.SIGHUP)
+	defer signal.Stop(ignored)
+	go f.ReopenOnSignal(ctx, nil, syscall.SIGHUP)
+	p, err := os.FindProcess(os.Getpid())
+	if err != nil {
+		t.Fatal(err)
+	}
+	deadline := time.After(2 * time.Second)
+	for {
+		if err := p.Signal(syscall.SIGHUP); err != nil {
+			t.Skipf("cannot send SIGHUP: %s", err)
+		}
+		if _, err := os.Stat(path); err == nil {
+			break
+		}
+		select {
+		case <-time.After(20 * time.Millisecond):
+		case <-deadline:
+			t.Fatal("timeout waiting for reopen")
+		}
+	}
+	f.Write([]byte("after\n")) // nolint: errcheck
+	if b, _ := os.ReadFile(path); string(b) != "after\n" {
+		t.Errorf("got content %q, expected %q", b, "after\n")
+	}
+}
@@ -0,0 +1,11 @@
+//go:build !windows
+
+package middleware
+
+import (
+	"os"
+	"syscall"
+)
+
+// reopenSignals are the signals that reopen a RotatingFile by default.
+var reopenSignals = []os.Signal{syscall.SIGUSR1}
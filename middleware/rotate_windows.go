@@ -0,0 +1,7 @@
+package middleware
+
+import "os"
+
+// reopenSignals are the signals that reopen a RotatingFile by default, there
+// is no user defined signal on Windows.
+var reopenSignals []os.Signal
@@ -0,0 +1,235 @@
+package middleware
+
+import (
+	"sort"
+	"sync"
+	"time"
+)
+
+type (
+	// SLO is a service level objective of a service method: the fraction
+	// of the requests given by Target must succeed within Latency over the
+	// compliance window of the tracker.
+	SLO struct {
+		// Service is the name of the service.
+		Service string `json:"service"`
+		// Method is the name of the method.
+		Method string `json:"method"`
+		// Latency is the maximum duration of a good request, 0 if only
+		// the failed requests count against the objective.
+		Latency time.Duration `json:"latency,omitempty"`
+		// Target is the fraction of good requests, e.g. 0.999.
+		Target float64 `json:"target"`
+	}
+
+	// SLOTracker tracks the compliance of service level objectives over a
+	// rolling window and calls the burn rate alert callbacks when the error
+	// budget of an objective burns too fast. SLOTracker is safe for
+	// concurrent use.
+	SLOTracker struct {
+		window time.Duration
+		bucket time.Duration
+		alerts []*burnRateAlert
+		now    func() time.Time
+
+		mu   sync.Mutex
+		slos map[SLO]*sloState
+	}
+
+	// SLOOption configures a SLOTracker.
+	SLOOption func(*SLOTracker)
+
+	// SLOStatus describes the compliance of an objective.
+	SLOStatus struct {
+		// SLO is the objective.
+		SLO SLO `json:"slo"`
+		// Good is the number of good requests in the compliance window.
+		Good int64 `json:"good"`
+		// Total is the number of requests in the compliance window.
+		Total int64 `json:"total"`
+		// Compliance is the fraction of good requests, 1 if there was
+		// no request.
+		Compliance float64 `json:"compliance"`
+		// BudgetRemaining is the fraction of the error budget that is
+		// left, negative if the objective is not met.
+		BudgetRemaining float64 `json:"budget_remaining"`
+	}
+
+	// BurnRateAlert is given to the burn rate alert callbacks when the
+	// burn rate of an objective crosses the alert threshold.
+	BurnRateAlert struct {
+		// SLO is the objective.
+		SLO SLO
+		// Window is the window over which the burn rate is computed.
+		Window time.Duration
+		// Threshold is the alert threshold.
+		Threshold float64
+		// BurnRate is the rate at which the error budget is consumed
+		// relative to the rate that exhausts it exactly at the end of
+		// the compliance window.
+		BurnRate float64
+		// Firing is true when the burn rate rises above the threshold
+		// and false when it falls back below it.
+		Firing bool
+	}
+
+	// burnRateAlert is an alert configured with WithBurnRateAlert.
+	burnRateAlert struct {
+		threshold float64
+		window    time.Duration
+		fn        func(*BurnRateAlert)
+	}
+
+	// sloState contains the request counts of an objective in a ring of
+	// buckets covering the compliance window.
+	sloState struct {
+		ids    []int64
+		good   []int64
+		total  []int64
+		firing []bool
+		// evaluated is the ID of the bucket during which the alerts
+		// were last evaluated.
+		evaluated int64
+	}
+)
+
+// sloBuckets is the number of buckets covering the compliance window.
+const sloBuckets = 1440
+
+// NewSLOTracker returns a tracker that computes the compliance of the
+// objectives over the given rolling window, e.g. 30 days. The requests are
+// counted in 1440 buckets so that the resolution of the burn rate windows is
+// window/1440 (30 minutes for a 30 days window).
+//
+// Example:
+//
+//	tracker := middleware.NewSLOTracker(30*24*time.Hour,
+//	    middleware.WithBurnRateAlert(14.4, time.Hour, page),
+//	    middleware.WithBurnRateAlert(6, 6*time.Hour, notify))
+//	ordersServer.UseSLOs(tracker)
+func NewSLOTracker(window time.Duration, opts ...SLOOption) *SLOTracker {
+	bucket := window / sloBuckets
+	if bucket <= 0 {
+		bucket = 1
+	}
+	t := &SLOTracker{
+		window: window,
+		bucket: bucket,
+		now:    time.Now,
+		slos:   make(map[SLO]*sloState),
+	}
+	for _, opt := range opts {
+		opt(t)
+	}
+	return t
+}
+
+// WithBurnRateAlert calls fn when the burn rate of an objective computed over
+// the given window rises above threshold and again when it falls back below
+// it. A burn rate of 1 consumes the error budget exactly over the compliance
+// window. The alerts are evaluated at most once per bucket as requests are
+// observed.
+func WithBurnRateAlert(threshold float64, window time.Duration, fn func(*BurnRateAlert)) SLOOption {
+	return func(t *SLOTracker) {
+		t.alerts = append(t.alerts, &burnRateAlert{threshold: threshold, window: window, fn: fn})
+	}
+}
+
+// Observe records a request made to the method of the given objective that
+// took d and whether it failed.
+func (t *SLOTracker) Observe(slo *SLO, d time.Duration, failed bool) {
+	good := !failed && (slo.Latency == 0 || d <= slo.Latency)
+	id := t.now().UnixNano() / int64(t.bucket)
+	t.mu.Lock()
+	s, ok := t.slos[*slo]
+	if !ok {
+		s = &sloState{
+			ids:       make([]int64, sloBuckets),
+			good:      make([]int64, sloBuckets),
+			total:     make([]int64, sloBuckets),
+			firing:    make([]bool, len(t.alerts)),
+			evaluated: id,
+		}
+		t.slos[*slo] = s
+	}
+	i := id % sloBuckets
+	if s.ids[i] != id {
+		s.ids[i], s.good[i], s.total[i] = id, 0, 0
+	}
+	s.total[i]++
+	if good {
+		s.good[i]++
+	}
+	var (
+		fired []*BurnRateAlert
+		fns   []func(*BurnRateAlert)
+	)
+	if s.evaluated != id {
+		s.evaluated = id
+		for j, a := range t.alerts {
+			rate := s.burnRate(slo.Target, id, int64(a.window/t.bucket))
+			if firing := rate > a.threshold; firing != s.firing[j] {
+				s.firing[j] = firing
+				fired = append(fired, &BurnRateAlert{SLO: *slo, Window: a.window, Threshold: a.threshold, BurnRate: rate, Firing: firing})
+				fns = append(fns, a.fn)
+			}
+		}
+	}
+	t.mu.Unlock()
+	for i, f := range fired {
+		fns[i](f)
+	}
+}
+
+// Status returns the compliance of the objectives for which requests were
+// observed sorted by service and method.
+func (t *SLOTracker) Status() []*SLOStatus {
+	id := t.now().UnixNano() / int64(t.bucket)
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	res := make([]*SLOStatus, 0, len(t.slos))
+	for slo, s := range t.slos {
+		good, total := s.counts(id, sloBuckets)
+		st := &SLOStatus{SLO: slo, Good: good, Total: total, Compliance: 1, BudgetRemaining: 1}
+		if total > 0 {
+			st.Compliance = float64(good) / float64(total)
+			if budget := 1 - slo.Target; budget > 0 {
+				st.BudgetRemaining = 1 - (1-st.Compliance)/budget
+			}
+		}
+		res = append(res, st)
+	}
+	sort.Slice(res, func(i, j int) bool {
+		if res[i].SLO.Service != res[j].SLO.Service {
+			return res[i].SLO.Service < res[j].SLO.Service
+		}
+		return res[i].SLO.Method < res[j].SLO.Method
+	})
+	return res
+}
+
+// counts returns the number of good and total requests in the n buckets
+// ending with the bucket with the given ID.
+func (s *sloState) counts(id, n int64) (good, total int64) {
+	if n < 1 {
+		n = 1
+	}
+	for i := range s.ids {
+		if s.ids[i] > id-n && s.ids[i] <= id {
+			good += s.good[i]
+			total += s.total[i]
+		}
+	}
+	return good, total
+}
+
+// burnRate returns the burn rate of the error budget over the n buckets
+// ending with the bucket with the given ID.
+func (s *sloState) burnRate(target float64, id, n int64) float64 {
+	good, total := s.counts(id, n)
+	budget := 1 - target
+	if total == 0 || budget <= 0 {
+		return 0
+	}
+	return (float64(total-good) / float64(total)) / budget
+}
@@ -0,0 +1,46 @@
+package middleware
+
+import (
+	"testing"
+	"time"
+)
+
+func TestSLOTracker(t *testing.T) {
+	var (
+		now    = time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC)
+		alerts []*BurnRateAlert
+	)
+	tracker := NewSLOTracker(24*time.Hour, WithBurnRateAlert(10, time.Hour, func(a *BurnRateAlert) { alerts = append(alerts, a) }))
+	tracker.now = func() time.Time { return now }
+	slo := &SLO{Service: "svc", Method: "list", Latency: 100 * time.Millisecond, Target: 0.99}
+
+	for i := 0; i < 80; i++ {
+		tracker.Observe(slo, 10*time.Millisecond, false)
+	}
+	tracker.Observe(slo, time.Second, false) // too slow
+	for i := 0; i < 19; i++ {
+		tracker.Observe(slo, 10*time.Millisecond, true)
+	}
+	status := tracker.Status()
+	if len(status) != 1 {
+		t.Fatalf("got %d statuses, expected 1", len(status))
+	}
+	if s := status[0]; s.Good != 80 || s.Total != 100 || s.Compliance != 0.8 {
+		t.Errorf("got status %+v", s)
+	}
+	if len(alerts) != 0 {
+		t.Fatalf("got %d alerts before the end of the bucket, expected none", len(alerts))
+	}
+
+	now = now.Add(2 * time.Minute) // next bucket
+	tracker.Observe(slo, 10*time.Millisecond, false)
+	if len(alerts) != 1 || !alerts[0].Firing || alerts[0].BurnRate < 10 {
+		t.Fatalf("got alerts %+v, expected a firing alert", alerts)
+	}
+
+	now = now.Add(2 * time.Hour) // the errors are out of the alert window
+	tracker.Observe(slo, 10*time.Millisecond, false)
+	if len(alerts) != 2 || alerts[1].Firing {
+		t.Fatalf("got alerts %+v, expected a resolved alert", alerts)
+	}
+}
@@ -0,0 +1,138 @@
+// Package statsd contains a MetricsProvider that sends the metrics recorded by
+// the goa middlewares to a StatsD or DogStatsD (Datadog agent) server.
+package statsd
+
+import (
+	"net"
+	"strconv"
+	"strings"
+	"sync"
+
+	"goa.design/goa/v3/middleware"
+)
+
+type (
+	// Client is a middleware.MetricsProvider that sends the metrics to a
+	// StatsD server over UDP. Client is safe for concurrent use.
+	Client struct {
+		prefix string
+		dog    bool
+		tags   []string
+
+		mu   sync.Mutex
+		conn net.Conn
+	}
+
+	// Option configures a Client.
+	Option func(*Client)
+
+	// metric is a counter, gauge or histogram sent by a Client.
+	metric struct {
+		c    *Client
+		name string
+		typ  string
+	}
+)
+
+// New returns a client that sends the metrics to the StatsD server listening
+// on the given UDP address, e.g. "127.0.0.1:8125". StatsD has no notion of
+// labels: the label values are appended to the metric names unless DogStatsD
+// tags are enabled with WithDogStatsD.
+func New(addr string, opts ...Option) (*Client, error) {
+	conn, err := net.Dial("udp", addr)
+	if err != nil {
+		return nil, err
+	}
+	c := &Client{conn: conn}
+	for _, opt := range opts {
+		opt(c)
+	}
+	return c, nil
+}
+
+// WithPrefix sets the prefix prepended to the metric names, e.g. "myapp.".
+func WithPrefix(prefix string) Option {
+	return func(c *Client) { c.prefix = prefix }
+}
+
+// WithDogStatsD sends the labels as DogStatsD tags and the histograms with
+// the DogStatsD histogram type. The given tags, e.g. "env:prod", are added to
+// all the metrics.
+func WithDogStatsD(tags ...string) Option {
+	return func(c *Client) {
+		c.dog = true
+		c.tags = tags
+	}
+}
+
+// NewCounter returns a counter sent with the StatsD counter type.
+func (c *Client) NewCounter(name, _ string) middleware.Counter {
+	return &metric{c: c, name: name, typ: "c"}
+}
+
+// NewGauge returns a gauge sent with the StatsD gauge type.
+func (c *Client) NewGauge(name, _ string) middleware.Gauge {
+	return &metric{c: c, name: name, typ: "g"}
+}
+
+// NewHistogram returns a histogram sent with the DogStatsD histogram type or
+// the StatsD timer type. The values are sent as given, the StatsD server
+// interprets timer values as milliseconds.
+func (c *Client) NewHistogram(name, _ string) middleware.Histogram {
+	typ := "ms"
+	if c.dog {
+		typ = "h"
+	}
+	return &metric{c: c, name: name, typ: typ}
+}
+
+// Close closes the connection to the server.
+func (c *Client) Close() error {
+	return c.conn.Close()
+}
+
+// Add implements middleware.Counter.
+func (m *metric) Add(delta float64, labels ...string) { m.c.send(m.name, m.typ, delta, labels) }
+
+// Set implements middleware.Gauge.
+func (m *metric) Set(value float64, labels ...string) { m.c.send(m.name, m.typ, value, labels) }
+
+// Observe implements middleware.Histogram.
+func (m *metric) Observe(value float64, labels ...string) { m.c.send(m.name, m.typ, value, labels) }
+
+// send writes the metric line to the connection. Errors are ignored as StatsD
+// metrics are sent on a best effort basis.
+func (c *Client) send(name, typ string, value float64, labels []string) {
+	var b strings.Builder
+	b.WriteString(c.prefix)
+	b.WriteString(name)
+	if !c.dog {
+		for i := 1; i < len(labels); i += 2 {
+			b.WriteByte('.')
+			b.WriteString(labels[i])
+		}
+	}
+	b.WriteByte(':')
+	b.WriteString(strconv.FormatFloat(value, 'f', -1, 64))
+	b.WriteByte('|')
+	b.WriteString(typ)
+	if c.dog && (len(c.tags) > 0 || len(labels) > 1) {
+		b.WriteString("|#")
+		sep := ""
+		for _, t := range c.tags {
+			b.WriteString(sep)
+			b.WriteString(t)
+			sep = ","
+		}
+		for i := 1; i < len(labels); i += 2 {
+			b.WriteString(sep)
+			b.WriteString(labels[i-1])
+			b.WriteByte(':')
+			b.WriteString(labels[i])
+			sep = ","
+		}
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.conn.Write([]byte(b.String())) // nolint: errcheck
+}
@@ -0,0 +1,52 @@
+package statsd
+
+import (
+	"net"
+	"testing"
+	"time"
+)
+
+func TestClient(t *testing.T) {
+	cases := map[string]struct {
+		opts     []Option
+		expected []string
+	}{
+		"statsd": {
+			[]Option{WithPrefix("app.")},
+			[]string{"app.requests.2xx:1|c", "app.open:3|g", "app.latency.2xx:0.25|ms"},
+		},
+		"dogstatsd": {
+			[]Option{WithDogStatsD("env:prod")},
+			[]string{"requests:1|c|#env:prod,code:2xx", "open:3|g|#env:prod", "latency:0.25|h|#env:prod,code:2xx"},
+		},
+	}
+	for name, c := range cases {
+		t.Run(name, func(t *testing.T) {
+			pc, err := net.ListenPacket("udp", "127.0.0.1:0")
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer pc.Close()
+			client, err := New(pc.LocalAddr().String(), c.opts...)
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer client.Close()
+			client.NewCounter("requests", "").Add(1, "code", "2xx")
+			client.NewGauge("open", "").Set(3)
+			client.NewHistogram("latency", "").Observe(0.25, "code", "2xx")
+
+			buf := make([]byte, 1024)
+			for _, expected := range c.expected {
+				pc.SetReadDeadline(time.Now().Add(time.Second)) // nolint: errcheck
+				n, _, err := pc.ReadFrom(buf)
+				if err != nil {
+					t.Fatal(err)
+				}
+				if got := string(buf[:n]); got != expected {
+					t.Errorf("got %q, expected %q", got, expected)
+				}
+			}
+		})
+	}
+}
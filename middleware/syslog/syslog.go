@@ -0,0 +1,343 @@
+// Package syslog contains a middleware.Logger that sends the log entries to a
+// syslog server using the RFC 5424 format with the key/value pairs encoded as
+// structured data.
+package syslog
+
+import (
+	"fmt"
+	"net"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+type (
+	// Logger is a middleware.Logger that sends the log entries to a syslog
+	// server. Logger is safe for concurrent use.
+	Logger struct {
+		network  string
+		addr     string
+		facility Facility
+		severity Severity
+		hostname string
+		appName  string
+		sdID     string
+		now      func() time.Time
+
+		mu     sync.Mutex
+		conn   net.Conn
+		stream bool
+	}
+
+	// Option configures a Logger.
+	Option func(*Logger)
+
+	// Facility is a syslog facility.
+	Facility int
+
+	// Severity is a syslog severity.
+	Severity int
+)
+
+// Syslog facilities.
+const (
+	Kern Facility = iota
+	User
+	Mail
+	Daemon
+	Auth
+	Syslog
+	Lpr
+	News
+	Uucp
+	Cron
+	AuthPriv
+	Ftp
+	Local0 Facility = iota + 4
+	Local1
+	Local2
+	Local3
+	Local4
+	Local5
+	Local6
+	Local7
+)
+
+// Syslog severities.
+const (
+	Emergency Severity = iota
+	Alert
+	Critical
+	Error
+	Warning
+	Notice
+	Info
+	Debug
+)
+
+// DefaultSDID is the default ID of the structured data element that contains
+// the key/value pairs of the log entries. 32473 is the private enterprise
+// number reserved for documentation by RFC 5612, use WithSDID to set an ID
+// based on your own enterprise number.
+const DefaultSDID = "goa@32473"
+
+// New returns a logger that sends the log entries to the syslog server
+// listening on the given address. network is one of "udp", "tcp", "unix" or
+// "unixgram". The local syslog daemon is used if network and addr are empty.
+// The entries sent over stream connections are framed using octet counting
+// (RFC 6587).
+//
+// The severity of an entry is given by the value of its "level" key if any
+// ("debug", "info", "warn", "error" etc.) and defaults to Info. The value of
+// the "msg" key, if any, is used as the message of the entry. All the other
+// key/value pairs are sent as parameters of the structured data element.
+//
+// Example:
+//
+//	logger, err := syslog.New("udp", "logs.internal:514", syslog.WithFacility(syslog.Local0))
+//	if err != nil {
+//	    return err
+//	}
+//	handler = httpmdlwr.Log(logger)(handler)
+func New(network, addr string, opts ...Option) (*Logger, error) {
+	hostname, _ := os.Hostname()
+	l := &Logger{
+		network:  network,
+		addr:     addr,
+		facility: User,
+		severity: Info,
+		hostname: hostname,
+		appName:  filepath.Base(os.Args[0]),
+		sdID:     DefaultSDID,
+		now:      time.Now,
+	}
+	for _, opt := range opts {
+		opt(l)
+	}
+	if err := l.connect(); err != nil {
+		return nil, err
+	}
+	return l, nil
+}
+
+// WithFacility sets the facility of the entries, User by default.
+func WithFacility(f Facility) Option {
+	return func(l *Logger) { l.facility = f }
+}
+
+// WithSeverity sets the severity of the entries that have no "level" key,
+// Info by default.
+func WithSeverity(s Severity) Option {
+	return func(l *Logger) { l.severity = s }
+}
+
+// WithHostname sets the HOSTNAME field of the entries, the host name reported
+// by the kernel by default.
+func WithHostname(hostname string) Option {
+	return func(l *Logger) { l.hostname = hostname }
+}
+
+// WithAppName sets the APP-NAME field of the entries, the name of the
+// executable by default.
+func WithAppName(name string) Option {
+	return func(l *Logger) { l.appName = name }
+}
+
+// WithSDID sets the ID of the structured data element that contains the
+// key/value pairs, DefaultSDID by default.
+func WithSDID(id string) Option {
+	return func(l *Logger) { l.sdID = id }
+}
+
+// Log sends an entry made of the given alternating keys and values. Log
+// reconnects once to the server if sending the entry fails.
+func (l *Logger) Log(keyvals ...any) error {
+	if len(keyvals)%2 != 0 {
+		keyvals = append(keyvals, "MISSING")
+	}
+	msg := l.format(keyvals)
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if l.conn != nil {
+		if err := l.write(msg); err == nil {
+			return nil
+		}
+		l.conn.Close() // nolint: errcheck
+		l.conn = nil
+	}
+	if err := l.connect(); err != nil {
+		return err
+	}
+	return l.write(msg)
+}
+
+// Close closes the connection to the server.
+func (l *Logger) Close() error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if l.conn == nil {
+		return nil
+	}
+	err := l.conn.Close()
+	l.conn = nil
+	return err
+}
+
+// write writes the entry to the connection, framing it with its length if
+// the connection is a stream.
+func (l *Logger) write(msg []byte) error {
+	if l.stream {
+		msg = append([]byte(strconv.Itoa(len(msg))+" "), msg...)
+	}
+	_, err := l.conn.Write(msg)
+	return err
+}
+
+// connect opens the connection to the server.
+func (l *Logger) connect() error {
+	if l.network != "" || l.addr != "" {
+		conn, err := net.Dial(l.network, l.addr)
+		if err != nil {
+			return err
+		}
+		l.conn, l.stream = conn, isStream(l.network)
+		return nil
+	}
+	for _, network := range []string{"unixgram", "unix"} {
+		for _, path := range []string{"/dev/log", "/var/run/syslog", "/var/run/log"} {
+			if conn, err := net.Dial(network, path); err == nil {
+				l.conn, l.stream = conn, isStream(network)
+				return nil
+			}
+		}
+	}
+	return fmt.Errorf("syslog: local syslog server not found")
+}
+
+// isStream returns true if network is a stream oriented network.
+func isStream(network string) bool {
+	switch network {
+	case "tcp", "tcp4", "tcp6", "unix":
+		return true
+	}
+	return false
+}
+
+// format returns the RFC 5424 representation of the entry.
+func (l *Logger) format(keyvals []any) []byte {
+	severity := l.severity
+	msg := ""
+	var sd strings.Builder
+	for i := 0; i < len(keyvals); i += 2 {
+		k := fmt.Sprint(keyvals[i])
+		v := fmt.Sprintf("%+v", keyvals[i+1])
+		switch k {
+		case "level":
+			if s, ok := ParseSeverity(v); ok {
+				severity = s
+				continue
+			}
+		case "msg":
+			msg = v
+			continue
+		}
+		sd.WriteByte(' ')
+		sd.WriteString(paramName(k))
+		sd.WriteString(`="`)
+		sd.WriteString(paramValue(v))
+		sd.WriteByte('"')
+	}
+	var b strings.Builder
+	b.WriteByte('<')
+	b.WriteString(strconv.Itoa(int(l.facility)*8 + int(severity)))
+	b.WriteString(">1 ")
+	b.WriteString(l.now().Format(time.RFC3339Nano))
+	b.WriteByte(' ')
+	b.WriteString(header(l.hostname, 255))
+	b.WriteByte(' ')
+	b.WriteString(header(l.appName, 48))
+	b.WriteByte(' ')
+	b.WriteString(strconv.Itoa(os.Getpid()))
+	b.WriteString(" - ")
+	if sd.Len() == 0 {
+		b.WriteByte('-')
+	} else {
+		b.WriteByte('[')
+		b.WriteString(paramName(l.sdID))
+		b.WriteString(sd.String())
+		b.WriteByte(']')
+	}
+	if msg != "" {
+		b.WriteByte(' ')
+		b.WriteString(msg)
+	}
+	return []byte(b.String())
+}
+
+// ParseSeverity returns the severity corresponding to the given level name,
+// e.g. "warn" or "error".
+func ParseSeverity(level string) (Severity, bool) {
+	switch strings.ToLower(level) {
+	case "emerg", "emergency", "panic":
+		return Emergency, true
+	case "alert":
+		return Alert, true
+	case "crit", "critical", "fatal":
+		return Critical, true
+	case "err", "error":
+		return Error, true
+	case "warn", "warning":
+		return Warning, true
+	case "notice":
+		return Notice, true
+	case "info":
+		return Info, true
+	case "debug", "trace":
+		return Debug, true
+	}
+	return 0, false
+}
+
+// header returns s truncated to max printable US-ASCII characters, "-" if
+// empty.
+func header(s string, max int) string {
+	s = strings.Map(func(r rune) rune {
+		if r < 33 || r > 126 {
+			return '_'
+		}
+		return r
+	}, s)
+	if len(s) > max {
+		s = s[:max]
+	}
+	if s == "" {
+		return "-"
+	}
+	return s
+}
+
+// paramName returns a valid structured data name: at most 32 printable
+// US-ASCII characters other than '=', ' ', ']' and '"'.
+func paramName(s string) string {
+	s = strings.Map(func(r rune) rune {
+		if r < 33 || r > 126 || r == '=' || r == ']' || r == '"' {
+			return '_'
+		}
+		return r
+	}, s)
+	if len(s) > 32 {
+		s = s[:32]
+	}
+	if s == "" {
+		return "_"
+	}
+	return s
+}
+
+// paramValue escapes the '"', '\' and ']' characters of s.
+func paramValue(s string) string {
+	return strings.NewReplacer(`\`, `\\`, `"`, `\"`, `]`, `\]`).Replace(s)
+}
@@ -0,0 +1,77 @@
+package syslog
+
+import (
+	"bufio"
+	"io"
+	"net"
+	"os"
+	"strconv"
+	"testing"
+	"time"
+)
+
+func TestLogUDP(t *testing.T) {
+	server, err := net.ListenPacket("udp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer server.Close()
+	l, err := New("udp", server.LocalAddr().String(), WithFacility(Local0), WithHostname("host"), WithAppName("calc"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer l.Close()
+	l.now = func() time.Time { return time.Date(2020, 1, 2, 3, 4, 5, 0, time.UTC) }
+	if err := l.Log("msg", "request", "level", "warn", "path", `/a"]`, "id", 42); err != nil {
+		t.Fatal(err)
+	}
+	buf := make([]byte, 1024)
+	server.SetReadDeadline(time.Now().Add(time.Second)) // nolint: errcheck
+	n, _, err := server.ReadFrom(buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := `<132>1 2020-01-02T03:04:05Z host calc ` + strconv.Itoa(os.Getpid()) + ` - [goa@32473 path="/a\"\]" id="42"] request`
+	if got := string(buf[:n]); got != expected {
+		t.Errorf("got %q, expected %q", got, expected)
+	}
+}
+
+func TestLogTCP(t *testing.T) {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer ln.Close()
+	l, err := New("tcp", ln.Addr().String(), WithHostname("host"), WithAppName("calc"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer l.Close()
+	conn, err := ln.Accept()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+	l.Log("id", 1)                                    // nolint: errcheck
+	l.Log("msg", "hi")                                // nolint: errcheck
+	conn.SetReadDeadline(time.Now().Add(time.Second)) // nolint: errcheck
+	r := bufio.NewReader(conn)
+	for _, expected := range []string{`[goa@32473 id="1"]`, `- hi`} {
+		size, err := r.ReadString(' ')
+		if err != nil {
+			t.Fatal(err)
+		}
+		n, err := strconv.Atoi(size[:len(size)-1])
+		if err != nil {
+			t.Fatalf("invalid frame length %q", size)
+		}
+		msg := make([]byte, n)
+		if _, err := io.ReadFull(r, msg); err != nil {
+			t.Fatal(err)
+		}
+		if got := string(msg); got[len(got)-len(expected):] != expected {
+			t.Errorf("got %q, expected suffix %q", got, expected)
+		}
+	}
+}
@@ -0,0 +1,52 @@
+package goa
+
+import (
+	"context"
+	"sync"
+)
+
+// deferredBody holds the function registered by a transport request decoder
+// to decode the request body once the endpoint needs it.
+type deferredBody struct {
+	once   sync.Once
+	decode func() error
+	err    error
+}
+
+// WithDeferredBody returns a copy of ctx that lets the transport request
+// decoders defer the decoding of the request body, see DeferBody. The
+// generated endpoints of the methods with security requirements decode the
+// body with DecodeBody once the requirements are met so that the requests
+// rejected by the authentication functions or by the middlewares never pay
+// for the parsing of the body. The payload passed to the endpoint middlewares
+// only holds the values decoded from the request path, query string, headers
+// and cookies: middlewares that need the body fields must call DecodeBody
+// first.
+func WithDeferredBody(ctx context.Context) context.Context {
+	return context.WithValue(ctx, bodyKey, &deferredBody{})
+}
+
+// DeferBody registers decode as the function that decodes the request body
+// and returns true if ctx was created with WithDeferredBody. It returns false
+// otherwise, in which case the caller must decode the body right away.
+func DeferBody(ctx context.Context, decode func() error) bool {
+	b, ok := ctx.Value(bodyKey).(*deferredBody)
+	if !ok || b.decode != nil {
+		return false
+	}
+	b.decode = decode
+	return true
+}
+
+// DecodeBody decodes the request body if its decoding was deferred with
+// DeferBody. The body is decoded at most once, DecodeBody returns the same
+// error on subsequent calls. DecodeBody does nothing and returns nil if the
+// body was not deferred.
+func DecodeBody(ctx context.Context) error {
+	b, ok := ctx.Value(bodyKey).(*deferredBody)
+	if !ok || b.decode == nil {
+		return nil
+	}
+	b.once.Do(func() { b.err = b.decode() })
+	return b.err
+}
@@ -0,0 +1,43 @@
+package goa
+
+import (
+	"context"
+	"errors"
+	"testing"
+)
+
+func TestDeferBody(t *testing.T) {
+	t.Run("not deferred", func(t *testing.T) {
+		ctx := context.Background()
+		if DeferBody(ctx, func() error { return nil }) {
+			t.Fatal("expected DeferBody to return false without WithDeferredBody")
+		}
+		if err := DecodeBody(ctx); err != nil {
+			t.Errorf("unexpected error, %v", err)
+		}
+	})
+	t.Run("deferred", func(t *testing.T) {
+		var (
+			ctx   = WithDeferredBody(context.Background())
+			calls int
+			err   = errors.New("invalid body")
+		)
+		if !DeferBody(ctx, func() error { calls++; return err }) {
+			t.Fatal("expected DeferBody to return true")
+		}
+		if DeferBody(ctx, func() error { return nil }) {
+			t.Error("expected DeferBody to return false once a decoder is registered")
+		}
+		if calls != 0 {
+			t.Fatalf("got %d calls before DecodeBody, expected none", calls)
+		}
+		for i := 0; i < 2; i++ {
+			if got := DecodeBody(ctx); got != err {
+				t.Errorf("got error %v, expected %v", got, err)
+			}
+		}
+		if calls != 1 {
+			t.Errorf("got %d calls, expected one", calls)
+		}
+	})
+}
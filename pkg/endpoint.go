@@ -12,6 +12,14 @@ const (
 	// service as defined in the design. The generated transport code
 	// initializes the corresponding value prior to invoking the endpoint.
 	ServiceKey
+
+	// routeKey is the request context key used to store the function that
+	// describes the route of the request, see WithRoute.
+	routeKey
+
+	// bodyKey is the request context key used to store the request body
+	// decoding deferred by the transport, see WithDeferredBody.
+	bodyKey
 )
 
 type (
@@ -32,6 +32,12 @@ type (
 		history []ServiceError
 		// err holds the original error if exists.
 		err error
+		// key identifies the message in translation catalogs, see
+		// Translate.
+		key string
+		// args contains the values used to render the translated
+		// message indexed by placeholder name.
+		args map[string]any
 	}
 
 	// GoaErrorNamer is an interface implemented by generated error structs that
@@ -56,6 +62,9 @@ const (
 	InvalidRange = "invalid_range"
 	// InvalidLength is the error name for invalid length errors.
 	InvalidLength = "invalid_length"
+	// InvalidValue is the error name for the errors returned by the
+	// decode and validate functions of custom types.
+	InvalidValue = "invalid_value"
 )
 
 // NewServiceError creates an error.
@@ -107,27 +116,29 @@ func TemporaryTimeoutError(name, format string, v ...any) *ServiceError {
 // MissingPayloadError is the error produced by the generated code when a
 // request is missing a required payload.
 func MissingPayloadError() error {
-	return PermanentError("missing_payload", "missing required payload")
+	return withKey(PermanentError("missing_payload", "missing required payload"), MsgMissingPayload, nil)
 }
 
 // DecodePayloadError is the error produced by the generated code when a request
 // body cannot be decoded successfully.
 func DecodePayloadError(msg string) error {
-	return PermanentError("decode_payload", msg)
+	return withKey(PermanentError("decode_payload", msg), MsgDecodePayload, map[string]any{"error": msg})
 }
 
 // InvalidFieldTypeError is the error produced by the generated code when the
 // type of a payload field does not match the type defined in the design.
 func InvalidFieldTypeError(name string, val any, expected string) error {
-	return withField(name, PermanentError(
-		InvalidFieldType, "invalid value %#v for %q, must be a %s", val, name, expected))
+	return withKey(withField(name, PermanentError(
+		InvalidFieldType, "invalid value %#v for %q, must be a %s", val, name, expected)),
+		MsgInvalidFieldType, map[string]any{"field": name, "value": val, "expected": expected})
 }
 
 // MissingFieldError is the error produced by the generated code when a payload
 // is missing a required field.
 func MissingFieldError(name, context string) error {
-	return withField(name, PermanentError(
-		MissingField, "%q is missing from %s", name, context))
+	return withKey(withField(name, PermanentError(
+		MissingField, "%q is missing from %s", name, context)),
+		MsgMissingField, map[string]any{"field": name, "context": context})
 }
 
 // InvalidEnumValueError is the error produced by the generated code when the
@@ -138,48 +149,62 @@ func InvalidEnumValueError(name string, val any, allowed []any) error {
 	for i, a := range allowed {
 		elems[i] = fmt.Sprintf("%#v", a)
 	}
-	return withField(name, PermanentError(
-		InvalidEnumValue, "value of %s must be one of %s but got value %#v", name, strings.Join(elems, ", "), val))
+	return withKey(withField(name, PermanentError(
+		InvalidEnumValue, "value of %s must be one of %s but got value %#v", name, strings.Join(elems, ", "), val)),
+		MsgInvalidEnumValue, map[string]any{"field": name, "value": val, "allowed": strings.Join(elems, ", ")})
 }
 
 // InvalidFormatError is the error produced by the generated code when the value
 // of a payload field does not match the format validation defined in the
 // design.
 func InvalidFormatError(name, target string, format Format, formatError error) error {
-	return withField(name, PermanentError(
-		InvalidFormat, "%s must be formatted as a %s but got value %q, %s", name, format, target, formatError.Error()))
+	return withKey(withField(name, PermanentError(
+		InvalidFormat, "%s must be formatted as a %s but got value %q, %s", name, format, target, formatError.Error())),
+		MsgInvalidFormat, map[string]any{"field": name, "value": target, "format": format, "error": formatError.Error()})
 }
 
 // InvalidPatternError is the error produced by the generated code when the
 // value of a payload field does not match the pattern validation defined in the
 // design.
 func InvalidPatternError(name, target string, pattern string) error {
-	return withField(name, PermanentError(
-		InvalidPattern, "%s must match the regexp %q but got value %q", name, pattern, target))
+	return withKey(withField(name, PermanentError(
+		InvalidPattern, "%s must match the regexp %q but got value %q", name, pattern, target)),
+		MsgInvalidPattern, map[string]any{"field": name, "value": target, "pattern": pattern})
 }
 
 // InvalidRangeError is the error produced by the generated code when the value
 // of a payload field does not match the range validation defined in the design.
 // value may be an int or a float64.
 func InvalidRangeError(name string, target any, value any, min bool) error {
-	comp := "greater or equal"
+	comp, key := "greater or equal", MsgInvalidRangeMin
 	if !min {
-		comp = "lesser or equal"
+		comp, key = "lesser or equal", MsgInvalidRangeMax
 	}
-	return withField(name, PermanentError(
-		InvalidRange, "%s must be %s than %d but got value %#v", name, comp, value, target))
+	return withKey(withField(name, PermanentError(
+		InvalidRange, "%s must be %s than %d but got value %#v", name, comp, value, target)),
+		key, map[string]any{"field": name, "value": target, "limit": value})
 }
 
 // InvalidLengthError is the error produced by the generated code when the value
 // of a payload field does not match the length validation defined in the
 // design.
 func InvalidLengthError(name string, target any, ln, value int, min bool) error {
-	comp := "greater or equal"
+	comp, key := "greater or equal", MsgInvalidLengthMin
 	if !min {
-		comp = "lesser or equal"
+		comp, key = "lesser or equal", MsgInvalidLengthMax
 	}
-	return withField(name, PermanentError(
-		InvalidLength, "length of %s must be %s than %d but got value %#v (len=%d)", name, comp, value, target, ln))
+	return withKey(withField(name, PermanentError(
+		InvalidLength, "length of %s must be %s than %d but got value %#v (len=%d)", name, comp, value, target, ln)),
+		key, map[string]any{"field": name, "value": target, "length": ln, "limit": value})
+}
+
+// InvalidValueError is the error produced by the generated code when the decode
+// or validate function of a payload field whose type is overridden with the
+// struct:field:type metadata returns an error.
+func InvalidValueError(name string, val any, err error) error {
+	return withKey(withField(name, PermanentError(
+		InvalidValue, "invalid value %v for %q, %s", val, name, err.Error())),
+		MsgInvalidValue, map[string]any{"field": name, "value": val, "error": err.Error()})
 }
 
 // NewErrorID creates a unique 8 character ID that is well suited to use as an
@@ -264,6 +289,12 @@ func withField(field string, err *ServiceError) *ServiceError {
 	return err
 }
 
+func withKey(err *ServiceError, key string, args map[string]any) *ServiceError {
+	err.key = key
+	err.args = args
+	return err
+}
+
 func newError(name string, timeout, temporary, fault bool, format string, v ...any) *ServiceError {
 	return &ServiceError{
 		Name:      name,
@@ -0,0 +1,230 @@
+package goa
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+type (
+	// FieldMask is a set of paths designating fields of a target type, see
+	// google.protobuf.FieldMask. The generated code uses it for the payload
+	// attributes defined with the FieldMask DSL to implement the partial
+	// updates described in https://google.aip.dev/134.
+	FieldMask struct {
+		// Paths lists the paths of the fields using the design attribute
+		// names separated with dots, e.g. "address.street_name". The
+		// single path "*" designates all the fields.
+		Paths []string
+	}
+)
+
+// InvalidFieldMask is the error name for the errors produced when a field mask
+// is invalid or cannot be applied.
+const InvalidFieldMask = "invalid_field_mask"
+
+// ParseFieldMask parses the comma separated list of paths s, e.g.
+// "name,address.street_name", using the encoding of google.protobuf.FieldMask
+// in JSON. The paths may use the design attribute names or their lower camel
+// case version, e.g. "address.streetName". ParseFieldMask returns an error named
+// InvalidFieldMask if paths is not empty and s contains a path that is not
+// listed in paths. The paths of the result use the spelling given by paths.
+// Parsing an empty string returns an empty field mask.
+func ParseFieldMask(s string, paths ...string) (*FieldMask, error) {
+	var (
+		res  FieldMask
+		seen = make(map[string]struct{})
+	)
+	for _, p := range strings.Split(s, ",") {
+		p = strings.TrimSpace(p)
+		if p == "" {
+			continue
+		}
+		if p != "*" && len(paths) > 0 {
+			canonical, ok := matchMaskPath(p, paths)
+			if !ok {
+				return nil, PermanentError(InvalidFieldMask, "unknown field mask path %q", p)
+			}
+			p = canonical
+		}
+		if _, ok := seen[p]; ok {
+			continue
+		}
+		seen[p] = struct{}{}
+		res.Paths = append(res.Paths, p)
+	}
+	if _, ok := seen["*"]; ok && len(res.Paths) > 1 {
+		return nil, PermanentError(InvalidFieldMask, "field mask path \"*\" cannot be combined with other paths")
+	}
+	return &res, nil
+}
+
+// String returns the comma separated list of paths.
+func (m *FieldMask) String() string {
+	if m == nil {
+		return ""
+	}
+	return strings.Join(m.Paths, ",")
+}
+
+// IsEmpty returns true if the field mask does not designate any field.
+func (m *FieldMask) IsEmpty() bool {
+	return m == nil || len(m.Paths) == 0
+}
+
+// Contains returns true if the field mask designates the field with the given
+// path, that is if the mask lists the path, one of its parents or "*".
+func (m *FieldMask) Contains(path string) bool {
+	if m == nil {
+		return false
+	}
+	for _, p := range m.Paths {
+		if p == "*" || p == path || strings.HasPrefix(path, p+".") {
+			return true
+		}
+	}
+	return false
+}
+
+// ApplyFieldMask returns a copy of target where the fields designated by mask
+// are set to their value in update. target and update must be structs or
+// pointers to structs of the same type, they are left unchanged. Following
+// https://google.aip.dev/134 an empty mask designates the fields of update that
+// are not set to their zero value and the mask "*" replaces target with update. ApplyFieldMask returns an error
+// named InvalidFieldMask if a path does not designate a field. The generated
+// Apply functions also run the validations defined in the design on the
+// result.
+func ApplyFieldMask[T any](target, update T, mask *FieldMask) (T, error) {
+	var res T
+	doc, err := encodeTree(reflect.ValueOf(target))
+	if err != nil {
+		return res, err
+	}
+	src, err := encodeTree(reflect.ValueOf(update))
+	if err != nil {
+		return res, err
+	}
+	switch {
+	case mask.IsEmpty():
+		node, _ := src.(structNode)
+		for _, k := range populatedFields(reflect.ValueOf(update)) {
+			if doc, err = setMaskValue(doc, []string{k}, node[k]); err != nil {
+				return res, PermanentError(InvalidFieldMask, "field mask path %q: %s", k, err)
+			}
+		}
+	case mask.Paths[0] == "*":
+		doc = src
+	default:
+		for _, p := range mask.Paths {
+			path := strings.Split(p, ".")
+			v, err := maskValue(src, path)
+			if err != nil {
+				return res, PermanentError(InvalidFieldMask, "field mask path %q: %s", p, err)
+			}
+			if doc, err = setMaskValue(doc, path, v); err != nil {
+				return res, PermanentError(InvalidFieldMask, "field mask path %q: %s", p, err)
+			}
+		}
+	}
+	if err := decodeTree(doc, reflect.ValueOf(&res).Elem()); err != nil {
+		return res, PermanentError(InvalidFieldMask, "invalid field mask result: %s", err)
+	}
+	return res, nil
+}
+
+// maskValue returns the value of the field at the location of path in doc,
+// nil if one of the parent fields is nil.
+func maskValue(doc any, path []string) (any, error) {
+	for _, key := range path {
+		if doc == nil {
+			return nil, nil
+		}
+		node, ok := doc.(structNode)
+		if !ok {
+			return nil, fmt.Errorf("%q is not a field of an object", key)
+		}
+		k, ok := node.field(key)
+		if !ok {
+			return nil, fmt.Errorf("unknown field %q", key)
+		}
+		doc = node[k]
+	}
+	return doc, nil
+}
+
+// setMaskValue sets the field at the location of path in doc to val and
+// returns the result. The nil parent fields are initialized with empty
+// objects unless val is nil.
+func setMaskValue(doc any, path []string, val any) (any, error) {
+	if doc == nil && val == nil {
+		return nil, nil
+	}
+	if doc == nil {
+		doc = make(structNode)
+	}
+	node, ok := doc.(structNode)
+	if !ok {
+		return nil, fmt.Errorf("%q is not a field of an object", path[0])
+	}
+	k, ok := node.field(path[0])
+	if !ok {
+		if len(node) > 0 {
+			return nil, fmt.Errorf("unknown field %q", path[0])
+		}
+		k = path[0]
+	}
+	if len(path) == 1 {
+		node[k] = copyTree(val)
+		return node, nil
+	}
+	child, err := setMaskValue(node[k], path[1:], val)
+	if err != nil {
+		return nil, err
+	}
+	node[k] = child
+	return node, nil
+}
+
+// populatedFields returns the names of the fields of the struct v that are not
+// set to their zero value.
+func populatedFields(v reflect.Value) []string {
+	for v.Kind() == reflect.Ptr || v.Kind() == reflect.Interface {
+		if v.IsNil() {
+			return nil
+		}
+		v = v.Elem()
+	}
+	if v.Kind() != reflect.Struct {
+		return nil
+	}
+	var names []string
+	for _, f := range cachedStructInfo(v.Type()).fields {
+		if !v.Field(f.index).IsZero() {
+			names = append(names, f.name)
+		}
+	}
+	return names
+}
+
+// matchMaskPath returns the path listed in paths that matches p ignoring the
+// case and the separators of each path segment.
+func matchMaskPath(p string, paths []string) (string, bool) {
+	segs := strings.Split(p, ".")
+	for _, candidate := range paths {
+		csegs := strings.Split(candidate, ".")
+		if len(csegs) != len(segs) {
+			continue
+		}
+		match := true
+		for i := range segs {
+			if normalizeName(segs[i]) != normalizeName(csegs[i]) {
+				match = false
+				break
+			}
+		}
+		if match {
+			return candidate, true
+		}
+	}
+	return "", false
+}
@@ -0,0 +1,133 @@
+package goa
+
+import (
+	"encoding/json"
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestParseFieldMask(t *testing.T) {
+	paths := []string{"first_name", "age", "address", "address.street_name", "address.city"}
+	cases := []struct {
+		Name     string
+		Mask     string
+		Paths    []string
+		Expected []string
+		Error    string
+	}{
+		{"empty", "", paths, nil, ""},
+		{"single", "age", paths, []string{"age"}, ""},
+		{"multiple", "first_name, address.city", paths, []string{"first_name", "address.city"}, ""},
+		{"camel-case", "firstName,address.streetName", paths, []string{"first_name", "address.street_name"}, ""},
+		{"duplicate", "age,age", paths, []string{"age"}, ""},
+		{"wildcard", "*", paths, []string{"*"}, ""},
+		{"no-paths", "anything.goes", nil, []string{"anything.goes"}, ""},
+		{"unknown", "age,unknown", paths, nil, `unknown field mask path "unknown"`},
+		{"unknown-nested", "address.zip", paths, nil, `unknown field mask path "address.zip"`},
+		{"wildcard-combined", "*,age", paths, nil, `field mask path "*" cannot be combined with other paths`},
+	}
+	for _, c := range cases {
+		t.Run(c.Name, func(t *testing.T) {
+			m, err := ParseFieldMask(c.Mask, c.Paths...)
+			if c.Error != "" {
+				var serr *ServiceError
+				if !errors.As(err, &serr) || serr.Name != InvalidFieldMask || serr.Message != c.Error {
+					t.Errorf("got error %v, expected %s error %q", err, InvalidFieldMask, c.Error)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("got error %v, expected none", err)
+			}
+			if !reflect.DeepEqual(m.Paths, c.Expected) {
+				t.Errorf("got paths %#v, expected %#v", m.Paths, c.Expected)
+			}
+		})
+	}
+}
+
+func TestFieldMaskContains(t *testing.T) {
+	m := &FieldMask{Paths: []string{"address", "first_name"}}
+	for path, expected := range map[string]bool{"address": true, "address.city": true, "first_name": true, "first": false, "age": false} {
+		if got := m.Contains(path); got != expected {
+			t.Errorf("got %v for %q, expected %v", got, path, expected)
+		}
+	}
+	if (&FieldMask{Paths: []string{"*"}}).Contains("age") != true {
+		t.Errorf("got false for wildcard, expected true")
+	}
+	var nilMask *FieldMask
+	if nilMask.Contains("age") || !nilMask.IsEmpty() || nilMask.String() != "" {
+		t.Errorf("nil field mask must be empty")
+	}
+}
+
+func TestApplyFieldMask(t *testing.T) {
+	city := "Paris"
+	name := "Ada"
+	target := &patchAccount{
+		ID:        "1",
+		FirstName: &name,
+		Address:   &patchAddress{StreetName: "Main", City: &city},
+		Tags:      []string{"a", "b"},
+	}
+	newName := "Grace"
+	age := 42
+	update := &patchAccount{
+		FirstName: &newName,
+		Age:       &age,
+		Address:   &patchAddress{StreetName: "Elm"},
+	}
+	cases := []struct {
+		Name     string
+		Mask     *FieldMask
+		Expected func(a *patchAccount)
+		Error    string
+	}{
+		{"empty", nil, func(a *patchAccount) {
+			a.FirstName, a.Age, a.Address = &newName, &age, &patchAddress{StreetName: "Elm"}
+		}, ""},
+		{"field", &FieldMask{Paths: []string{"first_name"}}, func(a *patchAccount) { a.FirstName = &newName }, ""},
+		{"clear", &FieldMask{Paths: []string{"tags"}}, func(a *patchAccount) { a.Tags = nil }, ""},
+		{"nested", &FieldMask{Paths: []string{"address.street_name"}}, func(a *patchAccount) {
+			a.Address = &patchAddress{StreetName: "Elm", City: &city}
+		}, ""},
+		{"nested-clear", &FieldMask{Paths: []string{"address.city"}}, func(a *patchAccount) {
+			a.Address = &patchAddress{StreetName: "Main"}
+		}, ""},
+		{"object", &FieldMask{Paths: []string{"address"}}, func(a *patchAccount) { a.Address = &patchAddress{StreetName: "Elm"} }, ""},
+		{"wildcard", &FieldMask{Paths: []string{"*"}}, func(a *patchAccount) { *a = *update }, ""},
+		{"unknown", &FieldMask{Paths: []string{"unknown"}}, nil, `field mask path "unknown": unknown field "unknown"`},
+		{"not-object", &FieldMask{Paths: []string{"tags.name"}}, nil, `field mask path "tags.name": "name" is not a field of an object`},
+	}
+	for _, c := range cases {
+		t.Run(c.Name, func(t *testing.T) {
+			before, _ := json.Marshal(target)
+			res, err := ApplyFieldMask(target, update, c.Mask)
+			if after, _ := json.Marshal(target); string(after) != string(before) {
+				t.Errorf("target was modified: got %s, expected %s", after, before)
+			}
+			if c.Error != "" {
+				var serr *ServiceError
+				if !errors.As(err, &serr) || serr.Name != InvalidFieldMask || serr.Message != c.Error {
+					t.Errorf("got error %v, expected %s error %q", err, InvalidFieldMask, c.Error)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("got error %v, expected none", err)
+			}
+			var expected patchAccount
+			if err := json.Unmarshal(before, &expected); err != nil {
+				t.Fatal(err)
+			}
+			c.Expected(&expected)
+			if !reflect.DeepEqual(res, &expected) {
+				got, _ := json.Marshal(res)
+				exp, _ := json.Marshal(expected)
+				t.Errorf("got %s, expected %s", got, exp)
+			}
+		})
+	}
+}
@@ -0,0 +1,174 @@
+package goa
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// Message keys of the errors produced by the generated validation and decoding
+// code. The translated messages may use the placeholders listed for each key,
+// e.g. "{field} doit contenir au moins {limit} caractères".
+const (
+	// MsgMissingPayload identifies the missing payload error message.
+	MsgMissingPayload = "missing_payload"
+	// MsgDecodePayload identifies the payload decoding error message.
+	// Placeholders: {error}.
+	MsgDecodePayload = "decode_payload"
+	// MsgInvalidFieldType identifies the invalid field type error message.
+	// Placeholders: {field}, {value} and {expected}.
+	MsgInvalidFieldType = "invalid_field_type"
+	// MsgMissingField identifies the missing field error message.
+	// Placeholders: {field} and {context}.
+	MsgMissingField = "missing_field"
+	// MsgInvalidEnumValue identifies the invalid enum value error message.
+	// Placeholders: {field}, {value} and {allowed}.
+	MsgInvalidEnumValue = "invalid_enum_value"
+	// MsgInvalidFormat identifies the invalid format error message.
+	// Placeholders: {field}, {value}, {format} and {error}.
+	MsgInvalidFormat = "invalid_format"
+	// MsgInvalidPattern identifies the invalid pattern error message.
+	// Placeholders: {field}, {value} and {pattern}.
+	MsgInvalidPattern = "invalid_pattern"
+	// MsgInvalidRangeMin identifies the error message of values lesser
+	// than the Minimum or ExclusiveMinimum validation. Placeholders:
+	// {field}, {value} and {limit}.
+	MsgInvalidRangeMin = "invalid_range.min"
+	// MsgInvalidRangeMax identifies the error message of values greater
+	// than the Maximum or ExclusiveMaximum validation. Placeholders:
+	// {field}, {value} and {limit}.
+	MsgInvalidRangeMax = "invalid_range.max"
+	// MsgInvalidLengthMin identifies the error message of values shorter
+	// than the MinLength validation. Placeholders: {field}, {value},
+	// {length} and {limit}.
+	MsgInvalidLengthMin = "invalid_length.min"
+	// MsgInvalidLengthMax identifies the error message of values longer
+	// than the MaxLength validation. Placeholders: {field}, {value},
+	// {length} and {limit}.
+	MsgInvalidLengthMax = "invalid_length.max"
+	// MsgInvalidValue identifies the error message of values rejected by
+	// the decode or validate function of a custom type. Placeholders:
+	// {field}, {value} and {error}.
+	MsgInvalidValue = "invalid_value"
+)
+
+type (
+	// Translator translates error messages.
+	Translator interface {
+		// Translate returns the message identified by key in the given
+		// language rendered with args. It returns false if there is no
+		// translation.
+		Translate(lang, key string, args map[string]any) (string, bool)
+	}
+
+	// Catalog is a Translator backed by message templates indexed by
+	// language tag and message key. The templates use placeholders of the
+	// form {name} replaced with the corresponding argument. Messages for a
+	// language tag with a region (e.g. "fr-CA") fall back to the language
+	// without the region ("fr").
+	//
+	// Example:
+	//
+	//	catalog := goa.Catalog{
+	//	    "fr": {
+	//	        goa.MsgMissingField:     "{field} est manquant",
+	//	        goa.MsgInvalidLengthMin: "{field} doit contenir au moins {limit} caractères",
+	//	        "not_found":             "ressource introuvable",
+	//	    },
+	//	}
+	Catalog map[string]map[string]string
+)
+
+// Translate implements Translator.
+func (c Catalog) Translate(lang, key string, args map[string]any) (string, bool) {
+	lang = strings.ToLower(lang)
+	for {
+		if msgs, ok := c[lang]; ok {
+			if tmpl, ok := msgs[key]; ok {
+				return render(tmpl, args), true
+			}
+		}
+		i := strings.LastIndexByte(lang, '-')
+		if i < 0 {
+			return "", false
+		}
+		lang = lang[:i]
+	}
+}
+
+// Localize sets the key and arguments used to translate the message of err and
+// returns err. The key of service errors defaults to the error name and their
+// arguments to the "message" placeholder holding the untranslated message.
+//
+// Example:
+//
+//	return goa.Localize(goa.PermanentError("quota", "quota of %d exceeded", n), "quota", map[string]any{"quota": n})
+func Localize(err *ServiceError, key string, args map[string]any) *ServiceError {
+	return withKey(err, key, args)
+}
+
+// Translate returns the message of err translated in the first of the given
+// languages supported by t, the languages being listed by order of preference.
+// The messages of merged errors are translated individually and joined with
+// "; ". Errors that implement GoaErrorNamer such as the errors defined in the
+// design are translated using their name as key. Translate returns the
+// original message if there is no translation.
+func Translate(err error, t Translator, langs []string) string {
+	var se *ServiceError
+	if !errors.As(err, &se) {
+		var namer GoaErrorNamer
+		if errors.As(err, &namer) {
+			return translate(t, langs, namer.GoaErrorName(), map[string]any{"message": err.Error()}, err.Error())
+		}
+		return err.Error()
+	}
+	hist := se.History()
+	msgs := make([]string, len(hist))
+	for i, e := range hist {
+		key, args := e.key, e.args
+		if key == "" {
+			key, args = e.Name, map[string]any{"message": e.Message}
+		}
+		msgs[i] = translate(t, langs, key, args, e.Message)
+	}
+	return strings.Join(msgs, "; ")
+}
+
+// translate returns the message identified by key in the first of langs
+// supported by t, def if none is.
+func translate(t Translator, langs []string, key string, args map[string]any, def string) string {
+	for _, lang := range langs {
+		if msg, ok := t.Translate(lang, key, args); ok {
+			return msg
+		}
+	}
+	return def
+}
+
+// render replaces the {name} placeholders of tmpl with the corresponding
+// arguments. Unknown placeholders are left untouched.
+func render(tmpl string, args map[string]any) string {
+	if len(args) == 0 || !strings.Contains(tmpl, "{") {
+		return tmpl
+	}
+	var b strings.Builder
+	for {
+		i := strings.IndexByte(tmpl, '{')
+		if i < 0 {
+			break
+		}
+		j := strings.IndexByte(tmpl[i:], '}')
+		if j < 0 {
+			break
+		}
+		b.WriteString(tmpl[:i])
+		if v, ok := args[tmpl[i+1:i+j]]; ok {
+			fmt.Fprint(&b, v)
+		} else {
+			b.WriteString(tmpl[i : i+j+1])
+		}
+		tmpl = tmpl[i+j+1:]
+	}
+	b.WriteString(tmpl)
+	return b.String()
+}
@@ -0,0 +1,55 @@
+package goa
+
+import (
+	"errors"
+	"testing"
+)
+
+type namedError struct{}
+
+func (namedError) Error() string        { return "bottle not found" }
+func (namedError) GoaErrorName() string { return "not_found" }
+
+func TestTranslate(t *testing.T) {
+	catalog := Catalog{
+		"fr": {
+			MsgMissingField:     "{field} est manquant dans {context}",
+			MsgInvalidLengthMin: "{field} doit contenir au moins {limit} caractères ({length} donnés)",
+			MsgInvalidRangeMax:  "{field} doit être inférieur ou égal à {limit}",
+			MsgInvalidValue:     "{field} est invalide : {error}",
+			"not_found":         "bouteille introuvable",
+			"quota":             "quota de {quota} dépassé ({unknown})",
+		},
+		"de": {
+			MsgMissingField: "{field} fehlt",
+		},
+	}
+	cases := map[string]struct {
+		Err   error
+		Langs []string
+		// output
+		Expected string
+	}{
+		"missing-field":      {MissingFieldError("name", "body"), []string{"fr"}, "name est manquant dans body"},
+		"region-fallback":    {MissingFieldError("name", "body"), []string{"fr-ca"}, "name est manquant dans body"},
+		"preference":         {MissingFieldError("name", "body"), []string{"es", "de", "fr"}, "name fehlt"},
+		"length-min":         {InvalidLengthError("name", "ab", 2, 3, true), []string{"FR"}, "name doit contenir au moins 3 caractères (2 donnés)"},
+		"range-max":          {InvalidRangeError("age", 200, 150, false), []string{"fr"}, "age doit être inférieur ou égal à 150"},
+		"invalid-value":      {InvalidValueError("id", "x", errors.New("invalid UUID length: 1")), []string{"fr"}, "id est invalide : invalid UUID length: 1"},
+		"untranslated":       {InvalidPatternError("name", "x", "^a"), []string{"fr"}, `name must match the regexp "^a" but got value "x"`},
+		"untranslated-value": {InvalidValueError("id", "x", errors.New("invalid UUID length: 1")), []string{"de"}, `invalid value x for "id", invalid UUID length: 1`},
+		"no-language":        {MissingFieldError("name", "body"), nil, `"name" is missing from body`},
+		"merged":             {MergeErrors(MissingFieldError("name", "body"), MissingFieldError("id", "body")), []string{"de"}, "name fehlt; id fehlt"},
+		"named":              {namedError{}, []string{"fr"}, "bouteille introuvable"},
+		"service-error":      {PermanentError("not_found", "bottle %d not found", 1), []string{"fr"}, "bouteille introuvable"},
+		"localized":          {Localize(PermanentError("quota", "quota exceeded"), "quota", map[string]any{"quota": 10}), []string{"fr"}, "quota de 10 dépassé ({unknown})"},
+		"plain-error":        {errors.New("boom"), []string{"fr"}, "boom"},
+	}
+	for k, c := range cases {
+		t.Run(k, func(t *testing.T) {
+			if got := Translate(c.Err, catalog, c.Langs); got != c.Expected {
+				t.Errorf("got %q, expected %q", got, c.Expected)
+			}
+		})
+	}
+}
@@ -0,0 +1,130 @@
+package goa
+
+import (
+	"context"
+	"errors"
+)
+
+type (
+	// PageFunc retrieves the page identified by cursor. It returns the items
+	// of the page and the cursor of the next page, the zero value if the
+	// page is the last one.
+	PageFunc[T any, C comparable] func(ctx context.Context, cursor C) (items []T, next C, err error)
+
+	// Iterator iterates over the items of a paginated method, retrieving
+	// the pages as needed. The generated service clients expose iterators
+	// for the methods defined with the Paginate DSL:
+	//
+	//	it := client.ListIter(ctx, &svc.ListPayload{}, 1000)
+	//	defer it.Close()
+	//	for it.Next() {
+	//		item := it.Item()
+	//		...
+	//	}
+	//	if err := it.Err(); err != nil {
+	//		...
+	//	}
+	Iterator[T any, C comparable] struct {
+		ctx      context.Context
+		fetch    PageFunc[T, C]
+		next     C
+		fetched  bool
+		page     []T
+		item     T
+		count    int
+		maxItems int
+		err      error
+		done     bool
+	}
+)
+
+var (
+	// ErrMaxItems is the error returned by the iterators that stopped
+	// after returning the maximum number of items while more items
+	// remain.
+	ErrMaxItems = errors.New("pagination: maximum number of items reached")
+
+	// ErrCursorLoop is the error returned by the iterators when the server
+	// returns the cursor of the current page as the cursor of the next
+	// page.
+	ErrCursorLoop = errors.New("pagination: next page cursor did not advance")
+)
+
+// NewIterator returns an iterator that retrieves the pages with fetch starting
+// with the page identified by cursor. The iterator stops after returning
+// maxItems items if maxItems is greater than zero. It stops with the context
+// error when ctx is canceled.
+func NewIterator[T any, C comparable](ctx context.Context, cursor C, fetch PageFunc[T, C], maxItems int) *Iterator[T, C] {
+	return &Iterator[T, C]{ctx: ctx, fetch: fetch, next: cursor, maxItems: maxItems}
+}
+
+// Collect returns all the items of the iterator. It returns the items
+// retrieved so far and the iterator error if the iteration fails.
+func Collect[T any, C comparable](it *Iterator[T, C]) ([]T, error) {
+	defer it.Close() // nolint: errcheck
+	var items []T
+	for it.Next() {
+		items = append(items, it.Item())
+	}
+	return items, it.Err()
+}
+
+// Next advances the iterator to the next item, retrieving the next page if
+// needed. It returns false when there are no more items or if the iteration
+// failed, use Err to tell the two apart.
+func (it *Iterator[T, C]) Next() bool {
+	if it.done {
+		return false
+	}
+	if err := it.ctx.Err(); err != nil {
+		return it.fail(err)
+	}
+	var zero C
+	for len(it.page) == 0 {
+		if it.fetched && it.next == zero {
+			it.done = true
+			return false
+		}
+		if it.maxItems > 0 && it.count >= it.maxItems {
+			return it.fail(ErrMaxItems)
+		}
+		items, next, err := it.fetch(it.ctx, it.next)
+		if err != nil {
+			return it.fail(err)
+		}
+		if next != zero && next == it.next {
+			return it.fail(ErrCursorLoop)
+		}
+		it.next, it.page, it.fetched = next, items, true
+	}
+	if it.maxItems > 0 && it.count >= it.maxItems {
+		return it.fail(ErrMaxItems)
+	}
+	it.item, it.page = it.page[0], it.page[1:]
+	it.count++
+	return true
+}
+
+// Item returns the current item.
+func (it *Iterator[T, C]) Item() T {
+	return it.item
+}
+
+// Err returns the error that stopped the iteration if any.
+func (it *Iterator[T, C]) Err() error {
+	return it.err
+}
+
+// Close stops the iteration, Next returns false once the iterator is closed.
+func (it *Iterator[T, C]) Close() error {
+	it.done = true
+	it.page = nil
+	return nil
+}
+
+// fail stops the iteration with err.
+func (it *Iterator[T, C]) fail(err error) bool {
+	it.err = err
+	it.done = true
+	return false
+}
@@ -0,0 +1,90 @@
+package goa
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+	"testing"
+)
+
+// pages maps the page cursors to the page items and next cursors.
+type pages map[string]struct {
+	items []int
+	next  string
+}
+
+func (p pages) fetch(requested *[]string) PageFunc[int, string] {
+	return func(_ context.Context, cursor string) ([]int, string, error) {
+		*requested = append(*requested, cursor)
+		pg, ok := p[cursor]
+		if !ok {
+			return nil, "", fmt.Errorf("unknown cursor %q", cursor)
+		}
+		return pg.items, pg.next, nil
+	}
+}
+
+func TestIterator(t *testing.T) {
+	threePages := pages{
+		"":  {[]int{1, 2}, "b"},
+		"b": {nil, "c"},
+		"c": {[]int{3}, ""},
+	}
+	cases := []struct {
+		Name      string
+		Pages     pages
+		MaxItems  int
+		Items     []int
+		Requested []string
+		Err       error
+	}{
+		{"all-pages", threePages, 0, []int{1, 2, 3}, []string{"", "b", "c"}, nil},
+		{"max-items-reached", threePages, 2, []int{1, 2}, []string{""}, ErrMaxItems},
+		{"max-items-exact", threePages, 3, []int{1, 2, 3}, []string{"", "b", "c"}, nil},
+		{"cursor-loop", pages{"": {[]int{1}, "a"}, "a": {[]int{2}, "a"}}, 0, []int{1}, []string{"", "a"}, ErrCursorLoop},
+	}
+	for _, c := range cases {
+		t.Run(c.Name, func(t *testing.T) {
+			var requested []string
+			items, err := Collect(NewIterator(context.Background(), "", c.Pages.fetch(&requested), c.MaxItems))
+			if !errors.Is(err, c.Err) {
+				t.Errorf("got error %v, expected %v", err, c.Err)
+			}
+			if !reflect.DeepEqual(items, c.Items) {
+				t.Errorf("got items %v, expected %v", items, c.Items)
+			}
+			if !reflect.DeepEqual(requested, c.Requested) {
+				t.Errorf("got requested cursors %q, expected %q", requested, c.Requested)
+			}
+		})
+	}
+}
+
+func TestIteratorCanceled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	var requested []string
+	it := NewIterator(ctx, "", pages{"": {[]int{1, 2}, "b"}}.fetch(&requested), 0)
+	if !it.Next() || it.Item() != 1 {
+		t.Fatalf("got item %d, expected 1", it.Item())
+	}
+	cancel()
+	if it.Next() {
+		t.Errorf("got item %d, expected the iteration to stop", it.Item())
+	}
+	if !errors.Is(it.Err(), context.Canceled) {
+		t.Errorf("got error %v, expected %v", it.Err(), context.Canceled)
+	}
+}
+
+func TestIteratorClose(t *testing.T) {
+	var requested []string
+	it := NewIterator(context.Background(), "", pages{"": {[]int{1, 2}, ""}}.fetch(&requested), 0)
+	it.Next()
+	if err := it.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if it.Next() || it.Err() != nil {
+		t.Errorf("got next item or error %v after Close", it.Err())
+	}
+}
@@ -0,0 +1,83 @@
+package goa
+
+import (
+	"context"
+	"fmt"
+	"runtime/debug"
+)
+
+type (
+	// PanicError is the error produced by the recovery middlewares when they
+	// catch a panic. It carries a snapshot of the request being handled so
+	// that error handlers and error reporting sinks can report actionable
+	// diagnostics.
+	PanicError struct {
+		// Value is the value given to panic.
+		Value any
+		// Stack is the stack trace of the goroutine that panicked.
+		Stack []byte
+		// Snapshot describes the request being handled.
+		Snapshot *RequestSnapshot
+	}
+
+	// RequestSnapshot describes the request being handled when a panic
+	// occurred.
+	RequestSnapshot struct {
+		// Service is the name of the service as defined in the design.
+		Service string
+		// Method is the name of the method as defined in the design.
+		Method string
+		// Route describes the transport route of the request, e.g.
+		// "GET /bottles/42".
+		Route string
+		// Params contains the path parameters of the request.
+		Params map[string]string
+		// Payload is the redacted decoded payload if the panic occurred
+		// in the endpoint.
+		Payload any
+	}
+
+	// routeFunc returns the route and path parameters of a request.
+	routeFunc func() (string, map[string]string)
+)
+
+// NewPanicError builds a PanicError from the value recovered from a panic. The
+// snapshot contains the service and method names and the route stored in ctx
+// and the redacted payload. NewPanicError must be called from the deferred
+// function that recovered from the panic so that the stack trace is the one of
+// the panicking goroutine.
+func NewPanicError(ctx context.Context, v any, payload any) *PanicError {
+	s := &RequestSnapshot{Payload: Redact(payload)}
+	s.Service, _ = ctx.Value(ServiceKey).(string)
+	s.Method, _ = ctx.Value(MethodKey).(string)
+	s.Route, s.Params = Route(ctx)
+	return &PanicError{Value: v, Stack: debug.Stack(), Snapshot: s}
+}
+
+// WithRoute returns a copy of ctx that stores the function called by
+// NewPanicError to retrieve the route and path parameters of the request. The
+// function is called lazily so that transports may route the request after
+// the context is created.
+func WithRoute(ctx context.Context, route func() (string, map[string]string)) context.Context {
+	return context.WithValue(ctx, routeKey, routeFunc(route))
+}
+
+// Route returns the route and path parameters of the request stored in ctx
+// with WithRoute, an empty string and nil if there is none.
+func Route(ctx context.Context) (string, map[string]string) {
+	if route, ok := ctx.Value(routeKey).(routeFunc); ok {
+		return route()
+	}
+	return "", nil
+}
+
+// Error returns the panic value formatted as an error message.
+func (e *PanicError) Error() string {
+	return fmt.Sprintf("panic: %v", e.Value)
+}
+
+// Unwrap returns the panic value if it is an error.
+func (e *PanicError) Unwrap() error {
+	err, _ := e.Value.(error)
+	return err
+}
@@ -0,0 +1,740 @@
+package goa
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+type (
+	// Patch is a JSON Patch (RFC 6902) or JSON Merge Patch (RFC 7396)
+	// document. The generated code uses it for the payload attributes
+	// defined with the Patch DSL. Decoding a JSON array produces a JSON
+	// Patch, decoding a JSON object a JSON Merge Patch.
+	Patch struct {
+		// Operations lists the operations of a JSON Patch document, nil
+		// for JSON Merge Patch documents.
+		Operations []*PatchOperation
+		// Merge is the JSON Merge Patch document, nil for JSON Patch
+		// documents.
+		Merge json.RawMessage
+	}
+
+	// PatchOperation is a JSON Patch operation.
+	PatchOperation struct {
+		// Op is the operation: "add", "remove", "replace", "move",
+		// "copy" or "test".
+		Op string `json:"op"`
+		// Path is the JSON Pointer (RFC 6901) to the target location.
+		Path string `json:"path"`
+		// From is the JSON Pointer to the source location of the "move"
+		// and "copy" operations.
+		From string `json:"from,omitempty"`
+		// Value is the value of the "add", "replace" and "test"
+		// operations.
+		Value json.RawMessage `json:"value,omitempty"`
+	}
+
+	// structNode is the JSON representation of a Go struct used when
+	// applying patches. The keys are the Go field names (or the names
+	// given by the json struct tags) which the patches reference using
+	// the attribute names defined in the design.
+	structNode map[string]any
+)
+
+const (
+	// InvalidPatch is the error name for the errors produced when a patch
+	// cannot be applied.
+	InvalidPatch = "invalid_patch"
+
+	// JSONPatchContentType is the content type of JSON Patch documents.
+	JSONPatchContentType = "application/json-patch+json"
+
+	// MergePatchContentType is the content type of JSON Merge Patch
+	// documents.
+	MergePatchContentType = "application/merge-patch+json"
+)
+
+var (
+	marshalerType   = reflect.TypeOf((*json.Marshaler)(nil)).Elem()
+	unmarshalerType = reflect.TypeOf((*json.Unmarshaler)(nil)).Elem()
+)
+
+// ApplyPatch returns a copy of target with the patch applied. target must be
+// a struct or a pointer to a struct, it is left unchanged. The patch locations
+// designate struct fields with the names of the corresponding design
+// attributes, e.g. "/first_name" for the field FirstName. ApplyPatch returns an
+// error named InvalidPatch if the patch cannot be applied, in particular if a
+// "test" operation fails. The generated Apply functions also run the
+// validations defined in the design on the result.
+func ApplyPatch[T any](target T, p *Patch) (T, error) {
+	var res T
+	doc, err := encodeTree(reflect.ValueOf(target))
+	if err != nil {
+		return res, err
+	}
+	if p != nil {
+		if p.Merge != nil {
+			patch, err := decodeJSON(p.Merge)
+			if err != nil {
+				return res, PermanentError(InvalidPatch, "invalid merge patch: %s", err)
+			}
+			doc = mergeTree(doc, patch)
+		} else {
+			for i, op := range p.Operations {
+				if doc, err = op.apply(doc); err != nil {
+					return res, PermanentError(InvalidPatch, "patch operation %d (%s %q): %s", i, op.Op, op.Path, err)
+				}
+			}
+		}
+	}
+	if err := decodeTree(doc, reflect.ValueOf(&res).Elem()); err != nil {
+		return res, PermanentError(InvalidPatch, "invalid patch result: %s", err)
+	}
+	return res, nil
+}
+
+// ContentType returns the content type of the patch document.
+func (p *Patch) ContentType() string {
+	if p.Merge != nil {
+		return MergePatchContentType
+	}
+	return JSONPatchContentType
+}
+
+// MarshalJSON encodes the patch document.
+func (p Patch) MarshalJSON() ([]byte, error) {
+	if p.Merge != nil {
+		return p.Merge, nil
+	}
+	if p.Operations == nil {
+		return []byte("[]"), nil
+	}
+	return json.Marshal(p.Operations)
+}
+
+// UnmarshalJSON decodes a JSON Patch document if data is an array and a JSON
+// Merge Patch document otherwise. It returns an error if a JSON Patch
+// operation is invalid.
+func (p *Patch) UnmarshalJSON(data []byte) error {
+	data = bytes.TrimSpace(data)
+	if len(data) == 0 || data[0] != '[' {
+		p.Operations = nil
+		p.Merge = append(json.RawMessage(nil), data...)
+		return nil
+	}
+	var ops []*PatchOperation
+	if err := json.Unmarshal(data, &ops); err != nil {
+		return err
+	}
+	for i, op := range ops {
+		if err := op.validate(); err != nil {
+			return fmt.Errorf("invalid patch operation %d: %w", i, err)
+		}
+	}
+	p.Operations, p.Merge = ops, nil
+	return nil
+}
+
+// validate checks that the operation is well formed.
+func (op *PatchOperation) validate() error {
+	switch op.Op {
+	case "add", "replace", "test":
+		if len(op.Value) == 0 {
+			return fmt.Errorf("%s operation is missing a value", op.Op)
+		}
+	case "move", "copy":
+		if _, err := parsePointer(op.From); err != nil {
+			return err
+		}
+	case "remove":
+	default:
+		return fmt.Errorf("unknown operation %q", op.Op)
+	}
+	_, err := parsePointer(op.Path)
+	return err
+}
+
+// apply applies the operation to doc and returns the result.
+func (op *PatchOperation) apply(doc any) (any, error) {
+	if err := op.validate(); err != nil {
+		return nil, err
+	}
+	path, _ := parsePointer(op.Path)
+	switch op.Op {
+	case "add":
+		val, err := decodeJSON(op.Value)
+		if err != nil {
+			return nil, err
+		}
+		return addValue(doc, path, val)
+	case "remove":
+		return removeValue(doc, path)
+	case "replace":
+		val, err := decodeJSON(op.Value)
+		if err != nil {
+			return nil, err
+		}
+		if len(path) == 0 {
+			return val, nil
+		}
+		return update(doc, path, func(c any, key string) (any, error) {
+			switch c := c.(type) {
+			case structNode:
+				k, ok := c.field(key)
+				if !ok {
+					return nil, fmt.Errorf("unknown field %q", key)
+				}
+				c[k] = val
+				return c, nil
+			case map[string]any:
+				if _, ok := c[key]; !ok {
+					return nil, fmt.Errorf("key %q not found", key)
+				}
+				c[key] = val
+				return c, nil
+			case []any:
+				i, err := arrayIndex(key, len(c)-1)
+				if err != nil {
+					return nil, err
+				}
+				c[i] = val
+				return c, nil
+			}
+			return nil, fmt.Errorf("cannot replace %q in a %T", key, c)
+		})
+	case "move", "copy":
+		from, _ := parsePointer(op.From)
+		val, err := getValue(doc, from)
+		if err != nil {
+			return nil, err
+		}
+		if op.Op == "copy" {
+			return addValue(doc, path, copyTree(val))
+		}
+		if strings.HasPrefix(op.Path, op.From+"/") {
+			return nil, errors.New("cannot move a value into one of its children")
+		}
+		if doc, err = removeValue(doc, from); err != nil {
+			return nil, err
+		}
+		return addValue(doc, path, val)
+	default: // test
+		val, err := decodeJSON(op.Value)
+		if err != nil {
+			return nil, err
+		}
+		actual, err := getValue(doc, path)
+		if err != nil {
+			return nil, err
+		}
+		if !equalTree(actual, val) {
+			return nil, errors.New("test failed")
+		}
+		return doc, nil
+	}
+}
+
+// field returns the key of the field matching the given attribute name.
+func (n structNode) field(name string) (string, bool) {
+	if _, ok := n[name]; ok {
+		return name, true
+	}
+	norm := normalizeName(name)
+	for k := range n {
+		if normalizeName(k) == norm {
+			return k, true
+		}
+	}
+	return "", false
+}
+
+// addValue adds val at the location of path in doc.
+func addValue(doc any, path []string, val any) (any, error) {
+	if len(path) == 0 {
+		return val, nil
+	}
+	return update(doc, path, func(c any, key string) (any, error) {
+		switch c := c.(type) {
+		case structNode:
+			k, ok := c.field(key)
+			if !ok {
+				return nil, fmt.Errorf("unknown field %q", key)
+			}
+			c[k] = val
+			return c, nil
+		case map[string]any:
+			c[key] = val
+			return c, nil
+		case []any:
+			if key == "-" {
+				return append(c, val), nil
+			}
+			i, err := arrayIndex(key, len(c))
+			if err != nil {
+				return nil, err
+			}
+			c = append(c, nil)
+			copy(c[i+1:], c[i:])
+			c[i] = val
+			return c, nil
+		}
+		return nil, fmt.Errorf("cannot add %q to a %T", key, c)
+	})
+}
+
+// removeValue removes the value at the location of path in doc. Removing a
+// struct field sets it to its zero value.
+func removeValue(doc any, path []string) (any, error) {
+	if len(path) == 0 {
+		return nil, errors.New("cannot remove the whole document")
+	}
+	return update(doc, path, func(c any, key string) (any, error) {
+		switch c := c.(type) {
+		case structNode:
+			k, ok := c.field(key)
+			if !ok {
+				return nil, fmt.Errorf("unknown field %q", key)
+			}
+			c[k] = nil
+			return c, nil
+		case map[string]any:
+			if _, ok := c[key]; !ok {
+				return nil, fmt.Errorf("key %q not found", key)
+			}
+			delete(c, key)
+			return c, nil
+		case []any:
+			i, err := arrayIndex(key, len(c)-1)
+			if err != nil {
+				return nil, err
+			}
+			return append(c[:i], c[i+1:]...), nil
+		}
+		return nil, fmt.Errorf("cannot remove %q from a %T", key, c)
+	})
+}
+
+// getValue returns the value at the location of path in doc.
+func getValue(doc any, path []string) (any, error) {
+	for _, key := range path {
+		switch c := doc.(type) {
+		case structNode:
+			k, ok := c.field(key)
+			if !ok {
+				return nil, fmt.Errorf("unknown field %q", key)
+			}
+			doc = c[k]
+		case map[string]any:
+			v, ok := c[key]
+			if !ok {
+				return nil, fmt.Errorf("key %q not found", key)
+			}
+			doc = v
+		case []any:
+			i, err := arrayIndex(key, len(c)-1)
+			if err != nil {
+				return nil, err
+			}
+			doc = c[i]
+		default:
+			return nil, fmt.Errorf("path %q not found", key)
+		}
+	}
+	return doc, nil
+}
+
+// update replaces the container holding the value at the location of path in
+// doc with the result of fn called with the container and the last token of
+// path.
+func update(doc any, path []string, fn func(c any, key string) (any, error)) (any, error) {
+	if len(path) == 1 {
+		return fn(doc, path[0])
+	}
+	child, err := getValue(doc, path[:1])
+	if err != nil {
+		return nil, err
+	}
+	child, err = update(child, path[1:], fn)
+	if err != nil {
+		return nil, err
+	}
+	switch c := doc.(type) {
+	case structNode:
+		k, _ := c.field(path[0])
+		c[k] = child
+	case map[string]any:
+		c[path[0]] = child
+	case []any:
+		i, _ := arrayIndex(path[0], len(c)-1)
+		c[i] = child
+	}
+	return doc, nil
+}
+
+// mergeTree applies the JSON Merge Patch patch to doc.
+func mergeTree(doc, patch any) any {
+	pm, ok := patch.(map[string]any)
+	if !ok {
+		return patch
+	}
+	switch d := doc.(type) {
+	case structNode:
+		for k, v := range pm {
+			key, ok := d.field(k)
+			if !ok {
+				// Let decodeTree report the unknown field.
+				key = k
+			}
+			if v == nil {
+				d[key] = nil
+				continue
+			}
+			d[key] = mergeTree(d[key], v)
+		}
+		return d
+	case map[string]any:
+		for k, v := range pm {
+			if v == nil {
+				delete(d, k)
+				continue
+			}
+			d[k] = mergeTree(d[k], v)
+		}
+		return d
+	}
+	return mergeTree(map[string]any{}, patch)
+}
+
+// equalTree returns true if a and b represent the same JSON value.
+func equalTree(a, b any) bool {
+	switch av := a.(type) {
+	case structNode, map[string]any:
+		_, an := a.(structNode)
+		_, bn := b.(structNode)
+		am, bm := normalizeMap(a, an || bn), normalizeMap(b, an || bn)
+		if am == nil || bm == nil || len(am) != len(bm) {
+			return false
+		}
+		for k, v := range am {
+			if !equalTree(v, bm[k]) {
+				return false
+			}
+		}
+		return true
+	case []any:
+		bv, ok := b.([]any)
+		if !ok || len(av) != len(bv) {
+			return false
+		}
+		for i := range av {
+			if !equalTree(av[i], bv[i]) {
+				return false
+			}
+		}
+		return true
+	case json.Number:
+		bv, ok := b.(json.Number)
+		if !ok {
+			return false
+		}
+		if av == bv {
+			return true
+		}
+		af, aerr := av.Float64()
+		bf, berr := bv.Float64()
+		return aerr == nil && berr == nil && af == bf
+	}
+	return a == b
+}
+
+// normalizeMap returns the keys and values of the given JSON object or nil if
+// v is not an object. The keys are normalized if norm is true. The null fields
+// of struct nodes are omitted so that they compare with the objects defined in
+// patches.
+func normalizeMap(v any, norm bool) map[string]any {
+	var (
+		m      map[string]any
+		isNode bool
+	)
+	switch t := v.(type) {
+	case structNode:
+		m, isNode = t, true
+	case map[string]any:
+		m = t
+	default:
+		return nil
+	}
+	res := make(map[string]any, len(m))
+	for k, v := range m {
+		if v == nil && isNode {
+			continue
+		}
+		if norm {
+			k = normalizeName(k)
+		}
+		res[k] = v
+	}
+	return res
+}
+
+// copyTree returns a deep copy of v.
+func copyTree(v any) any {
+	switch c := v.(type) {
+	case structNode:
+		res := make(structNode, len(c))
+		for k, v := range c {
+			res[k] = copyTree(v)
+		}
+		return res
+	case map[string]any:
+		res := make(map[string]any, len(c))
+		for k, v := range c {
+			res[k] = copyTree(v)
+		}
+		return res
+	case []any:
+		res := make([]any, len(c))
+		for i, v := range c {
+			res[i] = copyTree(v)
+		}
+		return res
+	}
+	return v
+}
+
+// encodeTree returns the JSON representation of v where structs are
+// represented with struct nodes.
+func encodeTree(v reflect.Value) (any, error) {
+	if !v.IsValid() {
+		return nil, nil
+	}
+	if v.Kind() != reflect.Ptr && v.Type().Implements(marshalerType) {
+		return encodeJSON(v)
+	}
+	switch v.Kind() {
+	case reflect.Ptr, reflect.Interface:
+		if v.IsNil() {
+			return nil, nil
+		}
+		return encodeTree(v.Elem())
+	case reflect.Struct:
+		info := cachedStructInfo(v.Type())
+		res := make(structNode, len(info.fields))
+		for _, f := range info.fields {
+			fv, err := encodeTree(v.Field(f.index))
+			if err != nil {
+				return nil, err
+			}
+			res[f.name] = fv
+		}
+		return res, nil
+	case reflect.Slice, reflect.Array:
+		if v.Kind() == reflect.Slice && v.IsNil() {
+			return nil, nil
+		}
+		if v.Type().Elem().Kind() == reflect.Uint8 {
+			return encodeJSON(v)
+		}
+		res := make([]any, v.Len())
+		for i := range res {
+			ev, err := encodeTree(v.Index(i))
+			if err != nil {
+				return nil, err
+			}
+			res[i] = ev
+		}
+		return res, nil
+	case reflect.Map:
+		if v.IsNil() {
+			return nil, nil
+		}
+		if v.Type().Key().Kind() != reflect.String {
+			return encodeJSON(v)
+		}
+		res := make(map[string]any, v.Len())
+		iter := v.MapRange()
+		for iter.Next() {
+			ev, err := encodeTree(iter.Value())
+			if err != nil {
+				return nil, err
+			}
+			res[iter.Key().String()] = ev
+		}
+		return res, nil
+	}
+	return encodeJSON(v)
+}
+
+// decodeTree initializes v with the JSON representation tree.
+func decodeTree(tree any, v reflect.Value) error {
+	if v.CanAddr() && v.Kind() != reflect.Ptr && v.Addr().Type().Implements(unmarshalerType) {
+		return decodeJSONValue(tree, v)
+	}
+	switch v.Kind() {
+	case reflect.Ptr:
+		if tree == nil {
+			v.Set(reflect.Zero(v.Type()))
+			return nil
+		}
+		p := reflect.New(v.Type().Elem())
+		if err := decodeTree(tree, p.Elem()); err != nil {
+			return err
+		}
+		v.Set(p)
+		return nil
+	case reflect.Struct:
+		if tree == nil {
+			v.Set(reflect.Zero(v.Type()))
+			return nil
+		}
+		var m map[string]any
+		switch t := tree.(type) {
+		case structNode:
+			m = t
+		case map[string]any:
+			m = t
+		default:
+			return fmt.Errorf("cannot use %T as %s", tree, v.Type())
+		}
+		v.Set(reflect.Zero(v.Type()))
+		info := cachedStructInfo(v.Type())
+		for k, fv := range m {
+			f, ok := info.field(k)
+			if !ok {
+				return fmt.Errorf("unknown field %q", k)
+			}
+			if err := decodeTree(fv, v.Field(f.index)); err != nil {
+				return fmt.Errorf("%s: %w", k, err)
+			}
+		}
+		return nil
+	case reflect.Slice:
+		if v.Type().Elem().Kind() == reflect.Uint8 {
+			return decodeJSONValue(tree, v)
+		}
+		if tree == nil {
+			v.Set(reflect.Zero(v.Type()))
+			return nil
+		}
+		arr, ok := tree.([]any)
+		if !ok {
+			return fmt.Errorf("cannot use %T as %s", tree, v.Type())
+		}
+		s := reflect.MakeSlice(v.Type(), len(arr), len(arr))
+		for i, e := range arr {
+			if err := decodeTree(e, s.Index(i)); err != nil {
+				return fmt.Errorf("[%d]: %w", i, err)
+			}
+		}
+		v.Set(s)
+		return nil
+	case reflect.Map:
+		if v.Type().Key().Kind() != reflect.String {
+			return decodeJSONValue(tree, v)
+		}
+		if tree == nil {
+			v.Set(reflect.Zero(v.Type()))
+			return nil
+		}
+		m, ok := tree.(map[string]any)
+		if !ok {
+			return fmt.Errorf("cannot use %T as %s", tree, v.Type())
+		}
+		res := reflect.MakeMapWithSize(v.Type(), len(m))
+		for k, e := range m {
+			ev := reflect.New(v.Type().Elem()).Elem()
+			if err := decodeTree(e, ev); err != nil {
+				return fmt.Errorf("[%s]: %w", k, err)
+			}
+			res.SetMapIndex(reflect.ValueOf(k).Convert(v.Type().Key()), ev)
+		}
+		v.Set(res)
+		return nil
+	}
+	return decodeJSONValue(tree, v)
+}
+
+// fieldName returns the name of the struct field used in the JSON
+// representation of the struct and false if the field is not encoded.
+func fieldName(f reflect.StructField) (string, bool) {
+	if !f.IsExported() {
+		return "", false
+	}
+	tag := f.Tag.Get("json")
+	if tag == "-" {
+		return "", false
+	}
+	if name, _, _ := strings.Cut(tag, ","); name != "" {
+		return name, true
+	}
+	return f.Name, true
+}
+
+// encodeJSON returns the JSON representation of v using encoding/json.
+func encodeJSON(v reflect.Value) (any, error) {
+	b, err := json.Marshal(v.Interface())
+	if err != nil {
+		return nil, err
+	}
+	return decodeJSON(b)
+}
+
+// decodeJSONValue initializes v with tree using encoding/json.
+func decodeJSONValue(tree any, v reflect.Value) error {
+	b, err := json.Marshal(tree)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(b, v.Addr().Interface())
+}
+
+// decodeJSON decodes b preserving the number literals.
+func decodeJSON(b []byte) (any, error) {
+	d := json.NewDecoder(bytes.NewReader(b))
+	d.UseNumber()
+	var v any
+	if err := d.Decode(&v); err != nil {
+		return nil, err
+	}
+	return v, nil
+}
+
+// parsePointer returns the reference tokens of the JSON Pointer s.
+func parsePointer(s string) ([]string, error) {
+	if s == "" {
+		return nil, nil
+	}
+	if !strings.HasPrefix(s, "/") {
+		return nil, fmt.Errorf("invalid JSON pointer %q", s)
+	}
+	tokens := strings.Split(s[1:], "/")
+	for i, t := range tokens {
+		tokens[i] = strings.ReplaceAll(strings.ReplaceAll(t, "~1", "/"), "~0", "~")
+	}
+	return tokens, nil
+}
+
+// arrayIndex returns the array index designated by key given the maximum
+// index value.
+func arrayIndex(key string, max int) (int, error) {
+	i, err := strconv.Atoi(key)
+	if err != nil || i < 0 || (len(key) > 1 && key[0] == '0') {
+		return 0, fmt.Errorf("invalid array index %q", key)
+	}
+	if i > max {
+		return 0, fmt.Errorf("array index %d out of range", i)
+	}
+	return i, nil
+}
+
+// normalizeName returns the lower case version of name without the
+// separators so that the design attribute names match the Go field names, e.g.
+// "first_name" matches "FirstName".
+func normalizeName(name string) string {
+	return strings.ToLower(strings.NewReplacer("_", "", "-", "", " ", "").Replace(name))
+}
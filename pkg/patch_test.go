@@ -0,0 +1,149 @@
+package goa
+
+import (
+	"encoding/json"
+	"errors"
+	"reflect"
+	"testing"
+)
+
+type (
+	patchAccount struct {
+		ID        string
+		FirstName *string
+		Age       *int
+		Address   *patchAddress
+		Tags      []string
+		Labels    map[string]string
+	}
+
+	patchAddress struct {
+		StreetName string
+		City       *string
+	}
+)
+
+func TestApplyPatch(t *testing.T) {
+	city := "Paris"
+	name := "Ada"
+	target := &patchAccount{
+		ID:        "1",
+		FirstName: &name,
+		Address:   &patchAddress{StreetName: "Main", City: &city},
+		Tags:      []string{"a", "b"},
+		Labels:    map[string]string{"env": "prod"},
+	}
+	cases := []struct {
+		Name     string
+		Patch    string
+		Expected func(a *patchAccount)
+		Error    string
+	}{
+		{"replace", `[{"op":"replace","path":"/first_name","value":"Grace"}]`, func(a *patchAccount) { s := "Grace"; a.FirstName = &s }, ""},
+		{"add-field", `[{"op":"add","path":"/age","value":42}]`, func(a *patchAccount) { n := 42; a.Age = &n }, ""},
+		{"remove-field", `[{"op":"remove","path":"/first_name"}]`, func(a *patchAccount) { a.FirstName = nil }, ""},
+		{"nested", `[{"op":"replace","path":"/address/street_name","value":"Elm"}]`, func(a *patchAccount) { a.Address = &patchAddress{StreetName: "Elm", City: &city} }, ""},
+		{"add-object", `[{"op":"add","path":"/address","value":{"street_name":"Elm"}}]`, func(a *patchAccount) { a.Address = &patchAddress{StreetName: "Elm"} }, ""},
+		{"array-append", `[{"op":"add","path":"/tags/-","value":"c"}]`, func(a *patchAccount) { a.Tags = []string{"a", "b", "c"} }, ""},
+		{"array-insert", `[{"op":"add","path":"/tags/0","value":"c"}]`, func(a *patchAccount) { a.Tags = []string{"c", "a", "b"} }, ""},
+		{"array-remove", `[{"op":"remove","path":"/tags/0"}]`, func(a *patchAccount) { a.Tags = []string{"b"} }, ""},
+		{"map-add", `[{"op":"add","path":"/labels/team","value":"core"}]`, func(a *patchAccount) { a.Labels = map[string]string{"env": "prod", "team": "core"} }, ""},
+		{"map-remove", `[{"op":"remove","path":"/labels/env"}]`, func(a *patchAccount) { a.Labels = map[string]string{} }, ""},
+		{"move", `[{"op":"move","from":"/labels/env","path":"/labels/stage"}]`, func(a *patchAccount) { a.Labels = map[string]string{"stage": "prod"} }, ""},
+		{"copy", `[{"op":"copy","from":"/tags/0","path":"/tags/-"}]`, func(a *patchAccount) { a.Tags = []string{"a", "b", "a"} }, ""},
+		{"test", `[{"op":"test","path":"/address","value":{"street_name":"Main","city":"Paris"}},{"op":"replace","path":"/id","value":"2"}]`, func(a *patchAccount) { a.ID = "2" }, ""},
+		{"test-failed", `[{"op":"test","path":"/id","value":"2"}]`, nil, `patch operation 0 (test "/id"): test failed`},
+		{"unknown-field", `[{"op":"add","path":"/unknown","value":1}]`, nil, `patch operation 0 (add "/unknown"): unknown field "unknown"`},
+		{"invalid-index", `[{"op":"replace","path":"/tags/5","value":"c"}]`, nil, `patch operation 0 (replace "/tags/5"): array index 5 out of range`},
+		{"invalid-type", `[{"op":"replace","path":"/age","value":"old"}]`, nil, "invalid patch result: Age: json: cannot unmarshal string into Go value of type int"},
+		{"merge", `{"first_name":null,"address":{"city":"Rome"},"labels":{"env":null,"team":"core"}}`, func(a *patchAccount) {
+			c := "Rome"
+			a.FirstName = nil
+			a.Address = &patchAddress{StreetName: "Main", City: &c}
+			a.Labels = map[string]string{"team": "core"}
+		}, ""},
+		{"merge-unknown-field", `{"unknown":1}`, nil, `invalid patch result: unknown field "unknown"`},
+	}
+	for _, c := range cases {
+		t.Run(c.Name, func(t *testing.T) {
+			var p *Patch
+			if err := json.Unmarshal([]byte(c.Patch), &p); err != nil {
+				t.Fatal(err)
+			}
+			before, _ := json.Marshal(target)
+			res, err := ApplyPatch(target, p)
+			if after, _ := json.Marshal(target); string(after) != string(before) {
+				t.Errorf("target was modified: got %s, expected %s", after, before)
+			}
+			if c.Error != "" {
+				var serr *ServiceError
+				if !errors.As(err, &serr) || serr.Name != InvalidPatch || serr.Message != c.Error {
+					t.Errorf("got error %v, expected %s error %q", err, InvalidPatch, c.Error)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("got error %v, expected none", err)
+			}
+			var expected patchAccount
+			if err := json.Unmarshal(before, &expected); err != nil {
+				t.Fatal(err)
+			}
+			c.Expected(&expected)
+			if !reflect.DeepEqual(res, &expected) {
+				got, _ := json.Marshal(res)
+				exp, _ := json.Marshal(expected)
+				t.Errorf("got %s, expected %s", got, exp)
+			}
+		})
+	}
+}
+
+func TestPatchUnmarshalJSON(t *testing.T) {
+	cases := []struct {
+		Name        string
+		JSON        string
+		ContentType string
+		Error       string
+	}{
+		{"json-patch", `[{"op":"remove","path":"/a"}]`, JSONPatchContentType, ""},
+		{"merge-patch", `{"a":null}`, MergePatchContentType, ""},
+		{"unknown-op", `[{"op":"delete","path":"/a"}]`, "", `invalid patch operation 0: unknown operation "delete"`},
+		{"missing-value", `[{"op":"add","path":"/a"}]`, "", "invalid patch operation 0: add operation is missing a value"},
+		{"invalid-pointer", `[{"op":"remove","path":"a"}]`, "", `invalid patch operation 0: invalid JSON pointer "a"`},
+	}
+	for _, c := range cases {
+		t.Run(c.Name, func(t *testing.T) {
+			var p Patch
+			err := json.Unmarshal([]byte(c.JSON), &p)
+			if c.Error != "" {
+				if err == nil || err.Error() != c.Error {
+					t.Errorf("got error %v, expected %q", err, c.Error)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("got error %v, expected none", err)
+			}
+			if ct := p.ContentType(); ct != c.ContentType {
+				t.Errorf("got content type %q, expected %q", ct, c.ContentType)
+			}
+			b, err := json.Marshal(p)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !equalTree(mustDecodeJSON(t, b), mustDecodeJSON(t, []byte(c.JSON))) {
+				t.Errorf("got %s, expected %s", b, c.JSON)
+			}
+		})
+	}
+}
+
+func mustDecodeJSON(t *testing.T, b []byte) any {
+	t.Helper()
+	v, err := decodeJSON(b)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return v
+}
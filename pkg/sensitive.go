@@ -0,0 +1,37 @@
+package goa
+
+import "context"
+
+// Redacted is the value that replaces the sensitive string attributes of
+// redacted values.
+const Redacted = "[REDACTED]"
+
+type (
+	// Redacter is implemented by the generated types that define sensitive
+	// attributes.
+	Redacter interface {
+		// Redact returns a copy of the value where the sensitive string
+		// attributes are replaced with Redacted and the other sensitive
+		// attributes are zeroed.
+		Redact() any
+	}
+
+	// FieldCodec encrypts and decrypts the values of the attributes marked
+	// as encrypted in the design. Field is the name of the attribute as
+	// defined in the design.
+	FieldCodec interface {
+		// Encrypt returns the ciphertext of the given attribute value.
+		Encrypt(ctx context.Context, field, value string) (string, error)
+		// Decrypt returns the plaintext of the given attribute value.
+		Decrypt(ctx context.Context, field, value string) (string, error)
+	}
+)
+
+// Redact returns the redacted copy of v if v implements Redacter, v
+// otherwise.
+func Redact(v any) any {
+	if r, ok := v.(Redacter); ok {
+		return r.Redact()
+	}
+	return v
+}
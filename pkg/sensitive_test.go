@@ -0,0 +1,30 @@
+package goa
+
+import "testing"
+
+type redacterType struct {
+	Secret string
+}
+
+func (r *redacterType) Redact() any {
+	res := *r
+	res.Secret = Redacted
+	return &res
+}
+
+func TestRedact(t *testing.T) {
+	v := &redacterType{Secret: "secret"}
+	got, ok := Redact(v).(*redacterType)
+	if !ok {
+		t.Fatalf("got %T, expected *redacterType", Redact(v))
+	}
+	if got.Secret != Redacted {
+		t.Errorf("got secret %q, expected %q", got.Secret, Redacted)
+	}
+	if v.Secret != "secret" {
+		t.Errorf("original value was modified, got secret %q", v.Secret)
+	}
+	if got := Redact("value"); got != "value" {
+		t.Errorf("got %v for non redacter value, expected value", got)
+	}
+}
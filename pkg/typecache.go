@@ -0,0 +1,98 @@
+package goa
+
+import (
+	"reflect"
+	"sync"
+)
+
+type (
+	// structInfo is the reflection metadata of a struct type used to
+	// encode and decode its values. It is computed once per type and
+	// cached.
+	structInfo struct {
+		// fields lists the encoded fields in declaration order.
+		fields []*structField
+		// byName indexes the fields by name.
+		byName map[string]*structField
+		// byNorm indexes the fields by normalized name, see
+		// normalizeName.
+		byNorm map[string]*structField
+	}
+
+	// structField is an encoded struct field.
+	structField struct {
+		// name is the name of the field in the JSON representation.
+		name string
+		// index is the index of the field in the struct.
+		index int
+	}
+
+	// typeCacheShard is a shard of the struct metadata cache.
+	typeCacheShard struct {
+		mu    sync.RWMutex
+		types map[reflect.Type]*structInfo
+	}
+)
+
+// typeCacheShards is the number of shards of the struct metadata cache, the
+// shards reduce the lock contention when many types are decoded concurrently.
+const typeCacheShards = 16
+
+// typeCache caches the metadata of the struct types so that repeated
+// encodings and decodings of the same type do not rebuild the field maps.
+var typeCache [typeCacheShards]typeCacheShard
+
+// cachedStructInfo returns the metadata of the struct type t.
+func cachedStructInfo(t reflect.Type) *structInfo {
+	// reflect.Type values are pointers to the unique type descriptors.
+	s := &typeCache[(reflect.ValueOf(t).Pointer()>>4)%typeCacheShards]
+	s.mu.RLock()
+	info, ok := s.types[t]
+	s.mu.RUnlock()
+	if ok {
+		return info
+	}
+	info = newStructInfo(t)
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if cached, ok := s.types[t]; ok {
+		return cached
+	}
+	if s.types == nil {
+		s.types = make(map[reflect.Type]*structInfo)
+	}
+	s.types[t] = info
+	return info
+}
+
+// newStructInfo computes the metadata of the struct type t. The first field
+// wins when several fields have the same normalized name.
+func newStructInfo(t reflect.Type) *structInfo {
+	info := &structInfo{
+		byName: make(map[string]*structField, t.NumField()),
+		byNorm: make(map[string]*structField, t.NumField()),
+	}
+	for i := 0; i < t.NumField(); i++ {
+		name, ok := fieldName(t.Field(i))
+		if !ok {
+			continue
+		}
+		f := &structField{name: name, index: i}
+		info.fields = append(info.fields, f)
+		info.byName[name] = f
+		if norm := normalizeName(name); info.byNorm[norm] == nil {
+			info.byNorm[norm] = f
+		}
+	}
+	return info
+}
+
+// field returns the field matching the given attribute name exactly or else
+// ignoring the case and the separators.
+func (info *structInfo) field(name string) (*structField, bool) {
+	if f, ok := info.byName[name]; ok {
+		return f, true
+	}
+	f, ok := info.byNorm[normalizeName(name)]
+	return f, ok
+}
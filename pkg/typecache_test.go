@@ -0,0 +1,89 @@
+package goa
+
+import (
+	"reflect"
+	"sync"
+	"testing"
+)
+
+func TestCachedStructInfo(t *testing.T) {
+	type account struct {
+		ID        string `json:"id"`
+		FirstName string `json:"first_name"`
+		Ignored   string `json:"-"`
+		private   string // nolint: unused
+		Labels    map[string]string
+	}
+	typ := reflect.TypeOf(account{})
+	var wg sync.WaitGroup
+	infos := make([]*structInfo, 8)
+	for i := range infos {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			infos[i] = cachedStructInfo(typ)
+		}(i)
+	}
+	wg.Wait()
+	for _, info := range infos[1:] {
+		if info != infos[0] {
+			t.Fatal("expected the metadata to be computed once")
+		}
+	}
+	info := infos[0]
+	var names []string
+	for _, f := range info.fields {
+		names = append(names, f.name)
+	}
+	if expected := []string{"id", "first_name", "Labels"}; !reflect.DeepEqual(names, expected) {
+		t.Errorf("got fields %v, expected %v", names, expected)
+	}
+	for _, name := range []string{"first_name", "firstName", "FirstName"} {
+		if f, ok := info.field(name); !ok || f.index != 1 {
+			t.Errorf("%s: got %v, %v, expected field 1", name, f, ok)
+		}
+	}
+	if _, ok := info.field("Ignored"); ok {
+		t.Error("expected ignored field not to be found")
+	}
+}
+
+func BenchmarkDecodeTree(b *testing.B) {
+	name, age, city := "Alice", 42, "Paris"
+	acc := &patchAccount{
+		ID:        "1",
+		FirstName: &name,
+		Age:       &age,
+		Address:   &patchAddress{StreetName: "Main", City: &city},
+		Tags:      []string{"a", "b"},
+		Labels:    map[string]string{"k": "v"},
+	}
+	tree, err := encodeTree(reflect.ValueOf(acc))
+	if err != nil {
+		b.Fatal(err)
+	}
+	run := func(b *testing.B, reset bool) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			if reset {
+				resetTypeCache()
+			}
+			var res patchAccount
+			if err := decodeTree(tree, reflect.ValueOf(&res).Elem()); err != nil {
+				b.Fatal(err)
+			}
+		}
+	}
+	b.Run("cached", func(b *testing.B) { run(b, false) })
+	b.Run("uncached", func(b *testing.B) { run(b, true) })
+}
+
+// resetTypeCache empties the struct metadata cache.
+func resetTypeCache() {
+	for i := range typeCache {
+		s := &typeCache[i]
+		s.mu.Lock()
+		s.types = nil
+		s.mu.Unlock()
+	}
+}
@@ -0,0 +1,132 @@
+/*
+Package random contains the runtime used by the generated functions that
+produce random instances of the service payload and result types. The generated
+functions are defined in the random.go file of each service package. They take
+a *rand.Rand as source of randomness so that the values can be reproduced by
+seeding the source, for example:
+
+	r := rand.New(rand.NewSource(42))
+	p := calc.RandomAddPayload(r)
+
+The generated values satisfy the validations defined in the design: enums,
+patterns, formats, ranges and lengths.
+*/
+package random
+
+import (
+	"fmt"
+	"math/rand"
+	"net"
+	"strings"
+	"time"
+
+	regen "github.com/AnatolyRugalev/goregen"
+)
+
+// MaxDepth is the maximum number of nested user types generated for recursive
+// types. Generated functions return nil for object user types nested deeper.
+var MaxDepth = 5
+
+// letters is the set of characters used to generate random strings.
+const letters = "abcdefghijklmnopqrstuvwxyzABCDEFGHIJKLMNOPQRSTUVWXYZ0123456789"
+
+// Int64 returns a random integer in the interval [min, max].
+func Int64(r *rand.Rand, min, max int64) int64 {
+	if max <= min {
+		return min
+	}
+	n := uint64(max - min)
+	if n >= 1<<63-1 {
+		return min + int64(r.Uint64()%n)
+	}
+	return min + r.Int63n(int64(n)+1)
+}
+
+// Float64 returns a random floating point number in the interval [min, max).
+func Float64(r *rand.Rand, min, max float64) float64 {
+	if max <= min {
+		return min
+	}
+	return min + r.Float64()*(max-min)
+}
+
+// Length returns a random length in the interval [min, max].
+func Length(r *rand.Rand, min, max int) int {
+	return int(Int64(r, int64(min), int64(max)))
+}
+
+// String returns a random alphanumeric string whose length is in the interval
+// [min, max].
+func String(r *rand.Rand, min, max int) string {
+	n := Length(r, min, max)
+	var b strings.Builder
+	b.Grow(n)
+	for i := 0; i < n; i++ {
+		b.WriteByte(letters[r.Intn(len(letters))])
+	}
+	return b.String()
+}
+
+// Pattern returns a random string that matches the given regular expression.
+// It panics if the pattern is invalid.
+func Pattern(r *rand.Rand, pattern string) string {
+	gen, err := regen.NewGenerator(pattern, &regen.GeneratorArgs{
+		RngSource:               r,
+		MaxUnboundedRepeatCount: 6,
+	})
+	if err != nil {
+		panic(fmt.Sprintf("random: invalid pattern %q: %s", pattern, err))
+	}
+	return gen.Generate()
+}
+
+// Format returns a random string that satisfies the given format validation
+// as defined by the Format DSL. It panics if the format is unknown.
+func Format(r *rand.Rand, format string) string {
+	switch format {
+	case "date":
+		return randomTime(r).Format(time.DateOnly)
+	case "date-time":
+		return randomTime(r).Format(time.RFC3339)
+	case "rfc1123":
+		return randomTime(r).Format(time.RFC1123)
+	case "uuid":
+		b := make([]byte, 16)
+		r.Read(b) // nolint: errcheck
+		b[6] = b[6]&0x0f | 0x40
+		b[8] = b[8]&0x3f | 0x80
+		return fmt.Sprintf("%x-%x-%x-%x-%x", b[0:4], b[4:6], b[6:8], b[8:10], b[10:])
+	case "email":
+		return strings.ToLower(String(r, 1, 10)) + "@" + hostname(r)
+	case "hostname":
+		return hostname(r)
+	case "ipv4", "ip":
+		return net.IPv4(byte(r.Intn(256)), byte(r.Intn(256)), byte(r.Intn(256)), byte(r.Intn(256))).String()
+	case "ipv6":
+		ip := make(net.IP, net.IPv6len)
+		r.Read(ip) // nolint: errcheck
+		return ip.String()
+	case "uri":
+		return "https://" + hostname(r) + "/" + strings.ToLower(String(r, 1, 10))
+	case "mac":
+		return Pattern(r, `([0-9A-F]{2}-){5}[0-9A-F]{2}`)
+	case "cidr":
+		ip := net.IPv4(byte(r.Intn(256)), byte(r.Intn(256)), byte(r.Intn(256)), 0)
+		return fmt.Sprintf("%s/%d", ip, 8+r.Intn(17))
+	case "regexp":
+		return String(r, 1, 5) + ".*"
+	case "json":
+		return fmt.Sprintf(`{"value":%q}`, String(r, 1, 10))
+	}
+	panic(fmt.Sprintf("random: unknown format %q", format))
+}
+
+// randomTime returns a random time between 1970 and 2038.
+func randomTime(r *rand.Rand) time.Time {
+	return time.Unix(r.Int63n(1<<31), 0).UTC()
+}
+
+// hostname returns a random host name.
+func hostname(r *rand.Rand) string {
+	return strings.ToLower(String(r, 1, 10)) + ".example.com"
+}
@@ -0,0 +1,74 @@
+package random
+
+import (
+	"math/rand"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	goa "goa.design/goa/v3/pkg"
+)
+
+func TestInt64(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+	for i := 0; i < 1000; i++ {
+		v := Int64(r, -3, 3)
+		assert.GreaterOrEqual(t, v, int64(-3))
+		assert.LessOrEqual(t, v, int64(3))
+	}
+	assert.Equal(t, int64(5), Int64(r, 5, 5))
+}
+
+func TestFloat64(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+	for i := 0; i < 1000; i++ {
+		v := Float64(r, 0.5, 1)
+		assert.GreaterOrEqual(t, v, 0.5)
+		assert.Less(t, v, 1.0)
+	}
+}
+
+func TestString(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+	for i := 0; i < 1000; i++ {
+		v := String(r, 2, 4)
+		assert.GreaterOrEqual(t, len(v), 2)
+		assert.LessOrEqual(t, len(v), 4)
+	}
+}
+
+func TestPattern(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+	for i := 0; i < 100; i++ {
+		v := Pattern(r, "^[a-z]{2}-[0-9]+$")
+		assert.NoError(t, goa.ValidatePattern("v", v, "^[a-z]{2}-[0-9]+$"))
+	}
+	assert.Panics(t, func() { Pattern(r, "[") })
+}
+
+func TestFormat(t *testing.T) {
+	formats := []goa.Format{
+		goa.FormatDate, goa.FormatDateTime, goa.FormatUUID, goa.FormatEmail,
+		goa.FormatHostname, goa.FormatIPv4, goa.FormatIPv6, goa.FormatIP,
+		goa.FormatURI, goa.FormatMAC, goa.FormatCIDR, goa.FormatRegexp,
+		goa.FormatJSON, goa.FormatRFC1123,
+	}
+	r := rand.New(rand.NewSource(1))
+	for _, f := range formats {
+		t.Run(string(f), func(t *testing.T) {
+			for i := 0; i < 100; i++ {
+				v := Format(r, string(f))
+				assert.NoError(t, goa.ValidateFormat("v", v, f))
+			}
+		})
+	}
+	assert.Panics(t, func() { Format(r, "unknown") })
+}
+
+func TestReproducible(t *testing.T) {
+	gen := func(seed int64) []string {
+		r := rand.New(rand.NewSource(seed))
+		return []string{String(r, 1, 10), Pattern(r, "[a-z]+"), Format(r, "uuid")}
+	}
+	assert.Equal(t, gen(42), gen(42))
+}
@@ -0,0 +1,176 @@
+/*
+Package casbin provides a security.Authorizer implementation backed by a
+Casbin enforcer. The requests are enforced with the authenticated subject, the
+service name as object and the method name as action so that a model such as:
+
+	[request_definition]
+	r = sub, obj, act
+
+	[policy_definition]
+	p = sub, obj, act
+
+	[role_definition]
+	g = _, _
+
+	[policy_effect]
+	e = some(where (p.eft == allow))
+
+	[matchers]
+	m = g(r.sub, p.sub) && r.obj == p.obj && (r.act == p.act || p.act == "*")
+
+authorizes policies such as "p, admin, calc, *" and "p, alice, calc, add".
+
+The package does not depend on Casbin, *casbin.Enforcer and
+*casbin.SyncedEnforcer implement the Enforcer interface and the Casbin watchers
+(Redis, etcd, NATS...) implement the Watcher interface.
+*/
+package casbin
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+
+	"goa.design/goa/v3/security"
+)
+
+type (
+	// Enforcer is the interface implemented by the Casbin enforcers.
+	Enforcer interface {
+		// Enforce decides whether the request described by rvals is
+		// allowed.
+		Enforce(rvals ...any) (bool, error)
+		// LoadPolicy reloads the policy from the enforcer adapter.
+		LoadPolicy() error
+	}
+
+	// MatcherEnforcer is implemented by the enforcers that can enforce a
+	// request with a custom matcher. It is used to enforce the policies
+	// that define a Rule.
+	MatcherEnforcer interface {
+		// EnforceWithMatcher decides whether the request described by
+		// rvals is allowed using the given matcher.
+		EnforceWithMatcher(matcher string, rvals ...any) (bool, error)
+	}
+
+	// Watcher is the interface implemented by the Casbin watchers, it
+	// notifies the authorizer when the policy is updated by another
+	// instance.
+	Watcher interface {
+		// SetUpdateCallback sets the function called when the policy
+		// is updated.
+		SetUpdateCallback(func(string)) error
+	}
+
+	// RequestFunc returns the Casbin request values used to enforce the
+	// request made by subject to the endpoint with policy p.
+	RequestFunc func(ctx context.Context, subject string, p *security.Policy, payload any) []any
+
+	// Authorizer authorizes the requests with a Casbin enforcer.
+	Authorizer struct {
+		enforcer Enforcer
+		request  RequestFunc
+		onError  func(error)
+
+		// mu prevents enforcing requests while the policy is reloaded.
+		mu sync.RWMutex
+	}
+
+	// Option configures the authorizer.
+	Option func(*Authorizer)
+)
+
+// ErrNoMatcher is the error returned when a policy defines a rule but the
+// enforcer does not implement MatcherEnforcer.
+var ErrNoMatcher = errors.New("casbin: enforcer does not support custom matchers")
+
+// NewAuthorizer returns an authorizer that enforces the requests with e. The
+// returned value implements the Authorize method of the generated Authorizer
+// interfaces, services delegate to it:
+//
+//	func (s *calcsrvc) Authorize(ctx context.Context, p *security.Policy, payload any) error {
+//		return s.authz.Authorize(ctx, p, payload)
+//	}
+func NewAuthorizer(e Enforcer, opts ...Option) *Authorizer {
+	a := &Authorizer{enforcer: e, request: defaultRequest}
+	for _, o := range opts {
+		o(a)
+	}
+	return a
+}
+
+// WithRequest sets the function that builds the Casbin request values, e.g. to
+// add the tenant as domain for models using RBAC with domains. The default
+// request values are the subject, the service name and the method name.
+func WithRequest(fn RequestFunc) Option {
+	return func(a *Authorizer) { a.request = fn }
+}
+
+// WithWatcher makes the authorizer reload the policy whenever w reports an
+// update. errfn is called with the errors that occur while reloading the
+// policy, it may be nil.
+func WithWatcher(w Watcher, errfn func(error)) Option {
+	return func(a *Authorizer) {
+		a.onError = errfn
+		if err := w.SetUpdateCallback(func(string) { a.reload() }); err != nil && errfn != nil {
+			errfn(err)
+		}
+	}
+}
+
+// Authorize checks the roles and permissions of the policy against the
+// principal stored in ctx with security.WithPrincipal and then enforces the
+// request with the principal subject. Policies that define a Rule are enforced
+// using the rule as matcher. Authorize returns a *security.AuthorizationError
+// if the request is denied.
+func (a *Authorizer) Authorize(ctx context.Context, p *security.Policy, payload any) error {
+	pr, ok := security.PrincipalFromContext(ctx)
+	if !ok || pr.Subject == "" {
+		return &security.AuthorizationError{Service: p.Service, Method: p.Method, Reason: "no authenticated subject"}
+	}
+	if err := p.Check(pr); err != nil {
+		return err
+	}
+	rvals := a.request(ctx, pr.Subject, p, payload)
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+	var (
+		allowed bool
+		err     error
+	)
+	if p.Rule != "" {
+		me, ok := a.enforcer.(MatcherEnforcer)
+		if !ok {
+			return ErrNoMatcher
+		}
+		allowed, err = me.EnforceWithMatcher(p.Rule, rvals...)
+	} else {
+		allowed, err = a.enforcer.Enforce(rvals...)
+	}
+	if err != nil {
+		return fmt.Errorf("casbin: %w", err)
+	}
+	if !allowed {
+		return &security.AuthorizationError{
+			Service: p.Service,
+			Method:  p.Method,
+			Reason:  fmt.Sprintf("%s is not allowed to %s %s", pr.Subject, p.Method, p.Service),
+		}
+	}
+	return nil
+}
+
+// reload reloads the enforcer policy.
+func (a *Authorizer) reload() {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	if err := a.enforcer.LoadPolicy(); err != nil && a.onError != nil {
+		a.onError(fmt.Errorf("casbin: failed to reload policy: %w", err))
+	}
+}
+
+// defaultRequest returns the subject, the service name and the method name.
+func defaultRequest(_ context.Context, subject string, p *security.Policy, _ any) []any {
+	return []any{subject, p.Service, p.Method}
+}
@@ -0,0 +1,97 @@
+package casbin
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"goa.design/goa/v3/security"
+)
+
+type (
+	// testEnforcer allows the requests listed in policies, loaded from
+	// source by LoadPolicy.
+	testEnforcer struct {
+		source   [][3]string
+		policies [][3]string
+		matchers []string
+	}
+
+	testWatcher struct {
+		callback func(string)
+	}
+)
+
+func TestAuthorize(t *testing.T) {
+	e := &testEnforcer{source: [][3]string{{"alice", "calc", "add"}}}
+	e.LoadPolicy() // nolint: errcheck
+	w := &testWatcher{}
+	authz := NewAuthorizer(e, WithWatcher(w, func(err error) { t.Error(err) }))
+	add := &security.Policy{Service: "calc", Method: "add"}
+	ctx := func(sub string) context.Context {
+		return security.WithPrincipal(context.Background(), &security.Principal{Subject: sub})
+	}
+
+	if err := authz.Authorize(ctx("alice"), add, nil); err != nil {
+		t.Errorf("got error %v, expected alice to be authorized", err)
+	}
+	var aerr *security.AuthorizationError
+	if err := authz.Authorize(ctx("bob"), add, nil); !errors.As(err, &aerr) || aerr.Reason == "" {
+		t.Errorf("got error %v, expected bob to be denied", err)
+	}
+	if err := authz.Authorize(context.Background(), add, nil); !errors.As(err, &aerr) {
+		t.Errorf("got error %v, expected the anonymous caller to be denied", err)
+	}
+	if err := authz.Authorize(ctx("alice"), &security.Policy{Service: "calc", Method: "add", Roles: []string{"admin"}}, nil); !errors.As(err, &aerr) || len(aerr.MissingRoles) != 1 {
+		t.Errorf("got error %v, expected alice to miss the admin role", err)
+	}
+
+	e.source = append(e.source, [3]string{"bob", "calc", "add"})
+	w.callback("updated")
+	if err := authz.Authorize(ctx("bob"), add, nil); err != nil {
+		t.Errorf("got error %v, expected bob to be authorized after the policy update", err)
+	}
+
+	rule := &security.Policy{Service: "calc", Method: "add", Rule: "r.sub == p.sub"}
+	if err := authz.Authorize(ctx("alice"), rule, nil); err != nil {
+		t.Errorf("got error %v, expected alice to be authorized with the rule", err)
+	}
+	if len(e.matchers) != 1 || e.matchers[0] != rule.Rule {
+		t.Errorf("got matchers %v, expected %q", e.matchers, rule.Rule)
+	}
+}
+
+func TestWithRequest(t *testing.T) {
+	e := &testEnforcer{policies: [][3]string{{"alice", "tenant1", "calc.add"}}}
+	authz := NewAuthorizer(e, WithRequest(func(ctx context.Context, sub string, p *security.Policy, _ any) []any {
+		return []any{sub, "tenant1", p.Service + "." + p.Method}
+	}))
+	ctx := security.WithPrincipal(context.Background(), &security.Principal{Subject: "alice"})
+	if err := authz.Authorize(ctx, &security.Policy{Service: "calc", Method: "add"}, nil); err != nil {
+		t.Errorf("got error %v, expected request with domain to be authorized", err)
+	}
+}
+
+func (e *testEnforcer) Enforce(rvals ...any) (bool, error) {
+	for _, p := range e.policies {
+		if len(rvals) == 3 && rvals[0] == p[0] && rvals[1] == p[1] && rvals[2] == p[2] {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+func (e *testEnforcer) EnforceWithMatcher(matcher string, rvals ...any) (bool, error) {
+	e.matchers = append(e.matchers, matcher)
+	return e.Enforce(rvals...)
+}
+
+func (e *testEnforcer) LoadPolicy() error {
+	e.policies = append([][3]string{}, e.source...)
+	return nil
+}
+
+func (w *testWatcher) SetUpdateCallback(fn func(string)) error {
+	w.callback = fn
+	return nil
+}
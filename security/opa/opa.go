@@ -0,0 +1,206 @@
+/*
+Package opa provides a security.Authorizer implementation that evaluates the
+endpoint policies with Open Policy Agent. The Rego queries are evaluated either
+by an OPA server through its REST API (see NewRemoteEvaluator) or by OPA
+embedded in the service using an EvaluatorFunc that wraps the rego package.
+
+The roles and permissions of the endpoint policies are checked first, the
+Rule of the policy is then evaluated as a Rego query against an input document
+describing the request:
+
+	{
+		"principal": {"subject": "alice", "roles": ["admin"], "permissions": ["calc:add"]},
+		"endpoint":  {"service": "calc", "method": "add"},
+		"policy":    {"roles": ["admin"], "permissions": ["calc:add"], "rule": "..."},
+		"payload":   {"A": 1, "B": 2}
+	}
+
+The payload is redacted with goa.Redact before being encoded in the input
+document, its fields are keyed by the names produced by encoding/json, i.e. the
+Go field names of the generated payload struct.
+*/
+package opa
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	goa "goa.design/goa/v3/pkg"
+	"goa.design/goa/v3/security"
+)
+
+type (
+	// Evaluator evaluates Rego queries.
+	Evaluator interface {
+		// Evaluate returns true if query evaluates successfully to a
+		// non-false value given input.
+		Evaluate(ctx context.Context, query string, input *Input) (bool, error)
+	}
+
+	// EvaluatorFunc is an adapter that makes it possible to use a function
+	// as Evaluator, e.g. to evaluate the queries with embedded OPA:
+	//
+	//	eval := opa.EvaluatorFunc(func(ctx context.Context, query string, input *opa.Input) (bool, error) {
+	//		rs, err := rego.New(rego.Query(query), rego.Module("authz.rego", module), rego.Input(input)).Eval(ctx)
+	//		if err != nil {
+	//			return false, err
+	//		}
+	//		return rs.Allowed(), nil
+	//	})
+	EvaluatorFunc func(ctx context.Context, query string, input *Input) (bool, error)
+
+	// Authorizer authorizes the requests by evaluating the endpoint policies
+	// with an Evaluator.
+	Authorizer struct {
+		eval     Evaluator
+		decision string
+		fields   []string
+	}
+
+	// Option configures the authorizer.
+	Option func(*Authorizer)
+
+	// Input is the input document of the Rego queries.
+	Input struct {
+		// Principal describes the authenticated caller if any.
+		Principal *Principal `json:"principal,omitempty"`
+		// Endpoint identifies the endpoint being called.
+		Endpoint *Endpoint `json:"endpoint"`
+		// Policy is the policy of the endpoint.
+		Policy *Policy `json:"policy"`
+		// Payload is the redacted request payload.
+		Payload any `json:"payload,omitempty"`
+	}
+
+	// Principal describes the authenticated caller in the input document.
+	Principal struct {
+		// Subject identifies the caller.
+		Subject string `json:"subject"`
+		// Roles lists the roles granted to the caller.
+		Roles []string `json:"roles"`
+		// Permissions lists the permissions granted to the caller.
+		Permissions []string `json:"permissions"`
+	}
+
+	// Endpoint identifies the endpoint in the input document.
+	Endpoint struct {
+		// Service is the name of the service.
+		Service string `json:"service"`
+		// Method is the name of the method.
+		Method string `json:"method"`
+	}
+
+	// Policy describes the endpoint policy in the input document.
+	Policy struct {
+		// Roles lists the roles authorized by the policy.
+		Roles []string `json:"roles,omitempty"`
+		// Permissions lists the permissions required by the policy.
+		Permissions []string `json:"permissions,omitempty"`
+		// Rule is the policy rule.
+		Rule string `json:"rule,omitempty"`
+	}
+)
+
+// NewAuthorizer returns an authorizer that evaluates the rules of the endpoint
+// policies with eval. The returned value implements the Authorize method of the
+// generated Authorizer interfaces, services delegate to it:
+//
+//	func (s *calcsrvc) Authorize(ctx context.Context, p *security.Policy, payload any) error {
+//		return s.authz.Authorize(ctx, p, payload)
+//	}
+func NewAuthorizer(eval Evaluator, opts ...Option) *Authorizer {
+	a := &Authorizer{eval: eval}
+	for _, o := range opts {
+		o(a)
+	}
+	return a
+}
+
+// WithDecision sets the query evaluated for the endpoints whose policy does not
+// define a rule, e.g. "data.goa.authz.allow". By default the requests that
+// satisfy the roles and permissions of such policies are authorized without
+// evaluating any query.
+func WithDecision(query string) Option {
+	return func(a *Authorizer) { a.decision = query }
+}
+
+// WithPayloadFields restricts the payload fields included in the input
+// document to the given top level fields. By default the whole redacted payload
+// is included.
+func WithPayloadFields(fields ...string) Option {
+	return func(a *Authorizer) { a.fields = fields }
+}
+
+// Authorize checks the roles and permissions of the policy against the
+// principal stored in ctx with security.WithPrincipal and then evaluates the
+// policy rule or the decision query. It returns a *security.AuthorizationError
+// if the request is denied.
+func (a *Authorizer) Authorize(ctx context.Context, p *security.Policy, payload any) error {
+	pr, _ := security.PrincipalFromContext(ctx)
+	if err := p.Check(pr); err != nil {
+		return err
+	}
+	query := p.Rule
+	if query == "" {
+		query = a.decision
+	}
+	if query == "" {
+		return nil
+	}
+	input, err := a.Input(pr, p, payload)
+	if err != nil {
+		return err
+	}
+	ok, err := a.eval.Evaluate(ctx, query, input)
+	if err != nil {
+		return fmt.Errorf("opa: %w", err)
+	}
+	if !ok {
+		return &security.AuthorizationError{
+			Service: p.Service,
+			Method:  p.Method,
+			Reason:  fmt.Sprintf("denied by policy %q", query),
+		}
+	}
+	return nil
+}
+
+// Input builds the input document of the queries evaluated for the given
+// principal, policy and payload.
+func (a *Authorizer) Input(pr *security.Principal, p *security.Policy, payload any) (*Input, error) {
+	input := &Input{
+		Endpoint: &Endpoint{Service: p.Service, Method: p.Method},
+		Policy:   &Policy{Roles: p.Roles, Permissions: p.Permissions, Rule: p.Rule},
+	}
+	if pr != nil {
+		input.Principal = &Principal{Subject: pr.Subject, Roles: pr.Roles, Permissions: pr.Permissions}
+	}
+	if payload == nil {
+		return input, nil
+	}
+	b, err := json.Marshal(goa.Redact(payload))
+	if err != nil {
+		return nil, fmt.Errorf("opa: failed to encode payload: %w", err)
+	}
+	var v any
+	if err := json.Unmarshal(b, &v); err != nil {
+		return nil, fmt.Errorf("opa: failed to encode payload: %w", err)
+	}
+	if obj, ok := v.(map[string]any); ok && len(a.fields) > 0 {
+		subset := make(map[string]any, len(a.fields))
+		for _, f := range a.fields {
+			if fv, ok := obj[f]; ok {
+				subset[f] = fv
+			}
+		}
+		v = subset
+	}
+	input.Payload = v
+	return input, nil
+}
+
+// Evaluate calls f(ctx, query, input).
+func (f EvaluatorFunc) Evaluate(ctx context.Context, query string, input *Input) (bool, error) {
+	return f(ctx, query, input)
+}
@@ -0,0 +1,94 @@
+package opa
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+
+	goa "goa.design/goa/v3/pkg"
+	"goa.design/goa/v3/security"
+)
+
+type testPayload struct {
+	Owner  string
+	Amount int
+	Secret string
+}
+
+func (p *testPayload) Redact() any {
+	res := *p
+	res.Secret = goa.Redacted
+	return &res
+}
+
+func TestAuthorize(t *testing.T) {
+	var input map[string]any
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req struct {
+			Query string         `json:"query"`
+			Input map[string]any `json:"input"`
+		}
+		if r.URL.Path != "/v1/query" || json.NewDecoder(r.Body).Decode(&req) != nil {
+			http.Error(w, "bad request", http.StatusBadRequest)
+			return
+		}
+		input = req.Input
+		principal, _ := req.Input["principal"].(map[string]any)
+		payload, _ := req.Input["payload"].(map[string]any)
+		if req.Query == "owner" && principal["subject"] == payload["Owner"] {
+			w.Write([]byte(`{"result":[{}]}`)) // nolint: errcheck
+			return
+		}
+		w.Write([]byte(`{}`)) // nolint: errcheck
+	}))
+	defer srv.Close()
+
+	authz := NewAuthorizer(NewRemoteEvaluator(srv.URL, nil), WithPayloadFields("Owner", "Secret"))
+	policy := &security.Policy{Service: "bank", Method: "transfer", Roles: []string{"customer"}, Rule: "owner"}
+	payload := &testPayload{Owner: "alice", Amount: 10, Secret: "pin"}
+	alice := security.WithPrincipal(context.Background(), &security.Principal{Subject: "alice", Roles: []string{"customer"}})
+	bob := security.WithPrincipal(context.Background(), &security.Principal{Subject: "bob", Roles: []string{"customer"}})
+
+	if err := authz.Authorize(alice, policy, payload); err != nil {
+		t.Fatalf("got error %v, expected alice to be authorized", err)
+	}
+	expected := map[string]any{"Owner": "alice", "Secret": goa.Redacted}
+	if !reflect.DeepEqual(input["payload"], expected) {
+		t.Errorf("got payload %v, expected %v", input["payload"], expected)
+	}
+	if ep, _ := input["endpoint"].(map[string]any); ep["service"] != "bank" || ep["method"] != "transfer" {
+		t.Errorf("got endpoint %v, expected bank transfer", input["endpoint"])
+	}
+
+	var aerr *security.AuthorizationError
+	if err := authz.Authorize(bob, policy, payload); !errors.As(err, &aerr) || aerr.Reason == "" {
+		t.Errorf("got error %v, expected bob to be denied by the rule", err)
+	}
+	if err := authz.Authorize(context.Background(), policy, payload); !errors.As(err, &aerr) || len(aerr.MissingRoles) != 1 {
+		t.Errorf("got error %v, expected the anonymous caller to miss the customer role", err)
+	}
+	if err := authz.Authorize(bob, &security.Policy{Roles: []string{"customer"}}, nil); err != nil {
+		t.Errorf("got error %v, expected policy without rule to be authorized", err)
+	}
+	decision := NewAuthorizer(NewRemoteEvaluator(srv.URL, nil), WithDecision("deny"))
+	if err := decision.Authorize(bob, &security.Policy{}, nil); !errors.As(err, &aerr) {
+		t.Errorf("got error %v, expected the decision query to deny the request", err)
+	}
+}
+
+func TestRemoteEvaluatorError(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		http.Error(w, "rego_parse_error", http.StatusBadRequest)
+	}))
+	defer srv.Close()
+	authz := NewAuthorizer(NewRemoteEvaluator(srv.URL, nil))
+	err := authz.Authorize(context.Background(), &security.Policy{Rule: "invalid"}, nil)
+	var aerr *security.AuthorizationError
+	if err == nil || errors.As(err, &aerr) {
+		t.Errorf("got error %v, expected an evaluation error", err)
+	}
+}
@@ -0,0 +1,71 @@
+package opa
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+
+	goahttp "goa.design/goa/v3/http"
+)
+
+type (
+	// RemoteEvaluator evaluates the Rego queries with the query API of an
+	// OPA server.
+	RemoteEvaluator struct {
+		addr string
+		doer goahttp.Doer
+	}
+
+	// queryRequest is the body of the OPA query API requests.
+	queryRequest struct {
+		Query string `json:"query"`
+		Input *Input `json:"input"`
+	}
+
+	// queryResponse is the body of the OPA query API responses.
+	queryResponse struct {
+		Result []json.RawMessage `json:"result"`
+	}
+)
+
+// NewRemoteEvaluator returns an evaluator that uses the REST API of the OPA
+// server at addr (e.g. "http://localhost:8181"). d is used to make the requests
+// to the server, http.DefaultClient if nil. A query evaluates to true if it has
+// at least one result.
+func NewRemoteEvaluator(addr string, d goahttp.Doer) *RemoteEvaluator {
+	if d == nil {
+		d = http.DefaultClient
+	}
+	return &RemoteEvaluator{addr: strings.TrimSuffix(addr, "/"), doer: d}
+}
+
+// Evaluate implements Evaluator.
+func (e *RemoteEvaluator) Evaluate(ctx context.Context, query string, input *Input) (bool, error) {
+	body, err := json.Marshal(&queryRequest{Query: query, Input: input})
+	if err != nil {
+		return false, err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, e.addr+"/v1/query", bytes.NewReader(body))
+	if err != nil {
+		return false, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := e.doer.Do(req)
+	if err != nil {
+		return false, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		msg, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return false, fmt.Errorf("unexpected status %d: %s", resp.StatusCode, strings.TrimSpace(string(msg)))
+	}
+	var res queryResponse
+	if err := json.NewDecoder(resp.Body).Decode(&res); err != nil {
+		return false, fmt.Errorf("failed to decode query result: %w", err)
+	}
+	return len(res.Result) > 0, nil
+}
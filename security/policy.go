@@ -0,0 +1,138 @@
+package security
+
+import (
+	"context"
+	"fmt"
+	"strings"
+)
+
+type (
+	// Policy represents the authorization policy of an endpoint as defined in
+	// the design with the Policy DSL.
+	Policy struct {
+		// Service is the name of the service as defined in the design.
+		Service string
+		// Method is the name of the method as defined in the design.
+		Method string
+		// Roles lists the roles authorized to call the endpoint, the
+		// principal must have at least one of them.
+		Roles []string
+		// Permissions lists the permissions required to call the
+		// endpoint, the principal must have all of them.
+		Permissions []string
+		// Rule is the authorization expression defined in the design if
+		// any. Rules are evaluated by the service Authorizer.
+		Rule string
+	}
+
+	// AuthorizeFunc is the function type that implements the authorization
+	// of a request given the policy of the endpoint and the request payload.
+	// It is invoked after the request has been authenticated and returns an
+	// error, typically an AuthorizationError, if the request is denied.
+	AuthorizeFunc func(ctx context.Context, p *Policy, payload any) error
+
+	// Principal describes the authenticated caller. The authentication
+	// functions store the principal in the request context with
+	// WithPrincipal so that it can be checked against the endpoint policies.
+	Principal struct {
+		// Subject identifies the caller, e.g. the JWT subject claim.
+		Subject string
+		// Roles lists the roles granted to the caller.
+		Roles []string
+		// Permissions lists the permissions or scopes granted to the
+		// caller.
+		Permissions []string
+	}
+
+	// AuthorizationError is the error returned when a request is denied by
+	// an endpoint policy. The HTTP and gRPC transports map it to a 403
+	// Forbidden response and a PermissionDenied status respectively.
+	AuthorizationError struct {
+		// Service is the name of the service.
+		Service string
+		// Method is the name of the method.
+		Method string
+		// MissingRoles lists the roles authorized by the policy when the
+		// principal has none of them.
+		MissingRoles []string
+		// MissingPermissions lists the permissions required by the policy
+		// that the principal does not have.
+		MissingPermissions []string
+		// Reason describes why the request was denied if not because of
+		// missing roles or permissions, e.g. a rule evaluating to false.
+		Reason string
+	}
+
+	// principalKey is the context key used to store the principal.
+	principalKey struct{}
+)
+
+// WithPrincipal returns a copy of ctx that contains p.
+func WithPrincipal(ctx context.Context, p *Principal) context.Context {
+	return context.WithValue(ctx, principalKey{}, p)
+}
+
+// PrincipalFromContext returns the principal stored in ctx with WithPrincipal
+// if any.
+func PrincipalFromContext(ctx context.Context) (*Principal, bool) {
+	p, ok := ctx.Value(principalKey{}).(*Principal)
+	return p, ok && p != nil
+}
+
+// Check returns an AuthorizationError if principal does not have any of the
+// roles or all of the permissions required by the policy. A nil principal has
+// no role and no permission. Check does not evaluate the policy rule.
+func (p *Policy) Check(principal *Principal) error {
+	if principal == nil {
+		principal = &Principal{}
+	}
+	aerr := &AuthorizationError{Service: p.Service, Method: p.Method}
+	if len(p.Roles) > 0 && len(intersect(p.Roles, principal.Roles)) == 0 {
+		aerr.MissingRoles = p.Roles
+	}
+	for _, perm := range p.Permissions {
+		if len(intersect([]string{perm}, principal.Permissions)) == 0 {
+			aerr.MissingPermissions = append(aerr.MissingPermissions, perm)
+		}
+	}
+	if len(aerr.MissingRoles) == 0 && len(aerr.MissingPermissions) == 0 {
+		return nil
+	}
+	return aerr
+}
+
+// Error returns the error message.
+func (e *AuthorizationError) Error() string {
+	var reasons []string
+	if len(e.MissingRoles) > 0 {
+		reasons = append(reasons, "requires one of roles: "+strings.Join(e.MissingRoles, ", "))
+	}
+	if len(e.MissingPermissions) > 0 {
+		reasons = append(reasons, "missing permissions: "+strings.Join(e.MissingPermissions, ", "))
+	}
+	if e.Reason != "" {
+		reasons = append(reasons, e.Reason)
+	}
+	msg := "forbidden"
+	if e.Service != "" {
+		msg = fmt.Sprintf("%s.%s forbidden", e.Service, e.Method)
+	}
+	if len(reasons) == 0 {
+		return msg
+	}
+	return msg + ": " + strings.Join(reasons, "; ")
+}
+
+// intersect returns the elements of a that are also in b.
+func intersect(a, b []string) []string {
+	var res []string
+	for _, x := range a {
+		for _, y := range b {
+			if x == y {
+				res = append(res, x)
+				break
+			}
+		}
+	}
+	return res
+}
@@ -0,0 +1,62 @@
+package security
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestPolicyCheck(t *testing.T) {
+	policy := &Policy{Service: "svc", Method: "m", Roles: []string{"admin", "operator"}, Permissions: []string{"read", "write"}}
+	cases := []struct {
+		Name               string
+		Principal          *Principal
+		MissingRoles       []string
+		MissingPermissions []string
+	}{
+		{"authorized", &Principal{Roles: []string{"operator"}, Permissions: []string{"write", "read"}}, nil, nil},
+		{"missing-role", &Principal{Roles: []string{"viewer"}, Permissions: []string{"read", "write"}}, []string{"admin", "operator"}, nil},
+		{"missing-permission", &Principal{Roles: []string{"admin"}, Permissions: []string{"read"}}, nil, []string{"write"}},
+		{"no-principal", nil, []string{"admin", "operator"}, []string{"read", "write"}},
+	}
+	for _, c := range cases {
+		t.Run(c.Name, func(t *testing.T) {
+			err := policy.Check(c.Principal)
+			if c.MissingRoles == nil && c.MissingPermissions == nil {
+				if err != nil {
+					t.Errorf("got error %v, expected nil", err)
+				}
+				return
+			}
+			var aerr *AuthorizationError
+			if !errors.As(err, &aerr) {
+				t.Fatalf("got error %v, expected an authorization error", err)
+			}
+			if !reflect.DeepEqual(aerr.MissingRoles, c.MissingRoles) || !reflect.DeepEqual(aerr.MissingPermissions, c.MissingPermissions) {
+				t.Errorf("got missing roles %v and permissions %v, expected %v and %v", aerr.MissingRoles, aerr.MissingPermissions, c.MissingRoles, c.MissingPermissions)
+			}
+		})
+	}
+	if err := (&Policy{Rule: "true"}).Check(nil); err != nil {
+		t.Errorf("got error %v for a rule only policy, expected nil", err)
+	}
+}
+
+func TestAuthorizationErrorMessage(t *testing.T) {
+	err := &AuthorizationError{Service: "svc", Method: "m", MissingRoles: []string{"admin"}, MissingPermissions: []string{"read", "write"}}
+	expected := "svc.m forbidden: requires one of roles: admin; missing permissions: read, write"
+	if err.Error() != expected {
+		t.Errorf("got %q, expected %q", err.Error(), expected)
+	}
+}
+
+func TestPrincipalFromContext(t *testing.T) {
+	if _, ok := PrincipalFromContext(context.Background()); ok {
+		t.Error("got principal from empty context")
+	}
+	p := &Principal{Subject: "alice"}
+	if got, ok := PrincipalFromContext(WithPrincipal(context.Background(), p)); !ok || got != p {
+		t.Errorf("got %v, expected %v", got, p)
+	}
+}
@@ -5,6 +5,9 @@ secure goa endpoint. It supports the following security schemes:
   * API key security using keys.
   * JWT security using JWT tokens.
   * OAuth2 security using OAuth2 tokens.
+
+It also contains the types used to authorize the authenticated requests using
+the endpoint policies defined with the Policy DSL.
 */
 package security
 
@@ -0,0 +1,232 @@
+package spiffe
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"io"
+	"math/big"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/protobuf/encoding/protowire"
+)
+
+type (
+	testCA struct {
+		cert *x509.Certificate
+		key  *ecdsa.PrivateKey
+	}
+
+	staticSource struct {
+		cert  *tls.Certificate
+		roots *x509.CertPool
+	}
+)
+
+func TestTLSConfig(t *testing.T) {
+	ca := newTestCA(t)
+	server := &staticSource{cert: ca.svid(t, "spiffe://example.org/server"), roots: ca.pool()}
+	client := &staticSource{cert: ca.svid(t, "spiffe://example.org/client"), roots: ca.pool()}
+
+	srv := httptest.NewUnstartedServer(PeerID()(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		id, _ := PeerIDFromContext(r.Context())
+		w.Write([]byte(id)) // nolint: errcheck
+	})))
+	srv.Listener = tls.NewListener(srv.Listener, ServerTLSConfig(server, AuthorizeMemberOf("example.org")))
+	srv.Start()
+	defer srv.Close()
+	u := "https://" + srv.Listener.Addr().String()
+
+	get := func(src Source, authorize Authorizer) (string, error) {
+		c := &http.Client{Transport: &http.Transport{TLSClientConfig: ClientTLSConfig(src, authorize)}}
+		resp, err := c.Get(u)
+		if err != nil {
+			return "", err
+		}
+		defer resp.Body.Close()
+		b, err := io.ReadAll(resp.Body)
+		return string(b), err
+	}
+	id, err := get(client, AuthorizeID("spiffe://example.org/server"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if id != "spiffe://example.org/client" {
+		t.Errorf("got peer ID %q, expected spiffe://example.org/client", id)
+	}
+	if _, err := get(client, AuthorizeID("spiffe://example.org/other")); err == nil {
+		t.Error("expected the server to be unauthorized")
+	}
+	other := newTestCA(t)
+	if _, err := get(&staticSource{cert: other.svid(t, "spiffe://example.org/client"), roots: ca.pool()}, AuthorizeAny()); err == nil {
+		t.Error("expected the client SVID issued by an unknown CA to be rejected")
+	}
+	if _, err := get(&staticSource{cert: ca.svid(t, "spiffe://other.org/client"), roots: ca.pool()}, AuthorizeAny()); err == nil {
+		t.Error("expected the client from another trust domain to be unauthorized")
+	}
+}
+
+func TestWorkloadSource(t *testing.T) {
+	ca := newTestCA(t)
+	dir, err := os.MkdirTemp("", "spiffe")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	sock := filepath.Join(dir, "agent.sock")
+	lis, err := net.Listen("unix", sock)
+	if err != nil {
+		t.Fatal(err)
+	}
+	rotate := make(chan string)
+	srv := grpc.NewServer(grpc.ForceServerCodec(rawCodec{}), grpc.UnknownServiceHandler(func(_ any, stream grpc.ServerStream) error {
+		md, _ := metadata.FromIncomingContext(stream.Context())
+		if method, _ := grpc.MethodFromServerStream(stream); method != "/SpiffeWorkloadAPI/FetchX509SVID" || len(md.Get("workload.spiffe.io")) == 0 {
+			return errors.New("unexpected request")
+		}
+		var req rawMessage
+		if err := stream.RecvMsg(&req); err != nil {
+			return err
+		}
+		id := "spiffe://example.org/v1"
+		for {
+			msg := svidResponse(t, ca, id)
+			if err := stream.SendMsg(&msg); err != nil {
+				return err
+			}
+			select {
+			case id = <-rotate:
+			case <-stream.Context().Done():
+				return nil
+			}
+		}
+	}))
+	go srv.Serve(lis) // nolint: errcheck
+	defer srv.Stop()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	src, err := NewWorkloadSource(ctx, "unix://"+sock)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer src.Close()
+	if src.ID() != "spiffe://example.org/v1" {
+		t.Errorf("got ID %q, expected spiffe://example.org/v1", src.ID())
+	}
+	cert, err := src.Certificate()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if id, _ := IDFromCert(cert.Leaf); id != "spiffe://example.org/v1" {
+		t.Errorf("got certificate ID %q", id)
+	}
+	roots, err := src.Roots()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := cert.Leaf.Verify(x509.VerifyOptions{Roots: roots, KeyUsages: []x509.ExtKeyUsage{x509.ExtKeyUsageAny}}); err != nil {
+		t.Errorf("SVID does not verify against the bundle: %v", err)
+	}
+
+	rotate <- "spiffe://example.org/v2"
+	for src.ID() != "spiffe://example.org/v2" {
+		select {
+		case <-ctx.Done():
+			t.Fatal("SVID was not rotated")
+		case <-time.After(10 * time.Millisecond):
+		}
+	}
+}
+
+func (s *staticSource) Certificate() (*tls.Certificate, error) { return s.cert, nil }
+func (s *staticSource) Roots() (*x509.CertPool, error)         { return s.roots, nil }
+
+func newTestCA(t *testing.T) *testCA {
+	t.Helper()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tmpl := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		IsCA:                  true,
+		BasicConstraintsValid: true,
+		KeyUsage:              x509.KeyUsageCertSign,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, tmpl, tmpl, &key.PublicKey, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cert, err := x509.ParseCertificate(der)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return &testCA{cert: cert, key: key}
+}
+
+func (ca *testCA) pool() *x509.CertPool {
+	pool := x509.NewCertPool()
+	pool.AddCert(ca.cert)
+	return pool
+}
+
+func (ca *testCA) svid(t *testing.T, id string) *tls.Certificate {
+	t.Helper()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	u, _ := url.Parse(id)
+	tmpl := &x509.Certificate{
+		SerialNumber: big.NewInt(time.Now().UnixNano()),
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		URIs:         []*url.URL{u},
+		KeyUsage:     x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth, x509.ExtKeyUsageClientAuth},
+	}
+	der, err := x509.CreateCertificate(rand.Reader, tmpl, ca.cert, &key.PublicKey, ca.key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	leaf, _ := x509.ParseCertificate(der)
+	return &tls.Certificate{Certificate: [][]byte{der}, PrivateKey: key, Leaf: leaf}
+}
+
+// svidResponse encodes a X509SVIDResponse message for the given ID.
+func svidResponse(t *testing.T, ca *testCA, id string) rawMessage {
+	cert := ca.svid(t, id)
+	key, err := x509.MarshalPKCS8PrivateKey(cert.PrivateKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var svid []byte
+	svid = protowire.AppendTag(svid, 1, protowire.BytesType)
+	svid = protowire.AppendString(svid, id)
+	svid = protowire.AppendTag(svid, 2, protowire.BytesType)
+	svid = protowire.AppendBytes(svid, cert.Certificate[0])
+	svid = protowire.AppendTag(svid, 3, protowire.BytesType)
+	svid = protowire.AppendBytes(svid, key)
+	svid = protowire.AppendTag(svid, 4, protowire.BytesType)
+	svid = protowire.AppendBytes(svid, ca.cert.Raw)
+	var msg []byte
+	msg = protowire.AppendTag(msg, 1, protowire.BytesType)
+	msg = protowire.AppendBytes(msg, svid)
+	return msg
+}
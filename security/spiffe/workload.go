@@ -0,0 +1,297 @@
+package spiffe
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/protobuf/encoding/protowire"
+)
+
+// EndpointSocketEnv is the name of the environment variable that holds the
+// address of the SPIFFE Workload API by default.
+const EndpointSocketEnv = "SPIFFE_ENDPOINT_SOCKET"
+
+type (
+	// WorkloadSource is a Source backed by the SPIFFE Workload API. It
+	// streams the X.509 SVIDs and trust bundles of the workload from the
+	// agent and rotates them as soon as the agent sends updates. Use
+	// Close to release the connection to the agent.
+	WorkloadSource struct {
+		conn   *grpc.ClientConn
+		cancel context.CancelFunc
+		done   chan struct{}
+
+		mu    sync.RWMutex
+		id    string
+		cert  *tls.Certificate
+		roots *x509.CertPool
+	}
+
+	// rawCodec is a gRPC codec that passes the encoded protobuf messages
+	// through so that the Workload API messages can be decoded without
+	// generated code.
+	rawCodec struct{}
+
+	// rawMessage is an encoded protobuf message.
+	rawMessage []byte
+)
+
+// NewWorkloadSource connects to the SPIFFE Workload API at addr (e.g.
+// "unix:///run/spire/sockets/agent.sock") and returns once the first SVID has
+// been received or ctx is done. addr defaults to the value of the
+// SPIFFE_ENDPOINT_SOCKET environment variable. The source reconnects to the
+// agent if the connection is lost.
+func NewWorkloadSource(ctx context.Context, addr string) (*WorkloadSource, error) {
+	if addr == "" {
+		addr = os.Getenv(EndpointSocketEnv)
+		if addr == "" {
+			return nil, fmt.Errorf("workload API address not set, set the %s environment variable", EndpointSocketEnv)
+		}
+	}
+	addr = strings.TrimPrefix(addr, "tcp://")
+	conn, err := grpc.Dial(addr, grpc.WithTransportCredentials(insecure.NewCredentials()))
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to workload API: %w", err)
+	}
+	wctx, cancel := context.WithCancel(context.Background())
+	s := &WorkloadSource{conn: conn, cancel: cancel, done: make(chan struct{})}
+	ready := make(chan error, 1)
+	go s.watch(wctx, ready)
+	select {
+	case err := <-ready:
+		if err == nil {
+			return s, nil
+		}
+		s.Close() // nolint: errcheck
+		return nil, err
+	case <-ctx.Done():
+		s.Close() // nolint: errcheck
+		return nil, ctx.Err()
+	}
+}
+
+// Certificate implements Source.
+func (s *WorkloadSource) Certificate() (*tls.Certificate, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	if s.cert == nil {
+		return nil, errors.New("no SVID received from the workload API")
+	}
+	return s.cert, nil
+}
+
+// Roots implements Source.
+func (s *WorkloadSource) Roots() (*x509.CertPool, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	if s.roots == nil {
+		return nil, errors.New("no trust bundle received from the workload API")
+	}
+	return s.roots, nil
+}
+
+// ID returns the SPIFFE ID of the workload.
+func (s *WorkloadSource) ID() string {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.id
+}
+
+// Close stops watching the workload API updates and closes the connection to
+// the agent.
+func (s *WorkloadSource) Close() error {
+	s.cancel()
+	<-s.done
+	return s.conn.Close()
+}
+
+// watch receives the updates sent by the agent until ctx is canceled,
+// reconnecting with an exponential backoff on errors. The result of the first
+// update is sent to ready.
+func (s *WorkloadSource) watch(ctx context.Context, ready chan<- error) {
+	defer close(s.done)
+	backoff := time.Second
+	for {
+		// Errors are retried as the agent may be restarting.
+		s.fetch(ctx, func(err error) { // nolint: errcheck
+			if ready != nil {
+				ready <- err
+				ready = nil
+			}
+			if err == nil {
+				backoff = time.Second
+			}
+		})
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(backoff):
+		}
+		if backoff < 30*time.Second {
+			backoff *= 2
+		}
+	}
+}
+
+// fetch opens the FetchX509SVID stream and applies the updates sent by the
+// agent, calling updated after each update.
+func (s *WorkloadSource) fetch(ctx context.Context, updated func(error)) error {
+	ctx = metadata.AppendToOutgoingContext(ctx, "workload.spiffe.io", "true")
+	desc := &grpc.StreamDesc{StreamName: "FetchX509SVID", ServerStreams: true}
+	stream, err := s.conn.NewStream(ctx, desc, "/SpiffeWorkloadAPI/FetchX509SVID", grpc.ForceCodec(rawCodec{}))
+	if err != nil {
+		return err
+	}
+	if err := stream.SendMsg(&rawMessage{}); err != nil {
+		return err
+	}
+	if err := stream.CloseSend(); err != nil {
+		return err
+	}
+	for {
+		var msg rawMessage
+		if err := stream.RecvMsg(&msg); err != nil {
+			return err
+		}
+		err := s.update(msg)
+		updated(err)
+		if err != nil {
+			return err
+		}
+	}
+}
+
+// update decodes the X509SVIDResponse message sent by the agent and rotates
+// the SVID and trust bundle of the source. Only the first (default) SVID is
+// used, the federated bundles are added to the trusted roots.
+func (s *WorkloadSource) update(msg []byte) error {
+	var svid []byte
+	var bundles [][]byte
+	err := decodeFields(msg, func(num protowire.Number, v []byte) error {
+		switch num {
+		case 1: // svids
+			if svid == nil {
+				svid = v
+			}
+		case 3: // federated_bundles
+			return decodeFields(v, func(num protowire.Number, v []byte) error {
+				if num == 2 {
+					bundles = append(bundles, v)
+				}
+				return nil
+			})
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	if svid == nil {
+		return errors.New("workload API response does not contain any SVID")
+	}
+	var id string
+	var chain, key, bundle []byte
+	err = decodeFields(svid, func(num protowire.Number, v []byte) error {
+		switch num {
+		case 1:
+			id = string(v)
+		case 2:
+			chain = v
+		case 3:
+			key = v
+		case 4:
+			bundle = v
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	certs, err := x509.ParseCertificates(chain)
+	if err != nil || len(certs) == 0 {
+		return fmt.Errorf("invalid SVID certificates: %v", err)
+	}
+	pk, err := x509.ParsePKCS8PrivateKey(key)
+	if err != nil {
+		return fmt.Errorf("invalid SVID private key: %w", err)
+	}
+	roots := x509.NewCertPool()
+	for _, b := range append([][]byte{bundle}, bundles...) {
+		cas, err := x509.ParseCertificates(b)
+		if err != nil {
+			return fmt.Errorf("invalid trust bundle: %w", err)
+		}
+		for _, ca := range cas {
+			roots.AddCert(ca)
+		}
+	}
+	cert := &tls.Certificate{PrivateKey: pk, Leaf: certs[0]}
+	for _, c := range certs {
+		cert.Certificate = append(cert.Certificate, c.Raw)
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.id, s.cert, s.roots = id, cert, roots
+	return nil
+}
+
+// decodeFields calls fn with the number and value of each length-delimited
+// field of the given protobuf message.
+func decodeFields(b []byte, fn func(protowire.Number, []byte) error) error {
+	for len(b) > 0 {
+		num, typ, n := protowire.ConsumeTag(b)
+		if n < 0 {
+			return protowire.ParseError(n)
+		}
+		b = b[n:]
+		if typ != protowire.BytesType {
+			n = protowire.ConsumeFieldValue(num, typ, b)
+			if n < 0 {
+				return protowire.ParseError(n)
+			}
+			b = b[n:]
+			continue
+		}
+		v, n := protowire.ConsumeBytes(b)
+		if n < 0 {
+			return protowire.ParseError(n)
+		}
+		b = b[n:]
+		if err := fn(num, v); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Marshal implements encoding.Codec.
+func (rawCodec) Marshal(v any) ([]byte, error) {
+	m, ok := v.(*rawMessage)
+	if !ok {
+		return nil, fmt.Errorf("unexpected message type %T", v)
+	}
+	return *m, nil
+}
+
+// Unmarshal implements encoding.Codec.
+func (rawCodec) Unmarshal(data []byte, v any) error {
+	m, ok := v.(*rawMessage)
+	if !ok {
+		return fmt.Errorf("unexpected message type %T", v)
+	}
+	*m = append((*m)[:0], data...)
+	return nil
+}
+
+// Name implements encoding.Codec.
+func (rawCodec) Name() string { return "proto" }
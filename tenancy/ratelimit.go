@@ -0,0 +1,81 @@
+package tenancy
+
+import (
+	"math"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+)
+
+type (
+	// Limiter limits the rate of the requests made by each tenant using one
+	// token bucket per tenant. Limiter is safe for concurrent use.
+	Limiter struct {
+		rate  float64
+		burst float64
+		now   func() time.Time
+
+		mu      sync.Mutex
+		buckets map[string]*bucket
+	}
+
+	// bucket is the token bucket of a tenant.
+	bucket struct {
+		tokens float64
+		last   time.Time
+	}
+)
+
+// NewLimiter returns a limiter that lets each tenant make rate requests per
+// second on average with bursts of up to burst requests.
+func NewLimiter(rate float64, burst int) *Limiter {
+	return &Limiter{rate: rate, burst: float64(burst), now: time.Now, buckets: make(map[string]*bucket)}
+}
+
+// Allow reports whether the tenant with the given ID may make a request now
+// and if not how long it must wait before the next request is allowed.
+func (l *Limiter) Allow(id string) (bool, time.Duration) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	now := l.now()
+	b, ok := l.buckets[id]
+	if !ok {
+		b = &bucket{tokens: l.burst, last: now}
+		l.buckets[id] = b
+	}
+	b.tokens = math.Min(l.burst, b.tokens+now.Sub(b.last).Seconds()*l.rate)
+	b.last = now
+	if b.tokens >= 1 {
+		b.tokens--
+		return true, 0
+	}
+	return false, time.Duration((1 - b.tokens) / l.rate * float64(time.Second))
+}
+
+// RateLimit returns a HTTP middleware that limits the rate of the requests
+// made by each tenant, see NewLimiter. Requests exceeding the limit receive a
+// 429 Too Many Requests response with a Retry-After header. The middleware
+// must be mounted after (that is inside) the Resolve middleware, requests
+// that do not identify a tenant are not limited.
+func RateLimit(rate float64, burst int) func(http.Handler) http.Handler {
+	return NewLimiter(rate, burst).Handler
+}
+
+// Handler returns a HTTP middleware that limits the rate of the requests made
+// by each tenant using l, see RateLimit.
+func (l *Limiter) Handler(h http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		id, ok := FromContext(r.Context())
+		if !ok {
+			h.ServeHTTP(w, r)
+			return
+		}
+		if allowed, wait := l.Allow(id); !allowed {
+			w.Header().Set("Retry-After", strconv.Itoa(int(math.Ceil(wait.Seconds()))))
+			http.Error(w, http.StatusText(http.StatusTooManyRequests), http.StatusTooManyRequests)
+			return
+		}
+		h.ServeHTTP(w, r)
+	})
+}
@@ -0,0 +1,230 @@
+/*
+Package tenancy scopes the requests made to multi-tenant services. The Resolve
+HTTP middleware resolves the tenant of each request from a header, the host
+subdomain or a token claim and stores its ID in the request context where the
+services and the other middlewares retrieve it with ID or FromContext. The
+package also provides a per-tenant rate limiter and a logger that adds the
+tenant ID to the log entries.
+
+Example:
+
+	handler = tenancy.RateLimit(10, 20)(handler)
+	handler = httpmdlwr.LogContext(tenancy.LogContext(adapter))(handler)
+	handler = tenancy.Resolve(tenancy.FirstOf(
+		tenancy.FromHeader("X-Tenant-ID"),
+		tenancy.FromSubdomain("example.com"),
+	))(handler)
+*/
+package tenancy
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net"
+	"net/http"
+	"strings"
+
+	"goa.design/goa/v3/middleware"
+)
+
+type (
+	// Resolver resolves the ID of the tenant of a HTTP request. It returns
+	// an empty ID and no error if the request does not identify a tenant
+	// using the method implemented by the resolver.
+	Resolver func(r *http.Request) (string, error)
+
+	// Option is a Resolve middleware option.
+	Option func(*options)
+
+	// options contains the Resolve middleware options.
+	options struct {
+		optional bool
+		errh     func(http.ResponseWriter, *http.Request, error)
+	}
+
+	// private type used to define context keys.
+	ctxKey int
+
+	// logger adds the tenant ID to the log entries.
+	logger struct {
+		middleware.Logger
+		id string
+	}
+)
+
+// tenantKey is the context key used to store the tenant ID.
+const tenantKey ctxKey = iota + 1
+
+// ErrNoTenant is the error given to the error handler of the Resolve
+// middleware when the resolver does not identify the tenant of a request.
+var ErrNoTenant = errors.New("tenant not found")
+
+// WithTenant returns a copy of ctx that holds the given tenant ID.
+func WithTenant(ctx context.Context, id string) context.Context {
+	return context.WithValue(ctx, tenantKey, id)
+}
+
+// FromContext returns the ID of the tenant stored in ctx by the Resolve
+// middleware or WithTenant if any.
+func FromContext(ctx context.Context) (string, bool) {
+	id, ok := ctx.Value(tenantKey).(string)
+	return id, ok && id != ""
+}
+
+// ID returns the ID of the tenant stored in ctx. It panics if there is none,
+// it is meant to be used by the services whose requests always go through the
+// Resolve middleware.
+func ID(ctx context.Context) string {
+	id, ok := FromContext(ctx)
+	if !ok {
+		panic("tenancy: no tenant in context, make sure the Resolve middleware is mounted")
+	}
+	return id
+}
+
+// Resolve returns a HTTP middleware that resolves the tenant of each request
+// using the given resolver and stores its ID in the request context, see
+// FirstOf to combine resolvers. Requests whose tenant cannot be resolved are
+// rejected with a 400 Bad Request response unless the Optional option is
+// used, requests for which the resolver returns an error are rejected with a
+// 401 Unauthorized response. Use WithErrorHandler to customize the responses.
+func Resolve(resolver Resolver, opts ...Option) func(http.Handler) http.Handler {
+	o := &options{errh: defaultErrorHandler}
+	for _, opt := range opts {
+		opt(o)
+	}
+	return func(h http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			id, err := resolver(r)
+			if err != nil {
+				o.errh(w, r, err)
+				return
+			}
+			if id == "" {
+				if !o.optional {
+					o.errh(w, r, ErrNoTenant)
+					return
+				}
+				h.ServeHTTP(w, r)
+				return
+			}
+			h.ServeHTTP(w, r.WithContext(WithTenant(r.Context(), id)))
+		})
+	}
+}
+
+// Optional lets requests that do not identify a tenant through.
+func Optional() Option {
+	return func(o *options) { o.optional = true }
+}
+
+// WithErrorHandler sets the function that writes the response to the requests
+// whose tenant cannot be resolved. err is ErrNoTenant if the resolver does not
+// identify the tenant or the error returned by the resolver.
+func WithErrorHandler(errh func(w http.ResponseWriter, r *http.Request, err error)) Option {
+	return func(o *options) { o.errh = errh }
+}
+
+// FirstOf returns a resolver that calls the given resolvers in order and
+// returns the first tenant ID found. It stops at the first error.
+func FirstOf(resolvers ...Resolver) Resolver {
+	return func(r *http.Request) (string, error) {
+		for _, res := range resolvers {
+			id, err := res(r)
+			if err != nil {
+				return "", err
+			}
+			if id != "" {
+				return id, nil
+			}
+		}
+		return "", nil
+	}
+}
+
+// FromHeader returns a resolver that reads the tenant ID from the request
+// header with the given name.
+func FromHeader(name string) Resolver {
+	return func(r *http.Request) (string, error) {
+		return strings.TrimSpace(r.Header.Get(name)), nil
+	}
+}
+
+// FromSubdomain returns a resolver that reads the tenant ID from the subdomain
+// of the request host relative to the given domain, e.g. "acme" for the host
+// "acme.example.com" and the domain "example.com". Hosts that are not direct
+// subdomains of domain do not identify a tenant.
+func FromSubdomain(domain string) Resolver {
+	suffix := "." + strings.ToLower(strings.Trim(domain, "."))
+	return func(r *http.Request) (string, error) {
+		host := r.Host
+		if h, _, err := net.SplitHostPort(host); err == nil {
+			host = h
+		}
+		host = strings.ToLower(host)
+		if !strings.HasSuffix(host, suffix) {
+			return "", nil
+		}
+		sub := strings.TrimSuffix(host, suffix)
+		if sub == "" || strings.Contains(sub, ".") {
+			return "", nil
+		}
+		return sub, nil
+	}
+}
+
+// FromClaim returns a resolver that reads the tenant ID from the claim with the
+// given name of the bearer token set in the Authorization header. parse must
+// verify the token and return its claims: the claims of tokens that cannot be
+// verified must not be trusted as they are forged trivially. The claim value
+// must be a string or a number.
+func FromClaim(claim string, parse func(token string) (map[string]any, error)) Resolver {
+	return func(r *http.Request) (string, error) {
+		auth := r.Header.Get("Authorization")
+		if len(auth) < 7 || !strings.EqualFold(auth[:7], "bearer ") {
+			return "", nil
+		}
+		claims, err := parse(strings.TrimSpace(auth[7:]))
+		if err != nil {
+			return "", err
+		}
+		switch v := claims[claim].(type) {
+		case string:
+			return v, nil
+		case float64, json.Number:
+			b, _ := json.Marshal(v)
+			return string(b), nil
+		}
+		return "", nil
+	}
+}
+
+// LogContext returns a function that returns a logger adding the ID of the
+// tenant stored in the context if any to the entries created with l under
+// the "tenant" key. It is meant to be used with the HTTP LogContext middleware
+// mounted after (that is inside) the Resolve middleware.
+func LogContext(l middleware.Logger) func(context.Context) middleware.Logger {
+	return func(ctx context.Context) middleware.Logger {
+		id, ok := FromContext(ctx)
+		if !ok {
+			return l
+		}
+		return &logger{Logger: l, id: id}
+	}
+}
+
+// Log adds the tenant ID to the entry.
+func (l *logger) Log(keyvals ...any) error {
+	return l.Logger.Log(append([]any{"tenant", l.id}, keyvals...)...)
+}
+
+// defaultErrorHandler writes a 400 Bad Request response if err is ErrNoTenant
+// and a 401 Unauthorized response otherwise.
+func defaultErrorHandler(w http.ResponseWriter, _ *http.Request, err error) {
+	if errors.Is(err, ErrNoTenant) {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	http.Error(w, http.StatusText(http.StatusUnauthorized), http.StatusUnauthorized)
+}
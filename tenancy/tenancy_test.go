@@ -0,0 +1,120 @@
+package tenancy
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestResolve(t *testing.T) {
+	parse := func(token string) (map[string]any, error) {
+		if token != "valid" {
+			return nil, errors.New("invalid token")
+		}
+		return map[string]any{"tid": "claimed", "org": float64(42)}, nil
+	}
+	cases := map[string]struct {
+		Resolver Resolver
+		Host     string
+		Header   map[string]string
+		Options  []Option
+		// output
+		Expected string
+		Status   int
+	}{
+		"header":        {FromHeader("X-Tenant"), "", map[string]string{"X-Tenant": " acme "}, nil, "acme", http.StatusOK},
+		"subdomain":     {FromSubdomain("example.com"), "Acme.example.com:8080", nil, nil, "acme", http.StatusOK},
+		"nested":        {FromSubdomain("example.com"), "a.b.example.com", nil, nil, "", http.StatusBadRequest},
+		"apex":          {FromSubdomain("example.com"), "example.com", nil, nil, "", http.StatusBadRequest},
+		"claim":         {FromClaim("tid", parse), "", map[string]string{"Authorization": "Bearer valid"}, nil, "claimed", http.StatusOK},
+		"number-claim":  {FromClaim("org", parse), "", map[string]string{"Authorization": "bearer valid"}, nil, "42", http.StatusOK},
+		"invalid-token": {FromClaim("tid", parse), "", map[string]string{"Authorization": "Bearer forged"}, nil, "", http.StatusUnauthorized},
+		"first-of":      {FirstOf(FromHeader("X-Tenant"), FromSubdomain("example.com")), "acme.example.com", nil, nil, "acme", http.StatusOK},
+		"missing":       {FromHeader("X-Tenant"), "", nil, nil, "", http.StatusBadRequest},
+		"optional":      {FromHeader("X-Tenant"), "", nil, []Option{Optional()}, "", http.StatusOK},
+	}
+	for k, c := range cases {
+		t.Run(k, func(t *testing.T) {
+			var got string
+			h := Resolve(c.Resolver, c.Options...)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				got, _ = FromContext(r.Context())
+			}))
+			req := httptest.NewRequest("GET", "/", nil)
+			if c.Host != "" {
+				req.Host = c.Host
+			}
+			for n, v := range c.Header {
+				req.Header.Set(n, v)
+			}
+			w := httptest.NewRecorder()
+			h.ServeHTTP(w, req)
+			if w.Code != c.Status {
+				t.Errorf("got status %d, expected %d", w.Code, c.Status)
+			}
+			if got != c.Expected {
+				t.Errorf("got tenant %q, expected %q", got, c.Expected)
+			}
+		})
+	}
+}
+
+func TestID(t *testing.T) {
+	if got := ID(WithTenant(context.Background(), "acme")); got != "acme" {
+		t.Errorf("got %q, expected acme", got)
+	}
+	defer func() {
+		if recover() == nil {
+			t.Error("expected panic")
+		}
+	}()
+	ID(context.Background())
+}
+
+type testLogger struct{ keyvals []any }
+
+func (l *testLogger) Log(keyvals ...any) error {
+	l.keyvals = keyvals
+	return nil
+}
+
+func TestLogContext(t *testing.T) {
+	l := &testLogger{}
+	LogContext(l)(WithTenant(context.Background(), "acme")).Log("msg", "hello") // nolint: errcheck
+	if len(l.keyvals) != 4 || l.keyvals[0] != "tenant" || l.keyvals[1] != "acme" {
+		t.Errorf("got %v, expected the tenant key first", l.keyvals)
+	}
+	if LogContext(l)(context.Background()) != l {
+		t.Error("expected the logger to be returned as is without tenant")
+	}
+}
+
+func TestLimiter(t *testing.T) {
+	now := time.Now()
+	l := NewLimiter(1, 2)
+	l.now = func() time.Time { return now }
+	for i, expected := range []bool{true, true, false} {
+		if allowed, _ := l.Allow("a"); allowed != expected {
+			t.Errorf("request %d: got allowed %v, expected %v", i, allowed, expected)
+		}
+	}
+	if allowed, _ := l.Allow("b"); !allowed {
+		t.Error("expected other tenant to be allowed")
+	}
+	if _, wait := l.Allow("a"); wait != time.Second {
+		t.Errorf("got wait %s, expected 1s", wait)
+	}
+	now = now.Add(time.Second)
+	if allowed, _ := l.Allow("a"); !allowed {
+		t.Error("expected request to be allowed after refill")
+	}
+
+	h := l.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, httptest.NewRequest("GET", "/", nil).WithContext(WithTenant(context.Background(), "a")))
+	if w.Code != http.StatusTooManyRequests || w.Header().Get("Retry-After") != "1" {
+		t.Errorf("got status %d and Retry-After %q, expected 429 and 1", w.Code, w.Header().Get("Retry-After"))
+	}
+}